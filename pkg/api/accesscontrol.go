@@ -15,6 +15,7 @@ import (
 // API related actions
 const (
 	ActionProvisioningReload = "provisioning:reload"
+	ActionProvisioningRead   = "provisioning:read"
 )
 
 // API related scopes
@@ -36,6 +37,22 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		return err
 	}
 
+	provisioningReaderRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:provisioning:reader",
+			DisplayName: "Provisioning reader",
+			Description: "Read the status of provisioning.",
+			Group:       "Provisioning",
+			Permissions: []ac.Permission{
+				{
+					Action: ActionProvisioningRead,
+					Scope:  ScopeProvisionersAll,
+				},
+			},
+		},
+		Grants: []string{ac.RoleGrafanaAdmin},
+	}
+
 	provisioningWriterRole := ac.RoleRegistration{
 		Role: ac.RoleDTO{
 			Name:        "fixed:provisioning:writer",
@@ -47,6 +64,10 @@ func (hs *HTTPServer) declareFixedRoles() error {
 					Action: ActionProvisioningReload,
 					Scope:  ScopeProvisionersAll,
 				},
+				{
+					Action: ActionProvisioningRead,
+					Scope:  ScopeProvisionersAll,
+				},
 			},
 		},
 		Grants: []string{ac.RoleGrafanaAdmin},
@@ -421,14 +442,40 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{"Admin"},
 	}
 
+	dashboardsSaveHooksBypasserRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:dashboards.savehooks:bypasser",
+			DisplayName: "Dashboard save hooks bypasser",
+			Description: "Save dashboards without calling the save webhooks and hooks.",
+			Group:       "Dashboards",
+			Permissions: []ac.Permission{
+				{Action: dashboards.ActionDashboardsSaveHooksBypass},
+			},
+		},
+		Grants: []string{ac.RoleGrafanaAdmin},
+	}
+
+	dashboardsApproverRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:dashboards:approver",
+			DisplayName: "Dashboard approver",
+			Description: "Approve or reject the dashboard changes of the folders requiring an approval.",
+			Group:       "Dashboards",
+			Permissions: []ac.Permission{
+				{Action: dashboards.ActionDashboardsApprove, Scope: dashboards.ScopeFoldersAll},
+			},
+		},
+		Grants: []string{"Admin"},
+	}
+
 	return hs.accesscontrolService.DeclareFixedRoles(
-		provisioningWriterRole, datasourcesReaderRole, builtInDatasourceReader, datasourcesWriterRole,
+		provisioningReaderRole, provisioningWriterRole, datasourcesReaderRole, builtInDatasourceReader, datasourcesWriterRole,
 		datasourcesIdReaderRole, orgReaderRole, orgWriterRole,
 		orgMaintainerRole, teamsCreatorRole, teamsWriterRole, datasourcesExplorerRole,
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
 		dashboardsCreatorRole, dashboardsReaderRole, dashboardsWriterRole,
 		foldersCreatorRole, foldersReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
-		publicDashboardsWriterRole,
+		publicDashboardsWriterRole, dashboardsSaveHooksBypasserRole, dashboardsApproverRole,
 	)
 }
 
@@ -4,9 +4,12 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/settingswatcher"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/stats"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -33,6 +36,77 @@ func (hs *HTTPServer) AdminGetSettings(c *contextmodel.ReqContext) response.Resp
 	return response.JSON(http.StatusOK, settings)
 }
 
+// swagger:route GET /admin/settings/export admin adminExportSettings
+//
+// Export the effective configuration.
+//
+// Returns the value of every setting after merging the defaults, the configuration file, the environment variables and the command line arguments, along with the source of the value (`default`, `file`, `env` or `cli`).
+// Secrets are redacted, so that the export can be shared and compared between environments.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `settings:read`, only the settings in scope are exported.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminExportSettingsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminExportSettings(c *contextmodel.ReqContext) response.Response {
+	settings, err := hs.getAuthorizedSettings(c.Req.Context(), c.SignedInUser, hs.SettingsProvider.Current())
+	if err != nil {
+		return response.Error(http.StatusForbidden, "Failed to authorize settings", err)
+	}
+
+	export := make(dtos.SettingsExport, len(settings))
+	for section, keys := range settings {
+		export[section] = make(map[string]dtos.ExportedSetting, len(keys))
+		for key, value := range keys {
+			export[section][key] = dtos.ExportedSetting{Value: value, Source: hs.Cfg.ConfigSource(section, key)}
+		}
+	}
+	return response.JSON(http.StatusOK, export)
+}
+
+// swagger:route GET /admin/settings/reload admin adminGetSettingsReload
+//
+// Fetch the configuration changes that are not applied yet.
+//
+// Reads the configuration files, the environment variables and the command line arguments again without applying them, and returns the changed keys that a reload applies (`applied`) and the ones that require a restart (`requiresRestart`).
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `settings:read`.
+//
+// Responses:
+// 200: adminSettingsReloadResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminGetSettingsReload(c *contextmodel.ReqContext) response.Response {
+	result, err := hs.settingsWatcher.Pending()
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to read the configuration", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /admin/settings/reload admin adminReloadSettings
+//
+// Reload the configuration.
+//
+// Reads the configuration files, the environment variables and the command line arguments again, the same way as when the server receives `SIGHUP`.
+// The changes of the reloadable sections are applied without a restart, the changed keys of the other sections are returned in `requiresRestart`.
+//
+// Responses:
+// 200: adminSettingsReloadResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminReloadSettings(c *contextmodel.ReqContext) response.Response {
+	result, err := hs.settingsWatcher.Reload(settingswatcher.TriggerAPI)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reload the configuration", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 // swagger:route GET /admin/stats admin adminGetStats
 //
 // Fetch Grafana Stats.
@@ -55,6 +129,34 @@ func (hs *HTTPServer) AdminGetStats(c *contextmodel.ReqContext) response.Respons
 	return response.JSON(http.StatusOK, statsQuery.Result)
 }
 
+// swagger:route GET /admin/migrations admin adminGetMigrationStatus
+//
+// Fetch the status of the database migrations.
+//
+// Returns the number of registered and applied migrations, the pending migrations and the last error of the pending migrations that failed.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `settings:read`.
+//
+// Responses:
+// 200: adminGetMigrationStatusResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminGetMigrationStatus(c *contextmodel.ReqContext) response.Response {
+	store, ok := hs.SQLStore.(interface {
+		MigrationStatus() (*sqlstore.MigrationStatus, error)
+	})
+	if !ok {
+		return response.Error(http.StatusNotImplemented, "Migration status is not supported by the database store", nil)
+	}
+
+	status, err := store.MigrationStatus()
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get migration status", err)
+	}
+
+	return response.JSON(http.StatusOK, status)
+}
+
 func (hs *HTTPServer) getAuthorizedSettings(ctx context.Context, user *user.SignedInUser, bag setting.SettingsBag) (setting.SettingsBag, error) {
 	if hs.AccessControl.IsDisabled() {
 		return bag, nil
@@ -106,8 +208,26 @@ type GetSettingsResponse struct {
 	Body setting.SettingsBag `json:"body"`
 }
 
+// swagger:response adminExportSettingsResponse
+type ExportSettingsResponse struct {
+	// in:body
+	Body dtos.SettingsExport `json:"body"`
+}
+
+// swagger:response adminSettingsReloadResponse
+type SettingsReloadResponse struct {
+	// in:body
+	Body settingswatcher.ReloadResult `json:"body"`
+}
+
 // swagger:response adminGetStatsResponse
 type GetStatsResponse struct {
 	// in:body
 	Body stats.AdminStats `json:"body"`
 }
+
+// swagger:response adminGetMigrationStatusResponse
+type GetMigrationStatusResponse struct {
+	// in:body
+	Body sqlstore.MigrationStatus `json:"body"`
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// swagger:route GET /admin/emails/deliveries admin adminGetEmailDeliveries
+//
+// Search the email delivery log.
+//
+// Returns the most recent deliveries first, with the provider that sent the email, the number of attempts and the error of the failed deliveries.
+// Only works with Basic Authentication (username and password) and for Grafana admins.
+//
+// Responses:
+// 200: adminGetEmailDeliveriesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminGetEmailDeliveries(c *contextmodel.ReqContext) response.Response {
+	status := c.Query("status")
+	if status != "" && status != notifications.DeliveryStatusSent && status != notifications.DeliveryStatusFailed {
+		return response.Error(http.StatusBadRequest, "Invalid status, expected sent or failed", nil)
+	}
+
+	limit := c.QueryInt("limit")
+	if limit <= 0 {
+		limit = 100
+	}
+
+	return response.JSON(http.StatusOK, hs.NotificationService.GetDeliveries(notifications.DeliveryQuery{
+		Status: status,
+		To:     c.Query("to"),
+		Limit:  limit,
+	}))
+}
+
+// swagger:parameters adminGetEmailDeliveries
+type AdminGetEmailDeliveriesParams struct {
+	// Only return the deliveries with this status
+	// in:query
+	// required:false
+	// enum: sent,failed
+	Status string `json:"status"`
+	// Only return the deliveries to a recipient containing this text
+	// in:query
+	// required:false
+	To string `json:"to"`
+	// in:query
+	// required:false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:response adminGetEmailDeliveriesResponse
+type AdminGetEmailDeliveriesResponse struct {
+	// in:body
+	Body []notifications.Delivery `json:"body"`
+}
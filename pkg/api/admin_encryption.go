@@ -1,12 +1,17 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
 	skv "github.com/grafana/grafana/pkg/services/secrets/kvstore"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 func (hs *HTTPServer) AdminRotateDataEncryptionKeys(c *contextmodel.ReqContext) response.Response {
@@ -17,25 +22,51 @@ func (hs *HTTPServer) AdminRotateDataEncryptionKeys(c *contextmodel.ReqContext)
 	return response.Respond(http.StatusNoContent, "")
 }
 
+const (
+	reEncryptDataKeysJob = "secrets.reencrypt-data-keys"
+	reEncryptSecretsJob  = "secrets.reencrypt-secrets"
+)
+
+// registerReEncryptionJobs registers the re-encryption jobs with the job queue, so that the re-encryption
+// outlives the requests triggering it and is retried when it fails.
+func (hs *HTTPServer) registerReEncryptionJobs() {
+	hs.jobQueue.RegisterHandler(reEncryptDataKeysJob, func(ctx context.Context, _ *jobqueue.Job) error {
+		return hs.SecretsService.ReEncryptDataKeys(ctx)
+	}, jobqueue.HandlerOptions{})
+	hs.jobQueue.RegisterHandler(reEncryptSecretsJob, func(ctx context.Context, _ *jobqueue.Job) error {
+		success, err := hs.secretsMigrator.ReEncryptSecrets(ctx)
+		if err != nil {
+			return err
+		}
+		if !success {
+			return errors.New("some secrets could not be re-encrypted")
+		}
+		return nil
+	}, jobqueue.HandlerOptions{})
+}
+
 func (hs *HTTPServer) AdminReEncryptEncryptionKeys(c *contextmodel.ReqContext) response.Response {
-	if err := hs.SecretsService.ReEncryptDataKeys(c.Req.Context()); err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to re-encrypt data keys", err)
+	job, err := hs.jobQueue.Enqueue(c.Req.Context(), jobqueue.EnqueueCommand{Type: reEncryptDataKeysJob, Key: reEncryptDataKeysJob})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to queue the re-encryption of data keys", err)
 	}
 
-	return response.Respond(http.StatusOK, "Data encryption keys re-encrypted successfully")
+	return response.JSON(http.StatusAccepted, util.DynMap{
+		"message": "Data encryption keys re-encryption queued",
+		"jobId":   job.ID,
+	})
 }
 
 func (hs *HTTPServer) AdminReEncryptSecrets(c *contextmodel.ReqContext) response.Response {
-	success, err := hs.secretsMigrator.ReEncryptSecrets(c.Req.Context())
+	job, err := hs.jobQueue.Enqueue(c.Req.Context(), jobqueue.EnqueueCommand{Type: reEncryptSecretsJob, Key: reEncryptSecretsJob})
 	if err != nil {
-		return response.Error(http.StatusInternalServerError, "Failed to re-encrypt secrets", err)
+		return response.Error(http.StatusInternalServerError, "Failed to queue the re-encryption of secrets", err)
 	}
 
-	if !success {
-		return response.Error(http.StatusPartialContent, "Something unexpected happened, refer to the server logs for more details", err)
-	}
-
-	return response.Respond(http.StatusOK, "Secrets re-encrypted successfully")
+	return response.JSON(http.StatusAccepted, util.DynMap{
+		"message": "Secrets re-encryption queued",
+		"jobId":   job.ID,
+	})
 }
 
 func (hs *HTTPServer) AdminRollbackSecrets(c *contextmodel.ReqContext) response.Response {
@@ -98,3 +129,49 @@ func (hs *HTTPServer) AdminDeleteAllSecretsManagerPluginSecrets(c *contextmodel.
 	}
 	return response.Respond(http.StatusOK, fmt.Sprintf("All %d Secrets Manager plugin secrets deleted", len(items)))
 }
+
+// AdminGetSecretsUsage returns which consumers read and reference the stored secrets, the secrets never read nor
+// referenced are not reported.
+func (hs *HTTPServer) AdminGetSecretsUsage(c *contextmodel.ReqContext) response.Response {
+	query := &skv.SecretUsageQuery{
+		OrgId:     skv.AllOrganizations,
+		Namespace: c.Query("namespace"),
+		Type:      c.Query("type"),
+	}
+	if orgID := c.QueryInt64("orgId"); orgID != 0 {
+		query.OrgId = orgID
+	}
+	report, err := hs.secretsUsage.Report(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get the usage of the secrets", err)
+	}
+	return response.JSON(http.StatusOK, report)
+}
+
+// AdminAddSecretReference records that a consumer depends on a secret, so that the secret cannot be deleted
+// until the reference is removed.
+func (hs *HTTPServer) AdminAddSecretReference(c *contextmodel.ReqContext) response.Response {
+	cmd := skv.SecretReferenceCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.OrgId == 0 || cmd.Namespace == "" || cmd.Type == "" || cmd.Consumer == "" {
+		return response.Error(http.StatusBadRequest, "orgId, namespace, type and consumer are required", nil)
+	}
+	if err := hs.secretsUsage.AddReference(c.Req.Context(), cmd.OrgId, cmd.Namespace, cmd.Type, cmd.Consumer); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to add the secret reference", err)
+	}
+	return response.Success("Secret reference added")
+}
+
+func (hs *HTTPServer) AdminRemoveSecretReference(c *contextmodel.ReqContext) response.Response {
+	orgID := c.QueryInt64("orgId")
+	namespace, typ, consumer := c.Query("namespace"), c.Query("type"), c.Query("consumer")
+	if orgID == 0 || namespace == "" || typ == "" || consumer == "" {
+		return response.Error(http.StatusBadRequest, "orgId, namespace, type and consumer are required", nil)
+	}
+	if err := hs.secretsUsage.RemoveReference(c.Req.Context(), orgID, namespace, typ, consumer); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to remove the secret reference", err)
+	}
+	return response.Success("Secret reference removed")
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /admin/log-levels admin adminGetLogLevels
+//
+// Fetch the levels of the named loggers.
+//
+// Returns the level of every named logger, from the configuration or set at runtime (`overridden`).
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `settings:read`.
+//
+// Responses:
+// 200: adminGetLogLevelsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminGetLogLevels(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, log.Levels())
+}
+
+// swagger:route PUT /admin/log-levels/{logger_name} admin adminSetLogLevel
+//
+// Set the level of a named logger.
+//
+// The level applies until the next restart or until it is reset. High-volume debug loggers can be sampled with `sampleRate`, then only one out of `sampleRate` debug messages is logged.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminSetLogLevel(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.SetLogLevelCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.SampleRate < 0 {
+		return response.Error(http.StatusBadRequest, "Sample rate cannot be negative", nil)
+	}
+
+	name := web.Params(c.Req)[":name"]
+	if err := log.SetLevel(name, cmd.Level, cmd.SampleRate); err != nil {
+		if errors.Is(err, log.ErrUnknownLogLevel) {
+			return response.Error(http.StatusBadRequest, "Unknown log level", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to set the log level", err)
+	}
+
+	hs.log.Info("Log level changed", "logger", name, "level", cmd.Level, "sampleRate", cmd.SampleRate, "userId", c.UserID)
+	return response.Success("Log level changed")
+}
+
+// swagger:route DELETE /admin/log-levels/{logger_name} admin adminResetLogLevel
+//
+// Reset the level of a named logger.
+//
+// The logger logs at its configured level again.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminResetLogLevel(c *contextmodel.ReqContext) response.Response {
+	name := web.Params(c.Req)[":name"]
+	log.ResetLevel(name)
+
+	hs.log.Info("Log level reset", "logger", name, "userId", c.UserID)
+	return response.Success("Log level reset")
+}
+
+// swagger:parameters adminSetLogLevel
+type AdminSetLogLevelParams struct {
+	// in:path
+	// required:true
+	LoggerName string `json:"logger_name"`
+	// in:body
+	// required:true
+	Body dtos.SetLogLevelCommand `json:"body"`
+}
+
+// swagger:parameters adminResetLogLevel
+type AdminResetLogLevelParams struct {
+	// in:path
+	// required:true
+	LoggerName string `json:"logger_name"`
+}
+
+// swagger:response adminGetLogLevelsResponse
+type GetLogLevelsResponse struct {
+	// in:body
+	Body []log.LoggerLevel `json:"body"`
+}
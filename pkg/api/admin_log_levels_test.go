@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_AdminLogLevels(t *testing.T) {
+	const loggerName = "test.admin-log-levels"
+	t.Cleanup(func() { log.ResetLevel(loggerName) })
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.log = log.NewNopLogger()
+	})
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, IsGrafanaAdmin: true}
+
+	send := func(t *testing.T, req *http.Request, signedInUser *user.SignedInUser) *http.Response {
+		t.Helper()
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, signedInUser))
+		require.NoError(t, err)
+		return res
+	}
+
+	getLevel := func(t *testing.T) *log.LoggerLevel {
+		t.Helper()
+		res := send(t, server.NewGetRequest("/api/admin/log-levels"), admin)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var levels []log.LoggerLevel
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&levels))
+		require.NoError(t, res.Body.Close())
+		for _, level := range levels {
+			if level.Name == loggerName {
+				return &level
+			}
+		}
+		return nil
+	}
+
+	t.Run("should set the level of a logger", func(t *testing.T) {
+		res := send(t, server.NewRequest(http.MethodPut, "/api/admin/log-levels/"+loggerName, strings.NewReader(`{"level":"debug","sampleRate":10}`)), admin)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		assert.Equal(t, &log.LoggerLevel{Name: loggerName, Level: "debug", SampleRate: 10, Overridden: true}, getLevel(t))
+	})
+
+	t.Run("should reject an unknown level", func(t *testing.T) {
+		res := send(t, server.NewRequest(http.MethodPut, "/api/admin/log-levels/"+loggerName, strings.NewReader(`{"level":"verbose"}`)), admin)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not allow users who are not server admins to set the level", func(t *testing.T) {
+		res := send(t, server.NewRequest(http.MethodPut, "/api/admin/log-levels/"+loggerName, strings.NewReader(`{"level":"error"}`)), &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleAdmin})
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should reset the level of a logger", func(t *testing.T) {
+		res := send(t, server.NewRequest(http.MethodDelete, "/api/admin/log-levels/"+loggerName, nil), admin)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		assert.Nil(t, getLevel(t))
+	})
+}
@@ -3,9 +3,12 @@ package api
 import (
 	"context"
 	"errors"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/web"
 )
 
 // swagger:route POST /admin/provisioning/dashboards/reload admin_provisioning adminProvisioningReloadDashboards
@@ -107,3 +110,82 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route GET /admin/provisioning/status admin_provisioning adminProvisioningGetStatus
+//
+// Get the status of the provisioning providers.
+//
+// Returns, for the orgs, dashboards, datasources, plugins, legacy alert notifiers and alerting providers, the time and duration of the last run, the number of items applied by the last successful run and the error of the last run if it failed.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:read` and scope `provisioners:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminProvisioningGetStatusResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminProvisioningGetStatus(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, hs.ProvisioningService.GetStatus())
+}
+
+// swagger:route POST /admin/provisioning/reload admin_provisioning adminProvisioningReloadAll
+//
+// Reload all provisioning configurations.
+//
+// Starts reloading the provisioning config files of all the providers in the background and returns the job tracking the reload. If a reload is already running, its job is returned.
+// The job can be polled with `GET /admin/provisioning/reload/{jobId}`.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 202: adminProvisioningReloadJobResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) AdminProvisioningReloadAll(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusAccepted, hs.ProvisioningService.ReloadAll(c.Req.Context()))
+}
+
+// swagger:route GET /admin/provisioning/reload/{jobId} admin_provisioning adminProvisioningGetReloadJob
+//
+// Get a provisioning reload job.
+//
+// Only the most recent jobs are kept.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:read` and scope `provisioners:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminProvisioningReloadJobResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+func (hs *HTTPServer) AdminProvisioningGetReloadJob(c *contextmodel.ReqContext) response.Response {
+	job, ok := hs.ProvisioningService.GetReloadJob(web.Params(c.Req)[":jobId"])
+	if !ok {
+		return response.Error(http.StatusNotFound, "Reload job not found", nil)
+	}
+	return response.JSON(http.StatusOK, job)
+}
+
+// swagger:parameters adminProvisioningGetReloadJob
+type AdminProvisioningGetReloadJobParams struct {
+	// in:path
+	// required:true
+	JobID string `json:"jobId"`
+}
+
+// swagger:response adminProvisioningGetStatusResponse
+type AdminProvisioningGetStatusResponse struct {
+	// in:body
+	Body []provisioning.ProviderStatus `json:"body"`
+}
+
+// swagger:response adminProvisioningReloadJobResponse
+type AdminProvisioningReloadJobResponse struct {
+	// in:body
+	Body *provisioning.ReloadJob `json:"body"`
+}
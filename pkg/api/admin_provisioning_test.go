@@ -156,6 +156,31 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			expectedCode: http.StatusForbidden,
 			url:          "/api/admin/provisioning/alerting/reload",
 		},
+		{
+			desc:         "should start reloading all providers with broader scope",
+			expectedCode: http.StatusAccepted,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersAll,
+				},
+			},
+			url: "/api/admin/provisioning/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ReloadAll, 1)
+			},
+		},
+		{
+			desc:         "should fail reloading all providers with specific scope",
+			expectedCode: http.StatusForbidden,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersDashboards,
+				},
+			},
+			url: "/api/admin/provisioning/reload",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -210,60 +212,12 @@ func (hs *HTTPServer) AdminDeleteUser(c *contextmodel.ReqContext) response.Respo
 	}
 
 	g, ctx := errgroup.WithContext(c.Req.Context())
-	g.Go(func() error {
-		if err := hs.starService.DeleteByUser(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.orgService.DeleteUserFromAll(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.DashboardService.DeleteACLByUser(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.preferenceService.DeleteByUser(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.teamGuardian.DeleteByUser(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.authInfoService.DeleteUserAuthInfo(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.AuthTokenService.RevokeAllUserTokens(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.QuotaService.DeleteQuotaForUser(ctx, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
-	g.Go(func() error {
-		if err := hs.accesscontrolService.DeleteUserPermissions(ctx, accesscontrol.GlobalOrgID, cmd.UserID); err != nil {
-			return err
-		}
-		return nil
-	})
+	for _, cleanup := range hs.deletedUserCleanups(cmd.UserID) {
+		cleanup := cleanup
+		g.Go(func() error {
+			return cleanup(ctx)
+		})
+	}
 	if err := g.Wait(); err != nil {
 		return response.Error(500, "Failed to delete user", err)
 	}
@@ -271,6 +225,26 @@ func (hs *HTTPServer) AdminDeleteUser(c *contextmodel.ReqContext) response.Respo
 	return response.Success("User deleted")
 }
 
+// deletedUserCleanups returns the functions removing everything that
+// references a user once the user itself has been deleted.
+func (hs *HTTPServer) deletedUserCleanups(userID int64) []func(ctx context.Context) error {
+	return []func(ctx context.Context) error{
+		func(ctx context.Context) error { return hs.starService.DeleteByUser(ctx, userID) },
+		func(ctx context.Context) error { return hs.orgService.DeleteUserFromAll(ctx, userID) },
+		func(ctx context.Context) error { return hs.DashboardService.DeleteACLByUser(ctx, userID) },
+		func(ctx context.Context) error { return hs.preferenceService.DeleteByUser(ctx, userID) },
+		func(ctx context.Context) error { return hs.teamGuardian.DeleteByUser(ctx, userID) },
+		func(ctx context.Context) error { return hs.authInfoService.DeleteUserAuthInfo(ctx, userID) },
+		func(ctx context.Context) error { return hs.AuthTokenService.RevokeAllUserTokens(ctx, userID) },
+		func(ctx context.Context) error { return hs.QuotaService.DeleteQuotaForUser(ctx, userID) },
+		func(ctx context.Context) error { return hs.userDataService.DeleteByUser(ctx, userID) },
+		func(ctx context.Context) error { return hs.inboxService.DeleteByUser(ctx, userID) },
+		func(ctx context.Context) error {
+			return hs.accesscontrolService.DeleteUserPermissions(ctx, accesscontrol.GlobalOrgID, userID)
+		},
+	}
+}
+
 // swagger:route POST /admin/users/{user_id}/disable admin_users adminDisableUser
 //
 // Disable user.
@@ -352,6 +326,82 @@ func (hs *HTTPServer) AdminEnableUser(c *contextmodel.ReqContext) response.Respo
 	return response.Success("User enabled")
 }
 
+// swagger:route POST /admin/users/{user_id}/unlock admin_users adminUnlockUser
+//
+// Unlock user.
+//
+// Unlock user resets the failed login attempts of the user, which ends a lockout of the brute force login protection.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:write` and scope `global.users:1` (userIDScope).
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminUnlockUser(c *contextmodel.ReqContext) response.Response {
+	userID, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	usr, err := hs.userService.GetByID(c.Req.Context(), &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.Error(http.StatusNotFound, user.ErrUserNotFound.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Could not read user from database", err)
+	}
+
+	// login attempts are recorded with the username entered in the login form, which can be the login or the email
+	for _, username := range []string{usr.Login, usr.Email} {
+		if username == "" {
+			continue
+		}
+		if err := hs.loginAttemptService.Reset(c.Req.Context(), username); err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to unlock user", err)
+		}
+	}
+
+	return response.Success("User unlocked")
+}
+
+// swagger:route POST /admin/login-attempts/unlock-ip admin_users adminUnlockIPAddress
+//
+// Unlock IP address.
+//
+// Unlock IP address resets the failed login attempts made from the IP address, which ends a lockout of the brute force login protection.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `users:write` and scope `global.users:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminUnlockIPAddress(c *contextmodel.ReqContext) response.Response {
+	form := dtos.AdminUnlockIPAddressForm{}
+	if err := web.Bind(c.Req, &form); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if net.ParseIP(form.IPAddress) == nil {
+		return response.Error(http.StatusBadRequest, "ipAddress is invalid", nil)
+	}
+
+	if err := hs.loginAttemptService.ResetIP(c.Req.Context(), form.IPAddress); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to unlock IP address", err)
+	}
+
+	return response.Success("IP address unlocked")
+}
+
 // swagger:route POST /admin/users/{user_id}/logout admin_users adminLogoutUser
 //
 // Logout user revokes all auth tokens (devices) for the user. User of issued auth tokens (devices) will no longer be logged in and will be required to authenticate again upon next activity.
@@ -454,6 +504,20 @@ type AdminEnableUserParams struct {
 	UserID int64 `json:"user_id"`
 }
 
+// swagger:parameters adminUnlockUser
+type AdminUnlockUserParams struct {
+	// in:path
+	// required:true
+	UserID int64 `json:"user_id"`
+}
+
+// swagger:parameters adminUnlockIPAddress
+type AdminUnlockIPAddressParams struct {
+	// in:body
+	// required:true
+	Body dtos.AdminUnlockIPAddressForm `json:"body"`
+}
+
 // swagger:parameters adminDisableUser
 type AdminDisableUserParams struct {
 	// in:path
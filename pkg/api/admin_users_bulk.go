@@ -0,0 +1,443 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/metrics"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	bulkUsersDefaultBatchSize = 100
+	bulkUsersMaxBatchSize     = 1000
+	bulkUsersMaxRows          = 5000
+)
+
+var (
+	errBulkUserMissingIdentifier = errors.New("one of id, login or email is required")
+	errBulkUserMissingLogin      = errors.New("login or email is required")
+	errBulkUserPasswordTooShort  = errors.New("password is missing or too short")
+	errBulkUserInvalidRole       = errors.New("invalid role")
+	errBulkUserExternal          = errors.New("external users cannot be disabled")
+	errBulkUserSelf              = errors.New("cannot apply this operation to the signed in user")
+)
+
+// bulkUserAction applies a bulk operation to a single row and returns the ID
+// of the affected user.
+type bulkUserAction func(ctx context.Context, c *contextmodel.ReqContext, row *dtos.BulkUserRow) (int64, error)
+
+// swagger:route POST /admin/users/bulk/create admin_users adminBulkCreateUsers
+//
+// Create users in bulk.
+//
+// Accepts a JSON array of rows or a CSV document (`Content-Type: text/csv`) whose header names the columns `login`, `email`, `name`, `password`, `orgId` and `role`.
+// Rows are processed in batches of `batchSize` rows, each batch in its own transaction: if a row fails, the whole batch is rolled back.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminBulkUsersResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminBulkCreateUsers(c *contextmodel.ReqContext) response.Response {
+	return hs.handleBulkUsers(c, hs.bulkCreateUser, func(result dtos.BulkUserResponse) {
+		metrics.MApiAdminUserCreate.Add(float64(result.Succeeded))
+	})
+}
+
+// swagger:route POST /admin/users/bulk/role admin_users adminBulkUpdateUserRoles
+//
+// Update organization roles of users in bulk.
+//
+// Each row identifies a user by `id`, `login` or `email` and sets its `role` in the organization `orgId`, defaulting to the current organization.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminBulkUsersResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminBulkUpdateUserRoles(c *contextmodel.ReqContext) response.Response {
+	return hs.handleBulkUsers(c, hs.bulkUpdateUserRole, nil)
+}
+
+// swagger:route POST /admin/users/bulk/disable admin_users adminBulkDisableUsers
+//
+// Disable users in bulk.
+//
+// Each row identifies a user by `id`, `login` or `email`. All sessions of disabled users are revoked.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminBulkUsersResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminBulkDisableUsers(c *contextmodel.ReqContext) response.Response {
+	return hs.handleBulkUsers(c, hs.bulkDisableUser, nil)
+}
+
+// swagger:route POST /admin/users/bulk/delete admin_users adminBulkDeleteUsers
+//
+// Delete users in bulk.
+//
+// Each row identifies a user by `id`, `login` or `email`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminBulkUsersResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminBulkDeleteUsers(c *contextmodel.ReqContext) response.Response {
+	return hs.handleBulkUsers(c, hs.bulkDeleteUser, nil)
+}
+
+func (hs *HTTPServer) handleBulkUsers(c *contextmodel.ReqContext, action bulkUserAction, onDone func(dtos.BulkUserResponse)) response.Response {
+	batchSize := c.QueryInt("batchSize")
+	if batchSize <= 0 {
+		batchSize = bulkUsersDefaultBatchSize
+	}
+	if batchSize > bulkUsersMaxBatchSize {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("batchSize cannot exceed %d", bulkUsersMaxBatchSize), nil)
+	}
+
+	rows, err := bindBulkUserRows(c.Req)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if len(rows) == 0 {
+		return response.Error(http.StatusBadRequest, "no rows provided", nil)
+	}
+	if len(rows) > bulkUsersMaxRows {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("cannot process more than %d rows at once", bulkUsersMaxRows), nil)
+	}
+
+	result := hs.runBulkUserAction(c, rows, batchSize, action)
+	if onDone != nil {
+		onDone(result)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// runBulkUserAction applies the action to all rows, one transaction per
+// batch. The first failing row of a batch rolls back the rows of the batch
+// already processed and skips the remaining ones.
+func (hs *HTTPServer) runBulkUserAction(c *contextmodel.ReqContext, rows []dtos.BulkUserRow, batchSize int, action bulkUserAction) dtos.BulkUserResponse {
+	results := make([]dtos.BulkUserResult, len(rows))
+	for i, row := range rows {
+		results[i] = dtos.BulkUserResult{Row: i + 1, UserID: row.ID, Login: row.Login, Email: row.Email}
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		err := hs.SQLStore.InTransaction(c.Req.Context(), func(ctx context.Context) error {
+			for i := start; i < end; i++ {
+				userID, err := action(ctx, c, &rows[i])
+				if err != nil {
+					results[i].Status = dtos.BulkUserStatusFailed
+					results[i].Error = hs.bulkUserErrorMessage(c, err)
+					for j := i + 1; j < end; j++ {
+						results[j].Status = dtos.BulkUserStatusSkipped
+					}
+					return err
+				}
+				results[i].UserID = userID
+				results[i].Status = dtos.BulkUserStatusOK
+			}
+			return nil
+		})
+		if err == nil {
+			continue
+		}
+
+		for i := start; i < end; i++ {
+			switch results[i].Status {
+			case dtos.BulkUserStatusOK:
+				results[i].Status = dtos.BulkUserStatusRolledBack
+			case "":
+				// the batch failed while committing
+				results[i].Status = dtos.BulkUserStatusFailed
+				results[i].Error = hs.bulkUserErrorMessage(c, err)
+			}
+		}
+	}
+
+	res := dtos.BulkUserResponse{Results: results}
+	for _, r := range results {
+		if r.Status == dtos.BulkUserStatusOK {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+	return res
+}
+
+func (hs *HTTPServer) bulkUserErrorMessage(c *contextmodel.ReqContext, err error) string {
+	switch {
+	case errors.Is(err, errBulkUserMissingIdentifier),
+		errors.Is(err, errBulkUserMissingLogin),
+		errors.Is(err, errBulkUserPasswordTooShort),
+		errors.Is(err, errBulkUserInvalidRole),
+		errors.Is(err, errBulkUserExternal),
+		errors.Is(err, errBulkUserSelf),
+		errors.Is(err, user.ErrUserNotFound),
+		errors.Is(err, user.ErrUserAlreadyExists),
+		errors.Is(err, org.ErrOrgNotFound),
+		errors.Is(err, org.ErrOrgUserNotFound),
+		errors.Is(err, org.ErrLastOrgAdmin):
+		return err.Error()
+	}
+
+	c.Logger.Error("Bulk user operation failed", "error", err)
+	return "internal error"
+}
+
+func (hs *HTTPServer) bulkCreateUser(ctx context.Context, c *contextmodel.ReqContext, row *dtos.BulkUserRow) (int64, error) {
+	cmd := user.CreateUserCommand{
+		Login:    strings.TrimSpace(row.Login),
+		Email:    strings.TrimSpace(row.Email),
+		Name:     row.Name,
+		Password: row.Password,
+		OrgID:    row.OrgID,
+	}
+
+	if len(cmd.Login) == 0 {
+		cmd.Login = cmd.Email
+		if len(cmd.Login) == 0 {
+			return 0, errBulkUserMissingLogin
+		}
+	}
+
+	if len(cmd.Password) < 4 {
+		return 0, errBulkUserPasswordTooShort
+	}
+
+	if row.Role != "" {
+		if !row.Role.IsValid() {
+			return 0, errBulkUserInvalidRole
+		}
+		cmd.DefaultOrgRole = string(row.Role)
+	}
+
+	usr, err := hs.userService.Create(ctx, &cmd)
+	if err != nil {
+		return 0, err
+	}
+	return usr.ID, nil
+}
+
+func (hs *HTTPServer) bulkUpdateUserRole(ctx context.Context, c *contextmodel.ReqContext, row *dtos.BulkUserRow) (int64, error) {
+	if !row.Role.IsValid() {
+		return 0, errBulkUserInvalidRole
+	}
+
+	usr, err := hs.getBulkUser(ctx, row)
+	if err != nil {
+		return 0, err
+	}
+
+	orgID := row.OrgID
+	if orgID == 0 {
+		orgID = c.OrgID
+	}
+
+	cmd := org.UpdateOrgUserCommand{OrgID: orgID, UserID: usr.ID, Role: row.Role}
+	if err := hs.orgService.UpdateOrgUser(ctx, &cmd); err != nil {
+		return 0, err
+	}
+	return usr.ID, nil
+}
+
+func (hs *HTTPServer) bulkDisableUser(ctx context.Context, c *contextmodel.ReqContext, row *dtos.BulkUserRow) (int64, error) {
+	usr, err := hs.getBulkUser(ctx, row)
+	if err != nil {
+		return 0, err
+	}
+	if usr.ID == c.UserID {
+		return 0, errBulkUserSelf
+	}
+
+	// External users shouldn't be disabled from API
+	authInfoQuery := &login.GetAuthInfoQuery{UserId: usr.ID}
+	if err := hs.authInfoService.GetAuthInfo(ctx, authInfoQuery); !errors.Is(err, user.ErrUserNotFound) {
+		return 0, errBulkUserExternal
+	}
+
+	if err := hs.userService.Disable(ctx, &user.DisableUserCommand{UserID: usr.ID, IsDisabled: true}); err != nil {
+		return 0, err
+	}
+	if err := hs.AuthTokenService.RevokeAllUserTokens(ctx, usr.ID); err != nil {
+		return 0, err
+	}
+	return usr.ID, nil
+}
+
+func (hs *HTTPServer) bulkDeleteUser(ctx context.Context, c *contextmodel.ReqContext, row *dtos.BulkUserRow) (int64, error) {
+	usr, err := hs.getBulkUser(ctx, row)
+	if err != nil {
+		return 0, err
+	}
+	if usr.ID == c.UserID {
+		return 0, errBulkUserSelf
+	}
+
+	if err := hs.userService.Delete(ctx, &user.DeleteUserCommand{UserID: usr.ID}); err != nil {
+		return 0, err
+	}
+
+	// The cleanups share the batch transaction, so they have to run sequentially
+	for _, cleanup := range hs.deletedUserCleanups(usr.ID) {
+		if err := cleanup(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return usr.ID, nil
+}
+
+func (hs *HTTPServer) getBulkUser(ctx context.Context, row *dtos.BulkUserRow) (*user.User, error) {
+	if row.ID > 0 {
+		return hs.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: row.ID})
+	}
+
+	loginOrEmail := strings.TrimSpace(row.Login)
+	if loginOrEmail == "" {
+		loginOrEmail = strings.TrimSpace(row.Email)
+	}
+	if loginOrEmail == "" {
+		return nil, errBulkUserMissingIdentifier
+	}
+	return hs.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+}
+
+// bindBulkUserRows reads the rows of a bulk request, either from a JSON array
+// or from a CSV document with a header line.
+func bindBulkUserRows(req *http.Request) ([]dtos.BulkUserRow, error) {
+	m, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	if m != "text/csv" {
+		rows := []dtos.BulkUserRow{}
+		if err := web.Bind(req, &rows); err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+
+	defer func() { _ = req.Body.Close() }()
+	reader := csv.NewReader(req.Body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		switch header[i] {
+		case "id", "login", "email", "name", "password", "orgid", "role":
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+
+	rows := []dtos.BulkUserRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := dtos.BulkUserRow{}
+		for i, value := range record {
+			switch header[i] {
+			case "id":
+				if value == "" {
+					continue
+				}
+				if row.ID, err = strconv.ParseInt(value, 10, 64); err != nil {
+					return nil, fmt.Errorf("line %d: invalid id", len(rows)+2)
+				}
+			case "login":
+				row.Login = value
+			case "email":
+				row.Email = value
+			case "name":
+				row.Name = value
+			case "password":
+				row.Password = value
+			case "orgid":
+				if value == "" {
+					continue
+				}
+				if row.OrgID, err = strconv.ParseInt(value, 10, 64); err != nil {
+					return nil, fmt.Errorf("line %d: invalid orgId", len(rows)+2)
+				}
+			case "role":
+				row.Role = org.RoleType(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// swagger:parameters adminBulkCreateUsers adminBulkUpdateUserRoles adminBulkDisableUsers adminBulkDeleteUsers
+type AdminBulkUsersParams struct {
+	// Number of rows processed in a single transaction
+	// in:query
+	// required:false
+	// default: 100
+	BatchSize int `json:"batchSize"`
+	// in:body
+	// required:true
+	Body []dtos.BulkUserRow `json:"body"`
+}
+
+// swagger:response adminBulkUsersResponse
+type AdminBulkUsersResponse struct {
+	// in:body
+	Body dtos.BulkUserResponse `json:"body"`
+}
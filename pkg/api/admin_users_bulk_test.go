@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestBindBulkUserRows(t *testing.T) {
+	t.Run("should parse a CSV payload", func(t *testing.T) {
+		body := "login,email,password,orgId,role\nalice,alice@example.com,secret,2,Editor\nbob,,secret,,\n"
+		req, err := http.NewRequest(http.MethodPost, "/api/admin/users/bulk/create", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "text/csv")
+
+		rows, err := bindBulkUserRows(req)
+		require.NoError(t, err)
+		require.Equal(t, []dtos.BulkUserRow{
+			{Login: "alice", Email: "alice@example.com", Password: "secret", OrgID: 2, Role: org.RoleEditor},
+			{Login: "bob", Password: "secret"},
+		}, rows)
+	})
+
+	t.Run("should reject unknown CSV columns", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/api/admin/users/bulk/create", strings.NewReader("login,admin\nalice,true\n"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "text/csv")
+
+		_, err = bindBulkUserRows(req)
+		require.Error(t, err)
+	})
+
+	t.Run("should parse a JSON payload", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/api/admin/users/bulk/delete", strings.NewReader(`[{"id": 3}, {"login": "bob"}]`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		rows, err := bindBulkUserRows(req)
+		require.NoError(t, err)
+		require.Equal(t, []dtos.BulkUserRow{{ID: 3}, {Login: "bob"}}, rows)
+	})
+}
+
+func TestRunBulkUserAction(t *testing.T) {
+	hs := &HTTPServer{SQLStore: db.InitTestDB(t)}
+	httpReq, err := http.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, err)
+	c := &contextmodel.ReqContext{
+		Context:      &web.Context{Req: httpReq},
+		SignedInUser: &user.SignedInUser{},
+		Logger:       log.New("test"),
+	}
+
+	rows := []dtos.BulkUserRow{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	var applied []int64
+	action := func(ctx context.Context, c *contextmodel.ReqContext, row *dtos.BulkUserRow) (int64, error) {
+		if row.ID == 4 {
+			return 0, user.ErrUserNotFound
+		}
+		if row.ID == 5 {
+			return 0, errors.New("database is on fire")
+		}
+		applied = append(applied, row.ID)
+		return row.ID, nil
+	}
+
+	result := hs.runBulkUserAction(c, rows, 3, action)
+	assert.ElementsMatch(t, []int64{1, 2, 3}, applied)
+	assert.Equal(t, 3, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+
+	statuses := make([]string, 0, len(result.Results))
+	for _, r := range result.Results {
+		statuses = append(statuses, r.Status)
+	}
+	assert.Equal(t, []string{
+		dtos.BulkUserStatusOK, dtos.BulkUserStatusOK, dtos.BulkUserStatusOK,
+		dtos.BulkUserStatusFailed, dtos.BulkUserStatusSkipped,
+	}, statuses)
+	assert.Equal(t, user.ErrUserNotFound.Error(), result.Results[3].Error)
+
+	t.Run("should roll back rows processed before a failure in the same batch", func(t *testing.T) {
+		result := hs.runBulkUserAction(c, rows[2:], 3, action)
+		assert.Equal(t, 0, result.Succeeded)
+		assert.Equal(t, dtos.BulkUserStatusRolledBack, result.Results[0].Status)
+		assert.Equal(t, dtos.BulkUserStatusFailed, result.Results[1].Status)
+		assert.Equal(t, dtos.BulkUserStatusSkipped, result.Results[2].Status)
+
+		result = hs.runBulkUserAction(c, rows[4:], 3, action)
+		assert.Equal(t, "internal error", result.Results[0].Error)
+	})
+}
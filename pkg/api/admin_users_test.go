@@ -2,6 +2,8 @@ package api
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,10 +19,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
+	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattempttest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 const (
@@ -145,6 +150,26 @@ func TestAdminAPIEndpoint(t *testing.T) {
 			})
 	})
 
+	t.Run("When a server admin attempts to unlock a user", func(t *testing.T) {
+		loginAttempts := &loginattempttest.MockLoginAttemptService{}
+		userService := &usertest.FakeUserService{ExpectedUser: &user.User{ID: 42, Login: "locked", Email: "locked@example.com"}}
+		adminUnlockUserScenario(t, "Should reset the login attempts on a POST request", "/api/admin/users/42/unlock",
+			"/api/admin/users/:id/unlock", userService, loginAttempts, func(sc *scenarioContext) {
+				sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+
+				assert.Equal(t, 200, sc.resp.Code)
+				assert.True(t, loginAttempts.ResetCalled)
+			})
+
+		userService = &usertest.FakeUserService{ExpectedError: user.ErrUserNotFound}
+		adminUnlockUserScenario(t, "Should return user not found on a POST request", "/api/admin/users/42/unlock",
+			"/api/admin/users/:id/unlock", userService, &loginattempttest.MockLoginAttemptService{}, func(sc *scenarioContext) {
+				sc.fakeReqWithParams("POST", sc.url, map[string]string{}).exec()
+
+				assert.Equal(t, 404, sc.resp.Code)
+			})
+	})
+
 	t.Run("When a server admin attempts to delete a nonexistent user", func(t *testing.T) {
 		adminDeleteUserScenario(t, "Should return user not found error", "/api/admin/users/42",
 			"/api/admin/users/:id", func(sc *scenarioContext) {
@@ -372,6 +397,28 @@ func adminDisableUserScenario(t *testing.T, desc string, action string, url stri
 	})
 }
 
+func adminUnlockUserScenario(t *testing.T, desc string, url string, routePattern string, userService user.Service,
+	loginAttemptService loginattempt.Service, fn scenarioFunc) {
+	t.Run(fmt.Sprintf("%s %s", desc, url), func(t *testing.T) {
+		hs := HTTPServer{
+			userService:         userService,
+			loginAttemptService: loginAttemptService,
+		}
+
+		sc := setupScenarioContext(t, url)
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			sc.context = c
+			sc.context.UserID = testUserID
+
+			return hs.AdminUnlockUser(c)
+		})
+
+		sc.m.Post(routePattern, sc.defaultHandler)
+
+		fn(sc)
+	})
+}
+
 func adminDeleteUserScenario(t *testing.T, desc string, url string, routePattern string, fn scenarioFunc) {
 	hs := HTTPServer{
 		SQLStore:    dbtest.NewFakeDB(),
@@ -416,3 +463,52 @@ func adminCreateUserScenario(t *testing.T, desc string, url string, routePattern
 		fn(sc)
 	})
 }
+
+func TestAdminAPI_UnlockIPAddress(t *testing.T) {
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, IsGrafanaAdmin: true}
+
+	tests := []struct {
+		desc            string
+		body            string
+		signedInUser    *user.SignedInUser
+		expectedCode    int
+		expectedResetIP bool
+	}{
+		{
+			desc:            "should unlock the IP address",
+			body:            `{"ipAddress": "192.168.0.1"}`,
+			signedInUser:    admin,
+			expectedCode:    http.StatusOK,
+			expectedResetIP: true,
+		},
+		{
+			desc:         "should reject an invalid IP address",
+			body:         `{"ipAddress": "not-an-ip"}`,
+			signedInUser: admin,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not unlock the IP address for an org admin",
+			body:         `{"ipAddress": "192.168.0.1"}`,
+			signedInUser: &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleAdmin},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			loginAttempts := &loginattempttest.MockLoginAttemptService{}
+			server := SetupAPITestServer(t, func(hs *HTTPServer) {
+				hs.loginAttemptService = loginAttempts
+			})
+
+			req := server.NewPostRequest("/api/admin/login-attempts/unlock-ip", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			res, err := server.Send(webtest.RequestWithSignedInUser(req, tt.signedInUser))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			assert.Equal(t, tt.expectedResetIP, loginAttempts.ResetIPCalled)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
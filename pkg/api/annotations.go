@@ -67,6 +67,9 @@ func (hs *HTTPServer) GetAnnotations(c *contextmodel.ReqContext) response.Respon
 	if err != nil {
 		return response.Error(500, "Failed to get annotations", err)
 	}
+	if hs.annotationFederation != nil {
+		items = hs.annotationFederation.Merge(c.Req.Context(), query, items)
+	}
 
 	// since there are several annotations per dashboard, we can cache dashboard uid
 	dashboardCache := make(map[int64]*string)
@@ -44,6 +44,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/org"
 	publicdashboardsapi "github.com/grafana/grafana/pkg/services/publicdashboards/api"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -64,11 +65,17 @@ func (hs *HTTPServer) registerRoutes() {
 	reqOrgAdminDashOrFolderAdminOrTeamAdmin := middleware.OrgAdminDashOrFolderAdminOrTeamAdmin(hs.SQLStore, hs.DashboardService, hs.teamService)
 	reqCanAccessTeams := middleware.AdminOrEditorAndFeatureEnabled(hs.Cfg.EditorsCanAdmin)
 	reqSnapshotPublicModeOrSignedIn := middleware.SnapshotPublicModeOrSignedIn(hs.Cfg)
+	reqSignedInOrSignedRenderURL := middleware.ReqSignedInOrSignedRenderURL
 	redirectFromLegacyPanelEditURL := middleware.RedirectFromLegacyPanelEditURL(hs.Cfg)
 	ensureEditorOrViewerCanEdit := middleware.EnsureEditorOrViewerCanEdit(hs.Cfg)
 	authorize := ac.Middleware(hs.AccessControl)
 	authorizeInOrg := ac.AuthorizeInOrgMiddleware(hs.AccessControl, hs.accesscontrolService, hs.userService)
 	quota := middleware.Quota(hs.QuotaService)
+	rateLimit := middleware.RateLimit(hs.Cfg, hs.rateLimitService)
+	dashboardBodyLimit := middleware.RequestBodyLimit("dashboard", hs.Cfg.RequestLimits.DashboardMaxBodySize)
+	annotationBodyLimit := middleware.RequestBodyLimit("annotation", hs.Cfg.RequestLimits.AnnotationMaxBodySize)
+	queryLimits := middleware.QueryLimits(hs.Cfg)
+	idempotent := hs.idempotency.Middleware()
 
 	r := hs.RouteRegister
 
@@ -219,9 +226,22 @@ func (hs *HTTPServer) registerRoutes() {
 	// expose plugin file system assets
 	r.Get("/public/plugins/:pluginId/*", hs.getPluginAssets)
 
+	// rendered images missing from the local disk, stored by another instance
+	r.Get("/public/img/attachments/:filename", routing.Wrap(hs.GetRenderedImage))
+
 	r.Get("/swagger-ui", swaggerUI)
 	r.Get("/openapi3", openapi3)
 
+	// versioned resource APIs with list and watch semantics, for external controllers
+	r.Get("/openapi/v3/apis/:group/:version", routing.Wrap(hs.GetAPIsOpenAPI))
+	r.Group("/apis", func(apisRoute routing.RouteRegister) {
+		apisRoute.Get("/", routing.Wrap(hs.GetAPIGroupList))
+		apisRoute.Get("/:group", routing.Wrap(hs.GetAPIGroup))
+		apisRoute.Get("/:group/:version", routing.Wrap(hs.GetAPIResourceList))
+		apisRoute.Get("/:group/:version/namespaces/:namespace/:resource", routing.Wrap(hs.ListAPIObjects))
+		apisRoute.Get("/:group/:version/namespaces/:namespace/:resource/:name", routing.Wrap(hs.GetAPIObject))
+	}, reqSignedIn)
+
 	// authed api
 	r.Group("/api", func(apiRoute routing.RouteRegister) {
 		// user (signed in)
@@ -242,6 +262,10 @@ func (hs *HTTPServer) registerRoutes() {
 
 			userRoute.Post("/stars/dashboard/uid/:uid", routing.Wrap(hs.starApi.StarDashboardByUID))
 			userRoute.Delete("/stars/dashboard/uid/:uid", routing.Wrap(hs.starApi.UnstarDashboardByUID))
+			userRoute.Post("/stars/folder/uid/:uid", routing.Wrap(hs.starApi.StarFolderByUID))
+			userRoute.Delete("/stars/folder/uid/:uid", routing.Wrap(hs.starApi.UnstarFolderByUID))
+			userRoute.Post("/stars/datasource/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(ac.Parameter(":uid")))), routing.Wrap(hs.starApi.StarDatasourceByUID))
+			userRoute.Delete("/stars/datasource/uid/:uid", routing.Wrap(hs.starApi.UnstarDatasourceByUID))
 
 			userRoute.Put("/password", routing.Wrap(hs.ChangeUserPassword))
 			userRoute.Get("/quotas", routing.Wrap(hs.GetUserQuotas))
@@ -255,6 +279,17 @@ func (hs *HTTPServer) registerRoutes() {
 
 			userRoute.Get("/auth-tokens", routing.Wrap(hs.GetUserAuthTokens))
 			userRoute.Post("/revoke-auth-token", routing.Wrap(hs.RevokeUserAuthToken))
+
+			userRoute.Get("/export", routing.Wrap(hs.ExportUserData))
+			userRoute.Get("/deletion-request", routing.Wrap(hs.GetAccountDeletionRequest))
+			userRoute.Post("/deletion-request", routing.Wrap(hs.RequestAccountDeletion))
+			userRoute.Delete("/deletion-request", routing.Wrap(hs.CancelAccountDeletion))
+
+			userRoute.Get("/notifications", routing.Wrap(hs.GetUserNotifications))
+			userRoute.Post("/notifications/read", routing.Wrap(hs.MarkAllUserNotificationsRead))
+			userRoute.Post("/notifications/:notificationId/read", routing.Wrap(hs.MarkUserNotificationRead))
+			userRoute.Post("/notifications/:notificationId/unread", routing.Wrap(hs.MarkUserNotificationUnread))
+			userRoute.Delete("/notifications/:notificationId", routing.Wrap(hs.DeleteUserNotification))
 		}, reqSignedInNoAnonymous)
 
 		apiRoute.Group("/users", func(usersRoute routing.RouteRegister) {
@@ -281,6 +316,9 @@ func (hs *HTTPServer) registerRoutes() {
 			teamsRoute.Delete("/:teamId/members/:userId", authorize(reqCanAccessTeams, ac.EvalPermission(ac.ActionTeamsPermissionsWrite, ac.ScopeTeamsID)), routing.Wrap(hs.RemoveTeamMember))
 			teamsRoute.Get("/:teamId/preferences", authorize(reqCanAccessTeams, ac.EvalPermission(ac.ActionTeamsRead, ac.ScopeTeamsID)), routing.Wrap(hs.GetTeamPreferences))
 			teamsRoute.Put("/:teamId/preferences", authorize(reqCanAccessTeams, ac.EvalPermission(ac.ActionTeamsWrite, ac.ScopeTeamsID)), routing.Wrap(hs.UpdateTeamPreferences))
+			teamsRoute.Patch("/:teamId/preferences", authorize(reqCanAccessTeams, ac.EvalPermission(ac.ActionTeamsWrite, ac.ScopeTeamsID)), routing.Wrap(hs.PatchTeamPreferences))
+			teamsRoute.Put("/:teamId/parent", authorize(reqCanAccessTeams, ac.EvalPermission(ac.ActionTeamsWrite, ac.ScopeTeamsID)), routing.Wrap(hs.SetTeamParent))
+			teamsRoute.Get("/:teamId/children", authorize(reqCanAccessTeams, ac.EvalPermission(ac.ActionTeamsRead, ac.ScopeTeamsID)), routing.Wrap(hs.GetTeamChildren))
 		})
 
 		// team without requirement of user to be org admin
@@ -383,6 +421,7 @@ func (hs *HTTPServer) registerRoutes() {
 			keysRoute.Get("/", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionAPIKeyRead)), routing.Wrap(hs.GetAPIKeys))
 			keysRoute.Post("/", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionAPIKeyCreate)), quota(string(apikey.QuotaTargetSrv)), routing.Wrap(hs.AddAPIKey))
 			keysRoute.Delete("/:id", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionAPIKeyDelete, apikeyIDScope)), routing.Wrap(hs.DeleteAPIKey))
+			keysRoute.Get("/:id/usage", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionAPIKeyRead, apikeyIDScope)), routing.Wrap(hs.GetAPIKeyUsage))
 		})
 
 		// Preferences
@@ -396,7 +435,7 @@ func (hs *HTTPServer) registerRoutes() {
 			uidScope := datasources.ScopeProvider.GetResourceScopeUID(ac.Parameter(":uid"))
 			nameScope := datasources.ScopeProvider.GetResourceScopeName(ac.Parameter(":name"))
 			datasourceRoute.Get("/", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(hs.GetDataSources))
-			datasourceRoute.Post("/", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionCreate)), quota(string(datasources.QuotaTargetSrv)), routing.Wrap(hs.AddDataSource))
+			datasourceRoute.Post("/", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionCreate)), quota(string(datasources.QuotaTargetSrv)), idempotent, routing.Wrap(hs.AddDataSource))
 			datasourceRoute.Put("/:id", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, idScope)), routing.Wrap(hs.UpdateDataSourceByID))
 			datasourceRoute.Put("/uid/:uid", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(hs.UpdateDataSourceByUID))
 			datasourceRoute.Delete("/:id", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionDelete, idScope)), routing.Wrap(hs.DeleteDataSourceById))
@@ -404,6 +443,8 @@ func (hs *HTTPServer) registerRoutes() {
 			datasourceRoute.Delete("/name/:name", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionDelete, nameScope)), routing.Wrap(hs.DeleteDataSourceByName))
 			datasourceRoute.Get("/:id", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionRead, idScope)), routing.Wrap(hs.GetDataSourceById))
 			datasourceRoute.Get("/uid/:uid", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(hs.GetDataSourceByUID))
+			datasourceRoute.Get("/uid/:uid/teams", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionRead, uidScope)), routing.Wrap(hs.GetDataSourceTeams))
+			datasourceRoute.Put("/uid/:uid/teams", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(hs.SetDataSourceTeams))
 			datasourceRoute.Get("/name/:name", authorize(reqOrgAdmin, ac.EvalPermission(datasources.ActionRead, nameScope)), routing.Wrap(hs.GetDataSourceByName))
 			datasourceRoute.Get("/id/:name", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionIDRead, nameScope)), routing.Wrap(hs.GetDataSourceIdByName))
 		})
@@ -413,6 +454,7 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Get("/plugins/:pluginId/settings", routing.Wrap(hs.GetPluginSettingByID)) // RBAC check performed in handler for App Plugins
 		apiRoute.Get("/plugins/:pluginId/markdown/:name", routing.Wrap(hs.GetPluginMarkdown))
 		apiRoute.Get("/plugins/:pluginId/health", routing.Wrap(hs.CheckHealth))
+		apiRoute.Get("/plugins/:pluginId/health/details", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionSettingsRead)), routing.Wrap(hs.GetPluginHealthDetails))
 		apiRoute.Any("/plugins/:pluginId/resources", authorize(reqSignedIn, ac.EvalPermission(plugins.ActionAppAccess, pluginIDScope)), hs.CallResource)
 		apiRoute.Any("/plugins/:pluginId/resources/*", authorize(reqSignedIn, ac.EvalPermission(plugins.ActionAppAccess, pluginIDScope)), hs.CallResource)
 		apiRoute.Get("/plugins/errors", routing.Wrap(hs.GetPluginErrorsList))
@@ -430,19 +472,24 @@ func (hs *HTTPServer) registerRoutes() {
 			pluginRoute.Get("/:pluginId/dashboards/", reqOrgAdmin, routing.Wrap(hs.GetPluginDashboards))
 			pluginRoute.Post("/:pluginId/settings", authorize(reqOrgAdmin, ac.EvalPermission(plugins.ActionWrite, pluginIDScope)), routing.Wrap(hs.UpdatePluginSetting))
 			pluginRoute.Get("/:pluginId/metrics", reqOrgAdmin, routing.Wrap(hs.CollectPluginMetrics))
+			pluginRoute.Get("/:pluginId/secrets", authorize(reqOrgAdmin, ac.EvalPermission(plugins.ActionSecretsRead, pluginIDScope)), routing.Wrap(hs.ListPluginSecrets))
+			pluginRoute.Get("/:pluginId/secrets/:name", authorize(reqOrgAdmin, ac.EvalPermission(plugins.ActionSecretsRead, pluginIDScope)), routing.Wrap(hs.GetPluginSecret))
+			pluginRoute.Put("/:pluginId/secrets/:name", authorize(reqOrgAdmin, ac.EvalPermission(plugins.ActionSecretsWrite, pluginIDScope)), routing.Wrap(hs.SetPluginSecret))
+			pluginRoute.Delete("/:pluginId/secrets/:name", authorize(reqOrgAdmin, ac.EvalPermission(plugins.ActionSecretsWrite, pluginIDScope)), routing.Wrap(hs.DeletePluginSecret))
 		})
 
 		apiRoute.Get("/frontend/settings/", hs.GetFrontendSettings)
-		apiRoute.Any("/datasources/proxy/:id/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.ProxyDataSourceRequest)
-		apiRoute.Any("/datasources/proxy/uid/:uid/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.ProxyDataSourceRequestWithUID)
-		apiRoute.Any("/datasources/proxy/:id", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.ProxyDataSourceRequest)
-		apiRoute.Any("/datasources/proxy/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.ProxyDataSourceRequestWithUID)
+		apiRoute.Get("/frontend/capabilities", routing.Wrap(hs.GetFrontendCapabilities))
+		apiRoute.Any("/datasources/proxy/:id/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.ProxyDataSourceRequest)
+		apiRoute.Any("/datasources/proxy/uid/:uid/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.ProxyDataSourceRequestWithUID)
+		apiRoute.Any("/datasources/proxy/:id", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.ProxyDataSourceRequest)
+		apiRoute.Any("/datasources/proxy/uid/:uid", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.ProxyDataSourceRequestWithUID)
 		// Deprecated: use /datasources/uid/:uid/resources API instead.
-		apiRoute.Any("/datasources/:id/resources", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.CallDatasourceResource)
-		apiRoute.Any("/datasources/uid/:uid/resources", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.CallDatasourceResourceWithUID)
+		apiRoute.Any("/datasources/:id/resources", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.CallDatasourceResource)
+		apiRoute.Any("/datasources/uid/:uid/resources", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.CallDatasourceResourceWithUID)
 		// Deprecated: use /datasources/uid/:uid/resources/* API instead.
-		apiRoute.Any("/datasources/:id/resources/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.CallDatasourceResource)
-		apiRoute.Any("/datasources/uid/:uid/resources/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.CallDatasourceResourceWithUID)
+		apiRoute.Any("/datasources/:id/resources/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.CallDatasourceResource)
+		apiRoute.Any("/datasources/uid/:uid/resources/*", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), hs.denyRestrictedViewers, hs.CallDatasourceResourceWithUID)
 		// Deprecated: use /datasources/uid/:uid/health API instead.
 		apiRoute.Any("/datasources/:id/health", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), routing.Wrap(hs.CheckDatasourceHealth))
 		apiRoute.Any("/datasources/uid/:uid/health", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), routing.Wrap(hs.CheckDatasourceHealthWithUID))
@@ -476,6 +523,7 @@ func (hs *HTTPServer) registerRoutes() {
 				dashUidRoute.Get("/versions", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
 				dashUidRoute.Post("/restore", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
 				dashUidRoute.Get("/versions/:id", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
+				dashUidRoute.Patch("/panels/:panelId", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), dashboardBodyLimit, routing.Wrap(hs.PatchDashboardPanel))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
 					dashboardPermissionRoute.Post("/", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(hs.UpdateDashboardPermissions))
@@ -492,7 +540,7 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Post("/validate", authorize(reqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.ValidateDashboard))
 			dashboardRoute.Post("/trim", routing.Wrap(hs.TrimDashboard))
 
-			dashboardRoute.Post("/db", authorize(reqSignedIn, ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), routing.Wrap(hs.PostDashboard))
+			dashboardRoute.Post("/db", authorize(reqSignedIn, ac.EvalAny(ac.EvalPermission(dashboards.ActionDashboardsCreate), ac.EvalPermission(dashboards.ActionDashboardsWrite))), dashboardBodyLimit, idempotent, routing.Wrap(hs.PostDashboard))
 			dashboardRoute.Get("/home", routing.Wrap(hs.GetHomeDashboard))
 			dashboardRoute.Get("/tags", hs.GetDashboardTags)
 
@@ -529,12 +577,14 @@ func (hs *HTTPServer) registerRoutes() {
 		})
 
 		// Search
+		apiRoute.Get("/stars", routing.Wrap(hs.starApi.ListStars))
 		apiRoute.Get("/search/sorting", routing.Wrap(hs.ListSortOptions))
-		apiRoute.Get("/search/", routing.Wrap(hs.Search))
+		apiRoute.Get("/search/", rateLimit(ratelimit.GroupSearch), routing.Wrap(hs.Search))
 
 		// metrics
 		// DataSource w/ expressions
-		apiRoute.Post("/ds/query", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), routing.Wrap(hs.QueryMetricsV2))
+		apiRoute.Post("/ds/query", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), rateLimit(ratelimit.GroupQuery), queryLimits, hs.restrictViewerQueries, routing.Wrap(hs.QueryMetricsV2))
+		apiRoute.Post("/ds/query/federated", authorize(reqSignedIn, ac.EvalPermission(datasources.ActionQuery)), rateLimit(ratelimit.GroupQuery), queryLimits, hs.restrictViewerQueries, routing.Wrap(hs.QueryMetricsFederated))
 
 		apiRoute.Group("/alerts", func(alertsRoute routing.RouteRegister) {
 			alertsRoute.Post("/test", routing.Wrap(hs.AlertTest))
@@ -576,17 +626,19 @@ func (hs *HTTPServer) registerRoutes() {
 		apiRoute.Post("/annotations/mass-delete", authorize(reqOrgAdmin, ac.EvalPermission(ac.ActionAnnotationsDelete)), routing.Wrap(hs.MassDeleteAnnotations))
 
 		apiRoute.Group("/annotations", func(annotationsRoute routing.RouteRegister) {
-			annotationsRoute.Post("/", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsCreate)), routing.Wrap(hs.PostAnnotation))
+			annotationsRoute.Post("/", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsCreate)), annotationBodyLimit, routing.Wrap(hs.PostAnnotation))
 			annotationsRoute.Get("/:annotationId", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsRead, ac.ScopeAnnotationsID)), routing.Wrap(hs.GetAnnotationByID))
 			annotationsRoute.Delete("/:annotationId", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsDelete, ac.ScopeAnnotationsID)), routing.Wrap(hs.DeleteAnnotationByID))
-			annotationsRoute.Put("/:annotationId", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsWrite, ac.ScopeAnnotationsID)), routing.Wrap(hs.UpdateAnnotation))
-			annotationsRoute.Patch("/:annotationId", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsWrite, ac.ScopeAnnotationsID)), routing.Wrap(hs.PatchAnnotation))
-			annotationsRoute.Post("/graphite", authorize(reqEditorRole, ac.EvalPermission(ac.ActionAnnotationsCreate, ac.ScopeAnnotationsTypeOrganization)), routing.Wrap(hs.PostGraphiteAnnotation))
+			annotationsRoute.Put("/:annotationId", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsWrite, ac.ScopeAnnotationsID)), annotationBodyLimit, routing.Wrap(hs.UpdateAnnotation))
+			annotationsRoute.Patch("/:annotationId", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsWrite, ac.ScopeAnnotationsID)), annotationBodyLimit, routing.Wrap(hs.PatchAnnotation))
+			annotationsRoute.Post("/graphite", authorize(reqEditorRole, ac.EvalPermission(ac.ActionAnnotationsCreate, ac.ScopeAnnotationsTypeOrganization)), annotationBodyLimit, routing.Wrap(hs.PostGraphiteAnnotation))
 			annotationsRoute.Get("/tags", authorize(reqSignedIn, ac.EvalPermission(ac.ActionAnnotationsRead)), routing.Wrap(hs.GetAnnotationTags))
 		})
 
 		apiRoute.Post("/frontend-metrics", routing.Wrap(hs.PostFrontendMetrics))
 
+		apiRoute.Post("/render/sign", routing.Wrap(hs.SignRenderURL))
+
 		apiRoute.Group("/live", func(liveRoute routing.RouteRegister) {
 			// the channel path is in the name
 			liveRoute.Post("/publish", routing.Wrap(hs.Live.HandleHTTPPublish))
@@ -623,7 +675,14 @@ func (hs *HTTPServer) registerRoutes() {
 	// admin api
 	r.Group("/api/admin", func(adminRoute routing.RouteRegister) {
 		adminRoute.Get("/settings", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionSettingsRead)), routing.Wrap(hs.AdminGetSettings))
+		adminRoute.Get("/settings/export", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionSettingsRead)), routing.Wrap(hs.AdminExportSettings))
+		adminRoute.Get("/settings/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionSettingsRead)), routing.Wrap(hs.AdminGetSettingsReload))
+		adminRoute.Post("/settings/reload", reqGrafanaAdmin, routing.Wrap(hs.AdminReloadSettings))
 		adminRoute.Get("/stats", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionServerStatsRead)), routing.Wrap(hs.AdminGetStats))
+		adminRoute.Get("/migrations", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionSettingsRead)), routing.Wrap(hs.AdminGetMigrationStatus))
+		adminRoute.Get("/log-levels", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionSettingsRead)), routing.Wrap(hs.AdminGetLogLevels))
+		adminRoute.Put("/log-levels/:name", reqGrafanaAdmin, routing.Wrap(hs.AdminSetLogLevel))
+		adminRoute.Delete("/log-levels/:name", reqGrafanaAdmin, routing.Wrap(hs.AdminResetLogLevel))
 		adminRoute.Post("/pause-all-alerts", reqGrafanaAdmin, routing.Wrap(hs.PauseAllAlerts(setting.AlertingEnabled)))
 
 		adminRoute.Post("/encryption/rotate-data-keys", reqGrafanaAdmin, routing.Wrap(hs.AdminRotateDataEncryptionKeys))
@@ -633,12 +692,28 @@ func (hs *HTTPServer) registerRoutes() {
 		adminRoute.Post("/encryption/migrate-secrets/to-plugin", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateSecretsToPlugin))
 		adminRoute.Post("/encryption/migrate-secrets/from-plugin", reqGrafanaAdmin, routing.Wrap(hs.AdminMigrateSecretsFromPlugin))
 		adminRoute.Post("/encryption/delete-secretsmanagerplugin-secrets", reqGrafanaAdmin, routing.Wrap(hs.AdminDeleteAllSecretsManagerPluginSecrets))
+		adminRoute.Get("/encryption/secrets/usage", reqGrafanaAdmin, routing.Wrap(hs.AdminGetSecretsUsage))
+		adminRoute.Post("/encryption/secrets/references", reqGrafanaAdmin, routing.Wrap(hs.AdminAddSecretReference))
+		adminRoute.Delete("/encryption/secrets/references", reqGrafanaAdmin, routing.Wrap(hs.AdminRemoveSecretReference))
+
+		adminRoute.Get("/short-urls", reqGrafanaAdmin, routing.Wrap(hs.AdminSearchShortURLs))
+		adminRoute.Get("/emails/deliveries", reqGrafanaAdmin, routing.Wrap(hs.AdminGetEmailDeliveries))
+		adminRoute.Post("/announcements", reqGrafanaAdmin, routing.Wrap(hs.AdminSendAnnouncement))
+
+		adminRoute.Post("/login-attempts/unlock-ip", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersWrite, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminUnlockIPAddress))
+
+		adminRoute.Get("/quotas", reqGrafanaAdmin, routing.Wrap(hs.GetQuotaTargets))
+		adminRoute.Get("/quotas/global", reqGrafanaAdmin, routing.Wrap(hs.GetGlobalQuotas))
+		adminRoute.Put("/quotas/global/:target", reqGrafanaAdmin, routing.Wrap(hs.UpdateGlobalQuota))
 
 		adminRoute.Post("/provisioning/dashboards/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDashboards)), routing.Wrap(hs.AdminProvisioningReloadDashboards))
 		adminRoute.Post("/provisioning/plugins/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersPlugins)), routing.Wrap(hs.AdminProvisioningReloadPlugins))
 		adminRoute.Post("/provisioning/datasources/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersDatasources)), routing.Wrap(hs.AdminProvisioningReloadDatasources))
 		adminRoute.Post("/provisioning/notifications/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersNotifications)), routing.Wrap(hs.AdminProvisioningReloadNotifications))
 		adminRoute.Post("/provisioning/alerting/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAlertRules)), routing.Wrap(hs.AdminProvisioningReloadAlerting))
+		adminRoute.Get("/provisioning/status", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersAll)), routing.Wrap(hs.AdminProvisioningGetStatus))
+		adminRoute.Post("/provisioning/reload", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningReload, ScopeProvisionersAll)), routing.Wrap(hs.AdminProvisioningReloadAll))
+		adminRoute.Get("/provisioning/reload/:jobId", authorize(reqGrafanaAdmin, ac.EvalPermission(ActionProvisioningRead, ScopeProvisionersAll)), routing.Wrap(hs.AdminProvisioningGetReloadJob))
 	}, reqSignedIn)
 
 	// Administering users
@@ -646,11 +721,19 @@ func (hs *HTTPServer) registerRoutes() {
 		userIDScope := ac.Scope("global.users", "id", ac.Parameter(":id"))
 
 		adminUserRoute.Post("/", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersCreate)), routing.Wrap(hs.AdminCreateUser))
+		adminUserRoute.Post("/bulk/create", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersCreate)), routing.Wrap(hs.AdminBulkCreateUsers))
+		adminUserRoute.Post("/bulk/role", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionOrgUsersWrite, ac.ScopeUsersAll)), routing.Wrap(hs.AdminBulkUpdateUserRoles))
+		adminUserRoute.Post("/bulk/disable", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersDisable, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminBulkDisableUsers))
+		adminUserRoute.Post("/bulk/delete", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersDelete, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminBulkDeleteUsers))
+		adminUserRoute.Get("/deletion-requests", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersRead, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminListAccountDeletionRequests))
+		adminUserRoute.Post("/deletion-requests/:requestId/approve", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersDelete, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminApproveAccountDeletion))
+		adminUserRoute.Post("/deletion-requests/:requestId/reject", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersDelete, ac.ScopeGlobalUsersAll)), routing.Wrap(hs.AdminRejectAccountDeletion))
 		adminUserRoute.Put("/:id/password", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersPasswordUpdate, userIDScope)), routing.Wrap(hs.AdminUpdateUserPassword))
 		adminUserRoute.Put("/:id/permissions", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersPermissionsUpdate, userIDScope)), routing.Wrap(hs.AdminUpdateUserPermissions))
 		adminUserRoute.Delete("/:id", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersDelete, userIDScope)), routing.Wrap(hs.AdminDeleteUser))
 		adminUserRoute.Post("/:id/disable", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersDisable, userIDScope)), routing.Wrap(hs.AdminDisableUser))
 		adminUserRoute.Post("/:id/enable", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersEnable, userIDScope)), routing.Wrap(hs.AdminEnableUser))
+		adminUserRoute.Post("/:id/unlock", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersWrite, userIDScope)), routing.Wrap(hs.AdminUnlockUser))
 		adminUserRoute.Get("/:id/quotas", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersQuotasList, userIDScope)), routing.Wrap(hs.GetUserQuotas))
 		adminUserRoute.Put("/:id/quotas/:target", authorize(reqGrafanaAdmin, ac.EvalPermission(ac.ActionUsersQuotasUpdate, userIDScope)), routing.Wrap(hs.UpdateUserQuota))
 
@@ -660,7 +743,7 @@ func (hs *HTTPServer) registerRoutes() {
 	}, reqSignedIn)
 
 	// rendering
-	r.Get("/render/*", reqSignedIn, hs.RenderToPng)
+	r.Get("/render/*", reqSignedInOrSignedRenderURL, rateLimit(ratelimit.GroupRender), hs.RenderToPng)
 
 	// grafana.net proxy
 	r.Any("/api/gnet/*", reqSignedIn, hs.ProxyGnetRequest)
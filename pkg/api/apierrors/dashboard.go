@@ -30,6 +30,16 @@ func ToDashboardErrorResponse(ctx context.Context, pluginStore plugins.Store, er
 		return response.Error(http.StatusBadRequest, err.Error(), nil)
 	}
 
+	var pendingErr dashboards.PendingApprovalError
+	if ok := errors.As(err, &pendingErr); ok {
+		return response.JSON(http.StatusAccepted, util.DynMap{
+			"status":   "pending-approval",
+			"message":  "The change was submitted for approval",
+			"changeId": pendingErr.ChangeID,
+			"diffUrl":  pendingErr.DiffURL,
+		})
+	}
+
 	var validationErr alerting.ValidationError
 	if ok := errors.As(err, &validationErr); ok {
 		return response.Error(http.StatusUnprocessableEntity, validationErr.Error(), err)
@@ -45,5 +55,5 @@ func ToDashboardErrorResponse(ctx context.Context, pluginStore plugins.Store, er
 		return response.JSON(http.StatusPreconditionFailed, util.DynMap{"status": "plugin-dashboard", "message": message})
 	}
 
-	return response.Error(http.StatusInternalServerError, "Failed to save dashboard", err)
+	return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save dashboard", err)
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/apikeygen"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -48,6 +49,7 @@ func (hs *HTTPServer) GetAPIKeys(c *contextmodel.ReqContext) response.Response {
 			Role:       t.Role,
 			Expiration: expiration,
 			LastUsedAt: t.LastUsedAt,
+			LastUsedIP: t.LastUsedIP,
 		}
 	}
 
@@ -92,6 +94,32 @@ func (hs *HTTPServer) DeleteAPIKey(c *contextmodel.ReqContext) response.Response
 	return response.Success("API key deleted")
 }
 
+// swagger:route GET /auth/keys/{id}/usage api_keys getAPIkeyUsage
+//
+// Get API key usage.
+//
+// Will return the number of calls by endpoint made with the API key, the most used endpoints first.
+//
+// Responses:
+// 200: getAPIkeyUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetAPIKeyUsage(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	usage, err := hs.apiKeyService.GetAPIKeyUsage(c.Req.Context(), &apikey.GetUsageQuery{OrgID: c.OrgID, APIKeyID: id})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get API key usage", err)
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
+
 // swagger:route POST /auth/keys api_keys addAPIkey
 //
 // Creates an API key.
@@ -117,11 +145,11 @@ func (hs *HTTPServer) AddAPIKey(c *contextmodel.ReqContext) response.Response {
 		return response.Error(http.StatusForbidden, "Cannot assign a role higher than user's role", nil)
 	}
 
-	if hs.Cfg.ApiKeyMaxSecondsToLive != -1 {
+	if maxSecondsToLive := hs.orgSettingsService.GetInt64(c.Req.Context(), c.OrgID, orgsettings.APIKeyMaxSecondsToLive); maxSecondsToLive != -1 {
 		if cmd.SecondsToLive == 0 {
 			return response.Error(400, "Number of seconds before expiration should be set", nil)
 		}
-		if cmd.SecondsToLive > hs.Cfg.ApiKeyMaxSecondsToLive {
+		if cmd.SecondsToLive > maxSecondsToLive {
 			return response.Error(400, "Number of seconds before expiration is greater than the global limit", nil)
 		}
 	}
@@ -176,6 +204,19 @@ type DeleteAPIkeyParams struct {
 	ID int64 `json:"id"`
 }
 
+// swagger:parameters getAPIkeyUsage
+type GetAPIkeyUsageParams struct {
+	// in:path
+	// required:true
+	ID int64 `json:"id"`
+}
+
+// swagger:response getAPIkeyUsageResponse
+type GetAPIkeyUsageResponse struct {
+	// in: body
+	Body []*apikey.EndpointUsage `json:"body"`
+}
+
 // swagger:response getAPIkeyResponse
 type GetAPIkeyResponse struct {
 	// The response message
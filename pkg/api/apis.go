@@ -0,0 +1,469 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// The /apis endpoints expose the core resources with the list and watch semantics of the Kubernetes APIs, as a
+// stable contract for external controllers. Every resource belongs to its own API group, is namespaced by
+// organization and is served in a single version.
+
+const (
+	apisVersion = "v1alpha1"
+	// apisMaxWatchTimeout is the maximum duration of a watch, clients watch again from the last resource version
+	apisMaxWatchTimeout = 30 * time.Minute
+	// apisDefaultNamespace is the namespace of the main organization, the others are org-<id>
+	apisDefaultNamespace = "default"
+)
+
+// apisWatchPollInterval is the interval at which a watch lists the resources to detect the changes
+var apisWatchPollInterval = 5 * time.Second
+
+var errAPIsInvalidContinue = errors.New("invalid continue token")
+
+// apisResource describes a resource served by the /apis endpoints.
+type apisResource struct {
+	group    string
+	resource string
+	singular string
+	kind     string
+	// fields are the field selectors supported in addition to metadata.name and metadata.namespace
+	fields []string
+	// spec is the OpenAPI schema of the spec of the objects
+	spec map[string]interface{}
+	// list returns the objects of the organization visible to the signed in user, only the named ones if any
+	list func(c *contextmodel.ReqContext, orgID int64, names []string) ([]*apisObject, error)
+}
+
+type apisObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              interface{} `json:"spec"`
+
+	// fields are the values of the field selectors of the object
+	fields fields.Set
+}
+
+type apisObjectList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []*apisObject `json:"items"`
+}
+
+type apisWatchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object interface{}     `json:"object"`
+}
+
+// newAPIsObject returns the object of the resource, updated is used as its resource version.
+func newAPIsObject(res *apisResource, orgID int64, name string, generation int64, created, updated time.Time, spec interface{}, set fields.Set) *apisObject {
+	namespace := apisNamespace(orgID)
+	if set == nil {
+		set = fields.Set{}
+	}
+	set["metadata.name"] = name
+	set["metadata.namespace"] = namespace
+	return &apisObject{
+		TypeMeta: metav1.TypeMeta{Kind: res.kind, APIVersion: res.group + "/" + apisVersion},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			ResourceVersion:   strconv.FormatInt(updated.UnixMilli(), 10),
+			Generation:        generation,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec:   spec,
+		fields: set,
+	}
+}
+
+func apisNamespace(orgID int64) string {
+	if orgID == 1 {
+		return apisDefaultNamespace
+	}
+	return fmt.Sprintf("org-%d", orgID)
+}
+
+// apisOrgID returns the organization of the namespace, false if the namespace is invalid.
+func apisOrgID(namespace string) (int64, bool) {
+	if namespace == apisDefaultNamespace {
+		return 1, true
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(namespace, "org-"), 10, 64)
+	if !strings.HasPrefix(namespace, "org-") || err != nil || id <= 1 {
+		return 0, false
+	}
+	return id, true
+}
+
+func apisResourceVersion(obj *apisObject) int64 {
+	rv, _ := strconv.ParseInt(obj.ResourceVersion, 10, 64)
+	return rv
+}
+
+func apisError(code int, reason metav1.StatusReason, message string) response.Response {
+	return response.JSON(code, metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
+
+func (hs *HTTPServer) apisInternalError(message string, err error) response.Response {
+	hs.log.Error(message, "error", err)
+	return apisError(http.StatusInternalServerError, metav1.StatusReasonInternalError, message)
+}
+
+// apisGroups returns the API groups, sorted by name.
+func (hs *HTTPServer) apisGroups() []metav1.APIGroup {
+	var groups []metav1.APIGroup
+	seen := map[string]bool{}
+	for _, res := range hs.apisResources() {
+		if seen[res.group] {
+			continue
+		}
+		seen[res.group] = true
+		version := metav1.GroupVersionForDiscovery{GroupVersion: res.group + "/" + apisVersion, Version: apisVersion}
+		groups = append(groups, metav1.APIGroup{
+			TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+			Name:             res.group,
+			Versions:         []metav1.GroupVersionForDiscovery{version},
+			PreferredVersion: version,
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+func (hs *HTTPServer) apisGroupResources(group string) []*apisResource {
+	var resources []*apisResource
+	for _, res := range hs.apisResources() {
+		if res.group == group {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// GetAPIGroupList returns the API groups of the /apis endpoints.
+func (hs *HTTPServer) GetAPIGroupList(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, metav1.APIGroupList{
+		TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+		Groups:   hs.apisGroups(),
+	})
+}
+
+// GetAPIGroup returns an API group and its versions.
+func (hs *HTTPServer) GetAPIGroup(c *contextmodel.ReqContext) response.Response {
+	group := web.Params(c.Req)[":group"]
+	for _, g := range hs.apisGroups() {
+		if g.Name == group {
+			return response.JSON(http.StatusOK, g)
+		}
+	}
+	return apisError(http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("API group %q not found", group))
+}
+
+// GetAPIResourceList returns the resources of a version of an API group.
+func (hs *HTTPServer) GetAPIResourceList(c *contextmodel.ReqContext) response.Response {
+	group, version := web.Params(c.Req)[":group"], web.Params(c.Req)[":version"]
+	resources := hs.apisGroupResources(group)
+	if len(resources) == 0 || version != apisVersion {
+		return apisError(http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("API version %s/%s not found", group, version))
+	}
+
+	list := metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: group + "/" + version,
+	}
+	for _, res := range resources {
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:         res.resource,
+			SingularName: res.singular,
+			Namespaced:   true,
+			Kind:         res.kind,
+			Verbs:        metav1.Verbs{"get", "list", "watch"},
+		})
+	}
+	return response.JSON(http.StatusOK, list)
+}
+
+// resolveAPIsResource returns the resource and the organization of the request, or the response of the failure.
+func (hs *HTTPServer) resolveAPIsResource(c *contextmodel.ReqContext) (*apisResource, int64, response.Response) {
+	params := web.Params(c.Req)
+	var res *apisResource
+	for _, r := range hs.apisGroupResources(params[":group"]) {
+		if r.resource == params[":resource"] {
+			res = r
+		}
+	}
+	if res == nil || params[":version"] != apisVersion {
+		return nil, 0, apisError(http.StatusNotFound, metav1.StatusReasonNotFound,
+			fmt.Sprintf("resource %q not found in %s/%s", params[":resource"], params[":group"], params[":version"]))
+	}
+
+	orgID, ok := apisOrgID(params[":namespace"])
+	if !ok {
+		return nil, 0, apisError(http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("namespace %q not found", params[":namespace"]))
+	}
+	if orgID != c.OrgID {
+		return nil, 0, apisError(http.StatusForbidden, metav1.StatusReasonForbidden,
+			fmt.Sprintf("namespace %q is not the namespace of the current organization %q", params[":namespace"], apisNamespace(c.OrgID)))
+	}
+	return res, orgID, nil
+}
+
+// GetAPIObject returns an object of a resource by name.
+func (hs *HTTPServer) GetAPIObject(c *contextmodel.ReqContext) response.Response {
+	res, orgID, errResp := hs.resolveAPIsResource(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	name := web.Params(c.Req)[":name"]
+	objs, err := res.list(c, orgID, []string{name})
+	if err != nil {
+		return hs.apisInternalError(fmt.Sprintf("Failed to get %s", res.resource), err)
+	}
+	for _, obj := range objs {
+		if obj.Name == name {
+			return response.JSON(http.StatusOK, obj)
+		}
+	}
+	return apisError(http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("%s %q not found", res.resource, name))
+}
+
+// ListAPIObjects lists the objects of a resource matching the field selector, or watches them if watch is set.
+func (hs *HTTPServer) ListAPIObjects(c *contextmodel.ReqContext) response.Response {
+	res, orgID, errResp := hs.resolveAPIsResource(c)
+	if errResp != nil {
+		return errResp
+	}
+
+	selector, err := parseAPIsFieldSelector(res, c.Query("fieldSelector"))
+	if err != nil {
+		return apisError(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+	}
+
+	if c.QueryBool("watch") {
+		since, err := parseAPIsResourceVersion(c.Query("resourceVersion"))
+		if err != nil {
+			return apisError(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		}
+		return hs.watchAPIObjects(c, res, orgID, selector, since)
+	}
+
+	objs, err := hs.listAPIObjects(c, res, orgID, selector)
+	if err != nil {
+		return hs.apisInternalError(fmt.Sprintf("Failed to list %s", res.resource), err)
+	}
+
+	list := apisObjectList{
+		TypeMeta: metav1.TypeMeta{Kind: res.kind + "List", APIVersion: res.group + "/" + apisVersion},
+		Items:    []*apisObject{},
+	}
+	var rv int64
+	for _, obj := range objs {
+		if v := apisResourceVersion(obj); v > rv {
+			rv = v
+		}
+	}
+	list.ResourceVersion = strconv.FormatInt(rv, 10)
+
+	offset, err := decodeAPIsContinue(c.Query("continue"))
+	if err != nil || offset > len(objs) {
+		return apisError(http.StatusBadRequest, metav1.StatusReasonBadRequest, errAPIsInvalidContinue.Error())
+	}
+	objs = objs[offset:]
+	if limit := int(c.QueryInt64("limit")); limit > 0 && limit < len(objs) {
+		objs = objs[:limit]
+		list.Continue = encodeAPIsContinue(offset + limit)
+	}
+	list.Items = append(list.Items, objs...)
+
+	return response.JSON(http.StatusOK, list)
+}
+
+// listAPIObjects returns the objects matching the selector, sorted by name.
+func (hs *HTTPServer) listAPIObjects(c *contextmodel.ReqContext, res *apisResource, orgID int64, selector fields.Selector) ([]*apisObject, error) {
+	all, err := res.list(c, orgID, nil)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]*apisObject, 0, len(all))
+	for _, obj := range all {
+		if selector.Matches(obj.fields) {
+			objs = append(objs, obj)
+		}
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Name < objs[j].Name })
+	return objs, nil
+}
+
+// watchAPIObjects streams the changes of the objects as newline delimited watch events. The objects changed since
+// the resource version are sent first, all of them if it's not set, then the changes are detected by listing the
+// objects periodically until the request is canceled or timeoutSeconds is reached.
+func (hs *HTTPServer) watchAPIObjects(c *contextmodel.ReqContext, res *apisResource, orgID int64, selector fields.Selector, since int64) response.Response {
+	timeout := apisMaxWatchTimeout
+	if s := c.QueryInt64("timeoutSeconds"); s > 0 && time.Duration(s)*time.Second < timeout {
+		timeout = time.Duration(s) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(c.Req.Context(), timeout)
+	defer cancel()
+
+	objs, err := hs.listAPIObjects(c, res, orgID, selector)
+	if err != nil {
+		return hs.apisInternalError(fmt.Sprintf("Failed to watch %s", res.resource), err)
+	}
+
+	c.Resp.Header().Set("Content-Type", "application/json")
+	c.Resp.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(c.Resp)
+	send := func(eventType watch.EventType, obj interface{}) error {
+		if err := enc.Encode(apisWatchEvent{Type: eventType, Object: obj}); err != nil {
+			return err
+		}
+		c.Resp.Flush()
+		return nil
+	}
+
+	known := make(map[string]*apisObject, len(objs))
+	for _, obj := range objs {
+		known[obj.Name] = obj
+		if since >= 0 && apisResourceVersion(obj) <= since {
+			continue
+		}
+		eventType := watch.Added
+		if since >= 0 && obj.CreationTimestamp.UnixMilli() <= since {
+			eventType = watch.Modified
+		}
+		if err := send(eventType, obj); err != nil {
+			return nil
+		}
+	}
+	c.Resp.Flush()
+
+	ticker := time.NewTicker(apisWatchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		objs, err := hs.listAPIObjects(c, res, orgID, selector)
+		if err != nil {
+			// the status was already sent, the failure is reported in the stream
+			hs.log.Error("Failed to watch resource", "resource", res.resource, "error", err)
+			_ = send(watch.Error, metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure,
+				Message:  fmt.Sprintf("Failed to watch %s", res.resource),
+				Reason:   metav1.StatusReasonInternalError,
+				Code:     http.StatusInternalServerError,
+			})
+			return nil
+		}
+
+		current := make(map[string]*apisObject, len(objs))
+		for _, obj := range objs {
+			current[obj.Name] = obj
+			prev, ok := known[obj.Name]
+			switch {
+			case !ok:
+				err = send(watch.Added, obj)
+			case prev.ResourceVersion != obj.ResourceVersion || prev.Generation != obj.Generation:
+				err = send(watch.Modified, obj)
+			}
+			if err != nil {
+				return nil
+			}
+		}
+		var deleted []string
+		for name := range known {
+			if _, ok := current[name]; !ok {
+				deleted = append(deleted, name)
+			}
+		}
+		sort.Strings(deleted)
+		for _, name := range deleted {
+			if err := send(watch.Deleted, known[name]); err != nil {
+				return nil
+			}
+		}
+		known = current
+	}
+}
+
+// parseAPIsFieldSelector parses the field selector, only the fields of the resource are supported.
+func parseAPIsFieldSelector(res *apisResource, s string) (fields.Selector, error) {
+	selector, err := fields.ParseSelector(s)
+	if err != nil {
+		return nil, err
+	}
+	supported := append([]string{"metadata.name", "metadata.namespace"}, res.fields...)
+	for _, r := range selector.Requirements() {
+		found := false
+		for _, f := range supported {
+			if r.Field == f {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("field %q is not a supported field selector of %s, supported fields are %s", r.Field, res.resource, strings.Join(supported, ", "))
+		}
+	}
+	return selector, nil
+}
+
+// parseAPIsResourceVersion returns the resource version, -1 if it's not set.
+func parseAPIsResourceVersion(s string) (int64, error) {
+	if s == "" || s == "0" {
+		return -1, nil
+	}
+	rv, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || rv < 0 {
+		return 0, fmt.Errorf("invalid resource version %q", s)
+	}
+	return rv, nil
+}
+
+func encodeAPIsContinue(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeAPIsContinue(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errAPIsInvalidContinue
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, errAPIsInvalidContinue
+	}
+	return offset, nil
+}
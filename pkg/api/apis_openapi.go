@@ -0,0 +1,144 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// apisObjectSchema returns the OpenAPI schema of an object with the given properties.
+func apisObjectSchema(properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func apisSchemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func apisParameter(name, in, schemaType, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"in":          in,
+		"required":    in == "path",
+		"description": description,
+		"schema":      map[string]interface{}{"type": schemaType},
+	}
+}
+
+func apisJSONResponse(description, schema string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": apisSchemaRef(schema)},
+		},
+	}
+}
+
+// GetAPIsOpenAPI returns the OpenAPI v3 document of a version of an API group.
+func (hs *HTTPServer) GetAPIsOpenAPI(c *contextmodel.ReqContext) response.Response {
+	group, version := web.Params(c.Req)[":group"], web.Params(c.Req)[":version"]
+	resources := hs.apisGroupResources(group)
+	if len(resources) == 0 || version != apisVersion {
+		return apisError(http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("API version %s/%s not found", group, version))
+	}
+	return response.JSON(http.StatusOK, apisOpenAPIDocument(group, resources))
+}
+
+func apisOpenAPIDocument(group string, resources []*apisResource) map[string]interface{} {
+	namespace := apisParameter("namespace", "path", "string", "The namespace of the organization, default for the main organization and org-<id> for the others")
+	errorResponses := map[string]interface{}{
+		"400": apisJSONResponse("Bad request", "Status"),
+		"401": apisJSONResponse("Unauthorized", "Status"),
+		"403": apisJSONResponse("Forbidden", "Status"),
+		"404": apisJSONResponse("Not found", "Status"),
+	}
+	withErrors := func(ok map[string]interface{}) map[string]interface{} {
+		responses := map[string]interface{}{"200": ok}
+		for code, r := range errorResponses {
+			responses[code] = r
+		}
+		return responses
+	}
+
+	paths := map[string]interface{}{}
+	schemas := map[string]interface{}{
+		"ObjectMeta": apisObjectSchema(map[string]interface{}{
+			"name":              map[string]interface{}{"type": "string", "description": "The UID of the object"},
+			"namespace":         map[string]interface{}{"type": "string"},
+			"resourceVersion":   map[string]interface{}{"type": "string"},
+			"generation":        map[string]interface{}{"type": "integer", "format": "int64"},
+			"creationTimestamp": map[string]interface{}{"type": "string", "format": "date-time"},
+		}),
+		"ListMeta": apisObjectSchema(map[string]interface{}{
+			"resourceVersion": map[string]interface{}{"type": "string"},
+			"continue":        map[string]interface{}{"type": "string"},
+		}),
+		"Status": apisObjectSchema(map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"status":     map[string]interface{}{"type": "string"},
+			"message":    map[string]interface{}{"type": "string"},
+			"reason":     map[string]interface{}{"type": "string"},
+			"code":       map[string]interface{}{"type": "integer", "format": "int32"},
+		}),
+		"WatchEvent": apisObjectSchema(map[string]interface{}{
+			"type":   map[string]interface{}{"type": "string", "enum": []string{"ADDED", "MODIFIED", "DELETED", "ERROR"}},
+			"object": map[string]interface{}{"type": "object"},
+		}),
+	}
+
+	for _, res := range resources {
+		base := fmt.Sprintf("/apis/%s/%s/namespaces/{namespace}/%s", group, apisVersion, res.resource)
+		paths[base] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "list" + res.kind,
+				"description": fmt.Sprintf("List or watch the %s of the namespace. The supported field selectors are metadata.name, metadata.namespace and %s.", res.resource, strings.Join(res.fields, ", ")),
+				"parameters": []interface{}{
+					namespace,
+					apisParameter("fieldSelector", "query", "string", "Restrict the list of returned objects by their fields"),
+					apisParameter("limit", "query", "integer", "The maximum number of objects to return"),
+					apisParameter("continue", "query", "string", "The continue token of the previous page"),
+					apisParameter("watch", "query", "boolean", "Watch the changes of the objects, returned as a stream of newline delimited WatchEvent"),
+					apisParameter("resourceVersion", "query", "string", "Only watch the changes that happened after this resource version"),
+					apisParameter("timeoutSeconds", "query", "integer", "The duration of the watch"),
+				},
+				"responses": withErrors(apisJSONResponse("OK", res.kind+"List")),
+			},
+		}
+		paths[base+"/{name}"] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": "read" + res.kind,
+				"parameters":  []interface{}{namespace, apisParameter("name", "path", "string", "The name of the object")},
+				"responses":   withErrors(apisJSONResponse("OK", res.kind)),
+			},
+		}
+
+		schemas[res.kind] = apisObjectSchema(map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"metadata":   apisSchemaRef("ObjectMeta"),
+			"spec":       res.spec,
+		})
+		schemas[res.kind+"List"] = apisObjectSchema(map[string]interface{}{
+			"apiVersion": map[string]interface{}{"type": "string"},
+			"kind":       map[string]interface{}{"type": "string"},
+			"metadata":   apisSchemaRef("ListMeta"),
+			"items":      map[string]interface{}{"type": "array", "items": apisSchemaRef(res.kind)},
+		})
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info":    map[string]interface{}{"title": group, "version": apisVersion},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"k8s.io/apimachinery/pkg/fields"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/search/model"
+)
+
+const (
+	apisDashboardsGroup  = "dashboards.grafana.com"
+	apisFoldersGroup     = "folders.grafana.com"
+	apisDataSourcesGroup = "datasources.grafana.com"
+
+	// apisSearchPageSize is the page size of the dashboard searches listing the dashboards and folders
+	apisSearchPageSize = 1000
+)
+
+type apisDashboardSpec struct {
+	Title     string           `json:"title"`
+	FolderUID string           `json:"folderUID,omitempty"`
+	Dashboard *simplejson.Json `json:"dashboard"`
+}
+
+type apisFolderSpec struct {
+	Title string `json:"title"`
+}
+
+// apisDataSourceSpec is the data source without its secure fields
+type apisDataSourceSpec struct {
+	Name            string               `json:"name"`
+	Type            string               `json:"type"`
+	Access          datasources.DsAccess `json:"access"`
+	URL             string               `json:"url"`
+	Database        string               `json:"database,omitempty"`
+	User            string               `json:"user,omitempty"`
+	BasicAuth       bool                 `json:"basicAuth"`
+	BasicAuthUser   string               `json:"basicAuthUser,omitempty"`
+	WithCredentials bool                 `json:"withCredentials"`
+	IsDefault       bool                 `json:"isDefault"`
+	JSONData        *simplejson.Json     `json:"jsonData,omitempty"`
+	ReadOnly        bool                 `json:"readOnly"`
+}
+
+// apisResources returns the resources served by the /apis endpoints.
+func (hs *HTTPServer) apisResources() []*apisResource {
+	dashboardsRes := &apisResource{
+		group:    apisDashboardsGroup,
+		resource: "dashboards",
+		singular: "dashboard",
+		kind:     "Dashboard",
+		fields:   []string{"spec.title", "spec.folderUID"},
+		spec: apisObjectSchema(map[string]interface{}{
+			"title":     map[string]interface{}{"type": "string"},
+			"folderUID": map[string]interface{}{"type": "string"},
+			"dashboard": map[string]interface{}{"type": "object", "description": "The dashboard JSON model"},
+		}),
+	}
+	dashboardsRes.list = func(c *contextmodel.ReqContext, orgID int64, names []string) ([]*apisObject, error) {
+		dashs, folderUIDs, err := hs.apisListDashboards(c, orgID, names, model.DashHitDB)
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]*apisObject, 0, len(dashs))
+		for _, d := range dashs {
+			spec := apisDashboardSpec{Title: d.Title, FolderUID: folderUIDs[d.UID], Dashboard: d.Data}
+			set := fields.Set{"spec.title": spec.Title, "spec.folderUID": spec.FolderUID}
+			objs = append(objs, newAPIsObject(dashboardsRes, orgID, d.UID, int64(d.Version), d.Created, d.Updated, spec, set))
+		}
+		return objs, nil
+	}
+
+	foldersRes := &apisResource{
+		group:    apisFoldersGroup,
+		resource: "folders",
+		singular: "folder",
+		kind:     "Folder",
+		fields:   []string{"spec.title"},
+		spec: apisObjectSchema(map[string]interface{}{
+			"title": map[string]interface{}{"type": "string"},
+		}),
+	}
+	foldersRes.list = func(c *contextmodel.ReqContext, orgID int64, names []string) ([]*apisObject, error) {
+		folders, _, err := hs.apisListDashboards(c, orgID, names, model.DashHitFolder)
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]*apisObject, 0, len(folders))
+		for _, f := range folders {
+			set := fields.Set{"spec.title": f.Title}
+			objs = append(objs, newAPIsObject(foldersRes, orgID, f.UID, int64(f.Version), f.Created, f.Updated, apisFolderSpec{Title: f.Title}, set))
+		}
+		return objs, nil
+	}
+
+	dataSourcesRes := &apisResource{
+		group:    apisDataSourcesGroup,
+		resource: "datasources",
+		singular: "datasource",
+		kind:     "DataSource",
+		fields:   []string{"spec.name", "spec.type"},
+		spec: apisObjectSchema(map[string]interface{}{
+			"name":            map[string]interface{}{"type": "string"},
+			"type":            map[string]interface{}{"type": "string"},
+			"access":          map[string]interface{}{"type": "string", "enum": []string{"proxy", "direct"}},
+			"url":             map[string]interface{}{"type": "string"},
+			"database":        map[string]interface{}{"type": "string"},
+			"user":            map[string]interface{}{"type": "string"},
+			"basicAuth":       map[string]interface{}{"type": "boolean"},
+			"basicAuthUser":   map[string]interface{}{"type": "string"},
+			"withCredentials": map[string]interface{}{"type": "boolean"},
+			"isDefault":       map[string]interface{}{"type": "boolean"},
+			"jsonData":        map[string]interface{}{"type": "object"},
+			"readOnly":        map[string]interface{}{"type": "boolean"},
+		}),
+	}
+	dataSourcesRes.list = func(c *contextmodel.ReqContext, orgID int64, names []string) ([]*apisObject, error) {
+		dss, err := hs.apisListDataSources(c, orgID, names)
+		if err != nil {
+			return nil, err
+		}
+		objs := make([]*apisObject, 0, len(dss))
+		for _, ds := range dss {
+			spec := apisDataSourceSpec{
+				Name:            ds.Name,
+				Type:            ds.Type,
+				Access:          ds.Access,
+				URL:             ds.URL,
+				Database:        ds.Database,
+				User:            ds.User,
+				BasicAuth:       ds.BasicAuth,
+				BasicAuthUser:   ds.BasicAuthUser,
+				WithCredentials: ds.WithCredentials,
+				IsDefault:       ds.IsDefault,
+				JSONData:        ds.JsonData,
+				ReadOnly:        ds.ReadOnly,
+			}
+			set := fields.Set{"spec.name": ds.Name, "spec.type": ds.Type}
+			objs = append(objs, newAPIsObject(dataSourcesRes, orgID, ds.UID, int64(ds.Version), ds.Created, ds.Updated, spec, set))
+		}
+		return objs, nil
+	}
+
+	return []*apisResource{dashboardsRes, foldersRes, dataSourcesRes}
+}
+
+// apisListDashboards returns the dashboards or folders the signed in user can view, only the named ones if any,
+// and the UIDs of their folders.
+func (hs *HTTPServer) apisListDashboards(c *contextmodel.ReqContext, orgID int64, names []string, hitType model.HitType) ([]*dashboards.Dashboard, map[string]string, error) {
+	folderUIDs := map[string]string{}
+	var uids []string
+	for page := int64(1); ; page++ {
+		hits, err := hs.DashboardService.FindDashboards(c.Req.Context(), &dashboards.FindPersistedDashboardsQuery{
+			OrgId:         orgID,
+			SignedInUser:  c.SignedInUser,
+			DashboardUIDs: names,
+			Type:          string(hitType),
+			Limit:         apisSearchPageSize,
+			Page:          page,
+			Permission:    dashboards.PERMISSION_VIEW,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, hit := range hits {
+			uids = append(uids, hit.UID)
+			folderUIDs[hit.UID] = hit.FolderUID
+		}
+		if len(hits) < apisSearchPageSize {
+			break
+		}
+	}
+
+	if len(uids) == 0 {
+		return []*dashboards.Dashboard{}, folderUIDs, nil
+	}
+	dashs, err := hs.DashboardService.GetDashboards(c.Req.Context(), &dashboards.GetDashboardsQuery{OrgID: orgID, DashboardUIDs: uids})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dashs, folderUIDs, nil
+}
+
+// apisListDataSources returns the data sources the signed in user can read, only the named ones if any.
+func (hs *HTTPServer) apisListDataSources(c *contextmodel.ReqContext, orgID int64, names []string) ([]*datasources.DataSource, error) {
+	dss, err := hs.DataSourcesService.GetDataSources(c.Req.Context(), &datasources.GetDataSourcesQuery{
+		OrgID:           orgID,
+		DataSourceLimit: hs.Cfg.DataSourceLimit,
+		User:            c.SignedInUser,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filter := make(map[string]bool, len(names))
+	for _, name := range names {
+		filter[name] = true
+	}
+	result := make([]*datasources.DataSource, 0, len(dss))
+	for _, ds := range dss {
+		if len(filter) > 0 && !filter[ds.UID] {
+			continue
+		}
+		ok, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser,
+			accesscontrol.EvalPermission(datasources.ActionRead, datasources.ScopeProvider.GetResourceScopeUID(ds.UID)))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, ds)
+		}
+	}
+	return result, nil
+}
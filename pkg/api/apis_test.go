@@ -0,0 +1,235 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+var apisTestTime = time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func TestAPIsDiscovery(t *testing.T) {
+	server := SetupAPITestServer(t)
+
+	t.Run("should list the API groups", func(t *testing.T) {
+		var groups metav1.APIGroupList
+		sendAPIsRequest(t, server, "/apis/", userWithPermissions(1, nil), http.StatusOK, &groups)
+		require.Len(t, groups.Groups, 3)
+		assert.Equal(t, "dashboards.grafana.com", groups.Groups[0].Name)
+		assert.Equal(t, "dashboards.grafana.com/v1alpha1", groups.Groups[0].PreferredVersion.GroupVersion)
+	})
+
+	t.Run("should list the resources of a version", func(t *testing.T) {
+		var resources metav1.APIResourceList
+		sendAPIsRequest(t, server, "/apis/datasources.grafana.com/v1alpha1", userWithPermissions(1, nil), http.StatusOK, &resources)
+		require.Len(t, resources.APIResources, 1)
+		assert.Equal(t, "datasources", resources.APIResources[0].Name)
+		assert.Equal(t, "DataSource", resources.APIResources[0].Kind)
+		assert.True(t, resources.APIResources[0].Namespaced)
+	})
+
+	t.Run("should return 404 for unknown versions", func(t *testing.T) {
+		sendAPIsRequest(t, server, "/apis/datasources.grafana.com/v1", userWithPermissions(1, nil), http.StatusNotFound, nil)
+	})
+
+	t.Run("should serve the OpenAPI document", func(t *testing.T) {
+		var doc map[string]interface{}
+		sendAPIsRequest(t, server, "/openapi/v3/apis/dashboards.grafana.com/v1alpha1", userWithPermissions(1, nil), http.StatusOK, &doc)
+		paths := doc["paths"].(map[string]interface{})
+		assert.Contains(t, paths, "/apis/dashboards.grafana.com/v1alpha1/namespaces/{namespace}/dashboards")
+		assert.Contains(t, paths, "/apis/dashboards.grafana.com/v1alpha1/namespaces/{namespace}/dashboards/{name}")
+	})
+}
+
+func TestAPIsDashboards(t *testing.T) {
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("FindDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+		return q.Type == "dash-db" && q.OrgId == 2 && q.Permission == dashboards.PERMISSION_VIEW
+	})).Return([]dashboards.DashboardSearchProjection{
+		{UID: "b", FolderUID: "folder"},
+		{UID: "a"},
+	}, nil)
+	dashboardService.On("GetDashboards", mock.Anything, &dashboards.GetDashboardsQuery{OrgID: 2, DashboardUIDs: []string{"b", "a"}}).Return([]*dashboards.Dashboard{
+		{UID: "b", Title: "B", Version: 3, Created: apisTestTime, Updated: apisTestTime.Add(2 * time.Second), Data: simplejson.NewFromAny(map[string]interface{}{"title": "B"})},
+		{UID: "a", Title: "A", Version: 1, Created: apisTestTime, Updated: apisTestTime.Add(time.Second), Data: simplejson.New()},
+	}, nil)
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.DashboardService = dashboardService
+	})
+	usr := userWithPermissions(2, nil)
+
+	t.Run("should list the dashboards sorted by name", func(t *testing.T) {
+		var list apisObjectList
+		sendAPIsRequest(t, server, "/apis/dashboards.grafana.com/v1alpha1/namespaces/org-2/dashboards", usr, http.StatusOK, &list)
+		assert.Equal(t, "DashboardList", list.Kind)
+		assert.Equal(t, "dashboards.grafana.com/v1alpha1", list.APIVersion)
+		assert.Equal(t, "1677628802000", list.ResourceVersion)
+		require.Len(t, list.Items, 2)
+		assert.Equal(t, "a", list.Items[0].Name)
+		assert.Equal(t, "org-2", list.Items[0].Namespace)
+		assert.Equal(t, "1677628801000", list.Items[0].ResourceVersion)
+		assert.Equal(t, "b", list.Items[1].Name)
+		assert.Equal(t, int64(3), list.Items[1].Generation)
+		assert.Equal(t, map[string]interface{}{"title": "B", "folderUID": "folder", "dashboard": map[string]interface{}{"title": "B"}}, list.Items[1].Spec)
+	})
+
+	t.Run("should filter the dashboards with field selectors", func(t *testing.T) {
+		var list apisObjectList
+		sendAPIsRequest(t, server, "/apis/dashboards.grafana.com/v1alpha1/namespaces/org-2/dashboards?fieldSelector=spec.folderUID%3Dfolder", usr, http.StatusOK, &list)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "b", list.Items[0].Name)
+	})
+
+	t.Run("should reject unsupported field selectors", func(t *testing.T) {
+		var status metav1.Status
+		sendAPIsRequest(t, server, "/apis/dashboards.grafana.com/v1alpha1/namespaces/org-2/dashboards?fieldSelector=spec.tags%3Dprod", usr, http.StatusBadRequest, &status)
+		assert.Equal(t, metav1.StatusReasonBadRequest, status.Reason)
+	})
+
+	t.Run("should paginate the dashboards", func(t *testing.T) {
+		var first, second apisObjectList
+		sendAPIsRequest(t, server, "/apis/dashboards.grafana.com/v1alpha1/namespaces/org-2/dashboards?limit=1", usr, http.StatusOK, &first)
+		require.Len(t, first.Items, 1)
+		assert.Equal(t, "a", first.Items[0].Name)
+		require.NotEmpty(t, first.Continue)
+
+		sendAPIsRequest(t, server, "/apis/dashboards.grafana.com/v1alpha1/namespaces/org-2/dashboards?limit=1&continue="+first.Continue, usr, http.StatusOK, &second)
+		require.Len(t, second.Items, 1)
+		assert.Equal(t, "b", second.Items[0].Name)
+		assert.Empty(t, second.Continue)
+	})
+
+	t.Run("should forbid the namespace of another organization", func(t *testing.T) {
+		var status metav1.Status
+		sendAPIsRequest(t, server, "/apis/dashboards.grafana.com/v1alpha1/namespaces/default/dashboards", usr, http.StatusForbidden, &status)
+		assert.Equal(t, metav1.StatusReasonForbidden, status.Reason)
+	})
+}
+
+func TestAPIsDataSources(t *testing.T) {
+	dss := &apisDataSourcesFake{snapshots: [][]*datasources.DataSource{
+		{
+			{UID: "prom", Name: "Prometheus", Type: "prometheus", Created: apisTestTime, Updated: apisTestTime, SecureJsonData: map[string][]byte{"password": []byte("secret")}},
+			{UID: "loki", Name: "Loki", Type: "loki", Created: apisTestTime, Updated: apisTestTime},
+			{UID: "hidden", Name: "Hidden", Type: "loki", Created: apisTestTime, Updated: apisTestTime},
+		},
+	}}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.DataSourcesService = dss
+	})
+	usr := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: datasources.ActionRead, Scope: "datasources:uid:prom"},
+		{Action: datasources.ActionRead, Scope: "datasources:uid:loki"},
+	})
+
+	t.Run("should list the data sources the user can read", func(t *testing.T) {
+		var list apisObjectList
+		sendAPIsRequest(t, server, "/apis/datasources.grafana.com/v1alpha1/namespaces/default/datasources", usr, http.StatusOK, &list)
+		require.Len(t, list.Items, 2)
+		assert.Equal(t, "loki", list.Items[0].Name)
+		assert.Equal(t, "prom", list.Items[1].Name)
+		assert.NotContains(t, list.Items[1].Spec, "secureJsonData")
+	})
+
+	t.Run("should get a data source by name", func(t *testing.T) {
+		var obj apisObject
+		sendAPIsRequest(t, server, "/apis/datasources.grafana.com/v1alpha1/namespaces/default/datasources/prom", usr, http.StatusOK, &obj)
+		assert.Equal(t, "DataSource", obj.Kind)
+		assert.Equal(t, "Prometheus", obj.Spec.(map[string]interface{})["name"])
+	})
+
+	t.Run("should not get a data source the user can't read", func(t *testing.T) {
+		sendAPIsRequest(t, server, "/apis/datasources.grafana.com/v1alpha1/namespaces/default/datasources/hidden", usr, http.StatusNotFound, nil)
+	})
+}
+
+func TestAPIsWatch(t *testing.T) {
+	interval := apisWatchPollInterval
+	apisWatchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { apisWatchPollInterval = interval })
+
+	updated := apisTestTime.Add(time.Minute)
+	dss := &apisDataSourcesFake{snapshots: [][]*datasources.DataSource{
+		{
+			{UID: "prom", Name: "Prometheus", Type: "prometheus", Created: apisTestTime, Updated: apisTestTime},
+			{UID: "loki", Name: "Loki", Type: "loki", Created: apisTestTime, Updated: apisTestTime},
+		},
+		{
+			{UID: "prom", Name: "Prometheus", Type: "prometheus", Created: apisTestTime, Updated: updated, Version: 2},
+			{UID: "tempo", Name: "Tempo", Type: "tempo", Created: updated, Updated: updated},
+		},
+	}}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.DataSourcesService = dss
+	})
+	usr := userWithPermissions(1, []accesscontrol.Permission{{Action: datasources.ActionRead, Scope: datasources.ScopeAll}})
+
+	req := server.NewGetRequest("/apis/datasources.grafana.com/v1alpha1/namespaces/default/datasources?watch=true&timeoutSeconds=1")
+	res, err := server.Send(webtest.RequestWithSignedInUser(req, usr))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var events []string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() && len(events) < 5 {
+		var event struct {
+			Type   string     `json:"type"`
+			Object apisObject `json:"object"`
+		}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event.Type+" "+event.Object.Name)
+	}
+	assert.Equal(t, []string{"ADDED loki", "ADDED prom", "MODIFIED prom", "ADDED tempo", "DELETED loki"}, events)
+}
+
+func sendAPIsRequest(t *testing.T, server *webtest.Server, url string, usr *user.SignedInUser, expectedCode int, result interface{}) {
+	t.Helper()
+	res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest(url), usr))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, res.Body.Close()) }()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, res.StatusCode, string(body))
+	if result != nil {
+		require.NoError(t, json.Unmarshal(body, result))
+	}
+}
+
+// apisDataSourcesFake returns the snapshots in order on each list, the last one once they are exhausted
+type apisDataSourcesFake struct {
+	datasources.DataSourceService
+
+	mu        sync.Mutex
+	snapshots [][]*datasources.DataSource
+	calls     int
+}
+
+func (f *apisDataSourcesFake) GetDataSources(_ context.Context, _ *datasources.GetDataSourcesQuery) ([]*datasources.DataSource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.calls
+	if i >= len(f.snapshots) {
+		i = len(f.snapshots) - 1
+	}
+	f.calls++
+	return f.snapshots[i], nil
+}
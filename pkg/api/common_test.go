@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/infra/fs"
@@ -40,8 +41,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/login/loginservice"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
+	"github.com/grafana/grafana/pkg/services/oauthserver/oauthservertest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/search"
@@ -102,6 +105,8 @@ func anonymousUserScenario(t *testing.T, desc string, method string, url string,
 		switch method {
 		case "GET":
 			sc.m.Get(routePattern, sc.defaultHandler)
+		case "POST":
+			sc.m.Post(routePattern, sc.defaultHandler)
 		case "DELETE":
 			sc.m.Delete(routePattern, sc.defaultHandler)
 		}
@@ -209,7 +214,8 @@ func getContextHandler(t *testing.T, cfg *setting.Cfg) *contexthandler.ContextHa
 	ctxHdlr := contexthandler.ProvideService(cfg, userAuthTokenSvc, authJWTSvc,
 		remoteCacheSvc, renderSvc, sqlStore, tracer, authProxy, loginService, nil,
 		authenticator, usertest.NewUserServiceFake(), orgtest.NewOrgServiceFake(),
-		nil, featuremgmt.WithFeatures(), &authntest.FakeService{}, &anontest.FakeAnonymousSessionService{})
+		nil, featuremgmt.WithFeatures(), &authntest.FakeService{}, &anontest.FakeAnonymousSessionService{},
+		orgsettingstest.NewFakeService(cfg), oauthservertest.NewFakeService())
 
 	return ctxHdlr
 }
@@ -288,6 +294,7 @@ func SetupAPITestServer(t *testing.T, opts ...APITestServerOption) *webtest.Serv
 		Features:           featuremgmt.WithFeatures(),
 		QuotaService:       quotatest.New(false, nil),
 		searchUsersService: &searchusers.OSSService{},
+		bus:                bus.ProvideBus(tracing.InitializeTracerForTest()),
 	}
 
 	for _, opt := range opts {
@@ -318,9 +325,13 @@ type setUpConf struct {
 	aclMockResp []*dashboards.DashboardACLInfoDTO
 }
 
-type mockSearchService struct{ ExpectedResult model.HitList }
+type mockSearchService struct {
+	ExpectedResult model.HitList
+	LastQuery      *search.Query
+}
 
 func (mss *mockSearchService) SearchHandler(_ context.Context, q *search.Query) error {
+	mss.LastQuery = q
 	q.Result = mss.ExpectedResult
 	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	publicdashboardModels "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/star"
@@ -107,7 +108,7 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 
 	// If public dashboards is enabled and we have a public dashboard, update meta
 	// values
-	if hs.Features.IsEnabled(featuremgmt.FlagPublicDashboards) {
+	if hs.Features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagPublicDashboards) {
 		publicDashboard, err := hs.PublicDashboardsApi.PublicDashboardService.FindByDashboardUid(c.Req.Context(), c.OrgID, dash.UID)
 		if err != nil && !errors.Is(err, publicdashboardModels.ErrPublicDashboardNotFound) {
 			return response.Error(500, "Error while retrieving public dashboards", err)
@@ -238,6 +239,10 @@ func (hs *HTTPServer) GetDashboard(c *contextmodel.ReqContext) response.Response
 		Meta:      meta,
 	}
 
+	if hs.usageInsightsService != nil {
+		hs.usageInsightsService.RecordDashboardView(c.Req.Context(), c.OrgID, dash.UID)
+	}
+
 	c.TimeRequest(metrics.MApiDashboardGet)
 	return response.JSON(http.StatusOK, dto)
 }
@@ -377,7 +382,7 @@ func (hs *HTTPServer) PostDashboard(c *contextmodel.ReqContext) response.Respons
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
-	if hs.Features.IsEnabled(featuremgmt.FlagValidateDashboardsOnSave) {
+	if hs.Features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagValidateDashboardsOnSave) {
 		kind := hs.Kinds.Dashboard()
 
 		dashbytes, err := cmd.Dashboard.Bytes()
@@ -548,7 +553,8 @@ func (hs *HTTPServer) GetHomeDashboard(c *contextmodel.ReqContext) response.Resp
 		hs.log.Warn("Failed to get slug from database", "err", err)
 	}
 
-	filePath := hs.Cfg.DefaultHomeDashboardPath
+	customPath := hs.orgSettingsService.GetString(c.Req.Context(), c.OrgID, orgsettings.HomeDashboardPath)
+	filePath := customPath
 	if filePath == "" {
 		filePath = filepath.Join(hs.Cfg.StaticRootPath, "dashboards/home.json")
 	}
@@ -576,17 +582,18 @@ func (hs *HTTPServer) GetHomeDashboard(c *contextmodel.ReqContext) response.Resp
 		return response.Error(500, "Failed to load home dashboard", err)
 	}
 
-	hs.addGettingStartedPanelToHomeDashboard(c, dash.Dashboard)
+	// the getting started panel is not added if a custom default home dashboard has been configured
+	if customPath == "" {
+		hs.addGettingStartedPanelToHomeDashboard(c, dash.Dashboard)
+	}
 
 	return response.JSON(http.StatusOK, &dash)
 }
 
 func (hs *HTTPServer) addGettingStartedPanelToHomeDashboard(c *contextmodel.ReqContext, dash *simplejson.Json) {
-	// We only add this getting started panel for Admins who have not dismissed it,
-	// and if a custom default home dashboard hasn't been configured
+	// We only add this getting started panel for Admins who have not dismissed it
 	if !c.HasUserRole(org.RoleAdmin) ||
-		c.HasHelpFlag(user.HelpFlagGettingStartedPanelDismissed) ||
-		hs.Cfg.DefaultHomeDashboardPath != "" {
+		c.HasHelpFlag(user.HelpFlagGettingStartedPanelDismissed) {
 		return
 	}
 
@@ -1015,6 +1022,97 @@ func (hs *HTTPServer) RestoreDashboardVersion(c *contextmodel.ReqContext) respon
 	return hs.postDashboard(c, saveCmd)
 }
 
+// swagger:route PATCH /dashboards/uid/{uid}/panels/{panelId} dashboards patchDashboardPanel
+//
+// Patch a panel of a dashboard.
+//
+// Applies a JSON patch (RFC 6902) to the panel with the given id, the paths of the patch are relative to the panel.
+// Panels of collapsed rows can be patched as well.
+//
+// Responses:
+// 200: postDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) PatchDashboardPanel(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.PatchDashboardPanelCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	panelID, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "panelId is invalid", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	if cmd.Version != 0 && cmd.Version != int64(dash.Version) {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, dashboards.ErrDashboardVersionMismatch)
+	}
+
+	panels, index := findPanel(dash.Data.Get("panels").MustArray(), panelID)
+	if panels == nil {
+		return response.Error(http.StatusNotFound, "Panel not found", nil)
+	}
+
+	patched, err := cmd.Patch.Apply(panels[index])
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Failed to apply the patch to the panel", err)
+	}
+	if _, ok := patched.(map[string]interface{}); !ok {
+		return response.Error(http.StatusBadRequest, "The patched panel must be an object", nil)
+	}
+	if simplejson.NewFromAny(patched).Get("id").MustInt64() != panelID {
+		return response.Error(http.StatusBadRequest, "The patch must not change the id of the panel", nil)
+	}
+	panels[index] = patched
+
+	saveCmd := dashboards.SaveDashboardCommand{}
+	saveCmd.OrgID = c.OrgID
+	saveCmd.UserID = c.UserID
+	saveCmd.Dashboard = dash.Data
+	saveCmd.Dashboard.Set("version", dash.Version)
+	saveCmd.Dashboard.Set("uid", dash.UID)
+	saveCmd.Message = cmd.Message
+	if saveCmd.Message == "" {
+		saveCmd.Message = fmt.Sprintf("Patched panel %d", panelID)
+	}
+	saveCmd.FolderID = dash.FolderID
+
+	return hs.postDashboard(c, saveCmd)
+}
+
+// findPanel returns the list of panels containing the panel with the given id and the index of the panel in the list.
+// The panels of collapsed rows are nested in the row.
+func findPanel(panels []interface{}, id int64) ([]interface{}, int) {
+	for i, p := range panels {
+		panel := simplejson.NewFromAny(p)
+		if panel.Get("id").MustInt64() == id {
+			return panels, i
+		}
+		if nested, index := findPanel(panel.Get("panels").MustArray(), id); nested != nil {
+			return nested, index
+		}
+	}
+	return nil, 0
+}
+
 // swagger:route GET /dashboards/tags dashboards getDashboardTags
 //
 // Get all dashboards tags of an organisation.
@@ -1093,6 +1191,19 @@ type RestoreDashboardVersionByUIDParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters patchDashboardPanel
+type PatchDashboardPanelParams struct {
+	// in:body
+	// required:true
+	Body dtos.PatchDashboardPanelCommand
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:path
+	// required:true
+	PanelID int64 `json:"panelId"`
+}
+
 // swagger:parameters getDashboardVersionByID
 type GetDashboardVersionByIDParams struct {
 	// in:path
@@ -49,7 +49,7 @@ type CreateExternalSnapshotResponse struct {
 	DeleteUrl string `json:"deleteUrl"`
 }
 
-func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnapshotCommand, externalSnapshotUrl string) (*CreateExternalSnapshotResponse, error) {
+func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnapshotCommand, externalSnapshotUrl, token string) (*CreateExternalSnapshotResponse, error) {
 	var createSnapshotResponse CreateExternalSnapshotResponse
 	message := map[string]interface{}{
 		"name":      cmd.Name,
@@ -64,7 +64,16 @@ func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnaps
 		return nil, err
 	}
 
-	resp, err := client.Post(externalSnapshotUrl+"/api/snapshots", "application/json", bytes.NewBuffer(messageBytes))
+	req, err := http.NewRequest(http.MethodPost, externalSnapshotUrl+"/api/snapshots", bytes.NewBuffer(messageBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(dashboardsnapshots.ServerTokenHeader, token)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +107,7 @@ func createOriginalDashboardURL(cmd *dashboardsnapshots.CreateDashboardSnapshotC
 //
 // When creating a snapshot using the API, you have to provide the full dashboard payload including the snapshot data. This endpoint is designed for the Grafana UI.
 //
-// Snapshot public mode should be enabled or authentication is required.
+// Snapshot public mode should be enabled, a snapshot server token provided or authentication is required.
 //
 // Responses:
 // 200: createDashboardSnapshotResponse
@@ -123,6 +132,18 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) respon
 	cmd.ExternalURL = ""
 	cmd.OrgID = c.OrgID
 	cmd.UserID = c.UserID
+
+	// requests of other instances using this instance as their external snapshot server
+	if orgID, ok := dashboardsnapshots.OrgIDForServerToken(hs.Cfg, c.Req.Header.Get(dashboardsnapshots.ServerTokenHeader)); ok {
+		if cmd.External {
+			return response.Error(http.StatusBadRequest, "External snapshots cannot be created on a snapshot server", nil)
+		}
+		cmd.OrgID = orgID
+		cmd.UserID = 0
+		if resp := hs.applySnapshotServerLimits(c, &cmd); resp != nil {
+			return resp
+		}
+	}
 	originalDashboardURL, err := createOriginalDashboardURL(&cmd)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Invalid app URL", err)
@@ -134,7 +155,7 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) respon
 			return nil
 		}
 
-		resp, err := createExternalDashboardSnapshot(cmd, hs.Cfg.ExternalSnapshotUrl)
+		resp, err := createExternalDashboardSnapshot(cmd, hs.Cfg.ExternalSnapshotUrl, hs.Cfg.ExternalSnapshotToken)
 		if err != nil {
 			c.JsonApiErr(http.StatusInternalServerError, "Failed to create external snapshot", err)
 			return nil
@@ -190,6 +211,25 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) respon
 	return nil
 }
 
+// applySnapshotServerLimits caps the time to live of the snapshots created by other instances and enforces the
+// quota of their organization.
+func (hs *HTTPServer) applySnapshotServerLimits(c *contextmodel.ReqContext, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) response.Response {
+	if maxTTL := int64(hs.Cfg.SnapshotServerMaxTTL.Seconds()); maxTTL > 0 && (cmd.Expires <= 0 || cmd.Expires > maxTTL) {
+		cmd.Expires = maxTTL
+	}
+
+	if hs.Cfg.SnapshotServerOrgQuota > 0 {
+		count, err := hs.dashboardsnapshotsService.CountDashboardSnapshots(c.Req.Context(), &dashboardsnapshots.CountDashboardSnapshotsQuery{OrgID: cmd.OrgID})
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to count snapshots", err)
+		}
+		if count >= hs.Cfg.SnapshotServerOrgQuota {
+			return response.Error(http.StatusForbidden, "Snapshot quota reached", nil)
+		}
+	}
+	return nil
+}
+
 // GET /api/snapshots/:key
 // swagger:route GET /snapshots/{key} snapshots getDashboardSnapshot
 //
@@ -240,8 +280,16 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *contextmodel.ReqContext) response.
 	return response.JSON(http.StatusOK, dto).SetHeader("Cache-Control", "public, max-age=3600")
 }
 
-func deleteExternalDashboardSnapshot(externalUrl string) error {
-	resp, err := client.Get(externalUrl)
+func deleteExternalDashboardSnapshot(externalUrl, token string) error {
+	req, err := http.NewRequest(http.MethodGet, externalUrl, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set(dashboardsnapshots.ServerTokenHeader, token)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -276,7 +324,7 @@ func deleteExternalDashboardSnapshot(externalUrl string) error {
 //
 // Delete Snapshot by deleteKey.
 //
-// Snapshot public mode should be enabled or authentication is required.
+// Snapshot public mode should be enabled, a snapshot server token provided or authentication is required.
 //
 // Responses:
 // 200: okResponse
@@ -302,7 +350,7 @@ func (hs *HTTPServer) DeleteDashboardSnapshotByDeleteKey(c *contextmodel.ReqCont
 	}
 
 	if queryResult.External {
-		err := deleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL)
+		err := deleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL, hs.Cfg.ExternalSnapshotToken)
 		if err != nil {
 			return response.Error(500, "Failed to delete external dashboard", err)
 		}
@@ -351,7 +399,7 @@ func (hs *HTTPServer) DeleteDashboardSnapshot(c *contextmodel.ReqContext) respon
 	}
 
 	if queryResult.External {
-		err := deleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL)
+		err := deleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL, hs.Cfg.ExternalSnapshotToken)
 		if err != nil {
 			return response.Error(http.StatusInternalServerError, "Failed to delete external dashboard", err)
 		}
@@ -3,6 +3,7 @@ package api
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -442,3 +443,69 @@ func buildHttpServer(d dashboardsnapshots.Service, snapshotEnabled bool) *HTTPSe
 	}
 	return hs
 }
+
+func TestDashboardSnapshotServerMode(t *testing.T) {
+	const body = `{"dashboard": {"uid": "abcdef", "title": "Remote"}, "name": "remote", "key": "remote-key", "deleteKey": "remote-delete-key", "expires": 0}`
+
+	setUpServer := func(t *testing.T, count int64) (*HTTPServer, *dashboardsnapshots.CreateDashboardSnapshotCommand) {
+		t.Helper()
+		created := &dashboardsnapshots.CreateDashboardSnapshotCommand{}
+		dashSnapSvc := dashboardsnapshots.NewMockService(t)
+		dashSnapSvc.On("CountDashboardSnapshots", mock.Anything, &dashboardsnapshots.CountDashboardSnapshotsQuery{OrgID: 2}).Return(count, nil)
+		dashSnapSvc.On("CreateDashboardSnapshot", mock.Anything, mock.AnythingOfType("*dashboardsnapshots.CreateDashboardSnapshotCommand")).Run(func(args mock.Arguments) {
+			*created = *args.Get(1).(*dashboardsnapshots.CreateDashboardSnapshotCommand)
+		}).Return(&dashboardsnapshots.DashboardSnapshot{ID: 7}, nil).Maybe()
+
+		hs := buildHttpServer(dashSnapSvc, true)
+		hs.Cfg.SnapshotServerEnabled = true
+		hs.Cfg.SnapshotServerTokens = map[string]int64{"instance-token": 2}
+		hs.Cfg.SnapshotServerMaxTTL = time.Hour
+		hs.Cfg.SnapshotServerOrgQuota = 5
+		return hs, created
+	}
+
+	post := func(sc *scenarioContext, token string) {
+		sc.fakeReqWithParams("POST", sc.url, map[string]string{})
+		sc.req.Body = io.NopCloser(strings.NewReader(body))
+		sc.req.Header.Set("Content-Type", "application/json")
+		sc.req.Header.Set(dashboardsnapshots.ServerTokenHeader, token)
+		sc.exec()
+	}
+
+	anonymousUserScenario(t, "Should store the snapshots of other instances in the organization of their token when calling POST on",
+		"POST", "/api/snapshots", "/api/snapshots", func(sc *scenarioContext) {
+			hs, created := setUpServer(t, 4)
+			sc.handlerFunc = hs.CreateDashboardSnapshot
+			post(sc, "instance-token")
+
+			require.Equal(t, http.StatusOK, sc.resp.Code)
+			assert.Equal(t, int64(2), created.OrgID)
+			assert.Equal(t, int64(0), created.UserID)
+			assert.Equal(t, "remote-key", created.Key)
+			assert.Equal(t, int64(3600), created.Expires)
+		})
+
+	anonymousUserScenario(t, "Should enforce the quota of the organization when calling POST on",
+		"POST", "/api/snapshots", "/api/snapshots", func(sc *scenarioContext) {
+			hs, _ := setUpServer(t, 5)
+			sc.handlerFunc = hs.CreateDashboardSnapshot
+			post(sc, "instance-token")
+
+			require.Equal(t, http.StatusForbidden, sc.resp.Code)
+		})
+
+	t.Run("Should send the token to the external snapshot server", func(t *testing.T) {
+		var token string
+		ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			token = r.Header.Get(dashboardsnapshots.ServerTokenHeader)
+			_, _ = rw.Write([]byte(`{"key": "k", "deleteKey": "d", "url": "u", "deleteUrl": "du"}`))
+		}))
+		t.Cleanup(ts.Close)
+
+		cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{Dashboard: simplejson.New()}
+		resp, err := createExternalDashboardSnapshot(cmd, ts.URL, "instance-token")
+		require.NoError(t, err)
+		assert.Equal(t, "k", resp.Key)
+		assert.Equal(t, "instance-token", token)
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/jsonpatch"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
@@ -46,6 +47,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
 	"github.com/grafana/grafana/pkg/services/provisioning"
@@ -74,6 +76,7 @@ func TestGetHomeDashboard(t *testing.T) {
 		preferenceService:       prefService,
 		dashboardVersionService: dashboardVersionService,
 		Kinds:                   corekind.NewBase(nil),
+		orgSettingsService:      orgsettingstest.NewFakeService(cfg),
 	}
 
 	tests := []struct {
@@ -667,6 +670,7 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 				{SaveError: dashboards.ErrDashboardUidTooLong, ExpectedStatusCode: 400},
 				{SaveError: dashboards.ErrDashboardCannotSaveProvisionedDashboard, ExpectedStatusCode: 400},
 				{SaveError: dashboards.UpdatePluginDashboardError{PluginId: "plug"}, ExpectedStatusCode: 412},
+				{SaveError: dashboards.PendingApprovalError{ChangeID: 1, DiffURL: "/api/dashboards/pending-changes/1/diff"}, ExpectedStatusCode: 202},
 			}
 
 			cmd := dashboards.SaveDashboardCommand{
@@ -1013,7 +1017,7 @@ func (hs *HTTPServer) callGetDashboardVersions(sc *scenarioContext) {
 func (hs *HTTPServer) callDeleteDashboardByUID(t *testing.T,
 	sc *scenarioContext, mockDashboard *dashboards.FakeDashboardService, mockPubdashService *publicdashboards.FakePublicDashboardService) {
 	hs.DashboardService = mockDashboard
-	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures())
+	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures(), quotatest.New(false, nil), setting.NewCfg())
 	hs.PublicDashboardsApi = pubdashApi
 	sc.handlerFunc = hs.DeleteDashboardByUID
 	sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
@@ -1185,6 +1189,129 @@ func restoreDashboardVersionScenario(t *testing.T, desc string, url string, rout
 	})
 }
 
+func TestDashboardAPIEndpoint_PatchDashboardPanel(t *testing.T) {
+	newDashboard := func(t *testing.T) *dashboards.Dashboard {
+		data, err := simplejson.NewJson([]byte(`{
+			"title": "Dash",
+			"panels": [
+				{"id": 1, "type": "timeseries", "fieldConfig": {"defaults": {"thresholds": {"steps": [{"value": null}, {"value": 80}]}}}},
+				{"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "type": "stat", "title": "Nested"}]}
+			]
+		}`))
+		require.NoError(t, err)
+		dash := dashboards.NewDashboardFromJson(data)
+		dash.Version = 3
+		return dash
+	}
+
+	testCases := []struct {
+		desc         string
+		url          string
+		cmd          dtos.PatchDashboardPanelCommand
+		expectedCode int
+		verify       func(t *testing.T, saved *dashboards.SaveDashboardDTO)
+	}{
+		{
+			desc: "should patch a panel",
+			url:  "/api/dashboards/uid/dash/panels/1",
+			cmd: dtos.PatchDashboardPanelCommand{Version: 3, Patch: jsonpatch.Patch{
+				{Op: jsonpatch.OpReplace, Path: "/fieldConfig/defaults/thresholds/steps/1/value", Value: 90},
+			}},
+			expectedCode: http.StatusOK,
+			verify: func(t *testing.T, saved *dashboards.SaveDashboardDTO) {
+				panel := saved.Dashboard.Data.Get("panels").GetIndex(0)
+				require.Equal(t, int64(90), panel.GetPath("fieldConfig", "defaults", "thresholds", "steps").GetIndex(1).Get("value").MustInt64())
+				require.Equal(t, 3, saved.Dashboard.Version)
+				require.Equal(t, "Patched panel 1", saved.Message)
+			},
+		},
+		{
+			desc: "should patch a panel of a collapsed row",
+			url:  "/api/dashboards/uid/dash/panels/3",
+			cmd: dtos.PatchDashboardPanelCommand{Message: "Rename", Patch: jsonpatch.Patch{
+				{Op: jsonpatch.OpReplace, Path: "/title", Value: "Renamed"},
+			}},
+			expectedCode: http.StatusOK,
+			verify: func(t *testing.T, saved *dashboards.SaveDashboardDTO) {
+				row := saved.Dashboard.Data.Get("panels").GetIndex(1)
+				require.Equal(t, "Renamed", row.Get("panels").GetIndex(0).Get("title").MustString())
+				require.Equal(t, "Rename", saved.Message)
+			},
+		},
+		{
+			desc:         "should reject outdated patches",
+			url:          "/api/dashboards/uid/dash/panels/1",
+			cmd:          dtos.PatchDashboardPanelCommand{Version: 2, Patch: jsonpatch.Patch{{Op: jsonpatch.OpRemove, Path: "/type"}}},
+			expectedCode: http.StatusPreconditionFailed,
+		},
+		{
+			desc:         "should return not found for unknown panels",
+			url:          "/api/dashboards/uid/dash/panels/4",
+			cmd:          dtos.PatchDashboardPanelCommand{Patch: jsonpatch.Patch{{Op: jsonpatch.OpRemove, Path: "/type"}}},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			desc:         "should reject failing patches",
+			url:          "/api/dashboards/uid/dash/panels/1",
+			cmd:          dtos.PatchDashboardPanelCommand{Patch: jsonpatch.Patch{{Op: jsonpatch.OpTest, Path: "/type", Value: "graph"}}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			desc:         "should not change the id of the panel",
+			url:          "/api/dashboards/uid/dash/panels/1",
+			cmd:          dtos.PatchDashboardPanelCommand{Patch: jsonpatch.Patch{{Op: jsonpatch.OpReplace, Path: "/id", Value: 5}}},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			origNew, origNewByUID, origNewByDashboard := guardian.New, guardian.NewByUID, guardian.NewByDashboard
+			t.Cleanup(func() {
+				guardian.New, guardian.NewByUID, guardian.NewByDashboard = origNew, origNewByUID, origNewByDashboard
+			})
+			guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+			var saved *dashboards.SaveDashboardDTO
+			dashboardService := dashboards.NewFakeDashboardService(t)
+			dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(newDashboard(t), nil)
+			dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).Run(func(args mock.Arguments) {
+				saved = args.Get(1).(*dashboards.SaveDashboardDTO)
+			}).Return(&dashboards.Dashboard{ID: 2, UID: "dash", Title: "Dash", Slug: "dash", Version: 4}, nil).Maybe()
+
+			hs := HTTPServer{
+				Cfg:                   setting.NewCfg(),
+				ProvisioningService:   provisioning.NewProvisioningServiceMock(context.Background()),
+				QuotaService:          quotatest.New(false, nil),
+				LibraryPanelService:   &mockLibraryPanelService{},
+				LibraryElementService: &mockLibraryElementService{},
+				DashboardService:      dashboardService,
+				Features:              featuremgmt.WithFeatures(),
+				Kinds:                 corekind.NewBase(nil),
+				accesscontrolService:  actest.FakeService{},
+			}
+
+			sc := setupScenarioContext(t, tc.url)
+			sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+				c.Req.Body = mockRequestBody(tc.cmd)
+				c.Req.Header.Add("Content-Type", "application/json")
+				sc.context = c
+				sc.context.SignedInUser = &user.SignedInUser{OrgID: testOrgID, UserID: testUserID}
+				sc.context.OrgRole = org.RoleEditor
+				return hs.PatchDashboardPanel(c)
+			})
+			sc.m.Patch("/api/dashboards/uid/:uid/panels/:panelId", sc.defaultHandler)
+
+			sc.fakeReqWithParams("PATCH", sc.url, map[string]string{}).exec()
+			require.Equal(t, tc.expectedCode, sc.resp.Code, sc.resp.Body.String())
+			if tc.verify != nil {
+				require.NotNil(t, saved)
+				tc.verify(t, saved)
+			}
+		})
+	}
+}
+
 func (sc *scenarioContext) ToJSON() *simplejson.Json {
 	result := simplejson.New()
 	err := json.NewDecoder(sc.resp.Body).Decode(result)
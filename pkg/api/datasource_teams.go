@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /datasources/uid/{uid}/teams datasources getDataSourceTeams
+//
+// Get the teams a data source is restricted to.
+//
+// A data source restricted to teams is only visible to the members of the teams and to the users who can edit it.
+//
+// Responses:
+// 200: getDataSourceTeamsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDataSourceTeams(c *contextmodel.ReqContext) response.Response {
+	ds, err := hs.getRawDataSourceByUID(c.Req.Context(), web.Params(c.Req)[":uid"], c.OrgID)
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return response.Error(http.StatusNotFound, "Data source not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to query datasource", err)
+	}
+
+	teamIDs, err := hs.DataSourcesService.GetDataSourceTeams(c.Req.Context(), &datasources.GetDataSourceTeamsQuery{OrgID: c.OrgID, DataSourceID: ds.ID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get data source teams", err)
+	}
+	return response.JSON(http.StatusOK, dtos.DataSourceTeams{TeamIDs: teamIDs})
+}
+
+// swagger:route PUT /datasources/uid/{uid}/teams datasources setDataSourceTeams
+//
+// Restrict a data source to teams.
+//
+// The data source is restricted to the teams of the request, or visible to everyone if there is no team.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SetDataSourceTeams(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.DataSourceTeams{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	ds, err := hs.getRawDataSourceByUID(c.Req.Context(), web.Params(c.Req)[":uid"], c.OrgID)
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return response.Error(http.StatusNotFound, "Data source not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to query datasource", err)
+	}
+
+	err = hs.DataSourcesService.SetDataSourceTeams(c.Req.Context(), &datasources.SetDataSourceTeamsCommand{
+		OrgID:        c.OrgID,
+		DataSourceID: ds.ID,
+		TeamIDs:      cmd.TeamIDs,
+	})
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceTeamNotFound) {
+			return response.Error(http.StatusBadRequest, err.Error(), nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to set data source teams", err)
+	}
+	return response.Success("Data source teams updated")
+}
+
+// swagger:parameters getDataSourceTeams
+type GetDataSourceTeamsParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"uid"`
+}
+
+// swagger:parameters setDataSourceTeams
+type SetDataSourceTeamsParams struct {
+	// in:body
+	// required:true
+	Body dtos.DataSourceTeams
+	// in:path
+	// required:true
+	DatasourceUID string `json:"uid"`
+}
+
+// swagger:response getDataSourceTeamsResponse
+type GetDataSourceTeamsResponse struct {
+	// in: body
+	Body dtos.DataSourceTeams `json:"body"`
+}
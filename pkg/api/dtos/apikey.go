@@ -23,5 +23,6 @@ type ApiKeyDTO struct {
 	Role          org.RoleType           `json:"role"`
 	Expiration    *time.Time             `json:"expiration,omitempty"`
 	LastUsedAt    *time.Time             `json:"lastUsedAt,omitempty"`
+	LastUsedIP    *string                `json:"lastUsedIp,omitempty"`
 	AccessControl accesscontrol.Metadata `json:"accessControl,omitempty"`
 }
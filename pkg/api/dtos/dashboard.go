@@ -3,6 +3,7 @@ package dtos
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/jsonpatch"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -35,6 +36,7 @@ type DashboardMeta struct {
 	PublicDashboardAccessToken string                `json:"publicDashboardAccessToken"`
 	PublicDashboardUID         string                `json:"publicDashboardUid"`
 	PublicDashboardEnabled     bool                  `json:"publicDashboardEnabled"`
+	PublicDashboardQueryUrls   map[int64]string      `json:"publicDashboardQueryUrls,omitempty"`
 }
 type AnnotationPermission struct {
 	Dashboard    AnnotationActions `json:"dashboard"`
@@ -76,3 +78,12 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+// PatchDashboardPanelCommand applies a JSON patch (RFC 6902) to a single panel of a dashboard.
+type PatchDashboardPanelCommand struct {
+	// Version is the version of the dashboard the patch was written for.
+	// The patch is rejected if the dashboard has changed since, unless the version is omitted.
+	Version int64           `json:"version"`
+	Message string          `json:"message"`
+	Patch   jsonpatch.Patch `json:"patch" binding:"Required"`
+}
@@ -61,3 +61,8 @@ func (slice DataSourceList) Less(i, j int) bool {
 func (slice DataSourceList) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
+
+// DataSourceTeams are the teams a data source is restricted to, the data source is visible to everyone if there is no team.
+type DataSourceTeams struct {
+	TeamIDs []int64 `json:"teamIds"`
+}
@@ -227,3 +227,14 @@ type FrontendSettingsDTO struct {
 	Licensing     *FrontendSettingsLicensingDTO     `json:"licensing,omitempty"`
 	Whitelabeling *FrontendSettingsWhitelabelingDTO `json:"whitelabeling,omitempty"`
 }
+
+// FrontendCapabilitiesDTO is the capability manifest of the signed in user, what the frontend and the plugins can
+// rely on to decide which features to show.
+type FrontendCapabilitiesDTO struct {
+	OrgID          int64                          `json:"orgId"`
+	OrgRole        string                         `json:"orgRole"`
+	IsGrafanaAdmin bool                           `json:"isGrafanaAdmin"`
+	FeatureToggles map[string]bool                `json:"featureToggles"`
+	Permissions    map[string]bool                `json:"permissions"`
+	License        FrontendSettingsLicenseInfoDTO `json:"license"`
+}
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -81,6 +83,22 @@ type MetricRequest struct {
 	PublicDashboardAccessToken string `json:"publicDashboardAccessToken"`
 }
 
+// swagger:model
+type FederatedMetricRequest struct {
+	MetricRequest
+	// RefIDs of the queries and expressions to align in the response. All of them are aligned by default.
+	// required: false
+	// example: ["A", "B"]
+	RefIDs []string `json:"refIds"`
+}
+
+type FederatedMetricResponse struct {
+	// Frame holds the fields of all the time series, aligned on a single time field.
+	Frame *data.Frame `json:"frame"`
+	// Errors holds the errors of the queries that failed or did not return time series, by refId.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
 func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
 	dsTypes := make(map[string]bool)
 	for _, query := range mr.Queries {
@@ -64,3 +64,12 @@ func (slice PluginList) Swap(i, j int) {
 type InstallPluginCommand struct {
 	Version string `json:"version"`
 }
+
+type PluginSecret struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type SetPluginSecretCommand struct {
+	Value string `json:"value" binding:"Required"`
+}
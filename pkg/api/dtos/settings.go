@@ -0,0 +1,22 @@
+package dtos
+
+import "github.com/grafana/grafana/pkg/setting"
+
+// ExportedSetting is the effective value of a configuration key along with
+// where it comes from. Secrets are redacted.
+type ExportedSetting struct {
+	Value  string               `json:"value"`
+	Source setting.ConfigSource `json:"source"`
+}
+
+// SettingsExport holds the exported settings indexed by section and key name
+type SettingsExport map[string]map[string]ExportedSetting
+
+// SetLogLevelCommand overrides the level of a named logger until the next restart.
+type SetLogLevelCommand struct {
+	// One of `trace`, `debug`, `info`, `warn`, `error` or `critical`.
+	// required:true
+	Level string `json:"level"`
+	// Only one out of `sampleRate` debug messages is logged when greater than one.
+	SampleRate int `json:"sampleRate"`
+}
@@ -7,4 +7,8 @@ type ShortURL struct {
 
 type CreateShortURLCmd struct {
 	Path string `json:"path"`
+	// Slug is the uid of the short URL, a random one is generated when empty
+	Slug string `json:"slug"`
+	// Expires is the number of seconds the short URL lives, it never expires when 0
+	Expires int64 `json:"expires"`
 }
@@ -1,5 +1,7 @@
 package dtos
 
+import "github.com/grafana/grafana/pkg/services/org"
+
 type SignUpForm struct {
 	Email string `json:"email" binding:"Required"`
 }
@@ -25,6 +27,10 @@ type AdminUpdateUserPasswordForm struct {
 	Password string `json:"password" binding:"Required"`
 }
 
+type AdminUnlockIPAddressForm struct {
+	IPAddress string `json:"ipAddress" binding:"Required"`
+}
+
 type AdminUpdateUserPermissionsForm struct {
 	IsGrafanaAdmin bool `json:"isGrafanaAdmin"`
 }
@@ -44,3 +50,38 @@ type UserLookupDTO struct {
 	Login     string `json:"login"`
 	AvatarURL string `json:"avatarUrl"`
 }
+
+// BulkUserRow is a single row of a bulk user request. Rows other than
+// create ones identify the user by id, login or email, in that order.
+type BulkUserRow struct {
+	ID       int64        `json:"id"`
+	Login    string       `json:"login"`
+	Email    string       `json:"email"`
+	Name     string       `json:"name"`
+	Password string       `json:"password"`
+	OrgID    int64        `json:"orgId"`
+	Role     org.RoleType `json:"role"`
+}
+
+const (
+	BulkUserStatusOK         = "ok"
+	BulkUserStatusFailed     = "failed"
+	BulkUserStatusRolledBack = "rolledBack"
+	BulkUserStatusSkipped    = "skipped"
+)
+
+type BulkUserResult struct {
+	// Row is the 1-based position of the row in the request payload
+	Row    int    `json:"row"`
+	UserID int64  `json:"userId,omitempty"`
+	Login  string `json:"login,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkUserResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkUserResult `json:"results"`
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/libraryelements/model"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
@@ -36,6 +37,10 @@ import (
 // 403: forbiddenError
 // 500: internalServerError
 func (hs *HTTPServer) GetFolders(c *contextmodel.ReqContext) response.Response {
+	if c.QueryBool("watch") {
+		return hs.watchDashboards(c, resourcewatch.KindFolder)
+	}
+
 	var folders []*folder.Folder
 	var err error
 	if hs.Features.IsEnabled(featuremgmt.FlagNestedFolders) {
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/licensing"
+)
+
+const frontendCapabilitiesCacheTTL = 30 * time.Second
+
+// GET /api/frontend/capabilities
+func (hs *HTTPServer) GetFrontendCapabilities(c *contextmodel.ReqContext) response.Response {
+	reloadCache := c.QueryBool("reloadcache")
+
+	// anonymous users have no cache key, their manifest is computed on every request
+	cacheKey, err := c.SignedInUser.GetCacheKey()
+	if err == nil {
+		cacheKey = "frontend-capabilities-" + cacheKey
+		if cached, found := hs.CacheService.Get(cacheKey); found && !reloadCache {
+			return response.JSON(http.StatusOK, cached)
+		}
+	}
+
+	capabilities, err := hs.getFrontendCapabilities(c, reloadCache)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get frontend capabilities", err)
+	}
+
+	if cacheKey != "" {
+		hs.CacheService.Set(cacheKey, capabilities, frontendCapabilitiesCacheTTL)
+	}
+	return response.JSON(http.StatusOK, capabilities)
+}
+
+// getFrontendCapabilities merges the feature toggles, the permissions of the user and the license of the instance.
+func (hs *HTTPServer) getFrontendCapabilities(c *contextmodel.ReqContext, reloadCache bool) (*dtos.FrontendCapabilitiesDTO, error) {
+	permissions := map[string]bool{}
+	if !hs.AccessControl.IsDisabled() {
+		userPermissions, err := hs.accesscontrolService.GetUserPermissions(c.Req.Context(), c.SignedInUser, accesscontrol.Options{ReloadCache: reloadCache})
+		if err != nil {
+			return nil, err
+		}
+		permissions = accesscontrol.BuildPermissionsMap(userPermissions)
+	}
+
+	hasAccess := accesscontrol.HasAccess(hs.AccessControl, c)
+	return &dtos.FrontendCapabilitiesDTO{
+		OrgID:          c.OrgID,
+		OrgRole:        string(c.OrgRole),
+		IsGrafanaAdmin: c.IsGrafanaAdmin,
+		FeatureToggles: hs.Features.GetEnabled(c.Req.Context()),
+		Permissions:    permissions,
+		License: dtos.FrontendSettingsLicenseInfoDTO{
+			Expiry:          hs.License.Expiry(),
+			StateInfo:       hs.License.StateInfo(),
+			LicenseUrl:      hs.License.LicenseURL(hasAccess(accesscontrol.ReqGrafanaAdmin, licensing.PageAccess)),
+			Edition:         hs.License.Edition(),
+			EnabledFeatures: hs.License.EnabledFeatures(),
+		},
+	}, nil
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPIEndpoint_GetFrontendCapabilities(t *testing.T) {
+	acService := &actest.FakeService{
+		ExpectedPermissions: []accesscontrol.Permission{
+			{Action: "dashboards:read", Scope: "dashboards:*"},
+			{Action: "folders:read", Scope: "folders:*"},
+		},
+	}
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.CacheService = localcache.ProvideService()
+		hs.Features = featuremgmt.WithFeatures("testToggle")
+		hs.accesscontrolService = acService
+	})
+
+	getCapabilities := func(t *testing.T, url string) dtos.FrontendCapabilitiesDTO {
+		t.Helper()
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest(url), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var capabilities dtos.FrontendCapabilitiesDTO
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&capabilities))
+		require.NoError(t, res.Body.Close())
+		return capabilities
+	}
+
+	t.Run("should merge the feature toggles, the permissions and the license", func(t *testing.T) {
+		capabilities := getCapabilities(t, "/api/frontend/capabilities")
+		assert.Equal(t, int64(1), capabilities.OrgID)
+		assert.Equal(t, "Editor", capabilities.OrgRole)
+		assert.True(t, capabilities.FeatureToggles["testToggle"])
+		assert.Equal(t, map[string]bool{"dashboards:read": true, "folders:read": true}, capabilities.Permissions)
+		assert.Equal(t, "Open Source", capabilities.License.Edition)
+	})
+
+	t.Run("should serve the cached manifest", func(t *testing.T) {
+		acService.ExpectedPermissions = []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}}
+		capabilities := getCapabilities(t, "/api/frontend/capabilities")
+		assert.Equal(t, map[string]bool{"dashboards:read": true, "folders:read": true}, capabilities.Permissions)
+	})
+
+	t.Run("should compute the manifest again when reloading the cache", func(t *testing.T) {
+		capabilities := getCapabilities(t, "/api/frontend/capabilities?reloadcache=true")
+		assert.Equal(t, map[string]bool{"dashboards:read": true}, capabilities.Permissions)
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
 	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -147,6 +148,22 @@ func TestHealthAPI_DatabaseHealthCached(t *testing.T) {
 	require.True(t, healthy.(bool))
 }
 
+func TestReadyz(t *testing.T) {
+	m, hs := setupHealthAPITestEnvironment(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "Ok", rec.Body.String())
+
+	hs.Drain()
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "Shutting down", rec.Body.String())
+}
+
 func setupHealthAPITestEnvironment(t *testing.T, cbs ...func(*setting.Cfg)) (*web.Mux, *HTTPServer) {
 	t.Helper()
 
@@ -159,8 +176,10 @@ func setupHealthAPITestEnvironment(t *testing.T, cbs ...func(*setting.Cfg)) (*we
 		CacheService: localcache.New(5*time.Minute, 10*time.Minute),
 		Cfg:          cfg,
 		SQLStore:     dbtest.NewFakeDB(),
+		log:          log.New("http.server"),
 	}
 
 	m.Get("/api/health", hs.apiHealthHandler)
+	m.Get("/readyz", hs.readyzHandler)
 	return m, hs
 }
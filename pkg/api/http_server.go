@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -25,6 +26,9 @@ import (
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/log/audit"
+	"github.com/grafana/grafana/pkg/infra/metrics/orgusage"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	loginpkg "github.com/grafana/grafana/pkg/login"
@@ -32,17 +36,21 @@ import (
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/middleware/csrf"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/pluginscdn"
 	"github.com/grafana/grafana/pkg/registry/corekind"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/federation"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/cleanup"
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
@@ -52,7 +60,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
 	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/idempotency"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/librarypanels"
 	"github.com/grafana/grafana/pkg/services/licensing"
@@ -65,9 +77,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
+	"github.com/grafana/grafana/pkg/services/pluginmigration"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsecrets"
 	pluginSettings "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/provisioning"
@@ -76,14 +91,19 @@ import (
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/querylibrary"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/searchusers"
+	"github.com/grafana/grafana/pkg/services/seats"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	secretsKV "github.com/grafana/grafana/pkg/services/secrets/kvstore"
 	spm "github.com/grafana/grafana/pkg/services/secrets/kvstore/migrations"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/settingswatcher"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/star"
@@ -97,17 +117,21 @@ import (
 	tempUser "github.com/grafana/grafana/pkg/services/temp_user"
 	"github.com/grafana/grafana/pkg/services/thumbs"
 	"github.com/grafana/grafana/pkg/services/updatechecker"
+	"github.com/grafana/grafana/pkg/services/usageinsights"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/userdata"
 	"github.com/grafana/grafana/pkg/services/validations"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type HTTPServer struct {
-	log              log.Logger
-	web              *web.Mux
-	context          context.Context
-	httpSrv          *http.Server
+	log     log.Logger
+	web     *web.Mux
+	context context.Context
+	httpSrv *http.Server
+	// draining is set once the server is shutting down, it is reported by the readiness endpoint
+	draining         atomic.Bool
 	middlewares      []web.Handler
 	namedMiddlewares []routing.RegisterNamedMiddleware
 	bus              bus.Bus
@@ -134,6 +158,8 @@ type HTTPServer struct {
 	pluginClient                 plugins.Client
 	pluginStore                  plugins.Store
 	pluginInstaller              plugins.Installer
+	pluginProcessManager         process.Service
+	pluginSecretsService         pluginsecrets.Service
 	pluginDashboardService       plugindashboards.Service
 	pluginStaticRouteResolver    plugins.StaticRouteResolver
 	pluginErrorResolver          plugins.ErrorResolver
@@ -210,6 +236,26 @@ type HTTPServer struct {
 	statsService           stats.Service
 	authnService           authn.Service
 	starApi                *starApi.API
+	usageInsightsService   usageinsights.Service
+	rateLimitService       ratelimit.Service
+	auditLogger            audit.Logger
+	orgUsageMetrics        *orgusage.Service
+	jobQueue               jobqueue.Service
+	settingsWatcher        *settingswatcher.Service
+	orgSettingsService     orgsettings.Service
+	folderSettingsService  foldersettings.Service
+	dashboardLintService   dashboardlint.Service
+	pluginMigrations       pluginmigration.Service
+	seatsService           seats.Service
+	objectStorage          *objectstore.Service
+	userDataService        userdata.Service
+	inboxService           inbox.Service
+	dashboardApproval      dashboardapproval.Service
+	idempotency            *idempotency.Service
+	secretsUsage           *secretsKV.UsageTracker
+	resourceWatch          *resourcewatch.Service
+	savedSearchService     savedsearch.Service
+	annotationFederation   *federation.Service
 }
 
 type ServerOptions struct {
@@ -221,7 +267,7 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	cacheService *localcache.CacheService, sqlStore *sqlstore.SQLStore, alertEngine *alerting.AlertEngine,
 	pluginRequestValidator validations.PluginRequestValidator, pluginStaticRouteResolver plugins.StaticRouteResolver,
 	pluginDashboardService plugindashboards.Service, pluginStore plugins.Store, pluginClient plugins.Client,
-	pluginErrorResolver plugins.ErrorResolver, pluginInstaller plugins.Installer, settingsProvider setting.Provider,
+	pluginErrorResolver plugins.ErrorResolver, pluginInstaller plugins.Installer, pluginProcessManager process.Service, pluginSecretsService pluginsecrets.Service, settingsProvider setting.Provider,
 	dataSourceCache datasources.CacheService, userTokenService auth.UserTokenService,
 	cleanUpService *cleanup.CleanUpService, shortURLService shorturls.Service, queryHistoryService queryhistory.Service, correlationsService correlations.Service,
 	thumbService thumbs.Service, remoteCache *remotecache.RemoteCache, provisioningService provisioning.ProvisioningService,
@@ -253,7 +299,14 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 	annotationRepo annotations.Repository, tagService tag.Service, searchv2HTTPService searchV2.SearchHTTPService,
 	queryLibraryHTTPService querylibrary.HTTPService, queryLibraryService querylibrary.Service, oauthTokenService oauthtoken.OAuthTokenService,
 	statsService stats.Service, authnService authn.Service, pluginsCDNService *pluginscdn.Service,
-	starApi *starApi.API,
+	starApi *starApi.API, usageInsightsService usageinsights.Service, orgSettingsService orgsettings.Service,
+	rateLimitService ratelimit.Service, auditLogger audit.Logger, orgUsageMetrics *orgusage.Service,
+	jobQueue jobqueue.Service, settingsWatcher *settingswatcher.Service, folderSettingsService foldersettings.Service,
+	secretsUsage *secretsKV.UsageTracker, resourceWatch *resourcewatch.Service, savedSearchService savedsearch.Service,
+	annotationFederation *federation.Service, dashboardLintService dashboardlint.Service,
+	pluginMigrations pluginmigration.Service,
+	seatsService seats.Service, objectStorage *objectstore.Service, userDataService userdata.Service,
+	inboxService inbox.Service, dashboardApproval dashboardapproval.Service, idempotencyService *idempotency.Service,
 ) (*HTTPServer, error) {
 	web.Env = cfg.Env
 	m := web.New()
@@ -270,6 +323,8 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		AlertEngine:                  alertEngine,
 		PluginRequestValidator:       pluginRequestValidator,
 		pluginInstaller:              pluginInstaller,
+		pluginProcessManager:         pluginProcessManager,
+		pluginSecretsService:         pluginSecretsService,
 		pluginClient:                 pluginClient,
 		pluginStore:                  pluginStore,
 		pluginStaticRouteResolver:    pluginStaticRouteResolver,
@@ -359,11 +414,34 @@ func ProvideHTTPServer(opts ServerOptions, cfg *setting.Cfg, routeRegister routi
 		authnService:                 authnService,
 		pluginsCDNService:            pluginsCDNService,
 		starApi:                      starApi,
+		usageInsightsService:         usageInsightsService,
+		rateLimitService:             rateLimitService,
+		auditLogger:                  auditLogger,
+		orgUsageMetrics:              orgUsageMetrics,
+		jobQueue:                     jobQueue,
+		settingsWatcher:              settingsWatcher,
+		orgSettingsService:           orgSettingsService,
+		folderSettingsService:        folderSettingsService,
+		secretsUsage:                 secretsUsage,
+		resourceWatch:                resourceWatch,
+		savedSearchService:           savedSearchService,
+		annotationFederation:         annotationFederation,
+		dashboardLintService:         dashboardLintService,
+		pluginMigrations:             pluginMigrations,
+		seatsService:                 seatsService,
+		objectStorage:                objectStorage,
+		userDataService:              userDataService,
+		inboxService:                 inboxService,
+		dashboardApproval:            dashboardApproval,
+		idempotency:                  idempotencyService,
 	}
 	if hs.Listener != nil {
 		hs.log.Debug("Using provided listener")
 	}
 	hs.registerRoutes()
+	hs.HooksService.AddLoginHook(hs.auditLogin)
+	hs.registerReEncryptionJobs()
+	hs.registerAccountDeletionJob()
 
 	// Register access control scope resolver for annotations
 	hs.AccessControl.RegisterScopeAttributeResolver(AnnotationTypeScopeResolver(hs.annotationsRepo))
@@ -422,6 +500,7 @@ func (hs *HTTPServer) Run(ctx context.Context) error {
 		defer wg.Done()
 
 		<-ctx.Done()
+		hs.Drain()
 		if err := hs.httpSrv.Shutdown(context.Background()); err != nil {
 			hs.log.Error("Failed to shutdown server", "error", err)
 		}
@@ -429,30 +508,31 @@ func (hs *HTTPServer) Run(ctx context.Context) error {
 
 	switch hs.Cfg.Protocol {
 	case setting.HTTPScheme, setting.SocketScheme:
-		if err := hs.httpSrv.Serve(listener); err != nil {
-			if errors.Is(err, http.ErrServerClosed) {
-				hs.log.Debug("server was shutdown gracefully")
-				return nil
-			}
-			return err
-		}
+		err = hs.httpSrv.Serve(listener)
 	case setting.HTTP2Scheme, setting.HTTPSScheme:
-		if err := hs.httpSrv.ServeTLS(listener, hs.Cfg.CertFile, hs.Cfg.KeyFile); err != nil {
-			if errors.Is(err, http.ErrServerClosed) {
-				hs.log.Debug("server was shutdown gracefully")
-				return nil
-			}
-			return err
-		}
+		err = hs.httpSrv.ServeTLS(listener, hs.Cfg.CertFile, hs.Cfg.KeyFile)
 	default:
 		panic(fmt.Sprintf("Unhandled protocol %q", hs.Cfg.Protocol))
 	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
 
+	// Serve returns as soon as the listener is closed, wait for the requests in progress to complete
 	wg.Wait()
+	hs.log.Debug("server was shutdown gracefully")
 
 	return nil
 }
 
+// Drain reports the server as not ready, so that the load balancers stop sending it new requests
+// before it shuts down. The requests keep being served until the server stops.
+func (hs *HTTPServer) Drain() {
+	if hs.draining.CompareAndSwap(false, true) {
+		hs.log.Info("Server is draining, reporting not ready")
+	}
+}
+
 func (hs *HTTPServer) getListener() (net.Listener, error) {
 	if hs.Listener != nil {
 		return hs.Listener, nil
@@ -581,7 +661,7 @@ func (hs *HTTPServer) addMiddlewaresAndStaticRoutes() {
 	m := hs.web
 
 	m.Use(middleware.RequestTracing(hs.tracer))
-	m.Use(middleware.RequestMetrics(hs.Features))
+	m.Use(middleware.RequestMetrics(hs.Features, hs.Cfg))
 
 	m.UseMiddleware(middleware.Logger(hs.Cfg))
 
@@ -615,8 +695,10 @@ func (hs *HTTPServer) addMiddlewaresAndStaticRoutes() {
 	// These endpoints are used for monitoring the Grafana instance
 	// and should not be redirected or rejected.
 	m.Use(hs.healthzHandler)
+	m.Use(hs.readyzHandler)
 	m.Use(hs.apiHealthHandler)
 	m.Use(hs.metricsEndpoint)
+	m.Use(hs.orgUsageMetricsEndpoint)
 	m.Use(hs.pluginMetricsEndpoint)
 	m.Use(hs.frontendLogEndpoints())
 
@@ -632,6 +714,7 @@ func (hs *HTTPServer) addMiddlewaresAndStaticRoutes() {
 	}
 
 	m.Use(middleware.HandleNoCacheHeader)
+	m.UseMiddleware(middleware.Audit(hs.auditLogger))
 
 	if hs.Cfg.CSPEnabled || hs.Cfg.CSPReportOnlyEnabled {
 		m.UseMiddleware(middleware.ContentSecurityPolicy(hs.Cfg, hs.log))
@@ -661,6 +744,24 @@ func (hs *HTTPServer) metricsEndpoint(ctx *web.Context) {
 		ServeHTTP(ctx.Resp, ctx.Req)
 }
 
+// orgUsageMetricsEndpoint serves the usage metrics by organization, protected like the /metrics endpoint
+func (hs *HTTPServer) orgUsageMetricsEndpoint(ctx *web.Context) {
+	if hs.orgUsageMetrics == nil || hs.orgUsageMetrics.IsDisabled() {
+		return
+	}
+
+	if ctx.Req.Method != http.MethodGet || ctx.Req.URL.Path != "/metrics/usage" {
+		return
+	}
+
+	if hs.metricsEndpointBasicAuthEnabled() && !BasicAuthenticatedRequest(ctx.Req, hs.Cfg.MetricsEndpointBasicAuthUsername, hs.Cfg.MetricsEndpointBasicAuthPassword) {
+		ctx.Resp.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	hs.orgUsageMetrics.Handler().ServeHTTP(ctx.Resp, ctx.Req)
+}
+
 // healthzHandler always return 200 - Ok if Grafana's web server is running
 func (hs *HTTPServer) healthzHandler(ctx *web.Context) {
 	notHeadOrGet := ctx.Req.Method != http.MethodGet && ctx.Req.Method != http.MethodHead
@@ -674,6 +775,24 @@ func (hs *HTTPServer) healthzHandler(ctx *web.Context) {
 	}
 }
 
+// readyzHandler returns 200 - Ok while Grafana's web server accepts new requests, and
+// 503 - Shutting down once it is draining for shutdown.
+func (hs *HTTPServer) readyzHandler(ctx *web.Context) {
+	notHeadOrGet := ctx.Req.Method != http.MethodGet && ctx.Req.Method != http.MethodHead
+	if notHeadOrGet || ctx.Req.URL.Path != "/readyz" {
+		return
+	}
+
+	status, body := http.StatusOK, "Ok"
+	if hs.draining.Load() {
+		status, body = http.StatusServiceUnavailable, "Shutting down"
+	}
+	ctx.Resp.WriteHeader(status)
+	if _, err := ctx.Resp.Write([]byte(body)); err != nil {
+		hs.log.Error("could not write to response", "err", err)
+	}
+}
+
 // apiHealthHandler will return ok if Grafana's web server is running and it
 // can access the database. If the database cannot be accessed it will return
 // http status code 503.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log/audit"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/infra/network"
 	"github.com/grafana/grafana/pkg/login"
@@ -20,6 +21,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	loginservice "github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -108,6 +110,16 @@ func (hs *HTTPServer) LoginView(c *contextmodel.ReqContext) {
 		return
 	}
 
+	// the session was signed out by the authentication methods allowed in the organization
+	if errors.Is(c.LookupTokenErr, loginservice.ErrAuthMethodNotAllowed) {
+		var gfErr errutil.Error
+		if errors.As(c.LookupTokenErr, &gfErr) {
+			viewData.Settings.LoginError = gfErr.PublicMessage
+		}
+		c.HTML(http.StatusOK, getViewIndex(), viewData)
+		return
+	}
+
 	if hs.tryAutoLogin(c) {
 		return
 	}
@@ -116,7 +128,7 @@ func (hs *HTTPServer) LoginView(c *contextmodel.ReqContext) {
 		// Assign login token to auth proxy users if enable_login_token = true
 		if hs.Cfg.AuthProxyEnabled && hs.Cfg.AuthProxyEnableLoginToken {
 			user := &user.User{ID: c.SignedInUser.UserID, Email: c.SignedInUser.Email, Login: c.SignedInUser.Login}
-			err := hs.loginUserWithUser(user, c)
+			err := hs.loginUserWithUser(user, c, loginservice.AuthProxyAuthModule)
 			if err != nil {
 				c.Handle(hs.Cfg, http.StatusInternalServerError, "Failed to sign in user", err)
 				return
@@ -275,7 +287,16 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 
 	usr = authQuery.User
 
-	err = hs.loginUserWithUser(usr, c)
+	// the Grafana admins can sign in with any method so that they can't be locked out
+	if !usr.IsAdmin {
+		allowed := util.SplitString(hs.orgSettingsService.GetString(c.Req.Context(), usr.OrgID, orgsettings.AllowedAuthMethods))
+		if err := loginservice.CheckAuthMethod(allowed, authModule); err != nil {
+			resp = response.Err(err)
+			return resp
+		}
+	}
+
+	err = hs.loginUserWithUser(usr, c, authModule)
 	if err != nil {
 		var createTokenErr *auth.CreateTokenErr
 		if errors.As(err, &createTokenErr) {
@@ -304,7 +325,7 @@ func (hs *HTTPServer) LoginPost(c *contextmodel.ReqContext) response.Response {
 	return resp
 }
 
-func (hs *HTTPServer) loginUserWithUser(user *user.User, c *contextmodel.ReqContext) error {
+func (hs *HTTPServer) loginUserWithUser(user *user.User, c *contextmodel.ReqContext, authModule string) error {
 	if user == nil {
 		return errors.New("could not login user")
 	}
@@ -318,7 +339,7 @@ func (hs *HTTPServer) loginUserWithUser(user *user.User, c *contextmodel.ReqCont
 
 	hs.log.Debug("Got IP address from client address", "addr", addr, "ip", ip)
 	ctx := context.WithValue(c.Req.Context(), loginservice.RequestURIKey{}, c.Req.RequestURI)
-	userToken, err := hs.AuthTokenService.CreateToken(ctx, user, ip, c.Req.UserAgent())
+	userToken, err := hs.AuthTokenService.CreateToken(ctx, user, ip, c.Req.UserAgent(), authModule)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "failed to create auth token", err)
 	}
@@ -329,6 +350,26 @@ func (hs *HTTPServer) loginUserWithUser(user *user.User, c *contextmodel.ReqCont
 	return nil
 }
 
+// auditLogin is the login hook recording the login attempts, of all the authentication modules, in the audit trail.
+func (hs *HTTPServer) auditLogin(info *loginservice.LoginInfo, c *contextmodel.ReqContext) {
+	event := audit.Event{
+		Action: audit.ActionLogin,
+		Result: audit.ResultSuccess,
+		Actor:  audit.Actor{Login: info.LoginUsername, IP: c.RemoteAddr()},
+		Fields: map[string]any{"authModule": info.AuthModule, "status": info.HTTPStatus},
+	}
+	if info.User != nil {
+		event.Actor.UserID = info.User.ID
+		event.Actor.Login = info.User.Login
+		event.Actor.OrgID = info.User.OrgID
+	}
+	if info.Error != nil {
+		event.Result = audit.ResultFailure
+		event.Fields["error"] = info.Error.Error()
+	}
+	hs.auditLogger.Log(c.Req.Context(), event)
+}
+
 func (hs *HTTPServer) Logout(c *contextmodel.ReqContext) {
 	// If SAML is enabled and this is a SAML user use saml logout
 	if hs.samlSingleLogoutEnabled() {
@@ -402,7 +443,7 @@ func (hs *HTTPServer) RedirectResponseWithError(c *contextmodel.ReqContext, err
 
 func (hs *HTTPServer) redirectURLWithErrorCookie(c *contextmodel.ReqContext, err error) string {
 	setCookie := true
-	if hs.Features.IsEnabled(featuremgmt.FlagIndividualCookiePreferences) {
+	if hs.Features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagIndividualCookiePreferences) {
 		prefsQuery := pref.GetPreferenceWithDefaultsQuery{UserID: c.UserID, OrgID: c.OrgID, Teams: c.Teams}
 		prefs, err := hs.preferenceService.GetWithDefaults(c.Req.Context(), &prefsQuery)
 		if err != nil {
@@ -283,7 +283,7 @@ func (hs *HTTPServer) OAuthLogin(ctx *contextmodel.ReqContext) {
 	}
 
 	// login
-	if err := hs.loginUserWithUser(loginInfo.User, ctx); err != nil {
+	if err := hs.loginUserWithUser(loginInfo.User, ctx, loginInfo.ExternalUser.AuthModule); err != nil {
 		hs.handleOAuthLoginErrorWithRedirect(ctx, loginInfo, err)
 		return
 	}
@@ -29,11 +29,14 @@ import (
 	"github.com/grafana/grafana/pkg/services/licensing"
 	loginservice "github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/navtree"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 func fakeSetIndexViewData(t *testing.T) {
@@ -327,6 +330,7 @@ func TestLoginPostRedirect(t *testing.T) {
 		AuthTokenService: authtest.NewFakeUserAuthTokenService(),
 		Features:         featuremgmt.WithFeatures(),
 	}
+	hs.orgSettingsService = orgsettingstest.NewFakeService(hs.Cfg)
 	hs.Cfg.CookieSecure = true
 
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
@@ -608,6 +612,11 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 		Features:         featuremgmt.WithFeatures(),
 		HooksService:     hookService,
 	}
+	orgSettings := orgsettingstest.NewFakeService(hs.Cfg)
+	orgSettings.Overrides[orgsettings.AllowedAuthMethods] = "basic,ldap"
+	hs.orgSettingsService = orgSettings
+	var notAllowedErr errutil.Error
+	require.ErrorAs(t, loginservice.CheckAuthMethod([]string{"basic", "ldap"}, loginservice.SAMLAuthModule), &notAllowedErr)
 
 	sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
 		c.Req.Header.Set("Content-Type", "application/json")
@@ -669,6 +678,17 @@ func TestLoginPostRunLokingHook(t *testing.T) {
 				HTTPStatus: 200,
 			},
 		},
+		{
+			desc:       "SAML user not allowed in the organization",
+			authUser:   testUser,
+			authModule: loginservice.SAMLAuthModule,
+			info: loginservice.LoginInfo{
+				AuthModule: loginservice.SAMLAuthModule,
+				User:       testUser,
+				HTTPStatus: 403,
+				Error:      &notAllowedErr,
+			},
+		},
 	}
 
 	for _, c := range testCases {
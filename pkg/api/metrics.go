@@ -1,17 +1,28 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
 
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -39,6 +50,18 @@ func (hs *HTTPServer) handleQueryMetricsError(err error) *response.NormalRespons
 // If you are running Grafana Enterprise and have Fine-grained access control enabled
 // you need to have a permission with action: `datasources:query`.
 //
+// When the request accepts `application/x-ndjson`, the frames are streamed as newline delimited JSON,
+// one `{"refId", "frame"}` or `{"refId", "error"}` object per line, as the data sources return them.
+//
+// When the request accepts `application/vnd.apache.arrow.stream`, every frame is written as an Arrow IPC stream,
+// one after the other. The refId of the frame is stored in the `refId` metadata of its schema, and a failed query
+// is returned as a frame without fields holding the error in the notices of its `meta` metadata.
+//
+// Produces:
+// - application/json
+// - application/x-ndjson
+// - application/vnd.apache.arrow.stream
+//
 // Responses:
 // 200: queryMetricsWithExpressionsRespons
 // 207: queryMetricsWithExpressionsRespons
@@ -52,27 +75,266 @@ func (hs *HTTPServer) QueryMetricsV2(c *contextmodel.ReqContext) response.Respon
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 
+	if strings.Contains(c.Req.Header.Get("Accept"), contentTypeChunkedQuery) {
+		return hs.queryMetricsChunked(c, reqDTO)
+	}
+
 	resp, err := hs.queryDataService.QueryData(c.Req.Context(), c.SignedInUser, c.SkipCache, reqDTO)
+	hs.recordQueryUsage(c, reqDTO, resp, err)
 	if err != nil {
 		return hs.handleQueryMetricsError(err)
 	}
-	return hs.toJsonStreamingResponse(resp)
+	if strings.Contains(c.Req.Header.Get("Accept"), contentTypeArrowStream) {
+		return hs.toArrowStreamingResponse(c.Req.Context(), resp)
+	}
+	return hs.toJsonStreamingResponse(c.Req.Context(), resp)
 }
 
-func (hs *HTTPServer) toJsonStreamingResponse(qdr *backend.QueryDataResponse) response.Response {
-	statusWhenError := http.StatusBadRequest
-	if hs.Features.IsEnabled(featuremgmt.FlagDatasourceQueryMultiStatus) {
-		statusWhenError = http.StatusMultiStatus
+// swagger:route POST /ds/query/federated ds queryMetricsFederated
+//
+// Query multiple data sources and align their time series on a single time field.
+//
+// The queries and expressions are executed like in `/ds/query`, then the time series frames of the responses are
+// outer joined on their time into a single frame. The values missing at a time of the joined frame are null.
+// `refIds` limits the responses that are joined, for example to leave out the inputs of an expression.
+//
+// If you are running Grafana Enterprise and have Fine-grained access control enabled
+// you need to have a permission with action: `datasources:query`.
+//
+// Responses:
+// 200: queryMetricsFederatedResponse
+// 207: queryMetricsFederatedResponse
+// 401: unauthorisedError
+// 400: badRequestError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) QueryMetricsFederated(c *contextmodel.ReqContext) response.Response {
+	reqDTO := dtos.FederatedMetricRequest{}
+	if err := web.Bind(c.Req, &reqDTO); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	resp, err := hs.queryDataService.QueryData(c.Req.Context(), c.SignedInUser, c.SkipCache, reqDTO.MetricRequest)
+	hs.recordQueryUsage(c, reqDTO.MetricRequest, resp, err)
+	if err != nil {
+		return hs.handleQueryMetricsError(err)
+	}
+
+	refIDs := reqDTO.RefIDs
+	if len(refIDs) == 0 {
+		for refID := range resp.Responses {
+			refIDs = append(refIDs, refID)
+		}
+		sort.Strings(refIDs)
 	}
 
+	frame, errs := query.AlignResponsesByTime(resp.Responses, refIDs)
+	result := dtos.FederatedMetricResponse{Frame: frame}
 	statusCode := http.StatusOK
+	if len(errs) > 0 {
+		result.Errors = make(map[string]string, len(errs))
+		for refID, err := range errs {
+			result.Errors[refID] = err.Error()
+		}
+		statusCode = hs.queryErrorStatus(c.Req.Context())
+	}
+	return response.JSON(statusCode, result)
+}
+
+// queryMetricsChunked streams the frames of the query responses to the client as soon as they are available
+func (hs *HTTPServer) queryMetricsChunked(c *contextmodel.ReqContext, reqDTO dtos.MetricRequest) response.Response {
+	w := &chunkedQueryWriter{resp: c.Resp, enc: json.NewEncoder(c.Resp), errors: backend.Responses{}}
+	err := hs.queryDataService.QueryDataChunked(c.Req.Context(), c.SignedInUser, c.SkipCache, reqDTO, w)
+	hs.recordQueryUsage(c, reqDTO, &backend.QueryDataResponse{Responses: w.errors}, err)
+	if err != nil {
+		if !w.started {
+			return hs.handleQueryMetricsError(err)
+		}
+		// the status was already sent, the failure is reported in the stream
+		hs.log.Error("Failed to stream query data", "error", err)
+		_ = w.write(chunkedQueryLine{Error: "Query data error"})
+		return nil
+	}
+
+	w.start()
+	return nil
+}
+
+const contentTypeChunkedQuery = "application/x-ndjson"
+
+type chunkedQueryLine struct {
+	RefID string      `json:"refId,omitempty"`
+	Frame *data.Frame `json:"frame,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// chunkedQueryWriter writes the frames of the query responses as newline delimited JSON, flushing each line
+type chunkedQueryWriter struct {
+	resp    web.ResponseWriter
+	enc     *json.Encoder
+	started bool
+	// errors holds the failed queries, for usage insights
+	errors backend.Responses
+}
+
+func (w *chunkedQueryWriter) start() {
+	if w.started {
+		return
+	}
+	w.resp.Header().Set("Content-Type", contentTypeChunkedQuery)
+	w.resp.WriteHeader(http.StatusOK)
+	w.started = true
+}
+
+func (w *chunkedQueryWriter) write(line chunkedQueryLine) error {
+	w.start()
+	if err := w.enc.Encode(line); err != nil {
+		return err
+	}
+	w.resp.Flush()
+	return nil
+}
+
+func (w *chunkedQueryWriter) WriteFrame(refID string, frame *data.Frame) error {
+	return w.write(chunkedQueryLine{RefID: refID, Frame: frame})
+}
+
+func (w *chunkedQueryWriter) WriteError(refID string, err error) error {
+	w.errors[refID] = backend.DataResponse{Error: err}
+	return w.write(chunkedQueryLine{RefID: refID, Error: err.Error()})
+}
+
+// recordQueryUsage counts the queries and failures per data source and dashboard for usage insights,
+// and the queries per organization for the usage metrics
+func (hs *HTTPServer) recordQueryUsage(c *contextmodel.ReqContext, reqDTO dtos.MetricRequest, resp *backend.QueryDataResponse, queryErr error) {
+	if hs.orgUsageMetrics != nil {
+		for range reqDTO.Queries {
+			hs.orgUsageMetrics.RecordQuery(c.OrgID)
+		}
+	}
+	if hs.usageInsightsService == nil {
+		return
+	}
+
+	dashboardUID := c.Req.Header.Get(query.HeaderDashboardUID)
+	for _, q := range reqDTO.Queries {
+		failed := queryErr != nil
+		if !failed && resp != nil {
+			if res, ok := resp.Responses[q.Get("refId").MustString("A")]; ok && res.Error != nil {
+				failed = true
+			}
+		}
+
+		datasourceUID := q.Get("datasource").Get("uid").MustString()
+		hs.usageInsightsService.RecordQuery(c.Req.Context(), c.OrgID, dashboardUID, datasourceUID, failed)
+	}
+}
+
+func (hs *HTTPServer) toJsonStreamingResponse(ctx context.Context, qdr *backend.QueryDataResponse) response.Response {
+	return response.JSONStreaming(hs.queryResponseStatus(ctx, qdr), qdr)
+}
+
+func (hs *HTTPServer) toArrowStreamingResponse(ctx context.Context, qdr *backend.QueryDataResponse) response.Response {
+	return arrowQueryResponse{status: hs.queryResponseStatus(ctx, qdr), qdr: qdr}
+}
+
+func (hs *HTTPServer) queryResponseStatus(ctx context.Context, qdr *backend.QueryDataResponse) int {
 	for _, res := range qdr.Responses {
 		if res.Error != nil {
-			statusCode = statusWhenError
+			return hs.queryErrorStatus(ctx)
 		}
 	}
+	return http.StatusOK
+}
+
+// queryErrorStatus is the status of the responses with failed queries
+func (hs *HTTPServer) queryErrorStatus(ctx context.Context) int {
+	if hs.Features.IsEnabledCtx(ctx, featuremgmt.FlagDatasourceQueryMultiStatus) {
+		return http.StatusMultiStatus
+	}
+	return http.StatusBadRequest
+}
+
+const contentTypeArrowStream = "application/vnd.apache.arrow.stream"
 
-	return response.JSONStreaming(statusCode, qdr)
+// arrowQueryResponse writes the frames of the query responses as consecutive Arrow IPC streams, ordered by refId
+type arrowQueryResponse struct {
+	status int
+	qdr    *backend.QueryDataResponse
+}
+
+func (r arrowQueryResponse) Status() int {
+	return r.status
+}
+
+func (r arrowQueryResponse) Body() []byte {
+	return nil
+}
+
+func (r arrowQueryResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	ctx.Resp.Header().Set("Content-Type", contentTypeArrowStream)
+	ctx.Resp.WriteHeader(r.status)
+
+	refIDs := make([]string, 0, len(r.qdr.Responses))
+	for refID := range r.qdr.Responses {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+
+	for _, refID := range refIDs {
+		res := r.qdr.Responses[refID]
+		frames := res.Frames
+		if res.Error != nil {
+			frames = data.Frames{data.NewFrame("").SetMeta(&data.FrameMeta{
+				Notices: []data.Notice{{Severity: data.NoticeSeverityError, Text: res.Error.Error()}},
+			})}
+		}
+
+		for _, frame := range frames {
+			frame.RefID = refID
+			if err := writeArrowStream(ctx.Resp, frame); err != nil {
+				ctx.Logger.Error("Error writing to response", "refId", refID, "err", err)
+				return
+			}
+		}
+	}
+}
+
+// writeArrowStream writes the frame as an Arrow IPC stream. The SDK only encodes frames in the Arrow IPC file
+// format, so the records of the encoded file are copied to a stream writer. Empty frames are written with a
+// record without rows, so that every stream can be decoded to a frame.
+func writeArrowStream(w io.Writer, frame *data.Frame) error {
+	b, err := frame.MarshalArrow()
+	if err != nil {
+		return err
+	}
+
+	fr, err := ipc.NewFileReader(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fr.Close() }()
+
+	sw := ipc.NewWriter(w, ipc.WithSchema(fr.Schema()))
+	if fr.NumRecords() == 0 {
+		b := array.NewRecordBuilder(memory.DefaultAllocator, fr.Schema())
+		defer b.Release()
+		rec := b.NewRecord()
+		defer rec.Release()
+		if err := sw.Write(rec); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < fr.NumRecords(); i++ {
+		rec, err := fr.Record(i)
+		if err != nil {
+			return err
+		}
+		if err := sw.Write(rec); err != nil {
+			return err
+		}
+	}
+	return sw.Close()
 }
 
 // swagger:parameters queryMetricsWithExpressions
@@ -82,6 +344,19 @@ type QueryMetricsWithExpressionsBodyParams struct {
 	Body dtos.MetricRequest `json:"body"`
 }
 
+// swagger:parameters queryMetricsFederated
+type QueryMetricsFederatedBodyParams struct {
+	// in:body
+	// required:true
+	Body dtos.FederatedMetricRequest `json:"body"`
+}
+
+// swagger:response queryMetricsFederatedResponse
+type QueryMetricsFederatedResponse struct {
+	// in: body
+	Body dtos.FederatedMetricResponse `json:"body"`
+}
+
 // swagger:response queryMetricsWithExpressionsRespons
 type QueryMetricsWithExpressionsRespons struct {
 	// The response message
@@ -10,10 +10,14 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/apache/arrow/go/arrow/ipc"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/config"
@@ -92,6 +96,159 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 	})
 }
 
+func TestAPIEndpoint_Metrics_QueryMetricsV2Chunked(t *testing.T) {
+	qds := query.ProvideService(
+		setting.NewCfg(),
+		nil,
+		nil,
+		&fakePluginRequestValidator{},
+		&fakeDatasources.FakeDataSourceService{},
+		&fakePluginClient{
+			QueryDataHandlerFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				// a plugin streaming part of its response
+				w, ok := backendplugin.ChunkedDataWriterFromContext(ctx)
+				require.True(t, ok)
+				require.NoError(t, w.WriteFrame("A", data.NewFrame("streamed")))
+
+				resp := backend.Responses{
+					"A": backend.DataResponse{Frames: data.Frames{data.NewFrame("returned")}},
+				}
+				return &backend.QueryDataResponse{Responses: resp}, nil
+			},
+		},
+	)
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.queryDataService = qds
+		hs.QuotaService = quotatest.New(false, nil)
+	})
+
+	req := server.NewPostRequest("/api/ds/query", strings.NewReader(reqValid))
+	req.Header.Set("Accept", "application/x-ndjson")
+	webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+	resp, err := server.SendJSON(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var names []string
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var line struct {
+			RefID string      `json:"refId"`
+			Frame *data.Frame `json:"frame"`
+		}
+		require.NoError(t, dec.Decode(&line))
+		require.Equal(t, "A", line.RefID)
+		names = append(names, line.Frame.Name)
+	}
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, []string{"streamed", "returned"}, names)
+}
+
+func TestAPIEndpoint_Metrics_QueryMetricsV2Arrow(t *testing.T) {
+	qds := query.ProvideService(
+		setting.NewCfg(),
+		nil,
+		nil,
+		&fakePluginRequestValidator{},
+		&fakeDatasources.FakeDataSourceService{},
+		&fakePluginClient{
+			QueryDataHandlerFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				resp := backend.Responses{
+					"A": backend.DataResponse{Frames: data.Frames{
+						data.NewFrame("first", data.NewField("value", nil, []int64{1, 2})),
+						data.NewFrame("second", data.NewField("value", nil, []string{})),
+					}},
+					"B": backend.DataResponse{Error: errors.New("query failed")},
+				}
+				return &backend.QueryDataResponse{Responses: resp}, nil
+			},
+		},
+	)
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.queryDataService = qds
+		hs.Features = featuremgmt.WithFeatures(featuremgmt.FlagDatasourceQueryMultiStatus, true)
+		hs.QuotaService = quotatest.New(false, nil)
+	})
+
+	req := server.NewPostRequest("/api/ds/query", strings.NewReader(reqValid))
+	req.Header.Set("Accept", "application/vnd.apache.arrow.stream")
+	webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+	resp, err := server.SendJSON(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	require.Equal(t, "application/vnd.apache.arrow.stream", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	var frames data.Frames
+	r := bytes.NewReader(body)
+	for r.Len() > 0 {
+		sr, err := ipc.NewReader(r)
+		require.NoError(t, err)
+		require.True(t, sr.Next())
+		frame, err := data.FromArrowRecord(sr.Record())
+		require.NoError(t, err)
+		frames = append(frames, frame)
+		require.False(t, sr.Next())
+		sr.Release()
+	}
+
+	require.Len(t, frames, 3)
+	require.Equal(t, "first", frames[0].Name)
+	require.Equal(t, "A", frames[0].RefID)
+	require.Equal(t, 2, frames[0].Rows())
+	require.Equal(t, "second", frames[1].Name)
+	require.Equal(t, 0, frames[1].Rows())
+	require.Equal(t, "B", frames[2].RefID)
+	require.Equal(t, "query failed", frames[2].Meta.Notices[0].Text)
+}
+
+func TestAPIEndpoint_Metrics_QueryMetricsFederated(t *testing.T) {
+	qds := query.ProvideService(
+		setting.NewCfg(),
+		nil,
+		nil,
+		&fakePluginRequestValidator{},
+		&fakeDatasources.FakeDataSourceService{},
+		&fakePluginClient{
+			QueryDataHandlerFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				resp := backend.Responses{
+					"A": backend.DataResponse{Frames: data.Frames{
+						data.NewFrame("a",
+							data.NewField("time", nil, []time.Time{time.Unix(60, 0), time.Unix(0, 0)}),
+							data.NewField("value", nil, []float64{2, 1}),
+						),
+					}},
+				}
+				return &backend.QueryDataResponse{Responses: resp}, nil
+			},
+		},
+	)
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.queryDataService = qds
+		hs.QuotaService = quotatest.New(false, nil)
+	})
+
+	req := server.NewPostRequest("/api/ds/query/federated", strings.NewReader(reqValid))
+	webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+	resp, err := server.SendJSON(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result dtos.FederatedMetricResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.NoError(t, resp.Body.Close())
+	require.Empty(t, result.Errors)
+	require.Len(t, result.Frame.Fields, 2)
+	require.True(t, time.Unix(0, 0).Equal(result.Frame.Fields[0].At(0).(time.Time)))
+	v, ok := result.Frame.Fields[1].ConcreteAt(1)
+	require.True(t, ok)
+	require.Equal(t, 2.0, v)
+}
+
 func TestAPIEndpoint_Metrics_PluginDecryptionFailure(t *testing.T) {
 	qds := query.ProvideService(
 		setting.NewCfg(),
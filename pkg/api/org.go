@@ -142,7 +142,7 @@ func (hs *HTTPServer) CreateOrg(c *contextmodel.ReqContext) response.Response {
 		if errors.Is(err, org.ErrOrgNameTaken) {
 			return response.Error(http.StatusConflict, "Organization name taken", err)
 		}
-		return response.Error(http.StatusInternalServerError, "Failed to create organization", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create organization", err)
 	}
 
 	metrics.MApiOrgCreate.Inc()
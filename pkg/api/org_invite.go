@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/inbox"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -152,7 +153,20 @@ func (hs *HTTPServer) inviteExistingUserToOrg(c *contextmodel.ReqContext, user *
 		if errors.Is(err, org.ErrOrgUserAlreadyAdded) {
 			return response.Error(412, fmt.Sprintf("User %s is already added to organization", inviteDto.LoginOrEmail), err)
 		}
-		return response.Error(500, "Error while trying to create org user", err)
+		return response.ErrOrFallback(500, "Error while trying to create org user", err)
+	}
+
+	// the user is already added to the org, so failing to notify the user doesn't fail the invite
+	notifyCmd := inbox.NotifyCommand{
+		OrgID:   c.OrgID,
+		UserIDs: []int64{user.ID},
+		Kind:    inbox.KindShareInvitation,
+		Title:   fmt.Sprintf("You were added to the organization %s", c.OrgName),
+		Body:    fmt.Sprintf("%s added you to the organization %s as %s.", util.StringsFallback3(c.Name, c.Email, c.Login), c.OrgName, inviteDto.Role),
+		URL:     fmt.Sprintf("/?orgId=%d", c.OrgID),
+	}
+	if err := hs.inboxService.Notify(c.Req.Context(), &notifyCmd); err != nil {
+		hs.log.Warn("Failed to notify the user added to the org", "userId", user.ID, "orgId", c.OrgID, "error", err)
 	}
 
 	if inviteDto.SendEmail && util.IsEmail(user.Email) {
@@ -285,7 +299,7 @@ func (hs *HTTPServer) CompleteInvite(c *contextmodel.ReqContext) response.Respon
 		return rsp
 	}
 
-	err = hs.loginUserWithUser(usr, c)
+	err = hs.loginUserWithUser(usr, c, "")
 	if err != nil {
 		return response.Error(500, "failed to accept invite", err)
 	}
@@ -314,7 +328,7 @@ func (hs *HTTPServer) applyUserInvite(ctx context.Context, usr *user.User, invit
 	addOrgUserCmd := org.AddOrgUserCommand{OrgID: invite.OrgID, UserID: usr.ID, Role: invite.Role}
 	if err := hs.orgService.AddOrgUser(ctx, &addOrgUserCmd); err != nil {
 		if !errors.Is(err, org.ErrOrgUserAlreadyAdded) {
-			return false, response.Error(500, "Error while trying to create org user", err)
+			return false, response.ErrOrFallback(500, "Error while trying to create org user", err)
 		}
 	}
 
@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/inbox/inboxtest"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
@@ -59,12 +61,14 @@ func TestOrgInvitesAPIEndpoint_RBAC(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
+			inboxService := inboxtest.NewFakeService()
 			server := SetupAPITestServer(t, func(hs *HTTPServer) {
 				hs.Cfg = setting.NewCfg()
 				hs.orgService = orgtest.NewOrgServiceFake()
 				hs.userService = &usertest.FakeUserService{
 					ExpectedUser: &user.User{ID: 1},
 				}
+				hs.inboxService = inboxService
 			})
 
 			req := webtest.RequestWithSignedInUser(server.NewPostRequest("/api/org/invites", strings.NewReader(tt.body)), userWithPermissions(1, tt.permissions))
@@ -72,6 +76,15 @@ func TestOrgInvitesAPIEndpoint_RBAC(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedCode, res.StatusCode)
 			require.NoError(t, res.Body.Close())
+
+			// the existing users added to the org are notified
+			if tt.expectedCode == http.StatusOK {
+				require.Len(t, inboxService.Notified, 1)
+				assert.Equal(t, inbox.KindShareInvitation, inboxService.Notified[0].Kind)
+				assert.Equal(t, []int64{1}, inboxService.Notified[0].UserIDs)
+			} else {
+				assert.Empty(t, inboxService.Notified)
+			}
 		})
 	}
 }
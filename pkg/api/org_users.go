@@ -92,7 +92,7 @@ func (hs *HTTPServer) addOrgUserHelper(c *contextmodel.ReqContext, cmd org.AddOr
 				"userId":  cmd.UserID,
 			})
 		}
-		return response.Error(500, "Could not add user to organization", err)
+		return response.ErrOrFallback(500, "Could not add user to organization", err)
 	}
 
 	return response.JSON(http.StatusOK, util.DynMap{
@@ -390,7 +390,7 @@ func (hs *HTTPServer) updateOrgUserHelper(c *contextmodel.ReqContext, cmd org.Up
 		if errors.Is(err, org.ErrLastOrgAdmin) {
 			return response.Error(http.StatusBadRequest, "Cannot change role so that there is no organization admin left", nil)
 		}
-		return response.Error(http.StatusInternalServerError, "Failed update org user", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed update org user", err)
 	}
 
 	if !hs.accesscontrolService.IsDisabled() {
@@ -20,6 +20,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/inbox/inboxtest"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
@@ -636,6 +637,7 @@ func TestOrgUsersAPIEndpointWithSetPerms_AccessControl(t *testing.T) {
 					ExpectedSignedInUser: userWithPermissions(1, tt.permissions),
 				}
 				hs.accesscontrolService = &actest.FakeService{}
+				hs.inboxService = inboxtest.NewFakeService()
 			})
 
 			u := userWithPermissions(1, tt.permissions)
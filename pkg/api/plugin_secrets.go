@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /plugins/{plugin_id}/secrets plugins listPluginSecrets
+//
+// List the names of the secrets of a plugin in the current organization.
+//
+// You need to have a permission with action `plugins.secrets:read` and scope `plugins:id:<plugin id>`.
+//
+// Responses:
+// 200: listPluginSecretsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) ListPluginSecrets(c *contextmodel.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+	if _, exists := hs.pluginStore.Plugin(c.Req.Context(), pluginID); !exists {
+		return response.Error(http.StatusNotFound, "Plugin not found", nil)
+	}
+
+	names, err := hs.pluginSecretsService.ListSecrets(c.Req.Context(), c.OrgID, pluginID)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, names)
+}
+
+// swagger:route GET /plugins/{plugin_id}/secrets/{name} plugins getPluginSecret
+//
+// Get the decrypted value of a secret of a plugin in the current organization.
+//
+// You need to have a permission with action `plugins.secrets:read` and scope `plugins:id:<plugin id>`.
+//
+// Responses:
+// 200: getPluginSecretResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetPluginSecret(c *contextmodel.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+	name := web.Params(c.Req)[":name"]
+	if _, exists := hs.pluginStore.Plugin(c.Req.Context(), pluginID); !exists {
+		return response.Error(http.StatusNotFound, "Plugin not found", nil)
+	}
+
+	value, err := hs.pluginSecretsService.GetSecret(c.Req.Context(), c.OrgID, pluginID, name)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, dtos.PluginSecret{Name: name, Value: value})
+}
+
+// swagger:route PUT /plugins/{plugin_id}/secrets/{name} plugins setPluginSecret
+//
+// Create or update a secret of a plugin in the current organization.
+//
+// You need to have a permission with action `plugins.secrets:write` and scope `plugins:id:<plugin id>`.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SetPluginSecret(c *contextmodel.ReqContext) response.Response {
+	cmd := dtos.SetPluginSecretCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	pluginID := web.Params(c.Req)[":pluginId"]
+	name := web.Params(c.Req)[":name"]
+	if _, exists := hs.pluginStore.Plugin(c.Req.Context(), pluginID); !exists {
+		return response.Error(http.StatusNotFound, "Plugin not found", nil)
+	}
+
+	if err := hs.pluginSecretsService.SetSecret(c.Req.Context(), c.OrgID, pluginID, name, cmd.Value); err != nil {
+		return response.Err(err)
+	}
+	return response.Success("Plugin secret saved")
+}
+
+// swagger:route DELETE /plugins/{plugin_id}/secrets/{name} plugins deletePluginSecret
+//
+// Delete a secret of a plugin in the current organization.
+//
+// You need to have a permission with action `plugins.secrets:write` and scope `plugins:id:<plugin id>`.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeletePluginSecret(c *contextmodel.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+	name := web.Params(c.Req)[":name"]
+	if _, exists := hs.pluginStore.Plugin(c.Req.Context(), pluginID); !exists {
+		return response.Error(http.StatusNotFound, "Plugin not found", nil)
+	}
+
+	if err := hs.pluginSecretsService.DeleteSecret(c.Req.Context(), c.OrgID, pluginID, name); err != nil {
+		return response.Err(err)
+	}
+	return response.Success("Plugin secret deleted")
+}
+
+// swagger:parameters listPluginSecrets
+type ListPluginSecretsParams struct {
+	// in:path
+	// required:true
+	PluginID string `json:"plugin_id"`
+}
+
+// swagger:parameters getPluginSecret deletePluginSecret
+type PluginSecretParams struct {
+	// in:path
+	// required:true
+	PluginID string `json:"plugin_id"`
+	// in:path
+	// required:true
+	Name string `json:"name"`
+}
+
+// swagger:parameters setPluginSecret
+type SetPluginSecretParams struct {
+	// in:path
+	// required:true
+	PluginID string `json:"plugin_id"`
+	// in:path
+	// required:true
+	Name string `json:"name"`
+	// in:body
+	// required:true
+	Body dtos.SetPluginSecretCommand `json:"body"`
+}
+
+// swagger:response listPluginSecretsResponse
+type ListPluginSecretsResponse struct {
+	// in: body
+	Body []string `json:"body"`
+}
+
+// swagger:response getPluginSecretResponse
+type GetPluginSecretResponse struct {
+	// in: body
+	Body dtos.PluginSecret `json:"body"`
+}
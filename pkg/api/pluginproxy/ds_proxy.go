@@ -21,6 +21,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/proxyutil"
@@ -37,6 +38,7 @@ type DataSourceProxy struct {
 	targetUrl          *url.URL
 	proxyPath          string
 	matchedRoute       *plugins.Route
+	routeResponse      *routeResponse
 	pluginRoutes       []*plugins.Route
 	cfg                *setting.Cfg
 	clientProvider     httpclient.Provider
@@ -125,6 +127,22 @@ func (proxy *DataSourceProxy) HandleRequest() {
 		return nil
 	}
 
+	// the responses are cached per data source, and per user when the requests carry the identity of the user
+	keyParts := []interface{}{"datasource", proxy.ds.OrgID, proxy.ds.UID, proxy.ds.Updated.Unix()}
+	if proxy.forwardsIdentity() {
+		keyParts = append(keyParts, userCacheKey(proxy.ctx.SignedInUser))
+	}
+	proxy.routeResponse = newRouteResponse(proxy.matchedRoute, proxy.ctx.Req, keyParts...)
+	if proxy.routeResponse != nil {
+		dsModifyResponse := modifyResponse
+		modifyResponse = func(resp *http.Response) error {
+			if err := dsModifyResponse(resp); err != nil {
+				return err
+			}
+			return proxy.routeResponse.modifyResponse(resp)
+		}
+	}
+
 	reverseProxy := proxyutil.NewReverseProxy(
 		proxyErrorLogger,
 		proxy.director,
@@ -133,6 +151,9 @@ func (proxy *DataSourceProxy) HandleRequest() {
 	)
 
 	proxy.logRequest()
+	if proxy.routeResponse != nil && proxy.routeResponse.serveCached(proxy.ctx.Resp) {
+		return
+	}
 	ctx, span := proxy.tracer.Start(proxy.ctx.Req.Context(), "datasource reverse proxy")
 	defer span.End()
 
@@ -151,6 +172,14 @@ func (proxy *DataSourceProxy) HandleRequest() {
 	reverseProxy.ServeHTTP(proxy.ctx.Resp, proxy.ctx.Req)
 }
 
+// forwardsIdentity returns true when the requests sent to the data source carry the identity of the user: the
+// user header, the OAuth tokens of the user, the cookies allowed by the data source or the credentials sent by
+// the client in the X-DS-Authorization header.
+func (proxy *DataSourceProxy) forwardsIdentity() bool {
+	return proxy.cfg.SendUserHeader || proxy.oAuthTokenService.IsOAuthPassThruEnabled(proxy.ds) ||
+		len(proxy.ds.AllowedCookies()) > 0 || proxy.ctx.Req.Header.Get("X-DS-Authorization") != ""
+}
+
 func (proxy *DataSourceProxy) addTraceFromHeaderValue(span tracing.Span, headerName string, tagName string) {
 	panelId := proxy.ctx.Req.Header.Get(headerName)
 	dashId, err := strconv.Atoi(panelId)
@@ -237,7 +266,7 @@ func (proxy *DataSourceProxy) director(req *http.Request) {
 	}
 
 	if proxy.matchedRoute != nil {
-		decryptedValues, err := proxy.dataSourcesService.DecryptedValues(req.Context(), proxy.ds)
+		decryptedValues, err := proxy.dataSourcesService.DecryptedValues(secrets.WithConsumer(req.Context(), "datasource-proxy"), proxy.ds)
 		if err != nil {
 			ctxLogger.Error("Error interpolating proxy url", "error", err)
 			return
@@ -251,6 +280,10 @@ func (proxy *DataSourceProxy) director(req *http.Request) {
 		}, proxy.cfg)
 	}
 
+	if proxy.routeResponse != nil {
+		proxy.routeResponse.prepareRequest(req)
+	}
+
 	if proxy.oAuthTokenService.IsOAuthPassThruEnabled(proxy.ds) {
 		if token := proxy.oAuthTokenService.GetCurrentOAuthToken(req.Context(), proxy.ctx.SignedInUser); token != nil {
 			req.Header.Set("Authorization", fmt.Sprintf("%s %s", token.Type(), token.AccessToken))
@@ -1113,6 +1113,27 @@ func Test_PathCheck(t *testing.T) {
 	require.Equal(t, routes[1], proxy.matchedRoute)
 }
 
+func TestDataSourceProxy_forwardsIdentity(t *testing.T) {
+	newProxy := func(cfg *setting.Cfg, jsonData map[string]interface{}, oAuthEnabled bool, header http.Header) *DataSourceProxy {
+		req := httptest.NewRequest(http.MethodGet, "/api/datasources/proxy/uid/ds/api", nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		return &DataSourceProxy{
+			ds:                &datasources.DataSource{UID: "ds", JsonData: simplejson.NewFromAny(jsonData)},
+			ctx:               &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{}},
+			cfg:               cfg,
+			oAuthTokenService: &mockOAuthTokenService{oAuthEnabled: oAuthEnabled},
+		}
+	}
+
+	require.False(t, newProxy(&setting.Cfg{}, nil, false, nil).forwardsIdentity())
+	require.True(t, newProxy(&setting.Cfg{SendUserHeader: true}, nil, false, nil).forwardsIdentity())
+	require.True(t, newProxy(&setting.Cfg{}, nil, true, nil).forwardsIdentity())
+	require.True(t, newProxy(&setting.Cfg{}, map[string]interface{}{"keepCookies": []interface{}{"session"}}, false, nil).forwardsIdentity())
+	require.True(t, newProxy(&setting.Cfg{}, nil, false, http.Header{"X-Ds-Authorization": {"Bearer token"}}).forwardsIdentity())
+}
+
 type mockOAuthTokenService struct {
 	token        *oauth2.Token
 	oAuthEnabled bool
@@ -25,6 +25,7 @@ type PluginProxy struct {
 	ctx            *contextmodel.ReqContext
 	proxyPath      string
 	matchedRoute   *plugins.Route
+	routeResponse  *routeResponse
 	cfg            *setting.Cfg
 	secretsService secrets.Service
 	tracer         tracing.Tracer
@@ -94,13 +95,24 @@ func (proxy *PluginProxy) HandleRequest() {
 		"referer", proxy.ctx.Req.Referer(),
 	)
 
+	// the requests carry the signed in user in the X-Grafana-Context header, the responses are cached per user
+	keyParts := []interface{}{"app", proxy.ps.PluginID, proxy.ps.OrgID, proxy.ps.Updated.Unix(), userCacheKey(proxy.ctx.SignedInUser)}
+	proxy.routeResponse = newRouteResponse(proxy.matchedRoute, proxy.ctx.Req, keyParts...)
+
+	opts := []proxyutil.ReverseProxyOption{proxyutil.WithTransport(proxy.transport)}
+	if proxy.routeResponse != nil {
+		opts = append(opts, proxyutil.WithModifyResponse(proxy.routeResponse.modifyResponse))
+	}
 	reverseProxy := proxyutil.NewReverseProxy(
 		proxyErrorLogger,
 		proxy.director,
-		proxyutil.WithTransport(proxy.transport),
+		opts...,
 	)
 
 	proxy.logRequest()
+	if proxy.routeResponse != nil && proxy.routeResponse.serveCached(proxy.ctx.Resp) {
+		return
+	}
 	ctx, span := proxy.tracer.Start(proxy.ctx.Req.Context(), "plugin reverse proxy")
 	defer span.End()
 
@@ -164,6 +176,10 @@ func (proxy PluginProxy) director(req *http.Request) {
 	if err := setBodyContent(req, proxy.matchedRoute, data); err != nil {
 		logger.FromContext(req.Context()).Error("Failed to set plugin route body content", "error", err)
 	}
+
+	if proxy.routeResponse != nil {
+		proxy.routeResponse.prepareRequest(req)
+	}
 }
 
 func (proxy PluginProxy) logRequest() {
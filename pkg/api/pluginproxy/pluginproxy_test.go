@@ -438,3 +438,78 @@ func getPluginProxiedRequest(t *testing.T, ps *pluginsettings.DTO, secretsServic
 	proxy.director(req)
 	return req
 }
+
+func TestPluginProxyRouteResponse(t *testing.T) {
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+	requests := 0
+	backendServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Secret", "123")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"name":"app","token":"abc","items":[{"id":1,"secret":"s1"},{"id":2,"secret":"s2"}]}`))
+	}))
+	t.Cleanup(backendServer.Close)
+
+	routes := []*plugins.Route{
+		{
+			Path: "api",
+			URL:  backendServer.URL,
+			Response: &plugins.RouteResponse{
+				CacheTTL:     "1m",
+				StripHeaders: []string{"X-Secret"},
+				RedactFields: []string{"token", "items.secret"},
+			},
+		},
+	}
+	ps := &pluginsettings.DTO{
+		PluginID:       "test-app",
+		OrgID:          1,
+		SecureJSONData: map[string][]byte{},
+	}
+
+	proxyRequest := func(t *testing.T) *httptest.ResponseRecorder {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		ctx := &contextmodel.ReqContext{
+			SignedInUser: &user.SignedInUser{OrgID: 1},
+			Context: &web.Context{
+				Req:  httptest.NewRequest("GET", "/api/plugins/test-app/resources/api", nil),
+				Resp: web.NewResponseWriter("GET", recorder),
+			},
+		}
+		proxy, err := NewPluginProxy(ps, routes, ctx, "api", &setting.Cfg{}, secretsService, tracing.InitializeTracerForTest(), &http.Transport{})
+		require.NoError(t, err)
+		proxy.HandleRequest()
+		return recorder
+	}
+
+	t.Run("When proxying a request should transform and cache the response", func(t *testing.T) {
+		resp := proxyRequest(t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, "MISS", resp.Header().Get("X-Cache"))
+		require.Empty(t, resp.Header().Get("X-Secret"))
+		require.JSONEq(t, `{"name":"app","items":[{"id":1},{"id":2}]}`, resp.Body.String())
+		require.Equal(t, 1, requests)
+	})
+
+	t.Run("When proxying the same request again should serve the cached response", func(t *testing.T) {
+		resp := proxyRequest(t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, "HIT", resp.Header().Get("X-Cache"))
+		require.Empty(t, resp.Header().Get("X-Secret"))
+		require.JSONEq(t, `{"name":"app","items":[{"id":1},{"id":2}]}`, resp.Body.String())
+		require.Equal(t, 1, requests)
+	})
+}
+
+func TestRedactJSONFields(t *testing.T) {
+	body := []byte(`{"a":{"b":1,"c":2},"list":[{"key":"x","value":1}],"big":12345678901234567890}`)
+
+	redacted, err := redactJSONFields(body, []string{"a.b", "list.*", "missing.field"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"a":{"c":2},"list":[{}],"big":12345678901234567890}`, string(redacted))
+
+	_, err = redactJSONFields([]byte("not json"), []string{"a"})
+	require.Error(t, err)
+}
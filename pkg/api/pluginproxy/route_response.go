@@ -0,0 +1,200 @@
+package pluginproxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/proxyutil"
+)
+
+// maxRouteResponseSize is the size of the largest response body that is cached or redacted.
+const maxRouteResponseSize = 10 * 1024 * 1024
+
+// routeResponseCache keeps the responses of the plugin routes with a cache TTL, for all the plugins.
+var routeResponseCache = localcache.New(5*time.Minute, 10*time.Minute)
+
+var errRouteResponseTooLarge = errors.New("plugin route response too large to redact")
+
+type cachedRouteResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// routeResponse caches and transforms the responses of a plugin route, as configured in plugin.json.
+type routeResponse struct {
+	settings *plugins.RouteResponse
+	cacheKey string
+	cacheTTL time.Duration
+}
+
+// userCacheKey identifies the user of a request in the keys of the cached responses, including the API keys
+// that are not users.
+func userCacheKey(u *user.SignedInUser) string {
+	if key, err := u.GetCacheKey(); err == nil {
+		return key
+	}
+	return "anonymous"
+}
+
+// newRouteResponse returns nil when the route doesn't configure its responses. The responses of the GET requests
+// are cached by the key parts, the method and the proxied URL.
+func newRouteResponse(route *plugins.Route, req *http.Request, keyParts ...interface{}) *routeResponse {
+	if route == nil || route.Response == nil {
+		return nil
+	}
+
+	r := &routeResponse{settings: route.Response}
+	if route.Response.CacheTTL != "" && req.Method == http.MethodGet {
+		ttl, err := time.ParseDuration(route.Response.CacheTTL)
+		if err != nil || ttl <= 0 {
+			logger.Warn("Invalid cache TTL of plugin route", "path", route.Path, "cacheTTL", route.Response.CacheTTL)
+			return r
+		}
+		r.cacheTTL = ttl
+		r.cacheKey = fmt.Sprintf("plugin-route-response-%v-%s-%s", keyParts, req.Method, req.URL.RequestURI())
+	}
+	return r
+}
+
+// prepareRequest asks for a response without content encoding when it is read to be cached or redacted.
+func (r *routeResponse) prepareRequest(req *http.Request) {
+	if r.cacheKey != "" || len(r.settings.RedactFields) > 0 {
+		req.Header.Del("Accept-Encoding")
+	}
+}
+
+// serveCached writes the cached response, if any.
+func (r *routeResponse) serveCached(w http.ResponseWriter) bool {
+	if r.cacheKey == "" {
+		return false
+	}
+	cached, ok := routeResponseCache.Get(r.cacheKey)
+	if !ok {
+		return false
+	}
+
+	resp := cached.(cachedRouteResponse)
+	for name, values := range resp.header {
+		w.Header()[name] = values
+	}
+	proxyutil.SetProxyResponseHeaders(w.Header())
+	w.Header().Set("X-Cache", "HIT")
+	w.WriteHeader(resp.statusCode)
+	if _, err := w.Write(resp.body); err != nil {
+		logger.Debug("Failed to write cached plugin route response", "error", err)
+	}
+	return true
+}
+
+// modifyResponse strips the headers and redacts the JSON fields of the response, then caches it.
+func (r *routeResponse) modifyResponse(resp *http.Response) error {
+	for _, name := range r.settings.StripHeaders {
+		resp.Header.Del(name)
+	}
+
+	redact := len(r.settings.RedactFields) > 0 && isJSONResponse(resp)
+	cache := r.cacheKey != "" && resp.StatusCode == http.StatusOK && cacheableResponse(resp.Header)
+	if !redact && !cache {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRouteResponseSize+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > maxRouteResponseSize {
+		if redact {
+			return errRouteResponseTooLarge
+		}
+		// too large to be cached, the response is returned as is
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return nil
+	}
+	_ = resp.Body.Close()
+
+	if redact {
+		if body, err = redactJSONFields(body, r.settings.RedactFields); err != nil {
+			return fmt.Errorf("failed to redact plugin route response: %w", err)
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	if cache {
+		routeResponseCache.Set(r.cacheKey, cachedRouteResponse{
+			statusCode: resp.StatusCode,
+			header:     resp.Header.Clone(),
+			body:       body,
+		}, r.cacheTTL)
+		resp.Header.Set("X-Cache", "MISS")
+	}
+	return nil
+}
+
+func isJSONResponse(resp *http.Response) bool {
+	if resp.Header.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// cacheableResponse reports whether the upstream allows sharing the response between the users.
+func cacheableResponse(header http.Header) bool {
+	if header.Get("Set-Cookie") != "" {
+		return false
+	}
+	cacheControl := strings.ToLower(header.Get("Cache-Control"))
+	return !strings.Contains(cacheControl, "no-store") && !strings.Contains(cacheControl, "private")
+}
+
+// redactJSONFields removes the fields at the dot-separated paths from the JSON document.
+func redactJSONFields(body []byte, paths []string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		redactJSONPath(doc, strings.Split(path, "."))
+	}
+	return json.Marshal(doc)
+}
+
+func redactJSONPath(value interface{}, path []string) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			redactJSONPath(item, path)
+		}
+	case map[string]interface{}:
+		for key, child := range v {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			if len(path) == 1 {
+				delete(v, key)
+			} else {
+				redactJSONPath(child, path[1:])
+			}
+		}
+	}
+}
@@ -452,6 +452,56 @@ func (hs *HTTPServer) CheckHealth(c *contextmodel.ReqContext) response.Response
 	return response.JSON(http.StatusOK, payload)
 }
 
+// GetPluginHealthDetails returns the health of a plugin along with the status of its backend process
+func (hs *HTTPServer) GetPluginHealthDetails(c *contextmodel.ReqContext) response.Response {
+	pluginID := web.Params(c.Req)[":pluginId"]
+
+	plugin, exists := hs.pluginStore.Plugin(c.Req.Context(), pluginID)
+	if !exists {
+		return response.Error(http.StatusNotFound, "Plugin not found", nil)
+	}
+
+	payload := map[string]interface{}{
+		"pluginId": pluginID,
+		"backend":  plugin.Backend,
+	}
+	if !plugin.Backend {
+		return response.JSON(http.StatusOK, payload)
+	}
+
+	if status, exists := hs.pluginProcessManager.Status(c.Req.Context(), pluginID); exists {
+		payload["process"] = status
+	}
+
+	pCtx, found, err := hs.PluginContextProvider.Get(c.Req.Context(), pluginID, c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get plugin settings", err)
+	}
+	if !found {
+		return response.Error(http.StatusNotFound, "Plugin not found", nil)
+	}
+
+	health := map[string]interface{}{}
+	resp, err := hs.pluginClient.CheckHealth(c.Req.Context(), &backend.CheckHealthRequest{
+		PluginContext: pCtx,
+		Headers:       map[string]string{},
+	})
+	switch {
+	case errors.Is(err, backendplugin.ErrMethodNotImplemented):
+		health["status"] = backend.HealthStatusUnknown.String()
+		health["message"] = "Plugin does not implement health checks"
+	case err != nil:
+		health["status"] = backend.HealthStatusError.String()
+		health["message"] = err.Error()
+	default:
+		health["status"] = resp.Status.String()
+		health["message"] = resp.Message
+	}
+	payload["health"] = health
+
+	return response.JSON(http.StatusOK, payload)
+}
+
 func (hs *HTTPServer) GetPluginErrorsList(_ *contextmodel.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, hs.pluginErrorResolver.PluginErrors())
 }
@@ -488,10 +538,18 @@ func (hs *HTTPServer) InstallPlugin(c *contextmodel.ReqContext) response.Respons
 		if errors.Is(err, plugins.ErrInstallCorePlugin) {
 			return response.Error(http.StatusForbidden, "Cannot install or change a Core plugin", err)
 		}
+		if errors.Is(err, plugins.ErrInstallPluginNotLoaded) {
+			return response.Error(http.StatusBadRequest, "Plugin could not be loaded, its signature may be missing or invalid", err)
+		}
 
 		return response.Error(http.StatusInternalServerError, "Failed to install plugin", err)
 	}
 
+	// the plugin is already running, a failed sync of its app dashboards is retried on the next start
+	if err := hs.bus.Publish(c.Req.Context(), &pluginsettings.PluginInstalledEvent{PluginId: pluginID}); err != nil {
+		hs.log.Warn("Failed to sync the catalog of the installed plugin", "pluginId", pluginID, "error", err)
+	}
+
 	return response.JSON(http.StatusOK, []byte{})
 }
 
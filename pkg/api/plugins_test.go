@@ -19,15 +19,23 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/config"
+	"github.com/grafana/grafana/pkg/plugins/manager/client/clienttest"
+	"github.com/grafana/grafana/pkg/plugins/manager/fakes"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/pluginscdn"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	datasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
+	pluginSettings "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings/service"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/updatechecker"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -597,6 +605,79 @@ func Test_PluginsList_AccessControl(t *testing.T) {
 	}
 }
 
+func Test_GetPluginHealthDetails(t *testing.T) {
+	backendPlugin := createPluginDTO(plugins.JSONData{ID: "test-datasource", Type: "datasource", Backend: true},
+		plugins.External, plugins.NewLocalFS(map[string]struct{}{}, ""))
+	panelPlugin := createPluginDTO(plugins.JSONData{ID: "test-panel", Type: "panel"},
+		plugins.External, plugins.NewLocalFS(map[string]struct{}{}, ""))
+	pluginStore := plugins.FakePluginStore{PluginList: []plugins.PluginDTO{backendPlugin, panelPlugin}}
+
+	procMgr := fakes.NewFakeProcessManager()
+	procMgr.StatusFunc = func(_ context.Context, pluginID string) (process.ProcessStatus, bool) {
+		return process.ProcessStatus{Running: true, Pid: 42, Restarts: 2}, pluginID == "test-datasource"
+	}
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.pluginStore = pluginStore
+		hs.pluginProcessManager = procMgr
+		hs.PluginContextProvider = plugincontext.ProvideService(localcache.ProvideService(), pluginStore,
+			&datasources.FakeCacheService{}, &datasources.FakeDataSourceService{}, pluginSettings.ProvideService(db.InitTestDB(t), nil))
+		hs.pluginClient = &clienttest.TestClient{
+			CheckHealthFunc: func(_ context.Context, _ *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+				return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: "cannot reach the database"}, nil
+			},
+		}
+	})
+	admin := userWithPermissions(1, []ac.Permission{{Action: ac.ActionSettingsRead, Scope: ac.ScopeSettingsAll}})
+
+	t.Run("Returns the process status and health of a backend plugin", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/plugins/test-datasource/health/details"), admin))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var result struct {
+			Backend bool                  `json:"backend"`
+			Process process.ProcessStatus `json:"process"`
+			Health  map[string]string     `json:"health"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		require.NoError(t, res.Body.Close())
+		require.True(t, result.Backend)
+		require.Equal(t, 42, result.Process.Pid)
+		require.Equal(t, 2, result.Process.Restarts)
+		require.Equal(t, "ERROR", result.Health["status"])
+		require.Equal(t, "cannot reach the database", result.Health["message"])
+	})
+
+	t.Run("Returns no process for a frontend plugin", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/plugins/test-panel/health/details"), admin))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, false, result["backend"])
+		require.NotContains(t, result, "process")
+		require.NotContains(t, result, "health")
+	})
+
+	t.Run("Returns 404 for an unknown plugin", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/plugins/unknown/health/details"), admin))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Requires the permission to install plugins", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/plugins/test-datasource/health/details"), userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func createPluginDTO(jd plugins.JSONData, class plugins.Class, files plugins.FS) plugins.PluginDTO {
 	p := &plugins.Plugin{
 		JSONData: jd,
@@ -160,6 +160,96 @@ func (hs *HTTPServer) UpdateUserQuota(c *contextmodel.ReqContext) response.Respo
 	return response.Success("Organization quota updated")
 }
 
+// swagger:route GET /admin/quotas admin getQuotaTargets
+//
+// Fetch the registered quota targets.
+//
+// Lists the quota targets registered by Grafana services and their default limits.
+// Only works with Basic Authentication (username and password). See introduction for an explanation.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: getQuotaTargetsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetQuotaTargets(c *contextmodel.ReqContext) response.Response {
+	targets, err := hs.QuotaService.GetTargets(c.Req.Context())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get quota targets", err)
+	}
+	return response.JSON(http.StatusOK, targets)
+}
+
+// swagger:route GET /admin/quotas/global admin getGlobalQuotas
+//
+// Fetch global quota.
+//
+// Only works with Basic Authentication (username and password). See introduction for an explanation.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: getQuotaResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetGlobalQuotas(c *contextmodel.ReqContext) response.Response {
+	q, err := hs.QuotaService.GetQuotasByScope(c.Req.Context(), quota.GlobalScope, 0)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get global quotas", err)
+	}
+	return response.JSON(http.StatusOK, q)
+}
+
+// swagger:route PUT /admin/quotas/global/{quota_target} admin updateGlobalQuota
+//
+// Update global quota.
+//
+// Overrides the default limit of a global quota target.
+// Only works with Basic Authentication (username and password). See introduction for an explanation.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) UpdateGlobalQuota(c *contextmodel.ReqContext) response.Response {
+	cmd := quota.UpdateQuotaCmd{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Err(quota.ErrBadRequest.Errorf("bad request data: %w", err))
+	}
+	cmd.Target = web.Params(c.Req)[":target"]
+
+	if err := hs.QuotaService.Update(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update global quotas", err)
+	}
+	return response.Success("Global quota updated")
+}
+
+// swagger:parameters updateGlobalQuota
+type UpdateGlobalQuotaParams struct {
+	// in:body
+	// required:true
+	Body quota.UpdateQuotaCmd `json:"body"`
+	// in:path
+	// required:true
+	QuotaTarget string `json:"quota_target"`
+}
+
+// swagger:response getQuotaTargetsResponse
+type GetQuotaTargetsResponse struct {
+	// in:body
+	Body []quota.TargetDTO `json:"body"`
+}
+
 // swagger:parameters updateUserQuota
 type UpdateUserQuotaParams struct {
 	// in:body
@@ -4,12 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/models"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -23,6 +31,24 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 
 	queryParams := fmt.Sprintf("?%s", c.Req.URL.RawQuery)
 
+	authOpts := rendering.AuthOpts{
+		OrgID:   c.OrgID,
+		UserID:  c.UserID,
+		OrgRole: c.OrgRole,
+	}
+	if query := c.Req.URL.Query(); rendering.IsSignedURL(query) {
+		scope, err := rendering.ValidateSignedURL(hs.Cfg.SecretKey, web.Params(c.Req)["*"], query, time.Now())
+		if err != nil {
+			c.WriteErr(err)
+			return
+		}
+		authOpts = scope.AuthOpts
+		queryParams = "?" + rendering.WithoutSignature(query).Encode()
+	} else if !c.IsSignedIn {
+		c.JsonApiErr(http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
 	width, err := strconv.Atoi(queryReader.Get("width", "800"))
 	if err != nil {
 		c.Handle(hs.Cfg, 400, "Render parameters error", fmt.Errorf("cannot parse width as int: %s", err))
@@ -57,11 +83,7 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 		TimeoutOpts: rendering.TimeoutOpts{
 			Timeout: time.Duration(timeout) * time.Second,
 		},
-		AuthOpts: rendering.AuthOpts{
-			OrgID:   c.OrgID,
-			UserID:  c.UserID,
-			OrgRole: c.OrgRole,
-		},
+		AuthOpts:          authOpts,
 		Width:             width,
 		Height:            height,
 		Path:              web.Params(c.Req)["*"] + queryParams,
@@ -85,3 +107,95 @@ func (hs *HTTPServer) RenderToPng(c *contextmodel.ReqContext) {
 	c.Resp.Header().Set("Content-Type", "image/png")
 	http.ServeFile(c.Resp, c.Req, result.FilePath)
 }
+
+// GetRenderedImage serves the rendered images from the object storage of the rendering artifacts, the images on
+// the local disk being served as static files.
+func (hs *HTTPServer) GetRenderedImage(c *contextmodel.ReqContext) response.Response {
+	store := hs.objectStorage.Store(objectstore.ArtifactRendering)
+	filename := web.Params(c.Req)[":filename"]
+	if store == nil || filepath.Ext(filename) != ".png" {
+		return response.Error(http.StatusNotFound, "Not found", nil)
+	}
+
+	data, err := store.Get(c.Req.Context(), filename)
+	if err != nil {
+		if errors.Is(err, objectstore.ErrObjectNotFound) {
+			return response.Error(http.StatusNotFound, "Not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get rendered image", err)
+	}
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "image/png")
+	headers.Set("Cache-Control", "public, max-age=3600")
+	return response.CreateNormalResponse(headers, data, http.StatusOK)
+}
+
+// swagger:model
+type SignRenderURLCommand struct {
+	// Render path of the dashboard or panel, with its query, for example d-solo/uid/slug?orgId=1&panelId=2&from=now-6h&to=now.
+	Path string `json:"path"`
+	// How long the URL is valid, for example 1h. Defaults to 1h, capped by the signed_url_max_expiration setting.
+	ExpiresIn string `json:"expiresIn"`
+}
+
+// swagger:route POST /render/sign render signRenderURL
+//
+// Create a signed render URL.
+//
+// Signs the render URL of a dashboard or panel on behalf of the signed in user, so that it can be rendered without an
+// API key until it expires. The dashboard, the panel and the time range of a signed URL cannot be changed.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+func (hs *HTTPServer) SignRenderURL(c *contextmodel.ReqContext) response.Response {
+	cmd := SignRenderURLCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	u, err := url.Parse(cmd.Path)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Invalid render path", err)
+	}
+	dashboardUID, ok := rendering.DashboardUIDFromPath(u.Path)
+	if !ok {
+		return response.Error(http.StatusBadRequest, "Only dashboard and panel render paths can be signed", nil)
+	}
+
+	expiresIn := time.Hour
+	if cmd.ExpiresIn != "" {
+		if expiresIn, err = gtime.ParseDuration(cmd.ExpiresIn); err != nil || expiresIn <= 0 {
+			return response.Error(http.StatusBadRequest, "Invalid expiresIn", err)
+		}
+	}
+	if expiresIn > hs.Cfg.RendererSignedURLMaxExpiration {
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("expiresIn cannot exceed %s", hs.Cfg.RendererSignedURLMaxExpiration), nil)
+	}
+
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+
+	expires := time.Now().Add(expiresIn)
+	signed, err := rendering.SignURL(hs.Cfg.SecretKey, u.Path, u.Query(), rendering.AuthOpts{
+		OrgID:   c.OrgID,
+		UserID:  c.UserID,
+		OrgRole: c.OrgRole,
+	}, expires)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"url":     setting.ToAbsUrl("render/" + signed),
+		"expires": expires.UTC(),
+	})
+}
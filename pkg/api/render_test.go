@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/objectstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_GetRenderedImage(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.ObjectStorage.Rendering = setting.ObjectStoreSettings{
+		Provider: objectstore.ProviderFile,
+		File:     setting.ObjectStoreFileSettings{Path: t.TempDir()},
+	}
+	objectStorage, err := objectstore.ProvideService(cfg)
+	require.NoError(t, err)
+	require.NoError(t, objectStorage.Store(objectstore.ArtifactRendering).Put(context.Background(), "rendered.png", []byte("png")))
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = cfg
+		hs.objectStorage = objectStorage
+	})
+
+	get := func(t *testing.T, path string) *http.Response {
+		t.Helper()
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest(path), &user.SignedInUser{}))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+
+	t.Run("should serve a rendered image from the object storage", func(t *testing.T) {
+		res := get(t, "/public/img/attachments/rendered.png")
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "image/png", res.Header.Get("Content-Type"))
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, []byte("png"), body)
+	})
+
+	t.Run("should return 404 for a missing image", func(t *testing.T) {
+		res := get(t, "/public/img/attachments/missing.png")
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should only serve images", func(t *testing.T) {
+		res := get(t, "/public/img/attachments/rendered.txt")
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// isRestrictedViewer returns whether the queries of the user are restricted to the queries saved in dashboard panels.
+func (hs *HTTPServer) isRestrictedViewer(c *contextmodel.ReqContext) bool {
+	return hs.Cfg.RestrictViewerQueries && !hs.Cfg.ViewersCanEdit && !c.IsGrafanaAdmin && !c.SignedInUser.HasRole(org.RoleEditor)
+}
+
+// denyRestrictedViewers rejects the data source proxy and resource requests of viewers when their queries are
+// restricted, these requests carry raw data source queries which can't be matched with the saved queries.
+func (hs *HTTPServer) denyRestrictedViewers(c *contextmodel.ReqContext) {
+	if hs.isRestrictedViewer(c) {
+		c.WriteErr(query.ErrRawRequestRestricted.Errorf("the queries of viewers are restricted to the saved queries"))
+	}
+}
+
+// restrictViewerQueries rejects the query requests of viewers that do not reference a dashboard panel they
+// can view, or with queries that are not saved in the panel, when the queries of viewers are restricted.
+func (hs *HTTPServer) restrictViewerQueries(c *contextmodel.ReqContext) {
+	if !hs.isRestrictedViewer(c) || c.Req.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(c.Req.Body)
+	_ = c.Req.Body.Close()
+	if err != nil {
+		c.JsonApiErr(http.StatusBadRequest, "Failed to read request body", err)
+		return
+	}
+	c.Req.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req struct {
+		Queries []*simplejson.Json `json:"queries"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		// invalid requests are reported by the handler
+		return
+	}
+
+	saved, err := hs.savedPanelQueries(c)
+	if err != nil {
+		c.WriteErr(err)
+		return
+	}
+	for _, q := range req.Queries {
+		if !saved.Contains(q, hs.savedQueryDataSource(c, q)) {
+			c.WriteErr(query.ErrQueryNotSaved.Build(errutil.TemplateData{
+				Public: map[string]any{"RefId": q.Get("refId").MustString()},
+			}))
+			return
+		}
+	}
+}
+
+// savedPanelQueries returns the saved queries of the dashboard panel referenced by the headers of the request.
+func (hs *HTTPServer) savedPanelQueries(c *contextmodel.ReqContext) (*query.SavedQueries, error) {
+	dashboardUID := c.Req.Header.Get(query.HeaderDashboardUID)
+	panelID, err := strconv.ParseInt(c.Req.Header.Get(query.HeaderPanelID), 10, 64)
+	if dashboardUID == "" || err != nil {
+		return nil, query.ErrSavedPanelRequired.Errorf("the request does not reference a dashboard panel")
+	}
+
+	dash, err := hs.DashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{UID: dashboardUID, OrgID: c.OrgID})
+	if err != nil {
+		return nil, query.ErrSavedPanelRequired.Errorf("failed to get dashboard %s: %w", dashboardUID, err)
+	}
+	g, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return nil, err
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return nil, query.ErrSavedPanelRequired.Errorf("the user cannot view dashboard %s", dashboardUID)
+	}
+
+	panel, ok := query.FindPanel(dash.Data, panelID)
+	if !ok {
+		return nil, query.ErrSavedPanelRequired.Errorf("dashboard %s has no panel %d", dashboardUID, panelID)
+	}
+	// the queries of a library panel are saved in the library panel, not in the dashboard
+	if uid := panel.Get("libraryPanel").Get("uid").MustString(); uid != "" {
+		element, err := hs.LibraryElementService.GetElement(c.Req.Context(), c.SignedInUser, uid)
+		if err != nil {
+			return nil, query.ErrSavedPanelRequired.Errorf("failed to get library panel %s: %w", uid, err)
+		}
+		if panel, err = simplejson.NewJson(element.Model); err != nil {
+			return nil, err
+		}
+	}
+	return query.NewSavedQueries(dash.Data, panel), nil
+}
+
+// savedQueryDataSource returns the data source a query of the request runs against, nil if the user can't
+// query it.
+func (hs *HTTPServer) savedQueryDataSource(c *contextmodel.ReqContext, q *simplejson.Json) *datasources.DataSource {
+	uid := q.Get("datasource").Get("uid").MustString()
+	if expr.IsDataSource(uid) {
+		return expr.DataSourceModel()
+	}
+	if uid == "" {
+		return nil
+	}
+	ds, err := hs.DataSourceCache.GetDatasourceByUID(c.Req.Context(), uid, c.SignedInUser, c.SkipCache)
+	if err != nil {
+		return nil
+	}
+	return ds
+}
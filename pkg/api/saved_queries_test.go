@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestIsRestrictedViewer(t *testing.T) {
+	tests := []struct {
+		desc           string
+		restrict       bool
+		viewersCanEdit bool
+		user           *user.SignedInUser
+		expected       bool
+	}{
+		{desc: "viewer with restricted queries", restrict: true, user: &user.SignedInUser{OrgRole: org.RoleViewer}, expected: true},
+		{desc: "viewer without restricted queries", user: &user.SignedInUser{OrgRole: org.RoleViewer}},
+		{desc: "viewer who can edit", restrict: true, viewersCanEdit: true, user: &user.SignedInUser{OrgRole: org.RoleViewer}},
+		{desc: "editor", restrict: true, user: &user.SignedInUser{OrgRole: org.RoleEditor}},
+		{desc: "server admin", restrict: true, user: &user.SignedInUser{OrgRole: org.RoleViewer, IsGrafanaAdmin: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			hs := &HTTPServer{Cfg: setting.NewCfg()}
+			hs.Cfg.RestrictViewerQueries = tt.restrict
+			hs.Cfg.ViewersCanEdit = tt.viewersCanEdit
+			assert.Equal(t, tt.expected, hs.isRestrictedViewer(&contextmodel.ReqContext{SignedInUser: tt.user}))
+		})
+	}
+}
+
+func TestDenyRestrictedViewers(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Cfg.RestrictViewerQueries = true
+	})
+	viewer := userWithPermissions(1, []ac.Permission{{Action: datasources.ActionQuery, Scope: datasources.ScopeAll}})
+
+	for _, path := range []string{
+		"/api/datasources/proxy/1/api/v1/query",
+		"/api/datasources/proxy/uid/abc/api/v1/query",
+		"/api/datasources/1/resources/labels",
+		"/api/datasources/uid/abc/resources/labels",
+	} {
+		t.Run(path, func(t *testing.T) {
+			res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest(path), viewer))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
@@ -3,12 +3,15 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/util"
@@ -22,6 +25,17 @@ import (
 // 422: unprocessableEntityError
 // 500: internalServerError
 func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
+	if c.QueryBool("watch") {
+		switch model.HitType(c.Query("type")) {
+		case model.DashHitDB:
+			return hs.watchDashboards(c, resourcewatch.KindDashboard)
+		case model.DashHitFolder:
+			return hs.watchDashboards(c, resourcewatch.KindFolder)
+		default:
+			return hs.watchDashboards(c, resourcewatch.KindDashboard, resourcewatch.KindFolder)
+		}
+	}
+
 	query := c.Query("query")
 	tags := c.QueryStrings("tag")
 	starred := c.Query("starred")
@@ -65,6 +79,26 @@ func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
 		return response.Error(400, "search supports UIDs or IDs, not both", nil)
 	}
 
+	// a saved search replaces the query and the filters of the request, the pagination is kept
+	if uid := c.Query("savedSearchUid"); uid != "" {
+		saved, errResp := hs.getSavedSearch(c, uid)
+		if errResp != nil {
+			return errResp
+		}
+		query = saved.Query
+		tags, dashboardType, folderIDs, sort = nil, "", nil, ""
+		starred = ""
+		if saved.Filters != nil {
+			tags = saved.Filters.Tags
+			dashboardType = saved.Filters.Type
+			folderIDs = saved.Filters.FolderIDs
+			sort = saved.Filters.Sort
+			if saved.Filters.Starred {
+				starred = "true"
+			}
+		}
+	}
+
 	searchQuery := search.Query{
 		Title:         query,
 		Tags:          tags,
@@ -88,6 +122,12 @@ func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
 
 	defer c.TimeRequest(metrics.MApiDashboardSearch)
 
+	if c.QueryBool("usage") && hs.usageInsightsService != nil {
+		if err := hs.addUsageToHits(c, searchQuery.Result); err != nil {
+			return response.Error(500, "Failed to get dashboard usage", err)
+		}
+	}
+
 	if !c.QueryBool("accesscontrol") {
 		return response.JSON(http.StatusOK, searchQuery.Result)
 	}
@@ -95,6 +135,52 @@ func (hs *HTTPServer) Search(c *contextmodel.ReqContext) response.Response {
 	return hs.searchHitsWithMetadata(c, searchQuery.Result)
 }
 
+func (hs *HTTPServer) getSavedSearch(c *contextmodel.ReqContext, uid string) (*savedsearch.SavedSearch, response.Response) {
+	if !hs.AccessControl.IsDisabled() {
+		hasAccess, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, accesscontrol.EvalPermission(savedsearch.ActionRead))
+		if err != nil {
+			return nil, response.Error(http.StatusInternalServerError, "Failed to evaluate permissions", err)
+		}
+		if !hasAccess {
+			return nil, response.Error(http.StatusForbidden, "Not allowed to run saved searches", nil)
+		}
+	}
+
+	saved, err := hs.savedSearchService.Get(c.Req.Context(), c.SignedInUser, uid)
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get saved search", err)
+	}
+	return saved, nil
+}
+
+func (hs *HTTPServer) addUsageToHits(c *contextmodel.ReqContext, hits model.HitList) error {
+	uids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		if hit.Type == model.DashHitDB {
+			uids = append(uids, hit.UID)
+		}
+	}
+
+	usage, err := hs.usageInsightsService.GetDashboardsUsageByUID(c.Req.Context(), c.OrgID, uids, time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		return err
+	}
+
+	for _, hit := range hits {
+		if hit.Type != model.DashHitDB {
+			continue
+		}
+		hit.Usage = &model.HitUsage{}
+		if u, ok := usage[hit.UID]; ok {
+			hit.Usage.Views = u.Views
+			hit.Usage.Queries = u.Queries
+			hit.Usage.Errors = u.Errors
+			hit.Usage.LastViewedAt = u.LastViewedAt
+		}
+	}
+	return nil
+}
+
 func (hs *HTTPServer) searchHitsWithMetadata(c *contextmodel.ReqContext, hits model.HitList) response.Response {
 	folderUIDs := make(map[string]bool)
 	dashboardUIDs := make(map[string]bool)
@@ -211,6 +297,10 @@ type SearchParams struct {
 	// default: alpha-asc
 	// Enum: alpha-asc,alpha-desc
 	Sort string `json:"sort"`
+	// Include the views, queries and errors of each dashboard over the last 30 days
+	// in:query
+	// required: false
+	Usage bool `json:"usage"`
 }
 
 // swagger:response searchResponse
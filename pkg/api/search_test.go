@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+	"github.com/grafana/grafana/pkg/services/savedsearch/savedsearchtest"
 	"github.com/grafana/grafana/pkg/services/search/model"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
@@ -99,3 +102,60 @@ func TestHTTPServer_Search(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPServer_SearchSavedSearch(t *testing.T) {
+	searchService := &mockSearchService{}
+	savedSearchService := savedsearchtest.NewFakeService()
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.SearchService = searchService
+		hs.savedSearchService = savedSearchService
+	})
+
+	t.Run("should run the saved search with the pagination of the request", func(t *testing.T) {
+		savedSearchService.ExpectedSavedSearch = &savedsearch.SavedSearch{
+			UID:     "abc",
+			Query:   "cpu",
+			Filters: &savedsearch.Filters{Tags: []string{"infra"}, Type: string(model.DashHitDB), Starred: true},
+		}
+		savedSearchService.ExpectedError = nil
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/search?savedSearchUid=abc&query=mem&tag=other&limit=10&page=2"),
+			userWithPermissions(1, []accesscontrol.Permission{{Action: savedsearch.ActionRead}}),
+		))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		q := searchService.LastQuery
+		require.Equal(t, "cpu", q.Title)
+		require.Equal(t, []string{"infra"}, q.Tags)
+		require.Equal(t, string(model.DashHitDB), q.Type)
+		require.True(t, q.IsStarred)
+		require.Equal(t, int64(10), q.Limit)
+		require.Equal(t, int64(2), q.Page)
+	})
+
+	t.Run("should return 404 for an unknown saved search", func(t *testing.T) {
+		savedSearchService.ExpectedError = savedsearch.ErrNotFound.Errorf("not found")
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/search?savedSearchUid=unknown"),
+			userWithPermissions(1, []accesscontrol.Permission{{Action: savedsearch.ActionRead}}),
+		))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should require the permission to read saved searches", func(t *testing.T) {
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewGetRequest("/api/search?savedSearchUid=abc"),
+			userWithPermissions(1, nil),
+		))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
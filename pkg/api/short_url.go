@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
@@ -20,8 +21,12 @@ func (hs *HTTPServer) createShortURL(c *contextmodel.ReqContext) response.Respon
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Err(shorturls.ErrShortURLBadRequest.Errorf("bad request data: %w", err))
 	}
-	hs.log.Debug("Received request to create short URL", "path", cmd.Path)
-	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, cmd.Path)
+	hs.log.Debug("Received request to create short URL", "path", cmd.Path, "slug", cmd.Slug)
+	shortURL, err := hs.ShortURLService.CreateShortURL(c.Req.Context(), c.SignedInUser, &shorturls.CreateShortURLCommand{
+		Path: cmd.Path,
+		Slug: cmd.Slug,
+		TTL:  time.Duration(cmd.Expires) * time.Second,
+	})
 	if err != nil {
 		return response.Err(err)
 	}
@@ -37,6 +42,20 @@ func (hs *HTTPServer) createShortURL(c *contextmodel.ReqContext) response.Respon
 	return response.JSON(http.StatusOK, dto)
 }
 
+// AdminSearchShortURLs lists the short URLs of all the organizations, with who created them and how often they were followed.
+func (hs *HTTPServer) AdminSearchShortURLs(c *contextmodel.ReqContext) response.Response {
+	result, err := hs.ShortURLService.SearchShortURLs(c.Req.Context(), &shorturls.SearchShortURLsQuery{
+		OrgID: c.QueryInt64("orgId"),
+		Query: c.Query("query"),
+		Page:  c.QueryInt("page"),
+		Limit: c.QueryInt("perpage"),
+	})
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 func (hs *HTTPServer) redirectFromShortURL(c *contextmodel.ReqContext) {
 	shortURLUID := web.Params(c.Req)[":uid"]
 
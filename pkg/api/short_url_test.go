@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -21,7 +22,9 @@ import (
 func TestShortURLAPIEndpoint(t *testing.T) {
 	t.Run("Given a correct request for creating a shortUrl", func(t *testing.T) {
 		cmd := dtos.CreateShortURLCmd{
-			Path: "d/TxKARsmGz/new-dashboard?orgId=1&from=1599389322894&to=1599410922894",
+			Path:    "d/TxKARsmGz/new-dashboard?orgId=1&from=1599389322894&to=1599410922894",
+			Slug:    "N1u6L4eGz",
+			Expires: 3600,
 		}
 
 		createResp := &shorturls.ShortUrl{
@@ -31,7 +34,8 @@ func TestShortURLAPIEndpoint(t *testing.T) {
 			Path:  cmd.Path,
 		}
 		service := &fakeShortURLService{
-			createShortURLFunc: func(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error) {
+			createShortURLFunc: func(ctx context.Context, user *user.SignedInUser, createCmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
+				require.Equal(t, &shorturls.CreateShortURLCommand{Path: cmd.Path, Slug: cmd.Slug, TTL: time.Hour}, createCmd)
 				return createResp, nil
 			},
 		}
@@ -78,16 +82,16 @@ func createShortURLScenario(t *testing.T, desc string, url string, routePattern
 }
 
 type fakeShortURLService struct {
-	createShortURLFunc func(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error)
+	createShortURLFunc func(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error)
 }
 
 func (s *fakeShortURLService) GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error) {
 	return nil, nil
 }
 
-func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, path string) (*shorturls.ShortUrl, error) {
+func (s *fakeShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
 	if s.createShortURLFunc != nil {
-		return s.createShortURLFunc(ctx, user, path)
+		return s.createShortURLFunc(ctx, user, cmd)
 	}
 
 	return nil, nil
@@ -100,3 +104,11 @@ func (s *fakeShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *sh
 func (s *fakeShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return nil
 }
+
+func (s *fakeShortURLService) DeleteExpiredShortURLs(ctx context.Context, cmd *shorturls.DeleteExpiredShortURLsCommand) error {
+	return nil
+}
+
+func (s *fakeShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	return &shorturls.SearchShortURLsResult{}, nil
+}
@@ -140,7 +140,7 @@ func (hs *HTTPServer) SignUpStep2(c *contextmodel.ReqContext) response.Response
 		apiResponse["code"] = "redirect-to-select-org"
 	}
 
-	err = hs.loginUserWithUser(usr, c)
+	err = hs.loginUserWithUser(usr, c, "")
 	if err != nil {
 		return response.Error(500, "failed to login user", err)
 	}
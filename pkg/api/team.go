@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -312,6 +313,161 @@ func (hs *HTTPServer) UpdateTeamPreferences(c *contextmodel.ReqContext) response
 	return hs.updatePreferencesFor(c.Req.Context(), orgId, 0, teamId, &dtoCmd)
 }
 
+// swagger:route PATCH /teams/{team_id}/preferences teams patchTeamPreferences
+//
+// Patch Team Preferences.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) PatchTeamPreferences(c *contextmodel.ReqContext) response.Response {
+	dtoCmd := dtos.PatchPrefsCmd{}
+	if err := web.Bind(c.Req, &dtoCmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	teamId, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	orgId := c.OrgID
+
+	if hs.AccessControl.IsDisabled() {
+		if err := hs.teamGuardian.CanAdmin(c.Req.Context(), orgId, teamId, c.SignedInUser); err != nil {
+			return response.Error(403, "Not allowed to update team preferences.", err)
+		}
+	}
+
+	return hs.patchPreferencesFor(c.Req.Context(), orgId, 0, teamId, &dtoCmd)
+}
+
+// swagger:route PUT /teams/{team_id}/parent teams setTeamParent
+//
+// Set the parent of a team.
+//
+// Members of a team are also treated as members of all of its ancestors. Setting the parent to 0 turns the team into a root team.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) SetTeamParent(c *contextmodel.ReqContext) response.Response {
+	cmd := team.SetParentTeamCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	var err error
+	cmd.OrgID = c.OrgID
+	cmd.TeamID, err = strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	// Moving a team below another one grants its members the permissions of the
+	// parent, so the caller has to be able to manage the parent's members as well
+	if hs.AccessControl.IsDisabled() {
+		if err := hs.teamGuardian.CanAdmin(c.Req.Context(), cmd.OrgID, cmd.TeamID, c.SignedInUser); err != nil {
+			return response.Error(403, "Not allowed to update team", err)
+		}
+		if cmd.ParentID != 0 {
+			if err := hs.teamGuardian.CanAdmin(c.Req.Context(), cmd.OrgID, cmd.ParentID, c.SignedInUser); err != nil {
+				return response.Error(403, "Not allowed to update parent team", err)
+			}
+		}
+	} else if cmd.ParentID != 0 {
+		evaluator := ac.EvalPermission(ac.ActionTeamsPermissionsWrite, ac.Scope("teams", "id", strconv.FormatInt(cmd.ParentID, 10)))
+		if ok, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator); err != nil {
+			return response.Error(500, "Failed to evaluate permissions", err)
+		} else if !ok {
+			return response.Error(403, "Not allowed to update parent team", nil)
+		}
+	}
+
+	if err := hs.teamService.SetParentTeam(c.Req.Context(), &cmd); err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return response.Error(404, "Team not found", err)
+		}
+		if errors.Is(err, team.ErrTeamHierarchyCycle) {
+			return response.Error(400, "Team hierarchy would contain a cycle", err)
+		}
+		return response.Error(500, "Failed to set parent team", err)
+	}
+
+	return response.Success("Team parent updated")
+}
+
+// swagger:route GET /teams/{team_id}/children teams getTeamChildren
+//
+// Get the direct child teams of a team.
+//
+// Responses:
+// 200: getTeamChildrenResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetTeamChildren(c *contextmodel.ReqContext) response.Response {
+	teamID, err := strconv.ParseInt(web.Params(c.Req)[":teamId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "teamId is invalid", err)
+	}
+
+	if hs.AccessControl.IsDisabled() {
+		if err := hs.teamGuardian.CanAdmin(c.Req.Context(), c.OrgID, teamID, c.SignedInUser); err != nil {
+			return response.Error(403, "Not allowed to view team", err)
+		}
+	}
+
+	children, err := hs.teamService.GetChildTeams(c.Req.Context(), &team.GetChildTeamsQuery{
+		OrgID:        c.OrgID,
+		TeamID:       teamID,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			return response.Error(404, "Team not found", err)
+		}
+		return response.Error(500, "Failed to get child teams", err)
+	}
+
+	for _, t := range children {
+		t.AvatarURL = dtos.GetGravatarUrlWithDefault(t.Email, t.Name)
+	}
+
+	return response.JSON(http.StatusOK, children)
+}
+
+// swagger:parameters setTeamParent
+type SetTeamParentParams struct {
+	// in:path
+	// required:true
+	TeamID string `json:"team_id"`
+	// in:body
+	// required:true
+	Body team.SetParentTeamCommand `json:"body"`
+}
+
+// swagger:parameters getTeamChildren
+type GetTeamChildrenParams struct {
+	// in:path
+	// required:true
+	TeamID string `json:"team_id"`
+}
+
+// swagger:response getTeamChildrenResponse
+type GetTeamChildrenResponse struct {
+	// The response message
+	// in: body
+	Body []*team.TeamDTO `json:"body"`
+}
+
 // swagger:parameters updateTeamPreferences
 type UpdateTeamPreferencesParams struct {
 	// in:path
@@ -322,6 +478,16 @@ type UpdateTeamPreferencesParams struct {
 	Body dtos.UpdatePrefsCmd `json:"body"`
 }
 
+// swagger:parameters patchTeamPreferences
+type PatchTeamPreferencesParams struct {
+	// in:path
+	// required:true
+	TeamID string `json:"team_id"`
+	// in:body
+	// required:true
+	Body dtos.PatchPrefsCmd `json:"body"`
+}
+
 // swagger:parameters getTeamByID
 type GetTeamByIDParams struct {
 	// in:path
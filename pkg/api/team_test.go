@@ -452,3 +452,34 @@ func TestTeamAPIEndpoint_UpdateTeamPreferences_RBAC(t *testing.T) {
 		require.NoError(t, res.Body.Close())
 	})
 }
+
+func TestTeamAPIEndpoint_PatchTeamPreferences_RBAC(t *testing.T) {
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.preferenceService = &preftest.FakePreferenceService{ExpectedPreference: &pref.Preference{}}
+	})
+
+	request := func(teamID int64, user *user.SignedInUser) (*http.Response, error) {
+		req := server.NewRequest(http.MethodPatch, fmt.Sprintf(detailTeamPreferenceURL, teamID), strings.NewReader(teamPreferenceCmd))
+		req = webtest.RequestWithSignedInUser(req, user)
+		return server.SendJSON(req)
+	}
+
+	t.Run("Access control allows patching team preferences with the correct permissions", func(t *testing.T) {
+		res, err := request(1, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsWrite, Scope: "teams:id:1"},
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Access control prevents patching team preferences with the incorrect permissions", func(t *testing.T) {
+		res, err := request(1, userWithPermissions(1, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsWrite, Scope: "teams:id:2"},
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/userdata"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	accountDeletionJob         = "users.delete-requested-accounts"
+	accountDeletionJobInterval = time.Hour
+)
+
+// swagger:route GET /user/export signed_in_user exportUserData
+//
+// Export the personal data of the actual User.
+//
+// Returns all the personal data Grafana holds about the user as a JSON file: the profile, the organizations, the preferences, the stars, the query history, the sessions and the audit events of the user.
+// Only the audit events written to the file sink are exported.
+//
+// Produces:
+// - application/json
+//
+// Responses:
+// 200: exportUserDataResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) ExportUserData(c *contextmodel.ReqContext) response.Response {
+	export, err := hs.userDataService.Export(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to export user data", err)
+	}
+	filename := fmt.Sprintf("grafana-user-%d-%s.json", c.UserID, export.ExportedAt.Format("2006-01-02"))
+	return response.JSONDownload(http.StatusOK, export, filename)
+}
+
+// swagger:route GET /user/deletion-request signed_in_user getAccountDeletionRequest
+//
+// Get the deletion request of the actual User.
+//
+// Responses:
+// 200: accountDeletionRequestResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetAccountDeletionRequest(c *contextmodel.ReqContext) response.Response {
+	req, err := hs.userDataService.GetDeletionRequest(c.Req.Context(), c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get account deletion request", err)
+	}
+	return response.JSON(http.StatusOK, req)
+}
+
+// swagger:route POST /user/deletion-request signed_in_user requestAccountDeletion
+//
+// Request the deletion of the account of the actual User.
+//
+// The account is deleted once the grace period is over, and once a server admin approved the request when approval is required.
+// The request can be cancelled until the account is deleted.
+//
+// Responses:
+// 200: accountDeletionRequestResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RequestAccountDeletion(c *contextmodel.ReqContext) response.Response {
+	cmd := userdata.RequestDeletionCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	req, err := hs.userDataService.RequestDeletion(c.Req.Context(), c.UserID, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to request account deletion", err)
+	}
+	return response.JSON(http.StatusOK, req)
+}
+
+// swagger:route DELETE /user/deletion-request signed_in_user cancelAccountDeletion
+//
+// Cancel the deletion request of the actual User.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CancelAccountDeletion(c *contextmodel.ReqContext) response.Response {
+	if err := hs.userDataService.CancelDeletion(c.Req.Context(), c.UserID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to cancel account deletion", err)
+	}
+	return response.Success("Account deletion cancelled")
+}
+
+// swagger:route GET /admin/users/deletion-requests admin_users adminListAccountDeletionRequests
+//
+// List the account deletion requests.
+//
+// Returns the requests with their users, ordered by the time the accounts are deleted.
+//
+// Responses:
+// 200: adminListAccountDeletionRequestsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminListAccountDeletionRequests(c *contextmodel.ReqContext) response.Response {
+	status := userdata.DeletionStatus(c.Query("status"))
+	switch status {
+	case "", userdata.DeletionPending, userdata.DeletionApproved, userdata.DeletionRejected:
+	default:
+		return response.Error(http.StatusBadRequest, "Invalid status, expected pending, approved or rejected", nil)
+	}
+
+	requests, err := hs.userDataService.ListDeletionRequests(c.Req.Context(), status)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list account deletion requests", err)
+	}
+	return response.JSON(http.StatusOK, requests)
+}
+
+// swagger:route POST /admin/users/deletion-requests/{request_id}/approve admin_users adminApproveAccountDeletion
+//
+// Approve an account deletion request.
+//
+// The account is deleted once the grace period of the request is over.
+//
+// Responses:
+// 200: accountDeletionRequestResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminApproveAccountDeletion(c *contextmodel.ReqContext) response.Response {
+	return hs.reviewAccountDeletion(c, hs.userDataService.ApproveDeletion)
+}
+
+// swagger:route POST /admin/users/deletion-requests/{request_id}/reject admin_users adminRejectAccountDeletion
+//
+// Reject an account deletion request.
+//
+// Responses:
+// 200: accountDeletionRequestResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) AdminRejectAccountDeletion(c *contextmodel.ReqContext) response.Response {
+	return hs.reviewAccountDeletion(c, hs.userDataService.RejectDeletion)
+}
+
+func (hs *HTTPServer) reviewAccountDeletion(c *contextmodel.ReqContext, review func(ctx context.Context, id, reviewerID int64) (*userdata.DeletionRequest, error)) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":requestId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "requestId is invalid", err)
+	}
+
+	req, err := review(c.Req.Context(), id, c.UserID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to review account deletion request", err)
+	}
+	return response.JSON(http.StatusOK, req)
+}
+
+// registerAccountDeletionJob registers the recurring job deleting the accounts whose deletion request is due.
+func (hs *HTTPServer) registerAccountDeletionJob() {
+	if !hs.Cfg.AccountDeletion.Enabled {
+		return
+	}
+	hs.jobQueue.RegisterHandler(accountDeletionJob, func(ctx context.Context, _ *jobqueue.Job) error {
+		return hs.deleteRequestedAccounts(ctx)
+	}, jobqueue.HandlerOptions{Interval: accountDeletionJobInterval})
+}
+
+func (hs *HTTPServer) deleteRequestedAccounts(ctx context.Context) error {
+	due, err := hs.userDataService.ListDueDeletions(ctx)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, req := range due {
+		if err := hs.deleteRequestedAccount(ctx, req.UserID); err != nil {
+			hs.log.Error("Failed to delete account on request", "userId", req.UserID, "error", err)
+			failed++
+			continue
+		}
+		hs.log.Info("Deleted account on request", "userId", req.UserID, "requested", req.Created, "reviewedBy", req.ReviewedBy)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d requested accounts", failed, len(due))
+	}
+	return nil
+}
+
+func (hs *HTTPServer) deleteRequestedAccount(ctx context.Context, userID int64) error {
+	usr, err := hs.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		// the user is already deleted, only the references are left
+	case err != nil:
+		return err
+	case usr.IsAdmin:
+		// the user was made a server admin since the request was approved
+		return fmt.Errorf("user %d is a server admin", userID)
+	default:
+		if err := hs.userService.Delete(ctx, &user.DeleteUserCommand{UserID: userID}); err != nil {
+			return err
+		}
+	}
+
+	for _, cleanup := range hs.deletedUserCleanups(userID) {
+		if err := cleanup(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// swagger:parameters adminApproveAccountDeletion adminRejectAccountDeletion
+type AccountDeletionRequestIDParam struct {
+	// in:path
+	// required:true
+	RequestID int64 `json:"request_id"`
+}
+
+// swagger:parameters requestAccountDeletion
+type RequestAccountDeletionParams struct {
+	// in:body
+	// required:true
+	Body userdata.RequestDeletionCommand `json:"body"`
+}
+
+// swagger:parameters adminListAccountDeletionRequests
+type AdminListAccountDeletionRequestsParams struct {
+	// Only return the requests with this status
+	// in:query
+	// required:false
+	// enum: pending,approved,rejected
+	Status string `json:"status"`
+}
+
+// swagger:response exportUserDataResponse
+type ExportUserDataResponse struct {
+	// in:body
+	Body userdata.Export `json:"body"`
+}
+
+// swagger:response accountDeletionRequestResponse
+type AccountDeletionRequestResponse struct {
+	// in:body
+	Body userdata.DeletionRequest `json:"body"`
+}
+
+// swagger:response adminListAccountDeletionRequestsResponse
+type AdminListAccountDeletionRequestsResponse struct {
+	// in:body
+	Body []userdata.DeletionRequestDTO `json:"body"`
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/userdata"
+	"github.com/grafana/grafana/pkg/services/userdata/userdatatest"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_UserData(t *testing.T) {
+	userData := userdatatest.NewFakeService()
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.userDataService = userData
+	})
+	viewer := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleViewer, Login: "viewer"}
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, IsGrafanaAdmin: true}
+
+	send := func(t *testing.T, req *http.Request, signedInUser *user.SignedInUser) *http.Response {
+		t.Helper()
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, signedInUser))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+
+	t.Run("should export the data of the user as a file", func(t *testing.T) {
+		userData.ExpectedExport = &userdata.Export{
+			ExportedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+			Profile:    &user.UserProfileDTO{ID: 2, Login: "viewer"},
+		}
+
+		res := send(t, server.NewGetRequest("/api/user/export"), viewer)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `attachment;filename="grafana-user-2-2023-03-01.json"`, res.Header.Get("Content-Disposition"))
+
+		var export userdata.Export
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&export))
+		assert.Equal(t, "viewer", export.Profile.Login)
+	})
+
+	t.Run("should return the errors of the deletion requests", func(t *testing.T) {
+		userData.ExpectedError = userdata.ErrDeletionDisabled.Errorf("disabled")
+		t.Cleanup(func() { userData.ExpectedError = nil })
+
+		res := send(t, server.NewRequest(http.MethodPost, "/api/user/deletion-request", strings.NewReader(`{"reason":"leaving"}`)), viewer)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should request the deletion of the account", func(t *testing.T) {
+		userData.ExpectedDeletionRequest = &userdata.DeletionRequest{ID: 1, UserID: 2, Status: userdata.DeletionPending}
+
+		res := send(t, server.NewRequest(http.MethodPost, "/api/user/deletion-request", strings.NewReader(`{"reason":"leaving"}`)), viewer)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var req userdata.DeletionRequest
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&req))
+		assert.Equal(t, userdata.DeletionPending, req.Status)
+	})
+
+	t.Run("should only let the server admins review the requests", func(t *testing.T) {
+		res := send(t, server.NewRequest(http.MethodPost, "/api/admin/users/deletion-requests/1/approve", nil), viewer)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		res = send(t, server.NewRequest(http.MethodPost, "/api/admin/users/deletion-requests/1/approve", nil), admin)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		res = send(t, server.NewRequest(http.MethodPost, "/api/admin/users/deletion-requests/abc/reject", nil), admin)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should reject an unknown status", func(t *testing.T) {
+		res := send(t, server.NewGetRequest("/api/admin/users/deletion-requests?status=done"), admin)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /user/notifications signed_in_user getUserNotifications
+//
+// Get the notifications of the actual User.
+//
+// Returns the notifications of the user in the current organization, and the ones sent to the user in every organization, latest first.
+// The new notifications are pushed to the `grafana/notifications/user/<userId>` Live channel of the user.
+//
+// Responses:
+// 200: getUserNotificationsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) GetUserNotifications(c *contextmodel.ReqContext) response.Response {
+	query := &inbox.ListQuery{
+		UserID:     c.UserID,
+		OrgID:      c.OrgID,
+		UnreadOnly: c.QueryBool("unread"),
+		Kind:       inbox.Kind(c.Query("kind")),
+		Page:       c.QueryInt("page"),
+		PerPage:    c.QueryInt("perpage"),
+	}
+	if query.Kind != "" && !query.Kind.IsValid() {
+		return response.Error(http.StatusBadRequest, "Invalid kind, expected alert-digest, share-invitation, report-completed, announcement or change-review", nil)
+	}
+
+	result, err := hs.inboxService.List(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get notifications", err)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /user/notifications/{notification_id}/read signed_in_user markUserNotificationRead
+//
+// Mark a notification of the actual User as read.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) MarkUserNotificationRead(c *contextmodel.ReqContext) response.Response {
+	return hs.setUserNotificationRead(c, true)
+}
+
+// swagger:route POST /user/notifications/{notification_id}/unread signed_in_user markUserNotificationUnread
+//
+// Mark a notification of the actual User as unread.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) MarkUserNotificationUnread(c *contextmodel.ReqContext) response.Response {
+	return hs.setUserNotificationRead(c, false)
+}
+
+func (hs *HTTPServer) setUserNotificationRead(c *contextmodel.ReqContext, read bool) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":notificationId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "notificationId is invalid", err)
+	}
+
+	if err := hs.inboxService.SetRead(c.Req.Context(), c.UserID, id, read); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update notification", err)
+	}
+	if read {
+		return response.Success("Notification marked as read")
+	}
+	return response.Success("Notification marked as unread")
+}
+
+// swagger:route POST /user/notifications/read signed_in_user markAllUserNotificationsRead
+//
+// Mark all the notifications of the actual User as read.
+//
+// Marks the notifications of the user in the current organization, and the ones sent to the user in every organization.
+//
+// Responses:
+// 200: markAllUserNotificationsReadResponse
+// 401: unauthorisedError
+// 500: internalServerError
+func (hs *HTTPServer) MarkAllUserNotificationsRead(c *contextmodel.ReqContext) response.Response {
+	marked, err := hs.inboxService.MarkAllRead(c.Req.Context(), c.UserID, c.OrgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to update notifications", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Notifications marked as read",
+		"marked":  marked,
+	})
+}
+
+// swagger:route DELETE /user/notifications/{notification_id} signed_in_user deleteUserNotification
+//
+// Delete a notification of the actual User.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteUserNotification(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":notificationId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "notificationId is invalid", err)
+	}
+
+	if err := hs.inboxService.Delete(c.Req.Context(), c.UserID, id); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete notification", err)
+	}
+	return response.Success("Notification deleted")
+}
+
+// swagger:route POST /admin/announcements admin adminSendAnnouncement
+//
+// Send an announcement.
+//
+// Sends a notification to all the users of an organization, or to all the users when the organization is 0.
+// The service accounts and the disabled users aren't notified. The announcement is pushed to the `grafana/notifications/announcements` Live channel of the organizations.
+// Only works for Grafana admins.
+//
+// Responses:
+// 200: adminSendAnnouncementResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminSendAnnouncement(c *contextmodel.ReqContext) response.Response {
+	cmd := inbox.AnnounceCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	notified, err := hs.inboxService.Announce(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to send announcement", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message":  "Announcement sent",
+		"notified": notified,
+	})
+}
+
+// swagger:parameters getUserNotifications
+type GetUserNotificationsParams struct {
+	// Only return the unread notifications
+	// in:query
+	// required:false
+	Unread bool `json:"unread"`
+	// Only return the notifications of this kind
+	// in:query
+	// required:false
+	// enum: alert-digest,share-invitation,report-completed,announcement,change-review
+	Kind string `json:"kind"`
+	// in:query
+	// required:false
+	// default:1
+	Page int `json:"page"`
+	// Number of notifications per page, at most 200
+	// in:query
+	// required:false
+	// default:50
+	PerPage int `json:"perpage"`
+}
+
+// swagger:parameters markUserNotificationRead markUserNotificationUnread deleteUserNotification
+type UserNotificationIDParam struct {
+	// in:path
+	// required:true
+	NotificationID int64 `json:"notification_id"`
+}
+
+// swagger:parameters adminSendAnnouncement
+type AdminSendAnnouncementParams struct {
+	// in:body
+	// required:true
+	Body inbox.AnnounceCommand `json:"body"`
+}
+
+// swagger:response getUserNotificationsResponse
+type GetUserNotificationsResponse struct {
+	// in:body
+	Body inbox.ListResult `json:"body"`
+}
+
+// swagger:response markAllUserNotificationsReadResponse
+type MarkAllUserNotificationsReadResponse struct {
+	// in:body
+	Body struct {
+		Message string `json:"message"`
+		// Number of notifications marked as read
+		Marked int64 `json:"marked"`
+	} `json:"body"`
+}
+
+// swagger:response adminSendAnnouncementResponse
+type AdminSendAnnouncementResponse struct {
+	// in:body
+	Body struct {
+		Message string `json:"message"`
+		// Number of users notified
+		Notified int64 `json:"notified"`
+	} `json:"body"`
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/inbox/inboxtest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestAPI_UserNotifications(t *testing.T) {
+	inboxService := inboxtest.NewFakeService()
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.inboxService = inboxService
+	})
+	viewer := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleViewer, Login: "viewer"}
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, IsGrafanaAdmin: true}
+
+	send := func(t *testing.T, req *http.Request, signedInUser *user.SignedInUser) *http.Response {
+		t.Helper()
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, signedInUser))
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, res.Body.Close()) })
+		return res
+	}
+
+	t.Run("should list the notifications of the user", func(t *testing.T) {
+		inboxService.ExpectedResult = &inbox.ListResult{
+			Notifications: []*inbox.Notification{{ID: 1, UserID: 2, Kind: inbox.KindAnnouncement, Title: "Maintenance"}},
+			TotalCount:    1,
+			UnreadCount:   1,
+		}
+
+		res := send(t, server.NewGetRequest("/api/user/notifications?unread=true"), viewer)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var result inbox.ListResult
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		require.Len(t, result.Notifications, 1)
+		assert.Equal(t, int64(1), result.UnreadCount)
+
+		res = send(t, server.NewGetRequest("/api/user/notifications?kind=unknown"), viewer)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("should update the read state of the notifications", func(t *testing.T) {
+		res := send(t, server.NewRequest(http.MethodPost, "/api/user/notifications/1/read", nil), viewer)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		res = send(t, server.NewRequest(http.MethodPost, "/api/user/notifications/1/unread", nil), viewer)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		res = send(t, server.NewRequest(http.MethodPost, "/api/user/notifications/abc/read", nil), viewer)
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+		inboxService.ExpectedCount = 3
+		t.Cleanup(func() { inboxService.ExpectedCount = 0 })
+		res = send(t, server.NewRequest(http.MethodPost, "/api/user/notifications/read", nil), viewer)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var body struct{ Marked int64 }
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, int64(3), body.Marked)
+	})
+
+	t.Run("should return the errors of the service", func(t *testing.T) {
+		inboxService.ExpectedError = inbox.ErrNotificationNotFound.Errorf("not found")
+		t.Cleanup(func() { inboxService.ExpectedError = nil })
+
+		res := send(t, server.NewRequest(http.MethodDelete, "/api/user/notifications/1", nil), viewer)
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("should only let the server admins send announcements", func(t *testing.T) {
+		body := `{"title":"Maintenance tonight"}`
+		res := send(t, server.NewRequest(http.MethodPost, "/api/admin/announcements", strings.NewReader(body)), viewer)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+
+		res = send(t, server.NewRequest(http.MethodPost, "/api/admin/announcements", strings.NewReader(body)), admin)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
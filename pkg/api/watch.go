@@ -0,0 +1,24 @@
+package api
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
+)
+
+// watchDashboards streams the changes of the dashboards or folders the signed in user can view, for the list
+// endpoints called with watch=true.
+func (hs *HTTPServer) watchDashboards(c *contextmodel.ReqContext, kinds ...resourcewatch.Kind) response.Response {
+	events := hs.resourceWatch.Watch(c.Req.Context(), c.OrgID, kinds...)
+	return resourcewatch.Stream(c, events, func(ctx context.Context, e resourcewatch.Event) bool {
+		g, err := guardian.NewByUID(ctx, e.UID, c.OrgID, c.SignedInUser)
+		if err != nil {
+			return false
+		}
+		canView, err := g.CanView()
+		return err == nil && canView
+	})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestWatchDashboards(t *testing.T) {
+	origNewByUID := guardian.NewByUID
+	t.Cleanup(func() { guardian.NewByUID = origNewByUID })
+	guardian.NewByUID = func(_ context.Context, dashUID string, orgID int64, user *user.SignedInUser) (guardian.DashboardGuardian, error) {
+		return &guardian.FakeDashboardGuardian{DashUID: dashUID, OrgID: orgID, User: user, CanViewValue: dashUID != "hidden"}, nil
+	}
+
+	watch := func(t *testing.T, url string, publish ...bus.Msg) []resourcewatch.Event {
+		t.Helper()
+		b := bus.ProvideBus(tracing.InitializeTracerForTest())
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.resourceWatch = resourcewatch.ProvideService(b)
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := server.NewGetRequest(url).WithContext(ctx)
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "application/x-ndjson", res.Header.Get("Content-Type"))
+
+		// the watcher is registered once the headers are received
+		for _, msg := range publish {
+			require.NoError(t, b.Publish(context.Background(), msg))
+		}
+		// a deleted event of each kind marks the end of the published events
+		require.NoError(t, b.Publish(context.Background(), &events.DashboardDeleted{UID: "end", OrgID: 1, IsFolder: true}))
+		require.NoError(t, b.Publish(context.Background(), &events.DashboardDeleted{UID: "end", OrgID: 1}))
+
+		var received []resourcewatch.Event
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			var e resourcewatch.Event
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+			if e.UID == "end" {
+				break
+			}
+			received = append(received, e)
+		}
+		return received
+	}
+
+	t.Run("search should stream the changes of the dashboards and folders the user can view", func(t *testing.T) {
+		received := watch(t, "/api/search?watch=true",
+			&events.DashboardCreated{UID: "dash", Title: "Dash", OrgID: 1, Version: 1},
+			&events.DashboardCreated{UID: "hidden", OrgID: 1},
+			&events.DashboardCreated{UID: "other-org", OrgID: 2},
+			&events.DashboardUpdated{UID: "folder", OrgID: 1, IsFolder: true, Version: 2},
+			&events.DashboardDeleted{UID: "hidden", OrgID: 1},
+		)
+		require.Len(t, received, 3)
+		require.Equal(t, resourcewatch.Event{Type: resourcewatch.EventCreated, Kind: resourcewatch.KindDashboard, UID: "dash", Title: "Dash", Version: 1}, received[0])
+		require.Equal(t, resourcewatch.Event{Type: resourcewatch.EventUpdated, Kind: resourcewatch.KindFolder, UID: "folder", Version: 2}, received[1])
+		require.Equal(t, resourcewatch.Event{Type: resourcewatch.EventDeleted, Kind: resourcewatch.KindDashboard, UID: "hidden"}, received[2])
+	})
+
+	t.Run("folders should only stream the changes of the folders", func(t *testing.T) {
+		received := watch(t, "/api/folders?watch=true",
+			&events.DashboardCreated{UID: "dash", OrgID: 1},
+			&events.DashboardCreated{UID: "folder", OrgID: 1, IsFolder: true},
+		)
+		require.Len(t, received, 1)
+		require.Equal(t, "folder", received[0].UID)
+	})
+}
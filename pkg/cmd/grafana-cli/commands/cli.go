@@ -56,6 +56,16 @@ func CLICommand(version string) *cli.Command {
 				Name:  "insecure",
 				Usage: "Skip TLS verification (insecure)",
 			},
+			&cli.StringFlag{
+				Name:    "remoteUrl",
+				Usage:   "URL of a Grafana instance to run the admin commands against over its HTTP API, instead of the local files and database",
+				EnvVars: []string{"GF_CLI_REMOTE_URL"},
+			},
+			&cli.StringFlag{
+				Name:    "remoteToken",
+				Usage:   "Service account token of a Grafana server admin, used to authenticate against remoteUrl",
+				EnvVars: []string{"GF_CLI_REMOTE_TOKEN"},
+			},
 		},
 		Subcommands: Commands,
 		Before: func(c *cli.Context) error {
@@ -1,12 +1,14 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/dataintegrity"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
@@ -102,6 +104,28 @@ func runPluginCommand(command func(commandLine utils.CommandLine) error) func(co
 	}
 }
 
+// runRemoteCommand runs the remote variant of a command against the HTTP API of the instance
+// set with remoteUrl, or the local variant when no remote instance is set.
+func runRemoteCommand(remote func(commandLine utils.CommandLine, client *services.RemoteClient) error, local cli.ActionFunc) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		cmd := &utils.ContextCommandLine{Context: context}
+		if cmd.RemoteURL() == "" {
+			return local(context)
+		}
+
+		if cmd.RemoteToken() == "" {
+			return errors.New("remoteToken is required to run commands against a remote instance")
+		}
+
+		if err := remote(cmd, services.NewRemoteClient(cmd.RemoteURL(), cmd.RemoteToken())); err != nil {
+			return err
+		}
+
+		logger.Info("\n\n")
+		return nil
+	}
+}
+
 // Command contains command state.
 type Command struct {
 	Client utils.ApiClient
@@ -115,7 +139,7 @@ var pluginCommands = []*cli.Command{
 	{
 		Name:   "install",
 		Usage:  "install <plugin id> <plugin version (optional)>",
-		Action: runPluginCommand(cmd.installCommand),
+		Action: runRemoteCommand(installRemoteCommand, runPluginCommand(cmd.installCommand)),
 	}, {
 		Name:   "list-remote",
 		Usage:  "list remote available plugins",
@@ -137,12 +161,19 @@ var pluginCommands = []*cli.Command{
 	}, {
 		Name:   "ls",
 		Usage:  "list installed plugins (excludes core plugins)",
-		Action: runPluginCommand(cmd.lsCommand),
+		Action: runRemoteCommand(lsRemoteCommand, runPluginCommand(cmd.lsCommand)),
 	}, {
 		Name:    "uninstall",
 		Aliases: []string{"remove"},
 		Usage:   "uninstall <plugin id>",
-		Action:  runPluginCommand(cmd.removeCommand),
+		Action:  runRemoteCommand(removeRemoteCommand, runPluginCommand(cmd.removeCommand)),
+	},
+}
+
+var dataIntegrityFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "report",
+		Usage: "Path of a file to write the JSON report to",
 	},
 }
 
@@ -150,7 +181,7 @@ var adminCommands = []*cli.Command{
 	{
 		Name:   "reset-admin-password",
 		Usage:  "reset-admin-password <new password>",
-		Action: runRunnerCommand(resetPasswordCommand),
+		Action: runRemoteCommand(resetPasswordRemoteCommand, runRunnerCommand(resetPasswordCommand)),
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "password-from-stdin",
@@ -175,6 +206,24 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "data-integrity",
+		Usage: "Detects and repairs orphaned rows in your database",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "check",
+				Usage:  "Reports the orphaned rows, such as dashboards of deleted organizations or permissions of deleted users. Does not change the database.",
+				Action: runDbCommand(dataintegrity.Check),
+				Flags:  dataIntegrityFlags,
+			},
+			{
+				Name:   "repair",
+				Usage:  "Deletes the orphaned rows, each kind of orphaned rows in its own transaction. Safe to execute multiple times.",
+				Action: runDbCommand(dataintegrity.Repair),
+				Flags:  dataIntegrityFlags,
+			},
+		},
+	},
 	{
 		Name:  "secrets-migration",
 		Usage: "Runs a script that migrates secrets in your database",
@@ -196,11 +245,62 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "migrations",
+		Usage: "Inspects the database migrations",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "status",
+				Usage:  "Lists the pending and failed database migrations without applying them",
+				Action: runRemoteCommand(migrationStatusRemoteCommand, runMigrationStatusCommand()),
+			},
+		},
+	},
 	{
 		Name:  "user-manager",
 		Usage: "Runs different helpful user commands",
 		Subcommands: []*cli.Command{
 			// TODO: reset password for user
+			{
+				Name:   "list",
+				Usage:  "list users, optionally filtered by login, email or name",
+				Action: runRemoteCommand(listUsersRemoteCommand, runRunnerCommand(listUsersCommand)),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "query",
+						Usage: "Only list the users whose login, email or name contains the query",
+					},
+				},
+			},
+			{
+				Name:   "create",
+				Usage:  "create <login>",
+				Action: runRemoteCommand(createUserRemoteCommand, runRunnerCommand(createUserCommand)),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "email",
+						Usage: "The user's email",
+					},
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "The user's name",
+					},
+					&cli.StringFlag{
+						Name:     "password",
+						Usage:    "The user's password",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "admin",
+						Usage: "Make the user a Grafana server admin",
+					},
+				},
+			},
+			{
+				Name:   "delete",
+				Usage:  "delete <user id>",
+				Action: runRemoteCommand(deleteUserRemoteCommand, runRunnerCommand(deleteUserCommand)),
+			},
 			{
 				Name:  "conflicts",
 				Usage: "runs a conflict resolution to find users with multiple entries",
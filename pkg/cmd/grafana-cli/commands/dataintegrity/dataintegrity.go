@@ -0,0 +1,195 @@
+package dataintegrity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+// check detects the orphaned rows of a table, the rows matching where reference rows that no longer exist.
+type check struct {
+	name        string
+	description string
+	table       string
+	where       func(quote func(string) string) string
+}
+
+func notIn(column, table string) func(quote func(string) string) string {
+	return func(quote func(string) string) string {
+		return fmt.Sprintf("%s NOT IN (SELECT id FROM %s)", column, quote(table))
+	}
+}
+
+func positiveNotIn(column, table string) func(quote func(string) string) string {
+	return func(quote func(string) string) string {
+		return fmt.Sprintf("%s > 0 AND %s", column, notIn(column, table)(quote))
+	}
+}
+
+// checks are run in order, so that the rows orphaned by a repair are detected by the following checks.
+var checks = []check{
+	{
+		name:        "dashboards-without-org",
+		description: "dashboards and folders of deleted organizations",
+		table:       "dashboard",
+		where:       notIn("org_id", "org"),
+	},
+	{
+		name:        "dashboard-acl-missing-dashboard",
+		description: "dashboard permissions of deleted dashboards",
+		table:       "dashboard_acl",
+		where:       positiveNotIn("dashboard_id", "dashboard"),
+	},
+	{
+		name:        "dashboard-acl-deleted-user",
+		description: "dashboard permissions of deleted users",
+		table:       "dashboard_acl",
+		where:       positiveNotIn("user_id", "user"),
+	},
+	{
+		name:        "dashboard-acl-deleted-team",
+		description: "dashboard permissions of deleted teams",
+		table:       "dashboard_acl",
+		where:       positiveNotIn("team_id", "team"),
+	},
+	{
+		name:        "user-role-deleted-user",
+		description: "role assignments of deleted users",
+		table:       "user_role",
+		where:       notIn("user_id", "user"),
+	},
+	{
+		name:        "team-member-deleted-user",
+		description: "team memberships of deleted users",
+		table:       "team_member",
+		where:       notIn("user_id", "user"),
+	},
+	{
+		name:        "team-member-deleted-team",
+		description: "memberships of deleted teams",
+		table:       "team_member",
+		where:       notIn("team_id", "team"),
+	},
+	{
+		name:        "org-user-deleted-user",
+		description: "organization memberships of deleted users",
+		table:       "org_user",
+		where:       notIn("user_id", "user"),
+	},
+	{
+		name:        "org-user-deleted-org",
+		description: "memberships of deleted organizations",
+		table:       "org_user",
+		where:       notIn("org_id", "org"),
+	},
+}
+
+// Report is the result of a check or repair run.
+type Report struct {
+	Started time.Time     `json:"started"`
+	Repair  bool          `json:"repair"`
+	Results []CheckResult `json:"results"`
+}
+
+type CheckResult struct {
+	Name     string `json:"name"`
+	Table    string `json:"table"`
+	Orphaned int64  `json:"orphaned"`
+	Repaired int64  `json:"repaired"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Check detects the orphaned rows without changing the database.
+func Check(c utils.CommandLine, sqlStore db.DB) error {
+	return run(c, sqlStore, false)
+}
+
+// Repair deletes the orphaned rows, each check is repaired in its own transaction.
+func Repair(c utils.CommandLine, sqlStore db.DB) error {
+	return run(c, sqlStore, true)
+}
+
+func run(c utils.CommandLine, sqlStore db.DB, repair bool) error {
+	report := runChecks(context.Background(), sqlStore, repair)
+	logReport(report)
+
+	if path := c.String("report"); path != "" {
+		if err := writeReport(path, report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		logger.Infof("Report written to %s\n", path)
+	}
+
+	for _, r := range report.Results {
+		if r.Error != "" {
+			return fmt.Errorf("check %s failed: %s", r.Name, r.Error)
+		}
+	}
+	return nil
+}
+
+func runChecks(ctx context.Context, sqlStore db.DB, repair bool) Report {
+	report := Report{Started: time.Now(), Repair: repair, Results: make([]CheckResult, 0, len(checks))}
+	for _, chk := range checks {
+		result := CheckResult{Name: chk.name, Table: chk.table}
+		where := chk.where(sqlStore.Quote)
+
+		err := sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			count, err := sess.Table(chk.table).Where(where).Count()
+			if err != nil {
+				return err
+			}
+			result.Orphaned = count
+
+			if !repair || count == 0 {
+				return nil
+			}
+
+			res, err := sess.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", sqlStore.Quote(chk.table), where))
+			if err != nil {
+				return err
+			}
+			result.Repaired, err = res.RowsAffected()
+			return err
+		})
+		if err != nil {
+			result.Repaired = 0
+			result.Error = err.Error()
+		}
+
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+func logReport(report Report) {
+	logger.Info("\n")
+	for i, r := range report.Results {
+		switch {
+		case r.Error != "":
+			logger.Infof("%s %s: %s\n", color.RedString("✗"), r.Name, r.Error)
+		case r.Orphaned == 0:
+			logger.Infof("%s %s: no %s\n", color.GreenString("✔"), r.Name, checks[i].description)
+		case report.Repair:
+			logger.Infof("%s %s: deleted %d of %d %s from %s\n", color.GreenString("✔"), r.Name, r.Repaired, r.Orphaned, checks[i].description, r.Table)
+		default:
+			logger.Infof("%s %s: found %d %s in %s\n", color.YellowString("!"), r.Name, r.Orphaned, checks[i].description, r.Table)
+		}
+	}
+}
+
+func writeReport(path string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
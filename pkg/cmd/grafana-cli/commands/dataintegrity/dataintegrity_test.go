@@ -0,0 +1,82 @@
+package dataintegrity
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/commandstest"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestIntegrationDataIntegrity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := db.InitTestDB(t)
+
+	err := store.WithDbSession(context.Background(), func(sess *db.Session) error {
+		now := time.Now()
+		dash := &dashboards.Dashboard{OrgID: 999, UID: "orphan", Slug: "orphan", Title: "orphan", Data: nil, Created: now, Updated: now}
+		if _, err := sess.Insert(dash); err != nil {
+			return err
+		}
+		_, err := sess.Insert(
+			&dashboards.DashboardACL{OrgID: 1, DashboardID: dash.ID, UserID: 12345, Permission: dashboards.PERMISSION_VIEW, Created: now, Updated: now},
+			&dashboards.DashboardACL{OrgID: 1, DashboardID: -1, TeamID: 6789, Permission: dashboards.PERMISSION_VIEW, Created: now, Updated: now},
+		)
+		return err
+	})
+	require.NoError(t, err)
+
+	orphaned := func(report Report) map[string]int64 {
+		counts := map[string]int64{}
+		for _, r := range report.Results {
+			require.Empty(t, r.Error)
+			if r.Orphaned > 0 {
+				counts[r.Name] = r.Orphaned
+			}
+		}
+		return counts
+	}
+
+	t.Run("check reports the orphaned rows without deleting them", func(t *testing.T) {
+		report := runChecks(context.Background(), store, false)
+		assert.Equal(t, map[string]int64{
+			"dashboards-without-org":     1,
+			"dashboard-acl-deleted-user": 1,
+			"dashboard-acl-deleted-team": 1,
+		}, orphaned(report))
+
+		report = runChecks(context.Background(), store, false)
+		assert.Len(t, orphaned(report), 3)
+	})
+
+	t.Run("repair deletes the orphaned rows and the rows they orphan", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "report.json")
+		cmd, err := commandstest.NewCliContext(map[string]string{"report": path})
+		require.NoError(t, err)
+
+		require.NoError(t, Repair(cmd, store))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var report Report
+		require.NoError(t, json.Unmarshal(data, &report))
+		assert.True(t, report.Repair)
+		assert.Equal(t, map[string]int64{
+			"dashboards-without-org":          1,
+			"dashboard-acl-missing-dashboard": 1,
+			"dashboard-acl-deleted-team":      1,
+		}, orphaned(report))
+
+		assert.Empty(t, orphaned(runChecks(context.Background(), store, false)))
+	})
+}
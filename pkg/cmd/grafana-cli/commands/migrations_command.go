@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrations"
+)
+
+// runMigrationStatusCommand reads the migration log of the local database without applying the pending migrations.
+func runMigrationStatusCommand() func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		cmd := &utils.ContextCommandLine{Context: context}
+
+		cfg, err := initCfg(cmd)
+		if err != nil {
+			return fmt.Errorf("%v: %w", "failed to load configuration", err)
+		}
+		cfg.Raw.Section("database").Key("skip_migrations").SetValue("true")
+
+		tracer, err := tracing.ProvideService(cfg)
+		if err != nil {
+			return fmt.Errorf("%v: %w", "failed to initialize tracer service", err)
+		}
+
+		sqlStore, err := db.ProvideService(cfg, nil, &migrations.OSSMigrations{}, bus.ProvideBus(tracer), tracer)
+		if err != nil {
+			return fmt.Errorf("%v: %w", "failed to initialize SQL store", err)
+		}
+
+		status, err := sqlStore.MigrationStatus()
+		if err != nil {
+			return fmt.Errorf("%v: %w", "failed to read migration log", err)
+		}
+
+		logMigrationStatus(toMigrationStatus(status))
+		logger.Info("\n\n")
+		return nil
+	}
+}
+
+func migrationStatusRemoteCommand(_ utils.CommandLine, client *services.RemoteClient) error {
+	status, err := client.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	logMigrationStatus(status)
+	return nil
+}
+
+func toMigrationStatus(status *sqlstore.MigrationStatus) models.MigrationStatus {
+	result := models.MigrationStatus{
+		Total:   status.Total,
+		Applied: status.Applied,
+		Pending: status.Pending,
+	}
+	for _, f := range status.Failed {
+		result.Failed = append(result.Failed, models.FailedMigration{ID: f.ID, Error: f.Error, Timestamp: f.Timestamp})
+	}
+	return result
+}
+
+func logMigrationStatus(status models.MigrationStatus) {
+	logger.Infof("%d/%d migrations applied\n", status.Applied, status.Total)
+	if len(status.Pending) == 0 {
+		logger.Infof("Database is up to date %s\n", color.GreenString("✔"))
+		return
+	}
+
+	logger.Infof("\npending migrations:\n")
+	for _, id := range status.Pending {
+		logger.Infof("  %s\n", id)
+	}
+
+	for _, f := range status.Failed {
+		logger.Infof("\n%s %s failed at %s: %s\n", color.RedString("✗"), f.ID, f.Timestamp.Format("2006-01-02 15:04:05"), f.Error)
+	}
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+)
+
+// installRemoteCommand installs a plugin on a remote instance, which downloads it from its own plugin repository.
+func installRemoteCommand(c utils.CommandLine, client *services.RemoteClient) error {
+	pluginID := c.Args().First()
+	if pluginID == "" {
+		return errors.New("please specify plugin to install")
+	}
+
+	if err := client.InstallPlugin(pluginID, c.Args().Get(1)); err != nil {
+		return err
+	}
+
+	logger.Infof("%s Installed %s successfully\n", color.GreenString("✔"), pluginID)
+	return nil
+}
+
+func removeRemoteCommand(c utils.CommandLine, client *services.RemoteClient) error {
+	pluginID := c.Args().First()
+	if pluginID == "" {
+		return errors.New("missing plugin parameter")
+	}
+
+	if err := client.UninstallPlugin(pluginID); err != nil {
+		if errors.Is(err, services.ErrNotFoundError) {
+			return errors.New("plugin does not exist")
+		}
+		return err
+	}
+
+	logger.Infof("%s Uninstalled %s successfully\n", color.GreenString("✔"), pluginID)
+	return nil
+}
+
+func lsRemoteCommand(_ utils.CommandLine, client *services.RemoteClient) error {
+	plugins, err := client.ListPlugins()
+	if err != nil {
+		return err
+	}
+
+	if len(plugins) > 0 {
+		logger.Info("installed plugins:\n")
+	} else {
+		logger.Info("no installed plugins found\n")
+	}
+
+	for _, plugin := range plugins {
+		logger.Infof("%s %s %s\n", plugin.ID, color.YellowString("@"), plugin.Info.Version)
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/runner"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
@@ -18,25 +19,26 @@ import (
 const DefaultAdminUserId = 1
 
 func resetPasswordCommand(c utils.CommandLine, runner runner.Runner) error {
-	newPassword := ""
-	adminId := int64(c.Int("user-id"))
-
-	if c.Bool("password-from-stdin") {
-		logger.Infof("New Password: ")
-
-		scanner := bufio.NewScanner(os.Stdin)
-		if ok := scanner.Scan(); !ok {
-			if err := scanner.Err(); err != nil {
-				return fmt.Errorf("can't read password from stdin: %w", err)
-			}
-			return fmt.Errorf("can't read password from stdin")
-		}
-		newPassword = scanner.Text()
-	} else {
-		newPassword = c.Args().First()
+	newPassword, err := readNewPassword(c)
+	if err != nil {
+		return err
+	}
+
+	err = resetPassword(int64(c.Int("user-id")), newPassword, runner.UserService)
+	if err == nil {
+		logger.Infof("\n")
+		logger.Infof("Admin password changed successfully %s", color.GreenString("✔"))
+	}
+	return err
+}
+
+func resetPasswordRemoteCommand(c utils.CommandLine, client *services.RemoteClient) error {
+	newPassword, err := readNewPassword(c)
+	if err != nil {
+		return err
 	}
 
-	err := resetPassword(adminId, newPassword, runner.UserService)
+	err = resetPasswordRemote(int64(c.Int("user-id")), newPassword, client)
 	if err == nil {
 		logger.Infof("\n")
 		logger.Infof("Admin password changed successfully %s", color.GreenString("✔"))
@@ -44,6 +46,23 @@ func resetPasswordCommand(c utils.CommandLine, runner runner.Runner) error {
 	return err
 }
 
+func readNewPassword(c utils.CommandLine) (string, error) {
+	if !c.Bool("password-from-stdin") {
+		return c.Args().First(), nil
+	}
+
+	logger.Infof("New Password: ")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	if ok := scanner.Scan(); !ok {
+		if err := scanner.Err(); err != nil {
+			return "", fmt.Errorf("can't read password from stdin: %w", err)
+		}
+		return "", fmt.Errorf("can't read password from stdin")
+	}
+	return scanner.Text(), nil
+}
+
 func resetPassword(adminId int64, newPassword string, userSvc user.Service) error {
 	password := user.Password(newPassword)
 	if password.IsWeak() {
@@ -76,4 +95,24 @@ func resetPassword(adminId int64, newPassword string, userSvc user.Service) erro
 	return nil
 }
 
+func resetPasswordRemote(adminId int64, newPassword string, client *services.RemoteClient) error {
+	if user.Password(newPassword).IsWeak() {
+		return fmt.Errorf("new password is too short")
+	}
+
+	usr, err := client.GetUser(adminId)
+	if err != nil {
+		return fmt.Errorf("could not read user from remote instance. Error: %v", err)
+	}
+	if !usr.IsGrafanaAdmin {
+		return ErrMustBeAdmin
+	}
+
+	if err := client.UpdateUserPassword(adminId, newPassword); err != nil {
+		return fmt.Errorf("failed to update user password: %w", err)
+	}
+
+	return nil
+}
+
 var ErrMustBeAdmin = fmt.Errorf("reset-admin-password can only be used to reset an admin user account")
@@ -1,10 +1,15 @@
 package commands
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 )
@@ -52,3 +57,34 @@ func TestResetPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestResetPasswordRemote(t *testing.T) {
+	for name, isAdmin := range map[string]bool{"user is an admin": true, "user is not an admin": false} {
+		t.Run(name, func(t *testing.T) {
+			var updated string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/api/users/11":
+					_, _ = fmt.Fprintf(w, `{"id": 11, "isGrafanaAdmin": %t}`, isAdmin)
+				case r.Method == http.MethodPut && r.URL.Path == "/api/admin/users/11/password":
+					var body map[string]string
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					updated = body["password"]
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			t.Cleanup(ts.Close)
+
+			err := resetPasswordRemote(11, "s00pers3cure!", services.NewRemoteClient(ts.URL, "token"))
+			if !isAdmin {
+				require.ErrorIs(t, err, ErrMustBeAdmin)
+				require.Empty(t, updated)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "s00pers3cure!", updated)
+		})
+	}
+}
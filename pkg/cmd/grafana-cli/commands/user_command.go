@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/fatih/color"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/runner"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func listUsersCommand(c utils.CommandLine, runner runner.Runner) error {
+	// the search is filtered by the permissions of the signed in user, so search as a user allowed to read all users
+	signedInUser := accesscontrol.BackgroundUser("cli", 0, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionUsersRead, Scope: accesscontrol.ScopeGlobalUsersAll},
+	})
+
+	result, err := runner.UserService.Search(context.Background(), &user.SearchUsersQuery{
+		SignedInUser: signedInUser,
+		Query:        c.String("query"),
+		Limit:        1000,
+		Page:         1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search users: %w", err)
+	}
+
+	users := make([]*models.RemoteUser, 0, len(result.Users))
+	for _, u := range result.Users {
+		users = append(users, &models.RemoteUser{ID: u.ID, Login: u.Login, Email: u.Email, Name: u.Name, IsAdmin: u.IsAdmin, IsDisabled: u.IsDisabled})
+	}
+	logUsers(users)
+	return nil
+}
+
+func listUsersRemoteCommand(c utils.CommandLine, client *services.RemoteClient) error {
+	users, err := client.SearchUsers(c.String("query"))
+	if err != nil {
+		return fmt.Errorf("failed to search users: %w", err)
+	}
+
+	logUsers(users)
+	return nil
+}
+
+func logUsers(users []*models.RemoteUser) {
+	if len(users) == 0 {
+		logger.Info("no users found\n")
+		return
+	}
+
+	for _, u := range users {
+		flags := ""
+		if u.IsAdmin {
+			flags += color.YellowString(" [admin]")
+		}
+		if u.IsDisabled {
+			flags += color.RedString(" [disabled]")
+		}
+		logger.Infof("%d\t%s\t%s%s\n", u.ID, u.Login, u.Email, flags)
+	}
+}
+
+func createUserArgs(c utils.CommandLine) (models.CreateRemoteUser, error) {
+	cmd := models.CreateRemoteUser{
+		Login:    c.Args().First(),
+		Email:    c.String("email"),
+		Name:     c.String("name"),
+		Password: c.String("password"),
+	}
+	if cmd.Login == "" {
+		return cmd, errors.New("missing login parameter")
+	}
+	if user.Password(cmd.Password).IsWeak() {
+		return cmd, errors.New("password is too short")
+	}
+	return cmd, nil
+}
+
+func createUserCommand(c utils.CommandLine, runner runner.Runner) error {
+	cmd, err := createUserArgs(c)
+	if err != nil {
+		return err
+	}
+
+	usr, err := runner.UserService.Create(context.Background(), &user.CreateUserCommand{
+		Login:    cmd.Login,
+		Email:    cmd.Email,
+		Name:     cmd.Name,
+		Password: cmd.Password,
+		IsAdmin:  c.Bool("admin"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	logger.Infof("User %s created with id %d %s\n", usr.Login, usr.ID, color.GreenString("✔"))
+	return nil
+}
+
+func createUserRemoteCommand(c utils.CommandLine, client *services.RemoteClient) error {
+	cmd, err := createUserArgs(c)
+	if err != nil {
+		return err
+	}
+	id, err := client.CreateUser(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if c.Bool("admin") {
+		if err := client.UpdateUserPermissions(id, true); err != nil {
+			return fmt.Errorf("user %s created with id %d but failed to make it a server admin: %w", cmd.Login, id, err)
+		}
+	}
+
+	logger.Infof("User %s created with id %d %s\n", cmd.Login, id, color.GreenString("✔"))
+	return nil
+}
+
+func deleteUserArgs(c utils.CommandLine) (int64, error) {
+	id, err := strconv.ParseInt(c.Args().First(), 10, 64)
+	if err != nil {
+		return 0, errors.New("missing or invalid user id parameter")
+	}
+	return id, nil
+}
+
+func deleteUserCommand(c utils.CommandLine, runner runner.Runner) error {
+	id, err := deleteUserArgs(c)
+	if err != nil {
+		return err
+	}
+
+	if err := runner.UserService.Delete(context.Background(), &user.DeleteUserCommand{UserID: id}); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	logger.Infof("User %d deleted %s\n", id, color.GreenString("✔"))
+	return nil
+}
+
+func deleteUserRemoteCommand(c utils.CommandLine, client *services.RemoteClient) error {
+	id, err := deleteUserArgs(c)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteUser(id); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	logger.Infof("User %d deleted %s\n", id, color.GreenString("✔"))
+	return nil
+}
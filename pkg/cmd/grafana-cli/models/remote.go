@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// RemoteUser is a user as returned by the HTTP API of a remote Grafana instance.
+type RemoteUser struct {
+	ID             int64  `json:"id"`
+	Login          string `json:"login"`
+	Email          string `json:"email"`
+	Name           string `json:"name"`
+	IsAdmin        bool   `json:"isAdmin"`
+	IsGrafanaAdmin bool   `json:"isGrafanaAdmin"`
+	IsDisabled     bool   `json:"isDisabled"`
+}
+
+type RemoteUserSearch struct {
+	TotalCount int64         `json:"totalCount"`
+	Users      []*RemoteUser `json:"users"`
+}
+
+type CreateRemoteUser struct {
+	Login    string `json:"login"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// MigrationStatus describes the database migrations of a Grafana instance.
+type MigrationStatus struct {
+	Total   int               `json:"total"`
+	Applied int               `json:"applied"`
+	Pending []string          `json:"pending"`
+	Failed  []FailedMigration `json:"failed"`
+}
+
+type FailedMigration struct {
+	ID        string    `json:"id"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/models"
+)
+
+// RemoteClient runs admin operations against the HTTP API of a remote Grafana instance.
+// Requests are authenticated with a service account or API key token of a Grafana server admin.
+type RemoteClient struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewRemoteClient(grafanaURL, token string) *RemoteClient {
+	return &RemoteClient{
+		url:    strings.TrimSuffix(grafanaURL, "/"),
+		token:  token,
+		client: &HttpClient,
+	}
+}
+
+func (c *RemoteClient) GetUser(id int64) (models.RemoteUser, error) {
+	var usr models.RemoteUser
+	err := c.do(http.MethodGet, "/api/users/"+strconv.FormatInt(id, 10), nil, &usr)
+	return usr, err
+}
+
+func (c *RemoteClient) SearchUsers(query string) ([]*models.RemoteUser, error) {
+	params := url.Values{}
+	params.Set("perpage", "1000")
+	params.Set("query", query)
+
+	var result models.RemoteUserSearch
+	if err := c.do(http.MethodGet, "/api/users/search?"+params.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Users, nil
+}
+
+func (c *RemoteClient) CreateUser(cmd models.CreateRemoteUser) (int64, error) {
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	err := c.do(http.MethodPost, "/api/admin/users", cmd, &result)
+	return result.ID, err
+}
+
+func (c *RemoteClient) DeleteUser(id int64) error {
+	return c.do(http.MethodDelete, "/api/admin/users/"+strconv.FormatInt(id, 10), nil, nil)
+}
+
+func (c *RemoteClient) UpdateUserPassword(id int64, password string) error {
+	body := map[string]string{"password": password}
+	return c.do(http.MethodPut, "/api/admin/users/"+strconv.FormatInt(id, 10)+"/password", body, nil)
+}
+
+func (c *RemoteClient) UpdateUserPermissions(id int64, isGrafanaAdmin bool) error {
+	body := map[string]bool{"isGrafanaAdmin": isGrafanaAdmin}
+	return c.do(http.MethodPut, "/api/admin/users/"+strconv.FormatInt(id, 10)+"/permissions", body, nil)
+}
+
+// ListPlugins returns the plugins installed on the remote instance, core plugins excluded.
+func (c *RemoteClient) ListPlugins() ([]models.InstalledPlugin, error) {
+	var result []models.InstalledPlugin
+	err := c.do(http.MethodGet, "/api/plugins?core=0&embedded=0", nil, &result)
+	return result, err
+}
+
+func (c *RemoteClient) InstallPlugin(id, version string) error {
+	body := map[string]string{"version": version}
+	return c.do(http.MethodPost, "/api/plugins/"+url.PathEscape(id)+"/install", body, nil)
+}
+
+func (c *RemoteClient) UninstallPlugin(id string) error {
+	return c.do(http.MethodPost, "/api/plugins/"+url.PathEscape(id)+"/uninstall", nil, nil)
+}
+
+func (c *RemoteClient) MigrationStatus() (models.MigrationStatus, error) {
+	var status models.MigrationStatus
+	err := c.do(http.MethodGet, "/api/admin/migrations", nil, &status)
+	return status, err
+}
+
+func (c *RemoteClient) do(method, path string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	logger.Debugf("%s %s%s\n", method, c.url, path)
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "grafana "+GrafanaVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warnf("Failed to close response body: %v\n", err)
+		}
+	}()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrNotFoundError
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		message := struct {
+			Message string `json:"message"`
+		}{}
+		_ = json.Unmarshal(data, &message)
+		return &BadRequestError{Status: res.Status, Message: message.Message}
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("%v: %w", "failed to decode response", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "invalid API key"}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/admin/migrations":
+			_, _ = w.Write([]byte(`{"total": 3, "applied": 2, "pending": ["add column"], "failed": []}`))
+		case "/api/plugins":
+			assert.Equal(t, "0", r.URL.Query().Get("core"))
+			_, _ = w.Write([]byte(`[{"id": "grafana-clock-panel", "info": {"version": "2.1.0"}}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	t.Run("Decodes the response", func(t *testing.T) {
+		client := NewRemoteClient(ts.URL+"/", "token")
+
+		status, err := client.MigrationStatus()
+		require.NoError(t, err)
+		assert.Equal(t, 3, status.Total)
+		assert.Equal(t, []string{"add column"}, status.Pending)
+
+		plugins, err := client.ListPlugins()
+		require.NoError(t, err)
+		require.Len(t, plugins, 1)
+		assert.Equal(t, "2.1.0", plugins[0].Info.Version)
+	})
+
+	t.Run("Returns ErrNotFoundError if status == 404", func(t *testing.T) {
+		err := NewRemoteClient(ts.URL, "token").UninstallPlugin("unknown")
+		require.ErrorIs(t, err, ErrNotFoundError)
+	})
+
+	t.Run("Returns the message of the error response", func(t *testing.T) {
+		_, err := NewRemoteClient(ts.URL, "wrong").MigrationStatus()
+		var badRequest *BadRequestError
+		require.ErrorAs(t, err, &badRequest)
+		assert.Equal(t, "invalid API key", badRequest.Message)
+	})
+}
@@ -23,6 +23,8 @@ type CommandLine interface {
 	PluginDirectory() string
 	PluginRepoURL() string
 	PluginURL() string
+	RemoteURL() string
+	RemoteToken() string
 }
 
 type ApiClient interface {
@@ -62,3 +64,11 @@ func (c *ContextCommandLine) PluginRepoURL() string {
 func (c *ContextCommandLine) PluginURL() string {
 	return c.String("pluginUrl")
 }
+
+func (c *ContextCommandLine) RemoteURL() string {
+	return c.String("remoteUrl")
+}
+
+func (c *ContextCommandLine) RemoteToken() string {
+	return c.String("remoteToken")
+}
@@ -0,0 +1,333 @@
+// Package jsonpatch applies JSON patches, as defined by RFC 6902, to decoded JSON documents.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidOperation = errors.New("invalid operation")
+	ErrPathNotFound     = errors.New("path not found")
+	ErrTestFailed       = errors.New("test failed")
+)
+
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// Operation is one operation of a JSON patch.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch is a list of operations applied in order.
+type Patch []Operation
+
+// Apply applies the patch to a copy of the document, the document itself is never modified.
+// The document must be made of the types produced by encoding/json, json.Number included.
+func (p Patch) Apply(doc interface{}) (interface{}, error) {
+	doc, err := deepCopy(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		doc, err = apply(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func apply(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case OpAdd:
+		value, err := deepCopy(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpRemove:
+		doc, _, err := remove(doc, path)
+		return doc, err
+	case OpReplace:
+		value, err := deepCopy(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, path); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpMove:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidOperation)
+		}
+		doc, value, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpCopy:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = deepCopy(value); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	case OpTest:
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(value, op.Value) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidOperation, op.Op)
+}
+
+// parsePointer parses a JSON pointer, as defined by RFC 6901.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidOperation, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPathNotFound, token)
+	}
+	if index > length || (!allowEnd && index == length) {
+		return 0, fmt.Errorf("%w: array index %d out of bounds", ErrPathNotFound, index)
+	}
+	return index, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrPathNotFound, token)
+			}
+			doc = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("%w: %q is not in an object or an array", ErrPathNotFound, token)
+		}
+	}
+	return doc, nil
+}
+
+// add returns the document with the value added, as the root and the arrays may be replaced.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), true)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return set(doc, path[:len(path)-1], node)
+	}
+	return nil, fmt.Errorf("%w: %q is not in an object or an array", ErrPathNotFound, token)
+}
+
+// remove returns the document without the value, and the removed value.
+func remove(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the root of the document", ErrInvalidOperation)
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	token := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		value, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: no member %q", ErrPathNotFound, token)
+		}
+		delete(node, token)
+		return doc, value, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[index]
+		node = append(node[:index:index], node[index+1:]...)
+		doc, err = set(doc, path[:len(path)-1], node)
+		return doc, value, err
+	}
+	return nil, nil, fmt.Errorf("%w: %q is not in an object or an array", ErrPathNotFound, token)
+}
+
+// set replaces the value at the path, which must exist.
+func set(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := get(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	token := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+	return doc, nil
+}
+
+func deepCopy(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			c, err := deepCopy(child)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = c
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, child := range v {
+			c, err := deepCopy(child)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = c
+		}
+		return result, nil
+	case nil, bool, string, float64, json.Number:
+		return v, nil
+	}
+
+	// values that weren't decoded from JSON, e.g. ints, are normalized through a JSON round trip
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// equal compares the values as JSON, so that json.Number and float64 values can be compared.
+func equal(a, b interface{}) bool {
+	normalize := func(v interface{}) (interface{}, bool) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		var result interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, false
+		}
+		return result, true
+	}
+
+	na, ok := normalize(a)
+	if !ok {
+		return false
+	}
+	nb, ok := normalize(b)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
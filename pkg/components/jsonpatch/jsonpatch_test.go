@@ -0,0 +1,102 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewBufferString(s))
+	dec.UseNumber()
+	var v interface{}
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+func TestPatch_Apply(t *testing.T) {
+	const doc = `{"id": 2, "title": "CPU", "targets": [{"refId": "A"}, {"refId": "B"}], "a/b": {"~c": 1}}`
+
+	testCases := []struct {
+		desc     string
+		patch    string
+		expected string
+		err      error
+	}{
+		{
+			desc:     "add member",
+			patch:    `[{"op": "add", "path": "/description", "value": "Usage"}]`,
+			expected: `{"id": 2, "title": "CPU", "description": "Usage", "targets": [{"refId": "A"}, {"refId": "B"}], "a/b": {"~c": 1}}`,
+		},
+		{
+			desc:     "add to array",
+			patch:    `[{"op": "add", "path": "/targets/1", "value": {"refId": "C"}}, {"op": "add", "path": "/targets/-", "value": {"refId": "D"}}]`,
+			expected: `{"id": 2, "title": "CPU", "targets": [{"refId": "A"}, {"refId": "C"}, {"refId": "B"}, {"refId": "D"}], "a/b": {"~c": 1}}`,
+		},
+		{
+			desc:     "remove from array",
+			patch:    `[{"op": "remove", "path": "/targets/0"}]`,
+			expected: `{"id": 2, "title": "CPU", "targets": [{"refId": "B"}], "a/b": {"~c": 1}}`,
+		},
+		{
+			desc:     "replace escaped path",
+			patch:    `[{"op": "replace", "path": "/a~1b/~0c", "value": 2}]`,
+			expected: `{"id": 2, "title": "CPU", "targets": [{"refId": "A"}, {"refId": "B"}], "a/b": {"~c": 2}}`,
+		},
+		{
+			desc:     "move and copy",
+			patch:    `[{"op": "move", "from": "/targets/1", "path": "/targets/0"}, {"op": "copy", "from": "/title", "path": "/description"}]`,
+			expected: `{"id": 2, "title": "CPU", "description": "CPU", "targets": [{"refId": "B"}, {"refId": "A"}], "a/b": {"~c": 1}}`,
+		},
+		{
+			desc:     "test numbers",
+			patch:    `[{"op": "test", "path": "/id", "value": 2}, {"op": "replace", "path": "/title", "value": "Memory"}]`,
+			expected: `{"id": 2, "title": "Memory", "targets": [{"refId": "A"}, {"refId": "B"}], "a/b": {"~c": 1}}`,
+		},
+		{
+			desc:  "failed test",
+			patch: `[{"op": "replace", "path": "/title", "value": "Memory"}, {"op": "test", "path": "/id", "value": 3}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			desc:  "replace missing member",
+			patch: `[{"op": "replace", "path": "/description", "value": "Usage"}]`,
+			err:   ErrPathNotFound,
+		},
+		{
+			desc:  "array index out of bounds",
+			patch: `[{"op": "add", "path": "/targets/3", "value": {}}]`,
+			err:   ErrPathNotFound,
+		},
+		{
+			desc:  "move into a child",
+			patch: `[{"op": "move", "from": "/targets", "path": "/targets/0"}]`,
+			err:   ErrInvalidOperation,
+		},
+		{
+			desc:  "unknown operation",
+			patch: `[{"op": "merge", "path": "/title"}]`,
+			err:   ErrInvalidOperation,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var patch Patch
+			require.NoError(t, json.Unmarshal([]byte(tc.patch), &patch))
+
+			original := decode(t, doc)
+			result, err := patch.Apply(original)
+			require.Equal(t, decode(t, doc), original, "the document must not be modified")
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, equal(decode(t, tc.expected), result), "expected %s, got %v", tc.expected, result)
+		})
+	}
+}
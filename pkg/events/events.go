@@ -19,6 +19,12 @@ type OrgUpdated struct {
 	Name      string    `json:"name"`
 }
 
+type OrgDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	Id        int64     `json:"id"`
+	Name      string    `json:"name"`
+}
+
 type UserCreated struct {
 	Timestamp time.Time `json:"timestamp"`
 	Id        int64     `json:"id"`
@@ -78,3 +84,67 @@ type FolderTitleUpdated struct {
 	UID       string    `json:"uid"`
 	OrgID     int64     `json:"org_id"`
 }
+
+type DashboardCreated struct {
+	Timestamp time.Time `json:"timestamp"`
+	Title     string    `json:"name"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+	IsFolder  bool      `json:"is_folder"`
+	Version   int       `json:"version"`
+}
+
+type DashboardUpdated struct {
+	Timestamp time.Time `json:"timestamp"`
+	Title     string    `json:"name"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+	IsFolder  bool      `json:"is_folder"`
+	Version   int       `json:"version"`
+}
+
+type DashboardDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	ID        int64     `json:"id"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+	IsFolder  bool      `json:"is_folder"`
+}
+
+type AlertRuleCreated struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Title        string    `json:"name"`
+	UID          string    `json:"uid"`
+	OrgID        int64     `json:"org_id"`
+	NamespaceUID string    `json:"namespace_uid"`
+	RuleGroup    string    `json:"rule_group"`
+	Version      int64     `json:"version"`
+}
+
+type AlertRuleUpdated struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Title        string    `json:"name"`
+	UID          string    `json:"uid"`
+	OrgID        int64     `json:"org_id"`
+	NamespaceUID string    `json:"namespace_uid"`
+	RuleGroup    string    `json:"rule_group"`
+	Version      int64     `json:"version"`
+}
+
+type AlertRuleDeleted struct {
+	Timestamp time.Time `json:"timestamp"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+}
+
+// AlertFired is published when an alert instance of a Grafana managed rule starts firing.
+type AlertFired struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	RuleUID     string            `json:"rule_uid"`
+	OrgID       int64             `json:"org_id"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"starts_at"`
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/secrets"
 )
 
 var (
@@ -154,6 +155,6 @@ func hiddenRefIDs(queries []Query) (map[string]struct{}, error) {
 
 func (s *Service) decryptSecureJsonDataFn(ctx context.Context) func(ds *datasources.DataSource) (map[string]string, error) {
 	return func(ds *datasources.DataSource) (map[string]string, error) {
-		return s.dataSourceService.DecryptedValues(ctx, ds)
+		return s.dataSourceService.DecryptedValues(secrets.WithConsumer(ctx, "expressions"), ds)
 	}
 }
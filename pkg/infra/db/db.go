@@ -20,6 +20,8 @@ type DB interface {
 	GetSqlxSession() *session.SessionDB
 	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	Quote(value string) string
+	// RecursiveQueriesAreSupported returns whether the database supports recursive common table expressions.
+	RecursiveQueriesAreSupported() (bool, error)
 }
 
 type Session = sqlstore.DBSession
@@ -0,0 +1,23 @@
+package distlock
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Get("/api/admin/locks", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleListHolders))
+}
+
+// handleListHolders lists the locks with the instances holding them and the number of instances waiting for them.
+func (s *Service) handleListHolders(c *contextmodel.ReqContext) response.Response {
+	holders, err := s.Holders(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list locks", err)
+	}
+	return response.JSON(http.StatusOK, holders)
+}
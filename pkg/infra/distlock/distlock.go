@@ -0,0 +1,354 @@
+// Package distlock provides locks shared by the Grafana instances of a high availability setup, backed by the database.
+//
+// Unlike the serverlock package, a lock is held for as long as its holder works under it: it is renewed in the
+// background and expires only if its holder stops renewing it, for example because its instance is gone. The
+// instances waiting for a lock are granted it in the order they started waiting.
+package distlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	// ErrLockHeld is returned when a lock is held by another holder, or other holders wait for it.
+	ErrLockHeld = errors.New("lock is held by another holder")
+	// ErrLockLost is returned when a lock expired before its holder released it.
+	ErrLockLost = errors.New("lock was lost before being released")
+)
+
+const defaultTTL = 30 * time.Second
+
+type Service struct {
+	store   *store
+	tracer  tracing.Tracer
+	log     log.Logger
+	metrics *metrics
+	// holder identifies this instance in the locks it holds
+	holder       string
+	ttl          time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, tracer tracing.Tracer, registerer prometheus.Registerer, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		store:   &store{db: sqlStore},
+		tracer:  tracer,
+		log:     log.New("infra.distlock"),
+		metrics: newMetrics(registerer),
+		holder:  holderID(),
+		ttl:     cfg.SectionWithEnvOverrides("distributed_lock").Key("ttl").MustDuration(defaultTTL),
+		now:     time.Now,
+	}
+	if s.ttl < time.Second {
+		s.log.Warn("Distributed lock TTL is too low, using the default", "ttl", s.ttl, "default", defaultTTL)
+		s.ttl = defaultTTL
+	}
+	s.pollInterval = s.ttl / 10
+
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// holderID identifies the instance by its hostname, and its process in case several instances run on the same host.
+func holderID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%s", hostname, uuid.NewString()[:8])
+}
+
+// Lock is a lock held by this instance. It is renewed in the background until it is released.
+type Lock struct {
+	Name string
+
+	s             *Service
+	token         string
+	acquiredAt    time.Time
+	lastCompleted time.Time
+	lost          chan struct{}
+	stop          chan struct{}
+	done          chan struct{}
+	releaseOnce   sync.Once
+	releaseErr    error
+}
+
+// TryLock acquires the lock without waiting. It returns ErrLockHeld if another holder holds the lock or waits for it.
+func (s *Service) TryLock(ctx context.Context, name string) (*Lock, error) {
+	ctx, span := s.tracer.Start(ctx, "distlock.TryLock")
+	span.SetAttributes("distlock.name", name, attribute.Key("distlock.name").String(name))
+	defer span.End()
+
+	lock, err := s.acquire(ctx, name, noTicket)
+	if err != nil {
+		span.RecordError(err)
+		s.metrics.acquisitions.WithLabelValues(name, "error").Inc()
+		return nil, err
+	}
+	if lock == nil {
+		s.metrics.acquisitions.WithLabelValues(name, "held").Inc()
+		return nil, ErrLockHeld
+	}
+	s.metrics.acquisitions.WithLabelValues(name, "acquired").Inc()
+	return lock, nil
+}
+
+// Lock waits until the lock is acquired or the context is done. The holders waiting for a lock are granted it
+// in the order they started waiting.
+func (s *Service) Lock(ctx context.Context, name string) (*Lock, error) {
+	ctx, span := s.tracer.Start(ctx, "distlock.Lock")
+	span.SetAttributes("distlock.name", name, attribute.Key("distlock.name").String(name))
+	defer span.End()
+
+	start := s.now()
+	lock, err := s.wait(ctx, name)
+	if err != nil {
+		span.RecordError(err)
+		s.metrics.acquisitions.WithLabelValues(name, "error").Inc()
+		return nil, err
+	}
+	s.metrics.acquisitions.WithLabelValues(name, "acquired").Inc()
+	s.metrics.waitDuration.WithLabelValues(name).Observe(s.now().Sub(start).Seconds())
+	return lock, nil
+}
+
+func (s *Service) wait(ctx context.Context, name string) (*Lock, error) {
+	if err := s.store.ensure(ctx, name); err != nil {
+		return nil, err
+	}
+	ticket, err := s.store.enqueue(ctx, name, s.holder, s.now(), s.ttl)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// the ticket must leave the queue even if the context is done
+		if err := s.store.dequeue(context.Background(), ticket); err != nil {
+			s.log.Warn("Failed to leave the queue of a lock, other holders will wait for it to expire", "name", name, "error", err)
+		}
+	}()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		lock, err := s.acquire(ctx, name, ticket)
+		if err != nil || lock != nil {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := s.store.heartbeat(ctx, ticket, s.now()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *Service) acquire(ctx context.Context, name string, ticket int64) (*Lock, error) {
+	if ticket == noTicket {
+		if err := s.store.ensure(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	token := uuid.NewString()
+	row, err := s.store.acquire(ctx, name, s.holder, token, ticket, s.now(), s.ttl)
+	if err != nil || row == nil {
+		return nil, err
+	}
+
+	lock := &Lock{
+		Name:       name,
+		s:          s,
+		token:      token,
+		acquiredAt: time.UnixMilli(row.AcquiredAt),
+		lost:       make(chan struct{}),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if row.LastCompleted > 0 {
+		lock.lastCompleted = time.UnixMilli(row.LastCompleted)
+	}
+	s.log.Debug("Acquired lock", "name", name, "holder", s.holder)
+	go lock.renew()
+	return lock, nil
+}
+
+// Lost is closed when the lock expired before being released, the work done under the lock must then stop.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// LastCompleted returns the time the work done under the lock was last completed, by any holder.
+func (l *Lock) LastCompleted() time.Time {
+	return l.lastCompleted
+}
+
+// Complete records the completion of the work done under the lock.
+func (l *Lock) Complete(ctx context.Context) error {
+	completed, err := l.s.store.complete(ctx, l.Name, l.token, l.s.now())
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release stops renewing the lock and frees it for the other holders.
+func (l *Lock) Release(ctx context.Context) error {
+	l.releaseOnce.Do(func() {
+		close(l.stop)
+		<-l.done
+
+		l.s.metrics.heldDuration.WithLabelValues(l.Name).Observe(l.s.now().Sub(l.acquiredAt).Seconds())
+		released, err := l.s.store.release(ctx, l.Name, l.token)
+		switch {
+		case err != nil:
+			l.releaseErr = err
+		case !released:
+			l.releaseErr = ErrLockLost
+		default:
+			l.s.log.Debug("Released lock", "name", l.Name, "holder", l.s.holder)
+		}
+	})
+	return l.releaseErr
+}
+
+// renew extends the expiration of the lock until it is released. The lock is lost when another holder acquired
+// it, or when it could not be renewed before it expired.
+func (l *Lock) renew() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.s.ttl / 3)
+	defer ticker.Stop()
+	expiresAt := l.acquiredAt.Add(l.s.ttl)
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		next := l.s.now().Add(l.s.ttl)
+		renewed, err := l.s.store.renew(context.Background(), l.Name, l.token, next)
+		if err == nil && renewed {
+			expiresAt = next
+			continue
+		}
+		if err != nil && l.s.now().Before(expiresAt) {
+			l.s.log.Warn("Failed to renew lock, retrying", "name", l.Name, "expiresAt", expiresAt, "error", err)
+			continue
+		}
+
+		l.s.log.Error("Lost lock", "name", l.Name, "holder", l.s.holder, "error", err)
+		l.s.metrics.lost.WithLabelValues(l.Name).Inc()
+		close(l.lost)
+		return
+	}
+}
+
+// RunOnce runs fn under the lock, unless another holder holds it or the work was completed less than interval ago
+// by any holder. It lets the instances of a high availability setup share periodic jobs instead of all running them.
+// The context of fn is cancelled if the lock is lost, the work is then not recorded as completed.
+func (s *Service) RunOnce(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context)) error {
+	lock, err := s.TryLock(ctx, name)
+	if errors.Is(err, ErrLockHeld) {
+		s.metrics.runs.WithLabelValues(name, "held").Inc()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Release(context.Background()); err != nil && !errors.Is(err, ErrLockLost) {
+			s.log.Warn("Failed to release lock", "name", name, "error", err)
+		}
+	}()
+
+	if interval > 0 && s.now().Sub(lock.LastCompleted()) < interval {
+		s.metrics.runs.WithLabelValues(name, "recent").Inc()
+		return nil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lock.Lost():
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	fn(runCtx)
+
+	select {
+	case <-lock.Lost():
+		s.metrics.runs.WithLabelValues(name, "lost").Inc()
+		return ErrLockLost
+	default:
+	}
+	s.metrics.runs.WithLabelValues(name, "completed").Inc()
+	return lock.Complete(ctx)
+}
+
+// Holder is the state of a lock, as shown by the inspection endpoint.
+type Holder struct {
+	Name string `json:"name"`
+	// Holder is the instance holding the lock, empty if the lock is free
+	Holder        string     `json:"holder"`
+	AcquiredAt    *time.Time `json:"acquiredAt,omitempty"`
+	ExpiresAt     *time.Time `json:"expiresAt,omitempty"`
+	LastCompleted *time.Time `json:"lastCompleted,omitempty"`
+	Waiters       int64      `json:"waiters"`
+}
+
+// Holders returns the state of all the locks.
+func (s *Service) Holders(ctx context.Context) ([]Holder, error) {
+	now := s.now()
+	locks, waiters, err := s.store.list(ctx, now, s.ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	holders := make([]Holder, 0, len(locks))
+	for _, lock := range locks {
+		h := Holder{Name: lock.Name, Waiters: waiters[lock.Name]}
+		if lock.ExpiresAt >= now.UnixMilli() {
+			h.Holder = lock.Holder
+			h.AcquiredAt = timeRef(lock.AcquiredAt)
+			h.ExpiresAt = timeRef(lock.ExpiresAt)
+		}
+		if lock.LastCompleted > 0 {
+			h.LastCompleted = timeRef(lock.LastCompleted)
+		}
+		holders = append(holders, h)
+	}
+	return holders, nil
+}
+
+func timeRef(ms int64) *time.Time {
+	t := time.UnixMilli(ms).UTC()
+	return &t
+}
@@ -0,0 +1,180 @@
+package distlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationDistributedLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	// setup returns services sharing a database, as the instances of a high availability setup
+	setup := func(t *testing.T, instances int) []*Service {
+		sqlStore := db.InitTestDB(t)
+		services := make([]*Service, 0, instances)
+		for i := 0; i < instances; i++ {
+			s := ProvideService(setting.NewCfg(), sqlStore, tracing.InitializeTracerForTest(), prometheus.NewRegistry(), routing.NewRouteRegister())
+			s.ttl = time.Second
+			s.pollInterval = 20 * time.Millisecond
+			services = append(services, s)
+		}
+		return services
+	}
+
+	t.Run("A lock is held by one holder at a time", func(t *testing.T) {
+		s := setup(t, 2)
+
+		lock, err := s[0].TryLock(ctx, "test")
+		require.NoError(t, err)
+		_, err = s[1].TryLock(ctx, "test")
+		require.ErrorIs(t, err, ErrLockHeld)
+
+		require.NoError(t, lock.Release(ctx))
+		lock, err = s[1].TryLock(ctx, "test")
+		require.NoError(t, err)
+		require.NoError(t, lock.Release(ctx))
+	})
+
+	t.Run("A lock is renewed while it is held", func(t *testing.T) {
+		s := setup(t, 2)
+
+		lock, err := s[0].TryLock(ctx, "test")
+		require.NoError(t, err)
+		time.Sleep(2 * s[0].ttl)
+
+		_, err = s[1].TryLock(ctx, "test")
+		require.ErrorIs(t, err, ErrLockHeld)
+		require.NoError(t, lock.Release(ctx))
+	})
+
+	t.Run("A lock which expired is lost by its holder", func(t *testing.T) {
+		s := setup(t, 2)
+
+		lock, err := s[0].TryLock(ctx, "test")
+		require.NoError(t, err)
+		expire(t, s[0], "test")
+
+		other, err := s[1].TryLock(ctx, "test")
+		require.NoError(t, err)
+		select {
+		case <-lock.Lost():
+		case <-time.After(2 * s[0].ttl):
+			t.Fatal("the lock was not lost")
+		}
+		require.ErrorIs(t, lock.Release(ctx), ErrLockLost)
+		require.NoError(t, other.Release(ctx))
+	})
+
+	t.Run("A lock is granted to the holders waiting for it before the others", func(t *testing.T) {
+		s := setup(t, 3)
+
+		lock, err := s[0].TryLock(ctx, "test")
+		require.NoError(t, err)
+
+		acquired := make(chan *Lock)
+		go func() {
+			waited, err := s[1].Lock(ctx, "test")
+			require.NoError(t, err)
+			acquired <- waited
+		}()
+		require.Eventually(t, func() bool {
+			holders, err := s[0].Holders(ctx)
+			require.NoError(t, err)
+			return len(holders) == 1 && holders[0].Waiters == 1
+		}, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, lock.Release(ctx))
+		_, err = s[2].TryLock(ctx, "test")
+		require.ErrorIs(t, err, ErrLockHeld)
+
+		waited := <-acquired
+		holders, err := s[0].Holders(ctx)
+		require.NoError(t, err)
+		require.Equal(t, s[1].holder, holders[0].Holder)
+		require.Zero(t, holders[0].Waiters)
+		require.NoError(t, waited.Release(ctx))
+	})
+
+	t.Run("Waiting for a lock stops when the context is done", func(t *testing.T) {
+		s := setup(t, 2)
+
+		lock, err := s[0].TryLock(ctx, "test")
+		require.NoError(t, err)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		_, err = s[1].Lock(waitCtx, "test")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		holders, err := s[0].Holders(ctx)
+		require.NoError(t, err)
+		require.Zero(t, holders[0].Waiters)
+		require.NoError(t, lock.Release(ctx))
+	})
+
+	t.Run("A job is run once per interval across the holders", func(t *testing.T) {
+		s := setup(t, 2)
+
+		runs := 0
+		fn := func(context.Context) { runs++ }
+		require.NoError(t, s[0].RunOnce(ctx, "job", time.Hour, fn))
+		require.NoError(t, s[1].RunOnce(ctx, "job", time.Hour, fn))
+		require.Equal(t, 1, runs)
+
+		require.NoError(t, s[1].RunOnce(ctx, "job", 0, fn))
+		require.Equal(t, 2, runs)
+
+		holders, err := s[0].Holders(ctx)
+		require.NoError(t, err)
+		require.Empty(t, holders[0].Holder)
+		require.NotNil(t, holders[0].LastCompleted)
+	})
+
+	t.Run("A job is skipped while another holder runs it", func(t *testing.T) {
+		s := setup(t, 2)
+
+		runs := 0
+		err := s[0].RunOnce(ctx, "job", time.Hour, func(ctx context.Context) {
+			require.NoError(t, s[1].RunOnce(ctx, "job", time.Hour, func(context.Context) { runs++ }))
+		})
+		require.NoError(t, err)
+		require.Zero(t, runs)
+	})
+
+	t.Run("A job is cancelled and not completed when its lock is lost", func(t *testing.T) {
+		s := setup(t, 2)
+
+		err := s[0].RunOnce(ctx, "job", time.Hour, func(ctx context.Context) {
+			expire(t, s[0], "job")
+			lock, err := s[1].TryLock(context.Background(), "job")
+			require.NoError(t, err)
+			defer func() { require.NoError(t, lock.Release(context.Background())) }()
+			<-ctx.Done()
+		})
+		require.ErrorIs(t, err, ErrLockLost)
+
+		holders, err := s[0].Holders(ctx)
+		require.NoError(t, err)
+		require.Nil(t, holders[0].LastCompleted)
+	})
+}
+
+// expire makes the lock expire as if its holder stopped renewing it, the holder can't renew it anymore.
+func expire(t *testing.T, s *Service, name string) {
+	t.Helper()
+
+	_, err := s.store.update(context.Background(), `UPDATE distributed_lock SET expires_at = 1, token = 'expired' WHERE name = ?`, name)
+	require.NoError(t, err)
+}
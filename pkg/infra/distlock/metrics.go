@@ -0,0 +1,52 @@
+package distlock
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type metrics struct {
+	acquisitions *prometheus.CounterVec
+	runs         *prometheus.CounterVec
+	lost         *prometheus.CounterVec
+	waitDuration *prometheus.HistogramVec
+	heldDuration *prometheus.HistogramVec
+}
+
+func newMetrics(registerer prometheus.Registerer) *metrics {
+	factory := promauto.With(registerer)
+	return &metrics{
+		acquisitions: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "distributed_lock",
+			Name:      "acquisitions_total",
+			Help:      "Number of attempts to acquire a lock, by lock and result.",
+		}, []string{"name", "result"}),
+		runs: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "distributed_lock",
+			Name:      "runs_total",
+			Help:      "Number of jobs run once across the instances, by lock and result.",
+		}, []string{"name", "result"}),
+		lost: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "distributed_lock",
+			Name:      "lost_total",
+			Help:      "Number of locks which expired before being released, by lock.",
+		}, []string{"name"}),
+		waitDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "grafana",
+			Subsystem: "distributed_lock",
+			Name:      "wait_duration_seconds",
+			Help:      "Time waited to acquire a lock, by lock.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 8),
+		}, []string{"name"}),
+		heldDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "grafana",
+			Subsystem: "distributed_lock",
+			Name:      "held_duration_seconds",
+			Help:      "Time a lock was held before being released, by lock.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 8),
+		}, []string{"name"}),
+	}
+}
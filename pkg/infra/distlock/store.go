@@ -0,0 +1,173 @@
+package distlock
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type distributedLock struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	Name          string `xorm:"name"`
+	Holder        string `xorm:"holder"`
+	Token         string `xorm:"token"`
+	AcquiredAt    int64  `xorm:"acquired_at"`
+	ExpiresAt     int64  `xorm:"expires_at"`
+	LastCompleted int64  `xorm:"last_completed"`
+}
+
+func (distributedLock) TableName() string { return "distributed_lock" }
+
+type distributedLockWaiter struct {
+	ID          int64  `xorm:"pk autoincr 'id'"`
+	Name        string `xorm:"name"`
+	Holder      string `xorm:"holder"`
+	HeartbeatAt int64  `xorm:"heartbeat_at"`
+}
+
+func (distributedLockWaiter) TableName() string { return "distributed_lock_waiter" }
+
+// noTicket is the ticket of the acquisitions which don't wait, they are granted the lock only if nobody waits for it.
+const noTicket = math.MaxInt64
+
+type store struct {
+	db db.DB
+}
+
+// ensure creates the row of the lock if it doesn't exist yet.
+func (s *store) ensure(ctx context.Context, name string) error {
+	exists, err := s.exists(ctx, name)
+	if err != nil || exists {
+		return err
+	}
+	err = s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(&distributedLock{Name: name})
+		return err
+	})
+	if err != nil {
+		// another instance may have created it concurrently
+		if exists, existsErr := s.exists(ctx, name); existsErr == nil && exists {
+			return nil
+		}
+	}
+	return err
+}
+
+func (s *store) exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Exist(&distributedLock{Name: name})
+		return err
+	})
+	return exists, err
+}
+
+// acquire grants the lock if it is expired and no live waiter holds a ticket older than the given one.
+// It returns the lock row once acquired, nil otherwise.
+func (s *store) acquire(ctx context.Context, name, holder, token string, ticket int64, now time.Time, ttl time.Duration) (*distributedLock, error) {
+	var acquired *distributedLock
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec(`UPDATE distributed_lock SET holder = ?, token = ?, acquired_at = ?, expires_at = ?
+			WHERE name = ? AND expires_at < ?
+			AND NOT EXISTS (SELECT 1 FROM distributed_lock_waiter WHERE name = ? AND id < ? AND heartbeat_at >= ?)`,
+			holder, token, now.UnixMilli(), now.Add(ttl).UnixMilli(),
+			name, now.UnixMilli(),
+			name, ticket, now.Add(-ttl).UnixMilli())
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected != 1 {
+			return err
+		}
+
+		lock := &distributedLock{}
+		if _, err := sess.Where("name = ?", name).Get(lock); err != nil {
+			return err
+		}
+		acquired = lock
+		return nil
+	})
+	return acquired, err
+}
+
+// renew extends the expiration of the lock, it returns false if the lock isn't held with the token anymore.
+func (s *store) renew(ctx context.Context, name, token string, expiresAt time.Time) (bool, error) {
+	return s.update(ctx, `UPDATE distributed_lock SET expires_at = ? WHERE name = ? AND token = ?`, expiresAt.UnixMilli(), name, token)
+}
+
+// complete records the completion of the work done under the lock.
+func (s *store) complete(ctx context.Context, name, token string, now time.Time) (bool, error) {
+	return s.update(ctx, `UPDATE distributed_lock SET last_completed = ? WHERE name = ? AND token = ?`, now.UnixMilli(), name, token)
+}
+
+func (s *store) release(ctx context.Context, name, token string) (bool, error) {
+	return s.update(ctx, `UPDATE distributed_lock SET holder = '', token = '', expires_at = 0 WHERE name = ? AND token = ?`, name, token)
+}
+
+func (s *store) update(ctx context.Context, sql string, args ...interface{}) (bool, error) {
+	var updated bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec(append([]interface{}{sql}, args...)...)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		updated = affected == 1
+		return err
+	})
+	return updated, err
+}
+
+// enqueue adds a waiter for the lock and returns its ticket, the waiters which stopped waiting without
+// leaving the queue are removed.
+func (s *store) enqueue(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (int64, error) {
+	waiter := &distributedLockWaiter{Name: name, Holder: holder, HeartbeatAt: now.UnixMilli()}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec(`DELETE FROM distributed_lock_waiter WHERE name = ? AND heartbeat_at < ?`, name, now.Add(-ttl).UnixMilli()); err != nil {
+			return err
+		}
+		_, err := sess.Insert(waiter)
+		return err
+	})
+	return waiter.ID, err
+}
+
+func (s *store) heartbeat(ctx context.Context, ticket int64, now time.Time) error {
+	_, err := s.update(ctx, `UPDATE distributed_lock_waiter SET heartbeat_at = ? WHERE id = ?`, now.UnixMilli(), ticket)
+	return err
+}
+
+func (s *store) dequeue(ctx context.Context, ticket int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec(`DELETE FROM distributed_lock_waiter WHERE id = ?`, ticket)
+		return err
+	})
+}
+
+// list returns the locks with the number of their live waiters.
+func (s *store) list(ctx context.Context, now time.Time, ttl time.Duration) ([]distributedLock, map[string]int64, error) {
+	var locks []distributedLock
+	waiters := map[string]int64{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if err := sess.OrderBy("name").Find(&locks); err != nil {
+			return err
+		}
+
+		var counts []struct {
+			Name  string `xorm:"name"`
+			Count int64  `xorm:"count"`
+		}
+		if err := sess.SQL(`SELECT name, COUNT(*) AS count FROM distributed_lock_waiter WHERE heartbeat_at >= ? GROUP BY name`,
+			now.Add(-ttl).UnixMilli()).Find(&counts); err != nil {
+			return err
+		}
+		for _, c := range counts {
+			waiters[c.Name] = c.Count
+		}
+		return nil
+	})
+	return locks, waiters, err
+}
@@ -0,0 +1,113 @@
+package httpclientprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+)
+
+const CircuitBreakerMiddlewareName = "circuit-breaker"
+
+// ErrCircuitOpen is returned without sending the request when the host failed too many times in a row.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreakerMiddleware fails the requests to a host immediately once it failed maxFailures times in a row,
+// so that the requests to a slow or unavailable host do not pile up. After openDuration, a single request probes
+// the host: its success closes the circuit. The failures are the errors and the 5xx responses, a maxFailures of 0
+// disables the middleware. The clients created with the same middleware share the state of the hosts.
+func CircuitBreakerMiddleware(maxFailures int, openDuration time.Duration) sdkhttpclient.Middleware {
+	breaker := &circuitBreaker{
+		maxFailures:  maxFailures,
+		openDuration: openDuration,
+		now:          time.Now,
+		hosts:        make(map[string]*circuit),
+	}
+
+	return sdkhttpclient.NamedMiddlewareFunc(CircuitBreakerMiddlewareName, func(opts sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		if maxFailures <= 0 {
+			return next
+		}
+
+		return sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			host := req.URL.Host
+			if !breaker.allow(host) {
+				return nil, fmt.Errorf("%w: %s failed %d times in a row", ErrCircuitOpen, host, maxFailures)
+			}
+
+			res, err := next.RoundTrip(req)
+			// the requests canceled by the caller say nothing about the host
+			if !errors.Is(req.Context().Err(), context.Canceled) {
+				breaker.record(host, err != nil || res.StatusCode >= http.StatusInternalServerError)
+			} else {
+				breaker.release(host)
+			}
+			return res, err
+		})
+	})
+}
+
+type circuitBreaker struct {
+	maxFailures  int
+	openDuration time.Duration
+	now          func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *circuitBreaker) allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.hosts[host]
+	if !ok || c.failures < b.maxFailures {
+		return true
+	}
+	if c.probing || b.now().Before(c.openUntil) {
+		return false
+	}
+	c.probing = true
+	return true
+}
+
+func (b *circuitBreaker) record(host string, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		delete(b.hosts, host)
+		return
+	}
+
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	c.probing = false
+	c.failures++
+	if c.failures >= b.maxFailures {
+		c.openUntil = b.now().Add(b.openDuration)
+	}
+}
+
+// release lets another request probe the host when the probe was canceled.
+func (b *circuitBreaker) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.hosts[host]; ok {
+		c.probing = false
+	}
+}
@@ -0,0 +1,70 @@
+package httpclientprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	calls := 0
+	status := http.StatusInternalServerError
+	finalRoundTripper := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: status, Request: req, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+
+	mw := CircuitBreakerMiddleware(2, 50*time.Millisecond)
+	rt := mw.CreateMiddleware(httpclient.Options{}, finalRoundTripper)
+	middlewareName, ok := mw.(httpclient.MiddlewareName)
+	require.True(t, ok)
+	require.Equal(t, CircuitBreakerMiddlewareName, middlewareName.MiddlewareName())
+
+	roundTrip := func(t *testing.T, url string) error {
+		t.Helper()
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		if err == nil {
+			require.NoError(t, res.Body.Close())
+		}
+		return err
+	}
+
+	t.Run("should open the circuit of a host after consecutive failures", func(t *testing.T) {
+		require.NoError(t, roundTrip(t, "http://failing.com/query"))
+		require.NoError(t, roundTrip(t, "http://failing.com/query"))
+		require.Equal(t, 2, calls)
+
+		err := roundTrip(t, "http://failing.com/query")
+		require.True(t, errors.Is(err, ErrCircuitOpen))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("should not open the circuit of the other hosts", func(t *testing.T) {
+		require.NoError(t, roundTrip(t, "http://other.com/query"))
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("should close the circuit once a probe succeeds", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+		status = http.StatusOK
+
+		require.NoError(t, roundTrip(t, "http://failing.com/query"))
+		require.NoError(t, roundTrip(t, "http://failing.com/query"))
+		require.Equal(t, 5, calls)
+	})
+
+	t.Run("should be disabled without a maximum number of failures", func(t *testing.T) {
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) { return nil, nil })
+		rt := CircuitBreakerMiddleware(0, time.Minute).CreateMiddleware(httpclient.Options{}, next)
+		require.NotNil(t, rt)
+	})
+}
@@ -86,7 +86,8 @@ func executeMiddleware(next http.RoundTripper, datasourceLabel prometheus.Labels
 
 		res, err := promhttp.InstrumentRoundTripperDuration(requestHistogram,
 			promhttp.InstrumentRoundTripperCounter(requestCounter,
-				promhttp.InstrumentRoundTripperInFlight(requestInFlight, next))).
+				promhttp.InstrumentRoundTripperInFlight(requestInFlight, next)),
+			promhttp.WithExemplarFromContext(metricutil.ExemplarLabels)).
 			RoundTrip(r)
 		if err != nil {
 			return nil, err
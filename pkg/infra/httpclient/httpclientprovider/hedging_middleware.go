@@ -0,0 +1,117 @@
+package httpclientprovider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+)
+
+const HedgingMiddlewareName = "hedging"
+
+// HedgingMiddleware sends an idempotent request again when it has no response after delay, or right away when it
+// failed, until maxAttempts requests are sent. The first response is returned and the other requests are canceled.
+// A delay of 0 disables the middleware.
+func HedgingMiddleware(delay time.Duration, maxAttempts int) sdkhttpclient.Middleware {
+	return sdkhttpclient.NamedMiddlewareFunc(HedgingMiddlewareName, func(opts sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		if delay <= 0 || maxAttempts <= 1 {
+			return next
+		}
+
+		return sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !hedgeable(req) {
+				return next.RoundTrip(req)
+			}
+			return hedge(req, next, delay, maxAttempts)
+		})
+	})
+}
+
+// hedgeable returns true for the requests without body that do not modify the upstream.
+func hedgeable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody
+	default:
+		return false
+	}
+}
+
+type hedgedResult struct {
+	res     *http.Response
+	err     error
+	attempt int
+}
+
+func hedge(req *http.Request, next http.RoundTripper, delay time.Duration, maxAttempts int) (*http.Response, error) {
+	results := make(chan hedgedResult, maxAttempts)
+	cancels := make([]context.CancelFunc, 0, maxAttempts)
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		attempt := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			res, err := next.RoundTrip(req.Clone(ctx))
+			results <- hedgedResult{res: res, err: err, attempt: attempt}
+		}()
+	}
+	// cancelOthers cancels the other requests and closes their responses once they are received.
+	cancelOthers := func(winner int, pending int) {
+		for i, cancel := range cancels {
+			if i != winner {
+				cancel()
+			}
+		}
+		go func() {
+			for ; pending > 0; pending-- {
+				if r := <-results; r.err == nil {
+					_ = r.res.Body.Close()
+				}
+			}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	send()
+	received := 0
+	for {
+		select {
+		case r := <-results:
+			received++
+			if r.err == nil {
+				cancelOthers(r.attempt, len(cancels)-received)
+				r.res.Body = &cancelOnCloseBody{ReadCloser: r.res.Body, cancel: cancels[r.attempt]}
+				return r.res, nil
+			}
+			if req.Context().Err() != nil || len(cancels) == maxAttempts {
+				if received == len(cancels) {
+					cancelOthers(-1, 0)
+					return nil, r.err
+				}
+				continue
+			}
+			send()
+		case <-timer.C:
+			if len(cancels) < maxAttempts {
+				send()
+				timer.Reset(delay)
+			}
+		}
+	}
+}
+
+// cancelOnCloseBody cancels the context of the request once its response is read.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,97 @@
+package httpclientprovider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedgingMiddleware(t *testing.T) {
+	mw := HedgingMiddleware(20*time.Millisecond, 3)
+	middlewareName, ok := mw.(httpclient.MiddlewareName)
+	require.True(t, ok)
+	require.Equal(t, HedgingMiddlewareName, middlewareName.MiddlewareName())
+
+	// respond returns the response of the attempt after its delay, or the error of the canceled request.
+	respond := func(attempts *int32, delays ...time.Duration) http.RoundTripper {
+		return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			attempt := atomic.AddInt32(attempts, 1)
+			select {
+			case <-time.After(delays[attempt-1]):
+				return &http.Response{StatusCode: http.StatusOK, Request: req, Body: io.NopCloser(strings.NewReader(req.Method))}, nil
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			}
+		})
+	}
+
+	t.Run("should return the response of the hedged request when the first request is slow", func(t *testing.T) {
+		var attempts int32
+		rt := mw.CreateMiddleware(httpclient.Options{}, respond(&attempts, time.Second, 0, time.Second))
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://test.com/query", nil)
+		require.NoError(t, err)
+		start := time.Now()
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.MethodGet, string(body))
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("should retry a failed request right away", func(t *testing.T) {
+		var attempts int32
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return &http.Response{StatusCode: http.StatusOK, Request: req, Body: io.NopCloser(strings.NewReader(""))}, nil
+		})
+		rt := mw.CreateMiddleware(httpclient.Options{}, next)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://test.com/query", nil)
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("should return the last error when all the attempts fail", func(t *testing.T) {
+		var attempts int32
+		next := httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			return nil, errors.New("connection refused")
+		})
+		rt := mw.CreateMiddleware(httpclient.Options{}, next)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://test.com/query", nil)
+		require.NoError(t, err)
+		_, err = rt.RoundTrip(req)
+		require.EqualError(t, err, "connection refused")
+		require.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	})
+
+	t.Run("should not hedge the requests that are not idempotent", func(t *testing.T) {
+		var attempts int32
+		rt := mw.CreateMiddleware(httpclient.Options{}, respond(&attempts, 50*time.Millisecond))
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://test.com/query", strings.NewReader("{}"))
+		require.NoError(t, err)
+		res, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	})
+}
@@ -0,0 +1,55 @@
+package httpclientprovider
+
+import (
+	"net/http"
+
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	outboundRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grafana",
+			Name:      "outbound_request_total",
+			Help:      "A counter for outgoing requests per client and host",
+		},
+		[]string{"client", "host", "code", "method"},
+	)
+
+	outboundRequestHistogram = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "grafana",
+			Name:      "outbound_request_duration_seconds",
+			Help:      "histogram of durations of outgoing requests per client and host",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25, 50, 100},
+		}, []string{"client", "host", "code", "method"},
+	)
+
+	outboundRequestsInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Name:      "outbound_request_in_flight",
+			Help:      "A gauge of outgoing requests currently being sent per client and host",
+		},
+		[]string{"client", "host"},
+	)
+)
+
+const HostMetricsMiddlewareName = "host-metrics"
+
+// HostMetricsMiddleware counts and times the requests per host, the client labels the metrics with the
+// component sending the requests.
+func HostMetricsMiddleware(client string) sdkhttpclient.Middleware {
+	return sdkhttpclient.NamedMiddlewareFunc(HostMetricsMiddlewareName, func(opts sdkhttpclient.Options, next http.RoundTripper) http.RoundTripper {
+		return sdkhttpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			labels := prometheus.Labels{"client": client, "host": req.URL.Host}
+			return promhttp.InstrumentRoundTripperDuration(outboundRequestHistogram.MustCurryWith(labels),
+				promhttp.InstrumentRoundTripperCounter(outboundRequestCounter.MustCurryWith(labels),
+					promhttp.InstrumentRoundTripperInFlight(outboundRequestsInFlight.With(labels), next))).
+				RoundTrip(req)
+		})
+	})
+}
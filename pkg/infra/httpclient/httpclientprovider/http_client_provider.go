@@ -41,6 +41,8 @@ func New(cfg *setting.Cfg, validator validations.PluginRequestValidator, tracer
 		middlewares = append(middlewares, HTTPLoggerMiddleware(cfg.PluginSettings))
 	}
 
+	middlewares = append(middlewares, OutboundMiddlewares(cfg.OutboundHTTP, "datasource")...)
+
 	setDefaultTimeoutOptions(cfg)
 
 	return newProviderFunc(sdkhttpclient.ProviderOptions{
@@ -0,0 +1,37 @@
+package httpclientprovider
+
+import (
+	"net/http"
+
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// OutboundMiddlewares returns the middlewares shared by the clients of the outbound requests: the circuit breaker,
+// the hedged retries and the metrics per host. They should be the last middlewares of a client, so that the circuit
+// breaker and the metrics see every attempt of the hedged requests. client labels the metrics.
+func OutboundMiddlewares(cfg setting.OutboundHTTPSettings, client string) []sdkhttpclient.Middleware {
+	return []sdkhttpclient.Middleware{
+		CircuitBreakerMiddleware(cfg.CircuitBreakerFailures, cfg.CircuitBreakerOpenDuration),
+		HedgingMiddleware(cfg.HedgeDelay, cfg.HedgeMaxAttempts),
+		HostMetricsMiddleware(client),
+	}
+}
+
+// OutboundRoundTripper wraps the transport of a client that does not send data source requests with the outbound
+// middlewares, and with the tracing middleware if the tracer is not nil.
+func OutboundRoundTripper(cfg *setting.Cfg, tracer tracing.Tracer, client string, transport http.RoundTripper) http.RoundTripper {
+	middlewares := OutboundMiddlewares(cfg.OutboundHTTP, client)
+	if tracer != nil {
+		middlewares = append([]sdkhttpclient.Middleware{TracingMiddleware(log.New("httpclient"), tracer)}, middlewares...)
+	}
+
+	next := transport
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i].CreateMiddleware(sdkhttpclient.Options{}, next)
+	}
+	return next
+}
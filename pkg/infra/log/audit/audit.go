@@ -0,0 +1,61 @@
+// Package audit records the audit trail of the instance: who did what, when and with which result.
+// Services emit typed events with a Logger, the events are redacted and written in batches to the
+// configured sinks, and the pending events are flushed when the server shuts down.
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	ActionLogin       = "login"
+	ActionAPIMutation = "api.mutation"
+)
+
+type Result string
+
+const (
+	ResultSuccess Result = "success"
+	ResultFailure Result = "failure"
+)
+
+// Event is an entry of the audit trail.
+type Event struct {
+	Time     time.Time      `json:"time"`
+	Action   string         `json:"action"`
+	Result   Result         `json:"result"`
+	Actor    Actor          `json:"actor"`
+	Resource Resource       `json:"resource"`
+	Fields   map[string]any `json:"fields,omitempty"`
+}
+
+// Actor is who made the change, the user is empty for the anonymous requests.
+type Actor struct {
+	UserID int64  `json:"userId,omitempty"`
+	Login  string `json:"login,omitempty"`
+	OrgID  int64  `json:"orgId,omitempty"`
+	IP     string `json:"ip,omitempty"`
+}
+
+// Resource is what the change was made to.
+type Resource struct {
+	Kind string `json:"kind,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+// Logger is used by the services to emit audit events.
+type Logger interface {
+	Log(ctx context.Context, event Event)
+}
+
+// Sink writes batches of audit events to a destination.
+type Sink interface {
+	Write(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// NopLogger discards the events, it can be used in tests.
+type NopLogger struct{}
+
+func (NopLogger) Log(context.Context, Event) {}
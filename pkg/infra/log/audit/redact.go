@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"path"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+// redactor replaces the values of the event fields matching one of the patterns, at any depth.
+// The patterns use the path.Match syntax and are matched case insensitively.
+type redactor struct {
+	patterns []string
+}
+
+func newRedactor(patterns []string) redactor {
+	r := redactor{}
+	for _, p := range patterns {
+		r.patterns = append(r.patterns, strings.ToLower(p))
+	}
+	return r
+}
+
+func (r redactor) redact(fields map[string]any) map[string]any {
+	if len(fields) == 0 || len(r.patterns) == 0 {
+		return fields
+	}
+
+	res := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if r.matches(k) {
+			res[k] = redactedValue
+			continue
+		}
+		if m, ok := v.(map[string]any); ok {
+			res[k] = r.redact(m)
+			continue
+		}
+		res[k] = v
+	}
+	return res
+}
+
+func (r redactor) matches(field string) bool {
+	field = strings.ToLower(field)
+	for _, p := range r.patterns {
+		if ok, _ := path.Match(p, field); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type namedSink struct {
+	name string
+	sink Sink
+}
+
+// Service is the audit Logger of the instance. The events are buffered and written in batches by Run,
+// which flushes the buffered events to the sinks and closes them once its context is done.
+type Service struct {
+	cfg      setting.AuditSettings
+	log      log.Logger
+	sinks    []namedSink
+	redactor redactor
+	now      func() time.Time
+
+	events   chan Event
+	stopping chan struct{}
+	// mu guards stopped, the senders hold a read lock so that no event is sent once the buffer is drained
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func ProvideService(cfg *setting.Cfg) (*Service, error) {
+	s := &Service{
+		cfg:      cfg.Audit,
+		log:      log.New("audit"),
+		redactor: newRedactor(cfg.Audit.RedactFields),
+		now:      time.Now,
+		stopping: make(chan struct{}),
+	}
+	if !s.cfg.Enabled {
+		return s, nil
+	}
+
+	for _, name := range s.cfg.Sinks {
+		sink, err := newSink(name, s.cfg)
+		if err != nil {
+			s.closeSinks()
+			return nil, fmt.Errorf("failed to create the %s audit sink: %w", name, err)
+		}
+		s.sinks = append(s.sinks, namedSink{name: name, sink: sink})
+	}
+	s.events = make(chan Event, s.cfg.BufferSize)
+	return s, nil
+}
+
+func newSink(name string, cfg setting.AuditSettings) (Sink, error) {
+	switch name {
+	case "file":
+		return newFileSink(cfg.FilePath)
+	case "loki":
+		return newLokiSink(cfg.LokiURL, cfg.LokiTenantID, cfg.LokiTimeout)
+	case "syslog":
+		return newSyslogSink(cfg.SyslogNetwork, cfg.SyslogAddress, cfg.SyslogTag)
+	default:
+		return nil, fmt.Errorf("unknown sink")
+	}
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.Enabled
+}
+
+// Log buffers the event to be written to the sinks. It waits while the buffer is full, unless the
+// context is done or the service is stopping, in which case the event is dropped.
+func (s *Service) Log(ctx context.Context, event Event) {
+	if !s.cfg.Enabled {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = s.now()
+	}
+	event.Fields = s.redactor.redact(event.Fields)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		s.log.Warn("Audit event dropped after shutdown", "action", event.Action)
+		return
+	}
+
+	select {
+	case s.events <- event:
+	case <-ctx.Done():
+		s.log.Warn("Audit event dropped", "action", event.Action, "error", ctx.Err())
+	case <-s.stopping:
+		s.log.Warn("Audit event dropped during shutdown", "action", event.Action)
+	}
+}
+
+// UserEvents returns the events of the file sink made by the user, the events sent to Loki and syslog can't
+// be read back. It returns no events when the file sink isn't configured.
+func (s *Service) UserEvents(ctx context.Context, userID int64) ([]Event, error) {
+	if !s.cfg.Enabled || !s.hasSink("file") {
+		return []Event{}, nil
+	}
+	return readFileEvents(ctx, s.cfg.FilePath, func(e Event) bool {
+		return e.Actor.UserID == userID
+	})
+}
+
+func (s *Service) hasSink(name string) bool {
+	for _, ns := range s.sinks {
+		if ns.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Event, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case e := <-s.events:
+			batch = append(batch, e)
+			if len(batch) >= s.cfg.BatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		case <-ctx.Done():
+			s.shutdown(batch)
+			return nil
+		}
+	}
+}
+
+// shutdown stops accepting events, then writes the buffered ones and closes the sinks.
+func (s *Service) shutdown(batch []Event) {
+	close(s.stopping)
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	for {
+		select {
+		case e := <-s.events:
+			batch = append(batch, e)
+		default:
+			s.flush(batch)
+			s.closeSinks()
+			return
+		}
+	}
+}
+
+func (s *Service) flush(batch []Event) {
+	if len(batch) == 0 {
+		return
+	}
+	// the events are written even while the server shuts down
+	ctx := context.Background()
+	for _, ns := range s.sinks {
+		if err := ns.sink.Write(ctx, batch); err != nil {
+			s.log.Error("Failed to write audit events", "sink", ns.name, "count", len(batch), "error", err)
+		}
+	}
+}
+
+func (s *Service) closeSinks() {
+	for _, ns := range s.sinks {
+		if err := ns.sink.Close(); err != nil {
+			s.log.Warn("Failed to close audit sink", "sink", ns.name, "error", err)
+		}
+	}
+}
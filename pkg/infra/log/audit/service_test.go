@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService(t *testing.T) {
+	t.Run("Buffered events are flushed to the sinks on shutdown", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit", "audit.log")
+		cfg := setting.NewCfg()
+		cfg.Audit = setting.AuditSettings{
+			Enabled:       true,
+			Sinks:         []string{"file"},
+			RedactFields:  []string{"*password*"},
+			BufferSize:    10,
+			BatchSize:     100,
+			FlushInterval: time.Hour,
+			FilePath:      path,
+		}
+		s, err := ProvideService(cfg)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- s.Run(ctx) }()
+
+		s.Log(context.Background(), Event{
+			Action: ActionLogin,
+			Result: ResultSuccess,
+			Actor:  Actor{UserID: 1, Login: "admin", OrgID: 1},
+			Fields: map[string]any{"user": map[string]any{"newPassword": "secret", "name": "admin"}},
+		})
+		s.Log(context.Background(), Event{Action: ActionAPIMutation, Result: ResultFailure})
+
+		cancel()
+		require.NoError(t, <-done)
+
+		events := readEvents(t, path)
+		require.Len(t, events, 2)
+		assert.Equal(t, ActionLogin, events[0].Action)
+		assert.Equal(t, "admin", events[0].Actor.Login)
+		assert.False(t, events[0].Time.IsZero())
+		assert.Equal(t, map[string]any{"newPassword": redactedValue, "name": "admin"}, events[0].Fields["user"])
+		assert.Equal(t, ResultFailure, events[1].Result)
+
+		// the events logged after the shutdown are dropped
+		s.Log(context.Background(), Event{Action: ActionLogin})
+		assert.Len(t, readEvents(t, path), 2)
+
+		userEvents, err := s.UserEvents(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, userEvents, 1)
+		assert.Equal(t, ActionLogin, userEvents[0].Action)
+	})
+
+	t.Run("Events are pushed to Loki", func(t *testing.T) {
+		var pushed lokiPushRequest
+		var tenant string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant = r.Header.Get("X-Scope-OrgID")
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &pushed)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(server.Close)
+
+		sink, err := newLokiSink(server.URL, "tenant-1", time.Second)
+		require.NoError(t, err)
+		err = sink.Write(context.Background(), []Event{{Time: time.Unix(10, 0), Action: ActionLogin, Result: ResultSuccess}})
+		require.NoError(t, err)
+
+		assert.Equal(t, "tenant-1", tenant)
+		require.Len(t, pushed.Streams, 1)
+		require.Len(t, pushed.Streams[0].Values, 1)
+		assert.Equal(t, "10000000000", pushed.Streams[0].Values[0][0])
+		assert.Contains(t, pushed.Streams[0].Values[0][1], `"action":"login"`)
+	})
+
+	t.Run("Unknown sinks are rejected", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.Audit = setting.AuditSettings{Enabled: true, Sinks: []string{"kafka"}}
+		_, err := ProvideService(cfg)
+		require.Error(t, err)
+	})
+
+	t.Run("Disabled service ignores the events", func(t *testing.T) {
+		s, err := ProvideService(setting.NewCfg())
+		require.NoError(t, err)
+		require.True(t, s.IsDisabled())
+		s.Log(context.Background(), Event{Action: ActionLogin})
+	})
+}
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
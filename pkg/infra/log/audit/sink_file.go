@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// fileSink appends the events to a file as JSON lines.
+type fileSink struct {
+	file *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	// nolint:gosec
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0640)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: f}, nil
+}
+
+func (s *fileSink) Write(_ context.Context, events []Event) error {
+	enc := json.NewEncoder(s.file)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return s.file.Sync()
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+// readFileEvents returns the events of a file written by the file sink that match the filter. The lines that
+// can't be parsed are skipped.
+func readFileEvents(ctx context.Context, path string, match func(Event) bool) ([]Event, error) {
+	// nolint:gosec
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Event{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	events := []Event{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if match(e) {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
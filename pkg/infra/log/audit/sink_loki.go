@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// lokiSink pushes the events to Loki as a single stream, the events are the JSON log lines.
+type lokiSink struct {
+	url      string
+	tenantID string
+	client   *http.Client
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func newLokiSink(url, tenantID string, timeout time.Duration) (*lokiSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("the url of the loki audit sink is required")
+	}
+	return &lokiSink{
+		url:      url,
+		tenantID: tenantID,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *lokiSink) Write(ctx context.Context, events []Event) error {
+	stream := lokiStream{
+		Stream: map[string]string{"service": "grafana", "source": "audit"},
+		Values: make([][2]string, 0, len(events)),
+	}
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(e.Time.UnixNano(), 10), string(line)})
+	}
+	body, err := json.Marshal(lokiPushRequest{Streams: []lokiStream{stream}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.tenantID)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("loki push failed with status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func (s *lokiSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
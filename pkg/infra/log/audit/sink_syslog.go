@@ -0,0 +1,40 @@
+//go:build !windows && !nacl && !plan9
+// +build !windows,!nacl,!plan9
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+)
+
+// syslogSink sends the events to syslog as JSON messages with the auth facility.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+func newSyslogSink(network, address, tag string) (*syslogSink, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: w}, nil
+}
+
+func (s *syslogSink) Write(_ context.Context, events []Event) error {
+	for _, e := range events {
+		msg, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if err := s.writer.Info(string(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,23 @@
+//go:build windows
+// +build windows
+
+package audit
+
+import (
+	"context"
+	"errors"
+)
+
+type syslogSink struct{}
+
+func newSyslogSink(network, address, tag string) (*syslogSink, error) {
+	return nil, errors.New("the syslog audit sink is not supported on Windows")
+}
+
+func (s *syslogSink) Write(context.Context, []Event) error {
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	return nil
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+var ErrUnknownLogLevel = errors.New("unknown log level")
+
+// LoggerLevel is the level of a named logger.
+type LoggerLevel struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+	// SampleRate is set when only one out of SampleRate debug messages is logged.
+	SampleRate int `json:"sampleRate,omitempty"`
+	// Overridden is true when the level was set at runtime.
+	Overridden bool `json:"overridden"`
+}
+
+// levelOverride is a level set at runtime, kept until the next restart.
+type levelOverride struct {
+	level      string
+	option     level.Option
+	sampleRate int
+}
+
+// SetLevel overrides the level of the named logger until the next restart. When sampleRate is greater than one, only
+// one out of sampleRate debug messages of the logger is logged.
+func SetLevel(name string, levelName string, sampleRate int) error {
+	levelName = strings.ToLower(levelName)
+	option, ok := logLevels[levelName]
+	if !ok {
+		return ErrUnknownLogLevel
+	}
+
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+
+	root.overrides[name] = levelOverride{level: levelName, option: option, sampleRate: sampleRate}
+	root.swapNamedLogger(name)
+	return nil
+}
+
+// ResetLevel removes the level override of the named logger, it logs at its configured level again.
+func ResetLevel(name string) {
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+
+	if _, ok := root.overrides[name]; !ok {
+		return
+	}
+	delete(root.overrides, name)
+	root.swapNamedLogger(name)
+}
+
+// Levels returns the levels of the named loggers, sorted by name.
+func Levels() []LoggerLevel {
+	root.mutex.RLock()
+	defer root.mutex.RUnlock()
+
+	names := map[string]struct{}{}
+	for name := range root.loggersByName {
+		names[name] = struct{}{}
+	}
+	for name := range root.filterLevels {
+		names[name] = struct{}{}
+	}
+	for name := range root.overrides {
+		names[name] = struct{}{}
+	}
+
+	levels := make([]LoggerLevel, 0, len(names))
+	for name := range names {
+		loggerLevel := LoggerLevel{Name: name, Level: root.levelName}
+		if override, ok := root.overrides[name]; ok {
+			loggerLevel.Level = override.level
+			loggerLevel.SampleRate = override.sampleRate
+			loggerLevel.Overridden = true
+		} else if levelName, ok := root.filterLevels[name]; ok {
+			loggerLevel.Level = levelName
+		}
+		levels = append(levels, loggerLevel)
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Name < levels[j].Name
+	})
+	return levels
+}
+
+func (lm *logManager) setConfiguredLevels(levelName string, filterLevels map[string]string) {
+	lm.mutex.Lock()
+	defer lm.mutex.Unlock()
+
+	lm.levelName = levelName
+	lm.filterLevels = filterLevels
+}
+
+// swapNamedLogger applies the level of the named logger, if it was already created. Until the handlers are
+// initialized, the level is applied when they are. The caller must hold the mutex.
+func (lm *logManager) swapNamedLogger(name string) {
+	logger, exists := lm.loggersByName[name]
+	if !exists || len(lm.logFilters) == 0 {
+		return
+	}
+	logger.Swap(gokitlog.With(lm.namedLogger(name), logger.ctx...))
+}
@@ -58,13 +58,20 @@ type logManager struct {
 	*ConcreteLogger
 	loggersByName map[string]*ConcreteLogger
 	logFilters    []logWithFilters
-	mutex         sync.RWMutex
+	// levelName and filterLevels are the configured levels, overrides the levels set at runtime
+	levelName    string
+	filterLevels map[string]string
+	overrides    map[string]levelOverride
+	mutex        sync.RWMutex
 }
 
 func newManager(logger gokitlog.Logger) *logManager {
 	return &logManager{
 		ConcreteLogger: newConcreteLogger(logger),
 		loggersByName:  map[string]*ConcreteLogger{},
+		levelName:      "info",
+		filterLevels:   map[string]string{},
+		overrides:      map[string]levelOverride{},
 	}
 }
 
@@ -87,19 +94,30 @@ func (lm *logManager) initialize(loggers []logWithFilters) {
 	sort.Strings(loggersByName)
 
 	for _, name := range loggersByName {
-		ctxLoggers := make([]gokitlog.Logger, len(loggers))
-
-		for index, logger := range loggers {
-			ctxLogger := gokitlog.With(logger.val, lm.loggersByName[name].ctx...)
-			if filterLevel, exists := logger.filters[name]; !exists {
-				ctxLoggers[index] = level.NewFilter(ctxLogger, logger.maxLevel)
-			} else {
-				ctxLoggers[index] = level.NewFilter(ctxLogger, filterLevel)
-			}
+		lm.loggersByName[name].Swap(gokitlog.With(lm.namedLogger(name), lm.loggersByName[name].ctx...))
+	}
+}
+
+// namedLogger returns the logger writing the messages of the named logger to every handler, filtered by the level
+// of the logger. The caller must hold the mutex.
+func (lm *logManager) namedLogger(name string) gokitlog.Logger {
+	override, overridden := lm.overrides[name]
+
+	loggers := make([]gokitlog.Logger, len(lm.logFilters))
+	for index, logger := range lm.logFilters {
+		filterLevel, exists := logger.filters[name]
+		if overridden {
+			filterLevel = override.option
+		} else if !exists {
+			filterLevel = logger.maxLevel
 		}
+		loggers[index] = level.NewFilter(logger.val, filterLevel)
+	}
 
-		lm.loggersByName[name].Swap(&compositeLogger{loggers: ctxLoggers})
+	if overridden && override.sampleRate > 1 {
+		return newSampledLogger(newCompositeLogger(loggers...), override.sampleRate)
 	}
+	return newCompositeLogger(loggers...)
 }
 
 func (lm *logManager) New(ctx ...interface{}) *ConcreteLogger {
@@ -125,19 +143,7 @@ func (lm *logManager) New(ctx ...interface{}) *ConcreteLogger {
 		return ctxLogger
 	}
 
-	compositeLogger := newCompositeLogger()
-	for _, logWithFilter := range lm.logFilters {
-		filterLevel, ok := logWithFilter.filters[loggerName]
-		if ok {
-			logWithFilter.val = level.NewFilter(logWithFilter.val, filterLevel)
-		} else {
-			logWithFilter.val = level.NewFilter(logWithFilter.val, logWithFilter.maxLevel)
-		}
-
-		compositeLogger.loggers = append(compositeLogger.loggers, logWithFilter.val)
-	}
-
-	ctxLogger := newConcreteLogger(compositeLogger, ctx...)
+	ctxLogger := newConcreteLogger(lm.namedLogger(loggerName), ctx...)
 	lm.loggersByName[loggerName] = ctxLogger
 	return ctxLogger
 }
@@ -300,6 +306,15 @@ func getLogLevelFromString(levelName string) level.Option {
 // the filter is composed with logger name and level
 func getFilters(filterStrArray []string) map[string]level.Option {
 	filterMap := make(map[string]level.Option)
+	for name, levelName := range getFilterLevelNames(filterStrArray) {
+		filterMap[name] = getLogLevelFromString(levelName)
+	}
+
+	return filterMap
+}
+
+func getFilterLevelNames(filterStrArray []string) map[string]string {
+	filterMap := make(map[string]string)
 
 	for i := 0; i < len(filterStrArray); i++ {
 		filterStr := strings.TrimSpace(filterStrArray[i])
@@ -313,7 +328,7 @@ func getFilters(filterStrArray []string) map[string]level.Option {
 
 		parts := strings.Split(filterStr, ":")
 		if len(parts) > 1 {
-			filterMap[parts[0]] = getLogLevelFromString(parts[1])
+			filterMap[parts[0]] = parts[1]
 		}
 	}
 
@@ -408,6 +423,7 @@ func ReadLoggingConfig(modes []string, logsPath string, cfg *ini.File) error {
 	}
 
 	defaultLevelName, _ := getLogLevelFromConfig("log", "info", cfg)
+	defaultFilterLevels := getFilterLevelNames(util.SplitString(cfg.Section("log").Key("filters").String()))
 	defaultFilters := getFilters(util.SplitString(cfg.Section("log").Key("filters").String()))
 
 	var configLoggers []logWithFilters
@@ -473,6 +489,7 @@ func ReadLoggingConfig(modes []string, logsPath string, cfg *ini.File) error {
 		handler.maxLevel = leveloption
 		configLoggers = append(configLoggers, handler)
 	}
+	root.setConfiguredLevels(defaultLevelName, defaultFilterLevels)
 	if len(configLoggers) > 0 {
 		root.initialize(configLoggers)
 	}
@@ -197,6 +197,67 @@ func TestWithSuffix_appendsContext(t *testing.T) {
 	})
 }
 
+func TestSetLevel(t *testing.T) {
+	newLoggerScenario(t)
+
+	loggedArgs := [][]interface{}{}
+	root.initialize([]logWithFilters{
+		{
+			val: gokitlog.LoggerFunc(func(i ...interface{}) error {
+				loggedArgs = append(loggedArgs, i)
+				return nil
+			}),
+			filters:  map[string]level.Option{"configured": level.AllowDebug()},
+			maxLevel: level.AllowInfo(),
+		},
+	})
+	root.setConfiguredLevels("info", map[string]string{"configured": "debug"})
+
+	logger := New("service-watcher")
+	logger.Debug("before override")
+	require.Len(t, loggedArgs, 0)
+
+	t.Run("Setting an unknown level should fail", func(t *testing.T) {
+		require.ErrorIs(t, SetLevel("service-watcher", "verbose", 0), ErrUnknownLogLevel)
+	})
+
+	t.Run("Setting the level should apply to the existing and new loggers", func(t *testing.T) {
+		require.NoError(t, SetLevel("service-watcher", "DEBUG", 0))
+		require.NoError(t, SetLevel("not-created-yet", "error", 0))
+
+		logger.Debug("after override")
+		New("not-created-yet").Info("info")
+		require.Len(t, loggedArgs, 1)
+
+		require.Equal(t, []LoggerLevel{
+			{Name: "configured", Level: "debug"},
+			{Name: "not-created-yet", Level: "error", Overridden: true},
+			{Name: "service-watcher", Level: "debug", Overridden: true},
+		}, Levels())
+	})
+
+	t.Run("Sampling should log one out of every rate debug messages", func(t *testing.T) {
+		loggedArgs = [][]interface{}{}
+		require.NoError(t, SetLevel("service-watcher", "debug", 3))
+
+		for i := 0; i < 7; i++ {
+			logger.Debug("sampled")
+		}
+		logger.Info("not sampled")
+		require.Len(t, loggedArgs, 4)
+	})
+
+	t.Run("Resetting the level should restore the configured level", func(t *testing.T) {
+		loggedArgs = [][]interface{}{}
+		ResetLevel("service-watcher")
+
+		logger.Debug("after reset")
+		logger.Info("after reset")
+		require.Len(t, loggedArgs, 1)
+		require.Contains(t, Levels(), LoggerLevel{Name: "service-watcher", Level: "info"})
+	})
+}
+
 func TestGetFilters(t *testing.T) {
 	t.Run("Parsing filters on single line with only space should return expected result", func(t *testing.T) {
 		filter := `   `
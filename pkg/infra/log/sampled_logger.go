@@ -0,0 +1,37 @@
+package log
+
+import (
+	"sync/atomic"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+// sampledLogger logs one out of every rate debug messages, the messages of the other levels are always logged.
+type sampledLogger struct {
+	logger gokitlog.Logger
+	rate   uint64
+	count  uint64
+}
+
+func newSampledLogger(logger gokitlog.Logger, rate int) *sampledLogger {
+	return &sampledLogger{logger: logger, rate: uint64(rate)}
+}
+
+func (l *sampledLogger) Log(keyvals ...interface{}) error {
+	if isDebug(keyvals) && atomic.AddUint64(&l.count, 1)%l.rate != 1 {
+		return nil
+	}
+
+	return l.logger.Log(keyvals...)
+}
+
+func isDebug(keyvals []interface{}) bool {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == level.Key() {
+			return keyvals[i+1] == level.DebugValue()
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package metricutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// ExemplarLabels returns the labels of the exemplar linking an observation to the trace of the context, or nil
+// when the trace is not sampled.
+func ExemplarLabels(ctx context.Context) prometheus.Labels {
+	traceID := tracing.TraceIDFromContext(ctx, true)
+	if traceID == "" {
+		return nil
+	}
+	return prometheus.Labels{"traceID": traceID}
+}
+
+// ObserveWithExemplar observes the value, with the trace ID of the context as exemplar when the trace is sampled.
+func ObserveWithExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+		if labels := ExemplarLabels(ctx); labels != nil {
+			exemplarObserver.ObserveWithExemplar(value, labels)
+			return
+		}
+	}
+	observer.Observe(value)
+}
+
+// WithNativeHistogram returns the options of a histogram that is also exposed as a native histogram to the scrapers
+// that support it. The classic buckets are kept for the others.
+func WithNativeHistogram(opts prometheus.HistogramOpts) prometheus.HistogramOpts {
+	// the bucket factor and limits recommended by the Prometheus client
+	opts.NativeHistogramBucketFactor = 1.1
+	opts.NativeHistogramMaxBucketNumber = 160
+	opts.NativeHistogramMinResetDuration = time.Hour
+	return opts
+}
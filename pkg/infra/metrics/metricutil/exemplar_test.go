@@ -0,0 +1,46 @@
+package metricutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveWithExemplar(t *testing.T) {
+	newHistogram := func() prometheus.Histogram {
+		return prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_duration_seconds", Buckets: []float64{1, 10}})
+	}
+	exemplars := func(t *testing.T, histogram prometheus.Histogram) []*dto.Exemplar {
+		t.Helper()
+		metric := &dto.Metric{}
+		require.NoError(t, histogram.Write(metric))
+		result := []*dto.Exemplar{}
+		for _, bucket := range metric.GetHistogram().GetBucket() {
+			if bucket.GetExemplar() != nil {
+				result = append(result, bucket.GetExemplar())
+			}
+		}
+		return result
+	}
+
+	t.Run("should observe without exemplar without a trace", func(t *testing.T) {
+		require.Nil(t, ExemplarLabels(context.Background()))
+
+		histogram := newHistogram()
+		ObserveWithExemplar(context.Background(), histogram, 0.5)
+
+		require.Empty(t, exemplars(t, histogram))
+		metric := &dto.Metric{}
+		require.NoError(t, histogram.Write(metric))
+		require.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+	})
+}
+
+func TestWithNativeHistogram(t *testing.T) {
+	opts := WithNativeHistogram(prometheus.HistogramOpts{Name: "test_duration_seconds", Buckets: prometheus.DefBuckets})
+	require.Equal(t, prometheus.DefBuckets, opts.Buckets)
+	require.Greater(t, opts.NativeHistogramBucketFactor, 1.0)
+}
@@ -0,0 +1,188 @@
+package orgusage
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// otherOrgs is the org_id label of the organizations above the cap or outside of the allow-list
+	otherOrgs = "other"
+	// activeUserTimeLimit is how recently a user must have been seen to be active
+	activeUserTimeLimit = time.Hour * 24 * 30
+)
+
+var (
+	activeUsersDesc = prometheus.NewDesc("grafana_org_usage_active_users", "Number of users of the organization seen in the last 30 days.", []string{"org_id"}, nil)
+	dashboardsDesc  = prometheus.NewDesc("grafana_org_usage_dashboards", "Number of dashboards of the organization.", []string{"org_id"}, nil)
+	queriesDesc     = prometheus.NewDesc("grafana_org_usage_queries_total", "Number of data source queries of the organization.", []string{"org_id"}, nil)
+)
+
+// Service exposes usage metrics by organization on their own registry, separate from the instance metrics,
+// so that the usage can be billed per tenant. The number of org_id label values is capped: the organizations
+// outside of the allow-list, or above the cap when there's no allow-list, are counted under org_id="other".
+type Service struct {
+	store    store
+	log      log.Logger
+	now      func() time.Time
+	registry *prometheus.Registry
+
+	enabled   bool
+	interval  time.Duration
+	maxOrgs   int
+	allowList map[int64]bool
+
+	mutex       sync.Mutex
+	orgLabels   map[int64]string
+	activeUsers map[string]float64
+	dashboards  map[string]float64
+	queries     map[string]float64
+}
+
+func ProvideService(cfg *setting.Cfg, sql db.DB) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("metrics.usage")
+	s := &Service{
+		store:       &sqlStore{db: sql},
+		log:         log.New("metrics.usage"),
+		now:         time.Now,
+		registry:    prometheus.NewRegistry(),
+		enabled:     section.Key("enabled").MustBool(false),
+		interval:    section.Key("interval").MustDuration(time.Minute),
+		maxOrgs:     section.Key("max_orgs").MustInt(1000),
+		allowList:   map[int64]bool{},
+		orgLabels:   map[int64]string{},
+		activeUsers: map[string]float64{},
+		dashboards:  map[string]float64{},
+		queries:     map[string]float64{},
+	}
+	for _, id := range util.SplitString(section.Key("org_allow_list").MustString("")) {
+		orgID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		s.allowList[orgID] = true
+	}
+	if s.interval <= 0 {
+		s.interval = time.Minute
+	}
+
+	if err := s.registry.Register(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.enabled
+}
+
+// Run refreshes the active users and dashboards of the organizations every interval.
+func (s *Service) Run(ctx context.Context) error {
+	s.refresh(ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Handler serves the usage metrics in the Prometheus exposition format.
+func (s *Service) Handler() http.Handler {
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// RecordQuery counts a data source query of the organization.
+func (s *Service) RecordQuery(orgID int64) {
+	if !s.enabled {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queries[s.orgLabel(orgID)]++
+}
+
+func (s *Service) refresh(ctx context.Context) {
+	activeUsers, err := s.store.ActiveUsersByOrg(ctx, s.now().Add(-activeUserTimeLimit))
+	if err != nil {
+		s.log.Error("Failed to count the active users by organization", "error", err)
+		return
+	}
+	dashboards, err := s.store.DashboardsByOrg(ctx)
+	if err != nil {
+		s.log.Error("Failed to count the dashboards by organization", "error", err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.activeUsers = s.byOrgLabel(activeUsers)
+	s.dashboards = s.byOrgLabel(dashboards)
+}
+
+// byOrgLabel sums the counts by org_id label, it must be called with the lock held.
+func (s *Service) byOrgLabel(counts map[int64]int64) map[string]float64 {
+	res := make(map[string]float64, len(counts))
+	for orgID, count := range counts {
+		res[s.orgLabel(orgID)] += float64(count)
+	}
+	return res
+}
+
+// orgLabel returns the org_id label of an organization, it must be called with the lock held.
+func (s *Service) orgLabel(orgID int64) string {
+	if len(s.allowList) > 0 {
+		if s.allowList[orgID] {
+			return strconv.FormatInt(orgID, 10)
+		}
+		return otherOrgs
+	}
+
+	if label, ok := s.orgLabels[orgID]; ok {
+		return label
+	}
+	if len(s.orgLabels) >= s.maxOrgs {
+		return otherOrgs
+	}
+	label := strconv.FormatInt(orgID, 10)
+	s.orgLabels[orgID] = label
+	return label
+}
+
+func (s *Service) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeUsersDesc
+	ch <- dashboardsDesc
+	ch <- queriesDesc
+}
+
+func (s *Service) Collect(ch chan<- prometheus.Metric) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for label, v := range s.activeUsers {
+		ch <- prometheus.MustNewConstMetric(activeUsersDesc, prometheus.GaugeValue, v, label)
+	}
+	for label, v := range s.dashboards {
+		ch <- prometheus.MustNewConstMetric(dashboardsDesc, prometheus.GaugeValue, v, label)
+	}
+	for label, v := range s.queries {
+		ch <- prometheus.MustNewConstMetric(queriesDesc, prometheus.CounterValue, v, label)
+	}
+}
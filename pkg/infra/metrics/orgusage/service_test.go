@@ -0,0 +1,137 @@
+package orgusage
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeStore struct {
+	activeUsers map[int64]int64
+	dashboards  map[int64]int64
+}
+
+func (f *fakeStore) ActiveUsersByOrg(context.Context, time.Time) (map[int64]int64, error) {
+	return f.activeUsers, nil
+}
+
+func (f *fakeStore) DashboardsByOrg(context.Context) (map[int64]int64, error) {
+	return f.dashboards, nil
+}
+
+func newTestService(t *testing.T, settings string) *Service {
+	t.Helper()
+	cfg := setting.NewCfg()
+	section := cfg.Raw.Section("metrics.usage")
+	for _, kv := range strings.Split(settings, ";") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			section.Key(k).SetValue(v)
+		}
+	}
+	s, err := ProvideService(cfg, nil)
+	require.NoError(t, err)
+	s.store = &fakeStore{
+		activeUsers: map[int64]int64{1: 10, 2: 5, 3: 1},
+		dashboards:  map[int64]int64{1: 100, 2: 20, 3: 3},
+	}
+	return s
+}
+
+func TestService(t *testing.T) {
+	t.Run("Organizations above the cap are counted together", func(t *testing.T) {
+		s := newTestService(t, "enabled=true;max_orgs=2")
+		s.RecordQuery(1)
+		s.RecordQuery(2)
+		s.RecordQuery(3)
+		s.RecordQuery(3)
+		s.refresh(context.Background())
+
+		expected := `
+# HELP grafana_org_usage_queries_total Number of data source queries of the organization.
+# TYPE grafana_org_usage_queries_total counter
+grafana_org_usage_queries_total{org_id="1"} 1
+grafana_org_usage_queries_total{org_id="2"} 1
+grafana_org_usage_queries_total{org_id="other"} 2
+# HELP grafana_org_usage_active_users Number of users of the organization seen in the last 30 days.
+# TYPE grafana_org_usage_active_users gauge
+grafana_org_usage_active_users{org_id="1"} 10
+grafana_org_usage_active_users{org_id="2"} 5
+grafana_org_usage_active_users{org_id="other"} 1
+`
+		require.NoError(t, testutil.GatherAndCompare(s.registry, strings.NewReader(expected), "grafana_org_usage_queries_total", "grafana_org_usage_active_users"))
+	})
+
+	t.Run("Only the allowed organizations have their own label", func(t *testing.T) {
+		s := newTestService(t, "enabled=true;org_allow_list=2")
+		s.refresh(context.Background())
+
+		expected := `
+# HELP grafana_org_usage_dashboards Number of dashboards of the organization.
+# TYPE grafana_org_usage_dashboards gauge
+grafana_org_usage_dashboards{org_id="2"} 20
+grafana_org_usage_dashboards{org_id="other"} 103
+`
+		require.NoError(t, testutil.GatherAndCompare(s.registry, strings.NewReader(expected), "grafana_org_usage_dashboards"))
+	})
+
+	t.Run("Handler serves the usage metrics only", func(t *testing.T) {
+		s := newTestService(t, "enabled=true")
+		s.RecordQuery(1)
+
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics/usage", nil))
+		assert.Contains(t, rec.Body.String(), `grafana_org_usage_queries_total{org_id="1"} 1`)
+		assert.NotContains(t, rec.Body.String(), "go_goroutines")
+	})
+
+	t.Run("Queries are not counted when disabled", func(t *testing.T) {
+		s := newTestService(t, "")
+		require.True(t, s.IsDisabled())
+		s.RecordQuery(1)
+		assert.Empty(t, s.queries)
+	})
+}
+
+func TestIntegrationSQLStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	ctx := context.Background()
+	now := time.Now()
+	err := testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		for uid, orgID := range map[string]int64{"a": 1, "b": 1, "c": 2} {
+			if _, err := sess.Insert(&dashboards.Dashboard{
+				UID: uid, Title: uid, Slug: uid, OrgID: orgID, Data: simplejson.New(), Created: now, Updated: now,
+			}); err != nil {
+				return err
+			}
+		}
+		_, err := sess.Insert(&dashboards.Dashboard{
+			UID: "folder", Title: "folder", Slug: "folder", OrgID: 2, IsFolder: true, Data: simplejson.New(), Created: now, Updated: now,
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	store := &sqlStore{db: testDB}
+	counts, err := store.DashboardsByOrg(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{1: 2, 2: 1}, counts)
+
+	counts, err = store.ActiveUsersByOrg(ctx, now.Add(-activeUserTimeLimit))
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
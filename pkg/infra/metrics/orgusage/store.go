@@ -0,0 +1,52 @@
+package orgusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	ActiveUsersByOrg(ctx context.Context, since time.Time) (map[int64]int64, error)
+	DashboardsByOrg(ctx context.Context) (map[int64]int64, error)
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+type orgCount struct {
+	OrgID int64 `xorm:"org_id"`
+	Count int64 `xorm:"count"`
+}
+
+func (ss *sqlStore) ActiveUsersByOrg(ctx context.Context, since time.Time) (map[int64]int64, error) {
+	dialect := ss.db.GetDialect()
+	rawSQL := `SELECT org_user.org_id AS org_id, COUNT(*) AS count FROM org_user` +
+		` INNER JOIN ` + dialect.Quote("user") + ` ON ` + dialect.Quote("user") + `.id = org_user.user_id` +
+		` WHERE ` + dialect.Quote("user") + `.is_service_account = ` + dialect.BooleanStr(false) +
+		` AND ` + dialect.Quote("user") + `.last_seen_at > ?` +
+		` GROUP BY org_user.org_id`
+	return ss.countByOrg(ctx, rawSQL, since)
+}
+
+func (ss *sqlStore) DashboardsByOrg(ctx context.Context) (map[int64]int64, error) {
+	rawSQL := `SELECT org_id, COUNT(*) AS count FROM dashboard WHERE is_folder = ` + ss.db.GetDialect().BooleanStr(false) + ` GROUP BY org_id`
+	return ss.countByOrg(ctx, rawSQL)
+}
+
+func (ss *sqlStore) countByOrg(ctx context.Context, rawSQL string, args ...interface{}) (map[int64]int64, error) {
+	res := map[int64]int64{}
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var counts []orgCount
+		if err := sess.SQL(rawSQL, args...).Find(&counts); err != nil {
+			return err
+		}
+		for _, c := range counts {
+			res[c.OrgID] = c.Count
+		}
+		return nil
+	})
+	return res, err
+}
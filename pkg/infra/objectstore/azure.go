@@ -0,0 +1,72 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type azureStore struct {
+	container azblob.ContainerURL
+}
+
+func newAzureStore(settings setting.ObjectStoreAzureSettings) (*azureStore, error) {
+	if settings.AccountName == "" || settings.AccountKey == "" || settings.ContainerName == "" {
+		return nil, errors.New("account_name, account_key and container_name are required")
+	}
+
+	credential, err := azblob.NewSharedKeyCredential(settings.AccountName, settings.AccountKey)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := settings.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", settings.AccountName)
+	}
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/") + "/" + settings.ContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	return &azureStore{container: azblob.NewContainerURL(*u, pipeline)}, nil
+}
+
+func (s *azureStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := azblob.UploadBufferToBlockBlob(ctx, data, s.container.NewBlockBlobURL(key), azblob.UploadToBlockBlobOptions{})
+	return err
+}
+
+func (s *azureStore) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.container.NewBlobURL(key).Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if isAzureBlobNotFound(err) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	body := resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3})
+	defer func() { _ = body.Close() }()
+	return io.ReadAll(body)
+}
+
+func (s *azureStore) Delete(ctx context.Context, key string) error {
+	_, err := s.container.NewBlobURL(key).Delete(ctx, azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{})
+	if isAzureBlobNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func isAzureBlobNotFound(err error) bool {
+	var serr azblob.StorageError
+	return errors.As(err, &serr) && serr.ServiceCode() == azblob.ServiceCodeBlobNotFound
+}
@@ -0,0 +1,85 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fileStore stores the objects in the files of a directory, the slashes of the keys being subdirectories.
+type fileStore struct {
+	root string
+}
+
+func newFileStore(settings setting.ObjectStoreFileSettings) (*fileStore, error) {
+	if settings.Path == "" {
+		return nil, errors.New("path is required")
+	}
+	if err := os.MkdirAll(settings.Path, 0750); err != nil {
+		return nil, err
+	}
+	return &fileStore{root: settings.Path}, nil
+}
+
+func (s *fileStore) path(key string) (string, error) {
+	p := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(p, filepath.Clean(s.root)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid key %q", key)
+	}
+	return p, nil
+}
+
+func (s *fileStore) Put(_ context.Context, key string, data []byte) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0750); err != nil {
+		return err
+	}
+
+	// the object is written to a temporary file renamed once complete, so that it's never read partially
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *fileStore) Get(_ context.Context, key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is checked to be in the root directory
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (s *fileStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
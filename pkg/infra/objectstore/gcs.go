@@ -0,0 +1,62 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type gcsStore struct {
+	bucket *storage.BucketHandle
+}
+
+func newGCSStore(ctx context.Context, settings setting.ObjectStoreGCSSettings) (*gcsStore, error) {
+	if settings.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	// the application default credentials are used without key file
+	var opts []option.ClientOption
+	if settings.KeyFile != "" {
+		opts = append(opts, option.WithCredentialsFile(settings.KeyFile))
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsStore{bucket: client.Bucket(settings.Bucket)}, nil
+}
+
+func (s *gcsStore) Put(ctx context.Context, key string, data []byte) error {
+	w := s.bucket.Object(key).NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (s *gcsStore) Get(ctx context.Context, key string) ([]byte, error) {
+	r, err := s.bucket.Object(key).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	return io.ReadAll(r)
+}
+
+func (s *gcsStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.Object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+// Package objectstore stores objects by key in an object storage service, S3, GCS or Azure Blob Storage, or in a
+// directory of the local file system.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	ProviderS3    = "s3"
+	ProviderGCS   = "gcs"
+	ProviderAzure = "azure"
+	ProviderFile  = "file"
+)
+
+var (
+	// ErrObjectNotFound is returned when there is no object with the key.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrNotConfigured is returned by New when no provider is configured.
+	ErrNotConfigured = errors.New("object storage is not configured")
+)
+
+// ObjectStore stores objects by key.
+type ObjectStore interface {
+	// Put creates or replaces the object with the key.
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns the object with the key, or ErrObjectNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete deletes the object with the key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the object storage of the settings, ErrNotConfigured when they have no provider.
+func New(ctx context.Context, settings setting.ObjectStoreSettings) (ObjectStore, error) {
+	var store ObjectStore
+	var err error
+	switch settings.Provider {
+	case "":
+		return nil, ErrNotConfigured
+	case ProviderS3:
+		store, err = newS3Store(settings.S3)
+	case ProviderGCS:
+		store, err = newGCSStore(ctx, settings.GCS)
+	case ProviderAzure:
+		store, err = newAzureStore(settings.Azure)
+	case ProviderFile:
+		store, err = newFileStore(settings.File)
+	default:
+		return nil, fmt.Errorf("unknown object storage provider %q", settings.Provider)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the %s object storage: %w", settings.Provider, err)
+	}
+
+	if settings.Prefix != "" {
+		store = &prefixedStore{store: store, prefix: settings.Prefix}
+	}
+	return store, nil
+}
+
+// prefixedStore prepends a prefix to the keys of the objects.
+type prefixedStore struct {
+	store  ObjectStore
+	prefix string
+}
+
+func (s *prefixedStore) Put(ctx context.Context, key string, data []byte) error {
+	return s.store.Put(ctx, s.prefix+key, data)
+}
+
+func (s *prefixedStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.store.Get(ctx, s.prefix+key)
+}
+
+func (s *prefixedStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, s.prefix+key)
+}
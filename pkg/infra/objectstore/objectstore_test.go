@@ -0,0 +1,69 @@
+package objectstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Not configured without provider", func(t *testing.T) {
+		_, err := New(context.Background(), setting.ObjectStoreSettings{})
+		require.ErrorIs(t, err, ErrNotConfigured)
+	})
+
+	t.Run("Unknown provider", func(t *testing.T) {
+		_, err := New(context.Background(), setting.ObjectStoreSettings{Provider: "ftp"})
+		require.Error(t, err)
+	})
+
+	t.Run("Missing bucket", func(t *testing.T) {
+		_, err := New(context.Background(), setting.ObjectStoreSettings{Provider: ProviderS3})
+		require.Error(t, err)
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := New(ctx, setting.ObjectStoreSettings{
+		Provider: ProviderFile,
+		Prefix:   "prefix/",
+		File:     setting.ObjectStoreFileSettings{Path: dir},
+	})
+	require.NoError(t, err)
+
+	t.Run("Put, get and delete an object", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "a/b", []byte("data")))
+		data, err := store.Get(ctx, "a/b")
+		require.NoError(t, err)
+		require.Equal(t, []byte("data"), data)
+
+		_, err = os.Stat(filepath.Join(dir, "prefix", "a", "b"))
+		require.NoError(t, err, "the prefix is prepended to the keys")
+
+		require.NoError(t, store.Put(ctx, "a/b", []byte("replaced")))
+		data, err = store.Get(ctx, "a/b")
+		require.NoError(t, err)
+		require.Equal(t, []byte("replaced"), data)
+
+		require.NoError(t, store.Delete(ctx, "a/b"))
+		_, err = store.Get(ctx, "a/b")
+		require.ErrorIs(t, err, ErrObjectNotFound)
+	})
+
+	t.Run("Delete a missing object", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "missing"))
+	})
+
+	t.Run("Reject the keys outside of the directory", func(t *testing.T) {
+		require.Error(t, store.Put(ctx, "../../escaped", []byte("data")))
+		_, err := store.Get(ctx, "../../escaped")
+		require.Error(t, err)
+	})
+}
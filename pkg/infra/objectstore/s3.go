@@ -0,0 +1,83 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type s3Store struct {
+	client *s3.S3
+	bucket string
+}
+
+func newS3Store(settings setting.ObjectStoreS3Settings) (*s3Store, error) {
+	if settings.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+
+	cfg := &aws.Config{
+		S3ForcePathStyle: aws.Bool(settings.PathStyleAccess),
+	}
+	if settings.Region != "" {
+		cfg.Region = aws.String(settings.Region)
+	}
+	if settings.Endpoint != "" {
+		cfg.Endpoint = aws.String(settings.Endpoint)
+	}
+	// the default credential chain is used without access key: environment, shared files, web identity and roles
+	if settings.AccessKey != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(settings.AccessKey, settings.SecretKey, "")
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3Store{client: s3.New(sess), bucket: settings.Bucket}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var aerr awserr.Error
+		if errors.As(err, &aerr) && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	defer func() { _ = out.Body.Close() }()
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
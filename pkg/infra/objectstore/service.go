@@ -0,0 +1,53 @@
+package objectstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Artifact is a type of artifact stored in an object storage.
+type Artifact string
+
+const (
+	// ArtifactRendering is the rendered images.
+	ArtifactRendering Artifact = "rendering"
+	// ArtifactReports is the generated reports.
+	ArtifactReports Artifact = "reports"
+	// ArtifactSnapshots is the payloads of the dashboard snapshots.
+	ArtifactSnapshots Artifact = "snapshots"
+)
+
+// Service provides the object storage configured for each type of artifact in the [object_storage] sections.
+type Service struct {
+	stores map[Artifact]ObjectStore
+}
+
+func ProvideService(cfg *setting.Cfg) (*Service, error) {
+	s := &Service{stores: map[Artifact]ObjectStore{}}
+	for artifact, settings := range map[Artifact]setting.ObjectStoreSettings{
+		ArtifactRendering: cfg.ObjectStorage.Rendering,
+		ArtifactReports:   cfg.ObjectStorage.Reports,
+		ArtifactSnapshots: cfg.ObjectStorage.Snapshots,
+	} {
+		if settings.Provider == "" {
+			continue
+		}
+		store, err := New(context.Background(), settings)
+		if err != nil {
+			return nil, fmt.Errorf("object storage of the %s: %w", artifact, err)
+		}
+		s.stores[artifact] = store
+	}
+	return s, nil
+}
+
+// Store returns the object storage of the artifact type, nil when the artifacts aren't stored in an object
+// storage.
+func (s *Service) Store(artifact Artifact) ObjectStore {
+	if s == nil {
+		return nil
+	}
+	return s.stores[artifact]
+}
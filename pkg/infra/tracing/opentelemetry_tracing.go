@@ -42,6 +42,11 @@ type Opentelemetry struct {
 	customAttribs []attribute.KeyValue
 	log           log.Logger
 
+	samplingRate          float64
+	componentRates        map[string]float64
+	tailSampling          bool
+	tailSamplingThreshold time.Duration
+
 	tracerProvider tracerProvider
 	tracer         trace.Tracer
 
@@ -88,6 +93,15 @@ func (ots *Opentelemetry) parseSettingsOpentelemetry() error {
 		return err
 	}
 
+	ots.samplingRate = section.Key("sampling_rate").MustFloat64(1)
+	if ots.samplingRate < 0 || ots.samplingRate > 1 {
+		return fmt.Errorf("sampling rate must be between 0 and 1: %v", ots.samplingRate)
+	}
+	ots.componentRates, err = splitComponentRates(section.Key("component_sampling_rates").MustString(""))
+	if err != nil {
+		return err
+	}
+
 	section, err = ots.Cfg.Raw.GetSection("tracing.opentelemetry.jaeger")
 	if err != nil {
 		return err
@@ -111,6 +125,8 @@ func (ots *Opentelemetry) parseSettingsOpentelemetry() error {
 		ots.enabled = otlpExporter
 	}
 	ots.propagation = section.Key("propagation").MustString("")
+	ots.tailSampling = section.Key("tail_sampling_enabled").MustBool(false)
+	ots.tailSamplingThreshold = section.Key("tail_sampling_latency_threshold").MustDuration(time.Second)
 	return nil
 }
 
@@ -153,6 +169,7 @@ func (ots *Opentelemetry) initJaegerTracerProvider() (*tracesdk.TracerProvider,
 
 	tp := tracesdk.NewTracerProvider(
 		tracesdk.WithBatcher(exp),
+		tracesdk.WithSampler(ots.sampler()),
 		tracesdk.WithResource(res),
 	)
 
@@ -180,16 +197,27 @@ func (ots *Opentelemetry) initOTLPTracerProvider() (*tracesdk.TracerProvider, er
 		return nil, err
 	}
 
+	var processor tracesdk.SpanProcessor = tracesdk.NewBatchSpanProcessor(exp)
+	sampler := ots.sampler()
+	if ots.tailSampling {
+		// the spans which are not sampled are recorded for the slow and failed ones to be exported anyway
+		processor = newTailSamplingProcessor(processor, ots.tailSamplingThreshold)
+		sampler = recordingSampler{Sampler: sampler}
+	}
+
 	tp := tracesdk.NewTracerProvider(
-		tracesdk.WithBatcher(exp),
-		tracesdk.WithSampler(tracesdk.ParentBased(
-			tracesdk.AlwaysSample(),
-		)),
+		tracesdk.WithSpanProcessor(processor),
+		tracesdk.WithSampler(sampler),
 		tracesdk.WithResource(res),
 	)
 	return tp, nil
 }
 
+// sampler samples the new traces with the rate of their component, the other spans follow their parent.
+func (ots *Opentelemetry) sampler() tracesdk.Sampler {
+	return tracesdk.ParentBased(newComponentSampler(ots.samplingRate, ots.componentRates))
+}
+
 func (ots *Opentelemetry) initNoopTracerProvider() (tracerProvider, error) {
 	return &noopTracerProvider{TracerProvider: trace.NewNoopTracerProvider()}, nil
 }
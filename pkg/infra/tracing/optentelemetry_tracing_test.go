@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/attribute"
@@ -86,4 +87,15 @@ func TestOptentelemetry_ParseSettingsOpentelemetry(t *testing.T) {
 	assert.NoError(t, otel.parseSettingsOpentelemetry())
 	assert.Equal(t, "somehost:4317", otel.address)
 	assert.Equal(t, otlpExporter, otel.enabled)
+
+	assert.False(t, otel.tailSampling)
+
+	otelsect.Key("sampling_rate").SetValue("0.1")
+	otelsect.Key("component_sampling_rates").SetValue("alerting:1,search:0.01")
+	otlpsect.Key("tail_sampling_enabled").SetValue("true")
+	assert.NoError(t, otel.parseSettingsOpentelemetry())
+	assert.Equal(t, 0.1, otel.samplingRate)
+	assert.Equal(t, map[string]float64{"alerting": 1, "search": 0.01}, otel.componentRates)
+	assert.True(t, otel.tailSampling)
+	assert.Equal(t, time.Second, otel.tailSamplingThreshold)
 }
@@ -0,0 +1,209 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	trace "go.opentelemetry.io/otel/trace"
+)
+
+// ComponentKey is the attribute naming the component a span belongs to, it selects the sampling rate of the span.
+const ComponentKey = attribute.Key("component")
+
+const (
+	// maxPendingTraces bounds the number of traces buffered by the tail sampling
+	maxPendingTraces = 10000
+	// pendingTraceTTL is how long the spans of a trace whose local root never ends are buffered
+	pendingTraceTTL = 5 * time.Minute
+)
+
+// WithComponent sets the component of a new span, the span is sampled with the rate configured for the component.
+// The component is only taken into account for the spans starting a trace, the other spans follow their parent.
+func WithComponent(component string) trace.SpanStartOption {
+	return trace.WithAttributes(ComponentKey.String(component))
+}
+
+// componentSampler samples the traces with the rate of the component of their root span, or the
+// default rate when the component has no rate of its own.
+type componentSampler struct {
+	defaultSampler tracesdk.Sampler
+	components     map[string]tracesdk.Sampler
+}
+
+func newComponentSampler(defaultRate float64, componentRates map[string]float64) tracesdk.Sampler {
+	s := &componentSampler{
+		defaultSampler: tracesdk.TraceIDRatioBased(defaultRate),
+		components:     make(map[string]tracesdk.Sampler, len(componentRates)),
+	}
+	for component, rate := range componentRates {
+		s.components[component] = tracesdk.TraceIDRatioBased(rate)
+	}
+	return s
+}
+
+func (s *componentSampler) ShouldSample(p tracesdk.SamplingParameters) tracesdk.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key != ComponentKey {
+			continue
+		}
+		if sampler, ok := s.components[attr.Value.AsString()]; ok {
+			return sampler.ShouldSample(p)
+		}
+		break
+	}
+	return s.defaultSampler.ShouldSample(p)
+}
+
+func (s *componentSampler) Description() string {
+	return fmt.Sprintf("ComponentSampler{default:%s,components:%d}", s.defaultSampler.Description(), len(s.components))
+}
+
+// recordingSampler records the spans which are not sampled, so that the tail sampling can still export them.
+type recordingSampler struct {
+	tracesdk.Sampler
+}
+
+func (s recordingSampler) ShouldSample(p tracesdk.SamplingParameters) tracesdk.SamplingResult {
+	res := s.Sampler.ShouldSample(p)
+	if res.Decision == tracesdk.Drop {
+		res.Decision = tracesdk.RecordOnly
+	}
+	return res
+}
+
+func (s recordingSampler) Description() string {
+	return fmt.Sprintf("Recording{%s}", s.Sampler.Description())
+}
+
+// splitComponentRates parses the sampling rates by component, in the component:rate form.
+func splitComponentRates(s string) (map[string]float64, error) {
+	res := map[string]float64{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("component sampling rate malformed - must be in 'component:rate' form: %q", v)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("component sampling rate must be between 0 and 1: %q", v)
+		}
+		res[strings.TrimSpace(parts[0])] = rate
+	}
+	return res, nil
+}
+
+type pendingTrace struct {
+	spans   []tracesdk.ReadOnlySpan
+	keep    bool
+	created time.Time
+}
+
+// tailSamplingProcessor forwards the sampled spans to the next processor, and buffers the spans which are
+// not sampled until the local root span of their trace ends. The whole trace is then forwarded when one
+// of its spans failed or was slower than the latency threshold, and dropped otherwise.
+type tailSamplingProcessor struct {
+	next             tracesdk.SpanProcessor
+	latencyThreshold time.Duration
+	now              func() time.Time
+
+	mu        sync.Mutex
+	traces    map[trace.TraceID]*pendingTrace
+	lastSweep time.Time
+}
+
+func newTailSamplingProcessor(next tracesdk.SpanProcessor, latencyThreshold time.Duration) *tailSamplingProcessor {
+	return &tailSamplingProcessor{
+		next:             next,
+		latencyThreshold: latencyThreshold,
+		now:              time.Now,
+		traces:           map[trace.TraceID]*pendingTrace{},
+	}
+}
+
+func (p *tailSamplingProcessor) OnStart(parent context.Context, s tracesdk.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *tailSamplingProcessor) OnEnd(s tracesdk.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.next.OnEnd(s)
+		return
+	}
+
+	keep := s.Status().Code == codes.Error || (p.latencyThreshold > 0 && s.EndTime().Sub(s.StartTime()) >= p.latencyThreshold)
+	isLocalRoot := !s.Parent().IsValid() || s.Parent().IsRemote()
+
+	p.mu.Lock()
+	traceID := s.SpanContext().TraceID()
+	t, ok := p.traces[traceID]
+	if !ok {
+		p.sweep()
+		if isLocalRoot || len(p.traces) >= maxPendingTraces {
+			// the span can't be buffered with the rest of its trace
+			p.mu.Unlock()
+			if keep {
+				p.next.OnEnd(sampledSpan{s})
+			}
+			return
+		}
+		t = &pendingTrace{created: p.now()}
+		p.traces[traceID] = t
+	}
+	t.spans = append(t.spans, s)
+	t.keep = t.keep || keep
+	if !isLocalRoot {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.traces, traceID)
+	p.mu.Unlock()
+
+	if t.keep {
+		for _, span := range t.spans {
+			p.next.OnEnd(sampledSpan{span})
+		}
+	}
+}
+
+// sweep drops the traces whose local root span didn't end in time, it must be called with the lock held.
+func (p *tailSamplingProcessor) sweep() {
+	now := p.now()
+	if now.Sub(p.lastSweep) < pendingTraceTTL {
+		return
+	}
+	for id, t := range p.traces {
+		if now.Sub(t.created) >= pendingTraceTTL {
+			delete(p.traces, id)
+		}
+	}
+	p.lastSweep = now
+}
+
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// sampledSpan marks a recorded span as sampled, for the exporting processors to accept it.
+type sampledSpan struct {
+	tracesdk.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	trace "go.opentelemetry.io/otel/trace"
+)
+
+func TestSplitComponentRates(t *testing.T) {
+	rates, err := splitComponentRates("alerting:1, search:0.01")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"alerting": 1, "search": 0.01}, rates)
+
+	for _, input := range []string{"alerting", "alerting:high", "search:2"} {
+		_, err := splitComponentRates(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestComponentSampler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := tracesdk.NewTracerProvider(
+		tracesdk.WithSpanProcessor(recorder),
+		tracesdk.WithSampler(tracesdk.ParentBased(newComponentSampler(0, map[string]float64{"alerting": 1}))),
+	)
+	tracer := tp.Tracer("test")
+
+	ctx, span := tracer.Start(context.Background(), "alert rule execution", WithComponent("alerting"))
+	_, child := tracer.Start(ctx, "evaluate")
+	child.End()
+	span.End()
+	_, span = tracer.Start(context.Background(), "searchV2 build signal", WithComponent("search"))
+	span.End()
+	_, span = tracer.Start(context.Background(), "no component")
+	span.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "evaluate", ended[0].Name())
+	assert.Equal(t, "alert rule execution", ended[1].Name())
+}
+
+func TestTailSamplingProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := tracesdk.NewTracerProvider(
+		tracesdk.WithSpanProcessor(newTailSamplingProcessor(recorder, time.Second)),
+		tracesdk.WithSampler(recordingSampler{Sampler: tracesdk.ParentBased(newComponentSampler(0, nil))}),
+	)
+	tracer := tp.Tracer("test")
+	start := time.Now()
+
+	t.Run("Traces without failed or slow spans are dropped", func(t *testing.T) {
+		ctx, root := tracer.Start(context.Background(), "fast")
+		_, child := tracer.Start(ctx, "fast child")
+		child.End()
+		root.End()
+
+		assert.Empty(t, recorder.Ended())
+	})
+
+	t.Run("Traces with a failed span are exported", func(t *testing.T) {
+		ctx, root := tracer.Start(context.Background(), "root")
+		_, child := tracer.Start(ctx, "failed child")
+		child.SetStatus(codes.Error, "failed")
+		child.End()
+		assert.Empty(t, recorder.Ended(), "the trace is exported once its root ends")
+		root.End()
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 2)
+		for _, s := range ended {
+			assert.Equal(t, root.SpanContext().TraceID(), s.SpanContext().TraceID())
+			assert.True(t, s.SpanContext().IsSampled())
+		}
+	})
+
+	t.Run("Slow spans are exported", func(t *testing.T) {
+		_, root := tracer.Start(context.Background(), "slow", trace.WithTimestamp(start))
+		root.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 3)
+		assert.Equal(t, "slow", ended[2].Name())
+	})
+}
@@ -35,3 +35,5 @@ func (usm *UsageStatsMock) ShouldBeReported(_ context.Context, s string) bool {
 }
 
 func (usm *UsageStatsMock) RegisterSendReportCallback(_ SendReportCallbackFunc) {}
+
+func (usm *UsageStatsMock) RegisterExporter(_ Exporter) {}
@@ -19,9 +19,16 @@ type MetricsFunc func(context.Context) (map[string]interface{}, error)
 
 type SendReportCallbackFunc func()
 
+// Exporter is a destination of the usage stats report, in addition to Grafana.com.
+type Exporter interface {
+	Name() string
+	Export(context.Context, Report) error
+}
+
 type Service interface {
 	GetUsageReport(context.Context) (Report, error)
 	RegisterMetricsFunc(MetricsFunc)
 	RegisterSendReportCallback(SendReportCallbackFunc)
+	RegisterExporter(Exporter)
 	ShouldBeReported(context.Context, string) bool
 }
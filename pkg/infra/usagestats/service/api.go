@@ -1,7 +1,9 @@
 package service
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -17,6 +19,7 @@ func (uss *UsageStats) registerAPIEndpoints() {
 
 	uss.RouteRegister.Group(rootUrl, func(subrouter routing.RouteRegister) {
 		subrouter.Get("/usage-report-preview", authorize(middleware.ReqGrafanaAdmin, accesscontrol.EvalPermission(ActionRead)), routing.Wrap(uss.getUsageReportPreview))
+		subrouter.Get("/usage-reports", authorize(middleware.ReqGrafanaAdmin, accesscontrol.EvalPermission(ActionRead)), routing.Wrap(uss.getStoredUsageReports))
 	})
 }
 
@@ -28,3 +31,28 @@ func (uss *UsageStats) getUsageReportPreview(ctx *contextmodel.ReqContext) respo
 
 	return response.JSON(http.StatusOK, usageReport)
 }
+
+type storedReportDTO struct {
+	ID      int64           `json:"id"`
+	Created time.Time       `json:"created"`
+	Report  json.RawMessage `json:"report"`
+}
+
+// getStoredUsageReports returns the most recent reports written by the local exporter.
+func (uss *UsageStats) getStoredUsageReports(ctx *contextmodel.ReqContext) response.Response {
+	limit := ctx.QueryInt("limit")
+	if limit <= 0 || limit > 1000 {
+		limit = 30
+	}
+
+	reports, err := uss.localStore.list(ctx.Req.Context(), limit)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "failed to get stored usage reports", err)
+	}
+
+	result := make([]storedReportDTO, 0, len(reports))
+	for _, r := range reports {
+		result = append(result, storedReportDTO{ID: r.ID, Created: r.Created, Report: json.RawMessage(r.Report)})
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// grafanaComExporter sends the report to Grafana.com, it is active when reporting_enabled is set.
+type grafanaComExporter struct {
+	uss *UsageStats
+}
+
+func (e *grafanaComExporter) Name() string {
+	return "grafana_com"
+}
+
+func (e *grafanaComExporter) Export(ctx context.Context, report usagestats.Report) error {
+	out, err := json.MarshalIndent(report, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return sendUsageStats(e.uss, ctx, bytes.NewBuffer(out))
+}
+
+// httpExporter posts the report to a configurable endpoint.
+type httpExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPExporter(cfg setting.UsageStatsExportSettings) *httpExporter {
+	return &httpExporter{
+		url:     cfg.HTTPURL,
+		headers: cfg.HTTPHeaders,
+		client:  &http.Client{Timeout: cfg.HTTPTimeout},
+	}
+}
+
+func (e *httpExporter) Name() string {
+	return setting.UsageStatsExporterHTTP
+}
+
+func (e *httpExporter) Export(ctx context.Context, report usagestats.Report) error {
+	out, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(out))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, e.url)
+	}
+	return nil
+}
+
+// storedReport is a usage stats report written to the database by the local exporter.
+type storedReport struct {
+	ID      int64 `xorm:"pk autoincr 'id'"`
+	Created time.Time
+	Report  string
+}
+
+func (storedReport) TableName() string {
+	return "usage_stats_report"
+}
+
+// localExporter writes the report to the usage_stats_report table and deletes the reports
+// older than the retention, so that air-gapped instances can keep a history of their usage.
+type localExporter struct {
+	db        db.DB
+	retention time.Duration
+	now       func() time.Time
+}
+
+func newLocalExporter(sqlStore db.DB, cfg setting.UsageStatsExportSettings) *localExporter {
+	return &localExporter{
+		db:        sqlStore,
+		retention: cfg.LocalRetention,
+		now:       time.Now,
+	}
+}
+
+func (e *localExporter) Name() string {
+	return setting.UsageStatsExporterLocal
+}
+
+func (e *localExporter) Export(ctx context.Context, report usagestats.Report) error {
+	out, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	now := e.now()
+	return e.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(&storedReport{Created: now, Report: string(out)}); err != nil {
+			return err
+		}
+
+		if e.retention <= 0 {
+			return nil
+		}
+		_, err := sess.Where("created < ?", now.Add(-e.retention)).Delete(&storedReport{})
+		return err
+	})
+}
+
+func (e *localExporter) list(ctx context.Context, limit int) ([]*storedReport, error) {
+	reports := make([]*storedReport, 0, limit)
+	err := e.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Desc("created").Limit(limit).Find(&reports)
+	})
+	return reports, err
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/db/dbtest"
+	"github.com/grafana/grafana/pkg/infra/usagestats"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type recordingExporter struct {
+	reports []usagestats.Report
+	err     error
+}
+
+func (e *recordingExporter) Name() string {
+	return "recording"
+}
+
+func (e *recordingExporter) Export(_ context.Context, report usagestats.Report) error {
+	e.reports = append(e.reports, report)
+	return e.err
+}
+
+func TestRegisteredExporters(t *testing.T) {
+	uss := createService(t, setting.Cfg{}, dbtest.NewFakeDB(), false)
+	uss.RegisterMetricsFunc(func(context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"stats.test_metric.count": 1}, nil
+	})
+
+	t.Run("exporters are called when reporting to Grafana.com is disabled", func(t *testing.T) {
+		exporter := &recordingExporter{}
+		uss.exporters = []usagestats.Exporter{exporter}
+
+		_, err := uss.sendUsageStats(context.Background())
+		require.NoError(t, err)
+		require.Len(t, exporter.reports, 1)
+		assert.Equal(t, 1, exporter.reports[0].Metrics["stats.test_metric.count"])
+	})
+
+	t.Run("a failing exporter does not prevent the others from being called", func(t *testing.T) {
+		failing := &recordingExporter{err: errors.New("boom")}
+		exporter := &recordingExporter{}
+		uss.exporters = []usagestats.Exporter{failing, exporter}
+
+		_, err := uss.sendUsageStats(context.Background())
+		require.Error(t, err)
+		require.Len(t, failing.reports, 1)
+		require.Len(t, exporter.reports, 1)
+	})
+}
+
+func TestHTTPExporter(t *testing.T) {
+	var received usagestats.Report
+	var authHeader string
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+
+	exporter := newHTTPExporter(setting.UsageStatsExportSettings{
+		HTTPURL:     ts.URL,
+		HTTPHeaders: map[string]string{"Authorization": "Bearer token"},
+		HTTPTimeout: time.Second,
+	})
+
+	err := exporter.Export(context.Background(), usagestats.Report{Version: "10_0_0"})
+	require.NoError(t, err)
+	assert.Equal(t, "10_0_0", received.Version)
+	assert.Equal(t, "Bearer token", authHeader)
+
+	status = http.StatusInternalServerError
+	err = exporter.Export(context.Background(), usagestats.Report{Version: "10_0_0"})
+	require.Error(t, err)
+}
+
+func TestIntegrationLocalExporter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+
+	exporter := newLocalExporter(sqlStore, setting.UsageStatsExportSettings{LocalRetention: 24 * time.Hour})
+	now := time.Now().Truncate(time.Second)
+
+	exporter.now = func() time.Time { return now.Add(-30 * time.Hour) }
+	require.NoError(t, exporter.Export(context.Background(), usagestats.Report{Version: "old"}))
+
+	exporter.now = func() time.Time { return now.Add(-12 * time.Hour) }
+	require.NoError(t, exporter.Export(context.Background(), usagestats.Report{Version: "recent"}))
+
+	reports, err := exporter.list(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	exporter.now = func() time.Time { return now }
+	require.NoError(t, exporter.Export(context.Background(), usagestats.Report{Version: "new"}))
+
+	reports, err = exporter.list(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	var latest usagestats.Report
+	require.NoError(t, json.Unmarshal([]byte(reports[0].Report), &latest))
+	assert.Equal(t, "new", latest.Version)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -28,11 +29,14 @@ type UsageStats struct {
 
 	externalMetrics     []usagestats.MetricsFunc
 	sendReportCallbacks []usagestats.SendReportCallbackFunc
+	exporters           []usagestats.Exporter
+	localStore          *localExporter
 }
 
 func ProvideService(cfg *setting.Cfg,
 	pluginStore plugins.Store,
 	kvStore kvstore.KVStore,
+	sqlStore db.DB,
 	routeRegister routing.RouteRegister,
 	tracer tracing.Tracer,
 	accesscontrol ac.AccessControl,
@@ -47,6 +51,14 @@ func ProvideService(cfg *setting.Cfg,
 		log:           log.New("infra.usagestats"),
 		tracer:        tracer,
 		accesscontrol: accesscontrol,
+		localStore:    newLocalExporter(sqlStore, cfg.UsageStatsExport),
+	}
+
+	if cfg.UsageStatsExport.IsEnabled(setting.UsageStatsExporterHTTP) {
+		s.RegisterExporter(newHTTPExporter(cfg.UsageStatsExport))
+	}
+	if cfg.UsageStatsExport.IsEnabled(setting.UsageStatsExporterLocal) {
+		s.RegisterExporter(s.localStore)
 	}
 
 	if !accesscontrol.IsDisabled() {
@@ -115,6 +127,20 @@ func (uss *UsageStats) RegisterSendReportCallback(c usagestats.SendReportCallbac
 	uss.sendReportCallbacks = append(uss.sendReportCallbacks, c)
 }
 
+// RegisterExporter adds a destination for the usage stats report. Exporters
+// are called on every report, regardless of reporting_enabled.
+func (uss *UsageStats) RegisterExporter(e usagestats.Exporter) {
+	uss.exporters = append(uss.exporters, e)
+}
+
+func (uss *UsageStats) activeExporters() []usagestats.Exporter {
+	exporters := make([]usagestats.Exporter, 0, len(uss.exporters)+1)
+	if uss.Cfg.ReportingEnabled {
+		exporters = append(exporters, &grafanaComExporter{uss: uss})
+	}
+	return append(exporters, uss.exporters...)
+}
+
 func (uss *UsageStats) ShouldBeReported(ctx context.Context, dsType string) bool {
 	ds, exists := uss.pluginStore.Plugin(ctx, dsType)
 	if !exists {
@@ -3,7 +3,6 @@ package service
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -96,13 +95,14 @@ func (uss *UsageStats) RegisterMetricsFunc(fn usagestats.MetricsFunc) {
 }
 
 func (uss *UsageStats) sendUsageStats(ctx context.Context) (string, error) {
-	if !uss.Cfg.ReportingEnabled {
+	exporters := uss.activeExporters()
+	if len(exporters) == 0 {
 		return "", nil
 	}
 	ctx, span := uss.tracer.Start(ctx, "UsageStats.BackgroundJob")
 	defer span.End()
 	traceID := tracing.TraceIDFromContext(ctx, false)
-	uss.log.FromContext(ctx).Debug("Sending anonymous usage stats", "url", usageStatsURL)
+	uss.log.FromContext(ctx).Debug("Sending anonymous usage stats", "exporters", len(exporters))
 	start := time.Now()
 
 	report, err := uss.GetUsageReport(ctx)
@@ -110,15 +110,15 @@ func (uss *UsageStats) sendUsageStats(ctx context.Context) (string, error) {
 		return traceID, err
 	}
 
-	out, err := json.MarshalIndent(report, "", " ")
-	if err != nil {
-		return traceID, err
+	var failed []string
+	for _, exporter := range exporters {
+		if err := exporter.Export(ctx, report); err != nil {
+			uss.log.FromContext(ctx).Warn("Failed to export usage stats", "exporter", exporter.Name(), "error", err)
+			failed = append(failed, exporter.Name())
+		}
 	}
-
-	data := bytes.NewBuffer(out)
-	err = sendUsageStats(uss, ctx, data)
-	if err != nil {
-		return traceID, err
+	if len(failed) > 0 {
+		return traceID, fmt.Errorf("failed to export usage stats to %s", strings.Join(failed, ", "))
 	}
 
 	uss.log.FromContext(ctx).Info("Sent usage stats", "duration", time.Since(start))
@@ -220,6 +220,7 @@ func createService(t *testing.T, cfg setting.Cfg, sqlStore db.DB, withDB bool) *
 		&cfg,
 		&plugins.FakePluginStore{},
 		kvstore.ProvideService(sqlStore),
+		sqlStore,
 		routing.NewRouteRegister(),
 		tracing.InitializeTracerForTest(),
 		actest.FakeAccessControl{ExpectedDisabled: true},
@@ -96,7 +96,7 @@ func TestSocialOkta_UserInfo(t *testing.T) {
 			defer server.Close()
 			provider := &SocialOkta{
 				SocialBase: newSocialBase("okta", &oauth2.Config{},
-					&OAuthInfo{RoleAttributePath: tt.RoleAttributePath}, tt.autoAssignOrgRole, false, *featuremgmt.WithFeatures()),
+					&OAuthInfo{RoleAttributePath: tt.RoleAttributePath}, tt.autoAssignOrgRole, false, featuremgmt.WithFeatures()),
 				apiUrl:          server.URL + "/user",
 				skipOrgRoleSync: tt.settingSkipOrgRoleSync,
 			}
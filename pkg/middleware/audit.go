@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log/audit"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// Audit emits an audit event for every mutating request to the HTTP API once it has been handled.
+// The kind of resource is the first segment of the path after /api/, the request is a failure when
+// its status is 400 or above.
+func Audit(auditLogger audit.Logger) web.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutatingMethod(r.Method) || !strings.HasPrefix(r.URL.Path, "/api/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := web.Rw(w, r)
+			next.ServeHTTP(rw, r)
+
+			c := contexthandler.FromContext(r.Context())
+			if c == nil {
+				return
+			}
+
+			result := audit.ResultSuccess
+			if rw.Status() >= http.StatusBadRequest {
+				result = audit.ResultFailure
+			}
+			kind, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+
+			event := audit.Event{
+				Action:   audit.ActionAPIMutation,
+				Result:   result,
+				Actor:    audit.Actor{OrgID: c.OrgID, IP: c.RemoteAddr()},
+				Resource: audit.Resource{Kind: kind},
+				Fields: map[string]any{
+					"method": r.Method,
+					"path":   r.URL.Path,
+					"status": rw.Status(),
+				},
+			}
+			if c.IsSignedIn {
+				event.Actor.UserID = c.UserID
+				event.Actor.Login = c.Login
+			}
+			auditLogger.Log(r.Context(), event)
+		})
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
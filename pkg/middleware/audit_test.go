@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/audit"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+type fakeAuditLogger struct {
+	events []audit.Event
+}
+
+func (l *fakeAuditLogger) Log(_ context.Context, event audit.Event) {
+	l.events = append(l.events, event)
+}
+
+func TestAudit(t *testing.T) {
+	middlewareScenario(t, "Mutating API requests are audited", func(t *testing.T, sc *scenarioContext) {
+		auditLogger := &fakeAuditLogger{}
+		sc.m.UseMiddleware(Audit(auditLogger))
+		sc.m.Post("/api/dashboards/db", func(c *contextmodel.ReqContext) {
+			c.JsonApiErr(http.StatusForbidden, "Access denied", nil)
+		})
+		sc.m.Get("/api/dashboards/uid/:uid", sc.defaultHandler)
+
+		sc.fakeReq("POST", "/api/dashboards/db").exec()
+		sc.fakeReq("GET", "/api/dashboards/uid/abc").exec()
+
+		require.Len(t, auditLogger.events, 1)
+		e := auditLogger.events[0]
+		assert.Equal(t, audit.ActionAPIMutation, e.Action)
+		assert.Equal(t, audit.ResultFailure, e.Result)
+		assert.Equal(t, "dashboards", e.Resource.Kind)
+		assert.Equal(t, http.StatusForbidden, e.Fields["status"])
+		assert.Equal(t, "/api/dashboards/db", e.Fields["path"])
+	})
+}
@@ -14,8 +14,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
@@ -25,6 +27,9 @@ type AuthOptions struct {
 	ReqGrafanaAdmin bool
 	ReqSignedIn     bool
 	ReqNoAnonynmous bool
+	// AllowSignedRenderURL lets the requests with a signed render URL through,
+	// their signature is validated by the render handler.
+	AllowSignedRenderURL bool
 }
 
 func accessForbidden(c *contextmodel.ReqContext) {
@@ -104,7 +109,7 @@ func RoleAuth(roles ...org.RoleType) web.Handler {
 	return func(c *contextmodel.ReqContext) {
 		ok := false
 		for _, role := range roles {
-			if role == c.OrgRole {
+			if role == c.OrgRole && c.PermissionBoundary == nil {
 				ok = true
 				break
 			}
@@ -117,6 +122,10 @@ func RoleAuth(roles ...org.RoleType) web.Handler {
 
 func Auth(options *AuthOptions) web.Handler {
 	return func(c *contextmodel.ReqContext) {
+		if options.AllowSignedRenderURL && rendering.IsSignedURL(c.Req.URL.Query()) {
+			return
+		}
+
 		forceLogin := false
 		if c.AllowAnonymous {
 			forceLogin = shouldForceLogin(c)
@@ -156,6 +165,11 @@ func Auth(options *AuthOptions) web.Handler {
 // are otherwise only available to admins.
 func AdminOrEditorAndFeatureEnabled(enabled bool) web.Handler {
 	return func(c *contextmodel.ReqContext) {
+		if c.PermissionBoundary != nil {
+			accessForbidden(c)
+			return
+		}
+
 		if c.OrgRole == org.RoleAdmin {
 			return
 		}
@@ -169,13 +183,18 @@ func AdminOrEditorAndFeatureEnabled(enabled bool) web.Handler {
 }
 
 // SnapshotPublicModeOrSignedIn creates a middleware that allows access
-// if snapshot public mode is enabled or if user is signed in.
+// if snapshot public mode is enabled, if the request has a snapshot server
+// token or if user is signed in.
 func SnapshotPublicModeOrSignedIn(cfg *setting.Cfg) web.Handler {
 	return func(c *contextmodel.ReqContext) {
 		if cfg.SnapshotPublicMode {
 			return
 		}
 
+		if _, ok := dashboardsnapshots.OrgIDForServerToken(cfg, c.Req.Header.Get(dashboardsnapshots.ServerTokenHeader)); ok {
+			return
+		}
+
 		if !c.IsSignedIn {
 			notAuthorized(c)
 			return
@@ -214,7 +233,7 @@ func shouldForceLogin(c *contextmodel.ReqContext) bool {
 
 func OrgAdminDashOrFolderAdminOrTeamAdmin(ss db.DB, ds dashboards.DashboardService, ts team.Service) func(c *contextmodel.ReqContext) {
 	return func(c *contextmodel.ReqContext) {
-		if c.OrgRole == org.RoleAdmin {
+		if c.OrgRole == org.RoleAdmin && c.PermissionBoundary == nil {
 			return
 		}
 
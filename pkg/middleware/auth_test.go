@@ -9,6 +9,7 @@ import (
 
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -110,6 +111,39 @@ func TestMiddlewareAuth(t *testing.T) {
 		sc.fakeReq("GET", "/api/snapshot").exec()
 		assert.Equal(t, 200, sc.resp.Code)
 	})
+
+	middlewareScenario(t, "Org admin request should be allowed by the role", func(
+		t *testing.T, sc *scenarioContext) {
+		sc.m.Get("/api/admin", func(c *contextmodel.ReqContext) {
+			c.SignedInUser = &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin}
+		}, ReqOrgAdmin, sc.defaultHandler)
+		sc.fakeReq("GET", "/api/admin").exec()
+		assert.Equal(t, 200, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "Org admin request restricted by a permission boundary should not be allowed by the role", func(
+		t *testing.T, sc *scenarioContext) {
+		sc.m.Get("/api/admin", func(c *contextmodel.ReqContext) {
+			c.SignedInUser = &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, IsServiceAccount: true,
+				PermissionBoundary: map[string][]string{"dashboards:read": {""}}}
+		}, ReqOrgAdmin, sc.defaultHandler)
+		sc.fakeReq("GET", "/api/admin").exec()
+		assert.Equal(t, 403, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "Unauthenticated render request should return 401", func(
+		t *testing.T, sc *scenarioContext) {
+		sc.m.Get("/render/*", ReqSignedInOrSignedRenderURL, sc.defaultHandler)
+		sc.fakeReq("GET", "/render/d-solo/abc/dash?panelId=1").exec()
+		assert.Equal(t, 302, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "Unauthenticated render request with a signed URL should be validated by the handler", func(
+		t *testing.T, sc *scenarioContext) {
+		sc.m.Get("/render/*", ReqSignedInOrSignedRenderURL, sc.defaultHandler)
+		sc.fakeReq("GET", "/render/d-solo/abc/dash?panelId=1&signature=abc").exec()
+		assert.Equal(t, 200, sc.resp.Code)
+	})
 }
 
 func TestRemoveForceLoginparams(t *testing.T) {
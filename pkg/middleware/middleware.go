@@ -17,8 +17,10 @@ var (
 	})
 	ReqSignedIn            = Auth(&AuthOptions{ReqSignedIn: true})
 	ReqSignedInNoAnonymous = Auth(&AuthOptions{ReqSignedIn: true, ReqNoAnonynmous: true})
-	ReqEditorRole          = RoleAuth(org.RoleEditor, org.RoleAdmin)
-	ReqOrgAdmin            = RoleAuth(org.RoleAdmin)
+	// ReqSignedInOrSignedRenderURL allows the render requests of signed URLs, which have no user.
+	ReqSignedInOrSignedRenderURL = Auth(&AuthOptions{ReqSignedIn: true, AllowSignedRenderURL: true})
+	ReqEditorRole                = RoleAuth(org.RoleEditor, org.RoleAdmin)
+	ReqOrgAdmin                  = RoleAuth(org.RoleAdmin)
 )
 
 func HandleNoCacheHeader(ctx *contextmodel.ReqContext) {
@@ -37,8 +39,9 @@ func AddDefaultResponseHeaders(cfg *setting.Cfg) web.Handler {
 
 			_, _, resourceURLMatch := t.Match(c.Req.URL.Path)
 			resourceCachable := resourceURLMatch && allowCacheControl(c.Resp)
+			publicDashboardCachable := strings.HasPrefix(c.Req.URL.Path, "/api/public/dashboards/") && allowPublicDashboardCacheControl(c.Resp)
 			if !strings.HasPrefix(c.Req.URL.Path, "/public/plugins/") &&
-				!strings.HasPrefix(c.Req.URL.Path, "/api/datasources/proxy/") && !resourceCachable {
+				!strings.HasPrefix(c.Req.URL.Path, "/api/datasources/proxy/") && !resourceCachable && !publicDashboardCachable {
 				addNoCacheHeaders(c.Resp)
 			}
 
@@ -121,3 +124,9 @@ func allowCacheControl(rw web.ResponseWriter) bool {
 
 	return foundPrivate && !foundPublic && rw.Header().Get("X-Grafana-Cache") != ""
 }
+
+// allowPublicDashboardCacheControl returns true for the responses of the public dashboards cached by CDNs, which are
+// public and have a surrogate key to purge them.
+func allowPublicDashboardCacheControl(rw web.ResponseWriter) bool {
+	return strings.Contains(rw.Header().Get("Cache-Control"), "public") && rw.Header().Get("Surrogate-Key") != ""
+}
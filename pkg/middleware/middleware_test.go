@@ -41,8 +41,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/login/loginservice"
 	"github.com/grafana/grafana/pkg/services/login/logintest"
 	"github.com/grafana/grafana/pkg/services/navtree"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+	"github.com/grafana/grafana/pkg/services/oauthserver/oauthservertest"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
@@ -172,6 +175,19 @@ func TestMiddlewareContext(t *testing.T) {
 		assert.Equal(t, noStore, sc.resp.Header().Get("Cache-Control"))
 	})
 
+	middlewareScenario(t, "middleware should pass cache-control on public dashboards cached by CDNs", func(t *testing.T, sc *scenarioContext) {
+		sc = sc.fakeReq("GET", "/api/public/dashboards/abc")
+		sc.resp.Header().Add("Cache-Control", "public, max-age=60, s-maxage=60")
+		sc.resp.Header().Add("Surrogate-Key", "publicdashboard-abc")
+		sc.exec()
+		assert.Equal(t, "public, max-age=60, s-maxage=60", sc.resp.Header().Get("Cache-Control"))
+	})
+
+	middlewareScenario(t, "middleware should add Cache-Control header for public dashboards not cached by CDNs", func(t *testing.T, sc *scenarioContext) {
+		sc.fakeReq("GET", "/api/public/dashboards/abc").exec()
+		assert.Equal(t, noStore, sc.resp.Header().Get("Cache-Control"))
+	})
+
 	middlewareScenario(t, "middleware should not add Cache-Control header for requests to datasource proxy API", func(
 		t *testing.T, sc *scenarioContext) {
 		sc.fakeReq("GET", "/api/datasources/proxy/1/test").exec()
@@ -324,6 +340,27 @@ func TestMiddlewareContext(t *testing.T) {
 		assert.Equal(t, "Expired API key", sc.respJson["message"])
 	})
 
+	middlewareScenario(t, "Valid OAuth access token", func(t *testing.T, sc *scenarioContext) {
+		sc.oauthServer.ExpectedAccessToken = &oauthserver.AccessToken{OrgID: 2, ClientID: "client", ServiceAccountID: 3, Scopes: []string{"dashboards:read"}}
+		sc.userService.ExpectedSignedInUser = &user.SignedInUser{OrgID: 2, UserID: 3, IsServiceAccount: true}
+
+		sc.fakeReq("GET", "/").withAuthorizationHeader("Bearer gloa_secret_checksum").exec()
+
+		require.Equal(t, 200, sc.resp.Code)
+		assert.True(t, sc.context.IsSignedIn)
+		assert.Equal(t, int64(3), sc.context.UserID)
+		assert.Equal(t, map[string][]string{"dashboards:read": {""}}, sc.context.PermissionBoundary)
+	})
+
+	middlewareScenario(t, "Invalid OAuth access token", func(t *testing.T, sc *scenarioContext) {
+		sc.oauthServer.ExpectedError = oauthserver.ErrInvalidToken.Errorf("access token expired")
+
+		sc.fakeReq("GET", "/").withAuthorizationHeader("Bearer gloa_secret_checksum").exec()
+
+		assert.Equal(t, 401, sc.resp.Code)
+		assert.Equal(t, "Invalid access token", sc.respJson["message"])
+	})
+
 	middlewareScenario(t, "Non-expired auth token in cookie which is not being rotated", func(
 		t *testing.T, sc *scenarioContext) {
 		const userID int64 = 12
@@ -900,7 +937,8 @@ func middlewareScenario(t *testing.T, desc string, fn scenarioFunc, cbs ...func(
 		sc.orgService = orgtest.NewOrgServiceFake()
 		sc.apiKeyService = &apikeytest.Service{}
 		sc.oauthTokenService = &authtest.FakeOAuthTokenService{}
-		ctxHdlr := getContextHandler(t, cfg, sc.mockSQLStore, sc.loginService, sc.apiKeyService, sc.userService, sc.orgService, sc.oauthTokenService)
+		sc.oauthServer = oauthservertest.NewFakeService()
+		ctxHdlr := getContextHandler(t, cfg, sc.mockSQLStore, sc.loginService, sc.apiKeyService, sc.userService, sc.orgService, sc.oauthTokenService, sc.oauthServer)
 		sc.sqlStore = ctxHdlr.SQLStore
 		sc.contextHandler = ctxHdlr
 		sc.m.Use(ctxHdlr.Middleware)
@@ -936,7 +974,7 @@ func middlewareScenario(t *testing.T, desc string, fn scenarioFunc, cbs ...func(
 func getContextHandler(t *testing.T, cfg *setting.Cfg, mockSQLStore *dbtest.FakeDB,
 	loginService *loginservice.LoginServiceMock, apiKeyService *apikeytest.Service,
 	userService *usertest.FakeUserService, orgService *orgtest.FakeOrgService,
-	oauthTokenService *authtest.FakeOAuthTokenService,
+	oauthTokenService *authtest.FakeOAuthTokenService, oauthServer *oauthservertest.FakeService,
 ) *contexthandler.ContextHandler {
 	t.Helper()
 
@@ -960,7 +998,8 @@ func getContextHandler(t *testing.T, cfg *setting.Cfg, mockSQLStore *dbtest.Fake
 		loginService, apiKeyService, authenticator, userService, orgService,
 		oauthTokenService,
 		featuremgmt.WithFeatures(featuremgmt.FlagAccessTokenExpirationCheck),
-		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{})
+		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{}, orgsettingstest.NewFakeService(cfg),
+		oauthServer)
 }
 
 type fakeRenderService struct {
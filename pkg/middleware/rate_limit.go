@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// RateLimit returns a function that returns a handler limiting the rate of the requests to a group of endpoints.
+// The requests are counted per user or API key, or per organization when the buckets are shared by organization.
+// The state of the bucket is reported with the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+func RateLimit(cfg *setting.Cfg, rateLimitService ratelimit.Service) func(string) web.Handler {
+	return func(group string) web.Handler {
+		return func(c *contextmodel.ReqContext) {
+			if rateLimitService == nil {
+				return
+			}
+
+			res, ok, err := rateLimitService.Allow(c.Req.Context(), group, rateLimitKey(cfg, c))
+			if err != nil {
+				// the requests are not limited while the buckets can't be reached
+				c.Logger.Warn("Failed to check rate limit", "group", group, "error", err)
+				return
+			}
+			if !ok {
+				return
+			}
+
+			header := c.Resp.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			header.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			if !res.Allowed {
+				header.Set("Retry-After", ceilSeconds(res.RetryAfter))
+				c.JsonApiErr(http.StatusTooManyRequests, fmt.Sprintf("Rate limit of %s requests reached", group), nil)
+			}
+		}
+	}
+}
+
+func rateLimitKey(cfg *setting.Cfg, c *contextmodel.ReqContext) string {
+	if cfg.CurrentRateLimiting().PerOrg {
+		return fmt.Sprintf("org-%d", c.OrgID)
+	}
+	if key, err := c.SignedInUser.GetCacheKey(); err == nil {
+		return key
+	}
+	return fmt.Sprintf("%d-ip-%s", c.OrgID, c.RemoteAddr())
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+)
+
+type fakeRateLimitService struct {
+	result ratelimit.Result
+	keys   []string
+}
+
+func (s *fakeRateLimitService) Allow(_ context.Context, group, key string) (ratelimit.Result, bool, error) {
+	s.keys = append(s.keys, group+":"+key)
+	return s.result, true, nil
+}
+
+func TestRateLimit(t *testing.T) {
+	middlewareScenario(t, "Allowed requests report the state of the bucket", func(t *testing.T, sc *scenarioContext) {
+		svc := &fakeRateLimitService{result: ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 1500 * time.Millisecond}}
+		sc.m.Get("/api/search", RateLimit(sc.cfg, svc)(ratelimit.GroupSearch), sc.defaultHandler)
+
+		sc.fakeReq("GET", "/api/search").exec()
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+		assert.Equal(t, "10", sc.resp.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "9", sc.resp.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", sc.resp.Header().Get("RateLimit-Reset"))
+		assert.Len(t, svc.keys, 1)
+	})
+
+	middlewareScenario(t, "Limited requests are rejected", func(t *testing.T, sc *scenarioContext) {
+		svc := &fakeRateLimitService{result: ratelimit.Result{Allowed: false, Limit: 10, Reset: 10 * time.Second, RetryAfter: time.Second}}
+		sc.m.Get("/api/search", RateLimit(sc.cfg, svc)(ratelimit.GroupSearch), sc.defaultHandler)
+
+		sc.fakeReq("GET", "/api/search").exec()
+
+		require.Equal(t, http.StatusTooManyRequests, sc.resp.Code)
+		assert.Equal(t, "1", sc.resp.Header().Get("Retry-After"))
+		assert.Equal(t, "0", sc.resp.Header().Get("RateLimit-Remaining"))
+	})
+
+	middlewareScenario(t, "Requests are counted per organization when configured", func(t *testing.T, sc *scenarioContext) {
+		sc.cfg.RateLimiting.PerOrg = true
+		svc := &fakeRateLimitService{result: ratelimit.Result{Allowed: true}}
+		sc.m.Get("/api/search", RateLimit(sc.cfg, svc)(ratelimit.GroupSearch), sc.defaultHandler)
+
+		sc.fakeReq("GET", "/api/search").exec()
+
+		assert.Equal(t, []string{"search:org-0"}, svc.keys)
+	})
+}
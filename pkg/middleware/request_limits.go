@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+var (
+	errRequestTooLarge   = errutil.NewBase(errutil.StatusPayloadTooLarge, "request.tooLarge").MustTemplate("request body for {{ .Public.Group }} larger than {{ .Public.Limit }} bytes", errutil.WithPublic("The request body is larger than the limit of {{ .Public.Limit }} bytes"))
+	errTooManyQueries    = errutil.NewBase(errutil.StatusBadRequest, "request.tooManyQueries").MustTemplate("request with {{ .Public.Count }} queries", errutil.WithPublic("The request has {{ .Public.Count }} queries, more than the limit of {{ .Public.Limit }}"))
+	errTimeRangeTooLarge = errutil.NewBase(errutil.StatusBadRequest, "request.timeRangeTooLarge").MustTemplate("query time range of {{ .Public.Range }}", errutil.WithPublic("The query time range is larger than the limit of {{ .Public.Limit }}"))
+)
+
+// RequestBodyLimit returns a handler rejecting the requests of a group of endpoints with a body larger
+// than the limit in bytes. A limit of 0 disables the check.
+func RequestBodyLimit(group string, limit int64) web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		if limit <= 0 || c.Req.Body == nil {
+			return
+		}
+
+		tooLarge := errRequestTooLarge.Build(errutil.TemplateData{
+			Public: map[string]any{"Group": group, "Limit": limit},
+		})
+		if c.Req.ContentLength > limit {
+			c.WriteErr(tooLarge)
+			return
+		}
+
+		// the content length can be missing or wrong, the body is read up to the limit to be sure
+		body, err := io.ReadAll(io.LimitReader(c.Req.Body, limit+1))
+		_ = c.Req.Body.Close()
+		if err != nil {
+			c.JsonApiErr(http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+		if int64(len(body)) > limit {
+			c.WriteErr(tooLarge)
+			return
+		}
+		c.Req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+}
+
+// QueryLimits returns a handler rejecting the data source query requests with more queries or
+// a larger time range than configured in the request limits.
+func QueryLimits(cfg *setting.Cfg) web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		limits := cfg.RequestLimits
+		if (limits.MaxQueriesPerRequest <= 0 && limits.MaxQueryTimeRange <= 0) || c.Req.Body == nil {
+			return
+		}
+
+		body, err := io.ReadAll(c.Req.Body)
+		_ = c.Req.Body.Close()
+		if err != nil {
+			c.JsonApiErr(http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+		c.Req.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req struct {
+			From    string            `json:"from"`
+			To      string            `json:"to"`
+			Queries []json.RawMessage `json:"queries"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			// invalid requests are reported by the handler
+			return
+		}
+
+		if limits.MaxQueriesPerRequest > 0 && len(req.Queries) > limits.MaxQueriesPerRequest {
+			c.WriteErr(errTooManyQueries.Build(errutil.TemplateData{
+				Public: map[string]any{"Count": len(req.Queries), "Limit": limits.MaxQueriesPerRequest},
+			}))
+			return
+		}
+
+		if limits.MaxQueryTimeRange > 0 && req.From != "" && req.To != "" {
+			timeRange := legacydata.NewDataTimeRange(req.From, req.To)
+			from, err := timeRange.ParseFrom()
+			if err != nil {
+				return
+			}
+			to, err := timeRange.ParseTo()
+			if err != nil {
+				return
+			}
+			if to.Sub(from) > limits.MaxQueryTimeRange {
+				c.WriteErr(errTimeRangeTooLarge.Build(errutil.TemplateData{
+					Public: map[string]any{"Range": to.Sub(from).String(), "Limit": limits.MaxQueryTimeRange.String()},
+				}))
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestRequestBodyLimit(t *testing.T) {
+	middlewareScenario(t, "Requests within the limit are passed on", func(t *testing.T, sc *scenarioContext) {
+		sc.m.Post("/api/dashboards/db", RequestBodyLimit("dashboard", 16), sc.defaultHandler)
+
+		sc.fakeReq("POST", "/api/dashboards/db")
+		sc.req.Body = io.NopCloser(strings.NewReader(`{"title":"a"}`))
+		sc.exec()
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+	})
+
+	middlewareScenario(t, "Requests over the limit are rejected", func(t *testing.T, sc *scenarioContext) {
+		sc.m.Post("/api/dashboards/db", RequestBodyLimit("dashboard", 16), sc.defaultHandler)
+
+		sc.fakeReq("POST", "/api/dashboards/db")
+		// the content length is unknown, the body is read to find out
+		sc.req.Body = io.NopCloser(strings.NewReader(`{"title":"a larger dashboard"}`))
+		sc.exec()
+
+		require.Equal(t, http.StatusRequestEntityTooLarge, sc.resp.Code)
+		assert.Equal(t, "request.tooLarge", sc.respJson["messageId"])
+	})
+}
+
+func TestQueryLimits(t *testing.T) {
+	limits := func(cfg *setting.Cfg) {
+		cfg.RequestLimits.MaxQueriesPerRequest = 2
+		cfg.RequestLimits.MaxQueryTimeRange = 24 * time.Hour
+	}
+
+	middlewareScenario(t, "Queries within the limits are passed on", func(t *testing.T, sc *scenarioContext) {
+		sc.m.Post("/api/ds/query", QueryLimits(sc.cfg), sc.defaultHandler)
+
+		sc.fakeReq("POST", "/api/ds/query")
+		sc.req.Body = io.NopCloser(strings.NewReader(`{"from":"now-6h","to":"now","queries":[{"refId":"A"},{"refId":"B"}]}`))
+		sc.exec()
+
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+	}, limits)
+
+	middlewareScenario(t, "Requests with too many queries are rejected", func(t *testing.T, sc *scenarioContext) {
+		sc.m.Post("/api/ds/query", QueryLimits(sc.cfg), sc.defaultHandler)
+
+		sc.fakeReq("POST", "/api/ds/query")
+		sc.req.Body = io.NopCloser(strings.NewReader(`{"from":"now-6h","to":"now","queries":[{"refId":"A"},{"refId":"B"},{"refId":"C"}]}`))
+		sc.exec()
+
+		require.Equal(t, http.StatusBadRequest, sc.resp.Code)
+		assert.Equal(t, "request.tooManyQueries", sc.respJson["messageId"])
+	}, limits)
+
+	middlewareScenario(t, "Requests with a too large time range are rejected", func(t *testing.T, sc *scenarioContext) {
+		sc.m.Post("/api/ds/query", QueryLimits(sc.cfg), sc.defaultHandler)
+
+		sc.fakeReq("POST", "/api/ds/query")
+		sc.req.Body = io.NopCloser(strings.NewReader(`{"from":"now-7d","to":"now","queries":[{"refId":"A"}]}`))
+		sc.exec()
+
+		require.Equal(t, http.StatusBadRequest, sc.resp.Code)
+		assert.Equal(t, "request.timeRangeTooLarge", sc.respJson["messageId"])
+	}, limits)
+}
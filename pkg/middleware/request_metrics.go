@@ -4,27 +4,32 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
-	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/infra/metrics/metricutil"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 var (
 	httpRequestsInFlight         prometheus.Gauge
 	httpRequestDurationHistogram *prometheus.HistogramVec
+	registerRequestMetricsOnce   sync.Once
 
 	// DefBuckets are histogram buckets for the response time (in seconds)
 	// of a network service, including one that is responding very slowly.
 	defBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25}
 )
 
-func init() {
+// registerRequestMetrics registers the request metrics, the latency histogram is also exposed as a native
+// histogram when enabled.
+func registerRequestMetrics(nativeHistograms bool) {
 	httpRequestsInFlight = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "grafana",
@@ -33,13 +38,17 @@ func init() {
 		},
 	)
 
+	histogramOpts := prometheus.HistogramOpts{
+		Namespace: "grafana",
+		Name:      "http_request_duration_seconds",
+		Help:      "Histogram of latencies for HTTP requests.",
+		Buckets:   defBuckets,
+	}
+	if nativeHistograms {
+		histogramOpts = metricutil.WithNativeHistogram(histogramOpts)
+	}
 	httpRequestDurationHistogram = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Namespace: "grafana",
-			Name:      "http_request_duration_seconds",
-			Help:      "Histogram of latencies for HTTP requests.",
-			Buckets:   defBuckets,
-		},
+		histogramOpts,
 		[]string{"handler", "status_code", "method"},
 	)
 
@@ -47,8 +56,11 @@ func init() {
 }
 
 // RequestMetrics is a middleware handler that instruments the request.
-func RequestMetrics(features featuremgmt.FeatureToggles) web.Middleware {
+func RequestMetrics(features featuremgmt.FeatureToggles, cfg *setting.Cfg) web.Middleware {
 	log := log.New("middleware.request-metrics")
+	registerRequestMetricsOnce.Do(func() {
+		registerRequestMetrics(cfg.MetricsNativeHistograms)
+	})
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -81,16 +93,7 @@ func RequestMetrics(features featuremgmt.FeatureToggles) web.Middleware {
 			// since they dont make much sense. We should remove them later.
 			histogram := httpRequestDurationHistogram.
 				WithLabelValues(handler, code, r.Method)
-			if traceID := tracing.TraceIDFromContext(r.Context(), true); traceID != "" {
-				// Need to type-convert the Observer to an
-				// ExemplarObserver. This will always work for a
-				// HistogramVec.
-				histogram.(prometheus.ExemplarObserver).ObserveWithExemplar(
-					time.Since(now).Seconds(), prometheus.Labels{"traceID": traceID},
-				)
-				return
-			}
-			histogram.Observe(time.Since(now).Seconds())
+			metricutil.ObserveWithExemplar(r.Context(), histogram, time.Since(now).Seconds())
 
 			switch {
 			case strings.HasPrefix(r.RequestURI, "/api/datasources/proxy"):
@@ -82,7 +82,7 @@ func RequestTracing(tracer tracing.Tracer) web.Middleware {
 			rw := web.Rw(w, req)
 
 			wireContext := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
-			ctx, span := tracer.Start(wireContext, fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Path), trace.WithLinks(trace.LinkFromContext(wireContext)))
+			ctx, span := tracer.Start(wireContext, fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Path), trace.WithLinks(trace.LinkFromContext(wireContext)), tracing.WithComponent(requestComponent(req)))
 
 			req = req.WithContext(ctx)
 			next.ServeHTTP(w, req)
@@ -106,3 +106,12 @@ func RequestTracing(tracer tracing.Tracer) web.Middleware {
 		})
 	}
 }
+
+// requestComponent is the tracing component of a request, the first segment of the path of the API requests.
+func requestComponent(req *http.Request) string {
+	if strings.HasPrefix(req.URL.Path, "/api/") {
+		component, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/api/"), "/")
+		return component
+	}
+	return "http"
+}
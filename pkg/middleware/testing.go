@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login/loginservice"
+	"github.com/grafana/grafana/pkg/services/oauthserver/oauthservertest"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
@@ -48,6 +49,7 @@ type scenarioContext struct {
 	apiKeyService        *apikeytest.Service
 	userService          *usertest.FakeUserService
 	oauthTokenService    *authtest.FakeOAuthTokenService
+	oauthServer          *oauthservertest.FakeService
 	orgService           *orgtest.FakeOrgService
 
 	req *http.Request
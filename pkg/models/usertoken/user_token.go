@@ -33,5 +33,7 @@ type UserToken struct {
 	CreatedAt     int64
 	UpdatedAt     int64
 	RevokedAt     int64
+	// AuthModule is the auth module the user signed in with
+	AuthModule    string
 	UnhashedToken string
 }
@@ -14,6 +14,10 @@ const (
 
 	// App Plugins actions
 	ActionAppAccess = "plugins.app:access"
+
+	// Plugin secrets actions
+	ActionSecretsRead  = "plugins.secrets:read"
+	ActionSecretsWrite = "plugins.secrets:write"
 )
 
 var (
@@ -67,9 +71,35 @@ func DeclareRBACRoles(service ac.Service, cfg *setting.Cfg) error {
 		Grants: []string{ac.RoleGrafanaAdmin},
 	}
 
+	// Not granted by default, meant to be assigned to the service accounts of the plugins reading their secrets
+	PluginsSecretsReader := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        ac.FixedRolePrefix + "plugins.secrets:reader",
+			DisplayName: "Plugin Secrets Reader",
+			Description: "Read the decrypted secrets of plugins",
+			Group:       "Plugins",
+			Permissions: []ac.Permission{
+				{Action: ActionSecretsRead, Scope: ScopeProvider.GetResourceAllScope()},
+			},
+		},
+		Grants: []string{},
+	}
+	PluginsSecretsWriter := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        ac.FixedRolePrefix + "plugins.secrets:writer",
+			DisplayName: "Plugin Secrets Writer",
+			Description: "Create, update and delete the secrets of plugins",
+			Group:       "Plugins",
+			Permissions: []ac.Permission{
+				{Action: ActionSecretsWrite, Scope: ScopeProvider.GetResourceAllScope()},
+			},
+		},
+		Grants: []string{string(org.RoleAdmin)},
+	}
+
 	if !cfg.PluginAdminEnabled || cfg.PluginAdminExternalManageEnabled {
 		PluginsMaintainer.Grants = []string{}
 	}
 
-	return service.DeclareFixedRoles(AppPluginsReader, PluginsWriter, PluginsMaintainer)
+	return service.DeclareFixedRoles(AppPluginsReader, PluginsWriter, PluginsMaintainer, PluginsSecretsReader, PluginsSecretsWriter)
 }
@@ -0,0 +1,30 @@
+package backendplugin
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// ChunkedDataWriter receives the frames of a query data response as a backend plugin streams them.
+type ChunkedDataWriter interface {
+	// WriteFrame writes a frame of the response of the query with the given refID.
+	WriteFrame(refID string, frame *data.Frame) error
+	// WriteError writes the error of the query with the given refID.
+	WriteError(refID string, err error) error
+}
+
+type chunkedDataWriterKey struct{}
+
+// WithChunkedDataWriter returns a context asking the plugins that support it to stream the response of the
+// query data requests made with it to w. The frames written to w are then left out of the returned response.
+// Passing the writer through the context keeps the plugin client middlewares applied to streamed queries.
+func WithChunkedDataWriter(ctx context.Context, w ChunkedDataWriter) context.Context {
+	return context.WithValue(ctx, chunkedDataWriterKey{}, w)
+}
+
+// ChunkedDataWriterFromContext returns the writer set by WithChunkedDataWriter, if any.
+func ChunkedDataWriterFromContext(ctx context.Context) (ChunkedDataWriter, bool) {
+	w, ok := ctx.Value(chunkedDataWriterKey{}).(ChunkedDataWriter)
+	return w, ok
+}
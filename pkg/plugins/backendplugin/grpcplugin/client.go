@@ -78,6 +78,7 @@ func getV2PluginSet() goplugin.PluginSet {
 		"stream":         &grpcplugin.StreamGRPCPlugin{},
 		"renderer":       &pluginextensionv2.RendererGRPCPlugin{},
 		"secretsmanager": &secretsmanagerplugin.SecretsManagerGRPCPlugin{},
+		"datastream":     &pluginextensionv2.DataStreamGRPCPlugin{},
 	}
 }
 
@@ -26,6 +26,7 @@ type ClientV2 struct {
 	grpcplugin.StreamClient
 	pluginextensionv2.RendererPlugin
 	secretsmanagerplugin.SecretsManagerPlugin
+	pluginextensionv2.DataStreamClient
 }
 
 func newClientV2(descriptor PluginDescriptor, logger log.Logger, rpcClient plugin.ClientProtocol) (pluginClient, error) {
@@ -59,6 +60,11 @@ func newClientV2(descriptor PluginDescriptor, logger log.Logger, rpcClient plugi
 		return nil, err
 	}
 
+	rawDataStream, err := rpcClient.Dispense("datastream")
+	if err != nil {
+		return nil, err
+	}
+
 	c := ClientV2{}
 	if rawDiagnostics != nil {
 		if diagnosticsClient, ok := rawDiagnostics.(grpcplugin.DiagnosticsClient); ok {
@@ -96,6 +102,12 @@ func newClientV2(descriptor PluginDescriptor, logger log.Logger, rpcClient plugi
 		}
 	}
 
+	if rawDataStream != nil {
+		if dataStreamClient, ok := rawDataStream.(pluginextensionv2.DataStreamClient); ok {
+			c.DataStreamClient = dataStreamClient
+		}
+	}
+
 	if descriptor.startRendererFn != nil {
 		if err := descriptor.startRendererFn(descriptor.pluginID, c.RendererPlugin, logger); err != nil {
 			return nil, err
@@ -155,6 +167,19 @@ func (c *ClientV2) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	}
 
 	protoReq := backend.ToProto().QueryDataRequest(req)
+
+	if w, ok := backendplugin.ChunkedDataWriterFromContext(ctx); ok && c.DataStreamClient != nil {
+		err := c.queryChunkedData(ctx, protoReq, w)
+		if err == nil {
+			// the frames were all written to w
+			return backend.NewQueryDataResponse(), nil
+		}
+		// plugins not implementing the DataStream service answer with a single response
+		if status.Code(err) != codes.Unimplemented {
+			return nil, fmt.Errorf("%v: %w", "Failed to query data", err)
+		}
+	}
+
 	protoResp, err := c.DataClient.QueryData(ctx, protoReq)
 
 	if err != nil {
@@ -168,6 +193,41 @@ func (c *ClientV2) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return backend.FromProto().QueryDataResponse(protoResp)
 }
 
+// queryChunkedData streams the response of the query data request to w as the plugin sends it.
+func (c *ClientV2) queryChunkedData(ctx context.Context, protoReq *pluginv2.QueryDataRequest, w backendplugin.ChunkedDataWriter) error {
+	protoStream, err := c.DataStreamClient.QueryChunkedData(ctx, protoReq)
+	if err != nil {
+		return err
+	}
+
+	for {
+		protoResp, err := protoStream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		resp, err := backend.FromProto().QueryDataResponse(protoResp)
+		if err != nil {
+			return err
+		}
+		for refID, res := range resp.Responses {
+			if res.Error != nil {
+				if err := w.WriteError(refID, res.Error); err != nil {
+					return err
+				}
+			}
+			for _, frame := range res.Frames {
+				if err := w.WriteFrame(refID, frame); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
 func (c *ClientV2) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
 	if c.ResourceClient == nil {
 		return backendplugin.ErrMethodNotImplemented
@@ -0,0 +1,125 @@
+package grpcplugin
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/genproto/pluginv2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin/pluginextensionv2"
+)
+
+func TestClientV2_QueryData(t *testing.T) {
+	t.Run("Frames streamed by the plugin are written to the chunked data writer", func(t *testing.T) {
+		dataClient := &fakeDataClient{}
+		c := &ClientV2{
+			DataClient: dataClient,
+			DataStreamClient: pluginextensionv2.NewDataStreamClient(newDataStreamConn(t, &fakeDataStreamServer{
+				chunks: []*backend.QueryDataResponse{
+					{Responses: backend.Responses{"A": {Frames: data.Frames{data.NewFrame("first")}}}},
+					{Responses: backend.Responses{"A": {Frames: data.Frames{data.NewFrame("second")}}}},
+				},
+			})),
+		}
+
+		w := &fakeChunkedDataWriter{}
+		resp, err := c.QueryData(backendplugin.WithChunkedDataWriter(context.Background(), w), &backend.QueryDataRequest{})
+		require.NoError(t, err)
+		require.Empty(t, resp.Responses)
+		require.Equal(t, []string{"first", "second"}, w.frames["A"])
+		require.False(t, dataClient.called)
+	})
+
+	t.Run("Plugins without the DataStream service fall back to a single response", func(t *testing.T) {
+		dataClient := &fakeDataClient{
+			resp: &backend.QueryDataResponse{Responses: backend.Responses{"A": {Frames: data.Frames{data.NewFrame("all")}}}},
+		}
+		c := &ClientV2{
+			DataClient:       dataClient,
+			DataStreamClient: pluginextensionv2.NewDataStreamClient(newDataStreamConn(t, nil)),
+		}
+
+		w := &fakeChunkedDataWriter{}
+		resp, err := c.QueryData(backendplugin.WithChunkedDataWriter(context.Background(), w), &backend.QueryDataRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		require.Empty(t, w.frames)
+		require.True(t, dataClient.called)
+	})
+}
+
+// newDataStreamConn returns a connection to an in-memory gRPC server serving srv, if not nil.
+func newDataStreamConn(t *testing.T, srv pluginextensionv2.DataStreamServer) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	if srv != nil {
+		pluginextensionv2.RegisterDataStreamServer(s, srv)
+	}
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+type fakeDataStreamServer struct {
+	chunks []*backend.QueryDataResponse
+}
+
+func (s *fakeDataStreamServer) QueryChunkedData(_ *pluginv2.QueryDataRequest, stream pluginextensionv2.DataStream_QueryChunkedDataServer) error {
+	for _, chunk := range s.chunks {
+		protoResp, err := backend.ToProto().QueryDataResponse(chunk)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(protoResp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type fakeDataClient struct {
+	resp   *backend.QueryDataResponse
+	called bool
+}
+
+func (c *fakeDataClient) QueryData(_ context.Context, _ *pluginv2.QueryDataRequest, _ ...grpc.CallOption) (*pluginv2.QueryDataResponse, error) {
+	c.called = true
+	return backend.ToProto().QueryDataResponse(c.resp)
+}
+
+type fakeChunkedDataWriter struct {
+	frames map[string][]string
+}
+
+func (w *fakeChunkedDataWriter) WriteFrame(refID string, frame *data.Frame) error {
+	if w.frames == nil {
+		w.frames = make(map[string][]string)
+	}
+	w.frames[refID] = append(w.frames[refID], frame.Name)
+	return nil
+}
+
+func (w *fakeChunkedDataWriter) WriteError(_ string, err error) error {
+	return err
+}
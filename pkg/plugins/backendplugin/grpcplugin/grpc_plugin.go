@@ -108,6 +108,19 @@ func (p *grpcPlugin) Exited() bool {
 	return true
 }
 
+func (p *grpcPlugin) Pid() (int, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.client == nil || p.client.Exited() {
+		return 0, false
+	}
+	reattach := p.client.ReattachConfig()
+	if reattach == nil {
+		return 0, false
+	}
+	return reattach.Pid, true
+}
+
 func (p *grpcPlugin) Decommission() error {
 	p.mutex.RLock()
 	defer p.mutex.RUnlock()
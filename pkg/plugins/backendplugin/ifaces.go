@@ -26,6 +26,12 @@ type Plugin interface {
 	backend.StreamHandler
 }
 
+// ProcessPlugin is implemented by backend plugins running in their own process.
+type ProcessPlugin interface {
+	// Pid returns the ID of the running plugin process.
+	Pid() (int, bool)
+}
+
 type Target string
 
 const (
@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics/metricutil"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	plog "github.com/grafana/grafana/pkg/plugins/log"
@@ -28,6 +29,13 @@ var (
 		Help:      "Plugin request duration",
 		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 25, 50, 100},
 	}, []string{"plugin_id", "endpoint", "target"})
+
+	pluginQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "grafana",
+		Name:      "plugin_query_duration_seconds",
+		Help:      "Duration of the data queries of the plugins",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"plugin_id", "status"})
 )
 
 var logger = plog.New("plugin.instrumentation")
@@ -46,7 +54,7 @@ func instrumentPluginRequest(ctx context.Context, cfg Cfg, pluginCtx *backend.Pl
 	}
 
 	elapsed := time.Since(start)
-	pluginRequestDuration.WithLabelValues(pluginCtx.PluginID, endpoint, string(cfg.Target)).Observe(float64(elapsed / time.Millisecond))
+	metricutil.ObserveWithExemplar(ctx, pluginRequestDuration.WithLabelValues(pluginCtx.PluginID, endpoint, string(cfg.Target)), float64(elapsed/time.Millisecond))
 	pluginRequestCounter.WithLabelValues(pluginCtx.PluginID, endpoint, status, string(cfg.Target)).Inc()
 
 	if cfg.LogDatasourceRequests {
@@ -101,5 +109,13 @@ func InstrumentCallResourceRequest(ctx context.Context, req *backend.PluginConte
 
 // InstrumentQueryDataRequest instruments success rate and latency of query data requests.
 func InstrumentQueryDataRequest(ctx context.Context, req *backend.PluginContext, cfg Cfg, fn func() error) error {
-	return instrumentPluginRequest(ctx, cfg, req, "queryData", fn)
+	start := time.Now()
+	err := instrumentPluginRequest(ctx, cfg, req, "queryData", fn)
+
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	pluginQueryDuration.WithLabelValues(req.PluginID, status).Observe(time.Since(start).Seconds())
+	return err
 }
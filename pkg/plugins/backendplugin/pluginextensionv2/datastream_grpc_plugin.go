@@ -0,0 +1,119 @@
+package pluginextensionv2
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/genproto/pluginv2"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
+
+// The DataStream service lets backend plugins stream the response of a query data request over the plugin
+// gRPC connection instead of returning it at once. It reuses the messages of the Data service of the plugin
+// SDK: every QueryDataResponse sent on the stream holds a chunk of the frames of one or more queries.
+
+const dataStreamQueryChunkedDataMethod = "/pluginextensionv2.DataStream/QueryChunkedData"
+
+type DataStreamClient interface {
+	QueryChunkedData(ctx context.Context, in *pluginv2.QueryDataRequest, opts ...grpc.CallOption) (DataStream_QueryChunkedDataClient, error)
+}
+
+// nolint:revive,stylecheck
+type DataStream_QueryChunkedDataClient interface {
+	Recv() (*pluginv2.QueryDataResponse, error)
+	grpc.ClientStream
+}
+
+type dataStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataStreamClient(cc grpc.ClientConnInterface) DataStreamClient {
+	return &dataStreamClient{cc}
+}
+
+func (c *dataStreamClient) QueryChunkedData(ctx context.Context, in *pluginv2.QueryDataRequest, opts ...grpc.CallOption) (DataStream_QueryChunkedDataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataStream_ServiceDesc.Streams[0], dataStreamQueryChunkedDataMethod, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataStreamQueryChunkedDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type dataStreamQueryChunkedDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataStreamQueryChunkedDataClient) Recv() (*pluginv2.QueryDataResponse, error) {
+	m := new(pluginv2.QueryDataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DataStreamServer is the server API of the DataStream service, implemented by the plugins streaming their query responses.
+type DataStreamServer interface {
+	QueryChunkedData(*pluginv2.QueryDataRequest, DataStream_QueryChunkedDataServer) error
+}
+
+// nolint:revive,stylecheck
+type DataStream_QueryChunkedDataServer interface {
+	Send(*pluginv2.QueryDataResponse) error
+	grpc.ServerStream
+}
+
+func RegisterDataStreamServer(s grpc.ServiceRegistrar, srv DataStreamServer) {
+	s.RegisterService(&DataStream_ServiceDesc, srv)
+}
+
+func dataStreamQueryChunkedDataHandler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(pluginv2.QueryDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataStreamServer).QueryChunkedData(m, &dataStreamQueryChunkedDataServer{stream})
+}
+
+type dataStreamQueryChunkedDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataStreamQueryChunkedDataServer) Send(m *pluginv2.QueryDataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// nolint:revive,stylecheck
+var DataStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pluginextensionv2.DataStream",
+	HandlerType: (*DataStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryChunkedData",
+			Handler:       dataStreamQueryChunkedDataHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+type DataStreamGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+}
+
+func (p *DataStreamGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	return nil
+}
+
+func (p *DataStreamGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return NewDataStreamClient(c), nil
+}
+
+var _ plugin.GRPCPlugin = &DataStreamGRPCPlugin{}
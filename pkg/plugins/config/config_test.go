@@ -36,3 +36,31 @@ func TestPluginSettings(t *testing.T) {
 	require.Equal(t, ps["secret-plugin"]["secret_key"], "secret")
 	require.Equal(t, ps["secret-plugin"]["normal_key"], "not a secret")
 }
+
+func TestProcessLimits(t *testing.T) {
+	cfg := &Cfg{PluginSettings: setting.PluginSettings{
+		"limited-datasource": {
+			"max_memory_mb":   "256",
+			"max_cpu_percent": "50",
+			"restart_policy":  "never",
+			"max_restarts":    "3",
+		},
+		"invalid-datasource": {
+			"max_memory_mb":  "lots",
+			"restart_policy": "sometimes",
+			"max_restarts":   "-1",
+		},
+	}}
+
+	require.Equal(t, ProcessLimits{
+		MaxMemoryBytes: 256 * 1024 * 1024,
+		MaxCPUPercent:  50,
+		RestartPolicy:  RestartPolicyNever,
+		MaxRestarts:    3,
+	}, cfg.ProcessLimits("limited-datasource"))
+	require.True(t, cfg.ProcessLimits("limited-datasource").HasResourceLimits())
+
+	require.Equal(t, ProcessLimits{RestartPolicy: RestartPolicyAlways}, cfg.ProcessLimits("invalid-datasource"))
+	require.Equal(t, ProcessLimits{RestartPolicy: RestartPolicyAlways}, cfg.ProcessLimits("other-datasource"))
+	require.False(t, cfg.ProcessLimits("other-datasource").HasResourceLimits())
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/plugins/log"
+)
+
+type RestartPolicy string
+
+const (
+	// RestartPolicyAlways restarts the plugin process whenever it exits
+	RestartPolicyAlways RestartPolicy = "always"
+	// RestartPolicyNever leaves the plugin process stopped once it exits
+	RestartPolicyNever RestartPolicy = "never"
+)
+
+// ProcessLimits are the resource limits and restart policy of a backend plugin process,
+// configured in the [plugin.<plugin id>] section.
+type ProcessLimits struct {
+	// MaxMemoryBytes is the memory limit of the process, 0 means unlimited
+	MaxMemoryBytes uint64 `json:"maxMemoryBytes"`
+	// MaxCPUPercent is the CPU limit of the process in percent of one core, 0 means unlimited
+	MaxCPUPercent int           `json:"maxCpuPercent"`
+	RestartPolicy RestartPolicy `json:"restartPolicy"`
+	// MaxRestarts is the number of times the process is restarted, 0 means unlimited
+	MaxRestarts int `json:"maxRestarts"`
+}
+
+// HasResourceLimits returns true if either a memory or a CPU limit is configured
+func (l ProcessLimits) HasResourceLimits() bool {
+	return l.MaxMemoryBytes > 0 || l.MaxCPUPercent > 0
+}
+
+// ProcessLimitsKeys are the keys of the plugin settings that configure the process limits.
+// They are used by Grafana and not passed down to the plugin.
+var ProcessLimitsKeys = map[string]bool{
+	"max_memory_mb":   true,
+	"max_cpu_percent": true,
+	"restart_policy":  true,
+	"max_restarts":    true,
+}
+
+// ProcessLimits returns the process limits of the plugin. Invalid values are ignored.
+func (c *Cfg) ProcessLimits(pluginID string) ProcessLimits {
+	limits := ProcessLimits{RestartPolicy: RestartPolicyAlways}
+	settings := c.PluginSettings[pluginID]
+	logger := log.New("plugin.cfg")
+
+	if v, ok := settings["max_memory_mb"]; ok {
+		if mb, err := strconv.ParseUint(v, 10, 64); err == nil {
+			limits.MaxMemoryBytes = mb * 1024 * 1024
+		} else {
+			logger.Warn("Invalid plugin memory limit", "pluginID", pluginID, "value", v)
+		}
+	}
+	if v, ok := settings["max_cpu_percent"]; ok {
+		if percent, err := strconv.Atoi(v); err == nil && percent >= 0 {
+			limits.MaxCPUPercent = percent
+		} else {
+			logger.Warn("Invalid plugin CPU limit", "pluginID", pluginID, "value", v)
+		}
+	}
+	if v, ok := settings["restart_policy"]; ok {
+		switch policy := RestartPolicy(v); policy {
+		case RestartPolicyAlways, RestartPolicyNever:
+			limits.RestartPolicy = policy
+		default:
+			logger.Warn("Invalid plugin restart policy", "pluginID", pluginID, "value", v)
+		}
+	}
+	if v, ok := settings["max_restarts"]; ok {
+		if restarts, err := strconv.Atoi(v); err == nil && restarts >= 0 {
+			limits.MaxRestarts = restarts
+		} else {
+			logger.Warn("Invalid plugin max restarts", "pluginID", pluginID, "value", v)
+		}
+	}
+
+	return limits
+}
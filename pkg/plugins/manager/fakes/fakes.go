@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/plugins/log"
+	"github.com/grafana/grafana/pkg/plugins/manager/process"
 	"github.com/grafana/grafana/pkg/plugins/repo"
 	"github.com/grafana/grafana/pkg/plugins/storage"
 )
@@ -269,10 +270,11 @@ func (s *FakePluginStorage) Remove(ctx context.Context, pluginID string) error {
 }
 
 type FakeProcessManager struct {
-	StartFunc func(_ context.Context, pluginID string) error
-	StopFunc  func(_ context.Context, pluginID string) error
-	Started   map[string]int
-	Stopped   map[string]int
+	StartFunc  func(_ context.Context, pluginID string) error
+	StopFunc   func(_ context.Context, pluginID string) error
+	StatusFunc func(_ context.Context, pluginID string) (process.ProcessStatus, bool)
+	Started    map[string]int
+	Stopped    map[string]int
 }
 
 func NewFakeProcessManager() *FakeProcessManager {
@@ -298,6 +300,13 @@ func (m *FakeProcessManager) Stop(ctx context.Context, pluginID string) error {
 	return nil
 }
 
+func (m *FakeProcessManager) Status(ctx context.Context, pluginID string) (process.ProcessStatus, bool) {
+	if m.StatusFunc != nil {
+		return m.StatusFunc(ctx, pluginID)
+	}
+	return process.ProcessStatus{}, false
+}
+
 type FakeBackendProcessProvider struct {
 	Requested map[string]int
 	Invoked   map[string]int
@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/config"
@@ -118,13 +119,25 @@ func (m *PluginInstaller) Add(ctx context.Context, pluginID, version string, opt
 		pathsToScan = append(pathsToScan, depArchive.Path)
 	}
 
-	_, err = m.pluginLoader.Load(ctx, plugins.External, pathsToScan)
+	loaded, err := m.pluginLoader.Load(ctx, plugins.External, pathsToScan)
 	if err != nil {
 		m.log.Error("Could not load plugins", "paths", pathsToScan, "err", err)
 		return err
 	}
 
-	return nil
+	for _, p := range loaded {
+		if p.ID == pluginID {
+			return nil
+		}
+	}
+
+	// The loader skips plugins failing the signature verification, remove the files so that
+	// the plugin isn't loaded on the next restart either.
+	m.log.Error("Installed plugin could not be loaded", "pluginID", pluginID, "path", extractedArchive.Path)
+	if err = os.RemoveAll(extractedArchive.Path); err != nil {
+		m.log.Error("Could not remove plugin files", "pluginID", pluginID, "err", err)
+	}
+	return plugins.ErrInstallPluginNotLoaded
 }
 
 func (m *PluginInstaller) Remove(ctx context.Context, pluginID string) error {
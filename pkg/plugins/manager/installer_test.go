@@ -4,6 +4,8 @@ import (
 	"archive/zip"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -189,6 +191,35 @@ func TestPluginManager_Add_Remove(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("Removes the files of a plugin the loader rejected", func(t *testing.T) {
+		pluginPath := filepath.Join(t.TempDir(), testPluginID)
+		require.NoError(t, os.MkdirAll(pluginPath, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(pluginPath, "plugin.json"), []byte(`{}`), 0600))
+
+		loader := &fakes.FakeLoader{
+			LoadFunc: func(_ context.Context, _ plugins.Class, paths []string) ([]*plugins.Plugin, error) {
+				// e.g. the plugin has an invalid signature
+				return []*plugins.Plugin{}, nil
+			},
+		}
+		pluginRepo := &fakes.FakePluginRepo{
+			GetPluginArchiveFunc: func(_ context.Context, id, version string, _ repo.CompatOpts) (*repo.PluginArchive, error) {
+				return &repo.PluginArchive{File: &zip.ReadCloser{}}, nil
+			},
+		}
+		fs := &fakes.FakePluginStorage{
+			AddFunc: func(_ context.Context, id string, z *zip.ReadCloser) (*storage.ExtractedPluginArchive, error) {
+				return &storage.ExtractedPluginArchive{Path: pluginPath}, nil
+			},
+			Store: map[string]struct{}{},
+		}
+
+		inst := New(fakes.NewFakePluginRegistry(), loader, pluginRepo, fs)
+		err := inst.Add(context.Background(), testPluginID, "1.0.0", plugins.CompatOpts{})
+		require.ErrorIs(t, err, plugins.ErrInstallPluginNotLoaded)
+		require.NoDirExists(t, pluginPath)
+	})
 }
 
 func createPlugin(t *testing.T, pluginID string, class plugins.Class, managed, backend bool, cbs ...func(*plugins.Plugin)) *plugins.Plugin {
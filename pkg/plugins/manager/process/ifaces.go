@@ -7,4 +7,6 @@ type Service interface {
 	Start(ctx context.Context, pluginID string) error
 	// Stop terminates a backend plugin process.
 	Stop(ctx context.Context, pluginID string) error
+	// Status returns the status of a backend plugin process.
+	Status(ctx context.Context, pluginID string) (ProcessStatus, bool)
 }
@@ -0,0 +1,98 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+const (
+	cgroupRoot = "/sys/fs/cgroup"
+	// cpuPeriod is the cgroup CPU accounting period in microseconds
+	cpuPeriod = 100000
+)
+
+// applyResourceLimits moves the plugin process into a cgroup v2 dedicated to the plugin
+// and sets its memory and CPU limits.
+func applyResourceLimits(pluginID string, pid int, limits config.ProcessLimits) error {
+	dir, err := pluginCgroupDir(pluginID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if limits.MaxMemoryBytes > 0 {
+		if err := writeCgroupFile(dir, "memory.max", strconv.FormatUint(limits.MaxMemoryBytes, 10)); err != nil {
+			return err
+		}
+	}
+	if limits.MaxCPUPercent > 0 {
+		quota := limits.MaxCPUPercent * cpuPeriod / 100
+		if err := writeCgroupFile(dir, "cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriod)); err != nil {
+			return err
+		}
+	}
+	return writeCgroupFile(dir, "cgroup.procs", strconv.Itoa(pid))
+}
+
+// releaseResourceLimits removes the cgroup of the plugin once its process has stopped
+func releaseResourceLimits(pluginID string) {
+	if dir, err := pluginCgroupDir(pluginID); err == nil {
+		_ = os.Remove(dir)
+	}
+}
+
+// pluginCgroupDir returns the cgroup of the plugin, which is created next to the cgroup of Grafana.
+// Processes can't be added to a child cgroup of Grafana as cgroup v2 only allows processes in leaf cgroups.
+func pluginCgroupDir(pluginID string) (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", errResourceLimitsUnsupported
+	}
+
+	b, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	// with cgroup v2 the process belongs to a single cgroup listed as "0::<path>"
+	for _, line := range strings.Split(string(b), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			parent := filepath.Dir(strings.TrimPrefix(line, "0::"))
+			return filepath.Join(cgroupRoot, parent, "grafana-plugin-"+filepath.Base(pluginID)), nil
+		}
+	}
+	return "", errResourceLimitsUnsupported
+}
+
+func writeCgroupFile(dir, name, value string) error {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from the cgroup of the process
+	return os.WriteFile(filepath.Join(dir, name), []byte(value), 0600)
+}
+
+// residentMemory returns the resident set size of the process in bytes
+func residentMemory(pid int) (uint64, error) {
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is built from a process ID
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected content of the statm file of process %d", pid)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
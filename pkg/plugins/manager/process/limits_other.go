@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import (
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+func applyResourceLimits(_ string, _ int, _ config.ProcessLimits) error {
+	return errResourceLimitsUnsupported
+}
+
+func releaseResourceLimits(_ string) {}
+
+func residentMemory(_ int) (uint64, error) {
+	return 0, errResourceLimitsUnsupported
+}
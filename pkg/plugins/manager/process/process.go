@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/log"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
 )
@@ -15,19 +16,34 @@ import (
 var _ Service = (*Manager)(nil)
 
 type Manager struct {
+	cfg            *config.Cfg
 	pluginRegistry registry.Service
 
+	// processCtx bounds the supervision of the plugin processes. It is not tied to the context of the caller
+	// of Start, so that processes started at runtime (e.g. when installing a plugin) keep being restarted
+	// after the request that started them has completed, until the manager is shut down.
+	processCtx    context.Context
+	cancelProcess context.CancelFunc
+
 	mu  sync.Mutex
 	log log.Logger
+
+	statusMu sync.RWMutex
+	statuses map[string]*ProcessStatus
 }
 
-func ProvideService(pluginRegistry registry.Service) *Manager {
-	return NewManager(pluginRegistry)
+func ProvideService(cfg *config.Cfg, pluginRegistry registry.Service) *Manager {
+	return NewManager(cfg, pluginRegistry)
 }
 
-func NewManager(pluginRegistry registry.Service) *Manager {
+func NewManager(cfg *config.Cfg, pluginRegistry registry.Service) *Manager {
+	processCtx, cancelProcess := context.WithCancel(context.Background())
 	return &Manager{
+		cfg:            cfg,
 		pluginRegistry: pluginRegistry,
+		statuses:       make(map[string]*ProcessStatus),
+		processCtx:     processCtx,
+		cancelProcess:  cancelProcess,
 		log:            log.New("plugin.process.manager"),
 	}
 }
@@ -51,7 +67,7 @@ func (m *Manager) Start(ctx context.Context, pluginID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if err := startPluginAndRestartKilledProcesses(ctx, p); err != nil {
+	if err := m.startPluginAndRestartKilledProcesses(ctx, p); err != nil {
 		return err
 	}
 
@@ -75,12 +91,16 @@ func (m *Manager) Stop(ctx context.Context, pluginID string) error {
 	if err := p.Stop(ctx); err != nil {
 		return err
 	}
+	m.processStopped(p.ID)
 
 	return nil
 }
 
 // shutdown stops all backend plugin processes
 func (m *Manager) shutdown(ctx context.Context) {
+	// stop restarting the processes that are about to be stopped
+	m.cancelProcess()
+
 	var wg sync.WaitGroup
 	for _, p := range m.pluginRegistry.Plugins(ctx) {
 		wg.Add(1)
@@ -90,13 +110,14 @@ func (m *Manager) shutdown(ctx context.Context) {
 			if err := p.Stop(ctx); err != nil {
 				p.Logger().Error("Failed to stop plugin", "error", err)
 			}
+			m.processStopped(p.PluginID())
 			p.Logger().Debug("Plugin stopped")
 		}(p, ctx)
 	}
 	wg.Wait()
 }
 
-func startPluginAndRestartKilledProcesses(ctx context.Context, p *plugins.Plugin) error {
+func (m *Manager) startPluginAndRestartKilledProcesses(ctx context.Context, p *plugins.Plugin) error {
 	if err := p.Start(ctx); err != nil {
 		return err
 	}
@@ -105,16 +126,19 @@ func startPluginAndRestartKilledProcesses(ctx context.Context, p *plugins.Plugin
 		return nil
 	}
 
+	limits := m.cfg.ProcessLimits(p.ID)
+	m.processStarted(p, limits, false)
+
 	go func(ctx context.Context, p *plugins.Plugin) {
-		if err := restartKilledProcess(ctx, p); err != nil {
+		if err := m.restartKilledProcess(ctx, p, limits); err != nil {
 			p.Logger().Error("Attempt to restart killed plugin process failed", "error", err)
 		}
-	}(ctx, p)
+	}(m.processCtx, p)
 
 	return nil
 }
 
-func restartKilledProcess(ctx context.Context, p *plugins.Plugin) error {
+func (m *Manager) restartKilledProcess(ctx context.Context, p *plugins.Plugin, limits config.ProcessLimits) error {
 	ticker := time.NewTicker(time.Second * 1)
 
 	for {
@@ -131,14 +155,27 @@ func restartKilledProcess(ctx context.Context, p *plugins.Plugin) error {
 			}
 
 			if !p.Exited() {
+				m.collectResourceUsage(p)
 				continue
 			}
+			m.processExited(p.ID)
+
+			if limits.RestartPolicy == config.RestartPolicyNever {
+				p.Logger().Info("Plugin process exited and will not be restarted as its restart policy is never")
+				return nil
+			}
+			if limits.MaxRestarts > 0 && m.restarts(p.ID) >= limits.MaxRestarts {
+				p.Logger().Warn("Plugin process exited and will not be restarted as it reached its maximum number of restarts",
+					"maxRestarts", limits.MaxRestarts)
+				return nil
+			}
 
 			p.Logger().Debug("Restarting plugin")
 			if err := p.Start(ctx); err != nil {
 				p.Logger().Error("Failed to restart plugin", "error", err)
 				continue
 			}
+			m.processStarted(p, limits, true)
 			p.Logger().Debug("Plugin restarted")
 		}
 	}
@@ -4,17 +4,20 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/backendplugin"
+	"github.com/grafana/grafana/pkg/plugins/config"
 	"github.com/grafana/grafana/pkg/plugins/log"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestProcessManager_Start(t *testing.T) {
 	t.Run("Plugin not found in registry", func(t *testing.T) {
-		m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{}))
+		m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{}))
 		err := m.Start(context.Background(), "non-existing-datasource")
 		require.ErrorIs(t, err, backendplugin.ErrPluginNotRegistered)
 	})
@@ -63,7 +66,7 @@ func TestProcessManager_Start(t *testing.T) {
 					plugin.SignatureError = tc.signatureError
 				})
 
-				m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{
+				m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
 					p.ID: p,
 				}))
 
@@ -83,7 +86,7 @@ func TestProcessManager_Start(t *testing.T) {
 
 func TestProcessManager_Stop(t *testing.T) {
 	t.Run("Plugin not found in registry", func(t *testing.T) {
-		m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{}))
+		m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{}))
 		err := m.Stop(context.Background(), "non-existing-datasource")
 		require.ErrorIs(t, err, backendplugin.ErrPluginNotRegistered)
 	})
@@ -97,7 +100,7 @@ func TestProcessManager_Stop(t *testing.T) {
 			plugin.Backend = true
 		})
 
-		m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{
+		m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
 			pluginID: p,
 		}))
 		err := m.Stop(context.Background(), pluginID)
@@ -116,7 +119,7 @@ func TestProcessManager_ManagedBackendPluginLifecycle(t *testing.T) {
 		plugin.Backend = true
 	})
 
-	m := NewManager(newFakePluginRegistry(map[string]*plugins.Plugin{
+	m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
 		p.ID: p,
 	}))
 
@@ -162,6 +165,107 @@ func TestProcessManager_ManagedBackendPluginLifecycle(t *testing.T) {
 	})
 }
 
+func TestProcessManager_RuntimeStartedPluginLifecycle(t *testing.T) {
+	bp := newFakeBackendPlugin(true)
+	p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+		plugin.Backend = true
+	})
+
+	m := NewManager(&config.Cfg{}, newFakePluginRegistry(map[string]*plugins.Plugin{
+		p.ID: p,
+	}))
+
+	// e.g. the context of the request installing the plugin
+	reqCtx, cancel := context.WithCancel(context.Background())
+	err := m.Start(reqCtx, p.ID)
+	require.NoError(t, err)
+	cancel()
+
+	t.Run("When plugin process is killed after the caller context is done, the process is restarted", func(t *testing.T) {
+		bp.kill()
+		require.Eventually(t, func() bool {
+			return !bp.Exited()
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("When the manager is shut down, the process is not restarted anymore", func(t *testing.T) {
+		m.shutdown(context.Background())
+		require.True(t, bp.Exited())
+		time.Sleep(1500 * time.Millisecond)
+		require.True(t, bp.Exited())
+	})
+}
+
+func TestProcessManager_RestartPolicy(t *testing.T) {
+	t.Run("Plugin process is not restarted beyond its maximum number of restarts", func(t *testing.T) {
+		bp := newFakeBackendPlugin(true)
+		p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+			plugin.Backend = true
+		})
+
+		m := NewManager(&config.Cfg{PluginSettings: setting.PluginSettings{
+			p.ID: {"max_restarts": "1"},
+		}}, newFakePluginRegistry(map[string]*plugins.Plugin{
+			p.ID: p,
+		}))
+		t.Cleanup(func() { m.shutdown(context.Background()) })
+
+		err := m.Start(context.Background(), p.ID)
+		require.NoError(t, err)
+
+		status, exists := m.Status(context.Background(), p.ID)
+		require.True(t, exists)
+		require.True(t, status.Running)
+		require.Equal(t, 0, status.Restarts)
+		require.Equal(t, config.RestartPolicyAlways, status.Limits.RestartPolicy)
+
+		bp.kill()
+		require.Eventually(t, func() bool {
+			return !bp.Exited()
+		}, 5*time.Second, 100*time.Millisecond)
+
+		status, _ = m.Status(context.Background(), p.ID)
+		require.Equal(t, 1, status.Restarts)
+		require.NotNil(t, status.LastExitAt)
+
+		bp.kill()
+		time.Sleep(1500 * time.Millisecond)
+		require.True(t, bp.Exited())
+		require.Equal(t, 2, bp.startCount)
+
+		status, _ = m.Status(context.Background(), p.ID)
+		require.False(t, status.Running)
+		require.Equal(t, 1, status.Restarts)
+	})
+
+	t.Run("Plugin process is not restarted with the never restart policy", func(t *testing.T) {
+		bp := newFakeBackendPlugin(true)
+		p := createPlugin(t, bp, func(plugin *plugins.Plugin) {
+			plugin.Backend = true
+		})
+
+		m := NewManager(&config.Cfg{PluginSettings: setting.PluginSettings{
+			p.ID: {"restart_policy": "never"},
+		}}, newFakePluginRegistry(map[string]*plugins.Plugin{
+			p.ID: p,
+		}))
+		t.Cleanup(func() { m.shutdown(context.Background()) })
+
+		err := m.Start(context.Background(), p.ID)
+		require.NoError(t, err)
+
+		bp.kill()
+		time.Sleep(1500 * time.Millisecond)
+		require.True(t, bp.Exited())
+		require.Equal(t, 1, bp.startCount)
+
+		status, exists := m.Status(context.Background(), p.ID)
+		require.True(t, exists)
+		require.False(t, status.Running)
+		require.NotNil(t, status.LastExitAt)
+	})
+}
+
 type fakePluginRegistry struct {
 	store map[string]*plugins.Plugin
 }
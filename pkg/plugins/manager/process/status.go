@@ -0,0 +1,138 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/config"
+)
+
+var errResourceLimitsUnsupported = errors.New("plugin resource limits are not supported on this system")
+
+var (
+	processResidentMemory = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "grafana",
+		Name:      "plugin_process_resident_memory_bytes",
+		Help:      "Resident memory size of the backend plugin processes in bytes",
+	}, []string{"plugin_id"})
+
+	processRestarts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "grafana",
+		Name:      "plugin_process_restarts_total",
+		Help:      "The total amount of backend plugin process restarts",
+	}, []string{"plugin_id"})
+)
+
+// ProcessStatus describes the process of a backend plugin
+type ProcessStatus struct {
+	Running   bool       `json:"running"`
+	Pid       int        `json:"pid,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// Restarts is the number of times the process was restarted after exiting
+	Restarts            int                  `json:"restarts"`
+	LastExitAt          *time.Time           `json:"lastExitAt,omitempty"`
+	ResidentMemoryBytes uint64               `json:"residentMemoryBytes"`
+	Limits              config.ProcessLimits `json:"limits"`
+	// LimitsEnforced is false if resource limits are configured but could not be applied
+	LimitsEnforced bool `json:"limitsEnforced"`
+}
+
+// Status returns the status of the process of a backend plugin started by the manager.
+func (m *Manager) Status(ctx context.Context, pluginID string) (ProcessStatus, bool) {
+	p, exists := m.pluginRegistry.Plugin(ctx, pluginID)
+	if !exists {
+		return ProcessStatus{}, false
+	}
+
+	m.statusMu.RLock()
+	s, exists := m.statuses[pluginID]
+	m.statusMu.RUnlock()
+	if !exists {
+		return ProcessStatus{}, false
+	}
+
+	status := *s
+	status.Running = !p.Exited()
+	status.Pid, _ = p.Pid()
+	if !status.Running {
+		status.Pid = 0
+		status.ResidentMemoryBytes = 0
+	} else if rss, err := residentMemory(status.Pid); err == nil {
+		status.ResidentMemoryBytes = rss
+	}
+	return status, true
+}
+
+// processStarted applies the resource limits to a freshly (re)started plugin process and records it.
+func (m *Manager) processStarted(p *plugins.Plugin, limits config.ProcessLimits, restarted bool) {
+	enforced := false
+	if limits.HasResourceLimits() {
+		if pid, ok := p.Pid(); ok {
+			if err := applyResourceLimits(p.ID, pid, limits); err != nil {
+				p.Logger().Warn("Failed to apply resource limits to plugin process", "error", err)
+			} else {
+				enforced = true
+			}
+		}
+	}
+
+	now := time.Now()
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+
+	s, exists := m.statuses[p.ID]
+	if !exists || !restarted {
+		s = &ProcessStatus{}
+		m.statuses[p.ID] = s
+	}
+	s.StartedAt = &now
+	s.Limits = limits
+	s.LimitsEnforced = enforced
+	if restarted {
+		s.Restarts++
+		processRestarts.WithLabelValues(p.ID).Inc()
+	}
+}
+
+// processExited records that the plugin process has exited
+func (m *Manager) processExited(pluginID string) {
+	processResidentMemory.DeleteLabelValues(pluginID)
+
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	if s, exists := m.statuses[pluginID]; exists && (s.LastExitAt == nil || s.LastExitAt.Before(*s.StartedAt)) {
+		now := time.Now()
+		s.LastExitAt = &now
+	}
+}
+
+// processStopped releases the resources held for a plugin process stopped by the manager
+func (m *Manager) processStopped(pluginID string) {
+	m.processExited(pluginID)
+	releaseResourceLimits(pluginID)
+}
+
+func (m *Manager) restarts(pluginID string) int {
+	m.statusMu.RLock()
+	defer m.statusMu.RUnlock()
+	if s, exists := m.statuses[pluginID]; exists {
+		return s.Restarts
+	}
+	return 0
+}
+
+// collectResourceUsage updates the resource usage metrics of a running plugin process
+func (m *Manager) collectResourceUsage(p *plugins.Plugin) {
+	pid, ok := p.Pid()
+	if !ok {
+		return
+	}
+	if rss, err := residentMemory(pid); err == nil {
+		processResidentMemory.WithLabelValues(p.ID).Set(float64(rss))
+	}
+}
@@ -15,6 +15,9 @@ var (
 	ErrInstallCorePlugin   = errors.New("cannot install a Core plugin")
 	ErrUninstallCorePlugin = errors.New("cannot uninstall a Core plugin")
 	ErrPluginNotInstalled  = errors.New("plugin is not installed")
+	// ErrInstallPluginNotLoaded is returned when an installed plugin was rejected by the loader,
+	// which happens when its signature is missing or invalid
+	ErrInstallPluginNotLoaded = errors.New("installed plugin could not be loaded")
 )
 
 type NotFoundError struct {
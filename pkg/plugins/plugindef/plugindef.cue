@@ -378,6 +378,25 @@ seqs: [
 					// For data source plugins. Token authentication section used with
 					// an JWT OAuth API.
 					jwtTokenAuth?: #JWTTokenAuth
+
+					// Caching and transformation rules applied to the responses of
+					// the route.
+					response?: #RouteResponse
+				}
+
+				// Caching and transformation rules applied to the responses of a
+				// proxy route.
+				#RouteResponse: {
+					// Duration the successful responses of the GET requests are
+					// cached for, for example 30s or 5m.
+					cacheTTL?: string
+
+					// HTTP headers removed from the responses.
+					stripHeaders?: [...string]
+
+					// Dot-separated paths of the fields removed from the JSON
+					// responses. A * matches any field.
+					redactFields?: [...string]
 				}
 
 				// TODO docs
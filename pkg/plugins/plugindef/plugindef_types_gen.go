@@ -436,6 +436,10 @@ type Route struct {
 	ReqRole     *string `json:"reqRole,omitempty"`
 	ReqSignedIn *bool   `json:"reqSignedIn,omitempty"`
 
+	// Caching and transformation rules applied to the responses of a
+	// proxy route.
+	Response *RouteResponse `json:"response,omitempty"`
+
 	// TODO docs
 	TokenAuth *TokenAuth `json:"tokenAuth,omitempty"`
 
@@ -445,6 +449,21 @@ type Route struct {
 	UrlParams []URLParam `json:"urlParams,omitempty"`
 }
 
+// Caching and transformation rules applied to the responses of a
+// proxy route.
+type RouteResponse struct {
+	// Duration the successful responses of the GET requests are
+	// cached for, for example 30s or 5m.
+	CacheTTL *string `json:"cacheTTL,omitempty"`
+
+	// Dot-separated paths of the fields removed from the JSON
+	// responses. A * matches any field.
+	RedactFields []string `json:"redactFields,omitempty"`
+
+	// HTTP headers removed from the responses.
+	StripHeaders []string `json:"stripHeaders,omitempty"`
+}
+
 // TODO docs
 type TokenAuth struct {
 	// Parameters for the token authentication request.
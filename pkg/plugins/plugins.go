@@ -178,6 +178,19 @@ type Route struct {
 	TokenAuth    *JWTTokenAuth   `json:"tokenAuth"`
 	JwtTokenAuth *JWTTokenAuth   `json:"jwtTokenAuth"`
 	Body         json.RawMessage `json:"body"`
+	Response     *RouteResponse  `json:"response"`
+}
+
+// RouteResponse describes how the responses of a plugin route
+// are cached and transformed before being returned
+type RouteResponse struct {
+	// CacheTTL is the duration the successful GET responses are cached for, e.g. "5m"
+	CacheTTL string `json:"cacheTTL"`
+	// StripHeaders are removed from the responses
+	StripHeaders []string `json:"stripHeaders"`
+	// RedactFields are the dot-separated paths of the fields removed from the JSON responses,
+	// "*" matching any field and the arrays being traversed, e.g. "items.*.secret"
+	RedactFields []string `json:"redactFields"`
 }
 
 // Header describes an HTTP header that is forwarded with
@@ -256,6 +269,14 @@ func (p *Plugin) Exited() bool {
 	return false
 }
 
+// Pid returns the ID of the plugin process if the plugin runs in its own process.
+func (p *Plugin) Pid() (int, bool) {
+	if pp, ok := p.client.(backendplugin.ProcessPlugin); ok {
+		return pp.Pid()
+	}
+	return 0, false
+}
+
 func (p *Plugin) Target() backendplugin.Target {
 	if !p.Backend {
 		return backendplugin.TargetNone
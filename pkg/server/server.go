@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"strconv"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -190,13 +191,17 @@ func (s *Server) Run() error {
 	return s.childRoutines.Wait()
 }
 
-// Shutdown initiates Grafana graceful shutdown. This shuts down all
-// running background services. Since Run blocks Shutdown supposed to
-// be run from a separate goroutine.
+// Shutdown initiates Grafana graceful shutdown. The server first reports
+// not ready for the configured drain delay, then shuts down all running
+// background services, letting the requests and alert evaluations in
+// progress complete. Since Run blocks Shutdown supposed to be run from a
+// separate goroutine.
 func (s *Server) Shutdown(ctx context.Context, reason string) error {
 	var err error
 	s.shutdownOnce.Do(func() {
 		s.log.Info("Shutdown started", "reason", reason)
+		s.notifySystemd("STOPPING=1")
+		s.drain(ctx)
 		if err := s.moduleService.Shutdown(ctx); err != nil {
 			s.log.Error("Failed to shutdown modules", "error", err)
 		}
@@ -215,6 +220,25 @@ func (s *Server) Shutdown(ctx context.Context, reason string) error {
 	return err
 }
 
+// drain reports the server as not ready and waits for the drain delay, for the
+// load balancers to stop sending new requests before the server stops.
+func (s *Server) drain(ctx context.Context) {
+	if s.HTTPServer != nil {
+		s.HTTPServer.Drain()
+	}
+	if s.cfg.ShutdownDrainDelay <= 0 {
+		return
+	}
+
+	s.log.Info("Waiting for the load balancers to stop sending requests", "delay", s.cfg.ShutdownDrainDelay)
+	timer := time.NewTimer(s.cfg.ShutdownDrainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
 // writePIDFile retrieves the current process ID and writes it to file.
 func (s *Server) writePIDFile() error {
 	if s.pidFile == "" {
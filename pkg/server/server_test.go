@@ -91,6 +91,23 @@ func TestServer_Shutdown(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestServer_Shutdown_DrainDelay(t *testing.T) {
+	svc := newTestService(nil, false)
+	s := testServer(t, svc)
+	s.cfg.ShutdownDrainDelay = 200 * time.Millisecond
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- s.Run()
+	}()
+	<-svc.started
+
+	start := time.Now()
+	require.NoError(t, s.Shutdown(context.Background(), "test interrupt"))
+	require.GreaterOrEqual(t, time.Since(start), s.cfg.ShutdownDrainDelay)
+	require.NoError(t, <-errCh)
+}
+
 type MockModuleService struct {
 	initFunc     func(context.Context) error
 	runFunc      func(context.Context) error
@@ -14,11 +14,15 @@ import (
 	"github.com/grafana/grafana/pkg/cuectx"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/distlock"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log/audit"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/infra/metrics/orgusage"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -37,6 +41,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/annotations/annotationsimpl"
+	"github.com/grafana/grafana/pkg/services/annotations/federation"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/apikey/apikeyimpl"
 	"github.com/grafana/grafana/pkg/services/auth/jwt"
 	"github.com/grafana/grafana/pkg/services/authn/authnimpl"
@@ -44,8 +50,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/contexthandler"
 	"github.com/grafana/grafana/pkg/services/contexthandler/authproxy"
 	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval/dashboardapprovalimpl"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
 	dashboardimportservice "github.com/grafana/grafana/pkg/services/dashboardimport/service"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboardlint/dashboardlintimpl"
 	dashboardstore "github.com/grafana/grafana/pkg/services/dashboards/database"
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
@@ -57,14 +67,24 @@ import (
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources/service"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/services/eventoutbox"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/featuremgmt/runtimetoggles"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+	"github.com/grafana/grafana/pkg/services/foldersettings/foldersettingsimpl"
 	"github.com/grafana/grafana/pkg/services/grpcserver"
 	grpccontext "github.com/grafana/grafana/pkg/services/grpcserver/context"
 	"github.com/grafana/grafana/pkg/services/grpcserver/interceptors"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/hooks"
+	"github.com/grafana/grafana/pkg/services/idempotency"
+	"github.com/grafana/grafana/pkg/services/inactiveusers"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/inbox/inboximpl"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/services/jobqueue/jobqueueimpl"
 	ldapapi "github.com/grafana/grafana/pkg/services/ldap/api"
 	ldapservice "github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
@@ -77,18 +97,27 @@ import (
 	"github.com/grafana/grafana/pkg/services/login/loginservice"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
 	"github.com/grafana/grafana/pkg/services/loginattempt/loginattemptimpl"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+	"github.com/grafana/grafana/pkg/services/navcustomization/navcustomizationimpl"
 	"github.com/grafana/grafana/pkg/services/navtree/navtreeimpl"
 	"github.com/grafana/grafana/pkg/services/ngalert"
 	ngimage "github.com/grafana/grafana/pkg/services/ngalert/image"
 	ngmetrics "github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngstore "github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+	"github.com/grafana/grafana/pkg/services/oauthserver/oauthserverimpl"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/oauthtoken/oauthtokentest"
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
+	"github.com/grafana/grafana/pkg/services/org/orglifecycle"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingsimpl"
 	"github.com/grafana/grafana/pkg/services/playlist/playlistimpl"
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
 	plugindashboardsservice "github.com/grafana/grafana/pkg/services/plugindashboards/service"
+	"github.com/grafana/grafana/pkg/services/pluginmigration"
+	"github.com/grafana/grafana/pkg/services/pluginmigration/pluginmigrationimpl"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration"
 	"github.com/grafana/grafana/pkg/services/preference/prefimpl"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
@@ -99,9 +128,16 @@ import (
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/querylibrary/querylibraryimpl"
 	"github.com/grafana/grafana/pkg/services/quota/quotaimpl"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/services/ratelimit/ratelimitimpl"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+	"github.com/grafana/grafana/pkg/services/savedsearch/savedsearchimpl"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/searchV2"
+	"github.com/grafana/grafana/pkg/services/seats"
+	"github.com/grafana/grafana/pkg/services/seats/seatsimpl"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	secretsDatabase "github.com/grafana/grafana/pkg/services/secrets/database"
 	secretsStore "github.com/grafana/grafana/pkg/services/secrets/kvstore"
@@ -111,6 +147,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	serviceaccountsmanager "github.com/grafana/grafana/pkg/services/serviceaccounts/manager"
 	serviceaccountsretriever "github.com/grafana/grafana/pkg/services/serviceaccounts/retriever"
+	"github.com/grafana/grafana/pkg/services/settingswatcher"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/shorturls/shorturlimpl"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
@@ -137,7 +174,11 @@ import (
 	"github.com/grafana/grafana/pkg/services/thumbs"
 	"github.com/grafana/grafana/pkg/services/thumbs/dashboardthumbsimpl"
 	"github.com/grafana/grafana/pkg/services/updatechecker"
+	"github.com/grafana/grafana/pkg/services/usageinsights"
+	"github.com/grafana/grafana/pkg/services/usageinsights/usageinsightsimpl"
 	"github.com/grafana/grafana/pkg/services/user/userimpl"
+	"github.com/grafana/grafana/pkg/services/userdata"
+	"github.com/grafana/grafana/pkg/services/userdata/userdataimpl"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/azuremonitor"
 	"github.com/grafana/grafana/pkg/tsdb/cloudmonitoring"
@@ -178,6 +219,7 @@ var wireBasicSet = wire.NewSet(
 	thumbs.ProvideService,
 	rendering.ProvideService,
 	wire.Bind(new(rendering.Service), new(*rendering.RenderingService)),
+	objectstore.ProvideService,
 	routing.ProvideRegister,
 	wire.Bind(new(routing.RouteRegister), new(*routing.RouteRegisterImpl)),
 	hooks.ProvideService,
@@ -203,6 +245,8 @@ var wireBasicSet = wire.NewSet(
 	httpclientprovider.New,
 	wire.Bind(new(httpclient.Provider), new(*sdkhttpclient.Provider)),
 	serverlock.ProvideService,
+	distlock.ProvideService,
+	idempotency.ProvideService,
 	annotationsimpl.ProvideCleanupService,
 	wire.Bind(new(annotations.Cleaner), new(*annotationsimpl.CleanupServiceImpl)),
 	cleanup.ProvideService,
@@ -289,6 +333,7 @@ var wireBasicSet = wire.NewSet(
 	teamguardianManager.ProvideService,
 	featuremgmt.ProvideManagerService,
 	featuremgmt.ProvideToggles,
+	runtimetoggles.ProvideService,
 	dashboardservice.ProvideDashboardServiceImpl,
 	dashboardservice.ProvideDashboardService,
 	dashboardservice.ProvideDashboardProvisioningService,
@@ -307,6 +352,7 @@ var wireBasicSet = wire.NewSet(
 	guardian.ProvideService,
 	sanitizer.ProvideService,
 	secretsStore.ProvideService,
+	secretsStore.ProvideUsageTracker,
 	avatar.ProvideAvatarCacheServer,
 	authproxy.ProvideAuthProxy,
 	statscollector.ProvideService,
@@ -323,6 +369,7 @@ var wireBasicSet = wire.NewSet(
 	starimpl.ProvideService,
 	playlistimpl.ProvideService,
 	apikeyimpl.ProvideService,
+	wire.Bind(new(apikey.Service), new(*apikeyimpl.Service)),
 	dashverimpl.ProvideService,
 	publicdashboardsService.ProvideService,
 	wire.Bind(new(publicdashboards.Service), new(*publicdashboardsService.PublicDashboardServiceImpl)),
@@ -353,12 +400,49 @@ var wireBasicSet = wire.NewSet(
 	wire.Bind(new(secretsMigrations.SecretMigrationProvider), new(*secretsMigrations.SecretMigrationProviderImpl)),
 	acimpl.ProvideAccessControl,
 	navtreeimpl.ProvideService,
+	navcustomizationimpl.ProvideService,
+	wire.Bind(new(navcustomization.Service), new(*navcustomizationimpl.Service)),
+	savedsearchimpl.ProvideService,
+	wire.Bind(new(savedsearch.Service), new(*savedsearchimpl.Service)),
+	oauthserverimpl.ProvideService,
+	wire.Bind(new(oauthserver.Service), new(*oauthserverimpl.Service)),
+	userdataimpl.ProvideService,
+	wire.Bind(new(userdata.Service), new(*userdataimpl.Service)),
+	inboximpl.ProvideService,
+	wire.Bind(new(inbox.Service), new(*inboximpl.Service)),
+	federation.ProvideService,
 	wire.Bind(new(accesscontrol.AccessControl), new(*acimpl.AccessControl)),
 	wire.Bind(new(notifications.TempUserStore), new(tempuser.Service)),
 	tagimpl.ProvideService,
 	wire.Bind(new(tag.Service), new(*tagimpl.Service)),
 	authnimpl.ProvideService,
 	supportbundlesimpl.ProvideService,
+	usageinsightsimpl.ProvideService,
+	wire.Bind(new(usageinsights.Service), new(*usageinsightsimpl.Service)),
+	orgsettingsimpl.ProvideService,
+	wire.Bind(new(orgsettings.Service), new(*orgsettingsimpl.Service)),
+	foldersettingsimpl.ProvideService,
+	wire.Bind(new(foldersettings.Service), new(*foldersettingsimpl.Service)),
+	dashboardlintimpl.ProvideService,
+	wire.Bind(new(dashboardlint.Service), new(*dashboardlintimpl.Service)),
+	dashboardapprovalimpl.ProvideService,
+	wire.Bind(new(dashboardapproval.Service), new(*dashboardapprovalimpl.Service)),
+	pluginmigrationimpl.ProvideService,
+	wire.Bind(new(pluginmigration.Service), new(*pluginmigrationimpl.Service)),
+	seatsimpl.ProvideService,
+	wire.Bind(new(seats.Service), new(*seatsimpl.Service)),
+	ratelimitimpl.ProvideService,
+	wire.Bind(new(ratelimit.Service), new(*ratelimitimpl.Service)),
+	audit.ProvideService,
+	wire.Bind(new(audit.Logger), new(*audit.Service)),
+	orgusage.ProvideService,
+	jobqueueimpl.ProvideService,
+	wire.Bind(new(jobqueue.Service), new(*jobqueueimpl.Service)),
+	inactiveusers.ProvideService,
+	orglifecycle.ProvideService,
+	eventoutbox.ProvideService,
+	settingswatcher.ProvideService,
+	resourcewatch.ProvideService,
 	modules.WireSet,
 )
 
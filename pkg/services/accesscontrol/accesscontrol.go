@@ -192,6 +192,25 @@ func GroupScopesByAction(permissions []Permission) map[string][]string {
 	return m
 }
 
+// RestrictPermissions keeps the permissions allowed by the boundary, the scopes of the boundary grouped by action.
+// A permission is kept when a scope of the boundary includes it, or narrowed down to the scopes of the boundary it
+// includes. An empty scope in the boundary allows all the scopes of the action.
+func RestrictPermissions(permissions []Permission, boundary map[string][]string) []Permission {
+	restricted := make([]Permission, 0, len(permissions))
+	for _, p := range permissions {
+		for _, scope := range boundary[p.Action] {
+			if scope == "" || scope == p.Scope || (p.Scope != "" && match(scope, p.Scope)) {
+				restricted = append(restricted, p)
+				break
+			}
+			if match(p.Scope, scope) {
+				restricted = append(restricted, Permission{Action: p.Action, Scope: scope})
+			}
+		}
+	}
+	return restricted
+}
+
 func Reduce(ps []Permission) map[string][]string {
 	reduced := make(map[string][]string)
 	scopesByAction := make(map[string]map[string]bool)
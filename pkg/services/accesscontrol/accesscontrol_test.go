@@ -125,3 +125,26 @@ func TestReduce(t *testing.T) {
 		})
 	}
 }
+
+func TestRestrictPermissions(t *testing.T) {
+	permissions := []Permission{
+		{Action: "dashboards:read", Scope: "dashboards:*"},
+		{Action: "dashboards:write", Scope: "dashboards:uid:ci"},
+		{Action: "folders:read", Scope: "folders:uid:ops"},
+		{Action: "datasources:create"},
+	}
+
+	restricted := RestrictPermissions(permissions, map[string][]string{
+		"dashboards:read":  {"dashboards:uid:ci", "dashboards:uid:cd"},
+		"dashboards:write": {"dashboards:*"},
+		"folders:read":     {""},
+		"users:read":       {"global.users:*"},
+	})
+
+	require.ElementsMatch(t, []Permission{
+		{Action: "dashboards:read", Scope: "dashboards:uid:ci"},
+		{Action: "dashboards:read", Scope: "dashboards:uid:cd"},
+		{Action: "dashboards:write", Scope: "dashboards:uid:ci"},
+		{Action: "folders:read", Scope: "folders:uid:ops"},
+	}, restricted)
+}
@@ -91,11 +91,21 @@ func (s *Service) GetUserPermissions(ctx context.Context, user *user.SignedInUse
 	timer := prometheus.NewTimer(metrics.MAccessPermissionsSummary)
 	defer timer.ObserveDuration()
 
+	var (
+		permissions []accesscontrol.Permission
+		err         error
+	)
 	if !s.cfg.RBACPermissionCache || !user.HasUniqueId() {
-		return s.getUserPermissions(ctx, user, options)
+		permissions, err = s.getUserPermissions(ctx, user, options)
+	} else {
+		permissions, err = s.getCachedUserPermissions(ctx, user, options)
+	}
+	if err != nil || user.PermissionBoundary == nil {
+		return permissions, err
 	}
 
-	return s.getCachedUserPermissions(ctx, user, options)
+	// the cache is shared by all the tokens of a service account, their boundaries are applied afterwards
+	return accesscontrol.RestrictPermissions(permissions, user.PermissionBoundary), nil
 }
 
 func (s *Service) getUserPermissions(ctx context.Context, user *user.SignedInUser, options accesscontrol.Options) ([]accesscontrol.Permission, error) {
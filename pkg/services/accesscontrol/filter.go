@@ -18,6 +18,7 @@ var sqlIDAcceptList = map[string]struct{}{
 	"\"user\".\"id\"":  {}, // For Postgres
 	"`user`.`id`":      {}, // For MySQL and SQLite
 	"dashboard.uid":    {},
+	"data_source.uid":  {},
 }
 
 var (
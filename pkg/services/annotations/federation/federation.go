@@ -0,0 +1,140 @@
+package federation
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	SourceTypeGrafana = "grafana"
+	SourceTypeHTTP    = "http"
+
+	defaultLimit = 100
+)
+
+// Service merges the annotations of external sources, another Grafana or a generic HTTP API, into the annotations
+// of Grafana, so that the deploy markers of other systems are shown without importing them.
+type Service struct {
+	sources []*source
+	log     log.Logger
+}
+
+func ProvideService(cfg *setting.Cfg, tracer tracing.Tracer) *Service {
+	s := &Service{log: log.New("annotations.federation")}
+	if !cfg.AnnotationFederation.Enabled {
+		return s
+	}
+
+	// the sources share the circuit breaker of their client, the timeouts are per source
+	httpClient := &http.Client{
+		Transport: httpclientprovider.OutboundRoundTripper(cfg, tracer, "annotation-federation", http.DefaultTransport),
+	}
+	for _, sourceCfg := range cfg.AnnotationFederation.Sources {
+		if sourceCfg.URL == "" {
+			s.log.Warn("Skipping annotation source without url", "source", sourceCfg.Name)
+			continue
+		}
+		if sourceCfg.Type != SourceTypeGrafana && sourceCfg.Type != SourceTypeHTTP {
+			s.log.Warn("Skipping annotation source of unknown type", "source", sourceCfg.Name, "type", sourceCfg.Type)
+			continue
+		}
+		s.sources = append(s.sources, &source{cfg: sourceCfg, httpClient: httpClient})
+	}
+	return s
+}
+
+// Merge adds the annotations of the external sources matching the query to the annotations of Grafana, ordered
+// like them by end and start time. The failing sources, or the ones not answering before their timeout, are
+// skipped.
+func (s *Service) Merge(ctx context.Context, query *annotations.ItemQuery, items []*annotations.ItemDTO) []*annotations.ItemDTO {
+	if !federated(query) {
+		return items
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		merged = items
+	)
+	for _, src := range s.sources {
+		if !src.enabledForOrg(query.OrgID) {
+			continue
+		}
+		wg.Add(1)
+		go func(src *source) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, src.cfg.Timeout)
+			defer cancel()
+
+			found, err := src.find(ctx, query)
+			if err != nil {
+				s.log.Warn("Failed to get annotations of source", "source", src.cfg.Name, "error", err)
+				return
+			}
+			found = filterTags(found, query.Tags, query.MatchAny)
+			mu.Lock()
+			merged = append(merged, found...)
+			mu.Unlock()
+		}(src)
+	}
+	wg.Wait()
+
+	if len(merged) == len(items) {
+		return items
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].TimeEnd != merged[j].TimeEnd {
+			return merged[i].TimeEnd > merged[j].TimeEnd
+		}
+		return merged[i].Time > merged[j].Time
+	})
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if int64(len(merged)) > limit {
+		merged = merged[:limit]
+	}
+	return merged
+}
+
+// federated reports whether the annotations of the external sources can match the query, they don't belong to the
+// dashboards, panels, alerts and users of Grafana.
+func federated(query *annotations.ItemQuery) bool {
+	return query.DashboardID == 0 && query.DashboardUID == "" && query.PanelID == 0 && query.AlertID == 0 &&
+		query.UserID == 0 && query.AnnotationID == 0 && query.Type != "alert"
+}
+
+// filterTags checks the tags of the query again, the generic HTTP sources may ignore them.
+func filterTags(items []*annotations.ItemDTO, tags []string, matchAny bool) []*annotations.ItemDTO {
+	if len(tags) == 0 {
+		return items
+	}
+
+	filtered := make([]*annotations.ItemDTO, 0, len(items))
+	for _, item := range items {
+		has := make(map[string]bool, len(item.Tags))
+		for _, tag := range item.Tags {
+			has[tag] = true
+		}
+		matches := 0
+		for _, tag := range tags {
+			if has[tag] {
+				matches++
+			}
+		}
+		if (matchAny && matches > 0) || matches == len(tags) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
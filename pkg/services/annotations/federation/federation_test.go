@@ -0,0 +1,92 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Merge(t *testing.T) {
+	grafana := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/annotations", r.URL.Path)
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		require.Equal(t, "annotation", r.URL.Query().Get("type"))
+		require.Equal(t, []string{"deploy"}, r.URL.Query()["tags"])
+		_ = json.NewEncoder(w).Encode([]*annotations.ItemDTO{
+			{ID: 7, DashboardID: 3, PanelID: 2, Time: 3000, TimeEnd: 3000, Text: "v2", Tags: []string{"deploy"}, AvatarURL: "/avatar/abc"},
+		})
+	}))
+	t.Cleanup(grafana.Close)
+
+	generic := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "1000", r.URL.Query().Get("from"))
+		_, _ = w.Write([]byte(`[{"time": 1500, "text": "v1", "tags": ["deploy"]}, {"time": 4000, "text": "ignored", "tags": ["other"]}]`))
+	}))
+	t.Cleanup(generic.Close)
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		_, _ = w.Write([]byte(`[{"time": 5000, "text": "late", "tags": ["deploy"]}]`))
+	}))
+	t.Cleanup(slow.Close)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failing.Close)
+
+	cfg := setting.NewCfg()
+	cfg.AnnotationFederation = setting.AnnotationFederationSettings{
+		Enabled: true,
+		Sources: []setting.AnnotationSourceSettings{
+			{Name: "ops", Type: SourceTypeGrafana, URL: grafana.URL, APIKey: "key", Timeout: time.Second},
+			{Name: "ci", Type: SourceTypeHTTP, URL: generic.URL, Timeout: time.Second},
+			{Name: "slow", Type: SourceTypeHTTP, URL: slow.URL, Timeout: 50 * time.Millisecond},
+			{Name: "failing", Type: SourceTypeHTTP, URL: failing.URL, Timeout: time.Second},
+			{Name: "other-org", Type: SourceTypeHTTP, URL: generic.URL, OrgIDs: []int64{2}, Timeout: time.Second},
+		},
+	}
+	s := ProvideService(cfg, nil)
+	ctx := context.Background()
+	local := []*annotations.ItemDTO{{ID: 1, Time: 2000, TimeEnd: 2000, Text: "local", Tags: []string{"deploy"}}}
+
+	t.Run("should merge the annotations of the sources by time", func(t *testing.T) {
+		items := s.Merge(ctx, &annotations.ItemQuery{OrgID: 1, From: 1000, To: 6000, Tags: []string{"deploy"}}, local)
+		require.Len(t, items, 3)
+
+		require.Equal(t, "v2", items[0].Text)
+		require.Equal(t, "ops", items[0].Source)
+		require.Zero(t, items[0].ID)
+		require.Zero(t, items[0].DashboardID)
+		require.Equal(t, grafana.URL+"/avatar/abc", items[0].AvatarURL)
+
+		require.Equal(t, "local", items[1].Text)
+		require.Empty(t, items[1].Source)
+
+		require.Equal(t, "v1", items[2].Text)
+		require.Equal(t, "ci", items[2].Source)
+		require.Equal(t, int64(1500), items[2].TimeEnd)
+	})
+
+	t.Run("should apply the limit to the merged annotations", func(t *testing.T) {
+		items := s.Merge(ctx, &annotations.ItemQuery{OrgID: 1, From: 1000, Tags: []string{"deploy"}, Limit: 2}, local)
+		require.Len(t, items, 2)
+		require.Equal(t, "local", items[1].Text)
+	})
+
+	t.Run("should not merge into the queries of a dashboard", func(t *testing.T) {
+		items := s.Merge(ctx, &annotations.ItemQuery{OrgID: 1, DashboardUID: "abc"}, local)
+		require.Equal(t, local, items)
+	})
+}
@@ -0,0 +1,144 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const maxResponseSize = 10 * 1024 * 1024
+
+// source queries the annotations of an external annotation source.
+type source struct {
+	cfg        setting.AnnotationSourceSettings
+	httpClient *http.Client
+}
+
+// httpAnnotation is the generic annotation format returned by the http sources.
+type httpAnnotation struct {
+	Time    int64    `json:"time"`
+	TimeEnd int64    `json:"timeEnd"`
+	Text    string   `json:"text"`
+	Tags    []string `json:"tags"`
+}
+
+func (s *source) enabledForOrg(orgID int64) bool {
+	if len(s.cfg.OrgIDs) == 0 {
+		return true
+	}
+	for _, id := range s.cfg.OrgIDs {
+		if id == orgID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *source) find(ctx context.Context, query *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
+	params := url.Values{}
+	if query.From > 0 {
+		params.Set("from", strconv.FormatInt(query.From, 10))
+	}
+	if query.To > 0 {
+		params.Set("to", strconv.FormatInt(query.To, 10))
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.FormatInt(query.Limit, 10))
+	}
+	for _, tag := range query.Tags {
+		params.Add("tags", tag)
+	}
+	if query.MatchAny {
+		params.Set("matchAny", "true")
+	}
+
+	u := s.cfg.URL
+	if s.cfg.Type == SourceTypeGrafana {
+		params.Set("type", "annotation")
+		u += "/api/annotations"
+	}
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u+sep+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.APIKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("annotation source %s returned status %d", s.cfg.Name, resp.StatusCode)
+	}
+	body := io.LimitReader(resp.Body, maxResponseSize)
+
+	if s.cfg.Type == SourceTypeGrafana {
+		return s.decodeGrafana(body)
+	}
+	return s.decodeHTTP(body)
+}
+
+// decodeGrafana reads the annotations of another Grafana, without their identifiers which don't exist here.
+func (s *source) decodeGrafana(body io.Reader) ([]*annotations.ItemDTO, error) {
+	var items []*annotations.ItemDTO
+	if err := json.NewDecoder(body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to decode the annotations of source %s: %w", s.cfg.Name, err)
+	}
+	for _, item := range items {
+		item.ID = 0
+		item.AlertID = 0
+		item.DashboardID = 0
+		item.DashboardUID = nil
+		item.PanelID = 0
+		item.UserID = 0
+		if strings.HasPrefix(item.AvatarURL, "/") {
+			item.AvatarURL = s.cfg.URL + item.AvatarURL
+		}
+		item.Source = s.cfg.Name
+	}
+	return items, nil
+}
+
+func (s *source) decodeHTTP(body io.Reader) ([]*annotations.ItemDTO, error) {
+	var found []httpAnnotation
+	if err := json.NewDecoder(body).Decode(&found); err != nil {
+		return nil, fmt.Errorf("failed to decode the annotations of source %s: %w", s.cfg.Name, err)
+	}
+	items := make([]*annotations.ItemDTO, 0, len(found))
+	for _, a := range found {
+		timeEnd := a.TimeEnd
+		if timeEnd == 0 {
+			timeEnd = a.Time
+		}
+		tags := a.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		items = append(items, &annotations.ItemDTO{
+			Time:    a.Time,
+			TimeEnd: timeEnd,
+			Text:    a.Text,
+			Tags:    tags,
+			Source:  s.cfg.Name,
+		})
+	}
+	return items, nil
+}
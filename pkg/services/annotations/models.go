@@ -107,6 +107,8 @@ type ItemDTO struct {
 	Email        string           `json:"email"`
 	AvatarURL    string           `json:"avatarUrl" xorm:"avatar_url"`
 	Data         *simplejson.Json `json:"data"`
+	// Source is the name of the external source of the annotation, empty for the annotations of Grafana
+	Source string `json:"source,omitempty" xorm:"-"`
 }
 
 type annotationType int
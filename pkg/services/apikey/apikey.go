@@ -2,6 +2,7 @@ package apikey
 
 import (
 	"context"
+	"net/http"
 )
 
 type Service interface {
@@ -13,6 +14,11 @@ type Service interface {
 	GetApiKeyByName(ctx context.Context, query *GetByNameQuery) error
 	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
 	UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error
+	// RecordAPIKeyUsage records the last use, the source IP and the endpoint of a request authenticated with an API
+	// key or a service account token. The usage is buffered and written to the database periodically.
+	RecordAPIKeyUsage(ctx context.Context, tokenID int64, req *http.Request)
+	// GetAPIKeyUsage returns the number of calls by endpoint of an API key or a service account token.
+	GetAPIKeyUsage(ctx context.Context, query *GetUsageQuery) ([]*EndpointUsage, error)
 	// IsDisabled returns true if the API key is not available for use.
 	IsDisabled(ctx context.Context, orgID int64) (bool, error)
 }
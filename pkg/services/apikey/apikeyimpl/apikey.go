@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -11,11 +12,16 @@ import (
 )
 
 type Service struct {
-	store store
+	store  store
+	usage  *usageBuffer
+	logger log.Logger
 }
 
-func ProvideService(db db.DB, cfg *setting.Cfg, quotaService quota.Service) (apikey.Service, error) {
-	s := &Service{}
+func ProvideService(db db.DB, cfg *setting.Cfg, quotaService quota.Service) (*Service, error) {
+	s := &Service{
+		usage:  newUsageBuffer(),
+		logger: log.New("apikey"),
+	}
 	if cfg.IsFeatureToggleEnabled(featuremgmt.FlagNewDBLibrary) {
 		s.store = &sqlxStore{
 			sess: db.GetSqlxSession(),
@@ -68,6 +74,9 @@ func (s *Service) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) error {
 func (s *Service) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
 	return s.store.UpdateAPIKeyLastUsedDate(ctx, tokenID)
 }
+func (s *Service) GetAPIKeyUsage(ctx context.Context, query *apikey.GetUsageQuery) ([]*apikey.EndpointUsage, error) {
+	return s.store.GetAPIKeyUsage(ctx, query)
+}
 
 // IsDisabled returns true if the apikey service is disabled for the given org.
 // This is the case if the org has no apikeys.
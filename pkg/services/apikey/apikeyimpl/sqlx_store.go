@@ -113,10 +113,11 @@ func (ss *sqlxStore) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) erro
 		Expires:          expires,
 		ServiceAccountId: nil,
 		IsRevoked:        &isRevoked,
+		Permissions:      cmd.Permissions,
 	}
 
 	t.ID, err = ss.sess.ExecWithReturningId(ctx,
-		`INSERT INTO api_key (org_id, name, role, "key", created, updated, expires, service_account_id, is_revoked) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, t.OrgID, t.Name, t.Role, t.Key, t.Created, t.Updated, t.Expires, t.ServiceAccountId, t.IsRevoked)
+		`INSERT INTO api_key (org_id, name, role, "key", created, updated, expires, service_account_id, is_revoked, permissions) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, t.OrgID, t.Name, t.Role, t.Key, t.Created, t.Updated, t.Expires, t.ServiceAccountId, t.IsRevoked, t.Permissions)
 	cmd.Result = &t
 	return err
 }
@@ -156,6 +157,46 @@ func (ss *sqlxStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64
 	return err
 }
 
+func (ss *sqlxStore) UpdateAPIKeysUsage(ctx context.Context, usage map[int64]*keyUsage) error {
+	return ss.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
+		for tokenID, u := range usage {
+			if _, err := tx.Exec(ctx, `UPDATE api_key SET last_used_at=?, last_used_ip=? WHERE id=?`, u.lastUsedAt, u.lastUsedIP, tokenID); err != nil {
+				return err
+			}
+
+			for endpoint, e := range u.endpoints {
+				res, err := tx.Exec(ctx, `UPDATE api_key_usage SET call_count=call_count+?, last_used_at=? WHERE api_key_id=? AND endpoint=?`, e.count, e.lastUsedAt, tokenID, endpoint)
+				if err != nil {
+					return err
+				}
+				if affected, err := res.RowsAffected(); err != nil {
+					return err
+				} else if affected > 0 {
+					continue
+				}
+				if _, err := tx.Exec(ctx, `INSERT INTO api_key_usage (api_key_id, endpoint, call_count, last_used_at) VALUES (?, ?, ?, ?)`, tokenID, endpoint, e.count, e.lastUsedAt); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (ss *sqlxStore) GetAPIKeyUsage(ctx context.Context, query *apikey.GetUsageQuery) ([]*apikey.EndpointUsage, error) {
+	usage := make([]*apikey.EndpointUsage, 0)
+	err := ss.sess.Select(ctx, &usage, `SELECT api_key_usage.* FROM api_key_usage
+		INNER JOIN api_key ON api_key.id = api_key_usage.api_key_id
+		WHERE api_key.org_id=? AND api_key.id=?
+		ORDER BY api_key_usage.call_count DESC`, query.OrgID, query.APIKeyID)
+	return usage, err
+}
+
+func (ss *sqlxStore) DeleteOrphanedAPIKeyUsage(ctx context.Context) error {
+	_, err := ss.sess.Exec(ctx, `DELETE FROM api_key_usage WHERE api_key_id NOT IN (SELECT id FROM api_key)`)
+	return err
+}
+
 func (ss *sqlxStore) Count(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
 	u := &quota.Map{}
 	type result struct {
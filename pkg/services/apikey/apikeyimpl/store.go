@@ -17,6 +17,9 @@ type store interface {
 	GetApiKeyByName(ctx context.Context, query *apikey.GetByNameQuery) error
 	GetAPIKeyByHash(ctx context.Context, hash string) (*apikey.APIKey, error)
 	UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error
+	UpdateAPIKeysUsage(ctx context.Context, usage map[int64]*keyUsage) error
+	GetAPIKeyUsage(ctx context.Context, query *apikey.GetUsageQuery) ([]*apikey.EndpointUsage, error)
+	DeleteOrphanedAPIKeyUsage(ctx context.Context) error
 
 	Count(context.Context, *quota.ScopeParameters) (*quota.Map, error)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -100,6 +101,22 @@ func testIntegrationApiKeyDataAccess(t *testing.T, fn getStore) {
 			assert.Nil(t, query.Result.Expires)
 		})
 
+		t.Run("Add a key restricted to permissions", func(t *testing.T) {
+			permissions := apikey.Permissions{{Action: "dashboards:read", Scope: "dashboards:uid:ci"}}
+			cmd := apikey.AddCommand{OrgID: 1, Name: "restricted", Key: "asd-restricted", Permissions: permissions}
+			err := ss.AddAPIKey(context.Background(), &cmd)
+			require.NoError(t, err)
+
+			key, err := ss.GetAPIKeyByHash(context.Background(), cmd.Key)
+			require.NoError(t, err)
+			assert.Equal(t, permissions, key.Permissions)
+
+			query := apikey.GetByNameQuery{KeyName: "non-expiring", OrgID: 1}
+			err = ss.GetApiKeyByName(context.Background(), &query)
+			require.NoError(t, err)
+			assert.Nil(t, query.Result.Permissions)
+		})
+
 		t.Run("Add an expiring key", func(t *testing.T) {
 			// expires in one hour
 			cmd := apikey.AddCommand{OrgID: 1, Name: "expiring-in-an-hour", Key: "asd2", SecondsToLive: 3600}
@@ -137,6 +154,53 @@ func testIntegrationApiKeyDataAccess(t *testing.T, fn getStore) {
 			assert.NotNil(t, query.Result.LastUsedAt)
 		})
 
+		t.Run("Usage update", func(t *testing.T) {
+			cmd := apikey.AddCommand{OrgID: 1, Name: "usage", Key: "asd-usage"}
+			err := ss.AddAPIKey(context.Background(), &cmd)
+			require.NoError(t, err)
+
+			usedAt := time.Unix(1680000000, 0)
+			buffer := newUsageBuffer()
+			buffer.add(cmd.Result.ID, "10.0.0.1", "GET /api/dashboards/uid/:id", usedAt)
+			buffer.add(cmd.Result.ID, "10.0.0.1", "GET /api/dashboards/uid/:id", usedAt)
+			buffer.add(cmd.Result.ID, "10.0.0.2", "POST /api/ds/query", usedAt)
+			require.NoError(t, ss.UpdateAPIKeysUsage(context.Background(), buffer.take()))
+
+			buffer.add(cmd.Result.ID, "10.0.0.3", "GET /api/dashboards/uid/:id", usedAt)
+			require.NoError(t, ss.UpdateAPIKeysUsage(context.Background(), buffer.take()))
+
+			query := apikey.GetByNameQuery{KeyName: "usage", OrgID: 1}
+			err = ss.GetApiKeyByName(context.Background(), &query)
+			require.NoError(t, err)
+			require.NotNil(t, query.Result.LastUsedIP)
+			assert.Equal(t, "10.0.0.3", *query.Result.LastUsedIP)
+			assert.NotNil(t, query.Result.LastUsedAt)
+
+			usage, err := ss.GetAPIKeyUsage(context.Background(), &apikey.GetUsageQuery{OrgID: 1, APIKeyID: cmd.Result.ID})
+			require.NoError(t, err)
+			require.Len(t, usage, 2)
+			assert.Equal(t, "GET /api/dashboards/uid/:id", usage[0].Endpoint)
+			assert.Equal(t, int64(3), usage[0].Count)
+			assert.Equal(t, "POST /api/ds/query", usage[1].Endpoint)
+			assert.Equal(t, int64(1), usage[1].Count)
+
+			usage, err = ss.GetAPIKeyUsage(context.Background(), &apikey.GetUsageQuery{OrgID: 2, APIKeyID: cmd.Result.ID})
+			require.NoError(t, err)
+			assert.Empty(t, usage, "the usage of another organization is not returned")
+
+			err = ss.DeleteApiKey(context.Background(), &apikey.DeleteCommand{ID: cmd.Result.ID, OrgID: 1})
+			require.NoError(t, err)
+			require.NoError(t, ss.DeleteOrphanedAPIKeyUsage(context.Background()))
+			var count int64
+			err = db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+				var err error
+				count, err = sess.Table("api_key_usage").Where("api_key_id = ?", cmd.Result.ID).Count()
+				return err
+			})
+			require.NoError(t, err)
+			assert.Zero(t, count)
+		})
+
 		t.Run("Add a key with negative lifespan", func(t *testing.T) {
 			// expires in one day
 			cmd := apikey.AddCommand{OrgID: 1, Name: "key-with-negative-lifespan", Key: "asd3", SecondsToLive: -3600}
@@ -0,0 +1,149 @@
+package apikeyimpl
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	// usageFlushInterval is how often the buffered usage is written to the database, so that busy API keys don't
+	// update their row on every request.
+	usageFlushInterval = 30 * time.Second
+	// usageCleanupInterval is how often the usage of deleted API keys is removed.
+	usageCleanupInterval = time.Hour
+	// maxEndpointsPerKey bounds the number of endpoints tracked for a key, the other calls are counted as "other".
+	maxEndpointsPerKey = 100
+	// maxEndpointSegments is the number of path segments kept in an endpoint.
+	maxEndpointSegments = 4
+
+	otherEndpoint = "other"
+)
+
+var (
+	// idSegmentRegexp matches the path segments that are likely to be IDs: numbers and UIDs that contain a digit.
+	idSegmentRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]*\d[a-zA-Z0-9_-]*$`)
+	// versionSegmentRegexp matches the API versions in the path, like v1.
+	versionSegmentRegexp = regexp.MustCompile(`^v\d+$`)
+)
+
+// keyUsage is the usage of an API key buffered since the last flush.
+type keyUsage struct {
+	lastUsedAt time.Time
+	lastUsedIP string
+	endpoints  map[string]*endpointUsage
+}
+
+type endpointUsage struct {
+	count      int64
+	lastUsedAt time.Time
+}
+
+type usageBuffer struct {
+	mu    sync.Mutex
+	usage map[int64]*keyUsage
+}
+
+func newUsageBuffer() *usageBuffer {
+	return &usageBuffer{usage: make(map[int64]*keyUsage)}
+}
+
+func (b *usageBuffer) add(tokenID int64, ip, endpoint string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, ok := b.usage[tokenID]
+	if !ok {
+		u = &keyUsage{endpoints: make(map[string]*endpointUsage)}
+		b.usage[tokenID] = u
+	}
+	u.lastUsedAt = now
+	u.lastUsedIP = ip
+
+	e, ok := u.endpoints[endpoint]
+	if !ok {
+		if len(u.endpoints) >= maxEndpointsPerKey {
+			endpoint = otherEndpoint
+		}
+		if e, ok = u.endpoints[endpoint]; !ok {
+			e = &endpointUsage{}
+			u.endpoints[endpoint] = e
+		}
+	}
+	e.count++
+	e.lastUsedAt = now
+}
+
+// take returns the buffered usage and empties the buffer.
+func (b *usageBuffer) take() map[int64]*keyUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	usage := b.usage
+	b.usage = make(map[int64]*keyUsage)
+	return usage
+}
+
+// usageEndpoint groups the requests by method and path, the IDs in the path are replaced and the path is truncated
+// to keep the number of endpoints small.
+func usageEndpoint(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(segments) > maxEndpointSegments {
+		segments = segments[:maxEndpointSegments]
+	}
+	for i, segment := range segments {
+		if idSegmentRegexp.MatchString(segment) && !versionSegmentRegexp.MatchString(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return req.Method + " /" + strings.Join(segments, "/")
+}
+
+// Run flushes the buffered usage of the API keys to the database.
+func (s *Service) Run(ctx context.Context) error {
+	flush := time.NewTicker(usageFlushInterval)
+	defer flush.Stop()
+	cleanup := time.NewTicker(usageCleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-flush.C:
+			s.flushUsage(ctx)
+		case <-cleanup.C:
+			if err := s.store.DeleteOrphanedAPIKeyUsage(ctx); err != nil {
+				s.logger.Warn("Failed to delete the usage of deleted API keys", "error", err)
+			}
+		case <-ctx.Done():
+			// write the usage buffered since the last flush before shutting down
+			s.flushUsage(context.Background())
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) flushUsage(ctx context.Context) {
+	usage := s.usage.take()
+	if len(usage) == 0 {
+		return
+	}
+	if err := s.store.UpdateAPIKeysUsage(ctx, usage); err != nil {
+		s.logger.Warn("Failed to update the usage of API keys", "keys", len(usage), "error", err)
+	}
+}
+
+// RecordAPIKeyUsage buffers the usage of an API key or a service account token by a request.
+func (s *Service) RecordAPIKeyUsage(ctx context.Context, tokenID int64, req *http.Request) {
+	ip := ""
+	endpoint := otherEndpoint
+	if req != nil {
+		ip = web.RemoteAddr(req)
+		endpoint = usageEndpoint(req)
+	}
+	s.usage.add(tokenID, ip, endpoint, timeNow())
+}
@@ -0,0 +1,87 @@
+package apikeyimpl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestUsageEndpoint(t *testing.T) {
+	testCases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: http.MethodGet, path: "/api/dashboards/uid/abc123", expected: "GET /api/dashboards/uid/:id"},
+		{method: http.MethodPost, path: "/api/ds/query", expected: "POST /api/ds/query"},
+		{method: http.MethodGet, path: "/api/datasources/42/resources/a/b", expected: "GET /api/datasources/:id/resources"},
+		{method: http.MethodGet, path: "/api/v1/provisioning/alert-rules", expected: "GET /api/v1/provisioning/alert-rules"},
+	}
+
+	for _, tc := range testCases {
+		req, err := http.NewRequest(tc.method, tc.path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, usageEndpoint(req), tc.path)
+	}
+}
+
+func TestUsageBuffer(t *testing.T) {
+	now := time.Unix(1680000000, 0)
+	buffer := newUsageBuffer()
+
+	buffer.add(1, "10.0.0.1", "GET /api/search", now)
+	buffer.add(1, "10.0.0.2", "GET /api/search", now.Add(time.Second))
+	buffer.add(2, "10.0.0.3", "POST /api/ds/query", now)
+
+	usage := buffer.take()
+	require.Len(t, usage, 2)
+	assert.Equal(t, "10.0.0.2", usage[1].lastUsedIP)
+	assert.Equal(t, now.Add(time.Second), usage[1].lastUsedAt)
+	assert.Equal(t, int64(2), usage[1].endpoints["GET /api/search"].count)
+	assert.Empty(t, buffer.take(), "the buffer is emptied")
+
+	t.Run("counts the calls of too many endpoints as other", func(t *testing.T) {
+		for i := 0; i < maxEndpointsPerKey+5; i++ {
+			buffer.add(1, "10.0.0.1", fmt.Sprintf("GET /api/endpoint/%d", i), now)
+		}
+		usage := buffer.take()
+		assert.Len(t, usage[1].endpoints, maxEndpointsPerKey+1)
+		assert.Equal(t, int64(5), usage[1].endpoints[otherEndpoint].count)
+	})
+}
+
+func TestService_RecordAPIKeyUsage(t *testing.T) {
+	store := &usageStore{}
+	s := &Service{store: store, usage: newUsageBuffer(), logger: log.NewNopLogger()}
+
+	req, err := http.NewRequest(http.MethodGet, "/api/search", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "10.0.0.1:1234"
+	s.RecordAPIKeyUsage(context.Background(), 1, req)
+	s.RecordAPIKeyUsage(context.Background(), 1, req)
+
+	s.flushUsage(context.Background())
+	require.Len(t, store.updates, 1)
+	assert.Equal(t, "10.0.0.1", store.updates[0][1].lastUsedIP)
+	assert.Equal(t, int64(2), store.updates[0][1].endpoints["GET /api/search"].count)
+
+	s.flushUsage(context.Background())
+	assert.Len(t, store.updates, 1, "nothing is written without usage")
+}
+
+type usageStore struct {
+	store
+	updates []map[int64]*keyUsage
+}
+
+func (s *usageStore) UpdateAPIKeysUsage(ctx context.Context, usage map[int64]*keyUsage) error {
+	s.updates = append(s.updates, usage)
+	return nil
+}
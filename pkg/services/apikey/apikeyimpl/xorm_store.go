@@ -128,6 +128,7 @@ func (ss *sqlStore) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) error
 			Expires:          expires,
 			ServiceAccountId: cmd.ServiceAccountID,
 			IsRevoked:        &isRevoked,
+			Permissions:      cmd.Permissions,
 		}
 
 		if _, err := sess.Insert(&t); err != nil {
@@ -195,6 +196,51 @@ func (ss *sqlStore) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64)
 	})
 }
 
+func (ss *sqlStore) UpdateAPIKeysUsage(ctx context.Context, usage map[int64]*keyUsage) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for tokenID, u := range usage {
+			lastUsedAt, lastUsedIP := u.lastUsedAt, u.lastUsedIP
+			if _, err := sess.Table("api_key").ID(tokenID).Cols("last_used_at", "last_used_ip").Update(&apikey.APIKey{LastUsedAt: &lastUsedAt, LastUsedIP: &lastUsedIP}); err != nil {
+				return err
+			}
+
+			for endpoint, e := range u.endpoints {
+				res, err := sess.Exec("UPDATE api_key_usage SET call_count = call_count + ?, last_used_at = ? WHERE api_key_id = ? AND endpoint = ?", e.count, e.lastUsedAt, tokenID, endpoint)
+				if err != nil {
+					return err
+				}
+				if affected, err := res.RowsAffected(); err != nil {
+					return err
+				} else if affected > 0 {
+					continue
+				}
+				if _, err := sess.Insert(&apikey.EndpointUsage{APIKeyID: tokenID, Endpoint: endpoint, Count: e.count, LastUsedAt: e.lastUsedAt}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (ss *sqlStore) GetAPIKeyUsage(ctx context.Context, query *apikey.GetUsageQuery) ([]*apikey.EndpointUsage, error) {
+	usage := make([]*apikey.EndpointUsage, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(`SELECT api_key_usage.* FROM api_key_usage
+			INNER JOIN api_key ON api_key.id = api_key_usage.api_key_id
+			WHERE api_key.org_id = ? AND api_key.id = ?
+			ORDER BY api_key_usage.call_count DESC`, query.OrgID, query.APIKeyID).Find(&usage)
+	})
+	return usage, err
+}
+
+func (ss *sqlStore) DeleteOrphanedAPIKeyUsage(ctx context.Context) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM api_key_usage WHERE api_key_id NOT IN (SELECT id FROM api_key)")
+		return err
+	})
+}
+
 func (ss *sqlStore) Count(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
 	u := &quota.Map{}
 	type result struct {
@@ -2,6 +2,7 @@ package apikeytest
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/services/apikey"
 )
@@ -11,6 +12,9 @@ type Service struct {
 	ExpectedBool    bool
 	ExpectedAPIKeys []*apikey.APIKey
 	ExpectedAPIKey  *apikey.APIKey
+	ExpectedUsage   []*apikey.EndpointUsage
+
+	RecordedUsage []int64
 }
 
 func (s *Service) GetAPIKeys(ctx context.Context, query *apikey.GetApiKeysQuery) error {
@@ -41,6 +45,12 @@ func (s *Service) AddAPIKey(ctx context.Context, cmd *apikey.AddCommand) error {
 func (s *Service) UpdateAPIKeyLastUsedDate(ctx context.Context, tokenID int64) error {
 	return s.ExpectedError
 }
+func (s *Service) RecordAPIKeyUsage(ctx context.Context, tokenID int64, req *http.Request) {
+	s.RecordedUsage = append(s.RecordedUsage, tokenID)
+}
+func (s *Service) GetAPIKeyUsage(ctx context.Context, query *apikey.GetUsageQuery) ([]*apikey.EndpointUsage, error) {
+	return s.ExpectedUsage, s.ExpectedError
+}
 func (s *Service) IsDisabled(ctx context.Context, orgID int64) (bool, error) {
 	return s.ExpectedBool, s.ExpectedError
 }
@@ -1,7 +1,10 @@
 package apikey
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/org"
@@ -25,13 +28,91 @@ type APIKey struct {
 	Created          time.Time    `db:"created"`
 	Updated          time.Time    `db:"updated"`
 	LastUsedAt       *time.Time   `xorm:"last_used_at" db:"last_used_at"`
+	LastUsedIP       *string      `xorm:"last_used_ip" db:"last_used_ip"`
 	Expires          *int64       `db:"expires"`
 	ServiceAccountId *int64       `db:"service_account_id"`
 	IsRevoked        *bool        `xorm:"is_revoked" db:"is_revoked"`
+	// Permissions restrict a service account token to a subset of the permissions of its service account, the
+	// token has all of them when empty.
+	Permissions Permissions `xorm:"permissions" db:"permissions"`
 }
 
 func (k APIKey) TableName() string { return "api_key" }
 
+// Permission is an action and its scope, as in the roles of access control.
+type Permission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+// Permissions are stored as JSON, NULL when empty.
+type Permissions []Permission
+
+// ByAction groups the scopes of the permissions by action, like the permissions of a signed in user.
+func (p Permissions) ByAction() map[string][]string {
+	if len(p) == 0 {
+		return nil
+	}
+	grouped := make(map[string][]string, len(p))
+	for _, permission := range p {
+		grouped[permission.Action] = append(grouped[permission.Action], permission.Scope)
+	}
+	return grouped
+}
+
+func (p *Permissions) FromDB(data []byte) error {
+	*p = nil
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, p)
+}
+
+func (p *Permissions) ToDB() ([]byte, error) {
+	if p == nil || len(*p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+func (p *Permissions) Scan(val interface{}) error {
+	switch v := val.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		return p.FromDB(v)
+	case string:
+		return p.FromDB([]byte(v))
+	default:
+		return fmt.Errorf("unsupported type: %T", v)
+	}
+}
+
+func (p Permissions) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// EndpointUsage is the number of calls of an endpoint made with an API key or a service account token.
+type EndpointUsage struct {
+	ID         int64     `db:"id" xorm:"pk autoincr 'id'" json:"-"`
+	APIKeyID   int64     `db:"api_key_id" xorm:"api_key_id" json:"-"`
+	Endpoint   string    `db:"endpoint" json:"endpoint"`
+	Count      int64     `db:"call_count" xorm:"call_count" json:"count"`
+	LastUsedAt time.Time `db:"last_used_at" xorm:"last_used_at" json:"lastUsedAt"`
+}
+
+func (u EndpointUsage) TableName() string { return "api_key_usage" }
+
+// GetUsageQuery returns the usage by endpoint of an API key or a service account token of the organization.
+type GetUsageQuery struct {
+	OrgID    int64
+	APIKeyID int64
+}
+
 // swagger:model
 type AddCommand struct {
 	Name             string       `json:"name" binding:"Required"`
@@ -40,6 +121,7 @@ type AddCommand struct {
 	Key              string       `json:"-"`
 	SecondsToLive    int64        `json:"secondsToLive"`
 	ServiceAccountID *int64       `json:"-"`
+	Permissions      Permissions  `json:"-"`
 
 	Result *APIKey `json:"-"`
 }
@@ -12,8 +12,8 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/distlock"
 	"github.com/grafana/grafana/pkg/infra/log"
-	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -26,15 +26,15 @@ const urgentRotateTime = 1 * time.Minute
 var getTime = time.Now
 
 func ProvideUserAuthTokenService(sqlStore db.DB,
-	serverLockService *serverlock.ServerLockService,
+	lockService *distlock.Service,
 	quotaService quota.Service,
 	cfg *setting.Cfg) (*UserAuthTokenService, error) {
 	s := &UserAuthTokenService{
-		sqlStore:          sqlStore,
-		serverLockService: serverLockService,
-		cfg:               cfg,
-		log:               log.New("auth"),
-		singleflight:      new(singleflight.Group),
+		sqlStore:     sqlStore,
+		lockService:  lockService,
+		cfg:          cfg,
+		log:          log.New("auth"),
+		singleflight: new(singleflight.Group),
 	}
 
 	defaultLimits, err := readQuotaConfig(cfg)
@@ -54,14 +54,14 @@ func ProvideUserAuthTokenService(sqlStore db.DB,
 }
 
 type UserAuthTokenService struct {
-	sqlStore          db.DB
-	serverLockService *serverlock.ServerLockService
-	cfg               *setting.Cfg
-	log               log.Logger
-	singleflight      *singleflight.Group
+	sqlStore     db.DB
+	lockService  *distlock.Service
+	cfg          *setting.Cfg
+	log          log.Logger
+	singleflight *singleflight.Group
 }
 
-func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User, clientIP net.IP, userAgent string) (*auth.UserToken, error) {
+func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User, clientIP net.IP, userAgent string, authModule string) (*auth.UserToken, error) {
 	token, err := util.RandomHex(16)
 	if err != nil {
 		return nil, err
@@ -87,6 +87,7 @@ func (s *UserAuthTokenService) CreateToken(ctx context.Context, user *user.User,
 		SeenAt:        0,
 		RevokedAt:     0,
 		AuthTokenSeen: false,
+		AuthModule:    authModule,
 	}
 
 	err = s.sqlStore.WithDbSession(ctx, func(dbSession *db.Session) error {
@@ -32,7 +32,7 @@ func TestUserAuthToken(t *testing.T) {
 	t.Run("When creating token", func(t *testing.T) {
 		createToken := func() *auth.UserToken {
 			userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-				net.ParseIP("192.168.10.11"), "some user agent")
+				net.ParseIP("192.168.10.11"), "some user agent", "ldap")
 			require.Nil(t, err)
 			require.NotNil(t, userToken)
 			require.False(t, userToken.AuthTokenSeen)
@@ -56,6 +56,7 @@ func TestUserAuthToken(t *testing.T) {
 			require.Nil(t, err)
 			require.NotNil(t, userToken)
 			require.Equal(t, user.ID, userToken.UserId)
+			require.Equal(t, "ldap", userToken.AuthModule)
 			require.True(t, userToken.AuthTokenSeen)
 
 			storedAuthToken, err := ctx.getAuthTokenByID(userToken.Id)
@@ -105,7 +106,7 @@ func TestUserAuthToken(t *testing.T) {
 
 		t.Run("When creating an additional token", func(t *testing.T) {
 			userToken2, err := ctx.tokenService.CreateToken(context.Background(), user,
-				net.ParseIP("192.168.10.11"), "some user agent")
+				net.ParseIP("192.168.10.11"), "some user agent", "")
 			require.Nil(t, err)
 			require.NotNil(t, userToken2)
 
@@ -152,7 +153,7 @@ func TestUserAuthToken(t *testing.T) {
 					userId := user.ID + int64(i+1)
 					userIds = append(userIds, userId)
 					_, err := ctx.tokenService.CreateToken(context.Background(), user,
-						net.ParseIP("192.168.10.11"), "some user agent")
+						net.ParseIP("192.168.10.11"), "some user agent", "")
 					require.Nil(t, err)
 				}
 
@@ -171,7 +172,7 @@ func TestUserAuthToken(t *testing.T) {
 	t.Run("expires correctly", func(t *testing.T) {
 		ctx := createTestContext(t)
 		userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-			net.ParseIP("192.168.10.11"), "some user agent")
+			net.ParseIP("192.168.10.11"), "some user agent", "")
 		require.Nil(t, err)
 
 		userToken, err = ctx.tokenService.LookupToken(context.Background(), userToken.UnhashedToken)
@@ -257,7 +258,7 @@ func TestUserAuthToken(t *testing.T) {
 		getTime = func() time.Time { return now }
 		ctx := createTestContext(t)
 		userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-			net.ParseIP("192.168.10.11"), "some user agent")
+			net.ParseIP("192.168.10.11"), "some user agent", "")
 		require.Nil(t, err)
 
 		prevToken := userToken.AuthToken
@@ -341,7 +342,7 @@ func TestUserAuthToken(t *testing.T) {
 	t.Run("keeps prev token valid for 1 minute after it is confirmed", func(t *testing.T) {
 		getTime = func() time.Time { return now }
 		userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-			net.ParseIP("192.168.10.11"), "some user agent")
+			net.ParseIP("192.168.10.11"), "some user agent", "")
 		require.Nil(t, err)
 		require.NotNil(t, userToken)
 
@@ -372,7 +373,7 @@ func TestUserAuthToken(t *testing.T) {
 
 	t.Run("will not mark token unseen when prev and current are the same", func(t *testing.T) {
 		userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-			net.ParseIP("192.168.10.11"), "some user agent")
+			net.ParseIP("192.168.10.11"), "some user agent", "")
 		require.Nil(t, err)
 		require.NotNil(t, userToken)
 
@@ -394,7 +395,7 @@ func TestUserAuthToken(t *testing.T) {
 		t.Run("Should rotate current token and previous token when auth token seen", func(t *testing.T) {
 			getTime = func() time.Time { return now }
 			userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-				net.ParseIP("192.168.10.11"), "some user agent")
+				net.ParseIP("192.168.10.11"), "some user agent", "")
 			require.Nil(t, err)
 			require.NotNil(t, userToken)
 
@@ -446,7 +447,7 @@ func TestUserAuthToken(t *testing.T) {
 		t.Run("Should rotate current token, but keep previous token when auth token not seen", func(t *testing.T) {
 			getTime = func() time.Time { return now }
 			userToken, err := ctx.tokenService.CreateToken(context.Background(), user,
-				net.ParseIP("192.168.10.11"), "some user agent")
+				net.ParseIP("192.168.10.11"), "some user agent", "")
 			require.Nil(t, err)
 			require.NotNil(t, userToken)
 
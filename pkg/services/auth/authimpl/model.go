@@ -19,6 +19,7 @@ type userAuthToken struct {
 	CreatedAt     int64
 	UpdatedAt     int64
 	RevokedAt     int64
+	AuthModule    string
 	UnhashedToken string `xorm:"-"`
 }
 
@@ -45,6 +46,7 @@ func (uat *userAuthToken) fromUserToken(ut *auth.UserToken) error {
 	uat.CreatedAt = ut.CreatedAt
 	uat.UpdatedAt = ut.UpdatedAt
 	uat.RevokedAt = ut.RevokedAt
+	uat.AuthModule = ut.AuthModule
 	uat.UnhashedToken = ut.UnhashedToken
 
 	return nil
@@ -67,6 +69,7 @@ func (uat *userAuthToken) toUserToken(ut *auth.UserToken) error {
 	ut.CreatedAt = uat.CreatedAt
 	ut.UpdatedAt = uat.UpdatedAt
 	ut.RevokedAt = uat.RevokedAt
+	ut.AuthModule = uat.AuthModule
 	ut.UnhashedToken = uat.UnhashedToken
 
 	return nil
@@ -14,7 +14,7 @@ import (
 )
 
 type FakeUserAuthTokenService struct {
-	CreateTokenProvider          func(ctx context.Context, user *user.User, clientIP net.IP, userAgent string) (*auth.UserToken, error)
+	CreateTokenProvider          func(ctx context.Context, user *user.User, clientIP net.IP, userAgent string, authModule string) (*auth.UserToken, error)
 	TryRotateTokenProvider       func(ctx context.Context, token *auth.UserToken, clientIP net.IP, userAgent string) (bool, *auth.UserToken, error)
 	LookupTokenProvider          func(ctx context.Context, unhashedToken string) (*auth.UserToken, error)
 	RevokeTokenProvider          func(ctx context.Context, token *auth.UserToken, soft bool) error
@@ -28,7 +28,7 @@ type FakeUserAuthTokenService struct {
 
 func NewFakeUserAuthTokenService() *FakeUserAuthTokenService {
 	return &FakeUserAuthTokenService{
-		CreateTokenProvider: func(ctx context.Context, user *user.User, clientIP net.IP, userAgent string) (*auth.UserToken, error) {
+		CreateTokenProvider: func(ctx context.Context, user *user.User, clientIP net.IP, userAgent string, authModule string) (*auth.UserToken, error) {
 			return &auth.UserToken{
 				UserId:        0,
 				UnhashedToken: "",
@@ -70,8 +70,8 @@ func (s *FakeUserAuthTokenService) Init() error {
 	return nil
 }
 
-func (s *FakeUserAuthTokenService) CreateToken(ctx context.Context, user *user.User, clientIP net.IP, userAgent string) (*auth.UserToken, error) {
-	return s.CreateTokenProvider(context.Background(), user, clientIP, userAgent)
+func (s *FakeUserAuthTokenService) CreateToken(ctx context.Context, user *user.User, clientIP net.IP, userAgent string, authModule string) (*auth.UserToken, error) {
+	return s.CreateTokenProvider(context.Background(), user, clientIP, userAgent, authModule)
 }
 
 func (s *FakeUserAuthTokenService) LookupToken(ctx context.Context, unhashedToken string) (*auth.UserToken, error) {
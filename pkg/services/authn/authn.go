@@ -22,15 +22,16 @@ import (
 )
 
 const (
-	ClientAPIKey    = "auth.client.api-key" // #nosec G101
-	ClientAnonymous = "auth.client.anonymous"
-	ClientBasic     = "auth.client.basic"
-	ClientJWT       = "auth.client.jwt"
-	ClientRender    = "auth.client.render"
-	ClientSession   = "auth.client.session"
-	ClientForm      = "auth.client.form"
-	ClientProxy     = "auth.client.proxy"
-	ClientSAML      = "auth.client.saml"
+	ClientAPIKey      = "auth.client.api-key" // #nosec G101
+	ClientAnonymous   = "auth.client.anonymous"
+	ClientBasic       = "auth.client.basic"
+	ClientJWT         = "auth.client.jwt"
+	ClientRender      = "auth.client.render"
+	ClientSession     = "auth.client.session"
+	ClientForm        = "auth.client.form"
+	ClientProxy       = "auth.client.proxy"
+	ClientSAML        = "auth.client.saml"
+	ClientOAuthServer = "auth.client.oauth-server"
 )
 
 const (
@@ -225,6 +226,9 @@ type Identity struct {
 	ClientParams ClientParams
 	// Permissions is the list of permissions the entity has.
 	Permissions map[int64]map[string][]string
+	// PermissionBoundary restricts the permissions of a service account signed in with a token created for a
+	// subset of them.
+	PermissionBoundary map[string][]string
 }
 
 // Role returns the role of the identity in the active organization.
@@ -278,6 +282,7 @@ func (i *Identity) SignedInUser() *user.SignedInUser {
 		LastSeenAt:         i.LastSeenAt,
 		Teams:              i.Teams,
 		Permissions:        i.Permissions,
+		PermissionBoundary: i.PermissionBoundary,
 	}
 
 	namespace, id := i.NamespacedID()
@@ -311,21 +316,22 @@ func (i *Identity) ExternalUserInfo() login.ExternalUserInfo {
 // IdentityFromSignedInUser creates an identity from a SignedInUser.
 func IdentityFromSignedInUser(id string, usr *user.SignedInUser, params ClientParams) *Identity {
 	return &Identity{
-		ID:             id,
-		OrgID:          usr.OrgID,
-		OrgName:        usr.OrgName,
-		OrgRoles:       map[int64]org.RoleType{usr.OrgID: usr.OrgRole},
-		Login:          usr.Login,
-		Name:           usr.Name,
-		Email:          usr.Email,
-		OrgCount:       usr.OrgCount,
-		IsGrafanaAdmin: &usr.IsGrafanaAdmin,
-		IsDisabled:     usr.IsDisabled,
-		HelpFlags1:     usr.HelpFlags1,
-		LastSeenAt:     usr.LastSeenAt,
-		Teams:          usr.Teams,
-		ClientParams:   params,
-		Permissions:    usr.Permissions,
+		ID:                 id,
+		OrgID:              usr.OrgID,
+		OrgName:            usr.OrgName,
+		OrgRoles:           map[int64]org.RoleType{usr.OrgID: usr.OrgRole},
+		Login:              usr.Login,
+		Name:               usr.Name,
+		Email:              usr.Email,
+		OrgCount:           usr.OrgCount,
+		IsGrafanaAdmin:     &usr.IsGrafanaAdmin,
+		IsDisabled:         usr.IsDisabled,
+		HelpFlags1:         usr.HelpFlags1,
+		LastSeenAt:         usr.LastSeenAt,
+		Teams:              usr.Teams,
+		ClientParams:       params,
+		Permissions:        usr.Permissions,
+		PermissionBoundary: usr.PermissionBoundary,
 	}
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ldap/service"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/loginattempt"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -61,7 +62,7 @@ func ProvideService(
 	authInfoService login.AuthInfoService, renderService rendering.Service,
 	features *featuremgmt.FeatureManager, oauthTokenService oauthtoken.OAuthTokenService,
 	socialService social.Service, cache *remotecache.RemoteCache,
-	ldapService service.LDAP, registerer prometheus.Registerer,
+	ldapService service.LDAP, registerer prometheus.Registerer, oauthServer oauthserver.Service,
 ) authn.Service {
 	s := &Service{
 		log:            log.New("authn.service"),
@@ -80,6 +81,10 @@ func ProvideService(
 	s.RegisterClient(clients.ProvideRender(userService, renderService))
 	s.RegisterClient(clients.ProvideAPIKey(apikeyService, userService))
 
+	if cfg.OAuthServer.Enabled {
+		s.RegisterClient(clients.ProvideOAuthServer(oauthServer, userService))
+	}
+
 	if cfg.LoginCookieName != "" {
 		s.RegisterClient(clients.ProvideSession(sessionService, userService, cfg))
 	}
@@ -277,7 +282,13 @@ func (s *Service) Login(ctx context.Context, client string, r *authn.Request) (i
 		s.log.FromContext(ctx).Debug("Failed to parse ip from address", "client", c.Name(), "id", identity.ID, "addr", addr, "error", err)
 	}
 
-	sessionToken, err := s.sessionService.CreateToken(ctx, &user.User{ID: id}, ip, r.HTTPRequest.UserAgent())
+	// the password clients only set the auth module in the meta of the request
+	authModule := identity.AuthModule
+	if authModule == "" {
+		authModule = r.GetMeta(authn.MetaKeyAuthModule)
+	}
+
+	sessionToken, err := s.sessionService.CreateToken(ctx, &user.User{ID: id}, ip, r.HTTPRequest.UserAgent(), authModule)
 	if err != nil {
 		s.metrics.failedLogin.WithLabelValues(client).Inc()
 		s.log.FromContext(ctx).Error("Failed to create session", "client", client, "id", identity.ID, "err", err)
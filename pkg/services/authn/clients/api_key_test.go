@@ -90,6 +90,41 @@ func TestAPIKey_Authenticate(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "should restrict the permissions of a service account token created for a subset of them",
+			req: &authn.Request{HTTPRequest: &http.Request{
+				Header: map[string][]string{
+					"Authorization": {"Bearer " + secret},
+				},
+			}},
+			expectedKey: &apikey.APIKey{
+				ID:               1,
+				OrgID:            1,
+				Key:              hash,
+				ServiceAccountId: intPtr(1),
+				Permissions:      apikey.Permissions{{Action: "dashboards:read", Scope: "dashboards:uid:ci"}},
+			},
+			expectedUser: &user.SignedInUser{
+				UserID:           1,
+				OrgID:            1,
+				IsServiceAccount: true,
+				OrgCount:         1,
+				OrgRole:          org.RoleViewer,
+				Name:             "test",
+			},
+			expectedIdentity: &authn.Identity{
+				ID:                 "service-account:1",
+				OrgID:              1,
+				OrgCount:           1,
+				Name:               "test",
+				OrgRoles:           map[int64]org.RoleType{1: org.RoleViewer},
+				IsGrafanaAdmin:     boolPtr(false),
+				PermissionBoundary: map[string][]string{"dashboards:read": {"dashboards:uid:ci"}},
+				ClientParams: authn.ClientParams{
+					SyncPermissions: true,
+				},
+			},
+		},
 		{
 			desc: "should fail for expired api key",
 			req:  &authn.Request{HTTPRequest: &http.Request{Header: map[string][]string{"Authorization": {"Bearer " + secret}}}},
@@ -112,9 +147,10 @@ func TestAPIKey_Authenticate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
-			c := ProvideAPIKey(&apikeytest.Service{
+			apiKeyService := &apikeytest.Service{
 				ExpectedAPIKey: tt.expectedKey,
-			}, &usertest.FakeUserService{
+			}
+			c := ProvideAPIKey(apiKeyService, &usertest.FakeUserService{
 				ExpectedSignedInUser: tt.expectedUser,
 			})
 
@@ -122,9 +158,11 @@ func TestAPIKey_Authenticate(t *testing.T) {
 			if tt.expectedErr != nil {
 				assert.Nil(t, identity)
 				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Empty(t, apiKeyService.RecordedUsage)
 			} else {
 				assert.NoError(t, err)
 				assert.EqualValues(t, *tt.expectedIdentity, *identity)
+				assert.Equal(t, []int64{tt.expectedKey.ID}, apiKeyService.RecordedUsage)
 			}
 		})
 	}
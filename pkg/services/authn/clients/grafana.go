@@ -7,6 +7,7 @@ import (
 	"net/mail"
 
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/proxyheaders"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -71,14 +72,15 @@ func (c *Grafana) AuthenticateProxy(ctx context.Context, r *authn.Request, usern
 
 	if v, ok := additional[proxyFieldRole]; ok {
 		orgRoles, isGrafanaAdmin, _ := getRoles(c.cfg, func() (org.RoleType, *bool, error) {
-			return org.RoleType(v), nil, nil
+			role, _ := proxyheaders.MapRole(c.cfg, v)
+			return role, nil, nil
 		})
 		identity.OrgRoles = orgRoles
 		identity.IsGrafanaAdmin = isGrafanaAdmin
 	}
 
 	if v, ok := additional[proxyFieldGroups]; ok {
-		identity.Groups = util.SplitString(v)
+		identity.Groups = proxyheaders.MapGroups(c.cfg, v)
 	}
 
 	identity.ClientParams.LookUpParams.Email = &identity.Email
@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+var _ authn.ContextAwareClient = new(OAuthServer)
+
+func ProvideOAuthServer(oauthServer oauthserver.Service, userService user.Service) *OAuthServer {
+	return &OAuthServer{
+		log:         log.New(authn.ClientOAuthServer),
+		oauthServer: oauthServer,
+		userService: userService,
+	}
+}
+
+// OAuthServer authenticates the external applications with the access tokens issued by the OAuth server of Grafana.
+type OAuthServer struct {
+	log         log.Logger
+	oauthServer oauthserver.Service
+	userService user.Service
+}
+
+func (s *OAuthServer) Name() string {
+	return authn.ClientOAuthServer
+}
+
+func (s *OAuthServer) Authenticate(ctx context.Context, r *authn.Request) (*authn.Identity, error) {
+	token, err := s.oauthServer.ValidateAccessToken(ctx, getTokenFromRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := s.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{
+		UserID: token.ServiceAccountID,
+		OrgID:  token.OrgID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	identity := authn.IdentityFromSignedInUser(authn.NamespacedID(authn.NamespaceServiceAccount, usr.UserID), usr, authn.ClientParams{SyncPermissions: true})
+	identity.PermissionBoundary = token.PermissionBoundary()
+	return identity, nil
+}
+
+func (s *OAuthServer) Test(ctx context.Context, r *authn.Request) bool {
+	return oauthserver.IsAccessToken(getTokenFromRequest(r))
+}
+
+func (s *OAuthServer) Priority() uint {
+	return 25
+}
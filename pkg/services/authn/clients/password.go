@@ -35,12 +35,17 @@ type Password struct {
 func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, username, password string) (*authn.Identity, error) {
 	r.SetMeta(authn.MetaKeyUsername, username)
 
-	ok, err := c.loginAttempts.Validate(ctx, username)
+	var ipAddress string
+	if r.HTTPRequest != nil {
+		ipAddress = web.RemoteAddr(r.HTTPRequest)
+	}
+
+	ok, err := c.loginAttempts.Validate(ctx, username, ipAddress)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
-		return nil, errLoginAttemptBlocked.Errorf("too many consecutive incorrect login attempts for user - login for user temporarily blocked")
+		return nil, errLoginAttemptBlocked.Errorf("too many consecutive incorrect login attempts for user or IP address - login temporarily blocked")
 	}
 
 	if len(password) == 0 {
@@ -61,7 +66,7 @@ func (c *Password) AuthenticatePassword(ctx context.Context, r *authn.Request, u
 	}
 
 	if errors.Is(clientErrs, errInvalidPassword) {
-		_ = c.loginAttempts.Add(ctx, username, web.RemoteAddr(r.HTTPRequest))
+		_ = c.loginAttempts.Add(ctx, username, ipAddress)
 	}
 
 	return nil, errPasswordAuthFailed.Errorf("failed to authenticate identity: %w", clientErrs)
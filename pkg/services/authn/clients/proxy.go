@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/authn/proxyheaders"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -46,7 +47,11 @@ func ProvideProxy(cfg *setting.Cfg, cache proxyCache, userSrv user.Service, clie
 	if err != nil {
 		return nil, err
 	}
-	return &Proxy{log.New(authn.ClientProxy), cfg, cache, userSrv, clients, list}, nil
+	verifier, err := proxyheaders.NewVerifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Proxy{log.New(authn.ClientProxy), cfg, cache, userSrv, clients, list, verifier}, nil
 }
 
 type proxyCache interface {
@@ -61,6 +66,7 @@ type Proxy struct {
 	userSrv     user.Service
 	clients     []authn.ProxyClient
 	acceptedIPs []*net.IPNet
+	verifier    *proxyheaders.Verifier
 }
 
 func (c *Proxy) Name() string {
@@ -72,6 +78,12 @@ func (c *Proxy) Authenticate(ctx context.Context, r *authn.Request) (*authn.Iden
 		return nil, errNotAcceptedIP.Errorf("request ip is not in the configured accept list")
 	}
 
+	if c.verifier != nil {
+		if err := c.verifier.Verify(r.HTTPRequest.Header); err != nil {
+			return nil, err
+		}
+	}
+
 	username := getProxyHeader(r, c.cfg.AuthProxyHeaderName, c.cfg.AuthProxyHeadersEncoded)
 	if len(username) == 0 {
 		return nil, errEmptyProxyHeader.Errorf("no username provided in auth proxy header")
@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/authn"
 	"github.com/grafana/grafana/pkg/services/authn/authntest"
+	"github.com/grafana/grafana/pkg/services/authn/proxyheaders"
 	"github.com/grafana/grafana/pkg/services/user/usertest"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -23,6 +24,7 @@ func TestProxy_Authenticate(t *testing.T) {
 		ips                string
 		proxyHeader        string
 		proxyHeaders       map[string]string
+		signatureHeader    string
 		expectedErr        error
 		expectedUsername   string
 		expectedAdditional map[string]string
@@ -95,6 +97,19 @@ func TestProxy_Authenticate(t *testing.T) {
 			ips:         "127.0.0.1",
 			expectedErr: errNotAcceptedIP,
 		},
+		{
+			desc: "should fail when the signature of the headers is invalid",
+			req: &authn.Request{
+				HTTPRequest: &http.Request{Header: map[string][]string{
+					"X-Username":  {"username"},
+					"X-Role":      {"Admin"},
+					"X-Signature": {"t=0,s=invalid"},
+				}},
+			},
+			proxyHeaders:    map[string]string{proxyFieldRole: "X-Role"},
+			signatureHeader: "X-Signature",
+			expectedErr:     proxyheaders.ErrInvalidSignature,
+		},
 	}
 
 	for _, tt := range tests {
@@ -103,6 +118,9 @@ func TestProxy_Authenticate(t *testing.T) {
 			cfg.AuthProxyHeaderName = "X-Username"
 			cfg.AuthProxyHeaders = tt.proxyHeaders
 			cfg.AuthProxyWhitelist = tt.ips
+			if tt.signatureHeader != "" {
+				cfg.AuthProxySignature = setting.AuthProxySignatureSettings{Header: tt.signatureHeader, Type: "hmac", Keys: []string{"key"}, MaxAge: time.Minute}
+			}
 
 			calledUsername := ""
 			var calledAdditional map[string]string
@@ -0,0 +1,46 @@
+package proxyheaders
+
+import (
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// MapRole returns the Grafana role of the value of the role header. Without a role mapping the value has to be a
+// Grafana role. With a role mapping the value is a list of proxy roles, the highest mapped role is returned and
+// the proxy roles that are not mapped are ignored, so a Grafana role sent as is cannot be used to escalate privileges.
+func MapRole(cfg *setting.Cfg, value string) (org.RoleType, bool) {
+	if len(cfg.AuthProxyRoleMapping) == 0 {
+		role := org.RoleType(value)
+		return role, role.IsValid()
+	}
+
+	var mapped org.RoleType
+	for _, proxyRole := range util.SplitString(value) {
+		role := org.RoleType(cfg.AuthProxyRoleMapping[proxyRole])
+		if !role.IsValid() {
+			continue
+		}
+		if mapped == "" || role.Includes(mapped) {
+			mapped = role
+		}
+	}
+	return mapped, mapped != ""
+}
+
+// MapGroups returns the groups of the value of the groups header. With a groups mapping only the mapped groups are
+// returned.
+func MapGroups(cfg *setting.Cfg, value string) []string {
+	groups := util.SplitString(value)
+	if len(cfg.AuthProxyGroupsMapping) == 0 {
+		return groups
+	}
+
+	mapped := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if g, ok := cfg.AuthProxyGroupsMapping[group]; ok {
+			mapped = append(mapped, g)
+		}
+	}
+	return mapped
+}
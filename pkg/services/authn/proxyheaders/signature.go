@@ -0,0 +1,202 @@
+package proxyheaders
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	SignatureTypeHMAC = "hmac"
+	SignatureTypeJWT  = "jwt"
+)
+
+var ErrInvalidSignature = errutil.NewBase(errutil.StatusUnauthorized, "auth-proxy.invalid-signature", errutil.WithPublicMessage("Invalid auth proxy signature"))
+
+// Verifier verifies the signature of the auth proxy headers. The signature covers the username header and every
+// configured additional header, with their raw values, so none of them can be added, changed or removed.
+//
+// With the hmac type the signature header is "t=<unix time>,s=<hex HMAC-SHA256 of the time and the headers>". With
+// the jwt type it is a JWT signed with HS256 whose "hdr" claim is the base64url SHA-256 of the headers.
+type Verifier struct {
+	header        string
+	typ           string
+	keys          [][]byte
+	maxAge        time.Duration
+	signedHeaders []string
+	now           func() time.Time
+}
+
+// NewVerifier returns the verifier of the auth proxy signature, or nil when the verification is disabled.
+func NewVerifier(cfg *setting.Cfg) (*Verifier, error) {
+	settings := cfg.AuthProxySignature
+	if settings.Header == "" {
+		return nil, nil
+	}
+	if settings.Type != SignatureTypeHMAC && settings.Type != SignatureTypeJWT {
+		return nil, fmt.Errorf("invalid auth proxy signature type, expected hmac or jwt but got: %s", settings.Type)
+	}
+	if len(settings.Keys) == 0 {
+		return nil, fmt.Errorf("auth proxy signature requires at least one key")
+	}
+
+	keys := make([][]byte, 0, len(settings.Keys))
+	for _, key := range settings.Keys {
+		keys = append(keys, []byte(key))
+	}
+
+	return &Verifier{
+		header:        settings.Header,
+		typ:           settings.Type,
+		keys:          keys,
+		maxAge:        settings.MaxAge,
+		signedHeaders: signedHeaders(cfg),
+		now:           time.Now,
+	}, nil
+}
+
+// Verify checks the signature of the headers against every accepted key.
+func (v *Verifier) Verify(h http.Header) error {
+	signature := h.Get(v.header)
+	if signature == "" {
+		return ErrInvalidSignature.Errorf("missing auth proxy signature header %s", v.header)
+	}
+
+	payload := canonicalHeaders(h, v.signedHeaders)
+	if v.typ == SignatureTypeJWT {
+		return v.verifyJWT(signature, payload)
+	}
+	return v.verifyHMAC(signature, payload)
+}
+
+func (v *Verifier) verifyHMAC(signature, payload string) error {
+	var timestamp, sig string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "s":
+			sig = value
+		}
+	}
+
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature.Errorf("invalid auth proxy signature time: %w", err)
+	}
+	if err := v.checkAge(time.Unix(t, 0)); err != nil {
+		return err
+	}
+
+	for _, key := range v.keys {
+		if hmac.Equal([]byte(sig), []byte(hmacSignature(key, timestamp, payload))) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature.Errorf("auth proxy signature does not match any key")
+}
+
+func (v *Verifier) verifyJWT(signature, payload string) error {
+	token, err := jwt.ParseSigned(signature)
+	if err != nil {
+		return ErrInvalidSignature.Errorf("invalid auth proxy signature token: %w", err)
+	}
+	for _, header := range token.Headers {
+		if header.Algorithm != string(jose.HS256) {
+			return ErrInvalidSignature.Errorf("unsupported auth proxy signature algorithm: %s", header.Algorithm)
+		}
+	}
+
+	var claims struct {
+		jwt.Claims
+		Headers string `json:"hdr"`
+	}
+	verified := false
+	for _, key := range v.keys {
+		if err := token.Claims(key, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return ErrInvalidSignature.Errorf("auth proxy signature token does not match any key")
+	}
+
+	if claims.IssuedAt == nil {
+		return ErrInvalidSignature.Errorf("auth proxy signature token has no iat claim")
+	}
+	if err := claims.Claims.ValidateWithLeeway(jwt.Expected{Time: v.now()}, 0); err != nil {
+		return ErrInvalidSignature.Errorf("invalid auth proxy signature token claims: %w", err)
+	}
+	if err := v.checkAge(claims.IssuedAt.Time()); err != nil {
+		return err
+	}
+
+	if !hmac.Equal([]byte(claims.Headers), []byte(headersHash(payload))) {
+		return ErrInvalidSignature.Errorf("auth proxy signature token does not match the headers")
+	}
+	return nil
+}
+
+func (v *Verifier) checkAge(signedAt time.Time) error {
+	now := v.now()
+	if signedAt.Before(now.Add(-v.maxAge)) || signedAt.After(now.Add(v.maxAge)) {
+		return ErrInvalidSignature.Errorf("auth proxy signature is older than %s or in the future", v.maxAge)
+	}
+	return nil
+}
+
+// signedHeaders returns the names of the headers covered by the signature, in canonical form and sorted.
+func signedHeaders(cfg *setting.Cfg) []string {
+	names := map[string]struct{}{http.CanonicalHeaderKey(cfg.AuthProxyHeaderName): {}}
+	for _, name := range cfg.AuthProxyHeaders {
+		names[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// canonicalHeaders is the signed payload, a "name:value" line for every signed header, missing headers have an
+// empty value.
+func canonicalHeaders(h http.Header, names []string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(strings.ToLower(name))
+		b.WriteByte(':')
+		b.WriteString(h.Get(name))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func hmacSignature(key []byte, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func headersHash(payload string) string {
+	sum := sha256.Sum256([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+package proxyheaders
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestVerifier(t *testing.T) {
+	now := time.Unix(1680000000, 0)
+
+	newVerifier := func(t *testing.T, typ string, keys ...string) *Verifier {
+		t.Helper()
+		cfg := setting.NewCfg()
+		cfg.AuthProxyHeaderName = "X-Webauth-User"
+		cfg.AuthProxyHeaders = map[string]string{"Role": "X-Webauth-Role", "Groups": "X-Webauth-Groups"}
+		cfg.AuthProxySignature = setting.AuthProxySignatureSettings{
+			Header: "X-Webauth-Signature",
+			Type:   typ,
+			Keys:   keys,
+			MaxAge: 5 * time.Minute,
+		}
+		v, err := NewVerifier(cfg)
+		require.NoError(t, err)
+		v.now = func() time.Time { return now }
+		return v
+	}
+	headers := func() http.Header {
+		h := http.Header{}
+		h.Set("X-Webauth-User", "alice")
+		h.Set("X-Webauth-Role", "Viewer")
+		return h
+	}
+	signHMAC := func(h http.Header, key string, signedAt time.Time) {
+		timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+		payload := canonicalHeaders(h, []string{"X-Webauth-Groups", "X-Webauth-Role", "X-Webauth-User"})
+		h.Set("X-Webauth-Signature", "t="+timestamp+",s="+hmacSignature([]byte(key), timestamp, payload))
+	}
+	signJWT := func(t *testing.T, h http.Header, key string, signedAt time.Time) {
+		t.Helper()
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(key)}, nil)
+		require.NoError(t, err)
+		payload := canonicalHeaders(h, []string{"X-Webauth-Groups", "X-Webauth-Role", "X-Webauth-User"})
+		token, err := jwt.Signed(signer).Claims(jwt.Claims{
+			IssuedAt: jwt.NewNumericDate(signedAt),
+			Expiry:   jwt.NewNumericDate(signedAt.Add(time.Minute)),
+		}).Claims(map[string]interface{}{"hdr": headersHash(payload)}).CompactSerialize()
+		require.NoError(t, err)
+		h.Set("X-Webauth-Signature", token)
+	}
+
+	t.Run("is disabled without a signature header", func(t *testing.T) {
+		v, err := NewVerifier(setting.NewCfg())
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("accepts the headers signed with any of the keys", func(t *testing.T) {
+		v := newVerifier(t, SignatureTypeHMAC, "new", "old")
+		for _, key := range []string{"new", "old"} {
+			h := headers()
+			signHMAC(h, key, now)
+			assert.NoError(t, v.Verify(h), key)
+		}
+	})
+
+	t.Run("rejects changed, added or unsigned headers", func(t *testing.T) {
+		v := newVerifier(t, SignatureTypeHMAC, "key")
+
+		h := headers()
+		signHMAC(h, "key", now)
+		h.Set("X-Webauth-Role", "Admin")
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature)
+
+		h = headers()
+		signHMAC(h, "key", now)
+		h.Set("X-Webauth-Groups", "admins")
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature)
+
+		assert.ErrorIs(t, v.Verify(headers()), ErrInvalidSignature)
+
+		h = headers()
+		signHMAC(h, "other key", now)
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature)
+	})
+
+	t.Run("rejects an old signature", func(t *testing.T) {
+		v := newVerifier(t, SignatureTypeHMAC, "key")
+		h := headers()
+		signHMAC(h, "key", now.Add(-10*time.Minute))
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature)
+	})
+
+	t.Run("verifies JWT signatures", func(t *testing.T) {
+		v := newVerifier(t, SignatureTypeJWT, "new", "old")
+
+		h := headers()
+		signJWT(t, h, "old", now)
+		assert.NoError(t, v.Verify(h))
+
+		h.Set("X-Webauth-Role", "Admin")
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature)
+
+		h = headers()
+		signJWT(t, h, "other key", now)
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature)
+
+		h = headers()
+		signJWT(t, h, "new", now.Add(-2*time.Minute))
+		assert.ErrorIs(t, v.Verify(h), ErrInvalidSignature, "expired token")
+	})
+
+	t.Run("requires a valid configuration", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.AuthProxySignature = setting.AuthProxySignatureSettings{Header: "X-Webauth-Signature", Type: "rsa", Keys: []string{"key"}}
+		_, err := NewVerifier(cfg)
+		assert.Error(t, err)
+
+		cfg.AuthProxySignature = setting.AuthProxySignatureSettings{Header: "X-Webauth-Signature", Type: SignatureTypeHMAC}
+		_, err = NewVerifier(cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestMapping(t *testing.T) {
+	cfg := setting.NewCfg()
+
+	role, ok := MapRole(cfg, "Editor")
+	assert.True(t, ok)
+	assert.Equal(t, "Editor", string(role))
+
+	cfg.AuthProxyRoleMapping = map[string]string{"ops": "Editor", "admins": "Admin", "readers": "Viewer"}
+	role, ok = MapRole(cfg, "readers, ops")
+	assert.True(t, ok)
+	assert.Equal(t, "Editor", string(role))
+
+	_, ok = MapRole(cfg, "Admin")
+	assert.False(t, ok, "unmapped roles are ignored")
+
+	assert.Equal(t, []string{"a", "b"}, MapGroups(cfg, "a,b"))
+	cfg.AuthProxyGroupsMapping = map[string]string{"a": "team-a"}
+	assert.Equal(t, []string{"team-a"}, MapGroups(cfg, "a,b"))
+}
@@ -0,0 +1,23 @@
+package cleanup
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (srv *CleanUpService) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Get("/api/admin/cleanup/reports", middleware.ReqGrafanaAdmin, routing.Wrap(srv.handleListReports))
+}
+
+// handleListReports lists the reports of the latest runs of the cleanup jobs.
+func (srv *CleanUpService) handleListReports(c *contextmodel.ReqContext) response.Response {
+	reports, err := srv.Reports(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list cleanup reports", err)
+	}
+	return response.JSON(http.StatusOK, reports)
+}
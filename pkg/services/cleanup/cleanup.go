@@ -11,14 +11,20 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/distlock"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
-	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/services/loginattempt/loginattemptimpl"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
@@ -26,13 +32,15 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
+func ProvideService(cfg *setting.Cfg, lockService *distlock.Service,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner, jobQueue jobqueue.Service,
+	loginAttemptService *loginattemptimpl.Service, kvStore kvstore.KVStore, routeRegister routing.RouteRegister,
+	inboxService inbox.Service) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
-		ServerLockService:         serverLockService,
+		lockService:               lockService,
 		ShortURLService:           shortURLService,
 		QueryHistoryService:       queryHistoryService,
 		store:                     sqlstore,
@@ -43,16 +51,26 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		loginAttemptService:       loginAttemptService,
+		inboxService:              inboxService,
+		reports:                   kvstore.WithNamespace(kvStore, 0, reportsNamespace),
 	}
+
+	jobQueue.RegisterHandler(deleteExpiredSnapshotsJob, s.deleteExpiredSnapshots, jobqueue.HandlerOptions{Interval: cfg.Cleanup.Interval})
+	s.registerAPIEndpoints(routeRegister)
 	return s
 }
 
+// deleteExpiredSnapshotsJob is the job of the job queue deleting the expired snapshots,
+// it is retried when the snapshots can't be deleted.
+const deleteExpiredSnapshotsJob = "cleanup.delete-expired-snapshots"
+
 type CleanUpService struct {
 	log                       log.Logger
 	tracer                    tracing.Tracer
 	store                     db.DB
 	Cfg                       *setting.Cfg
-	ServerLockService         *serverlock.ServerLockService
+	lockService               *distlock.Service
 	ShortURLService           shorturls.Service
 	QueryHistoryService       queryhistory.Service
 	dashboardVersionService   dashver.Service
@@ -60,11 +78,16 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	loginAttemptService       *loginattemptimpl.Service
+	inboxService              inbox.Service
+	reports                   *kvstore.NamespacedKVStore
 }
 
 type cleanUpJob struct {
 	name string
-	fn   func(context.Context)
+	// policy is the retention policy applied by the job, nil when the job has its own settings
+	policy *setting.CleanupPolicySettings
+	fn     func(context.Context) (int64, error)
 }
 
 func (j cleanUpJob) String() string {
@@ -72,88 +95,139 @@ func (j cleanUpJob) String() string {
 }
 
 func (srv *CleanUpService) Run(ctx context.Context) error {
-	srv.cleanUpTmpFiles(ctx)
+	srv.run(ctx, false)
 
-	ticker := time.NewTicker(time.Minute * 10)
+	ticker := time.NewTicker(srv.Cfg.Cleanup.Interval)
 	for {
 		select {
 		case <-ticker.C:
-			srv.clean(ctx)
+			srv.run(ctx, true)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
-func (srv *CleanUpService) clean(ctx context.Context) {
-	const timeout = 9 * time.Minute
+// run runs the jobs cleaning up the files of this instance and, when shared is true and no other instance
+// ran them during the interval, the jobs cleaning up the database, and saves the report of the run.
+func (srv *CleanUpService) run(ctx context.Context, shared bool) {
+	report := Report{Instance: setting.InstanceName, Started: time.Now()}
+	report.Jobs = srv.runJobs(ctx, srv.localJobs())
+
+	if shared {
+		interval := srv.Cfg.Cleanup.Interval * 9 / 10
+		err := srv.lockService.RunOnce(ctx, "cleanup", interval, func(ctx context.Context) {
+			ctx, cancelFn := context.WithTimeout(ctx, interval)
+			defer cancelFn()
+			report.Jobs = append(report.Jobs, srv.runJobs(ctx, srv.sharedJobs())...)
+		})
+		if err != nil {
+			srv.log.Error("Failed to run cleanup jobs", "error", err)
+		}
+	}
+
+	report.Finished = time.Now()
+	srv.saveReport(ctx, report)
+}
+
+// localJobs are the jobs cleaning up the files of this instance, they are run by every instance.
+func (srv *CleanUpService) localJobs() []cleanUpJob {
+	policies := srv.Cfg.Cleanup
+	return []cleanUpJob{
+		{"delete stale temporary files", &policies.TempFiles, func(ctx context.Context) (int64, error) {
+			return srv.cleanUpTmpFolder(ctx, srv.Cfg.CSVsDir, policies.TempFiles.Retention)
+		}},
+		{"delete stale rendered images", &policies.RenderCache, func(ctx context.Context) (int64, error) {
+			return srv.cleanUpTmpFolder(ctx, srv.Cfg.ImagesDir, policies.RenderCache.Retention)
+		}},
+	}
+}
+
+// sharedJobs are the jobs cleaning up the database, they are run by one instance at a time.
+func (srv *CleanUpService) sharedJobs() []cleanUpJob {
+	policies := srv.Cfg.Cleanup
+	return []cleanUpJob{
+		{"delete expired dashboard versions", &policies.DashboardVersions, srv.deleteExpiredDashboardVersions},
+		{"archive dashboard versions", nil, srv.archiveDashboardVersions},
+		{"delete expired images", nil, srv.deleteExpiredImages},
+		{"cleanup old annotations", nil, srv.cleanUpOldAnnotations},
+		{"expire old user invites", nil, srv.expireOldUserInvites},
+		{"delete stale short URLs", &policies.ShortURLs, srv.deleteStaleShortURLs},
+		{"delete expired short URLs", nil, srv.deleteExpiredShortURLs},
+		{"delete stale query history", nil, srv.deleteStaleQueryHistory},
+		{"delete old login attempts", &policies.LoginAttempts, srv.deleteOldLoginAttempts},
+		{"delete old notifications", &policies.Notifications, srv.deleteOldNotifications},
+	}
+}
+
+// runJobs runs the jobs whose policy is enabled, and reports their outcome.
+func (srv *CleanUpService) runJobs(ctx context.Context, jobs []cleanUpJob) []JobReport {
 	start := time.Now()
 	ctx, span := srv.tracer.Start(ctx, "cleanup background job")
 	defer span.End()
-	ctx, cancelFn := context.WithTimeout(ctx, timeout)
-	defer cancelFn()
-
-	cleanupJobs := []cleanUpJob{
-		{"clean up temporary files", srv.cleanUpTmpFiles},
-		{"delete expired snapshots", srv.deleteExpiredSnapshots},
-		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
-		{"delete expired images", srv.deleteExpiredImages},
-		{"cleanup old annotations", srv.cleanUpOldAnnotations},
-		{"expire old user invites", srv.expireOldUserInvites},
-		{"delete stale short URLs", srv.deleteStaleShortURLs},
-		{"delete stale query history", srv.deleteStaleQueryHistory},
-	}
 
 	logger := srv.log.FromContext(ctx)
-	logger.Debug("Starting cleanup jobs", "jobs", fmt.Sprintf("%v", cleanupJobs))
+	logger.Debug("Starting cleanup jobs", "jobs", fmt.Sprintf("%v", jobs))
 
-	for _, j := range cleanupJobs {
+	reports := make([]JobReport, 0, len(jobs))
+	for _, j := range jobs {
+		if j.policy != nil && !j.policy.Enabled {
+			continue
+		}
 		if ctx.Err() != nil {
 			logger.Error("Cancelled cleanup job", "error", ctx.Err(), "duration", time.Since(start))
-			return
+			break
 		}
-		ctx, span := srv.tracer.Start(ctx, j.name)
-		j.fn(ctx)
-		span.End()
+		reports = append(reports, srv.runJob(ctx, j))
 	}
 
 	logger.Info("Completed cleanup jobs", "duration", time.Since(start))
+	return reports
 }
 
-func (srv *CleanUpService) cleanUpOldAnnotations(ctx context.Context) {
+func (srv *CleanUpService) runJob(ctx context.Context, j cleanUpJob) JobReport {
+	ctx, span := srv.tracer.Start(ctx, j.name)
+	defer span.End()
+
+	report := JobReport{Name: j.name}
+	if j.policy != nil {
+		report.Retention = j.policy.Retention.String()
+	}
+
+	start := time.Now()
+	deleted, err := j.fn(ctx)
+	report.Deleted = deleted
+	report.DurationMs = time.Since(start).Milliseconds()
+
 	logger := srv.log.FromContext(ctx)
-	affected, affectedTags, err := srv.annotationCleaner.Run(ctx, srv.Cfg)
-	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
-		logger.Error("failed to clean up old annotations", "error", err)
+	if err != nil {
+		report.Error = err.Error()
+		logger.Error("Cleanup job failed", "job", j.name, "error", err)
 	} else {
-		logger.Debug("Deleted excess annotations", "annotations affected", affected, "annotation tags affected", affectedTags)
+		logger.Debug("Completed cleanup job", "job", j.name, "rows affected", deleted)
 	}
+	return report
 }
 
-func (srv *CleanUpService) cleanUpTmpFiles(ctx context.Context) {
-	folders := []string{
-		srv.Cfg.ImagesDir,
-		srv.Cfg.CSVsDir,
-	}
-
-	for _, f := range folders {
-		ctx, span := srv.tracer.Start(ctx, "delete stale files in temporary directory")
-		span.SetAttributes("directory", f, attribute.Key("directory").String(f))
-		srv.cleanUpTmpFolder(ctx, f)
-		span.End()
+func (srv *CleanUpService) cleanUpOldAnnotations(ctx context.Context) (int64, error) {
+	affected, affectedTags, err := srv.annotationCleaner.Run(ctx, srv.Cfg)
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return affected, fmt.Errorf("failed to clean up old annotations: %w", err)
 	}
+	srv.log.FromContext(ctx).Debug("Deleted excess annotations", "annotations affected", affected, "annotation tags affected", affectedTags)
+	return affected, nil
 }
 
-func (srv *CleanUpService) cleanUpTmpFolder(ctx context.Context, folder string) {
+func (srv *CleanUpService) cleanUpTmpFolder(ctx context.Context, folder string, retention time.Duration) (int64, error) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("directory", folder))
 	logger := srv.log.FromContext(ctx)
 	if _, err := os.Stat(folder); os.IsNotExist(err) {
-		return
+		return 0, nil
 	}
 
 	files, err := os.ReadDir(folder)
 	if err != nil {
-		logger.Error("Problem reading dir", "folder", folder, "error", err)
-		return
+		return 0, fmt.Errorf("failed to read directory %s: %w", folder, err)
 	}
 
 	var toDelete []fs.DirEntry
@@ -166,64 +240,92 @@ func (srv *CleanUpService) cleanUpTmpFolder(ctx context.Context, folder string)
 			continue
 		}
 
-		if srv.shouldCleanupTempFile(info.ModTime(), now) {
+		if shouldCleanupTempFile(info.ModTime(), now, retention) {
 			toDelete = append(toDelete, file)
 		}
 	}
 
+	var deleted int64
 	for _, file := range toDelete {
 		fullPath := path.Join(folder, file.Name())
 		err := os.Remove(fullPath)
 		if err != nil {
 			logger.Error("Failed to delete temp file", "file", file.Name(), "error", err)
+			continue
 		}
+		deleted++
 	}
 
-	logger.Debug("Found old rendered file to delete", "folder", folder, "deleted", len(toDelete), "kept", len(files))
+	logger.Debug("Found old rendered file to delete", "folder", folder, "deleted", deleted, "kept", len(files)-int(deleted))
+	return deleted, nil
 }
 
-func (srv *CleanUpService) shouldCleanupTempFile(filemtime time.Time, now time.Time) bool {
-	if srv.Cfg.TempDataLifetime == 0 {
+// shouldCleanupTempFile returns whether a file modified at filemtime is older than the retention, the files
+// are never cleaned up when the retention is 0.
+func shouldCleanupTempFile(filemtime time.Time, now time.Time, retention time.Duration) bool {
+	if retention == 0 {
 		return false
 	}
 
-	return filemtime.Add(srv.Cfg.TempDataLifetime).Before(now)
+	return filemtime.Add(retention).Before(now)
 }
 
-func (srv *CleanUpService) deleteExpiredSnapshots(ctx context.Context) {
-	logger := srv.log.FromContext(ctx)
-	cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
-	if err := srv.dashboardSnapshotService.DeleteExpiredSnapshots(ctx, &cmd); err != nil {
-		logger.Error("Failed to delete expired snapshots", "error", err.Error())
-	} else {
-		logger.Debug("Deleted expired snapshots", "rows affected", cmd.DeletedRows)
+func (srv *CleanUpService) deleteExpiredSnapshots(ctx context.Context, _ *jobqueue.Job) error {
+	policy := srv.Cfg.Cleanup.ExpiredSnapshots
+	job := cleanUpJob{"delete expired snapshots", &policy, func(ctx context.Context) (int64, error) {
+		cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{
+			ExpiredBefore: time.Now().Add(-policy.Retention),
+		}
+		if err := srv.dashboardSnapshotService.DeleteExpiredSnapshots(ctx, &cmd); err != nil {
+			return 0, fmt.Errorf("failed to delete expired snapshots: %w", err)
+		}
+		return cmd.DeletedRows, nil
+	}}
+
+	report := Report{Instance: setting.InstanceName, Started: time.Now()}
+	report.Jobs = srv.runJobs(ctx, []cleanUpJob{job})
+	report.Finished = time.Now()
+	srv.saveReport(ctx, report)
+
+	// the job queue retries the job when it fails
+	if len(report.Jobs) > 0 && report.Jobs[0].Error != "" {
+		return errors.New(report.Jobs[0].Error)
 	}
+	return nil
 }
 
-func (srv *CleanUpService) deleteExpiredDashboardVersions(ctx context.Context) {
-	logger := srv.log.FromContext(ctx)
-	cmd := dashver.DeleteExpiredVersionsCommand{}
+func (srv *CleanUpService) deleteExpiredDashboardVersions(ctx context.Context) (int64, error) {
+	policy := srv.Cfg.Cleanup.DashboardVersions
+	cmd := dashver.DeleteExpiredVersionsCommand{VersionsToKeep: policy.MaxCount}
+	if policy.Retention > 0 {
+		cmd.OlderThan = time.Now().Add(-policy.Retention)
+	}
 	if err := srv.dashboardVersionService.DeleteExpired(ctx, &cmd); err != nil {
-		logger.Error("Failed to delete expired dashboard versions", "error", err.Error())
-	} else {
-		logger.Debug("Deleted old/expired dashboard versions", "rows affected", cmd.DeletedRows)
+		return cmd.DeletedRows, fmt.Errorf("failed to delete expired dashboard versions: %w", err)
 	}
+	return cmd.DeletedRows, nil
 }
 
-func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
-	logger := srv.log.FromContext(ctx)
+func (srv *CleanUpService) archiveDashboardVersions(ctx context.Context) (int64, error) {
+	cmd := dashver.ArchiveExpiredVersionsCommand{}
+	if err := srv.dashboardVersionService.ArchiveExpired(ctx, &cmd); err != nil {
+		return cmd.ArchivedContents, fmt.Errorf("failed to archive dashboard versions: %w", err)
+	}
+	return cmd.ArchivedContents, nil
+}
+
+func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) (int64, error) {
 	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
-		return
+		return 0, nil
 	}
-	if rowsAffected, err := srv.deleteExpiredImageService.DeleteExpired(ctx); err != nil {
-		logger.Error("Failed to delete expired images", "error", err.Error())
-	} else {
-		logger.Debug("Deleted expired images", "rows affected", rowsAffected)
+	rowsAffected, err := srv.deleteExpiredImageService.DeleteExpired(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired images: %w", err)
 	}
+	return rowsAffected, nil
 }
 
-func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
-	logger := srv.log.FromContext(ctx)
+func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) (int64, error) {
 	maxInviteLifetime := srv.Cfg.UserInviteMaxLifetime
 
 	cmd := tempuser.ExpireTempUsersCommand{
@@ -231,51 +333,79 @@ func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
 	}
 
 	if err := srv.tempUserService.ExpireOldUserInvites(ctx, &cmd); err != nil {
-		logger.Error("Problem expiring user invites", "error", err.Error())
-	} else {
-		logger.Debug("Expired user invites", "rows affected", cmd.NumExpired)
+		return 0, fmt.Errorf("failed to expire user invites: %w", err)
 	}
+	return cmd.NumExpired, nil
 }
 
-func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) {
-	logger := srv.log.FromContext(ctx)
+// deleteStaleShortURLs deletes the short URLs which were never visited in the retention of their policy.
+func (srv *CleanUpService) deleteStaleShortURLs(ctx context.Context) (int64, error) {
+	retention := srv.Cfg.Cleanup.ShortURLs.Retention
+	if retention == 0 {
+		return 0, nil
+	}
 	cmd := shorturls.DeleteShortUrlCommand{
-		OlderThan: time.Now().Add(-time.Hour * 24 * 7),
+		OlderThan: time.Now().Add(-retention),
 	}
 	if err := srv.ShortURLService.DeleteStaleShortURLs(ctx, &cmd); err != nil {
-		logger.Error("Problem deleting stale short urls", "error", err.Error())
-	} else {
-		logger.Debug("Deleted short urls", "rows affected", cmd.NumDeleted)
+		return 0, fmt.Errorf("failed to delete stale short urls: %w", err)
 	}
+	return cmd.NumDeleted, nil
 }
 
-func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
-	logger := srv.log.FromContext(ctx)
-	// Delete query history from 14+ days ago with exception of starred queries
-	maxQueryHistoryLifetime := time.Hour * 24 * 14
-	olderThan := time.Now().Add(-maxQueryHistoryLifetime).Unix()
-	rowsCount, err := srv.QueryHistoryService.DeleteStaleQueriesInQueryHistory(ctx, olderThan)
+func (srv *CleanUpService) deleteExpiredShortURLs(ctx context.Context) (int64, error) {
+	cmd := shorturls.DeleteExpiredShortURLsCommand{}
+	if err := srv.ShortURLService.DeleteExpiredShortURLs(ctx, &cmd); err != nil {
+		return 0, fmt.Errorf("failed to delete expired short urls: %w", err)
+	}
+	return cmd.NumDeleted, nil
+}
+
+func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) (int64, error) {
+	// Delete query history older than the retention of each organization with exception of starred queries
+	rowsCount, err := srv.QueryHistoryService.DeleteExpiredQueriesInQueryHistory(ctx)
 	if err != nil {
-		logger.Error("Problem deleting stale query history", "error", err.Error())
-	} else {
-		logger.Debug("Deleted stale query history", "rows affected", rowsCount)
+		return 0, fmt.Errorf("failed to delete stale query history: %w", err)
 	}
+	deleted := int64(rowsCount)
 
 	// Enforce 200k limit for query_history table
 	queryHistoryLimit := 200000
 	rowsCount, err = srv.QueryHistoryService.EnforceRowLimitInQueryHistory(ctx, queryHistoryLimit, false)
 	if err != nil {
-		logger.Error("Problem with enforcing row limit for query_history", "error", err.Error())
-	} else {
-		logger.Debug("Enforced row limit for query_history", "rows affected", rowsCount)
+		return deleted, fmt.Errorf("failed to enforce row limit for query_history: %w", err)
 	}
+	deleted += int64(rowsCount)
 
 	// Enforce 150k limit for query_history_star table
 	queryHistoryStarLimit := 150000
 	rowsCount, err = srv.QueryHistoryService.EnforceRowLimitInQueryHistory(ctx, queryHistoryStarLimit, true)
 	if err != nil {
-		logger.Error("Problem with enforcing row limit for query_history_star", "error", err.Error())
-	} else {
-		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
+		return deleted, fmt.Errorf("failed to enforce row limit for query_history_star: %w", err)
+	}
+	return deleted + int64(rowsCount), nil
+}
+
+// deleteOldLoginAttempts deletes the login attempts older than the retention of their policy, which is at
+// least the maximum lockout duration of the brute force login protection.
+func (srv *CleanUpService) deleteOldLoginAttempts(ctx context.Context) (int64, error) {
+	deleted, err := srv.loginAttemptService.DeleteOldLoginAttempts(ctx, time.Now().Add(-srv.Cfg.Cleanup.LoginAttempts.Retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old login attempts: %w", err)
+	}
+	return deleted, nil
+}
+
+// deleteOldNotifications deletes the notifications of the notification center older than the retention of their
+// policy, read or not.
+func (srv *CleanUpService) deleteOldNotifications(ctx context.Context) (int64, error) {
+	retention := srv.Cfg.Cleanup.Notifications.Retention
+	if retention == 0 {
+		return 0, nil
+	}
+	deleted, err := srv.inboxService.DeleteCreatedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old notifications: %w", err)
 	}
+	return deleted, nil
 }
@@ -1,38 +1,118 @@
 package cleanup
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestCleanUpTmpFiles(t *testing.T) {
-	cfg := setting.Cfg{}
-	cfg.TempDataLifetime, _ = time.ParseDuration("24h")
-	service := CleanUpService{
-		Cfg: &cfg,
-	}
+	retention, _ := time.ParseDuration("24h")
 	now := time.Now()
 	secondAgo := now.Add(-time.Second)
 	twoDaysAgo := now.Add(-time.Second * 3600 * 24 * 2)
 	weekAgo := now.Add(-time.Second * 3600 * 24 * 7)
 	t.Run("Should not cleanup recent files", func(t *testing.T) {
-		require.False(t, service.shouldCleanupTempFile(secondAgo, now))
+		require.False(t, shouldCleanupTempFile(secondAgo, now, retention))
 	})
 	t.Run("Should cleanup older files", func(t *testing.T) {
-		require.True(t, service.shouldCleanupTempFile(twoDaysAgo, now))
+		require.True(t, shouldCleanupTempFile(twoDaysAgo, now, retention))
 	})
 
 	t.Run("After increasing temporary files lifetime, older files should be kept", func(t *testing.T) {
-		cfg.TempDataLifetime, _ = time.ParseDuration("1000h")
-		require.False(t, service.shouldCleanupTempFile(weekAgo, now))
+		retention, _ = time.ParseDuration("1000h")
+		require.False(t, shouldCleanupTempFile(weekAgo, now, retention))
 	})
 
 	t.Run("If lifetime is 0, files should never be cleaned up", func(t *testing.T) {
-		cfg.TempDataLifetime = 0
-		require.False(t, service.shouldCleanupTempFile(weekAgo, now))
+		require.False(t, shouldCleanupTempFile(weekAgo, now, 0))
+	})
+}
+
+func TestCleanUpTmpFolder(t *testing.T) {
+	service := newTestService(t)
+	dir := t.TempDir()
+
+	recent := filepath.Join(dir, "recent.png")
+	old := filepath.Join(dir, "old.png")
+	require.NoError(t, os.WriteFile(recent, []byte{}, 0600))
+	require.NoError(t, os.WriteFile(old, []byte{}, 0600))
+	require.NoError(t, os.Chtimes(old, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+
+	deleted, err := service.cleanUpTmpFolder(context.Background(), dir, 24*time.Hour)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.FileExists(t, recent)
+	assert.NoFileExists(t, old)
+
+	deleted, err = service.cleanUpTmpFolder(context.Background(), filepath.Join(dir, "missing"), 24*time.Hour)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, deleted)
+}
+
+func TestRunJobs(t *testing.T) {
+	service := newTestService(t)
+	enabled := setting.CleanupPolicySettings{Enabled: true, Retention: time.Hour}
+	disabled := setting.CleanupPolicySettings{Enabled: false, Retention: time.Hour}
+
+	reports := service.runJobs(context.Background(), []cleanUpJob{
+		{"enabled policy", &enabled, func(context.Context) (int64, error) { return 3, nil }},
+		{"disabled policy", &disabled, func(context.Context) (int64, error) { return 5, nil }},
+		{"own settings", nil, func(context.Context) (int64, error) { return 1, errors.New("boom") }},
 	})
+
+	require.Len(t, reports, 2)
+	assert.Equal(t, "enabled policy", reports[0].Name)
+	assert.Equal(t, "1h0m0s", reports[0].Retention)
+	assert.EqualValues(t, 3, reports[0].Deleted)
+	assert.Empty(t, reports[0].Error)
+	assert.Equal(t, "own settings", reports[1].Name)
+	assert.Empty(t, reports[1].Retention)
+	assert.Equal(t, "boom", reports[1].Error)
+}
+
+func TestReports(t *testing.T) {
+	service := newTestService(t)
+	service.Cfg.Cleanup.ReportsToKeep = 2
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		service.saveReport(ctx, Report{
+			Instance: "instance",
+			Started:  start.Add(time.Duration(i) * time.Minute),
+			Jobs:     []JobReport{{Name: "job", Deleted: int64(i)}},
+		})
+	}
+	// the runs without jobs aren't reported
+	service.saveReport(ctx, Report{Instance: "instance", Started: start.Add(time.Hour)})
+
+	reports, err := service.Reports(ctx)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.EqualValues(t, 2, reports[0].Jobs[0].Deleted)
+	assert.EqualValues(t, 1, reports[1].Jobs[0].Deleted)
+}
+
+func newTestService(t *testing.T) *CleanUpService {
+	t.Helper()
+	cfg := setting.NewCfg()
+	cfg.Cleanup.ReportsToKeep = 20
+	return &CleanUpService{
+		Cfg:     cfg,
+		log:     log.New("cleanup"),
+		tracer:  tracing.InitializeTracerForTest(),
+		reports: kvstore.WithNamespace(kvstore.NewFakeKVStore(), 0, reportsNamespace),
+	}
 }
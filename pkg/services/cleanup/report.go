@@ -0,0 +1,94 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// reportsNamespace is the kvstore namespace of the run reports, they are shared by the instances.
+const reportsNamespace = "cleanup.reports"
+
+// Report is the report of a run of the cleanup jobs by an instance.
+type Report struct {
+	Instance string      `json:"instance"`
+	Started  time.Time   `json:"started"`
+	Finished time.Time   `json:"finished"`
+	Jobs     []JobReport `json:"jobs"`
+}
+
+// JobReport is the outcome of a cleanup job.
+type JobReport struct {
+	Name string `json:"name"`
+	// Retention is the retention of the policy applied by the job, empty when the job has its own settings
+	Retention string `json:"retention,omitempty"`
+	// Deleted is the number of items deleted, or archived by the archival jobs
+	Deleted    int64  `json:"deleted"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// reportKey sorts the reports by start time, the instance name prevents conflicts between the instances.
+func reportKey(r Report) string {
+	return fmt.Sprintf("%020d/%s", r.Started.UnixNano(), r.Instance)
+}
+
+// saveReport saves the report of a run which ran jobs, and deletes the reports past the number of reports to keep.
+func (srv *CleanUpService) saveReport(ctx context.Context, report Report) {
+	toKeep := srv.Cfg.Cleanup.ReportsToKeep
+	if len(report.Jobs) == 0 || toKeep == 0 {
+		return
+	}
+
+	logger := srv.log.FromContext(ctx)
+	value, err := json.Marshal(report)
+	if err != nil {
+		logger.Error("Failed to marshal cleanup report", "error", err)
+		return
+	}
+	if err := srv.reports.Set(ctx, reportKey(report), string(value)); err != nil {
+		logger.Error("Failed to save cleanup report", "error", err)
+		return
+	}
+
+	keys, err := srv.reports.Keys(ctx, "")
+	if err != nil {
+		logger.Error("Failed to list cleanup reports", "error", err)
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key > keys[j].Key })
+	for i := toKeep; i < len(keys); i++ {
+		if err := srv.reports.Del(ctx, keys[i].Key); err != nil {
+			logger.Error("Failed to delete cleanup report", "key", keys[i].Key, "error", err)
+		}
+	}
+}
+
+// Reports returns the saved reports of the runs of the cleanup jobs, the latest first.
+func (srv *CleanUpService) Reports(ctx context.Context) ([]Report, error) {
+	keys, err := srv.reports.Keys(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key > keys[j].Key })
+
+	reports := make([]Report, 0, len(keys))
+	for _, key := range keys {
+		value, ok, err := srv.reports.Get(ctx, key.Key)
+		if err != nil {
+			return nil, err
+		}
+		// the report was deleted since the keys were listed
+		if !ok {
+			continue
+		}
+		var report Report
+		if err := json.Unmarshal([]byte(value), &report); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal cleanup report %s: %w", key.Key, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -152,6 +152,7 @@ func (h *ContextHandler) initContextWithJWT(ctx *contextmodel.ReqContext, orgId
 
 	ctx.SignedInUser = queryResult
 	ctx.IsSignedIn = true
+	ctx.AuthModule = loginsvc.JWTModule
 
 	return true
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/login/loginservice"
 	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -119,7 +120,7 @@ func getContextHandler(t *testing.T) *ContextHandler {
 	return ProvideService(cfg, userAuthTokenSvc, authJWTSvc, remoteCacheSvc,
 		renderSvc, sqlStore, tracer, authProxy, loginService, nil, authenticator,
 		&userService, orgService, nil, featuremgmt.WithFeatures(),
-		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{})
+		&authntest.FakeService{}, &anontest.FakeAnonymousSessionService{}, orgsettingstest.NewFakeService(cfg), nil)
 }
 
 type fakeAuthenticator struct{}
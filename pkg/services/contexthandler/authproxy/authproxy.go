@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/authn/proxyheaders"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/ldap"
 	"github.com/grafana/grafana/pkg/services/ldap/service"
@@ -136,6 +137,21 @@ func HashCacheKey(key string) (string, error) {
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
+// VerifySignature checks the signature of the auth proxy headers, when it is enabled.
+func (auth *AuthProxy) VerifySignature(reqCtx *contextmodel.ReqContext) error {
+	verifier, err := proxyheaders.NewVerifier(auth.cfg)
+	if err != nil {
+		return newError("could not verify the auth proxy signature", err)
+	}
+	if verifier == nil {
+		return nil
+	}
+	if err := verifier.Verify(reqCtx.Req.Header); err != nil {
+		return newError("proxy authentication required", err)
+	}
+	return nil
+}
+
 // getKey forms a key for the cache based on the headers received as part of the authentication flow.
 // Our configuration supports multiple headers. The main header contains the email or username.
 // And the additional ones that allow us to specify extra attributes: Name, Email, Role, or Groups.
@@ -274,12 +290,12 @@ func (auth *AuthProxy) loginViaHeader(reqCtx *contextmodel.ReqContext) (int64, e
 	auth.headersIterator(reqCtx, func(field string, header string) {
 		switch field {
 		case "Groups":
-			extUser.Groups = util.SplitString(header)
+			extUser.Groups = proxyheaders.MapGroups(auth.cfg, header)
 		case "Role":
 			// If Role header is specified, we update the user role of the default org
 			if header != "" {
-				rt := org.RoleType(header)
-				if rt.IsValid() {
+				rt, ok := proxyheaders.MapRole(auth.cfg, header)
+				if ok {
 					extUser.OrgRoles = map[int64]org.RoleType{}
 					orgID := int64(1)
 					if auth.cfg.AutoAssignOrg && auth.cfg.AutoAssignOrgId > 0 {
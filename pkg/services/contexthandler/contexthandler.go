@@ -32,8 +32,10 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -54,7 +56,8 @@ func ProvideService(cfg *setting.Cfg, tokenService auth.UserTokenService, jwtSer
 	tracer tracing.Tracer, authProxy *authproxy.AuthProxy, loginService login.Service,
 	apiKeyService apikey.Service, authenticator loginpkg.Authenticator, userService user.Service,
 	orgService org.Service, oauthTokenService oauthtoken.OAuthTokenService, features *featuremgmt.FeatureManager,
-	authnService authn.Service, anonSessionService anonymous.Service,
+	authnService authn.Service, anonSessionService anonymous.Service, orgSettings orgsettings.Service,
+	oauthServer oauthserver.Service,
 ) *ContextHandler {
 	return &ContextHandler{
 		Cfg:                cfg,
@@ -74,6 +77,8 @@ func ProvideService(cfg *setting.Cfg, tokenService auth.UserTokenService, jwtSer
 		features:           features,
 		authnService:       authnService,
 		anonSessionService: anonSessionService,
+		orgSettings:        orgSettings,
+		oauthServer:        oauthServer,
 		singleflight:       new(singleflight.Group),
 	}
 }
@@ -98,6 +103,8 @@ type ContextHandler struct {
 	authnService       authn.Service
 	singleflight       *singleflight.Group
 	anonSessionService anonymous.Service
+	orgSettings        orgsettings.Service
+	oauthServer        oauthserver.Service
 	// GetTime returns the current time.
 	// Stubbable by tests.
 	GetTime func() time.Time
@@ -157,6 +164,10 @@ func (h *ContextHandler) Middleware(next http.Handler) http.Handler {
 				reqContext.IsSignedIn = !identity.IsAnonymous
 				reqContext.AllowAnonymous = identity.IsAnonymous
 				reqContext.IsRenderCall = identity.AuthModule == login.RenderModule
+				reqContext.AuthModule = identity.AuthModule
+				if identity.SessionToken != nil {
+					reqContext.AuthModule = identity.SessionToken.AuthModule
+				}
 			}
 		} else {
 			const headerName = "X-Grafana-Org-Id"
@@ -199,6 +210,8 @@ func (h *ContextHandler) Middleware(next http.Handler) http.Handler {
 			}
 		}
 
+		h.enforceAuthMethod(reqContext)
+
 		reqContext.Logger = reqContext.Logger.New("userId", reqContext.UserID, "orgId", reqContext.OrgID, "uname", reqContext.Login)
 		span.AddEvents(
 			[]string{"uname", "orgId", "userId"},
@@ -208,6 +221,13 @@ func (h *ContextHandler) Middleware(next http.Handler) http.Handler {
 				{Num: reqContext.UserID}},
 		)
 
+		// store the identity used to evaluate feature toggle rollouts
+		*reqContext.Req = *reqContext.Req.WithContext(featuremgmt.WithEvaluationContext(reqContext.Req.Context(), featuremgmt.EvaluationContext{
+			OrgID:   reqContext.OrgID,
+			UserID:  reqContext.UserID,
+			TeamIDs: reqContext.Teams,
+		}))
+
 		// when using authn service this is implemented as a post auth hook
 		if !h.features.IsEnabled(featuremgmt.FlagAuthnService) {
 			// update last seen every 5min
@@ -223,6 +243,30 @@ func (h *ContextHandler) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// enforceAuthMethod signs out the users who signed in with an authentication method their organization doesn't allow.
+// The API keys, service accounts, render calls and Grafana admins are not checked, so that the admins can't lock
+// themselves out.
+func (h *ContextHandler) enforceAuthMethod(reqContext *contextmodel.ReqContext) {
+	if !reqContext.IsSignedIn || reqContext.IsRenderCall || reqContext.IsApiKeyUser() ||
+		reqContext.IsServiceAccount || reqContext.IsGrafanaAdmin {
+		return
+	}
+
+	ctx := reqContext.Req.Context()
+	allowed := util.SplitString(h.orgSettings.GetString(ctx, reqContext.OrgID, orgsettings.AllowedAuthMethods))
+	err := login.CheckAuthMethod(allowed, reqContext.AuthModule)
+	if err == nil {
+		return
+	}
+
+	reqContext.Logger.Debug("Authentication method not allowed in organization", "authModule", reqContext.AuthModule, "orgId", reqContext.OrgID)
+	reqContext.LookupTokenErr = err
+	reqContext.SignedInUser = &user.SignedInUser{Permissions: map[int64]map[string][]string{}}
+	reqContext.IsSignedIn = false
+	reqContext.UserToken = nil
+	reqContext.AuthModule = ""
+}
+
 func (h *ContextHandler) initContextWithAnonymousUser(reqContext *contextmodel.ReqContext) bool {
 	_, span := h.tracer.Start(reqContext.Req.Context(), "initContextWithAnonymousUser")
 	defer span.End()
@@ -315,6 +359,10 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *contextmodel.ReqConte
 		return false
 	}
 
+	if oauthserver.IsAccessToken(keyString) {
+		return h.initContextWithOAuthAccessToken(reqContext, keyString)
+	}
+
 	_, span := h.tracer.Start(reqContext.Req.Context(), "initContextWithAPIKey")
 	defer span.End()
 
@@ -358,17 +406,8 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *contextmodel.ReqConte
 		return true
 	}
 
-	// non-blocking update api_key last used date
-	go func(id int64) {
-		defer func() {
-			if err := recover(); err != nil {
-				reqContext.Logger.Error("api key authentication panic", "err", err)
-			}
-		}()
-		if err := h.apiKeyService.UpdateAPIKeyLastUsedDate(context.Background(), id); err != nil {
-			reqContext.Logger.Warn("failed to update last use date for api key", "id", id)
-		}
-	}(apiKey.ID)
+	// non-blocking update of the api_key usage, it is buffered and written periodically
+	h.apiKeyService.RecordAPIKeyUsage(reqContext.Req.Context(), apiKey.ID, reqContext.Req)
 
 	if apiKey.ServiceAccountId == nil || *apiKey.ServiceAccountId < 1 { //There is no service account attached to the apikey
 		// Use the old APIkey method.  This provides backwards compatibility.
@@ -403,12 +442,46 @@ func (h *ContextHandler) initContextWithAPIKey(reqContext *contextmodel.ReqConte
 		return true
 	}
 
+	// the user is a copy of the cached one, the boundary of the token only applies to this request
+	querySignedInUserResult.PermissionBoundary = apiKey.Permissions.ByAction()
 	reqContext.IsSignedIn = true
 	reqContext.SignedInUser = querySignedInUserResult
 
 	return true
 }
 
+// initContextWithOAuthAccessToken signs in the service account of the client the access token was issued to.
+func (h *ContextHandler) initContextWithOAuthAccessToken(reqContext *contextmodel.ReqContext, accessToken string) bool {
+	ctx, span := h.tracer.Start(reqContext.Req.Context(), "initContextWithOAuthAccessToken")
+	defer span.End()
+
+	token, err := h.oauthServer.ValidateAccessToken(ctx, accessToken)
+	if err != nil {
+		if errors.Is(err, oauthserver.ErrInvalidToken) {
+			reqContext.JsonApiErr(http.StatusUnauthorized, "Invalid access token", err)
+			return true
+		}
+		reqContext.JsonApiErr(http.StatusInternalServerError, "Failed to validate access token", err)
+		return true
+	}
+
+	query := user.GetSignedInUserQuery{UserID: token.ServiceAccountID, OrgID: token.OrgID}
+	signedInUser, err := h.userService.GetSignedInUserWithCacheCtx(ctx, &query)
+	if err != nil {
+		reqContext.JsonApiErr(http.StatusInternalServerError, "Unable to link access token to service account", err)
+		return true
+	}
+	if signedInUser.IsDisabled {
+		reqContext.JsonApiErr(http.StatusUnauthorized, "Service account is disabled", nil)
+		return true
+	}
+
+	signedInUser.PermissionBoundary = token.PermissionBoundary()
+	reqContext.IsSignedIn = true
+	reqContext.SignedInUser = signedInUser
+	return true
+}
+
 func (h *ContextHandler) initContextWithBasicAuth(reqContext *contextmodel.ReqContext, orgID int64) bool {
 	if !h.Cfg.BasicAuthEnabled {
 		return false
@@ -463,6 +536,7 @@ func (h *ContextHandler) initContextWithBasicAuth(reqContext *contextmodel.ReqCo
 
 	reqContext.SignedInUser = queryResult
 	reqContext.IsSignedIn = true
+	reqContext.AuthModule = authQuery.AuthModule
 	return true
 }
 
@@ -530,6 +604,7 @@ func (h *ContextHandler) initContextWithToken(reqContext *contextmodel.ReqContex
 	reqContext.SignedInUser = queryResult
 	reqContext.IsSignedIn = true
 	reqContext.UserToken = token
+	reqContext.AuthModule = token.AuthModule
 
 	// Rotate the token just before we write response headers to ensure there is no delay between
 	// the new token being generated and the client receiving it.
@@ -683,6 +758,13 @@ func (h *ContextHandler) initContextWithAuthProxy(reqContext *contextmodel.ReqCo
 		return true
 	}
 
+	if err := h.authProxy.VerifySignature(reqContext); err != nil {
+		h.handleError(reqContext, err, 407, func(details error) {
+			logger.Error("Failed to verify auth proxy signature", "message", err.Error(), "error", details)
+		})
+		return true
+	}
+
 	id, err := logUserIn(reqContext, h.authProxy, username, logger, false)
 	if err != nil {
 		h.handleError(reqContext, err, 407, nil)
@@ -722,6 +804,7 @@ func (h *ContextHandler) initContextWithAuthProxy(reqContext *contextmodel.ReqCo
 	// Add user info to context
 	reqContext.SignedInUser = user
 	reqContext.IsSignedIn = true
+	reqContext.AuthModule = login.AuthProxyAuthModule
 
 	// Remember user data in cache
 	if err := h.authProxy.Remember(reqContext, id); err != nil {
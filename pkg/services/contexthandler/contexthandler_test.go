@@ -15,7 +15,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/auth/authtest"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -76,6 +82,48 @@ func TestTokenRotationAtEndOfRequest(t *testing.T) {
 	assert.True(t, foundLoginCookie, "Could not find cookie")
 }
 
+func TestEnforceAuthMethod(t *testing.T) {
+	orgSettings := orgsettingstest.NewFakeService(setting.NewCfg())
+	orgSettings.Overrides[orgsettings.AllowedAuthMethods] = "saml, oauth"
+	ctxHdlr := &ContextHandler{orgSettings: orgSettings}
+
+	signedIn := func(t *testing.T, authModule string, usr *user.SignedInUser) *contextmodel.ReqContext {
+		reqContext, _, err := initTokenRotationScenario(context.Background(), t, &ContextHandler{Cfg: setting.NewCfg()})
+		require.NoError(t, err)
+		reqContext.SignedInUser = usr
+		reqContext.IsSignedIn = true
+		reqContext.AuthModule = authModule
+		return reqContext
+	}
+
+	t.Run("should keep the users signed in with an allowed method", func(t *testing.T) {
+		for _, authModule := range []string{login.SAMLAuthModule, login.GithubAuthModule} {
+			reqContext := signedIn(t, authModule, &user.SignedInUser{UserID: 1, OrgID: 2})
+			ctxHdlr.enforceAuthMethod(reqContext)
+			require.True(t, reqContext.IsSignedIn)
+			require.NoError(t, reqContext.LookupTokenErr)
+		}
+	})
+
+	t.Run("should sign out the users signed in with another method", func(t *testing.T) {
+		reqContext := signedIn(t, "", &user.SignedInUser{UserID: 1, OrgID: 2})
+		ctxHdlr.enforceAuthMethod(reqContext)
+		require.False(t, reqContext.IsSignedIn)
+		require.Zero(t, reqContext.UserID)
+		require.ErrorIs(t, reqContext.LookupTokenErr, login.ErrAuthMethodNotAllowed)
+
+		var gfErr errutil.Error
+		require.ErrorAs(t, reqContext.LookupTokenErr, &gfErr)
+		require.Equal(t, "Signing in with basic is not allowed in this organization, sign in with: saml, oauth", gfErr.PublicMessage)
+	})
+
+	t.Run("should not check the Grafana admins", func(t *testing.T) {
+		reqContext := signedIn(t, login.LDAPAuthModule, &user.SignedInUser{UserID: 1, OrgID: 2, IsGrafanaAdmin: true})
+		ctxHdlr.enforceAuthMethod(reqContext)
+		require.True(t, reqContext.IsSignedIn)
+	})
+}
+
 func initTokenRotationScenario(ctx context.Context, t *testing.T, ctxHdlr *ContextHandler) (
 	*contextmodel.ReqContext, *httptest.ResponseRecorder, error) {
 	t.Helper()
@@ -21,6 +21,8 @@ type ReqContext struct {
 	*web.Context
 	*user.SignedInUser
 	UserToken *usertoken.UserToken
+	// AuthModule is the module the user signed in with, empty for the logins with a Grafana password.
+	AuthModule string
 
 	IsSignedIn     bool
 	IsRenderCall   bool
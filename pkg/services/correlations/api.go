@@ -27,6 +27,7 @@ func (s *CorrelationsService) registerAPIEndpoints() {
 			entities.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.getCorrelationHandler))
 			entities.Delete("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.deleteHandler))
 			entities.Patch("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(datasources.ActionWrite, uidScope)), routing.Wrap(s.updateHandler))
+			entities.Post("/target-query", authorize(middleware.ReqSignedIn, ac.EvalPermission(datasources.ActionRead)), routing.Wrap(s.generateTargetQueryHandler))
 		})
 	}, middleware.ReqSignedIn)
 }
@@ -245,6 +246,64 @@ type GetCorrelationResponse struct {
 	Body Correlation `json:"body"`
 }
 
+// swagger:route POST /datasources/uid/{sourceUID}/correlations/{correlationUID}/target-query correlations generateCorrelationTargetQuery
+//
+// Generates the target query of a correlation for a row of the source data.
+//
+// Applies the transformations of the correlation to the field values of the row and interpolates the resulting variables in the target query.
+//
+// Responses:
+// 200: generateCorrelationTargetQueryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *CorrelationsService) generateTargetQueryHandler(c *contextmodel.ReqContext) response.Response {
+	cmd := GenerateTargetQueryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.UID = web.Params(c.Req)[":correlationUID"]
+	cmd.SourceUID = web.Params(c.Req)[":uid"]
+	cmd.OrgId = c.OrgID
+
+	targetQuery, err := s.GenerateTargetQuery(c.Req.Context(), cmd)
+	if err != nil {
+		if errors.Is(err, ErrCorrelationNotFound) {
+			return response.Error(http.StatusNotFound, "Correlation not found", err)
+		}
+		if errors.Is(err, ErrSourceDataSourceDoesNotExists) {
+			return response.Error(http.StatusNotFound, "Source data source not found", err)
+		}
+		if errors.Is(err, ErrTransformationInvalidRegex) || errors.Is(err, ErrTransformationInvalidLogfmt) || errors.Is(err, ErrInvalidTransformationType) {
+			return response.Error(http.StatusBadRequest, "Failed to apply the correlation transformations", err)
+		}
+
+		return response.Error(http.StatusInternalServerError, "Failed to generate the correlation target query", err)
+	}
+
+	return response.JSON(http.StatusOK, targetQuery)
+}
+
+// swagger:parameters generateCorrelationTargetQuery
+type GenerateTargetQueryParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"sourceUID"`
+	// in:path
+	// required:true
+	CorrelationUID string `json:"correlationUID"`
+	// in: body
+	// required:true
+	Body GenerateTargetQueryCommand `json:"body"`
+}
+
+//swagger:response generateCorrelationTargetQueryResponse
+type GenerateTargetQueryResponse struct {
+	// in: body
+	Body TargetQuery `json:"body"`
+}
+
 // swagger:route GET /datasources/uid/{sourceUID}/correlations correlations getCorrelationsBySourceUID
 //
 // Gets all correlations originating from the given data source.
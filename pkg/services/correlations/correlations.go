@@ -67,6 +67,34 @@ func (s CorrelationsService) GetCorrelations(ctx context.Context, cmd GetCorrela
 	return s.getCorrelations(ctx, cmd)
 }
 
+// GenerateTargetQuery applies the transformations of the correlation to a row of the source data and
+// interpolates the resulting variables in its target query.
+func (s CorrelationsService) GenerateTargetQuery(ctx context.Context, cmd GenerateTargetQueryCommand) (TargetQuery, error) {
+	correlation, err := s.getCorrelation(ctx, GetCorrelationQuery{
+		UID:       cmd.UID,
+		SourceUID: cmd.SourceUID,
+		OrgId:     cmd.OrgId,
+	})
+	if err != nil {
+		return TargetQuery{}, err
+	}
+
+	vars, err := correlation.Config.Transformations.Apply(cmd.Fields, correlation.Config.Field)
+	if err != nil {
+		return TargetQuery{}, err
+	}
+
+	target := correlation.Config.Target
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+	return TargetQuery{
+		TargetUID: correlation.TargetUID,
+		Query:     InterpolateTarget(target, vars),
+		Variables: vars,
+	}, nil
+}
+
 func (s CorrelationsService) DeleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
 	return s.deleteCorrelationsBySourceUID(ctx, cmd)
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 )
 
 var (
@@ -17,12 +18,15 @@ var (
 	ErrInvalidTransformationType          = errors.New("invalid transformation type")
 	ErrTransformationNotNested            = errors.New("transformations must be nested under config")
 	ErrTransformationRegexReqExp          = errors.New("regex transformations require expression")
+	ErrTransformationTemplateReqExp       = errors.New("template transformations require expression and mapValue")
+	ErrTransformationInvalidRegex         = errors.New("invalid regex transformation expression")
+	ErrTransformationInvalidLogfmt        = errors.New("invalid logfmt value")
 )
 
 type CorrelationConfigType string
 
 type Transformation struct {
-	//Enum: regex,logfmt
+	//Enum: regex,logfmt,template
 	Type       string `json:"type"`
 	Expression string `json:"expression,omitempty"`
 	Field      string `json:"field,omitempty"`
@@ -42,10 +46,16 @@ func (t CorrelationConfigType) Validate() error {
 
 func (t Transformations) Validate() error {
 	for _, v := range t {
-		if v.Type != "regex" && v.Type != "logfmt" {
+		if v.Type != TransformationTypeRegex && v.Type != TransformationTypeLogfmt && v.Type != TransformationTypeTemplate {
 			return fmt.Errorf("%s: \"%s\"", ErrInvalidTransformationType, t)
-		} else if v.Type == "regex" && len(v.Expression) == 0 {
+		} else if v.Type == TransformationTypeRegex && len(v.Expression) == 0 {
 			return fmt.Errorf("%s: \"%s\"", ErrTransformationRegexReqExp, t)
+		} else if v.Type == TransformationTypeRegex {
+			if _, err := regexp.Compile(v.Expression); err != nil {
+				return fmt.Errorf("%s: \"%s\"", ErrTransformationInvalidRegex, t)
+			}
+		} else if v.Type == TransformationTypeTemplate && (len(v.Expression) == 0 || len(v.MapValue) == 0) {
+			return fmt.Errorf("%s: \"%s\"", ErrTransformationTemplateReqExp, t)
 		}
 	}
 	return nil
@@ -198,7 +208,7 @@ func (c CorrelationConfigUpdateDTO) Validate() error {
 		}
 	}
 
-	return nil
+	return Transformations(c.Transformations).Validate()
 }
 
 // UpdateCorrelationCommand is the command for updating a correlation
@@ -260,3 +270,31 @@ type DeleteCorrelationsBySourceUIDCommand struct {
 type DeleteCorrelationsByTargetUIDCommand struct {
 	TargetUID string
 }
+
+// GenerateTargetQueryCommand is the command for generating the target query of a correlation
+// swagger:model
+type GenerateTargetQueryCommand struct {
+	// UID of the correlation
+	UID string `json:"-"`
+	// UID of the source data source
+	SourceUID string `json:"-"`
+	OrgId     int64  `json:"-"`
+
+	// Values of the fields of the source data row the correlation link is generated for
+	// required:true
+	// example: { "message": "level=error traceID=8f3d1 msg=\"request failed\"" }
+	Fields map[string]string `json:"fields" binding:"Required"`
+}
+
+// TargetQuery is the query of the target data source of a correlation, for a row of the source data
+// swagger:model
+type TargetQuery struct {
+	// UID of the target data source
+	// example: PDDA8E780A17E7EF1
+	TargetUID *string `json:"targetUID"`
+	// Target data query with the variables interpolated
+	// example: { "query": "8f3d1" }
+	Query map[string]interface{} `json:"query"`
+	// Variables computed by the transformations, including the fields of the source data row
+	Variables map[string]string `json:"variables"`
+}
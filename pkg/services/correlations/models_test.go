@@ -57,6 +57,27 @@ func TestCorrelationModels(t *testing.T) {
 		})
 	})
 
+	t.Run("Transformations Validate", func(t *testing.T) {
+		type test struct {
+			input     Transformations
+			assertion require.ErrorAssertionFunc
+		}
+
+		tests := []test{
+			{input: Transformations{{Type: "logfmt"}}, assertion: require.NoError},
+			{input: Transformations{{Type: "regex", Expression: `(\w+)`}}, assertion: require.NoError},
+			{input: Transformations{{Type: "regex"}}, assertion: require.Error},
+			{input: Transformations{{Type: "regex", Expression: `(`}}, assertion: require.Error},
+			{input: Transformations{{Type: "template", Expression: "$a-$b", MapValue: "c"}}, assertion: require.NoError},
+			{input: Transformations{{Type: "template", Expression: "$a-$b"}}, assertion: require.Error},
+			{input: Transformations{{Type: "unknown"}}, assertion: require.Error},
+		}
+
+		for _, tc := range tests {
+			tc.assertion(t, tc.input.Validate())
+		}
+	})
+
 	t.Run("CorrelationConfigType Validate", func(t *testing.T) {
 		t.Run("Successfully validates a correct type", func(t *testing.T) {
 			type test struct {
@@ -0,0 +1,142 @@
+package correlations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-logfmt/logfmt"
+)
+
+const (
+	TransformationTypeRegex    = "regex"
+	TransformationTypeLogfmt   = "logfmt"
+	TransformationTypeTemplate = "template"
+)
+
+// variablePattern matches the $name and ${name} variables of the target queries and template transformations
+var variablePattern = regexp.MustCompile(`\$\{([\w.]+)\}|\$([\w.]+)`)
+
+// Apply computes the variables of the transformations from the field values of a row of the source data.
+// The transformations are applied in order, each of them reading the field it is configured with, or the
+// field of the correlation, so that a template can reuse the variables extracted by the previous ones.
+func (t Transformations) Apply(fields map[string]string, defaultField string) (map[string]string, error) {
+	vars := make(map[string]string, len(fields))
+	for k, v := range fields {
+		vars[k] = v
+	}
+
+	for _, transformation := range t {
+		field := transformation.Field
+		if field == "" {
+			field = defaultField
+		}
+
+		switch transformation.Type {
+		case TransformationTypeRegex:
+			re, err := regexp.Compile("(?i)" + transformation.Expression)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrTransformationInvalidRegex, err)
+			}
+			applyRegex(re, vars[field], transformation.MapValue, field, vars)
+		case TransformationTypeLogfmt:
+			if err := applyLogfmt(vars[field], vars); err != nil {
+				return nil, err
+			}
+		case TransformationTypeTemplate:
+			vars[transformation.MapValue] = interpolate(transformation.Expression, vars)
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTransformationType, transformation.Type)
+		}
+	}
+	return vars, nil
+}
+
+// applyRegex stores the named groups of the last match, or its first group, or the whole match.
+func applyRegex(re *regexp.Regexp, value, mapValue, field string, vars map[string]string) {
+	matches := re.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return
+	}
+	match := matches[len(matches)-1]
+
+	named := false
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			vars[name] = match[i]
+			named = true
+		}
+	}
+	if named {
+		return
+	}
+
+	name := mapValue
+	if name == "" {
+		name = field
+	}
+	if len(match) > 1 && match[1] != "" {
+		vars[name] = match[1]
+	} else {
+		vars[name] = match[0]
+	}
+}
+
+// applyLogfmt stores the keys of the logfmt value, keys without a value being true.
+func applyLogfmt(value string, vars map[string]string) error {
+	d := logfmt.NewDecoder(strings.NewReader(value))
+	for d.ScanRecord() {
+		for d.ScanKeyval() {
+			if d.Value() == nil {
+				vars[string(d.Key())] = "true"
+				continue
+			}
+			vars[string(d.Key())] = string(d.Value())
+		}
+	}
+	if err := d.Err(); err != nil {
+		return fmt.Errorf("%w: %s", ErrTransformationInvalidLogfmt, err)
+	}
+	return nil
+}
+
+// interpolate replaces the variables of the text, the unknown ones being left as is.
+func interpolate(text string, vars map[string]string) string {
+	return variablePattern.ReplaceAllStringFunc(text, func(v string) string {
+		m := variablePattern.FindStringSubmatch(v)
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return v
+	})
+}
+
+// InterpolateTarget returns a copy of the target query with the variables of its strings replaced.
+func InterpolateTarget(target map[string]interface{}, vars map[string]string) map[string]interface{} {
+	return interpolateValue(target, vars).(map[string]interface{})
+}
+
+func interpolateValue(value interface{}, vars map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return interpolate(v, vars)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			result[k] = interpolateValue(item, vars)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = interpolateValue(item, vars)
+		}
+		return result
+	default:
+		return value
+	}
+}
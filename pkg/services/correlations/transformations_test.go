@@ -0,0 +1,81 @@
+package correlations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformationsApply(t *testing.T) {
+	t.Run("Regex stores the first group in the mapped variable", func(t *testing.T) {
+		transformations := Transformations{{Type: "regex", Expression: `traceID=(\w+)`, MapValue: "trace"}}
+		vars, err := transformations.Apply(map[string]string{"message": "level=error traceID=8f3d1"}, "message")
+		require.NoError(t, err)
+		require.Equal(t, "8f3d1", vars["trace"])
+	})
+
+	t.Run("Regex stores the whole match in the field variable when there is no group nor mapped variable", func(t *testing.T) {
+		transformations := Transformations{{Type: "regex", Expression: `ERROR`}}
+		vars, err := transformations.Apply(map[string]string{"message": "level=error"}, "message")
+		require.NoError(t, err)
+		require.Equal(t, "error", vars["message"])
+	})
+
+	t.Run("Regex stores the named groups of the last match", func(t *testing.T) {
+		transformations := Transformations{{Type: "regex", Expression: `(?P<key>\w+)=(?P<value>\d+)`}}
+		vars, err := transformations.Apply(map[string]string{"message": "a=1 b=2"}, "message")
+		require.NoError(t, err)
+		require.Equal(t, "b", vars["key"])
+		require.Equal(t, "2", vars["value"])
+	})
+
+	t.Run("Regex reads the field of the transformation", func(t *testing.T) {
+		transformations := Transformations{{Type: "regex", Field: "path", Expression: `/api/(\w+)`, MapValue: "resource"}}
+		vars, err := transformations.Apply(map[string]string{"message": "no match", "path": "/api/users"}, "message")
+		require.NoError(t, err)
+		require.Equal(t, "users", vars["resource"])
+	})
+
+	t.Run("Logfmt stores the keys of the value", func(t *testing.T) {
+		transformations := Transformations{{Type: "logfmt"}}
+		vars, err := transformations.Apply(map[string]string{"message": `level=error msg="request failed" retry`}, "message")
+		require.NoError(t, err)
+		require.Equal(t, "error", vars["level"])
+		require.Equal(t, "request failed", vars["msg"])
+		require.Equal(t, "true", vars["retry"])
+	})
+
+	t.Run("Template interpolates the variables of the previous transformations", func(t *testing.T) {
+		transformations := Transformations{
+			{Type: "logfmt"},
+			{Type: "template", Expression: "${service}-$level-${unknown}", MapValue: "name"},
+		}
+		vars, err := transformations.Apply(map[string]string{"message": "level=error", "service": "api"}, "message")
+		require.NoError(t, err)
+		require.Equal(t, "api-error-${unknown}", vars["name"])
+	})
+
+	t.Run("Fails if the regex is invalid", func(t *testing.T) {
+		transformations := Transformations{{Type: "regex", Expression: `(`}}
+		_, err := transformations.Apply(map[string]string{"message": "a"}, "message")
+		require.ErrorIs(t, err, ErrTransformationInvalidRegex)
+	})
+}
+
+func TestInterpolateTarget(t *testing.T) {
+	target := map[string]interface{}{
+		"expr":    `{job="$job"} |= "${traceID}"`,
+		"refId":   "A",
+		"limit":   float64(10),
+		"filters": []interface{}{map[string]interface{}{"value": "$job"}},
+	}
+
+	result := InterpolateTarget(target, map[string]string{"job": "app", "traceID": "8f3d1"})
+	require.Equal(t, map[string]interface{}{
+		"expr":    `{job="app"} |= "8f3d1"`,
+		"refId":   "A",
+		"limit":   float64(10),
+		"filters": []interface{}{map[string]interface{}{"value": "app"}},
+	}, result)
+	require.Equal(t, `{job="$job"} |= "${traceID}"`, target["expr"])
+}
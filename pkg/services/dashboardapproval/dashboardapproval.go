@@ -0,0 +1,107 @@
+package dashboardapproval
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// IsValid returns true for the known statuses of the changes.
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusApproved, StatusRejected:
+		return true
+	}
+	return false
+}
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+var (
+	ErrChangeNotFound  = errutil.NewBase(errutil.StatusNotFound, "dashboardapproval.notFound", errutil.WithPublicMessage("Pending change not found"))
+	ErrForbidden       = errutil.NewBase(errutil.StatusForbidden, "dashboardapproval.forbidden", errutil.WithPublicMessage("Not allowed to review the change"))
+	ErrAlreadyReviewed = errutil.NewBase(errutil.StatusBadRequest, "dashboardapproval.alreadyReviewed", errutil.WithPublicMessage("The change was already reviewed"))
+	ErrChangeOutdated  = errutil.NewBase(errutil.StatusBadRequest, "dashboardapproval.outdated", errutil.WithPublicMessage("The dashboard was changed since the change was submitted"))
+)
+
+// Service holds the changes of the dashboards of the folders requiring an approval, until a user with the
+// dashboards:approve action on the folder approves or rejects them. The changes are held by the dashboard
+// service, the approved ones are saved as a new version of the dashboard.
+type Service interface {
+	// GetChange returns a change, with the dashboard as it is saved once approved.
+	GetChange(ctx context.Context, orgID, id int64) (*PendingChange, error)
+	// ListChanges returns the changes of an org, latest first, without their dashboard.
+	ListChanges(ctx context.Context, query *ListQuery) ([]*PendingChange, error)
+	// Diff compares the dashboard of a change with the current version of the dashboard.
+	Diff(ctx context.Context, change *PendingChange, diffType dashdiffs.DiffType) (*dashdiffs.Result, error)
+	// CanReview returns true if the user is allowed to approve or reject a change: the user needs the
+	// dashboards:approve action on the folder of the change, and on the folder the dashboard is moved from.
+	CanReview(ctx context.Context, u *user.SignedInUser, change *PendingChange) (bool, error)
+
+	// Approve saves the dashboard of a pending change, and returns the reviewed change.
+	Approve(ctx context.Context, cmd *ReviewCommand) (*PendingChange, error)
+	// Reject discards a pending change, and returns the reviewed change.
+	Reject(ctx context.Context, cmd *ReviewCommand) (*PendingChange, error)
+}
+
+// PendingChange is a change of a dashboard held for approval.
+type PendingChange struct {
+	ID    int64 `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID int64 `xorm:"org_id" json:"orgId"`
+	// DashboardUID is the dashboard changed, or created when BaseVersion is 0
+	DashboardUID string `xorm:"dashboard_uid" json:"dashboardUid"`
+	FolderUID    string `xorm:"folder_uid" json:"folderUid"`
+	Title        string `xorm:"title" json:"title"`
+	// Data is the dashboard as it is saved once approved
+	Data    *simplejson.Json `xorm:"data" json:"dashboard,omitempty"`
+	Message string           `xorm:"message" json:"message"`
+	// BaseVersion is the version of the dashboard the change was made on, the approval fails if the dashboard
+	// was saved since
+	BaseVersion int       `xorm:"base_version" json:"baseVersion"`
+	Status      Status    `xorm:"status" json:"status"`
+	CreatedBy   int64     `xorm:"created_by" json:"createdBy"`
+	Created     time.Time `xorm:"created" json:"created"`
+	// ReviewedBy is the user who approved or rejected the change, 0 while it is pending
+	ReviewedBy    int64  `xorm:"reviewed_by" json:"reviewedBy"`
+	ReviewComment string `xorm:"review_comment" json:"reviewComment"`
+	// DashboardVersion is the version of the dashboard saved when the change was approved
+	DashboardVersion int       `xorm:"dashboard_version" json:"dashboardVersion"`
+	Updated          time.Time `xorm:"updated" json:"updated"`
+
+	// DiffURL is the URL of the diff of the change with the current dashboard
+	DiffURL string `xorm:"-" json:"diffUrl"`
+}
+
+func (c PendingChange) TableName() string {
+	return "dashboard_pending_change"
+}
+
+type ListQuery struct {
+	OrgID int64
+	// Status filters the changes by status, all of them when empty
+	Status       Status
+	DashboardUID string
+	Limit        int
+}
+
+type ReviewCommand struct {
+	OrgID   int64              `json:"-"`
+	ID      int64              `json:"-"`
+	User    *user.SignedInUser `json:"-"`
+	Comment string             `json:"comment"`
+}
@@ -0,0 +1,165 @@
+package dashboardapprovalimpl
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	reqApprover := authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsApprove))
+
+	routeRegister.Group("/api/dashboards/pending-changes", func(changes routing.RouteRegister) {
+		changes.Get("/", middleware.ReqSignedIn, routing.Wrap(s.handleList))
+		changes.Get("/:id", middleware.ReqSignedIn, routing.Wrap(s.handleGet))
+		changes.Get("/:id/diff", middleware.ReqSignedIn, routing.Wrap(s.handleDiff))
+		changes.Post("/:id/approve", reqApprover, routing.Wrap(s.handleApprove))
+		changes.Post("/:id/reject", reqApprover, routing.Wrap(s.handleReject))
+	})
+}
+
+// handleList returns the changes the user made or can review.
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	query := &dashboardapproval.ListQuery{
+		OrgID:        c.OrgID,
+		Status:       dashboardapproval.Status(c.Query("status")),
+		DashboardUID: c.Query("dashboardUid"),
+		Limit:        c.QueryInt("limit"),
+	}
+	if query.Status != "" && !query.Status.IsValid() {
+		return response.Error(http.StatusBadRequest, "Invalid status, expected pending, approved or rejected", nil)
+	}
+
+	changes, err := s.ListChanges(c.Req.Context(), query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list pending changes", err)
+	}
+
+	visible := make([]*dashboardapproval.PendingChange, 0, len(changes))
+	for _, change := range changes {
+		canView, err := s.canView(c, change)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+		}
+		if canView {
+			visible = append(visible, change)
+		}
+	}
+	return response.JSON(http.StatusOK, visible)
+}
+
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	change, rsp := s.getVisibleChange(c)
+	if rsp != nil {
+		return rsp
+	}
+	return response.JSON(http.StatusOK, change)
+}
+
+// handleDiff compares the change with the current dashboard, as the diff of the versions of a dashboard.
+func (s *Service) handleDiff(c *contextmodel.ReqContext) response.Response {
+	change, rsp := s.getVisibleChange(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	diffType := dashdiffs.ParseDiffType(c.Query("diffType"))
+	result, err := s.Diff(c.Req.Context(), change, diffType)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+
+	if diffType == dashdiffs.DiffDelta {
+		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
+	}
+	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "text/html")
+}
+
+func (s *Service) handleApprove(c *contextmodel.ReqContext) response.Response {
+	cmd, rsp := bindReview(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	change, err := s.Approve(c.Req.Context(), cmd)
+	if err != nil {
+		return reviewErrorResponse("Failed to approve change", err)
+	}
+	return response.JSON(http.StatusOK, change)
+}
+
+func (s *Service) handleReject(c *contextmodel.ReqContext) response.Response {
+	cmd, rsp := bindReview(c)
+	if rsp != nil {
+		return rsp
+	}
+
+	change, err := s.Reject(c.Req.Context(), cmd)
+	if err != nil {
+		return reviewErrorResponse("Failed to reject change", err)
+	}
+	return response.JSON(http.StatusOK, change)
+}
+
+func bindReview(c *contextmodel.ReqContext) (*dashboardapproval.ReviewCommand, response.Response) {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return nil, response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+	cmd := &dashboardapproval.ReviewCommand{}
+	if err := web.Bind(c.Req, cmd); err != nil {
+		return nil, response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.ID = id
+	cmd.User = c.SignedInUser
+	return cmd, nil
+}
+
+// getVisibleChange returns the change of the request if the user made it or can review it.
+func (s *Service) getVisibleChange(c *contextmodel.ReqContext) (*dashboardapproval.PendingChange, response.Response) {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 64)
+	if err != nil {
+		return nil, response.Error(http.StatusBadRequest, "id is invalid", err)
+	}
+
+	change, err := s.GetChange(c.Req.Context(), c.OrgID, id)
+	if err != nil {
+		return nil, response.ErrOrFallback(http.StatusInternalServerError, "Failed to get pending change", err)
+	}
+	canView, err := s.canView(c, change)
+	if err != nil {
+		return nil, response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+	}
+	if !canView {
+		return nil, response.Err(dashboardapproval.ErrForbidden.Errorf("user %d can't view change %d", c.UserID, change.ID))
+	}
+	return change, nil
+}
+
+func (s *Service) canView(c *contextmodel.ReqContext, change *dashboardapproval.PendingChange) (bool, error) {
+	if change.CreatedBy == c.UserID {
+		return true, nil
+	}
+	return s.CanReview(c.Req.Context(), c.SignedInUser, change)
+}
+
+// reviewErrorResponse returns the errors of the dashboard service as the dashboard API does.
+func reviewErrorResponse(message string, err error) response.Response {
+	var dashboardErr dashboards.DashboardErr
+	if errors.As(err, &dashboardErr) {
+		return response.Error(dashboardErr.StatusCode, dashboardErr.Error(), err)
+	}
+	return response.ErrOrFallback(http.StatusInternalServerError, message, err)
+}
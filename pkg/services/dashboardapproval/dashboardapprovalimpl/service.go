@@ -0,0 +1,403 @@
+package dashboardapprovalimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type Service struct {
+	db               db.DB
+	store            store
+	folderStore      folder.FolderStore
+	folderSettings   foldersettings.Service
+	dashboardService dashboards.DashboardService
+	accessControl    ac.AccessControl
+	acService        ac.Service
+	inboxService     inbox.Service
+	log              log.Logger
+	now              func() time.Time
+}
+
+var _ dashboardapproval.Service = (*Service)(nil)
+
+func ProvideService(
+	sql db.DB,
+	folderStore folder.FolderStore,
+	folderSettings foldersettings.Service,
+	dashboardService *dashboardservice.DashboardServiceImpl,
+	accessControl ac.AccessControl,
+	acService ac.Service,
+	inboxService inbox.Service,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := &Service{
+		db:               sql,
+		store:            &sqlStore{db: sql},
+		folderStore:      folderStore,
+		folderSettings:   folderSettings,
+		dashboardService: dashboardService,
+		accessControl:    accessControl,
+		acService:        acService,
+		inboxService:     inboxService,
+		log:              log.New("dashboardapproval"),
+		now:              time.Now,
+	}
+
+	dashboardService.SetApprovalHook(s.holdForApproval)
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+// DiffURL is the URL of the diff of a change with the current dashboard.
+func DiffURL(id int64) string {
+	return fmt.Sprintf("/api/dashboards/pending-changes/%d/diff", id)
+}
+
+// holdForApproval is the dashboard approval hook: the changes of the dashboards of the folders requiring an
+// approval, or moved out of them, are stored as pending changes unless the user can approve them.
+func (s *Service) holdForApproval(ctx context.Context, dto *dashboards.SaveDashboardDTO, isNew bool) error {
+	dash := dto.Dashboard
+	folderIDs := []int64{dash.FolderID}
+	baseVersion := 0
+	if !isNew {
+		existing, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: dash.OrgID, ID: dash.ID})
+		if err != nil {
+			return err
+		}
+		if existing.FolderID != dash.FolderID {
+			folderIDs = append(folderIDs, existing.FolderID)
+		}
+		baseVersion = existing.Version
+	}
+
+	targetUID := ""
+	var reviewFolders []string
+	for i, folderID := range folderIDs {
+		if folderID == 0 {
+			continue
+		}
+		f, err := s.folderStore.GetFolderByID(ctx, dash.OrgID, folderID)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			targetUID = f.UID
+		}
+		settings, err := s.folderSettings.Get(ctx, dash.OrgID, f.UID)
+		if err != nil {
+			return err
+		}
+		if settings.RequireApproval {
+			reviewFolders = append(reviewFolders, f.UID)
+		}
+	}
+	if len(reviewFolders) == 0 {
+		return nil
+	}
+
+	canApprove, err := s.canApprove(ctx, dto.User, reviewFolders)
+	if err != nil || canApprove {
+		return err
+	}
+
+	// the change is applied on the version it was made on, even when it overwrote a newer version
+	if dash.UID == "" {
+		dash.SetUID(util.GenerateShortUID())
+	}
+	dash.Data.Set("version", baseVersion)
+
+	now := s.now()
+	change := &dashboardapproval.PendingChange{
+		OrgID:        dash.OrgID,
+		DashboardUID: dash.UID,
+		FolderUID:    targetUID,
+		Title:        dash.Title,
+		Data:         dash.Data,
+		Message:      dto.Message,
+		BaseVersion:  baseVersion,
+		Status:       dashboardapproval.StatusPending,
+		CreatedBy:    dto.User.UserID,
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.store.Insert(ctx, change); err != nil {
+		return err
+	}
+	change.DiffURL = DiffURL(change.ID)
+	s.log.Info("Dashboard change held for approval", "orgId", change.OrgID, "dashboardUid", change.DashboardUID, "changeId", change.ID, "userId", change.CreatedBy)
+
+	s.notifyReviewers(ctx, change, dto.User, reviewFolders)
+	return dashboards.PendingApprovalError{ChangeID: change.ID, DiffURL: change.DiffURL}
+}
+
+// canApprove returns true if the user has the dashboards:approve action on all the folders.
+func (s *Service) canApprove(ctx context.Context, u *user.SignedInUser, folderUIDs []string) (bool, error) {
+	if len(folderUIDs) == 0 {
+		return false, nil
+	}
+	evaluators := make([]ac.Evaluator, 0, len(folderUIDs))
+	for _, uid := range folderUIDs {
+		evaluators = append(evaluators, ac.EvalPermission(dashboards.ActionDashboardsApprove, dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid)))
+	}
+	return s.accessControl.Evaluate(ctx, u, ac.EvalAll(evaluators...))
+}
+
+// changeFolders returns the folder of a change, and the current folder of its dashboard when the change moves it.
+func (s *Service) changeFolders(ctx context.Context, change *dashboardapproval.PendingChange) ([]string, error) {
+	var folderUIDs []string
+	if change.FolderUID != "" {
+		folderUIDs = append(folderUIDs, change.FolderUID)
+	}
+
+	current, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: change.OrgID, UID: change.DashboardUID})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return folderUIDs, nil
+		}
+		return nil, err
+	}
+	if current.FolderID != 0 {
+		f, err := s.folderStore.GetFolderByID(ctx, change.OrgID, current.FolderID)
+		if err != nil {
+			return nil, err
+		}
+		if f.UID != change.FolderUID {
+			folderUIDs = append(folderUIDs, f.UID)
+		}
+	}
+	return folderUIDs, nil
+}
+
+func (s *Service) CanReview(ctx context.Context, u *user.SignedInUser, change *dashboardapproval.PendingChange) (bool, error) {
+	folderUIDs, err := s.changeFolders(ctx, change)
+	if err != nil {
+		return false, err
+	}
+	return s.canApprove(ctx, u, folderUIDs)
+}
+
+func (s *Service) GetChange(ctx context.Context, orgID, id int64) (*dashboardapproval.PendingChange, error) {
+	change, err := s.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	change.DiffURL = DiffURL(change.ID)
+	return change, nil
+}
+
+func (s *Service) ListChanges(ctx context.Context, query *dashboardapproval.ListQuery) ([]*dashboardapproval.PendingChange, error) {
+	if query.Limit <= 0 {
+		query.Limit = dashboardapproval.DefaultLimit
+	}
+	if query.Limit > dashboardapproval.MaxLimit {
+		query.Limit = dashboardapproval.MaxLimit
+	}
+	changes, err := s.store.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		change.DiffURL = DiffURL(change.ID)
+	}
+	return changes, nil
+}
+
+func (s *Service) Diff(ctx context.Context, change *dashboardapproval.PendingChange, diffType dashdiffs.DiffType) (*dashdiffs.Result, error) {
+	// a new dashboard is compared with an empty one
+	base := simplejson.New()
+	current, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: change.OrgID, UID: change.DashboardUID})
+	if err == nil {
+		base = current.Data
+	} else if !errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return nil, err
+	}
+
+	return dashdiffs.CalculateDiff(ctx, &dashdiffs.Options{OrgId: change.OrgID, DiffType: diffType}, base, change.Data)
+}
+
+func (s *Service) Approve(ctx context.Context, cmd *dashboardapproval.ReviewCommand) (*dashboardapproval.PendingChange, error) {
+	change, err := s.reviewable(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	var folderID int64
+	if change.FolderUID != "" {
+		f, err := s.folderStore.GetFolderByUID(ctx, change.OrgID, change.FolderUID)
+		if err != nil {
+			return nil, err
+		}
+		folderID = f.ID
+	}
+	dash := dashboards.NewDashboardFromJson(change.Data)
+	dash.FolderID = folderID
+
+	// the change is claimed in the transaction of the save, so that the save is rolled back when the change was
+	// reviewed concurrently
+	err = s.db.InTransaction(ctx, func(ctx context.Context) error {
+		// the reviewer can approve, so the dashboard is saved instead of being held again
+		saved, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+			OrgID:     change.OrgID,
+			User:      cmd.User,
+			Message:   change.Message,
+			Dashboard: dash,
+		}, true)
+		if err != nil {
+			if errors.Is(err, dashboards.ErrDashboardVersionMismatch) || errors.Is(err, dashboards.ErrDashboardWithSameUIDExists) {
+				return dashboardapproval.ErrChangeOutdated.Errorf("change %d can't be applied: %v", change.ID, err)
+			}
+			return err
+		}
+
+		change.Status = dashboardapproval.StatusApproved
+		change.DashboardVersion = saved.Version
+		return s.storeReview(ctx, change, cmd)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.reviewed(ctx, change, cmd)
+	return change, nil
+}
+
+func (s *Service) Reject(ctx context.Context, cmd *dashboardapproval.ReviewCommand) (*dashboardapproval.PendingChange, error) {
+	change, err := s.reviewable(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	change.Status = dashboardapproval.StatusRejected
+	if err := s.storeReview(ctx, change, cmd); err != nil {
+		return nil, err
+	}
+	s.reviewed(ctx, change, cmd)
+	return change, nil
+}
+
+// reviewable returns the change of the command if it is pending and the user can review it.
+func (s *Service) reviewable(ctx context.Context, cmd *dashboardapproval.ReviewCommand) (*dashboardapproval.PendingChange, error) {
+	change, err := s.GetChange(ctx, cmd.OrgID, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	if change.Status != dashboardapproval.StatusPending {
+		return nil, dashboardapproval.ErrAlreadyReviewed.Errorf("change %d was already %s", change.ID, change.Status)
+	}
+
+	canReview, err := s.CanReview(ctx, cmd.User, change)
+	if err != nil {
+		return nil, err
+	}
+	if !canReview {
+		return nil, dashboardapproval.ErrForbidden.Errorf("user %d can't review change %d", cmd.User.UserID, change.ID)
+	}
+	return change, nil
+}
+
+// storeReview stores the review of the change, it fails if the change is no longer pending.
+func (s *Service) storeReview(ctx context.Context, change *dashboardapproval.PendingChange, cmd *dashboardapproval.ReviewCommand) error {
+	change.ReviewedBy = cmd.User.UserID
+	change.ReviewComment = cmd.Comment
+	change.Updated = s.now()
+	return s.store.Review(ctx, change)
+}
+
+// reviewed logs the stored review of the change and notifies its author.
+func (s *Service) reviewed(ctx context.Context, change *dashboardapproval.PendingChange, cmd *dashboardapproval.ReviewCommand) {
+	s.log.Info("Dashboard change reviewed", "orgId", change.OrgID, "dashboardUid", change.DashboardUID, "changeId", change.ID, "status", change.Status, "userId", change.ReviewedBy)
+
+	s.notifyAuthor(ctx, change, cmd.User)
+}
+
+// notifyReviewers notifies the users who can approve a new change, the notifications are best effort.
+func (s *Service) notifyReviewers(ctx context.Context, change *dashboardapproval.PendingChange, author *user.SignedInUser, folderUIDs []string) {
+	// the reviewers are searched as a service, the author may not be allowed to list the permissions of the users
+	searcher := ac.BackgroundUser("dashboard_approval", change.OrgID, org.RoleAdmin, []ac.Permission{
+		{Action: ac.ActionUsersPermissionsRead, Scope: ac.ScopeUsersAll},
+	})
+	usersPermissions, err := s.acService.SearchUsersPermissions(ctx, searcher, change.OrgID, ac.SearchOptions{Action: dashboards.ActionDashboardsApprove})
+	if err != nil {
+		s.log.Warn("Failed to search the reviewers of a dashboard change", "changeId", change.ID, "error", err)
+		return
+	}
+
+	evaluators := make([]ac.Evaluator, 0, len(folderUIDs))
+	for _, uid := range folderUIDs {
+		evaluators = append(evaluators, ac.EvalPermission(dashboards.ActionDashboardsApprove, dashboards.ScopeFoldersProvider.GetResourceScopeUID(uid)))
+	}
+	canApprove := ac.EvalAll(evaluators...)
+
+	reviewers := make([]int64, 0, len(usersPermissions))
+	for userID, permissions := range usersPermissions {
+		if userID != author.UserID && canApprove.Evaluate(ac.GroupScopesByAction(permissions)) {
+			reviewers = append(reviewers, userID)
+		}
+	}
+
+	err = s.inboxService.Notify(ctx, &inbox.NotifyCommand{
+		OrgID:   change.OrgID,
+		UserIDs: reviewers,
+		Kind:    inbox.KindChangeReview,
+		Title:   "A dashboard change awaits your approval",
+		Body:    notificationBody(fmt.Sprintf("%s changed the dashboard %q", author.Login, change.Title), change.Message),
+		URL:     change.DiffURL,
+	})
+	if err != nil {
+		s.log.Warn("Failed to notify the reviewers of a dashboard change", "changeId", change.ID, "error", err)
+	}
+}
+
+// notifyAuthor notifies the author of a change that it was reviewed, the notification is best effort.
+func (s *Service) notifyAuthor(ctx context.Context, change *dashboardapproval.PendingChange, reviewer *user.SignedInUser) {
+	title := "Your dashboard change was rejected"
+	url := change.DiffURL
+	if change.Status == dashboardapproval.StatusApproved {
+		title = "Your dashboard change was approved"
+		url = fmt.Sprintf("/d/%s", change.DashboardUID)
+	}
+
+	err := s.inboxService.Notify(ctx, &inbox.NotifyCommand{
+		OrgID:   change.OrgID,
+		UserIDs: []int64{change.CreatedBy},
+		Kind:    inbox.KindChangeReview,
+		Title:   title,
+		Body:    notificationBody(fmt.Sprintf("%s reviewed your change to the dashboard %q", reviewer.Login, change.Title), change.ReviewComment),
+		URL:     url,
+	})
+	if err != nil {
+		s.log.Warn("Failed to notify the author of a dashboard change", "changeId", change.ID, "error", err)
+	}
+}
+
+// notificationBody adds the message of the author or of the reviewer to a notification, shortened as the
+// messages have no length limit.
+func notificationBody(summary, message string) string {
+	body := summary
+	if message != "" {
+		body += ": " + message
+	}
+	if len(body) > inbox.MaxBodyLength {
+		body = strings.ToValidUTF8(body[:inbox.MaxBodyLength], "")
+	}
+	return body
+}
@@ -0,0 +1,217 @@
+package dashboardapprovalimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/inbox/inboxtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationDashboardApproval(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sql := db.InitTestDB(t)
+	now := time.Now().Truncate(time.Second)
+	folderStore := foldertest.NewFakeFolderStore(t)
+	for _, f := range []*folder.Folder{{ID: 10, UID: "protected"}, {ID: 20, UID: "open"}} {
+		folderStore.On("GetFolderByID", mock.Anything, int64(1), f.ID).Return(f, nil).Maybe()
+		folderStore.On("GetFolderByUID", mock.Anything, int64(1), f.UID).Return(f, nil).Maybe()
+	}
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	inboxService := inboxtest.NewFakeService()
+	s := &Service{
+		db:               sql,
+		store:            &sqlStore{db: sql},
+		folderStore:      folderStore,
+		folderSettings:   fakeFolderSettings{"protected": {RequireApproval: true}},
+		dashboardService: dashboardService,
+		accessControl:    acimpl.ProvideAccessControl(setting.NewCfg()),
+		acService: actest.FakeService{ExpectedUsersPermissions: map[int64][]ac.Permission{
+			2: {{Action: dashboards.ActionDashboardsApprove, Scope: dashboards.ScopeFoldersAll}},
+			3: {{Action: dashboards.ActionDashboardsApprove, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("other")}},
+		}},
+		inboxService: inboxService,
+		log:          log.NewNopLogger(),
+		now:          func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	newUser := func(id int64, permissions ...ac.Permission) *user.SignedInUser {
+		return &user.SignedInUser{UserID: id, OrgID: 1, Login: "user", Permissions: map[int64]map[string][]string{1: ac.GroupScopesByAction(permissions)}}
+	}
+	editor := newUser(1, ac.Permission{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeFoldersAll})
+	approver := newUser(2, ac.Permission{Action: dashboards.ActionDashboardsApprove, Scope: dashboards.ScopeFoldersAll})
+	newDTO := func(u *user.SignedInUser, id, folderID int64) *dashboards.SaveDashboardDTO {
+		dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{"title": "Production", "version": 1}))
+		dash.ID = id
+		dash.OrgID = 1
+		dash.FolderID = folderID
+		return &dashboards.SaveDashboardDTO{OrgID: 1, User: u, Message: "more panels", Dashboard: dash}
+	}
+
+	t.Run("should save the dashboards of the folders without approval", func(t *testing.T) {
+		require.NoError(t, s.holdForApproval(ctx, newDTO(editor, 0, 20), true))
+		require.NoError(t, s.holdForApproval(ctx, newDTO(editor, 0, 0), true))
+	})
+
+	t.Run("should save the changes of the approvers", func(t *testing.T) {
+		require.NoError(t, s.holdForApproval(ctx, newDTO(approver, 0, 10), true))
+	})
+
+	var created, moved int64
+	t.Run("should hold the changes of the folders requiring an approval", func(t *testing.T) {
+		dto := newDTO(editor, 0, 10)
+		err := s.holdForApproval(ctx, dto, true)
+		var pending dashboards.PendingApprovalError
+		require.ErrorAs(t, err, &pending)
+		require.Equal(t, DiffURL(pending.ChangeID), pending.DiffURL)
+		require.NotEmpty(t, dto.Dashboard.UID)
+		created = pending.ChangeID
+
+		change, err := s.GetChange(ctx, 1, created)
+		require.NoError(t, err)
+		require.Equal(t, dashboardapproval.StatusPending, change.Status)
+		require.Equal(t, "protected", change.FolderUID)
+		require.Equal(t, dto.Dashboard.UID, change.DashboardUID)
+		require.Equal(t, "more panels", change.Message)
+		require.Equal(t, "Production", change.Data.Get("title").MustString())
+
+		// only the reviewers of the folder are notified
+		require.Len(t, inboxService.Notified, 1)
+		require.Equal(t, []int64{2}, inboxService.Notified[0].UserIDs)
+		require.Equal(t, inbox.KindChangeReview, inboxService.Notified[0].Kind)
+		require.Equal(t, DiffURL(created), inboxService.Notified[0].URL)
+	})
+
+	t.Run("should hold the dashboards moved out of the folders requiring an approval", func(t *testing.T) {
+		dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{OrgID: 1, ID: 5}).Return(&dashboards.Dashboard{ID: 5, UID: "moved", FolderID: 10, Version: 3}, nil).Once()
+		dto := newDTO(editor, 5, 20)
+		dto.Dashboard.SetUID("moved")
+		err := s.holdForApproval(ctx, dto, false)
+		var pending dashboards.PendingApprovalError
+		require.ErrorAs(t, err, &pending)
+		moved = pending.ChangeID
+
+		change, err := s.GetChange(ctx, 1, moved)
+		require.NoError(t, err)
+		require.Equal(t, "open", change.FolderUID)
+		require.Equal(t, 3, change.BaseVersion)
+		require.Equal(t, 3, change.Data.Get("version").MustInt())
+	})
+
+	t.Run("should list the changes without their dashboard", func(t *testing.T) {
+		changes, err := s.ListChanges(ctx, &dashboardapproval.ListQuery{OrgID: 1, Status: dashboardapproval.StatusPending})
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Nil(t, changes[0].Data)
+		require.Equal(t, DiffURL(changes[0].ID), changes[0].DiffURL)
+
+		changes, err = s.ListChanges(ctx, &dashboardapproval.ListQuery{OrgID: 1, DashboardUID: "moved"})
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+	})
+
+	t.Run("should compare the new dashboards with an empty dashboard", func(t *testing.T) {
+		change, err := s.GetChange(ctx, 1, created)
+		require.NoError(t, err)
+		dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{OrgID: 1, UID: change.DashboardUID}).Return(nil, dashboards.ErrDashboardNotFound)
+
+		result, err := s.Diff(ctx, change, dashdiffs.DiffJSON)
+		require.NoError(t, err)
+		require.Contains(t, string(result.Delta), "Production")
+	})
+
+	t.Run("should only let the reviewers of all the folders of the change review it", func(t *testing.T) {
+		dashboardService.On("GetDashboard", mock.Anything, &dashboards.GetDashboardQuery{OrgID: 1, UID: "moved"}).Return(&dashboards.Dashboard{ID: 5, UID: "moved", FolderID: 10, Version: 3}, nil)
+		_, err := s.Reject(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: moved, User: editor})
+		require.ErrorIs(t, err, dashboardapproval.ErrForbidden)
+
+		openReviewer := newUser(4, ac.Permission{Action: dashboards.ActionDashboardsApprove, Scope: dashboards.ScopeFoldersProvider.GetResourceScopeUID("open")})
+		_, err = s.Reject(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: moved, User: openReviewer})
+		require.ErrorIs(t, err, dashboardapproval.ErrForbidden)
+	})
+
+	t.Run("should reject the changes", func(t *testing.T) {
+		inboxService.Notified = nil
+		change, err := s.Reject(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: moved, User: approver, Comment: "keep it here"})
+		require.NoError(t, err)
+		require.Equal(t, dashboardapproval.StatusRejected, change.Status)
+		require.Equal(t, int64(2), change.ReviewedBy)
+
+		require.Len(t, inboxService.Notified, 1)
+		require.Equal(t, []int64{1}, inboxService.Notified[0].UserIDs)
+		require.Contains(t, inboxService.Notified[0].Body, "keep it here")
+
+		_, err = s.Approve(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: moved, User: approver})
+		require.ErrorIs(t, err, dashboardapproval.ErrAlreadyReviewed)
+	})
+
+	t.Run("should save the approved changes", func(t *testing.T) {
+		dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, true).Return(nil, dashboards.ErrDashboardVersionMismatch).Once()
+		_, err := s.Approve(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: created, User: approver})
+		require.ErrorIs(t, err, dashboardapproval.ErrChangeOutdated)
+
+		// the change is reviewed by someone else while the dashboard is saved, the save is rolled back
+		dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, true).Run(func(args mock.Arguments) {
+			concurrent := &dashboardapproval.PendingChange{ID: created, Status: dashboardapproval.StatusRejected, ReviewedBy: 3}
+			require.NoError(t, s.store.Review(args.Get(0).(context.Context), concurrent))
+		}).Return(&dashboards.Dashboard{Version: 1}, nil).Once()
+		_, err = s.Approve(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: created, User: approver})
+		require.ErrorIs(t, err, dashboardapproval.ErrAlreadyReviewed)
+		change, err := s.GetChange(ctx, 1, created)
+		require.NoError(t, err)
+		require.Equal(t, dashboardapproval.StatusPending, change.Status)
+
+		var saved *dashboards.SaveDashboardDTO
+		dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, true).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*dashboards.SaveDashboardDTO)
+		}).Return(&dashboards.Dashboard{Version: 1}, nil).Once()
+		change, err = s.Approve(ctx, &dashboardapproval.ReviewCommand{OrgID: 1, ID: created, User: approver})
+		require.NoError(t, err)
+		require.Equal(t, dashboardapproval.StatusApproved, change.Status)
+		require.Equal(t, 1, change.DashboardVersion)
+
+		require.Equal(t, approver, saved.User)
+		require.Equal(t, int64(10), saved.Dashboard.FolderID)
+		require.Equal(t, change.DashboardUID, saved.Dashboard.UID)
+		require.Equal(t, "more panels", saved.Message)
+
+		changes, err := s.ListChanges(ctx, &dashboardapproval.ListQuery{OrgID: 1, Status: dashboardapproval.StatusPending})
+		require.NoError(t, err)
+		require.Empty(t, changes)
+	})
+}
+
+type fakeFolderSettings map[string]*foldersettings.FolderSettings
+
+func (f fakeFolderSettings) Get(ctx context.Context, orgID int64, folderUID string) (*foldersettings.FolderSettings, error) {
+	if settings, ok := f[folderUID]; ok {
+		return settings, nil
+	}
+	return &foldersettings.FolderSettings{}, nil
+}
+
+func (f fakeFolderSettings) Set(ctx context.Context, cmd *foldersettings.SetCommand) error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package dashboardapprovalimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardapproval"
+)
+
+type store interface {
+	Insert(ctx context.Context, change *dashboardapproval.PendingChange) error
+	Get(ctx context.Context, orgID, id int64) (*dashboardapproval.PendingChange, error)
+	// List returns the changes matching the query, without their dashboard.
+	List(ctx context.Context, query *dashboardapproval.ListQuery) ([]*dashboardapproval.PendingChange, error)
+	// Review stores the review of a pending change, it fails if the change was reviewed in the meantime.
+	Review(ctx context.Context, change *dashboardapproval.PendingChange) error
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (s *sqlStore) Insert(ctx context.Context, change *dashboardapproval.PendingChange) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(change)
+		return err
+	})
+}
+
+func (s *sqlStore) Get(ctx context.Context, orgID, id int64) (*dashboardapproval.PendingChange, error) {
+	change := &dashboardapproval.PendingChange{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(change)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboardapproval.ErrChangeNotFound.Errorf("change %d not found in org %d", id, orgID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+func (s *sqlStore) List(ctx context.Context, query *dashboardapproval.ListQuery) ([]*dashboardapproval.PendingChange, error) {
+	changes := make([]*dashboardapproval.PendingChange, 0)
+	where := "org_id = ?"
+	args := []interface{}{query.OrgID}
+	if query.Status != "" {
+		where += " AND status = ?"
+		args = append(args, query.Status)
+	}
+	if query.DashboardUID != "" {
+		where += " AND dashboard_uid = ?"
+		args = append(args, query.DashboardUID)
+	}
+
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where(where, args...).Omit("data").Desc("created", "id").Limit(query.Limit).Find(&changes)
+	})
+	return changes, err
+}
+
+func (s *sqlStore) Review(ctx context.Context, change *dashboardapproval.PendingChange) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		updated, err := sess.Where("id = ? AND status = ?", change.ID, dashboardapproval.StatusPending).
+			Cols("status", "reviewed_by", "review_comment", "dashboard_version", "updated").
+			Update(change)
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return dashboardapproval.ErrAlreadyReviewed.Errorf("change %d was already reviewed", change.ID)
+		}
+		return nil
+	})
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/apierrors"
@@ -13,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/util/errutil"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -41,6 +44,16 @@ func (api *ImportDashboardAPI) RegisterAPIEndpoints(routeRegister routing.RouteR
 			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
 			routing.Wrap(api.ImportDashboard),
 		)
+		route.Post(
+			"/import/preflight",
+			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
+			routing.Wrap(api.PreflightImport),
+		)
+		route.Post(
+			"/import/bundle",
+			authorize(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsCreate)),
+			routing.Wrap(api.ImportDashboardBundle),
+		)
 	}, middleware.ReqSignedIn)
 }
 
@@ -77,12 +90,95 @@ func (api *ImportDashboardAPI) ImportDashboard(c *contextmodel.ReqContext) respo
 	req.User = c.SignedInUser
 	resp, err := api.dashboardImportService.ImportDashboard(c.Req.Context(), &req)
 	if err != nil {
-		return apierrors.ToDashboardErrorResponse(c.Req.Context(), api.pluginStore, err)
+		return api.toErrorResponse(c.Req.Context(), err)
 	}
 
 	return response.JSON(http.StatusOK, resp)
 }
 
+// swagger:route POST /dashboards/import/preflight dashboards preflightImportDashboard
+//
+// Validate a dashboard import.
+//
+// Resolves the inputs, datasource references and variables of the import request without importing the dashboard.
+//
+// Responses:
+// 200: preflightImportDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 422: unprocessableEntityError
+// 500: internalServerError
+func (api *ImportDashboardAPI) PreflightImport(c *contextmodel.ReqContext) response.Response {
+	req := dashboardimport.ImportDashboardRequest{}
+	if err := web.Bind(c.Req, &req); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	if req.PluginId == "" && req.Dashboard == nil {
+		return response.Error(http.StatusUnprocessableEntity, "Dashboard must be set", nil)
+	}
+
+	req.User = c.SignedInUser
+	report, err := api.dashboardImportService.PreflightImport(c.Req.Context(), &req)
+	if err != nil {
+		return api.toErrorResponse(c.Req.Context(), err)
+	}
+
+	return response.JSON(http.StatusOK, report)
+}
+
+// swagger:route POST /dashboards/import/bundle dashboards importDashboardBundle
+//
+// Import a bundle of dashboards.
+//
+// Either all the dashboards of the bundle are imported or none of them.
+//
+// Responses:
+// 200: importDashboardBundleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 412: preconditionFailedError
+// 422: unprocessableEntityError
+// 500: internalServerError
+func (api *ImportDashboardAPI) ImportDashboardBundle(c *contextmodel.ReqContext) response.Response {
+	req := dashboardimport.ImportDashboardBundleRequest{}
+	if err := web.Bind(c.Req, &req); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	for _, dash := range req.Dashboards {
+		if dash.PluginId == "" && dash.Dashboard == nil {
+			return response.Error(http.StatusUnprocessableEntity, "Dashboard must be set", nil)
+		}
+	}
+
+	limitReached, err := api.quotaService.QuotaReached(c, dashboards.QuotaTargetSrv)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if limitReached {
+		return response.Error(403, "Quota reached", nil)
+	}
+
+	req.User = c.SignedInUser
+	resp, err := api.dashboardImportService.ImportDashboardBundle(c.Req.Context(), &req)
+	if err != nil {
+		return api.toErrorResponse(c.Req.Context(), err)
+	}
+
+	return response.JSON(http.StatusOK, resp)
+}
+
+func (api *ImportDashboardAPI) toErrorResponse(ctx context.Context, err error) response.Response {
+	var grafanaErr errutil.Error
+	if errors.As(err, &grafanaErr) {
+		return response.Err(err)
+	}
+	return apierrors.ToDashboardErrorResponse(ctx, api.pluginStore, err)
+}
+
 type QuotaService interface {
 	QuotaReached(c *contextmodel.ReqContext, target quota.TargetSrv) (bool, error)
 }
@@ -105,3 +201,29 @@ type ImportDashboardResponse struct {
 	// in: body
 	Body dashboardimport.ImportDashboardResponse `json:"body"`
 }
+
+// swagger:parameters preflightImportDashboard
+type PreflightImportDashboardParams struct {
+	// in:body
+	// required:true
+	Body dashboardimport.ImportDashboardRequest
+}
+
+// swagger:response preflightImportDashboardResponse
+type PreflightImportDashboardResponse struct {
+	// in: body
+	Body dashboardimport.ImportPreflightReport `json:"body"`
+}
+
+// swagger:parameters importDashboardBundle
+type ImportDashboardBundleParams struct {
+	// in:body
+	// required:true
+	Body dashboardimport.ImportDashboardBundleRequest
+}
+
+// swagger:response importDashboardBundleResponse
+type ImportDashboardBundleResponse struct {
+	// in: body
+	Body dashboardimport.ImportDashboardBundleResponse `json:"body"`
+}
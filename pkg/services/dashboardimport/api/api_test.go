@@ -155,8 +155,82 @@ func TestImportDashboardAPI(t *testing.T) {
 	})
 }
 
+func TestImportDashboardAPI_PreflightAndBundle(t *testing.T) {
+	var bundleReq *dashboardimport.ImportDashboardBundleRequest
+	service := &serviceMock{
+		preflightImportFunc: func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportPreflightReport, error) {
+			if len(req.DatasourceMapping) == 0 {
+				return nil, dashboardimport.ErrInvalidDatasourceMapping.Errorf("no mapping")
+			}
+			return &dashboardimport.ImportPreflightReport{Ready: false, Unresolved: 1}, nil
+		},
+		importDashboardBundleFunc: func(ctx context.Context, req *dashboardimport.ImportDashboardBundleRequest) (*dashboardimport.ImportDashboardBundleResponse, error) {
+			bundleReq = req
+			return &dashboardimport.ImportDashboardBundleResponse{}, nil
+		},
+	}
+
+	importDashboardAPI := New(service, quotaServiceFunc(quotaNotReached), nil, acmock.New().WithDisabled())
+	routeRegister := routing.NewRouteRegister()
+	importDashboardAPI.RegisterAPIEndpoints(routeRegister)
+	s := webtest.NewServer(t, routeRegister)
+
+	send := func(t *testing.T, url string, cmd interface{}) *http.Response {
+		t.Helper()
+		jsonBytes, err := json.Marshal(cmd)
+		require.NoError(t, err)
+		req := s.NewPostRequest(url, bytes.NewReader(jsonBytes))
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1})
+		resp, err := s.SendJSON(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Preflight should return the report", func(t *testing.T) {
+		resp := send(t, "/api/dashboards/import/preflight", &dashboardimport.ImportDashboardRequest{
+			Dashboard:         simplejson.New(),
+			DatasourceMapping: []dashboardimport.DatasourceMapping{{Type: "prometheus", UID: "prom"}},
+		})
+		report := dashboardimport.ImportPreflightReport{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.False(t, report.Ready)
+		require.Equal(t, 1, report.Unresolved)
+	})
+
+	t.Run("Preflight with an invalid mapping should return bad request", func(t *testing.T) {
+		resp := send(t, "/api/dashboards/import/preflight", &dashboardimport.ImportDashboardRequest{Dashboard: simplejson.New()})
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Bundle with an empty dashboard should return error", func(t *testing.T) {
+		resp := send(t, "/api/dashboards/import/bundle", &dashboardimport.ImportDashboardBundleRequest{
+			Dashboards: []dashboardimport.ImportDashboardRequest{{Dashboard: simplejson.New()}, {}},
+		})
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		require.Nil(t, bundleReq)
+	})
+
+	t.Run("Bundle should call import dashboard bundle service", func(t *testing.T) {
+		resp := send(t, "/api/dashboards/import/bundle", &dashboardimport.ImportDashboardBundleRequest{
+			Dashboards: []dashboardimport.ImportDashboardRequest{{Dashboard: simplejson.New()}, {PluginId: "test-app", Path: "dashboard.json"}},
+			FolderUid:  "folder",
+		})
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NotNil(t, bundleReq)
+		require.Len(t, bundleReq.Dashboards, 2)
+		require.Equal(t, int64(1), bundleReq.User.UserID)
+	})
+}
+
 type serviceMock struct {
-	importDashboardFunc func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error)
+	importDashboardFunc       func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error)
+	preflightImportFunc       func(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportPreflightReport, error)
+	importDashboardBundleFunc func(ctx context.Context, req *dashboardimport.ImportDashboardBundleRequest) (*dashboardimport.ImportDashboardBundleResponse, error)
 }
 
 func (s *serviceMock) ImportDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
@@ -167,6 +241,22 @@ func (s *serviceMock) ImportDashboard(ctx context.Context, req *dashboardimport.
 	return nil, nil
 }
 
+func (s *serviceMock) PreflightImport(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportPreflightReport, error) {
+	if s.preflightImportFunc != nil {
+		return s.preflightImportFunc(ctx, req)
+	}
+
+	return nil, nil
+}
+
+func (s *serviceMock) ImportDashboardBundle(ctx context.Context, req *dashboardimport.ImportDashboardBundleRequest) (*dashboardimport.ImportDashboardBundleResponse, error) {
+	if s.importDashboardBundleFunc != nil {
+		return s.importDashboardBundleFunc(ctx, req)
+	}
+
+	return nil, nil
+}
+
 func quotaReached(c *contextmodel.ReqContext, target quota.TargetSrv) (bool, error) {
 	return true, nil
 }
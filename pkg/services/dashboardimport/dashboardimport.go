@@ -5,6 +5,13 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrUnresolvedInputs         = errutil.NewBase(errutil.StatusBadRequest, "dashboardimport.unresolvedInputs", errutil.WithPublicMessage("Some inputs of the dashboard could not be resolved"))
+	ErrInvalidDatasourceMapping = errutil.NewBase(errutil.StatusBadRequest, "dashboardimport.invalidDatasourceMapping")
+	ErrEmptyBundle              = errutil.NewBase(errutil.StatusBadRequest, "dashboardimport.emptyBundle", errutil.WithPublicMessage("The bundle must contain at least one dashboard"))
 )
 
 // ImportDashboardInput definition of input parameters when importing a dashboard.
@@ -24,10 +31,80 @@ type ImportDashboardRequest struct {
 	Inputs    []ImportDashboardInput `json:"inputs"`
 	FolderId  int64                  `json:"folderId"`
 	FolderUid string                 `json:"folderUid"`
+	// DatasourceMapping maps the datasources the dashboard was exported with to the datasources of the organization.
+	DatasourceMapping []DatasourceMapping `json:"datasourceMapping"`
+	// Variables sets the current value of the template variables of the dashboard, by variable name.
+	Variables map[string]string `json:"variables"`
+
+	User *user.SignedInUser `json:"-"`
+}
+
+// DatasourceMapping maps a datasource of the imported dashboard to a datasource of the organization.
+// A mapping without source applies to every datasource input of its type.
+type DatasourceMapping struct {
+	// Type is the plugin id of the mapped datasource.
+	Type string `json:"type"`
+	// Source is the name of a datasource input, e.g. DS_PROMETHEUS, or the UID of a datasource referenced by the dashboard.
+	Source string `json:"source"`
+	// UID is the UID of the datasource of the organization to use instead.
+	UID string `json:"uid"`
+}
+
+// ImportPreflightReport is the result of the validation of an import request, without importing the dashboard.
+type ImportPreflightReport struct {
+	Title      string               `json:"title"`
+	UID        string               `json:"uid"`
+	Ready      bool                 `json:"ready"`
+	Inputs     []PreflightInput     `json:"inputs"`
+	References []PreflightReference `json:"references"`
+	Variables  []PreflightVariable  `json:"variables"`
+	Unresolved int                  `json:"unresolved"`
+}
+
+// PreflightInput is the resolution of one of the `__inputs` of the dashboard.
+type PreflightInput struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	PluginId string `json:"pluginId"`
+	Value    string `json:"value"`
+	Resolved bool   `json:"resolved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// PreflightReference is a datasource referenced by UID in the dashboard. References that cannot be resolved
+// are reported but do not prevent the import, as the datasource can be created afterwards.
+type PreflightReference struct {
+	Type     string `json:"type"`
+	UID      string `json:"uid"`
+	Value    string `json:"value"`
+	Resolved bool   `json:"resolved"`
+}
+
+// PreflightVariable is a template variable bound by the import request.
+type PreflightVariable struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Resolved bool   `json:"resolved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ImportDashboardBundleRequest request object for importing several dashboards at once.
+// The dashboards inherit the folder, overwrite flag, datasource mapping and variables of the bundle unless they set their own.
+type ImportDashboardBundleRequest struct {
+	Dashboards        []ImportDashboardRequest `json:"dashboards"`
+	Overwrite         bool                     `json:"overwrite"`
+	FolderUid         string                   `json:"folderUid"`
+	DatasourceMapping []DatasourceMapping      `json:"datasourceMapping"`
+	Variables         map[string]string        `json:"variables"`
 
 	User *user.SignedInUser `json:"-"`
 }
 
+// ImportDashboardBundleResponse response object returned when importing a bundle of dashboards.
+type ImportDashboardBundleResponse struct {
+	Dashboards []*ImportDashboardResponse `json:"dashboards"`
+}
+
 // ImportDashboardResponse response object returned when importing a dashboard.
 type ImportDashboardResponse struct {
 	UID              string `json:"uid"`
@@ -50,4 +127,8 @@ type ImportDashboardResponse struct {
 // Service service interface for importing dashboards.
 type Service interface {
 	ImportDashboard(ctx context.Context, req *ImportDashboardRequest) (*ImportDashboardResponse, error)
+	// PreflightImport resolves the inputs, datasources and variables of the import request without importing the dashboard.
+	PreflightImport(ctx context.Context, req *ImportDashboardRequest) (*ImportPreflightReport, error)
+	// ImportDashboardBundle imports all the dashboards of the bundle, or none of them when one of them fails.
+	ImportDashboardBundle(ctx context.Context, req *ImportDashboardBundleRequest) (*ImportDashboardBundleResponse, error)
 }
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/dashboardimport"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// builtInDatasourceUIDs are the UIDs of the datasources that exist in every organization.
+var builtInDatasourceUIDs = map[string]bool{
+	"grafana":             true,
+	"-- Grafana --":       true,
+	"-- Mixed --":         true,
+	"-- Dashboard --":     true,
+	expr.DatasourceUID:    true,
+	expr.OldDatasourceUID: true,
+}
+
+// resolution is the outcome of the resolution of the inputs, datasource references and variables of an import request.
+type resolution struct {
+	report *dashboardimport.ImportPreflightReport
+	// inputs are the inputs of the request completed with the inputs resolved through the datasource mapping
+	inputs []dashboardimport.ImportDashboardInput
+	// references maps the UIDs of the datasources referenced by the dashboard to the UIDs they are replaced with
+	references map[string]string
+}
+
+type resolver struct {
+	dataSourceService datasources.DataSourceService
+	orgID             int64
+	datasources       map[string]*datasources.DataSource
+}
+
+func (r *resolver) getDatasource(ctx context.Context, uid string) (*datasources.DataSource, error) {
+	if ds, ok := r.datasources[uid]; ok {
+		return ds, nil
+	}
+
+	ds, err := r.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: r.orgID})
+	if err != nil && !errors.Is(err, datasources.ErrDataSourceNotFound) {
+		return nil, err
+	}
+	r.datasources[uid] = ds
+	return ds, nil
+}
+
+// resolve resolves the inputs of the dashboard with the inputs of the request first and the datasource mapping second.
+// It fails when the datasource mapping is invalid, unresolved inputs and variables are only reported.
+func (s *ImportDashboardService) resolve(ctx context.Context, orgID int64, template *simplejson.Json, req *dashboardimport.ImportDashboardRequest) (*resolution, error) {
+	r := &resolver{dataSourceService: s.dataSourceService, orgID: orgID, datasources: map[string]*datasources.DataSource{}}
+
+	for _, m := range req.DatasourceMapping {
+		if m.UID == "" || (m.Type == "" && m.Source == "") {
+			return nil, dashboardimport.ErrInvalidDatasourceMapping.Errorf("datasource mapping must have a uid and a type or a source")
+		}
+		ds, err := r.getDatasource(ctx, m.UID)
+		if err != nil {
+			return nil, err
+		}
+		if ds == nil {
+			return nil, dashboardimport.ErrInvalidDatasourceMapping.Errorf("datasource %q not found", m.UID)
+		}
+		if m.Type != "" && ds.Type != m.Type {
+			return nil, dashboardimport.ErrInvalidDatasourceMapping.Errorf("datasource %q is of type %q, not %q", m.UID, ds.Type, m.Type)
+		}
+	}
+
+	res := &resolution{
+		report: &dashboardimport.ImportPreflightReport{
+			Title:      template.Get("title").MustString(),
+			UID:        template.Get("uid").MustString(),
+			Inputs:     []dashboardimport.PreflightInput{},
+			References: []dashboardimport.PreflightReference{},
+			Variables:  []dashboardimport.PreflightVariable{},
+		},
+		inputs:     append([]dashboardimport.ImportDashboardInput{}, req.Inputs...),
+		references: map[string]string{},
+	}
+
+	for _, inputDef := range template.Get("__inputs").MustArray() {
+		inputDefJson := simplejson.NewFromAny(inputDef)
+		input := dashboardimport.PreflightInput{
+			Name:     inputDefJson.Get("name").MustString(),
+			Type:     inputDefJson.Get("type").MustString(),
+			PluginId: inputDefJson.Get("pluginId").MustString(),
+		}
+
+		if input.PluginId == expr.DatasourceType {
+			input.Value, input.Resolved = expr.DatasourceType, true
+		} else if value, ok := findInput(req.Inputs, input.Name, input.Type); ok {
+			input.Value, input.Resolved = value, true
+		} else if m, ok := findMapping(req.DatasourceMapping, input.Name, input.PluginId); ok && input.Type == "datasource" {
+			input.Value, input.Resolved = m.UID, true
+			res.inputs = append(res.inputs, dashboardimport.ImportDashboardInput{Name: input.Name, Type: input.Type, PluginId: input.PluginId, Value: m.UID})
+		} else {
+			input.Reason = "missing from the inputs and the datasource mapping"
+		}
+
+		if !input.Resolved {
+			res.report.Unresolved++
+		}
+		res.report.Inputs = append(res.report.Inputs, input)
+	}
+
+	refs := map[string]string{}
+	walkDatasourceRefs(template.Interface(), func(ref map[string]interface{}) {
+		uid, _ := ref["uid"].(string)
+		if uid == "" || strings.HasPrefix(uid, "$") || builtInDatasourceUIDs[uid] {
+			return
+		}
+		if _, ok := refs[uid]; !ok {
+			refs[uid], _ = ref["type"].(string)
+		}
+	})
+	uids := make([]string, 0, len(refs))
+	for uid := range refs {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+
+	for _, uid := range uids {
+		ref := dashboardimport.PreflightReference{Type: refs[uid], UID: uid, Value: uid}
+		if m, ok := findMapping(req.DatasourceMapping, uid, ""); ok && (m.Type == "" || ref.Type == "" || m.Type == ref.Type) {
+			ref.Value, ref.Resolved = m.UID, true
+			res.references[uid] = m.UID
+		} else {
+			ds, err := r.getDatasource(ctx, uid)
+			if err != nil {
+				return nil, err
+			}
+			ref.Resolved = ds != nil
+		}
+		res.report.References = append(res.report.References, ref)
+	}
+
+	names := make([]string, 0, len(req.Variables))
+	for name := range req.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		variable := dashboardimport.PreflightVariable{Name: name, Value: req.Variables[name]}
+		if findVariable(template, name) != nil {
+			variable.Resolved = true
+		} else {
+			variable.Reason = "the dashboard has no such variable"
+			res.report.Unresolved++
+		}
+		res.report.Variables = append(res.report.Variables, variable)
+	}
+
+	res.report.Ready = res.report.Unresolved == 0
+	return res, nil
+}
+
+// findInput matches the inputs the same way as the dashboard template evaluator.
+func findInput(inputs []dashboardimport.ImportDashboardInput, name, inputType string) (string, bool) {
+	for _, input := range inputs {
+		if inputType == input.Type && (input.Name == name || input.Name == "*") {
+			return input.Value, true
+		}
+	}
+	return "", false
+}
+
+// findMapping prefers the mapping of the source over the mapping of the type.
+func findMapping(mapping []dashboardimport.DatasourceMapping, source, pluginID string) (dashboardimport.DatasourceMapping, bool) {
+	for _, m := range mapping {
+		if m.Source == source {
+			return m, true
+		}
+	}
+	if pluginID == "" {
+		return dashboardimport.DatasourceMapping{}, false
+	}
+	for _, m := range mapping {
+		if m.Source == "" && m.Type == pluginID {
+			return m, true
+		}
+	}
+	return dashboardimport.DatasourceMapping{}, false
+}
+
+func findVariable(dash *simplejson.Json, name string) map[string]interface{} {
+	for _, v := range dash.Get("templating").Get("list").MustArray() {
+		if variable, ok := v.(map[string]interface{}); ok && variable["name"] == name {
+			return variable
+		}
+	}
+	return nil
+}
+
+// walkDatasourceRefs calls fn with every datasource reference, i.e. {"type": "...", "uid": "..."}, of the dashboard.
+func walkDatasourceRefs(value interface{}, fn func(ref map[string]interface{})) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(map[string]interface{}); ok && key == "datasource" {
+				fn(ref)
+			}
+			walkDatasourceRefs(child, fn)
+		}
+	case []interface{}:
+		for _, child := range v {
+			walkDatasourceRefs(child, fn)
+		}
+	}
+}
+
+// applyResolution replaces the mapped datasource references and binds the variables of the evaluated dashboard.
+func applyResolution(dash *simplejson.Json, res *resolution, variables map[string]string) {
+	if len(res.references) > 0 {
+		walkDatasourceRefs(dash.Interface(), func(ref map[string]interface{}) {
+			if uid, ok := ref["uid"].(string); ok {
+				if target, ok := res.references[uid]; ok {
+					ref["uid"] = target
+				}
+			}
+		})
+	}
+
+	for name, value := range variables {
+		variable := findVariable(dash, name)
+		if variable == nil {
+			continue
+		}
+		variable["current"] = map[string]interface{}{"selected": true, "text": value, "value": value}
+		// the value of constant and text box variables is their query
+		if t, _ := variable["type"].(string); t == "constant" || t == "textbox" {
+			variable["query"] = value
+		}
+	}
+}
@@ -2,15 +2,18 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
 	"github.com/grafana/grafana/pkg/services/dashboardimport/api"
 	"github.com/grafana/grafana/pkg/services/dashboardimport/utils"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/librarypanels"
 	"github.com/grafana/grafana/pkg/services/plugindashboards"
@@ -22,12 +25,15 @@ func ProvideService(routeRegister routing.RouteRegister,
 	pluginDashboardService plugindashboards.Service, pluginStore plugins.Store,
 	libraryPanelService librarypanels.Service, dashboardService dashboards.DashboardService,
 	ac accesscontrol.AccessControl, folderService folder.Service,
+	dataSourceService datasources.DataSourceService, sqlStore db.DB,
 ) *ImportDashboardService {
 	s := &ImportDashboardService{
 		pluginDashboardService: pluginDashboardService,
 		dashboardService:       dashboardService,
 		libraryPanelService:    libraryPanelService,
 		folderService:          folderService,
+		dataSourceService:      dataSourceService,
+		store:                  sqlStore,
 	}
 
 	dashboardImportAPI := api.New(s, quotaService, pluginStore, ac)
@@ -41,29 +47,58 @@ type ImportDashboardService struct {
 	dashboardService       dashboards.DashboardService
 	libraryPanelService    librarypanels.Service
 	folderService          folder.Service
+	dataSourceService      datasources.DataSourceService
+	store                  db.DB
+}
+
+func (s *ImportDashboardService) loadDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboards.Dashboard, error) {
+	if req.PluginId == "" {
+		return dashboards.NewDashboardFromJson(req.Dashboard), nil
+	}
+
+	resp, err := s.pluginDashboardService.LoadPluginDashboard(ctx, &plugindashboards.LoadPluginDashboardRequest{
+		PluginID:  req.PluginId,
+		Reference: req.Path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Dashboard, nil
+}
+
+func (s *ImportDashboardService) PreflightImport(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportPreflightReport, error) {
+	draftDashboard, err := s.loadDashboard(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.resolve(ctx, req.User.OrgID, draftDashboard.Data, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.report, nil
 }
 
 func (s *ImportDashboardService) ImportDashboard(ctx context.Context, req *dashboardimport.ImportDashboardRequest) (*dashboardimport.ImportDashboardResponse, error) {
-	var draftDashboard *dashboards.Dashboard
-	if req.PluginId != "" {
-		loadReq := &plugindashboards.LoadPluginDashboardRequest{
-			PluginID:  req.PluginId,
-			Reference: req.Path,
-		}
-		if resp, err := s.pluginDashboardService.LoadPluginDashboard(ctx, loadReq); err != nil {
-			return nil, err
-		} else {
-			draftDashboard = resp.Dashboard
-		}
-	} else {
-		draftDashboard = dashboards.NewDashboardFromJson(req.Dashboard)
+	draftDashboard, err := s.loadDashboard(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
-	evaluator := utils.NewDashTemplateEvaluator(draftDashboard.Data, req.Inputs)
+	res, err := s.resolve(ctx, req.User.OrgID, draftDashboard.Data, req)
+	if err != nil {
+		return nil, err
+	}
+	if !res.report.Ready {
+		return nil, dashboardimport.ErrUnresolvedInputs.Errorf("%d inputs or variables of dashboard %q could not be resolved", res.report.Unresolved, res.report.Title)
+	}
+
+	evaluator := utils.NewDashTemplateEvaluator(draftDashboard.Data, res.inputs)
 	generatedDash, err := evaluator.Eval()
 	if err != nil {
 		return nil, err
 	}
+	applyResolution(generatedDash, res, req.Variables)
 
 	// Maintain backwards compatibility by transforming array of library elements to map
 	libraryElements := generatedDash.Get("__elements")
@@ -153,3 +188,52 @@ func (s *ImportDashboardService) ImportDashboard(ctx context.Context, req *dashb
 		Slug:             savedDashboard.Slug,
 	}, nil
 }
+
+func (s *ImportDashboardService) ImportDashboardBundle(ctx context.Context, req *dashboardimport.ImportDashboardBundleRequest) (*dashboardimport.ImportDashboardBundleResponse, error) {
+	if len(req.Dashboards) == 0 {
+		return nil, dashboardimport.ErrEmptyBundle.Errorf("no dashboards to import")
+	}
+
+	result := &dashboardimport.ImportDashboardBundleResponse{
+		Dashboards: make([]*dashboardimport.ImportDashboardResponse, 0, len(req.Dashboards)),
+	}
+	err := s.store.InTransaction(ctx, func(ctx context.Context) error {
+		for i := range req.Dashboards {
+			dashReq := bundleDashboardRequest(req, req.Dashboards[i])
+			resp, err := s.ImportDashboard(ctx, &dashReq)
+			if err != nil {
+				return fmt.Errorf("failed to import dashboard %d of the bundle: %w", i, err)
+			}
+			result.Dashboards = append(result.Dashboards, resp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// bundleDashboardRequest completes the request of a dashboard of the bundle with the settings of the bundle.
+func bundleDashboardRequest(bundle *dashboardimport.ImportDashboardBundleRequest, req dashboardimport.ImportDashboardRequest) dashboardimport.ImportDashboardRequest {
+	req.User = bundle.User
+	req.Overwrite = req.Overwrite || bundle.Overwrite
+	if req.FolderUid == "" && req.FolderId == 0 {
+		req.FolderUid = bundle.FolderUid
+	}
+
+	// the mapping of the dashboard comes first so that it takes precedence over the mapping of the bundle
+	req.DatasourceMapping = append(append([]dashboardimport.DatasourceMapping{}, req.DatasourceMapping...), bundle.DatasourceMapping...)
+
+	variables := make(map[string]string, len(bundle.Variables)+len(req.Variables))
+	for name, value := range bundle.Variables {
+		variables[name] = value
+	}
+	for name, value := range req.Variables {
+		variables[name] = value
+	}
+	req.Variables = variables
+
+	return req
+}
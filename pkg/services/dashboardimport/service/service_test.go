@@ -9,8 +9,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/dashboardimport"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/folder/foldertest"
 	"github.com/grafana/grafana/pkg/services/librarypanels"
@@ -158,6 +161,188 @@ func TestImportDashboardService(t *testing.T) {
 	})
 }
 
+func TestImportDashboardService_DatasourceMapping(t *testing.T) {
+	newTemplate := func(t *testing.T) *simplejson.Json {
+		dash, err := simplejson.NewJson([]byte(`{
+			"__inputs": [
+				{"name": "DS_PROM", "type": "datasource", "pluginId": "prometheus"},
+				{"name": "DS_LOKI", "type": "datasource", "pluginId": "loki"}
+			],
+			"title": "Mapped",
+			"uid": "mapped",
+			"panels": [
+				{"id": 1, "datasource": {"type": "prometheus", "uid": "${DS_PROM}"}},
+				{"id": 2, "datasource": {"type": "loki", "uid": "${DS_LOKI}"}},
+				{"id": 3, "datasource": {"type": "tempo", "uid": "exported-tempo"}},
+				{"id": 4, "datasource": {"type": "graphite", "uid": "unknown-graphite"}},
+				{"id": 5, "datasource": {"type": "datasource", "uid": "grafana"}}
+			],
+			"templating": {"list": [
+				{"name": "env", "type": "constant", "query": "dev"},
+				{"name": "instance", "type": "query"}
+			]}
+		}`))
+		require.NoError(t, err)
+		return dash
+	}
+
+	var importDashboardArgs []*dashboards.SaveDashboardDTO
+	newService := func() *ImportDashboardService {
+		importDashboardArgs = nil
+		return &ImportDashboardService{
+			dashboardService: &dashboardServiceMock{
+				importDashboardFunc: func(ctx context.Context, dto *dashboards.SaveDashboardDTO) (*dashboards.Dashboard, error) {
+					importDashboardArgs = append(importDashboardArgs, dto)
+					if dto.Dashboard.Title == "Failing" {
+						return nil, dashboards.ErrDashboardWithSameUIDExists
+					}
+					return dto.Dashboard, nil
+				},
+			},
+			libraryPanelService: &libraryPanelServiceMock{},
+			folderService:       &foldertest.FakeService{ExpectedFolder: &folder.Folder{ID: 5, UID: "folder"}},
+			dataSourceService: &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+				{OrgID: 1, UID: "prom", Type: "prometheus"},
+				{OrgID: 1, UID: "loki", Type: "loki"},
+				{OrgID: 1, UID: "tempo", Type: "tempo"},
+			}},
+			store: &transactionDB{},
+		}
+	}
+	signedInUser := &user.SignedInUser{UserID: 2, OrgRole: org.RoleAdmin, OrgID: 1}
+
+	t.Run("should report the unresolved inputs and variables", func(t *testing.T) {
+		report, err := newService().PreflightImport(context.Background(), &dashboardimport.ImportDashboardRequest{
+			Dashboard:         newTemplate(t),
+			DatasourceMapping: []dashboardimport.DatasourceMapping{{Type: "prometheus", UID: "prom"}},
+			Variables:         map[string]string{"env": "prod", "region": "eu"},
+			User:              signedInUser,
+		})
+		require.NoError(t, err)
+		require.False(t, report.Ready)
+		require.Equal(t, 2, report.Unresolved)
+		require.Equal(t, []dashboardimport.PreflightInput{
+			{Name: "DS_PROM", Type: "datasource", PluginId: "prometheus", Value: "prom", Resolved: true},
+			{Name: "DS_LOKI", Type: "datasource", PluginId: "loki", Reason: "missing from the inputs and the datasource mapping"},
+		}, report.Inputs)
+		require.Equal(t, []dashboardimport.PreflightReference{
+			{Type: "tempo", UID: "exported-tempo", Value: "exported-tempo"},
+			{Type: "graphite", UID: "unknown-graphite", Value: "unknown-graphite"},
+		}, report.References)
+		require.Equal(t, []dashboardimport.PreflightVariable{
+			{Name: "env", Value: "prod", Resolved: true},
+			{Name: "region", Value: "eu", Reason: "the dashboard has no such variable"},
+		}, report.Variables)
+	})
+
+	t.Run("should reject mappings to unknown datasources or datasources of another type", func(t *testing.T) {
+		for _, mapping := range []dashboardimport.DatasourceMapping{
+			{Type: "prometheus", UID: "missing"},
+			{Type: "prometheus", UID: "loki"},
+			{UID: "prom"},
+		} {
+			_, err := newService().PreflightImport(context.Background(), &dashboardimport.ImportDashboardRequest{
+				Dashboard:         newTemplate(t),
+				DatasourceMapping: []dashboardimport.DatasourceMapping{mapping},
+				User:              signedInUser,
+			})
+			require.ErrorIs(t, err, dashboardimport.ErrInvalidDatasourceMapping)
+		}
+	})
+
+	t.Run("should not import dashboards with unresolved inputs", func(t *testing.T) {
+		_, err := newService().ImportDashboard(context.Background(), &dashboardimport.ImportDashboardRequest{
+			Dashboard:         newTemplate(t),
+			DatasourceMapping: []dashboardimport.DatasourceMapping{{Type: "prometheus", UID: "prom"}},
+			User:              signedInUser,
+		})
+		require.ErrorIs(t, err, dashboardimport.ErrUnresolvedInputs)
+		require.Empty(t, importDashboardArgs)
+	})
+
+	t.Run("should map the datasources and bind the variables", func(t *testing.T) {
+		s := newService()
+		_, err := s.ImportDashboard(context.Background(), &dashboardimport.ImportDashboardRequest{
+			Dashboard: newTemplate(t),
+			Inputs:    []dashboardimport.ImportDashboardInput{{Name: "DS_LOKI", Type: "datasource", PluginId: "loki", Value: "other-loki"}},
+			DatasourceMapping: []dashboardimport.DatasourceMapping{
+				{Type: "prometheus", UID: "prom"},
+				{Type: "loki", UID: "loki"},
+				{Source: "exported-tempo", UID: "tempo"},
+			},
+			Variables: map[string]string{"env": "prod", "instance": "host-1"},
+			User:      signedInUser,
+		})
+		require.NoError(t, err)
+		require.Len(t, importDashboardArgs, 1)
+
+		data := importDashboardArgs[0].Dashboard.Data
+		panels := data.Get("panels")
+		require.Equal(t, "prom", panels.GetIndex(0).GetPath("datasource", "uid").MustString())
+		// explicit inputs take precedence over the mapping
+		require.Equal(t, "other-loki", panels.GetIndex(1).GetPath("datasource", "uid").MustString())
+		require.Equal(t, "tempo", panels.GetIndex(2).GetPath("datasource", "uid").MustString())
+		require.Equal(t, "unknown-graphite", panels.GetIndex(3).GetPath("datasource", "uid").MustString())
+		require.Equal(t, "grafana", panels.GetIndex(4).GetPath("datasource", "uid").MustString())
+
+		variables := data.GetPath("templating", "list")
+		require.Equal(t, "prod", variables.GetIndex(0).Get("query").MustString())
+		require.Equal(t, "prod", variables.GetIndex(0).GetPath("current", "value").MustString())
+		require.Equal(t, "host-1", variables.GetIndex(1).GetPath("current", "value").MustString())
+		require.Equal(t, "", variables.GetIndex(1).Get("query").MustString())
+	})
+
+	t.Run("should import the bundle in a transaction with the settings of the bundle", func(t *testing.T) {
+		s := newService()
+		other := newTemplate(t)
+		other.Set("uid", "other")
+		resp, err := s.ImportDashboardBundle(context.Background(), &dashboardimport.ImportDashboardBundleRequest{
+			Dashboards: []dashboardimport.ImportDashboardRequest{
+				{Dashboard: newTemplate(t)},
+				{Dashboard: other, DatasourceMapping: []dashboardimport.DatasourceMapping{{Source: "DS_PROM", UID: "tempo"}}, Variables: map[string]string{"env": "staging"}},
+			},
+			Overwrite: true,
+			FolderUid: "folder",
+			DatasourceMapping: []dashboardimport.DatasourceMapping{
+				{Type: "prometheus", UID: "prom"},
+				{Type: "loki", UID: "loki"},
+			},
+			Variables: map[string]string{"env": "prod"},
+			User:      signedInUser,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Dashboards, 2)
+		require.True(t, s.store.(*transactionDB).called)
+
+		require.Len(t, importDashboardArgs, 2)
+		require.True(t, importDashboardArgs[0].Overwrite)
+		require.Equal(t, int64(5), importDashboardArgs[0].Dashboard.FolderID)
+		require.Equal(t, "prom", importDashboardArgs[0].Dashboard.Data.Get("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+		require.Equal(t, "prod", importDashboardArgs[0].Dashboard.Data.GetPath("templating", "list").GetIndex(0).Get("query").MustString())
+		require.Equal(t, "tempo", importDashboardArgs[1].Dashboard.Data.Get("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+		require.Equal(t, "staging", importDashboardArgs[1].Dashboard.Data.GetPath("templating", "list").GetIndex(0).Get("query").MustString())
+	})
+
+	t.Run("should fail the bundle when a dashboard fails", func(t *testing.T) {
+		failing := newTemplate(t)
+		failing.Set("title", "Failing")
+		_, err := newService().ImportDashboardBundle(context.Background(), &dashboardimport.ImportDashboardBundleRequest{
+			Dashboards: []dashboardimport.ImportDashboardRequest{{Dashboard: newTemplate(t)}, {Dashboard: failing}},
+			DatasourceMapping: []dashboardimport.DatasourceMapping{
+				{Type: "prometheus", UID: "prom"},
+				{Type: "loki", UID: "loki"},
+			},
+			User: signedInUser,
+		})
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameUIDExists)
+	})
+
+	t.Run("should not import empty bundles", func(t *testing.T) {
+		_, err := newService().ImportDashboardBundle(context.Background(), &dashboardimport.ImportDashboardBundleRequest{User: signedInUser})
+		require.ErrorIs(t, err, dashboardimport.ErrEmptyBundle)
+	})
+}
+
 func loadTestDashboard(ctx context.Context, req *plugindashboards.LoadPluginDashboardRequest) (*plugindashboards.LoadPluginDashboardResponse, error) {
 	// It's safe to ignore gosec warning G304 since this is a test and arguments comes from test configuration.
 	// nolint:gosec
@@ -223,3 +408,13 @@ func (s *libraryPanelServiceMock) ImportLibraryPanelsForDashboard(ctx context.Co
 
 	return nil
 }
+
+type transactionDB struct {
+	db.DB
+	called bool
+}
+
+func (f *transactionDB) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.called = true
+	return fn(ctx)
+}
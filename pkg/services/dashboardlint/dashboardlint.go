@@ -0,0 +1,89 @@
+package dashboardlint
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Service checks the dashboards against a registry of lint rules.
+type Service interface {
+	// RegisterRule adds a rule to the registry, replacing the rule with the same id. The rules must be
+	// registered when the services are initialized.
+	RegisterRule(rule Rule)
+	// Rules returns the enabled rules, in the order they are checked.
+	Rules() []Rule
+	// Lint returns the findings of the enabled rules in a dashboard model.
+	Lint(dashboard *simplejson.Json) []Finding
+	// LintFolder returns the findings of the dashboards of a folder that the user can read.
+	LintFolder(ctx context.Context, user *user.SignedInUser, folderUID string) ([]DashboardFindings, error)
+}
+
+var (
+	ErrFolderNotFound   = errutil.NewBase(errutil.StatusNotFound, "dashboardlint.folder-not-found", errutil.WithPublicMessage("Folder not found"))
+	ErrInvalidDashboard = errutil.NewBase(errutil.StatusBadRequest, "dashboardlint.invalid-dashboard", errutil.WithPublicMessage("Dashboard model is required"))
+
+	ErrDashboardRejected = errutil.NewBase(errutil.StatusValidationFailed, "dashboardlint.rejected").MustTemplate(
+		"dashboard {{ .Private.UID }} rejected by {{ .Public.count }} lint findings",
+		errutil.WithPublic("Dashboard rejected by {{ .Public.count }} lint findings"),
+	)
+)
+
+// Severity is the severity of a finding, from the least to the most severe: info, warning and error.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+var severityLevels = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// IsValid returns true if the severity is known.
+func (s Severity) IsValid() bool {
+	return severityLevels[s] > 0
+}
+
+// AtLeast returns true if the severity is as severe as other or more.
+func (s Severity) AtLeast(other Severity) bool {
+	return severityLevels[s] >= severityLevels[other]
+}
+
+// Rule is a check of the dashboard models.
+type Rule struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Severity    Severity `json:"severity"`
+	// Check returns the findings of the rule in a dashboard model, the service sets their rule id and severity.
+	Check func(dashboard *simplejson.Json) []Finding `json:"-"`
+}
+
+// Finding is a problem found by a rule in a dashboard.
+type Finding struct {
+	RuleID   string   `json:"ruleId"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// PanelID is the id of the panel of the finding, zero for the findings of the dashboard.
+	PanelID int64 `json:"panelId,omitempty"`
+	// Path is the JSON path of the element of the finding in the dashboard model, e.g. panels[2].panels[0].
+	Path string `json:"path,omitempty"`
+}
+
+// DashboardFindings are the findings of a saved dashboard.
+type DashboardFindings struct {
+	UID      string    `json:"uid"`
+	Title    string    `json:"title"`
+	Findings []Finding `json:"findings"`
+}
+
+// LintCommand is the body of the requests linting a dashboard model.
+type LintCommand struct {
+	Dashboard *simplejson.Json `json:"dashboard"`
+}
@@ -0,0 +1,65 @@
+package dashboardlintimpl
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	dashboardUIDScope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+	folderUIDScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	routeRegister.Group("/api/dashboards", func(dashboardRoute routing.RouteRegister) {
+		dashboardRoute.Get("/lint/rules", middleware.ReqSignedIn, routing.Wrap(s.handleGetRules))
+		dashboardRoute.Post("/lint", middleware.ReqSignedIn, routing.Wrap(s.handleLint))
+		dashboardRoute.Get("/uid/:uid/lint", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionDashboardsRead, dashboardUIDScope)), routing.Wrap(s.handleLintDashboard))
+	})
+	routeRegister.Get("/api/folders/:uid/lint", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionFoldersRead, folderUIDScope)), routing.Wrap(s.handleLintFolder))
+}
+
+func (s *Service) handleGetRules(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, s.Rules())
+}
+
+func (s *Service) handleLint(c *contextmodel.ReqContext) response.Response {
+	cmd := dashboardlint.LintCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if cmd.Dashboard == nil {
+		return response.Err(dashboardlint.ErrInvalidDashboard.Errorf("the request has no dashboard model"))
+	}
+
+	return response.JSON(http.StatusOK, map[string]interface{}{"findings": s.Lint(cmd.Dashboard)})
+}
+
+func (s *Service) handleLintDashboard(c *contextmodel.ReqContext) response.Response {
+	dash, err := s.dashboardService.GetDashboard(c.Req.Context(), &dashboards.GetDashboardQuery{OrgID: c.OrgID, UID: web.Params(c.Req)[":uid"]})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard", err)
+	}
+
+	return response.JSON(http.StatusOK, dashboardlint.DashboardFindings{UID: dash.UID, Title: dash.Title, Findings: s.Lint(dash.Data)})
+}
+
+func (s *Service) handleLintFolder(c *contextmodel.ReqContext) response.Response {
+	result, err := s.LintFolder(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to lint folder dashboards", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
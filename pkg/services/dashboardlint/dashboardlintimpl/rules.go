@@ -0,0 +1,109 @@
+package dashboardlintimpl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/pluginmigration"
+)
+
+// builtinRules are registered by the service before the rules of the other services.
+func builtinRules() []dashboardlint.Rule {
+	return []dashboardlint.Rule{
+		{
+			ID:          "panel-datasource-uid",
+			Description: "Panels with queries must reference their data source by UID",
+			Severity:    dashboardlint.SeverityWarning,
+			Check:       checkPanelDatasourceUID,
+		},
+		{
+			ID:          "angular-panel",
+			Description: "Panels must not use deprecated panel types, most of them built with AngularJS",
+			Severity:    dashboardlint.SeverityWarning,
+			Check:       checkAngularPanels,
+		},
+		{
+			ID:          "dashboard-description",
+			Description: "Dashboards must have a description",
+			Severity:    dashboardlint.SeverityInfo,
+			Check:       checkDashboardDescription,
+		},
+	}
+}
+
+// checkPanelDatasourceUID reports the panels with targets whose data source is missing, or referenced by name as
+// in the dashboards created before Grafana 8.3. The panels inheriting the dashboard default data source are reported,
+// their queries changing with the default data source of the organization.
+func checkPanelDatasourceUID(dashboard *simplejson.Json) []dashboardlint.Finding {
+	var findings []dashboardlint.Finding
+	forEachPanel(dashboard, func(panel *simplejson.Json, path string) {
+		if len(panel.Get("targets").MustArray()) == 0 {
+			return
+		}
+		ds, ok := panel.CheckGet("datasource")
+		switch {
+		case !ok || ds.Interface() == nil:
+			findings = append(findings, panelFinding(panel, path, "Panel %q has no data source", panelTitle(panel)))
+		case ds.Get("uid").MustString() == "":
+			findings = append(findings, panelFinding(panel, path, "Panel %q references its data source without UID", panelTitle(panel)))
+		}
+	})
+	return findings
+}
+
+// checkAngularPanels reports the panels that the plugin migrations can migrate to their successors.
+func checkAngularPanels(dashboard *simplejson.Json) []dashboardlint.Finding {
+	var findings []dashboardlint.Finding
+	forEachPanel(dashboard, func(panel *simplejson.Json, path string) {
+		panelType := panel.Get("type").MustString()
+		if replacement, ok := pluginmigration.DeprecatedPanelTypes[panelType]; ok {
+			findings = append(findings, panelFinding(panel, path, "Panel %q uses the deprecated %s panel, use the %s panel instead", panelTitle(panel), panelType, replacement))
+		}
+	})
+	return findings
+}
+
+func checkDashboardDescription(dashboard *simplejson.Json) []dashboardlint.Finding {
+	if strings.TrimSpace(dashboard.Get("description").MustString()) != "" {
+		return nil
+	}
+	return []dashboardlint.Finding{{Message: "Dashboard has no description"}}
+}
+
+// forEachPanel calls fn with the panels of the dashboard and the panels of its collapsed rows. The library panels are
+// skipped, their model is only a reference in the dashboard.
+func forEachPanel(dashboard *simplejson.Json, fn func(panel *simplejson.Json, path string)) {
+	var walk func(parent *simplejson.Json, prefix string)
+	walk = func(parent *simplejson.Json, prefix string) {
+		for i := range parent.Get("panels").MustArray() {
+			panel := parent.Get("panels").GetIndex(i)
+			path := fmt.Sprintf("%spanels[%d]", prefix, i)
+			if panel.Get("type").MustString() == "row" {
+				walk(panel, path+".")
+				continue
+			}
+			if _, ok := panel.CheckGet("libraryPanel"); ok {
+				continue
+			}
+			fn(panel, path)
+		}
+	}
+	walk(dashboard, "")
+}
+
+func panelFinding(panel *simplejson.Json, path string, format string, args ...interface{}) dashboardlint.Finding {
+	return dashboardlint.Finding{
+		Message: fmt.Sprintf(format, args...),
+		PanelID: panel.Get("id").MustInt64(),
+		Path:    path,
+	}
+}
+
+func panelTitle(panel *simplejson.Json) string {
+	if title := panel.Get("title").MustString(); title != "" {
+		return title
+	}
+	return fmt.Sprintf("#%d", panel.Get("id").MustInt64())
+}
@@ -0,0 +1,187 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// folderPageSize is the number of dashboards of a folder linted at once.
+const folderPageSize = 100
+
+type Service struct {
+	folderStore      folder.FolderStore
+	dashboardService dashboards.DashboardService
+	accessControl    ac.AccessControl
+	log              log.Logger
+
+	// onSaveSeverity is the severity of the findings rejecting the saved dashboards, empty to save them all.
+	onSaveSeverity dashboardlint.Severity
+	disabled       map[string]bool
+
+	mu    sync.RWMutex
+	rules []dashboardlint.Rule
+}
+
+var _ dashboardlint.Service = (*Service)(nil)
+
+func ProvideService(
+	cfg *setting.Cfg,
+	folderStore folder.FolderStore,
+	dashboardService *dashboardservice.DashboardServiceImpl,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := newService(cfg, folderStore, dashboardService, accessControl)
+
+	dashboardService.RegisterSaveHook(s.lintOnSave)
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+func newService(cfg *setting.Cfg, folderStore folder.FolderStore, dashboardService dashboards.DashboardService, accessControl ac.AccessControl) *Service {
+	s := &Service{
+		folderStore:      folderStore,
+		dashboardService: dashboardService,
+		accessControl:    accessControl,
+		log:              log.New("dashboardlint"),
+		disabled:         make(map[string]bool, len(cfg.DashboardLintDisabledRules)),
+	}
+
+	if severity := dashboardlint.Severity(cfg.DashboardLintOnSaveSeverity); severity != "" {
+		if severity.IsValid() {
+			s.onSaveSeverity = severity
+		} else {
+			s.log.Warn("Unknown lint severity, the dashboards are not linted on save", "severity", severity)
+		}
+	}
+	for _, id := range cfg.DashboardLintDisabledRules {
+		s.disabled[id] = true
+	}
+	for _, rule := range builtinRules() {
+		s.RegisterRule(rule)
+	}
+
+	return s
+}
+
+func (s *Service) RegisterRule(rule dashboardlint.Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, r := range s.rules {
+		if r.ID == rule.ID {
+			s.rules[i] = rule
+			return
+		}
+	}
+	s.rules = append(s.rules, rule)
+}
+
+func (s *Service) Rules() []dashboardlint.Rule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := make([]dashboardlint.Rule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		if !s.disabled[rule.ID] {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (s *Service) Lint(dashboard *simplejson.Json) []dashboardlint.Finding {
+	findings := []dashboardlint.Finding{}
+	for _, rule := range s.Rules() {
+		for _, finding := range rule.Check(dashboard) {
+			finding.RuleID = rule.ID
+			finding.Severity = rule.Severity
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func (s *Service) LintFolder(ctx context.Context, u *user.SignedInUser, folderUID string) ([]dashboardlint.DashboardFindings, error) {
+	f, err := s.folderStore.GetFolderByUID(ctx, u.OrgID, folderUID)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrFolderNotFound) {
+			return nil, dashboardlint.ErrFolderNotFound.Errorf("folder %s not found", folderUID)
+		}
+		return nil, err
+	}
+
+	result := []dashboardlint.DashboardFindings{}
+	for page := int64(1); ; page++ {
+		query := &dashboards.FindPersistedDashboardsQuery{
+			OrgId:        u.OrgID,
+			SignedInUser: u,
+			FolderIds:    []int64{f.ID},
+			Type:         string(model.DashHitDB),
+			Permission:   dashboards.PERMISSION_VIEW,
+			Limit:        folderPageSize,
+			Page:         page,
+		}
+		if err := s.dashboardService.SearchDashboards(ctx, query); err != nil {
+			return nil, err
+		}
+		if len(query.Result) == 0 {
+			break
+		}
+
+		uids := make([]string, 0, len(query.Result))
+		for _, hit := range query.Result {
+			uids = append(uids, hit.UID)
+		}
+		dashes, err := s.dashboardService.GetDashboards(ctx, &dashboards.GetDashboardsQuery{OrgID: u.OrgID, DashboardUIDs: uids})
+		if err != nil {
+			return nil, err
+		}
+		for _, dash := range dashes {
+			result = append(result, dashboardlint.DashboardFindings{UID: dash.UID, Title: dash.Title, Findings: s.Lint(dash.Data)})
+		}
+
+		if len(query.Result) < folderPageSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// lintOnSave is the dashboard save hook rejecting the dashboards with findings of the configured severity or higher.
+func (s *Service) lintOnSave(_ context.Context, dash *dashboards.Dashboard, _ *user.SignedInUser, _ bool) error {
+	if s.onSaveSeverity == "" || dash.IsFolder {
+		return nil
+	}
+
+	var rejecting []dashboardlint.Finding
+	for _, finding := range s.Lint(dash.Data) {
+		if finding.Severity.AtLeast(s.onSaveSeverity) {
+			rejecting = append(rejecting, finding)
+		}
+	}
+	if len(rejecting) == 0 {
+		return nil
+	}
+
+	return dashboardlint.ErrDashboardRejected.Build(errutil.TemplateData{
+		Public:  map[string]interface{}{"count": len(rejecting), "findings": rejecting},
+		Private: map[string]interface{}{"UID": dash.UID},
+	})
+}
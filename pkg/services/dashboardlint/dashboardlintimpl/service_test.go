@@ -0,0 +1,168 @@
+package dashboardlintimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboardlint"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const lintedDashboard = `{
+	"uid": "dash",
+	"title": "Linted dashboard",
+	"panels": [
+		{"id": 1, "type": "timeseries", "title": "Requests", "targets": [{"refId": "A"}], "datasource": {"type": "prometheus", "uid": "prom"}},
+		{"id": 2, "type": "graph", "title": "Errors", "targets": [{"refId": "A"}], "datasource": "Prometheus"},
+		{"id": 3, "type": "text", "title": "Notes"},
+		{"id": 4, "type": "row", "collapsed": true, "panels": [
+			{"id": 5, "type": "stat", "targets": [{"refId": "A"}], "datasource": null},
+			{"id": 6, "libraryPanel": {"uid": "lib", "name": "Library panel"}}
+		]}
+	]
+}`
+
+func TestLint(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(lintedDashboard))
+	require.NoError(t, err)
+
+	t.Run("should return the findings of the builtin rules", func(t *testing.T) {
+		s := newService(setting.NewCfg(), nil, nil, nil)
+
+		require.Equal(t, []dashboardlint.Finding{
+			{RuleID: "panel-datasource-uid", Severity: dashboardlint.SeverityWarning, Message: `Panel "Errors" references its data source without UID`, PanelID: 2, Path: "panels[1]"},
+			{RuleID: "panel-datasource-uid", Severity: dashboardlint.SeverityWarning, Message: `Panel "#5" has no data source`, PanelID: 5, Path: "panels[3].panels[0]"},
+			{RuleID: "angular-panel", Severity: dashboardlint.SeverityWarning, Message: `Panel "Errors" uses the deprecated graph panel, use the timeseries panel instead`, PanelID: 2, Path: "panels[1]"},
+			{RuleID: "dashboard-description", Severity: dashboardlint.SeverityInfo, Message: "Dashboard has no description"},
+		}, s.Lint(data))
+	})
+
+	t.Run("should skip the disabled rules", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DashboardLintDisabledRules = []string{"panel-datasource-uid", "dashboard-description"}
+		s := newService(cfg, nil, nil, nil)
+
+		findings := s.Lint(data)
+		require.Len(t, findings, 1)
+		require.Equal(t, "angular-panel", findings[0].RuleID)
+		require.Len(t, s.Rules(), 1)
+	})
+
+	t.Run("should check the registered rules", func(t *testing.T) {
+		s := newService(setting.NewCfg(), nil, nil, nil)
+		s.RegisterRule(dashboardlint.Rule{
+			ID:       "dashboard-description",
+			Severity: dashboardlint.SeverityError,
+			Check: func(dashboard *simplejson.Json) []dashboardlint.Finding {
+				return []dashboardlint.Finding{{Message: "replaced"}}
+			},
+		})
+		s.RegisterRule(dashboardlint.Rule{
+			ID:       "dashboard-tags",
+			Severity: dashboardlint.SeverityInfo,
+			Check: func(dashboard *simplejson.Json) []dashboardlint.Finding {
+				if len(dashboard.Get("tags").MustArray()) == 0 {
+					return []dashboardlint.Finding{{Message: "Dashboard has no tags"}}
+				}
+				return nil
+			},
+		})
+
+		findings := s.Lint(data)
+		require.Len(t, findings, 5)
+		require.Equal(t, dashboardlint.Finding{RuleID: "dashboard-description", Severity: dashboardlint.SeverityError, Message: "replaced"}, findings[3])
+		require.Equal(t, dashboardlint.Finding{RuleID: "dashboard-tags", Severity: dashboardlint.SeverityInfo, Message: "Dashboard has no tags"}, findings[4])
+	})
+}
+
+func TestLintOnSave(t *testing.T) {
+	newDash := func(t *testing.T) *dashboards.Dashboard {
+		t.Helper()
+		data, err := simplejson.NewJson([]byte(lintedDashboard))
+		require.NoError(t, err)
+		return dashboards.NewDashboardFromJson(data)
+	}
+
+	t.Run("should save the dashboards by default", func(t *testing.T) {
+		s := newService(setting.NewCfg(), nil, nil, nil)
+		require.NoError(t, s.lintOnSave(context.Background(), newDash(t), nil, true))
+	})
+
+	t.Run("should save the dashboards with findings below the severity", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DashboardLintOnSaveSeverity = "error"
+		s := newService(cfg, nil, nil, nil)
+		require.NoError(t, s.lintOnSave(context.Background(), newDash(t), nil, true))
+	})
+
+	t.Run("should ignore an unknown severity", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DashboardLintOnSaveSeverity = "critical"
+		s := newService(cfg, nil, nil, nil)
+		require.NoError(t, s.lintOnSave(context.Background(), newDash(t), nil, true))
+	})
+
+	t.Run("should reject the dashboards with findings of the severity", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.DashboardLintOnSaveSeverity = "warning"
+		s := newService(cfg, nil, nil, nil)
+
+		err := s.lintOnSave(context.Background(), newDash(t), nil, true)
+		require.ErrorIs(t, err, dashboardlint.ErrDashboardRejected.Base)
+
+		var lintErr errutil.Error
+		require.ErrorAs(t, err, &lintErr)
+		public := lintErr.Public()
+		require.Equal(t, 400, public.StatusCode)
+		require.Equal(t, "Dashboard rejected by 3 lint findings", public.Message)
+		require.Len(t, public.Extra["findings"], 3)
+	})
+}
+
+func TestLintFolder(t *testing.T) {
+	folderStore := foldertest.NewFakeFolderStore(t)
+	folderStore.On("GetFolderByUID", mock.Anything, int64(1), "folder").Return(&folder.Folder{ID: 10, UID: "folder"}, nil).Maybe()
+	folderStore.On("GetFolderByUID", mock.Anything, int64(1), "missing").Return(nil, dashboards.ErrFolderNotFound).Maybe()
+
+	data, err := simplejson.NewJson([]byte(lintedDashboard))
+	require.NoError(t, err)
+	described := simplejson.NewFromAny(map[string]interface{}{"uid": "described", "title": "Described", "description": "Dashboard with a description"})
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("SearchDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+		return q.Page == 1 && q.FolderIds[0] == 10 && q.Type == string(model.DashHitDB)
+	})).Run(func(args mock.Arguments) {
+		q := args.Get(1).(*dashboards.FindPersistedDashboardsQuery)
+		q.Result = model.HitList{{UID: "dash"}, {UID: "described"}}
+	}).Return(nil).Maybe()
+	dashboardService.On("GetDashboards", mock.Anything, &dashboards.GetDashboardsQuery{OrgID: 1, DashboardUIDs: []string{"dash", "described"}}).Return([]*dashboards.Dashboard{
+		dashboards.NewDashboardFromJson(data), dashboards.NewDashboardFromJson(described),
+	}, nil).Maybe()
+
+	s := newService(setting.NewCfg(), folderStore, dashboardService, nil)
+	u := &user.SignedInUser{OrgID: 1, UserID: 2}
+
+	t.Run("should return the findings of the dashboards of the folder", func(t *testing.T) {
+		result, err := s.LintFolder(context.Background(), u, "folder")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, "dash", result[0].UID)
+		require.Len(t, result[0].Findings, 4)
+		require.Equal(t, dashboardlint.DashboardFindings{UID: "described", Title: "Described", Findings: []dashboardlint.Finding{}}, result[1])
+	})
+
+	t.Run("should return an error if the folder does not exist", func(t *testing.T) {
+		_, err := s.LintFolder(context.Background(), u, "missing")
+		require.ErrorIs(t, err, dashboardlint.ErrFolderNotFound)
+	})
+}
@@ -29,6 +29,8 @@ const (
 	ActionDashboardsPermissionsRead  = "dashboards.permissions:read"
 	ActionDashboardsPermissionsWrite = "dashboards.permissions:write"
 	ActionDashboardsPublicWrite      = "dashboards.public:write"
+	ActionDashboardsSaveHooksBypass  = "dashboards.savehooks:bypass"
+	ActionDashboardsApprove          = "dashboards:approve"
 )
 
 var (
@@ -6,6 +6,7 @@ import (
 	alertmodels "github.com/grafana/grafana/pkg/services/alerting/models"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 // DashboardService is a service for operating on dashboards.
@@ -31,6 +32,14 @@ type DashboardService interface {
 	CountDashboardsInFolder(ctx context.Context, query *CountDashboardsInFolderQuery) (int64, error)
 }
 
+// SaveHook is called with the dashboards that are about to be saved, after the permissions of the
+// user are checked. The hook can modify the dashboard, an error aborts the save.
+type SaveHook func(ctx context.Context, dash *Dashboard, user *user.SignedInUser, isNew bool) error
+
+// ApprovalHook is called last with the dashboards that are about to be saved, after the save hooks. It holds
+// the changes requiring an approval by returning a PendingApprovalError, the dashboard is then not saved.
+type ApprovalHook func(ctx context.Context, dto *SaveDashboardDTO, isNew bool) error
+
 // PluginService is a service for operating on plugin dashboards.
 type PluginService interface {
 	GetDashboardsByPluginID(ctx context.Context, query *GetDashboardsByPluginIDQuery) ([]*Dashboard, error)
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"xorm.io/xorm"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
@@ -141,7 +144,7 @@ func (d *dashboardStore) SaveProvisionedDashboard(ctx context.Context, cmd dashb
 	var result *dashboards.Dashboard
 	var err error
 	err = d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		result, err = saveDashboard(sess, &cmd, d.emitEntityEvent())
+		result, err = saveDashboard(sess, d.store.GetDialect(), &cmd, d.emitEntityEvent())
 		if err != nil {
 			return err
 		}
@@ -159,7 +162,7 @@ func (d *dashboardStore) SaveDashboard(ctx context.Context, cmd dashboards.SaveD
 	var result *dashboards.Dashboard
 	var err error
 	err = d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		result, err = saveDashboard(sess, &cmd, d.emitEntityEvent())
+		result, err = saveDashboard(sess, d.store.GetDialect(), &cmd, d.emitEntityEvent())
 		if err != nil {
 			return err
 		}
@@ -419,7 +422,7 @@ func getExistingDashboardByTitleAndFolder(sess *db.Session, dash *dashboards.Das
 	return isParentFolderChanged, nil
 }
 
-func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitEntityEvent bool) (*dashboards.Dashboard, error) {
+func saveDashboard(sess *db.Session, dialect migrator.Dialect, cmd *dashboards.SaveDashboardCommand, emitEntityEvent bool) (*dashboards.Dashboard, error) {
 	dash := cmd.GetDashboardModel()
 
 	userId := cmd.UserID
@@ -458,10 +461,11 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 	}
 
 	parentVersion := dash.Version
+	isNew := dash.ID == 0
 	var affectedRows int64
 	var err error
 
-	if dash.ID == 0 {
+	if isNew {
 		dash.SetVersion(1)
 		dash.Created = time.Now()
 		dash.CreatedBy = userId
@@ -491,6 +495,11 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 		return nil, dashboards.ErrDashboardNotFound
 	}
 
+	content, err := saveDashboardVersionContent(sess, dialect, dash.Data, dash.Version)
+	if err != nil {
+		return nil, err
+	}
+
 	dashVersion := &dashver.DashboardVersion{
 		DashboardID:   dash.ID,
 		ParentVersion: parentVersion,
@@ -499,7 +508,8 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 		Created:       time.Now(),
 		CreatedBy:     dash.UpdatedBy,
 		Message:       cmd.Message,
-		Data:          dash.Data,
+		Data:          simplejson.New(),
+		ContentHash:   content.Hash,
 	}
 
 	// insert version entry
@@ -530,9 +540,63 @@ func saveDashboard(sess *db.Session, cmd *dashboards.SaveDashboardCommand, emitE
 			return dash, err
 		}
 	}
+
+	if isNew {
+		sess.PublishAfterCommit(&events.DashboardCreated{
+			Timestamp: dash.Created,
+			Title:     dash.Title,
+			ID:        dash.ID,
+			UID:       dash.UID,
+			OrgID:     dash.OrgID,
+			IsFolder:  dash.IsFolder,
+			Version:   dash.Version,
+		})
+	} else {
+		sess.PublishAfterCommit(&events.DashboardUpdated{
+			Timestamp: dash.Updated,
+			Title:     dash.Title,
+			ID:        dash.ID,
+			UID:       dash.UID,
+			OrgID:     dash.OrgID,
+			IsFolder:  dash.IsFolder,
+			Version:   dash.Version,
+		})
+	}
 	return dash, nil
 }
 
+// saveDashboardVersionContent saves the content of the JSON of a dashboard version, unless a version with the same
+// content was already saved.
+func saveDashboardVersionContent(sess *db.Session, dialect migrator.Dialect, data *simplejson.Json, version int) (*dashver.DashboardVersionContent, error) {
+	content, err := dashver.NewDashboardVersionContent(data, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// a single upsert, so that concurrent saves of the same content don't fail on the unique hash. Updating an
+	// existing content also tells the clean up of the unreferenced contents that the content is in use.
+	_, err = sess.Exec(upsertDashboardVersionContentSQL(dialect),
+		content.Hash, content.Data, content.Compression, content.Size, content.Created, content.Updated, content.Archived)
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func upsertDashboardVersionContentSQL(dialect migrator.Dialect) string {
+	cols := []string{"hash", "data", "compression", "size", "created", "updated", "archived"}
+	for i, c := range cols {
+		cols[i] = dialect.Quote(c)
+	}
+	updated := dialect.Quote("updated")
+
+	insert := "INSERT INTO dashboard_version_content (" + strings.Join(cols, ", ") + ") VALUES (?" + strings.Repeat(", ?", len(cols)-1) + ")"
+	if dialect.DriverName() == migrator.MySQL {
+		return insert + " ON DUPLICATE KEY UPDATE " + updated + " = VALUES(" + updated + ")"
+	}
+	return insert + " ON CONFLICT (" + dialect.Quote("hash") + ") DO UPDATE SET " + updated + " = excluded." + updated
+}
+
 func saveProvisionedData(sess *db.Session, provisioning *dashboards.DashboardProvisioning, dashboard *dashboards.Dashboard) error {
 	result := &dashboards.DashboardProvisioning{}
 
@@ -734,12 +798,28 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 			return err
 		}
 
+		_, err = sess.Exec("DELETE FROM resource_star WHERE org_id = ? AND kind = ? AND uid = ?", dashboard.OrgID, star.KindFolder, dashboard.UID)
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Exec("DELETE FROM folder_setting WHERE org_id = ? AND folder_uid = ?", dashboard.OrgID, dashboard.UID)
+		if err != nil {
+			return err
+		}
+
 		for _, dash := range dashIds {
 			// remove all access control permission with child dashboard scopes
 			_, err = sess.Exec("DELETE FROM permission WHERE scope = ?", ac.GetResourceScopeUID("dashboards", dash.Uid))
 			if err != nil {
 				return err
 			}
+			sess.PublishAfterCommit(&events.DashboardDeleted{
+				Timestamp: time.Now(),
+				ID:        dash.Id,
+				UID:       dash.Uid,
+				OrgID:     dashboard.OrgID,
+			})
 		}
 
 		if len(dashIds) > 0 {
@@ -806,6 +886,14 @@ func (d *dashboardStore) deleteDashboard(cmd *dashboards.DeleteDashboardCommand,
 			return err
 		}
 	}
+
+	sess.PublishAfterCommit(&events.DashboardDeleted{
+		Timestamp: time.Now(),
+		ID:        dashboard.ID,
+		UID:       dashboard.UID,
+		OrgID:     dashboard.OrgID,
+		IsFolder:  dashboard.IsFolder,
+	})
 	return nil
 }
 
@@ -912,6 +1000,19 @@ func (d *dashboardStore) GetDashboards(ctx context.Context, query *dashboards.Ge
 	return dashboards, nil
 }
 
+// nestedFoldersMode returns how the permission filter expands the folder permissions to the subfolders,
+// with the folder_closure table when the database does not support recursive queries.
+func (d *dashboardStore) nestedFoldersMode() permissions.NestedFoldersMode {
+	supported, err := d.store.RecursiveQueriesAreSupported()
+	if err != nil {
+		d.log.Warn("Failed to check whether recursive queries are supported", "error", err)
+	}
+	if err != nil || !supported {
+		return permissions.NestedFoldersClosure
+	}
+	return permissions.NestedFoldersRecursive
+}
+
 func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.FindPersistedDashboardsQuery) ([]dashboards.DashboardSearchProjection, error) {
 	filters := []interface{}{
 		permissions.DashboardPermissionFilter{
@@ -925,9 +1026,11 @@ func (d *dashboardStore) FindDashboards(ctx context.Context, query *dashboards.F
 
 	if !ac.IsDisabled(d.cfg) {
 		// if access control is enabled, overwrite the filters so far
-		filters = []interface{}{
-			permissions.NewAccessControlDashboardPermissionFilter(query.SignedInUser, query.Permission, query.Type),
+		filter := permissions.NewAccessControlDashboardPermissionFilter(query.SignedInUser, query.Permission, query.Type)
+		if d.features.IsEnabled(featuremgmt.FlagNestedFolders) {
+			filter = filter.WithNestedFolders(d.nestedFoldersMode())
 		}
+		filters = []interface{}{filter}
 	}
 
 	for _, filter := range query.Sort.Filter {
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/search/model"
@@ -115,6 +116,75 @@ func TestIntegrationDashboardDataAccess(t *testing.T) {
 		require.False(t, queryResult.IsFolder)
 	})
 
+	t.Run("Should store the JSON of the versions once per content", func(t *testing.T) {
+		setup()
+		for i := 0; i < 2; i++ {
+			_, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+				OrgID:     1,
+				Overwrite: true,
+				Dashboard: simplejson.NewFromAny(map[string]interface{}{
+					"id":    savedDash2.ID,
+					"uid":   savedDash2.UID,
+					"title": "test dash 67 renamed",
+				}),
+			})
+			require.NoError(t, err)
+		}
+
+		var hashes []string
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			return sess.Table("dashboard_version").Where("dashboard_id = ?", savedDash2.ID).OrderBy("version").Cols("content_hash").Find(&hashes)
+		})
+		require.NoError(t, err)
+		require.Len(t, hashes, 3)
+		require.NotEqual(t, hashes[0], hashes[1])
+		require.Equal(t, hashes[1], hashes[2], "the versions only differing by their number share their content")
+
+		var count int64
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			count, err = sess.Table("dashboard_version_content").In("hash", hashes).Count()
+			return err
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, count)
+	})
+
+	t.Run("Should save the same version content once and only refresh it", func(t *testing.T) {
+		setup()
+		data := simplejson.NewFromAny(map[string]interface{}{"title": "shared content"})
+
+		var first, second *dashver.DashboardVersionContent
+		err := sqlStore.WithTransactionalDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			first, err = saveDashboardVersionContent(sess, sqlStore.GetDialect(), data, 1)
+			return err
+		})
+		require.NoError(t, err)
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE dashboard_version_content SET archived = ?, updated = ? WHERE hash = ?", true, first.Updated.Add(-time.Hour), first.Hash)
+			return err
+		})
+		require.NoError(t, err)
+
+		err = sqlStore.WithTransactionalDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			second, err = saveDashboardVersionContent(sess, sqlStore.GetDialect(), data, 2)
+			return err
+		})
+		require.NoError(t, err)
+		require.Equal(t, first.Hash, second.Hash)
+
+		var contents []*dashver.DashboardVersionContent
+		err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			return sess.Table("dashboard_version_content").Where("hash = ?", first.Hash).Find(&contents)
+		})
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		require.True(t, contents[0].Archived, "the upsert only refreshes the updated timestamp")
+		require.False(t, contents[0].Updated.Before(first.Updated.Truncate(time.Second)))
+	})
+
 	t.Run("Should be able to get a dashboard UID by ID", func(t *testing.T) {
 		setup()
 		query := dashboards.GetDashboardRefByIDQuery{ID: savedDash.ID}
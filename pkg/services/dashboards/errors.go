@@ -2,8 +2,10 @@ package dashboards
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 // Typed errors
@@ -122,6 +124,12 @@ var (
 		Status:     "not-found",
 	}
 
+	ErrDashboardSaveRejected = errutil.NewBase(errutil.StatusValidationFailed, "dashboards.saveRejected").MustTemplate(
+		"dashboard rejected by save webhook {{ .Private.URL }}: {{ .Public.Message }}",
+		errutil.WithPublic("Dashboard rejected: {{ .Public.Message }}"),
+	)
+	ErrDashboardSaveWebhookFailed = errutil.NewBase(errutil.StatusInternal, "dashboards.saveWebhookFailed", errutil.WithPublicMessage("Failed to validate the dashboard"))
+
 	ErrFolderNotFound           = errors.New("folder not found")
 	ErrFolderVersionMismatch    = errors.New("the folder has been changed by someone else")
 	ErrFolderTitleEmpty         = errors.New("folder title cannot be empty")
@@ -168,3 +176,14 @@ type UpdatePluginDashboardError struct {
 func (d UpdatePluginDashboardError) Error() string {
 	return "Dashboard belongs to plugin"
 }
+
+// PendingApprovalError is returned when the change of a dashboard is held until it is approved.
+type PendingApprovalError struct {
+	ChangeID int64
+	// DiffURL is the URL of the diff of the change with the current dashboard.
+	DiffURL string
+}
+
+func (e PendingApprovalError) Error() string {
+	return fmt.Sprintf("the change is pending approval as change %d", e.ChangeID)
+}
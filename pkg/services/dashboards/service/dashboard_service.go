@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 
 	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/alerting"
@@ -29,6 +31,8 @@ var (
 		{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
 		{Action: dashboards.ActionDashboardsCreate, Scope: dashboards.ScopeFoldersAll},
 		{Action: dashboards.ActionDashboardsWrite, Scope: dashboards.ScopeFoldersAll},
+		// the provisioned dashboards are reviewed with their source, they don't wait for an approval
+		{Action: dashboards.ActionDashboardsApprove, Scope: dashboards.ScopeFoldersAll},
 	}
 	// DashboardServiceImpl implements the DashboardService interface
 	_ dashboards.DashboardService             = (*DashboardServiceImpl)(nil)
@@ -47,6 +51,9 @@ type DashboardServiceImpl struct {
 	folderPermissions    accesscontrol.FolderPermissionsService
 	dashboardPermissions accesscontrol.DashboardPermissionsService
 	ac                   accesscontrol.AccessControl
+	saveHooks            []dashboards.SaveHook
+	saveWebhooks         []dashboards.SaveHook
+	approvalHook         dashboards.ApprovalHook
 }
 
 // This is the uber service that implements a three smaller services
@@ -69,12 +76,63 @@ func ProvideDashboardServiceImpl(
 		folderService:        folderSvc,
 	}
 
+	// the save webhooks share the circuit breaker of their client
+	webhookClient := &http.Client{
+		Timeout:   cfg.DashboardSaveWebhookTimeout,
+		Transport: httpclientprovider.OutboundRoundTripper(cfg, nil, "dashboard-save-webhook", http.DefaultTransport),
+	}
+	for _, url := range cfg.DashboardSaveWebhooks {
+		webhook := newSaveWebhook(url, webhookClient, cfg.DashboardSaveWebhookFailOpen, folderStore)
+		dashSvc.saveWebhooks = append(dashSvc.saveWebhooks, webhook.hook)
+	}
+
 	ac.RegisterScopeAttributeResolver(dashboards.NewDashboardIDScopeResolver(folderStore, dashSvc, folderSvc))
 	ac.RegisterScopeAttributeResolver(dashboards.NewDashboardUIDScopeResolver(folderStore, dashSvc, folderSvc))
 
 	return dashSvc
 }
 
+// RegisterSaveHook adds a hook called before the dashboards are saved. The hooks must be registered
+// when the services are initialized.
+func (dr *DashboardServiceImpl) RegisterSaveHook(hook dashboards.SaveHook) {
+	dr.saveHooks = append(dr.saveHooks, hook)
+}
+
+// SetApprovalHook sets the hook holding the changes requiring an approval. It must be set when the services are
+// initialized.
+func (dr *DashboardServiceImpl) SetApprovalHook(hook dashboards.ApprovalHook) {
+	dr.approvalHook = hook
+}
+
+// runSaveHooks calls the registered hooks, then the configured webhooks, so that the webhooks validate the
+// dashboard as it is saved. The users allowed to bypass the hooks save the dashboards unmodified.
+func (dr *DashboardServiceImpl) runSaveHooks(ctx context.Context, dash *dashboards.Dashboard, u *user.SignedInUser) error {
+	if dash.IsFolder || len(dr.saveHooks)+len(dr.saveWebhooks) == 0 {
+		return nil
+	}
+
+	if u != nil {
+		bypass, err := dr.ac.Evaluate(ctx, u, accesscontrol.EvalPermission(dashboards.ActionDashboardsSaveHooksBypass))
+		if err != nil {
+			return err
+		}
+		if bypass {
+			dr.log.Debug("Bypassing the dashboard save hooks", "dashboardUid", dash.UID, "userId", u.UserID)
+			return nil
+		}
+	}
+
+	isNew := dash.ID == 0
+	for _, hooks := range [][]dashboards.SaveHook{dr.saveHooks, dr.saveWebhooks} {
+		for _, hook := range hooks {
+			if err := hook(ctx, dash, u, isNew); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (dr *DashboardServiceImpl) GetProvisionedDashboardData(ctx context.Context, name string) ([]*dashboards.DashboardProvisioning, error) {
 	return dr.dashboardStore.GetProvisionedDashboardData(ctx, name)
 }
@@ -176,6 +234,18 @@ func (dr *DashboardServiceImpl) BuildSaveDashboardCommand(ctx context.Context, d
 		}
 	}
 
+	if err := dr.runSaveHooks(ctx, dash, dto.User); err != nil {
+		return nil, err
+	}
+
+	// the approval comes after the save hooks, so that the change held is the dashboard as it would be saved,
+	// and it can't be bypassed
+	if dr.approvalHook != nil && !dash.IsFolder {
+		if err := dr.approvalHook(ctx, dto, dash.ID == 0); err != nil {
+			return nil, err
+		}
+	}
+
 	cmd := &dashboards.SaveDashboardCommand{
 		Dashboard: dash.Data,
 		Message:   dto.Message,
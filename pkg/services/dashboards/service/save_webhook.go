@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// maxSaveWebhookResponseSize bounds the size of the dashboards returned by the save webhooks.
+const maxSaveWebhookResponseSize = 10 << 20
+
+type saveWebhookUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	OrgID int64  `json:"orgId"`
+}
+
+type saveWebhookRequest struct {
+	Dashboard *simplejson.Json `json:"dashboard"`
+	FolderUID string           `json:"folderUid"`
+	IsNew     bool             `json:"isNew"`
+	User      *saveWebhookUser `json:"user,omitempty"`
+}
+
+type saveWebhookResponse struct {
+	Allowed bool   `json:"allowed"`
+	Message string `json:"message"`
+	// Dashboard replaces the saved dashboard if it is set.
+	Dashboard *simplejson.Json `json:"dashboard"`
+}
+
+// saveWebhook sends the dashboards about to be saved to an HTTP endpoint that validates them,
+// and can return a modified dashboard.
+type saveWebhook struct {
+	url         string
+	client      *http.Client
+	failOpen    bool
+	folderStore folder.FolderStore
+	log         log.Logger
+}
+
+func newSaveWebhook(url string, client *http.Client, failOpen bool, folderStore folder.FolderStore) *saveWebhook {
+	return &saveWebhook{
+		url:         url,
+		client:      client,
+		failOpen:    failOpen,
+		folderStore: folderStore,
+		log:         log.New("dashboard-save-webhook"),
+	}
+}
+
+func (w *saveWebhook) hook(ctx context.Context, dash *dashboards.Dashboard, u *user.SignedInUser, isNew bool) error {
+	resp, err := w.call(ctx, dash, u, isNew)
+	if err != nil {
+		if w.failOpen {
+			w.log.Warn("Dashboard save webhook failed, saving the dashboard", "url", w.url, "dashboardUid", dash.UID, "error", err)
+			return nil
+		}
+		return dashboards.ErrDashboardSaveWebhookFailed.Errorf("save webhook %s failed: %w", w.url, err)
+	}
+
+	if !resp.Allowed {
+		message := resp.Message
+		if message == "" {
+			message = "the dashboard does not comply with the policies of the organization"
+		}
+		return dashboards.ErrDashboardSaveRejected.Build(errutil.TemplateData{
+			Public:  map[string]interface{}{"Message": message},
+			Private: map[string]interface{}{"URL": w.url},
+		})
+	}
+
+	if resp.Dashboard == nil {
+		return nil
+	}
+	if uid := resp.Dashboard.Get("uid").MustString(); uid != dash.UID {
+		return dashboards.ErrDashboardSaveWebhookFailed.Errorf("save webhook %s changed the uid of dashboard %s to %q", w.url, dash.UID, uid)
+	}
+	title := strings.TrimSpace(resp.Dashboard.Get("title").MustString())
+	if title == "" {
+		return dashboards.ErrDashboardTitleEmpty
+	}
+	dash.Data = resp.Dashboard
+	dash.Title = title
+	dash.Data.Set("title", title)
+	// the id is set by the store and cannot be changed by the webhook
+	if dash.ID != 0 {
+		dash.Data.Set("id", dash.ID)
+	} else {
+		dash.Data.Del("id")
+	}
+	return nil
+}
+
+func (w *saveWebhook) call(ctx context.Context, dash *dashboards.Dashboard, u *user.SignedInUser, isNew bool) (*saveWebhookResponse, error) {
+	req := saveWebhookRequest{Dashboard: dash.Data, IsNew: isNew}
+	if dash.FolderID > 0 {
+		f, err := w.folderStore.GetFolderByID(ctx, dash.OrgID, dash.FolderID)
+		if err != nil {
+			return nil, err
+		}
+		req.FolderUID = f.UID
+	}
+	if u != nil {
+		req.User = &saveWebhookUser{ID: u.UserID, Login: u.Login, OrgID: u.OrgID}
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := w.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := httpResp.Body.Close(); err != nil {
+			w.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if httpResp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("unexpected status code %d", httpResp.StatusCode)
+	}
+
+	resp := &saveWebhookResponse{}
+	if err := json.NewDecoder(io.LimitReader(httpResp.Body, maxSaveWebhookResponseSize)).Decode(resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestSaveWebhook(t *testing.T) {
+	var received saveWebhookRequest
+	respond := func(w http.ResponseWriter, r *http.Request) {}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		respond(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	folderStore := foldertest.NewFakeFolderStore(t)
+	folderStore.On("GetFolderByID", mock.Anything, int64(1), int64(3)).Return(&folder.Folder{ID: 3, UID: "team-a"}, nil).Maybe()
+	signedInUser := &user.SignedInUser{UserID: 2, Login: "editor", OrgID: 1}
+
+	newDash := func() *dashboards.Dashboard {
+		dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{"uid": "abc", "title": "cpu usage"}))
+		dash.OrgID = 1
+		dash.FolderID = 3
+		dash.SetUID("abc")
+		return dash
+	}
+	reply := func(body string, status int) {
+		respond = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}
+	}
+
+	webhook := newSaveWebhook(server.URL, &http.Client{Timeout: time.Second}, false, folderStore)
+
+	t.Run("should send the dashboard and accept it", func(t *testing.T) {
+		reply(`{"allowed": true}`, http.StatusOK)
+		dash := newDash()
+		require.NoError(t, webhook.hook(context.Background(), dash, signedInUser, true))
+
+		require.Equal(t, "team-a", received.FolderUID)
+		require.True(t, received.IsNew)
+		require.Equal(t, &saveWebhookUser{ID: 2, Login: "editor", OrgID: 1}, received.User)
+		require.Equal(t, "cpu usage", received.Dashboard.Get("title").MustString())
+		require.Equal(t, "cpu usage", dash.Title)
+	})
+
+	t.Run("should replace the dashboard with the returned dashboard", func(t *testing.T) {
+		reply(`{"allowed": true, "dashboard": {"id": 10, "uid": "abc", "title": "Team A / CPU usage", "tags": ["owner:team-a"]}}`, http.StatusOK)
+		dash := newDash()
+		require.NoError(t, webhook.hook(context.Background(), dash, signedInUser, true))
+
+		require.Equal(t, "Team A / CPU usage", dash.Title)
+		require.Equal(t, []string{"owner:team-a"}, dash.GetTags())
+		_, ok := dash.Data.CheckGet("id")
+		require.False(t, ok)
+	})
+
+	t.Run("should not allow to change the uid", func(t *testing.T) {
+		reply(`{"allowed": true, "dashboard": {"uid": "other", "title": "cpu usage"}}`, http.StatusOK)
+		err := webhook.hook(context.Background(), newDash(), signedInUser, false)
+		require.ErrorIs(t, err, dashboards.ErrDashboardSaveWebhookFailed)
+	})
+
+	t.Run("should reject the dashboard with the message of the webhook", func(t *testing.T) {
+		reply(`{"allowed": false, "message": "titles must start with the team name"}`, http.StatusOK)
+		err := webhook.hook(context.Background(), newDash(), signedInUser, false)
+		require.ErrorIs(t, err, dashboards.ErrDashboardSaveRejected)
+		require.Contains(t, err.Error(), "titles must start with the team name")
+	})
+
+	t.Run("should reject the dashboard if the webhook fails", func(t *testing.T) {
+		reply(`internal error`, http.StatusInternalServerError)
+		err := webhook.hook(context.Background(), newDash(), signedInUser, false)
+		require.ErrorIs(t, err, dashboards.ErrDashboardSaveWebhookFailed)
+
+		failOpen := newSaveWebhook(server.URL, &http.Client{Timeout: time.Second}, true, folderStore)
+		require.NoError(t, failOpen.hook(context.Background(), newDash(), signedInUser, false))
+	})
+
+	t.Run("should not call the hooks for users allowed to bypass them", func(t *testing.T) {
+		reply(`{"allowed": false}`, http.StatusOK)
+		service := &DashboardServiceImpl{
+			log:          log.NewNopLogger(),
+			ac:           actest.FakeAccessControl{ExpectedEvaluate: true},
+			saveWebhooks: []dashboards.SaveHook{webhook.hook},
+		}
+		require.NoError(t, service.runSaveHooks(context.Background(), newDash(), signedInUser))
+
+		service.ac = actest.FakeAccessControl{ExpectedEvaluate: false}
+		err := service.runSaveHooks(context.Background(), newDash(), signedInUser)
+		require.ErrorIs(t, err, dashboards.ErrDashboardSaveRejected)
+	})
+}
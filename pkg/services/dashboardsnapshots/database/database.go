@@ -35,8 +35,20 @@ func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd
 			return nil
 		}
 
+		expiredBefore := cmd.ExpiredBefore
+		if expiredBefore.IsZero() {
+			expiredBefore = time.Now()
+		}
+
+		// the payloads in the object storage are deleted by the service once the rows are
+		keys := make([]string, 0)
+		if err := sess.Table("dashboard_snapshot").Cols("key").Where("expires < ? AND payload_in_storage = ?", expiredBefore, true).Find(&keys); err != nil {
+			return err
+		}
+		cmd.DeletedPayloadKeys = keys
+
 		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE expires < ?"
-		expiredResponse, err := sess.Exec(deleteExpiredSQL, time.Now())
+		expiredResponse, err := sess.Exec(deleteExpiredSQL, expiredBefore)
 		if err != nil {
 			return err
 		}
@@ -65,6 +77,7 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			PayloadInStorage:   cmd.PayloadInStorage,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
@@ -144,3 +157,13 @@ func (d *DashboardSnapshotStore) SearchDashboardSnapshots(ctx context.Context, q
 	}
 	return queryResult, nil
 }
+
+func (d *DashboardSnapshotStore) CountDashboardSnapshots(ctx context.Context, query *dashboardsnapshots.CountDashboardSnapshotsQuery) (int64, error) {
+	var count int64
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		count, err = sess.Table("dashboard_snapshot").Where("org_id = ? AND expires > ?", query.OrgID, time.Now()).Count()
+		return err
+	})
+	return count, err
+}
@@ -188,6 +188,20 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 
 		require.Len(t, queryResult, 1)
 		require.Equal(t, nonExpiredSnapshot.Key, queryResult[0].Key)
+
+		t.Run("Snapshots which expired after the given time are kept", func(t *testing.T) {
+			createTestSnapshot(t, dashStore, "key4", -1200)
+
+			cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{ExpiredBefore: time.Now().Add(-time.Hour)}
+			err := dashStore.DeleteExpiredSnapshots(context.Background(), &cmd)
+			require.NoError(t, err)
+			assert.EqualValues(t, 0, cmd.DeletedRows)
+
+			cmd = dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+			err = dashStore.DeleteExpiredSnapshots(context.Background(), &cmd)
+			require.NoError(t, err)
+			assert.EqualValues(t, 1, cmd.DeletedRows)
+		})
 	})
 }
 
@@ -217,3 +231,23 @@ func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key str
 
 	return result
 }
+
+func TestIntegrationCountDashboardSnapshots(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := ProvideStore(sqlstore, setting.NewCfg())
+
+	createTestSnapshot(t, dashStore, "key1", 0)
+	createTestSnapshot(t, dashStore, "key2", 48000)
+	createTestSnapshot(t, dashStore, "key3", -1200)
+
+	count, err := dashStore.CountDashboardSnapshots(context.Background(), &dashboardsnapshots.CountDashboardSnapshotsQuery{OrgID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	count, err = dashStore.CountDashboardSnapshots(context.Background(), &dashboardsnapshots.CountDashboardSnapshotsQuery{OrgID: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
@@ -25,6 +25,9 @@ type DashboardSnapshot struct {
 
 	Dashboard          *simplejson.Json
 	DashboardEncrypted []byte
+	// PayloadInStorage is set when the encrypted dashboard is in the object storage of the snapshots rather than
+	// in DashboardEncrypted
+	PayloadInStorage bool
 }
 
 // DashboardSnapshotDTO without dashboard map
@@ -77,6 +80,7 @@ type CreateDashboardSnapshotCommand struct {
 	UserID int64 `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
+	PayloadInStorage   bool   `json:"-"`
 }
 
 type DeleteDashboardSnapshotCommand struct {
@@ -84,7 +88,11 @@ type DeleteDashboardSnapshotCommand struct {
 }
 
 type DeleteExpiredSnapshotsCommand struct {
-	DeletedRows int64
+	// ExpiredBefore only deletes the snapshots which expired before it, the current time when zero
+	ExpiredBefore time.Time
+	DeletedRows   int64
+	// DeletedPayloadKeys are the keys of the deleted snapshots which had their payload in the object storage
+	DeletedPayloadKeys []string
 }
 
 type GetDashboardSnapshotQuery struct {
@@ -94,6 +102,11 @@ type GetDashboardSnapshotQuery struct {
 
 type DashboardSnapshotsList []*DashboardSnapshotDTO
 
+// CountDashboardSnapshotsQuery counts the snapshots of an organization that have not expired.
+type CountDashboardSnapshotsQuery struct {
+	OrgID int64
+}
+
 type GetDashboardSnapshotsQuery struct {
 	Name         string
 	Limit        int
@@ -0,0 +1,25 @@
+package dashboardsnapshots
+
+import (
+	"crypto/subtle"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ServerTokenHeader is the header authenticating the requests of other Grafana instances to this instance, when it
+// acts as their external snapshot server.
+const ServerTokenHeader = "X-Grafana-Snapshot-Token"
+
+// OrgIDForServerToken returns the organization storing the snapshots of the instance with the token, when this
+// instance acts as an external snapshot server.
+func OrgIDForServerToken(cfg *setting.Cfg, token string) (int64, bool) {
+	if !cfg.SnapshotServerEnabled || token == "" {
+		return 0, false
+	}
+	for t, orgID := range cfg.SnapshotServerTokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return orgID, true
+		}
+	}
+	return 0, false
+}
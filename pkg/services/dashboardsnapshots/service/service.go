@@ -2,8 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
@@ -11,15 +15,20 @@ import (
 type ServiceImpl struct {
 	store          dashboardsnapshots.Store
 	secretsService secrets.Service
+	// payloads stores the encrypted dashboards of the snapshots, nil when they're stored in the database
+	payloads objectstore.ObjectStore
+	log      log.Logger
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service) *ServiceImpl {
+func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, objectStorage *objectstore.Service) *ServiceImpl {
 	s := &ServiceImpl{
 		store:          store,
 		secretsService: secretsService,
+		payloads:       objectStorage.Store(objectstore.ArtifactSnapshots),
+		log:            log.New("dashboardsnapshots"),
 	}
 
 	return s
@@ -38,7 +47,24 @@ func (s *ServiceImpl) CreateDashboardSnapshot(ctx context.Context, cmd *dashboar
 
 	cmd.DashboardEncrypted = encryptedDashboard
 
-	return s.store.CreateDashboardSnapshot(ctx, cmd)
+	// the external snapshots only reference the snapshot server, they have no payload
+	if s.payloads == nil || cmd.External {
+		return s.store.CreateDashboardSnapshot(ctx, cmd)
+	}
+
+	if err := s.payloads.Put(ctx, cmd.Key, encryptedDashboard); err != nil {
+		return nil, fmt.Errorf("failed to store the dashboard snapshot: %w", err)
+	}
+	cmd.DashboardEncrypted = nil
+	cmd.PayloadInStorage = true
+
+	snapshot, err := s.store.CreateDashboardSnapshot(ctx, cmd)
+	if err != nil {
+		s.deletePayload(ctx, cmd.Key)
+		return nil, err
+	}
+	snapshot.DashboardEncrypted = encryptedDashboard
+	return snapshot, nil
 }
 
 func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) (*dashboardsnapshots.DashboardSnapshot, error) {
@@ -47,6 +73,17 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 		return nil, err
 	}
 
+	if queryResult.PayloadInStorage {
+		if s.payloads == nil {
+			return nil, errors.New("the dashboard snapshot is in an object storage but no storage is configured for the snapshots")
+		}
+		encryptedDashboard, err := s.payloads.Get(ctx, queryResult.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the dashboard snapshot from the object storage: %w", err)
+		}
+		queryResult.DashboardEncrypted = encryptedDashboard
+	}
+
 	if queryResult.DashboardEncrypted != nil {
 		decryptedDashboard, err := s.secretsService.Decrypt(ctx, queryResult.DashboardEncrypted)
 		if err != nil {
@@ -65,13 +102,46 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 }
 
 func (s *ServiceImpl) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
-	return s.store.DeleteDashboardSnapshot(ctx, cmd)
+	if s.payloads == nil {
+		return s.store.DeleteDashboardSnapshot(ctx, cmd)
+	}
+
+	snapshot, err := s.store.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{DeleteKey: cmd.DeleteKey})
+	if err != nil {
+		return err
+	}
+	if err := s.store.DeleteDashboardSnapshot(ctx, cmd); err != nil {
+		return err
+	}
+	if snapshot.PayloadInStorage {
+		s.deletePayload(ctx, snapshot.Key)
+	}
+	return nil
 }
 
 func (s *ServiceImpl) SearchDashboardSnapshots(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotsQuery) (dashboardsnapshots.DashboardSnapshotsList, error) {
 	return s.store.SearchDashboardSnapshots(ctx, query)
 }
 
+func (s *ServiceImpl) CountDashboardSnapshots(ctx context.Context, query *dashboardsnapshots.CountDashboardSnapshotsQuery) (int64, error) {
+	return s.store.CountDashboardSnapshots(ctx, query)
+}
+
 func (s *ServiceImpl) DeleteExpiredSnapshots(ctx context.Context, cmd *dashboardsnapshots.DeleteExpiredSnapshotsCommand) error {
-	return s.store.DeleteExpiredSnapshots(ctx, cmd)
+	if err := s.store.DeleteExpiredSnapshots(ctx, cmd); err != nil {
+		return err
+	}
+	if s.payloads != nil {
+		for _, key := range cmd.DeletedPayloadKeys {
+			s.deletePayload(ctx, key)
+		}
+	}
+	return nil
+}
+
+// deletePayload deletes the payload of a snapshot from the object storage, a failure only leaves an orphan object.
+func (s *ServiceImpl) deletePayload(ctx context.Context, key string) {
+	if err := s.payloads.Delete(ctx, key); err != nil {
+		s.log.Warn("Failed to delete the dashboard snapshot from the object storage", "error", err)
+	}
 }
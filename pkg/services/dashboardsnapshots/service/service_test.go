@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashsnapdb "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
 	"github.com/grafana/grafana/pkg/services/secrets/database"
@@ -19,7 +22,7 @@ func TestDashboardSnapshotsService(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	dsStore := dashsnapdb.ProvideStore(sqlStore, setting.NewCfg())
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
-	s := ProvideService(dsStore, secretsService)
+	s := ProvideService(dsStore, secretsService, nil)
 
 	origSecret := setting.SecretKey
 	setting.SecretKey = "dashboard_snapshot_service_test"
@@ -68,3 +71,67 @@ func TestDashboardSnapshotsService(t *testing.T) {
 		require.Equal(t, rawDashboard, decrypted)
 	})
 }
+
+func TestDashboardSnapshotsServiceWithObjectStorage(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.SnapShotRemoveExpired = true
+	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	cfg.ObjectStorage.Snapshots = setting.ObjectStoreSettings{
+		Provider: objectstore.ProviderFile,
+		File:     setting.ObjectStoreFileSettings{Path: t.TempDir()},
+	}
+	objectStorage, err := objectstore.ProvideService(cfg)
+	require.NoError(t, err)
+	s := ProvideService(dsStore, secretsService, objectStorage)
+	payloads := objectStorage.Store(objectstore.ArtifactSnapshots)
+
+	ctx := context.Background()
+	rawDashboard := []byte(`{"id":123}`)
+	dashboard, err := simplejson.NewJson(rawDashboard)
+	require.NoError(t, err)
+
+	t.Run("the payload of the snapshot is in the object storage", func(t *testing.T) {
+		_, err := s.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:       "stored",
+			DeleteKey: "stored-delete",
+			Dashboard: dashboard,
+		})
+		require.NoError(t, err)
+
+		stored, err := dsStore.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "stored"})
+		require.NoError(t, err)
+		assert.True(t, stored.PayloadInStorage)
+		assert.Empty(t, stored.DashboardEncrypted)
+
+		queryResult, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "stored"})
+		require.NoError(t, err)
+		decrypted, err := queryResult.Dashboard.Encode()
+		require.NoError(t, err)
+		require.Equal(t, rawDashboard, decrypted)
+
+		err = s.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: "stored-delete"})
+		require.NoError(t, err)
+		_, err = payloads.Get(ctx, "stored")
+		require.ErrorIs(t, err, objectstore.ErrObjectNotFound)
+	})
+
+	t.Run("the payloads of the expired snapshots are deleted", func(t *testing.T) {
+		_, err := s.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:       "expired",
+			DeleteKey: "expired-delete",
+			Dashboard: dashboard,
+			Expires:   1,
+		})
+		require.NoError(t, err)
+		_, err = payloads.Get(ctx, "expired")
+		require.NoError(t, err)
+
+		cmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{ExpiredBefore: time.Now().Add(time.Minute)}
+		require.NoError(t, s.DeleteExpiredSnapshots(ctx, &cmd))
+		assert.EqualValues(t, 1, cmd.DeletedRows)
+		_, err = payloads.Get(ctx, "expired")
+		require.ErrorIs(t, err, objectstore.ErrObjectNotFound)
+	})
+}
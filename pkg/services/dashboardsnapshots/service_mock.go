@@ -13,6 +13,27 @@ type MockService struct {
 	mock.Mock
 }
 
+// CountDashboardSnapshots provides a mock function with given fields: _a0, _a1
+func (_m *MockService) CountDashboardSnapshots(_a0 context.Context, _a1 *CountDashboardSnapshotsQuery) (int64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, *CountDashboardSnapshotsQuery) int64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *CountDashboardSnapshotsQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) CreateDashboardSnapshot(_a0 context.Context, _a1 *CreateDashboardSnapshotCommand) (*DashboardSnapshot, error) {
 	ret := _m.Called(_a0, _a1)
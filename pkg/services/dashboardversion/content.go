@@ -0,0 +1,128 @@
+package dashver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const CompressionGzip = "gzip"
+
+// DashboardVersionContent is the JSON of dashboard versions, stored once in the dashboard_version_content table
+// for all the versions with the same normalized JSON, which reference it by hash. Most saves only change a few
+// panels of large dashboards, and restores or provisioning save the same JSON over and over.
+type DashboardVersionContent struct {
+	ID   int64  `xorm:"pk autoincr 'id'" db:"id"`
+	Hash string `xorm:"hash" db:"hash"`
+	// Data is the normalized JSON, compressed with Compression
+	Data        []byte `xorm:"data" db:"data"`
+	Compression string `xorm:"compression" db:"compression"`
+	// Size is the size of the normalized JSON before compression
+	Size    int       `xorm:"size" db:"size"`
+	Created time.Time `xorm:"created" db:"created"`
+	// Updated is the last time a version referenced the content, the unreferenced contents are deleted once they
+	// weren't referenced for a while, so that a content deleted concurrently with a save isn't lost.
+	Updated time.Time `xorm:"updated" db:"updated"`
+	// Archived contents are stored in the dashboard version archive, their Data is empty in the database
+	Archived bool `xorm:"archived" db:"archived"`
+}
+
+// NewDashboardVersionContent returns the content of the JSON of a version of a dashboard. The version number
+// saved in the JSON is left out of the normalized JSON, so that the versions only differing by their number share
+// their content.
+func NewDashboardVersionContent(data *simplejson.Json, version int) (*DashboardVersionContent, error) {
+	normalized, err := normalize(data, version)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(normalized)
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(normalized); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &DashboardVersionContent{
+		Hash:        hex.EncodeToString(sum[:]),
+		Data:        buf.Bytes(),
+		Compression: CompressionGzip,
+		Size:        len(normalized),
+		Created:     now,
+		Updated:     now,
+	}, nil
+}
+
+// normalize encodes the JSON with sorted keys and without the version number, when it's the version's.
+func normalize(data *simplejson.Json, version int) ([]byte, error) {
+	m, err := data.Map()
+	if err != nil {
+		return nil, fmt.Errorf("dashboard JSON must be an object: %w", err)
+	}
+
+	normalized := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		normalized[k] = v
+	}
+	if v, ok := m["version"]; ok && isVersion(v, version) {
+		delete(normalized, "version")
+	}
+	// maps are encoded with sorted keys
+	return json.Marshal(normalized)
+}
+
+func isVersion(v interface{}, version int) bool {
+	switch n := v.(type) {
+	case int:
+		return n == version
+	case int64:
+		return n == int64(version)
+	case float64:
+		return n == float64(version)
+	case json.Number:
+		i, err := n.Int64()
+		return err == nil && i == int64(version)
+	}
+	return false
+}
+
+// Decode returns the JSON of the given version of the dashboard.
+func (c *DashboardVersionContent) Decode(version int) (*simplejson.Json, error) {
+	var r io.Reader = bytes.NewReader(c.Data)
+	switch c.Compression {
+	case CompressionGzip:
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = gr.Close() }()
+		r = gr
+	case "":
+	default:
+		return nil, fmt.Errorf("unknown dashboard version compression %q", c.Compression)
+	}
+
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := data.CheckGet("version"); !ok {
+		data.Set("version", version)
+	}
+	return data, nil
+}
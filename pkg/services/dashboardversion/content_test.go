@@ -0,0 +1,53 @@
+package dashver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestNewDashboardVersionContent(t *testing.T) {
+	dashboard := func(version int, title string) *simplejson.Json {
+		return simplejson.NewFromAny(map[string]interface{}{
+			"uid":     "dash",
+			"title":   title,
+			"version": version,
+			"panels":  []interface{}{map[string]interface{}{"id": 1, "type": "graph"}},
+		})
+	}
+
+	v1, err := NewDashboardVersionContent(dashboard(1, "Dash"), 1)
+	require.NoError(t, err)
+	v2, err := NewDashboardVersionContent(dashboard(2, "Dash"), 2)
+	require.NoError(t, err)
+	v3, err := NewDashboardVersionContent(dashboard(3, "Renamed"), 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, v1.Hash, v2.Hash, "the versions only differing by their number share their content")
+	assert.NotEqual(t, v1.Hash, v3.Hash)
+	assert.Equal(t, CompressionGzip, v1.Compression)
+
+	data, err := v1.Decode(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, data.Get("version").MustInt())
+	assert.Equal(t, "Dash", data.Get("title").MustString())
+	assert.Equal(t, "graph", data.Get("panels").GetIndex(0).Get("type").MustString())
+
+	t.Run("keeps a version number which isn't the version's", func(t *testing.T) {
+		c, err := NewDashboardVersionContent(dashboard(5, "Dash"), 6)
+		require.NoError(t, err)
+		assert.NotEqual(t, v1.Hash, c.Hash)
+
+		data, err := c.Decode(6)
+		require.NoError(t, err)
+		assert.Equal(t, 5, data.Get("version").MustInt())
+	})
+
+	t.Run("the JSON must be an object", func(t *testing.T) {
+		_, err := NewDashboardVersionContent(simplejson.NewFromAny([]interface{}{1}), 1)
+		assert.Error(t, err)
+	})
+}
@@ -7,5 +7,6 @@ import (
 type Service interface {
 	Get(context.Context, *GetDashboardVersionQuery) (*DashboardVersionDTO, error)
 	DeleteExpired(context.Context, *DeleteExpiredVersionsCommand) error
+	ArchiveExpired(context.Context, *ArchiveExpiredVersionsCommand) error
 	List(context.Context, *ListDashboardVersionsQuery) ([]*DashboardVersionDTO, error)
 }
@@ -3,9 +3,14 @@ package dashverimpl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/setting"
@@ -14,23 +19,50 @@ import (
 const (
 	maxVersionsToDeletePerBatch = 100
 	maxVersionDeletionBatches   = 50
+
+	// unreferencedContentGracePeriod is how long the contents no version references are kept, so that the contents
+	// referenced again by a save in progress aren't deleted.
+	unreferencedContentGracePeriod = time.Hour
+
+	maxContentsToArchivePerBatch = 100
+	maxContentArchivalBatches    = 50
+	// maxConcurrentArchiveGets is the number of archived contents fetched concurrently by a listing
+	maxConcurrentArchiveGets = 8
 )
 
 type Service struct {
 	store   store
 	dashSvc dashboards.DashboardService
 	log     log.Logger
+	cfg     setting.DashboardVersionArchiveSettings
+	// archive stores the data of the archived contents, nil when no storage is configured
+	archive objectstore.ObjectStore
 }
 
-func ProvideService(db db.DB, dashboardService dashboards.DashboardService) dashver.Service {
-	return &Service{
+func ProvideService(db db.DB, dashboardService dashboards.DashboardService, cfg *setting.Cfg) (dashver.Service, error) {
+	s := &Service{
 		store: &sqlStore{
 			db:      db,
 			dialect: db.GetDialect(),
 		},
 		dashSvc: dashboardService,
 		log:     log.New("dashboard-version"),
+		cfg:     cfg.DashboardVersionArchive,
 	}
+
+	// the storage is also needed with the archival disabled, to read the contents archived before
+	archive, err := objectstore.New(context.Background(), cfg.DashboardVersionArchive.Storage)
+	switch {
+	case errors.Is(err, objectstore.ErrNotConfigured):
+		if s.cfg.Enabled {
+			return nil, errors.New("the dashboard version archive requires a storage provider")
+		}
+	case err != nil:
+		return nil, fmt.Errorf("dashboard version archive: %w", err)
+	default:
+		s.archive = archive
+	}
+	return s, nil
 }
 
 func (s *Service) Get(ctx context.Context, query *dashver.GetDashboardVersionQuery) (*dashver.DashboardVersionDTO, error) {
@@ -58,12 +90,18 @@ func (s *Service) Get(ctx context.Context, query *dashver.GetDashboardVersionQue
 	if err != nil {
 		return nil, err
 	}
+	if err := s.resolveContents(ctx, []*dashver.DashboardVersion{version}); err != nil {
+		return nil, err
+	}
 	version.Data.Set("id", version.DashboardID)
 	return version.ToDTO(query.DashboardUID), nil
 }
 
 func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredVersionsCommand) error {
-	versionsToKeep := setting.DashboardVersionsToKeep
+	versionsToKeep := cmd.VersionsToKeep
+	if versionsToKeep == 0 {
+		versionsToKeep = setting.DashboardVersionsToKeep
+	}
 	if versionsToKeep < 1 {
 		versionsToKeep = 1
 	}
@@ -75,7 +113,7 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 		}
 
 		if len(versionIdsToDelete) < 1 {
-			return nil
+			break
 		}
 
 		deleted, err := s.store.DeleteBatch(ctx, cmd, versionIdsToDelete)
@@ -89,6 +127,48 @@ func (s *Service) DeleteExpired(ctx context.Context, cmd *dashver.DeleteExpiredV
 			break
 		}
 	}
+
+	deletedContents, err := s.store.DeleteUnreferencedContents(ctx, time.Now().Add(-unreferencedContentGracePeriod))
+	if err != nil {
+		return err
+	}
+	if deletedContents > 0 {
+		s.log.Debug("Deleted unreferenced dashboard version contents", "count", deletedContents)
+	}
+	return nil
+}
+
+// ArchiveExpired moves the data of the contents no version referenced since the archive_after setting to the
+// dashboard version archive. The contents are still referenced by their versions, and fetched from the archive
+// when the versions are read.
+func (s *Service) ArchiveExpired(ctx context.Context, cmd *dashver.ArchiveExpiredVersionsCommand) error {
+	if !s.cfg.Enabled || s.archive == nil {
+		return nil
+	}
+
+	olderThan := time.Now().Add(-s.cfg.ArchiveAfter)
+	for batch := 0; batch < maxContentArchivalBatches; batch++ {
+		contents, err := s.store.GetContentsToArchive(ctx, olderThan, maxContentsToArchivePerBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range contents {
+			// the data is uploaded before being removed from the database, a failure in between only leaves an
+			// object uploaded again by the next archival
+			if err := s.archive.Put(ctx, c.Hash, c.Data); err != nil {
+				return fmt.Errorf("failed to archive dashboard version content %s: %w", c.Hash, err)
+			}
+			if err := s.store.SetContentArchived(ctx, c.Hash); err != nil {
+				return err
+			}
+			cmd.ArchivedContents++
+		}
+
+		if len(contents) < maxContentsToArchivePerBatch {
+			break
+		}
+	}
 	return nil
 }
 
@@ -120,6 +200,9 @@ func (s *Service) List(ctx context.Context, query *dashver.ListDashboardVersions
 	if err != nil {
 		return nil, err
 	}
+	if err := s.resolveContents(ctx, dvs); err != nil {
+		return nil, err
+	}
 	dtos := make([]*dashver.DashboardVersionDTO, len(dvs))
 	for i, v := range dvs {
 		dtos[i] = v.ToDTO(query.DashboardUID)
@@ -127,6 +210,73 @@ func (s *Service) List(ctx context.Context, query *dashver.ListDashboardVersions
 	return dtos, nil
 }
 
+// resolveContents sets the JSON of the versions stored in the dashboard_version_content table.
+func (s *Service) resolveContents(ctx context.Context, versions []*dashver.DashboardVersion) error {
+	hashes := make([]string, 0, len(versions))
+	seen := map[string]bool{}
+	for _, v := range versions {
+		if v.ContentHash != "" && !seen[v.ContentHash] {
+			seen[v.ContentHash] = true
+			hashes = append(hashes, v.ContentHash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	contents, err := s.store.GetContents(ctx, hashes)
+	if err != nil {
+		return err
+	}
+	if err := s.fetchArchivedContents(ctx, contents); err != nil {
+		return err
+	}
+	byHash := make(map[string]*dashver.DashboardVersionContent, len(contents))
+	for _, c := range contents {
+		byHash[c.Hash] = c
+	}
+
+	for _, v := range versions {
+		if v.ContentHash == "" {
+			continue
+		}
+		c, ok := byHash[v.ContentHash]
+		if !ok {
+			return fmt.Errorf("content %s of version %d of dashboard %d not found", v.ContentHash, v.Version, v.DashboardID)
+		}
+		data, err := c.Decode(v.Version)
+		if err != nil {
+			return fmt.Errorf("failed to decode version %d of dashboard %d: %w", v.Version, v.DashboardID, err)
+		}
+		v.Data = data
+	}
+	return nil
+}
+
+// fetchArchivedContents sets the data of the archived contents from the dashboard version archive.
+func (s *Service) fetchArchivedContents(ctx context.Context, contents []*dashver.DashboardVersionContent) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentArchiveGets)
+	for _, c := range contents {
+		if !c.Archived {
+			continue
+		}
+		c := c
+		g.Go(func() error {
+			if s.archive == nil {
+				return fmt.Errorf("dashboard version content %s is archived but the dashboard version archive has no storage", c.Hash)
+			}
+			data, err := s.archive.Get(ctx, c.Hash)
+			if err != nil {
+				return fmt.Errorf("failed to get archived dashboard version content %s: %w", c.Hash, err)
+			}
+			c.Data = data
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
 // getDashUIDMaybeEmpty is a helper function which takes a dashboardID and
 // returns the UID. If the dashboard is not found, it will return an empty
 // string.
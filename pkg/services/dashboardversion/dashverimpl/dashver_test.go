@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/setting"
@@ -32,6 +34,25 @@ func TestDashboardVersionService(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, dashboard.ToDTO("uid"), dashboardVersion)
 	})
+
+	t.Run("Get dashboard version with its content", func(t *testing.T) {
+		content, err := dashver.NewDashboardVersionContent(simplejson.NewFromAny(map[string]interface{}{"title": "Dash", "version": 3}), 3)
+		require.NoError(t, err)
+		dashboardVersionStore.ExpectedDashboardVersion = &dashver.DashboardVersion{
+			ID:          12,
+			DashboardID: 42,
+			Version:     3,
+			Data:        simplejson.New(),
+			ContentHash: content.Hash,
+		}
+		dashboardVersionStore.ExpectedContents = []*dashver.DashboardVersionContent{content}
+
+		dashboardVersion, err := dashboardVersionService.Get(context.Background(), &dashver.GetDashboardVersionQuery{DashboardID: 42, DashboardUID: "uid"})
+		require.NoError(t, err)
+		require.Equal(t, "Dash", dashboardVersion.Data.Get("title").MustString())
+		require.Equal(t, 3, dashboardVersion.Data.Get("version").MustInt())
+		require.Equal(t, int64(42), dashboardVersion.Data.Get("id").MustInt64())
+	})
 }
 
 func TestDeleteExpiredVersions(t *testing.T) {
@@ -61,6 +82,74 @@ func TestDeleteExpiredVersions(t *testing.T) {
 	})
 }
 
+func TestArchiveExpiredVersions(t *testing.T) {
+	archiveSettings := setting.DashboardVersionArchiveSettings{
+		Enabled:      true,
+		ArchiveAfter: 24 * time.Hour,
+		Storage: setting.ObjectStoreSettings{
+			Provider: objectstore.ProviderFile,
+			Prefix:   "dashboard-versions/",
+			File:     setting.ObjectStoreFileSettings{Path: t.TempDir()},
+		},
+	}
+	archive, err := objectstore.New(context.Background(), archiveSettings.Storage)
+	require.NoError(t, err)
+
+	newContent := func(t *testing.T) (*dashver.DashboardVersionContent, *dashver.DashboardVersion) {
+		t.Helper()
+		content, err := dashver.NewDashboardVersionContent(simplejson.NewFromAny(map[string]interface{}{"title": "Dash"}), 2)
+		require.NoError(t, err)
+		return content, &dashver.DashboardVersion{DashboardID: 42, Version: 2, Data: simplejson.New(), ContentHash: content.Hash}
+	}
+
+	t.Run("Archive the contents and read them from the archive", func(t *testing.T) {
+		dashboardVersionStore := newDashboardVersionStoreFake()
+		dashboardVersionService := Service{store: dashboardVersionStore, log: log.NewNopLogger(), cfg: archiveSettings, archive: archive}
+		content, version := newContent(t)
+		data := content.Data
+		dashboardVersionStore.ExpectedContents = []*dashver.DashboardVersionContent{content}
+
+		cmd := dashver.ArchiveExpiredVersionsCommand{}
+		require.NoError(t, dashboardVersionService.ArchiveExpired(context.Background(), &cmd))
+		require.EqualValues(t, 1, cmd.ArchivedContents)
+		require.True(t, content.Archived)
+		require.Empty(t, content.Data)
+
+		archived, err := archive.Get(context.Background(), content.Hash)
+		require.NoError(t, err)
+		require.Equal(t, data, archived)
+
+		require.NoError(t, dashboardVersionService.resolveContents(context.Background(), []*dashver.DashboardVersion{version}))
+		require.Equal(t, "Dash", version.Data.Get("title").MustString())
+	})
+
+	t.Run("Don't archive anything when the archival is disabled", func(t *testing.T) {
+		dashboardVersionStore := newDashboardVersionStoreFake()
+		disabled := archiveSettings
+		disabled.Enabled = false
+		dashboardVersionService := Service{store: dashboardVersionStore, log: log.NewNopLogger(), cfg: disabled, archive: archive}
+		content, _ := newContent(t)
+		dashboardVersionStore.ExpectedContents = []*dashver.DashboardVersionContent{content}
+
+		cmd := dashver.ArchiveExpiredVersionsCommand{}
+		require.NoError(t, dashboardVersionService.ArchiveExpired(context.Background(), &cmd))
+		require.Zero(t, cmd.ArchivedContents)
+		require.False(t, content.Archived)
+	})
+
+	t.Run("Fail to read an archived content without storage", func(t *testing.T) {
+		dashboardVersionStore := newDashboardVersionStoreFake()
+		dashboardVersionService := Service{store: dashboardVersionStore, log: log.NewNopLogger()}
+		content, version := newContent(t)
+		content.Archived = true
+		content.Data = []byte{}
+		dashboardVersionStore.ExpectedContents = []*dashver.DashboardVersionContent{content}
+
+		err := dashboardVersionService.resolveContents(context.Background(), []*dashver.DashboardVersion{version})
+		require.Error(t, err)
+	})
+}
+
 func TestListDashboardVersions(t *testing.T) {
 	t.Run("List all versions for a given Dashboard ID", func(t *testing.T) {
 		dashboardVersionStore := newDashboardVersionStoreFake()
@@ -149,6 +238,7 @@ type FakeDashboardVersionStore struct {
 	ExptectedDeletedVersions int64
 	ExpectedVersions         []interface{}
 	ExpectedListVersions     []*dashver.DashboardVersion
+	ExpectedContents         []*dashver.DashboardVersionContent
 	ExpectedError            error
 }
 
@@ -171,3 +261,31 @@ func (f *FakeDashboardVersionStore) DeleteBatch(ctx context.Context, cmd *dashve
 func (f *FakeDashboardVersionStore) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error) {
 	return f.ExpectedListVersions, f.ExpectedError
 }
+
+func (f *FakeDashboardVersionStore) GetContents(ctx context.Context, hashes []string) ([]*dashver.DashboardVersionContent, error) {
+	return f.ExpectedContents, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) DeleteUnreferencedContents(ctx context.Context, olderThan time.Time) (int64, error) {
+	return 0, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) GetContentsToArchive(ctx context.Context, olderThan time.Time, limit int) ([]*dashver.DashboardVersionContent, error) {
+	contents := make([]*dashver.DashboardVersionContent, 0, len(f.ExpectedContents))
+	for _, c := range f.ExpectedContents {
+		if !c.Archived {
+			contents = append(contents, c)
+		}
+	}
+	return contents, f.ExpectedError
+}
+
+func (f *FakeDashboardVersionStore) SetContentArchived(ctx context.Context, hash string) error {
+	for _, c := range f.ExpectedContents {
+		if c.Hash == hash {
+			c.Data = []byte{}
+			c.Archived = true
+		}
+	}
+	return f.ExpectedError
+}
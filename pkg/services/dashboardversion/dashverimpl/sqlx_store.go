@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/sqlstore/session"
@@ -37,9 +38,15 @@ func (ss *sqlxStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVer
 		GROUP BY dashboard_id
 	) AS vtd
 	WHERE dashboard_version.dashboard_id=vtd.dashboard_id
-	AND version < vtd.min + vtd.count - ?
-	LIMIT ?`
-	err := ss.sess.Get(ctx, &versionIds, versionIdsToDeleteQuery, versionsToKeep, perBatch)
+	AND version < vtd.min + vtd.count - ?`
+	args := []interface{}{versionsToKeep}
+	if !cmd.OlderThan.IsZero() {
+		versionIdsToDeleteQuery += ` AND dashboard_version.created < ?`
+		args = append(args, cmd.OlderThan)
+	}
+	versionIdsToDeleteQuery += ` LIMIT ?`
+	args = append(args, perBatch)
+	err := ss.sess.Select(ctx, &versionIds, versionIdsToDeleteQuery, args...)
 	return versionIds, err
 }
 
@@ -68,7 +75,8 @@ func (ss *sqlxStore) List(ctx context.Context, query *dashver.ListDashboardVersi
 				dashboard_version.version,
 				dashboard_version.created,
 				dashboard_version.created_by,
-				dashboard_version.message
+				dashboard_version.message,
+				dashboard_version.content_hash
 			FROM dashboard_version
 			LEFT JOIN dashboard ON dashboard.id = dashboard_version.dashboard_id
 			WHERE dashboard_version.dashboard_id=? AND dashboard.org_id=?
@@ -84,3 +92,40 @@ func (ss *sqlxStore) List(ctx context.Context, query *dashver.ListDashboardVersi
 	}
 	return dashboardVersion, nil
 }
+
+func (ss *sqlxStore) GetContents(ctx context.Context, hashes []string) ([]*dashver.DashboardVersionContent, error) {
+	contents := make([]*dashver.DashboardVersionContent, 0, len(hashes))
+	if len(hashes) == 0 {
+		return contents, nil
+	}
+	qr := `SELECT * FROM dashboard_version_content WHERE hash IN (?` + strings.Repeat(",?", len(hashes)-1) + `)`
+	args := make([]interface{}, len(hashes))
+	for i, h := range hashes {
+		args[i] = h
+	}
+	err := ss.sess.Select(ctx, &contents, qr, args...)
+	return contents, err
+}
+
+func (ss *sqlxStore) DeleteUnreferencedContents(ctx context.Context, olderThan time.Time) (int64, error) {
+	res, err := ss.sess.Exec(ctx, `DELETE FROM dashboard_version_content WHERE updated < ? AND NOT EXISTS (
+		SELECT 1 FROM dashboard_version WHERE dashboard_version.content_hash = dashboard_version_content.hash
+	)`, olderThan)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (ss *sqlxStore) GetContentsToArchive(ctx context.Context, olderThan time.Time, limit int) ([]*dashver.DashboardVersionContent, error) {
+	contents := make([]*dashver.DashboardVersionContent, 0, limit)
+	err := ss.sess.Select(ctx, &contents, `SELECT * FROM dashboard_version_content WHERE archived = ? AND updated < ? AND EXISTS (
+		SELECT 1 FROM dashboard_version WHERE dashboard_version.content_hash = dashboard_version_content.hash
+	) ORDER BY id LIMIT ?`, false, olderThan, limit)
+	return contents, err
+}
+
+func (ss *sqlxStore) SetContentArchived(ctx context.Context, hash string) error {
+	_, err := ss.sess.Exec(ctx, "UPDATE dashboard_version_content SET data = ?, archived = ? WHERE hash = ?", []byte{}, true, hash)
+	return err
+}
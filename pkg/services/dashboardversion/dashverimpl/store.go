@@ -2,6 +2,7 @@ package dashverimpl
 
 import (
 	"context"
+	"time"
 
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 )
@@ -11,4 +12,11 @@ type store interface {
 	GetBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, int, int) ([]interface{}, error)
 	DeleteBatch(context.Context, *dashver.DeleteExpiredVersionsCommand, []interface{}) (int64, error)
 	List(context.Context, *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersion, error)
+	GetContents(ctx context.Context, hashes []string) ([]*dashver.DashboardVersionContent, error)
+	// DeleteUnreferencedContents deletes the contents no version references, which weren't referenced since olderThan.
+	DeleteUnreferencedContents(ctx context.Context, olderThan time.Time) (int64, error)
+	// GetContentsToArchive returns the referenced contents not archived yet, which weren't referenced since olderThan.
+	GetContentsToArchive(ctx context.Context, olderThan time.Time, limit int) ([]*dashver.DashboardVersionContent, error)
+	// SetContentArchived empties the data of a content stored in the dashboard version archive.
+	SetContentArchived(ctx context.Context, hash string) error
 }
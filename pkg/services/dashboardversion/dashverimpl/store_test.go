@@ -78,6 +78,99 @@ func testIntegrationGetDashboardVersion(t *testing.T, fn getStore) {
 		assert.EqualValues(t, 4, res)
 	})
 
+	t.Run("Get and clean up the contents of the versions", func(t *testing.T) {
+		savedDash := insertTestDashboard(t, ss, "test dash 78", 1, 0, false)
+		referenced, err := dashver.NewDashboardVersionContent(simplejson.NewFromAny(map[string]interface{}{"title": "referenced"}), 2)
+		require.NoError(t, err)
+		unreferenced, err := dashver.NewDashboardVersionContent(simplejson.NewFromAny(map[string]interface{}{"title": "unreferenced"}), 2)
+		require.NoError(t, err)
+
+		err = ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+			if _, err := sess.Table("dashboard_version_content").Insert(referenced); err != nil {
+				return err
+			}
+			if _, err := sess.Table("dashboard_version_content").Insert(unreferenced); err != nil {
+				return err
+			}
+			_, err := sess.Insert(&dashver.DashboardVersion{
+				DashboardID: savedDash.ID,
+				Version:     2,
+				Created:     time.Now(),
+				Data:        simplejson.New(),
+				ContentHash: referenced.Hash,
+			})
+			return err
+		})
+		require.NoError(t, err)
+
+		contents, err := dashVerStore.GetContents(context.Background(), []string{referenced.Hash, unreferenced.Hash})
+		require.NoError(t, err)
+		assert.Len(t, contents, 2)
+
+		deleted, err := dashVerStore.DeleteUnreferencedContents(context.Background(), time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted, "the recently referenced contents are kept")
+
+		deleted, err = dashVerStore.DeleteUnreferencedContents(context.Background(), time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		contents, err = dashVerStore.GetContents(context.Background(), []string{referenced.Hash, unreferenced.Hash})
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		assert.Equal(t, referenced.Hash, contents[0].Hash)
+	})
+
+	t.Run("Archive the contents of the old versions", func(t *testing.T) {
+		savedDash := insertTestDashboard(t, ss, "test dash 79", 1, 0, false)
+		old, err := dashver.NewDashboardVersionContent(simplejson.NewFromAny(map[string]interface{}{"title": "old"}), 2)
+		require.NoError(t, err)
+		recent, err := dashver.NewDashboardVersionContent(simplejson.NewFromAny(map[string]interface{}{"title": "recent"}), 3)
+		require.NoError(t, err)
+
+		err = ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+			for _, c := range []*dashver.DashboardVersionContent{old, recent} {
+				if _, err := sess.Table("dashboard_version_content").Insert(c); err != nil {
+					return err
+				}
+			}
+			for i, c := range []*dashver.DashboardVersionContent{old, recent} {
+				if _, err := sess.Insert(&dashver.DashboardVersion{
+					DashboardID: savedDash.ID,
+					Version:     i + 2,
+					Created:     time.Now(),
+					Data:        simplejson.New(),
+					ContentHash: c.Hash,
+				}); err != nil {
+					return err
+				}
+			}
+			// xorm sets the updated column on insert
+			_, err := sess.Exec("UPDATE dashboard_version_content SET updated = ? WHERE hash = ?", time.Now().Add(-48*time.Hour), old.Hash)
+			return err
+		})
+		require.NoError(t, err)
+
+		contents, err := dashVerStore.GetContentsToArchive(context.Background(), time.Now().Add(-24*time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		assert.Equal(t, old.Hash, contents[0].Hash)
+		assert.Equal(t, old.Data, contents[0].Data)
+
+		err = dashVerStore.SetContentArchived(context.Background(), old.Hash)
+		require.NoError(t, err)
+
+		contents, err = dashVerStore.GetContents(context.Background(), []string{old.Hash})
+		require.NoError(t, err)
+		require.Len(t, contents, 1)
+		assert.True(t, contents[0].Archived)
+		assert.Empty(t, contents[0].Data)
+
+		contents, err = dashVerStore.GetContentsToArchive(context.Background(), time.Now().Add(-24*time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, contents)
+	})
+
 	savedDash := insertTestDashboard(t, ss, "test dash 43", 1, 0, false, "diff-all")
 	t.Run("Get all versions for a given Dashboard ID", func(t *testing.T) {
 		query := dashver.ListDashboardVersionsQuery{
@@ -110,6 +203,18 @@ func testIntegrationGetDashboardVersion(t *testing.T, fn getStore) {
 		require.Nil(t, err)
 		assert.Equal(t, 2, len(res))
 	})
+
+	t.Run("Only get the expired versions created before a time", func(t *testing.T) {
+		ids, err := dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{}, 100, 1)
+		require.NoError(t, err)
+		assert.NotEmpty(t, ids)
+
+		ids, err = dashVerStore.GetBatch(context.Background(), &dashver.DeleteExpiredVersionsCommand{
+			OlderThan: time.Now().Add(-time.Hour),
+		}, 100, 1)
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+	})
 }
 
 func getDashboard(t *testing.T, sqlStore db.DB, dashboard *dashboards.Dashboard) error {
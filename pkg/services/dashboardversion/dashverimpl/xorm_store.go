@@ -3,6 +3,7 @@ package dashverimpl
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
@@ -46,10 +47,16 @@ func (ss *sqlStore) GetBatch(ctx context.Context, cmd *dashver.DeleteExpiredVers
 				GROUP BY dashboard_id
 			) AS vtd
 			WHERE dashboard_version.dashboard_id=vtd.dashboard_id
-			AND version < vtd.min + vtd.count - ?
-			LIMIT ?`
+			AND version < vtd.min + vtd.count - ?`
+		args := []interface{}{versionsToKeep}
+		if !cmd.OlderThan.IsZero() {
+			versionIdsToDeleteQuery += ` AND dashboard_version.created < ?`
+			args = append(args, cmd.OlderThan)
+		}
+		versionIdsToDeleteQuery += ` LIMIT ?`
+		args = append(args, perBatch)
 
-		err := sess.SQL(versionIdsToDeleteQuery, versionsToKeep, perBatch).Find(&versionIds)
+		err := sess.SQL(versionIdsToDeleteQuery, args...).Find(&versionIds)
 		return err
 	})
 	return versionIds, err
@@ -83,7 +90,8 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 				dashboard_version.created,
 				dashboard_version.created_by,
 				dashboard_version.message,
-				dashboard_version.data`).
+				dashboard_version.data,
+				dashboard_version.content_hash`).
 			Join("LEFT", "dashboard", `dashboard.id = dashboard_version.dashboard_id`).
 			Where("dashboard_version.dashboard_id=? AND dashboard.org_id=?", query.DashboardID, query.OrgID).
 			OrderBy("dashboard_version.version DESC").
@@ -103,3 +111,46 @@ func (ss *sqlStore) List(ctx context.Context, query *dashver.ListDashboardVersio
 	}
 	return dashboardVersion, nil
 }
+
+func (ss *sqlStore) GetContents(ctx context.Context, hashes []string) ([]*dashver.DashboardVersionContent, error) {
+	contents := make([]*dashver.DashboardVersionContent, 0, len(hashes))
+	if len(hashes) == 0 {
+		return contents, nil
+	}
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard_version_content").In("hash", hashes).Find(&contents)
+	})
+	return contents, err
+}
+
+func (ss *sqlStore) DeleteUnreferencedContents(ctx context.Context, olderThan time.Time) (int64, error) {
+	var deleted int64
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec(`DELETE FROM dashboard_version_content WHERE updated < ? AND NOT EXISTS (
+			SELECT 1 FROM dashboard_version WHERE dashboard_version.content_hash = dashboard_version_content.hash
+		)`, olderThan)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
+func (ss *sqlStore) GetContentsToArchive(ctx context.Context, olderThan time.Time, limit int) ([]*dashver.DashboardVersionContent, error) {
+	contents := make([]*dashver.DashboardVersionContent, 0, limit)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(`SELECT * FROM dashboard_version_content WHERE archived = ? AND updated < ? AND EXISTS (
+			SELECT 1 FROM dashboard_version WHERE dashboard_version.content_hash = dashboard_version_content.hash
+		) ORDER BY id LIMIT ?`, false, olderThan, limit).Find(&contents)
+	})
+	return contents, err
+}
+
+func (ss *sqlStore) SetContentArchived(ctx context.Context, hash string) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_version_content SET data = ?, archived = ? WHERE hash = ?", []byte{}, true, hash)
+		return err
+	})
+}
@@ -30,6 +30,10 @@ func (f *FakeDashboardVersionService) DeleteExpired(ctx context.Context, cmd *da
 	return f.ExpectedError
 }
 
+func (f *FakeDashboardVersionService) ArchiveExpired(ctx context.Context, cmd *dashver.ArchiveExpiredVersionsCommand) error {
+	return f.ExpectedError
+}
+
 func (f *FakeDashboardVersionService) List(ctx context.Context, query *dashver.ListDashboardVersionsQuery) ([]*dashver.DashboardVersionDTO, error) {
 	return f.ExpectedListDashboarVersions, f.ExpectedError
 }
@@ -28,6 +28,8 @@ type DashboardVersion struct {
 
 	Message string           `json:"message" db:"message"`
 	Data    *simplejson.Json `json:"data" db:"data"`
+	// ContentHash references the DashboardVersionContent with the JSON of the version, the JSON is in Data when empty
+	ContentHash string `json:"-" xorm:"content_hash" db:"content_hash"`
 }
 
 // ToDTO converts a DashboardVersion to a DashboardVersionDTO.
@@ -56,9 +58,19 @@ type GetDashboardVersionQuery struct {
 }
 
 type DeleteExpiredVersionsCommand struct {
+	// VersionsToKeep is the number of versions kept per dashboard, the versions_to_keep setting when 0
+	VersionsToKeep int
+	// OlderThan only deletes the versions created before it when set
+	OlderThan   time.Time
 	DeletedRows int64
 }
 
+// ArchiveExpiredVersionsCommand moves the contents of the versions older than the archive_after setting to the
+// dashboard version archive.
+type ArchiveExpiredVersionsCommand struct {
+	ArchivedContents int64
+}
+
 type ListDashboardVersionsQuery struct {
 	DashboardID  int64
 	DashboardUID string
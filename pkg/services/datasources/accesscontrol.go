@@ -1,6 +1,9 @@
 package datasources
 
-import "github.com/grafana/grafana/pkg/services/accesscontrol"
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/user"
+)
 
 const (
 	ScopeRoot   = "datasources"
@@ -48,3 +51,26 @@ var (
 		accesscontrol.EvalPermission(ActionWrite),
 	)
 )
+
+// CanAccessRestricted returns true if the user can access a data source restricted to the teams: the members of
+// the teams, the users who can edit the data source, the server admins and the internal users of Grafana, such as
+// the alert rule scheduler, can access it.
+func CanAccessRestricted(u *user.SignedInUser, ds *DataSource, teamIDs []int64) bool {
+	if len(teamIDs) == 0 || u == nil || u.IsGrafanaAdmin || u.UserID < 0 {
+		return true
+	}
+	if u.Permissions != nil {
+		uids, hasWildcard := accesscontrol.ParseScopes(ScopePrefix, u.Permissions[u.OrgID][ActionWrite])
+		if _, ok := uids[ds.UID]; hasWildcard || ok {
+			return true
+		}
+	}
+	for _, teamID := range teamIDs {
+		for _, userTeamID := range u.Teams {
+			if teamID == userTeamID {
+				return true
+			}
+		}
+	}
+	return false
+}
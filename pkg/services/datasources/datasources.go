@@ -21,6 +21,12 @@ type DataSourceService interface {
 	// GetAllDataSources gets all datasources.
 	GetAllDataSources(ctx context.Context, query *GetAllDataSourcesQuery) error
 
+	// GetDataSourceTeams gets the teams a datasource is restricted to.
+	GetDataSourceTeams(ctx context.Context, query *GetDataSourceTeamsQuery) ([]int64, error)
+
+	// SetDataSourceTeams restricts a datasource to teams.
+	SetDataSourceTeams(ctx context.Context, cmd *SetDataSourceTeamsCommand) error
+
 	// GetDataSourcesByType gets datasources by type.
 	GetDataSourcesByType(ctx context.Context, query *GetDataSourcesByTypeQuery) ([]*DataSource, error)
 
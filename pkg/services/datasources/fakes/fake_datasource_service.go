@@ -14,6 +14,7 @@ type FakeDataSourceService struct {
 	lastID                int64
 	DataSources           []*datasources.DataSource
 	SimulatePluginFailure bool
+	DataSourceTeams       map[int64][]int64
 }
 
 var _ datasources.DataSourceService = &FakeDataSourceService{}
@@ -46,6 +47,18 @@ func (s *FakeDataSourceService) GetAllDataSources(ctx context.Context, query *da
 	return nil
 }
 
+func (s *FakeDataSourceService) GetDataSourceTeams(ctx context.Context, query *datasources.GetDataSourceTeamsQuery) ([]int64, error) {
+	return s.DataSourceTeams[query.DataSourceID], nil
+}
+
+func (s *FakeDataSourceService) SetDataSourceTeams(ctx context.Context, cmd *datasources.SetDataSourceTeamsCommand) error {
+	if s.DataSourceTeams == nil {
+		s.DataSourceTeams = make(map[int64][]int64)
+	}
+	s.DataSourceTeams[cmd.DataSourceID] = cmd.TeamIDs
+	return nil
+}
+
 func (s *FakeDataSourceService) GetDataSourcesByType(ctx context.Context, query *datasources.GetDataSourcesByTypeQuery) ([]*datasources.DataSource, error) {
 	var dataSources []*datasources.DataSource
 	for _, datasource := range s.DataSources {
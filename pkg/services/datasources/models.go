@@ -72,6 +72,16 @@ func (ds DataSource) AllowedCookies() []string {
 	return []string{}
 }
 
+// DataSourceTeam restricts a data source to a team. A data source restricted to teams is only visible to
+// the members of the teams and to the users who can edit it.
+type DataSourceTeam struct {
+	ID           int64     `xorm:"pk autoincr 'id'"`
+	OrgID        int64     `xorm:"org_id"`
+	DataSourceID int64     `xorm:"data_source_id"`
+	TeamID       int64     `xorm:"team_id"`
+	Created      time.Time `xorm:"created"`
+}
+
 // Specific error type for grpc secrets management so that we can show more detailed plugin errors to users
 type ErrDatasourceSecretsPluginUserFriendly struct {
 	Err string
@@ -145,6 +155,13 @@ type DeleteDataSourceCommand struct {
 	UpdateSecretFn UpdateSecretFn
 }
 
+// SetDataSourceTeamsCommand restricts a data source to the teams, or removes the restriction if there is no team.
+type SetDataSourceTeamsCommand struct {
+	OrgID        int64
+	DataSourceID int64
+	TeamIDs      []int64
+}
+
 // Function for updating secrets along with datasources, to ensure atomicity
 type UpdateSecretFn func() error
 
@@ -181,6 +198,12 @@ type GetDataSourceQuery struct {
 	OrgID int64
 }
 
+// GetDataSourceTeamsQuery returns the teams a data source is restricted to.
+type GetDataSourceTeamsQuery struct {
+	OrgID        int64
+	DataSourceID int64
+}
+
 type DatasourcesPermissionFilterQuery struct {
 	User        *user.SignedInUser
 	Datasources []*DataSource
@@ -15,17 +15,60 @@ type DatasourcePermissionsService interface {
 	FilterDatasourceUidsBasedOnQueryPermissions(ctx context.Context, user *user.SignedInUser, datasourceUids []string) ([]string, error)
 }
 
-// dummy method
+// FilterDatasourcesBasedOnQueryPermissions removes the datasources restricted to teams that the user cannot access.
 func (hs *OSSDatasourcePermissionsService) FilterDatasourcesBasedOnQueryPermissions(ctx context.Context, cmd *datasources.DatasourcesPermissionFilterQuery) ([]*datasources.DataSource, error) {
-	return nil, ErrNotImplemented
+	if cmd.User == nil {
+		return cmd.Datasources, nil
+	}
+	accessible, err := hs.accessibleDatasources(ctx, cmd.User)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*datasources.DataSource, 0, len(cmd.Datasources))
+	for _, ds := range cmd.Datasources {
+		if _, ok := accessible[ds.UID]; ok {
+			filtered = append(filtered, ds)
+		}
+	}
+	return filtered, nil
 }
 
+// FilterDatasourceUidsBasedOnQueryPermissions removes the uids of the datasources restricted to teams that the user cannot access.
 func (hs *OSSDatasourcePermissionsService) FilterDatasourceUidsBasedOnQueryPermissions(ctx context.Context, user *user.SignedInUser, datasourceUids []string) ([]string, error) {
-	return nil, ErrNotImplemented
+	if user == nil {
+		return datasourceUids, nil
+	}
+	accessible, err := hs.accessibleDatasources(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]string, 0, len(datasourceUids))
+	for _, uid := range datasourceUids {
+		if _, ok := accessible[uid]; ok {
+			filtered = append(filtered, uid)
+		}
+	}
+	return filtered, nil
 }
 
-type OSSDatasourcePermissionsService struct{}
+// accessibleDatasources returns the uids of the datasources of the organization of the user that are not restricted
+// to teams the user cannot access, the restrictions are applied by the store in a single query.
+func (hs *OSSDatasourcePermissionsService) accessibleDatasources(ctx context.Context, user *user.SignedInUser) (map[string]struct{}, error) {
+	dataSources, err := hs.dataSourceService.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: user.OrgID, User: user})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]struct{}, len(dataSources))
+	for _, ds := range dataSources {
+		result[ds.UID] = struct{}{}
+	}
+	return result, nil
+}
+
+type OSSDatasourcePermissionsService struct {
+	dataSourceService datasources.DataSourceService
+}
 
-func ProvideDatasourcePermissionsService() *OSSDatasourcePermissionsService {
-	return &OSSDatasourcePermissionsService{}
+func ProvideDatasourcePermissionsService(dataSourceService datasources.DataSourceService) *OSSDatasourcePermissionsService {
+	return &OSSDatasourcePermissionsService{dataSourceService: dataSourceService}
 }
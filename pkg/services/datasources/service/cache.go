@@ -44,7 +44,7 @@ func (dc *CacheServiceImpl) GetDatasource(
 		if cached, found := dc.CacheService.Get(cacheKey); found {
 			ds := cached.(*datasources.DataSource)
 			if ds.OrgID == user.OrgID {
-				return ds, nil
+				return dc.checkTeams(ctx, ds, user, skipCache)
 			}
 		}
 	}
@@ -62,7 +62,7 @@ func (dc *CacheServiceImpl) GetDatasource(
 		dc.CacheService.Set(uidKey(ds.OrgID, ds.UID), ds, time.Second*5)
 	}
 	dc.CacheService.Set(cacheKey, ds, dc.cacheTTL)
-	return ds, nil
+	return dc.checkTeams(ctx, ds, user, skipCache)
 }
 
 func (dc *CacheServiceImpl) GetDatasourceByUID(
@@ -83,7 +83,7 @@ func (dc *CacheServiceImpl) GetDatasourceByUID(
 		if cached, found := dc.CacheService.Get(uidCacheKey); found {
 			ds := cached.(*datasources.DataSource)
 			if ds.OrgID == user.OrgID {
-				return ds, nil
+				return dc.checkTeams(ctx, ds, user, skipCache)
 			}
 		}
 	}
@@ -98,6 +98,29 @@ func (dc *CacheServiceImpl) GetDatasourceByUID(
 
 	dc.CacheService.Set(uidCacheKey, ds, dc.cacheTTL)
 	dc.CacheService.Set(idKey(ds.ID), ds, dc.cacheTTL)
+	return dc.checkTeams(ctx, ds, user, skipCache)
+}
+
+// checkTeams returns datasources.ErrDataSourceAccessDenied if the data source is restricted to teams and the user
+// cannot access it.
+func (dc *CacheServiceImpl) checkTeams(ctx context.Context, ds *datasources.DataSource, user *user.SignedInUser, skipCache bool) (*datasources.DataSource, error) {
+	cacheKey := teamsKey(ds.ID)
+	var teamIDs []int64
+	if cached, found := dc.CacheService.Get(cacheKey); found && !skipCache {
+		teamIDs = cached.([]int64)
+	} else {
+		ss := SqlStore{db: dc.SQLStore, logger: dc.logger}
+		var err error
+		teamIDs, err = ss.GetDataSourceTeams(ctx, &datasources.GetDataSourceTeamsQuery{OrgID: ds.OrgID, DataSourceID: ds.ID})
+		if err != nil {
+			return nil, err
+		}
+		dc.CacheService.Set(cacheKey, teamIDs, dc.cacheTTL)
+	}
+
+	if !datasources.CanAccessRestricted(user, ds, teamIDs) {
+		return nil, datasources.ErrDataSourceAccessDenied
+	}
 	return ds, nil
 }
 
@@ -105,6 +128,10 @@ func idKey(id int64) string {
 	return fmt.Sprintf("ds-%d", id)
 }
 
+func teamsKey(id int64) string {
+	return fmt.Sprintf("ds-teams-%d", id)
+}
+
 func uidKey(orgID int64, uid string) string {
 	return fmt.Sprintf("ds-orgid-uid-%d-%s", orgID, uid)
 }
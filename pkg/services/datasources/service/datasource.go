@@ -26,6 +26,10 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// secretsConsumer is the consumer referencing the secrets of the data sources, a data source secret cannot be
+// deleted by another consumer.
+const secretsConsumer = "datasources"
+
 type Service struct {
 	SQLStore           Store
 	SecretsStore       kvstore.SecretsKVStore
@@ -165,6 +169,14 @@ func (s *Service) GetAllDataSources(ctx context.Context, query *datasources.GetA
 	return s.SQLStore.GetAllDataSources(ctx, query)
 }
 
+func (s *Service) GetDataSourceTeams(ctx context.Context, query *datasources.GetDataSourceTeamsQuery) ([]int64, error) {
+	return s.SQLStore.GetDataSourceTeams(ctx, query)
+}
+
+func (s *Service) SetDataSourceTeams(ctx context.Context, cmd *datasources.SetDataSourceTeamsCommand) error {
+	return s.SQLStore.SetDataSourceTeams(ctx, cmd)
+}
+
 func (s *Service) GetDataSourcesByType(ctx context.Context, query *datasources.GetDataSourcesByTypeQuery) ([]*datasources.DataSource, error) {
 	return s.SQLStore.GetDataSourcesByType(ctx, query)
 }
@@ -189,7 +201,7 @@ func (s *Service) AddDataSource(ctx context.Context, cmd *datasources.AddDataSou
 				return err
 			}
 
-			return s.SecretsStore.Set(ctx, cmd.OrgID, cmd.Name, kvstore.DataSourceSecretType, string(secret))
+			return s.setSecret(ctx, cmd.OrgID, cmd.Name, string(secret))
 		}
 
 		dataSource, err = s.SQLStore.AddDataSource(ctx, cmd)
@@ -222,7 +234,8 @@ func (s *Service) AddDataSource(ctx context.Context, cmd *datasources.AddDataSou
 func (s *Service) DeleteDataSource(ctx context.Context, cmd *datasources.DeleteDataSourceCommand) error {
 	return s.db.InTransaction(ctx, func(ctx context.Context) error {
 		cmd.UpdateSecretFn = func() error {
-			return s.SecretsStore.Del(ctx, cmd.OrgID, cmd.Name, kvstore.DataSourceSecretType)
+			// the reference of the data source to its secret is removed with the secret
+			return s.SecretsStore.Del(secrets.WithConsumer(ctx, secretsConsumer), cmd.OrgID, cmd.Name, kvstore.DataSourceSecretType)
 		}
 
 		return s.SQLStore.DeleteDataSource(ctx, cmd)
@@ -262,7 +275,7 @@ func (s *Service) UpdateDataSource(ctx context.Context, cmd *datasources.UpdateD
 					}
 				}
 
-				return s.SecretsStore.Set(ctx, cmd.OrgID, cmd.Name, kvstore.DataSourceSecretType, string(secret))
+				return s.setSecret(ctx, cmd.OrgID, cmd.Name, string(secret))
 			}
 		}
 
@@ -271,6 +284,14 @@ func (s *Service) UpdateDataSource(ctx context.Context, cmd *datasources.UpdateD
 	})
 }
 
+// setSecret stores the secret of a data source and records the reference of the data source to it.
+func (s *Service) setSecret(ctx context.Context, orgID int64, name string, secret string) error {
+	if err := s.SecretsStore.Set(ctx, orgID, name, kvstore.DataSourceSecretType, secret); err != nil {
+		return err
+	}
+	return kvstore.GetUsageTrackerFromCache(s.SecretsStore).AddReference(ctx, orgID, name, kvstore.DataSourceSecretType, secretsConsumer)
+}
+
 func (s *Service) GetDefaultDataSource(ctx context.Context, query *datasources.GetDefaultDataSourceQuery) (*datasources.DataSource, error) {
 	return s.SQLStore.GetDefaultDataSource(ctx, query)
 }
@@ -325,6 +346,9 @@ func (s *Service) GetTLSConfig(ctx context.Context, ds *datasources.DataSource,
 }
 
 func (s *Service) DecryptedValues(ctx context.Context, ds *datasources.DataSource) (map[string]string, error) {
+	if secrets.ConsumerFromContext(ctx) == secrets.UnknownConsumer {
+		ctx = secrets.WithConsumer(ctx, secretsConsumer)
+	}
 	decryptedValues := make(map[string]string)
 	secret, exist, err := s.SecretsStore.Get(ctx, ds.OrgID, ds.Name, kvstore.DataSourceSecretType)
 	if err != nil {
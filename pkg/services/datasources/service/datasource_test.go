@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	acmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -773,3 +774,44 @@ Wtnpl+TdAoGAGqKqo2KU3JoY3IuTDUk1dsNAm8jd9EWDh+s1x4aG4N79mwcss5GD
 FF8MbFPneK7xQd8L6HisKUDAUi2NOyynM81LAftPkvN6ZuUVeFDfCL4vCA0HUXLD
 +VrOhtUZkNNJlLMiVRJuQKUOGlg8PpObqYbstQAf/0/yFJMRHG82Tcg=
 -----END RSA PRIVATE KEY-----`
+
+func TestIntegrationService_SecretReference(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	sqlStore := db.InitTestDB(t)
+	secretsService := secretsmng.SetupTestService(t, fakes.NewFakeSecretsStore())
+	usageTracker := secretskvs.ProvideUsageTracker(sqlStore)
+	secretsStore, err := secretskvs.ProvideService(sqlStore, secretsService, secretskvs.NewFakeSecretsPluginManager(t, false),
+		kvstore.ProvideService(sqlStore), featuremgmt.WithFeatures(), secretskvs.SetupTestConfig(t), usageTracker)
+	require.NoError(t, err)
+	dsService, err := ProvideService(sqlStore, secretsService, secretsStore, nil, featuremgmt.WithFeatures(), acmock.New().WithDisabled(), acmock.NewMockedPermissionsService(), quotatest.New(false, nil))
+	require.NoError(t, err)
+
+	ds, err := dsService.AddDataSource(ctx, &datasources.AddDataSourceCommand{
+		OrgID:          1,
+		Name:           "prometheus",
+		Type:           "prometheus",
+		SecureJsonData: map[string]string{"password": "securePassword"},
+	})
+	require.NoError(t, err)
+
+	t.Run("should reference the secret of a saved data source", func(t *testing.T) {
+		reports, err := usageTracker.Report(ctx, &secretskvs.SecretUsageQuery{OrgId: 1, Namespace: ds.Name, Type: secretskvs.DataSourceSecretType})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Len(t, reports[0].References, 1)
+		require.Equal(t, secretsConsumer, reports[0].References[0].Consumer)
+	})
+
+	t.Run("should remove the reference of a deleted data source", func(t *testing.T) {
+		err := dsService.DeleteDataSource(ctx, &datasources.DeleteDataSourceCommand{ID: ds.ID, UID: ds.UID, Name: ds.Name, OrgID: ds.OrgID})
+		require.NoError(t, err)
+
+		reports, err := usageTracker.Report(ctx, &secretskvs.SecretUsageQuery{OrgId: 1, Namespace: ds.Name})
+		require.NoError(t, err)
+		require.Empty(t, reports)
+	})
+}
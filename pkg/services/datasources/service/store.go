@@ -18,6 +18,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/star"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -31,6 +33,8 @@ type Store interface {
 	AddDataSource(context.Context, *datasources.AddDataSourceCommand) (*datasources.DataSource, error)
 	UpdateDataSource(context.Context, *datasources.UpdateDataSourceCommand) (*datasources.DataSource, error)
 	GetAllDataSources(ctx context.Context, query *datasources.GetAllDataSourcesQuery) error
+	GetDataSourceTeams(context.Context, *datasources.GetDataSourceTeamsQuery) ([]int64, error)
+	SetDataSourceTeams(context.Context, *datasources.SetDataSourceTeamsCommand) error
 
 	Count(context.Context, *quota.ScopeParameters) (*quota.Map, error)
 }
@@ -89,6 +93,11 @@ func (ss *SqlStore) GetDataSources(ctx context.Context, query *datasources.GetDa
 			sess = dbSess.Limit(query.DataSourceLimit, 0).Where("org_id=?", query.OrgID).Asc("name")
 		}
 
+		if query.User != nil {
+			filter := teamRestrictionFilter(query.User)
+			sess = sess.And(filter.Where, filter.Args...)
+		}
+
 		return sess.Find(&dataSources)
 	})
 }
@@ -100,6 +109,81 @@ func (ss *SqlStore) GetAllDataSources(ctx context.Context, query *datasources.Ge
 	})
 }
 
+// teamRestrictionFilter returns the filter of the data sources restricted to teams that the user cannot access,
+// see datasources.CanAccessRestricted.
+func teamRestrictionFilter(u *user.SignedInUser) ac.SQLFilter {
+	if u.IsGrafanaAdmin || u.UserID < 0 {
+		return ac.SQLFilter{Where: "1 = 1"}
+	}
+
+	var (
+		where strings.Builder
+		args  []interface{}
+	)
+	where.WriteString("(NOT EXISTS (SELECT 1 FROM data_source_team dst WHERE dst.data_source_id = data_source.id)")
+	if len(u.Teams) > 0 {
+		where.WriteString(" OR data_source.id IN (SELECT dst.data_source_id FROM data_source_team dst WHERE dst.team_id IN (?")
+		where.WriteString(strings.Repeat(",?", len(u.Teams)-1))
+		where.WriteString("))")
+		for _, teamID := range u.Teams {
+			args = append(args, teamID)
+		}
+	}
+	// the filter denies everything if the permissions of the user are not loaded
+	canWrite, _ := ac.Filter(u, "data_source.uid", datasources.ScopePrefix, datasources.ActionWrite)
+	where.WriteString(" OR")
+	where.WriteString(canWrite.Where)
+	where.WriteString(")")
+	args = append(args, canWrite.Args...)
+
+	return ac.SQLFilter{Where: where.String(), Args: args}
+}
+
+// GetDataSourceTeams returns the IDs of the teams the data source is restricted to.
+func (ss *SqlStore) GetDataSourceTeams(ctx context.Context, query *datasources.GetDataSourceTeamsQuery) ([]int64, error) {
+	teamIDs := make([]int64, 0)
+	return teamIDs, ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("data_source_team").Cols("team_id").
+			Where("org_id=? AND data_source_id=?", query.OrgID, query.DataSourceID).
+			Asc("team_id").Find(&teamIDs)
+	})
+}
+
+// SetDataSourceTeams replaces the teams the data source is restricted to.
+func (ss *SqlStore) SetDataSourceTeams(ctx context.Context, cmd *datasources.SetDataSourceTeamsCommand) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		teamIDs := make(map[int64]struct{}, len(cmd.TeamIDs))
+		for _, teamID := range cmd.TeamIDs {
+			teamIDs[teamID] = struct{}{}
+		}
+		for teamID := range teamIDs {
+			exists, err := sess.Table("team").Where("org_id=? AND id=?", cmd.OrgID, teamID).Exist()
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("%w: %d", datasources.ErrDataSourceTeamNotFound, teamID)
+			}
+		}
+
+		if _, err := sess.Exec("DELETE FROM data_source_team WHERE org_id=? AND data_source_id=?", cmd.OrgID, cmd.DataSourceID); err != nil {
+			return err
+		}
+		now := time.Now()
+		for teamID := range teamIDs {
+			if _, err := sess.Insert(&datasources.DataSourceTeam{
+				OrgID:        cmd.OrgID,
+				DataSourceID: cmd.DataSourceID,
+				TeamID:       teamID,
+				Created:      now,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // GetDataSourcesByType returns all datasources for a given type or an error if the specified type is an empty string
 func (ss *SqlStore) GetDataSourcesByType(ctx context.Context, query *datasources.GetDataSourcesByTypeQuery) ([]*datasources.DataSource, error) {
 	if query.Type == "" {
@@ -154,6 +238,14 @@ func (ss *SqlStore) DeleteDataSource(ctx context.Context, cmd *datasources.Delet
 				ac.Scope(datasources.ScopeProvider.GetResourceScope(ds.UID))); errDeletingPerms != nil {
 				return errDeletingPerms
 			}
+
+			if _, err := sess.Exec("DELETE FROM resource_star WHERE org_id=? AND kind=? AND uid=?", ds.OrgID, star.KindDatasource, ds.UID); err != nil {
+				return err
+			}
+
+			if _, err := sess.Exec("DELETE FROM data_source_team WHERE org_id=? AND data_source_id=?", ds.OrgID, ds.ID); err != nil {
+				return err
+			}
 		}
 
 		if cmd.UpdateSecretFn != nil {
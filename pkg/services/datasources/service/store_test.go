@@ -14,6 +14,8 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 func TestIntegrationDataAccess(t *testing.T) {
@@ -422,6 +424,69 @@ func TestIntegrationDataAccess(t *testing.T) {
 		})
 	})
 
+	t.Run("DataSourceTeams", func(t *testing.T) {
+		db := db.InitTestDB(t)
+		ss := SqlStore{db: db}
+		restricted := initDatasource(db)
+		cmd := defaultAddDatasourceCommand
+		cmd.Name = "public"
+		cmd.UID = "public"
+		_, err := ss.AddDataSource(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		err = db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			_, err := sess.Exec("INSERT INTO team (id, name, org_id, created, updated) VALUES (?, ?, ?, ?, ?)", 1, "team", 10, time.Now(), time.Now())
+			return err
+		})
+		require.NoError(t, err)
+
+		t.Run("cannot restrict a data source to a team of another organization", func(t *testing.T) {
+			err := ss.SetDataSourceTeams(context.Background(), &datasources.SetDataSourceTeamsCommand{OrgID: 10, DataSourceID: restricted.ID, TeamIDs: []int64{1, 2}})
+			require.ErrorIs(t, err, datasources.ErrDataSourceTeamNotFound)
+		})
+
+		err = ss.SetDataSourceTeams(context.Background(), &datasources.SetDataSourceTeamsCommand{OrgID: 10, DataSourceID: restricted.ID, TeamIDs: []int64{1}})
+		require.NoError(t, err)
+		teamIDs, err := ss.GetDataSourceTeams(context.Background(), &datasources.GetDataSourceTeamsQuery{OrgID: 10, DataSourceID: restricted.ID})
+		require.NoError(t, err)
+		require.Equal(t, []int64{1}, teamIDs)
+
+		names := func(t *testing.T, u *user.SignedInUser) []string {
+			t.Helper()
+			dataSources, err := ss.GetDataSources(context.Background(), &datasources.GetDataSourcesQuery{OrgID: 10, User: u})
+			require.NoError(t, err)
+			result := make([]string, 0, len(dataSources))
+			for _, ds := range dataSources {
+				result = append(result, ds.Name)
+			}
+			return result
+		}
+
+		t.Run("hides the restricted data sources from the users outside of the teams", func(t *testing.T) {
+			require.Equal(t, []string{"public"}, names(t, &user.SignedInUser{UserID: 1, OrgID: 10}))
+		})
+
+		t.Run("shows the restricted data sources to the members of the teams", func(t *testing.T) {
+			require.Equal(t, []string{"nisse", "public"}, names(t, &user.SignedInUser{UserID: 1, OrgID: 10, Teams: []int64{1}}))
+		})
+
+		t.Run("shows the restricted data sources to the users who can edit them", func(t *testing.T) {
+			u := &user.SignedInUser{UserID: 1, OrgID: 10, Permissions: map[int64]map[string][]string{
+				10: {datasources.ActionWrite: {datasources.ScopeProvider.GetResourceScopeUID(restricted.UID)}},
+			}}
+			require.Equal(t, []string{"nisse", "public"}, names(t, u))
+		})
+
+		t.Run("removes the restrictions of a deleted data source", func(t *testing.T) {
+			err := ss.DeleteDataSource(context.Background(), &datasources.DeleteDataSourceCommand{OrgID: 10, ID: restricted.ID})
+			require.NoError(t, err)
+			teamIDs, err := ss.GetDataSourceTeams(context.Background(), &datasources.GetDataSourceTeamsQuery{OrgID: 10, DataSourceID: restricted.ID})
+			require.NoError(t, err)
+			require.Empty(t, teamIDs)
+			require.Equal(t, []string{"public"}, names(t, &user.SignedInUser{UserID: 1, OrgID: 10, Teams: []int64{1}}))
+		})
+	})
+
 	t.Run("GetDataSourcesByType", func(t *testing.T) {
 		t.Run("Only returns datasources of specified type", func(t *testing.T) {
 			db := db.InitTestDB(t)
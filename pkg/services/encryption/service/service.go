@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
@@ -31,6 +32,10 @@ type Service struct {
 
 	ciphers   map[string]encryption.Cipher
 	deciphers map[string]encryption.Decipher
+
+	// reloadedAlgorithm is the encryption algorithm of the last configuration reload, the algorithm is read from
+	// the settings until the configuration is reloaded
+	reloadedAlgorithm atomic.Value
 }
 
 func ProvideEncryptionService(
@@ -86,9 +91,7 @@ func (s *Service) checkEncryptionAlgorithm(algorithm string) error {
 
 func (s *Service) registerUsageMetrics() {
 	s.usageMetrics.RegisterMetricsFunc(func(context.Context) (map[string]interface{}, error) {
-		algorithm := s.settingsProvider.
-			KeyValue(securitySection, encryptionAlgorithmKey).
-			MustString(defaultEncryptionAlgorithm)
+		algorithm := s.currentAlgorithm()
 
 		return map[string]interface{}{
 			fmt.Sprintf("stats.encryption.%s.count", algorithm): 1,
@@ -174,9 +177,7 @@ func (s *Service) Encrypt(ctx context.Context, payload []byte, secret string) ([
 		}
 	}()
 
-	algorithm := s.settingsProvider.
-		KeyValue(securitySection, encryptionAlgorithmKey).
-		MustString(defaultEncryptionAlgorithm)
+	algorithm := s.currentAlgorithm()
 
 	cipher, ok := s.ciphers[algorithm]
 	if !ok {
@@ -251,6 +252,16 @@ func (s *Service) Validate(section setting.Section) error {
 	return nil
 }
 
-func (s *Service) Reload(_ setting.Section) error {
+func (s *Service) Reload(section setting.Section) error {
+	s.reloadedAlgorithm.Store(section.KeyValue(encryptionAlgorithmKey).MustString(defaultEncryptionAlgorithm))
 	return nil
 }
+
+func (s *Service) currentAlgorithm() string {
+	if algorithm, ok := s.reloadedAlgorithm.Load().(string); ok {
+		return algorithm
+	}
+	return s.settingsProvider.
+		KeyValue(securitySection, encryptionAlgorithmKey).
+		MustString(defaultEncryptionAlgorithm)
+}
@@ -0,0 +1,63 @@
+package eventoutbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+)
+
+// DeadEvent is an event which couldn't be delivered to a sink. It's delivered again by retrying its job with
+// POST /api/admin/jobs/:jobId/retry.
+type DeadEvent struct {
+	JobID     int64     `json:"jobId"`
+	Sink      string    `json:"sink"`
+	Event     Event     `json:"event"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"lastError,omitempty"`
+	Updated   time.Time `json:"updated"`
+}
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Get("/api/admin/event-outbox/dead-letter", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleListDeadEvents))
+}
+
+// handleListDeadEvents lists the events which failed to be delivered too many times, the most recent failures first.
+func (s *Service) handleListDeadEvents(c *contextmodel.ReqContext) response.Response {
+	types := make([]string, 0, len(s.sinks))
+	for jobType := range s.sinks {
+		types = append(types, jobType)
+	}
+
+	jobs, err := s.jobQueue.ListJobs(c.Req.Context(), jobqueue.ListJobsQuery{
+		Status: jobqueue.StatusDead,
+		Types:  types,
+		Limit:  c.QueryInt("limit"),
+		Page:   c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list dead events", err)
+	}
+
+	result := make([]DeadEvent, 0, len(jobs))
+	for _, job := range jobs {
+		dead := DeadEvent{
+			JobID:     job.ID,
+			Sink:      strings.TrimPrefix(job.Type, jobTypePrefix),
+			Attempts:  job.Attempts,
+			LastError: job.LastError,
+			Updated:   job.Updated,
+		}
+		if err := json.Unmarshal(job.Payload, &dead.Event); err != nil {
+			s.log.Warn("Failed to read the event of a dead job", "jobId", job.ID, "error", err)
+		}
+		result = append(result, dead)
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,190 @@
+// Package eventoutbox delivers the domain events published on the bus to external systems, for the integrations
+// that can't rely on the in-process bus. The events are queued in the job queue once the change is committed, one
+// job per configured sink, so that a delivery survives restarts and is retried until the sink accepts it. The
+// delivery is at least once: consumers deduplicate the events by ID. The events failing too many times are kept
+// as dead jobs, for the server admins to inspect and retry them.
+package eventoutbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	EventDashboardSaved   = "dashboard.saved"
+	EventDashboardDeleted = "dashboard.deleted"
+	EventUserCreated      = "user.created"
+	EventAlertFired       = "alert.fired"
+
+	// jobTypePrefix prefixes the job types of the sinks, the job of the webhook sink is event-outbox.webhook.
+	jobTypePrefix = "event-outbox."
+)
+
+// Event is the envelope of the delivered events.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	OrgID     int64           `json:"orgId,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type DashboardData struct {
+	UID     string `json:"uid"`
+	Title   string `json:"title,omitempty"`
+	Version int    `json:"version,omitempty"`
+}
+
+type UserData struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+type AlertData struct {
+	RuleUID     string            `json:"ruleUid"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+}
+
+// sink delivers the events to an external system.
+type sink interface {
+	send(ctx context.Context, e Event, body []byte) error
+}
+
+type Service struct {
+	cfg      setting.EventOutboxSettings
+	log      log.Logger
+	jobQueue jobqueue.Service
+	// sinks are the configured sinks by job type
+	sinks  map[string]sink
+	events map[string]bool
+}
+
+func ProvideService(cfg *setting.Cfg, bus bus.Bus, jobQueue jobqueue.Service, routeRegister routing.RouteRegister) *Service {
+	s := &Service{
+		cfg:      cfg.EventOutbox,
+		log:      log.New("eventoutbox"),
+		jobQueue: jobQueue,
+		sinks:    map[string]sink{},
+		events:   map[string]bool{},
+	}
+	if !s.cfg.Enabled {
+		return s
+	}
+
+	if s.cfg.Webhook.URL != "" {
+		s.sinks[jobTypePrefix+"webhook"] = newWebhookSink(s.cfg.Webhook)
+	}
+	if s.cfg.Kafka.RestProxyURL != "" {
+		s.sinks[jobTypePrefix+"kafka"] = newKafkaSink(s.cfg.Kafka)
+	}
+	if s.cfg.NATS.URL != "" {
+		s.sinks[jobTypePrefix+"nats"] = newNATSSink(s.cfg.NATS)
+	}
+	if len(s.sinks) == 0 {
+		s.log.Warn("The event outbox is enabled but no sink is configured")
+		return s
+	}
+	for jobType := range s.sinks {
+		jobQueue.RegisterHandler(jobType, s.deliver, jobqueue.HandlerOptions{MaxAttempts: s.cfg.MaxAttempts})
+	}
+	for _, e := range s.cfg.Events {
+		s.events[e] = true
+	}
+
+	bus.AddEventListener(s.handleDashboardCreated)
+	bus.AddEventListener(s.handleDashboardUpdated)
+	bus.AddEventListener(s.handleDashboardDeleted)
+	bus.AddEventListener(s.handleUserCreated)
+	bus.AddEventListener(s.handleAlertFired)
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+// enqueue queues the delivery of the event to every sink. The errors are logged rather than returned, so that the
+// other listeners of the event still run.
+func (s *Service) enqueue(ctx context.Context, eventType string, orgID int64, timestamp time.Time, data any) {
+	if len(s.events) > 0 && !s.events[eventType] {
+		return
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		s.log.Error("Failed to marshal the event", "type", eventType, "error", err)
+		return
+	}
+	e := Event{ID: util.GenerateShortUID(), Type: eventType, OrgID: orgID, Timestamp: timestamp, Data: raw}
+	for jobType := range s.sinks {
+		if _, err := s.jobQueue.Enqueue(ctx, jobqueue.EnqueueCommand{Type: jobType, Payload: e}); err != nil {
+			s.log.Error("Failed to queue the event", "type", eventType, "id", e.ID, "sink", jobType, "error", err)
+		}
+	}
+}
+
+func (s *Service) deliver(ctx context.Context, job *jobqueue.Job) error {
+	sink, ok := s.sinks[job.Type]
+	if !ok {
+		return fmt.Errorf("event outbox sink %s is not configured", job.Type)
+	}
+
+	var e Event
+	if err := json.Unmarshal(job.Payload, &e); err != nil {
+		return err
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return sink.send(ctx, e, body)
+}
+
+func (s *Service) handleDashboardCreated(ctx context.Context, e *events.DashboardCreated) error {
+	if !e.IsFolder {
+		s.enqueue(ctx, EventDashboardSaved, e.OrgID, e.Timestamp, DashboardData{UID: e.UID, Title: e.Title, Version: e.Version})
+	}
+	return nil
+}
+
+func (s *Service) handleDashboardUpdated(ctx context.Context, e *events.DashboardUpdated) error {
+	if !e.IsFolder {
+		s.enqueue(ctx, EventDashboardSaved, e.OrgID, e.Timestamp, DashboardData{UID: e.UID, Title: e.Title, Version: e.Version})
+	}
+	return nil
+}
+
+func (s *Service) handleDashboardDeleted(ctx context.Context, e *events.DashboardDeleted) error {
+	if !e.IsFolder {
+		s.enqueue(ctx, EventDashboardDeleted, e.OrgID, e.Timestamp, DashboardData{UID: e.UID})
+	}
+	return nil
+}
+
+func (s *Service) handleUserCreated(ctx context.Context, e *events.UserCreated) error {
+	// users don't belong to an organization
+	s.enqueue(ctx, EventUserCreated, 0, e.Timestamp, UserData{ID: e.Id, Login: e.Login, Email: e.Email, Name: e.Name})
+	return nil
+}
+
+func (s *Service) handleAlertFired(ctx context.Context, e *events.AlertFired) error {
+	s.enqueue(ctx, EventAlertFired, e.OrgID, e.Timestamp, AlertData{RuleUID: e.RuleUID, Labels: e.Labels, Annotations: e.Annotations, StartsAt: e.StartsAt})
+	return nil
+}
+
+// partitionKey keeps the events of an organization in order in the partitioned sinks.
+func partitionKey(e Event) string {
+	return strconv.FormatInt(e.OrgID, 10)
+}
@@ -0,0 +1,262 @@
+package eventoutbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestService_Enqueue(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.EventOutbox = setting.EventOutboxSettings{
+		Enabled: true,
+		Webhook: setting.EventOutboxWebhookSettings{URL: "https://events.example.com"},
+		NATS:    setting.EventOutboxNATSSettings{URL: "nats://localhost:4222", Subject: "grafana.events"},
+	}
+
+	jobQueue := &fakeJobQueue{}
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	ProvideService(cfg, b, jobQueue, routing.NewRouteRegister())
+	assert.ElementsMatch(t, []string{"event-outbox.webhook", "event-outbox.nats"}, jobQueue.registered)
+
+	require.NoError(t, b.Publish(context.Background(), &events.DashboardCreated{UID: "dash", Title: "Dash", OrgID: 2, Version: 1}))
+	require.NoError(t, b.Publish(context.Background(), &events.DashboardCreated{UID: "folder", OrgID: 2, IsFolder: true}))
+
+	require.Len(t, jobQueue.enqueued, 2, "one job per sink, the folders are ignored")
+	types := []string{jobQueue.enqueued[0].Type, jobQueue.enqueued[1].Type}
+	assert.ElementsMatch(t, []string{"event-outbox.webhook", "event-outbox.nats"}, types)
+
+	e := jobQueue.enqueued[0].Payload.(Event)
+	assert.Equal(t, EventDashboardSaved, e.Type)
+	assert.Equal(t, int64(2), e.OrgID)
+	assert.NotEmpty(t, e.ID)
+	assert.Equal(t, e.ID, jobQueue.enqueued[1].Payload.(Event).ID, "the sinks receive the same event")
+	assert.JSONEq(t, `{"uid":"dash","title":"Dash","version":1}`, string(e.Data))
+}
+
+func TestService_EventsFilter(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.EventOutbox = setting.EventOutboxSettings{
+		Enabled: true,
+		Events:  []string{EventAlertFired},
+		Webhook: setting.EventOutboxWebhookSettings{URL: "https://events.example.com"},
+	}
+
+	jobQueue := &fakeJobQueue{}
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	ProvideService(cfg, b, jobQueue, routing.NewRouteRegister())
+
+	require.NoError(t, b.Publish(context.Background(), &events.UserCreated{Id: 1, Login: "admin"}))
+	require.NoError(t, b.Publish(context.Background(), &events.AlertFired{RuleUID: "rule", OrgID: 1, Labels: map[string]string{"team": "a"}}))
+
+	require.Len(t, jobQueue.enqueued, 1)
+	e := jobQueue.enqueued[0].Payload.(Event)
+	assert.Equal(t, EventAlertFired, e.Type)
+	assert.JSONEq(t, `{"ruleUid":"rule","labels":{"team":"a"},"startsAt":"0001-01-01T00:00:00Z"}`, string(e.Data))
+}
+
+func TestService_Disabled(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.EventOutbox = setting.EventOutboxSettings{Webhook: setting.EventOutboxWebhookSettings{URL: "https://events.example.com"}}
+
+	jobQueue := &fakeJobQueue{}
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	ProvideService(cfg, b, jobQueue, routing.NewRouteRegister())
+
+	require.NoError(t, b.Publish(context.Background(), &events.UserCreated{Id: 1, Login: "admin"}))
+	assert.Empty(t, jobQueue.registered)
+	assert.Empty(t, jobQueue.enqueued)
+}
+
+func testEvent(t *testing.T) (Event, []byte) {
+	t.Helper()
+	e := Event{ID: "abc", Type: EventUserCreated, Timestamp: time.Now(), Data: json.RawMessage(`{"id":1}`)}
+	body, err := json.Marshal(e)
+	require.NoError(t, err)
+	return e, body
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		headers http.Header
+		body    []byte
+		status  = http.StatusOK
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	s := newWebhookSink(setting.EventOutboxWebhookSettings{URL: server.URL, Secret: "secret", Timeout: time.Second})
+	e, payload := testEvent(t)
+	require.NoError(t, s.send(context.Background(), e, payload))
+
+	assert.Equal(t, payload, body)
+	assert.Equal(t, EventUserCreated, headers.Get(eventHeader))
+	assert.Equal(t, "abc", headers.Get(eventIDHeader))
+	assert.Equal(t, "sha256="+util.HmacSha256Sum("secret", payload), headers.Get(signatureHeader))
+
+	status = http.StatusBadGateway
+	assert.Error(t, s.send(context.Background(), e, payload), "the event is retried when the webhook fails")
+}
+
+func TestKafkaSink(t *testing.T) {
+	var (
+		path     string
+		received kafkaProduceRequest
+		response = `{"offsets":[{"partition":0,"offset":1}]}`
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.Equal(t, kafkaContentType, r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	s := newKafkaSink(setting.EventOutboxKafkaSettings{RestProxyURL: server.URL + "/", Topic: "grafana-events", Timeout: time.Second})
+	e, payload := testEvent(t)
+	require.NoError(t, s.send(context.Background(), e, payload))
+
+	assert.Equal(t, "/topics/grafana-events", path)
+	require.Len(t, received.Records, 1)
+	assert.Equal(t, "0", received.Records[0].Key)
+	assert.JSONEq(t, string(payload), string(received.Records[0].Value))
+
+	response = `{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"leader not available"}]}`
+	err := s.send(context.Background(), e, payload)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "leader not available")
+}
+
+func TestNATSSink(t *testing.T) {
+	t.Run("publishes the event and waits for the PONG", func(t *testing.T) {
+		server := newFakeNATSServer(t, "")
+		s := newNATSSink(setting.EventOutboxNATSSettings{URL: "nats://" + server.addr, Subject: "grafana.events", Token: "token", Timeout: time.Second})
+		e, payload := testEvent(t)
+		require.NoError(t, s.send(context.Background(), e, payload))
+
+		msg := <-server.messages
+		assert.Equal(t, "grafana.events.user.created", msg.subject)
+		assert.Equal(t, payload, msg.payload)
+		assert.Equal(t, "token", msg.connect.AuthToken)
+	})
+
+	t.Run("fails when the server refuses the connection", func(t *testing.T) {
+		server := newFakeNATSServer(t, "-ERR 'Authorization Violation'")
+		s := newNATSSink(setting.EventOutboxNATSSettings{URL: "nats://" + server.addr, Subject: "grafana.events", Timeout: time.Second})
+		e, payload := testEvent(t)
+		err := s.send(context.Background(), e, payload)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Authorization Violation")
+	})
+}
+
+func TestNATSAddress(t *testing.T) {
+	host, useTLS, err := natsAddress("nats://nats.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "nats.example.com:4222", host)
+	assert.False(t, useTLS)
+
+	host, useTLS, err = natsAddress("tls://nats.example.com:4443")
+	require.NoError(t, err)
+	assert.Equal(t, "nats.example.com:4443", host)
+	assert.True(t, useTLS)
+
+	_, _, err = natsAddress("nats.example.com")
+	assert.Error(t, err)
+}
+
+type natsMessage struct {
+	connect natsConnect
+	subject string
+	payload []byte
+}
+
+type fakeNATSServer struct {
+	addr     string
+	messages chan natsMessage
+}
+
+// newFakeNATSServer accepts a connection and reads a CONNECT, a PUB and a PING, answering with the given error
+// instead of a PONG when set.
+func newFakeNATSServer(t *testing.T, replyErr string) *fakeNATSServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	s := &fakeNATSServer{addr: l.Addr().String(), messages: make(chan natsMessage, 1)}
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"auth_required\":true}\r\n")
+
+		r := bufio.NewReader(conn)
+		var msg natsMessage
+		line, _ := r.ReadString('\n')
+		_ = json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "CONNECT ")), &msg.connect)
+
+		line, _ = r.ReadString('\n')
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return
+		}
+		msg.subject = fields[1]
+		size, _ := strconv.Atoi(fields[2])
+		msg.payload = make([]byte, size+2)
+		if _, err := io.ReadFull(r, msg.payload); err != nil {
+			return
+		}
+		msg.payload = msg.payload[:size]
+		_, _ = r.ReadString('\n') // PING
+
+		if replyErr != "" {
+			_, _ = fmt.Fprint(conn, replyErr+"\r\n")
+			return
+		}
+		s.messages <- msg
+		_, _ = fmt.Fprint(conn, "PONG\r\n")
+	}()
+	return s
+}
+
+type fakeJobQueue struct {
+	jobqueue.Service
+	registered []string
+	enqueued   []jobqueue.EnqueueCommand
+}
+
+func (f *fakeJobQueue) RegisterHandler(jobType string, handler jobqueue.Handler, opts jobqueue.HandlerOptions) {
+	f.registered = append(f.registered, jobType)
+}
+
+func (f *fakeJobQueue) Enqueue(ctx context.Context, cmd jobqueue.EnqueueCommand) (*jobqueue.Job, error) {
+	f.enqueued = append(f.enqueued, cmd)
+	return &jobqueue.Job{Type: cmd.Type}, nil
+}
@@ -0,0 +1,149 @@
+package eventoutbox
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const natsDefaultPort = "4222"
+
+// natsSink publishes the events to NATS, on the configured subject followed by the type of the event, for example
+// grafana.events.dashboard.saved. It speaks the text protocol of NATS over a connection opened for each event: the
+// PING sent after the PUB is answered with a PONG once the server processed the message.
+type natsSink struct {
+	cfg setting.EventOutboxNATSSettings
+}
+
+func newNATSSink(cfg setting.EventOutboxNATSSettings) *natsSink {
+	return &natsSink{cfg: cfg}
+}
+
+// natsInfo is the part of the INFO message sent by the server when the client connects that the sink needs.
+type natsInfo struct {
+	TLSRequired  bool `json:"tls_required"`
+	AuthRequired bool `json:"auth_required"`
+}
+
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	AuthToken string `json:"auth_token,omitempty"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+}
+
+func (s *natsSink) send(ctx context.Context, e Event, body []byte) error {
+	host, useTLS, err := natsAddress(s.cfg.URL)
+	if err != nil {
+		return err
+	}
+
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read the nats server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected nats server greeting: %q", strings.TrimSpace(line))
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return fmt.Errorf("failed to parse the nats server info: %w", err)
+	}
+
+	if useTLS || info.TLSRequired {
+		serverName, _, _ := net.SplitHostPort(host)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return err
+		}
+		conn = tlsConn
+		r = bufio.NewReader(conn)
+	}
+
+	connect, err := json.Marshal(natsConnect{
+		Name:      "grafana",
+		Lang:      "go",
+		Version:   "1.0.0",
+		AuthToken: s.cfg.Token,
+		User:      s.cfg.Username,
+		Pass:      s.cfg.Password,
+	})
+	if err != nil {
+		return err
+	}
+
+	subject := s.cfg.Subject + "." + e.Type
+	var b strings.Builder
+	fmt.Fprintf(&b, "CONNECT %s\r\n", connect)
+	fmt.Fprintf(&b, "PUB %s %d\r\n", subject, len(body))
+	b.Write(body)
+	b.WriteString("\r\nPING\r\n")
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return err
+	}
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read the nats server response: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats server refused the event: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case line == "PING":
+			if _, err := conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// natsAddress returns the host and port of a nats:// or tls:// URL, and whether the connection must use TLS.
+func natsAddress(rawURL string) (string, bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false, err
+	}
+	if u.Host == "" {
+		return "", false, errors.New("the nats url must be like nats://host:port")
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), natsDefaultPort)
+	}
+	return host, u.Scheme == "tls", nil
+}
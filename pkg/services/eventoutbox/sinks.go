@@ -0,0 +1,125 @@
+package eventoutbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	signatureHeader = "X-Grafana-Signature"
+	eventHeader     = "X-Grafana-Event"
+	eventIDHeader   = "X-Grafana-Event-Id"
+
+	kafkaContentType = "application/vnd.kafka.json.v2+json"
+)
+
+// webhookSink posts the events to a webhook.
+type webhookSink struct {
+	cfg        setting.EventOutboxWebhookSettings
+	httpClient *http.Client
+}
+
+func newWebhookSink(cfg setting.EventOutboxWebhookSettings) *webhookSink {
+	return &webhookSink{cfg: cfg, httpClient: &http.Client{Timeout: cfg.Timeout}}
+}
+
+func (s *webhookSink) send(ctx context.Context, e Event, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHeader, e.Type)
+	req.Header.Set(eventIDHeader, e.ID)
+	if s.cfg.Secret != "" {
+		req.Header.Set(signatureHeader, "sha256="+util.HmacSha256Sum(s.cfg.Secret, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("event webhook %s returned status %d", s.cfg.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// kafkaSink produces the events to a Kafka topic through the v2 API of a Kafka REST proxy, keyed by organization.
+type kafkaSink struct {
+	cfg        setting.EventOutboxKafkaSettings
+	httpClient *http.Client
+}
+
+func newKafkaSink(cfg setting.EventOutboxKafkaSettings) *kafkaSink {
+	return &kafkaSink{cfg: cfg, httpClient: &http.Client{Timeout: cfg.Timeout}}
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (s *kafkaSink) send(ctx context.Context, e Event, body []byte) error {
+	payload, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{{Key: partitionKey(e), Value: body}}})
+	if err != nil {
+		return err
+	}
+
+	u := strings.TrimSuffix(s.cfg.RestProxyURL, "/") + "/topics/" + url.PathEscape(s.cfg.Topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("kafka rest proxy returned status %d for topic %s", resp.StatusCode, s.cfg.Topic)
+	}
+
+	// the proxy returns 200 with the errors of the records which couldn't be produced
+	var produced kafkaProduceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&produced); err != nil {
+		return fmt.Errorf("failed to read the kafka rest proxy response: %w", err)
+	}
+	for _, o := range produced.Offsets {
+		if o.ErrorCode != nil || o.Error != "" {
+			return fmt.Errorf("kafka rest proxy failed to produce the event to topic %s: %s", s.cfg.Topic, o.Error)
+		}
+	}
+	return nil
+}
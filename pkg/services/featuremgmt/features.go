@@ -2,11 +2,19 @@ package featuremgmt
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 )
 
 type FeatureToggles interface {
+	// IsEnabled only checks the global value of the flag, it is meant for the checks made outside of a
+	// request, e.g. when the services are initialized. The flags picking the store implementations
+	// require a restart, so they can't be rolled out.
 	IsEnabled(flag string) bool
+
+	// IsEnabledCtx also honors the per-org, per-team and percentage rollouts
+	// for the identity stored in ctx with WithEvaluationContext
+	IsEnabledCtx(ctx context.Context, flag string) bool
 }
 
 // FeatureFlagState indicates the quality level
@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/licensing"
+	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
 var (
 	_ FeatureToggles = (*FeatureManager)(nil)
+
+	ErrFeatureFlagNotFound           = errutil.NewBase(errutil.StatusNotFound, "featuremgmt.not-found")
+	ErrFeatureFlagRequiresRestart    = errutil.NewBase(errutil.StatusBadRequest, "featuremgmt.requires-restart", errutil.WithPublicMessage("Feature toggle can only be changed with a restart"))
+	ErrFeatureFlagRequirementsNotMet = errutil.NewBase(errutil.StatusBadRequest, "featuremgmt.requirements-not-met", errutil.WithPublicMessage("Feature toggle is not available in this environment"))
 )
 
 type FeatureManager struct {
@@ -18,9 +24,12 @@ type FeatureManager struct {
 	licensing licensing.Licensing
 	flags     map[string]*FeatureFlag
 	enabled   map[string]bool // only the "on" values
-	config    string          // path to config file
+	overrides map[string]bool // values changed at runtime
+	rollouts  map[string]FeatureRollout
+	config    string // path to config file
 	vars      map[string]interface{}
 	log       log.Logger
+	mutex     sync.RWMutex
 }
 
 // This will merge the flags with the current configuration
@@ -95,7 +104,14 @@ func (fm *FeatureManager) update() {
 		// Update the registry
 		track := 0.0
 		// TODO: CEL - expression
-		if flag.Expression == "true" {
+		on := flag.Expression == "true"
+
+		// Runtime overrides are only honored for flags that can change without a restart
+		if val, ok := fm.overrides[flag.Name]; ok && !flag.RequiresRestart {
+			on = val
+		}
+
+		if on {
 			track = 1
 			enabled[flag.Name] = true
 		}
@@ -125,22 +141,68 @@ func (fm *FeatureManager) readFile() error {
 
 // IsEnabled checks if a feature is enabled
 func (fm *FeatureManager) IsEnabled(flag string) bool {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
 	return fm.enabled[flag]
 }
 
-// GetEnabled returns a map contaning only the features that are enabled
+// IsEnabledCtx checks if a feature is enabled, taking into account the rollouts
+// matching the evaluation context stored in ctx
+func (fm *FeatureManager) IsEnabledCtx(ctx context.Context, flag string) bool {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
+	if fm.enabled[flag] {
+		return true
+	}
+
+	ec, ok := EvaluationContextFromContext(ctx)
+	if !ok {
+		return false
+	}
+	return fm.isRolledOut(flag, ec)
+}
+
+// GetEnabled returns a map contaning only the features that are enabled.
+// Rollouts are included when ctx carries an evaluation context.
 func (fm *FeatureManager) GetEnabled(ctx context.Context) map[string]bool {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
 	enabled := make(map[string]bool, len(fm.enabled))
 	for key, val := range fm.enabled {
 		if val {
 			enabled[key] = true
 		}
 	}
+
+	if ec, ok := EvaluationContextFromContext(ctx); ok {
+		for key := range fm.rollouts {
+			if fm.isRolledOut(key, ec) {
+				enabled[key] = true
+			}
+		}
+	}
 	return enabled
 }
 
+// isRolledOut must be called while holding the read lock
+func (fm *FeatureManager) isRolledOut(flag string, ec EvaluationContext) bool {
+	rollout, ok := fm.rollouts[flag]
+	if !ok {
+		return false
+	}
+
+	ff, ok := fm.flags[flag]
+	if !ok || ff.RequiresRestart || !fm.meetsRequirements(ff) {
+		return false
+	}
+	return rollout.matches(flag, ec)
+}
+
 // GetFlags returns all flag definitions
 func (fm *FeatureManager) GetFlags() []FeatureFlag {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
+
 	v := make([]FeatureFlag, 0, len(fm.flags))
 	for _, value := range fm.flags {
 		v = append(v, *value)
@@ -148,6 +210,75 @@ func (fm *FeatureManager) GetFlags() []FeatureFlag {
 	return v
 }
 
+// GetFlag returns the definition of a single flag
+func (fm *FeatureManager) GetFlag(name string) (FeatureFlag, bool) {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
+
+	flag, ok := fm.flags[name]
+	if !ok {
+		return FeatureFlag{}, false
+	}
+	return *flag, true
+}
+
+// ValidateRuntimeChange checks whether the flag can be switched on or off while grafana is running
+func (fm *FeatureManager) ValidateRuntimeChange(name string) error {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
+
+	flag, ok := fm.flags[name]
+	if !ok {
+		return ErrFeatureFlagNotFound.Errorf("feature toggle %q is not registered", name)
+	}
+
+	if flag.RequiresRestart {
+		return ErrFeatureFlagRequiresRestart.Errorf("feature toggle %q requires a restart", name)
+	}
+
+	if !fm.meetsRequirements(flag) {
+		return ErrFeatureFlagRequirementsNotMet.Errorf("feature toggle %q does not meet dev mode or license requirements", name)
+	}
+
+	return nil
+}
+
+// SetRuntimeOverrides replaces the values changed at runtime and re-evaluates all flags.
+// Flags that require a restart keep their startup value.
+func (fm *FeatureManager) SetRuntimeOverrides(overrides map[string]bool) {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	fm.overrides = make(map[string]bool, len(overrides))
+	for key, val := range overrides {
+		fm.overrides[key] = val
+	}
+	fm.update()
+}
+
+// SetRollouts replaces the partial rollouts of all flags
+func (fm *FeatureManager) SetRollouts(rollouts map[string]FeatureRollout) {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+
+	fm.rollouts = make(map[string]FeatureRollout, len(rollouts))
+	for key, val := range rollouts {
+		fm.rollouts[key] = val
+	}
+}
+
+// GetRuntimeOverrides returns the values changed at runtime
+func (fm *FeatureManager) GetRuntimeOverrides() map[string]bool {
+	fm.mutex.RLock()
+	defer fm.mutex.RUnlock()
+
+	overrides := make(map[string]bool, len(fm.overrides))
+	for key, val := range fm.overrides {
+		overrides[key] = val
+	}
+	return overrides
+}
+
 // WithFeatures is used to define feature toggles for testing.
 // The arguments are a list of strings that are optionally followed by a boolean value for example:
 // WithFeatures([]interface{}{"my_feature", "other_feature"}) or WithFeatures([]interface{}{"my_feature", true})
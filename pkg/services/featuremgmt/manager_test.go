@@ -74,4 +74,35 @@ func TestFeatureManager(t *testing.T) {
 		require.Equal(t, "second", flag.Description)
 		require.Equal(t, "http://something", flag.DocsURL)
 	})
+	t.Run("check runtime overrides", func(t *testing.T) {
+		ft := FeatureManager{
+			flags: map[string]*FeatureFlag{},
+		}
+		ft.registerFlags(FeatureFlag{
+			Name:       "a",
+			Expression: "true",
+		}, FeatureFlag{
+			Name: "b",
+		}, FeatureFlag{
+			Name:            "c",
+			RequiresRestart: true,
+		})
+		require.True(t, ft.IsEnabled("a"))
+		require.False(t, ft.IsEnabled("b"))
+
+		ft.SetRuntimeOverrides(map[string]bool{"a": false, "b": true, "c": true})
+		require.False(t, ft.IsEnabled("a"))
+		require.True(t, ft.IsEnabled("b"))
+		require.False(t, ft.IsEnabled("c")) // requires restart
+
+		require.NoError(t, ft.ValidateRuntimeChange("a"))
+		require.ErrorIs(t, ft.ValidateRuntimeChange("c"), ErrFeatureFlagRequiresRestart)
+		require.ErrorIs(t, ft.ValidateRuntimeChange("d"), ErrFeatureFlagNotFound)
+
+		// Removing the overrides restores the startup values
+		ft.SetRuntimeOverrides(nil)
+		require.True(t, ft.IsEnabled("a"))
+		require.False(t, ft.IsEnabled("b"))
+		require.Empty(t, ft.GetRuntimeOverrides())
+	})
 }
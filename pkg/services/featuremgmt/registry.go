@@ -109,10 +109,11 @@ var (
 			Owner:       grafanaBackendPlatformSquad,
 		},
 		{
-			Name:        "storage",
-			Description: "Configurable storage for dashboards, datasources, and resources",
-			State:       FeatureStateAlpha,
-			Owner:       grafanaAppPlatformSquad,
+			Name:            "storage",
+			Description:     "Configurable storage for dashboards, datasources, and resources",
+			State:           FeatureStateAlpha,
+			Owner:           grafanaAppPlatformSquad,
+			RequiresRestart: true,
 		},
 		{
 			Name:            "k8s",
@@ -120,6 +121,7 @@ var (
 			State:           FeatureStateAlpha,
 			RequiresDevMode: true,
 			Owner:           grafanaAppPlatformSquad,
+			RequiresRestart: true,
 		},
 		{
 			Name:            "dashboardsFromStorage",
@@ -127,6 +129,7 @@ var (
 			State:           FeatureStateAlpha,
 			RequiresDevMode: true, // Also a gate on automatic git storage (for now)
 			Owner:           grafanaAppPlatformSquad,
+			RequiresRestart: true,
 		},
 		{
 			Name:         "exploreMixedDatasource",
@@ -176,10 +179,11 @@ var (
 			Owner:        grafanaObservabilityTracesAndProfilingSquad,
 		},
 		{
-			Name:        "newDBLibrary",
-			Description: "Use jmoiron/sqlx rather than xorm for a few backend services",
-			State:       FeatureStateBeta,
-			Owner:       grafanaBackendPlatformSquad,
+			Name:            "newDBLibrary",
+			Description:     "Use jmoiron/sqlx rather than xorm for a few backend services",
+			State:           FeatureStateBeta,
+			Owner:           grafanaBackendPlatformSquad,
+			RequiresRestart: true,
 		},
 		{
 			Name:            "validateDashboardsOnSave",
@@ -260,6 +264,7 @@ var (
 			State:           FeatureStateAlpha,
 			RequiresDevMode: true,
 			Owner:           grafanaAppPlatformSquad,
+			RequiresRestart: true,
 		},
 		{
 			Name:        "cloudWatchCrossAccountQuerying",
@@ -320,6 +325,7 @@ var (
 			State:           FeatureStateAlpha,
 			RequiresDevMode: true,
 			Owner:           grafanaBackendPlatformSquad,
+			RequiresRestart: true,
 		},
 		{
 			Name:        "accessTokenExpirationCheck",
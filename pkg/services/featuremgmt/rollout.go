@@ -0,0 +1,81 @@
+package featuremgmt
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+)
+
+// FeatureRollout enables a flag for a subset of the instance even when it is
+// switched off globally
+type FeatureRollout struct {
+	OrgIDs  []int64 `json:"orgIds,omitempty"`
+	TeamIDs []int64 `json:"teamIds,omitempty"`
+
+	// Percentage of users (0-100) that get the flag. Users are assigned to a bucket
+	// by hashing the flag name with their id, so the same users stay in the rollout
+	// while the percentage grows.
+	Percentage int `json:"percentage,omitempty"`
+}
+
+// EvaluationContext describes who a flag is being evaluated for
+type EvaluationContext struct {
+	OrgID   int64
+	UserID  int64
+	TeamIDs []int64
+}
+
+type evaluationContextKey struct{}
+
+// WithEvaluationContext returns a copy of ctx that carries the identity used to evaluate rollouts
+func WithEvaluationContext(ctx context.Context, ec EvaluationContext) context.Context {
+	return context.WithValue(ctx, evaluationContextKey{}, ec)
+}
+
+// EvaluationContextFromContext returns the identity stored by WithEvaluationContext
+func EvaluationContextFromContext(ctx context.Context) (EvaluationContext, bool) {
+	if ctx == nil {
+		return EvaluationContext{}, false
+	}
+	ec, ok := ctx.Value(evaluationContextKey{}).(EvaluationContext)
+	return ec, ok
+}
+
+func (r FeatureRollout) matches(flag string, ec EvaluationContext) bool {
+	for _, id := range r.OrgIDs {
+		if id == ec.OrgID {
+			return true
+		}
+	}
+
+	for _, id := range r.TeamIDs {
+		for _, teamID := range ec.TeamIDs {
+			if id == teamID {
+				return true
+			}
+		}
+	}
+
+	if r.Percentage <= 0 {
+		return false
+	}
+	if r.Percentage >= 100 {
+		return true
+	}
+
+	// Anonymous requests are bucketed per organization
+	subject := "user:" + strconv.FormatInt(ec.UserID, 10)
+	if ec.UserID == 0 {
+		subject = "org:" + strconv.FormatInt(ec.OrgID, 10)
+	}
+	return rolloutBucket(flag, subject) < uint32(r.Percentage)
+}
+
+// rolloutBucket maps the subject to a stable value between 0 and 99
+func rolloutBucket(flag string, subject string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(flag))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(subject))
+	return h.Sum32() % 100
+}
@@ -0,0 +1,74 @@
+package featuremgmt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureRollouts(t *testing.T) {
+	ft := FeatureManager{
+		flags: map[string]*FeatureFlag{},
+	}
+	ft.registerFlags(FeatureFlag{
+		Name: "a",
+	}, FeatureFlag{
+		Name:            "b",
+		RequiresRestart: true,
+	})
+
+	t.Run("should match organizations and teams", func(t *testing.T) {
+		ft.SetRollouts(map[string]FeatureRollout{
+			"a": {OrgIDs: []int64{2}, TeamIDs: []int64{5}},
+			"b": {OrgIDs: []int64{2}},
+		})
+
+		ctx := WithEvaluationContext(context.Background(), EvaluationContext{OrgID: 2, UserID: 1})
+		require.True(t, ft.IsEnabledCtx(ctx, "a"))
+		require.False(t, ft.IsEnabledCtx(ctx, "b")) // requires restart
+		require.False(t, ft.IsEnabled("a"))
+		require.Equal(t, map[string]bool{"a": true}, ft.GetEnabled(ctx))
+
+		ctx = WithEvaluationContext(context.Background(), EvaluationContext{OrgID: 1, UserID: 1, TeamIDs: []int64{4, 5}})
+		require.True(t, ft.IsEnabledCtx(ctx, "a"))
+
+		ctx = WithEvaluationContext(context.Background(), EvaluationContext{OrgID: 1, UserID: 1, TeamIDs: []int64{4}})
+		require.False(t, ft.IsEnabledCtx(ctx, "a"))
+
+		// No evaluation context
+		require.False(t, ft.IsEnabledCtx(context.Background(), "a"))
+		require.Empty(t, ft.GetEnabled(context.Background()))
+	})
+
+	t.Run("should roll out to a stable percentage of users", func(t *testing.T) {
+		countEnabled := func(percentage int) map[int64]bool {
+			ft.SetRollouts(map[string]FeatureRollout{"a": {Percentage: percentage}})
+			enabled := map[int64]bool{}
+			for id := int64(1); id <= 1000; id++ {
+				ctx := WithEvaluationContext(context.Background(), EvaluationContext{OrgID: 1, UserID: id})
+				if ft.IsEnabledCtx(ctx, "a") {
+					enabled[id] = true
+				}
+			}
+			return enabled
+		}
+
+		none := countEnabled(0)
+		require.Empty(t, none)
+
+		ten := countEnabled(10)
+		require.InDelta(t, 100, len(ten), 40)
+
+		fifty := countEnabled(50)
+		require.InDelta(t, 500, len(fifty), 80)
+
+		// Users that got the feature keep it while the rollout grows
+		for id := range ten {
+			require.True(t, fifty[id])
+		}
+
+		all := countEnabled(100)
+		require.Len(t, all, 1000)
+	})
+}
@@ -0,0 +1,93 @@
+package runtimetoggles
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/admin/feature-toggles", func(toggles routing.RouteRegister) {
+		toggles.Get("/", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionRead)), routing.Wrap(s.handleList))
+		toggles.Post("/:name/enable", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleEnable))
+		toggles.Post("/:name/disable", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleDisable))
+		toggles.Delete("/:name", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleReset))
+		toggles.Put("/:name/rollout", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleSetRollout))
+		toggles.Delete("/:name/rollout", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleDeleteRollout))
+	})
+}
+
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	toggles, err := s.List(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list feature toggles", err)
+	}
+
+	return response.JSON(http.StatusOK, toggles)
+}
+
+func (s *Service) handleEnable(c *contextmodel.ReqContext) response.Response {
+	return s.setToggle(c, true)
+}
+
+func (s *Service) handleDisable(c *contextmodel.ReqContext) response.Response {
+	return s.setToggle(c, false)
+}
+
+func (s *Service) setToggle(c *contextmodel.ReqContext, enabled bool) response.Response {
+	err := s.Set(c.Req.Context(), SetOverrideCommand{
+		Name:      web.Params(c.Req)[":name"],
+		Enabled:   enabled,
+		UpdatedBy: c.UserID,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update feature toggle", err)
+	}
+
+	if enabled {
+		return response.Success("Feature toggle enabled")
+	}
+	return response.Success("Feature toggle disabled")
+}
+
+func (s *Service) handleReset(c *contextmodel.ReqContext) response.Response {
+	if err := s.Reset(c.Req.Context(), web.Params(c.Req)[":name"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset feature toggle", err)
+	}
+
+	return response.Success("Feature toggle reset to configured value")
+}
+
+func (s *Service) handleSetRollout(c *contextmodel.ReqContext) response.Response {
+	rollout := featuremgmt.FeatureRollout{}
+	if err := web.Bind(c.Req, &rollout); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	err := s.SetRollout(c.Req.Context(), SetRolloutCommand{
+		Name:      web.Params(c.Req)[":name"],
+		Rollout:   rollout,
+		UpdatedBy: c.UserID,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update feature toggle rollout", err)
+	}
+
+	return response.Success("Feature toggle rollout updated")
+}
+
+func (s *Service) handleDeleteRollout(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteRollout(c.Req.Context(), web.Params(c.Req)[":name"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to remove feature toggle rollout", err)
+	}
+
+	return response.Success("Feature toggle rollout removed")
+}
@@ -0,0 +1,93 @@
+package runtimetoggles
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	ActionRead  = "featuremgmt:read"
+	ActionWrite = "featuremgmt:write"
+)
+
+var (
+	ErrRuntimeChangesDisabled = errutil.NewBase(errutil.StatusForbidden, "featuremgmt.runtime-changes-disabled", errutil.WithPublicMessage("Changing feature toggles at runtime is disabled"))
+	ErrOverrideNotFound       = errutil.NewBase(errutil.StatusNotFound, "featuremgmt.override-not-found")
+	ErrRolloutNotFound        = errutil.NewBase(errutil.StatusNotFound, "featuremgmt.rollout-not-found")
+	ErrInvalidRollout         = errutil.NewBase(errutil.StatusBadRequest, "featuremgmt.invalid-rollout")
+)
+
+var (
+	togglesReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:featuremgmt:reader",
+		DisplayName: "Feature toggle reader",
+		Description: "List feature toggles and their current state",
+		Group:       "Feature management",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+		},
+	}
+
+	togglesWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:featuremgmt:writer",
+		DisplayName: "Feature toggle writer",
+		Description: "List, enable and disable feature toggles at runtime",
+		Group:       "Feature management",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+			{Action: ActionWrite},
+		},
+	}
+)
+
+// FeatureToggleOverride is a feature toggle value changed at runtime.
+// It takes precedence over the value from the configuration files.
+type FeatureToggleOverride struct {
+	Id        int64
+	Name      string
+	Enabled   bool
+	Updated   time.Time
+	UpdatedBy int64
+}
+
+// FeatureToggleRollout enables a feature toggle for some organizations, teams
+// or a percentage of users while it stays disabled for everybody else.
+type FeatureToggleRollout struct {
+	Id         int64     `xorm:"pk autoincr 'id'"`
+	Name       string    `xorm:"name"`
+	OrgIDs     string    `xorm:"org_ids"`
+	TeamIDs    string    `xorm:"team_ids"`
+	Percentage int       `xorm:"percentage"`
+	Updated    time.Time `xorm:"'updated'"`
+	UpdatedBy  int64     `xorm:"updated_by"`
+}
+
+type SetRolloutCommand struct {
+	Name      string
+	Rollout   featuremgmt.FeatureRollout
+	UpdatedBy int64
+}
+
+type SetOverrideCommand struct {
+	Name      string
+	Enabled   bool
+	UpdatedBy int64
+}
+
+// FeatureToggleDTO is the state of a feature toggle as returned by the API
+type FeatureToggleDTO struct {
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	State           string     `json:"state"`
+	Enabled         bool       `json:"enabled"`
+	RequiresRestart bool       `json:"requiresRestart"`
+	Overridden      bool       `json:"overridden"`
+	Updated         *time.Time `json:"updated,omitempty"`
+	UpdatedBy       int64      `json:"updatedBy,omitempty"`
+
+	// Rollout enables the toggle for a subset of users while it is disabled globally
+	Rollout *featuremgmt.FeatureRollout `json:"rollout,omitempty"`
+}
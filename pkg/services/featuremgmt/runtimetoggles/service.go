@@ -0,0 +1,274 @@
+package runtimetoggles
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const defaultSyncInterval = 30 * time.Second
+
+// Service persists feature toggle values changed at runtime and keeps
+// every instance of a Grafana cluster in sync with the database.
+type Service struct {
+	accessControl ac.AccessControl
+	features      *featuremgmt.FeatureManager
+	store         store
+	log           log.Logger
+
+	allowEditing bool
+	syncInterval time.Duration
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	features *featuremgmt.FeatureManager,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("feature_management")
+	s := &Service{
+		accessControl: accessControl,
+		features:      features,
+		store:         &sqlStore{db: sql, now: time.Now},
+		log:           log.New("featuremgmt.runtime"),
+		allowEditing:  section.Key("allow_editing").MustBool(false),
+		syncInterval:  section.Key("sync_interval").MustDuration(defaultSyncInterval),
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := accesscontrolService.DeclareFixedRoles(
+			ac.RoleRegistration{Role: togglesReaderRole, Grants: []string{ac.RoleGrafanaAdmin}},
+			ac.RoleRegistration{Role: togglesWriterRole, Grants: []string{ac.RoleGrafanaAdmin}},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+// Run periodically loads the overrides from the database so changes made
+// through another instance are applied here as well.
+func (s *Service) Run(ctx context.Context) error {
+	if !s.allowEditing {
+		return nil
+	}
+
+	if err := s.sync(ctx); err != nil {
+		s.log.Error("failed to load feature toggle overrides", "error", err)
+	}
+
+	ticker := time.NewTicker(s.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.sync(ctx); err != nil {
+				s.log.Error("failed to sync feature toggle overrides", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// List returns all registered feature toggles and their current state.
+func (s *Service) List(ctx context.Context) ([]FeatureToggleDTO, error) {
+	overrides, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rollouts, err := s.loadRollouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]FeatureToggleOverride, len(overrides))
+	for _, o := range overrides {
+		byName[o.Name] = o
+	}
+
+	flags := s.features.GetFlags()
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+
+	result := make([]FeatureToggleDTO, 0, len(flags))
+	for _, flag := range flags {
+		dto := FeatureToggleDTO{
+			Name:            flag.Name,
+			Description:     flag.Description,
+			State:           flag.State.String(),
+			Enabled:         s.features.IsEnabled(flag.Name),
+			RequiresRestart: flag.RequiresRestart,
+		}
+		if o, ok := byName[flag.Name]; ok && !flag.RequiresRestart {
+			updated := o.Updated
+			dto.Overridden = true
+			dto.Updated = &updated
+			dto.UpdatedBy = o.UpdatedBy
+		}
+		if r, ok := rollouts[flag.Name]; ok && !flag.RequiresRestart {
+			rollout := r
+			dto.Rollout = &rollout
+		}
+		result = append(result, dto)
+	}
+
+	return result, nil
+}
+
+// Set persists a runtime value for a feature toggle and applies it immediately.
+func (s *Service) Set(ctx context.Context, cmd SetOverrideCommand) error {
+	if !s.allowEditing {
+		return ErrRuntimeChangesDisabled.Errorf("runtime changes are disabled in the [feature_management] section")
+	}
+
+	if err := s.features.ValidateRuntimeChange(cmd.Name); err != nil {
+		return err
+	}
+
+	if err := s.store.Set(ctx, cmd); err != nil {
+		return err
+	}
+
+	s.log.Info("feature toggle changed at runtime", "name", cmd.Name, "enabled", cmd.Enabled, "userId", cmd.UpdatedBy)
+	return s.sync(ctx)
+}
+
+// Reset removes the runtime value for a feature toggle, restoring the configured value.
+func (s *Service) Reset(ctx context.Context, name string) error {
+	if !s.allowEditing {
+		return ErrRuntimeChangesDisabled.Errorf("runtime changes are disabled in the [feature_management] section")
+	}
+
+	if err := s.features.ValidateRuntimeChange(name); err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.log.Info("feature toggle runtime override removed", "name", name)
+	return s.sync(ctx)
+}
+
+// SetRollout enables a feature toggle for a subset of organizations, teams or users.
+func (s *Service) SetRollout(ctx context.Context, cmd SetRolloutCommand) error {
+	if !s.allowEditing {
+		return ErrRuntimeChangesDisabled.Errorf("runtime changes are disabled in the [feature_management] section")
+	}
+
+	if err := s.features.ValidateRuntimeChange(cmd.Name); err != nil {
+		return err
+	}
+
+	if cmd.Rollout.Percentage < 0 || cmd.Rollout.Percentage > 100 {
+		return ErrInvalidRollout.Errorf("percentage must be between 0 and 100")
+	}
+
+	if len(cmd.Rollout.OrgIDs) == 0 && len(cmd.Rollout.TeamIDs) == 0 && cmd.Rollout.Percentage == 0 {
+		return ErrInvalidRollout.Errorf("rollout must target at least one organization, team or percentage of users")
+	}
+
+	orgIDs, err := json.Marshal(cmd.Rollout.OrgIDs)
+	if err != nil {
+		return err
+	}
+	teamIDs, err := json.Marshal(cmd.Rollout.TeamIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.SetRollout(ctx, FeatureToggleRollout{
+		Name:       cmd.Name,
+		OrgIDs:     string(orgIDs),
+		TeamIDs:    string(teamIDs),
+		Percentage: cmd.Rollout.Percentage,
+		UpdatedBy:  cmd.UpdatedBy,
+	}); err != nil {
+		return err
+	}
+
+	s.log.Info("feature toggle rollout changed", "name", cmd.Name, "orgs", len(cmd.Rollout.OrgIDs),
+		"teams", len(cmd.Rollout.TeamIDs), "percentage", cmd.Rollout.Percentage, "userId", cmd.UpdatedBy)
+	return s.sync(ctx)
+}
+
+// DeleteRollout removes the partial rollout of a feature toggle.
+func (s *Service) DeleteRollout(ctx context.Context, name string) error {
+	if !s.allowEditing {
+		return ErrRuntimeChangesDisabled.Errorf("runtime changes are disabled in the [feature_management] section")
+	}
+
+	if err := s.features.ValidateRuntimeChange(name); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteRollout(ctx, name); err != nil {
+		return err
+	}
+
+	s.log.Info("feature toggle rollout removed", "name", name)
+	return s.sync(ctx)
+}
+
+func (s *Service) loadRollouts(ctx context.Context) (map[string]featuremgmt.FeatureRollout, error) {
+	rows, err := s.store.ListRollouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rollouts := make(map[string]featuremgmt.FeatureRollout, len(rows))
+	for _, row := range rows {
+		rollout := featuremgmt.FeatureRollout{Percentage: row.Percentage}
+		if row.OrgIDs != "" {
+			if err := json.Unmarshal([]byte(row.OrgIDs), &rollout.OrgIDs); err != nil {
+				return nil, err
+			}
+		}
+		if row.TeamIDs != "" {
+			if err := json.Unmarshal([]byte(row.TeamIDs), &rollout.TeamIDs); err != nil {
+				return nil, err
+			}
+		}
+		rollouts[row.Name] = rollout
+	}
+	return rollouts, nil
+}
+
+func (s *Service) sync(ctx context.Context) error {
+	overrides, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]bool, len(overrides))
+	for _, o := range overrides {
+		values[o.Name] = o.Enabled
+	}
+
+	rollouts, err := s.loadRollouts(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.features.SetRuntimeOverrides(values)
+	s.features.SetRollouts(rollouts)
+	return nil
+}
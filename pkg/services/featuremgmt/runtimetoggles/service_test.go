@@ -0,0 +1,123 @@
+package runtimetoggles
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationRuntimeToggles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	newService := func(t *testing.T, allowEditing bool) *Service {
+		t.Helper()
+		features, err := featuremgmt.ProvideManagerService(setting.NewCfg(), nil)
+		require.NoError(t, err)
+
+		return &Service{
+			features:     features,
+			store:        &sqlStore{db: testDB, now: time.Now},
+			log:          log.NewNopLogger(),
+			allowEditing: allowEditing,
+			syncInterval: time.Second,
+		}
+	}
+
+	ctx := context.Background()
+
+	t.Run("should reject changes when editing is disabled", func(t *testing.T) {
+		s := newService(t, false)
+		err := s.Set(ctx, SetOverrideCommand{Name: featuremgmt.FlagTrimDefaults, Enabled: true})
+		require.ErrorIs(t, err, ErrRuntimeChangesDisabled)
+	})
+
+	t.Run("should reject toggles that require a restart", func(t *testing.T) {
+		s := newService(t, true)
+		err := s.Set(ctx, SetOverrideCommand{Name: featuremgmt.FlagValidateDashboardsOnSave, Enabled: true})
+		require.ErrorIs(t, err, featuremgmt.ErrFeatureFlagRequiresRestart)
+
+		err = s.Set(ctx, SetOverrideCommand{Name: "notARealToggle", Enabled: true})
+		require.ErrorIs(t, err, featuremgmt.ErrFeatureFlagNotFound)
+	})
+
+	t.Run("should apply and broadcast runtime changes", func(t *testing.T) {
+		s := newService(t, true)
+		other := newService(t, true)
+		require.False(t, s.features.IsEnabled(featuremgmt.FlagTrimDefaults))
+
+		err := s.Set(ctx, SetOverrideCommand{Name: featuremgmt.FlagTrimDefaults, Enabled: true, UpdatedBy: 1})
+		require.NoError(t, err)
+		require.True(t, s.features.IsEnabled(featuremgmt.FlagTrimDefaults))
+
+		// Another instance picks up the change on its next sync
+		require.False(t, other.features.IsEnabled(featuremgmt.FlagTrimDefaults))
+		require.NoError(t, other.sync(ctx))
+		require.True(t, other.features.IsEnabled(featuremgmt.FlagTrimDefaults))
+
+		toggles, err := s.List(ctx)
+		require.NoError(t, err)
+		for _, toggle := range toggles {
+			if toggle.Name == featuremgmt.FlagTrimDefaults {
+				require.True(t, toggle.Enabled)
+				require.True(t, toggle.Overridden)
+				require.Equal(t, int64(1), toggle.UpdatedBy)
+			}
+		}
+
+		// Updating an existing override
+		err = s.Set(ctx, SetOverrideCommand{Name: featuremgmt.FlagTrimDefaults, Enabled: false, UpdatedBy: 1})
+		require.NoError(t, err)
+		require.False(t, s.features.IsEnabled(featuremgmt.FlagTrimDefaults))
+
+		require.NoError(t, s.Reset(ctx, featuremgmt.FlagTrimDefaults))
+		require.Empty(t, s.features.GetRuntimeOverrides())
+		require.ErrorIs(t, s.Reset(ctx, featuremgmt.FlagTrimDefaults), ErrOverrideNotFound)
+		require.ErrorIs(t, s.Reset(ctx, "unknownFlag"), featuremgmt.ErrFeatureFlagNotFound)
+	})
+
+	t.Run("should store and apply rollouts", func(t *testing.T) {
+		s := newService(t, true)
+
+		err := s.SetRollout(ctx, SetRolloutCommand{Name: featuremgmt.FlagTrimDefaults, Rollout: featuremgmt.FeatureRollout{Percentage: 101}})
+		require.ErrorIs(t, err, ErrInvalidRollout)
+
+		err = s.SetRollout(ctx, SetRolloutCommand{Name: featuremgmt.FlagTrimDefaults})
+		require.ErrorIs(t, err, ErrInvalidRollout)
+
+		err = s.SetRollout(ctx, SetRolloutCommand{
+			Name:      featuremgmt.FlagTrimDefaults,
+			Rollout:   featuremgmt.FeatureRollout{OrgIDs: []int64{3}, Percentage: 10},
+			UpdatedBy: 1,
+		})
+		require.NoError(t, err)
+
+		inOrg := featuremgmt.WithEvaluationContext(ctx, featuremgmt.EvaluationContext{OrgID: 3, UserID: 1})
+		require.True(t, s.features.IsEnabledCtx(inOrg, featuremgmt.FlagTrimDefaults))
+		require.False(t, s.features.IsEnabled(featuremgmt.FlagTrimDefaults))
+
+		toggles, err := s.List(ctx)
+		require.NoError(t, err)
+		for _, toggle := range toggles {
+			if toggle.Name == featuremgmt.FlagTrimDefaults {
+				require.NotNil(t, toggle.Rollout)
+				require.Equal(t, []int64{3}, toggle.Rollout.OrgIDs)
+				require.Equal(t, 10, toggle.Rollout.Percentage)
+			}
+		}
+
+		require.NoError(t, s.DeleteRollout(ctx, featuremgmt.FlagTrimDefaults))
+		require.False(t, s.features.IsEnabledCtx(inOrg, featuremgmt.FlagTrimDefaults))
+		require.ErrorIs(t, s.DeleteRollout(ctx, featuremgmt.FlagTrimDefaults), ErrRolloutNotFound)
+		require.ErrorIs(t, s.DeleteRollout(ctx, "unknownFlag"), featuremgmt.ErrFeatureFlagNotFound)
+	})
+}
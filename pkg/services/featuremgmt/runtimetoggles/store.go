@@ -0,0 +1,122 @@
+package runtimetoggles
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type store interface {
+	List(ctx context.Context) ([]FeatureToggleOverride, error)
+	Set(ctx context.Context, cmd SetOverrideCommand) error
+	Delete(ctx context.Context, name string) error
+	ListRollouts(ctx context.Context) ([]FeatureToggleRollout, error)
+	SetRollout(ctx context.Context, rollout FeatureToggleRollout) error
+	DeleteRollout(ctx context.Context, name string) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) List(ctx context.Context) ([]FeatureToggleOverride, error) {
+	overrides := make([]FeatureToggleOverride, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Asc("name").Find(&overrides)
+	})
+	return overrides, err
+}
+
+func (s *sqlStore) Set(ctx context.Context, cmd SetOverrideCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := FeatureToggleOverride{}
+		has, err := sess.Where("name = ?", cmd.Name).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		override := FeatureToggleOverride{
+			Name:      cmd.Name,
+			Enabled:   cmd.Enabled,
+			Updated:   s.now(),
+			UpdatedBy: cmd.UpdatedBy,
+		}
+
+		if !has {
+			_, err = sess.Insert(&override)
+			return err
+		}
+
+		_, err = sess.ID(existing.Id).Cols("enabled", "updated", "updated_by").Update(&override)
+		return err
+	})
+}
+
+func (s *sqlStore) Delete(ctx context.Context, name string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM feature_toggle_override WHERE name = ?", name)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return ErrOverrideNotFound.Errorf("feature toggle %q has no runtime override", name)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) ListRollouts(ctx context.Context) ([]FeatureToggleRollout, error) {
+	rollouts := make([]FeatureToggleRollout, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Asc("name").Find(&rollouts)
+	})
+	return rollouts, err
+}
+
+func (s *sqlStore) SetRollout(ctx context.Context, rollout FeatureToggleRollout) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := FeatureToggleRollout{}
+		has, err := sess.Where("name = ?", rollout.Name).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		rollout.Updated = s.now()
+		if !has {
+			_, err = sess.Insert(&rollout)
+			return err
+		}
+
+		_, err = sess.ID(existing.Id).
+			Cols("org_ids", "team_ids", "percentage", "updated", "updated_by").
+			Update(&rollout)
+		return err
+	})
+}
+
+func (s *sqlStore) DeleteRollout(ctx context.Context, name string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM feature_toggle_rollout WHERE name = ?", name)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return ErrRolloutNotFound.Errorf("feature toggle %q has no rollout", name)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package folderimpl
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/folder"
+)
+
+// The folder_closure table stores a row for every folder and each of its ancestors, including the folder itself
+// at depth 0. It lets the access control filters expand folder permissions to the subfolders without recursive
+// queries, which are not supported by MySQL before 8.0.
+
+type folderClosure struct {
+	AncestorUID   string `xorm:"ancestor_uid"`
+	DescendantUID string `xorm:"descendant_uid"`
+	Depth         int    `xorm:"depth"`
+}
+
+// insertClosure adds the rows of a new folder, which has no subfolders yet.
+func insertClosure(sess *db.Session, orgID int64, uid string, parentUID string) error {
+	if _, err := sess.Exec("INSERT INTO folder_closure (org_id, ancestor_uid, descendant_uid, depth) VALUES (?, ?, ?, 0)", orgID, uid, uid); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to insert folder closure: %w", err)
+	}
+	if parentUID == "" || parentUID == folder.GeneralFolderUID {
+		return nil
+	}
+	if _, err := sess.Exec(`INSERT INTO folder_closure (org_id, ancestor_uid, descendant_uid, depth)
+		SELECT org_id, ancestor_uid, ?, depth + 1 FROM folder_closure WHERE org_id = ? AND descendant_uid = ?`, uid, orgID, parentUID); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to insert folder closure: %w", err)
+	}
+	return nil
+}
+
+// deleteClosure removes the rows of a deleted folder.
+func deleteClosure(sess *db.Session, orgID int64, uid string) error {
+	if _, err := sess.Exec("DELETE FROM folder_closure WHERE org_id = ? AND (ancestor_uid = ? OR descendant_uid = ?)", orgID, uid, uid); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to delete folder closure: %w", err)
+	}
+	return nil
+}
+
+// renameClosure replaces the UID of a folder in its rows.
+func renameClosure(sess *db.Session, orgID int64, uid string, newUID string) error {
+	if _, err := sess.Exec("UPDATE folder_closure SET ancestor_uid = ? WHERE org_id = ? AND ancestor_uid = ?", newUID, orgID, uid); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to update folder closure: %w", err)
+	}
+	if _, err := sess.Exec("UPDATE folder_closure SET descendant_uid = ? WHERE org_id = ? AND descendant_uid = ?", newUID, orgID, uid); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to update folder closure: %w", err)
+	}
+	return nil
+}
+
+// moveClosure detaches the subtree of a folder from its former ancestors and attaches it to the ancestors of the new parent.
+func moveClosure(sess *db.Session, orgID int64, uid string, newParentUID string) error {
+	var subtree []folderClosure
+	if err := sess.SQL("SELECT descendant_uid, depth FROM folder_closure WHERE org_id = ? AND ancestor_uid = ?", orgID, uid).Find(&subtree); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to get folder closure: %w", err)
+	}
+	if len(subtree) == 0 {
+		return nil
+	}
+
+	subtreeUIDs := make([]interface{}, 0, len(subtree))
+	for _, d := range subtree {
+		subtreeUIDs = append(subtreeUIDs, d.DescendantUID)
+	}
+	placeholders := "?" + strings.Repeat(", ?", len(subtreeUIDs)-1)
+	args := append([]interface{}{"DELETE FROM folder_closure WHERE org_id = ? AND descendant_uid IN (" + placeholders + ") AND ancestor_uid NOT IN (" + placeholders + ")", orgID}, subtreeUIDs...)
+	args = append(args, subtreeUIDs...)
+	if _, err := sess.Exec(args...); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to delete folder closure: %w", err)
+	}
+
+	if newParentUID == "" || newParentUID == folder.GeneralFolderUID {
+		return nil
+	}
+	var ancestors []folderClosure
+	if err := sess.SQL("SELECT ancestor_uid, depth FROM folder_closure WHERE org_id = ? AND descendant_uid = ?", orgID, newParentUID).Find(&ancestors); err != nil {
+		return folder.ErrDatabaseError.Errorf("failed to get folder closure: %w", err)
+	}
+	for _, a := range ancestors {
+		for _, d := range subtree {
+			if _, err := sess.Exec("INSERT INTO folder_closure (org_id, ancestor_uid, descendant_uid, depth) VALUES (?, ?, ?, ?)", orgID, a.AncestorUID, d.DescendantUID, a.Depth+d.Depth+1); err != nil {
+				return folder.ErrDatabaseError.Errorf("failed to insert folder closure: %w", err)
+			}
+		}
+	}
+	return nil
+}
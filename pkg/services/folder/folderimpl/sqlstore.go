@@ -70,6 +70,10 @@ func (ss *sqlStore) Create(ctx context.Context, cmd folder.CreateFolderCommand)
 			return err
 		}
 
+		if err := insertClosure(sess, cmd.OrgID, cmd.UID, cmd.ParentUID); err != nil {
+			return err
+		}
+
 		foldr, err = ss.Get(ctx, folder.GetFolderQuery{
 			ID: &lastInsertedID,
 		})
@@ -87,7 +91,7 @@ func (ss *sqlStore) Delete(ctx context.Context, uid string, orgID int64) error {
 		if err != nil {
 			return folder.ErrDatabaseError.Errorf("failed to delete folder: %w", err)
 		}
-		return nil
+		return deleteClosure(sess, orgID, uid)
 	})
 }
 
@@ -145,6 +149,17 @@ func (ss *sqlStore) Update(ctx context.Context, cmd folder.UpdateFolderCommand)
 			return folder.ErrInternal.Errorf("no folders are updated")
 		}
 
+		if cmd.NewUID != nil && *cmd.NewUID != cmd.UID {
+			if err := renameClosure(sess, cmd.OrgID, cmd.UID, *cmd.NewUID); err != nil {
+				return err
+			}
+		}
+		if cmd.NewParentUID != nil {
+			if err := moveClosure(sess, cmd.OrgID, uid, *cmd.NewParentUID); err != nil {
+				return err
+			}
+		}
+
 		foldr, err = ss.Get(ctx, folder.GetFolderQuery{
 			UID:   &uid,
 			OrgID: cmd.OrgID,
@@ -616,6 +616,76 @@ func TestIntegrationGetHeight(t *testing.T) {
 	})
 }
 
+func TestIntegrationFolderClosure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	db := sqlstore.InitTestDB(t)
+	folderStore := ProvideStore(db, db.Cfg, &featuremgmt.FeatureManager{})
+
+	orgID := CreateOrg(t, db)
+
+	descendants := func(t *testing.T, uid string) map[string]int {
+		t.Helper()
+		var rows []folderClosure
+		err := db.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+			return sess.SQL("SELECT descendant_uid, depth FROM folder_closure WHERE org_id = ? AND ancestor_uid = ?", orgID, uid).Find(&rows)
+		})
+		require.NoError(t, err)
+		result := make(map[string]int, len(rows))
+		for _, r := range rows {
+			result[r.DescendantUID] = r.Depth
+		}
+		return result
+	}
+
+	// a > b > c, d
+	tree := CreateSubtree(t, folderStore, orgID, "", 3, "closure")
+	a, b, c := tree[0], tree[1], tree[2]
+	d := CreateSubtree(t, folderStore, orgID, "", 1, "other")[0]
+
+	t.Run("creating folders should add the rows of their ancestors", func(t *testing.T) {
+		assert.Equal(t, map[string]int{a: 0, b: 1, c: 2}, descendants(t, a))
+		assert.Equal(t, map[string]int{b: 0, c: 1}, descendants(t, b))
+		assert.Equal(t, map[string]int{d: 0}, descendants(t, d))
+	})
+
+	t.Run("moving a folder should move its subtree", func(t *testing.T) {
+		_, err := folderStore.Update(context.Background(), folder.UpdateFolderCommand{
+			UID:          b,
+			OrgID:        orgID,
+			NewParentUID: &d,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]int{a: 0}, descendants(t, a))
+		assert.Equal(t, map[string]int{d: 0, b: 1, c: 2}, descendants(t, d))
+		assert.Equal(t, map[string]int{b: 0, c: 1}, descendants(t, b))
+	})
+
+	t.Run("changing the UID of a folder should update its rows", func(t *testing.T) {
+		newUID := util.GenerateShortUID()
+		_, err := folderStore.Update(context.Background(), folder.UpdateFolderCommand{
+			UID:    b,
+			OrgID:  orgID,
+			NewUID: &newUID,
+		})
+		require.NoError(t, err)
+		b = newUID
+
+		assert.Equal(t, map[string]int{d: 0, b: 1, c: 2}, descendants(t, d))
+		assert.Equal(t, map[string]int{b: 0, c: 1}, descendants(t, b))
+	})
+
+	t.Run("deleting a folder should delete its rows", func(t *testing.T) {
+		require.NoError(t, folderStore.Delete(context.Background(), c, orgID))
+
+		assert.Equal(t, map[string]int{d: 0, b: 1}, descendants(t, d))
+		assert.Empty(t, descendants(t, c))
+	})
+}
+
 func CreateOrg(t *testing.T, db *sqlstore.SQLStore) int64 {
 	t.Helper()
 
@@ -0,0 +1,47 @@
+package foldersettings
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Service manages the settings of the folders: the defaults the dashboards of a folder inherit when they are
+// saved, and whether their changes require an approval.
+type Service interface {
+	// Get returns the settings of a folder, empty settings if none are set.
+	Get(ctx context.Context, orgID int64, folderUID string) (*FolderSettings, error)
+	// Set replaces the settings of a folder.
+	Set(ctx context.Context, cmd *SetCommand) error
+}
+
+var (
+	ErrFolderNotFound    = errutil.NewBase(errutil.StatusNotFound, "foldersettings.folder-not-found", errutil.WithPublicMessage("Folder not found"))
+	ErrInvalidDataSource = errutil.NewBase(errutil.StatusBadRequest, "foldersettings.invalid-datasource")
+	ErrInvalidRefresh    = errutil.NewBase(errutil.StatusBadRequest, "foldersettings.invalid-refresh")
+)
+
+// FolderSettings are the defaults and the review policy of the dashboards of a folder.
+type FolderSettings struct {
+	// DefaultDatasourceUID is the data source of the panels without data source of the new dashboards.
+	DefaultDatasourceUID string `json:"defaultDatasourceUid"`
+	// Tags are added to the dashboards of the folder every time they are saved.
+	Tags []string `json:"tags"`
+	// DefaultRefresh is the refresh interval of the new dashboards without refresh interval.
+	DefaultRefresh string `json:"defaultRefresh"`
+	// RequireApproval holds the changes of the dashboards of the folder until they are approved by a user with
+	// the dashboards:approve action on the folder.
+	RequireApproval bool `json:"requireApproval"`
+}
+
+// IsEmpty returns true if no default is set, regardless of the approval of the changes.
+func (s FolderSettings) IsEmpty() bool {
+	return s.DefaultDatasourceUID == "" && len(s.Tags) == 0 && s.DefaultRefresh == ""
+}
+
+type SetCommand struct {
+	FolderSettings
+	OrgID     int64  `json:"-"`
+	FolderUID string `json:"-"`
+	UpdatedBy int64  `json:"-"`
+}
@@ -0,0 +1,65 @@
+package foldersettingsimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+	uidScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":uid"))
+
+	routeRegister.Group("/api/folders/:uid/settings", func(settings routing.RouteRegister) {
+		settings.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionFoldersRead, uidScope)), routing.Wrap(s.handleGet))
+		settings.Put("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(dashboards.ActionFoldersWrite, uidScope)), routing.Wrap(s.handleSet))
+	})
+}
+
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	settings, err := s.Get(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get folder settings", err)
+	}
+
+	return response.JSON(http.StatusOK, settings)
+}
+
+func (s *Service) handleSet(c *contextmodel.ReqContext) response.Response {
+	cmd := foldersettings.SetCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.FolderUID = web.Params(c.Req)[":uid"]
+	cmd.UpdatedBy = c.UserID
+
+	// the approval of the changes can only be turned on or off by the users approving them
+	current, err := s.Get(c.Req.Context(), cmd.OrgID, cmd.FolderUID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get folder settings", err)
+	}
+	if current.RequireApproval != cmd.RequireApproval {
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(cmd.FolderUID)
+		canApprove, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalPermission(dashboards.ActionDashboardsApprove, scope))
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to check permissions", err)
+		}
+		if !canApprove {
+			return response.Error(http.StatusForbidden, "Changing the approval of the folder requires the permission to approve its changes", nil)
+		}
+	}
+
+	if err := s.Set(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update folder settings", err)
+	}
+
+	return response.Success("Folder settings updated")
+}
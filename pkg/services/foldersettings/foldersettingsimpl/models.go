@@ -0,0 +1,21 @@
+package foldersettingsimpl
+
+import "time"
+
+// folderSetting is the row of the settings of a folder, the tags are stored as a JSON array.
+type folderSetting struct {
+	ID                   int64     `xorm:"pk autoincr 'id'"`
+	OrgID                int64     `xorm:"org_id"`
+	FolderUID            string    `xorm:"folder_uid"`
+	DefaultDatasourceUID string    `xorm:"default_datasource_uid"`
+	Tags                 string    `xorm:"tags"`
+	DefaultRefresh       string    `xorm:"default_refresh"`
+	RequireApproval      bool      `xorm:"require_approval"`
+	Created              time.Time `xorm:"created"`
+	Updated              time.Time `xorm:"updated"`
+	UpdatedBy            int64     `xorm:"updated_by"`
+}
+
+func (folderSetting) TableName() string {
+	return "folder_setting"
+}
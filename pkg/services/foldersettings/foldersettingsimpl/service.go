@@ -0,0 +1,212 @@
+package foldersettingsimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// cacheTTL bounds how long an instance can apply stale defaults after
+// they were changed through another instance.
+const cacheTTL = 30 * time.Second
+
+type Service struct {
+	store             store
+	cache             *localcache.CacheService
+	folderStore       folder.FolderStore
+	dataSourceService datasources.DataSourceService
+	accessControl     ac.AccessControl
+	log               log.Logger
+}
+
+var _ foldersettings.Service = (*Service)(nil)
+
+func ProvideService(
+	sql db.DB,
+	folderStore folder.FolderStore,
+	dataSourceService datasources.DataSourceService,
+	dashboardService *dashboardservice.DashboardServiceImpl,
+	accessControl ac.AccessControl,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := &Service{
+		store:             &sqlStore{db: sql, now: time.Now},
+		cache:             localcache.New(cacheTTL, 2*cacheTTL),
+		folderStore:       folderStore,
+		dataSourceService: dataSourceService,
+		accessControl:     accessControl,
+		log:               log.New("foldersettings"),
+	}
+
+	dashboardService.RegisterSaveHook(s.applyDefaults)
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+func (s *Service) Get(ctx context.Context, orgID int64, folderUID string) (*foldersettings.FolderSettings, error) {
+	if cached, ok := s.cache.Get(cacheKey(orgID, folderUID)); ok {
+		return cached.(*foldersettings.FolderSettings), nil
+	}
+
+	settings, err := s.store.Get(ctx, orgID, folderUID)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(cacheKey(orgID, folderUID), settings, cacheTTL)
+
+	return settings, nil
+}
+
+func (s *Service) Set(ctx context.Context, cmd *foldersettings.SetCommand) error {
+	if _, err := s.folderStore.GetFolderByUID(ctx, cmd.OrgID, cmd.FolderUID); err != nil {
+		if errors.Is(err, dashboards.ErrFolderNotFound) {
+			return foldersettings.ErrFolderNotFound.Errorf("folder %s not found", cmd.FolderUID)
+		}
+		return err
+	}
+
+	cmd.DefaultDatasourceUID = strings.TrimSpace(cmd.DefaultDatasourceUID)
+	if cmd.DefaultDatasourceUID != "" {
+		_, err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgID: cmd.OrgID, UID: cmd.DefaultDatasourceUID})
+		if err != nil {
+			if errors.Is(err, datasources.ErrDataSourceNotFound) {
+				return foldersettings.ErrInvalidDataSource.Errorf("data source %s not found", cmd.DefaultDatasourceUID)
+			}
+			return err
+		}
+	}
+
+	cmd.DefaultRefresh = strings.TrimSpace(cmd.DefaultRefresh)
+	if err := validateRefresh(cmd.DefaultRefresh); err != nil {
+		return err
+	}
+	cmd.Tags = normalizeTags(cmd.Tags)
+
+	if err := s.store.Set(ctx, cmd); err != nil {
+		return err
+	}
+	s.cache.Delete(cacheKey(cmd.OrgID, cmd.FolderUID))
+
+	s.log.Info("Folder settings updated", "orgId", cmd.OrgID, "folderUid", cmd.FolderUID, "userId", cmd.UpdatedBy)
+	return nil
+}
+
+// applyDefaults is the dashboard save hook applying the settings of the folder of the dashboard: the default data
+// source and refresh interval are only set on the new dashboards, the tags are added on every save.
+func (s *Service) applyDefaults(ctx context.Context, dash *dashboards.Dashboard, _ *user.SignedInUser, isNew bool) error {
+	if dash.IsFolder || dash.FolderID == 0 {
+		return nil
+	}
+
+	f, err := s.folderStore.GetFolderByID(ctx, dash.OrgID, dash.FolderID)
+	if err != nil {
+		return err
+	}
+	settings, err := s.Get(ctx, dash.OrgID, f.UID)
+	if err != nil {
+		return err
+	}
+	if settings.IsEmpty() {
+		return nil
+	}
+
+	if len(settings.Tags) > 0 {
+		tags := normalizeTags(append(dash.GetTags(), settings.Tags...))
+		// the tags are set as a JSON array, as they are read from the dashboard model
+		values := make([]interface{}, len(tags))
+		for i, tag := range tags {
+			values[i] = tag
+		}
+		dash.Data.Set("tags", values)
+	}
+
+	if !isNew {
+		return nil
+	}
+
+	if settings.DefaultRefresh != "" && dash.Data.Get("refresh").MustString() == "" {
+		dash.Data.Set("refresh", settings.DefaultRefresh)
+	}
+
+	if settings.DefaultDatasourceUID != "" {
+		ds, err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgID: dash.OrgID, UID: settings.DefaultDatasourceUID})
+		if err != nil {
+			// the data source was deleted after the settings were saved, the dashboard is saved without it
+			s.log.Warn("Failed to get the default data source of the folder", "folderUid", f.UID, "datasourceUid", settings.DefaultDatasourceUID, "error", err)
+			return nil
+		}
+		setDefaultDatasource(dash.Data, map[string]interface{}{"type": ds.Type, "uid": ds.UID})
+	}
+
+	return nil
+}
+
+// setDefaultDatasource sets the data source of the panels without data source, including the panels of collapsed rows.
+func setDefaultDatasource(dashboard *simplejson.Json, ref map[string]interface{}) {
+	for _, panelObj := range dashboard.Get("panels").MustArray() {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			setDefaultDatasource(panel, ref)
+			continue
+		}
+		if ds, ok := panel.CheckGet("datasource"); ok && ds.Interface() != nil {
+			continue
+		}
+		panel.Set("datasource", ref)
+	}
+}
+
+func validateRefresh(refresh string) error {
+	if refresh == "" {
+		return nil
+	}
+	d, err := gtime.ParseDuration(refresh)
+	if err != nil {
+		return foldersettings.ErrInvalidRefresh.Errorf("invalid refresh interval %q: %w", refresh, err)
+	}
+	if setting.MinRefreshInterval != "" {
+		minRefresh, err := gtime.ParseDuration(setting.MinRefreshInterval)
+		if err == nil && d < minRefresh {
+			return foldersettings.ErrInvalidRefresh.Errorf("refresh interval %s is shorter than the minimum refresh interval %s", refresh, setting.MinRefreshInterval)
+		}
+	}
+	return nil
+}
+
+// normalizeTags trims and deduplicates the tags, keeping their order.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+func cacheKey(orgID int64, folderUID string) string {
+	return fmt.Sprintf("foldersettings-%d-%s", orgID, folderUID)
+}
@@ -0,0 +1,136 @@
+package foldersettingsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakedatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const newDashboard = `{
+	"title": "New dashboard",
+	"tags": ["team-a"],
+	"panels": [
+		{"id": 1, "type": "timeseries"},
+		{"id": 2, "type": "timeseries", "datasource": {"type": "loki", "uid": "logs"}},
+		{"id": 3, "type": "row", "collapsed": true, "panels": [{"id": 4, "type": "stat", "datasource": null}]}
+	]
+}`
+
+func TestIntegrationFolderSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	folderStore := foldertest.NewFakeFolderStore(t)
+	folderStore.On("GetFolderByUID", mock.Anything, int64(1), "folder").Return(&folder.Folder{ID: 10, UID: "folder"}, nil).Maybe()
+	folderStore.On("GetFolderByUID", mock.Anything, int64(1), "missing").Return(nil, dashboards.ErrFolderNotFound).Maybe()
+	folderStore.On("GetFolderByID", mock.Anything, int64(1), int64(10)).Return(&folder.Folder{ID: 10, UID: "folder"}, nil).Maybe()
+
+	s := &Service{
+		store:       &sqlStore{db: testDB, now: time.Now},
+		cache:       localcache.New(cacheTTL, 2*cacheTTL),
+		folderStore: folderStore,
+		dataSourceService: &fakedatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{ID: 1, OrgID: 1, UID: "prom", Type: "prometheus"},
+		}},
+		log: log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	newDash := func(t *testing.T) *dashboards.Dashboard {
+		t.Helper()
+		data, err := simplejson.NewJson([]byte(newDashboard))
+		require.NoError(t, err)
+		dash := dashboards.NewDashboardFromJson(data)
+		dash.OrgID = 1
+		dash.FolderID = 10
+		return dash
+	}
+
+	t.Run("should return empty settings by default", func(t *testing.T) {
+		settings, err := s.Get(ctx, 1, "folder")
+		require.NoError(t, err)
+		require.True(t, settings.IsEmpty())
+
+		dash := newDash(t)
+		require.NoError(t, s.applyDefaults(ctx, dash, nil, true))
+		require.Equal(t, []string{"team-a"}, dash.GetTags())
+	})
+
+	t.Run("should validate the settings", func(t *testing.T) {
+		err := s.Set(ctx, &foldersettings.SetCommand{OrgID: 1, FolderUID: "missing"})
+		require.ErrorIs(t, err, foldersettings.ErrFolderNotFound)
+		err = s.Set(ctx, &foldersettings.SetCommand{OrgID: 1, FolderUID: "folder", FolderSettings: foldersettings.FolderSettings{DefaultDatasourceUID: "unknown"}})
+		require.ErrorIs(t, err, foldersettings.ErrInvalidDataSource)
+		err = s.Set(ctx, &foldersettings.SetCommand{OrgID: 1, FolderUID: "folder", FolderSettings: foldersettings.FolderSettings{DefaultRefresh: "often"}})
+		require.ErrorIs(t, err, foldersettings.ErrInvalidRefresh)
+
+		oldRefreshInterval := setting.MinRefreshInterval
+		setting.MinRefreshInterval = "5s"
+		defer func() { setting.MinRefreshInterval = oldRefreshInterval }()
+		err = s.Set(ctx, &foldersettings.SetCommand{OrgID: 1, FolderUID: "folder", FolderSettings: foldersettings.FolderSettings{DefaultRefresh: "1s"}})
+		require.ErrorIs(t, err, foldersettings.ErrInvalidRefresh)
+	})
+
+	err := s.Set(ctx, &foldersettings.SetCommand{OrgID: 1, FolderUID: "folder", UpdatedBy: 2, FolderSettings: foldersettings.FolderSettings{
+		DefaultDatasourceUID: "prom",
+		Tags:                 []string{"production", " team-a ", "production", ""},
+		DefaultRefresh:       "1m",
+		RequireApproval:      true,
+	}})
+	require.NoError(t, err)
+
+	t.Run("should store the settings", func(t *testing.T) {
+		settings, err := s.Get(ctx, 1, "folder")
+		require.NoError(t, err)
+		require.Equal(t, &foldersettings.FolderSettings{DefaultDatasourceUID: "prom", Tags: []string{"production", "team-a"}, DefaultRefresh: "1m", RequireApproval: true}, settings)
+	})
+
+	t.Run("should apply the defaults to the new dashboards", func(t *testing.T) {
+		dash := newDash(t)
+		require.NoError(t, s.applyDefaults(ctx, dash, nil, true))
+
+		require.Equal(t, []string{"team-a", "production"}, dash.GetTags())
+		require.Equal(t, "1m", dash.Data.Get("refresh").MustString())
+		panels := dash.Data.Get("panels")
+		require.Equal(t, "prom", panels.GetIndex(0).Get("datasource").Get("uid").MustString())
+		require.Equal(t, "prometheus", panels.GetIndex(0).Get("datasource").Get("type").MustString())
+		require.Equal(t, "logs", panels.GetIndex(1).Get("datasource").Get("uid").MustString())
+		require.Equal(t, "prom", panels.GetIndex(2).Get("panels").GetIndex(0).Get("datasource").Get("uid").MustString())
+		_, ok := panels.GetIndex(2).CheckGet("datasource")
+		require.False(t, ok)
+	})
+
+	t.Run("should only enforce the tags of the existing dashboards", func(t *testing.T) {
+		dash := newDash(t)
+		require.NoError(t, s.applyDefaults(ctx, dash, nil, false))
+
+		require.Equal(t, []string{"team-a", "production"}, dash.GetTags())
+		require.Empty(t, dash.Data.Get("refresh").MustString())
+		_, ok := dash.Data.Get("panels").GetIndex(0).CheckGet("datasource")
+		require.False(t, ok)
+	})
+
+	t.Run("should ignore the dashboards of the general folder", func(t *testing.T) {
+		dash := newDash(t)
+		dash.FolderID = 0
+		require.NoError(t, s.applyDefaults(ctx, dash, nil, true))
+		require.Equal(t, []string{"team-a"}, dash.GetTags())
+	})
+}
@@ -0,0 +1,72 @@
+package foldersettingsimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/foldersettings"
+)
+
+type store interface {
+	Get(ctx context.Context, orgID int64, folderUID string) (*foldersettings.FolderSettings, error)
+	Set(ctx context.Context, cmd *foldersettings.SetCommand) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) Get(ctx context.Context, orgID int64, folderUID string) (*foldersettings.FolderSettings, error) {
+	result := &foldersettings.FolderSettings{Tags: []string{}}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		row := folderSetting{}
+		has, err := sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Get(&row)
+		if err != nil || !has {
+			return err
+		}
+		result.DefaultDatasourceUID = row.DefaultDatasourceUID
+		result.DefaultRefresh = row.DefaultRefresh
+		result.RequireApproval = row.RequireApproval
+		return json.Unmarshal([]byte(row.Tags), &result.Tags)
+	})
+	return result, err
+}
+
+func (s *sqlStore) Set(ctx context.Context, cmd *foldersettings.SetCommand) error {
+	tags, err := json.Marshal(cmd.Tags)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := folderSetting{}
+		has, err := sess.Where("org_id = ? AND folder_uid = ?", cmd.OrgID, cmd.FolderUID).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		row := folderSetting{
+			OrgID:                cmd.OrgID,
+			FolderUID:            cmd.FolderUID,
+			DefaultDatasourceUID: cmd.DefaultDatasourceUID,
+			Tags:                 string(tags),
+			DefaultRefresh:       cmd.DefaultRefresh,
+			RequireApproval:      cmd.RequireApproval,
+			Created:              now,
+			Updated:              now,
+			UpdatedBy:            cmd.UpdatedBy,
+		}
+
+		if !has {
+			_, err = sess.Insert(&row)
+			return err
+		}
+
+		_, err = sess.ID(existing.ID).Cols("default_datasource_uid", "tags", "default_refresh", "require_approval", "updated", "updated_by").Update(&row)
+		return err
+	})
+}
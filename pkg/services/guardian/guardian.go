@@ -47,7 +47,7 @@ type dashboardGuardianImpl struct {
 	dashId           int64
 	orgId            int64
 	acl              []*dashboards.DashboardACLInfoDTO
-	teams            []*team.TeamDTO
+	teams            []int64
 	log              log.Logger
 	ctx              context.Context
 	store            db.DB
@@ -247,8 +247,8 @@ func (g *dashboardGuardianImpl) checkACL(permission dashboards.PermissionType, a
 
 	// evaluate team rules
 	for _, p := range acl {
-		for _, ug := range teams {
-			if ug.ID == p.TeamID && p.Permission >= permission {
+		for _, teamID := range teams {
+			if teamID == p.TeamID && p.Permission >= permission {
 				return true, nil
 			}
 		}
@@ -354,13 +354,15 @@ func (g *dashboardGuardianImpl) GetACLWithoutDuplicates() ([]*dashboards.Dashboa
 	return result, nil
 }
 
-func (g *dashboardGuardianImpl) getTeams() ([]*team.TeamDTO, error) {
+// getTeams returns the teams of the user, including the teams inherited through the team hierarchy. The teams are
+// resolved regardless of the teams the user can read, they only decide which team rules apply.
+func (g *dashboardGuardianImpl) getTeams() ([]int64, error) {
 	if g.teams != nil {
 		return g.teams, nil
 	}
 
-	query := team.GetTeamsByUserQuery{OrgID: g.orgId, UserID: g.user.UserID, SignedInUser: g.user}
-	queryResult, err := g.teamService.GetTeamsByUser(g.ctx, &query)
+	query := team.GetTeamIDsByUserQuery{OrgID: g.orgId, UserID: g.user.UserID}
+	queryResult, err := g.teamService.GetTeamIDsByUser(g.ctx, &query)
 
 	g.teams = queryResult
 	return queryResult, err
@@ -107,13 +107,15 @@ func permissionScenario(desc string, dashboardID int64, sc *scenarioContext,
 	sc.t.Run(desc, func(t *testing.T) {
 		store := dbtest.NewFakeDB()
 		teams := []*team.TeamDTO{}
+		teamIDs := []int64{}
 
 		for _, p := range permissions {
 			if p.TeamID > 0 {
 				teams = append(teams, &team.TeamDTO{ID: p.TeamID})
+				teamIDs = append(teamIDs, p.TeamID)
 			}
 		}
-		teamSvc := &teamtest.FakeService{ExpectedTeamsByUser: teams}
+		teamSvc := &teamtest.FakeService{ExpectedTeamIDsByUser: teamIDs}
 
 		dashSvc := dashboards.NewFakeDashboardService(t)
 		qResult := permissions
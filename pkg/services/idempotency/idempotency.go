@@ -0,0 +1,231 @@
+// Package idempotency replays the responses of the mutating requests retried with an Idempotency-Key header,
+// instead of executing them twice. It is enabled per route, only on the routes whose responses carry no
+// credentials: the dashboard saves, the data source creations and the alert rule writes.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/distlock"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotencyReplayed = "Idempotency-Replayed"
+
+	maxKeyLength = 255
+	// responses larger than this are not stored, a retry of the request is executed again
+	maxResponseSize = 1 << 20
+	// how long a request is reported in progress to its retries when the instance executing it does not complete it
+	pendingTTL = time.Minute
+	// the keys are reserved under one of lockShards distributed locks, picked by the hash of the key
+	lockShards = 16
+)
+
+// storedResponse is the response stored for an idempotency key. A response without status is the reservation
+// of a request in progress.
+type storedResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+type Service struct {
+	ttl   time.Duration
+	cache remotecache.CacheStorage
+	// lock serializes the reservations of the keys across the instances
+	lock func(ctx context.Context, name string) (release func(), err error)
+}
+
+func ProvideService(cfg *setting.Cfg, cache *remotecache.RemoteCache, lockService *distlock.Service) *Service {
+	return &Service{
+		ttl:   cfg.IdempotencyKeyTTL,
+		cache: cache,
+		lock: func(ctx context.Context, name string) (func(), error) {
+			lock, err := lockService.Lock(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return func() { _ = lock.Release(context.Background()) }, nil
+		},
+	}
+}
+
+// Middleware returns the route middleware storing the response of the mutating requests with an Idempotency-Key
+// header for idempotency_key_ttl, and replaying it when a request is sent again with the same key by the same
+// user. The key can't be reused for a request with a different method, path or body. Failed requests, with a
+// 5xx status, and responses marked no-store or setting cookies are not stored, a retry executes them again.
+//
+// It must only be added to the routes whose responses carry no credentials, as they are kept in the remote cache.
+func (s *Service) Middleware() web.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderIdempotencyKey)
+			if key == "" || s.ttl <= 0 || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			c := contexthandler.FromContext(r.Context())
+			if c == nil || !c.IsSignedIn {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userKey, err := c.SignedInUser.GetCacheKey()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxKeyLength {
+				c.JsonApiErr(http.StatusBadRequest, fmt.Sprintf("%s header must be at most %d characters", HeaderIdempotencyKey, maxKeyLength), nil)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				c.JsonApiErr(http.StatusBadRequest, "Failed to read request body", err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			cacheKey := cacheKey(userKey, key)
+			fingerprint := requestFingerprint(r.Method, r.URL.Path, body)
+
+			stored, err := s.reserve(ctx, cacheKey, fingerprint)
+			if err != nil {
+				c.JsonApiErr(http.StatusInternalServerError, fmt.Sprintf("Failed to reserve %s", HeaderIdempotencyKey), err)
+				return
+			}
+			if stored != nil {
+				switch {
+				case stored.Fingerprint != fingerprint:
+					c.JsonApiErr(http.StatusUnprocessableEntity, fmt.Sprintf("%s was already used for a different request", HeaderIdempotencyKey), nil)
+				case stored.Status == 0:
+					c.JsonApiErr(http.StatusConflict, fmt.Sprintf("A request with the same %s is in progress", HeaderIdempotencyKey), nil)
+				default:
+					if stored.ContentType != "" {
+						c.Resp.Header().Set("Content-Type", stored.ContentType)
+					}
+					c.Resp.Header().Set(HeaderIdempotencyReplayed, "true")
+					c.Resp.WriteHeader(stored.Status)
+					_, _ = c.Resp.Write(stored.Body)
+				}
+				return
+			}
+
+			rec := &recorder{ResponseWriter: c.Resp}
+			c.Resp = rec
+			next.ServeHTTP(rec, r)
+
+			status := rec.Status()
+			if status >= http.StatusInternalServerError || rec.overflow || !storable(rec.Header()) {
+				if err := s.cache.Delete(ctx, cacheKey); err != nil {
+					c.Logger.Warn("Failed to delete idempotency key", "error", err)
+				}
+				return
+			}
+
+			resp := storedResponse{
+				Fingerprint: fingerprint,
+				Status:      status,
+				ContentType: rec.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			}
+			if err := s.set(ctx, cacheKey, resp, s.ttl); err != nil {
+				c.Logger.Warn("Failed to store idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// reserve returns the response stored for a key, or reserves the key for the request when there is none. The
+// lookup and the reservation are done under a distributed lock, so that only one of the concurrent requests
+// with the same key is executed.
+func (s *Service) reserve(ctx context.Context, cacheKey, fingerprint string) (*storedResponse, error) {
+	release, err := s.lock(ctx, lockName(cacheKey))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// a failure to read the cache is handled like a missing response, the request is executed
+	if value, err := s.cache.Get(ctx, cacheKey); err == nil {
+		var stored storedResponse
+		if err := json.Unmarshal(value, &stored); err != nil {
+			return nil, err
+		}
+		return &stored, nil
+	}
+
+	return nil, s.set(ctx, cacheKey, storedResponse{Fingerprint: fingerprint}, pendingTTL)
+}
+
+func (s *Service) set(ctx context.Context, key string, resp storedResponse, ttl time.Duration) error {
+	value, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, key, value, ttl)
+}
+
+func isMutatingMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// storable returns false for the responses that must not be kept: the ones setting cookies, and the ones
+// their handler marked no-store.
+func storable(header http.Header) bool {
+	return header.Get("Set-Cookie") == "" && !strings.Contains(header.Get("Cache-Control"), "no-store")
+}
+
+func cacheKey(userKey, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("idempotency-%s-%s", userKey, hex.EncodeToString(sum[:]))
+}
+
+func lockName(cacheKey string) string {
+	sum := sha256.Sum256([]byte(cacheKey))
+	return fmt.Sprintf("idempotency-%d", int(sum[0])%lockShards)
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s %s\n", method, path)
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder keeps a copy of the response body written to the client
+type recorder struct {
+	web.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if !r.overflow {
+		if r.body.Len()+len(b) > maxResponseSize {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
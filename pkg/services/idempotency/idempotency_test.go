@@ -0,0 +1,128 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestMiddleware(t *testing.T) {
+	setup := func(t *testing.T, handler http.HandlerFunc) func(key, body string) *httptest.ResponseRecorder {
+		var mu sync.Mutex
+		s := &Service{
+			ttl:   time.Hour,
+			cache: remotecache.NewFakeStore(t),
+			lock: func(ctx context.Context, name string) (func(), error) {
+				mu.Lock()
+				return mu.Unlock, nil
+			},
+		}
+		h := s.Middleware()(handler)
+
+		return func(key, body string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/dashboards/db", strings.NewReader(body))
+			if key != "" {
+				req.Header.Set(HeaderIdempotencyKey, key)
+			}
+			c := &contextmodel.ReqContext{
+				Context:      &web.Context{Req: req, Resp: web.NewResponseWriter(req.Method, rec)},
+				SignedInUser: &user.SignedInUser{OrgID: 2, UserID: 12},
+				IsSignedIn:   true,
+				Logger:       log.NewNopLogger(),
+			}
+			req = req.WithContext(ctxkey.Set(req.Context(), c))
+			c.Req = req
+			h.ServeHTTP(c.Resp, req)
+			return rec
+		}
+	}
+
+	writeVersion := func(calls *int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*calls++
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]int{"version": *calls})
+		}
+	}
+
+	t.Run("Retries with the same key replay the response", func(t *testing.T) {
+		calls := 0
+		send := setup(t, writeVersion(&calls))
+
+		require.Equal(t, http.StatusOK, send("key", `{"title":"a"}`).Code)
+		resp := send("key", `{"title":"a"}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "true", resp.Header().Get(HeaderIdempotencyReplayed))
+		assert.JSONEq(t, `{"version":1}`, resp.Body.String())
+
+		require.Equal(t, http.StatusOK, send("other key", `{"title":"a"}`).Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Reusing a key for a different request fails", func(t *testing.T) {
+		calls := 0
+		send := setup(t, writeVersion(&calls))
+
+		require.Equal(t, http.StatusOK, send("key", `{"title":"a"}`).Code)
+		require.Equal(t, http.StatusUnprocessableEntity, send("key", `{"title":"b"}`).Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Requests without key are always executed", func(t *testing.T) {
+		calls := 0
+		send := setup(t, writeVersion(&calls))
+
+		require.Equal(t, http.StatusOK, send("", `{"title":"a"}`).Code)
+		require.Equal(t, http.StatusOK, send("", `{"title":"a"}`).Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Responses marked no-store are not replayed", func(t *testing.T) {
+		calls := 0
+		send := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusOK)
+		})
+
+		require.Equal(t, http.StatusOK, send("key", `{}`).Code)
+		resp := send("key", `{}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get(HeaderIdempotencyReplayed))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Concurrent requests with the same key are executed once", func(t *testing.T) {
+		started, done := make(chan struct{}), make(chan struct{})
+		send := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-done
+			w.WriteHeader(http.StatusOK)
+		})
+
+		first := make(chan int)
+		go func() { first <- send("key", `{}`).Code }()
+		<-started
+
+		assert.Equal(t, http.StatusConflict, send("key", `{}`).Code)
+		close(done)
+		assert.Equal(t, http.StatusOK, <-first)
+	})
+}
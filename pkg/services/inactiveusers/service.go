@@ -0,0 +1,269 @@
+// Package inactiveusers enforces the inactive user policy: users who haven't
+// signed in for a configurable number of days are disabled, or downgraded to
+// Viewer in the organization, after having been notified by email.
+//
+// The policy is configured in the [users] section and can be overridden per
+// organization through the organization settings.
+package inactiveusers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	ActionDisable   = "disable"
+	ActionDowngrade = "downgrade"
+
+	noticeTemplate = "inactive_user_notice"
+)
+
+type Service struct {
+	cfg              *setting.Cfg
+	store            store
+	orgService       org.Service
+	userService      user.Service
+	orgSettings      orgsettings.Service
+	authTokenService auth.UserTokenService
+	emailSender      notifications.EmailSender
+	lock             *serverlock.ServerLockService
+	log              log.Logger
+	now              func() time.Time
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	orgService org.Service,
+	userService user.Service,
+	orgSettings orgsettings.Service,
+	authTokenService auth.UserTokenService,
+	emailSender notifications.EmailSender,
+	lock *serverlock.ServerLockService,
+) *Service {
+	return &Service{
+		cfg:              cfg,
+		store:            &sqlStore{db: sql},
+		orgService:       orgService,
+		userService:      userService,
+		orgSettings:      orgSettings,
+		authTokenService: authTokenService,
+		emailSender:      emailSender,
+		lock:             lock,
+		log:              log.New("inactiveusers"),
+		now:              time.Now,
+	}
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	if s.cfg.InactiveUserPolicyInterval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(s.cfg.InactiveUserPolicyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := s.lock.LockAndExecute(ctx, "inactive users policy", s.cfg.InactiveUserPolicyInterval/2, func(ctx context.Context) {
+				s.enforce(ctx)
+			})
+			if err != nil {
+				s.log.Error("Failed to enforce inactive users policy", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// policy is the inactive user policy of an organization
+type policy struct {
+	afterDays        int64
+	action           string
+	notifyBeforeDays int64
+	exemptions       map[string]bool
+}
+
+func (p policy) exempted(u inactiveUser) bool {
+	return p.exemptions[strings.ToLower(u.Login)] || p.exemptions[strings.ToLower(u.Email)]
+}
+
+func (s *Service) getPolicy(ctx context.Context, orgID int64) policy {
+	p := policy{
+		afterDays:        s.orgSettings.GetInt64(ctx, orgID, orgsettings.InactiveUserDisableAfterDays),
+		action:           s.orgSettings.GetString(ctx, orgID, orgsettings.InactiveUserAction),
+		notifyBeforeDays: s.orgSettings.GetInt64(ctx, orgID, orgsettings.InactiveUserNotifyBeforeDays),
+		exemptions:       map[string]bool{},
+	}
+
+	// Users can't be notified without SMTP, the action is applied without notice then
+	if !s.cfg.Smtp.Enabled || p.notifyBeforeDays < 0 {
+		p.notifyBeforeDays = 0
+	}
+	if p.notifyBeforeDays > p.afterDays {
+		p.notifyBeforeDays = p.afterDays
+	}
+
+	for _, login := range strings.Split(s.orgSettings.GetString(ctx, orgID, orgsettings.InactiveUserExemptions), ",") {
+		if login = strings.TrimSpace(login); login != "" {
+			p.exemptions[strings.ToLower(login)] = true
+		}
+	}
+	return p
+}
+
+func (s *Service) enforce(ctx context.Context) {
+	orgs, err := s.orgService.Search(ctx, &org.SearchOrgsQuery{})
+	if err != nil {
+		s.log.Error("Failed to list organizations", "error", err)
+		return
+	}
+
+	for _, o := range orgs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		p := s.getPolicy(ctx, o.ID)
+		if p.afterDays <= 0 {
+			continue
+		}
+
+		if err := s.enforceForOrg(ctx, o, p); err != nil {
+			s.log.Error("Failed to enforce inactive users policy", "orgId", o.ID, "error", err)
+		}
+	}
+}
+
+func (s *Service) enforceForOrg(ctx context.Context, o *org.OrgDTO, p policy) error {
+	now := s.now()
+	viewersExcluded := p.action == ActionDowngrade
+
+	if p.notifyBeforeDays > 0 {
+		toNotify, err := s.store.listInactive(ctx, o.ID, now.AddDate(0, 0, -int(p.afterDays-p.notifyBeforeDays)), viewersExcluded)
+		if err != nil {
+			return err
+		}
+		for _, u := range toNotify {
+			if p.exempted(u) {
+				continue
+			}
+			if err := s.notify(ctx, o, p, u, now); err != nil {
+				s.log.Error("Failed to notify inactive user", "orgId", o.ID, "userId", u.ID, "error", err)
+			}
+		}
+	}
+
+	inactive, err := s.store.listInactive(ctx, o.ID, now.AddDate(0, 0, -int(p.afterDays)), viewersExcluded)
+	if err != nil {
+		return err
+	}
+	for _, u := range inactive {
+		if p.exempted(u) {
+			continue
+		}
+
+		if p.notifyBeforeDays > 0 {
+			// The action is only applied once the user had the full notice period to sign in
+			notice, err := s.store.getNotice(ctx, o.ID, u.ID)
+			if err != nil {
+				return err
+			}
+			if notice == nil || !notice.LastActive.Equal(u.lastActive()) || notice.NotifiedAt.After(now.AddDate(0, 0, -int(p.notifyBeforeDays))) {
+				continue
+			}
+		}
+
+		if err := s.apply(ctx, o.ID, p.action, u); err != nil {
+			s.log.Error("Failed to apply inactive user action", "orgId", o.ID, "userId", u.ID, "action", p.action, "error", err)
+			continue
+		}
+		if err := s.store.deleteNotice(ctx, o.ID, u.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) notify(ctx context.Context, o *org.OrgDTO, p policy, u inactiveUser, now time.Time) error {
+	notice, err := s.store.getNotice(ctx, o.ID, u.ID)
+	if err != nil {
+		return err
+	}
+	if notice != nil && notice.LastActive.Equal(u.lastActive()) {
+		return nil
+	}
+
+	deadline := u.lastActive().AddDate(0, 0, int(p.afterDays))
+	if earliest := now.AddDate(0, 0, int(p.notifyBeforeDays)); deadline.Before(earliest) {
+		deadline = earliest
+	}
+
+	action := "disabled"
+	if p.action == ActionDowngrade {
+		action = "downgraded to Viewer"
+	}
+
+	cmd := &notifications.SendEmailCommandSync{
+		SendEmailCommand: notifications.SendEmailCommand{
+			To:       []string{u.Email},
+			Template: noticeTemplate,
+			Subject:  "Your Grafana account will be " + action + " due to inactivity",
+			Data: map[string]interface{}{
+				"Name":     u.Name,
+				"OrgName":  o.Name,
+				"Action":   action,
+				"LastSeen": u.lastActive().Format("January 2, 2006"),
+				"Deadline": deadline.Format("January 2, 2006"),
+			},
+		},
+	}
+	if cmd.Data["Name"] == "" {
+		cmd.Data["Name"] = u.Login
+	}
+
+	if err := s.emailSender.SendEmailCommandHandlerSync(ctx, cmd); err != nil {
+		return err
+	}
+
+	return s.store.saveNotice(ctx, &Notice{OrgID: o.ID, UserID: u.ID, LastActive: u.lastActive(), NotifiedAt: now})
+}
+
+func (s *Service) apply(ctx context.Context, orgID int64, action string, u inactiveUser) error {
+	switch action {
+	case ActionDowngrade:
+		err := s.orgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{OrgID: orgID, UserID: u.ID, Role: org.RoleViewer})
+		if errors.Is(err, org.ErrLastOrgAdmin) {
+			s.log.Warn("Not downgrading the last admin of the organization", "orgId", orgID, "userId", u.ID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		if err := s.userService.Disable(ctx, &user.DisableUserCommand{UserID: u.ID, IsDisabled: true}); err != nil {
+			return err
+		}
+		if err := s.authTokenService.RevokeAllUserTokens(ctx, u.ID); err != nil {
+			return err
+		}
+	}
+
+	s.log.Info("Applied inactive user action", "orgId", orgID, "userId", u.ID, "login", u.Login, "action", action, "lastActive", u.lastActive())
+	return nil
+}
@@ -0,0 +1,146 @@
+package inactiveusers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+)
+
+type fakeOrgService struct {
+	orgtest.FakeOrgService
+	updated []org.UpdateOrgUserCommand
+}
+
+func (f *fakeOrgService) UpdateOrgUser(ctx context.Context, cmd *org.UpdateOrgUserCommand) error {
+	f.updated = append(f.updated, *cmd)
+	return nil
+}
+
+func TestIntegrationInactiveUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	const orgID int64 = 1
+
+	addUser := func(login string, lastSeenDaysAgo int, role org.RoleType, isAdmin bool) int64 {
+		usr := &user.User{
+			Login:      login,
+			Email:      login + "@example.com",
+			OrgID:      orgID,
+			IsAdmin:    isAdmin,
+			Created:    now.AddDate(0, 0, -100),
+			Updated:    now.AddDate(0, 0, -100),
+			LastSeenAt: now.AddDate(0, 0, -lastSeenDaysAgo),
+		}
+		err := testDB.WithDbSession(context.Background(), func(sess *db.Session) error {
+			if _, err := sess.Insert(usr); err != nil {
+				return err
+			}
+			_, err := sess.Insert(&org.OrgUser{OrgID: orgID, UserID: usr.ID, Role: role, Created: usr.Created, Updated: usr.Updated})
+			return err
+		})
+		require.NoError(t, err)
+		return usr.ID
+	}
+
+	active := addUser("active", 1, org.RoleEditor, false)
+	idle := addUser("idle", 25, org.RoleEditor, false)
+	gone := addUser("gone", 40, org.RoleEditor, false)
+	addUser("exempt", 40, org.RoleEditor, false)
+	addUser("admin", 40, org.RoleAdmin, true)
+	viewer := addUser("viewer", 40, org.RoleViewer, false)
+
+	cfg := testDB.Cfg
+	cfg.InactiveUserDisableAfterDays = 30
+	cfg.InactiveUserAction = ActionDisable
+	cfg.InactiveUserNotifyBeforeDays = 7
+	cfg.InactiveUserExemptions = "Exempt, someone@example.com"
+	cfg.Smtp.Enabled = true
+
+	orgService := &fakeOrgService{FakeOrgService: orgtest.FakeOrgService{ExpectedOrgs: []*org.OrgDTO{{ID: orgID, Name: "Main Org."}}}}
+	orgSettings := orgsettingstest.NewFakeService(cfg)
+
+	var disabled []int64
+	userService := usertest.NewUserServiceFake()
+	userService.DisableFn = func(ctx context.Context, cmd *user.DisableUserCommand) error {
+		disabled = append(disabled, cmd.UserID)
+		return nil
+	}
+
+	var notified []string
+	emailSender := notifications.MockNotificationService()
+	emailSender.EmailHandlerSync = func(ctx context.Context, cmd *notifications.SendEmailCommandSync) error {
+		notified = append(notified, cmd.To...)
+		return nil
+	}
+
+	s := &Service{
+		cfg:              cfg,
+		store:            &sqlStore{db: testDB},
+		orgService:       orgService,
+		userService:      userService,
+		orgSettings:      orgSettings,
+		authTokenService: authtest.NewFakeUserAuthTokenService(),
+		emailSender:      emailSender,
+		log:              log.NewNopLogger(),
+		now:              func() time.Time { return now },
+	}
+
+	t.Run("should notify users before applying the action", func(t *testing.T) {
+		s.enforce(context.Background())
+		assert.ElementsMatch(t, []string{"idle@example.com", "gone@example.com", "viewer@example.com"}, notified)
+		assert.Empty(t, disabled)
+
+		notified = nil
+		s.enforce(context.Background())
+		assert.Empty(t, notified, "users should only be notified once")
+		assert.Empty(t, disabled)
+	})
+
+	t.Run("should disable users after the notice period", func(t *testing.T) {
+		now = now.AddDate(0, 0, 8)
+		s.enforce(context.Background())
+		assert.ElementsMatch(t, []int64{idle, gone, viewer}, disabled)
+		assert.NotContains(t, disabled, active)
+		assert.Empty(t, notified)
+
+		notice, err := s.store.getNotice(context.Background(), orgID, gone)
+		require.NoError(t, err)
+		assert.Nil(t, notice)
+	})
+
+	t.Run("should downgrade users without notice when notifications are off", func(t *testing.T) {
+		disabled = nil
+		orgSettings.Overrides[orgsettings.InactiveUserAction] = ActionDowngrade
+		orgSettings.Overrides[orgsettings.InactiveUserNotifyBeforeDays] = "0"
+
+		s.enforce(context.Background())
+		assert.Empty(t, disabled)
+		assert.Empty(t, notified)
+
+		downgraded := make([]int64, 0, len(orgService.updated))
+		for _, cmd := range orgService.updated {
+			assert.Equal(t, org.RoleViewer, cmd.Role)
+			downgraded = append(downgraded, cmd.UserID)
+		}
+		// the users are still enabled in the test database, viewers are left alone
+		assert.ElementsMatch(t, []int64{idle, gone}, downgraded)
+	})
+}
@@ -0,0 +1,107 @@
+package inactiveusers
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// Notice records that a user was warned about the inactive user policy of an
+// organization. LastActive is the last activity of the user when the notice
+// was sent, so that users becoming inactive again get a new notice.
+type Notice struct {
+	ID         int64     `xorm:"pk autoincr 'id'"`
+	OrgID      int64     `xorm:"org_id"`
+	UserID     int64     `xorm:"user_id"`
+	LastActive time.Time `xorm:"last_active"`
+	NotifiedAt time.Time `xorm:"notified_at"`
+}
+
+func (n Notice) TableName() string {
+	return "user_inactivity_notice"
+}
+
+// inactiveUser is an organization member who hasn't been active since LastActive
+type inactiveUser struct {
+	ID         int64        `xorm:"id"`
+	Login      string       `xorm:"login"`
+	Email      string       `xorm:"email"`
+	Name       string       `xorm:"name"`
+	Role       org.RoleType `xorm:"role"`
+	Created    time.Time    `xorm:"created"`
+	LastSeenAt time.Time    `xorm:"last_seen_at"`
+}
+
+// lastActive returns the most recent of the creation and the last seen
+// dates: users who never signed in are considered active since their creation
+func (u inactiveUser) lastActive() time.Time {
+	if u.Created.After(u.LastSeenAt) {
+		return u.Created
+	}
+	return u.LastSeenAt
+}
+
+type store interface {
+	// listInactive returns the enabled members of the organization who haven't been active since the given time.
+	// Server admins and service accounts are never returned, nor are viewers when viewersExcluded is set.
+	listInactive(ctx context.Context, orgID int64, since time.Time, viewersExcluded bool) ([]inactiveUser, error)
+	getNotice(ctx context.Context, orgID, userID int64) (*Notice, error)
+	saveNotice(ctx context.Context, notice *Notice) error
+	deleteNotice(ctx context.Context, orgID, userID int64) error
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (ss *sqlStore) listInactive(ctx context.Context, orgID int64, since time.Time, viewersExcluded bool) ([]inactiveUser, error) {
+	users := make([]inactiveUser, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		userTable := ss.db.GetDialect().Quote("user")
+		rawSQL := `SELECT u.id, u.login, u.email, u.name, u.created, u.last_seen_at, ou.role
+			FROM org_user AS ou
+			INNER JOIN ` + userTable + ` AS u ON u.id = ou.user_id
+			WHERE ou.org_id = ? AND u.is_disabled = ? AND u.is_admin = ? AND u.is_service_account = ?
+			AND u.last_seen_at < ? AND u.created < ?`
+		params := []interface{}{orgID, false, false, false, since, since}
+		if viewersExcluded {
+			rawSQL += ` AND ou.role <> ?`
+			params = append(params, org.RoleViewer)
+		}
+		return sess.SQL(rawSQL+` ORDER BY u.id`, params...).Find(&users)
+	})
+	return users, err
+}
+
+func (ss *sqlStore) getNotice(ctx context.Context, orgID, userID int64) (*Notice, error) {
+	var notice Notice
+	var found bool
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Where("org_id = ? AND user_id = ?", orgID, userID).Get(&notice)
+		return err
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &notice, nil
+}
+
+func (ss *sqlStore) saveNotice(ctx context.Context, notice *Notice) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM user_inactivity_notice WHERE org_id = ? AND user_id = ?", notice.OrgID, notice.UserID); err != nil {
+			return err
+		}
+		_, err := sess.Insert(notice)
+		return err
+	})
+}
+
+func (ss *sqlStore) deleteNotice(ctx context.Context, orgID, userID int64) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM user_inactivity_notice WHERE org_id = ? AND user_id = ?", orgID, userID)
+		return err
+	})
+}
@@ -0,0 +1,121 @@
+package inbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+type Kind string
+
+const (
+	KindAlertDigest     Kind = "alert-digest"
+	KindShareInvitation Kind = "share-invitation"
+	KindReportCompleted Kind = "report-completed"
+	KindAnnouncement    Kind = "announcement"
+	KindChangeReview    Kind = "change-review"
+)
+
+// IsValid returns true for the known kinds of notifications.
+func (k Kind) IsValid() bool {
+	switch k {
+	case KindAlertDigest, KindShareInvitation, KindReportCompleted, KindAnnouncement, KindChangeReview:
+		return true
+	}
+	return false
+}
+
+const (
+	// LiveNamespace is the namespace of the Live channels new notifications are pushed to:
+	// grafana/notifications/user/<userId> for the notifications of a user, and
+	// grafana/notifications/announcements for the announcements to the users of an org.
+	LiveNamespace = "notifications"
+
+	MaxTitleLength = 190
+	MaxBodyLength  = 4000
+	DefaultPerPage = 50
+	MaxPerPage     = 200
+)
+
+var (
+	ErrNotificationNotFound = errutil.NewBase(errutil.StatusNotFound, "inbox.notFound", errutil.WithPublicMessage("Notification not found"))
+	ErrInvalidNotification  = errutil.NewBase(errutil.StatusBadRequest, "inbox.invalid")
+)
+
+// Service is the notification center of the users: it keeps the notifications sent to them, with their read
+// state, and pushes the new ones to their Live channel.
+type Service interface {
+	// Notify sends a notification to each of the users.
+	Notify(ctx context.Context, cmd *NotifyCommand) error
+	// Announce sends an announcement to all the users of an org, or of all the orgs when the org is 0, and
+	// returns the number of users notified.
+	Announce(ctx context.Context, cmd *AnnounceCommand) (int64, error)
+
+	// List returns the notifications of the user in an org, latest first, with the notifications of all the orgs.
+	List(ctx context.Context, query *ListQuery) (*ListResult, error)
+	// SetRead marks a notification of the user as read or unread.
+	SetRead(ctx context.Context, userID, id int64, read bool) error
+	// MarkAllRead marks the notifications of the user in an org as read, and returns the number of notifications marked.
+	MarkAllRead(ctx context.Context, userID, orgID int64) (int64, error)
+	Delete(ctx context.Context, userID, id int64) error
+
+	// DeleteCreatedBefore deletes the notifications created before the time, read or not.
+	DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error)
+	// DeleteByUser deletes the notifications of a deleted user.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+type Notification struct {
+	ID int64 `xorm:"pk autoincr 'id'" json:"id"`
+	// OrgID is the org the notification is listed in, all of them when 0
+	OrgID   int64     `xorm:"org_id" json:"orgId"`
+	UserID  int64     `xorm:"user_id" json:"userId"`
+	Kind    Kind      `xorm:"kind" json:"kind"`
+	Title   string    `xorm:"title" json:"title"`
+	Body    string    `xorm:"body" json:"body"`
+	URL     string    `xorm:"url" json:"url"`
+	Read    bool      `xorm:"is_read" json:"read"`
+	Created time.Time `xorm:"created" json:"created"`
+}
+
+func (n Notification) TableName() string {
+	return "user_notification"
+}
+
+type NotifyCommand struct {
+	// OrgID is the org the notification is listed in, all the orgs of the users when 0
+	OrgID   int64
+	UserIDs []int64
+	Kind    Kind
+	Title   string
+	Body    string
+	URL     string
+}
+
+type AnnounceCommand struct {
+	// OrgID is the org whose users are notified, all the users when 0
+	OrgID int64  `json:"orgId"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+}
+
+type ListQuery struct {
+	UserID     int64
+	OrgID      int64
+	UnreadOnly bool
+	Kind       Kind
+	Page       int
+	PerPage    int
+}
+
+type ListResult struct {
+	Notifications []*Notification `json:"notifications"`
+	// TotalCount is the number of notifications matching the query
+	TotalCount int64 `json:"totalCount"`
+	// UnreadCount is the number of unread notifications of the user in the org, regardless of the query
+	UnreadCount int64 `json:"unreadCount"`
+	Page        int   `json:"page"`
+	PerPage     int   `json:"perPage"`
+}
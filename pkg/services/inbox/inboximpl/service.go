@@ -0,0 +1,192 @@
+package inboximpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+// publisher publishes messages to the Live channels of an org.
+type publisher interface {
+	Publish(orgID int64, channel string, data []byte) error
+}
+
+type Service struct {
+	store      store
+	live       publisher
+	orgService org.Service
+	log        log.Logger
+	now        func() time.Time
+}
+
+var _ inbox.Service = (*Service)(nil)
+
+func ProvideService(sql db.DB, grafanaLive *live.GrafanaLive, orgService org.Service) *Service {
+	return &Service{
+		store:      &sqlStore{db: sql},
+		live:       grafanaLive,
+		orgService: orgService,
+		log:        log.New("inbox"),
+		now:        time.Now,
+	}
+}
+
+// UserChannel is the Live channel the new notifications of a user are pushed to.
+func UserChannel(userID int64) string {
+	return fmt.Sprintf("grafana/%s/user/%d", inbox.LiveNamespace, userID)
+}
+
+// AnnouncementsChannel is the Live channel the announcements to the users of an org are pushed to.
+func AnnouncementsChannel() string {
+	return fmt.Sprintf("grafana/%s/announcements", inbox.LiveNamespace)
+}
+
+func (s *Service) Notify(ctx context.Context, cmd *inbox.NotifyCommand) error {
+	if len(cmd.UserIDs) == 0 {
+		return nil
+	}
+	n, err := s.newNotification(cmd.OrgID, cmd.Kind, cmd.Title, cmd.Body, cmd.URL)
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]*inbox.Notification, 0, len(cmd.UserIDs))
+	for _, userID := range cmd.UserIDs {
+		userNotification := *n
+		userNotification.UserID = userID
+		notifications = append(notifications, &userNotification)
+	}
+	if err := s.store.Insert(ctx, notifications); err != nil {
+		return err
+	}
+
+	for _, n := range notifications {
+		s.push(ctx, n)
+	}
+	return nil
+}
+
+func (s *Service) Announce(ctx context.Context, cmd *inbox.AnnounceCommand) (int64, error) {
+	n, err := s.newNotification(cmd.OrgID, inbox.KindAnnouncement, cmd.Title, cmd.Body, cmd.URL)
+	if err != nil {
+		return 0, err
+	}
+
+	notified, err := s.store.InsertForAllUsers(ctx, n)
+	if err != nil {
+		return 0, err
+	}
+	s.log.Info("Announcement sent", "orgId", cmd.OrgID, "users", notified)
+
+	orgIDs := []int64{cmd.OrgID}
+	if cmd.OrgID == 0 {
+		orgs, err := s.orgService.Search(ctx, &org.SearchOrgsQuery{})
+		if err != nil {
+			// the announcement is listed by the users anyway, it just isn't pushed
+			s.log.Warn("Failed to list the orgs to push an announcement to", "error", err)
+			return notified, nil
+		}
+		orgIDs = orgIDs[:0]
+		for _, o := range orgs {
+			orgIDs = append(orgIDs, o.ID)
+		}
+	}
+	s.publish(orgIDs, AnnouncementsChannel(), n)
+	return notified, nil
+}
+
+func (s *Service) newNotification(orgID int64, kind inbox.Kind, title, body, url string) (*inbox.Notification, error) {
+	title = strings.TrimSpace(title)
+	switch {
+	case !kind.IsValid():
+		return nil, inbox.ErrInvalidNotification.Errorf("unknown kind of notification %q", kind)
+	case title == "":
+		return nil, inbox.ErrInvalidNotification.Errorf("title is required")
+	case len(title) > inbox.MaxTitleLength:
+		return nil, inbox.ErrInvalidNotification.Errorf("title is longer than %d characters", inbox.MaxTitleLength)
+	case len(body) > inbox.MaxBodyLength:
+		return nil, inbox.ErrInvalidNotification.Errorf("body is longer than %d characters", inbox.MaxBodyLength)
+	}
+
+	return &inbox.Notification{
+		OrgID:   orgID,
+		Kind:    kind,
+		Title:   title,
+		Body:    strings.TrimSpace(body),
+		URL:     strings.TrimSpace(url),
+		Created: s.now(),
+	}, nil
+}
+
+// push publishes a new notification to the channel of its user, in each of the orgs of the user when it isn't
+// for an org.
+func (s *Service) push(ctx context.Context, n *inbox.Notification) {
+	orgIDs := []int64{n.OrgID}
+	if n.OrgID == 0 {
+		orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: n.UserID})
+		if err != nil {
+			s.log.Warn("Failed to list the orgs to push a notification to", "userId", n.UserID, "error", err)
+			return
+		}
+		orgIDs = orgIDs[:0]
+		for _, o := range orgs {
+			orgIDs = append(orgIDs, o.OrgID)
+		}
+	}
+	s.publish(orgIDs, UserChannel(n.UserID), n)
+}
+
+// publish is best effort: the notifications are stored, so the clients which miss a message get them by listing.
+func (s *Service) publish(orgIDs []int64, channel string, n *inbox.Notification) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		s.log.Warn("Failed to marshal notification", "channel", channel, "error", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		if err := s.live.Publish(orgID, channel, data); err != nil {
+			s.log.Debug("Failed to push notification", "orgId", orgID, "channel", channel, "error", err)
+		}
+	}
+}
+
+func (s *Service) List(ctx context.Context, query *inbox.ListQuery) (*inbox.ListResult, error) {
+	if query.PerPage <= 0 {
+		query.PerPage = inbox.DefaultPerPage
+	}
+	if query.PerPage > inbox.MaxPerPage {
+		query.PerPage = inbox.MaxPerPage
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	return s.store.List(ctx, query)
+}
+
+func (s *Service) SetRead(ctx context.Context, userID, id int64, read bool) error {
+	return s.store.SetRead(ctx, userID, id, read)
+}
+
+func (s *Service) MarkAllRead(ctx context.Context, userID, orgID int64) (int64, error) {
+	return s.store.MarkAllRead(ctx, userID, orgID)
+}
+
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	return s.store.Delete(ctx, userID, id)
+}
+
+func (s *Service) DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return s.store.DeleteCreatedBefore(ctx, before)
+}
+
+func (s *Service) DeleteByUser(ctx context.Context, userID int64) error {
+	return s.store.DeleteByUser(ctx, userID)
+}
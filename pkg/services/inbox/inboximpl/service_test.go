@@ -0,0 +1,181 @@
+package inboximpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/inbox"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationInbox(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sql := db.InitTestDB(t)
+	now := time.Now().Truncate(time.Second)
+	live := &fakePublisher{}
+	orgs := &orgtest.FakeOrgService{
+		ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1}, {OrgID: 2}},
+		ExpectedOrgs:       []*org.OrgDTO{{ID: 1}, {ID: 2}},
+	}
+	s := &Service{
+		store:      &sqlStore{db: sql},
+		live:       live,
+		orgService: orgs,
+		log:        log.NewNopLogger(),
+		now:        func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	createUser := func(t *testing.T, login string, serviceAccount bool, orgIDs ...int64) int64 {
+		t.Helper()
+		usr := &user.User{Login: login, Email: login + "@example.org", IsServiceAccount: serviceAccount, Created: now, Updated: now}
+		err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+			if _, err := sess.Insert(usr); err != nil {
+				return err
+			}
+			for _, orgID := range orgIDs {
+				if _, err := sess.Insert(&org.OrgUser{OrgID: orgID, UserID: usr.ID, Role: org.RoleViewer, Created: now, Updated: now}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return usr.ID
+	}
+	list := func(t *testing.T, query inbox.ListQuery) *inbox.ListResult {
+		t.Helper()
+		result, err := s.List(ctx, &query)
+		require.NoError(t, err)
+		return result
+	}
+
+	alice := createUser(t, "alice", false, 1, 2)
+	bob := createUser(t, "bob", false, 2)
+	createUser(t, "robot", true, 1, 2)
+
+	t.Run("should store the notifications of the users and push them", func(t *testing.T) {
+		live.reset()
+		err := s.Notify(ctx, &inbox.NotifyCommand{OrgID: 1, UserIDs: []int64{alice}, Kind: inbox.KindShareInvitation, Title: " Invited ", URL: "/d/abc"})
+		require.NoError(t, err)
+		err = s.Notify(ctx, &inbox.NotifyCommand{UserIDs: []int64{alice, bob}, Kind: inbox.KindReportCompleted, Title: "Report ready"})
+		require.NoError(t, err)
+
+		result := list(t, inbox.ListQuery{UserID: alice, OrgID: 1})
+		require.Len(t, result.Notifications, 2)
+		require.Equal(t, int64(2), result.UnreadCount)
+		require.Equal(t, "Invited", result.Notifications[1].Title)
+
+		// the notification of org 1 isn't listed in org 2
+		result = list(t, inbox.ListQuery{UserID: alice, OrgID: 2})
+		require.Len(t, result.Notifications, 1)
+		require.Equal(t, inbox.KindReportCompleted, result.Notifications[0].Kind)
+
+		require.Equal(t, []string{
+			fmt.Sprintf("1/grafana/notifications/user/%d", alice),
+			// the notifications which aren't for an org are pushed in every org of the user
+			fmt.Sprintf("1/grafana/notifications/user/%d", alice),
+			fmt.Sprintf("2/grafana/notifications/user/%d", alice),
+			fmt.Sprintf("1/grafana/notifications/user/%d", bob),
+			fmt.Sprintf("2/grafana/notifications/user/%d", bob),
+		}, live.channels)
+		var pushed inbox.Notification
+		require.NoError(t, json.Unmarshal(live.data[0], &pushed))
+		require.Equal(t, "Invited", pushed.Title)
+		require.NotZero(t, pushed.ID)
+	})
+
+	t.Run("should refuse invalid notifications", func(t *testing.T) {
+		err := s.Notify(ctx, &inbox.NotifyCommand{UserIDs: []int64{alice}, Kind: "unknown", Title: "title"})
+		require.ErrorIs(t, err, inbox.ErrInvalidNotification)
+		err = s.Notify(ctx, &inbox.NotifyCommand{UserIDs: []int64{alice}, Kind: inbox.KindAlertDigest, Title: " "})
+		require.ErrorIs(t, err, inbox.ErrInvalidNotification)
+	})
+
+	t.Run("should announce to the active users of the org", func(t *testing.T) {
+		live.reset()
+		notified, err := s.Announce(ctx, &inbox.AnnounceCommand{OrgID: 2, Title: "Maintenance tonight"})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), notified)
+		require.Equal(t, []string{"2/grafana/notifications/announcements"}, live.channels)
+
+		result := list(t, inbox.ListQuery{UserID: bob, OrgID: 2, Kind: inbox.KindAnnouncement})
+		require.Len(t, result.Notifications, 1)
+
+		notified, err = s.Announce(ctx, &inbox.AnnounceCommand{Title: "Upgrade tomorrow"})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), notified)
+	})
+
+	t.Run("should mark the notifications as read", func(t *testing.T) {
+		result := list(t, inbox.ListQuery{UserID: bob, OrgID: 2, UnreadOnly: true})
+		require.Equal(t, int64(3), result.UnreadCount)
+		require.Len(t, result.Notifications, 3)
+
+		id := result.Notifications[0].ID
+		require.NoError(t, s.SetRead(ctx, bob, id, true))
+		require.NoError(t, s.SetRead(ctx, bob, id, true))
+		require.ErrorIs(t, s.SetRead(ctx, alice, id, true), inbox.ErrNotificationNotFound)
+		require.Equal(t, int64(2), list(t, inbox.ListQuery{UserID: bob, OrgID: 2}).UnreadCount)
+
+		marked, err := s.MarkAllRead(ctx, bob, 2)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), marked)
+		result = list(t, inbox.ListQuery{UserID: bob, OrgID: 2, UnreadOnly: true})
+		require.Zero(t, result.UnreadCount)
+		require.Empty(t, result.Notifications)
+
+		require.NoError(t, s.SetRead(ctx, bob, id, false))
+		require.Equal(t, int64(1), list(t, inbox.ListQuery{UserID: bob, OrgID: 2}).UnreadCount)
+	})
+
+	t.Run("should page the notifications", func(t *testing.T) {
+		result := list(t, inbox.ListQuery{UserID: alice, OrgID: 1, PerPage: 2, Page: 2})
+		require.Equal(t, int64(3), result.TotalCount)
+		require.Len(t, result.Notifications, 1)
+		require.Equal(t, "Invited", result.Notifications[0].Title)
+	})
+
+	t.Run("should delete the notifications", func(t *testing.T) {
+		result := list(t, inbox.ListQuery{UserID: alice, OrgID: 1})
+		require.ErrorIs(t, s.Delete(ctx, bob, result.Notifications[0].ID), inbox.ErrNotificationNotFound)
+		require.NoError(t, s.Delete(ctx, alice, result.Notifications[0].ID))
+		require.Equal(t, int64(2), list(t, inbox.ListQuery{UserID: alice, OrgID: 1}).TotalCount)
+
+		require.NoError(t, s.DeleteByUser(ctx, alice))
+		require.Zero(t, list(t, inbox.ListQuery{UserID: alice, OrgID: 1}).TotalCount)
+
+		deleted, err := s.DeleteCreatedBefore(ctx, now.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int64(3), deleted)
+		require.Zero(t, list(t, inbox.ListQuery{UserID: bob, OrgID: 2}).TotalCount)
+	})
+}
+
+type fakePublisher struct {
+	channels []string
+	data     [][]byte
+}
+
+func (p *fakePublisher) Publish(orgID int64, channel string, data []byte) error {
+	p.channels = append(p.channels, fmt.Sprintf("%d/%s", orgID, channel))
+	p.data = append(p.data, data)
+	return nil
+}
+
+func (p *fakePublisher) reset() {
+	p.channels = nil
+	p.data = nil
+}
@@ -0,0 +1,170 @@
+package inboximpl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/inbox"
+)
+
+type store interface {
+	Insert(ctx context.Context, notifications []*inbox.Notification) error
+	// InsertForAllUsers inserts a copy of the notification for each of the active users of its org, or of all
+	// the active users when its org is 0, and returns the number of copies.
+	InsertForAllUsers(ctx context.Context, n *inbox.Notification) (int64, error)
+	List(ctx context.Context, query *inbox.ListQuery) (*inbox.ListResult, error)
+	SetRead(ctx context.Context, userID, id int64, read bool) error
+	MarkAllRead(ctx context.Context, userID, orgID int64) (int64, error)
+	Delete(ctx context.Context, userID, id int64) error
+	DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error)
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+// insertBatchSize is the number of notifications inserted by a statement when notifying all the users.
+const insertBatchSize = 500
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (s *sqlStore) Insert(ctx context.Context, notifications []*inbox.Notification) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, n := range notifications {
+			if _, err := sess.Insert(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) InsertForAllUsers(ctx context.Context, n *inbox.Notification) (int64, error) {
+	dialect := s.db.GetDialect()
+	userTable := dialect.Quote("user")
+	activeUsers := fmt.Sprintf("%s.is_disabled = %s AND (%s.is_service_account = %s OR %s.is_service_account IS NULL)",
+		userTable, dialect.BooleanStr(false), userTable, dialect.BooleanStr(false), userTable)
+
+	var inserted int64
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		userIDs := make([]int64, 0)
+		q := sess.Table("user").Select(userTable + ".id").Where(activeUsers)
+		if n.OrgID != 0 {
+			q = q.Join("INNER", "org_user", "org_user.user_id = "+userTable+".id").And("org_user.org_id = ?", n.OrgID)
+		}
+		if err := q.Find(&userIDs); err != nil {
+			return err
+		}
+
+		for start := 0; start < len(userIDs); start += insertBatchSize {
+			end := start + insertBatchSize
+			if end > len(userIDs) {
+				end = len(userIDs)
+			}
+			batch := make([]*inbox.Notification, 0, end-start)
+			for _, userID := range userIDs[start:end] {
+				copied := *n
+				copied.UserID = userID
+				batch = append(batch, &copied)
+			}
+			affected, err := sess.InsertMulti(batch)
+			if err != nil {
+				return err
+			}
+			inserted += affected
+		}
+		return nil
+	})
+	return inserted, err
+}
+
+func (s *sqlStore) List(ctx context.Context, query *inbox.ListQuery) (*inbox.ListResult, error) {
+	result := &inbox.ListResult{
+		Notifications: make([]*inbox.Notification, 0),
+		Page:          query.Page,
+		PerPage:       query.PerPage,
+	}
+	where := "user_id = ? AND org_id IN (0, ?)"
+	args := []interface{}{query.UserID, query.OrgID}
+	if query.UnreadOnly {
+		where += " AND is_read = ?"
+		args = append(args, false)
+	}
+	if query.Kind != "" {
+		where += " AND kind = ?"
+		args = append(args, query.Kind)
+	}
+
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		if result.TotalCount, err = sess.Where(where, args...).Count(&inbox.Notification{}); err != nil {
+			return err
+		}
+		if err := sess.Where(where, args...).Desc("created", "id").Limit(query.PerPage, (query.Page-1)*query.PerPage).Find(&result.Notifications); err != nil {
+			return err
+		}
+
+		result.UnreadCount, err = sess.Where("user_id = ? AND org_id IN (0, ?) AND is_read = ?", query.UserID, query.OrgID, false).Count(&inbox.Notification{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *sqlStore) SetRead(ctx context.Context, userID, id int64, read bool) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		// MySQL doesn't count the rows left unchanged as affected, so the notification is looked up first
+		has, err := sess.Where("id = ? AND user_id = ?", id, userID).Exist(&inbox.Notification{})
+		if err != nil {
+			return err
+		}
+		if !has {
+			return inbox.ErrNotificationNotFound.Errorf("notification %d of user %d not found", id, userID)
+		}
+		_, err = sess.ID(id).Cols("is_read").Update(&inbox.Notification{Read: read})
+		return err
+	})
+}
+
+func (s *sqlStore) MarkAllRead(ctx context.Context, userID, orgID int64) (int64, error) {
+	var marked int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		marked, err = sess.Where("user_id = ? AND org_id IN (0, ?) AND is_read = ?", userID, orgID, false).Cols("is_read").Update(&inbox.Notification{Read: true})
+		return err
+	})
+	return marked, err
+}
+
+func (s *sqlStore) Delete(ctx context.Context, userID, id int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := sess.Where("id = ? AND user_id = ?", id, userID).Delete(&inbox.Notification{})
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return inbox.ErrNotificationNotFound.Errorf("notification %d of user %d not found", id, userID)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("created < ?", before).Delete(&inbox.Notification{})
+		return err
+	})
+	return deleted, err
+}
+
+func (s *sqlStore) DeleteByUser(ctx context.Context, userID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("user_id = ?", userID).Delete(&inbox.Notification{})
+		return err
+	})
+}
@@ -0,0 +1,57 @@
+package inboxtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/inbox"
+)
+
+type FakeService struct {
+	ExpectedResult *inbox.ListResult
+	ExpectedCount  int64
+	ExpectedError  error
+
+	// Notified are the commands passed to Notify
+	Notified []*inbox.NotifyCommand
+	// DeletedUsers are the users whose notifications were deleted by DeleteByUser
+	DeletedUsers []int64
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (s *FakeService) Notify(ctx context.Context, cmd *inbox.NotifyCommand) error {
+	s.Notified = append(s.Notified, cmd)
+	return s.ExpectedError
+}
+
+func (s *FakeService) Announce(ctx context.Context, cmd *inbox.AnnounceCommand) (int64, error) {
+	return s.ExpectedCount, s.ExpectedError
+}
+
+func (s *FakeService) List(ctx context.Context, query *inbox.ListQuery) (*inbox.ListResult, error) {
+	return s.ExpectedResult, s.ExpectedError
+}
+
+func (s *FakeService) SetRead(ctx context.Context, userID, id int64, read bool) error {
+	return s.ExpectedError
+}
+
+func (s *FakeService) MarkAllRead(ctx context.Context, userID, orgID int64) (int64, error) {
+	return s.ExpectedCount, s.ExpectedError
+}
+
+func (s *FakeService) Delete(ctx context.Context, userID, id int64) error {
+	return s.ExpectedError
+}
+
+func (s *FakeService) DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error) {
+	return s.ExpectedCount, s.ExpectedError
+}
+
+func (s *FakeService) DeleteByUser(ctx context.Context, userID int64) error {
+	s.DeletedUsers = append(s.DeletedUsers, userID)
+	return s.ExpectedError
+}
@@ -0,0 +1,82 @@
+// Package jobqueue runs background jobs from a queue persisted in the database, so that the jobs survive
+// restarts and are shared between the instances of a high availability setup. The jobs are picked by
+// priority, retried with an exponential backoff when they fail, and kept as dead jobs once they
+// failed too many times, for the server admins to inspect and retry them.
+package jobqueue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrJobNotFound    = errutil.NewBase(errutil.StatusNotFound, "jobqueue.notFound", errutil.WithPublicMessage("Job not found"))
+	ErrUnknownJobType = errutil.NewBase(errutil.StatusBadRequest, "jobqueue.unknownType", errutil.WithPublicMessage("Unknown job type"))
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	// StatusDead is the status of the jobs which failed more than their maximum number of attempts
+	StatusDead Status = "dead"
+)
+
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Priority    int             `json:"priority"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	LastError   string          `json:"lastError,omitempty"`
+	RunAt       time.Time       `json:"runAt"`
+	Created     time.Time       `json:"created"`
+	Updated     time.Time       `json:"updated"`
+}
+
+// Handler runs a job, the job is retried later when the handler returns an error.
+type Handler func(ctx context.Context, job *Job) error
+
+type HandlerOptions struct {
+	// MaxAttempts is the number of times a job is run before it is dead, the configured default when 0
+	MaxAttempts int
+	// Interval makes the job recurring: a job of the type is queued on start up, and again Interval
+	// after each run, wherever it ends up.
+	Interval time.Duration
+}
+
+type EnqueueCommand struct {
+	Type string
+	// Payload is marshalled to JSON, and available to the handler in Job.Payload
+	Payload any
+	// Priority orders the jobs ready to run, the highest first
+	Priority int
+	// Key deduplicates the jobs: a job isn't queued while another job with the same key is pending or running
+	Key string
+	// RunAt delays the job, it runs as soon as possible when zero
+	RunAt time.Time
+}
+
+type ListJobsQuery struct {
+	Status Status
+	// Types only lists the jobs of these types when set
+	Types []string
+	Limit int
+	Page  int
+}
+
+type Service interface {
+	// RegisterHandler sets the handler of a type of jobs, it must be called before the service runs.
+	RegisterHandler(jobType string, handler Handler, opts HandlerOptions)
+	// Enqueue queues a job, or returns the pending job with the same key.
+	Enqueue(ctx context.Context, cmd EnqueueCommand) (*Job, error)
+	ListJobs(ctx context.Context, query ListJobsQuery) ([]*Job, error)
+	// RetryJob queues a dead job again, with a new set of attempts.
+	RetryJob(ctx context.Context, id int64) (*Job, error)
+}
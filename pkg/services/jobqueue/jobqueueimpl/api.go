@@ -0,0 +1,46 @@
+package jobqueueimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Group("/api/admin/jobs", func(jobs routing.RouteRegister) {
+		jobs.Get("/dead-letter", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleListDeadJobs))
+		jobs.Post("/:jobId/retry", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleRetryJob))
+	})
+}
+
+// handleListDeadJobs lists the jobs which failed too many times, the most recent failures first.
+func (s *Service) handleListDeadJobs(c *contextmodel.ReqContext) response.Response {
+	jobs, err := s.ListJobs(c.Req.Context(), jobqueue.ListJobsQuery{
+		Status: jobqueue.StatusDead,
+		Limit:  c.QueryInt("limit"),
+		Page:   c.QueryInt("page"),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to list dead jobs", err)
+	}
+	return response.JSON(http.StatusOK, jobs)
+}
+
+func (s *Service) handleRetryJob(c *contextmodel.ReqContext) response.Response {
+	id, err := strconv.ParseInt(web.Params(c.Req)[":jobId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "jobId is invalid", err)
+	}
+
+	job, err := s.RetryJob(c.Req.Context(), id)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to retry job", err)
+	}
+	return response.JSON(http.StatusOK, job)
+}
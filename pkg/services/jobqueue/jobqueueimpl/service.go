@@ -0,0 +1,267 @@
+package jobqueueimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	backoffBase = 30 * time.Second
+	backoffMax  = time.Hour
+)
+
+var _ jobqueue.Service = (*Service)(nil)
+
+type registeredHandler struct {
+	handler jobqueue.Handler
+	opts    jobqueue.HandlerOptions
+}
+
+// Service runs the jobs of the queue with a pool of workers polling the database.
+type Service struct {
+	store store
+	log   log.Logger
+	now   func() time.Time
+
+	workers            int
+	pollInterval       time.Duration
+	jobTimeout         time.Duration
+	defaultMaxAttempts int
+
+	mutex    sync.RWMutex
+	handlers map[string]registeredHandler
+}
+
+func ProvideService(cfg *setting.Cfg, sql db.DB, routeRegister routing.RouteRegister) *Service {
+	section := cfg.SectionWithEnvOverrides("job_queue")
+	s := &Service{
+		store:              &sqlStore{db: sql},
+		log:                log.New("jobqueue"),
+		now:                time.Now,
+		workers:            section.Key("workers").MustInt(2),
+		pollInterval:       section.Key("poll_interval").MustDuration(5 * time.Second),
+		jobTimeout:         section.Key("job_timeout").MustDuration(10 * time.Minute),
+		defaultMaxAttempts: section.Key("max_attempts").MustInt(5),
+		handlers:           map[string]registeredHandler{},
+	}
+	if s.pollInterval <= 0 {
+		s.pollInterval = 5 * time.Second
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+func (s *Service) RegisterHandler(jobType string, handler jobqueue.Handler, opts jobqueue.HandlerOptions) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = s.defaultMaxAttempts
+	}
+	s.handlers[jobType] = registeredHandler{handler: handler, opts: opts}
+}
+
+func (s *Service) handler(jobType string) (registeredHandler, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	h, ok := s.handlers[jobType]
+	return h, ok
+}
+
+func (s *Service) Enqueue(ctx context.Context, cmd jobqueue.EnqueueCommand) (*jobqueue.Job, error) {
+	h, ok := s.handler(cmd.Type)
+	if !ok {
+		return nil, jobqueue.ErrUnknownJobType.Errorf("no handler for jobs of type %s", cmd.Type)
+	}
+
+	now := s.now()
+	row := &jobRow{
+		Type:        cmd.Type,
+		Priority:    cmd.Priority,
+		Status:      string(jobqueue.StatusPending),
+		MaxAttempts: h.opts.MaxAttempts,
+		RunAt:       now.Unix(),
+		Created:     now,
+		Updated:     now,
+	}
+	if !cmd.RunAt.IsZero() {
+		row.RunAt = cmd.RunAt.Unix()
+	}
+	if cmd.Key != "" {
+		key := cmd.Key
+		row.DedupKey = &key
+	}
+	if cmd.Payload != nil {
+		payload, err := json.Marshal(cmd.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the job payload: %w", err)
+		}
+		row.Payload = string(payload)
+	}
+
+	row, err := s.store.Insert(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return row.toJob(), nil
+}
+
+func (s *Service) ListJobs(ctx context.Context, query jobqueue.ListJobsQuery) ([]*jobqueue.Job, error) {
+	if query.Limit <= 0 {
+		query.Limit = 100
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+
+	rows, err := s.store.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*jobqueue.Job, 0, len(rows))
+	for _, row := range rows {
+		jobs = append(jobs, row.toJob())
+	}
+	return jobs, nil
+}
+
+func (s *Service) RetryJob(ctx context.Context, id int64) (*jobqueue.Job, error) {
+	ok, err := s.store.Retry(ctx, id, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, jobqueue.ErrJobNotFound.Errorf("no dead job %d", id)
+	}
+	row, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return row.toJob(), nil
+}
+
+// Run queues the recurring jobs, then runs the jobs of the queue until the context is done.
+func (s *Service) Run(ctx context.Context) error {
+	s.mutex.RLock()
+	for jobType, h := range s.handlers {
+		if h.opts.Interval > 0 {
+			s.enqueueRecurring(ctx, jobType, s.now())
+		}
+	}
+	s.mutex.RUnlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (s *Service) enqueueRecurring(ctx context.Context, jobType string, runAt time.Time) {
+	if _, err := s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: jobType, Key: jobType, RunAt: runAt}); err != nil {
+		s.log.Error("Failed to queue recurring job", "type", jobType, "error", err)
+	}
+}
+
+func (s *Service) work(ctx context.Context) {
+	for {
+		ran, err := s.runNext(ctx)
+		if err != nil {
+			s.log.Error("Failed to pick a job", "error", err)
+		}
+		if ran {
+			continue
+		}
+
+		select {
+		case <-time.After(s.pollInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runNext runs the next job ready to run, it returns false when there was none.
+func (s *Service) runNext(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	now := s.now()
+	row, err := s.store.Claim(ctx, now, now.Add(s.jobTimeout))
+	if err != nil || row == nil {
+		return false, err
+	}
+
+	job := row.toJob()
+	logger := s.log.New("type", job.Type, "id", job.ID, "attempt", job.Attempts)
+	h, ok := s.handler(job.Type)
+	if !ok {
+		err = jobqueue.ErrUnknownJobType.Errorf("no handler for jobs of type %s", job.Type)
+	} else {
+		err = s.execute(ctx, h.handler, job)
+	}
+
+	// the outcome is saved even when the server shuts down
+	ctx = context.Background()
+	if err == nil {
+		logger.Debug("Job completed")
+		if err := s.store.Delete(ctx, job.ID); err != nil {
+			logger.Error("Failed to delete completed job", "error", err)
+		}
+	} else if job.Attempts >= job.MaxAttempts {
+		logger.Error("Job failed for the last time", "error", err)
+		if err := s.store.MarkDead(ctx, job.ID, err.Error()); err != nil {
+			logger.Error("Failed to mark job as dead", "error", err)
+		}
+	} else {
+		retryAt := s.now().Add(backoff(job.Attempts))
+		logger.Warn("Job failed, it will be retried", "retryAt", retryAt, "error", err)
+		if err := s.store.Reschedule(ctx, job.ID, retryAt, err.Error()); err != nil {
+			logger.Error("Failed to reschedule job", "error", err)
+		}
+		return true, nil
+	}
+
+	if ok && h.opts.Interval > 0 {
+		s.enqueueRecurring(ctx, job.Type, s.now().Add(h.opts.Interval))
+	}
+	return true, nil
+}
+
+func (s *Service) execute(ctx context.Context, handler jobqueue.Handler, job *jobqueue.Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, s.jobTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// backoff is how long a job waits before its next attempt, it doubles with every failed attempt.
+func backoff(attempts int) time.Duration {
+	d := backoffBase
+	for i := 1; i < attempts && d < backoffMax; i++ {
+		d *= 2
+	}
+	if d > backoffMax {
+		return backoffMax
+	}
+	return d
+}
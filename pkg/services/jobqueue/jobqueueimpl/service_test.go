@@ -0,0 +1,144 @@
+package jobqueueimpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationJobQueue(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	setup := func(t *testing.T) (*Service, *time.Time) {
+		s := ProvideService(setting.NewCfg(), db.InitTestDB(t), routing.NewRouteRegister())
+		now := time.Unix(1700000000, 0)
+		s.now = func() time.Time { return now }
+		return s, &now
+	}
+
+	t.Run("Jobs run by priority and are deleted once completed", func(t *testing.T) {
+		s, _ := setup(t)
+		var ran []string
+		s.RegisterHandler("test", func(_ context.Context, job *jobqueue.Job) error {
+			ran = append(ran, string(job.Payload))
+			return nil
+		}, jobqueue.HandlerOptions{})
+
+		_, err := s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: "test", Payload: "low"})
+		require.NoError(t, err)
+		_, err = s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: "test", Payload: "high", Priority: 10})
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			ok, err := s.runNext(ctx)
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+		ok, err := s.runNext(ctx)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Equal(t, []string{`"high"`, `"low"`}, ran)
+	})
+
+	t.Run("Jobs with the same key are queued once", func(t *testing.T) {
+		s, _ := setup(t)
+		s.RegisterHandler("test", func(context.Context, *jobqueue.Job) error { return nil }, jobqueue.HandlerOptions{})
+
+		first, err := s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: "test", Key: "key"})
+		require.NoError(t, err)
+		second, err := s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: "test", Key: "key"})
+		require.NoError(t, err)
+		require.Equal(t, first.ID, second.ID)
+	})
+
+	t.Run("Jobs of unknown types are rejected", func(t *testing.T) {
+		s, _ := setup(t)
+		_, err := s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: "unknown"})
+		require.ErrorIs(t, err, jobqueue.ErrUnknownJobType)
+	})
+
+	t.Run("Failed jobs are retried with backoff, then dead until retried", func(t *testing.T) {
+		s, now := setup(t)
+		s.RegisterHandler("test", func(context.Context, *jobqueue.Job) error {
+			return errors.New("boom")
+		}, jobqueue.HandlerOptions{MaxAttempts: 2})
+
+		job, err := s.Enqueue(ctx, jobqueue.EnqueueCommand{Type: "test"})
+		require.NoError(t, err)
+
+		ok, err := s.runNext(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// the job waits for its backoff
+		ok, err = s.runNext(ctx)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		*now = now.Add(backoffBase)
+		ok, err = s.runNext(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		dead, err := s.ListJobs(ctx, jobqueue.ListJobsQuery{Status: jobqueue.StatusDead})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		require.Equal(t, job.ID, dead[0].ID)
+		require.Equal(t, 2, dead[0].Attempts)
+		require.Equal(t, "boom", dead[0].LastError)
+
+		other, err := s.ListJobs(ctx, jobqueue.ListJobsQuery{Status: jobqueue.StatusDead, Types: []string{"other"}})
+		require.NoError(t, err)
+		require.Empty(t, other)
+
+		retried, err := s.RetryJob(ctx, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, jobqueue.StatusPending, retried.Status)
+		require.Equal(t, 0, retried.Attempts)
+
+		_, err = s.RetryJob(ctx, job.ID)
+		require.ErrorIs(t, err, jobqueue.ErrJobNotFound)
+	})
+
+	t.Run("Recurring jobs are queued again after they run", func(t *testing.T) {
+		s, now := setup(t)
+		runs := 0
+		s.RegisterHandler("recurring", func(context.Context, *jobqueue.Job) error {
+			runs++
+			return nil
+		}, jobqueue.HandlerOptions{Interval: time.Minute})
+		s.enqueueRecurring(ctx, "recurring", s.now())
+
+		ok, err := s.runNext(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = s.runNext(ctx)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		*now = now.Add(time.Minute)
+		ok, err = s.runNext(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, 2, runs)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, backoff(1))
+	require.Equal(t, time.Minute, backoff(2))
+	require.Equal(t, 4*time.Minute, backoff(4))
+	require.Equal(t, time.Hour, backoff(20))
+}
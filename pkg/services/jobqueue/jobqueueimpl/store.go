@@ -0,0 +1,214 @@
+package jobqueueimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+)
+
+// maxClaimAttempts is how many times a worker tries to claim a job picked by another instance at the same time
+const maxClaimAttempts = 3
+
+type store interface {
+	Insert(ctx context.Context, row *jobRow) (*jobRow, error)
+	Get(ctx context.Context, id int64) (*jobRow, error)
+	// Claim marks the next job ready to run as running, it returns nil when no job is ready.
+	Claim(ctx context.Context, now time.Time, lockedUntil time.Time) (*jobRow, error)
+	Delete(ctx context.Context, id int64) error
+	Reschedule(ctx context.Context, id int64, runAt time.Time, lastError string) error
+	MarkDead(ctx context.Context, id int64, lastError string) error
+	List(ctx context.Context, query jobqueue.ListJobsQuery) ([]*jobRow, error)
+	Retry(ctx context.Context, id int64, now time.Time) (bool, error)
+}
+
+type jobRow struct {
+	ID          int64     `xorm:"pk autoincr 'id'"`
+	Type        string    `xorm:"type"`
+	DedupKey    *string   `xorm:"dedup_key"`
+	Payload     string    `xorm:"payload"`
+	Priority    int       `xorm:"priority"`
+	Status      string    `xorm:"status"`
+	Attempts    int       `xorm:"attempts"`
+	MaxAttempts int       `xorm:"max_attempts"`
+	LastError   string    `xorm:"last_error"`
+	RunAt       int64     `xorm:"run_at"`
+	LockedUntil int64     `xorm:"locked_until"`
+	Created     time.Time `xorm:"created"`
+	Updated     time.Time `xorm:"updated"`
+}
+
+func (r *jobRow) toJob() *jobqueue.Job {
+	job := &jobqueue.Job{
+		ID:          r.ID,
+		Type:        r.Type,
+		Priority:    r.Priority,
+		Status:      jobqueue.Status(r.Status),
+		Attempts:    r.Attempts,
+		MaxAttempts: r.MaxAttempts,
+		LastError:   r.LastError,
+		RunAt:       time.Unix(r.RunAt, 0),
+		Created:     r.Created,
+		Updated:     r.Updated,
+	}
+	if r.Payload != "" {
+		job.Payload = []byte(r.Payload)
+	}
+	return job
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (ss *sqlStore) Insert(ctx context.Context, row *jobRow) (*jobRow, error) {
+	if row.DedupKey != nil {
+		if existing, err := ss.getByKey(ctx, *row.DedupKey); err != nil || existing != nil {
+			return existing, err
+		}
+	}
+
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Table("job_queue").Insert(row)
+		return err
+	})
+	if err != nil && row.DedupKey != nil && ss.db.GetDialect().IsUniqueConstraintViolation(err) {
+		// queued by another instance in the meantime
+		return ss.getByKey(ctx, *row.DedupKey)
+	}
+	return row, err
+}
+
+func (ss *sqlStore) getByKey(ctx context.Context, key string) (*jobRow, error) {
+	var row jobRow
+	var found bool
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Table("job_queue").Where("dedup_key = ?", key).Get(&row)
+		return err
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (ss *sqlStore) Get(ctx context.Context, id int64) (*jobRow, error) {
+	var row jobRow
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		found, err := sess.Table("job_queue").ID(id).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return jobqueue.ErrJobNotFound.Errorf("job %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (ss *sqlStore) Claim(ctx context.Context, now time.Time, lockedUntil time.Time) (*jobRow, error) {
+	for i := 0; i < maxClaimAttempts; i++ {
+		var row jobRow
+		var claimed bool
+		err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+			found, err := sess.Table("job_queue").
+				Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until < ?)",
+					jobqueue.StatusPending, now.Unix(), jobqueue.StatusRunning, now.Unix()).
+				OrderBy("priority DESC, run_at ASC, id ASC").
+				Limit(1).
+				Get(&row)
+			if err != nil || !found {
+				return err
+			}
+
+			// the attempts act as a version, only one instance can claim the job
+			affected, err := sess.Table("job_queue").
+				Where("id = ? AND status = ? AND attempts = ?", row.ID, row.Status, row.Attempts).
+				Cols("status", "attempts", "locked_until", "updated").
+				Update(&jobRow{
+					Status:      string(jobqueue.StatusRunning),
+					Attempts:    row.Attempts + 1,
+					LockedUntil: lockedUntil.Unix(),
+					Updated:     now,
+				})
+			if err != nil {
+				return err
+			}
+			claimed = affected == 1
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if row.ID == 0 {
+			return nil, nil
+		}
+		if claimed {
+			row.Status = string(jobqueue.StatusRunning)
+			row.Attempts++
+			row.LockedUntil = lockedUntil.Unix()
+			row.Updated = now
+			return &row, nil
+		}
+	}
+	return nil, nil
+}
+
+func (ss *sqlStore) Delete(ctx context.Context, id int64) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM job_queue WHERE id = ?", id)
+		return err
+	})
+}
+
+func (ss *sqlStore) Reschedule(ctx context.Context, id int64, runAt time.Time, lastError string) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE job_queue SET status = ?, run_at = ?, locked_until = 0, last_error = ?, updated = ? WHERE id = ?",
+			jobqueue.StatusPending, runAt.Unix(), lastError, time.Now(), id)
+		return err
+	})
+}
+
+func (ss *sqlStore) MarkDead(ctx context.Context, id int64, lastError string) error {
+	return ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE job_queue SET status = ?, dedup_key = NULL, locked_until = 0, last_error = ?, updated = ? WHERE id = ?",
+			jobqueue.StatusDead, lastError, time.Now(), id)
+		return err
+	})
+}
+
+func (ss *sqlStore) List(ctx context.Context, query jobqueue.ListJobsQuery) ([]*jobRow, error) {
+	rows := make([]*jobRow, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("job_queue")
+		if query.Status != "" {
+			sess.Where("status = ?", query.Status)
+		}
+		if len(query.Types) > 0 {
+			sess.In("type", query.Types)
+		}
+		offset := query.Limit * (query.Page - 1)
+		return sess.OrderBy("updated DESC, id DESC").Limit(query.Limit, offset).Find(&rows)
+	})
+	return rows, err
+}
+
+func (ss *sqlStore) Retry(ctx context.Context, id int64, now time.Time) (bool, error) {
+	var affected int64
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("UPDATE job_queue SET status = ?, attempts = 0, run_at = ?, locked_until = 0, updated = ? WHERE id = ? AND status = ?",
+			jobqueue.StatusPending, now.Unix(), now, id, jobqueue.StatusDead)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected == 1, err
+}
@@ -0,0 +1,264 @@
+package resources
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// Syncer syncs a resource, it is implemented by the Engine.
+type Syncer interface {
+	Sync(ctx context.Context, orgID int64, uid string) (SyncAction, error)
+}
+
+// EventParker keeps the events which failed too many times, rather than dropping them.
+type EventParker interface {
+	Park(ctx context.Context, event FailedEvent) error
+}
+
+// FailedEvent is the sync of a resource which failed too many times.
+type FailedEvent struct {
+	Kind      string
+	OrgID     int64
+	UID       string
+	Attempts  int
+	LastError string
+}
+
+type QueueOptions struct {
+	// Workers is the number of events synced concurrently
+	Workers int
+	// InitialBackoff is how long an event waits before its second attempt, the wait doubles with every failed attempt
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts is the number of times an event is synced before it is parked
+	MaxAttempts int
+}
+
+func DefaultQueueOptions() QueueOptions {
+	return QueueOptions{
+		Workers:        2,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		MaxAttempts:    10,
+	}
+}
+
+type queueKey struct {
+	orgID int64
+	uid   string
+}
+
+type queueItem struct {
+	attempts int
+	// queued is true while the item waits for a worker, waiting while it waits for its next attempt
+	queued     bool
+	waiting    bool
+	processing bool
+	// dirty is set when the resource changed again while it was synced, it is synced once more afterwards
+	dirty bool
+}
+
+// Queue syncs the resources changed on either side, as reported by the watchers. The events of a resource are
+// collapsed while they wait, the engine always syncs the latest state of both sides. The events that fail are
+// retried with an exponential backoff, and parked once they failed MaxAttempts times rather than being lost.
+type Queue struct {
+	kind   string
+	syncer Syncer
+	parker EventParker
+	opts   QueueOptions
+	log    log.Logger
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	items    map[queueKey]*queueItem
+	ready    []queueKey
+	shutdown bool
+
+	afterFunc func(d time.Duration, f func())
+}
+
+func NewQueue(kind string, syncer Syncer, parker EventParker, opts QueueOptions) *Queue {
+	defaults := DefaultQueueOptions()
+	if opts.Workers <= 0 {
+		opts.Workers = defaults.Workers
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = defaults.InitialBackoff
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+
+	q := &Queue{
+		kind:   kind,
+		syncer: syncer,
+		parker: parker,
+		opts:   opts,
+		log:    log.New("k8s.resources.queue", "kind", kind),
+		items:  map[queueKey]*queueItem{},
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *Queue) Kind() string {
+	return q.kind
+}
+
+// Add queues the sync of a resource. It is a no-op when the resource is already queued or waits for a retry.
+func (q *Queue) Add(orgID int64, uid string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := queueKey{orgID: orgID, uid: uid}
+	item, ok := q.items[key]
+	if !ok {
+		item = &queueItem{}
+		q.items[key] = item
+	}
+	switch {
+	case item.processing:
+		item.dirty = true
+	case item.queued || item.waiting:
+	default:
+		q.push(key, item)
+	}
+}
+
+// push must be called with the lock held.
+func (q *Queue) push(key queueKey, item *queueItem) {
+	item.queued = true
+	q.ready = append(q.ready, key)
+	q.cond.Signal()
+}
+
+// Run syncs the queued resources until the context is done.
+func (q *Queue) Run(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		q.mu.Lock()
+		q.shutdown = true
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < q.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				key, ok := q.next()
+				if !ok {
+					return
+				}
+				q.process(ctx, key)
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// next blocks until a resource is ready to be synced, it returns false once the queue is shut down.
+func (q *Queue) next() (queueKey, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.ready) == 0 && !q.shutdown {
+		q.cond.Wait()
+	}
+	if q.shutdown {
+		return queueKey{}, false
+	}
+
+	key := q.ready[0]
+	q.ready = q.ready[1:]
+	item := q.items[key]
+	item.queued = false
+	item.processing = true
+	return key, true
+}
+
+func (q *Queue) process(ctx context.Context, key queueKey) {
+	_, err := q.syncer.Sync(ctx, key.orgID, key.uid)
+
+	q.mu.Lock()
+	item := q.items[key]
+	item.processing = false
+	dirty := item.dirty
+	item.dirty = false
+
+	if err == nil {
+		item.attempts = 0
+		if dirty {
+			q.push(key, item)
+		} else {
+			delete(q.items, key)
+		}
+		q.mu.Unlock()
+		return
+	}
+	if ctx.Err() != nil {
+		// the server shuts down, the resource is synced again by the next full sync
+		delete(q.items, key)
+		q.mu.Unlock()
+		return
+	}
+
+	item.attempts++
+	attempts := item.attempts
+	if attempts >= q.opts.MaxAttempts {
+		delete(q.items, key)
+		q.mu.Unlock()
+		q.park(key, attempts, err)
+		return
+	}
+
+	item.waiting = true
+	delay := q.backoff(attempts)
+	q.mu.Unlock()
+
+	q.log.Warn("Failed to sync resource, it will be retried", "orgID", key.orgID, "uid", key.uid, "attempt", attempts, "retryIn", delay, "error", err)
+	q.afterFunc(delay, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		item.waiting = false
+		q.push(key, item)
+	})
+}
+
+func (q *Queue) park(key queueKey, attempts int, syncErr error) {
+	q.log.Error("Failed to sync resource for the last time, parking the event", "orgID", key.orgID, "uid", key.uid, "attempts", attempts, "error", syncErr)
+	// the event is parked even when the server shuts down
+	err := q.parker.Park(context.Background(), FailedEvent{
+		Kind:      q.kind,
+		OrgID:     key.orgID,
+		UID:       key.uid,
+		Attempts:  attempts,
+		LastError: syncErr.Error(),
+	})
+	if err != nil {
+		q.log.Error("Failed to park event", "orgID", key.orgID, "uid", key.uid, "error", err)
+	}
+}
+
+// backoff is how long an event waits before its next attempt, it doubles with every failed attempt.
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := q.opts.InitialBackoff
+	for i := 1; i < attempts && d < q.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > q.opts.MaxBackoff {
+		return q.opts.MaxBackoff
+	}
+	return d
+}
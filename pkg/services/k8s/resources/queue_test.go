@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSyncer struct {
+	mu sync.Mutex
+	// failures is the number of times the sync of a resource fails before it succeeds, -1 to always fail
+	failures map[string]int
+	synced   map[string]int
+}
+
+func (f *fakeSyncer) Sync(_ context.Context, _ int64, uid string) (SyncAction, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.synced[uid]++
+	if n := f.failures[uid]; n != 0 {
+		f.failures[uid] = n - 1
+		return SyncActionNone, errors.New("kubernetes is unavailable")
+	}
+	return SyncActionK8sToGrafana, nil
+}
+
+func (f *fakeSyncer) count(uid string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.synced[uid]
+}
+
+type fakeParker struct {
+	mu     sync.Mutex
+	events []FailedEvent
+}
+
+func (f *fakeParker) Park(_ context.Context, event FailedEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeParker) parked() []FailedEvent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FailedEvent{}, f.events...)
+}
+
+func TestQueue(t *testing.T) {
+	setup := func(t *testing.T, failures map[string]int) (*Queue, *fakeSyncer, *fakeParker, func() []time.Duration) {
+		syncer := &fakeSyncer{failures: failures, synced: map[string]int{}}
+		parker := &fakeParker{}
+		q := NewQueue("publicdashboard", syncer, parker, QueueOptions{Workers: 1, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, MaxAttempts: 4})
+
+		// the retries are immediate, the delays are recorded
+		var mu sync.Mutex
+		var delays []time.Duration
+		q.afterFunc = func(d time.Duration, f func()) {
+			mu.Lock()
+			delays = append(delays, d)
+			mu.Unlock()
+			go f()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			_ = q.Run(ctx)
+			close(done)
+		}()
+		t.Cleanup(func() {
+			cancel()
+			<-done
+		})
+
+		return q, syncer, parker, func() []time.Duration {
+			mu.Lock()
+			defer mu.Unlock()
+			return append([]time.Duration{}, delays...)
+		}
+	}
+
+	t.Run("retries the failed events with an exponential backoff", func(t *testing.T) {
+		q, syncer, parker, delays := setup(t, map[string]int{"flaky": 2})
+
+		q.Add(1, "flaky")
+		require.Eventually(t, func() bool { return syncer.count("flaky") == 3 }, time.Second, time.Millisecond)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays())
+		require.Empty(t, parker.parked())
+	})
+
+	t.Run("parks the events which failed too many times", func(t *testing.T) {
+		q, syncer, parker, delays := setup(t, map[string]int{"poison": -1})
+
+		q.Add(2, "poison")
+		require.Eventually(t, func() bool { return len(parker.parked()) == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, 4, syncer.count("poison"))
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, delays())
+
+		event := parker.parked()[0]
+		require.Equal(t, "publicdashboard", event.Kind)
+		require.Equal(t, int64(2), event.OrgID)
+		require.Equal(t, "poison", event.UID)
+		require.Equal(t, 4, event.Attempts)
+		require.Equal(t, "kubernetes is unavailable", event.LastError)
+	})
+
+	t.Run("collapses the events of a resource waiting to be synced", func(t *testing.T) {
+		syncer := &fakeSyncer{failures: map[string]int{}, synced: map[string]int{}}
+		q := NewQueue("publicdashboard", syncer, &fakeParker{}, QueueOptions{Workers: 1})
+		q.Add(1, "uid")
+		q.Add(1, "uid")
+		q.Add(1, "other")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = q.Run(ctx) }()
+
+		require.Eventually(t, func() bool { return syncer.count("other") == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, 1, syncer.count("uid"))
+	})
+}
@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+)
+
+const syncStateNamespace = "k8s.resources.sync"
+
+// SyncState is what the engine remembers of a resource after a successful sync.
+type SyncState struct {
+	Generation int64     `json:"generation"`
+	Updated    time.Time `json:"updated"`
+}
+
+// StateStore persists the SyncState of the resources.
+type StateStore interface {
+	// Get returns nil when the resource was never synced.
+	Get(ctx context.Context, kind string, orgID int64, uid string) (*SyncState, error)
+	Set(ctx context.Context, kind string, orgID int64, uid string, state SyncState) error
+	Delete(ctx context.Context, kind string, orgID int64, uid string) error
+}
+
+// KVStateStore is a StateStore backed by the Grafana kvstore.
+type KVStateStore struct {
+	kv kvstore.KVStore
+}
+
+func NewKVStateStore(kv kvstore.KVStore) *KVStateStore {
+	return &KVStateStore{kv: kv}
+}
+
+func (s *KVStateStore) Get(ctx context.Context, kind string, orgID int64, uid string) (*SyncState, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, syncStateNamespace, stateKey(kind, uid))
+	if err != nil || !ok {
+		return nil, err
+	}
+	var state SyncState
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *KVStateStore) Set(ctx context.Context, kind string, orgID int64, uid string, state SyncState) error {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, orgID, syncStateNamespace, stateKey(kind, uid), string(value))
+}
+
+func (s *KVStateStore) Delete(ctx context.Context, kind string, orgID int64, uid string) error {
+	return s.kv.Del(ctx, orgID, syncStateNamespace, stateKey(kind, uid))
+}
+
+func stateKey(kind, uid string) string {
+	return kind + "/" + uid
+}
@@ -0,0 +1,258 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// ConflictPolicy defines which side wins when a resource changed both in Kubernetes and in Grafana since the last sync.
+type ConflictPolicy string
+
+const (
+	// ConflictPolicyK8sWins overwrites the Grafana resource with the Kubernetes resource.
+	ConflictPolicyK8sWins ConflictPolicy = "k8s-wins"
+	// ConflictPolicyGrafanaWins overwrites the Kubernetes resource with the Grafana resource.
+	ConflictPolicyGrafanaWins ConflictPolicy = "grafana-wins"
+	// ConflictPolicyManual leaves both sides untouched until the conflict is resolved by hand.
+	ConflictPolicyManual ConflictPolicy = "manual"
+)
+
+func (p ConflictPolicy) IsValid() bool {
+	switch p {
+	case ConflictPolicyK8sWins, ConflictPolicyGrafanaWins, ConflictPolicyManual:
+		return true
+	}
+	return false
+}
+
+// SyncAction is the outcome of the sync of a resource.
+type SyncAction string
+
+const (
+	SyncActionNone             SyncAction = "none"
+	SyncActionK8sToGrafana     SyncAction = "k8s-to-grafana"
+	SyncActionGrafanaToK8s     SyncAction = "grafana-to-k8s"
+	SyncActionDeleteInGrafana  SyncAction = "delete-in-grafana"
+	SyncActionDeleteInK8s      SyncAction = "delete-in-k8s"
+	SyncActionConflictUnsolved SyncAction = "conflict"
+)
+
+// Object is the content of a resource shared by both sides. Spec is the JSON encoded spec of the resource,
+// two objects with the same spec are considered in sync.
+type Object struct {
+	OrgID int64
+	UID   string
+	Spec  []byte
+}
+
+// K8sObject is a resource as stored in Kubernetes.
+type K8sObject struct {
+	Object
+	// Generation is the metadata.generation of the custom resource, it changes with every change of the spec.
+	Generation int64
+}
+
+// GrafanaObject is a resource as stored in Grafana.
+type GrafanaObject struct {
+	Object
+	Updated time.Time
+}
+
+// K8sStore reads and writes the custom resources of a kind.
+type K8sStore interface {
+	// Get returns nil when the resource does not exist.
+	Get(ctx context.Context, orgID int64, uid string) (*K8sObject, error)
+	// Apply creates or updates the resource and returns its new generation.
+	Apply(ctx context.Context, obj Object) (int64, error)
+	Delete(ctx context.Context, orgID int64, uid string) error
+}
+
+// GrafanaStore reads and writes the Grafana resources of a kind.
+type GrafanaStore interface {
+	// Get returns nil when the resource does not exist.
+	Get(ctx context.Context, orgID int64, uid string) (*GrafanaObject, error)
+	// Apply creates or updates the resource and returns its new updated timestamp.
+	Apply(ctx context.Context, obj Object) (time.Time, error)
+	Delete(ctx context.Context, orgID int64, uid string) error
+}
+
+// Conflict describes a resource that changed on both sides since the last sync.
+type Conflict struct {
+	Kind           string
+	OrgID          int64
+	UID            string
+	K8sGeneration  int64
+	GrafanaUpdated time.Time
+	Policy         ConflictPolicy
+	// Resolution is the action taken, SyncActionConflictUnsolved with the manual policy.
+	Resolution SyncAction
+	DetectedAt time.Time
+}
+
+// ConflictRecorder emits the conflicts, typically as a condition of the status subresource of the custom resource.
+type ConflictRecorder interface {
+	RecordConflict(ctx context.Context, conflict Conflict) error
+}
+
+// Engine synchronizes the resources of a kind between Kubernetes and Grafana. It is shared by the watchers of both
+// sides: they call Sync for every resource that changed, in any order, and the engine decides on the direction from
+// the generation and the updated timestamp recorded at the last sync.
+type Engine struct {
+	kind      string
+	k8s       K8sStore
+	grafana   GrafanaStore
+	conflicts ConflictRecorder
+	state     StateStore
+	policy    ConflictPolicy
+	log       log.Logger
+	now       func() time.Time
+}
+
+func NewEngine(kind string, k8s K8sStore, grafana GrafanaStore, conflicts ConflictRecorder, state StateStore, policy ConflictPolicy) (*Engine, error) {
+	if !policy.IsValid() {
+		return nil, fmt.Errorf("invalid conflict policy %q", policy)
+	}
+	return &Engine{
+		kind:      kind,
+		k8s:       k8s,
+		grafana:   grafana,
+		conflicts: conflicts,
+		state:     state,
+		policy:    policy,
+		log:       log.New("k8s.resources.sync", "kind", kind),
+		now:       time.Now,
+	}, nil
+}
+
+// Sync brings the resource with the given UID in sync on both sides and returns the action taken.
+func (e *Engine) Sync(ctx context.Context, orgID int64, uid string) (SyncAction, error) {
+	k8sObj, err := e.k8s.Get(ctx, orgID, uid)
+	if err != nil {
+		return SyncActionNone, fmt.Errorf("failed to get the %s resource %s from kubernetes: %w", e.kind, uid, err)
+	}
+	grafanaObj, err := e.grafana.Get(ctx, orgID, uid)
+	if err != nil {
+		return SyncActionNone, fmt.Errorf("failed to get the %s resource %s from grafana: %w", e.kind, uid, err)
+	}
+	last, err := e.state.Get(ctx, e.kind, orgID, uid)
+	if err != nil {
+		return SyncActionNone, fmt.Errorf("failed to get the sync state of the %s resource %s: %w", e.kind, uid, err)
+	}
+
+	k8sChanged := changedInK8s(k8sObj, last)
+	grafanaChanged := changedInGrafana(grafanaObj, last)
+
+	var action SyncAction
+	switch {
+	case !k8sChanged && !grafanaChanged:
+		return SyncActionNone, nil
+	case k8sObj == nil && grafanaObj == nil:
+		// deleted on both sides
+		return SyncActionNone, e.state.Delete(ctx, e.kind, orgID, uid)
+	case k8sChanged && grafanaChanged:
+		if k8sObj != nil && grafanaObj != nil && bytes.Equal(k8sObj.Spec, grafanaObj.Spec) {
+			// both sides made the same change
+			return SyncActionNone, e.saveState(ctx, orgID, uid, k8sObj.Generation, grafanaObj.Updated)
+		}
+		return e.resolveConflict(ctx, orgID, uid, k8sObj, grafanaObj)
+	case k8sChanged:
+		action, err = e.copyFromK8s(ctx, orgID, uid, k8sObj)
+	default:
+		action, err = e.copyFromGrafana(ctx, orgID, uid, grafanaObj)
+	}
+	if err != nil {
+		return SyncActionNone, err
+	}
+	e.log.Debug("Synced resource", "orgID", orgID, "uid", uid, "action", action)
+	return action, nil
+}
+
+func (e *Engine) resolveConflict(ctx context.Context, orgID int64, uid string, k8sObj *K8sObject, grafanaObj *GrafanaObject) (SyncAction, error) {
+	conflict := Conflict{
+		Kind:       e.kind,
+		OrgID:      orgID,
+		UID:        uid,
+		Policy:     e.policy,
+		Resolution: SyncActionConflictUnsolved,
+		DetectedAt: e.now(),
+	}
+	if k8sObj != nil {
+		conflict.K8sGeneration = k8sObj.Generation
+	}
+	if grafanaObj != nil {
+		conflict.GrafanaUpdated = grafanaObj.Updated
+	}
+
+	var err error
+	switch e.policy {
+	case ConflictPolicyK8sWins:
+		conflict.Resolution, err = e.copyFromK8s(ctx, orgID, uid, k8sObj)
+	case ConflictPolicyGrafanaWins:
+		conflict.Resolution, err = e.copyFromGrafana(ctx, orgID, uid, grafanaObj)
+	}
+	if err != nil {
+		return SyncActionNone, err
+	}
+
+	e.log.Warn("Resource changed in kubernetes and grafana", "orgID", orgID, "uid", uid, "policy", e.policy, "resolution", conflict.Resolution)
+	if err := e.conflicts.RecordConflict(ctx, conflict); err != nil {
+		return conflict.Resolution, fmt.Errorf("failed to record the conflict of the %s resource %s: %w", e.kind, uid, err)
+	}
+	return conflict.Resolution, nil
+}
+
+func (e *Engine) copyFromK8s(ctx context.Context, orgID int64, uid string, k8sObj *K8sObject) (SyncAction, error) {
+	if k8sObj == nil {
+		if err := e.grafana.Delete(ctx, orgID, uid); err != nil {
+			return SyncActionNone, fmt.Errorf("failed to delete the %s resource %s in grafana: %w", e.kind, uid, err)
+		}
+		return SyncActionDeleteInGrafana, e.state.Delete(ctx, e.kind, orgID, uid)
+	}
+	updated, err := e.grafana.Apply(ctx, k8sObj.Object)
+	if err != nil {
+		return SyncActionNone, fmt.Errorf("failed to apply the %s resource %s in grafana: %w", e.kind, uid, err)
+	}
+	return SyncActionK8sToGrafana, e.saveState(ctx, orgID, uid, k8sObj.Generation, updated)
+}
+
+func (e *Engine) copyFromGrafana(ctx context.Context, orgID int64, uid string, grafanaObj *GrafanaObject) (SyncAction, error) {
+	if grafanaObj == nil {
+		if err := e.k8s.Delete(ctx, orgID, uid); err != nil {
+			return SyncActionNone, fmt.Errorf("failed to delete the %s resource %s in kubernetes: %w", e.kind, uid, err)
+		}
+		return SyncActionDeleteInK8s, e.state.Delete(ctx, e.kind, orgID, uid)
+	}
+	generation, err := e.k8s.Apply(ctx, grafanaObj.Object)
+	if err != nil {
+		return SyncActionNone, fmt.Errorf("failed to apply the %s resource %s in kubernetes: %w", e.kind, uid, err)
+	}
+	return SyncActionGrafanaToK8s, e.saveState(ctx, orgID, uid, generation, grafanaObj.Updated)
+}
+
+func (e *Engine) saveState(ctx context.Context, orgID int64, uid string, generation int64, updated time.Time) error {
+	err := e.state.Set(ctx, e.kind, orgID, uid, SyncState{Generation: generation, Updated: updated})
+	if err != nil {
+		return fmt.Errorf("failed to save the sync state of the %s resource %s: %w", e.kind, uid, err)
+	}
+	return nil
+}
+
+// changedInK8s returns whether the resource was created, updated or deleted in kubernetes since the last sync.
+func changedInK8s(obj *K8sObject, last *SyncState) bool {
+	if last == nil {
+		return obj != nil
+	}
+	return obj == nil || obj.Generation != last.Generation
+}
+
+// changedInGrafana returns whether the resource was created, updated or deleted in grafana since the last sync.
+func changedInGrafana(obj *GrafanaObject, last *SyncState) bool {
+	if last == nil {
+		return obj != nil
+	}
+	return obj == nil || !obj.Updated.Equal(last.Updated)
+}
@@ -0,0 +1,177 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+)
+
+type fakeK8sStore struct {
+	objects map[string]*K8sObject
+}
+
+func (f *fakeK8sStore) Get(_ context.Context, _ int64, uid string) (*K8sObject, error) {
+	return f.objects[uid], nil
+}
+
+func (f *fakeK8sStore) Apply(_ context.Context, obj Object) (int64, error) {
+	var generation int64 = 1
+	if existing, ok := f.objects[obj.UID]; ok {
+		generation = existing.Generation + 1
+	}
+	f.objects[obj.UID] = &K8sObject{Object: obj, Generation: generation}
+	return generation, nil
+}
+
+func (f *fakeK8sStore) Delete(_ context.Context, _ int64, uid string) error {
+	delete(f.objects, uid)
+	return nil
+}
+
+type fakeGrafanaStore struct {
+	objects map[string]*GrafanaObject
+	now     time.Time
+}
+
+func (f *fakeGrafanaStore) Get(_ context.Context, _ int64, uid string) (*GrafanaObject, error) {
+	return f.objects[uid], nil
+}
+
+func (f *fakeGrafanaStore) Apply(_ context.Context, obj Object) (time.Time, error) {
+	f.now = f.now.Add(time.Second)
+	f.objects[obj.UID] = &GrafanaObject{Object: obj, Updated: f.now}
+	return f.now, nil
+}
+
+func (f *fakeGrafanaStore) Delete(_ context.Context, _ int64, uid string) error {
+	delete(f.objects, uid)
+	return nil
+}
+
+type fakeConflictRecorder struct {
+	conflicts []Conflict
+}
+
+func (f *fakeConflictRecorder) RecordConflict(_ context.Context, conflict Conflict) error {
+	f.conflicts = append(f.conflicts, conflict)
+	return nil
+}
+
+type syncTestEnv struct {
+	engine    *Engine
+	k8s       *fakeK8sStore
+	grafana   *fakeGrafanaStore
+	conflicts *fakeConflictRecorder
+}
+
+func newSyncTestEnv(t *testing.T, policy ConflictPolicy) *syncTestEnv {
+	t.Helper()
+	env := &syncTestEnv{
+		k8s:       &fakeK8sStore{objects: map[string]*K8sObject{}},
+		grafana:   &fakeGrafanaStore{objects: map[string]*GrafanaObject{}, now: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		conflicts: &fakeConflictRecorder{},
+	}
+	engine, err := NewEngine("playlist", env.k8s, env.grafana, env.conflicts, NewKVStateStore(kvstore.NewFakeKVStore()), policy)
+	require.NoError(t, err)
+	env.engine = engine
+	return env
+}
+
+func (env *syncTestEnv) sync(t *testing.T) SyncAction {
+	t.Helper()
+	action, err := env.engine.Sync(context.Background(), 1, "uid")
+	require.NoError(t, err)
+	return action
+}
+
+func TestEngineSync(t *testing.T) {
+	ctx := context.Background()
+	spec := func(s string) Object {
+		return Object{OrgID: 1, UID: "uid", Spec: []byte(s)}
+	}
+
+	t.Run("copies the changes in one direction", func(t *testing.T) {
+		env := newSyncTestEnv(t, ConflictPolicyManual)
+
+		_, err := env.k8s.Apply(ctx, spec(`{"name":"a"}`))
+		require.NoError(t, err)
+		require.Equal(t, SyncActionK8sToGrafana, env.sync(t))
+		require.Equal(t, `{"name":"a"}`, string(env.grafana.objects["uid"].Spec))
+		require.Equal(t, SyncActionNone, env.sync(t))
+
+		_, err = env.grafana.Apply(ctx, spec(`{"name":"b"}`))
+		require.NoError(t, err)
+		require.Equal(t, SyncActionGrafanaToK8s, env.sync(t))
+		require.Equal(t, `{"name":"b"}`, string(env.k8s.objects["uid"].Spec))
+		require.Equal(t, SyncActionNone, env.sync(t))
+
+		require.NoError(t, env.grafana.Delete(ctx, 1, "uid"))
+		require.Equal(t, SyncActionDeleteInK8s, env.sync(t))
+		require.Empty(t, env.k8s.objects)
+		require.Equal(t, SyncActionNone, env.sync(t))
+		require.Empty(t, env.conflicts.conflicts)
+	})
+
+	t.Run("identical changes on both sides are not a conflict", func(t *testing.T) {
+		env := newSyncTestEnv(t, ConflictPolicyManual)
+
+		_, err := env.k8s.Apply(ctx, spec(`{"name":"a"}`))
+		require.NoError(t, err)
+		_, err = env.grafana.Apply(ctx, spec(`{"name":"a"}`))
+		require.NoError(t, err)
+
+		require.Equal(t, SyncActionNone, env.sync(t))
+		require.Empty(t, env.conflicts.conflicts)
+	})
+
+	testCases := []struct {
+		policy          ConflictPolicy
+		expectedAction  SyncAction
+		expectedK8s     string
+		expectedGrafana string
+	}{
+		{policy: ConflictPolicyK8sWins, expectedAction: SyncActionK8sToGrafana, expectedK8s: `{"name":"k8s"}`, expectedGrafana: `{"name":"k8s"}`},
+		{policy: ConflictPolicyGrafanaWins, expectedAction: SyncActionGrafanaToK8s, expectedK8s: `{"name":"grafana"}`, expectedGrafana: `{"name":"grafana"}`},
+		{policy: ConflictPolicyManual, expectedAction: SyncActionConflictUnsolved, expectedK8s: `{"name":"k8s"}`, expectedGrafana: `{"name":"grafana"}`},
+	}
+	for _, tc := range testCases {
+		t.Run("resolves conflicts with policy "+string(tc.policy), func(t *testing.T) {
+			env := newSyncTestEnv(t, tc.policy)
+
+			_, err := env.k8s.Apply(ctx, spec(`{"name":"a"}`))
+			require.NoError(t, err)
+			require.Equal(t, SyncActionK8sToGrafana, env.sync(t))
+
+			_, err = env.k8s.Apply(ctx, spec(`{"name":"k8s"}`))
+			require.NoError(t, err)
+			_, err = env.grafana.Apply(ctx, spec(`{"name":"grafana"}`))
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedAction, env.sync(t))
+			require.Equal(t, tc.expectedK8s, string(env.k8s.objects["uid"].Spec))
+			require.Equal(t, tc.expectedGrafana, string(env.grafana.objects["uid"].Spec))
+
+			require.Len(t, env.conflicts.conflicts, 1)
+			conflict := env.conflicts.conflicts[0]
+			require.Equal(t, "playlist", conflict.Kind)
+			require.Equal(t, "uid", conflict.UID)
+			require.Equal(t, tc.policy, conflict.Policy)
+			require.Equal(t, tc.expectedAction, conflict.Resolution)
+			require.Equal(t, int64(2), conflict.K8sGeneration)
+
+			if tc.policy != ConflictPolicyManual {
+				// the winner is synced, the next sync has nothing to do
+				require.Equal(t, SyncActionNone, env.sync(t))
+			}
+		})
+	}
+
+	t.Run("rejects an unknown policy", func(t *testing.T) {
+		_, err := NewEngine("playlist", nil, nil, nil, nil, "last-wins")
+		require.Error(t, err)
+	})
+}
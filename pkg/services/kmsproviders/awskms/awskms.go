@@ -0,0 +1,97 @@
+package awskms
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Kind is the kind of the AWS KMS providers, configured in the [security.encryption.awskms.<key-name>] sections.
+const Kind = "awskms"
+
+type provider struct {
+	client kmsiface.KMSAPI
+	keyID  string
+}
+
+// New returns a provider encrypting the data keys with an AWS KMS key. Without access keys, the credentials
+// are looked up in the default chain: environment, shared configuration, web identity and EC2 or ECS roles.
+func New(section setting.Section) (secrets.Provider, error) {
+	keyID := section.KeyValue("key_id").Value()
+	if keyID == "" {
+		return nil, errors.New("key_id is required")
+	}
+
+	cfg := aws.NewConfig()
+	if region := section.KeyValue("region").Value(); region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+	if endpoint := section.KeyValue("endpoint").Value(); endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	if accessKeyID := section.KeyValue("access_key_id").Value(); accessKeyID != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(
+			accessKeyID,
+			section.KeyValue("secret_access_key").Value(),
+			section.KeyValue("session_token").Value(),
+		))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{Config: *cfg, SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, err
+	}
+
+	if roleARN := section.KeyValue("assume_role_arn").Value(); roleARN != "" {
+		externalID := section.KeyValue("external_id").Value()
+		creds := stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			if externalID != "" {
+				p.ExternalID = aws.String(externalID)
+			}
+		})
+		return &provider{client: kms.New(sess, aws.NewConfig().WithCredentials(creds)), keyID: keyID}, nil
+	}
+
+	return &provider{client: kms.New(sess), keyID: keyID}, nil
+}
+
+func (p *provider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	out, err := p.client.EncryptWithContext(ctx, &kms.EncryptInput{KeyId: aws.String(p.keyID), Plaintext: blob})
+	if err != nil {
+		return nil, err
+	}
+	return out.CiphertextBlob, nil
+}
+
+// Decrypt does not restrict the key to the configured one, the ciphertext identifies the key it was encrypted with:
+// the blobs encrypted with the previous key of an alias can still be decrypted.
+func (p *provider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	out, err := p.client.DecryptWithContext(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return nil, err
+	}
+	return out.Plaintext, nil
+}
+
+// Outdated returns true if the blob was encrypted with another key than the configured one, e.g. the alias was
+// updated to a new key. The automatic rotation of a key by AWS keeps its previous versions, it does not outdate the blobs.
+func (p *provider) Outdated(ctx context.Context, blob []byte) (bool, error) {
+	key, err := p.client.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{KeyId: aws.String(p.keyID)})
+	if err != nil {
+		return false, err
+	}
+	out, err := p.client.DecryptWithContext(ctx, &kms.DecryptInput{CiphertextBlob: blob})
+	if err != nil {
+		return false, err
+	}
+	return aws.StringValue(out.KeyId) != aws.StringValue(key.KeyMetadata.Arn), nil
+}
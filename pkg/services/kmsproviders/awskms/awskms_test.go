@@ -0,0 +1,56 @@
+package awskms
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKMS prefixes the ciphertexts with the ARN of the key the alias points to.
+type fakeKMS struct {
+	kmsiface.KMSAPI
+	aliasARN string
+}
+
+func (f *fakeKMS) EncryptWithContext(_ aws.Context, in *kms.EncryptInput, _ ...request.Option) (*kms.EncryptOutput, error) {
+	return &kms.EncryptOutput{CiphertextBlob: append([]byte(f.aliasARN+"|"), in.Plaintext...)}, nil
+}
+
+func (f *fakeKMS) DecryptWithContext(_ aws.Context, in *kms.DecryptInput, _ ...request.Option) (*kms.DecryptOutput, error) {
+	parts := bytes.SplitN(in.CiphertextBlob, []byte("|"), 2)
+	return &kms.DecryptOutput{KeyId: aws.String(string(parts[0])), Plaintext: parts[1]}, nil
+}
+
+func (f *fakeKMS) DescribeKeyWithContext(_ aws.Context, _ *kms.DescribeKeyInput, _ ...request.Option) (*kms.DescribeKeyOutput, error) {
+	return &kms.DescribeKeyOutput{KeyMetadata: &kms.KeyMetadata{Arn: aws.String(f.aliasARN)}}, nil
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeKMS{aliasARN: "arn:aws:kms:eu-north-1:111122223333:key/first"}
+	p := &provider{client: client, keyID: "alias/grafana"}
+
+	encrypted, err := p.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+
+	outdated, err := p.Outdated(ctx, encrypted)
+	require.NoError(t, err)
+	require.False(t, outdated)
+
+	// the alias now points to a new key
+	client.aliasARN = "arn:aws:kms:eu-north-1:111122223333:key/second"
+
+	outdated, err = p.Outdated(ctx, encrypted)
+	require.NoError(t, err)
+	require.True(t, outdated)
+
+	decrypted, err := p.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data key"), decrypted)
+}
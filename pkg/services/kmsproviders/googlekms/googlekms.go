@@ -0,0 +1,94 @@
+package googlekms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	gax "github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Kind is the kind of the Google Cloud KMS providers, configured in the [security.encryption.googlekms.<key-name>] sections.
+const Kind = "googlekms"
+
+type client interface {
+	Encrypt(ctx context.Context, req *kmspb.EncryptRequest, opts ...gax.CallOption) (*kmspb.EncryptResponse, error)
+	Decrypt(ctx context.Context, req *kmspb.DecryptRequest, opts ...gax.CallOption) (*kmspb.DecryptResponse, error)
+}
+
+type provider struct {
+	client  client
+	keyName string
+}
+
+// New returns a provider encrypting the data keys with a Google Cloud KMS key. Without a credentials file,
+// the application default credentials are used, e.g. the service account of the workload.
+func New(ctx context.Context, section setting.Section) (secrets.Provider, error) {
+	keyName, err := keyName(section)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []option.ClientOption
+	if file := section.KeyValue("credentials_file").Value(); file != "" {
+		opts = append(opts, option.WithCredentialsFile(file))
+	}
+	c, err := kms.NewKeyManagementClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &provider{client: c, keyName: keyName}, nil
+}
+
+// keyName returns the resource name of the key: key_id is either the resource name, or the name of the key
+// in the key ring of the project and location.
+func keyName(section setting.Section) (string, error) {
+	keyID := section.KeyValue("key_id").Value()
+	if keyID == "" {
+		return "", errors.New("key_id is required")
+	}
+	if strings.HasPrefix(keyID, "projects/") {
+		return keyID, nil
+	}
+
+	project := section.KeyValue("project").Value()
+	location := section.KeyValue("location").MustString("global")
+	keyRing := section.KeyValue("key_ring").Value()
+	if project == "" || keyRing == "" {
+		return "", errors.New("project and key_ring are required unless key_id is the resource name of the key")
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s", project, location, keyRing, keyID), nil
+}
+
+func (p *provider) Encrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	resp, err := p.client.Encrypt(ctx, &kmspb.EncryptRequest{Name: p.keyName, Plaintext: blob})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+func (p *provider) Decrypt(ctx context.Context, blob []byte) ([]byte, error) {
+	resp, err := p.client.Decrypt(ctx, &kmspb.DecryptRequest{Name: p.keyName, Ciphertext: blob})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// Outdated returns true if the blob was not encrypted with the primary version of the key, i.e. the key was rotated.
+func (p *provider) Outdated(ctx context.Context, blob []byte) (bool, error) {
+	resp, err := p.client.Decrypt(ctx, &kmspb.DecryptRequest{Name: p.keyName, Ciphertext: blob})
+	if err != nil {
+		return false, err
+	}
+	return !resp.UsedPrimary, nil
+}
@@ -0,0 +1,81 @@
+package googlekms
+
+import (
+	"context"
+	"testing"
+
+	gax "github.com/googleapis/gax-go/v2"
+	"github.com/stretchr/testify/require"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fakeClient prefixes the ciphertexts with the version of the key.
+type fakeClient struct {
+	primary byte
+}
+
+func (f *fakeClient) Encrypt(_ context.Context, req *kmspb.EncryptRequest, _ ...gax.CallOption) (*kmspb.EncryptResponse, error) {
+	return &kmspb.EncryptResponse{Ciphertext: append([]byte{f.primary}, req.Plaintext...)}, nil
+}
+
+func (f *fakeClient) Decrypt(_ context.Context, req *kmspb.DecryptRequest, _ ...gax.CallOption) (*kmspb.DecryptResponse, error) {
+	return &kmspb.DecryptResponse{Plaintext: req.Ciphertext[1:], UsedPrimary: req.Ciphertext[0] == f.primary}, nil
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeClient{primary: 1}
+	p := &provider{client: client, keyName: "projects/grafana/locations/global/keyRings/grafana/cryptoKeys/secrets"}
+
+	encrypted, err := p.Encrypt(ctx, []byte("data key"))
+	require.NoError(t, err)
+
+	outdated, err := p.Outdated(ctx, encrypted)
+	require.NoError(t, err)
+	require.False(t, outdated)
+
+	// the key is rotated
+	client.primary = 2
+
+	outdated, err = p.Outdated(ctx, encrypted)
+	require.NoError(t, err)
+	require.True(t, outdated)
+
+	decrypted, err := p.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data key"), decrypted)
+}
+
+func TestKeyName(t *testing.T) {
+	section := func(t *testing.T, cfg string) setting.Section {
+		raw, err := ini.Load([]byte(cfg))
+		require.NoError(t, err)
+		return (&setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}).Section("security.encryption.googlekms.v1")
+	}
+
+	name, err := keyName(section(t, `
+		[security.encryption.googlekms.v1]
+		key_id = projects/grafana/locations/europe-west1/keyRings/grafana/cryptoKeys/secrets
+		`))
+	require.NoError(t, err)
+	require.Equal(t, "projects/grafana/locations/europe-west1/keyRings/grafana/cryptoKeys/secrets", name)
+
+	name, err = keyName(section(t, `
+		[security.encryption.googlekms.v1]
+		key_id = secrets
+		project = grafana
+		location = europe-west1
+		key_ring = grafana
+		`))
+	require.NoError(t, err)
+	require.Equal(t, "projects/grafana/locations/europe-west1/keyRings/grafana/cryptoKeys/secrets", name)
+
+	_, err = keyName(section(t, `
+		[security.encryption.googlekms.v1]
+		key_id = secrets
+		`))
+	require.Error(t, err)
+}
@@ -1,18 +1,26 @@
 package osskmsproviders
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/kmsproviders"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/awskms"
 	grafana "github.com/grafana/grafana/pkg/services/kmsproviders/defaultprovider"
+	"github.com/grafana/grafana/pkg/services/kmsproviders/googlekms"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 type Service struct {
 	enc      encryption.Internal
 	settings setting.Provider
 	features featuremgmt.FeatureToggles
+	log      log.Logger
 }
 
 func ProvideService(enc encryption.Internal, settings setting.Provider, features featuremgmt.FeatureToggles) Service {
@@ -20,11 +28,43 @@ func ProvideService(enc encryption.Internal, settings setting.Provider, features
 		enc:      enc,
 		settings: settings,
 		features: features,
+		log:      log.New("kmsproviders"),
 	}
 }
 
+// Provide returns the default provider and the KMS providers listed in available_encryption_providers,
+// each configured in its [security.encryption.<kind>.<key-name>] section.
 func (s Service) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
-	return map[secrets.ProviderID]secrets.Provider{
+	providers := map[secrets.ProviderID]secrets.Provider{
 		kmsproviders.Default: grafana.New(s.settings, s.enc),
-	}, nil
+	}
+
+	for _, id := range util.SplitString(s.settings.KeyValue("security", "available_encryption_providers").Value()) {
+		providerID := kmsproviders.NormalizeProviderID(secrets.ProviderID(id))
+		if providerID == kmsproviders.Default {
+			continue
+		}
+		kind, err := providerID.Kind()
+		if err != nil {
+			return nil, err
+		}
+
+		section := s.settings.Section("security.encryption." + string(providerID))
+		var provider secrets.Provider
+		switch kind {
+		case awskms.Kind:
+			provider, err = awskms.New(section)
+		case googlekms.Kind:
+			provider, err = googlekms.New(context.Background(), section)
+		default:
+			s.log.Warn("Encryption provider not supported", "provider", providerID)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure encryption provider %s: %w", providerID, err)
+		}
+		providers[providerID] = provider
+	}
+
+	return providers, nil
 }
@@ -0,0 +1,46 @@
+package features
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/services/live/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// NotificationsHandler manages the `grafana/notifications/*` channels the notification center pushes the new
+// notifications to: `user/<userId>` for the notifications of a user, and `announcements` for the announcements
+// to the users of the org. The messages are only published by the server.
+type NotificationsHandler struct{}
+
+// GetHandlerForPath called on init
+func (h *NotificationsHandler) GetHandlerForPath(_ string) (model.ChannelHandler, error) {
+	return h, nil // all the paths share the same handler
+}
+
+// OnSubscribe lets the users subscribe to their own notifications, and to the announcements
+func (h *NotificationsHandler) OnSubscribe(_ context.Context, u *user.SignedInUser, e model.SubscribeEvent) (model.SubscribeReply, backend.SubscribeStreamStatus, error) {
+	if e.Path == "announcements" {
+		return model.SubscribeReply{}, backend.SubscribeStreamStatusOK, nil
+	}
+
+	parts := strings.Split(e.Path, "/")
+	if len(parts) == 2 && parts[0] == "user" {
+		userID, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || u.IsAnonymous || userID != u.UserID {
+			return model.SubscribeReply{}, backend.SubscribeStreamStatusPermissionDenied, nil
+		}
+		return model.SubscribeReply{}, backend.SubscribeStreamStatusOK, nil
+	}
+
+	logger.Error("Unknown notifications channel", "path", e.Path)
+	return model.SubscribeReply{}, backend.SubscribeStreamStatusNotFound, nil
+}
+
+// OnPublish is not allowed, the notifications are sent through the notification center
+func (h *NotificationsHandler) OnPublish(_ context.Context, _ *user.SignedInUser, _ model.PublishEvent) (model.PublishReply, backend.PublishStreamStatus, error) {
+	return model.PublishReply{}, backend.PublishStreamStatusPermissionDenied, nil
+}
@@ -0,0 +1,44 @@
+package features
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/live/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestNotificationsHandler_OnSubscribe(t *testing.T) {
+	h := &NotificationsHandler{}
+	usr := &user.SignedInUser{UserID: 2, OrgID: 1}
+
+	tests := []struct {
+		path   string
+		user   *user.SignedInUser
+		status backend.SubscribeStreamStatus
+	}{
+		{path: "user/2", user: usr, status: backend.SubscribeStreamStatusOK},
+		{path: "user/3", user: usr, status: backend.SubscribeStreamStatusPermissionDenied},
+		{path: "user/0", user: &user.SignedInUser{OrgID: 1, IsAnonymous: true}, status: backend.SubscribeStreamStatusPermissionDenied},
+		{path: "user/abc", user: usr, status: backend.SubscribeStreamStatusPermissionDenied},
+		{path: "announcements", user: usr, status: backend.SubscribeStreamStatusOK},
+		{path: "unknown", user: usr, status: backend.SubscribeStreamStatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, status, err := h.OnSubscribe(context.Background(), tt.user, model.SubscribeEvent{Channel: "grafana/notifications/" + tt.path, Path: tt.path})
+			require.NoError(t, err)
+			require.Equal(t, tt.status, status)
+		})
+	}
+}
+
+func TestNotificationsHandler_OnPublish(t *testing.T) {
+	h := &NotificationsHandler{}
+	_, status, err := h.OnPublish(context.Background(), &user.SignedInUser{UserID: 2}, model.PublishEvent{Path: "user/2"})
+	require.NoError(t, err)
+	require.Equal(t, backend.PublishStreamStatusPermissionDenied, status)
+}
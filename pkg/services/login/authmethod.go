@@ -0,0 +1,70 @@
+package login
+
+import (
+	"strings"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Authentication methods of the allowed_auth_methods setting, the OAuth providers are also named by their module.
+const (
+	BasicAuthMethod     = "basic"
+	LDAPAuthMethod      = "ldap"
+	SAMLAuthMethod      = "saml"
+	JWTAuthMethod       = "jwt"
+	AuthProxyAuthMethod = "authproxy"
+	OAuthAuthMethod     = "oauth"
+
+	oauthModulePrefix = "oauth_"
+)
+
+var ErrAuthMethodNotAllowed = errutil.NewBase(errutil.StatusForbidden, "auth.method-not-allowed").MustTemplate(
+	"authentication method {{ .Public.method }} not allowed in the organization",
+	errutil.WithPublic("Signing in with {{ .Public.method }} is not allowed in this organization, sign in with: {{ .Public.allowed }}"),
+)
+
+// AuthMethod returns the authentication method of an auth module, the Grafana logins and the sessions created before
+// the auth module was stored are basic.
+func AuthMethod(authModule string) string {
+	switch authModule {
+	case "", "grafana", "password":
+		return BasicAuthMethod
+	case SAMLAuthModule:
+		return SAMLAuthMethod
+	}
+	return authModule
+}
+
+// IsAuthMethod reports whether the name is an authentication method of the allowed_auth_methods setting.
+func IsAuthMethod(name string) bool {
+	switch name {
+	case BasicAuthMethod, LDAPAuthMethod, SAMLAuthMethod, JWTAuthMethod, AuthProxyAuthMethod, OAuthAuthMethod:
+		return true
+	}
+	return strings.HasPrefix(name, oauthModulePrefix) && len(name) > len(oauthModulePrefix)
+}
+
+// AuthMethodAllowed reports whether the auth module is one of the allowed methods, oauth allowing all the OAuth
+// providers. All the methods are allowed when the list is empty.
+func AuthMethodAllowed(allowed []string, authModule string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	method := AuthMethod(authModule)
+	for _, a := range allowed {
+		if a == method || (a == OAuthAuthMethod && strings.HasPrefix(method, oauthModulePrefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAuthMethod returns ErrAuthMethodNotAllowed, naming the allowed methods, when the auth module is not one of them.
+func CheckAuthMethod(allowed []string, authModule string) error {
+	if AuthMethodAllowed(allowed, authModule) {
+		return nil
+	}
+	return ErrAuthMethodNotAllowed.Build(errutil.TemplateData{
+		Public: map[string]interface{}{"method": AuthMethod(authModule), "allowed": strings.Join(allowed, ", ")},
+	})
+}
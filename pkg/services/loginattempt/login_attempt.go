@@ -7,11 +7,13 @@ import (
 type Service interface {
 	// Add adds a new login attempt record for provided username
 	Add(ctx context.Context, username, IPAddress string) error
-	// Validate checks if username has to many login attempts inside a window.
-	// Will return true if provided username do not have too many attempts.
-	Validate(ctx context.Context, username string) (bool, error)
+	// Validate checks if username or IP address are locked out after too many login attempts.
+	// Will return true if provided username and IP address are not locked out.
+	Validate(ctx context.Context, username, IPAddress string) (bool, error)
 	// Reset resets all login attempts attached to username
 	Reset(ctx context.Context, username string) error
+	// ResetIP resets all login attempts made from IP address
+	ResetIP(ctx context.Context, IPAddress string) error
 }
 
 type LoginAttempt struct {
@@ -4,48 +4,29 @@ import (
 	"context"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
-	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-const (
-	maxInvalidLoginAttempts int64 = 5
-	loginAttemptsWindow           = time.Minute * 5
-)
-
-func ProvideService(db db.DB, cfg *setting.Cfg, lock *serverlock.ServerLockService) *Service {
+func ProvideService(db db.DB, cfg *setting.Cfg, registerer prometheus.Registerer) *Service {
 	return &Service{
-		&xormStore{db: db, now: time.Now},
-		cfg,
-		lock,
-		log.New("login_attempt"),
+		store:   &xormStore{db: db, now: time.Now},
+		cfg:     cfg,
+		logger:  log.New("login_attempt"),
+		now:     time.Now,
+		metrics: newMetrics(registerer),
 	}
 }
 
 type Service struct {
-	store  store
-	cfg    *setting.Cfg
-	lock   *serverlock.ServerLockService
-	logger log.Logger
-}
-
-func (s *Service) Run(ctx context.Context) error {
-	// no need to run clean up job if it is disabled
-	if s.cfg.DisableBruteForceLoginProtection {
-		return nil
-	}
-
-	ticker := time.NewTicker(time.Minute * 10)
-	for {
-		select {
-		case <-ticker.C:
-			s.cleanup(ctx)
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-	}
+	store   store
+	cfg     *setting.Cfg
+	logger  log.Logger
+	now     func() time.Time
+	metrics *metrics
 }
 
 func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
@@ -53,51 +34,110 @@ func (s *Service) Add(ctx context.Context, username, IPAddress string) error {
 		return nil
 	}
 
-	return s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
+	err := s.store.CreateLoginAttempt(ctx, CreateLoginAttemptCommand{
 		Username:  username,
 		IpAddress: IPAddress,
 	})
+	if err != nil {
+		return err
+	}
+
+	// every failed login attempt above a threshold starts a new, longer, lockout
+	policy := s.cfg.BruteForceLoginProtection
+	if policy.MaxAttempts > 0 {
+		stats, err := s.stats(ctx, GetLoginAttemptStatsQuery{Username: username})
+		if err != nil {
+			return err
+		}
+		if stats.Count >= policy.MaxAttempts {
+			s.metrics.lockouts.WithLabelValues(lockoutTypeUser).Inc()
+		}
+	}
+	if policy.MaxAttemptsPerIP > 0 && IPAddress != "" {
+		stats, err := s.stats(ctx, GetLoginAttemptStatsQuery{IpAddress: IPAddress})
+		if err != nil {
+			return err
+		}
+		if stats.Count >= policy.MaxAttemptsPerIP {
+			s.metrics.lockouts.WithLabelValues(lockoutTypeIP).Inc()
+		}
+	}
+
+	return nil
 }
 
 func (s *Service) Reset(ctx context.Context, username string) error {
-	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{username})
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{Username: username})
 }
 
-func (s *Service) Validate(ctx context.Context, username string) (bool, error) {
+func (s *Service) ResetIP(ctx context.Context, IPAddress string) error {
+	return s.store.DeleteLoginAttempts(ctx, DeleteLoginAttemptsCommand{IpAddress: IPAddress})
+}
+
+func (s *Service) Validate(ctx context.Context, username, IPAddress string) (bool, error) {
 	if s.cfg.DisableBruteForceLoginProtection {
 		return true, nil
 	}
 
-	loginAttemptCountQuery := GetUserLoginAttemptCountQuery{
-		Username: username,
-		Since:    time.Now().Add(-loginAttemptsWindow),
+	policy := s.cfg.BruteForceLoginProtection
+	if policy.MaxAttempts > 0 {
+		locked, err := s.isLockedOut(ctx, GetLoginAttemptStatsQuery{Username: username}, policy.MaxAttempts)
+		if err != nil {
+			return false, err
+		}
+		if locked {
+			s.metrics.blocked.WithLabelValues(lockoutTypeUser).Inc()
+			return false, nil
+		}
+	}
+	if policy.MaxAttemptsPerIP > 0 && IPAddress != "" {
+		locked, err := s.isLockedOut(ctx, GetLoginAttemptStatsQuery{IpAddress: IPAddress}, policy.MaxAttemptsPerIP)
+		if err != nil {
+			return false, err
+		}
+		if locked {
+			s.metrics.blocked.WithLabelValues(lockoutTypeIP).Inc()
+			return false, nil
+		}
 	}
 
-	count, err := s.store.GetUserLoginAttemptCount(ctx, loginAttemptCountQuery)
+	return true, nil
+}
+
+// isLockedOut returns whether the latest failed login attempt is in a lockout. The first lockout starts when the
+// number of failed login attempts reaches maxAttempts and its duration doubles with every failed login attempt after
+// it, up to the maximum lockout duration.
+func (s *Service) isLockedOut(ctx context.Context, query GetLoginAttemptStatsQuery, maxAttempts int64) (bool, error) {
+	stats, err := s.stats(ctx, query)
 	if err != nil {
 		return false, err
 	}
-
-	if count >= maxInvalidLoginAttempts {
+	if stats.Count < maxAttempts {
 		return false, nil
 	}
 
-	return true, nil
+	return s.now().Before(stats.Latest.Add(lockoutDuration(s.cfg.BruteForceLoginProtection, stats.Count-maxAttempts))), nil
 }
 
-func (s *Service) cleanup(ctx context.Context) {
-	err := s.lock.LockAndExecute(ctx, "delete old login attempts", time.Minute*10, func(context.Context) {
-		cmd := DeleteOldLoginAttemptsCommand{
-			OlderThan: time.Now().Add(time.Minute * -10),
-		}
-		if deletedLogs, err := s.store.DeleteOldLoginAttempts(ctx, cmd); err != nil {
-			s.logger.Error("Problem deleting expired login attempts", "error", err.Error())
-		} else {
-			s.logger.Debug("Deleted expired login attempts", "rows affected", deletedLogs)
-		}
-	})
+// stats returns the failed login attempts that can still cause a lockout.
+func (s *Service) stats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	query.Since = s.now().Add(-s.cfg.BruteForceLoginProtection.MaxLockoutDuration)
+	return s.store.GetLoginAttemptStats(ctx, query)
+}
 
-	if err != nil {
-		s.logger.Error("failed to lock and execute cleanup of old login attempts", "error", err)
+func lockoutDuration(policy setting.BruteForceLoginProtectionSettings, extraAttempts int64) time.Duration {
+	duration := policy.LockoutDuration
+	for i := int64(0); i < extraAttempts && duration < policy.MaxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > policy.MaxLockoutDuration {
+		return policy.MaxLockoutDuration
 	}
+	return duration
+}
+
+// DeleteOldLoginAttempts deletes the login attempts made before olderThan, it is run by the cleanup service
+// according to its login attempts retention policy.
+func (s *Service) DeleteOldLoginAttempts(ctx context.Context, olderThan time.Time) (int64, error) {
+	return s.store.DeleteOldLoginAttempts(ctx, DeleteOldLoginAttemptsCommand{OlderThan: olderThan})
 }
@@ -3,59 +3,78 @@ package loginattemptimpl
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+var testPolicy = setting.BruteForceLoginProtectionSettings{
+	MaxAttempts:        5,
+	MaxAttemptsPerIP:   20,
+	LockoutDuration:    5 * time.Minute,
+	MaxLockoutDuration: time.Hour,
+}
+
 func TestService_Validate(t *testing.T) {
+	now := time.Unix(1680000000, 0)
+
 	testCases := []struct {
 		name          string
 		loginAttempts int64
+		latest        time.Time
+		ipAddress     string
 		disabled      bool
 		expected      bool
 		expectedErr   error
 	}{
 		{
 			name:          "When brute force protection enabled and user login attempt count is less than max",
-			loginAttempts: maxInvalidLoginAttempts - 1,
+			loginAttempts: testPolicy.MaxAttempts - 1,
+			latest:        now,
 			expected:      true,
-			expectedErr:   nil,
 		},
 		{
 			name:          "When brute force protection enabled and user login attempt count equals max",
-			loginAttempts: maxInvalidLoginAttempts,
+			loginAttempts: testPolicy.MaxAttempts,
+			latest:        now.Add(-time.Minute),
 			expected:      false,
-			expectedErr:   nil,
 		},
 		{
-			name:          "When brute force protection enabled and user login attempt count is greater than max",
-			loginAttempts: maxInvalidLoginAttempts + 1,
+			name:          "When brute force protection enabled and the first lockout is over",
+			loginAttempts: testPolicy.MaxAttempts,
+			latest:        now.Add(-6 * time.Minute),
+			expected:      true,
+		},
+		{
+			name:          "When brute force protection enabled and the lockout doubled after another login attempt",
+			loginAttempts: testPolicy.MaxAttempts + 1,
+			latest:        now.Add(-6 * time.Minute),
 			expected:      false,
-			expectedErr:   nil,
 		},
-
 		{
-			name:          "When brute force protection disabled and user login attempt count is less than max",
-			loginAttempts: maxInvalidLoginAttempts - 1,
-			disabled:      true,
+			name:          "When brute force protection enabled and the lockout reached the max duration",
+			loginAttempts: testPolicy.MaxAttempts + 100,
+			latest:        now.Add(-61 * time.Minute),
 			expected:      true,
-			expectedErr:   nil,
 		},
 		{
-			name:          "When brute force protection disabled and user login attempt count equals max",
-			loginAttempts: maxInvalidLoginAttempts,
-			disabled:      true,
-			expected:      true,
-			expectedErr:   nil,
+			name:          "When brute force protection enabled and IP address login attempt count equals max",
+			loginAttempts: testPolicy.MaxAttemptsPerIP,
+			latest:        now,
+			ipAddress:     "192.168.0.1",
+			expected:      false,
 		},
 		{
 			name:          "When brute force protection disabled and user login attempt count is greater than max",
-			loginAttempts: maxInvalidLoginAttempts + 1,
+			loginAttempts: testPolicy.MaxAttempts + 1,
+			latest:        now,
 			disabled:      true,
 			expected:      true,
-			expectedErr:   nil,
 		},
 	}
 
@@ -63,31 +82,65 @@ func TestService_Validate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.DisableBruteForceLoginProtection = tt.disabled
+			cfg.BruteForceLoginProtection = testPolicy
+
+			stats := map[string]LoginAttemptStats{"test": {Count: tt.loginAttempts, Latest: tt.latest}}
+			if tt.ipAddress != "" {
+				stats = map[string]LoginAttemptStats{tt.ipAddress: {Count: tt.loginAttempts, Latest: tt.latest}}
+			}
 			service := &Service{
-				store: fakeStore{
-					ExpectedCount: tt.loginAttempts,
-					ExpectedErr:   tt.expectedErr,
-				},
-				cfg: cfg,
+				store:   fakeStore{ExpectedStats: stats},
+				cfg:     cfg,
+				now:     func() time.Time { return now },
+				metrics: newMetrics(prometheus.NewRegistry()),
 			}
 
-			ok, err := service.Validate(context.Background(), "test")
+			ok, err := service.Validate(context.Background(), "test", tt.ipAddress)
 			assert.Equal(t, tt.expected, ok)
 			assert.Equal(t, tt.expectedErr, err)
 		})
 	}
 }
 
+func TestService_Add(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.BruteForceLoginProtection = testPolicy
+	service := &Service{
+		store: fakeStore{ExpectedStats: map[string]LoginAttemptStats{
+			"test":        {Count: testPolicy.MaxAttempts},
+			"192.168.0.1": {Count: 1},
+		}},
+		cfg:     cfg,
+		now:     time.Now,
+		metrics: newMetrics(prometheus.NewRegistry()),
+	}
+
+	require.NoError(t, service.Add(context.Background(), "test", "192.168.0.1"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(service.metrics.lockouts.WithLabelValues(lockoutTypeUser)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(service.metrics.lockouts.WithLabelValues(lockoutTypeIP)))
+}
+
+func TestLockoutDuration(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, lockoutDuration(testPolicy, 0))
+	assert.Equal(t, 10*time.Minute, lockoutDuration(testPolicy, 1))
+	assert.Equal(t, 40*time.Minute, lockoutDuration(testPolicy, 3))
+	assert.Equal(t, time.Hour, lockoutDuration(testPolicy, 4))
+	assert.Equal(t, time.Hour, lockoutDuration(testPolicy, 1000))
+}
+
 var _ store = new(fakeStore)
 
 type fakeStore struct {
 	ExpectedErr         error
-	ExpectedCount       int64
+	ExpectedStats       map[string]LoginAttemptStats
 	ExpectedDeletedRows int64
 }
 
-func (f fakeStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
-	return f.ExpectedCount, f.ExpectedErr
+func (f fakeStore) GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	if query.IpAddress != "" {
+		return f.ExpectedStats[query.IpAddress], f.ExpectedErr
+	}
+	return f.ExpectedStats[query.Username], f.ExpectedErr
 }
 
 func (f fakeStore) CreateLoginAttempt(ctx context.Context, command CreateLoginAttemptCommand) error {
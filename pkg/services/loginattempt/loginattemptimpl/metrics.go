@@ -0,0 +1,33 @@
+package loginattemptimpl
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	lockoutTypeUser = "user"
+	lockoutTypeIP   = "ip"
+)
+
+type metrics struct {
+	lockouts *prometheus.CounterVec
+	blocked  *prometheus.CounterVec
+}
+
+func newMetrics(registerer prometheus.Registerer) *metrics {
+	return &metrics{
+		lockouts: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "login_attempt",
+			Name:      "lockouts_total",
+			Help:      "Number of lockouts started by a failed login attempt, by type of lockout.",
+		}, []string{"type"}),
+		blocked: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "login_attempt",
+			Name:      "blocked_total",
+			Help:      "Number of login attempts blocked by a lockout, by type of lockout.",
+		}, []string{"type"}),
+	}
+}
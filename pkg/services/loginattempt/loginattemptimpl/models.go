@@ -13,15 +13,25 @@ type CreateLoginAttemptCommand struct {
 	Result loginattempt.LoginAttempt
 }
 
-type GetUserLoginAttemptCountQuery struct {
-	Username string
-	Since    time.Time
+// GetLoginAttemptStatsQuery filters the login attempts by username, or by IP address when it is set.
+type GetLoginAttemptStatsQuery struct {
+	Username  string
+	IpAddress string
+	Since     time.Time
+}
+
+type LoginAttemptStats struct {
+	Count int64
+	// Latest is the time of the latest login attempt
+	Latest time.Time
 }
 
 type DeleteOldLoginAttemptsCommand struct {
 	OlderThan time.Time
 }
 
+// DeleteLoginAttemptsCommand deletes the login attempts of a username, or of an IP address when it is set.
 type DeleteLoginAttemptsCommand struct {
-	Username string
+	Username  string
+	IpAddress string
 }
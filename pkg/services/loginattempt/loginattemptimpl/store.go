@@ -17,7 +17,7 @@ type store interface {
 	CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) error
 	DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLoginAttemptsCommand) (int64, error)
 	DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error
-	GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error)
+	GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error)
 }
 
 func (xs *xormStore) CreateLoginAttempt(ctx context.Context, cmd CreateLoginAttemptCommand) error {
@@ -57,27 +57,33 @@ func (xs *xormStore) DeleteOldLoginAttempts(ctx context.Context, cmd DeleteOldLo
 
 func (xs *xormStore) DeleteLoginAttempts(ctx context.Context, cmd DeleteLoginAttemptsCommand) error {
 	return xs.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if cmd.IpAddress != "" {
+			_, err := sess.Exec("DELETE FROM login_attempt WHERE ip_address = ?", cmd.IpAddress)
+			return err
+		}
 		_, err := sess.Exec("DELETE FROM login_attempt WHERE username = ?", cmd.Username)
 		return err
 	})
 }
 
-func (xs *xormStore) GetUserLoginAttemptCount(ctx context.Context, query GetUserLoginAttemptCountQuery) (int64, error) {
-	var total int64
+func (xs *xormStore) GetLoginAttemptStats(ctx context.Context, query GetLoginAttemptStatsQuery) (LoginAttemptStats, error) {
+	var result struct {
+		Count  int64
+		Latest int64
+	}
 	err := xs.db.WithDbSession(ctx, func(dbSession *db.Session) error {
-		var queryErr error
-		loginAttempt := new(loginattempt.LoginAttempt)
-		total, queryErr = dbSession.
-			Where("username = ?", query.Username).
-			And("created >= ?", query.Since.Unix()).
-			Count(loginAttempt)
-
-		if queryErr != nil {
-			return queryErr
+		sess := dbSession.Table("login_attempt").Select("COUNT(*) AS count, COALESCE(MAX(created), 0) AS latest")
+		if query.IpAddress != "" {
+			sess.Where("ip_address = ?", query.IpAddress)
+		} else {
+			sess.Where("username = ?", query.Username)
 		}
-
-		return nil
+		_, err := sess.And("created >= ?", query.Since.Unix()).Get(&result)
+		return err
 	})
+	if err != nil {
+		return LoginAttemptStats{}, err
+	}
 
-	return total, err
+	return LoginAttemptStats{Count: result.Count, Latest: time.Unix(result.Latest, 0)}, nil
 }
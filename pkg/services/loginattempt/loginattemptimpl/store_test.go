@@ -22,29 +22,37 @@ func TestIntegrationLoginAttemptsQuery(t *testing.T) {
 
 	for _, test := range []struct {
 		Name   string
-		Query  GetUserLoginAttemptCountQuery
+		Query  GetLoginAttemptStatsQuery
 		Err    error
 		Result int64
 	}{
 		{
 			"Should return a total count of zero login attempts when comparing since beginning of time + 2min and 1s",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
 		},
 		{
 			"Should return a total count of zero login attempts when comparing since beginning of time + 2min and 1s",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusTwoMinutes.Add(time.Second * 1)}, nil, 0,
 		},
 		{
 			"Should return the total count of login attempts since beginning of time",
-			GetUserLoginAttemptCountQuery{Username: user, Since: beginningOfTime}, nil, 3,
+			GetLoginAttemptStatsQuery{Username: user, Since: beginningOfTime}, nil, 3,
 		},
 		{
 			"Should return the total count of login attempts since beginning of time + 1min",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusOneMinute}, nil, 2,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusOneMinute}, nil, 2,
 		},
 		{
 			"Should return the total count of login attempts since beginning of time + 2min",
-			GetUserLoginAttemptCountQuery{Username: user, Since: timePlusTwoMinutes}, nil, 1,
+			GetLoginAttemptStatsQuery{Username: user, Since: timePlusTwoMinutes}, nil, 1,
+		},
+		{
+			"Should return the total count of login attempts of an IP address since beginning of time",
+			GetLoginAttemptStatsQuery{IpAddress: "192.168.0.1", Since: beginningOfTime}, nil, 3,
+		},
+		{
+			"Should return a total count of zero login attempts of another IP address",
+			GetLoginAttemptStatsQuery{IpAddress: "192.168.0.2", Since: beginningOfTime}, nil, 0,
 		},
 	} {
 		mockTime := beginningOfTime
@@ -73,9 +81,12 @@ func TestIntegrationLoginAttemptsQuery(t *testing.T) {
 		})
 		require.Nil(t, err)
 
-		count, err := s.GetUserLoginAttemptCount(context.Background(), test.Query)
+		stats, err := s.GetLoginAttemptStats(context.Background(), test.Query)
 		require.Equal(t, test.Err, err, test.Name)
-		require.Equal(t, test.Result, count, test.Name)
+		require.Equal(t, test.Result, stats.Count, test.Name)
+		if test.Result > 0 {
+			require.Equal(t, timePlusTwoMinutes.Unix(), stats.Latest.Unix(), test.Name)
+		}
 	}
 }
 
@@ -143,3 +154,33 @@ func TestIntegrationLoginAttemptsDelete(t *testing.T) {
 		require.Equal(t, test.DeletedRows, deletedRows, test.Name)
 	}
 }
+
+func TestIntegrationLoginAttemptsDeleteByIPAddress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	s := &xormStore{
+		db:  db.InitTestDB(t),
+		now: time.Now,
+	}
+
+	for _, cmd := range []CreateLoginAttemptCommand{
+		{Username: "user", IpAddress: "192.168.0.1"},
+		{Username: "other", IpAddress: "192.168.0.1"},
+		{Username: "user", IpAddress: "192.168.0.2"},
+	} {
+		require.NoError(t, s.CreateLoginAttempt(context.Background(), cmd))
+	}
+
+	err := s.DeleteLoginAttempts(context.Background(), DeleteLoginAttemptsCommand{IpAddress: "192.168.0.1"})
+	require.NoError(t, err)
+
+	since := time.Now().Add(-time.Hour)
+	stats, err := s.GetLoginAttemptStats(context.Background(), GetLoginAttemptStatsQuery{IpAddress: "192.168.0.1", Since: since})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), stats.Count)
+
+	stats, err = s.GetLoginAttemptStats(context.Background(), GetLoginAttemptStatsQuery{Username: "user", Since: since})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), stats.Count)
+}
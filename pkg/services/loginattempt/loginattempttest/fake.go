@@ -21,6 +21,10 @@ func (f FakeLoginAttemptService) Reset(ctx context.Context, username string) err
 	return f.ExpectedErr
 }
 
-func (f FakeLoginAttemptService) Validate(ctx context.Context, username string) (bool, error) {
+func (f FakeLoginAttemptService) ResetIP(ctx context.Context, IPAddress string) error {
+	return f.ExpectedErr
+}
+
+func (f FakeLoginAttemptService) Validate(ctx context.Context, username, IPAddress string) (bool, error) {
 	return f.ExpectedValid, f.ExpectedErr
 }
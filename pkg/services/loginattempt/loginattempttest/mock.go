@@ -11,6 +11,7 @@ var _ loginattempt.Service = new(MockLoginAttemptService)
 type MockLoginAttemptService struct {
 	AddCalled      bool
 	ResetCalled    bool
+	ResetIPCalled  bool
 	ValidateCalled bool
 
 	ExpectedValid bool
@@ -27,7 +28,12 @@ func (f *MockLoginAttemptService) Reset(ctx context.Context, username string) er
 	return f.ExpectedErr
 }
 
-func (f *MockLoginAttemptService) Validate(ctx context.Context, username string) (bool, error) {
+func (f *MockLoginAttemptService) ResetIP(ctx context.Context, IPAddress string) error {
+	f.ResetIPCalled = true
+	return f.ExpectedErr
+}
+
+func (f *MockLoginAttemptService) Validate(ctx context.Context, username, IPAddress string) (bool, error) {
 	f.ValidateCalled = true
 	return f.ExpectedValid, f.ExpectedErr
 }
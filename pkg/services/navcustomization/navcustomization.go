@@ -0,0 +1,123 @@
+package navcustomization
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Service stores the customizations of the navigation tree of the organizations and their teams.
+// The rules of an organization apply to all its users, the rules of a team apply to its members
+// and take precedence over the rules of the organization for the same navigation node.
+type Service interface {
+	// List returns the rules of the organization, teamID 0 lists the rules of all the teams as well.
+	List(ctx context.Context, query *ListRulesQuery) ([]*Rule, error)
+	// GetEffectiveRules returns the rules applying to a member of the given teams, there is at most one rule per navigation node.
+	GetEffectiveRules(ctx context.Context, orgID int64, teamIDs []int64) ([]*Rule, error)
+	// Save creates the rule for the navigation node or replaces the existing one.
+	Save(ctx context.Context, cmd *SaveRuleCommand) (*Rule, error)
+	Delete(ctx context.Context, orgID, ruleID int64) error
+}
+
+var (
+	ErrRuleNotFound = errutil.NewBase(errutil.StatusNotFound, "navcustomization.not-found", errutil.WithPublicMessage("Navigation rule not found"))
+	ErrInvalidRule  = errutil.NewBase(errutil.StatusBadRequest, "navcustomization.invalid-rule")
+)
+
+// RuleType is what a rule does to the navigation tree.
+type RuleType string
+
+const (
+	// RuleTypeHide removes the navigation node and its children.
+	RuleTypeHide RuleType = "hide"
+	// RuleTypeReorder changes the sort weight of the navigation node within its parent.
+	RuleTypeReorder RuleType = "reorder"
+	// RuleTypeLink adds a link to the navigation tree, as a section when it has no parent.
+	RuleTypeLink RuleType = "link"
+)
+
+type Rule struct {
+	ID     int64 `xorm:"pk autoincr 'id'" json:"id"`
+	OrgID  int64 `xorm:"org_id" json:"orgId"`
+	TeamID int64 `xorm:"team_id" json:"teamId"`
+	// NavID is the id of the customized navigation node, or of the added link
+	NavID      string   `xorm:"nav_id" json:"navId"`
+	Type       RuleType `xorm:"type" json:"type"`
+	SortWeight int64    `xorm:"sort_weight" json:"sortWeight,omitempty"`
+	// ParentID, Text, URL, Icon and Target are only used by links
+	ParentID  string    `xorm:"parent_id" json:"parentId,omitempty"`
+	Text      string    `xorm:"text" json:"text,omitempty"`
+	URL       string    `xorm:"url" json:"url,omitempty"`
+	Icon      string    `xorm:"icon" json:"icon,omitempty"`
+	Target    string    `xorm:"target" json:"target,omitempty"`
+	Created   time.Time `xorm:"created" json:"created"`
+	Updated   time.Time `xorm:"updated" json:"updated"`
+	UpdatedBy int64     `xorm:"updated_by" json:"updatedBy"`
+}
+
+func (r Rule) TableName() string {
+	return "nav_customization"
+}
+
+type ListRulesQuery struct {
+	OrgID  int64
+	TeamID int64
+}
+
+type SaveRuleCommand struct {
+	OrgID      int64    `json:"-"`
+	TeamID     int64    `json:"teamId"`
+	NavID      string   `json:"navId"`
+	Type       RuleType `json:"type"`
+	SortWeight int64    `json:"sortWeight"`
+	ParentID   string   `json:"parentId"`
+	Text       string   `json:"text"`
+	URL        string   `json:"url"`
+	Icon       string   `json:"icon"`
+	Target     string   `json:"target"`
+	UpdatedBy  int64    `json:"-"`
+}
+
+const maxNavIDLength = 190
+
+func (cmd *SaveRuleCommand) Validate() error {
+	if cmd.NavID == "" || len(cmd.NavID) > maxNavIDLength {
+		return ErrInvalidRule.Errorf("navId is required and must be at most %d characters long", maxNavIDLength)
+	}
+
+	switch cmd.Type {
+	case RuleTypeHide, RuleTypeReorder:
+		if cmd.ParentID != "" || cmd.Text != "" || cmd.URL != "" || cmd.Icon != "" || cmd.Target != "" {
+			return ErrInvalidRule.Errorf("only links can have a parent, text, url, icon or target")
+		}
+	case RuleTypeLink:
+		if cmd.Text == "" || cmd.URL == "" {
+			return ErrInvalidRule.Errorf("links require a text and an url")
+		}
+		if !isSafeURL(cmd.URL) {
+			return ErrInvalidRule.Errorf("links must be relative or use http or https")
+		}
+		if cmd.Target != "" && cmd.Target != "_blank" && cmd.Target != "_self" {
+			return ErrInvalidRule.Errorf("target must be _blank or _self")
+		}
+	default:
+		return ErrInvalidRule.Errorf("unknown rule type %q, expected one of hide, reorder or link", cmd.Type)
+	}
+
+	return nil
+}
+
+func isSafeURL(link string) bool {
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		// protocol-relative links would point to another host
+		return u.Host == "" && !strings.HasPrefix(link, "//")
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
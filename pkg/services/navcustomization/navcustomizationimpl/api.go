@@ -0,0 +1,76 @@
+package navcustomizationimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/orgs/:orgId/navigation", func(navigation routing.RouteRegister) {
+		navigation.Get("/", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionRead)), routing.Wrap(s.handleList))
+		navigation.Post("/", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleSave))
+		navigation.Delete("/:ruleId", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleDelete))
+	})
+}
+
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+
+	rules, err := s.List(c.Req.Context(), &navcustomization.ListRulesQuery{OrgID: orgID, TeamID: c.QueryInt64("teamId")})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list navigation rules", err)
+	}
+
+	return response.JSON(http.StatusOK, rules)
+}
+
+func (s *Service) handleSave(c *contextmodel.ReqContext) response.Response {
+	cmd := navcustomization.SaveRuleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+	cmd.OrgID = orgID
+	cmd.UpdatedBy = c.UserID
+
+	rule, err := s.Save(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to save navigation rule", err)
+	}
+
+	return response.JSON(http.StatusOK, rule)
+}
+
+func (s *Service) handleDelete(c *contextmodel.ReqContext) response.Response {
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+	ruleID, err := strconv.ParseInt(web.Params(c.Req)[":ruleId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "ruleId is invalid", err)
+	}
+
+	if err := s.Delete(c.Req.Context(), orgID, ruleID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete navigation rule", err)
+	}
+
+	return response.Success("Navigation rule deleted")
+}
@@ -0,0 +1,33 @@
+package navcustomizationimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionRead  = "orgs.navigation:read"
+	ActionWrite = "orgs.navigation:write"
+)
+
+var (
+	navigationReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:orgs.navigation:reader",
+		DisplayName: "Navigation customization reader",
+		Description: "Read the navigation customizations of the organizations and their teams",
+		Group:       "Organizations",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+		},
+	}
+
+	navigationWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:orgs.navigation:writer",
+		DisplayName: "Navigation customization writer",
+		Description: "Read, create, update and delete the navigation customizations of the organizations and their teams",
+		Group:       "Organizations",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+			{Action: ActionWrite},
+		},
+	}
+)
@@ -0,0 +1,145 @@
+package navcustomizationimpl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+// cacheTTL bounds how long an instance can serve stale rules after
+// they were changed through another instance.
+const cacheTTL = 30 * time.Second
+
+type Service struct {
+	store         store
+	cache         *localcache.CacheService
+	accessControl ac.AccessControl
+	teamService   team.Service
+	log           log.Logger
+}
+
+var _ navcustomization.Service = (*Service)(nil)
+
+func ProvideService(
+	sql db.DB,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	teamService team.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	s := &Service{
+		store:         &sqlStore{db: sql, now: time.Now},
+		cache:         localcache.New(cacheTTL, 2*cacheTTL),
+		accessControl: accessControl,
+		teamService:   teamService,
+		log:           log.New("navcustomization"),
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := accesscontrolService.DeclareFixedRoles(
+			ac.RoleRegistration{Role: navigationReaderRole, Grants: []string{ac.RoleGrafanaAdmin}},
+			ac.RoleRegistration{Role: navigationWriterRole, Grants: []string{ac.RoleGrafanaAdmin}},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) List(ctx context.Context, query *navcustomization.ListRulesQuery) ([]*navcustomization.Rule, error) {
+	return s.store.List(ctx, query)
+}
+
+func (s *Service) GetEffectiveRules(ctx context.Context, orgID int64, teamIDs []int64) ([]*navcustomization.Rule, error) {
+	rules, err := s.getRules(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember := make(map[int64]bool, len(teamIDs))
+	for _, id := range teamIDs {
+		isMember[id] = true
+	}
+
+	// rules are sorted by team, the rules of the teams replace the rules of the organization
+	effective := make(map[string]*navcustomization.Rule)
+	order := make([]string, 0)
+	for _, r := range rules {
+		if r.TeamID != 0 && !isMember[r.TeamID] {
+			continue
+		}
+		if _, ok := effective[r.NavID]; !ok {
+			order = append(order, r.NavID)
+		}
+		effective[r.NavID] = r
+	}
+
+	result := make([]*navcustomization.Rule, 0, len(order))
+	for _, navID := range order {
+		result = append(result, effective[navID])
+	}
+	return result, nil
+}
+
+func (s *Service) Save(ctx context.Context, cmd *navcustomization.SaveRuleCommand) (*navcustomization.Rule, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cmd.TeamID != 0 {
+		if _, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: cmd.OrgID, ID: cmd.TeamID}); err != nil {
+			return nil, navcustomization.ErrInvalidRule.Errorf("team %d not found in org %d: %w", cmd.TeamID, cmd.OrgID, err)
+		}
+	}
+
+	rule, err := s.store.Save(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Delete(cacheKey(cmd.OrgID))
+
+	s.log.Info("Navigation rule saved", "orgId", cmd.OrgID, "teamId", cmd.TeamID, "navId", cmd.NavID, "type", cmd.Type, "userId", cmd.UpdatedBy)
+	return rule, nil
+}
+
+func (s *Service) Delete(ctx context.Context, orgID, ruleID int64) error {
+	if err := s.store.Delete(ctx, orgID, ruleID); err != nil {
+		return err
+	}
+	s.cache.Delete(cacheKey(orgID))
+
+	s.log.Info("Navigation rule deleted", "orgId", orgID, "ruleId", ruleID)
+	return nil
+}
+
+// getRules returns all the rules of the organization sorted by team, the rules of the organization first.
+func (s *Service) getRules(ctx context.Context, orgID int64) ([]*navcustomization.Rule, error) {
+	if cached, ok := s.cache.Get(cacheKey(orgID)); ok {
+		return cached.([]*navcustomization.Rule), nil
+	}
+
+	rules, err := s.store.List(ctx, &navcustomization.ListRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].TeamID < rules[j].TeamID })
+	s.cache.Set(cacheKey(orgID), rules, cacheTTL)
+
+	return rules, nil
+}
+
+func cacheKey(orgID int64) string {
+	return fmt.Sprintf("navcustomization-%d", orgID)
+}
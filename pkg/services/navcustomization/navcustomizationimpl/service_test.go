@@ -0,0 +1,120 @@
+package navcustomizationimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+)
+
+func TestIntegrationNavCustomization(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	teamService := &teamtest.FakeService{ExpectedTeamDTO: &team.TeamDTO{ID: 1}}
+	s := &Service{
+		store:       &sqlStore{db: testDB, now: time.Now},
+		cache:       localcache.New(cacheTTL, 2*cacheTTL),
+		teamService: teamService,
+		log:         log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	t.Run("should update the rule of the same nav id", func(t *testing.T) {
+		_, err := s.Save(ctx, &navcustomization.SaveRuleCommand{OrgID: 1, NavID: "explore", Type: navcustomization.RuleTypeHide})
+		require.NoError(t, err)
+		rule, err := s.Save(ctx, &navcustomization.SaveRuleCommand{OrgID: 1, NavID: "explore", Type: navcustomization.RuleTypeReorder, SortWeight: -100})
+		require.NoError(t, err)
+
+		rules, err := s.List(ctx, &navcustomization.ListRulesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule.ID, rules[0].ID)
+		require.Equal(t, navcustomization.RuleTypeReorder, rules[0].Type)
+		require.Equal(t, int64(-100), rules[0].SortWeight)
+	})
+
+	t.Run("should prefer the rules of the teams of the user", func(t *testing.T) {
+		_, err := s.Save(ctx, &navcustomization.SaveRuleCommand{OrgID: 1, TeamID: 1, NavID: "explore", Type: navcustomization.RuleTypeHide})
+		require.NoError(t, err)
+		_, err = s.Save(ctx, &navcustomization.SaveRuleCommand{OrgID: 1, NavID: "alerting", Type: navcustomization.RuleTypeHide})
+		require.NoError(t, err)
+
+		rules, err := s.GetEffectiveRules(ctx, 1, []int64{1})
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		require.Equal(t, "explore", rules[0].NavID)
+		require.Equal(t, navcustomization.RuleTypeHide, rules[0].Type)
+		require.Equal(t, "alerting", rules[1].NavID)
+
+		rules, err = s.GetEffectiveRules(ctx, 1, nil)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		require.Equal(t, navcustomization.RuleTypeReorder, rules[0].Type)
+
+		rules, err = s.GetEffectiveRules(ctx, 2, []int64{1})
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("should not save rules for unknown teams", func(t *testing.T) {
+		teamService.ExpectedError = team.ErrTeamNotFound
+		t.Cleanup(func() { teamService.ExpectedError = nil })
+
+		_, err := s.Save(ctx, &navcustomization.SaveRuleCommand{OrgID: 1, TeamID: 2, NavID: "explore", Type: navcustomization.RuleTypeHide})
+		require.ErrorIs(t, err, navcustomization.ErrInvalidRule)
+	})
+
+	t.Run("should delete rules", func(t *testing.T) {
+		rules, err := s.List(ctx, &navcustomization.ListRulesQuery{OrgID: 1, TeamID: 1})
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+
+		require.NoError(t, s.Delete(ctx, 1, rules[0].ID))
+		require.ErrorIs(t, s.Delete(ctx, 1, rules[0].ID), navcustomization.ErrRuleNotFound)
+
+		effective, err := s.GetEffectiveRules(ctx, 1, []int64{1})
+		require.NoError(t, err)
+		require.Equal(t, navcustomization.RuleTypeReorder, effective[0].Type)
+	})
+}
+
+func TestSaveRuleCommand_Validate(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		cmd   navcustomization.SaveRuleCommand
+		valid bool
+	}{
+		{desc: "hide rule", cmd: navcustomization.SaveRuleCommand{NavID: "explore", Type: navcustomization.RuleTypeHide}, valid: true},
+		{desc: "missing nav id", cmd: navcustomization.SaveRuleCommand{Type: navcustomization.RuleTypeHide}},
+		{desc: "unknown type", cmd: navcustomization.SaveRuleCommand{NavID: "explore", Type: "move"}},
+		{desc: "hide rule with a url", cmd: navcustomization.SaveRuleCommand{NavID: "explore", Type: navcustomization.RuleTypeHide, URL: "/explore"}},
+		{desc: "relative link", cmd: navcustomization.SaveRuleCommand{NavID: "runbooks", Type: navcustomization.RuleTypeLink, Text: "Runbooks", URL: "/d/runbooks"}, valid: true},
+		{desc: "absolute link", cmd: navcustomization.SaveRuleCommand{NavID: "wiki", Type: navcustomization.RuleTypeLink, Text: "Wiki", URL: "https://wiki.example.com", Target: "_blank"}, valid: true},
+		{desc: "link without text", cmd: navcustomization.SaveRuleCommand{NavID: "wiki", Type: navcustomization.RuleTypeLink, URL: "https://wiki.example.com"}},
+		{desc: "javascript link", cmd: navcustomization.SaveRuleCommand{NavID: "wiki", Type: navcustomization.RuleTypeLink, Text: "Wiki", URL: "javascript:alert(1)"}},
+		{desc: "protocol relative link", cmd: navcustomization.SaveRuleCommand{NavID: "wiki", Type: navcustomization.RuleTypeLink, Text: "Wiki", URL: "//wiki.example.com"}},
+		{desc: "unknown target", cmd: navcustomization.SaveRuleCommand{NavID: "wiki", Type: navcustomization.RuleTypeLink, Text: "Wiki", URL: "/wiki", Target: "_parent"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.cmd.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, navcustomization.ErrInvalidRule)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package navcustomizationimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+)
+
+type store interface {
+	List(ctx context.Context, query *navcustomization.ListRulesQuery) ([]*navcustomization.Rule, error)
+	Save(ctx context.Context, cmd *navcustomization.SaveRuleCommand) (*navcustomization.Rule, error)
+	Delete(ctx context.Context, orgID, ruleID int64) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) List(ctx context.Context, query *navcustomization.ListRulesQuery) ([]*navcustomization.Rule, error) {
+	rules := make([]*navcustomization.Rule, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.TeamID != 0 {
+			q = q.And("team_id = ?", query.TeamID)
+		}
+		return q.Asc("team_id", "id").Find(&rules)
+	})
+	return rules, err
+}
+
+func (s *sqlStore) Save(ctx context.Context, cmd *navcustomization.SaveRuleCommand) (*navcustomization.Rule, error) {
+	var rule *navcustomization.Rule
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := navcustomization.Rule{}
+		has, err := sess.Where("org_id = ? AND team_id = ? AND nav_id = ?", cmd.OrgID, cmd.TeamID, cmd.NavID).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		rule = &navcustomization.Rule{
+			OrgID:      cmd.OrgID,
+			TeamID:     cmd.TeamID,
+			NavID:      cmd.NavID,
+			Type:       cmd.Type,
+			SortWeight: cmd.SortWeight,
+			ParentID:   cmd.ParentID,
+			Text:       cmd.Text,
+			URL:        cmd.URL,
+			Icon:       cmd.Icon,
+			Target:     cmd.Target,
+			Created:    now,
+			Updated:    now,
+			UpdatedBy:  cmd.UpdatedBy,
+		}
+
+		if !has {
+			_, err = sess.Insert(rule)
+			return err
+		}
+
+		rule.ID = existing.ID
+		rule.Created = existing.Created
+		_, err = sess.ID(existing.ID).AllCols().Omit("id", "org_id", "team_id", "nav_id", "created").Update(rule)
+		return err
+	})
+	return rule, err
+}
+
+func (s *sqlStore) Delete(ctx context.Context, orgID, ruleID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM nav_customization WHERE org_id = ? AND id = ?", orgID, ruleID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return navcustomization.ErrRuleNotFound.Errorf("rule %d not found in org %d", ruleID, orgID)
+		}
+		return nil
+	})
+}
@@ -7,4 +7,7 @@ import (
 
 type Service interface {
 	GetNavTree(c *contextmodel.ReqContext, hasEditPerm bool, prefs *pref.Preference) (*NavTreeRoot, error)
+	// ApplyCustomizations hides, reorders and adds navigation nodes according to the
+	// navigation rules of the organization and of the teams of the signed in user.
+	ApplyCustomizations(c *contextmodel.ReqContext, root *NavTreeRoot)
 }
@@ -0,0 +1,80 @@
+package navtreeimpl
+
+import (
+	"strings"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+	"github.com/grafana/grafana/pkg/services/navtree"
+)
+
+// ApplyCustomizations never fails: the navigation tree is left as is when the rules cannot be loaded.
+func (s *ServiceImpl) ApplyCustomizations(c *contextmodel.ReqContext, root *navtree.NavTreeRoot) {
+	if c.SignedInUser == nil || c.OrgID == 0 {
+		return
+	}
+
+	rules, err := s.navCustomization.GetEffectiveRules(c.Req.Context(), c.OrgID, c.Teams)
+	if err != nil {
+		s.log.Error("Failed to load the navigation rules", "orgId", c.OrgID, "error", err)
+		return
+	}
+
+	applyNavRules(root, rules, s.cfg.AppSubURL)
+}
+
+// applyNavRules hides the nodes first, so that a link can replace a hidden node,
+// then adds the links and finally reorders the nodes, links included.
+func applyNavRules(root *navtree.NavTreeRoot, rules []*navcustomization.Rule, appSubURL string) {
+	for _, r := range rules {
+		if r.Type == navcustomization.RuleTypeHide {
+			root.Children = removeNavNode(root.Children, r.NavID)
+		}
+	}
+
+	for _, r := range rules {
+		if r.Type != navcustomization.RuleTypeLink || root.FindById(r.NavID) != nil {
+			continue
+		}
+
+		link := &navtree.NavLink{
+			Id:         r.NavID,
+			Text:       r.Text,
+			Url:        r.URL,
+			Icon:       r.Icon,
+			Target:     r.Target,
+			SortWeight: r.SortWeight,
+		}
+		if strings.HasPrefix(link.Url, "/") {
+			link.Url = appSubURL + link.Url
+		}
+
+		if r.ParentID == "" {
+			link.Section = navtree.NavSectionCore
+			root.AddSection(link)
+		} else if parent := root.FindById(r.ParentID); parent != nil {
+			parent.Children = append(parent.Children, link)
+		}
+	}
+
+	for _, r := range rules {
+		if r.Type != navcustomization.RuleTypeReorder {
+			continue
+		}
+		if node := root.FindById(r.NavID); node != nil {
+			node.SortWeight = r.SortWeight
+		}
+	}
+}
+
+func removeNavNode(nodes []*navtree.NavLink, id string) []*navtree.NavLink {
+	result := nodes[:0]
+	for _, node := range nodes {
+		if node.Id == id {
+			continue
+		}
+		node.Children = removeNavNode(node.Children, id)
+		result = append(result, node)
+	}
+	return result
+}
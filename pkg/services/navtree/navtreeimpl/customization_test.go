@@ -0,0 +1,79 @@
+package navtreeimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/navcustomization"
+	"github.com/grafana/grafana/pkg/services/navtree"
+)
+
+func TestApplyNavRules(t *testing.T) {
+	newTree := func() *navtree.NavTreeRoot {
+		return &navtree.NavTreeRoot{Children: []*navtree.NavLink{
+			{Id: navtree.NavIDDashboards, Text: "Dashboards", SortWeight: 10, Children: []*navtree.NavLink{
+				{Id: "dashboards/browse", Text: "Browse"},
+				{Id: "dashboards/playlists", Text: "Playlists"},
+			}},
+			{Id: "explore", Text: "Explore", SortWeight: 20},
+		}}
+	}
+
+	t.Run("should hide nested nodes", func(t *testing.T) {
+		root := newTree()
+		applyNavRules(root, []*navcustomization.Rule{
+			{NavID: "dashboards/playlists", Type: navcustomization.RuleTypeHide},
+			{NavID: "explore", Type: navcustomization.RuleTypeHide},
+		}, "")
+
+		require.Len(t, root.Children, 1)
+		require.Len(t, root.Children[0].Children, 1)
+		require.Nil(t, root.FindById("dashboards/playlists"))
+	})
+
+	t.Run("should add links to the root and to sections", func(t *testing.T) {
+		root := newTree()
+		applyNavRules(root, []*navcustomization.Rule{
+			{NavID: "wiki", Type: navcustomization.RuleTypeLink, Text: "Wiki", URL: "https://wiki.example.com", Target: "_blank", SortWeight: 15},
+			{NavID: "runbooks", Type: navcustomization.RuleTypeLink, ParentID: navtree.NavIDDashboards, Text: "Runbooks", URL: "/d/runbooks"},
+			{NavID: "orphan", Type: navcustomization.RuleTypeLink, ParentID: "unknown", Text: "Orphan", URL: "/orphan"},
+			{NavID: "explore", Type: navcustomization.RuleTypeLink, Text: "Explore", URL: "/other"},
+		}, "/grafana")
+
+		wiki := root.FindById("wiki")
+		require.NotNil(t, wiki)
+		require.Equal(t, "https://wiki.example.com", wiki.Url)
+		require.Equal(t, navtree.NavSectionCore, wiki.Section)
+		require.Equal(t, int64(15), wiki.SortWeight)
+
+		runbooks := root.FindById("runbooks")
+		require.NotNil(t, runbooks)
+		require.Equal(t, "/grafana/d/runbooks", runbooks.Url)
+		require.Len(t, root.FindById(navtree.NavIDDashboards).Children, 3)
+
+		require.Nil(t, root.FindById("orphan"))
+		require.Equal(t, "", root.FindById("explore").Url)
+	})
+
+	t.Run("should replace hidden nodes with links", func(t *testing.T) {
+		root := newTree()
+		applyNavRules(root, []*navcustomization.Rule{
+			{NavID: "explore-link", Type: navcustomization.RuleTypeLink, Text: "Explore", URL: "/explore?orgId=1"},
+			{NavID: "explore", Type: navcustomization.RuleTypeHide},
+		}, "")
+
+		require.Nil(t, root.FindById("explore"))
+		require.NotNil(t, root.FindById("explore-link"))
+	})
+
+	t.Run("should reorder nodes", func(t *testing.T) {
+		root := newTree()
+		applyNavRules(root, []*navcustomization.Rule{
+			{NavID: "explore", Type: navcustomization.RuleTypeReorder, SortWeight: 1},
+		}, "")
+		root.Sort()
+
+		require.Equal(t, "explore", root.Children[0].Id)
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/navcustomization"
 	"github.com/grafana/grafana/pkg/services/navtree"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
@@ -37,6 +38,7 @@ type ServiceImpl struct {
 	kvStore              kvstore.KVStore
 	apiKeyService        apikey.Service
 	queryLibraryService  querylibrary.HTTPService
+	navCustomization     navcustomization.Service
 
 	// Navigation
 	navigationAppConfig     map[string]NavigationAppConfig
@@ -50,7 +52,7 @@ type NavigationAppConfig struct {
 	Icon       string
 }
 
-func ProvideService(cfg *setting.Cfg, accessControl ac.AccessControl, pluginStore plugins.Store, pluginSettings pluginsettings.Service, starService star.Service, features *featuremgmt.FeatureManager, dashboardService dashboards.DashboardService, accesscontrolService ac.Service, kvStore kvstore.KVStore, apiKeyService apikey.Service, queryLibraryService querylibrary.HTTPService) navtree.Service {
+func ProvideService(cfg *setting.Cfg, accessControl ac.AccessControl, pluginStore plugins.Store, pluginSettings pluginsettings.Service, starService star.Service, features *featuremgmt.FeatureManager, dashboardService dashboards.DashboardService, accesscontrolService ac.Service, kvStore kvstore.KVStore, apiKeyService apikey.Service, queryLibraryService querylibrary.HTTPService, navCustomization navcustomization.Service) navtree.Service {
 	service := &ServiceImpl{
 		cfg:                  cfg,
 		log:                  log.New("navtree service"),
@@ -64,6 +66,7 @@ func ProvideService(cfg *setting.Cfg, accessControl ac.AccessControl, pluginStor
 		kvStore:              kvStore,
 		apiKeyService:        apiKeyService,
 		queryLibraryService:  queryLibraryService,
+		navCustomization:     navCustomization,
 	}
 
 	service.readNavigationSettings()
@@ -158,13 +161,13 @@ func (s *ServiceImpl) GetNavTree(c *contextmodel.ReqContext, hasEditPerm bool, p
 		}
 	}
 
-	if s.features.IsEnabled(featuremgmt.FlagDataConnectionsConsole) {
+	if s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagDataConnectionsConsole) {
 		if connectionsSection := s.buildDataConnectionsNavLink(c); connectionsSection != nil {
 			treeRoot.AddSection(connectionsSection)
 		}
 	}
 
-	if s.features.IsEnabled(featuremgmt.FlagLivePipeline) {
+	if s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagLivePipeline) {
 		liveNavLinks := []*navtree.NavLink{}
 
 		liveNavLinks = append(liveNavLinks, &navtree.NavLink{
@@ -232,7 +235,7 @@ func (s *ServiceImpl) getHomeNode(c *contextmodel.ReqContext, prefs *pref.Prefer
 		Section:    navtree.NavSectionCore,
 		SortWeight: navtree.WeightHome,
 	}
-	if !s.features.IsEnabled(featuremgmt.FlagTopnav) {
+	if !s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 		homeNode.HideFromMenu = true
 	}
 	return homeNode
@@ -382,7 +385,7 @@ func (s *ServiceImpl) buildDashboardNavLinks(c *contextmodel.ReqContext, hasEdit
 
 	dashboardChildNavs := []*navtree.NavLink{}
 
-	if !s.features.IsEnabled(featuremgmt.FlagTopnav) {
+	if !s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 		dashboardChildNavs = append(dashboardChildNavs, &navtree.NavLink{
 			Text: "Browse", Id: navtree.NavIDDashboardsBrowse, Url: s.cfg.AppSubURL + "/dashboards", Icon: "sitemap",
 		})
@@ -411,7 +414,7 @@ func (s *ServiceImpl) buildDashboardNavLinks(c *contextmodel.ReqContext, hasEdit
 			Icon:     "library-panel",
 		})
 
-		if s.features.IsEnabled(featuremgmt.FlagPublicDashboards) {
+		if s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagPublicDashboards) {
 			dashboardChildNavs = append(dashboardChildNavs, &navtree.NavLink{
 				Text: "Public dashboards",
 				Id:   "dashboards/public",
@@ -421,7 +424,7 @@ func (s *ServiceImpl) buildDashboardNavLinks(c *contextmodel.ReqContext, hasEdit
 		}
 	}
 
-	if s.features.IsEnabled(featuremgmt.FlagScenes) {
+	if s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagScenes) {
 		dashboardChildNavs = append(dashboardChildNavs, &navtree.NavLink{
 			Text: "Scenes",
 			Id:   "scenes",
@@ -430,7 +433,7 @@ func (s *ServiceImpl) buildDashboardNavLinks(c *contextmodel.ReqContext, hasEdit
 		})
 	}
 
-	if hasEditPerm && !s.features.IsEnabled(featuremgmt.FlagTopnav) {
+	if hasEditPerm && !s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 		dashboardChildNavs = append(dashboardChildNavs, &navtree.NavLink{
 			Text: "Divider", Divider: true, Id: "divider", HideFromTabs: true,
 		})
@@ -444,7 +447,7 @@ func (s *ServiceImpl) buildDashboardNavLinks(c *contextmodel.ReqContext, hasEdit
 		}
 	}
 
-	if hasEditPerm && !s.features.IsEnabled(featuremgmt.FlagTopnav) {
+	if hasEditPerm && !s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 		if hasAccess(ac.ReqOrgAdminOrEditor, ac.EvalPermission(dashboards.ActionFoldersCreate)) {
 			dashboardChildNavs = append(dashboardChildNavs, &navtree.NavLink{
 				Text: "New folder", SubTitle: "Create a new folder to organize your dashboards", Id: "dashboards/folder/new",
@@ -486,7 +489,7 @@ func (s *ServiceImpl) buildLegacyAlertNavLinks(c *contextmodel.ReqContext) *navt
 		SortWeight: navtree.WeightAlerting,
 	}
 
-	if s.features.IsEnabled(featuremgmt.FlagTopnav) {
+	if s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 		alertNav.Url = s.cfg.AppSubURL + "/alerting"
 	} else {
 		alertNav.Url = s.cfg.AppSubURL + "/alerting/list"
@@ -499,7 +502,7 @@ func (s *ServiceImpl) buildAlertNavLinks(c *contextmodel.ReqContext, hasEditPerm
 	hasAccess := ac.HasAccess(s.accessControl, c)
 	var alertChildNavs []*navtree.NavLink
 
-	if !s.features.IsEnabled(featuremgmt.FlagTopnav) {
+	if !s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 		alertChildNavs = append(alertChildNavs, &navtree.NavLink{
 			Text: "Home",
 			Id:   "alert-home",
@@ -537,7 +540,7 @@ func (s *ServiceImpl) buildAlertNavLinks(c *contextmodel.ReqContext, hasEditPerm
 	fallbackHasEditPerm := func(*contextmodel.ReqContext) bool { return hasEditPerm }
 
 	if hasAccess(fallbackHasEditPerm, ac.EvalAny(ac.EvalPermission(ac.ActionAlertingRuleCreate), ac.EvalPermission(ac.ActionAlertingRuleExternalWrite))) {
-		if !s.features.IsEnabled(featuremgmt.FlagTopnav) {
+		if !s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 			alertChildNavs = append(alertChildNavs, &navtree.NavLink{
 				Text: "Divider", Divider: true, Id: "divider", HideFromTabs: true,
 			})
@@ -560,7 +563,7 @@ func (s *ServiceImpl) buildAlertNavLinks(c *contextmodel.ReqContext, hasEditPerm
 			SortWeight: navtree.WeightAlerting,
 		}
 
-		if s.features.IsEnabled(featuremgmt.FlagTopnav) {
+		if s.features.IsEnabledCtx(c.Req.Context(), featuremgmt.FlagTopnav) {
 			alertNav.Url = s.cfg.AppSubURL + "/alerting"
 		} else {
 			alertNav.Url = s.cfg.AppSubURL + "/alerting/home"
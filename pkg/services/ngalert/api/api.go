@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/idempotency"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -22,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -33,6 +35,12 @@ type ExternalAlertmanagerProvider interface {
 	DroppedAlertmanagersFor(orgID int64) []*url.URL
 }
 
+// ExternalAlertmanagerConfigSync synchronizes the Grafana-managed configuration with the external Alertmanagers.
+type ExternalAlertmanagerConfigSync interface {
+	Status(orgID int64) []apimodels.ExternalAlertmanagerConfigSync
+	Sync(ctx context.Context, orgID int64, force bool) ([]apimodels.ExternalAlertmanagerConfigSync, error)
+}
+
 type Alertmanager interface {
 	// Configuration
 	SaveAndApplyConfig(ctx context.Context, config *apimodels.PostableUserConfig) error
@@ -80,9 +88,14 @@ type API struct {
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
+	ConfigSync           ExternalAlertmanagerConfigSync
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
 	Historian            Historian
+	NotificationLogStore store.NotificationLogStore
+	EvaluationCosts      RuleEvaluationCosts
+	ResourceWatch        *resourcewatch.Service
+	Idempotency          *idempotency.Service
 
 	AppUrl *url.URL
 }
@@ -94,6 +107,9 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		DataProxy: api.DataProxy,
 		ac:        api.AccessControl,
 	}
+	if api.Idempotency != nil {
+		api.RouteRegister = &idempotentRouteRegister{RouteRegister: api.RouteRegister, middleware: api.Idempotency.Middleware()}
+	}
 
 	// Register endpoints for proxying to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
@@ -105,7 +121,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
 		api.DatasourceCache,
 		NewLotexProm(proxy, logger),
-		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, ac: api.AccessControl},
+		&PrometheusSrv{log: logger, manager: api.StateManager, store: api.RuleStore, ac: api.AccessControl, costs: api.EvaluationCosts},
 	), m)
 	// Register endpoints for proxying to Cortex Ruler-compatible backends.
 	api.RegisterRulerApiEndpoints(NewForkingRuler(
@@ -139,6 +155,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			store:                api.AdminConfigStore,
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
+			configSync:           api.ConfigSync,
 		},
 	), m)
 
@@ -149,6 +166,12 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		resourceWatch:       api.ResourceWatch,
+	}), m)
+
+	api.RegisterNotificationsApiEndpoints(NewNotificationsApi(&NotificationLogSrv{
+		log:   logger,
+		store: api.NotificationLogStore,
 	}), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
@@ -22,6 +22,7 @@ import (
 type ConfigSrv struct {
 	datasourceService    datasources.DataSourceService
 	alertmanagerProvider ExternalAlertmanagerProvider
+	configSync           ExternalAlertmanagerConfigSync
 	store                store.AdminConfigurationStore
 	log                  log.Logger
 }
@@ -160,3 +161,24 @@ func (srv ConfigSrv) RouteGetAlertingStatus(c *contextmodel.ReqContext) response
 	}
 	return response.JSON(http.StatusOK, resp)
 }
+
+func (srv ConfigSrv) RouteGetExternalAlertmanagerConfigSync(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, apimodels.ExternalAlertmanagerConfigSyncStatus{
+		Alertmanagers: srv.configSync.Status(c.OrgID),
+	})
+}
+
+func (srv ConfigSrv) RoutePostExternalAlertmanagerConfigSync(c *contextmodel.ReqContext, body apimodels.PostableExternalAlertmanagerConfigSync) response.Response {
+	statuses, err := srv.configSync.Sync(c.Req.Context(), c.OrgID, body.Force)
+	if err != nil {
+		msg := "failed to synchronize the configuration of the external Alertmanagers"
+		srv.log.Error(msg, "error", err)
+		return ErrResp(http.StatusInternalServerError, err, msg)
+	}
+	if statuses == nil {
+		statuses = []apimodels.ExternalAlertmanagerConfigSync{}
+	}
+	return response.JSON(http.StatusOK, apimodels.ExternalAlertmanagerConfigSyncStatus{
+		Alertmanagers: statuses,
+	})
+}
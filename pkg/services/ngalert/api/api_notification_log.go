@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	defaultNotificationLogLimit = 100
+	maxNotificationLogLimit     = 1000
+)
+
+type NotificationLogSrv struct {
+	log   log.Logger
+	store store.NotificationLogStore
+}
+
+func (srv *NotificationLogSrv) RouteGetNotificationLog(c *contextmodel.ReqContext) response.Response {
+	query := ngmodels.NotificationDeliveryQuery{
+		OrgID:           c.OrgID,
+		Receiver:        c.Query("receiver"),
+		IntegrationUID:  c.Query("integrationUid"),
+		IntegrationType: c.Query("integration"),
+		Status:          ngmodels.NotificationDeliveryStatus(c.Query("status")),
+		Limit:           c.QueryInt("limit"),
+	}
+
+	switch query.Status {
+	case "", ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailure:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("unknown status %q, expected %q or %q", query.Status, ngmodels.NotificationDeliverySuccess, ngmodels.NotificationDeliveryFailure), "")
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("from must not be after to"), "")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultNotificationLogLimit
+	}
+	if query.Limit > maxNotificationLogLimit {
+		query.Limit = maxNotificationLogLimit
+	}
+
+	deliveries, err := srv.store.GetNotificationDeliveries(c.Req.Context(), &query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the notification log")
+	}
+
+	result := apimodels.NotificationLog{Deliveries: make([]apimodels.NotificationDelivery, 0, len(deliveries))}
+	for _, d := range deliveries {
+		result.Deliveries = append(result.Deliveries, apimodels.NotificationDelivery{
+			Receiver:         d.Receiver,
+			IntegrationUID:   d.IntegrationUID,
+			IntegrationType:  d.IntegrationType,
+			IntegrationIndex: d.IntegrationIndex,
+			GroupKey:         d.GroupKey,
+			Status:           string(d.Status),
+			Error:            d.Error,
+			Retry:            d.Retry,
+			WillRetry:        d.WillRetry,
+			Alerts:           d.Alerts,
+			LatencyMs:        d.LatencyMs,
+			SentAt:           d.SentAt,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+type fakeNotificationLogStore struct {
+	query      *ngmodels.NotificationDeliveryQuery
+	deliveries []*ngmodels.NotificationDelivery
+}
+
+func (f *fakeNotificationLogStore) SaveNotificationDelivery(_ context.Context, delivery *ngmodels.NotificationDelivery) error {
+	f.deliveries = append(f.deliveries, delivery)
+	return nil
+}
+
+func (f *fakeNotificationLogStore) GetNotificationDeliveries(_ context.Context, query *ngmodels.NotificationDeliveryQuery) ([]*ngmodels.NotificationDelivery, error) {
+	f.query = query
+	return f.deliveries, nil
+}
+
+func (f *fakeNotificationLogStore) DeleteNotificationDeliveriesBefore(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestRouteGetNotificationLog(t *testing.T) {
+	requestCtx := func(query string) *contextmodel.ReqContext {
+		ctx := createRequestContext(1, org.RoleEditor, nil)
+		ctx.Req.URL, _ = url.Parse("http://localhost/api/v1/notifications/log?" + query)
+		return ctx
+	}
+
+	t.Run("returns the deliveries that match the query", func(t *testing.T) {
+		sentAt := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+		store := &fakeNotificationLogStore{deliveries: []*ngmodels.NotificationDelivery{{
+			OrgID:           1,
+			Receiver:        "ops",
+			IntegrationUID:  "uid",
+			IntegrationType: "slack",
+			Status:          ngmodels.NotificationDeliveryFailure,
+			Error:           "503 Service Unavailable",
+			Retry:           2,
+			WillRetry:       true,
+			Alerts:          3,
+			LatencyMs:       120,
+			SentAt:          sentAt,
+		}}}
+		srv := &NotificationLogSrv{log: log.NewNopLogger(), store: store}
+
+		resp := srv.RouteGetNotificationLog(requestCtx("receiver=ops&integration=slack&status=failure&from=1677672000&to=1677675600&limit=5000"))
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		require.Equal(t, ngmodels.NotificationDeliveryQuery{
+			OrgID:           1,
+			Receiver:        "ops",
+			IntegrationType: "slack",
+			Status:          ngmodels.NotificationDeliveryFailure,
+			From:            time.Unix(1677672000, 0),
+			To:              time.Unix(1677675600, 0),
+			Limit:           maxNotificationLogLimit,
+		}, *store.query)
+
+		var result apimodels.NotificationLog
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Equal(t, []apimodels.NotificationDelivery{{
+			Receiver:        "ops",
+			IntegrationUID:  "uid",
+			IntegrationType: "slack",
+			Status:          "failure",
+			Error:           "503 Service Unavailable",
+			Retry:           2,
+			WillRetry:       true,
+			Alerts:          3,
+			LatencyMs:       120,
+			SentAt:          sentAt,
+		}}, result.Deliveries)
+	})
+
+	t.Run("uses the default limit", func(t *testing.T) {
+		store := &fakeNotificationLogStore{}
+		srv := &NotificationLogSrv{log: log.NewNopLogger(), store: store}
+
+		resp := srv.RouteGetNotificationLog(requestCtx(""))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.Equal(t, defaultNotificationLogLimit, store.query.Limit)
+		require.JSONEq(t, `{"deliveries": []}`, string(resp.Body()))
+	})
+
+	t.Run("rejects an invalid query", func(t *testing.T) {
+		srv := &NotificationLogSrv{log: log.NewNopLogger(), store: &fakeNotificationLogStore{}}
+
+		resp := srv.RouteGetNotificationLog(requestCtx("status=pending"))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		resp = srv.RouteGetNotificationLog(requestCtx("from=1677675600&to=1677672000"))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
@@ -23,11 +23,17 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 )
 
+// RuleEvaluationCosts returns the cost of the evaluations of the rules tracked by the scheduler.
+type RuleEvaluationCosts interface {
+	GetRuleEvaluationCost(key ngmodels.AlertRuleKey) (ngmodels.RuleEvaluationCost, bool)
+}
+
 type PrometheusSrv struct {
 	log     log.Logger
 	manager state.AlertInstanceManager
 	store   RuleStore
 	ac      accesscontrol.AccessControl
+	costs   RuleEvaluationCosts
 }
 
 const queryIncludeInternalLabels = "includeInternalLabels"
@@ -64,6 +70,7 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 			State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
 			ActiveAt: &startsAt,
 			Value:    valString,
+			Flapping: alertState.Flapping,
 		})
 	}
 
@@ -221,6 +228,7 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *folder.Folder, ru
 				State:    state.FormatStateAndReason(alertState.State, alertState.StateReason),
 				ActiveAt: &activeAt,
 				Value:    valString,
+				Flapping: alertState.Flapping,
 			}
 
 			if alertState.LastEvaluationTime.After(newRule.LastEvaluation) {
@@ -251,6 +259,16 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *folder.Folder, ru
 			alertingRule.Alerts = append(alertingRule.Alerts, alert)
 		}
 
+		if srv.costs != nil {
+			if cost, ok := srv.costs.GetRuleEvaluationCost(rule.GetKey()); ok {
+				alertingRule.EvaluationCost = toEvaluationCost(cost)
+				if !cost.PausedUntil.IsZero() {
+					newRule.Health = "paused"
+					newRule.LastError = cost.PauseReason
+				}
+			}
+		}
+
 		alertingRule.Rule = newRule
 		newGroup.Rules = append(newGroup.Rules, alertingRule)
 		newGroup.Interval = float64(rule.IntervalSeconds)
@@ -261,6 +279,21 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *folder.Folder, ru
 	return newGroup
 }
 
+func toEvaluationCost(cost ngmodels.RuleEvaluationCost) *apimodels.EvaluationCost {
+	result := &apimodels.EvaluationCost{
+		LastDurationSeconds:   cost.Last.Duration.Seconds(),
+		LastDatapoints:        cost.Last.Datapoints,
+		WindowDurationSeconds: cost.Window.Duration.Seconds(),
+		WindowDatapoints:      cost.Window.Datapoints,
+	}
+	if !cost.PausedUntil.IsZero() {
+		pausedUntil := cost.PausedUntil
+		result.PausedUntil = &pausedUntil
+		result.PauseReason = cost.PauseReason
+	}
+	return result
+}
+
 // ruleToQuery attempts to extract the datasource queries from the alert query model.
 // Returns the whole JSON model as a string if it fails to extract a minimum of 1 query.
 func ruleToQuery(logger log.Logger, rule *ngmodels.AlertRule) string {
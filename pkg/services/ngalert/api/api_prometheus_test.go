@@ -424,6 +424,37 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 `, folder.Title), string(r.Body()))
 	})
 
+	t.Run("with the evaluation cost of a rule", func(t *testing.T) {
+		fakeStore, fakeAIM, _, api := setupAPI(t)
+		generateRuleAndInstanceWithQuery(t, orgID, fakeAIM, fakeStore, withClassicConditionSingleQuery())
+		rule := fakeStore.Rules[orgID][0]
+		pausedUntil := timeNow().Add(time.Minute)
+		api.costs = fakeRuleEvaluationCosts{rule.GetKey(): {
+			Last:        ngmodels.EvaluationCost{Duration: time.Second, Datapoints: 10},
+			Window:      ngmodels.EvaluationCost{Duration: 5 * time.Second, Datapoints: 50},
+			PausedUntil: pausedUntil,
+			PauseReason: "too expensive",
+		}}
+
+		r := api.RouteGetRuleStatuses(c)
+		require.Equal(t, http.StatusOK, r.Status())
+		var res apimodels.RuleResponse
+		require.NoError(t, json.Unmarshal(r.Body(), &res))
+		require.Len(t, res.Data.RuleGroups, 1)
+		require.Len(t, res.Data.RuleGroups[0].Rules, 1)
+		rr := res.Data.RuleGroups[0].Rules[0]
+		require.Equal(t, "paused", rr.Health)
+		require.Equal(t, "too expensive", rr.LastError)
+		require.Equal(t, &apimodels.EvaluationCost{
+			LastDurationSeconds:   1,
+			LastDatapoints:        10,
+			WindowDurationSeconds: 5,
+			WindowDatapoints:      50,
+			PausedUntil:           &pausedUntil,
+			PauseReason:           "too expensive",
+		}, rr.EvaluationCost)
+	})
+
 	t.Run("with many rules in a group", func(t *testing.T) {
 		t.Run("should return sorted", func(t *testing.T) {
 			ruleStore := fakes.NewRuleStore(t)
@@ -506,6 +537,13 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 	})
 }
 
+type fakeRuleEvaluationCosts map[ngmodels.AlertRuleKey]ngmodels.RuleEvaluationCost
+
+func (f fakeRuleEvaluationCosts) GetRuleEvaluationCost(key ngmodels.AlertRuleKey) (ngmodels.RuleEvaluationCost, bool) {
+	cost, ok := f[key]
+	return cost, ok
+}
+
 func setupAPI(t *testing.T) (*fakes.RuleStore, *fakeAlertInstanceManager, *acmock.Mock, PrometheusSrv) {
 	fakeStore := fakes.NewRuleStore(t)
 	fakeAIM := NewFakeAlertInstanceManager(t)
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/provisioning/alerting/file"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -27,6 +28,7 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	resourceWatch       *resourcewatch.Service
 }
 
 type ContactPointService interface {
@@ -255,6 +257,12 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 }
 
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
+	if c.QueryBool("watch") && srv.resourceWatch != nil {
+		// the provisioning API gives access to all the rules of the organization
+		events := srv.resourceWatch.Watch(c.Req.Context(), c.OrgID, resourcewatch.KindAlertRule)
+		return resourcewatch.Stream(c, events, func(context.Context, resourcewatch.Event) bool { return true })
+	}
+
 	rules, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.OrgID)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
@@ -400,6 +408,53 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext,
 	return exportResponse(c, e)
 }
 
+// RouteGetAlertingConfigurationExport retrieves the alert rules and the notification configuration of the organization in a format compatible with file provisioning.
+func (srv *ProvisioningSrv) RouteGetAlertingConfigurationExport(c *contextmodel.ReqContext) response.Response {
+	ctx := c.Req.Context()
+
+	groupsWithTitle, err := srv.alertRules.GetAlertGroupsWithFolderTitle(ctx, c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	rules, err := file.NewAlertingFileExport(groupsWithTitle)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+
+	policies, err := srv.policies.GetPolicyTree(ctx, c.OrgID)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification policies")
+	}
+	cps, err := srv.contactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: c.OrgID})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get contact points")
+	}
+	timings, err := srv.muteTimings.GetMuteTimings(ctx, c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get mute timings")
+	}
+	templates, err := srv.templates.GetTemplates(ctx, c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get templates")
+	}
+
+	e := definitions.AlertingConfigurationExport{
+		APIVersion:    rules.APIVersion,
+		Groups:        rules.Groups,
+		ContactPoints: ContactPointsToExport(c.OrgID, cps),
+		Policies: []definitions.NotificationPolicyExport{{
+			OrgID:       c.OrgID,
+			RouteExport: RouteToExport(policies),
+		}},
+		MuteTimes: MuteTimingsToExport(c.OrgID, timings),
+		Templates: TemplatesToExport(c.OrgID, templates),
+	}
+	return exportResponse(c, e)
+}
+
 func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) response.Response {
 	ag.FolderUID = folderUID
 	ag.Title = group
@@ -802,6 +802,42 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
 		})
+
+		t.Run("alerting configuration", func(t *testing.T) {
+			t.Run("query param download=true, GET returns content disposition attachment", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("download", "true")
+				response := sut.RouteGetAlertingConfigurationExport(&rc)
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Contains(t, rc.Context.Resp.Header().Get("Content-Disposition"), "attachment")
+			})
+
+			t.Run("yaml body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rc.Context.Req.Header.Add("Accept", "application/yaml")
+				expectedResponse := "apiVersion: 1\ncontactPoints:\n    - orgId: 1\n      name: email receiver\n      receivers:\n        - uid: email-uid\n          type: email\n          settings:\n            addresses: <example@email.com>\n          disableResolveMessage: false\npolicies:\n    - orgId: 1\n      receiver: some-receiver\nmuteTimes:\n    - orgId: 1\n      name: interval\n      time_intervals: []\ntemplates:\n    - orgId: 1\n      name: a\n      template: template\n"
+
+				response := sut.RouteGetAlertingConfigurationExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("no alertmanager configuration, GET returns 404", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rc.SignedInUser.OrgID = 2
+
+				response := sut.RouteGetAlertingConfigurationExport(&rc)
+
+				require.Equal(t, 404, response.Status())
+			})
+		})
 	})
 }
 
@@ -840,6 +876,7 @@ func createTestEnv(t *testing.T) testEnvironment {
 	prov := &provisioning.MockProvisioningStore{}
 	prov.EXPECT().SaveSucceeds()
 	prov.EXPECT().GetReturns(models.ProvenanceNone)
+	prov.EXPECT().GetProvenances(mock.Anything, mock.Anything, mock.Anything).Return(map[string]models.Provenance{}, nil).Maybe()
 
 	dashboardService := dashboards.NewFakeDashboardService(t)
 	dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(&dashboards.Dashboard{
@@ -117,6 +117,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceT
 			deletedGroups[groupKey] = keys
 		}
 		if len(rulesToDelete) > 0 {
+			if err := validateCompositeRules(ctx, srv.store, c.SignedInUser.OrgID, nil, rulesToDelete, nil); err != nil {
+				return err
+			}
 			return srv.store.DeleteAlertRulesByUID(ctx, c.SignedInUser.OrgID, rulesToDelete...)
 		}
 		// if none rules were deleted return an error.
@@ -137,7 +140,7 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceT
 		if errors.Is(err, ErrAuthorization) {
 			return ErrResp(http.StatusUnauthorized, err, "failed to delete rule group")
 		}
-		if errors.Is(err, errProvisionedResource) {
+		if errors.Is(err, errProvisionedResource) || errors.Is(err, ngmodels.ErrAlertRuleFailedValidation) {
 			return ErrResp(http.StatusBadRequest, err, "failed to delete rule group")
 		}
 		return ErrResp(http.StatusInternalServerError, err, "failed to delete rule group")
@@ -347,6 +350,20 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		}
 
 		finalChanges = store.UpdateCalculatedRuleFields(groupChanges)
+
+		upserts := make([]*ngmodels.AlertRule, 0, len(finalChanges.New)+len(finalChanges.Update))
+		upserts = append(upserts, finalChanges.New...)
+		for _, update := range finalChanges.Update {
+			upserts = append(upserts, update.New)
+		}
+		deletes := make([]string, 0, len(finalChanges.Delete))
+		for _, rule := range finalChanges.Delete {
+			deletes = append(deletes, rule.UID)
+		}
+		if err := validateCompositeRules(tranCtx, srv.store, groupKey.OrgID, upserts, deletes, srv.authorizeRuleGroupRead(c)); err != nil {
+			return err
+		}
+
 		logger.Debug("updating database with the authorized changes", "add", len(finalChanges.New), "update", len(finalChanges.New), "delete", len(finalChanges.Delete))
 
 		if len(finalChanges.Update) > 0 || len(finalChanges.New) > 0 {
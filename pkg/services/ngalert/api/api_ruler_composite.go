@@ -0,0 +1,221 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleGroupReadAuthorizer returns an error wrapping ErrAuthorization when the user cannot read the rule group.
+type ruleGroupReadAuthorizer func(ctx context.Context, key ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) error
+
+// authorizeRuleGroupRead returns the ruleGroupReadAuthorizer of the user of the request, the rule groups are readable
+// as in the ruler API: the namespace is visible to the user, who can query the data sources of every rule of the group.
+func (srv RulerSrv) authorizeRuleGroupRead(c *contextmodel.ReqContext) ruleGroupReadAuthorizer {
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+	var namespaceMap map[string]*folder.Folder
+	return func(ctx context.Context, key ngmodels.AlertRuleGroupKey, rules []*ngmodels.AlertRule) error {
+		if namespaceMap == nil {
+			var err error
+			namespaceMap, err = srv.store.GetUserVisibleNamespaces(ctx, c.OrgID, c.SignedInUser)
+			if err != nil {
+				return err
+			}
+		}
+		if _, ok := namespaceMap[key.NamespaceUID]; !ok {
+			return fmt.Errorf("%w to access the namespace %s of the rule group %s", ErrAuthorization, key.NamespaceUID, key.RuleGroup)
+		}
+		if !authorizeAccessToRuleGroup(rules, hasAccess) {
+			return fmt.Errorf("%w to access the rule group %s because it does not have access to one or many data sources one or many rules in the group use", ErrAuthorization, key.RuleGroup)
+		}
+		return nil
+	}
+}
+
+// validateCompositeRules verifies that the rules of the organization are consistent after the changes: the composite
+// rules that are created or updated reference existing rules that the user can read, with authorizeRead, and do not
+// depend on themselves, and the deleted rules are not referenced by the remaining composite rules.
+func validateCompositeRules(ctx context.Context, ruleStore RuleStore, orgID int64, upserts []*ngmodels.AlertRule, deletes []string, authorizeRead ruleGroupReadAuthorizer) error {
+	hasComposite := false
+	for _, rule := range upserts {
+		if rule.IsComposite() {
+			hasComposite = true
+			break
+		}
+	}
+	if !hasComposite && len(deletes) == 0 {
+		return nil
+	}
+
+	q := ngmodels.ListAlertRulesQuery{OrgID: orgID}
+	if err := ruleStore.ListAlertRules(ctx, &q); err != nil {
+		return err
+	}
+	rules := make(map[string]*ngmodels.AlertRule, len(q.Result)+len(upserts))
+	groups := make(map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRule)
+	for _, rule := range q.Result {
+		rules[rule.UID] = rule
+		groups[rule.GetGroupKey()] = append(groups[rule.GetGroupKey()], rule)
+	}
+	for _, uid := range deletes {
+		delete(rules, uid)
+	}
+	for _, rule := range upserts {
+		if rule.UID != "" {
+			rules[rule.UID] = rule
+		}
+	}
+
+	dependencies := make(map[string][]string)
+	for uid, rule := range rules {
+		if !rule.IsComposite() {
+			continue
+		}
+		condition, err := rule.GetCompositeCondition()
+		if err != nil {
+			// composite rules that are not changed are not validated again
+			continue
+		}
+		dependencies[uid] = condition.Dependencies()
+	}
+
+	deleted := make(map[string]struct{}, len(deletes))
+	for _, uid := range deletes {
+		deleted[uid] = struct{}{}
+	}
+	upserted := make(map[string]struct{}, len(upserts))
+	for _, rule := range upserts {
+		upserted[rule.UID] = struct{}{}
+	}
+	authorized := make(map[ngmodels.AlertRuleGroupKey]struct{})
+	changed := make([]string, 0, len(upserts))
+	for _, rule := range upserts {
+		if !rule.IsComposite() {
+			continue
+		}
+		condition, err := rule.GetCompositeCondition()
+		if err != nil {
+			return err
+		}
+		for _, dep := range condition.Dependencies() {
+			depRule, ok := rules[dep]
+			if !ok {
+				return fmt.Errorf("%w: composite rule %s references rule %s that does not exist", ngmodels.ErrAlertRuleFailedValidation, rule.Title, dep)
+			}
+			if _, ok := upserted[dep]; ok || authorizeRead == nil {
+				// the rules changed with the composite rule are authorized with the changes
+				continue
+			}
+			key := depRule.GetGroupKey()
+			if _, ok := authorized[key]; ok {
+				continue
+			}
+			if err := authorizeRead(ctx, key, groups[key]); err != nil {
+				return fmt.Errorf("composite rule %s references rule %s: %w", rule.Title, dep, err)
+			}
+			authorized[key] = struct{}{}
+		}
+		if rule.UID != "" {
+			changed = append(changed, rule.UID)
+		}
+	}
+	for uid, deps := range dependencies {
+		for _, dep := range deps {
+			if _, ok := deleted[dep]; ok {
+				return fmt.Errorf("%w: rule %s is referenced by composite rule %s", ngmodels.ErrAlertRuleFailedValidation, dep, rules[uid].Title)
+			}
+		}
+	}
+
+	if cycle := ngmodels.FindCompositeCycle(changed, func(uid string) []string { return dependencies[uid] }); cycle != nil {
+		return fmt.Errorf("%w: %s", ngmodels.ErrCompositeRuleCycle, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// RouteGetRuleDependencies returns the graph of the dependencies between the rules in the folders the user can read:
+// the rules are the nodes and each reference of a composite rule to another rule is an edge.
+func (srv RulerSrv) RouteGetRuleDependencies(c *contextmodel.ReqContext) response.Response {
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	result := apimodels.RuleDependencies{
+		Nodes: []apimodels.RuleDependencyNode{},
+		Edges: []apimodels.RuleDependencyEdge{},
+	}
+	if len(namespaceMap) == 0 {
+		return response.JSON(http.StatusOK, result)
+	}
+
+	namespaceUIDs := make([]string, 0, len(namespaceMap))
+	for uid := range namespaceMap {
+		namespaceUIDs = append(namespaceUIDs, uid)
+	}
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID:         c.SignedInUser.OrgID,
+		NamespaceUIDs: namespaceUIDs,
+	}
+	if err := srv.store.ListAlertRules(c.Req.Context(), &q); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+	groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+	for _, rule := range q.Result {
+		groups[rule.GetGroupKey()] = append(groups[rule.GetGroupKey()], rule)
+	}
+	visible := make(map[string]*ngmodels.AlertRule, len(q.Result))
+	for _, rules := range groups {
+		if !authorizeAccessToRuleGroup(rules, hasAccess) {
+			continue
+		}
+		for _, rule := range rules {
+			visible[rule.UID] = rule
+		}
+	}
+
+	for _, rule := range visible {
+		node := apimodels.RuleDependencyNode{
+			UID:          rule.UID,
+			Title:        rule.Title,
+			NamespaceUID: rule.NamespaceUID,
+			RuleGroup:    rule.RuleGroup,
+		}
+		if rule.IsComposite() {
+			node.Composite = true
+			condition, err := rule.GetCompositeCondition()
+			if err == nil {
+				node.Expression = condition.Expression
+				for ref, uid := range condition.Rules {
+					if _, ok := visible[uid]; !ok {
+						continue
+					}
+					result.Edges = append(result.Edges, apimodels.RuleDependencyEdge{From: rule.UID, To: uid, Ref: ref})
+				}
+			}
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool { return result.Nodes[i].UID < result.Nodes[j].UID })
+	sort.Slice(result.Edges, func(i, j int) bool {
+		if result.Edges[i].From != result.Edges[j].From {
+			return result.Edges[i].From < result.Edges[j].From
+		}
+		return result.Edges[i].Ref < result.Edges[j].Ref
+	})
+	return response.JSON(http.StatusOK, result)
+}
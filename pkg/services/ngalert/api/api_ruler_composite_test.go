@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func TestValidateCompositeRules(t *testing.T) {
+	orgID := int64(1)
+	composite := func(uid, expression string, rules map[string]string) *models.AlertRule {
+		rule := models.AlertRuleGen(models.WithOrgID(orgID))()
+		rule.UID = uid
+		model, err := json.Marshal(models.CompositeModel{Expression: expression, Rules: rules})
+		require.NoError(t, err)
+		rule.Condition = "A"
+		rule.Data = []models.AlertQuery{{RefID: "A", DatasourceUID: models.CompositeDatasourceUID, Model: model}}
+		return rule
+	}
+	simple := func(uid string) *models.AlertRule {
+		rule := models.AlertRuleGen(models.WithOrgID(orgID))()
+		rule.UID = uid
+		return rule
+	}
+
+	t.Run("should accept a composite rule referencing existing rules", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), simple("a"), simple("b"))
+
+		err := validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			composite("c", "A && B", map[string]string{"A": "a", "B": "b"}),
+		}, nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept a composite rule referencing a rule created with it", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+
+		err := validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			simple("a"),
+			composite("c", "A", map[string]string{"A": "a"}),
+		}, nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject a composite rule referencing a missing rule", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), simple("a"))
+
+		err := validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			composite("c", "A && B", map[string]string{"A": "a", "B": "b"}),
+		}, nil, nil)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should reject deleting a referenced rule", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), simple("a"), composite("c", "A", map[string]string{"A": "a"}))
+
+		err := validateCompositeRules(context.Background(), ruleStore, orgID, nil, []string{"a"}, nil)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		err = validateCompositeRules(context.Background(), ruleStore, orgID, nil, []string{"a", "c"}, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("should require read access to the groups of the referenced rules", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		a, b := simple("a"), simple("b")
+		ruleStore.PutRule(context.Background(), a, b)
+
+		var authorized []models.AlertRuleGroupKey
+		authorizeRead := func(ctx context.Context, key models.AlertRuleGroupKey, rules []*models.AlertRule) error {
+			authorized = append(authorized, key)
+			if key == b.GetGroupKey() {
+				return ErrAuthorization
+			}
+			return nil
+		}
+
+		err := validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			composite("c", "A", map[string]string{"A": "a"}),
+		}, nil, authorizeRead)
+		require.NoError(t, err)
+		require.Equal(t, []models.AlertRuleGroupKey{a.GetGroupKey()}, authorized)
+
+		err = validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			composite("c", "A && B", map[string]string{"A": "a", "B": "b"}),
+		}, nil, authorizeRead)
+		require.ErrorIs(t, err, ErrAuthorization)
+
+		authorized = nil
+		err = validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			b,
+			composite("c", "B", map[string]string{"B": "b"}),
+		}, nil, authorizeRead)
+		require.NoError(t, err)
+		require.Empty(t, authorized)
+	})
+
+	t.Run("should reject a cycle", func(t *testing.T) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), simple("a"), composite("b", "A", map[string]string{"A": "a"}))
+
+		err := validateCompositeRules(context.Background(), ruleStore, orgID, []*models.AlertRule{
+			composite("a", "B", map[string]string{"B": "b"}),
+		}, nil, nil)
+		require.ErrorIs(t, err, models.ErrCompositeRuleCycle)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
+func TestAuthorizeRuleGroupRead(t *testing.T) {
+	orgID := int64(1)
+	ruleStore := fakes.NewRuleStore(t)
+	rule := models.AlertRuleGen(models.WithOrgID(orgID))()
+	ruleStore.PutRule(context.Background(), rule)
+	hidden := models.AlertRuleGen(models.WithOrgID(orgID))()
+
+	t.Run("should authorize a group of a visible namespace with access to its data sources", func(t *testing.T) {
+		ac := acMock.New().WithPermissions(createPermissionsForRules([]*models.AlertRule{rule}))
+		authorizeRead := createService(ac, ruleStore).authorizeRuleGroupRead(createRequestContext(orgID, org.RoleViewer, nil))
+
+		require.NoError(t, authorizeRead(context.Background(), rule.GetGroupKey(), []*models.AlertRule{rule}))
+		require.ErrorIs(t, authorizeRead(context.Background(), hidden.GetGroupKey(), []*models.AlertRule{hidden}), ErrAuthorization)
+	})
+
+	t.Run("should not authorize a group without access to its data sources", func(t *testing.T) {
+		authorizeRead := createService(acMock.New(), ruleStore).authorizeRuleGroupRead(createRequestContext(orgID, org.RoleViewer, nil))
+
+		require.ErrorIs(t, authorizeRead(context.Background(), rule.GetGroupKey(), []*models.AlertRule{rule}), ErrAuthorization)
+	})
+}
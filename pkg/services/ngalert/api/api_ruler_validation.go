@@ -81,7 +81,12 @@ func validateRuleNode(
 			Condition: ruleNode.GrafanaManagedAlert.Condition,
 			Data:      ruleNode.GrafanaManagedAlert.Data,
 		}
-		if err = conditionValidator(cond); err != nil {
+		if ngmodels.IsCompositeCondition(cond) {
+			_, err = ngmodels.ParseCompositeCondition(cond)
+		} else {
+			err = conditionValidator(cond)
+		}
+		if err != nil {
 			return nil, fmt.Errorf("failed to validate condition of alert rule %s: %w", ruleNode.GrafanaManagedAlert.Title, err)
 		}
 	}
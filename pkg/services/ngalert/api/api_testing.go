@@ -145,15 +145,15 @@ func (srv TestingApiSrv) RouteEvalQueries(c *contextmodel.ReqContext, cmd apimod
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
 
+// maxBacktestEvaluations is the maximum number of evaluations replayed by a backtest, it bounds the window of the
+// backtest to the rule interval times maxBacktestEvaluations.
+const maxBacktestEvaluations = 10000
+
 func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
 	if !srv.featureManager.IsEnabled(featuremgmt.FlagAlertingBacktesting) {
 		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
 	}
 
-	if cmd.From.After(cmd.To) {
-		return ErrResp(400, nil, "From cannot be greater than To")
-	}
-
 	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
 
 	if err != nil {
@@ -169,6 +169,26 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		return ErrResp(400, err, "")
 	}
 
+	if cmd.Evaluations < 0 {
+		return ErrResp(400, nil, "Bad number of evaluations")
+	}
+	if cmd.Evaluations > maxBacktestEvaluations {
+		return ErrResp(400, nil, "The number of evaluations cannot be greater than %d", maxBacktestEvaluations)
+	}
+	if cmd.Evaluations > 0 {
+		if !cmd.From.IsZero() {
+			return ErrResp(400, nil, "From cannot be set with the number of evaluations")
+		}
+		if cmd.To.IsZero() {
+			cmd.To = time.Now()
+		}
+		cmd.From = cmd.To.Add(-time.Duration(cmd.Evaluations*intervalSeconds) * time.Second)
+	}
+
+	if cmd.From.After(cmd.To) {
+		return ErrResp(400, nil, "From cannot be greater than To")
+	}
+
 	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: cmd.Data}, func(evaluator accesscontrol.Evaluator) bool {
 		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
 	}) {
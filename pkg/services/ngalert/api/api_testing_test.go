@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -14,11 +15,13 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -277,6 +280,46 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestBacktestAlertRule(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	srv := &TestingApiSrv{
+		accessControl:  acMock.New(),
+		cfg:            &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second},
+		featureManager: featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting),
+	}
+
+	t.Run("should return 400 if the number of evaluations exceeds the maximum", func(t *testing.T) {
+		response := srv.BacktestAlertRule(rc, definitions.BacktestConfig{
+			Interval:    model.Duration(time.Minute),
+			Evaluations: maxBacktestEvaluations + 1,
+			NoDataState: definitions.NoData,
+			Data:        []models.AlertQuery{models.GenerateAlertQuery()},
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should accept the maximum number of evaluations", func(t *testing.T) {
+		data := models.GenerateAlertQuery()
+		response := srv.BacktestAlertRule(rc, definitions.BacktestConfig{
+			Interval:    model.Duration(time.Minute),
+			Evaluations: maxBacktestEvaluations,
+			NoDataState: definitions.NoData,
+			Data:        []models.AlertQuery{data},
+		})
+
+		// the request is rejected afterwards because the user cannot query the data source
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+}
+
 func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"sort"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -89,3 +90,73 @@ func AlertRuleGroupToApi(d models.AlertRuleGroup) definitions.AlertRuleGroup {
 		Rules:     rules,
 	}
 }
+
+// ContactPointsToExport converts the integrations of definitions.EmbeddedContactPoint to the contact points of the provisioning file format, grouped by name.
+func ContactPointsToExport(orgID int64, cps []definitions.EmbeddedContactPoint) []definitions.ContactPointExport {
+	result := make([]definitions.ContactPointExport, 0)
+	byName := make(map[string]int)
+	for _, cp := range cps {
+		idx, ok := byName[cp.Name]
+		if !ok {
+			idx = len(result)
+			byName[cp.Name] = idx
+			result = append(result, definitions.ContactPointExport{OrgID: orgID, Name: cp.Name})
+		}
+		var settings map[string]interface{}
+		if cp.Settings != nil {
+			settings = cp.Settings.MustMap()
+		}
+		result[idx].Receivers = append(result[idx].Receivers, definitions.ReceiverExport{
+			UID:                   cp.UID,
+			Type:                  cp.Type,
+			Settings:              settings,
+			DisableResolveMessage: cp.DisableResolveMessage,
+		})
+	}
+	return result
+}
+
+// RouteToExport converts definitions.Route to definitions.RouteExport, dropping the provenance.
+func RouteToExport(r definitions.Route) *definitions.RouteExport {
+	export := &definitions.RouteExport{
+		Receiver:          r.Receiver,
+		GroupByStr:        r.GroupByStr,
+		Match:             r.Match,
+		MatchRE:           r.MatchRE,
+		Matchers:          r.Matchers,
+		ObjectMatchers:    r.ObjectMatchers,
+		MuteTimeIntervals: r.MuteTimeIntervals,
+		Continue:          r.Continue,
+		GroupWait:         r.GroupWait,
+		GroupInterval:     r.GroupInterval,
+		RepeatInterval:    r.RepeatInterval,
+	}
+	for _, child := range r.Routes {
+		if child == nil {
+			continue
+		}
+		export.Routes = append(export.Routes, RouteToExport(*child))
+	}
+	return export
+}
+
+// MuteTimingsToExport converts definitions.MuteTimeInterval to the mute timings of the provisioning file format.
+func MuteTimingsToExport(orgID int64, mts []definitions.MuteTimeInterval) []definitions.MuteTimeIntervalExport {
+	result := make([]definitions.MuteTimeIntervalExport, 0, len(mts))
+	for _, mt := range mts {
+		result = append(result, definitions.MuteTimeIntervalExport{OrgID: orgID, MuteTimeInterval: mt.MuteTimeInterval})
+	}
+	return result
+}
+
+// TemplatesToExport converts the notification templates to the provisioning file format, sorted by name.
+func TemplatesToExport(orgID int64, templates map[string]string) []definitions.NotificationTemplateExport {
+	result := make([]definitions.NotificationTemplateExport, 0, len(templates))
+	for name, tmpl := range templates {
+		result = append(result, definitions.NotificationTemplateExport{OrgID: orgID, Name: name, Template: tmpl})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
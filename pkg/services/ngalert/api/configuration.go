@@ -33,6 +33,14 @@ func (f *ConfigurationApiHandler) handleRouteDeleteNGalertConfig(c *contextmodel
 	return f.grafana.RouteDeleteNGalertConfig(c)
 }
 
+func (f *ConfigurationApiHandler) handleRouteGetExternalAlertmanagerConfigSync(c *contextmodel.ReqContext) response.Response {
+	return f.grafana.RouteGetExternalAlertmanagerConfigSync(c)
+}
+
+func (f *ConfigurationApiHandler) handleRoutePostExternalAlertmanagerConfigSync(c *contextmodel.ReqContext, body apimodels.PostableExternalAlertmanagerConfigSync) response.Response {
+	return f.grafana.RoutePostExternalAlertmanagerConfigSync(c, body)
+}
+
 func (f *ConfigurationApiHandler) handleRouteGetStatus(c *contextmodel.ReqContext) response.Response {
 	return f.grafana.RouteGetAlertingStatus(c)
 }
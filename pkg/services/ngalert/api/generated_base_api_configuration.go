@@ -21,8 +21,10 @@ import (
 type ConfigurationApi interface {
 	RouteDeleteNGalertConfig(*contextmodel.ReqContext) response.Response
 	RouteGetAlertmanagers(*contextmodel.ReqContext) response.Response
+	RouteGetExternalAlertmanagerConfigSync(*contextmodel.ReqContext) response.Response
 	RouteGetNGalertConfig(*contextmodel.ReqContext) response.Response
 	RouteGetStatus(*contextmodel.ReqContext) response.Response
+	RoutePostExternalAlertmanagerConfigSync(*contextmodel.ReqContext) response.Response
 	RoutePostNGalertConfig(*contextmodel.ReqContext) response.Response
 }
 
@@ -32,12 +34,23 @@ func (f *ConfigurationApiHandler) RouteDeleteNGalertConfig(ctx *contextmodel.Req
 func (f *ConfigurationApiHandler) RouteGetAlertmanagers(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertmanagers(ctx)
 }
+func (f *ConfigurationApiHandler) RouteGetExternalAlertmanagerConfigSync(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetExternalAlertmanagerConfigSync(ctx)
+}
 func (f *ConfigurationApiHandler) RouteGetNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetNGalertConfig(ctx)
 }
 func (f *ConfigurationApiHandler) RouteGetStatus(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetStatus(ctx)
 }
+func (f *ConfigurationApiHandler) RoutePostExternalAlertmanagerConfigSync(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PostableExternalAlertmanagerConfigSync{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostExternalAlertmanagerConfigSync(ctx, conf)
+}
 func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.PostableNGalertConfig{}
@@ -69,6 +82,16 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/ngalert/alertmanagers/config_sync"),
+			api.authorize(http.MethodGet, "/api/v1/ngalert/alertmanagers/config_sync"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/ngalert/alertmanagers/config_sync",
+				srv.RouteGetExternalAlertmanagerConfigSync,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			api.authorize(http.MethodGet, "/api/v1/ngalert/admin_config"),
@@ -89,6 +112,16 @@ func (api *API) RegisterConfigurationApiEndpoints(srv ConfigurationApi, m *metri
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/ngalert/alertmanagers/config_sync"),
+			api.authorize(http.MethodPost, "/api/v1/ngalert/alertmanagers/config_sync"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/ngalert/alertmanagers/config_sync",
+				srv.RoutePostExternalAlertmanagerConfigSync,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/ngalert/admin_config"),
 			api.authorize(http.MethodPost, "/api/v1/ngalert/admin_config"),
@@ -29,6 +29,7 @@ type ProvisioningApi interface {
 	RouteGetAlertRuleGroupExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRules(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertingConfigurationExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
@@ -95,6 +96,9 @@ func (f *ProvisioningApiHandler) RouteGetAlertRules(ctx *contextmodel.ReqContext
 func (f *ProvisioningApiHandler) RouteGetAlertRulesExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRulesExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertingConfigurationExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertingConfigurationExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpoints(ctx)
 }
@@ -306,6 +310,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/export",
+				srv.RouteGetAlertingConfigurationExport,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points"),
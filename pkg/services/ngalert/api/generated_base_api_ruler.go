@@ -23,6 +23,7 @@ type RulerApi interface {
 	RouteDeleteNamespaceGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteDeleteNamespaceRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteDeleteRuleGroupConfig(*contextmodel.ReqContext) response.Response
+	RouteGetGrafanaRuleDependencies(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaRuleGroupConfig(*contextmodel.ReqContext) response.Response
 	RouteGetGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetNamespaceGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
@@ -57,6 +58,9 @@ func (f *RulerApiHandler) RouteDeleteRuleGroupConfig(ctx *contextmodel.ReqContex
 	groupnameParam := web.Params(ctx.Req)[":Groupname"]
 	return f.handleRouteDeleteRuleGroupConfig(ctx, datasourceUIDParam, namespaceParam, groupnameParam)
 }
+func (f *RulerApiHandler) RouteGetGrafanaRuleDependencies(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaRuleDependencies(ctx)
+}
 func (f *RulerApiHandler) RouteGetGrafanaRuleGroupConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	namespaceParam := web.Params(ctx.Req)[":Namespace"]
@@ -163,6 +167,16 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/dependencies"),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/dependencies"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/dependencies",
+				srv.RouteGetGrafanaRuleDependencies,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/ruler/grafana/api/v1/rules"),
 			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/rules"),
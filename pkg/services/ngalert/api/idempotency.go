@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// idempotentRoutes are the alert rule writes whose responses are replayed when they are retried with an
+// Idempotency-Key header.
+var idempotentRoutes = map[string]bool{
+	http.MethodPost + toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}"):                true,
+	http.MethodDelete + toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}"):              true,
+	http.MethodDelete + toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}/{Groupname}"):  true,
+	http.MethodPost + toMacaronPath("/api/v1/provisioning/alert-rules"):                           true,
+	http.MethodPut + toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"):                      true,
+	http.MethodDelete + toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"):                   true,
+	http.MethodPut + toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}"): true,
+}
+
+// idempotentRouteRegister adds the idempotency middleware to the idempotent routes registered through it,
+// right before the route handler. The other routes are registered unchanged.
+type idempotentRouteRegister struct {
+	routing.RouteRegister
+	prefix     string
+	middleware web.Handler
+}
+
+func (r *idempotentRouteRegister) Post(pattern string, handlers ...web.Handler) {
+	r.RouteRegister.Post(pattern, r.handlers(http.MethodPost, pattern, handlers)...)
+}
+
+func (r *idempotentRouteRegister) Put(pattern string, handlers ...web.Handler) {
+	r.RouteRegister.Put(pattern, r.handlers(http.MethodPut, pattern, handlers)...)
+}
+
+func (r *idempotentRouteRegister) Delete(pattern string, handlers ...web.Handler) {
+	r.RouteRegister.Delete(pattern, r.handlers(http.MethodDelete, pattern, handlers)...)
+}
+
+func (r *idempotentRouteRegister) Group(pattern string, fn func(routing.RouteRegister), handlers ...web.Handler) {
+	r.RouteRegister.Group(pattern, func(group routing.RouteRegister) {
+		fn(&idempotentRouteRegister{RouteRegister: group, prefix: r.prefix + pattern, middleware: r.middleware})
+	}, handlers...)
+}
+
+func (r *idempotentRouteRegister) handlers(method, pattern string, handlers []web.Handler) []web.Handler {
+	if len(handlers) == 0 || !idempotentRoutes[method+r.prefix+pattern] {
+		return handlers
+	}
+	last := len(handlers) - 1
+	withMiddleware := make([]web.Handler, 0, len(handlers)+1)
+	withMiddleware = append(withMiddleware, handlers[:last]...)
+	return append(withMiddleware, r.middleware, handlers[last])
+}
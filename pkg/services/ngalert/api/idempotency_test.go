@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeRouter struct {
+	handlers map[string][]web.Handler
+}
+
+func (r *fakeRouter) Handle(method, pattern string, handlers []web.Handler) {
+	r.handlers[method+pattern] = handlers
+}
+
+func (r *fakeRouter) Get(pattern string, handlers ...web.Handler) {
+	r.Handle(http.MethodGet, pattern, handlers)
+}
+
+func TestIdempotentRouteRegister(t *testing.T) {
+	rr := routing.NewRouteRegister()
+	register := &idempotentRouteRegister{RouteRegister: rr, middleware: "idempotency"}
+	register.Group("", func(group routing.RouteRegister) {
+		group.Post(toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}"), "authorize", "handler")
+		group.Get(toMacaronPath("/api/ruler/grafana/api/v1/rules/{Namespace}"), "authorize", "handler")
+		group.Post(toMacaronPath("/api/v1/provisioning/contact-points"), "authorize", "handler")
+		group.Delete(toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"), "authorize", "handler")
+	}, "signedIn")
+
+	router := &fakeRouter{handlers: map[string][]web.Handler{}}
+	rr.Register(router)
+
+	require.Equal(t, []web.Handler{"signedIn", "authorize", "idempotency", "handler"}, router.handlers[http.MethodPost+"/api/ruler/grafana/api/v1/rules/:Namespace"])
+	require.Equal(t, []web.Handler{"signedIn", "authorize", "idempotency", "handler"}, router.handlers[http.MethodDelete+"/api/v1/provisioning/alert-rules/:UID"])
+	require.Equal(t, []web.Handler{"signedIn", "authorize", "handler"}, router.handlers[http.MethodGet+"/api/ruler/grafana/api/v1/rules/:Namespace"])
+	require.Equal(t, []web.Handler{"signedIn", "authorize", "handler"}, router.handlers[http.MethodPost+"/api/v1/provisioning/contact-points"])
+}
@@ -92,6 +92,10 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *contextmode
 	return f.svc.RouteGetAlertRulesExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAlertingConfigurationExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertingConfigurationExport(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
 	return f.svc.RoutePostAlertRule(ctx, ar)
 }
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 )
 
@@ -24,6 +26,31 @@ import (
 //     Responses:
 //		 200: GettableAlertmanagers
 
+// swagger:route GET /api/v1/ngalert/alertmanagers/config_sync configuration RouteGetExternalAlertmanagerConfigSync
+//
+//  Get the status of the synchronization of the Grafana-managed contact points and notification policies with the external Alertmanagers of the user's organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//		 200: ExternalAlertmanagerConfigSyncStatus
+
+// swagger:route POST /api/v1/ngalert/alertmanagers/config_sync configuration RoutePostExternalAlertmanagerConfigSync
+//
+//  Synchronize the Grafana-managed contact points and notification policies with the external Alertmanagers of the user's organization now.
+//  The configuration of an external Alertmanager that was changed outside of Grafana is only overwritten if force is true.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//		 200: ExternalAlertmanagerConfigSyncStatus
+//		 400: ValidationError
+
 // swagger:route GET /api/v1/ngalert/admin_config configuration RouteGetNGalertConfig
 //
 //  Get the NGalert configuration of the user's organization, returns 404 if no configuration is present.
@@ -72,8 +99,68 @@ const (
 	InternalAlertmanager       AlertmanagersChoice = "internal"
 	ExternalAlertmanagers      AlertmanagersChoice = "external"
 	HandleGrafanaManagedAlerts                     = "handleGrafanaManagedAlerts"
+	// SyncGrafanaManagedConfig is the key of the datasource setting that enables pushing the Grafana-managed
+	// contact points and notification policies to the external Alertmanager.
+	SyncGrafanaManagedConfig = "syncGrafanaManagedConfig"
 )
 
+// swagger:parameters RoutePostExternalAlertmanagerConfigSync
+type ExternalAlertmanagerConfigSyncParams struct {
+	// in:body
+	Body PostableExternalAlertmanagerConfigSync
+}
+
+// swagger:model
+type PostableExternalAlertmanagerConfigSync struct {
+	// Force overwrites the configuration of the external Alertmanagers that was changed outside of Grafana.
+	Force bool `json:"force"`
+}
+
+// swagger:enum ExternalAlertmanagerConfigSyncState
+type ExternalAlertmanagerConfigSyncState string
+
+const (
+	// ConfigSyncInSync is the state of an external Alertmanager that has the configuration of Grafana.
+	ConfigSyncInSync ExternalAlertmanagerConfigSyncState = "in_sync"
+	// ConfigSyncDrifted is the state of an external Alertmanager whose configuration was changed outside of Grafana.
+	// Its configuration is not overwritten until a synchronization is forced.
+	ConfigSyncDrifted ExternalAlertmanagerConfigSyncState = "drifted"
+	// ConfigSyncError is the state of an external Alertmanager whose configuration could not be read or written.
+	ConfigSyncError ExternalAlertmanagerConfigSyncState = "error"
+	// ConfigSyncUnsupported is the state of an external Alertmanager whose implementation has no configuration API.
+	ConfigSyncUnsupported ExternalAlertmanagerConfigSyncState = "unsupported"
+)
+
+// swagger:model
+type ExternalAlertmanagerConfigSyncStatus struct {
+	Alertmanagers []ExternalAlertmanagerConfigSync `json:"alertmanagers"`
+}
+
+// ExternalAlertmanagerConfigSync is the result of the last synchronization with an external Alertmanager.
+type ExternalAlertmanagerConfigSync struct {
+	DatasourceUID  string                              `json:"datasourceUid"`
+	DatasourceName string                              `json:"datasourceName"`
+	State          ExternalAlertmanagerConfigSyncState `json:"state"`
+	Error          string                              `json:"error,omitempty"`
+	LastSync       time.Time                           `json:"lastSync"`
+	// LastPush is the last time the configuration of Grafana was written to the external Alertmanager.
+	LastPush *time.Time `json:"lastPush,omitempty"`
+	// Discrepancies are the differences between the configuration of the external Alertmanager and the configuration of Grafana.
+	Discrepancies []ConfigDiscrepancy `json:"discrepancies,omitempty"`
+	// UnsupportedIntegrations are the integrations of the contact points that have no equivalent in the external Alertmanager
+	// and are not pushed, as contact point/integration type.
+	UnsupportedIntegrations []string `json:"unsupportedIntegrations,omitempty"`
+}
+
+// ConfigDiscrepancy is a part of the configuration that differs between Grafana and an external Alertmanager.
+type ConfigDiscrepancy struct {
+	// Path of the part of the configuration, for example receivers/my-contact-point or template_files/my.tmpl
+	Path string `json:"path"`
+	// Kind is missing if the part is only in Grafana, unexpected if it is only in the external Alertmanager
+	// and changed if both have it but with different values.
+	Kind string `json:"kind"`
+}
+
 // swagger:model
 type PostableNGalertConfig struct {
 	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
@@ -723,9 +723,35 @@ type Route struct {
 	GroupInterval  *model.Duration `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
 	RepeatInterval *model.Duration `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
 
+	// FlappingAction is what happens to the notifications of flapping alerts matching the route. Inherited from the
+	// parent route when empty.
+	FlappingAction FlappingAction `yaml:"flapping_action,omitempty" json:"flapping_action,omitempty"`
+
 	Provenance Provenance `yaml:"provenance,omitempty" json:"provenance,omitempty"`
 }
 
+// FlappingAction defines how the notifications of flapping alerts are handled by a notification policy.
+type FlappingAction string
+
+const (
+	// FlappingActionNotify sends the notifications of flapping alerts as for any other alert.
+	FlappingActionNotify FlappingAction = "notify"
+	// FlappingActionSuppress does not send any notification for flapping alerts.
+	FlappingActionSuppress FlappingAction = "suppress"
+	// FlappingActionCoalesce sends the notifications of flapping alerts when they fire but not when they resolve,
+	// so that the receivers get one notification for as long as the alert keeps flapping.
+	FlappingActionCoalesce FlappingAction = "coalesce"
+)
+
+// Validate returns an error if the action is not known. The empty action is valid.
+func (a FlappingAction) Validate() error {
+	switch a {
+	case "", FlappingActionNotify, FlappingActionSuppress, FlappingActionCoalesce:
+		return nil
+	}
+	return fmt.Errorf("unknown flapping_action %q, must be one of %q, %q or %q", a, FlappingActionNotify, FlappingActionSuppress, FlappingActionCoalesce)
+}
+
 // UnmarshalYAML implements the yaml.Unmarshaler interface for Route. This is a copy of alertmanager's upstream except it removes validation on the label key.
 func (r *Route) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	type plain Route
@@ -44,6 +44,9 @@ func (r *Route) validateChild() error {
 	if r.RepeatInterval != nil && time.Duration(*r.RepeatInterval) == time.Duration(0) {
 		return fmt.Errorf("repeat_interval cannot be zero")
 	}
+	if err := r.FlappingAction.Validate(); err != nil {
+		return err
+	}
 
 	// Routes are a self-referential structure.
 	if r.Routes != nil {
@@ -128,6 +128,16 @@ import (
 //       202: Ack
 //       404: NotFound
 
+// swagger:route Get /api/ruler/grafana/api/v1/dependencies ruler RouteGetGrafanaRuleDependencies
+//
+// Get the dependencies between the rules
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleDependencies
+
 // swagger:parameters RoutePostNameRulesConfig RoutePostNameGrafanaRulesConfig
 type NamespaceConfig struct {
 	// in:path
@@ -166,6 +176,33 @@ type RuleGroupConfigResponse struct {
 // swagger:model
 type NamespaceConfigResponse map[string][]GettableRuleGroupConfig
 
+// swagger:model
+type RuleDependencies struct {
+	Nodes []RuleDependencyNode `json:"nodes"`
+	Edges []RuleDependencyEdge `json:"edges"`
+}
+
+// RuleDependencyNode is a rule of the graph of dependencies.
+type RuleDependencyNode struct {
+	UID          string `json:"uid"`
+	Title        string `json:"title"`
+	NamespaceUID string `json:"namespaceUid"`
+	RuleGroup    string `json:"ruleGroup"`
+	// Composite is true if the rule evaluates the firing state of other rules.
+	Composite  bool   `json:"composite"`
+	Expression string `json:"expression,omitempty"`
+}
+
+// RuleDependencyEdge is a reference of a composite rule to a rule it depends on.
+type RuleDependencyEdge struct {
+	// From is the UID of the composite rule.
+	From string `json:"from"`
+	// To is the UID of the rule it depends on.
+	To string `json:"to"`
+	// Ref is the identifier of the rule in the expression of the composite rule.
+	Ref string `json:"ref"`
+}
+
 // swagger:model
 type PostableRuleGroupConfig struct {
 	Name     string                     `yaml:"name" json:"name"`
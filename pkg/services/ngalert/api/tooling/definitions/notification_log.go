@@ -0,0 +1,78 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/notifications/log notifications RouteGetNotificationLog
+//
+// Query the delivery attempts of notifications to contact points.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: NotificationLog
+//       400: ValidationError
+
+// swagger:parameters RouteGetNotificationLog
+type NotificationLogParams struct {
+	// Filter the attempts to those of the contact point with this name.
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+
+	// Filter the attempts to those of the integration with this UID.
+	// in: query
+	// required: false
+	IntegrationUID string `json:"integrationUid"`
+
+	// Filter the attempts to those of the integrations of this type, e.g. slack or email.
+	// in: query
+	// required: false
+	Integration string `json:"integration"`
+
+	// Filter the attempts by status, either success or failure.
+	// in: query
+	// required: false
+	Status string `json:"status"`
+
+	// Only return the attempts made at or after this time, in Unix epoch seconds.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// Only return the attempts made at or before this time, in Unix epoch seconds.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// The maximum number of attempts to return, the most recent first.
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type NotificationLog struct {
+	Deliveries []NotificationDelivery `json:"deliveries"`
+}
+
+// swagger:model
+type NotificationDelivery struct {
+	// Receiver is the name of the contact point.
+	Receiver         string `json:"receiver"`
+	IntegrationUID   string `json:"integrationUid"`
+	IntegrationType  string `json:"integration"`
+	IntegrationIndex int    `json:"integrationIndex"`
+	GroupKey         string `json:"groupKey"`
+	// Status is either success or failure.
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Retry is the number of attempts that preceded this one for the same notification, 0 for the first attempt.
+	Retry int `json:"retry"`
+	// WillRetry is true when the attempt failed and the notification is going to be retried.
+	WillRetry bool      `json:"willRetry"`
+	Alerts    int       `json:"alerts"`
+	LatencyMs int64     `json:"latencyMs"`
+	SentAt    time.Time `json:"sentAt"`
+}
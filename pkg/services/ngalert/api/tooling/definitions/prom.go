@@ -107,9 +107,24 @@ type AlertingRule struct {
 	Annotations overrideLabels `json:"annotations,omitempty"`
 	// required: true
 	Alerts []*Alert `json:"alerts,omitempty"`
+	// EvaluationCost is the cost of the evaluations of Grafana-managed rules.
+	EvaluationCost *EvaluationCost `json:"evaluationCost,omitempty"`
 	Rule
 }
 
+// EvaluationCost is the cost of the evaluations of a rule, in time spent evaluating and values returned by the data sources.
+// swagger:model
+type EvaluationCost struct {
+	LastDurationSeconds float64 `json:"lastDurationSeconds"`
+	LastDatapoints      int64   `json:"lastDatapoints"`
+	// WindowDurationSeconds and WindowDatapoints are the cost of the evaluations in the rolling window of the evaluation budget.
+	WindowDurationSeconds float64 `json:"windowDurationSeconds"`
+	WindowDatapoints      int64   `json:"windowDatapoints"`
+	// PausedUntil is set while the evaluations are paused because the rule exceeded its quota or the budget of its folder.
+	PausedUntil *time.Time `json:"pausedUntil,omitempty"`
+	PauseReason string     `json:"pauseReason,omitempty"`
+}
+
 // adapted from cortex
 // swagger:model
 type Rule struct {
@@ -139,6 +154,8 @@ type Alert struct {
 	ActiveAt *time.Time `json:"activeAt"`
 	// required: true
 	Value string `json:"value"`
+	// Flapping is true if the alert instance changed between firing and not firing too often recently.
+	Flapping bool `json:"flapping,omitempty"`
 }
 
 // override the labels type with a map for generation.
@@ -74,6 +74,15 @@ import (
 //     Responses:
 //       204: description: The alert rule was deleted successfully.
 
+// swagger:parameters RouteGetAlertRules
+type AlertRulesWatchParam struct {
+	// Stream the created, updated and deleted alert rules as newline delimited JSON instead of listing them.
+	// in: query
+	// required: false
+	// default: false
+	Watch bool `json:"watch"`
+}
+
 // swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule RouteGetAlertRuleExport
 type AlertRuleUIDReference struct {
 	// Alert rule UID
@@ -193,7 +202,7 @@ type AlertRuleGroupMetadata struct {
 	Interval int64 `json:"interval"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetAlertingConfigurationExport
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
@@ -0,0 +1,81 @@
+package definitions
+
+import (
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting/file"
+)
+
+// swagger:route GET /api/v1/provisioning/export provisioning RouteGetAlertingConfigurationExport
+//
+// Export the alert rules, contact points, notification policies, mute timings and templates of the organization in provisioning file format.
+// Secure settings of contact points are redacted; provisioning the file again keeps the stored secrets of existing contact points.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingConfigurationExport
+
+// swagger:model
+type AlertingConfigurationExport struct {
+	APIVersion    int64                        `json:"apiVersion" yaml:"apiVersion"`
+	Groups        []file.AlertRuleGroupExport  `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints []ContactPointExport         `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies      []NotificationPolicyExport   `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimes     []MuteTimeIntervalExport     `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	Templates     []NotificationTemplateExport `json:"templates,omitempty" yaml:"templates,omitempty"`
+}
+
+// ContactPointExport is the provisioned file export of a contact point and its integrations.
+type ContactPointExport struct {
+	OrgID     int64            `json:"orgId" yaml:"orgId"`
+	Name      string           `json:"name" yaml:"name"`
+	Receivers []ReceiverExport `json:"receivers" yaml:"receivers"`
+}
+
+// ReceiverExport is the provisioned file export of an integration of a contact point.
+type ReceiverExport struct {
+	UID                   string                 `json:"uid" yaml:"uid"`
+	Type                  string                 `json:"type" yaml:"type"`
+	Settings              map[string]interface{} `json:"settings" yaml:"settings"`
+	DisableResolveMessage bool                   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+}
+
+// NotificationPolicyExport is the provisioned file export of the notification policy tree of an organization.
+type NotificationPolicyExport struct {
+	OrgID        int64 `json:"orgId" yaml:"orgId"`
+	*RouteExport `json:",inline" yaml:",inline"`
+}
+
+// RouteExport is the provisioned file export of a Route, without the provenance.
+type RouteExport struct {
+	Receiver          string              `yaml:"receiver,omitempty" json:"receiver,omitempty"`
+	GroupByStr        []string            `yaml:"group_by,omitempty" json:"group_by,omitempty"`
+	Match             map[string]string   `yaml:"match,omitempty" json:"match,omitempty"`
+	MatchRE           config.MatchRegexps `yaml:"match_re,omitempty" json:"match_re,omitempty"`
+	Matchers          config.Matchers     `yaml:"matchers,omitempty" json:"matchers,omitempty"`
+	ObjectMatchers    ObjectMatchers      `yaml:"object_matchers,omitempty" json:"object_matchers,omitempty"`
+	MuteTimeIntervals []string            `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty"`
+	Continue          bool                `yaml:"continue,omitempty" json:"continue,omitempty"`
+	Routes            []*RouteExport      `yaml:"routes,omitempty" json:"routes,omitempty"`
+	GroupWait         *model.Duration     `yaml:"group_wait,omitempty" json:"group_wait,omitempty"`
+	GroupInterval     *model.Duration     `yaml:"group_interval,omitempty" json:"group_interval,omitempty"`
+	RepeatInterval    *model.Duration     `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty"`
+}
+
+// MuteTimeIntervalExport is the provisioned file export of a mute timing.
+type MuteTimeIntervalExport struct {
+	OrgID                   int64 `json:"orgId" yaml:"orgId"`
+	config.MuteTimeInterval `json:",inline" yaml:",inline"`
+}
+
+// NotificationTemplateExport is the provisioned file export of a notification template.
+type NotificationTemplateExport struct {
+	OrgID    int64  `json:"orgId" yaml:"orgId"`
+	Name     string `json:"name" yaml:"name"`
+	Template string `json:"template" yaml:"template"`
+}
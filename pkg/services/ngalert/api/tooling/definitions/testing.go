@@ -186,6 +186,8 @@ type BacktestConfig struct {
 	From     time.Time      `json:"from"`
 	To       time.Time      `json:"to"`
 	Interval model.Duration `json:"interval,omitempty"`
+	// Number of evaluations to replay, ending at to or now, at most 10000. It cannot be used with from.
+	Evaluations int64 `json:"evaluations,omitempty"`
 
 	Condition string              `json:"condition"`
 	Data      []models.AlertQuery `json:"data"` // TODO yuri. Create API model for AlertQuery
@@ -0,0 +1,223 @@
+package configsync
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// checksumTemplate is the name of the template file that records the checksum of the configuration pushed by Grafana,
+// to tell the changes made outside of Grafana from the changes of the configuration of Grafana.
+const checksumTemplate = "grafana_config_sync.tmpl"
+
+var checksumRegexp = regexp.MustCompile(`grafana config sync checksum: ([0-9a-f]+)`)
+
+// remoteConfig is the configuration of a Mimir or Cortex Alertmanager, as read and written by their configuration API.
+type remoteConfig struct {
+	TemplateFiles      map[string]string `yaml:"template_files"`
+	AlertmanagerConfig string            `yaml:"alertmanager_config"`
+}
+
+// checksum returns the checksum of the configuration, without the template file that records it.
+func (c *remoteConfig) checksum() string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(c.AlertmanagerConfig))
+	names := make([]string, 0, len(c.TemplateFiles))
+	for name := range c.TemplateFiles {
+		if name != checksumTemplate {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(c.TemplateFiles[name]))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// recordedChecksum returns the checksum recorded when Grafana pushed the configuration, false if the configuration
+// was not pushed by Grafana.
+func (c *remoteConfig) recordedChecksum() (string, bool) {
+	m := checksumRegexp.FindStringSubmatch(c.TemplateFiles[checksumTemplate])
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// recordChecksum adds the template file that records the checksum of the configuration.
+func (c *remoteConfig) recordChecksum() {
+	sum := c.checksum()
+	if c.TemplateFiles == nil {
+		c.TemplateFiles = make(map[string]string)
+	}
+	c.TemplateFiles[checksumTemplate] = fmt.Sprintf("{{/* grafana config sync checksum: %s */}}\n", sum)
+}
+
+// decryptFn returns the decrypted secure setting of an integration, or the fallback if it is not set.
+type decryptFn func(ctx context.Context, sjd map[string][]byte, key, fallback string) string
+
+// integrationConverter returns the configuration of an integration of the upstream Alertmanager from the settings
+// of a Grafana integration, and the key of the receiver under which it is listed.
+type integrationConverter func(settings *simplejson.Json, secure func(key string) string) (string, map[string]interface{}, error)
+
+var integrationConverters = map[string]integrationConverter{
+	"webhook": func(settings *simplejson.Json, secure func(string) string) (string, map[string]interface{}, error) {
+		cfg := map[string]interface{}{"url": settings.Get("url").MustString()}
+		if username := settings.Get("username").MustString(); username != "" {
+			cfg["http_config"] = map[string]interface{}{
+				"basic_auth": map[string]interface{}{"username": username, "password": secure("password")},
+			}
+		}
+		if maxAlerts := settings.Get("maxAlerts").MustInt(0); maxAlerts > 0 {
+			cfg["max_alerts"] = maxAlerts
+		}
+		return "webhook_configs", cfg, nil
+	},
+	"slack": func(settings *simplejson.Json, secure func(string) string) (string, map[string]interface{}, error) {
+		cfg := map[string]interface{}{}
+		if url := secure("url"); url != "" {
+			cfg["api_url"] = url
+		} else if token := secure("token"); token != "" {
+			cfg["api_url"] = "https://slack.com/api/chat.postMessage"
+			cfg["http_config"] = map[string]interface{}{
+				"authorization": map[string]interface{}{"credentials": token},
+			}
+		} else {
+			return "", nil, fmt.Errorf("neither a webhook URL nor a token is set")
+		}
+		setIfNotEmpty(cfg, "channel", settings.Get("recipient").MustString())
+		setIfNotEmpty(cfg, "username", settings.Get("username").MustString())
+		return "slack_configs", cfg, nil
+	},
+	"pagerduty": func(settings *simplejson.Json, secure func(string) string) (string, map[string]interface{}, error) {
+		cfg := map[string]interface{}{"routing_key": secure("integrationKey")}
+		for grafanaKey, key := range map[string]string{
+			"severity":   "severity",
+			"class":      "class",
+			"component":  "component",
+			"group":      "group",
+			"client":     "client",
+			"client_url": "client_url",
+		} {
+			setIfNotEmpty(cfg, key, settings.Get(grafanaKey).MustString())
+		}
+		return "pagerduty_configs", cfg, nil
+	},
+	"opsgenie": func(settings *simplejson.Json, secure func(string) string) (string, map[string]interface{}, error) {
+		cfg := map[string]interface{}{"api_key": secure("apiKey")}
+		// Grafana is configured with the URL of the alerts API while the Alertmanager is configured with the base URL
+		if apiURL := settings.Get("apiUrl").MustString(); apiURL != "" {
+			cfg["api_url"] = strings.TrimSuffix(apiURL, "v2/alerts")
+		}
+		return "opsgenie_configs", cfg, nil
+	},
+	"discord": func(settings *simplejson.Json, secure func(string) string) (string, map[string]interface{}, error) {
+		url := secure("url")
+		if url == "" {
+			url = settings.Get("url").MustString()
+		}
+		return "discord_configs", map[string]interface{}{"webhook_url": url}, nil
+	},
+	"telegram": func(settings *simplejson.Json, secure func(string) string) (string, map[string]interface{}, error) {
+		chatID, err := strconv.ParseInt(settings.Get("chatid").MustString(), 10, 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("the chat ID is not a number: %w", err)
+		}
+		return "telegram_configs", map[string]interface{}{"bot_token": secure("bottoken"), "chat_id": chatID}, nil
+	},
+}
+
+// buildRemoteConfig converts the configuration of the Grafana Alertmanager to the configuration of a Mimir or Cortex
+// Alertmanager. The integrations that cannot be converted are skipped and returned as contact point/integration type,
+// the contact points are always pushed so that the notification policies that use them remain valid.
+func buildRemoteConfig(ctx context.Context, cfg *apimodels.PostableUserConfig, decrypt decryptFn) (*remoteConfig, []string, error) {
+	var skipped []string
+	receivers := make([]interface{}, 0, len(cfg.AlertmanagerConfig.Receivers))
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		receiver := map[string]interface{}{"name": r.Name}
+		for _, gr := range r.GrafanaManagedReceivers {
+			key, integration, err := convertIntegration(ctx, gr, decrypt)
+			if err != nil {
+				skipped = append(skipped, fmt.Sprintf("%s/%s", r.Name, gr.Type))
+				continue
+			}
+			integration["send_resolved"] = !gr.DisableResolveMessage
+			configs, _ := receiver[key].([]interface{})
+			receiver[key] = append(configs, integration)
+		}
+		receivers = append(receivers, receiver)
+	}
+
+	am := map[string]interface{}{
+		"receivers": receivers,
+	}
+	if route := cfg.AlertmanagerConfig.Route; route != nil {
+		am["route"] = route.AsAMRoute()
+	}
+	if len(cfg.AlertmanagerConfig.MuteTimeIntervals) > 0 {
+		am["mute_time_intervals"] = cfg.AlertmanagerConfig.MuteTimeIntervals
+	}
+	if len(cfg.AlertmanagerConfig.InhibitRules) > 0 {
+		am["inhibit_rules"] = cfg.AlertmanagerConfig.InhibitRules
+	}
+	b, err := yaml.Marshal(am)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode the configuration: %w", err)
+	}
+
+	templates := make(map[string]string, len(cfg.TemplateFiles))
+	for name, content := range cfg.TemplateFiles {
+		// the default template of Grafana is added by Grafana when the configuration is applied
+		if name == "__default__.tmpl" {
+			continue
+		}
+		templates[name] = content
+	}
+
+	result := &remoteConfig{TemplateFiles: templates, AlertmanagerConfig: string(b)}
+	result.recordChecksum()
+	return result, skipped, nil
+}
+
+func convertIntegration(ctx context.Context, gr *apimodels.PostableGrafanaReceiver, decrypt decryptFn) (string, map[string]interface{}, error) {
+	convert, ok := integrationConverters[gr.Type]
+	if !ok {
+		return "", nil, fmt.Errorf("integration %q is not supported", gr.Type)
+	}
+	settings, err := simplejson.NewJson(gr.Settings)
+	if err != nil {
+		return "", nil, err
+	}
+	secureSettings := make(map[string][]byte, len(gr.SecureSettings))
+	for k, v := range gr.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to decode secure setting %q: %w", k, err)
+		}
+		secureSettings[k] = d
+	}
+	secure := func(key string) string {
+		return decrypt(ctx, secureSettings, key, settings.Get(key).MustString())
+	}
+	return convert(settings, secure)
+}
+
+func setIfNotEmpty(cfg map[string]interface{}, key, value string) {
+	if value != "" {
+		cfg[key] = value
+	}
+}
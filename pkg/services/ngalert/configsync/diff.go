@@ -0,0 +1,114 @@
+package configsync
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	discrepancyMissing    = "missing"
+	discrepancyUnexpected = "unexpected"
+	discrepancyChanged    = "changed"
+)
+
+// namedSections are the parts of the configuration that are lists of items identified by their name,
+// which are compared item by item.
+var namedSections = map[string]bool{
+	"receivers":           true,
+	"mute_time_intervals": true,
+	"time_intervals":      true,
+}
+
+// diffConfigs returns the parts of the configuration of the external Alertmanager that differ from the configuration
+// of Grafana. The configurations are decoded before they are compared, so that the formatting does not matter.
+func diffConfigs(grafana, remote *remoteConfig) ([]apimodels.ConfigDiscrepancy, error) {
+	var expected, actual map[string]interface{}
+	if err := yaml.Unmarshal([]byte(grafana.AlertmanagerConfig), &expected); err != nil {
+		return nil, fmt.Errorf("failed to decode the configuration of Grafana: %w", err)
+	}
+	if err := yaml.Unmarshal([]byte(remote.AlertmanagerConfig), &actual); err != nil {
+		return nil, fmt.Errorf("failed to decode the configuration of the Alertmanager: %w", err)
+	}
+
+	var result []apimodels.ConfigDiscrepancy
+	for _, key := range unionKeys(expected, actual) {
+		if namedSections[key] {
+			result = append(result, diffNamed(key, byName(expected[key]), byName(actual[key]))...)
+			continue
+		}
+		if d, ok := diffValue(key, expected, actual); ok {
+			result = append(result, d)
+		}
+	}
+
+	templates := make(map[string]interface{}, len(grafana.TemplateFiles))
+	for name, content := range grafana.TemplateFiles {
+		templates[name] = content
+	}
+	remoteTemplates := make(map[string]interface{}, len(remote.TemplateFiles))
+	for name, content := range remote.TemplateFiles {
+		remoteTemplates[name] = content
+	}
+	delete(templates, checksumTemplate)
+	delete(remoteTemplates, checksumTemplate)
+	result = append(result, diffNamed("template_files", templates, remoteTemplates)...)
+	return result, nil
+}
+
+func diffNamed(section string, expected, actual map[string]interface{}) []apimodels.ConfigDiscrepancy {
+	var result []apimodels.ConfigDiscrepancy
+	for _, name := range unionKeys(expected, actual) {
+		if d, ok := diffValue(name, expected, actual); ok {
+			d.Path = section + "/" + d.Path
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func diffValue(key string, expected, actual map[string]interface{}) (apimodels.ConfigDiscrepancy, bool) {
+	e, inExpected := expected[key]
+	a, inActual := actual[key]
+	switch {
+	case !inActual:
+		return apimodels.ConfigDiscrepancy{Path: key, Kind: discrepancyMissing}, true
+	case !inExpected:
+		return apimodels.ConfigDiscrepancy{Path: key, Kind: discrepancyUnexpected}, true
+	case !reflect.DeepEqual(e, a):
+		return apimodels.ConfigDiscrepancy{Path: key, Kind: discrepancyChanged}, true
+	}
+	return apimodels.ConfigDiscrepancy{}, false
+}
+
+func byName(section interface{}) map[string]interface{} {
+	items, _ := section.([]interface{})
+	result := make(map[string]interface{}, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		result[name] = m
+	}
+	return result
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
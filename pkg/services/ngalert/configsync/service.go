@@ -0,0 +1,333 @@
+// Package configsync pushes the Grafana-managed contact points and notification policies to the external Alertmanagers
+// that handle the Grafana-managed alerts, and reports the external Alertmanagers whose configuration was changed
+// outside of Grafana instead of overwriting it.
+package configsync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/datasource"
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// configPath is the path of the configuration API of the Mimir and Cortex Alertmanagers.
+	configPath     = "/api/v1/alerts"
+	requestTimeout = 30 * time.Second
+	// maxErrorBodySize is how much of the body of an error response is included in the error.
+	maxErrorBodySize = 1024
+)
+
+// supportedImplementations are the implementations of the Alertmanager datasource that have a configuration API.
+// A datasource without implementation is a Cortex Alertmanager.
+var supportedImplementations = map[string]bool{
+	"":       true,
+	"cortex": true,
+	"mimir":  true,
+}
+
+// Store is the part of the alerting store that holds the configuration of the Grafana Alertmanagers.
+type Store interface {
+	GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error
+	GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([]*models.AlertConfiguration, error)
+}
+
+type Service struct {
+	cfg                setting.UnifiedAlertingSettings
+	store              Store
+	datasourceService  datasources.DataSourceService
+	decrypt            decryptFn
+	httpClientProvider httpclient.Provider
+	clock              clock.Clock
+	logger             log.Logger
+
+	// mtx serializes the synchronizations and protects the statuses
+	mtx sync.Mutex
+	// statuses are the results of the last synchronization, by organization and datasource UID
+	statuses map[int64]map[string]apimodels.ExternalAlertmanagerConfigSync
+}
+
+func NewService(cfg setting.UnifiedAlertingSettings, store Store, datasourceService datasources.DataSourceService,
+	decrypt decryptFn, clk clock.Clock) *Service {
+	return &Service{
+		cfg:                cfg,
+		store:              store,
+		datasourceService:  datasourceService,
+		decrypt:            decrypt,
+		httpClientProvider: httpclient.NewProvider(),
+		clock:              clk,
+		logger:             log.New("ngalert.configsync"),
+		statuses:           make(map[int64]map[string]apimodels.ExternalAlertmanagerConfigSync),
+	}
+}
+
+// Run synchronizes the configuration of all organizations periodically, if an interval is configured.
+func (s *Service) Run(ctx context.Context) error {
+	if s.cfg.ExternalAlertmanagerConfigSyncInterval <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := s.clock.Ticker(s.cfg.ExternalAlertmanagerConfigSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.SyncAll(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// SyncAll synchronizes the configuration of every organization that has a Grafana Alertmanager.
+func (s *Service) SyncAll(ctx context.Context) {
+	configs, err := s.store.GetAllLatestAlertmanagerConfiguration(ctx)
+	if err != nil {
+		s.logger.Error("Failed to get the Alertmanager configurations", "error", err)
+		return
+	}
+	for _, cfg := range configs {
+		if _, ok := s.cfg.DisabledOrgs[cfg.OrgID]; ok {
+			continue
+		}
+		if _, err := s.Sync(ctx, cfg.OrgID, false); err != nil {
+			s.logger.Error("Failed to synchronize the configuration of the external Alertmanagers", "org", cfg.OrgID, "error", err)
+		}
+	}
+}
+
+// Sync pushes the configuration of the Grafana Alertmanager of the organization to the external Alertmanagers that
+// synchronize it. The configuration of an external Alertmanager that was changed outside of Grafana is only
+// overwritten if force is true.
+func (s *Service) Sync(ctx context.Context, orgID int64, force bool) ([]apimodels.ExternalAlertmanagerConfigSync, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	dataSources, err := s.dataSources(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]apimodels.ExternalAlertmanagerConfigSync, len(dataSources))
+	s.statuses[orgID] = statuses
+	if len(dataSources) == 0 {
+		return nil, nil
+	}
+
+	desired, skipped, buildErr := s.desiredConfig(ctx, orgID)
+	for _, ds := range dataSources {
+		status := apimodels.ExternalAlertmanagerConfigSync{
+			DatasourceUID:           ds.UID,
+			DatasourceName:          ds.Name,
+			LastSync:                s.clock.Now(),
+			UnsupportedIntegrations: skipped,
+		}
+		if buildErr != nil {
+			status.State = apimodels.ConfigSyncError
+			status.Error = buildErr.Error()
+		} else {
+			s.syncDatasource(ctx, ds, desired, force, &status)
+		}
+		statuses[ds.UID] = status
+	}
+	return s.statusOf(orgID), nil
+}
+
+// Status returns the results of the last synchronization of the external Alertmanagers of the organization.
+func (s *Service) Status(orgID int64) []apimodels.ExternalAlertmanagerConfigSync {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.statusOf(orgID)
+}
+
+func (s *Service) statusOf(orgID int64) []apimodels.ExternalAlertmanagerConfigSync {
+	result := make([]apimodels.ExternalAlertmanagerConfigSync, 0, len(s.statuses[orgID]))
+	for _, status := range s.statuses[orgID] {
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DatasourceName < result[j].DatasourceName
+	})
+	return result
+}
+
+func (s *Service) syncDatasource(ctx context.Context, ds *datasources.DataSource, desired *remoteConfig, force bool, status *apimodels.ExternalAlertmanagerConfigSync) {
+	logger := s.logger.New("org", ds.OrgID, "datasource", ds.UID)
+	impl := ds.JsonData.Get("implementation").MustString("")
+	if !supportedImplementations[impl] {
+		status.State = apimodels.ConfigSyncUnsupported
+		status.Error = fmt.Sprintf("the %s Alertmanager has no configuration API", impl)
+		return
+	}
+
+	fail := func(err error) {
+		logger.Warn("Failed to synchronize the configuration of the external Alertmanager", "error", err)
+		status.State = apimodels.ConfigSyncError
+		status.Error = err.Error()
+	}
+
+	client, configURL, err := s.client(ctx, ds)
+	if err != nil {
+		fail(err)
+		return
+	}
+	remote, err := getConfig(ctx, client, configURL)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	if remote != nil {
+		recorded, managed := remote.recordedChecksum()
+		current := remote.checksum()
+		switch {
+		case managed && recorded == current && current == desired.checksum():
+			// the configuration is the one pushed by Grafana and the configuration of Grafana did not change
+			status.State = apimodels.ConfigSyncInSync
+			return
+		case !managed || recorded != current:
+			discrepancies, err := diffConfigs(desired, remote)
+			if err != nil {
+				fail(err)
+				return
+			}
+			if len(discrepancies) > 0 && !force {
+				logger.Warn("The configuration of the external Alertmanager was changed outside of Grafana", "discrepancies", len(discrepancies))
+				status.State = apimodels.ConfigSyncDrifted
+				status.Discrepancies = discrepancies
+				return
+			}
+		}
+	}
+
+	if err := postConfig(ctx, client, configURL, desired); err != nil {
+		fail(err)
+		return
+	}
+	logger.Info("Pushed the configuration to the external Alertmanager", "forced", force)
+	now := s.clock.Now()
+	status.State = apimodels.ConfigSyncInSync
+	status.LastPush = &now
+}
+
+// dataSources returns the Alertmanager datasources of the organization that handle the Grafana-managed alerts
+// and synchronize the configuration.
+func (s *Service) dataSources(ctx context.Context, orgID int64) ([]*datasources.DataSource, error) {
+	query := &datasources.GetDataSourcesByTypeQuery{
+		OrgID: orgID,
+		Type:  datasources.DS_ALERTMANAGER,
+	}
+	dataSources, err := s.datasourceService.GetDataSourcesByType(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch datasources for org: %w", err)
+	}
+	result := make([]*datasources.DataSource, 0, len(dataSources))
+	for _, ds := range dataSources {
+		if ds.JsonData == nil {
+			continue
+		}
+		if ds.JsonData.Get(apimodels.HandleGrafanaManagedAlerts).MustBool(false) &&
+			ds.JsonData.Get(apimodels.SyncGrafanaManagedConfig).MustBool(false) {
+			result = append(result, ds)
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) desiredConfig(ctx context.Context, orgID int64) (*remoteConfig, []string, error) {
+	query := &models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID}
+	if err := s.store.GetLatestAlertmanagerConfiguration(ctx, query); err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			return nil, nil, fmt.Errorf("the organization has no Alertmanager configuration")
+		}
+		return nil, nil, fmt.Errorf("failed to get the Alertmanager configuration: %w", err)
+	}
+	cfg, err := notifier.Load([]byte(query.Result.AlertmanagerConfiguration))
+	if err != nil {
+		return nil, nil, err
+	}
+	return buildRemoteConfig(ctx, cfg, s.decrypt)
+}
+
+func (s *Service) client(ctx context.Context, ds *datasources.DataSource) (*http.Client, string, error) {
+	// the URL is parsed the same way as by the datasource, so that it matches the health check
+	parsed, err := datasource.ValidateURL(datasources.DS_ALERTMANAGER, ds.URL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse alertmanager datasource url: %w", err)
+	}
+	// the transport of the datasource adds its authentication and custom headers, such as the tenant of Mimir
+	rt, err := s.datasourceService.GetHTTPTransport(ctx, ds, s.httpClientProvider)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create the HTTP transport of the datasource: %w", err)
+	}
+	return &http.Client{Transport: rt, Timeout: requestTimeout}, parsed.JoinPath(configPath).String(), nil
+}
+
+// getConfig returns the configuration of the external Alertmanager, nil if it is not configured.
+func getConfig(ctx context.Context, client *http.Client, url string) (*remoteConfig, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the configuration: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError("failed to get the configuration", resp)
+	}
+	cfg := &remoteConfig{}
+	if err := yaml.NewDecoder(resp.Body).Decode(cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode the configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+func postConfig(ctx context.Context, client *http.Client, url string, cfg *remoteConfig) error {
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode the configuration: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push the configuration: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		return responseError("failed to push the configuration", resp)
+	}
+	return nil
+}
+
+func responseError(msg string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return fmt.Errorf("%s: unexpected status %d: %s", msg, resp.StatusCode, bytes.TrimSpace(body))
+}
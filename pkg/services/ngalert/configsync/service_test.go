@@ -0,0 +1,210 @@
+package configsync
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	secretsfakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const grafanaConfig = `{
+	"template_files": {"custom.tmpl": "{{ define \"custom\" }}{{ .Status }}{{ end }}"},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "webhook",
+			"group_by": ["alertname"],
+			"routes": [{"receiver": "email", "object_matchers": [["team", "=", "a"]]}]
+		},
+		"receivers": [{
+			"name": "webhook",
+			"grafana_managed_receiver_configs": [{
+				"uid": "webhook-uid",
+				"name": "webhook",
+				"type": "webhook",
+				"settings": {"url": "http://localhost/hook", "username": "user"},
+				"secureSettings": {"password": "%s"}
+			}]
+		}, {
+			"name": "email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "email-uid",
+				"name": "email",
+				"type": "email",
+				"settings": {"addresses": "team-a@example.com"}
+			}]
+		}]
+	}
+}`
+
+type fakeStore struct {
+	config string
+}
+
+func (f *fakeStore) GetLatestAlertmanagerConfiguration(_ context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) error {
+	query.Result = &models.AlertConfiguration{OrgID: query.OrgID, AlertmanagerConfiguration: f.config}
+	return nil
+}
+
+func (f *fakeStore) GetAllLatestAlertmanagerConfiguration(context.Context) ([]*models.AlertConfiguration, error) {
+	return []*models.AlertConfiguration{{OrgID: 1, AlertmanagerConfiguration: f.config}}, nil
+}
+
+// fakeMimir is the configuration API of a Mimir Alertmanager.
+type fakeMimir struct {
+	mtx    sync.Mutex
+	config *remoteConfig
+	pushes int
+}
+
+func (f *fakeMimir) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if r.URL.Path != configPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if f.config == nil {
+			http.Error(w, "the Alertmanager is not configured", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_ = yaml.NewEncoder(w).Encode(f.config)
+	case http.MethodPost:
+		cfg := &remoteConfig{}
+		if err := yaml.NewDecoder(r.Body).Decode(cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.config = cfg
+		f.pushes++
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestSync(t *testing.T) {
+	mimir := &fakeMimir{}
+	server := httptest.NewServer(mimir)
+	t.Cleanup(server.Close)
+
+	password := base64.StdEncoding.EncodeToString([]byte("secret"))
+	store := &fakeStore{config: fmt.Sprintf(grafanaConfig, password)}
+	dataSource := func(uid, impl string, sync bool) *datasources.DataSource {
+		return &datasources.DataSource{
+			OrgID: 1,
+			UID:   uid,
+			Name:  uid,
+			Type:  datasources.DS_ALERTMANAGER,
+			URL:   server.URL,
+			JsonData: simplejson.NewFromAny(map[string]interface{}{
+				"implementation":                     impl,
+				apimodels.HandleGrafanaManagedAlerts: true,
+				apimodels.SyncGrafanaManagedConfig:   sync,
+			}),
+		}
+	}
+	dsService := &fakes.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		dataSource("mimir", "mimir", true),
+		dataSource("prometheus", "prometheus", true),
+		dataSource("not-synced", "mimir", false),
+	}}
+	s := NewService(setting.UnifiedAlertingSettings{}, store, dsService, secretsfakes.NewFakeSecretsService().GetDecryptedValue, clock.NewMock())
+
+	sync := func(t *testing.T, force bool) apimodels.ExternalAlertmanagerConfigSync {
+		t.Helper()
+		statuses, err := s.Sync(context.Background(), 1, force)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		require.Equal(t, "prometheus", statuses[1].DatasourceUID)
+		require.Equal(t, apimodels.ConfigSyncUnsupported, statuses[1].State)
+		require.Equal(t, statuses, s.Status(1))
+		return statuses[0]
+	}
+
+	t.Run("should push the configuration to an Alertmanager that is not configured", func(t *testing.T) {
+		status := sync(t, false)
+		require.Equal(t, apimodels.ConfigSyncInSync, status.State, status.Error)
+		require.NotNil(t, status.LastPush)
+		require.Equal(t, []string{"email/email"}, status.UnsupportedIntegrations)
+		require.Equal(t, 1, mimir.pushes)
+
+		require.Contains(t, mimir.config.AlertmanagerConfig, "url: http://localhost/hook")
+		require.Contains(t, mimir.config.AlertmanagerConfig, "password: secret")
+		require.Contains(t, mimir.config.AlertmanagerConfig, "- team=\"a\"")
+		require.Contains(t, mimir.config.TemplateFiles, "custom.tmpl")
+		require.NotContains(t, mimir.config.TemplateFiles, "__default__.tmpl")
+		recorded, ok := mimir.config.recordedChecksum()
+		require.True(t, ok)
+		require.Equal(t, mimir.config.checksum(), recorded)
+	})
+
+	t.Run("should not push the configuration again if nothing changed", func(t *testing.T) {
+		status := sync(t, false)
+		require.Equal(t, apimodels.ConfigSyncInSync, status.State)
+		require.Nil(t, status.LastPush)
+		require.Equal(t, 1, mimir.pushes)
+	})
+
+	t.Run("should push the changes of the configuration of Grafana", func(t *testing.T) {
+		store.config = strings.Replace(store.config, "http://localhost/hook", "http://localhost/other", 1)
+		status := sync(t, false)
+		require.Equal(t, apimodels.ConfigSyncInSync, status.State)
+		require.Equal(t, 2, mimir.pushes)
+		require.Contains(t, mimir.config.AlertmanagerConfig, "url: http://localhost/other")
+	})
+
+	t.Run("should report the changes made outside of Grafana without overwriting them", func(t *testing.T) {
+		mimir.config.AlertmanagerConfig = strings.Replace(mimir.config.AlertmanagerConfig, "http://localhost/other", "http://localhost/manual", 1)
+		mimir.config.TemplateFiles["manual.tmpl"] = "{{ define \"manual\" }}{{ end }}"
+
+		status := sync(t, false)
+		require.Equal(t, apimodels.ConfigSyncDrifted, status.State)
+		require.Equal(t, []apimodels.ConfigDiscrepancy{
+			{Path: "receivers/webhook", Kind: discrepancyChanged},
+			{Path: "template_files/manual.tmpl", Kind: discrepancyUnexpected},
+		}, status.Discrepancies)
+		require.Equal(t, 2, mimir.pushes)
+	})
+
+	t.Run("should overwrite the changes made outside of Grafana when forced", func(t *testing.T) {
+		status := sync(t, true)
+		require.Equal(t, apimodels.ConfigSyncInSync, status.State)
+		require.Empty(t, status.Discrepancies)
+		require.Equal(t, 3, mimir.pushes)
+		require.NotContains(t, mimir.config.TemplateFiles, "manual.tmpl")
+	})
+
+	t.Run("should report a configuration that was not pushed by Grafana", func(t *testing.T) {
+		mimir.config = &remoteConfig{AlertmanagerConfig: "route:\n  receiver: default\nreceivers:\n  - name: default\n"}
+		status := sync(t, false)
+		require.Equal(t, apimodels.ConfigSyncDrifted, status.State)
+		require.Contains(t, status.Discrepancies, apimodels.ConfigDiscrepancy{Path: "receivers/default", Kind: discrepancyUnexpected})
+		require.Contains(t, status.Discrepancies, apimodels.ConfigDiscrepancy{Path: "route", Kind: discrepancyChanged})
+		require.Equal(t, 3, mimir.pushes)
+	})
+
+	t.Run("should report the errors of the Alertmanager", func(t *testing.T) {
+		dsService.DataSources[0].URL = server.URL + "/unknown"
+		t.Cleanup(func() { dsService.DataSources[0].URL = server.URL })
+		status := sync(t, true)
+		require.Equal(t, apimodels.ConfigSyncError, status.State)
+		require.Contains(t, status.Error, "unexpected status 404")
+	})
+}
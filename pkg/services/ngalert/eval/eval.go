@@ -81,7 +81,35 @@ func (r *conditionEvaluator) Evaluate(ctx context.Context, now time.Time) (Resul
 		return nil, err
 	}
 	execResults := queryDataResponseToExecutionResults(r.condition, response)
-	return evaluateExecutionResult(execResults, now), nil
+	results := evaluateExecutionResult(execResults, now)
+	datapoints := countDatapoints(r.condition, response)
+	for i := range results {
+		results[i].Datapoints = datapoints
+	}
+	return results, nil
+}
+
+// countDatapoints returns the number of values returned by the data source queries of the condition,
+// the values of the time fields and of the expressions are not counted.
+func countDatapoints(c models.Condition, resp *backend.QueryDataResponse) int64 {
+	if resp == nil {
+		return 0
+	}
+	var count int64
+	for _, q := range c.Data {
+		if expr.IsDataSource(q.DatasourceUID) {
+			continue
+		}
+		for _, frame := range resp.Responses[q.RefID].Frames {
+			for _, field := range frame.Fields {
+				if field.Type().Time() {
+					continue
+				}
+				count += int64(field.Len())
+			}
+		}
+	}
+	return count
 }
 
 type evaluatorImpl struct {
@@ -171,6 +199,8 @@ type Result struct {
 
 	EvaluatedAt        time.Time
 	EvaluationDuration time.Duration
+	// Datapoints is the number of values returned by the data source queries of the evaluation.
+	Datapoints int64
 	// EvaluationString is a string representation of evaluation data such
 	// as EvalMatches (from "classic condition"), and in the future from operations
 	// like SSE "math".
@@ -352,6 +352,36 @@ func TestEvaluateExecutionResultsNoData(t *testing.T) {
 	})
 }
 
+func TestCountDatapoints(t *testing.T) {
+	condition := models.Condition{
+		Condition: "B",
+		Data: []models.AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus"},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID},
+		},
+	}
+	resp := &backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"A": {Frames: data.Frames{
+				data.NewFrame("",
+					data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0), time.Unix(3, 0)}),
+					data.NewField("value", data.Labels{"job": "a"}, []float64{1, 2, 3}),
+				),
+				data.NewFrame("",
+					data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+					data.NewField("value", data.Labels{"job": "b"}, []float64{1}),
+				),
+			}},
+			"B": {Frames: data.Frames{
+				data.NewFrame("", data.NewField("", nil, []*float64{util.Pointer(1.0)})),
+			}},
+		},
+	}
+
+	require.Equal(t, int64(4), countDatapoints(condition, resp))
+	require.Equal(t, int64(0), countDatapoints(condition, nil))
+}
+
 func TestValidate(t *testing.T) {
 	type services struct {
 		cache        *fakes.FakeCacheService
@@ -18,6 +18,11 @@ type MultiOrgAlertmanager struct {
 	ActiveConfigurations     prometheus.Gauge
 	DiscoveredConfigurations prometheus.Gauge
 
+	NotificationDeliveries       *prometheus.CounterVec
+	NotificationDeliveryRetries  *prometheus.CounterVec
+	NotificationDeliveryDuration *prometheus.HistogramVec
+	NotificationLogWritesFailed  prometheus.Counter
+
 	aggregatedMetrics *AlertmanagerAggregatedMetrics
 }
 
@@ -38,6 +43,31 @@ func NewMultiOrgAlertmanagerMetrics(r prometheus.Registerer) *MultiOrgAlertmanag
 			Name:      "active_configurations",
 			Help:      "The number of active Alertmanager configurations.",
 		}),
+		NotificationDeliveries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "notification_deliveries_total",
+			Help:      "The total number of attempts to deliver a notification, by integration type and status.",
+		}, []string{"org", "integration", "status"}),
+		NotificationDeliveryRetries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "notification_delivery_retries_total",
+			Help:      "The total number of attempts to deliver a notification that retried a failed attempt, by integration type.",
+		}, []string{"org", "integration"}),
+		NotificationDeliveryDuration: promauto.With(r).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "notification_delivery_duration_seconds",
+			Help:      "The latency of the attempts to deliver a notification, by integration type.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"integration"}),
+		NotificationLogWritesFailed: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "notification_log_writes_failed_total",
+			Help:      "The total number of delivery attempts that failed to be written to the notification log.",
+		}),
 		aggregatedMetrics: NewAlertmanagerAggregatedMetrics(registries),
 	}
 
@@ -104,6 +104,9 @@ const (
 
 	// StateReasonAnnotation is the name of the annotation that explains the difference between evaluation state and alert state (i.e. changing state when NoData or Error).
 	StateReasonAnnotation = GrafanaReservedLabelPrefix + "state_reason"
+
+	// FlappingAnnotation is the name of the annotation that is set to "true" when the alert instance is flapping.
+	FlappingAnnotation = GrafanaReservedLabelPrefix + "flapping"
 )
 
 const (
@@ -0,0 +1,305 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// CompositeDatasourceUID is the data source of the query of a composite rule. A composite rule evaluates a boolean
+// expression over the firing state of other rules of the organization, e.g. "A && B" fires only while the rules A
+// and B both fire, and fires for the duration of the rule once the expression held for that long.
+const CompositeDatasourceUID = "__composite__"
+
+var ErrCompositeRuleCycle = fmt.Errorf("%w: composite rules depend on each other", ErrAlertRuleFailedValidation)
+
+// CompositeModel is the model of the query of a composite rule.
+type CompositeModel struct {
+	// Expression combines the identifiers of the rules with &&, || and ! (or and, or and not) and parentheses.
+	Expression string `json:"expression"`
+	// Rules maps the identifiers of the expression to the UIDs of the rules.
+	Rules map[string]string `json:"rules"`
+}
+
+// CompositeCondition is the parsed condition of a composite rule.
+type CompositeCondition struct {
+	CompositeModel
+	expr compositeNode
+}
+
+// IsComposite returns true if the rule is a composite rule.
+func (alertRule *AlertRule) IsComposite() bool {
+	return len(alertRule.Data) == 1 && alertRule.Data[0].DatasourceUID == CompositeDatasourceUID
+}
+
+// GetCompositeCondition returns the condition of a composite rule.
+func (alertRule *AlertRule) GetCompositeCondition() (*CompositeCondition, error) {
+	return ParseCompositeCondition(Condition{Condition: alertRule.Condition, Data: alertRule.Data})
+}
+
+// IsCompositeCondition returns true if the condition is the condition of a composite rule.
+func IsCompositeCondition(condition Condition) bool {
+	for _, q := range condition.Data {
+		if q.DatasourceUID == CompositeDatasourceUID {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCompositeCondition parses and validates the condition of a composite rule: a single query of the composite
+// data source, with an expression referencing only the identifiers of its rules.
+func ParseCompositeCondition(condition Condition) (*CompositeCondition, error) {
+	if len(condition.Data) != 1 || condition.Data[0].DatasourceUID != CompositeDatasourceUID {
+		return nil, fmt.Errorf("%w: a composite rule must have a single query", ErrAlertRuleFailedValidation)
+	}
+	query := condition.Data[0]
+	if condition.Condition != query.RefID {
+		return nil, fmt.Errorf("%w: the condition of a composite rule must be its query %s", ErrAlertRuleFailedValidation, query.RefID)
+	}
+
+	c := &CompositeCondition{}
+	if err := json.Unmarshal(query.Model, &c.CompositeModel); err != nil {
+		return nil, fmt.Errorf("%w: invalid composite query: %s", ErrAlertRuleFailedValidation, err)
+	}
+	if len(c.Rules) == 0 {
+		return nil, fmt.Errorf("%w: a composite rule must reference rules", ErrAlertRuleFailedValidation)
+	}
+
+	expr, err := parseCompositeExpression(c.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid composite expression %q: %s", ErrAlertRuleFailedValidation, c.Expression, err)
+	}
+	for _, ref := range expr.refs(nil) {
+		if c.Rules[ref] == "" {
+			return nil, fmt.Errorf("%w: the composite expression references %s that is not a rule", ErrAlertRuleFailedValidation, ref)
+		}
+	}
+	c.expr = expr
+	return c, nil
+}
+
+// Evaluate returns the value of the expression given the firing state of the rules.
+func (c *CompositeCondition) Evaluate(firing func(ruleUID string) bool) bool {
+	return c.expr.eval(func(ref string) bool {
+		return firing(c.Rules[ref])
+	})
+}
+
+// Dependencies returns the sorted UIDs of the rules the composite rule depends on.
+func (c *CompositeCondition) Dependencies() []string {
+	seen := make(map[string]struct{}, len(c.Rules))
+	result := make([]string, 0, len(c.Rules))
+	for _, uid := range c.Rules {
+		if _, ok := seen[uid]; !ok {
+			seen[uid] = struct{}{}
+			result = append(result, uid)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// FindCompositeCycle returns the UIDs of the rules of a dependency cycle between the composite rules, or nil.
+// dependencies returns the UIDs of the rules a rule depends on.
+func FindCompositeCycle(uids []string, dependencies func(uid string) []string) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	marks := make(map[string]int, len(uids))
+	var path []string
+
+	var visit func(uid string) []string
+	visit = func(uid string) []string {
+		switch marks[uid] {
+		case visiting:
+			for i, p := range path {
+				if p == uid {
+					return append(append([]string{}, path[i:]...), uid)
+				}
+			}
+		case visited:
+			return nil
+		}
+		marks[uid] = visiting
+		path = append(path, uid)
+		for _, dep := range dependencies(uid) {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		marks[uid] = visited
+		return nil
+	}
+
+	sorted := append([]string{}, uids...)
+	sort.Strings(sorted)
+	for _, uid := range sorted {
+		if cycle := visit(uid); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+type compositeNode interface {
+	eval(firing func(ref string) bool) bool
+	refs(acc []string) []string
+}
+
+type compositeRef string
+
+func (n compositeRef) eval(firing func(string) bool) bool { return firing(string(n)) }
+func (n compositeRef) refs(acc []string) []string         { return append(acc, string(n)) }
+
+type compositeNot struct{ operand compositeNode }
+
+func (n compositeNot) eval(firing func(string) bool) bool { return !n.operand.eval(firing) }
+func (n compositeNot) refs(acc []string) []string         { return n.operand.refs(acc) }
+
+type compositeBinary struct {
+	and         bool
+	left, right compositeNode
+}
+
+func (n compositeBinary) eval(firing func(string) bool) bool {
+	if n.and {
+		return n.left.eval(firing) && n.right.eval(firing)
+	}
+	return n.left.eval(firing) || n.right.eval(firing)
+}
+
+func (n compositeBinary) refs(acc []string) []string {
+	return n.right.refs(n.left.refs(acc))
+}
+
+// compositeParser parses the expressions of the grammar:
+//
+//	or    = and { ("||" | "or") and }
+//	and   = unary { ("&&" | "and") unary }
+//	unary = ("!" | "not") unary | "(" or ")" | identifier
+type compositeParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseCompositeExpression(s string) (compositeNode, error) {
+	tokens, err := tokenizeCompositeExpression(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+	p := &compositeParser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return n, nil
+}
+
+func tokenizeCompositeExpression(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')' || c == '!':
+			tokens = append(tokens, string(c))
+			i++
+		case strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, s[i:i+2])
+			i += 2
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			j := i
+			for j < len(s) && (s[j] == '_' || unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *compositeParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *compositeParser) parseOr() (compositeNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "||" || strings.EqualFold(t, "or"); t = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = compositeBinary{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *compositeParser) parseAnd() (compositeNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t == "&&" || strings.EqualFold(t, "and"); t = p.peek() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = compositeBinary{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *compositeParser) parseUnary() (compositeNode, error) {
+	t := p.peek()
+	switch {
+	case t == "":
+		return nil, errors.New("unexpected end of expression")
+	case t == "!" || strings.EqualFold(t, "not"):
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return compositeNot{operand: operand}, nil
+	case t == "(":
+		p.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return n, nil
+	case t == ")" || t == "&&" || t == "||" || strings.EqualFold(t, "and") || strings.EqualFold(t, "or"):
+		return nil, fmt.Errorf("unexpected %q", t)
+	default:
+		p.pos++
+		return compositeRef(t), nil
+	}
+}
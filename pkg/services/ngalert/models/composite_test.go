@@ -0,0 +1,120 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func compositeCondition(t *testing.T, expression string, rules map[string]string) Condition {
+	t.Helper()
+	model, err := json.Marshal(CompositeModel{Expression: expression, Rules: rules})
+	require.NoError(t, err)
+	return Condition{
+		Condition: "A",
+		Data: []AlertQuery{{
+			RefID:         "A",
+			DatasourceUID: CompositeDatasourceUID,
+			Model:         model,
+		}},
+	}
+}
+
+func TestParseCompositeCondition(t *testing.T) {
+	rules := map[string]string{"A": "uid-a", "B": "uid-b", "C": "uid-c"}
+
+	testCases := []struct {
+		expression string
+		firing     []string
+		expected   bool
+	}{
+		{expression: "A && B", firing: []string{"uid-a", "uid-b"}, expected: true},
+		{expression: "A && B", firing: []string{"uid-a"}, expected: false},
+		{expression: "A and B", firing: []string{"uid-a", "uid-b"}, expected: true},
+		{expression: "A || B", firing: []string{"uid-b"}, expected: true},
+		{expression: "A or B", firing: nil, expected: false},
+		{expression: "!A", firing: nil, expected: true},
+		{expression: "not A", firing: []string{"uid-a"}, expected: false},
+		{expression: "A || B && C", firing: []string{"uid-a"}, expected: true},
+		{expression: "(A || B) && C", firing: []string{"uid-a"}, expected: false},
+		{expression: "(A || B) && !C", firing: []string{"uid-b"}, expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			c, err := ParseCompositeCondition(compositeCondition(t, tc.expression, rules))
+			require.NoError(t, err)
+			firing := make(map[string]bool)
+			for _, uid := range tc.firing {
+				firing[uid] = true
+			}
+			require.Equal(t, tc.expected, c.Evaluate(func(uid string) bool { return firing[uid] }))
+		})
+	}
+
+	t.Run("should return the dependencies", func(t *testing.T) {
+		c, err := ParseCompositeCondition(compositeCondition(t, "A && B", map[string]string{"A": "uid-b", "B": "uid-a", "C": "uid-a"}))
+		require.NoError(t, err)
+		require.Equal(t, []string{"uid-a", "uid-b"}, c.Dependencies())
+	})
+
+	for _, expression := range []string{"", "A &&", "(A || B", "A B", "A & B", "&& A", "A || ()", "D"} {
+		t.Run("should fail for "+expression, func(t *testing.T) {
+			_, err := ParseCompositeCondition(compositeCondition(t, expression, rules))
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+		})
+	}
+
+	t.Run("should fail without rules", func(t *testing.T) {
+		_, err := ParseCompositeCondition(compositeCondition(t, "A", nil))
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should fail if the condition is not the query", func(t *testing.T) {
+		condition := compositeCondition(t, "A", rules)
+		condition.Condition = "B"
+		_, err := ParseCompositeCondition(condition)
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should fail with other queries", func(t *testing.T) {
+		condition := compositeCondition(t, "A", rules)
+		condition.Data = append(condition.Data, AlertQuery{RefID: "B", DatasourceUID: "prometheus"})
+		require.True(t, IsCompositeCondition(condition))
+		_, err := ParseCompositeCondition(condition)
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+	})
+}
+
+func TestFindCompositeCycle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		graph    map[string][]string
+		expected []string
+	}{
+		{
+			name:  "no cycle",
+			graph: map[string][]string{"a": {"b", "c"}, "b": {"c"}},
+		},
+		{
+			name:     "self reference",
+			graph:    map[string][]string{"a": {"a"}},
+			expected: []string{"a", "a"},
+		},
+		{
+			name:     "cycle",
+			graph:    map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"b"}},
+			expected: []string{"b", "c", "b"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uids := make([]string, 0, len(tc.graph))
+			for uid := range tc.graph {
+				uids = append(uids, uid)
+			}
+			cycle := FindCompositeCycle(uids, func(uid string) []string { return tc.graph[uid] })
+			require.Equal(t, tc.expected, cycle)
+		})
+	}
+}
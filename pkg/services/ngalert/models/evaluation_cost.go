@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// EvaluationCost is the cost of evaluations of a rule: the time spent evaluating and the number of values
+// returned by the data sources.
+type EvaluationCost struct {
+	Duration   time.Duration
+	Datapoints int64
+}
+
+// Add returns the sum of the costs.
+func (c EvaluationCost) Add(other EvaluationCost) EvaluationCost {
+	return EvaluationCost{
+		Duration:   c.Duration + other.Duration,
+		Datapoints: c.Datapoints + other.Datapoints,
+	}
+}
+
+// RuleEvaluationCost is the cost of the evaluations of a rule tracked by the scheduler.
+type RuleEvaluationCost struct {
+	// Last is the cost of the last evaluation.
+	Last EvaluationCost
+	// Window is the cost of the evaluations in the rolling window of the evaluation budget.
+	Window EvaluationCost
+	// PausedUntil is set while the evaluations of the rule are paused because the rule exceeded its quota
+	// or was the most expensive rule of a folder that exceeded its budget.
+	PausedUntil time.Time
+	// PauseReason explains why the evaluations are paused.
+	PauseReason string
+}
+
+// IsPaused returns true if the evaluations of the rule are paused at the given time.
+func (c RuleEvaluationCost) IsPaused(now time.Time) bool {
+	return now.Before(c.PausedUntil)
+}
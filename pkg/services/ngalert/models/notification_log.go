@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailure NotificationDeliveryStatus = "failure"
+)
+
+// NotificationDelivery is an attempt to deliver a notification through one integration of a contact point.
+type NotificationDelivery struct {
+	ID    int64 `xorm:"pk autoincr 'id'"`
+	OrgID int64 `xorm:"org_id"`
+	// Receiver is the name of the contact point.
+	Receiver         string                     `xorm:"receiver"`
+	IntegrationUID   string                     `xorm:"integration_uid"`
+	IntegrationType  string                     `xorm:"integration_type"`
+	IntegrationIndex int                        `xorm:"integration_index"`
+	GroupKey         string                     `xorm:"group_key"`
+	Status           NotificationDeliveryStatus `xorm:"status"`
+	Error            string                     `xorm:"error"`
+	// Retry is the number of attempts that preceded this one for the same notification, 0 for the first attempt.
+	Retry int `xorm:"retry"`
+	// WillRetry is true when the attempt failed and the notification is going to be retried.
+	WillRetry bool      `xorm:"will_retry"`
+	Alerts    int       `xorm:"alerts"`
+	LatencyMs int64     `xorm:"latency_ms"`
+	SentAt    time.Time `xorm:"sent_at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (d *NotificationDelivery) TableName() string {
+	return "alert_notification_log"
+}
+
+// NotificationDeliveryQuery filters the notification deliveries of an organization.
+// Empty fields are ignored, the most recent deliveries are returned first.
+type NotificationDeliveryQuery struct {
+	OrgID           int64
+	Receiver        string
+	IntegrationUID  string
+	IntegrationType string
+	Status          NotificationDeliveryStatus
+	From            time.Time
+	To              time.Time
+	Limit           int
+}
@@ -25,7 +25,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/idempotency"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/configsync"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
@@ -40,6 +42,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
+	"github.com/grafana/grafana/pkg/services/resourcewatch"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -67,6 +70,8 @@ func ProvideService(
 	annotationsRepo annotations.Repository,
 	pluginsStore plugins.Store,
 	tracer tracing.Tracer,
+	resourceWatch *resourcewatch.Service,
+	idempotency *idempotency.Service,
 ) (*AlertNG, error) {
 	ng := &AlertNG{
 		Cfg:                  cfg,
@@ -92,6 +97,8 @@ func ProvideService(
 		annotationsRepo:      annotationsRepo,
 		pluginsStore:         pluginsStore,
 		tracer:               tracer,
+		resourceWatch:        resourceWatch,
+		idempotency:          idempotency,
 	}
 
 	if ng.IsDisabled() {
@@ -131,14 +138,17 @@ type AlertNG struct {
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	AlertsRouter         *sender.AlertsRouter
+	ConfigSync           *configsync.Service
 	accesscontrol        accesscontrol.AccessControl
 	accesscontrolService accesscontrol.Service
 	annotationsRepo      annotations.Repository
 	store                *store.DBstore
 
-	bus          bus.Bus
-	pluginsStore plugins.Store
-	tracer       tracing.Tracer
+	bus           bus.Bus
+	pluginsStore  plugins.Store
+	tracer        tracing.Tracer
+	resourceWatch *resourcewatch.Service
+	idempotency   *idempotency.Service
 }
 
 func (ng *AlertNG) init() error {
@@ -161,7 +171,7 @@ func (ng *AlertNG) init() error {
 
 	decryptFn := ng.SecretsService.GetDecryptedValue
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
-	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, store, store, ng.KVStore, store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService)
+	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, store, store, ng.KVStore, store, store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService)
 	if err != nil {
 		return err
 	}
@@ -194,8 +204,10 @@ func (ng *AlertNG) init() error {
 	}
 
 	ng.AlertsRouter = alertsRouter
+	ng.ConfigSync = configsync.NewService(ng.Cfg.UnifiedAlerting, store, ng.DataSourceService, decryptFn, clk)
 
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
+	evaluationBudget := schedule.NewEvaluationBudget(ng.Cfg.UnifiedAlerting.EvaluationBudget, clk)
 	schedCfg := schedule.SchedulerCfg{
 		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
 		C:                    clk,
@@ -208,6 +220,9 @@ func (ng *AlertNG) init() error {
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
 		AlertSender:          alertsRouter,
 		Tracer:               ng.tracer,
+		DrainTimeout:         ng.Cfg.ShutdownTimeout,
+		EvaluationBudget:     evaluationBudget,
+		Bus:                  ng.bus,
 	}
 
 	history, err := configureHistorianBackend(initCtx, ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, ng.store, ng.Metrics.GetHistorianMetrics(), ng.Log)
@@ -222,6 +237,10 @@ func (ng *AlertNG) init() error {
 		Clock:                clk,
 		Historian:            history,
 		DoNotSaveNormalState: ng.FeatureToggles.IsEnabled(featuremgmt.FlagAlertingNoNormalState),
+		FlapDetection: state.FlapDetection{
+			Window:    ng.Cfg.UnifiedAlerting.FlapDetectionWindow,
+			Threshold: ng.Cfg.UnifiedAlerting.FlapDetectionThreshold,
+		},
 	}
 	stateManager := state.NewManager(cfg)
 	scheduler := schedule.NewScheduler(schedCfg, stateManager)
@@ -264,10 +283,15 @@ func (ng *AlertNG) init() error {
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
+		ConfigSync:           ng.ConfigSync,
 		EvaluatorFactory:     evalFactory,
 		FeatureManager:       ng.FeatureToggles,
 		AppUrl:               appUrl,
 		Historian:            history,
+		NotificationLogStore: store,
+		EvaluationCosts:      evaluationBudget,
+		ResourceWatch:        ng.resourceWatch,
+		Idempotency:          ng.idempotency,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -329,6 +353,9 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.ConfigSync.Run(subCtx)
+	})
 
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts {
 		children.Go(func() error {
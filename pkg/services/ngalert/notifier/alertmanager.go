@@ -9,8 +9,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/grafana/alerting/images"
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/alerting/receivers"
 	alertingTemplates "github.com/grafana/alerting/templates"
@@ -54,8 +56,13 @@ type Alertmanager struct {
 	fileStore           *FileStore
 	NotificationService notifications.Service
 
-	decryptFn receivers.GetDecryptedValueFn
-	orgID     int64
+	decryptFn   receivers.GetDecryptedValueFn
+	orgID       int64
+	deliveryLog *DeliveryLog
+
+	// flappingMtx protects the flapping policy, which is replaced when a configuration is applied while alerts are put.
+	flappingMtx sync.RWMutex
+	flapping    *flappingPolicy
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -85,7 +92,7 @@ func (m maintenanceOptions) MaintenanceFunc(state alertingNotify.State) (int64,
 
 func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store AlertingStore, kvStore kvstore.KVStore,
 	peer alertingNotify.ClusterPeer, decryptFn receivers.GetDecryptedValueFn, ns notifications.Service,
-	m *metrics.Alertmanager) (*Alertmanager, error) {
+	m *metrics.Alertmanager, deliveryLog *DeliveryLog) (*Alertmanager, error) {
 	workingPath := filepath.Join(cfg.DataPath, workingDir, strconv.Itoa(int(orgID)))
 	fileStore := NewFileStore(orgID, kvStore, workingPath)
 
@@ -140,6 +147,7 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		orgID:               orgID,
 		decryptFn:           decryptFn,
 		fileStore:           fileStore,
+		deliveryLog:         deliveryLog,
 		logger:              l,
 	}
 
@@ -291,6 +299,10 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 		return false, err
 	}
 
+	am.flappingMtx.Lock()
+	am.flapping = newFlappingPolicy(cfg.AlertmanagerConfig.Route)
+	am.flappingMtx.Unlock()
+
 	return true, nil
 }
 
@@ -338,6 +350,9 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
+		if am.deliveryLog != nil {
+			n = am.deliveryLog.wrap(am.orgID, receiver.Name, i, r.UID, r.Type, n)
+		}
 		integrations = append(integrations, alertingNotify.NewIntegration(n, n, r.Type, i))
 	}
 	return integrations, nil
@@ -369,7 +384,11 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 			SecureSettings:        secureSettings,
 		}
 	)
-	factoryConfig, err := receivers.NewFactoryConfig(cfg, NewNotificationSender(am.NotificationService), am.decryptFn, tmpl, newImageStore(am.Store), LoggerFactory, setting.BuildVersion)
+	var imgStore images.ImageStore = &images.UnavailableImageStore{}
+	if am.includeImages(r) {
+		imgStore = newImageStore(am.Store)
+	}
+	factoryConfig, err := receivers.NewFactoryConfig(cfg, NewNotificationSender(am.NotificationService), am.decryptFn, tmpl, imgStore, LoggerFactory, setting.BuildVersion)
 	if err != nil {
 		return nil, InvalidReceiverError{
 			Receiver: r,
@@ -395,6 +414,10 @@ func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaRec
 
 // PutAlerts receives the alerts and then sends them through the corresponding route based on whenever the alert has a receiver embedded or not
 func (am *Alertmanager) PutAlerts(postableAlerts apimodels.PostableAlerts) error {
+	am.flappingMtx.RLock()
+	postableAlerts = am.flapping.filter(postableAlerts, time.Now())
+	am.flappingMtx.RUnlock()
+
 	alerts := make(alertingNotify.PostableAlerts, 0, len(postableAlerts.PostableAlerts))
 	for _, pa := range postableAlerts.PostableAlerts {
 		alerts = append(alerts, &alertingNotify.PostableAlert{
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets/database"
@@ -39,7 +40,7 @@ func setupAMTest(t *testing.T) *Alertmanager {
 	kvStore := NewFakeKVStore(t)
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
 	decryptFn := secretsService.GetDecryptedValue
-	am, err := newAlertmanager(context.Background(), 1, cfg, s, kvStore, &NilPeer{}, decryptFn, nil, m)
+	am, err := newAlertmanager(context.Background(), 1, cfg, s, kvStore, &NilPeer{}, decryptFn, nil, m, nil)
 	require.NoError(t, err)
 	return am
 }
@@ -48,3 +49,19 @@ func TestAlertmanager_newAlertmanager(t *testing.T) {
 	am := setupAMTest(t)
 	require.False(t, am.Ready())
 }
+
+func TestAlertmanager_includeImages(t *testing.T) {
+	optIn := &apimodels.PostableGrafanaReceiver{Type: "slack", Settings: apimodels.RawMessage(`{"recipient":"#alerts","includeImages":true}`)}
+	noOptIn := &apimodels.PostableGrafanaReceiver{Type: "slack", Settings: apimodels.RawMessage(`{"recipient":"#alerts"}`)}
+	noSettings := &apimodels.PostableGrafanaReceiver{Type: "webhook"}
+
+	am := &Alertmanager{Settings: &setting.Cfg{}}
+	require.True(t, am.includeImages(optIn))
+	require.True(t, am.includeImages(noOptIn))
+	require.True(t, am.includeImages(noSettings))
+
+	am.Settings.UnifiedAlerting.Screenshots.ContactPointOptIn = true
+	require.True(t, am.includeImages(optIn))
+	require.False(t, am.includeImages(noOptIn))
+	require.False(t, am.includeImages(noSettings))
+}
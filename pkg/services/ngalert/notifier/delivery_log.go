@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// deliveryLogMaintenanceInterval is how often the expired deliveries are deleted.
+	deliveryLogMaintenanceInterval = time.Hour
+	// deliveryLogWriteTimeout bounds the time spent writing a delivery, so that a slow database does not delay the retries.
+	deliveryLogWriteTimeout = 5 * time.Second
+	// deliveryAttemptsTTL is how long the attempts of a notification are counted. The retries of a notification
+	// stop with the flush of its group, so counting them longer than any reasonable group interval is enough.
+	deliveryAttemptsTTL = time.Hour
+)
+
+// DeliveryLog records the attempts of the Alertmanagers to deliver notifications through the integrations of
+// the contact points, in the notification log and in metrics. Failed attempts that are retried by the Alertmanager
+// are recorded with the number of attempts that preceded them.
+type DeliveryLog struct {
+	store     store.NotificationLogStore
+	retention time.Duration
+	metrics   *metrics.MultiOrgAlertmanager
+	logger    log.Logger
+
+	attemptsMtx sync.Mutex
+	attempts    map[deliveryKey]deliveryAttempts
+}
+
+// deliveryKey identifies a notification of an integration. The Alertmanager sets the dispatch time of a group
+// when it is flushed, so it is the same for all the retries of the notification and differs from a flush to the next.
+type deliveryKey struct {
+	orgID      int64
+	receiver   string
+	index      int
+	groupKey   string
+	dispatchAt int64
+}
+
+type deliveryAttempts struct {
+	count   int
+	started time.Time
+}
+
+// NewDeliveryLog creates a DeliveryLog. The deliveries are not persisted if the store is nil or the retention is zero.
+func NewDeliveryLog(s store.NotificationLogStore, retention time.Duration, m *metrics.MultiOrgAlertmanager, l log.Logger) *DeliveryLog {
+	return &DeliveryLog{
+		store:     s,
+		retention: retention,
+		metrics:   m,
+		logger:    l,
+		attempts:  map[deliveryKey]deliveryAttempts{},
+	}
+}
+
+func (d *DeliveryLog) persisted() bool {
+	return d.store != nil && d.retention > 0
+}
+
+// wrap returns a notifier that records the delivery attempts of the integration.
+func (d *DeliveryLog) wrap(orgID int64, receiver string, index int, uid, integrationType string, n alertingNotify.NotificationChannel) alertingNotify.NotificationChannel {
+	return &recordingNotifier{
+		NotificationChannel: n,
+		log:                 d,
+		orgID:               orgID,
+		receiver:            receiver,
+		index:               index,
+		uid:                 uid,
+		integrationType:     integrationType,
+	}
+}
+
+// maintenance deletes the deliveries that are older than the retention and forgets the attempts of old notifications.
+func (d *DeliveryLog) maintenance(ctx context.Context) {
+	d.attemptsMtx.Lock()
+	for key, a := range d.attempts {
+		if time.Since(a.started) > deliveryAttemptsTTL {
+			delete(d.attempts, key)
+		}
+	}
+	d.attemptsMtx.Unlock()
+
+	if !d.persisted() {
+		return
+	}
+	n, err := d.store.DeleteNotificationDeliveriesBefore(ctx, time.Now().Add(-d.retention))
+	if err != nil {
+		d.logger.Error("failed to delete expired notification deliveries", "error", err)
+		return
+	}
+	d.logger.Debug("deleted expired notification deliveries", "count", n)
+}
+
+func (d *DeliveryLog) record(ctx context.Context, n *recordingNotifier, alerts int, latency time.Duration, retry bool, err error) {
+	key := deliveryKey{orgID: n.orgID, receiver: n.receiver, index: n.index}
+	key.groupKey, _ = notify.GroupKey(ctx)
+	if dispatchAt, ok := notify.Now(ctx); ok {
+		key.dispatchAt = dispatchAt.UnixNano()
+	}
+	willRetry := err != nil && retry && ctx.Err() == nil
+
+	d.attemptsMtx.Lock()
+	a, ok := d.attempts[key]
+	if !ok {
+		a.started = time.Now()
+	}
+	previous := a.count
+	if willRetry {
+		a.count++
+		d.attempts[key] = a
+	} else {
+		delete(d.attempts, key)
+	}
+	d.attemptsMtx.Unlock()
+
+	delivery := &models.NotificationDelivery{
+		OrgID:            n.orgID,
+		Receiver:         n.receiver,
+		IntegrationUID:   n.uid,
+		IntegrationType:  n.integrationType,
+		IntegrationIndex: n.index,
+		GroupKey:         key.groupKey,
+		Status:           models.NotificationDeliverySuccess,
+		Retry:            previous,
+		WillRetry:        willRetry,
+		Alerts:           alerts,
+		LatencyMs:        latency.Milliseconds(),
+		SentAt:           time.Now().UTC(),
+	}
+	if err != nil {
+		delivery.Status = models.NotificationDeliveryFailure
+		delivery.Error = err.Error()
+	}
+
+	if d.metrics != nil {
+		org := strconv.FormatInt(n.orgID, 10)
+		d.metrics.NotificationDeliveries.WithLabelValues(org, n.integrationType, string(delivery.Status)).Inc()
+		if previous > 0 {
+			d.metrics.NotificationDeliveryRetries.WithLabelValues(org, n.integrationType).Inc()
+		}
+		d.metrics.NotificationDeliveryDuration.WithLabelValues(n.integrationType).Observe(latency.Seconds())
+	}
+
+	if !d.persisted() {
+		return
+	}
+	// The context of the notification can be canceled already, e.g. when the attempt timed out,
+	// but the attempt must be recorded anyway.
+	writeCtx, cancel := context.WithTimeout(context.Background(), deliveryLogWriteTimeout)
+	defer cancel()
+	if err := d.store.SaveNotificationDelivery(writeCtx, delivery); err != nil {
+		d.logger.Error("failed to record notification delivery", "receiver", n.receiver, "integration", n.integrationType, "error", err)
+		if d.metrics != nil {
+			d.metrics.NotificationLogWritesFailed.Inc()
+		}
+	}
+}
+
+// recordingNotifier records the delivery attempts of the notifier it wraps.
+type recordingNotifier struct {
+	alertingNotify.NotificationChannel
+	log *DeliveryLog
+
+	orgID           int64
+	receiver        string
+	index           int
+	uid             string
+	integrationType string
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := time.Now()
+	retry, err := n.NotificationChannel.Notify(ctx, alerts...)
+	n.log.record(ctx, n, len(alerts), time.Since(start), retry, err)
+	return retry, err
+}
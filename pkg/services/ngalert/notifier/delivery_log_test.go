@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeNotificationLogStore struct {
+	mtx        sync.Mutex
+	deliveries []*models.NotificationDelivery
+	before     time.Time
+}
+
+func (f *fakeNotificationLogStore) SaveNotificationDelivery(_ context.Context, delivery *models.NotificationDelivery) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.deliveries = append(f.deliveries, delivery)
+	return nil
+}
+
+func (f *fakeNotificationLogStore) GetNotificationDeliveries(_ context.Context, _ *models.NotificationDeliveryQuery) ([]*models.NotificationDelivery, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.deliveries, nil
+}
+
+func (f *fakeNotificationLogStore) DeleteNotificationDeliveriesBefore(_ context.Context, before time.Time) (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.before = before
+	return 0, nil
+}
+
+// failingNotifier fails the given number of times before succeeding.
+type failingNotifier struct {
+	failures int
+}
+
+func (n *failingNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	if n.failures > 0 {
+		n.failures--
+		return true, errors.New("503 Service Unavailable")
+	}
+	return false, nil
+}
+
+func (n *failingNotifier) SendResolved() bool {
+	return true
+}
+
+func TestDeliveryLog(t *testing.T) {
+	notificationCtx := func(groupKey string, dispatchAt time.Time) context.Context {
+		return notify.WithNow(notify.WithGroupKey(context.Background(), groupKey), dispatchAt)
+	}
+
+	t.Run("records the attempts and the retries of a notification", func(t *testing.T) {
+		store := &fakeNotificationLogStore{}
+		m := metrics.NewNGAlert(prometheus.NewRegistry()).GetMultiOrgAlertmanagerMetrics()
+		deliveryLog := NewDeliveryLog(store, time.Hour, m, log.NewNopLogger())
+		n := deliveryLog.wrap(1, "ops", 0, "uid", "slack", &failingNotifier{failures: 2})
+
+		ctx := notificationCtx("{}:{alertname=\"test\"}", time.Now())
+		alerts := []*types.Alert{{}, {}}
+		for i := 0; i < 3; i++ {
+			_, _ = n.Notify(ctx, alerts...)
+		}
+
+		require.Len(t, store.deliveries, 3)
+		for i, d := range store.deliveries {
+			require.Equal(t, int64(1), d.OrgID)
+			require.Equal(t, "ops", d.Receiver)
+			require.Equal(t, "uid", d.IntegrationUID)
+			require.Equal(t, "slack", d.IntegrationType)
+			require.Equal(t, "{}:{alertname=\"test\"}", d.GroupKey)
+			require.Equal(t, 2, d.Alerts)
+			require.Equal(t, i, d.Retry)
+		}
+		require.Equal(t, models.NotificationDeliveryFailure, store.deliveries[0].Status)
+		require.Equal(t, "503 Service Unavailable", store.deliveries[0].Error)
+		require.True(t, store.deliveries[0].WillRetry)
+		require.Equal(t, models.NotificationDeliverySuccess, store.deliveries[2].Status)
+		require.False(t, store.deliveries[2].WillRetry)
+		require.Empty(t, deliveryLog.attempts)
+
+		require.Equal(t, 2.0, testutil.ToFloat64(m.NotificationDeliveries.WithLabelValues("1", "slack", "failure")))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.NotificationDeliveries.WithLabelValues("1", "slack", "success")))
+		require.Equal(t, 2.0, testutil.ToFloat64(m.NotificationDeliveryRetries.WithLabelValues("1", "slack")))
+	})
+
+	t.Run("counts the attempts of each flush separately", func(t *testing.T) {
+		store := &fakeNotificationLogStore{}
+		deliveryLog := NewDeliveryLog(store, time.Hour, nil, log.NewNopLogger())
+		n := deliveryLog.wrap(1, "ops", 0, "uid", "slack", &failingNotifier{failures: 2})
+
+		now := time.Now()
+		_, _ = n.Notify(notificationCtx("group", now))
+		_, _ = n.Notify(notificationCtx("group", now.Add(time.Minute)))
+
+		require.Len(t, store.deliveries, 2)
+		require.Equal(t, 0, store.deliveries[0].Retry)
+		require.Equal(t, 0, store.deliveries[1].Retry)
+	})
+
+	t.Run("does not persist the attempts without retention", func(t *testing.T) {
+		store := &fakeNotificationLogStore{}
+		deliveryLog := NewDeliveryLog(store, 0, nil, log.NewNopLogger())
+		n := deliveryLog.wrap(1, "ops", 0, "uid", "slack", &failingNotifier{})
+
+		_, _ = n.Notify(notificationCtx("group", time.Now()))
+		deliveryLog.maintenance(context.Background())
+
+		require.Empty(t, store.deliveries)
+		require.True(t, store.before.IsZero())
+	})
+
+	t.Run("maintenance deletes the expired deliveries and forgets old attempts", func(t *testing.T) {
+		store := &fakeNotificationLogStore{}
+		deliveryLog := NewDeliveryLog(store, 24*time.Hour, nil, log.NewNopLogger())
+		deliveryLog.attempts[deliveryKey{groupKey: "old"}] = deliveryAttempts{count: 1, started: time.Now().Add(-2 * deliveryAttemptsTTL)}
+		deliveryLog.attempts[deliveryKey{groupKey: "recent"}] = deliveryAttempts{count: 1, started: time.Now()}
+
+		deliveryLog.maintenance(context.Background())
+
+		require.Len(t, deliveryLog.attempts, 1)
+		require.Contains(t, deliveryLog.attempts, deliveryKey{groupKey: "recent"})
+		require.WithinDuration(t, time.Now().Add(-24*time.Hour), store.before, time.Minute)
+	})
+}
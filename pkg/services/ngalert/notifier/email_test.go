@@ -282,7 +282,7 @@ func createEmailSender(t *testing.T) *emailSender {
 	cfg.Smtp.Host = "localhost:1234"
 	mailer := notifications.NewFakeMailer()
 
-	ns, err := notifications.ProvideService(bus, cfg, mailer, nil)
+	ns, err := notifications.ProvideService(bus, cfg, mailer, nil, tracing.InitializeTracerForTest())
 	require.NoError(t, err)
 
 	return &emailSender{ns: ns}
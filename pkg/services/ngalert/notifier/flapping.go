@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// flappingPolicy resolves the flapping action of the notification policies that an alert is routed to.
+type flappingPolicy struct {
+	root    *dispatch.Route
+	actions map[*dispatch.Route]apimodels.FlappingAction
+}
+
+// newFlappingPolicy returns nil if no notification policy of the tree sets an action other than notify,
+// in which case there is no need to match the alerts.
+func newFlappingPolicy(route *apimodels.Route) *flappingPolicy {
+	if route == nil || !hasFlappingAction(route) {
+		return nil
+	}
+	p := &flappingPolicy{
+		root:    dispatch.NewRoute(route.AsAMRoute(), nil),
+		actions: make(map[*dispatch.Route]apimodels.FlappingAction),
+	}
+	p.index(p.root, route, apimodels.FlappingActionNotify)
+	return p
+}
+
+// index walks the routing tree built by the Alertmanager together with the tree it was built from,
+// the children of both trees are in the same order.
+func (p *flappingPolicy) index(r *dispatch.Route, route *apimodels.Route, inherited apimodels.FlappingAction) {
+	action := inherited
+	if route.FlappingAction != "" {
+		action = route.FlappingAction
+	}
+	p.actions[r] = action
+	for i, child := range r.Routes {
+		if i < len(route.Routes) {
+			p.index(child, route.Routes[i], action)
+		}
+	}
+}
+
+// action returns the most permissive action of the policies matching the labels, so that a policy
+// that suppresses the notifications of flapping alerts does not silence another policy the alert is routed to.
+func (p *flappingPolicy) action(labels model.LabelSet) apimodels.FlappingAction {
+	result := apimodels.FlappingActionSuppress
+	for _, r := range p.root.Match(labels) {
+		switch p.actions[r] {
+		case apimodels.FlappingActionNotify:
+			return apimodels.FlappingActionNotify
+		case apimodels.FlappingActionCoalesce:
+			result = apimodels.FlappingActionCoalesce
+		}
+	}
+	return result
+}
+
+// filter drops the alerts of flapping instances that the notification policies suppress, and the
+// resolved alerts of flapping instances that the notification policies coalesce.
+func (p *flappingPolicy) filter(alerts apimodels.PostableAlerts, now time.Time) apimodels.PostableAlerts {
+	if p == nil {
+		return alerts
+	}
+	result := apimodels.PostableAlerts{PostableAlerts: make([]amv2.PostableAlert, 0, len(alerts.PostableAlerts))}
+	for _, alert := range alerts.PostableAlerts {
+		if alert.Annotations[ngmodels.FlappingAnnotation] != "true" {
+			result.PostableAlerts = append(result.PostableAlerts, alert)
+			continue
+		}
+		labels := make(model.LabelSet, len(alert.Labels))
+		for k, v := range alert.Labels {
+			labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		switch p.action(labels) {
+		case apimodels.FlappingActionSuppress:
+			continue
+		case apimodels.FlappingActionCoalesce:
+			if endsAt := time.Time(alert.EndsAt); !endsAt.IsZero() && !endsAt.After(now) {
+				continue
+			}
+		}
+		result.PostableAlerts = append(result.PostableAlerts, alert)
+	}
+	return result
+}
+
+func hasFlappingAction(route *apimodels.Route) bool {
+	if route.FlappingAction != "" && route.FlappingAction != apimodels.FlappingActionNotify {
+		return true
+	}
+	for _, child := range route.Routes {
+		if hasFlappingAction(child) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestFlappingPolicy(t *testing.T) {
+	matcher := func(name, value string) apimodels.ObjectMatchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		require.NoError(t, err)
+		return apimodels.ObjectMatchers{m}
+	}
+	route := &apimodels.Route{
+		Receiver: "default",
+		Routes: []*apimodels.Route{
+			{
+				Receiver:       "team-a",
+				ObjectMatchers: matcher("team", "a"),
+				FlappingAction: apimodels.FlappingActionSuppress,
+				Routes: []*apimodels.Route{
+					{Receiver: "team-a-db", ObjectMatchers: matcher("service", "db")},
+					{Receiver: "team-a-web", ObjectMatchers: matcher("service", "web"), FlappingAction: apimodels.FlappingActionCoalesce},
+				},
+			},
+			{
+				Receiver:       "team-b",
+				ObjectMatchers: matcher("team", "b"),
+				FlappingAction: apimodels.FlappingActionCoalesce,
+				Continue:       true,
+			},
+			{
+				Receiver:       "team-b-oncall",
+				ObjectMatchers: matcher("team", "b"),
+				FlappingAction: apimodels.FlappingActionNotify,
+			},
+		},
+	}
+
+	now := time.Now()
+	alert := func(flapping bool, resolved bool, lbls ...string) amv2.PostableAlert {
+		a := amv2.PostableAlert{
+			Annotations: amv2.LabelSet{},
+			Alert:       amv2.Alert{Labels: amv2.LabelSet{}},
+			EndsAt:      strfmt.DateTime(now.Add(time.Minute)),
+		}
+		if flapping {
+			a.Annotations[ngmodels.FlappingAnnotation] = "true"
+		}
+		if resolved {
+			a.EndsAt = strfmt.DateTime(now.Add(-time.Second))
+		}
+		for i := 0; i < len(lbls); i += 2 {
+			a.Labels[lbls[i]] = lbls[i+1]
+		}
+		return a
+	}
+
+	t.Run("should be nil when no policy damps notifications", func(t *testing.T) {
+		require.Nil(t, newFlappingPolicy(&apimodels.Route{Receiver: "default", FlappingAction: apimodels.FlappingActionNotify}))
+		var p *flappingPolicy
+		alerts := apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{alert(true, false)}}
+		require.Equal(t, alerts, p.filter(alerts, now))
+	})
+
+	p := newFlappingPolicy(route)
+	require.NotNil(t, p)
+
+	testCases := []struct {
+		name     string
+		alert    amv2.PostableAlert
+		expected bool
+	}{
+		{"alerts that are not flapping are kept", alert(false, false, "team", "a"), true},
+		{"flapping alerts of the default policy are kept", alert(true, true, "team", "c"), true},
+		{"flapping alerts of a suppressing policy are dropped", alert(true, false, "team", "a"), false},
+		{"the action is inherited by the child policies", alert(true, false, "team", "a", "service", "db"), false},
+		{"child policies override the inherited action", alert(true, false, "team", "a", "service", "web"), true},
+		{"resolved flapping alerts of a coalescing policy are dropped", alert(true, true, "team", "a", "service", "web"), false},
+		{"the most permissive of the matching policies applies", alert(true, true, "team", "b"), true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := p.filter(apimodels.PostableAlerts{PostableAlerts: []amv2.PostableAlert{tc.alert}}, now)
+			if tc.expected {
+				require.Len(t, result.PostableAlerts, 1)
+			} else {
+				require.Empty(t, result.PostableAlerts)
+			}
+		})
+	}
+
+	t.Run("should reject unknown actions", func(t *testing.T) {
+		r := &apimodels.Route{Receiver: "default", FlappingAction: "drop"}
+		require.Error(t, r.Validate())
+	})
+}
@@ -2,10 +2,12 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"github.com/grafana/alerting/images"
 
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 )
@@ -38,3 +40,21 @@ func (i imageStore) GetImage(ctx context.Context, token string) (*images.Image,
 	}
 	return result, err
 }
+
+// includeImagesSettings is the setting of a contact point opting in to the screenshots of its alerts, when the
+// contact points must opt in.
+type includeImagesSettings struct {
+	IncludeImages bool `json:"includeImages"`
+}
+
+// includeImages returns true if the screenshots of the alerts are attached to the notifications of the receiver.
+func (am *Alertmanager) includeImages(r *apimodels.PostableGrafanaReceiver) bool {
+	if am.Settings == nil || !am.Settings.UnifiedAlerting.Screenshots.ContactPointOptIn {
+		return true
+	}
+	var settings includeImagesSettings
+	if len(r.Settings) == 0 || json.Unmarshal(r.Settings, &settings) != nil {
+		return false
+	}
+	return settings.IncludeImages
+}
@@ -51,13 +51,14 @@ type MultiOrgAlertmanager struct {
 
 	decryptFn receivers.GetDecryptedValueFn
 
-	metrics *metrics.MultiOrgAlertmanager
-	ns      notifications.Service
+	metrics     *metrics.MultiOrgAlertmanager
+	ns          notifications.Service
+	deliveryLog *DeliveryLog
 }
 
 func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgStore store.OrgStore,
-	kvStore kvstore.KVStore, provStore provisioning.ProvisioningStore, decryptFn receivers.GetDecryptedValueFn,
-	m *metrics.MultiOrgAlertmanager, ns notifications.Service, l log.Logger, s secrets.Service,
+	kvStore kvstore.KVStore, provStore provisioning.ProvisioningStore, notificationLogStore store.NotificationLogStore,
+	decryptFn receivers.GetDecryptedValueFn, m *metrics.MultiOrgAlertmanager, ns notifications.Service, l log.Logger, s secrets.Service,
 ) (*MultiOrgAlertmanager, error) {
 	moa := &MultiOrgAlertmanager{
 		Crypto:    NewCrypto(s, configStore, l),
@@ -72,6 +73,7 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 		decryptFn:     decryptFn,
 		metrics:       m,
 		ns:            ns,
+		deliveryLog:   NewDeliveryLog(notificationLogStore, cfg.UnifiedAlerting.NotificationLogRetention, m, l.New("component", "notification-log")),
 	}
 
 	clusterLogger := l.New("component", "cluster")
@@ -115,6 +117,9 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 	moa.logger.Info("starting MultiOrg Alertmanager")
 
+	deliveryLogMaintenance := time.NewTicker(deliveryLogMaintenanceInterval)
+	defer deliveryLogMaintenance.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -124,6 +129,8 @@ func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 			if err := moa.LoadAndSyncAlertmanagersForOrgs(ctx); err != nil {
 				moa.logger.Error("error while synchronizing Alertmanager orgs", "error", err)
 			}
+		case <-deliveryLogMaintenance.C:
+			moa.deliveryLog.maintenance(ctx)
 		}
 	}
 }
@@ -183,7 +190,7 @@ func (moa *MultiOrgAlertmanager) SyncAlertmanagersForOrgs(ctx context.Context, o
 			// To export them, we need to translate the metrics from each individual registry and,
 			// then aggregate them on the main registry.
 			m := metrics.NewAlertmanagerMetrics(moa.metrics.GetOrCreateOrgRegistry(orgID))
-			am, err := newAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m)
+			am, err := newAlertmanager(ctx, orgID, moa.settings, moa.configStore, moa.kvStore, moa.peer, moa.decryptFn, moa.ns, m, moa.deliveryLog)
 			if err != nil {
 				moa.logger.Error("unable to create Alertmanager for org", "org", orgID, "error", err)
 			}
@@ -57,6 +57,10 @@ func stateToPostableAlert(alertState *state.State, appURL *url.URL) *models.Post
 		nA[alertingModels.StateReasonAnnotation] = alertState.StateReason
 	}
 
+	if alertState.Flapping {
+		nA[ngModels.FlappingAnnotation] = "true"
+	}
+
 	if alertState.OrgID != 0 {
 		nA[alertingModels.OrgIDAnnotation] = strconv.FormatInt(alertState.OrgID, 10)
 	}
@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// compositeEvaluator evaluates a composite rule: instead of querying data sources, it reads the current state of
+// the rules it depends on. A rule fires if any of its alert instances is alerting.
+type compositeEvaluator struct {
+	orgID     int64
+	condition *ngmodels.CompositeCondition
+	states    func(orgID int64, ruleUID string) []*state.State
+}
+
+func newCompositeEvaluator(rule *ngmodels.AlertRule, states func(orgID int64, ruleUID string) []*state.State) (eval.ConditionEvaluator, error) {
+	condition, err := rule.GetCompositeCondition()
+	if err != nil {
+		return nil, err
+	}
+	return &compositeEvaluator{orgID: rule.OrgID, condition: condition, states: states}, nil
+}
+
+func (e *compositeEvaluator) EvaluateRaw(_ context.Context, _ time.Time) (*backend.QueryDataResponse, error) {
+	return nil, errors.New("composite rules do not query data sources")
+}
+
+func (e *compositeEvaluator) Evaluate(_ context.Context, now time.Time) (eval.Results, error) {
+	firing := make(map[string]bool, len(e.condition.Rules))
+	for _, uid := range e.condition.Dependencies() {
+		for _, s := range e.states(e.orgID, uid) {
+			if s.State == eval.Alerting {
+				firing[uid] = true
+				break
+			}
+		}
+	}
+
+	result := eval.Normal
+	if e.condition.Evaluate(func(uid string) bool { return firing[uid] }) {
+		result = eval.Alerting
+	}
+
+	refs := make([]string, 0, len(e.condition.Rules))
+	for ref, uid := range e.condition.Rules {
+		refs = append(refs, fmt.Sprintf("%s=%t", ref, firing[uid]))
+	}
+	sort.Strings(refs)
+
+	return eval.Results{{
+		Instance:         data.Labels{},
+		State:            result,
+		EvaluatedAt:      now,
+		EvaluationString: fmt.Sprintf("[ expression=%s %s ]", e.condition.Expression, strings.Join(refs, " ")),
+	}}, nil
+}
@@ -0,0 +1,48 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestCompositeEvaluator(t *testing.T) {
+	model, err := json.Marshal(ngmodels.CompositeModel{Expression: "A && B", Rules: map[string]string{"A": "a", "B": "b"}})
+	require.NoError(t, err)
+	rule := &ngmodels.AlertRule{
+		OrgID:     1,
+		UID:       "c",
+		Condition: "A",
+		Data:      []ngmodels.AlertQuery{{RefID: "A", DatasourceUID: ngmodels.CompositeDatasourceUID, Model: model}},
+	}
+	require.True(t, rule.IsComposite())
+
+	states := map[string][]*state.State{}
+	evaluator, err := newCompositeEvaluator(rule, func(orgID int64, ruleUID string) []*state.State {
+		require.Equal(t, int64(1), orgID)
+		return states[ruleUID]
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	states["a"] = []*state.State{{State: eval.Normal}, {State: eval.Alerting}}
+	states["b"] = []*state.State{{State: eval.Pending}}
+	results, err := evaluator.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, eval.Normal, results[0].State)
+	require.Equal(t, now, results[0].EvaluatedAt)
+
+	states["b"] = []*state.State{{State: eval.Alerting}}
+	results, err = evaluator.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	require.Equal(t, eval.Alerting, results[0].State)
+	require.Equal(t, "[ expression=A && B A=true B=true ]", results[0].EvaluationString)
+}
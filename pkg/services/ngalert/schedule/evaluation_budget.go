@@ -0,0 +1,184 @@
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// EvaluationBudget tracks the cost of the evaluations of the rules over a rolling window, and pauses the rules
+// that exceed their quota, and the most expensive rule of the folders that exceed their budget, until the end
+// of the window. This protects the data sources shared by the rules from a few expensive rules.
+type EvaluationBudget struct {
+	cfg   setting.UnifiedAlertingEvaluationBudgetSettings
+	clock clock.Clock
+	log   log.Logger
+
+	mtx   sync.Mutex
+	rules map[ngmodels.AlertRuleKey]*ruleEvaluationCosts
+}
+
+type ruleEvaluationCosts struct {
+	namespaceUID string
+	samples      []evaluationCostSample
+	last         ngmodels.EvaluationCost
+	pausedUntil  time.Time
+	pauseReason  string
+}
+
+type evaluationCostSample struct {
+	at   time.Time
+	cost ngmodels.EvaluationCost
+}
+
+func NewEvaluationBudget(cfg setting.UnifiedAlertingEvaluationBudgetSettings, clk clock.Clock) *EvaluationBudget {
+	return &EvaluationBudget{
+		cfg:   cfg,
+		clock: clk,
+		log:   log.New("ngalert.scheduler.budget"),
+		rules: make(map[ngmodels.AlertRuleKey]*ruleEvaluationCosts),
+	}
+}
+
+// prune drops the samples that are not in the window and returns the cost of the remaining ones.
+func (r *ruleEvaluationCosts) prune(since time.Time) ngmodels.EvaluationCost {
+	idx := 0
+	for idx < len(r.samples) && !r.samples[idx].at.After(since) {
+		idx++
+	}
+	r.samples = r.samples[idx:]
+	var total ngmodels.EvaluationCost
+	for _, s := range r.samples {
+		total = total.Add(s.cost)
+	}
+	return total
+}
+
+func (r *ruleEvaluationCosts) pause(until time.Time, reason string) {
+	r.pausedUntil = until
+	r.pauseReason = reason
+	r.samples = nil
+}
+
+// IsPaused returns true if the evaluations of the rule are paused.
+func (b *EvaluationBudget) IsPaused(key ngmodels.AlertRuleKey) bool {
+	if b == nil {
+		return false
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	r, ok := b.rules[key]
+	return ok && b.clock.Now().Before(r.pausedUntil)
+}
+
+// Record adds the cost of an evaluation of the rule, and pauses the rule, or the most expensive rule of its
+// folder, if this evaluation exceeds the quota of the rule or the budget of the folder.
+func (b *EvaluationBudget) Record(rule *ngmodels.AlertRule, cost ngmodels.EvaluationCost) {
+	if b == nil {
+		return
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := b.clock.Now()
+	since := now.Add(-b.cfg.Window)
+	key := rule.GetKey()
+	r, ok := b.rules[key]
+	if !ok {
+		r = &ruleEvaluationCosts{}
+		b.rules[key] = r
+	}
+	r.namespaceUID = rule.NamespaceUID
+	r.last = cost
+	r.samples = append(r.samples, evaluationCostSample{at: now, cost: cost})
+
+	total := r.prune(since)
+	if b.cfg.RuleMaxDuration > 0 && total.Duration > b.cfg.RuleMaxDuration {
+		b.pause(key, r, now, fmt.Sprintf("the evaluations of the rule took %s in the last %s, exceeding the quota of %s", total.Duration, b.cfg.Window, b.cfg.RuleMaxDuration))
+		return
+	}
+	if b.cfg.RuleMaxDatapoints > 0 && total.Datapoints > b.cfg.RuleMaxDatapoints {
+		b.pause(key, r, now, fmt.Sprintf("the evaluations of the rule returned %d datapoints in the last %s, exceeding the quota of %d", total.Datapoints, b.cfg.Window, b.cfg.RuleMaxDatapoints))
+		return
+	}
+
+	if b.cfg.FolderMaxDuration <= 0 && b.cfg.FolderMaxDatapoints <= 0 {
+		return
+	}
+	var folderTotal ngmodels.EvaluationCost
+	costs := make(map[ngmodels.AlertRuleKey]ngmodels.EvaluationCost)
+	for k, other := range b.rules {
+		if k.OrgID != key.OrgID || other.namespaceUID != r.namespaceUID {
+			continue
+		}
+		c := other.prune(since)
+		costs[k] = c
+		folderTotal = folderTotal.Add(c)
+	}
+	if b.cfg.FolderMaxDuration > 0 && folderTotal.Duration > b.cfg.FolderMaxDuration {
+		noisiest := noisiestRule(costs, func(c ngmodels.EvaluationCost) int64 { return int64(c.Duration) })
+		b.pause(noisiest, b.rules[noisiest], now, fmt.Sprintf("the evaluations of the rules of the folder took %s in the last %s, exceeding its budget of %s, and this rule took the longest (%s)", folderTotal.Duration, b.cfg.Window, b.cfg.FolderMaxDuration, costs[noisiest].Duration))
+		return
+	}
+	if b.cfg.FolderMaxDatapoints > 0 && folderTotal.Datapoints > b.cfg.FolderMaxDatapoints {
+		noisiest := noisiestRule(costs, func(c ngmodels.EvaluationCost) int64 { return c.Datapoints })
+		b.pause(noisiest, b.rules[noisiest], now, fmt.Sprintf("the evaluations of the rules of the folder returned %d datapoints in the last %s, exceeding its budget of %d, and this rule returned the most (%d)", folderTotal.Datapoints, b.cfg.Window, b.cfg.FolderMaxDatapoints, costs[noisiest].Datapoints))
+	}
+}
+
+func (b *EvaluationBudget) pause(key ngmodels.AlertRuleKey, r *ruleEvaluationCosts, now time.Time, reason string) {
+	until := now.Add(b.cfg.Window)
+	b.log.Warn("Pausing the evaluations of the rule because of its evaluation cost", append(key.LogContext(), "until", until, "reason", reason)...)
+	r.pause(until, reason)
+}
+
+// noisiestRule returns the rule with the highest cost, the lowest key on ties to be deterministic.
+func noisiestRule(costs map[ngmodels.AlertRuleKey]ngmodels.EvaluationCost, value func(ngmodels.EvaluationCost) int64) ngmodels.AlertRuleKey {
+	var result ngmodels.AlertRuleKey
+	var max int64 = -1
+	for k, c := range costs {
+		if v := value(c); v > max || v == max && k.UID < result.UID {
+			result, max = k, v
+		}
+	}
+	return result
+}
+
+// Forget drops the costs of the rule, e.g. when it is deleted.
+func (b *EvaluationBudget) Forget(key ngmodels.AlertRuleKey) {
+	if b == nil {
+		return
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	delete(b.rules, key)
+}
+
+// GetRuleEvaluationCost returns the cost of the evaluations of the rule, false if the rule was not evaluated.
+func (b *EvaluationBudget) GetRuleEvaluationCost(key ngmodels.AlertRuleKey) (ngmodels.RuleEvaluationCost, bool) {
+	if b == nil {
+		return ngmodels.RuleEvaluationCost{}, false
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	r, ok := b.rules[key]
+	if !ok {
+		return ngmodels.RuleEvaluationCost{}, false
+	}
+	now := b.clock.Now()
+	result := ngmodels.RuleEvaluationCost{
+		Last:   r.last,
+		Window: r.prune(now.Add(-b.cfg.Window)),
+	}
+	if now.Before(r.pausedUntil) {
+		result.PausedUntil = r.pausedUntil
+		result.PauseReason = r.pauseReason
+	}
+	return result, true
+}
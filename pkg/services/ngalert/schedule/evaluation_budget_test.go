@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestEvaluationBudget(t *testing.T) {
+	rule := func(uid, namespaceUID string) *ngmodels.AlertRule {
+		return &ngmodels.AlertRule{OrgID: 1, UID: uid, NamespaceUID: namespaceUID}
+	}
+
+	t.Run("should track the cost of the evaluations in the window", func(t *testing.T) {
+		clk := clock.NewMock()
+		b := NewEvaluationBudget(setting.UnifiedAlertingEvaluationBudgetSettings{Window: time.Minute}, clk)
+		r := rule("a", "folder")
+
+		_, ok := b.GetRuleEvaluationCost(r.GetKey())
+		require.False(t, ok)
+
+		b.Record(r, ngmodels.EvaluationCost{Duration: time.Second, Datapoints: 10})
+		clk.Add(30 * time.Second)
+		b.Record(r, ngmodels.EvaluationCost{Duration: 2 * time.Second, Datapoints: 20})
+
+		cost, ok := b.GetRuleEvaluationCost(r.GetKey())
+		require.True(t, ok)
+		require.Equal(t, ngmodels.EvaluationCost{Duration: 2 * time.Second, Datapoints: 20}, cost.Last)
+		require.Equal(t, ngmodels.EvaluationCost{Duration: 3 * time.Second, Datapoints: 30}, cost.Window)
+		require.False(t, b.IsPaused(r.GetKey()))
+
+		clk.Add(time.Minute)
+		cost, _ = b.GetRuleEvaluationCost(r.GetKey())
+		require.Equal(t, ngmodels.EvaluationCost{}, cost.Window)
+
+		b.Forget(r.GetKey())
+		_, ok = b.GetRuleEvaluationCost(r.GetKey())
+		require.False(t, ok)
+	})
+
+	t.Run("should pause the rule exceeding its quota until the end of the window", func(t *testing.T) {
+		clk := clock.NewMock()
+		b := NewEvaluationBudget(setting.UnifiedAlertingEvaluationBudgetSettings{Window: time.Minute, RuleMaxDatapoints: 100}, clk)
+		r := rule("a", "folder")
+
+		b.Record(r, ngmodels.EvaluationCost{Datapoints: 60})
+		require.False(t, b.IsPaused(r.GetKey()))
+		b.Record(r, ngmodels.EvaluationCost{Datapoints: 60})
+		require.True(t, b.IsPaused(r.GetKey()))
+
+		cost, _ := b.GetRuleEvaluationCost(r.GetKey())
+		require.Equal(t, clk.Now().Add(time.Minute), cost.PausedUntil)
+		require.Contains(t, cost.PauseReason, "exceeding the quota of 100")
+
+		clk.Add(time.Minute)
+		require.False(t, b.IsPaused(r.GetKey()))
+		cost, _ = b.GetRuleEvaluationCost(r.GetKey())
+		require.False(t, cost.IsPaused(clk.Now()))
+		require.Empty(t, cost.PauseReason)
+	})
+
+	t.Run("should pause the noisiest rule of the folder exceeding its budget", func(t *testing.T) {
+		clk := clock.NewMock()
+		b := NewEvaluationBudget(setting.UnifiedAlertingEvaluationBudgetSettings{Window: time.Minute, FolderMaxDuration: 10 * time.Second}, clk)
+		cheap, noisy, other := rule("cheap", "folder"), rule("noisy", "folder"), rule("other", "other-folder")
+
+		b.Record(other, ngmodels.EvaluationCost{Duration: 9 * time.Second})
+		b.Record(noisy, ngmodels.EvaluationCost{Duration: 7 * time.Second})
+		require.False(t, b.IsPaused(noisy.GetKey()))
+		b.Record(cheap, ngmodels.EvaluationCost{Duration: 4 * time.Second})
+
+		require.True(t, b.IsPaused(noisy.GetKey()))
+		require.False(t, b.IsPaused(cheap.GetKey()))
+		require.False(t, b.IsPaused(other.GetKey()))
+		cost, _ := b.GetRuleEvaluationCost(noisy.GetKey())
+		require.Contains(t, cost.PauseReason, "exceeding its budget of 10s")
+	})
+
+	t.Run("should be a no-op when nil", func(t *testing.T) {
+		var b *EvaluationBudget
+		b.Record(rule("a", "folder"), ngmodels.EvaluationCost{Duration: time.Second})
+		require.False(t, b.IsPaused(rule("a", "folder").GetKey()))
+		_, ok := b.GetRuleEvaluationCost(rule("a", "folder").GetKey())
+		require.False(t, ok)
+	})
+}
@@ -14,6 +14,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -24,6 +26,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/ticker"
 )
 
@@ -92,6 +95,14 @@ type schedule struct {
 	schedulableAlertRules alertRulesRegistry
 
 	tracer tracing.Tracer
+
+	// drainTimeout is how long the evaluations in progress are given to complete
+	// once the scheduler stops, they are cancelled right away when 0.
+	drainTimeout time.Duration
+
+	evaluationBudget *EvaluationBudget
+
+	bus bus.Bus
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -107,6 +118,11 @@ type SchedulerCfg struct {
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
+	DrainTimeout         time.Duration
+	// EvaluationBudget tracks the cost of the evaluations and pauses the rules exceeding their quota or budget, optional.
+	EvaluationBudget *EvaluationBudget
+	// Bus publishes an AlertFired event when an alert starts firing, optional.
+	Bus bus.Bus
 }
 
 // NewScheduler returns a new schedule.
@@ -127,6 +143,9 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
+		drainTimeout:          cfg.DrainTimeout,
+		evaluationBudget:      cfg.EvaluationBudget,
+		bus:                   cfg.Bus,
 	}
 
 	return &sch
@@ -159,6 +178,7 @@ func (sch *schedule) deleteAlertRule(keys ...ngmodels.AlertRuleKey) {
 		}
 		// stop rule evaluation
 		ruleInfo.stop(errRuleDeleted)
+		sch.evaluationBudget.Forget(key)
 	}
 	// Our best bet at this point is that we update the metrics with what we hope to schedule in the next tick.
 	alertRules, _ := sch.schedulableAlertRules.all()
@@ -388,7 +408,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			},
 		}
 		evalCtx := eval.Context(ctx, schedulerUser)
-		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+		var ruleEval eval.ConditionEvaluator
+		var err error
+		if e.rule.IsComposite() {
+			ruleEval, err = newCompositeEvaluator(e.rule, sch.stateManager.GetStatesForRuleUID)
+		} else {
+			ruleEval, err = sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+		}
 		var results eval.Results
 		var dur time.Duration
 		if err == nil {
@@ -401,6 +427,12 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		}
 		dur = sch.clock.Now().Sub(start)
 
+		cost := ngmodels.EvaluationCost{Duration: dur}
+		if len(results) > 0 {
+			cost.Datapoints = results[0].Datapoints
+		}
+		sch.evaluationBudget.Record(e.rule, cost)
+
 		evalTotal.Inc()
 		evalDuration.Observe(dur.Seconds())
 
@@ -448,6 +480,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		if len(alerts.PostableAlerts) > 0 {
 			sch.alertsSender.Send(key, alerts)
 		}
+		sch.publishFiredAlerts(ctx, processedStates)
 	}
 
 	retryIfError := func(f func(attempt int64) error) error {
@@ -516,7 +549,13 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 					if isPaused {
 						return nil
 					}
-					tracingCtx, span := sch.tracer.Start(grafanaCtx, "alert rule execution")
+					if sch.evaluationBudget.IsPaused(key) {
+						logger.Debug("Skip the evaluation because the rule exceeded its evaluation budget")
+						return nil
+					}
+					evalCtx, cancelEval := sch.evaluationContext(grafanaCtx)
+					defer cancelEval()
+					tracingCtx, span := sch.tracer.Start(evalCtx, "alert rule execution", tracing.WithComponent("alerting"))
 					defer span.End()
 
 					span.SetAttributes("rule_uid", ctx.rule.UID, attribute.String("rule_uid", ctx.rule.UID))
@@ -549,6 +588,33 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 }
 
+// evaluationContext returns the context of an evaluation of the rule. The evaluation is not cancelled right away
+// when the scheduler stops, so that the state and the notifications of the evaluations in progress are not lost
+// on shutdown, only after the drain timeout. It is cancelled right away when the rule is deleted.
+func (sch *schedule) evaluationContext(ruleCtx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(util.WithoutCancel(ruleCtx))
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ruleCtx.Done():
+		}
+		if sch.drainTimeout <= 0 || errors.Is(ruleCtx.Err(), errRuleDeleted) {
+			cancel()
+			return
+		}
+		timer := time.NewTimer(sch.drainTimeout)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			sch.log.FromContext(ctx).Warn("Evaluation cancelled because it did not complete within the drain timeout", "timeout", sch.drainTimeout)
+			cancel()
+		}
+	}()
+	return ctx, cancel
+}
+
 // evalApplied is only used on tests.
 func (sch *schedule) evalApplied(alertDefKey ngmodels.AlertRuleKey, now time.Time) {
 	if sch.evalAppliedFunc == nil {
@@ -579,3 +645,25 @@ func (sch *schedule) getRuleExtraLabels(evalCtx *evaluation) map[string]string {
 	}
 	return extraLabels
 }
+
+// publishFiredAlerts publishes an AlertFired event for each alert that started firing.
+func (sch *schedule) publishFiredAlerts(ctx context.Context, states []state.StateTransition) {
+	if sch.bus == nil {
+		return
+	}
+	for _, s := range states {
+		if s.State.State != eval.Alerting || s.PreviousState == eval.Alerting {
+			continue
+		}
+		if err := sch.bus.Publish(ctx, &events.AlertFired{
+			Timestamp:   sch.clock.Now(),
+			RuleUID:     s.AlertRuleUID,
+			OrgID:       s.OrgID,
+			Labels:      s.Labels,
+			Annotations: s.Annotations,
+			StartsAt:    s.StartsAt,
+		}); err != nil {
+			sch.log.Warn("Failed to publish the fired alert", "ruleUID", s.AlertRuleUID, "error", err)
+		}
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
@@ -764,6 +766,70 @@ func TestSchedule_deleteAlertRule(t *testing.T) {
 	})
 }
 
+func TestSchedule_evaluationContext(t *testing.T) {
+	t.Run("evaluations are cancelled with the rule when there is no drain timeout", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		ruleCtx, stop := util.WithCancelCause(context.Background())
+		ctx, cancel := sch.evaluationContext(ruleCtx)
+		defer cancel()
+
+		stop(context.Canceled)
+		require.Eventually(t, func() bool { return ctx.Err() != nil }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("evaluations outlive the scheduler until the drain timeout", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.drainTimeout = 200 * time.Millisecond
+		ruleCtx, stop := util.WithCancelCause(context.Background())
+		ctx, cancel := sch.evaluationContext(ruleCtx)
+		defer cancel()
+
+		stop(context.Canceled)
+		require.Never(t, func() bool { return ctx.Err() != nil }, 100*time.Millisecond, 10*time.Millisecond)
+		require.Eventually(t, func() bool { return ctx.Err() != nil }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("evaluations are cancelled right away when the rule is deleted", func(t *testing.T) {
+		sch := setupScheduler(t, nil, nil, nil, nil, nil)
+		sch.drainTimeout = time.Hour
+		ruleCtx, stop := util.WithCancelCause(context.Background())
+		ctx, cancel := sch.evaluationContext(ruleCtx)
+		defer cancel()
+
+		stop(errRuleDeleted)
+		require.Eventually(t, func() bool { return ctx.Err() != nil }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestSchedule_publishFiredAlerts(t *testing.T) {
+	sch := setupScheduler(t, nil, nil, nil, nil, nil)
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	sch.bus = b
+
+	var fired []*events.AlertFired
+	b.AddEventListener(func(_ context.Context, e *events.AlertFired) error {
+		fired = append(fired, e)
+		return nil
+	})
+
+	transition := func(uid string, previous, current eval.State) state.StateTransition {
+		return state.StateTransition{
+			State:         &state.State{OrgID: 1, AlertRuleUID: uid, State: current, Labels: data.Labels{"alertname": uid}},
+			PreviousState: previous,
+		}
+	}
+	sch.publishFiredAlerts(context.Background(), []state.StateTransition{
+		transition("started", eval.Pending, eval.Alerting),
+		transition("still-firing", eval.Alerting, eval.Alerting),
+		transition("resolved", eval.Alerting, eval.Normal),
+	})
+
+	require.Len(t, fired, 1)
+	assert.Equal(t, "started", fired[0].RuleUID)
+	assert.Equal(t, int64(1), fired[0].OrgID)
+	assert.Equal(t, map[string]string{"alertname": "started"}, fired[0].Labels)
+}
+
 func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStore, registry *prometheus.Registry, senderMock *AlertsSenderMock, evalMock eval.EvaluatorFactory) *schedule {
 	t.Helper()
 	testTracer := tracing.InitializeTracerForTest()
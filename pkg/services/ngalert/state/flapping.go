@@ -0,0 +1,46 @@
+package state
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+)
+
+// FlapDetection configures the detection of alert instances that change between firing and not firing too often.
+type FlapDetection struct {
+	// Window is the rolling window in which the transitions are counted.
+	Window time.Duration
+	// Threshold is the number of transitions in the window from which an alert instance is flapping.
+	// Zero disables the detection.
+	Threshold int
+}
+
+// Enabled returns true if transitions should be counted.
+func (f FlapDetection) Enabled() bool {
+	return f.Threshold > 0 && f.Window > 0
+}
+
+// updateFlapping records a transition if the state changed between firing and not firing, drops the transitions
+// that are older than the window and updates Flapping. An instance starts flapping when the number of transitions
+// reaches the threshold and stops flapping when it drops below half of the threshold, so that an instance does not
+// keep entering and leaving the flapping state.
+func (a *State) updateFlapping(previous eval.State, now time.Time, cfg FlapDetection) {
+	if (previous == eval.Alerting) != (a.State == eval.Alerting) {
+		a.Transitions = append(a.Transitions, now)
+	}
+
+	cutoff := now.Add(-cfg.Window)
+	i := 0
+	for i < len(a.Transitions) && !a.Transitions[i].After(cutoff) {
+		i++
+	}
+	a.Transitions = a.Transitions[i:]
+
+	count := len(a.Transitions)
+	switch {
+	case count >= cfg.Threshold:
+		a.Flapping = true
+	case count*2 < cfg.Threshold:
+		a.Flapping = false
+	}
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+)
+
+func TestUpdateFlapping(t *testing.T) {
+	cfg := FlapDetection{Window: 10 * time.Minute, Threshold: 4}
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// evaluate flips the state every minute, n times.
+	evaluate := func(s *State, from time.Time, n int) time.Time {
+		now := from
+		for i := 0; i < n; i++ {
+			now = now.Add(time.Minute)
+			previous := s.State
+			if previous == eval.Alerting {
+				s.State = eval.Normal
+			} else {
+				s.State = eval.Alerting
+			}
+			s.updateFlapping(previous, now, cfg)
+		}
+		return now
+	}
+
+	t.Run("should not count transitions between states that do not fire", func(t *testing.T) {
+		s := &State{State: eval.Pending}
+		s.updateFlapping(eval.Normal, start, cfg)
+		s.State = eval.NoData
+		s.updateFlapping(eval.Pending, start.Add(time.Minute), cfg)
+		require.Empty(t, s.Transitions)
+		require.False(t, s.Flapping)
+	})
+
+	t.Run("should be flapping when transitions reach the threshold", func(t *testing.T) {
+		s := &State{State: eval.Normal}
+		now := evaluate(s, start, 3)
+		require.False(t, s.Flapping)
+		evaluate(s, now, 1)
+		require.True(t, s.Flapping)
+		require.Len(t, s.Transitions, 4)
+	})
+
+	t.Run("should keep flapping until transitions drop below half of the threshold", func(t *testing.T) {
+		s := &State{State: eval.Normal}
+		now := evaluate(s, start, 4)
+		require.True(t, s.Flapping)
+
+		// the transitions at minutes 1 and 2 leave the window, 2 transitions remain
+		s.updateFlapping(s.State, start.Add(12*time.Minute), cfg)
+		assert.Len(t, s.Transitions, 2)
+		assert.True(t, s.Flapping)
+
+		// the transition at minute 3 leaves the window
+		s.updateFlapping(s.State, now.Add(9*time.Minute), cfg)
+		assert.Len(t, s.Transitions, 1)
+		assert.False(t, s.Flapping)
+	})
+}
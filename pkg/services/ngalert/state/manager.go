@@ -40,6 +40,7 @@ type Manager struct {
 	externalURL   *url.URL
 
 	doNotSaveNormalState bool
+	flapDetection        FlapDetection
 }
 
 type ManagerCfg struct {
@@ -51,6 +52,8 @@ type ManagerCfg struct {
 	Historian     Historian
 	// DoNotSaveNormalState controls whether eval.Normal state is persisted to the database and returned by get methods
 	DoNotSaveNormalState bool
+	// FlapDetection configures how state transitions are counted to detect flapping alert instances
+	FlapDetection FlapDetection
 }
 
 func NewManager(cfg ManagerCfg) *Manager {
@@ -65,6 +68,7 @@ func NewManager(cfg ManagerCfg) *Manager {
 		clock:                cfg.Clock,
 		externalURL:          cfg.ExternalURL,
 		doNotSaveNormalState: cfg.DoNotSaveNormalState,
+		flapDetection:        cfg.FlapDetection,
 	}
 }
 
@@ -305,6 +309,14 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	// to Alertmanager.
 	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
 
+	if st.flapDetection.Enabled() {
+		wasFlapping := currentState.Flapping
+		currentState.updateFlapping(oldState, result.EvaluatedAt, st.flapDetection)
+		if currentState.Flapping != wasFlapping {
+			logger.Info("Alert instance flapping state changed", "flapping", currentState.Flapping, "transitions", len(currentState.Transitions))
+		}
+	}
+
 	if shouldTakeImage(currentState.State, oldState, currentState.Image, currentState.Resolved) {
 		image, err := takeImage(ctx, st.images, alertRule)
 		if err != nil {
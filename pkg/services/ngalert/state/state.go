@@ -42,6 +42,13 @@ type State struct {
 	// All subsequent states will be false until the next transition from Firing to Normal.
 	Resolved bool
 
+	// Transitions are the times the state changed between firing and not firing within the flap detection window.
+	Transitions []time.Time
+
+	// Flapping is set to true if the state changed between firing and not firing too often within the
+	// flap detection window. It is reset once the number of transitions drops below half of the threshold.
+	Flapping bool
+
 	// Image contains an optional image for the state. It tends to be included in notifications
 	// as a visualization to show why the alert fired.
 	Image *models.Image
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -63,6 +64,11 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("deleted alert instances", "count", rows)
+
+		now := TimeNow()
+		for _, uid := range ruleUID {
+			sess.PublishAfterCommit(&events.AlertRuleDeleted{Timestamp: now, UID: uid, OrgID: orgID})
+		}
 		return nil
 	})
 }
@@ -163,6 +169,15 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 					return fmt.Errorf("failed to create new rules: %w", err)
 				}
 				ids[newRules[i].UID] = newRules[i].ID
+				sess.PublishAfterCommit(&events.AlertRuleCreated{
+					Timestamp:    newRules[i].Updated,
+					Title:        newRules[i].Title,
+					UID:          newRules[i].UID,
+					OrgID:        newRules[i].OrgID,
+					NamespaceUID: newRules[i].NamespaceUID,
+					RuleGroup:    newRules[i].RuleGroup,
+					Version:      newRules[i].Version,
+				})
 			}
 		}
 
@@ -200,6 +215,15 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
 			parentVersion = r.Existing.Version
+			sess.PublishAfterCommit(&events.AlertRuleUpdated{
+				Timestamp:    r.New.Updated,
+				Title:        r.New.Title,
+				UID:          r.New.UID,
+				OrgID:        r.New.OrgID,
+				NamespaceUID: r.New.NamespaceUID,
+				RuleGroup:    r.New.RuleGroup,
+				Version:      r.New.Version + 1,
+			})
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
 				RuleOrgID:        r.New.OrgID,
 				RuleUID:          r.New.UID,
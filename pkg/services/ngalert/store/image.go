@@ -80,7 +80,7 @@ func (st DBstore) SaveImage(ctx context.Context, img *models.Image) error {
 			}
 			img.Token = token.String()
 			img.CreatedAt = TimeNow().UTC()
-			img.ExpiresAt = img.CreatedAt.Add(imageExpirationDuration)
+			img.ExpiresAt = img.CreatedAt.Add(st.imageRetention())
 			if _, err := sess.Insert(img); err != nil {
 				return fmt.Errorf("failed to insert image: %w", err)
 			}
@@ -102,6 +102,14 @@ func (st DBstore) SaveImage(ctx context.Context, img *models.Image) error {
 	})
 }
 
+// imageRetention returns the configured retention of the images, or imageExpirationDuration when unset.
+func (st DBstore) imageRetention() time.Duration {
+	if st.Cfg.Screenshots.Retention > 0 {
+		return st.Cfg.Screenshots.Retention
+	}
+	return imageExpirationDuration
+}
+
 func (st DBstore) DeleteExpiredImages(ctx context.Context) (int64, error) {
 	var n int64
 	if err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
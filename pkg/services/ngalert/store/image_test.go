@@ -68,6 +68,20 @@ func TestIntegrationSaveAndGetImage(t *testing.T) {
 	assert.Nil(t, result1)
 }
 
+func TestIntegrationSaveImageWithRetention(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+	dbstore.Cfg.Screenshots.Retention = time.Hour
+
+	image := models.Image{Path: "example.png"}
+	require.NoError(t, dbstore.SaveImage(ctx, &image))
+	assert.Equal(t, image.ExpiresAt, image.CreatedAt.Add(time.Hour))
+}
+
 func TestIntegrationGetImages(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type NotificationLogStore interface {
+	// SaveNotificationDelivery saves the delivery attempt of a notification.
+	SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+
+	// GetNotificationDeliveries returns the delivery attempts that match the query, the most recent first.
+	GetNotificationDeliveries(ctx context.Context, query *models.NotificationDeliveryQuery) ([]*models.NotificationDelivery, error)
+
+	// DeleteNotificationDeliveriesBefore deletes the delivery attempts sent before the time.
+	// It returns the number of deleted attempts or an error.
+	DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (st DBstore) SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(delivery); err != nil {
+			return fmt.Errorf("failed to insert notification delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetNotificationDeliveries(ctx context.Context, query *models.NotificationDeliveryQuery) ([]*models.NotificationDelivery, error) {
+	deliveries := make([]*models.NotificationDelivery, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.IntegrationUID != "" {
+			q = q.And("integration_uid = ?", query.IntegrationUID)
+		}
+		if query.IntegrationType != "" {
+			q = q.And("integration_type = ?", query.IntegrationType)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		if !query.From.IsZero() {
+			q = q.And("sent_at >= ?", query.From.UTC())
+		}
+		if !query.To.IsZero() {
+			q = q.And("sent_at <= ?", query.To.UTC())
+		}
+		q = q.Desc("sent_at", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&deliveries)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (st DBstore) DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	if err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("sent_at < ?", before.UTC()).Delete(&models.NotificationDelivery{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification deliveries: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
@@ -0,0 +1,75 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	deliveries := []*models.NotificationDelivery{
+		{OrgID: 1, Receiver: "ops", IntegrationUID: "slack", IntegrationType: "slack", Status: models.NotificationDeliveryFailure, Error: "503 Service Unavailable", WillRetry: true, Alerts: 2, LatencyMs: 120, SentAt: now.Add(-3 * time.Hour)},
+		{OrgID: 1, Receiver: "ops", IntegrationUID: "slack", IntegrationType: "slack", Status: models.NotificationDeliverySuccess, Retry: 1, Alerts: 2, LatencyMs: 80, SentAt: now.Add(-2 * time.Hour)},
+		{OrgID: 1, Receiver: "dev", IntegrationUID: "email", IntegrationType: "email", Status: models.NotificationDeliverySuccess, Alerts: 1, LatencyMs: 10, SentAt: now.Add(-time.Hour)},
+		{OrgID: 2, Receiver: "ops", IntegrationUID: "slack", IntegrationType: "slack", Status: models.NotificationDeliverySuccess, Alerts: 1, LatencyMs: 90, SentAt: now},
+	}
+	for _, d := range deliveries {
+		require.NoError(t, dbstore.SaveNotificationDelivery(ctx, d))
+		require.NotZero(t, d.ID)
+	}
+
+	t.Run("returns the deliveries of the organization, the most recent first", func(t *testing.T) {
+		result, err := dbstore.GetNotificationDeliveries(ctx, &models.NotificationDeliveryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		assert.Equal(t, deliveries[2].ID, result[0].ID)
+		assert.Equal(t, deliveries[1].ID, result[1].ID)
+		assert.Equal(t, deliveries[0].ID, result[2].ID)
+		assert.Equal(t, "503 Service Unavailable", result[2].Error)
+		assert.True(t, result[2].WillRetry)
+		assert.Equal(t, now.Add(-3*time.Hour), result[2].SentAt.UTC())
+	})
+
+	t.Run("filters the deliveries", func(t *testing.T) {
+		result, err := dbstore.GetNotificationDeliveries(ctx, &models.NotificationDeliveryQuery{OrgID: 1, Receiver: "ops", Status: models.NotificationDeliverySuccess})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, deliveries[1].ID, result[0].ID)
+
+		result, err = dbstore.GetNotificationDeliveries(ctx, &models.NotificationDeliveryQuery{OrgID: 1, IntegrationType: "slack", From: now.Add(-150 * time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, deliveries[1].ID, result[0].ID)
+
+		result, err = dbstore.GetNotificationDeliveries(ctx, &models.NotificationDeliveryQuery{OrgID: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, deliveries[2].ID, result[0].ID)
+	})
+
+	t.Run("deletes the deliveries before the time", func(t *testing.T) {
+		n, err := dbstore.DeleteNotificationDeliveriesBefore(ctx, now.Add(-90*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		result, err := dbstore.GetNotificationDeliveries(ctx, &models.NotificationDeliveryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, deliveries[2].ID, result[0].ID)
+	})
+}
@@ -0,0 +1,149 @@
+package notifications
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DeliveryStatusSent   = "sent"
+	DeliveryStatusFailed = "failed"
+)
+
+// Delivery is the outcome of sending an email, kept in the delivery log.
+type Delivery struct {
+	ID       int64     `json:"id"`
+	Time     time.Time `json:"time"`
+	Provider string    `json:"provider"`
+	To       []string  `json:"to"`
+	Subject  string    `json:"subject"`
+	Status   string    `json:"status"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// DeliveryQuery filters the delivery log, the most recent deliveries are returned first.
+type DeliveryQuery struct {
+	Status string
+	// To matches the deliveries sent to a recipient containing it
+	To    string
+	Limit int
+}
+
+// permanentError marks the errors sending an email again does not fix, such as an invalid address.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+func isPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// deliveryLog keeps the last deliveries in memory, the oldest ones are dropped when it is full.
+type deliveryLog struct {
+	mu      sync.Mutex
+	size    int
+	nextID  int64
+	entries []Delivery
+}
+
+func newDeliveryLog(size int) *deliveryLog {
+	return &deliveryLog{size: size}
+}
+
+func (l *deliveryLog) add(d Delivery) {
+	if l.size <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextID++
+	d.ID = l.nextID
+	if len(l.entries) >= l.size {
+		l.entries = append(l.entries[:0], l.entries[len(l.entries)-l.size+1:]...)
+	}
+	l.entries = append(l.entries, d)
+}
+
+func (l *deliveryLog) search(query DeliveryQuery) []Delivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]Delivery, 0)
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(result) >= query.Limit {
+			break
+		}
+		d := l.entries[i]
+		if query.Status != "" && d.Status != query.Status {
+			continue
+		}
+		if query.To != "" && !containsRecipient(d.To, query.To) {
+			continue
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func containsRecipient(to []string, search string) bool {
+	search = strings.ToLower(search)
+	for _, address := range to {
+		if strings.Contains(strings.ToLower(address), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendWithRetries sends a message, sending it again with an exponential backoff when the mailer fails,
+// and records the outcome in the delivery log.
+func (ns *NotificationService) sendWithRetries(msg *Message) error {
+	backoff := ns.Cfg.Smtp.RetryBackoff
+	attempts := 0
+
+	var err error
+	for {
+		attempts++
+		_, err = ns.mailer.Send(msg)
+		if err == nil || isPermanent(err) || attempts > ns.Cfg.Smtp.MaxRetries {
+			break
+		}
+		ns.log.Warn("Failed to send email, retrying", "attempt", attempts, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	d := Delivery{
+		Time:     time.Now(),
+		Provider: ns.Cfg.Smtp.Provider,
+		To:       msg.To,
+		Subject:  msg.Subject,
+		Status:   DeliveryStatusSent,
+		Attempts: attempts,
+	}
+	if err != nil {
+		d.Status = DeliveryStatusFailed
+		d.Error = err.Error()
+	}
+	ns.deliveries.add(d)
+	return err
+}
+
+// GetDeliveries returns the deliveries of the delivery log matching the query.
+func (ns *NotificationService) GetDeliveries(query DeliveryQuery) []Delivery {
+	return ns.deliveries.search(query)
+}
@@ -0,0 +1,87 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+type failingMailer struct {
+	err   error
+	calls int
+}
+
+func (m *failingMailer) Send(messages ...*Message) (int, error) {
+	m.calls++
+	return 0, m.err
+}
+
+func TestDeliveryLog(t *testing.T) {
+	log := newDeliveryLog(2)
+	log.add(Delivery{To: []string{"first@example.com"}, Status: DeliveryStatusSent})
+	log.add(Delivery{To: []string{"second@example.com"}, Status: DeliveryStatusFailed})
+	log.add(Delivery{To: []string{"third@example.com"}, Status: DeliveryStatusSent})
+
+	deliveries := log.search(DeliveryQuery{})
+	require.Len(t, deliveries, 2)
+	require.Equal(t, int64(3), deliveries[0].ID)
+	require.Equal(t, int64(2), deliveries[1].ID)
+
+	require.Len(t, log.search(DeliveryQuery{Status: DeliveryStatusFailed}), 1)
+	require.Len(t, log.search(DeliveryQuery{To: "THIRD"}), 1)
+	require.Len(t, log.search(DeliveryQuery{Limit: 1}), 1)
+	require.Empty(t, log.search(DeliveryQuery{To: "first"}))
+}
+
+func TestSendWithRetries(t *testing.T) {
+	bus := newBus(t)
+	cfg := createSmtpConfig()
+	cfg.Smtp.Provider = "smtp"
+	cfg.Smtp.MaxRetries = 2
+	cfg.Smtp.DeliveryLogSize = 10
+
+	t.Run("retries the temporary errors", func(t *testing.T) {
+		mailer := &failingMailer{err: errors.New("connection refused")}
+		ns, err := ProvideService(bus, cfg, mailer, nil, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+
+		_, err = ns.Send(&Message{To: []string{"to@example.com"}, Subject: "subject"})
+		require.Error(t, err)
+		require.Equal(t, 3, mailer.calls)
+
+		deliveries := ns.GetDeliveries(DeliveryQuery{})
+		require.Len(t, deliveries, 1)
+		require.Equal(t, DeliveryStatusFailed, deliveries[0].Status)
+		require.Equal(t, 3, deliveries[0].Attempts)
+		require.Equal(t, "smtp", deliveries[0].Provider)
+		require.Equal(t, "connection refused", deliveries[0].Error)
+	})
+
+	t.Run("does not retry the permanent errors", func(t *testing.T) {
+		mailer := &failingMailer{err: permanentError{errors.New("invalid address")}}
+		ns, err := ProvideService(bus, cfg, mailer, nil, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+
+		_, err = ns.Send(&Message{To: []string{"to@example.com"}})
+		require.Error(t, err)
+		require.Equal(t, 1, mailer.calls)
+	})
+
+	t.Run("records a delivery for each recipient", func(t *testing.T) {
+		ns, mailer, err := createSutWithConfig(t, bus, cfg)
+		require.NoError(t, err)
+
+		sent, err := ns.Send(&Message{To: []string{"a@example.com", "b@example.com"}})
+		require.NoError(t, err)
+		require.Equal(t, 2, sent)
+		require.Len(t, mailer.Sent, 2)
+
+		deliveries := ns.GetDeliveries(DeliveryQuery{Status: DeliveryStatusSent})
+		require.Len(t, deliveries, 2)
+		require.Equal(t, []string{"b@example.com"}, deliveries[0].To)
+		require.Equal(t, 1, deliveries[0].Attempts)
+	})
+}
@@ -50,7 +50,17 @@ func (ns *NotificationService) Send(msg *Message) (int, error) {
 		}
 	}
 
-	return ns.mailer.Send(messages...)
+	// the messages are sent one by one, so that each one is retried and has its own entry in the delivery log
+	sent := 0
+	var err error
+	for _, m := range messages {
+		if sendErr := ns.sendWithRetries(m); sendErr != nil {
+			err = sendErr
+			continue
+		}
+		sent++
+	}
+	return sent, err
 }
 
 func (ns *NotificationService) buildEmailMessage(cmd *SendEmailCommand) (*Message, error) {
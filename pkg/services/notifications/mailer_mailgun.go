@@ -0,0 +1,106 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// MailgunMailer sends the emails with the Mailgun messages API.
+type MailgunMailer struct {
+	cfg    setting.SmtpSettings
+	client *http.Client
+}
+
+func NewMailgunMailer(cfg setting.SmtpSettings) (*MailgunMailer, error) {
+	if cfg.Mailgun.Domain == "" || cfg.Mailgun.APIKey == "" {
+		return nil, fmt.Errorf("the Mailgun domain and API key are not configured")
+	}
+	return &MailgunMailer{cfg: cfg, client: newAPIClient(cfg)}, nil
+}
+
+func (m *MailgunMailer) Send(messages ...*Message) (int, error) {
+	return sendEach(messages, func(msg *Message) error {
+		body, contentType, err := m.buildMessage(msg)
+		if err != nil {
+			return err
+		}
+
+		url := fmt.Sprintf("%s/v3/%s/messages", strings.TrimSuffix(m.cfg.Mailgun.URL, "/"), m.cfg.Mailgun.Domain)
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth("api", m.cfg.Mailgun.APIKey)
+		req.Header.Set("Content-Type", contentType)
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		return checkAPIResponse("Mailgun", resp)
+	})
+}
+
+func (m *MailgunMailer) buildMessage(msg *Message) (*bytes.Buffer, string, error) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	fields := [][2]string{{"from", msg.From}, {"subject", msg.Subject}}
+	for _, to := range msg.To {
+		fields = append(fields, [2]string{"to", to})
+	}
+	if len(msg.ReplyTo) > 0 {
+		fields = append(fields, [2]string{"h:Reply-To", strings.Join(msg.ReplyTo, ", ")})
+	}
+	for _, contentType := range m.cfg.ContentTypes {
+		switch contentType {
+		case "text/html":
+			fields = append(fields, [2]string{"html", msg.Body[contentType]})
+		case "text/plain":
+			fields = append(fields, [2]string{"text", msg.Body[contentType]})
+		}
+	}
+	for _, field := range fields {
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, file := range msg.AttachedFiles {
+		part, err := w.CreateFormFile("attachment", file.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(file.Content); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, path := range msg.EmbeddedFiles {
+		// nolint:gosec
+		// the embedded files are images shipped with Grafana or generated by the renderer
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		part, err := w.CreateFormFile("inline", filepath.Base(path))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, w.FormDataContentType(), nil
+}
@@ -0,0 +1,127 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// SendGridMailer sends the emails with the SendGrid v3 mail send API.
+type SendGridMailer struct {
+	cfg    setting.SmtpSettings
+	client *http.Client
+}
+
+func NewSendGridMailer(cfg setting.SmtpSettings) (*SendGridMailer, error) {
+	if cfg.SendGrid.APIKey == "" {
+		return nil, fmt.Errorf("the SendGrid API key is not configured")
+	}
+	return &SendGridMailer{cfg: cfg, client: newAPIClient(cfg)}, nil
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition,omitempty"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridMessage struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	ReplyToList      []sendGridAddress         `json:"reply_to_list,omitempty"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Attachments      []sendGridAttachment      `json:"attachments,omitempty"`
+}
+
+func (m *SendGridMailer) Send(messages ...*Message) (int, error) {
+	return sendEach(messages, func(msg *Message) error {
+		body, err := m.buildMessage(msg)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(m.cfg.SendGrid.URL, "/")+"/v3/mail/send", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+m.cfg.SendGrid.APIKey)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		return checkAPIResponse("SendGrid", resp)
+	})
+}
+
+func (m *SendGridMailer) buildMessage(msg *Message) ([]byte, error) {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return nil, permanentError{err}
+	}
+
+	sg := sendGridMessage{
+		Personalizations: make([]sendGridPersonalization, 1),
+		From:             sendGridAddress{Email: from.Address, Name: from.Name},
+		Subject:          msg.Subject,
+	}
+	for _, to := range msg.To {
+		sg.Personalizations[0].To = append(sg.Personalizations[0].To, sendGridAddress{Email: to})
+	}
+	for _, replyTo := range msg.ReplyTo {
+		sg.ReplyToList = append(sg.ReplyToList, sendGridAddress{Email: replyTo})
+	}
+	// SendGrid expects the plain text content first
+	for i := len(m.cfg.ContentTypes) - 1; i >= 0; i-- {
+		sg.Content = append(sg.Content, sendGridContent{Type: m.cfg.ContentTypes[i], Value: msg.Body[m.cfg.ContentTypes[i]]})
+	}
+	for _, file := range msg.AttachedFiles {
+		sg.Attachments = append(sg.Attachments, sendGridAttachment{
+			Content:  base64.StdEncoding.EncodeToString(file.Content),
+			Filename: file.Name,
+		})
+	}
+	for _, path := range msg.EmbeddedFiles {
+		// nolint:gosec
+		// the embedded files are images shipped with Grafana or generated by the renderer
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		sg.Attachments = append(sg.Attachments, sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(content),
+			Filename:    name,
+			Disposition: "inline",
+			ContentID:   name,
+		})
+	}
+
+	return json.Marshal(sg)
+}
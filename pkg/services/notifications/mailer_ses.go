@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ses"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type sesRawEmailSender interface {
+	SendRawEmail(input *ses.SendRawEmailInput) (*ses.SendRawEmailOutput, error)
+}
+
+// SESMailer sends the emails with the Amazon SES API. The credentials are read from the default AWS credential chain.
+type SESMailer struct {
+	cfg    setting.SmtpSettings
+	client sesRawEmailSender
+}
+
+func NewSESMailer(cfg setting.SmtpSettings) (*SESMailer, error) {
+	awsCfg := aws.NewConfig().WithHTTPClient(newAPIClient(cfg))
+	if cfg.SES.Region != "" {
+		awsCfg = awsCfg.WithRegion(cfg.SES.Region)
+	}
+	if cfg.SES.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.SES.Endpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{Config: *awsCfg, SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SESMailer{cfg: cfg, client: ses.New(sess)}, nil
+}
+
+func (m *SESMailer) Send(messages ...*Message) (int, error) {
+	return sendEach(messages, func(msg *Message) error {
+		// the raw message is the same as the one sent to an SMTP server, so the attachments and the
+		// embedded files are supported
+		var raw bytes.Buffer
+		if _, err := buildEmail(m.cfg.ContentTypes, msg).WriteTo(&raw); err != nil {
+			return err
+		}
+
+		_, err := m.client.SendRawEmail(&ses.SendRawEmailInput{RawMessage: &ses.RawMessage{Data: raw.Bytes()}})
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == ses.ErrCodeMessageRejected {
+			return permanentError{err}
+		}
+		return err
+	})
+}
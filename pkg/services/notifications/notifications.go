@@ -6,15 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"net/http"
 	"net/url"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -38,7 +42,7 @@ var tmplResetPassword = "reset_password"
 var tmplSignUpStarted = "signup_started"
 var tmplWelcomeOnSignUp = "welcome_on_signup"
 
-func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUserStore) (*NotificationService, error) {
+func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUserStore, tracer tracing.Tracer) (*NotificationService, error) {
 	ns := &NotificationService{
 		Bus:          bus,
 		Cfg:          cfg,
@@ -47,6 +51,11 @@ func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUser
 		webhookQueue: make(chan *Webhook, 10),
 		mailer:       mailer,
 		store:        store,
+		deliveries:   newDeliveryLog(cfg.Smtp.DeliveryLogSize),
+		webhookClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: httpclientprovider.OutboundRoundTripper(cfg, tracer, "notifications", netTransport),
+		},
 	}
 
 	ns.Bus.AddEventListener(ns.signUpStartedHandler)
@@ -97,8 +106,11 @@ type NotificationService struct {
 	mailQueue    chan *Message
 	webhookQueue chan *Webhook
 	mailer       Mailer
+	deliveries   *deliveryLog
 	log          log.Logger
 	store        TempUserStore
+	// webhookClient sends the webhooks of the alert notifiers, netClient is used if it is nil
+	webhookClient WebhookClient
 }
 
 func (ns *NotificationService) Run(ctx context.Context) error {
@@ -283,7 +283,7 @@ func createSut(t *testing.T, bus bus.Bus) (*NotificationService, *FakeMailer) {
 
 func createSutWithConfig(t *testing.T, bus bus.Bus, cfg *setting.Cfg) (*NotificationService, *FakeMailer, error) {
 	smtp := NewFakeMailer()
-	ns, err := ProvideService(bus, cfg, smtp, nil)
+	ns, err := ProvideService(bus, cfg, smtp, nil, tracing.InitializeTracerForTest())
 	return ns, smtp, err
 }
 
@@ -292,7 +292,7 @@ func createDisconnectedSut(t *testing.T, bus bus.Bus) *NotificationService {
 
 	cfg := createSmtpConfig()
 	smtp := NewFakeDisconnectedMailer()
-	ns, err := ProvideService(bus, cfg, smtp, nil)
+	ns, err := ProvideService(bus, cfg, smtp, nil, tracing.InitializeTracerForTest())
 	require.NoError(t, err)
 	return ns
 }
@@ -0,0 +1,98 @@
+package notifications
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ProvideSmtpService returns the mailer sending the emails with the configured provider,
+// either an SMTP server or the API of SES, SendGrid or Mailgun.
+func ProvideSmtpService(cfg *setting.Cfg) (Mailer, error) {
+	return &providerMailer{cfg: cfg}, nil
+}
+
+// providerMailer sends the emails with the provider of the current settings, the provider
+// is created again when the settings change, so that the settings can be reloaded.
+type providerMailer struct {
+	cfg *setting.Cfg
+
+	mu       sync.Mutex
+	settings setting.SmtpSettings
+	mailer   Mailer
+}
+
+func (p *providerMailer) Send(messages ...*Message) (int, error) {
+	mailer, err := p.current()
+	if err != nil {
+		return 0, err
+	}
+	return mailer.Send(messages...)
+}
+
+func (p *providerMailer) current() (Mailer, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.mailer != nil && reflect.DeepEqual(p.settings, p.cfg.Smtp) {
+		return p.mailer, nil
+	}
+
+	mailer, err := newProviderMailer(p.cfg.Smtp)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := p.mailer.(io.Closer); ok {
+		_ = closer.Close()
+	}
+	p.settings = p.cfg.Smtp
+	p.mailer = mailer
+	return mailer, nil
+}
+
+func newProviderMailer(cfg setting.SmtpSettings) (Mailer, error) {
+	switch cfg.Provider {
+	case setting.EmailProviderSES:
+		return NewSESMailer(cfg)
+	case setting.EmailProviderSendGrid:
+		return NewSendGridMailer(cfg)
+	case setting.EmailProviderMailgun:
+		return NewMailgunMailer(cfg)
+	case setting.EmailProviderSMTP, "":
+		return NewSmtpClient(cfg)
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", cfg.Provider)
+	}
+}
+
+// newAPIClient returns the HTTP client of the email service APIs, keeping the connections open between emails.
+func newAPIClient(cfg setting.SmtpSettings) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.MaxIdleConnections
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnections
+	transport.IdleConnTimeout = cfg.IdleTimeout
+	if cfg.MaxIdleConnections <= 0 {
+		transport.DisableKeepAlives = true
+	}
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}
+}
+
+// checkAPIResponse returns an error when the email service did not accept the email,
+// the errors of the client are permanent except when the rate limit is reached.
+func checkAPIResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err := fmt.Errorf("%s responded with status %d: %s", provider, resp.StatusCode, body)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return permanentError{err}
+	}
+	return err
+}
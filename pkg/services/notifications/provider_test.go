@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func testMessage() *Message {
+	return &Message{
+		To:      []string{"to@example.com"},
+		From:    "Grafana <from@example.com>",
+		Subject: "Some subject",
+		Body: map[string]string{
+			"text/html":  "Some HTML body",
+			"text/plain": "Some plain text body",
+		},
+		ReplyTo:       []string{"reply@example.com"},
+		AttachedFiles: []*AttachedFile{{Name: "report.csv", Content: []byte("a,b")}},
+	}
+}
+
+func TestSendGridMailer(t *testing.T) {
+	var received sendGridMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/mail/send", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := setting.SmtpSettings{
+		ContentTypes: []string{"text/html", "text/plain"},
+		SendGrid:     setting.SendGridSettings{APIKey: "key", URL: server.URL},
+	}
+	mailer, err := NewSendGridMailer(cfg)
+	require.NoError(t, err)
+
+	sent, err := mailer.Send(testMessage())
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+
+	require.Equal(t, sendGridAddress{Email: "from@example.com", Name: "Grafana"}, received.From)
+	require.Equal(t, []sendGridAddress{{Email: "to@example.com"}}, received.Personalizations[0].To)
+	require.Equal(t, []sendGridContent{
+		{Type: "text/plain", Value: "Some plain text body"},
+		{Type: "text/html", Value: "Some HTML body"},
+	}, received.Content)
+	require.Len(t, received.Attachments, 1)
+	require.Equal(t, "report.csv", received.Attachments[0].Filename)
+}
+
+func TestMailgunMailer(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3/mg.example.com/messages", r.URL.Path)
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "api", user)
+		assert.Equal(t, "key", password)
+		assert.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "to@example.com", r.FormValue("to"))
+		assert.Equal(t, "Some HTML body", r.FormValue("html"))
+		assert.Equal(t, "reply@example.com", r.FormValue("h:Reply-To"))
+
+		file, _, err := r.FormFile("attachment")
+		if assert.NoError(t, err) {
+			content, _ := io.ReadAll(file)
+			assert.Equal(t, "a,b", string(content))
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := setting.SmtpSettings{
+		ContentTypes: []string{"text/html", "text/plain"},
+		Mailgun:      setting.MailgunSettings{Domain: "mg.example.com", APIKey: "key", URL: server.URL},
+	}
+	mailer, err := NewMailgunMailer(cfg)
+	require.NoError(t, err)
+
+	sent, err := mailer.Send(testMessage())
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+
+	t.Run("client errors are permanent", func(t *testing.T) {
+		status = http.StatusBadRequest
+		_, err := mailer.Send(testMessage())
+		require.Error(t, err)
+		require.True(t, isPermanent(err))
+	})
+
+	t.Run("rate limit errors are retried", func(t *testing.T) {
+		status = http.StatusTooManyRequests
+		_, err := mailer.Send(testMessage())
+		require.Error(t, err)
+		require.False(t, isPermanent(err))
+	})
+}
+
+func TestProvideSmtpService_ProviderChange(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.Smtp.Provider = setting.EmailProviderMailgun
+
+	mailer, err := ProvideSmtpService(cfg)
+	require.NoError(t, err)
+
+	_, err = mailer.Send(testMessage())
+	require.ErrorContains(t, err, "Mailgun domain and API key are not configured")
+
+	cfg.Smtp.Provider = setting.EmailProviderSMTP
+	cfg.Smtp.Host = "invalid%hostname:123:456"
+	_, err = mailer.Send(testMessage())
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "Mailgun")
+}
@@ -7,6 +7,8 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	gomail "gopkg.in/mail.v2"
 
@@ -15,10 +17,15 @@ import (
 
 type SmtpClient struct {
 	cfg setting.SmtpSettings
+
+	// idle are the connections kept open between emails, the most recently used last
+	mu   sync.Mutex
+	idle []idleConnection
 }
 
-func ProvideSmtpService(cfg *setting.Cfg) (Mailer, error) {
-	return NewSmtpClient(cfg.Smtp)
+type idleConnection struct {
+	sender gomail.SendCloser
+	since  time.Time
 }
 
 func NewSmtpClient(cfg setting.SmtpSettings) (*SmtpClient, error) {
@@ -30,22 +37,26 @@ func NewSmtpClient(cfg setting.SmtpSettings) (*SmtpClient, error) {
 }
 
 func (sc *SmtpClient) Send(messages ...*Message) (int, error) {
-	sentEmailsCount := 0
 	dialer, err := sc.createDialer()
 	if err != nil {
-		return sentEmailsCount, err
+		return 0, err
 	}
 
-	for _, msg := range messages {
-		m := sc.buildEmail(msg)
+	return sendEach(messages, func(msg *Message) error {
+		return sc.send(dialer, sc.buildEmail(msg))
+	})
+}
+
+// sendEach sends the messages one by one and counts the sent and failed emails.
+func sendEach(messages []*Message, send func(msg *Message) error) (int, error) {
+	sentEmailsCount := 0
+	var err error
 
-		innerError := dialer.DialAndSend(m)
+	for _, msg := range messages {
+		innerError := send(msg)
 		emailsSentTotal.Inc()
 		if innerError != nil {
-			// As gomail does not returned typed errors we have to parse the error
-			// to catch invalid error when the address is invalid.
-			// https://github.com/go-gomail/gomail/blob/81ebce5c23dfd25c6c67194b37d3dd3f338c98b1/send.go#L113
-			if !strings.HasPrefix(innerError.Error(), "gomail: invalid address") {
+			if !isPermanent(innerError) {
 				emailsSentFailed.Inc()
 			}
 
@@ -59,23 +70,109 @@ func (sc *SmtpClient) Send(messages ...*Message) (int, error) {
 	return sentEmailsCount, err
 }
 
+func (sc *SmtpClient) send(dialer *gomail.Dialer, m *gomail.Message) error {
+	if sc.cfg.MaxIdleConnections <= 0 {
+		return wrapSmtpError(dialer.DialAndSend(m))
+	}
+
+	if conn := sc.takeIdle(); conn != nil {
+		err := gomail.Send(conn, m)
+		if err == nil {
+			sc.putIdle(conn)
+			return nil
+		}
+		_ = conn.Close()
+		if isInvalidAddress(err) {
+			return permanentError{err}
+		}
+		// the server may have closed the idle connection, so the email is sent again with a new one
+	}
+
+	conn, err := dialer.Dial()
+	if err != nil {
+		return err
+	}
+	if err := gomail.Send(conn, m); err != nil {
+		_ = conn.Close()
+		return wrapSmtpError(err)
+	}
+	sc.putIdle(conn)
+	return nil
+}
+
+func (sc *SmtpClient) takeIdle() gomail.SendCloser {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for len(sc.idle) > 0 {
+		conn := sc.idle[len(sc.idle)-1]
+		sc.idle = sc.idle[:len(sc.idle)-1]
+		if time.Since(conn.since) < sc.cfg.IdleTimeout {
+			return conn.sender
+		}
+		_ = conn.sender.Close()
+	}
+	return nil
+}
+
+func (sc *SmtpClient) putIdle(conn gomail.SendCloser) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if len(sc.idle) >= sc.cfg.MaxIdleConnections {
+		_ = conn.Close()
+		return
+	}
+	sc.idle = append(sc.idle, idleConnection{sender: conn, since: time.Now()})
+}
+
+// Close closes the idle connections.
+func (sc *SmtpClient) Close() error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	for _, conn := range sc.idle {
+		_ = conn.sender.Close()
+	}
+	sc.idle = nil
+	return nil
+}
+
+// As gomail does not returned typed errors we have to parse the error
+// to catch invalid error when the address is invalid.
+// https://github.com/go-gomail/gomail/blob/81ebce5c23dfd25c6c67194b37d3dd3f338c98b1/send.go#L113
+func isInvalidAddress(err error) bool {
+	return strings.HasPrefix(err.Error(), "gomail: invalid address")
+}
+
+func wrapSmtpError(err error) error {
+	if err != nil && isInvalidAddress(err) {
+		return permanentError{err}
+	}
+	return err
+}
+
 // buildEmail converts the Message DTO to a gomail message.
 func (sc *SmtpClient) buildEmail(msg *Message) *gomail.Message {
+	return buildEmail(sc.cfg.ContentTypes, msg)
+}
+
+func buildEmail(contentTypes []string, msg *Message) *gomail.Message {
 	m := gomail.NewMessage()
 	m.SetHeader("From", msg.From)
 	m.SetHeader("To", msg.To...)
 	m.SetHeader("Subject", msg.Subject)
-	sc.setFiles(m, msg)
+	setFiles(m, msg)
 	for _, replyTo := range msg.ReplyTo {
 		m.SetAddressHeader("Reply-To", replyTo, "")
 	}
 	// loop over content types from settings in reverse order as they are ordered in according to descending
 	// preference while the alternatives should be ordered according to ascending preference
-	for i := len(sc.cfg.ContentTypes) - 1; i >= 0; i-- {
-		if i == len(sc.cfg.ContentTypes)-1 {
-			m.SetBody(sc.cfg.ContentTypes[i], msg.Body[sc.cfg.ContentTypes[i]])
+	for i := len(contentTypes) - 1; i >= 0; i-- {
+		if i == len(contentTypes)-1 {
+			m.SetBody(contentTypes[i], msg.Body[contentTypes[i]])
 		} else {
-			m.AddAlternative(sc.cfg.ContentTypes[i], msg.Body[sc.cfg.ContentTypes[i]])
+			m.AddAlternative(contentTypes[i], msg.Body[contentTypes[i]])
 		}
 	}
 
@@ -83,7 +180,7 @@ func (sc *SmtpClient) buildEmail(msg *Message) *gomail.Message {
 }
 
 // setFiles attaches files in various forms.
-func (sc *SmtpClient) setFiles(
+func setFiles(
 	m *gomail.Message,
 	msg *Message,
 ) {
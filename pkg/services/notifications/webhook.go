@@ -78,7 +78,11 @@ func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *
 		request.Header.Set(k, v)
 	}
 
-	resp, err := netClient.Do(request)
+	client := ns.webhookClient
+	if client == nil {
+		client = netClient
+	}
+	resp, err := client.Do(request)
 	if err != nil {
 		return err
 	}
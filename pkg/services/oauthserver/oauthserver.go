@@ -0,0 +1,211 @@
+package oauthserver
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	ActionClientsRead  = "oauthclients:read"
+	ActionClientsWrite = "oauthclients:write"
+)
+
+const (
+	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeRefreshToken      = "refresh_token"
+)
+
+// The tokens are prefixed keys, like the service account tokens, the service IDs tell them apart.
+const (
+	ClientSecretServiceID = "oc"
+	AccessTokenServiceID  = "oa"
+	RefreshTokenServiceID = "or"
+)
+
+var (
+	ErrClientNotFound       = errutil.NewBase(errutil.StatusNotFound, "oauthserver.clientNotFound", errutil.WithPublicMessage("OAuth client not found"))
+	ErrInvalidClientConfig  = errutil.NewBase(errutil.StatusBadRequest, "oauthserver.invalidClientConfig", errutil.WithPublicMessage("Invalid OAuth client: a name, an existing service account and access control actions as scopes are required"))
+	ErrInvalidRequest       = errutil.NewBase(errutil.StatusBadRequest, "oauthserver.invalidRequest", errutil.WithPublicMessage("The request is missing a required parameter"))
+	ErrInvalidClient        = errutil.NewBase(errutil.StatusUnauthorized, "oauthserver.invalidClient", errutil.WithPublicMessage("Client authentication failed"))
+	ErrInvalidGrant         = errutil.NewBase(errutil.StatusBadRequest, "oauthserver.invalidGrant", errutil.WithPublicMessage("The refresh token is invalid, expired or revoked"))
+	ErrInvalidScope         = errutil.NewBase(errutil.StatusBadRequest, "oauthserver.invalidScope", errutil.WithPublicMessage("The requested scope exceeds the scope granted to the client"))
+	ErrUnsupportedGrantType = errutil.NewBase(errutil.StatusBadRequest, "oauthserver.unsupportedGrantType", errutil.WithPublicMessage("The grant type is not supported"))
+	ErrInvalidToken         = errutil.NewBase(errutil.StatusUnauthorized, "oauthserver.invalidToken", errutil.WithPublicMessage("Invalid access token"))
+)
+
+// Service is the OAuth2 authorization server of Grafana. It registers the clients of the external applications, each
+// one acting as a service account, and issues them short lived access tokens with the client credentials and refresh
+// token grants. The scopes of a client are access control actions, the tokens are restricted to the permissions of
+// the service account for these actions.
+type Service interface {
+	ListClients(ctx context.Context, orgID int64) ([]*Client, error)
+	GetClient(ctx context.Context, orgID int64, clientID string) (*Client, error)
+	// RegisterClient creates a client, its secret is only returned by this call.
+	RegisterClient(ctx context.Context, orgID int64, cmd *RegisterClientCommand) (*Client, error)
+	UpdateClient(ctx context.Context, orgID int64, clientID string, cmd *UpdateClientCommand) (*Client, error)
+	// DeleteClient deletes the client and revokes all of its tokens.
+	DeleteClient(ctx context.Context, orgID int64, clientID string) error
+	// ResetClientSecret replaces the secret of the client and revokes the tokens already issued to it.
+	ResetClientSecret(ctx context.Context, orgID int64, clientID string) (*Client, error)
+
+	// IssueToken authenticates the client and grants it a new access token and refresh token. The refresh tokens
+	// are rotated: a refresh token can only be used once, its reuse revokes all the tokens derived from the same
+	// client credentials grant.
+	IssueToken(ctx context.Context, req *TokenRequest) (*TokenResponse, error)
+	// RevokeToken revokes the access token or refresh token of the client, and all the tokens derived from the same
+	// client credentials grant. Unknown tokens are ignored.
+	RevokeToken(ctx context.Context, clientID, clientSecret, token string) error
+	// ValidateAccessToken returns the grant of a valid access token.
+	ValidateAccessToken(ctx context.Context, token string) (*AccessToken, error)
+}
+
+// IsAccessToken tells if the token looks like an access token issued by the OAuth server.
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, apikeygenprefix.GrafanaPrefix+AccessTokenServiceID+"_")
+}
+
+type Client struct {
+	ID       int64  `xorm:"pk autoincr 'id'" json:"-"`
+	OrgID    int64  `xorm:"org_id" json:"orgId"`
+	ClientID string `xorm:"client_id" json:"clientId"`
+	Name     string `xorm:"name" json:"name"`
+	// Secret is the hash of the client secret
+	Secret           string `xorm:"secret" json:"-"`
+	ServiceAccountID int64  `xorm:"service_account_id" json:"serviceAccountId"`
+	// Scopes are the access control actions the client can request
+	Scopes  Scopes    `xorm:"scopes" json:"scopes"`
+	Created time.Time `xorm:"created" json:"created"`
+	Updated time.Time `xorm:"updated" json:"updated"`
+
+	// ClientSecret is only set when the client is registered or its secret is reset
+	ClientSecret string `xorm:"-" json:"clientSecret,omitempty"`
+}
+
+func (c Client) TableName() string {
+	return "oauth_client"
+}
+
+// Token is a pair of access token and refresh token. The tokens issued by a client credentials grant, and by the
+// refresh token grants that follow it, share the same family.
+type Token struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	ClientID int64  `xorm:"client_id"`
+	FamilyID string `xorm:"family_id"`
+	// AccessToken and RefreshToken are the hashes of the tokens
+	AccessToken    string    `xorm:"access_token"`
+	RefreshToken   string    `xorm:"refresh_token"`
+	Scopes         Scopes    `xorm:"scopes"`
+	AccessExpires  time.Time `xorm:"access_expires"`
+	RefreshExpires time.Time `xorm:"refresh_expires"`
+	// Rotated is set once the refresh token has been exchanged for a new pair
+	Rotated bool      `xorm:"rotated"`
+	Revoked bool      `xorm:"revoked"`
+	Created time.Time `xorm:"created"`
+}
+
+func (t Token) TableName() string {
+	return "oauth_token"
+}
+
+// Scopes are stored as JSON.
+type Scopes []string
+
+func (s *Scopes) FromDB(data []byte) error {
+	*s = nil
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, s)
+}
+
+func (s *Scopes) ToDB() ([]byte, error) {
+	if s == nil || *s == nil {
+		return json.Marshal([]string{})
+	}
+	return json.Marshal(s)
+}
+
+// Contains tells if all the scopes are in s.
+func (s Scopes) Contains(scopes []string) bool {
+	for _, scope := range scopes {
+		found := false
+		for _, granted := range s {
+			if scope == granted {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the scopes as the scope parameter of OAuth2, separated by spaces.
+func (s Scopes) String() string {
+	return strings.Join(s, " ")
+}
+
+// ParseScopes parses the scope parameter of OAuth2.
+func ParseScopes(scope string) Scopes {
+	fields := strings.Fields(scope)
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+type RegisterClientCommand struct {
+	Name             string   `json:"name"`
+	ServiceAccountID int64    `json:"serviceAccountId"`
+	Scopes           []string `json:"scopes"`
+}
+
+type UpdateClientCommand struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// TokenRequest are the parameters of the token endpoint.
+type TokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	// Scope is the requested scope, the scope of the client, or of the refreshed token, when empty
+	Scope        string
+	RefreshToken string
+}
+
+// TokenResponse is the successful response of the token endpoint.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// AccessToken is the grant of a valid access token.
+type AccessToken struct {
+	OrgID            int64
+	ClientID         string
+	ServiceAccountID int64
+	Scopes           Scopes
+	Expires          time.Time
+}
+
+// PermissionBoundary returns the permissions the token is restricted to, all the scopes of the granted actions.
+func (t *AccessToken) PermissionBoundary() map[string][]string {
+	boundary := make(map[string][]string, len(t.Scopes))
+	for _, action := range t.Scopes {
+		boundary[action] = []string{""}
+	}
+	return boundary
+}
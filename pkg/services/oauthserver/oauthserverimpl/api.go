@@ -0,0 +1,172 @@
+package oauthserverimpl
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/util/errutil"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	// the clients authenticate themselves with their credentials, these endpoints don't need a signed in user
+	routeRegister.Post("/oauth2/token", routing.Wrap(s.handleToken))
+	routeRegister.Post("/oauth2/revoke", routing.Wrap(s.handleRevoke))
+
+	routeRegister.Group("/api/oauth/clients", func(clients routing.RouteRegister) {
+		clients.Get("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(oauthserver.ActionClientsRead)), routing.Wrap(s.handleListClients))
+		clients.Post("/", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(oauthserver.ActionClientsWrite)), routing.Wrap(s.handleRegisterClient))
+		clients.Get("/:clientId", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(oauthserver.ActionClientsRead)), routing.Wrap(s.handleGetClient))
+		clients.Put("/:clientId", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(oauthserver.ActionClientsWrite)), routing.Wrap(s.handleUpdateClient))
+		clients.Delete("/:clientId", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(oauthserver.ActionClientsWrite)), routing.Wrap(s.handleDeleteClient))
+		clients.Post("/:clientId/secret", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(oauthserver.ActionClientsWrite)), routing.Wrap(s.handleResetClientSecret))
+	}, middleware.ReqSignedIn)
+}
+
+func (s *Service) handleListClients(c *contextmodel.ReqContext) response.Response {
+	clients, err := s.ListClients(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list OAuth clients", err)
+	}
+	return response.JSON(http.StatusOK, clients)
+}
+
+func (s *Service) handleGetClient(c *contextmodel.ReqContext) response.Response {
+	client, err := s.GetClient(c.Req.Context(), c.OrgID, web.Params(c.Req)[":clientId"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get OAuth client", err)
+	}
+	return response.JSON(http.StatusOK, client)
+}
+
+func (s *Service) handleRegisterClient(c *contextmodel.ReqContext) response.Response {
+	cmd := oauthserver.RegisterClientCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	// the client acts as the service account, registering it is as sensitive as creating a token for it
+	if !s.accessControl.IsDisabled() {
+		scope := ac.Scope("serviceaccounts", "id", strconv.FormatInt(cmd.ServiceAccountID, 10))
+		allowed, err := s.accessControl.Evaluate(c.Req.Context(), c.SignedInUser, ac.EvalPermission(serviceaccounts.ActionWrite, scope))
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to check the permissions on the service account", err)
+		}
+		if !allowed {
+			return response.Error(http.StatusForbidden, "Not allowed to register an OAuth client for the service account", nil)
+		}
+	}
+
+	client, err := s.RegisterClient(c.Req.Context(), c.OrgID, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to register OAuth client", err)
+	}
+	return response.JSON(http.StatusCreated, client)
+}
+
+func (s *Service) handleUpdateClient(c *contextmodel.ReqContext) response.Response {
+	cmd := oauthserver.UpdateClientCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	client, err := s.UpdateClient(c.Req.Context(), c.OrgID, web.Params(c.Req)[":clientId"], &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update OAuth client", err)
+	}
+	return response.JSON(http.StatusOK, client)
+}
+
+func (s *Service) handleDeleteClient(c *contextmodel.ReqContext) response.Response {
+	if err := s.DeleteClient(c.Req.Context(), c.OrgID, web.Params(c.Req)[":clientId"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete OAuth client", err)
+	}
+	return response.Success("OAuth client deleted")
+}
+
+func (s *Service) handleResetClientSecret(c *contextmodel.ReqContext) response.Response {
+	client, err := s.ResetClientSecret(c.Req.Context(), c.OrgID, web.Params(c.Req)[":clientId"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset OAuth client secret", err)
+	}
+	return response.JSON(http.StatusOK, client)
+}
+
+// handleToken is the token endpoint of RFC 6749, the clients authenticate with the client_id and client_secret
+// parameters of the form.
+func (s *Service) handleToken(c *contextmodel.ReqContext) response.Response {
+	if err := c.Req.ParseForm(); err != nil {
+		return s.tokenError(oauthserver.ErrInvalidRequest.Errorf("failed to parse form: %w", err))
+	}
+
+	resp, err := s.IssueToken(c.Req.Context(), &oauthserver.TokenRequest{
+		GrantType:    c.Req.PostForm.Get("grant_type"),
+		ClientID:     c.Req.PostForm.Get("client_id"),
+		ClientSecret: c.Req.PostForm.Get("client_secret"),
+		Scope:        c.Req.PostForm.Get("scope"),
+		RefreshToken: c.Req.PostForm.Get("refresh_token"),
+	})
+	if err != nil {
+		return s.tokenError(err)
+	}
+	return noStore(response.JSON(http.StatusOK, resp))
+}
+
+// handleRevoke is the revocation endpoint of RFC 7009.
+func (s *Service) handleRevoke(c *contextmodel.ReqContext) response.Response {
+	if err := c.Req.ParseForm(); err != nil {
+		return s.tokenError(oauthserver.ErrInvalidRequest.Errorf("failed to parse form: %w", err))
+	}
+
+	token := c.Req.PostForm.Get("token")
+	if token == "" {
+		return s.tokenError(oauthserver.ErrInvalidRequest.Errorf("token is required"))
+	}
+	if err := s.RevokeToken(c.Req.Context(), c.Req.PostForm.Get("client_id"), c.Req.PostForm.Get("client_secret"), token); err != nil {
+		return s.tokenError(err)
+	}
+	return noStore(response.Respond(http.StatusOK, nil))
+}
+
+// tokenError renders an error of the token endpoint as described in section 5.2 of RFC 6749.
+func (s *Service) tokenError(err error) response.Response {
+	codes := []struct {
+		base errutil.Base
+		code string
+	}{
+		{oauthserver.ErrInvalidRequest, "invalid_request"},
+		{oauthserver.ErrInvalidClient, "invalid_client"},
+		{oauthserver.ErrInvalidGrant, "invalid_grant"},
+		{oauthserver.ErrInvalidScope, "invalid_scope"},
+		{oauthserver.ErrUnsupportedGrantType, "unsupported_grant_type"},
+	}
+
+	var grafanaErr errutil.Error
+	if errors.As(err, &grafanaErr) {
+		for _, c := range codes {
+			if errors.Is(err, c.base) {
+				public := grafanaErr.Public()
+				return noStore(response.JSON(public.StatusCode, map[string]string{
+					"error":             c.code,
+					"error_description": public.Message,
+				}))
+			}
+		}
+	}
+	s.log.Error("Failed to handle OAuth token request", "error", err)
+	return noStore(response.JSON(http.StatusInternalServerError, map[string]string{"error": "server_error"}))
+}
+
+func noStore(resp *response.NormalResponse) *response.NormalResponse {
+	return resp.SetHeader("Cache-Control", "no-store").SetHeader("Pragma", "no-cache")
+}
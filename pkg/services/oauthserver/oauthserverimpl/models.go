@@ -0,0 +1,29 @@
+package oauthserverimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+)
+
+var (
+	oauthClientsReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:oauthclients:reader",
+		DisplayName: "OAuth clients reader",
+		Description: "Read the OAuth clients of the external applications",
+		Group:       "Service accounts",
+		Permissions: []accesscontrol.Permission{
+			{Action: oauthserver.ActionClientsRead},
+		},
+	}
+
+	oauthClientsWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:oauthclients:writer",
+		DisplayName: "OAuth clients writer",
+		Description: "Register, update and delete the OAuth clients of the external applications, and reset their secrets",
+		Group:       "Service accounts",
+		Permissions: []accesscontrol.Permission{
+			{Action: oauthserver.ActionClientsRead},
+			{Action: oauthserver.ActionClientsWrite},
+		},
+	}
+)
@@ -0,0 +1,411 @@
+package oauthserverimpl
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const maxNameLength = 190
+
+type Service struct {
+	cfg             setting.OAuthServerSettings
+	store           store
+	accessControl   ac.AccessControl
+	serviceAccounts serviceaccounts.Service
+	log             log.Logger
+	now             func() time.Time
+}
+
+var _ oauthserver.Service = (*Service)(nil)
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	serviceAccounts serviceaccounts.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	s := &Service{
+		cfg:             cfg.OAuthServer,
+		store:           &sqlStore{db: sql, now: time.Now},
+		accessControl:   accessControl,
+		serviceAccounts: serviceAccounts,
+		log:             log.New("oauthserver"),
+		now:             time.Now,
+	}
+
+	if !s.cfg.Enabled {
+		return s, nil
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := accesscontrolService.DeclareFixedRoles(
+			ac.RoleRegistration{Role: oauthClientsReaderRole, Grants: []string{string(org.RoleAdmin)}},
+			ac.RoleRegistration{Role: oauthClientsWriterRole, Grants: []string{string(org.RoleAdmin)}},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) ListClients(ctx context.Context, orgID int64) ([]*oauthserver.Client, error) {
+	return s.store.ListClients(ctx, orgID)
+}
+
+func (s *Service) GetClient(ctx context.Context, orgID int64, clientID string) (*oauthserver.Client, error) {
+	return s.store.GetClient(ctx, orgID, clientID)
+}
+
+func (s *Service) RegisterClient(ctx context.Context, orgID int64, cmd *oauthserver.RegisterClientCommand) (*oauthserver.Client, error) {
+	name, scopes, err := validateClient(cmd.Name, cmd.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.serviceAccounts.RetrieveServiceAccount(ctx, orgID, cmd.ServiceAccountID); err != nil {
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			return nil, oauthserver.ErrInvalidClientConfig.Errorf("service account %d not found in org %d", cmd.ServiceAccountID, orgID)
+		}
+		return nil, err
+	}
+
+	secret, err := apikeygenprefix.New(oauthserver.ClientSecretServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &oauthserver.Client{
+		OrgID:            orgID,
+		ClientID:         util.GenerateShortUID(),
+		Name:             name,
+		Secret:           secret.HashedKey,
+		ServiceAccountID: cmd.ServiceAccountID,
+		Scopes:           scopes,
+	}
+	if err := s.store.InsertClient(ctx, client); err != nil {
+		return nil, err
+	}
+	client.ClientSecret = secret.ClientSecret
+	return client, nil
+}
+
+func (s *Service) UpdateClient(ctx context.Context, orgID int64, clientID string, cmd *oauthserver.UpdateClientCommand) (*oauthserver.Client, error) {
+	client, err := s.store.GetClient(ctx, orgID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	name, scopes, err := validateClient(cmd.Name, cmd.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	// the tokens already issued lose the scopes removed from the client, see ValidateAccessToken
+	client.Name = name
+	client.Scopes = scopes
+	if err := s.store.UpdateClient(ctx, client); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (s *Service) DeleteClient(ctx context.Context, orgID int64, clientID string) error {
+	client, err := s.store.GetClient(ctx, orgID, clientID)
+	if err != nil {
+		return err
+	}
+	return s.store.DeleteClient(ctx, client)
+}
+
+func (s *Service) ResetClientSecret(ctx context.Context, orgID int64, clientID string) (*oauthserver.Client, error) {
+	client, err := s.store.GetClient(ctx, orgID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := apikeygenprefix.New(oauthserver.ClientSecretServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the secret is reset when it leaked, the tokens issued with it are revoked as well
+	client.Secret = secret.HashedKey
+	if err := s.store.ResetClientSecret(ctx, client); err != nil {
+		return nil, err
+	}
+	client.ClientSecret = secret.ClientSecret
+	return client, nil
+}
+
+func (s *Service) IssueToken(ctx context.Context, req *oauthserver.TokenRequest) (*oauthserver.TokenResponse, error) {
+	switch req.GrantType {
+	case oauthserver.GrantTypeClientCredentials, oauthserver.GrantTypeRefreshToken:
+	case "":
+		return nil, oauthserver.ErrInvalidRequest.Errorf("grant_type is required")
+	default:
+		return nil, oauthserver.ErrUnsupportedGrantType.Errorf("unsupported grant type %q", req.GrantType)
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GrantType == oauthserver.GrantTypeClientCredentials {
+		scopes, err := requestedScopes(client.Scopes, req.Scope)
+		if err != nil {
+			return nil, err
+		}
+		token, resp, err := s.newToken(client, util.GenerateShortUID(), scopes)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.store.InsertToken(ctx, token); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	return s.refresh(ctx, client, req)
+}
+
+// refresh exchanges a refresh token for a new pair of tokens. The reuse of a refresh token means that it leaked, or
+// that the client lost track of its tokens, the whole family is revoked either way.
+func (s *Service) refresh(ctx context.Context, client *oauthserver.Client, req *oauthserver.TokenRequest) (*oauthserver.TokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, oauthserver.ErrInvalidRequest.Errorf("refresh_token is required")
+	}
+	old, err := s.getToken(ctx, req.RefreshToken, oauthserver.RefreshTokenServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if old == nil || old.ClientID != client.ID || old.Revoked {
+		return nil, oauthserver.ErrInvalidGrant.Errorf("refresh token not found or revoked")
+	}
+	if old.Rotated {
+		return nil, s.revokeReusedFamily(ctx, client, old)
+	}
+	if !s.now().Before(old.RefreshExpires) {
+		return nil, oauthserver.ErrInvalidGrant.Errorf("refresh token expired")
+	}
+
+	scopes, err := requestedScopes(intersect(old.Scopes, client.Scopes), req.Scope)
+	if err != nil {
+		return nil, err
+	}
+	token, resp, err := s.newToken(client, old.FamilyID, scopes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.RotateToken(ctx, old, token); err != nil {
+		if errors.Is(err, oauthserver.ErrInvalidGrant) {
+			// the refresh token was exchanged concurrently
+			return nil, s.revokeReusedFamily(ctx, client, old)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *Service) revokeReusedFamily(ctx context.Context, client *oauthserver.Client, token *oauthserver.Token) error {
+	s.log.Warn("Refresh token reused, revoking the tokens of its family", "client", client.ClientID, "orgId", client.OrgID, "family", token.FamilyID)
+	if err := s.store.RevokeFamily(ctx, client.ID, token.FamilyID); err != nil {
+		return err
+	}
+	return oauthserver.ErrInvalidGrant.Errorf("refresh token reused")
+}
+
+func (s *Service) RevokeToken(ctx context.Context, clientID, clientSecret, token string) error {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	serviceID := oauthserver.RefreshTokenServiceID
+	if oauthserver.IsAccessToken(token) {
+		serviceID = oauthserver.AccessTokenServiceID
+	}
+	found, err := s.getToken(ctx, token, serviceID)
+	if err != nil {
+		if errors.Is(err, oauthserver.ErrInvalidGrant) {
+			return nil
+		}
+		return err
+	}
+	if found == nil || found.ClientID != client.ID {
+		return nil
+	}
+	return s.store.RevokeFamily(ctx, client.ID, found.FamilyID)
+}
+
+func (s *Service) ValidateAccessToken(ctx context.Context, token string) (*oauthserver.AccessToken, error) {
+	if !s.cfg.Enabled {
+		return nil, oauthserver.ErrInvalidToken.Errorf("oauth server is disabled")
+	}
+
+	found, err := s.getToken(ctx, token, oauthserver.AccessTokenServiceID)
+	if err != nil {
+		if errors.Is(err, oauthserver.ErrInvalidGrant) {
+			return nil, oauthserver.ErrInvalidToken.Errorf("malformed access token")
+		}
+		return nil, err
+	}
+	if found == nil || found.Revoked {
+		return nil, oauthserver.ErrInvalidToken.Errorf("access token not found or revoked")
+	}
+	if !s.now().Before(found.AccessExpires) {
+		return nil, oauthserver.ErrInvalidToken.Errorf("access token expired")
+	}
+
+	client, err := s.store.GetClientByID(ctx, found.ClientID)
+	if err != nil {
+		if errors.Is(err, oauthserver.ErrClientNotFound) {
+			return nil, oauthserver.ErrInvalidToken.Errorf("client %d not found", found.ClientID)
+		}
+		return nil, err
+	}
+
+	return &oauthserver.AccessToken{
+		OrgID:            client.OrgID,
+		ClientID:         client.ClientID,
+		ServiceAccountID: client.ServiceAccountID,
+		Scopes:           intersect(found.Scopes, client.Scopes),
+		Expires:          found.AccessExpires,
+	}, nil
+}
+
+func (s *Service) authenticateClient(ctx context.Context, clientID, clientSecret string) (*oauthserver.Client, error) {
+	if clientID == "" || clientSecret == "" {
+		return nil, oauthserver.ErrInvalidClient.Errorf("missing client credentials")
+	}
+	client, err := s.store.GetClientByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, oauthserver.ErrClientNotFound) {
+			return nil, oauthserver.ErrInvalidClient.Errorf("client %s not found", clientID)
+		}
+		return nil, err
+	}
+	hash, err := hashToken(clientSecret, oauthserver.ClientSecretServiceID)
+	if err != nil || subtle.ConstantTimeCompare([]byte(hash), []byte(client.Secret)) != 1 {
+		return nil, oauthserver.ErrInvalidClient.Errorf("invalid secret for client %s", clientID)
+	}
+	return client, nil
+}
+
+// getToken returns the pair of tokens of an access token or a refresh token, nil when it doesn't exist.
+func (s *Service) getToken(ctx context.Context, token, serviceID string) (*oauthserver.Token, error) {
+	hash, err := hashToken(token, serviceID)
+	if err != nil {
+		return nil, oauthserver.ErrInvalidGrant.Errorf("malformed token: %w", err)
+	}
+	if serviceID == oauthserver.AccessTokenServiceID {
+		return s.store.GetTokenByAccessToken(ctx, hash)
+	}
+	return s.store.GetTokenByRefreshToken(ctx, hash)
+}
+
+func (s *Service) newToken(client *oauthserver.Client, familyID string, scopes oauthserver.Scopes) (*oauthserver.Token, *oauthserver.TokenResponse, error) {
+	access, err := apikeygenprefix.New(oauthserver.AccessTokenServiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refresh, err := apikeygenprefix.New(oauthserver.RefreshTokenServiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now()
+	token := &oauthserver.Token{
+		OrgID:          client.OrgID,
+		ClientID:       client.ID,
+		FamilyID:       familyID,
+		AccessToken:    access.HashedKey,
+		RefreshToken:   refresh.HashedKey,
+		Scopes:         scopes,
+		AccessExpires:  now.Add(s.cfg.AccessTokenLifetime),
+		RefreshExpires: now.Add(s.cfg.RefreshTokenLifetime),
+	}
+	resp := &oauthserver.TokenResponse{
+		AccessToken:  access.ClientSecret,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.cfg.AccessTokenLifetime.Seconds()),
+		RefreshToken: refresh.ClientSecret,
+		Scope:        scopes.String(),
+	}
+	return token, resp, nil
+}
+
+// hashToken returns the hash of a prefixed token, it fails when the token isn't of the expected kind.
+func hashToken(token, serviceID string) (string, error) {
+	decoded, err := apikeygenprefix.Decode(token)
+	if err != nil {
+		return "", err
+	}
+	if decoded.ServiceID != serviceID {
+		return "", errors.New("unexpected token kind")
+	}
+	return decoded.Hash()
+}
+
+// requestedScopes returns the scopes requested from the granted ones, all of them when none are requested.
+func requestedScopes(granted oauthserver.Scopes, scope string) (oauthserver.Scopes, error) {
+	requested := oauthserver.ParseScopes(scope)
+	if len(requested) == 0 {
+		return granted, nil
+	}
+	if !granted.Contains(requested) {
+		return nil, oauthserver.ErrInvalidScope.Errorf("requested scope %q exceeds %q", scope, granted.String())
+	}
+	return requested, nil
+}
+
+func intersect(a, b oauthserver.Scopes) oauthserver.Scopes {
+	scopes := make(oauthserver.Scopes, 0, len(a))
+	for _, scope := range a {
+		if b.Contains([]string{scope}) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// validateClient checks the name and the scopes of a client, the scopes are access control actions.
+func validateClient(name string, scopes []string) (string, oauthserver.Scopes, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, oauthserver.ErrInvalidClientConfig.Errorf("name is required")
+	}
+	if len(name) > maxNameLength {
+		return "", nil, oauthserver.ErrInvalidClientConfig.Errorf("name is longer than %d characters", maxNameLength)
+	}
+	if len(scopes) == 0 {
+		return "", nil, oauthserver.ErrInvalidClientConfig.Errorf("at least one scope is required")
+	}
+
+	unique := make(oauthserver.Scopes, 0, len(scopes))
+	for _, scope := range scopes {
+		if !strings.Contains(scope, ":") || strings.ContainsAny(scope, " \t\n\"\\") {
+			return "", nil, oauthserver.ErrInvalidClientConfig.Errorf("scope %q isn't an access control action", scope)
+		}
+		if !unique.Contains([]string{scope}) {
+			unique = append(unique, scope)
+		}
+	}
+	return name, unique, nil
+}
@@ -0,0 +1,183 @@
+package oauthserverimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationOAuthServer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+	s := &Service{
+		cfg:             setting.OAuthServerSettings{Enabled: true, AccessTokenLifetime: time.Hour, RefreshTokenLifetime: 24 * time.Hour},
+		store:           &sqlStore{db: db.InitTestDB(t), now: clock},
+		serviceAccounts: &fakeServiceAccounts{id: 2},
+		log:             log.NewNopLogger(),
+		now:             clock,
+	}
+	ctx := context.Background()
+
+	client, err := s.RegisterClient(ctx, 1, &oauthserver.RegisterClientCommand{
+		Name:             "ci",
+		ServiceAccountID: 2,
+		Scopes:           []string{"dashboards:read", "dashboards:write", "dashboards:read"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, client.ClientSecret)
+	require.Equal(t, oauthserver.Scopes{"dashboards:read", "dashboards:write"}, client.Scopes)
+
+	issue := func(t *testing.T, req oauthserver.TokenRequest) (*oauthserver.TokenResponse, error) {
+		req.ClientID = client.ClientID
+		req.ClientSecret = client.ClientSecret
+		return s.IssueToken(ctx, &req)
+	}
+
+	t.Run("should reject invalid clients", func(t *testing.T) {
+		_, err := s.RegisterClient(ctx, 1, &oauthserver.RegisterClientCommand{Name: "ci", ServiceAccountID: 3, Scopes: []string{"dashboards:read"}})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidClientConfig)
+		_, err = s.RegisterClient(ctx, 1, &oauthserver.RegisterClientCommand{Name: "ci", ServiceAccountID: 2, Scopes: []string{"admin"}})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidClientConfig)
+		_, err = s.RegisterClient(ctx, 1, &oauthserver.RegisterClientCommand{Name: " ", ServiceAccountID: 2, Scopes: []string{"dashboards:read"}})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidClientConfig)
+	})
+
+	t.Run("should authenticate the client", func(t *testing.T) {
+		_, err := s.IssueToken(ctx, &oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials, ClientID: client.ClientID, ClientSecret: "glos_wrong_secret"})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidClient)
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: "password"})
+		require.ErrorIs(t, err, oauthserver.ErrUnsupportedGrantType)
+	})
+
+	t.Run("should issue tokens restricted to the requested scopes", func(t *testing.T) {
+		resp, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials, Scope: "dashboards:read"})
+		require.NoError(t, err)
+		require.Equal(t, "Bearer", resp.TokenType)
+		require.Equal(t, int64(3600), resp.ExpiresIn)
+		require.Equal(t, "dashboards:read", resp.Scope)
+		require.True(t, oauthserver.IsAccessToken(resp.AccessToken))
+
+		token, err := s.ValidateAccessToken(ctx, resp.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), token.ServiceAccountID)
+		require.Equal(t, map[string][]string{"dashboards:read": {""}}, token.PermissionBoundary())
+
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials, Scope: "users:read"})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidScope)
+
+		_, err = s.ValidateAccessToken(ctx, resp.RefreshToken)
+		require.ErrorIs(t, err, oauthserver.ErrInvalidToken)
+	})
+
+	t.Run("should rotate the refresh tokens and revoke the family when one is reused", func(t *testing.T) {
+		first, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.NoError(t, err)
+		second, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: first.RefreshToken})
+		require.NoError(t, err)
+		require.Equal(t, "dashboards:read dashboards:write", second.Scope)
+		require.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+		// the access token of the rotated pair stays valid until the reuse of its refresh token
+		_, err = s.ValidateAccessToken(ctx, first.AccessToken)
+		require.NoError(t, err)
+
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: first.RefreshToken})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidGrant)
+		_, err = s.ValidateAccessToken(ctx, second.AccessToken)
+		require.ErrorIs(t, err, oauthserver.ErrInvalidToken)
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: second.RefreshToken})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidGrant)
+	})
+
+	t.Run("should expire the tokens", func(t *testing.T) {
+		resp, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.NoError(t, err)
+
+		now = now.Add(2 * time.Hour)
+		_, err = s.ValidateAccessToken(ctx, resp.AccessToken)
+		require.ErrorIs(t, err, oauthserver.ErrInvalidToken)
+		refreshed, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: resp.RefreshToken})
+		require.NoError(t, err)
+
+		now = now.Add(48 * time.Hour)
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: refreshed.RefreshToken})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidGrant)
+	})
+
+	t.Run("should drop the scopes removed from the client", func(t *testing.T) {
+		resp, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.NoError(t, err)
+
+		_, err = s.UpdateClient(ctx, 1, client.ClientID, &oauthserver.UpdateClientCommand{Name: "ci", Scopes: []string{"dashboards:read"}})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_, err := s.UpdateClient(ctx, 1, client.ClientID, &oauthserver.UpdateClientCommand{Name: "ci", Scopes: []string{"dashboards:read", "dashboards:write"}})
+			require.NoError(t, err)
+		})
+
+		token, err := s.ValidateAccessToken(ctx, resp.AccessToken)
+		require.NoError(t, err)
+		require.Equal(t, oauthserver.Scopes{"dashboards:read"}, token.Scopes)
+		refreshed, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: resp.RefreshToken})
+		require.NoError(t, err)
+		require.Equal(t, "dashboards:read", refreshed.Scope)
+	})
+
+	t.Run("should revoke the tokens", func(t *testing.T) {
+		resp, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.NoError(t, err)
+
+		require.NoError(t, s.RevokeToken(ctx, client.ClientID, client.ClientSecret, resp.RefreshToken))
+		_, err = s.ValidateAccessToken(ctx, resp.AccessToken)
+		require.ErrorIs(t, err, oauthserver.ErrInvalidToken)
+		require.NoError(t, s.RevokeToken(ctx, client.ClientID, client.ClientSecret, "unknown"))
+	})
+
+	t.Run("should revoke the tokens when the secret is reset or the client is deleted", func(t *testing.T) {
+		resp, err := issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.NoError(t, err)
+
+		reset, err := s.ResetClientSecret(ctx, 1, client.ClientID)
+		require.NoError(t, err)
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidClient)
+		client.ClientSecret = reset.ClientSecret
+		_, err = s.ValidateAccessToken(ctx, resp.AccessToken)
+		require.ErrorIs(t, err, oauthserver.ErrInvalidToken)
+		_, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeRefreshToken, RefreshToken: resp.RefreshToken})
+		require.ErrorIs(t, err, oauthserver.ErrInvalidGrant)
+
+		resp, err = issue(t, oauthserver.TokenRequest{GrantType: oauthserver.GrantTypeClientCredentials})
+		require.NoError(t, err)
+
+		require.NoError(t, s.DeleteClient(ctx, 1, client.ClientID))
+		_, err = s.ValidateAccessToken(ctx, resp.AccessToken)
+		require.ErrorIs(t, err, oauthserver.ErrInvalidToken)
+		_, err = s.GetClient(ctx, 1, client.ClientID)
+		require.ErrorIs(t, err, oauthserver.ErrClientNotFound)
+	})
+}
+
+type fakeServiceAccounts struct {
+	serviceaccounts.Service
+	id int64
+}
+
+func (f *fakeServiceAccounts) RetrieveServiceAccount(ctx context.Context, orgID, serviceAccountID int64) (*serviceaccounts.ServiceAccountProfileDTO, error) {
+	if serviceAccountID != f.id {
+		return nil, serviceaccounts.ErrServiceAccountNotFound.Errorf("service account not found")
+	}
+	return &serviceaccounts.ServiceAccountProfileDTO{Id: f.id, OrgId: orgID}, nil
+}
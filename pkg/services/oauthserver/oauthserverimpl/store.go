@@ -0,0 +1,181 @@
+package oauthserverimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+)
+
+type store interface {
+	ListClients(ctx context.Context, orgID int64) ([]*oauthserver.Client, error)
+	GetClient(ctx context.Context, orgID int64, clientID string) (*oauthserver.Client, error)
+	// GetClientByClientID returns the client of any organization, the client IDs are unique.
+	GetClientByClientID(ctx context.Context, clientID string) (*oauthserver.Client, error)
+	GetClientByID(ctx context.Context, id int64) (*oauthserver.Client, error)
+	InsertClient(ctx context.Context, client *oauthserver.Client) error
+	UpdateClient(ctx context.Context, client *oauthserver.Client) error
+	// ResetClientSecret saves the new secret of the client and deletes its tokens.
+	ResetClientSecret(ctx context.Context, client *oauthserver.Client) error
+	// DeleteClient deletes the client and its tokens.
+	DeleteClient(ctx context.Context, client *oauthserver.Client) error
+
+	// InsertToken saves a new pair of tokens, and deletes the tokens of the client whose refresh token expired.
+	InsertToken(ctx context.Context, token *oauthserver.Token) error
+	GetTokenByAccessToken(ctx context.Context, hash string) (*oauthserver.Token, error)
+	GetTokenByRefreshToken(ctx context.Context, hash string) (*oauthserver.Token, error)
+	// RotateToken marks the refresh token of the old pair as used and saves the new pair. It fails with
+	// oauthserver.ErrInvalidGrant when the refresh token has already been used.
+	RotateToken(ctx context.Context, old, token *oauthserver.Token) error
+	// RevokeFamily revokes all the tokens of the family.
+	RevokeFamily(ctx context.Context, clientID int64, familyID string) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) ListClients(ctx context.Context, orgID int64) ([]*oauthserver.Client, error) {
+	clients := make([]*oauthserver.Client, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name", "id").Find(&clients)
+	})
+	return clients, err
+}
+
+func (s *sqlStore) GetClient(ctx context.Context, orgID int64, clientID string) (*oauthserver.Client, error) {
+	return s.getClient(ctx, "org_id = ? AND client_id = ?", orgID, clientID)
+}
+
+func (s *sqlStore) GetClientByClientID(ctx context.Context, clientID string) (*oauthserver.Client, error) {
+	return s.getClient(ctx, "client_id = ?", clientID)
+}
+
+func (s *sqlStore) GetClientByID(ctx context.Context, id int64) (*oauthserver.Client, error) {
+	return s.getClient(ctx, "id = ?", id)
+}
+
+func (s *sqlStore) getClient(ctx context.Context, query string, args ...interface{}) (*oauthserver.Client, error) {
+	client := &oauthserver.Client{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where(query, args...).Get(client)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return oauthserver.ErrClientNotFound.Errorf("oauth client not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (s *sqlStore) InsertClient(ctx context.Context, client *oauthserver.Client) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		now := s.now()
+		client.Created = now
+		client.Updated = now
+		_, err := sess.Insert(client)
+		return err
+	})
+}
+
+func (s *sqlStore) UpdateClient(ctx context.Context, client *oauthserver.Client) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		client.Updated = s.now()
+		_, err := sess.ID(client.ID).Cols("name", "scopes", "updated").Update(client)
+		return err
+	})
+}
+
+func (s *sqlStore) ResetClientSecret(ctx context.Context, client *oauthserver.Client) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM oauth_token WHERE client_id = ?", client.ID); err != nil {
+			return err
+		}
+		client.Updated = s.now()
+		_, err := sess.ID(client.ID).Cols("secret", "updated").Update(client)
+		return err
+	})
+}
+
+func (s *sqlStore) DeleteClient(ctx context.Context, client *oauthserver.Client) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM oauth_token WHERE client_id = ?", client.ID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM oauth_client WHERE id = ?", client.ID)
+		return err
+	})
+}
+
+func (s *sqlStore) InsertToken(ctx context.Context, token *oauthserver.Token) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		return insertToken(sess, token, s.now())
+	})
+}
+
+func insertToken(sess *db.Session, token *oauthserver.Token, now time.Time) error {
+	// the expired tokens are only kept until their refresh token expires, to detect the reuse of the refresh tokens
+	if _, err := sess.Exec("DELETE FROM oauth_token WHERE client_id = ? AND refresh_expires < ?", token.ClientID, now); err != nil {
+		return err
+	}
+	token.Created = now
+	_, err := sess.Insert(token)
+	return err
+}
+
+func (s *sqlStore) GetTokenByAccessToken(ctx context.Context, hash string) (*oauthserver.Token, error) {
+	return s.getToken(ctx, "access_token = ?", hash)
+}
+
+func (s *sqlStore) GetTokenByRefreshToken(ctx context.Context, hash string) (*oauthserver.Token, error) {
+	return s.getToken(ctx, "refresh_token = ?", hash)
+}
+
+func (s *sqlStore) getToken(ctx context.Context, query string, args ...interface{}) (*oauthserver.Token, error) {
+	token := &oauthserver.Token{}
+	var has bool
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		has, err = sess.Where(query, args...).Get(token)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, nil
+	}
+	return token, nil
+}
+
+func (s *sqlStore) RotateToken(ctx context.Context, old, token *oauthserver.Token) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		// the condition on rotated makes the concurrent exchanges of the same refresh token fail
+		res, err := sess.Exec("UPDATE oauth_token SET rotated = ? WHERE id = ? AND rotated = ?", true, old.ID, false)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return oauthserver.ErrInvalidGrant.Errorf("refresh token already used")
+		}
+		return insertToken(sess, token, s.now())
+	})
+}
+
+func (s *sqlStore) RevokeFamily(ctx context.Context, clientID int64, familyID string) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE oauth_token SET revoked = ? WHERE client_id = ? AND family_id = ?", true, clientID, familyID)
+		return err
+	})
+}
@@ -0,0 +1,55 @@
+package oauthservertest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/oauthserver"
+)
+
+type FakeService struct {
+	ExpectedClient        *oauthserver.Client
+	ExpectedClients       []*oauthserver.Client
+	ExpectedTokenResponse *oauthserver.TokenResponse
+	ExpectedAccessToken   *oauthserver.AccessToken
+	ExpectedError         error
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (s *FakeService) ListClients(ctx context.Context, orgID int64) ([]*oauthserver.Client, error) {
+	return s.ExpectedClients, s.ExpectedError
+}
+
+func (s *FakeService) GetClient(ctx context.Context, orgID int64, clientID string) (*oauthserver.Client, error) {
+	return s.ExpectedClient, s.ExpectedError
+}
+
+func (s *FakeService) RegisterClient(ctx context.Context, orgID int64, cmd *oauthserver.RegisterClientCommand) (*oauthserver.Client, error) {
+	return s.ExpectedClient, s.ExpectedError
+}
+
+func (s *FakeService) UpdateClient(ctx context.Context, orgID int64, clientID string, cmd *oauthserver.UpdateClientCommand) (*oauthserver.Client, error) {
+	return s.ExpectedClient, s.ExpectedError
+}
+
+func (s *FakeService) DeleteClient(ctx context.Context, orgID int64, clientID string) error {
+	return s.ExpectedError
+}
+
+func (s *FakeService) ResetClientSecret(ctx context.Context, orgID int64, clientID string) (*oauthserver.Client, error) {
+	return s.ExpectedClient, s.ExpectedError
+}
+
+func (s *FakeService) IssueToken(ctx context.Context, req *oauthserver.TokenRequest) (*oauthserver.TokenResponse, error) {
+	return s.ExpectedTokenResponse, s.ExpectedError
+}
+
+func (s *FakeService) RevokeToken(ctx context.Context, clientID, clientSecret, token string) error {
+	return s.ExpectedError
+}
+
+func (s *FakeService) ValidateAccessToken(ctx context.Context, token string) (*oauthserver.AccessToken, error) {
+	return s.ExpectedAccessToken, s.ExpectedError
+}
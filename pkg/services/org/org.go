@@ -22,4 +22,18 @@ type Service interface {
 	RemoveOrgUser(context.Context, *RemoveOrgUserCommand) error
 	GetOrgUsers(context.Context, *GetOrgUsersQuery) ([]*OrgUserDTO, error)
 	SearchOrgUsers(context.Context, *SearchOrgUsersQuery) (*SearchOrgUsersQueryResult, error)
+	// RegisterRoleAssignmentHook adds a hook called before a role is assigned to a user. The hooks must be
+	// registered when the services are initialized.
+	RegisterRoleAssignmentHook(RoleAssignmentHook)
 }
+
+// RoleAssignment is the role about to be given to a user in an organization, when the user is added to the
+// organization, when the role of the user changes, or when the user creates the organization.
+type RoleAssignment struct {
+	OrgID  int64
+	UserID int64
+	Role   RoleType
+}
+
+// RoleAssignmentHook rejects a role assignment by returning an error.
+type RoleAssignmentHook func(ctx context.Context, assignment RoleAssignment) error
@@ -18,6 +18,8 @@ type Service struct {
 	store store
 	cfg   *setting.Cfg
 	log   log.Logger
+
+	roleAssignmentHooks []org.RoleAssignmentHook
 }
 
 func ProvideService(db db.DB, cfg *setting.Cfg, quotaService quota.Service) (org.Service, error) {
@@ -98,7 +100,23 @@ func (s *Service) GetIDForNewUser(ctx context.Context, cmd org.GetOrgIDForNewUse
 	return s.store.Insert(ctx, &orga)
 }
 
+func (s *Service) RegisterRoleAssignmentHook(hook org.RoleAssignmentHook) {
+	s.roleAssignmentHooks = append(s.roleAssignmentHooks, hook)
+}
+
+func (s *Service) runRoleAssignmentHooks(ctx context.Context, assignment org.RoleAssignment) error {
+	for _, hook := range s.roleAssignmentHooks {
+		if err := hook(ctx, assignment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) InsertOrgUser(ctx context.Context, orguser *org.OrgUser) (int64, error) {
+	if err := s.runRoleAssignmentHooks(ctx, org.RoleAssignment{OrgID: orguser.OrgID, UserID: orguser.UserID, Role: orguser.Role}); err != nil {
+		return 0, err
+	}
 	return s.store.InsertOrgUser(ctx, orguser)
 }
 
@@ -131,6 +149,12 @@ func (s *Service) GetByName(ctx context.Context, query *org.GetOrgByNameQuery) (
 
 // TODO: refactor service to call store CRUD method
 func (s *Service) CreateWithMember(ctx context.Context, cmd *org.CreateOrgCommand) (*org.Org, error) {
+	if cmd.UserID != 0 {
+		// the organization doesn't exist yet, the creator becomes its admin
+		if err := s.runRoleAssignmentHooks(ctx, org.RoleAssignment{UserID: cmd.UserID, Role: org.RoleAdmin}); err != nil {
+			return nil, err
+		}
+	}
 	return s.store.CreateWithMember(ctx, cmd)
 }
 
@@ -179,11 +203,17 @@ func (s *Service) GetOrCreate(ctx context.Context, orgName string) (int64, error
 
 // TODO: refactor service to call store CRUD method
 func (s *Service) AddOrgUser(ctx context.Context, cmd *org.AddOrgUserCommand) error {
+	if err := s.runRoleAssignmentHooks(ctx, org.RoleAssignment{OrgID: cmd.OrgID, UserID: cmd.UserID, Role: cmd.Role}); err != nil {
+		return err
+	}
 	return s.store.AddOrgUser(ctx, cmd)
 }
 
 // TODO: refactor service to call store CRUD method
 func (s *Service) UpdateOrgUser(ctx context.Context, cmd *org.UpdateOrgUserCommand) error {
+	if err := s.runRoleAssignmentHooks(ctx, org.RoleAssignment{OrgID: cmd.OrgID, UserID: cmd.UserID, Role: cmd.Role}); err != nil {
+		return err
+	}
 	return s.store.UpdateOrgUser(ctx, cmd)
 }
 
@@ -2,6 +2,7 @@ package orgimpl
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,6 +69,37 @@ func TestOrgService(t *testing.T) {
 	})
 }
 
+func TestOrgServiceRoleAssignmentHooks(t *testing.T) {
+	errHook := errors.New("rejected")
+	var assignments []org.RoleAssignment
+	orgService := Service{
+		store: newOrgStoreFake(),
+		cfg:   setting.NewCfg(),
+	}
+	orgService.RegisterRoleAssignmentHook(func(ctx context.Context, assignment org.RoleAssignment) error {
+		assignments = append(assignments, assignment)
+		if assignment.Role == org.RoleAdmin {
+			return errHook
+		}
+		return nil
+	})
+
+	err := orgService.AddOrgUser(context.Background(), &org.AddOrgUserCommand{OrgID: 1, UserID: 2, Role: org.RoleEditor})
+	require.NoError(t, err)
+
+	err = orgService.UpdateOrgUser(context.Background(), &org.UpdateOrgUserCommand{OrgID: 1, UserID: 2, Role: org.RoleAdmin})
+	require.ErrorIs(t, err, errHook)
+
+	_, err = orgService.CreateWithMember(context.Background(), &org.CreateOrgCommand{Name: "new", UserID: 2})
+	require.ErrorIs(t, err, errHook)
+
+	assert.Equal(t, []org.RoleAssignment{
+		{OrgID: 1, UserID: 2, Role: org.RoleEditor},
+		{OrgID: 1, UserID: 2, Role: org.RoleAdmin},
+		{UserID: 2, Role: org.RoleAdmin},
+	}, assignments)
+}
+
 type FakeOrgStore struct {
 	ExpectedOrg                       *org.Org
 	ExpectedOrgID                     int64
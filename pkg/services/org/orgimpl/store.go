@@ -207,14 +207,16 @@ func (ss *sqlStore) UpdateAddress(ctx context.Context, cmd *org.UpdateOrgAddress
 // TODO: refactor move logic to service method
 func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error {
 	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		if res, err := sess.Query("SELECT 1 from org WHERE id=?", cmd.ID); err != nil {
+		var deleted org.Org
+		if has, err := sess.ID(cmd.ID).Get(&deleted); err != nil {
 			return err
-		} else if len(res) != 1 {
+		} else if !has {
 			return org.ErrOrgNotFound.Errorf("failed to delete organisation with ID: %d", cmd.ID)
 		}
 
 		deletes := []string{
 			"DELETE FROM star WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND star.dashboard_id = dashboard.id)",
+			"DELETE FROM resource_star WHERE org_id = ?",
 			"DELETE FROM dashboard_tag WHERE EXISTS (SELECT 1 FROM dashboard WHERE org_id = ? AND dashboard_tag.dashboard_id = dashboard.id)",
 			"DELETE FROM dashboard WHERE org_id = ?",
 			"DELETE FROM api_key WHERE org_id = ?",
@@ -233,6 +235,9 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM alert WHERE org_id = ?",
 			"DELETE FROM annotation WHERE org_id = ?",
 			"DELETE FROM kv_store WHERE org_id = ?",
+			"DELETE FROM org_setting WHERE org_id = ?",
+			"DELETE FROM nav_customization WHERE org_id = ?",
+			"DELETE FROM user_inactivity_notice WHERE org_id = ?",
 		}
 
 		for _, sql := range deletes {
@@ -242,6 +247,12 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			}
 		}
 
+		sess.PublishAfterCommit(&events.OrgDeleted{
+			Timestamp: time.Now(),
+			Id:        deleted.ID,
+			Name:      deleted.Name,
+		})
+
 		return nil
 	})
 }
@@ -786,6 +797,7 @@ func deleteUserAccessControl(sess *db.Session, userID int64) error {
 func (ss *sqlStore) userDeletions() []string {
 	deletes := []string{
 		"DELETE FROM star WHERE user_id = ?",
+		"DELETE FROM resource_star WHERE user_id = ?",
 		"DELETE FROM " + ss.dialect.Quote("user") + " WHERE id = ?",
 		"DELETE FROM org_user WHERE user_id = ?",
 		"DELETE FROM dashboard_acl WHERE user_id = ?",
@@ -794,6 +806,7 @@ func (ss *sqlStore) userDeletions() []string {
 		"DELETE FROM user_auth WHERE user_id = ?",
 		"DELETE FROM user_auth_token WHERE user_id = ?",
 		"DELETE FROM quota WHERE user_id = ?",
+		"DELETE FROM user_inactivity_notice WHERE user_id = ?",
 	}
 	return deletes
 }
@@ -0,0 +1,65 @@
+// Package orglifecycle seeds the organizations with the configured template when they are created, and calls the
+// configured webhooks when organizations are created or deleted, for example to keep an external billing system
+// in sync.
+package orglifecycle
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type Service struct {
+	cfg         *setting.Cfg
+	log         log.Logger
+	jobQueue    jobqueue.Service
+	folders     folder.Service
+	dataSources datasources.DataSourceService
+	teams       team.Service
+	prefs       pref.Service
+	httpClient  *http.Client
+}
+
+func ProvideService(cfg *setting.Cfg, bus bus.Bus, jobQueue jobqueue.Service, folderService folder.Service,
+	dataSourceService datasources.DataSourceService, teamService team.Service, prefService pref.Service) *Service {
+	s := &Service{
+		cfg:         cfg,
+		log:         log.New("org.lifecycle"),
+		jobQueue:    jobQueue,
+		folders:     folderService,
+		dataSources: dataSourceService,
+		teams:       teamService,
+		prefs:       prefService,
+		httpClient:  &http.Client{Timeout: cfg.OrgHooks.Timeout},
+	}
+
+	bus.AddEventListener(s.handleOrgCreated)
+	bus.AddEventListener(s.handleOrgDeleted)
+	jobQueue.RegisterHandler(webhookJob, s.sendWebhook, jobqueue.HandlerOptions{})
+	return s
+}
+
+func (s *Service) handleOrgCreated(ctx context.Context, e *events.OrgCreated) error {
+	// the errors are logged rather than returned, so that the other listeners of the event still run
+	if s.cfg.OrgTemplate.Enabled {
+		if err := s.applyTemplate(ctx, e.Id); err != nil {
+			s.log.Error("Failed to apply the template to the new organization", "orgId", e.Id, "error", err)
+		}
+	}
+	s.enqueueWebhooks(ctx, EventOrgCreated, e.Id, e.Name, e.Timestamp)
+	return nil
+}
+
+func (s *Service) handleOrgDeleted(ctx context.Context, e *events.OrgDeleted) error {
+	s.enqueueWebhooks(ctx, EventOrgDeleted, e.Id, e.Name, e.Timestamp)
+	return nil
+}
@@ -0,0 +1,167 @@
+package orglifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/foldertest"
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestService_OrgCreated(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.OrgTemplate = setting.OrgTemplateSettings{
+		Enabled:     true,
+		Folders:     []string{"Team dashboards"},
+		SourceOrgID: 1,
+		DataSources: []string{"prometheus"},
+		Teams:       []string{"Admins", "Editors"},
+		Theme:       "dark",
+	}
+	cfg.OrgHooks = setting.OrgHooksSettings{URLs: []string{"https://billing.example.com/hook"}}
+
+	folders := &fakeFolderService{}
+	dataSources := &fakeDatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+		{ID: 1, OrgID: 1, UID: "prometheus", Name: "Prometheus", Type: "prometheus"},
+	}}
+	teams := &fakeTeamService{}
+	prefs := &fakePreferenceService{}
+	jobQueue := &fakeJobQueue{}
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	ProvideService(cfg, b, jobQueue, folders, dataSources, teams, prefs)
+
+	err := b.Publish(context.Background(), &events.OrgCreated{Id: 2, Name: "new org", Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	require.Len(t, folders.created, 1)
+	assert.Equal(t, "Team dashboards", folders.created[0].Title)
+	assert.Equal(t, int64(2), folders.created[0].OrgID)
+
+	require.Len(t, dataSources.DataSources, 2)
+	assert.Equal(t, int64(2), dataSources.DataSources[1].OrgID)
+	assert.Equal(t, "prometheus", dataSources.DataSources[1].UID)
+
+	assert.Equal(t, []string{"Admins", "Editors"}, teams.created)
+
+	require.NotNil(t, prefs.saved)
+	assert.Equal(t, "dark", prefs.saved.Theme)
+	assert.Equal(t, int64(2), prefs.saved.OrgID)
+
+	require.Len(t, jobQueue.enqueued, 1)
+	payload := jobQueue.enqueued[0].Payload.(webhookJobPayload)
+	assert.Equal(t, "https://billing.example.com/hook", payload.URL)
+	assert.Equal(t, EventOrgCreated, payload.Payload.Event)
+	assert.Equal(t, int64(2), payload.Payload.OrgID)
+}
+
+func TestService_OrgDeleted(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.OrgHooks = setting.OrgHooksSettings{URLs: []string{"https://a.example.com", "https://b.example.com"}}
+
+	jobQueue := &fakeJobQueue{}
+	b := bus.ProvideBus(tracing.InitializeTracerForTest())
+	ProvideService(cfg, b, jobQueue, &fakeFolderService{}, &fakeDatasources.FakeDataSourceService{}, &fakeTeamService{}, &fakePreferenceService{})
+
+	err := b.Publish(context.Background(), &events.OrgDeleted{Id: 2, Name: "old org", Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	require.Len(t, jobQueue.enqueued, 2)
+	assert.Equal(t, EventOrgDeleted, jobQueue.enqueued[0].Payload.(webhookJobPayload).Payload.Event)
+}
+
+func TestService_SendWebhook(t *testing.T) {
+	var (
+		received  WebhookPayload
+		signature string
+		status    = http.StatusOK
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &received))
+		signature = r.Header.Get(signatureHeader)
+		assert.Equal(t, "sha256="+util.HmacSha256Sum("secret", body), signature)
+		assert.Equal(t, EventOrgCreated, r.Header.Get(eventHeader))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := setting.NewCfg()
+	cfg.OrgHooks = setting.OrgHooksSettings{Secret: "secret", Timeout: time.Second}
+	s := &Service{cfg: cfg, httpClient: server.Client()}
+
+	payload, err := json.Marshal(webhookJobPayload{URL: server.URL, Payload: WebhookPayload{Event: EventOrgCreated, OrgID: 2, OrgName: "new org"}})
+	require.NoError(t, err)
+	job := &jobqueue.Job{Type: webhookJob, Payload: payload}
+
+	require.NoError(t, s.sendWebhook(context.Background(), job))
+	assert.Equal(t, int64(2), received.OrgID)
+	assert.Equal(t, "new org", received.OrgName)
+	assert.NotEmpty(t, signature)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, s.sendWebhook(context.Background(), job), "the job is retried when the webhook fails")
+}
+
+type fakeFolderService struct {
+	foldertest.FakeService
+	created []*folder.CreateFolderCommand
+}
+
+func (f *fakeFolderService) Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
+	f.created = append(f.created, cmd)
+	return &folder.Folder{Title: cmd.Title, OrgID: cmd.OrgID}, nil
+}
+
+type fakeTeamService struct {
+	teamtest.FakeService
+	created []string
+}
+
+func (f *fakeTeamService) CreateTeam(name, email string, orgID int64) (team.Team, error) {
+	f.created = append(f.created, name)
+	return team.Team{Name: name, OrgID: orgID}, nil
+}
+
+type fakePreferenceService struct {
+	preftest.FakePreferenceService
+	saved *pref.SavePreferenceCommand
+}
+
+func (f *fakePreferenceService) Save(ctx context.Context, cmd *pref.SavePreferenceCommand) error {
+	f.saved = cmd
+	return nil
+}
+
+type fakeJobQueue struct {
+	jobqueue.Service
+	enqueued []jobqueue.EnqueueCommand
+}
+
+func (f *fakeJobQueue) RegisterHandler(jobType string, handler jobqueue.Handler, opts jobqueue.HandlerOptions) {
+}
+
+func (f *fakeJobQueue) Enqueue(ctx context.Context, cmd jobqueue.EnqueueCommand) (*jobqueue.Job, error) {
+	f.enqueued = append(f.enqueued, cmd)
+	return &jobqueue.Job{Type: cmd.Type}, nil
+}
@@ -0,0 +1,99 @@
+package orglifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+)
+
+// applyTemplate seeds a new organization with the folders, data sources, teams and preferences of the template. It
+// goes on when an item can't be created, and returns all the errors.
+func (s *Service) applyTemplate(ctx context.Context, orgID int64) error {
+	template := s.cfg.OrgTemplate
+	var errs []string
+
+	templateUser := accesscontrol.BackgroundUser("org_template", orgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersCreate},
+		{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+	})
+	for _, title := range template.Folders {
+		if _, err := s.folders.Create(ctx, &folder.CreateFolderCommand{
+			OrgID:        orgID,
+			Title:        title,
+			SignedInUser: templateUser,
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("folder %q: %s", title, err))
+		}
+	}
+
+	for _, uid := range template.DataSources {
+		if err := s.copyDataSource(ctx, uid, orgID); err != nil {
+			errs = append(errs, fmt.Sprintf("data source %q: %s", uid, err))
+		}
+	}
+
+	for _, name := range template.Teams {
+		if _, err := s.teams.CreateTeam(name, "", orgID); err != nil {
+			errs = append(errs, fmt.Sprintf("team %q: %s", name, err))
+		}
+	}
+
+	if template.Theme != "" || template.Timezone != "" || template.WeekStart != "" {
+		if err := s.prefs.Save(ctx, &pref.SavePreferenceCommand{
+			OrgID:     orgID,
+			Theme:     template.Theme,
+			Timezone:  template.Timezone,
+			WeekStart: template.WeekStart,
+		}); err != nil {
+			errs = append(errs, fmt.Sprintf("preferences: %s", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to apply the organization template: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// copyDataSource copies a data source of the source organization, with its secrets and its UID, so that the
+// dashboards referencing it by UID work in the new organization.
+func (s *Service) copyDataSource(ctx context.Context, uid string, orgID int64) error {
+	ds, err := s.dataSources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: s.cfg.OrgTemplate.SourceOrgID})
+	if err != nil {
+		return err
+	}
+	if ds == nil {
+		return errors.New("data source not found")
+	}
+
+	secureJSONData, err := s.dataSources.DecryptedValues(ctx, ds)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dataSources.AddDataSource(ctx, &datasources.AddDataSourceCommand{
+		Name:            ds.Name,
+		Type:            ds.Type,
+		Access:          ds.Access,
+		URL:             ds.URL,
+		Database:        ds.Database,
+		User:            ds.User,
+		BasicAuth:       ds.BasicAuth,
+		BasicAuthUser:   ds.BasicAuthUser,
+		WithCredentials: ds.WithCredentials,
+		IsDefault:       ds.IsDefault,
+		JsonData:        ds.JsonData,
+		SecureJsonData:  secureJSONData,
+		UID:             ds.UID,
+		OrgID:           orgID,
+	})
+	return err
+}
@@ -0,0 +1,91 @@
+package orglifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/jobqueue"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	EventOrgCreated = "org.created"
+	EventOrgDeleted = "org.deleted"
+
+	// webhookJob is the job of the job queue calling a webhook, so that the calls are retried when the webhook
+	// fails or Grafana restarts.
+	webhookJob = "org.lifecycle-webhook"
+
+	signatureHeader = "X-Grafana-Signature"
+	eventHeader     = "X-Grafana-Event"
+)
+
+// WebhookPayload is the body of the webhook requests.
+type WebhookPayload struct {
+	Event     string    `json:"event"`
+	OrgID     int64     `json:"orgId"`
+	OrgName   string    `json:"orgName"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type webhookJobPayload struct {
+	URL     string         `json:"url"`
+	Payload WebhookPayload `json:"payload"`
+}
+
+func (s *Service) enqueueWebhooks(ctx context.Context, event string, orgID int64, orgName string, timestamp time.Time) {
+	for _, url := range s.cfg.OrgHooks.URLs {
+		if _, err := s.jobQueue.Enqueue(ctx, jobqueue.EnqueueCommand{
+			Type: webhookJob,
+			Payload: webhookJobPayload{
+				URL:     url,
+				Payload: WebhookPayload{Event: event, OrgID: orgID, OrgName: orgName, Timestamp: timestamp},
+			},
+		}); err != nil {
+			s.log.Error("Failed to queue the organization webhook", "event", event, "orgId", orgID, "url", url, "error", err)
+		}
+	}
+}
+
+func (s *Service) sendWebhook(ctx context.Context, job *jobqueue.Job) error {
+	var p webhookJobPayload
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(p.Payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHeader, p.Payload.Event)
+	if s.cfg.OrgHooks.Secret != "" {
+		req.Header.Set(signatureHeader, "sha256="+util.HmacSha256Sum(s.cfg.OrgHooks.Secret, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.log.Warn("Failed to close the webhook response body", "error", err)
+		}
+	}()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("organization webhook %s returned status %d", p.URL, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package orgsettings
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/login"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Service resolves the settings that can be overridden per organization.
+// The value configured for an organization takes precedence over the value
+// from the configuration files.
+type Service interface {
+	// GetString returns the value of the setting for the organization.
+	GetString(ctx context.Context, orgID int64, name Name) string
+	// GetInt64 returns the value of the setting for the organization as an integer.
+	GetInt64(ctx context.Context, orgID int64, name Name) int64
+	// List returns all the settings that can be overridden, with their effective value for the organization.
+	List(ctx context.Context, orgID int64) ([]SettingDTO, error)
+	// Set overrides a setting for an organization.
+	Set(ctx context.Context, cmd *SetCommand) error
+	// Delete removes the override of a setting so that the global value applies again.
+	Delete(ctx context.Context, orgID int64, name Name) error
+}
+
+var (
+	ErrUnknownSetting = errutil.NewBase(errutil.StatusNotFound, "orgsettings.unknown-setting")
+	ErrInvalidValue   = errutil.NewBase(errutil.StatusBadRequest, "orgsettings.invalid-value")
+	ErrNotOverridden  = errutil.NewBase(errutil.StatusNotFound, "orgsettings.not-overridden", errutil.WithPublicMessage("Setting is not overridden for this organization"))
+)
+
+// Name identifies a setting with the format <section>.<key> as found in the configuration files
+type Name string
+
+const (
+	DefaultTheme           Name = "users.default_theme"
+	HomeDashboardPath      Name = "dashboards.default_home_dashboard_path"
+	APIKeyMaxSecondsToLive Name = "auth.api_key_max_seconds_to_live"
+	AllowedAuthMethods     Name = "auth.allowed_auth_methods"
+
+	InactiveUserDisableAfterDays Name = "users.inactive_user_disable_after_days"
+	InactiveUserAction           Name = "users.inactive_user_action"
+	InactiveUserNotifyBeforeDays Name = "users.inactive_user_notify_before_days"
+	InactiveUserExemptions       Name = "users.inactive_user_exemptions"
+
+	QueryHistoryRetentionDays Name = "query_history.retention_days"
+)
+
+type ValueType string
+
+const (
+	StringType ValueType = "string"
+	IntType    ValueType = "int"
+)
+
+// Definition describes a setting that can be overridden per organization
+type Definition struct {
+	Name        Name
+	Type        ValueType
+	Description string
+	// Default returns the global value of the setting
+	Default func(cfg *setting.Cfg) string
+	// Check is an optional validation of the value before it is stored
+	Check func(value string) error
+}
+
+// Definitions is the list of the settings that can be overridden per organization
+var Definitions = []Definition{
+	{
+		Name:        DefaultTheme,
+		Type:        StringType,
+		Description: "Default UI theme for the users of the organization",
+		Default:     func(cfg *setting.Cfg) string { return cfg.DefaultTheme },
+		Check: func(value string) error {
+			switch value {
+			case "", "light", "dark", "system":
+				return nil
+			}
+			return fmt.Errorf("unknown theme: %s", value)
+		},
+	},
+	{
+		Name:        HomeDashboardPath,
+		Type:        StringType,
+		Description: "Path to the JSON file used as home dashboard when no home dashboard is set in the preferences",
+		Default:     func(cfg *setting.Cfg) string { return cfg.DefaultHomeDashboardPath },
+	},
+	{
+		Name:        APIKeyMaxSecondsToLive,
+		Type:        IntType,
+		Description: "Maximum lifetime of the API keys and service account tokens created in the organization, -1 means unlimited",
+		Default:     func(cfg *setting.Cfg) string { return strconv.FormatInt(cfg.ApiKeyMaxSecondsToLive, 10) },
+		Check: func(value string) error {
+			if v, _ := strconv.ParseInt(value, 10, 64); v < -1 || v == 0 {
+				return fmt.Errorf("the maximum lifetime should be -1 or a positive number of seconds")
+			}
+			return nil
+		},
+	},
+	{
+		Name:        AllowedAuthMethods,
+		Type:        StringType,
+		Description: "Comma-separated list of the authentication methods the users can sign in to the organization with (basic, ldap, saml, jwt, authproxy, oauth or oauth_<provider>), all of them when empty",
+		Default:     func(cfg *setting.Cfg) string { return cfg.AllowedAuthMethods },
+		Check: func(value string) error {
+			for _, method := range util.SplitString(value) {
+				if !login.IsAuthMethod(method) {
+					return fmt.Errorf("unknown authentication method: %s", method)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Name:        InactiveUserDisableAfterDays,
+		Type:        IntType,
+		Description: "Number of days without sign in after which the inactive user action is applied, 0 disables the policy",
+		Default:     func(cfg *setting.Cfg) string { return strconv.FormatInt(cfg.InactiveUserDisableAfterDays, 10) },
+		Check:       checkNotNegative,
+	},
+	{
+		Name:        InactiveUserAction,
+		Type:        StringType,
+		Description: "Action applied to inactive users, either disable or downgrade",
+		Default:     func(cfg *setting.Cfg) string { return cfg.InactiveUserAction },
+		Check: func(value string) error {
+			switch value {
+			case "disable", "downgrade":
+				return nil
+			}
+			return fmt.Errorf("unknown action: %s", value)
+		},
+	},
+	{
+		Name:        InactiveUserNotifyBeforeDays,
+		Type:        IntType,
+		Description: "Number of days before the inactive user action that users are notified by email, 0 disables the notification",
+		Default:     func(cfg *setting.Cfg) string { return strconv.FormatInt(cfg.InactiveUserNotifyBeforeDays, 10) },
+		Check:       checkNotNegative,
+	},
+	{
+		Name:        InactiveUserExemptions,
+		Type:        StringType,
+		Description: "Comma-separated list of logins or emails exempted from the inactive user policy",
+		Default:     func(cfg *setting.Cfg) string { return cfg.InactiveUserExemptions },
+	},
+	{
+		Name:        QueryHistoryRetentionDays,
+		Type:        IntType,
+		Description: "Number of days the queries of the query history are kept, starred queries are kept until they are unstarred",
+		Default:     func(cfg *setting.Cfg) string { return strconv.FormatInt(cfg.QueryHistoryRetentionDays, 10) },
+		Check: func(value string) error {
+			if v, _ := strconv.ParseInt(value, 10, 64); v <= 0 {
+				return fmt.Errorf("the retention should be a positive number of days")
+			}
+			return nil
+		},
+	},
+}
+
+func checkNotNegative(value string) error {
+	if v, _ := strconv.ParseInt(value, 10, 64); v < 0 {
+		return fmt.Errorf("the value cannot be negative")
+	}
+	return nil
+}
+
+// GetDefinition returns the definition of a setting if it can be overridden per organization
+func GetDefinition(name Name) (Definition, bool) {
+	for _, def := range Definitions {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Validate checks that the value can be used for the setting
+func (d Definition) Validate(value string) error {
+	if d.Type == IntType {
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return ErrInvalidValue.Errorf("%s should be an integer: %w", d.Name, err)
+		}
+	}
+	if d.Check != nil {
+		if err := d.Check(value); err != nil {
+			return ErrInvalidValue.Errorf("invalid value for %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// OrgSetting is the value of a setting overridden for an organization
+type OrgSetting struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	OrgID     int64     `xorm:"org_id"`
+	Name      Name      `xorm:"name"`
+	Value     string    `xorm:"value"`
+	Created   time.Time `xorm:"created"`
+	Updated   time.Time `xorm:"updated"`
+	UpdatedBy int64     `xorm:"updated_by"`
+}
+
+type SetCommand struct {
+	OrgID     int64  `json:"-"`
+	Name      Name   `json:"-"`
+	Value     string `json:"value"`
+	UpdatedBy int64  `json:"-"`
+}
+
+// SettingDTO is the state of a setting for an organization as returned by the API
+type SettingDTO struct {
+	Name        Name       `json:"name"`
+	Type        ValueType  `json:"type"`
+	Description string     `json:"description"`
+	Value       string     `json:"value"`
+	GlobalValue string     `json:"globalValue"`
+	Overridden  bool       `json:"overridden"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	UpdatedBy   int64      `json:"updatedBy,omitempty"`
+}
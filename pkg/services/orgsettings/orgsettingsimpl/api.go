@@ -0,0 +1,72 @@
+package orgsettingsimpl
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/orgs/:orgId/settings", func(settings routing.RouteRegister) {
+		settings.Get("/", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionRead)), routing.Wrap(s.handleList))
+		settings.Put("/:name", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleSet))
+		settings.Delete("/:name", authorize(middleware.ReqGrafanaAdmin, ac.EvalPermission(ActionWrite)), routing.Wrap(s.handleDelete))
+	})
+}
+
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+
+	settings, err := s.List(c.Req.Context(), orgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list organization settings", err)
+	}
+
+	return response.JSON(http.StatusOK, settings)
+}
+
+func (s *Service) handleSet(c *contextmodel.ReqContext) response.Response {
+	cmd := orgsettings.SetCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+	cmd.OrgID = orgID
+	cmd.Name = orgsettings.Name(web.Params(c.Req)[":name"])
+	cmd.UpdatedBy = c.UserID
+
+	if err := s.Set(c.Req.Context(), &cmd); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update organization setting", err)
+	}
+
+	return response.Success("Organization setting updated")
+}
+
+func (s *Service) handleDelete(c *contextmodel.ReqContext) response.Response {
+	orgID, err := strconv.ParseInt(web.Params(c.Req)[":orgId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "orgId is invalid", err)
+	}
+
+	if err := s.Delete(c.Req.Context(), orgID, orgsettings.Name(web.Params(c.Req)[":name"])); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to reset organization setting", err)
+	}
+
+	return response.Success("Organization setting reset to global value")
+}
@@ -0,0 +1,33 @@
+package orgsettingsimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionRead  = "orgs.settings:read"
+	ActionWrite = "orgs.settings:write"
+)
+
+var (
+	settingsReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:orgs.settings:reader",
+		DisplayName: "Organization settings reader",
+		Description: "Read the configuration overrides of the organizations",
+		Group:       "Organizations",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+		},
+	}
+
+	settingsWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:orgs.settings:writer",
+		DisplayName: "Organization settings writer",
+		Description: "Read, override and reset the configuration of the organizations",
+		Group:       "Organizations",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+			{Action: ActionWrite},
+		},
+	}
+)
@@ -0,0 +1,172 @@
+package orgsettingsimpl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// cacheTTL bounds how long an instance can serve a stale value after
+// the setting was changed through another instance.
+const cacheTTL = 30 * time.Second
+
+type Service struct {
+	cfg           *setting.Cfg
+	store         store
+	cache         *localcache.CacheService
+	accessControl ac.AccessControl
+	log           log.Logger
+}
+
+var _ orgsettings.Service = (*Service)(nil)
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	s := &Service{
+		cfg:           cfg,
+		store:         &sqlStore{db: sql, now: time.Now},
+		cache:         localcache.New(cacheTTL, 2*cacheTTL),
+		accessControl: accessControl,
+		log:           log.New("orgsettings"),
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := accesscontrolService.DeclareFixedRoles(
+			ac.RoleRegistration{Role: settingsReaderRole, Grants: []string{ac.RoleGrafanaAdmin}},
+			ac.RoleRegistration{Role: settingsWriterRole, Grants: []string{ac.RoleGrafanaAdmin}},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+// GetString never fails: if the overrides cannot be loaded, the global value is used.
+func (s *Service) GetString(ctx context.Context, orgID int64, name orgsettings.Name) string {
+	def, ok := orgsettings.GetDefinition(name)
+	if !ok {
+		s.log.Warn("Unknown organization setting", "name", name)
+		return ""
+	}
+
+	overrides, err := s.getOverrides(ctx, orgID)
+	if err != nil {
+		s.log.Error("Failed to load organization settings, using global value", "orgId", orgID, "name", name, "error", err)
+		return def.Default(s.cfg)
+	}
+
+	if o, ok := overrides[name]; ok {
+		return o.Value
+	}
+	return def.Default(s.cfg)
+}
+
+func (s *Service) GetInt64(ctx context.Context, orgID int64, name orgsettings.Name) int64 {
+	value := s.GetString(ctx, orgID, name)
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		s.log.Error("Organization setting is not an integer", "orgId", orgID, "name", name, "value", value)
+	}
+	return v
+}
+
+func (s *Service) List(ctx context.Context, orgID int64) ([]orgsettings.SettingDTO, error) {
+	overrides, err := s.getOverrides(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]orgsettings.SettingDTO, 0, len(orgsettings.Definitions))
+	for _, def := range orgsettings.Definitions {
+		dto := orgsettings.SettingDTO{
+			Name:        def.Name,
+			Type:        def.Type,
+			Description: def.Description,
+			GlobalValue: def.Default(s.cfg),
+		}
+		dto.Value = dto.GlobalValue
+
+		if o, ok := overrides[def.Name]; ok {
+			updated := o.Updated
+			dto.Value = o.Value
+			dto.Overridden = true
+			dto.Updated = &updated
+			dto.UpdatedBy = o.UpdatedBy
+		}
+		result = append(result, dto)
+	}
+
+	return result, nil
+}
+
+func (s *Service) Set(ctx context.Context, cmd *orgsettings.SetCommand) error {
+	def, ok := orgsettings.GetDefinition(cmd.Name)
+	if !ok {
+		return orgsettings.ErrUnknownSetting.Errorf("setting %s cannot be overridden per organization", cmd.Name)
+	}
+	if err := def.Validate(cmd.Value); err != nil {
+		return err
+	}
+
+	if err := s.store.Set(ctx, cmd); err != nil {
+		return err
+	}
+	s.cache.Delete(cacheKey(cmd.OrgID))
+
+	s.log.Info("Organization setting overridden", "orgId", cmd.OrgID, "name", cmd.Name, "userId", cmd.UpdatedBy)
+	return nil
+}
+
+func (s *Service) Delete(ctx context.Context, orgID int64, name orgsettings.Name) error {
+	if _, ok := orgsettings.GetDefinition(name); !ok {
+		return orgsettings.ErrUnknownSetting.Errorf("setting %s cannot be overridden per organization", name)
+	}
+
+	if err := s.store.Delete(ctx, orgID, name); err != nil {
+		return err
+	}
+	s.cache.Delete(cacheKey(orgID))
+
+	s.log.Info("Organization setting reset", "orgId", orgID, "name", name)
+	return nil
+}
+
+func (s *Service) getOverrides(ctx context.Context, orgID int64) (map[orgsettings.Name]orgsettings.OrgSetting, error) {
+	if cached, ok := s.cache.Get(cacheKey(orgID)); ok {
+		return cached.(map[orgsettings.Name]orgsettings.OrgSetting), nil
+	}
+
+	settings, err := s.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[orgsettings.Name]orgsettings.OrgSetting, len(settings))
+	for _, o := range settings {
+		overrides[o.Name] = o
+	}
+	s.cache.Set(cacheKey(orgID), overrides, cacheTTL)
+
+	return overrides, nil
+}
+
+func cacheKey(orgID int64) string {
+	return fmt.Sprintf("orgsettings-%d", orgID)
+}
@@ -0,0 +1,79 @@
+package orgsettingsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationOrgSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.DefaultTheme = "light"
+	cfg.ApiKeyMaxSecondsToLive = -1
+
+	s := &Service{
+		cfg:   cfg,
+		store: &sqlStore{db: testDB, now: time.Now},
+		cache: localcache.New(cacheTTL, 2*cacheTTL),
+		log:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	t.Run("should fall back to the global value", func(t *testing.T) {
+		require.Equal(t, "light", s.GetString(ctx, 1, orgsettings.DefaultTheme))
+		require.Equal(t, int64(-1), s.GetInt64(ctx, 1, orgsettings.APIKeyMaxSecondsToLive))
+	})
+
+	t.Run("should override settings per org", func(t *testing.T) {
+		err := s.Set(ctx, &orgsettings.SetCommand{OrgID: 1, Name: orgsettings.DefaultTheme, Value: "dark", UpdatedBy: 2})
+		require.NoError(t, err)
+		err = s.Set(ctx, &orgsettings.SetCommand{OrgID: 1, Name: orgsettings.APIKeyMaxSecondsToLive, Value: "3600"})
+		require.NoError(t, err)
+
+		require.Equal(t, "dark", s.GetString(ctx, 1, orgsettings.DefaultTheme))
+		require.Equal(t, int64(3600), s.GetInt64(ctx, 1, orgsettings.APIKeyMaxSecondsToLive))
+		require.Equal(t, "light", s.GetString(ctx, 2, orgsettings.DefaultTheme))
+
+		settings, err := s.List(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, settings, len(orgsettings.Definitions))
+		for _, setting := range settings {
+			if setting.Name == orgsettings.DefaultTheme {
+				require.True(t, setting.Overridden)
+				require.Equal(t, "dark", setting.Value)
+				require.Equal(t, "light", setting.GlobalValue)
+				require.Equal(t, int64(2), setting.UpdatedBy)
+			}
+		}
+	})
+
+	t.Run("should validate the values", func(t *testing.T) {
+		err := s.Set(ctx, &orgsettings.SetCommand{OrgID: 1, Name: orgsettings.DefaultTheme, Value: "pink"})
+		require.ErrorIs(t, err, orgsettings.ErrInvalidValue)
+		err = s.Set(ctx, &orgsettings.SetCommand{OrgID: 1, Name: orgsettings.APIKeyMaxSecondsToLive, Value: "forever"})
+		require.ErrorIs(t, err, orgsettings.ErrInvalidValue)
+		err = s.Set(ctx, &orgsettings.SetCommand{OrgID: 1, Name: "server.http_port", Value: "80"})
+		require.ErrorIs(t, err, orgsettings.ErrUnknownSetting)
+	})
+
+	t.Run("should reset an override", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, 1, orgsettings.DefaultTheme))
+		require.Equal(t, "light", s.GetString(ctx, 1, orgsettings.DefaultTheme))
+
+		err := s.Delete(ctx, 1, orgsettings.DefaultTheme)
+		require.ErrorIs(t, err, orgsettings.ErrNotOverridden)
+	})
+}
@@ -0,0 +1,73 @@
+package orgsettingsimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+)
+
+type store interface {
+	List(ctx context.Context, orgID int64) ([]orgsettings.OrgSetting, error)
+	Set(ctx context.Context, cmd *orgsettings.SetCommand) error
+	Delete(ctx context.Context, orgID int64, name orgsettings.Name) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) List(ctx context.Context, orgID int64) ([]orgsettings.OrgSetting, error) {
+	settings := make([]orgsettings.OrgSetting, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", orgID).Asc("name").Find(&settings)
+	})
+	return settings, err
+}
+
+func (s *sqlStore) Set(ctx context.Context, cmd *orgsettings.SetCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := orgsettings.OrgSetting{}
+		has, err := sess.Where("org_id = ? AND name = ?", cmd.OrgID, cmd.Name).Get(&existing)
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		setting := orgsettings.OrgSetting{
+			OrgID:     cmd.OrgID,
+			Name:      cmd.Name,
+			Value:     cmd.Value,
+			Created:   now,
+			Updated:   now,
+			UpdatedBy: cmd.UpdatedBy,
+		}
+
+		if !has {
+			_, err = sess.Insert(&setting)
+			return err
+		}
+
+		_, err = sess.ID(existing.ID).Cols("value", "updated", "updated_by").Update(&setting)
+		return err
+	})
+}
+
+func (s *sqlStore) Delete(ctx context.Context, orgID int64, name orgsettings.Name) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM org_setting WHERE org_id = ? AND name = ?", orgID, name)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return orgsettings.ErrNotOverridden.Errorf("setting %s is not overridden for org %d", name, orgID)
+		}
+		return nil
+	})
+}
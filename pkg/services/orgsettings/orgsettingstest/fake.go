@@ -0,0 +1,52 @@
+package orgsettingstest
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// FakeService resolves the settings from the configuration, unless they are set in Overrides
+type FakeService struct {
+	Cfg           *setting.Cfg
+	Overrides     map[orgsettings.Name]string
+	ExpectedError error
+}
+
+func NewFakeService(cfg *setting.Cfg) *FakeService {
+	return &FakeService{Cfg: cfg, Overrides: map[orgsettings.Name]string{}}
+}
+
+func (f *FakeService) GetString(ctx context.Context, orgID int64, name orgsettings.Name) string {
+	if v, ok := f.Overrides[name]; ok {
+		return v
+	}
+	if def, ok := orgsettings.GetDefinition(name); ok {
+		return def.Default(f.Cfg)
+	}
+	return ""
+}
+
+func (f *FakeService) GetInt64(ctx context.Context, orgID int64, name orgsettings.Name) int64 {
+	v, _ := strconv.ParseInt(f.GetString(ctx, orgID, name), 10, 64)
+	return v
+}
+
+func (f *FakeService) List(ctx context.Context, orgID int64) ([]orgsettings.SettingDTO, error) {
+	return []orgsettings.SettingDTO{}, f.ExpectedError
+}
+
+func (f *FakeService) Set(ctx context.Context, cmd *orgsettings.SetCommand) error {
+	if f.ExpectedError != nil {
+		return f.ExpectedError
+	}
+	f.Overrides[cmd.Name] = cmd.Value
+	return nil
+}
+
+func (f *FakeService) Delete(ctx context.Context, orgID int64, name orgsettings.Name) error {
+	delete(f.Overrides, name)
+	return f.ExpectedError
+}
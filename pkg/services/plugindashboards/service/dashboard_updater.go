@@ -38,6 +38,7 @@ func newDashboardUpdater(bus bus.Bus, pluginStore plugins.Store,
 		logger:                 log.New("plugindashboards"),
 	}
 	bus.AddEventListener(s.handlePluginStateChanged)
+	bus.AddEventListener(s.handlePluginInstalled)
 
 	return s
 }
@@ -159,6 +160,31 @@ func (du *DashboardUpdater) handlePluginStateChanged(ctx context.Context, event
 	return nil
 }
 
+// handlePluginInstalled syncs the dashboards of a plugin installed or upgraded
+// at runtime in every org where it is enabled.
+func (du *DashboardUpdater) handlePluginInstalled(ctx context.Context, event *pluginsettings.PluginInstalledEvent) error {
+	p, exists := du.pluginStore.Plugin(ctx, event.PluginId)
+	if !exists {
+		return fmt.Errorf("plugin %s not found. Could not sync plugin dashboards", event.PluginId)
+	}
+
+	pluginSettings, err := du.pluginSettingsService.GetPluginSettings(ctx, &pluginsettings.GetArgs{OrgID: 0})
+	if err != nil {
+		return err
+	}
+
+	for _, pluginSetting := range pluginSettings {
+		if pluginSetting.PluginID != p.ID || !pluginSetting.Enabled {
+			continue
+		}
+		if p.Info.Version != pluginSetting.PluginVersion {
+			du.syncPluginDashboards(ctx, p, pluginSetting.OrgID)
+		}
+	}
+
+	return nil
+}
+
 func (du *DashboardUpdater) autoUpdateAppDashboard(ctx context.Context, pluginDashInfo *plugindashboards.PluginDashboard, orgID int64) error {
 	req := &plugindashboards.LoadPluginDashboardRequest{
 		PluginID:  pluginDashInfo.PluginId,
@@ -338,6 +338,53 @@ func TestDashboardUpdater(t *testing.T) {
 			require.Equal(t, int64(0), ctx.importDashboardArgs[2].FolderId)
 			require.True(t, ctx.importDashboardArgs[2].Overwrite)
 		})
+
+	scenario(t, "When app plugin is installed at runtime with a new version should sync dashboards of orgs where it is enabled",
+		scenarioInput{
+			storedPluginSettings: []*pluginsettings.DTO{
+				{
+					PluginID:      "test",
+					Enabled:       true,
+					OrgID:         2,
+					PluginVersion: "1.0.0",
+				},
+				{
+					PluginID:      "test",
+					Enabled:       false,
+					OrgID:         3,
+					PluginVersion: "1.0.0",
+				},
+			},
+			installedPlugins: []plugins.PluginDTO{
+				{
+					JSONData: plugins.JSONData{
+						ID: "test",
+						Info: plugins.Info{
+							Version: "1.0.1",
+						},
+					},
+				},
+			},
+			pluginDashboards: []*plugindashboards.PluginDashboard{
+				{
+					DashboardId:      3,
+					PluginId:         "test",
+					Reference:        "updated.json",
+					Revision:         2,
+					ImportedRevision: 1,
+				},
+			},
+		}, func(ctx *scenarioContext) {
+			err := ctx.bus.Publish(context.Background(), &pluginsettings.PluginInstalledEvent{
+				PluginId: "test",
+			})
+			require.NoError(t, err)
+
+			require.Empty(t, ctx.dashboardService.deleteDashboardArgs)
+			require.Len(t, ctx.importDashboardArgs, 1)
+			require.Equal(t, "updated.json", ctx.importDashboardArgs[0].Path)
+			require.Equal(t, int64(2), ctx.importDashboardArgs[0].User.OrgID)
+		})
 }
 
 type pluginStoreMock struct {
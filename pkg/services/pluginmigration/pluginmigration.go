@@ -0,0 +1,93 @@
+package pluginmigration
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// Service migrates the dashboards of an organization from the deprecated panel and data source plugins to their
+// successors.
+type Service interface {
+	// Scan returns the deprecated panel types and the data sources of deprecated types used by the dashboards.
+	Scan(ctx context.Context, user *user.SignedInUser) (*ScanResult, error)
+	// Migrate rewrites the dashboards using the mapped panel types and data sources. Every migrated dashboard is
+	// saved as a new version, its previous version being the backup.
+	Migrate(ctx context.Context, user *user.SignedInUser, cmd *MigrateCommand) (*MigrateResult, error)
+}
+
+var ErrInvalidMigration = errutil.NewBase(errutil.StatusBadRequest, "pluginmigration.invalid-migration")
+
+// DeprecatedPanelTypes maps the deprecated panel plugins, most of them built with AngularJS, to the panels replacing them.
+var DeprecatedPanelTypes = map[string]string{
+	"graph":                    "timeseries",
+	"table-old":                "table",
+	"singlestat":               "stat",
+	"grafana-singlestat-panel": "stat",
+	"grafana-piechart-panel":   "piechart",
+	"grafana-worldmap-panel":   "geomap",
+	"natel-discrete-panel":     "state-timeline",
+}
+
+// DeprecatedDataSourceTypes maps the deprecated data source plugins to the data sources replacing them.
+var DeprecatedDataSourceTypes = map[string]string{
+	"camptocamp-prometheus-alertmanager-datasource": "alertmanager",
+	"grafana-simple-json-datasource":                "yesoreyeram-infinity-datasource",
+}
+
+type ScanResult struct {
+	Panels      []PanelTypeUsage  `json:"panels"`
+	DataSources []DataSourceUsage `json:"datasources"`
+}
+
+// PanelTypeUsage is a deprecated panel type used by dashboards.
+type PanelTypeUsage struct {
+	Type      string `json:"type"`
+	Successor string `json:"successor"`
+	// Count is the number of panels of the type.
+	Count         int      `json:"count"`
+	DashboardUIDs []string `json:"dashboardUids"`
+}
+
+// DataSourceUsage is a data source of a deprecated type, and the dashboards referencing it.
+type DataSourceUsage struct {
+	UID           string `json:"uid"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	SuccessorType string `json:"successorType"`
+	// Candidates are the UIDs of the data sources of the successor type that can replace the data source.
+	Candidates    []string `json:"candidates"`
+	DashboardUIDs []string `json:"dashboardUids"`
+}
+
+type MigrateCommand struct {
+	// PanelTypes maps the panel types to migrate to their successors, DeprecatedPanelTypes if it is not set.
+	PanelTypes map[string]string `json:"panelTypes"`
+	// DataSources maps the UIDs of the data sources to replace to the UIDs of their successors.
+	DataSources map[string]string `json:"datasources"`
+	// DashboardUIDs restricts the migration to some dashboards, all the dashboards are migrated if it is empty.
+	DashboardUIDs []string `json:"dashboardUids"`
+	// DryRun returns the changes without saving the dashboards.
+	DryRun bool `json:"dryRun"`
+}
+
+type MigrateResult struct {
+	DryRun     bool                 `json:"dryRun"`
+	Dashboards []DashboardMigration `json:"dashboards"`
+}
+
+// DashboardMigration is the result of the migration of a dashboard.
+type DashboardMigration struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+	// Panels is the number of panels whose type was changed.
+	Panels int `json:"panels"`
+	// DataSourceRefs is the number of panel, query, template variable and annotation data source references changed.
+	DataSourceRefs int `json:"datasourceRefs"`
+	// BackupVersion is the version of the dashboard before the migration, that can be restored.
+	BackupVersion int `json:"backupVersion"`
+	// Version is the version of the migrated dashboard, zero for dry runs and failed migrations.
+	Version int    `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
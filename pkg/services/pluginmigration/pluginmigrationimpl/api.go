@@ -0,0 +1,42 @@
+package pluginmigrationimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/pluginmigration"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Group("/api/admin/plugin-migrations", func(migrations routing.RouteRegister) {
+		migrations.Get("/scan", routing.Wrap(s.handleScan))
+		migrations.Post("/migrate", routing.Wrap(s.handleMigrate))
+	}, middleware.ReqGrafanaAdmin)
+}
+
+func (s *Service) handleScan(c *contextmodel.ReqContext) response.Response {
+	result, err := s.Scan(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to scan dashboards", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) handleMigrate(c *contextmodel.ReqContext) response.Response {
+	cmd := pluginmigration.MigrateCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	result, err := s.Migrate(c.Req.Context(), c.SignedInUser, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to migrate dashboards", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
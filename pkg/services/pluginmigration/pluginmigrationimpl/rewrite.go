@@ -0,0 +1,91 @@
+package pluginmigrationimpl
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+type dataSourceRef struct {
+	UID  string
+	Type string
+}
+
+// walkDashboard calls onPanel with the panels of the dashboard, including the panels of the collapsed rows, and
+// onRef with the keys holding a data source reference: the data source of the panels, of their queries, of the
+// template variables and of the annotations. The library panels are skipped, their model is only a reference.
+func walkDashboard(dashboard *simplejson.Json, onPanel func(panel *simplejson.Json), onRef func(parent *simplejson.Json, key string)) {
+	var walkPanels func(parent *simplejson.Json)
+	walkPanels = func(parent *simplejson.Json) {
+		for i := range parent.Get("panels").MustArray() {
+			panel := parent.Get("panels").GetIndex(i)
+			if _, ok := panel.CheckGet("libraryPanel"); ok {
+				continue
+			}
+			if panel.Get("type").MustString() == "row" {
+				walkPanels(panel)
+			} else {
+				onPanel(panel)
+			}
+			onRef(panel, "datasource")
+			for j := range panel.Get("targets").MustArray() {
+				onRef(panel.Get("targets").GetIndex(j), "datasource")
+			}
+		}
+	}
+	walkPanels(dashboard)
+
+	for _, section := range []string{"templating", "annotations"} {
+		list := dashboard.Get(section).Get("list")
+		for i := range list.MustArray() {
+			onRef(list.GetIndex(i), "datasource")
+		}
+	}
+}
+
+// refUID returns the UID of the data source referenced by a key, resolving the names of the references created
+// before Grafana 8.3.
+func refUID(parent *simplejson.Json, key string, uidsByName map[string]string) (string, bool) {
+	ref, ok := parent.CheckGet(key)
+	if !ok {
+		return "", false
+	}
+	if name, err := ref.String(); err == nil {
+		if uid, ok := uidsByName[name]; ok {
+			return uid, true
+		}
+		// the references to variables, e.g. ${ds}, and the references by UID
+		return name, name != ""
+	}
+	uid := ref.Get("uid").MustString()
+	return uid, uid != ""
+}
+
+// rewriter changes the panel types and the data source references of the dashboards.
+type rewriter struct {
+	panelTypes  map[string]string
+	dataSources map[string]dataSourceRef
+	uidsByName  map[string]string
+}
+
+// rewrite changes the dashboard in place, returning the number of panels and data source references changed. The
+// migrated panels keep the type they were migrated from in autoMigrateFrom, for the successor plugin to migrate
+// their options when the dashboard is loaded.
+func (r *rewriter) rewrite(dashboard *simplejson.Json) (panels int, refs int) {
+	walkDashboard(dashboard, func(panel *simplejson.Json) {
+		panelType := panel.Get("type").MustString()
+		if successor, ok := r.panelTypes[panelType]; ok {
+			panel.Set("type", successor)
+			panel.Set("autoMigrateFrom", panelType)
+			panels++
+		}
+	}, func(parent *simplejson.Json, key string) {
+		uid, ok := refUID(parent, key, r.uidsByName)
+		if !ok {
+			return
+		}
+		if successor, ok := r.dataSources[uid]; ok {
+			parent.Set(key, map[string]interface{}{"type": successor.Type, "uid": successor.UID})
+			refs++
+		}
+	})
+	return panels, refs
+}
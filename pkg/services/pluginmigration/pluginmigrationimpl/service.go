@@ -0,0 +1,248 @@
+package pluginmigrationimpl
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/pluginmigration"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// pageSize is the number of dashboards loaded at once.
+const pageSize = 100
+
+// migrationMessage is the message of the dashboard versions saved by the migrations.
+const migrationMessage = "Migrated deprecated plugins"
+
+type Service struct {
+	dashboardService  dashboards.DashboardService
+	dataSourceService datasources.DataSourceService
+	log               log.Logger
+}
+
+var _ pluginmigration.Service = (*Service)(nil)
+
+func ProvideService(
+	dashboardService dashboards.DashboardService,
+	dataSourceService datasources.DataSourceService,
+	routeRegister routing.RouteRegister,
+) *Service {
+	s := &Service{
+		dashboardService:  dashboardService,
+		dataSourceService: dataSourceService,
+		log:               log.New("pluginmigration"),
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s
+}
+
+func (s *Service) Scan(ctx context.Context, u *user.SignedInUser) (*pluginmigration.ScanResult, error) {
+	dataSources, err := s.dataSourceService.GetDataSources(ctx, &datasources.GetDataSourcesQuery{OrgID: u.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	uidsByName := make(map[string]string, len(dataSources))
+	deprecated := map[string]*pluginmigration.DataSourceUsage{}
+	for _, ds := range dataSources {
+		uidsByName[ds.Name] = ds.UID
+		if successor, ok := pluginmigration.DeprecatedDataSourceTypes[ds.Type]; ok {
+			deprecated[ds.UID] = &pluginmigration.DataSourceUsage{UID: ds.UID, Name: ds.Name, Type: ds.Type, SuccessorType: successor, Candidates: []string{}, DashboardUIDs: []string{}}
+		}
+	}
+	for _, ds := range dataSources {
+		for _, usage := range deprecated {
+			if ds.Type == usage.SuccessorType {
+				usage.Candidates = append(usage.Candidates, ds.UID)
+			}
+		}
+	}
+
+	panels := map[string]*pluginmigration.PanelTypeUsage{}
+	err = s.forEachDashboard(ctx, u, nil, dashboards.PERMISSION_VIEW, func(dash *dashboards.Dashboard) error {
+		panelDashboards := map[string]bool{}
+		dataSourceDashboards := map[string]bool{}
+		walkDashboard(dash.Data, func(panel *simplejson.Json) {
+			panelType := panel.Get("type").MustString()
+			successor, ok := pluginmigration.DeprecatedPanelTypes[panelType]
+			if !ok {
+				return
+			}
+			usage, ok := panels[panelType]
+			if !ok {
+				usage = &pluginmigration.PanelTypeUsage{Type: panelType, Successor: successor, DashboardUIDs: []string{}}
+				panels[panelType] = usage
+			}
+			usage.Count++
+			if !panelDashboards[panelType] {
+				panelDashboards[panelType] = true
+				usage.DashboardUIDs = append(usage.DashboardUIDs, dash.UID)
+			}
+		}, func(parent *simplejson.Json, key string) {
+			uid, ok := refUID(parent, key, uidsByName)
+			if !ok {
+				return
+			}
+			if usage, ok := deprecated[uid]; ok && !dataSourceDashboards[uid] {
+				dataSourceDashboards[uid] = true
+				usage.DashboardUIDs = append(usage.DashboardUIDs, dash.UID)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pluginmigration.ScanResult{
+		Panels:      make([]pluginmigration.PanelTypeUsage, 0, len(panels)),
+		DataSources: make([]pluginmigration.DataSourceUsage, 0, len(deprecated)),
+	}
+	for _, usage := range panels {
+		result.Panels = append(result.Panels, *usage)
+	}
+	sort.Slice(result.Panels, func(i, j int) bool { return result.Panels[i].Type < result.Panels[j].Type })
+	for _, usage := range deprecated {
+		result.DataSources = append(result.DataSources, *usage)
+	}
+	sort.Slice(result.DataSources, func(i, j int) bool { return result.DataSources[i].Name < result.DataSources[j].Name })
+
+	return result, nil
+}
+
+func (s *Service) Migrate(ctx context.Context, u *user.SignedInUser, cmd *pluginmigration.MigrateCommand) (*pluginmigration.MigrateResult, error) {
+	r, err := s.newRewriter(ctx, u.OrgID, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pluginmigration.MigrateResult{DryRun: cmd.DryRun, Dashboards: []pluginmigration.DashboardMigration{}}
+	err = s.forEachDashboard(ctx, u, cmd.DashboardUIDs, dashboards.PERMISSION_EDIT, func(dash *dashboards.Dashboard) error {
+		panels, refs := r.rewrite(dash.Data)
+		if panels+refs == 0 {
+			return nil
+		}
+
+		migration := pluginmigration.DashboardMigration{UID: dash.UID, Title: dash.Title, Panels: panels, DataSourceRefs: refs, BackupVersion: dash.Version}
+		if !cmd.DryRun {
+			// the provisioned dashboards are not migrated, they would be overwritten by the provisioning
+			saved, err := s.dashboardService.SaveDashboard(ctx, &dashboards.SaveDashboardDTO{
+				OrgID:     u.OrgID,
+				User:      u,
+				Message:   migrationMessage,
+				Dashboard: dash,
+			}, false)
+			if err != nil {
+				s.log.Warn("Failed to migrate dashboard", "dashboardUid", dash.UID, "error", err)
+				migration.Error = err.Error()
+			} else {
+				migration.Version = saved.Version
+				s.log.Info("Dashboard migrated", "dashboardUid", dash.UID, "panels", panels, "datasourceRefs", refs, "backupVersion", dash.Version)
+			}
+		}
+		result.Dashboards = append(result.Dashboards, migration)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) newRewriter(ctx context.Context, orgID int64, cmd *pluginmigration.MigrateCommand) (*rewriter, error) {
+	r := &rewriter{
+		panelTypes:  cmd.PanelTypes,
+		dataSources: make(map[string]dataSourceRef, len(cmd.DataSources)),
+		uidsByName:  make(map[string]string, len(cmd.DataSources)),
+	}
+	if r.panelTypes == nil {
+		r.panelTypes = pluginmigration.DeprecatedPanelTypes
+	}
+	for panelType, successor := range r.panelTypes {
+		if panelType == "" || successor == "" || panelType == successor {
+			return nil, pluginmigration.ErrInvalidMigration.Errorf("invalid migration of panel type %q to %q", panelType, successor)
+		}
+	}
+
+	for uid, successorUID := range cmd.DataSources {
+		if uid == successorUID {
+			return nil, pluginmigration.ErrInvalidMigration.Errorf("data source %s cannot replace itself", uid)
+		}
+		ds, err := s.getDataSource(ctx, orgID, uid)
+		if err != nil {
+			return nil, err
+		}
+		successor, err := s.getDataSource(ctx, orgID, successorUID)
+		if err != nil {
+			return nil, err
+		}
+		r.dataSources[ds.UID] = dataSourceRef{UID: successor.UID, Type: successor.Type}
+		r.uidsByName[ds.Name] = ds.UID
+	}
+
+	if len(r.panelTypes)+len(r.dataSources) == 0 {
+		return nil, pluginmigration.ErrInvalidMigration.Errorf("no panel type nor data source to migrate")
+	}
+	return r, nil
+}
+
+func (s *Service) getDataSource(ctx context.Context, orgID int64, uid string) (*datasources.DataSource, error) {
+	ds, err := s.dataSourceService.GetDataSource(ctx, &datasources.GetDataSourceQuery{OrgID: orgID, UID: uid})
+	if err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return nil, pluginmigration.ErrInvalidMigration.Errorf("data source %s not found", uid)
+		}
+		return nil, err
+	}
+	return ds, nil
+}
+
+// forEachDashboard calls fn with the dashboards of the organization that the user has the permission on, or with
+// the dashboards of the given UIDs.
+func (s *Service) forEachDashboard(ctx context.Context, u *user.SignedInUser, uids []string, permission dashboards.PermissionType, fn func(dash *dashboards.Dashboard) error) error {
+	for page := int64(1); ; page++ {
+		query := &dashboards.FindPersistedDashboardsQuery{
+			OrgId:         u.OrgID,
+			SignedInUser:  u,
+			DashboardUIDs: uids,
+			Type:          string(model.DashHitDB),
+			Permission:    permission,
+			Limit:         pageSize,
+			Page:          page,
+		}
+		if err := s.dashboardService.SearchDashboards(ctx, query); err != nil {
+			return err
+		}
+		if len(query.Result) == 0 {
+			return nil
+		}
+
+		pageUIDs := make([]string, 0, len(query.Result))
+		for _, hit := range query.Result {
+			pageUIDs = append(pageUIDs, hit.UID)
+		}
+		dashes, err := s.dashboardService.GetDashboards(ctx, &dashboards.GetDashboardsQuery{OrgID: u.OrgID, DashboardUIDs: pageUIDs})
+		if err != nil {
+			return err
+		}
+		for _, dash := range dashes {
+			if err := fn(dash); err != nil {
+				return err
+			}
+		}
+
+		if len(query.Result) < pageSize {
+			return nil
+		}
+	}
+}
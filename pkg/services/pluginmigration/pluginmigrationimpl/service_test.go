@@ -0,0 +1,169 @@
+package pluginmigrationimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakedatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/pluginmigration"
+	"github.com/grafana/grafana/pkg/services/search/model"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const deprecatedDashboard = `{
+	"uid": "deprecated",
+	"title": "Deprecated",
+	"version": 3,
+	"panels": [
+		{"id": 1, "type": "graph", "datasource": "Old alerts", "targets": [{"refId": "A", "datasource": {"type": "camptocamp-prometheus-alertmanager-datasource", "uid": "old"}}]},
+		{"id": 2, "type": "row", "collapsed": true, "panels": [
+			{"id": 3, "type": "singlestat", "datasource": {"type": "prometheus", "uid": "prom"}}
+		]},
+		{"id": 4, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom"}},
+		{"id": 5, "libraryPanel": {"uid": "lib", "name": "Library panel"}}
+	],
+	"templating": {"list": [{"name": "receiver", "type": "query", "datasource": {"uid": "old"}}]},
+	"annotations": {"list": [{"name": "Annotations & Alerts", "datasource": {"type": "grafana", "uid": "-- Grafana --"}}]}
+}`
+
+const upToDateDashboard = `{
+	"uid": "up-to-date",
+	"title": "Up to date",
+	"version": 1,
+	"panels": [{"id": 1, "type": "timeseries", "datasource": {"type": "prometheus", "uid": "prom"}}]
+}`
+
+func setupService(t *testing.T) (*Service, *dashboards.FakeDashboardService) {
+	t.Helper()
+
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("SearchDashboards", mock.Anything, mock.MatchedBy(func(q *dashboards.FindPersistedDashboardsQuery) bool {
+		return q.Page == 1 && q.Type == string(model.DashHitDB)
+	})).Run(func(args mock.Arguments) {
+		q := args.Get(1).(*dashboards.FindPersistedDashboardsQuery)
+		q.Result = model.HitList{{UID: "deprecated"}, {UID: "up-to-date"}}
+	}).Return(nil).Maybe()
+	// the dashboards are loaded for every test, as they are modified by the migrations
+	dashboardService.On("GetDashboards", mock.Anything, mock.Anything).Return(func(context.Context, *dashboards.GetDashboardsQuery) []*dashboards.Dashboard {
+		var dashes []*dashboards.Dashboard
+		for _, model := range []string{deprecatedDashboard, upToDateDashboard} {
+			data, err := simplejson.NewJson([]byte(model))
+			require.NoError(t, err)
+			dashes = append(dashes, dashboards.NewDashboardFromJson(data))
+		}
+		return dashes
+	}, nil).Maybe()
+
+	s := &Service{
+		dashboardService: dashboardService,
+		dataSourceService: &fakedatasources.FakeDataSourceService{DataSources: []*datasources.DataSource{
+			{OrgID: 1, UID: "old", Name: "Old alerts", Type: "camptocamp-prometheus-alertmanager-datasource"},
+			{OrgID: 1, UID: "new", Name: "Alerts", Type: "alertmanager"},
+			{OrgID: 1, UID: "prom", Name: "Prometheus", Type: "prometheus"},
+		}},
+		log: log.NewNopLogger(),
+	}
+	return s, dashboardService
+}
+
+func TestScan(t *testing.T) {
+	s, _ := setupService(t)
+
+	result, err := s.Scan(context.Background(), &user.SignedInUser{OrgID: 1})
+	require.NoError(t, err)
+	require.Equal(t, &pluginmigration.ScanResult{
+		Panels: []pluginmigration.PanelTypeUsage{
+			{Type: "graph", Successor: "timeseries", Count: 1, DashboardUIDs: []string{"deprecated"}},
+			{Type: "singlestat", Successor: "stat", Count: 1, DashboardUIDs: []string{"deprecated"}},
+		},
+		DataSources: []pluginmigration.DataSourceUsage{
+			{UID: "old", Name: "Old alerts", Type: "camptocamp-prometheus-alertmanager-datasource", SuccessorType: "alertmanager", Candidates: []string{"new"}, DashboardUIDs: []string{"deprecated"}},
+		},
+	}, result)
+}
+
+func TestMigrate(t *testing.T) {
+	u := &user.SignedInUser{OrgID: 1, UserID: 2}
+	cmd := func(dryRun bool) *pluginmigration.MigrateCommand {
+		return &pluginmigration.MigrateCommand{DataSources: map[string]string{"old": "new"}, DryRun: dryRun}
+	}
+
+	t.Run("should not save the dashboards on dry runs", func(t *testing.T) {
+		s, dashboardService := setupService(t)
+
+		result, err := s.Migrate(context.Background(), u, cmd(true))
+		require.NoError(t, err)
+		require.Equal(t, &pluginmigration.MigrateResult{DryRun: true, Dashboards: []pluginmigration.DashboardMigration{
+			{UID: "deprecated", Title: "Deprecated", Panels: 2, DataSourceRefs: 3, BackupVersion: 3},
+		}}, result)
+		dashboardService.AssertNotCalled(t, "SaveDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should save the migrated dashboards", func(t *testing.T) {
+		s, dashboardService := setupService(t)
+		var saved *dashboards.Dashboard
+		dashboardService.On("SaveDashboard", mock.Anything, mock.MatchedBy(func(dto *dashboards.SaveDashboardDTO) bool {
+			return dto.OrgID == 1 && dto.User == u && dto.Message == migrationMessage && !dto.Overwrite
+		}), false).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard
+		}).Return(&dashboards.Dashboard{UID: "deprecated", Version: 4}, nil).Once()
+
+		result, err := s.Migrate(context.Background(), u, cmd(false))
+		require.NoError(t, err)
+		require.Equal(t, []pluginmigration.DashboardMigration{
+			{UID: "deprecated", Title: "Deprecated", Panels: 2, DataSourceRefs: 3, BackupVersion: 3, Version: 4},
+		}, result.Dashboards)
+
+		panels := saved.Data.Get("panels")
+		require.Equal(t, "timeseries", panels.GetIndex(0).Get("type").MustString())
+		require.Equal(t, "graph", panels.GetIndex(0).Get("autoMigrateFrom").MustString())
+		require.Equal(t, map[string]interface{}{"type": "alertmanager", "uid": "new"}, panels.GetIndex(0).Get("datasource").MustMap())
+		require.Equal(t, map[string]interface{}{"type": "alertmanager", "uid": "new"}, panels.GetIndex(0).Get("targets").GetIndex(0).Get("datasource").MustMap())
+		require.Equal(t, "stat", panels.GetIndex(1).Get("panels").GetIndex(0).Get("type").MustString())
+		require.Equal(t, "prom", panels.GetIndex(1).Get("panels").GetIndex(0).Get("datasource").Get("uid").MustString())
+		_, ok := panels.GetIndex(2).CheckGet("autoMigrateFrom")
+		require.False(t, ok)
+		require.Equal(t, "new", saved.Data.Get("templating").Get("list").GetIndex(0).Get("datasource").Get("uid").MustString())
+	})
+
+	t.Run("should report the dashboards that cannot be saved", func(t *testing.T) {
+		s, dashboardService := setupService(t)
+		dashboardService.On("SaveDashboard", mock.Anything, mock.Anything, false).Return(nil, dashboards.ErrDashboardCannotSaveProvisionedDashboard).Once()
+
+		result, err := s.Migrate(context.Background(), u, cmd(false))
+		require.NoError(t, err)
+		require.Len(t, result.Dashboards, 1)
+		require.Equal(t, dashboards.ErrDashboardCannotSaveProvisionedDashboard.Error(), result.Dashboards[0].Error)
+		require.Zero(t, result.Dashboards[0].Version)
+	})
+
+	t.Run("should only migrate the mapped panel types", func(t *testing.T) {
+		s, _ := setupService(t)
+
+		result, err := s.Migrate(context.Background(), u, &pluginmigration.MigrateCommand{PanelTypes: map[string]string{"singlestat": "gauge"}, DryRun: true})
+		require.NoError(t, err)
+		require.Equal(t, []pluginmigration.DashboardMigration{{UID: "deprecated", Title: "Deprecated", Panels: 1, BackupVersion: 3}}, result.Dashboards)
+	})
+
+	t.Run("should validate the migration", func(t *testing.T) {
+		s, _ := setupService(t)
+
+		for _, cmd := range []*pluginmigration.MigrateCommand{
+			{PanelTypes: map[string]string{}},
+			{PanelTypes: map[string]string{"graph": ""}},
+			{PanelTypes: map[string]string{"graph": "graph"}},
+			{DataSources: map[string]string{"old": "missing"}},
+			{DataSources: map[string]string{"old": "old"}},
+		} {
+			_, err := s.Migrate(context.Background(), u, cmd)
+			require.ErrorIs(t, err, pluginmigration.ErrInvalidMigration)
+		}
+	})
+}
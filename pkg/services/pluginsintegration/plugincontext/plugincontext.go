@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/adapters"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -133,7 +134,7 @@ func (p *Provider) InvalidateSettingsCache(_ context.Context, pluginID string) {
 
 func (p *Provider) decryptSecureJsonDataFn(ctx context.Context) func(ds *datasources.DataSource) (map[string]string, error) {
 	return func(ds *datasources.DataSource) (map[string]string, error) {
-		return p.dataSourceService.DecryptedValues(ctx, ds)
+		return p.dataSourceService.DecryptedValues(secrets.WithConsumer(ctx, "plugins"), ds)
 	}
 }
 
@@ -0,0 +1,26 @@
+package pluginsecrets
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrSecretNotFound    = errutil.NewBase(errutil.StatusNotFound, "pluginsecrets.notFound", errutil.WithPublicMessage("Plugin secret not found"))
+	ErrInvalidSecretName = errutil.NewBase(errutil.StatusBadRequest, "pluginsecrets.invalidName",
+		errutil.WithPublicMessage("Secret names must be 1 to 100 letters, digits, dots, dashes or underscores"))
+)
+
+// Service stores the secrets of backend plugins, encrypted by the secrets service of Grafana.
+// The secrets of a plugin are scoped to the plugin and the organization.
+type Service interface {
+	// ListSecrets returns the names of the secrets of the plugin in the organization
+	ListSecrets(ctx context.Context, orgID int64, pluginID string) ([]string, error)
+	// GetSecret returns the decrypted value of a secret of the plugin
+	GetSecret(ctx context.Context, orgID int64, pluginID, name string) (string, error)
+	// SetSecret creates or updates a secret of the plugin
+	SetSecret(ctx context.Context, orgID int64, pluginID, name, value string) error
+	// DeleteSecret deletes a secret of the plugin
+	DeleteSecret(ctx context.Context, orgID int64, pluginID, name string) error
+}
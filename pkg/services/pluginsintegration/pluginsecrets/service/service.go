@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsecrets"
+	"github.com/grafana/grafana/pkg/services/secrets/kvstore"
+)
+
+// secretsType is the kvstore type of the secrets of the plugins, which are stored in a single
+// encrypted item per plugin and organization, namespaced by the plugin ID.
+const secretsType = "plugin-secrets"
+
+var secretNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,100}$`)
+
+var _ pluginsecrets.Service = (*Service)(nil)
+
+type Service struct {
+	kvStore kvstore.SecretsKVStore
+	// mu serializes the updates of the secrets of a plugin, which are read and written as a whole
+	mu     sync.Mutex
+	logger log.Logger
+}
+
+func ProvideService(kvStore kvstore.SecretsKVStore) *Service {
+	return &Service{
+		kvStore: kvStore,
+		logger:  log.New("pluginsecrets"),
+	}
+}
+
+func (s *Service) ListSecrets(ctx context.Context, orgID int64, pluginID string) ([]string, error) {
+	secrets, err := s.load(ctx, orgID, pluginID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Service) GetSecret(ctx context.Context, orgID int64, pluginID, name string) (string, error) {
+	secrets, err := s.load(ctx, orgID, pluginID)
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := secrets[name]
+	if !ok {
+		return "", pluginsecrets.ErrSecretNotFound.Errorf("plugin %s has no secret %s", pluginID, name)
+	}
+	return value, nil
+}
+
+func (s *Service) SetSecret(ctx context.Context, orgID int64, pluginID, name, value string) error {
+	if !secretNameRegex.MatchString(name) {
+		return pluginsecrets.ErrInvalidSecretName.Errorf("invalid secret name %q", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load(ctx, orgID, pluginID)
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return s.save(ctx, orgID, pluginID, secrets)
+}
+
+func (s *Service) DeleteSecret(ctx context.Context, orgID int64, pluginID, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load(ctx, orgID, pluginID)
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return pluginsecrets.ErrSecretNotFound.Errorf("plugin %s has no secret %s", pluginID, name)
+	}
+	delete(secrets, name)
+
+	if len(secrets) == 0 {
+		return s.kvStore.Del(ctx, orgID, pluginID, secretsType)
+	}
+	return s.save(ctx, orgID, pluginID, secrets)
+}
+
+func (s *Service) load(ctx context.Context, orgID int64, pluginID string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	value, exists, err := s.kvStore.Get(ctx, orgID, pluginID, secretsType)
+	if err != nil || !exists {
+		return secrets, err
+	}
+
+	if err := json.Unmarshal([]byte(value), &secrets); err != nil {
+		s.logger.Error("Failed to decode plugin secrets", "pluginId", pluginID, "orgId", orgID, "error", err)
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (s *Service) save(ctx context.Context, orgID int64, pluginID string, secrets map[string]string) error {
+	value, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	return s.kvStore.Set(ctx, orgID, pluginID, secretsType, string(value))
+}
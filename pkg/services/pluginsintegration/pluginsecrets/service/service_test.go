@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsecrets"
+	"github.com/grafana/grafana/pkg/services/secrets/kvstore"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	s := ProvideService(kvstore.NewFakeSecretsKVStore())
+
+	require.NoError(t, s.SetSecret(ctx, 1, "test-datasource", "api_key", "secret"))
+	require.NoError(t, s.SetSecret(ctx, 1, "test-datasource", "token", "other secret"))
+	require.NoError(t, s.SetSecret(ctx, 1, "test-datasource", "token", "updated secret"))
+
+	t.Run("Secrets can be listed and read", func(t *testing.T) {
+		names, err := s.ListSecrets(ctx, 1, "test-datasource")
+		require.NoError(t, err)
+		require.Equal(t, []string{"api_key", "token"}, names)
+
+		value, err := s.GetSecret(ctx, 1, "test-datasource", "token")
+		require.NoError(t, err)
+		require.Equal(t, "updated secret", value)
+	})
+
+	t.Run("Secrets are scoped by plugin and organization", func(t *testing.T) {
+		names, err := s.ListSecrets(ctx, 2, "test-datasource")
+		require.NoError(t, err)
+		require.Empty(t, names)
+
+		_, err = s.GetSecret(ctx, 1, "other-datasource", "api_key")
+		require.ErrorIs(t, err, pluginsecrets.ErrSecretNotFound)
+	})
+
+	t.Run("Invalid secret names are rejected", func(t *testing.T) {
+		err := s.SetSecret(ctx, 1, "test-datasource", "../key", "secret")
+		require.ErrorIs(t, err, pluginsecrets.ErrInvalidSecretName)
+	})
+
+	t.Run("Secrets can be deleted", func(t *testing.T) {
+		require.NoError(t, s.DeleteSecret(ctx, 1, "test-datasource", "api_key"))
+		require.ErrorIs(t, s.DeleteSecret(ctx, 1, "test-datasource", "api_key"), pluginsecrets.ErrSecretNotFound)
+
+		require.NoError(t, s.DeleteSecret(ctx, 1, "test-datasource", "token"))
+		names, err := s.ListSecrets(ctx, 1, "test-datasource")
+		require.NoError(t, err)
+		require.Empty(t, names)
+	})
+}
@@ -67,6 +67,15 @@ func (ps *FakePluginSettings) UpdatePluginSettingPluginVersion(ctx context.Conte
 	return ErrPluginSettingNotFound
 }
 
+// DeletePluginSetting deletes a Plugin Setting
+func (ps *FakePluginSettings) DeletePluginSetting(ctx context.Context, args *DeleteArgs) error {
+	if _, ok := ps.Plugins[args.PluginID]; ok {
+		delete(ps.Plugins, args.PluginID)
+		return nil
+	}
+	return ErrPluginSettingNotFound
+}
+
 // DecryptedValues decrypts the encrypted secureJSONData of the provided plugin setting and
 // returns the decrypted values.
 func (ps *FakePluginSettings) DecryptedValues(dto *DTO) map[string]string {
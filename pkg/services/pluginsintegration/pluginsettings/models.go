@@ -46,6 +46,11 @@ type UpdatePluginVersionArgs struct {
 	OrgID         int64
 }
 
+type DeleteArgs struct {
+	PluginID string
+	OrgID    int64
+}
+
 type GetArgs struct {
 	OrgID int64
 }
@@ -114,3 +119,8 @@ type PluginStateChangedEvent struct {
 	OrgId    int64
 	Enabled  bool
 }
+
+// PluginInstalledEvent is published when a plugin is installed or upgraded at runtime
+type PluginInstalledEvent struct {
+	PluginId string
+}
@@ -112,6 +112,32 @@ func (s *Service) UpdatePluginSettingPluginVersion(ctx context.Context, args *pl
 	})
 }
 
+func (s *Service) DeletePluginSetting(ctx context.Context, args *pluginsettings.DeleteArgs) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var pluginSetting pluginsettings.PluginSetting
+
+		exists, err := sess.Where("org_id=? and plugin_id=?", args.OrgID, args.PluginID).Get(&pluginSetting)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return pluginsettings.ErrPluginSettingNotFound
+		}
+
+		// add state change event on commit success
+		if pluginSetting.Enabled {
+			sess.PublishAfterCommit(&pluginsettings.PluginStateChangedEvent{
+				PluginId: args.PluginID,
+				OrgId:    args.OrgID,
+				Enabled:  false,
+			})
+		}
+
+		_, err = sess.ID(pluginSetting.Id).Delete(&pluginsettings.PluginSetting{})
+		return err
+	})
+}
+
 func (s *Service) DecryptedValues(ps *pluginsettings.DTO) map[string]string {
 	s.decryptionCache.Lock()
 	defer s.decryptionCache.Unlock()
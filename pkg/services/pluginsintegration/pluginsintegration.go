@@ -21,6 +21,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/clientmiddleware"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsecrets"
+	pluginSecrets "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsecrets/service"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
 	pluginSettings "github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings/service"
 	"github.com/grafana/grafana/pkg/setting"
@@ -57,6 +59,8 @@ var WireSet = wire.NewSet(
 	sources.ProvideService,
 	pluginSettings.ProvideService,
 	wire.Bind(new(pluginsettings.Service), new(*pluginSettings.Service)),
+	pluginSecrets.ProvideService,
+	wire.Bind(new(pluginsecrets.Service), new(*pluginSecrets.Service)),
 )
 
 // WireExtensionSet provides a wire.ProviderSet of plugin providers that can be
@@ -122,5 +122,11 @@ func (s *inmemStore) Update(ctx context.Context, preference *pref.Preference) er
 }
 
 func (s *inmemStore) DeleteByUser(ctx context.Context, userID int64) error {
-	panic("not yet implemented")
+	for key, p := range s.preference {
+		if key.UserID == userID {
+			delete(s.preference, key)
+			delete(s.idMap, p.ID)
+		}
+	}
+	return nil
 }
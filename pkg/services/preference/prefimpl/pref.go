@@ -3,24 +3,40 @@ package prefimpl
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// cacheTTL bounds how long an instance can serve stale resolved preferences after
+// the preferences were changed through another instance.
+const cacheTTL = 30 * time.Second
+
 type Service struct {
-	store    store
-	cfg      *setting.Cfg
-	features *featuremgmt.FeatureManager
+	store       store
+	cfg         *setting.Cfg
+	features    *featuremgmt.FeatureManager
+	orgSettings orgsettings.Service
+	cache       *localcache.CacheService
+	// generation is part of the cache keys and is incremented on every write,
+	// which invalidates all the resolved preferences cached by this instance.
+	generation atomic.Int64
 }
 
-func ProvideService(db db.DB, cfg *setting.Cfg, features *featuremgmt.FeatureManager) pref.Service {
+func ProvideService(db db.DB, cfg *setting.Cfg, features *featuremgmt.FeatureManager, orgSettings orgsettings.Service) pref.Service {
 	service := &Service{
-		cfg:      cfg,
-		features: features,
+		cfg:         cfg,
+		features:    features,
+		orgSettings: orgSettings,
+		cache:       localcache.New(cacheTTL, 2*cacheTTL),
 	}
 	if features.IsEnabled(featuremgmt.FlagNewDBLibrary) {
 		service.store = &sqlxStore{
@@ -34,7 +50,24 @@ func ProvideService(db db.DB, cfg *setting.Cfg, features *featuremgmt.FeatureMan
 	return service
 }
 
+// GetWithDefaults resolves the preferences of a user, the first preference set wins in the order
+// user, teams of the user, organization and instance defaults.
 func (s *Service) GetWithDefaults(ctx context.Context, query *pref.GetPreferenceWithDefaultsQuery) (*pref.Preference, error) {
+	key := s.cacheKey(query)
+	if cached, ok := s.cache.Get(key); ok {
+		return copyPreference(cached.(*pref.Preference)), nil
+	}
+
+	res, err := s.resolve(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(key, copyPreference(res), cacheTTL)
+	return res, nil
+}
+
+func (s *Service) resolve(ctx context.Context, query *pref.GetPreferenceWithDefaultsQuery) (*pref.Preference, error) {
 	listQuery := &pref.Preference{
 		Teams:  query.Teams,
 		OrgID:  query.OrgID,
@@ -47,6 +80,7 @@ func (s *Service) GetWithDefaults(ctx context.Context, query *pref.GetPreference
 	}
 
 	res := s.GetDefaults()
+	res.Theme = s.orgSettings.GetString(ctx, query.OrgID, orgsettings.DefaultTheme)
 	for _, p := range prefs {
 		if p.Theme != "" {
 			res.Theme = p.Theme
@@ -119,6 +153,7 @@ func (s *Service) Save(ctx context.Context, cmd *pref.SavePreferenceCommand) err
 				Updated:         time.Now(),
 				JSONData:        jsonData,
 			}
+			defer s.invalidateCache()
 			_, err = s.store.Insert(ctx, preference)
 			if err != nil {
 				return err
@@ -135,6 +170,7 @@ func (s *Service) Save(ctx context.Context, cmd *pref.SavePreferenceCommand) err
 	preference.HomeDashboardID = cmd.HomeDashboardID
 	preference.JSONData = jsonData
 
+	defer s.invalidateCache()
 	return s.store.Update(ctx, preference)
 }
 
@@ -208,6 +244,7 @@ func (s *Service) Patch(ctx context.Context, cmd *pref.PatchPreferenceCommand) e
 	preference.Updated = time.Now()
 	preference.Version += 1
 
+	defer s.invalidateCache()
 	if exists {
 		err = s.store.Update(ctx, preference)
 	} else {
@@ -233,9 +270,35 @@ func (s *Service) GetDefaults() *pref.Preference {
 }
 
 func (s *Service) DeleteByUser(ctx context.Context, userID int64) error {
+	defer s.invalidateCache()
 	return s.store.DeleteByUser(ctx, userID)
 }
 
+func (s *Service) cacheKey(query *pref.GetPreferenceWithDefaultsQuery) string {
+	teams := make([]int64, len(query.Teams))
+	copy(teams, query.Teams)
+	sort.Slice(teams, func(i, j int) bool { return teams[i] < teams[j] })
+	return fmt.Sprintf("preferences-%d-%d-%d-%v", s.generation.Load(), query.OrgID, query.UserID, teams)
+}
+
+func (s *Service) invalidateCache() {
+	s.generation.Add(1)
+}
+
+// copyPreference copies the fields of the resolved preferences callers may modify.
+func copyPreference(p *pref.Preference) *pref.Preference {
+	res := *p
+	if p.WeekStart != nil {
+		weekStart := *p.WeekStart
+		res.WeekStart = &weekStart
+	}
+	if p.JSONData != nil {
+		jsonData := *p.JSONData
+		res.JSONData = &jsonData
+	}
+	return &res
+}
+
 func parseCookiePreferences(prefs []pref.CookieType) (map[string]struct{}, error) {
 	allowed := map[pref.CookieType]struct{}{
 		"analytics":   {},
@@ -8,7 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	pref "github.com/grafana/grafana/pkg/services/preference"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -16,9 +19,11 @@ import (
 func TestGet_empty(t *testing.T) {
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 	preference, err := prefService.Get(context.Background(), &pref.GetPreferenceQuery{})
 	require.NoError(t, err)
 	expected := &pref.Preference{}
@@ -30,9 +35,11 @@ func TestGet_empty(t *testing.T) {
 func TestGetDefaults(t *testing.T) {
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 	prefService.cfg.DefaultLanguage = "en-US"
 	prefService.cfg.DefaultTheme = "light"
 	prefService.cfg.DateFormats.DefaultTimezone = "UTC"
@@ -72,9 +79,11 @@ func TestGetDefaults(t *testing.T) {
 func TestGetDefaultsWithI18nFeatureFlag(t *testing.T) {
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(featuremgmt.FlagInternationalization),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 	weekStart := ""
 	prefService.cfg.DefaultLanguage = "en-US"
 	prefService.cfg.DefaultTheme = "light"
@@ -100,9 +109,11 @@ func TestGetDefaultsWithI18nFeatureFlag(t *testing.T) {
 func TestGetWithDefaults_withUserAndOrgPrefs(t *testing.T) {
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 	prefService.cfg.DefaultLanguage = "en-US"
 
 	weekStartOne := "1"
@@ -187,9 +198,11 @@ func TestGetDefaults_JSONData(t *testing.T) {
 	t.Run("users have precedence over org", func(t *testing.T) {
 		prefService := &Service{
 			store:    newFake(),
+			cache:    localcache.New(cacheTTL, 2*cacheTTL),
 			cfg:      setting.NewCfg(),
 			features: featuremgmt.WithFeatures(),
 		}
+		prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 
 		insertPrefs(t, prefService.store,
 			pref.Preference{
@@ -215,9 +228,11 @@ func TestGetDefaults_JSONData(t *testing.T) {
 	t.Run("user JSONData with missing language does not override org preference", func(t *testing.T) {
 		prefService := &Service{
 			store:    newFake(),
+			cache:    localcache.New(cacheTTL, 2*cacheTTL),
 			cfg:      setting.NewCfg(),
 			features: featuremgmt.WithFeatures(),
 		}
+		prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 
 		insertPrefs(t, prefService.store,
 			pref.Preference{
@@ -246,9 +261,11 @@ func TestGetDefaults_JSONData(t *testing.T) {
 	t.Run("teams have precedence over org and are read in ascending order", func(t *testing.T) {
 		prefService := &Service{
 			store:    newFake(),
+			cache:    localcache.New(cacheTTL, 2*cacheTTL),
 			cfg:      setting.NewCfg(),
 			features: featuremgmt.WithFeatures(),
 		}
+		prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 
 		insertPrefs(t, prefService.store,
 			pref.Preference{
@@ -284,9 +301,11 @@ func TestGetWithDefaults_teams(t *testing.T) {
 	weekStartTwo := "2"
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 	insertPrefs(t, prefService.store,
 		pref.Preference{
 			OrgID:           1,
@@ -331,9 +350,11 @@ func TestGetWithDefaults_teams(t *testing.T) {
 func TestPatch_toCreate(t *testing.T) {
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 
 	themeValue := "light"
 	err := prefService.Patch(context.Background(), &pref.PatchPreferenceCommand{
@@ -352,9 +373,11 @@ func TestPatch_toCreate(t *testing.T) {
 func TestSave(t *testing.T) {
 	prefService := &Service{
 		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
 		cfg:      setting.NewCfg(),
 		features: featuremgmt.WithFeatures(),
 	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
 
 	t.Run("insert", func(t *testing.T) {
 		err := prefService.Save(context.Background(),
@@ -437,3 +460,61 @@ func newFake() store {
 		nextID:     1,
 	}
 }
+
+func TestGetWithDefaults_orgSettings(t *testing.T) {
+	prefService := &Service{
+		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
+		cfg:      setting.NewCfg(),
+		features: featuremgmt.WithFeatures(),
+	}
+	prefService.cfg.DefaultTheme = "light"
+	orgSettings := orgsettingstest.NewFakeService(prefService.cfg)
+	orgSettings.Overrides[orgsettings.DefaultTheme] = "dark"
+	prefService.orgSettings = orgSettings
+
+	preference, err := prefService.GetWithDefaults(context.Background(), &pref.GetPreferenceWithDefaultsQuery{OrgID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "dark", preference.Theme)
+}
+
+func TestGetWithDefaults_cache(t *testing.T) {
+	prefService := &Service{
+		store:    newFake(),
+		cache:    localcache.New(cacheTTL, 2*cacheTTL),
+		cfg:      setting.NewCfg(),
+		features: featuremgmt.WithFeatures(),
+	}
+	prefService.orgSettings = orgsettingstest.NewFakeService(prefService.cfg)
+	ctx := context.Background()
+
+	require.NoError(t, prefService.Save(ctx, &pref.SavePreferenceCommand{OrgID: 1, TeamID: 2, Theme: "dark"}))
+
+	query := &pref.GetPreferenceWithDefaultsQuery{OrgID: 1, UserID: 1, Teams: []int64{3, 2}}
+	preferences, err := prefService.GetWithDefaults(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "dark", preferences.Theme)
+
+	t.Run("returns the cached preferences regardless of the order of the teams", func(t *testing.T) {
+		preferences.Theme = "modified"
+
+		preferences, err := prefService.GetWithDefaults(ctx, &pref.GetPreferenceWithDefaultsQuery{OrgID: 1, UserID: 1, Teams: []int64{2, 3}})
+		require.NoError(t, err)
+		assert.Equal(t, "dark", preferences.Theme)
+	})
+
+	t.Run("invalidates the cached preferences when the preferences change", func(t *testing.T) {
+		theme := "light"
+		require.NoError(t, prefService.Patch(ctx, &pref.PatchPreferenceCommand{OrgID: 1, UserID: 1, Theme: &theme}))
+
+		preferences, err := prefService.GetWithDefaults(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, "light", preferences.Theme)
+
+		require.NoError(t, prefService.DeleteByUser(ctx, 1))
+
+		preferences, err = prefService.GetWithDefaults(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, "dark", preferences.Theme)
+	})
+}
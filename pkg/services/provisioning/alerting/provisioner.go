@@ -20,13 +20,20 @@ type ProvisionerConfig struct {
 	TemplateService            provisioning.TemplateService
 }
 
-func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+func Provision(ctx context.Context, cfg ProvisionerConfig) (int, error) {
 	logger := log.New("provisioning.alerting")
 	cfgReader := newRulesConfigReader(logger)
 	files, err := cfgReader.readConfig(ctx, cfg.Path)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	if err := provision(ctx, logger, cfg, files); err != nil {
+		return 0, err
+	}
+	return countItems(files), nil
+}
+
+func provision(ctx context.Context, logger log.Logger, cfg ProvisionerConfig, files []*AlertingFile) error {
 	logger.Info("starting to provision alerting")
 	logger.Debug("read all alerting files", "file_count", len(files))
 	ruleProvisioner := NewAlertRuleProvisioner(
@@ -34,7 +41,7 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 		cfg.DashboardService,
 		cfg.DashboardProvService,
 		cfg.RuleService)
-	err = ruleProvisioner.Provision(ctx, files)
+	err := ruleProvisioner.Provision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("alert rules: %w", err)
 	}
@@ -77,3 +84,16 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	logger.Info("finished to provision alerting")
 	return nil
 }
+
+// countItems returns the number of rules, contact points, notification
+// policies, mute timings and templates defined in the files
+func countItems(files []*AlertingFile) int {
+	count := 0
+	for _, file := range files {
+		for _, group := range file.Groups {
+			count += len(group.Rules)
+		}
+		count += len(file.ContactPoints) + len(file.Policies) + len(file.MuteTimes) + len(file.Templates)
+	}
+	return count
+}
@@ -20,6 +20,7 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	GetProvisionedDashboardsCount() int
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
@@ -126,6 +127,15 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// GetProvisionedDashboardsCount returns the number of dashboard files found by all the readers.
+func (provider *Provisioner) GetProvisionedDashboardsCount() int {
+	count := 0
+	for _, reader := range provider.fileReaders {
+		count += reader.getFilesCount()
+	}
+	return count
+}
+
 func getFileReaders(
 	configs []*config, logger log.Logger, service dashboards.DashboardProvisioningService, store utils.DashboardStore,
 ) ([]*FileReader, error) {
@@ -67,3 +67,8 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// GetProvisionedDashboardsCount not implemented for mocks
+func (dpm *ProvisionerMock) GetProvisionedDashboardsCount() int {
+	return 0
+}
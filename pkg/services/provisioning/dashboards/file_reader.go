@@ -39,6 +39,7 @@ type FileReader struct {
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
+	filesCount              int
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
@@ -121,6 +122,7 @@ func (fr *FileReader) walkDisk(ctx context.Context) error {
 	defer fr.mux.Unlock()
 
 	fr.usageTracker = usageTracker
+	fr.filesCount = len(filesFoundOnDisk)
 	return nil
 }
 
@@ -131,6 +133,14 @@ func (fr *FileReader) changeWritePermissions(restrict bool) {
 	fr.dbWriteAccessRestricted = restrict
 }
 
+// getFilesCount returns the number of dashboard files found during the last walk
+func (fr *FileReader) getFilesCount() int {
+	fr.mux.RLock()
+	defer fr.mux.RUnlock()
+
+	return fr.filesCount
+}
+
 func (fr *FileReader) isDatabaseAccessRestricted() bool {
 	fr.mux.RLock()
 	defer fr.mux.RUnlock()
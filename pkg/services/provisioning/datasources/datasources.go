@@ -34,9 +34,10 @@ var (
 
 // Provision scans a directory for provisioning config files
 // and provisions the datasource in those files.
-func Provision(ctx context.Context, configDirectory string, store Store, correlationsStore CorrelationsStore, orgService org.Service) error {
+func Provision(ctx context.Context, configDirectory string, store Store, correlationsStore CorrelationsStore, orgService org.Service) (int, error) {
 	dc := newDatasourceProvisioner(log.New("provisioning.datasources"), store, correlationsStore, orgService)
-	return dc.applyChanges(ctx, configDirectory)
+	err := dc.applyChanges(ctx, configDirectory)
+	return dc.applied, err
 }
 
 // DatasourceProvisioner is responsible for provisioning datasources based on
@@ -46,6 +47,8 @@ type DatasourceProvisioner struct {
 	cfgProvider       *configReader
 	store             Store
 	correlationsStore CorrelationsStore
+	// applied is the number of data sources applied by the last call to applyChanges
+	applied int
 }
 
 func newDatasourceProvisioner(log log.Logger, store Store, correlationsStore CorrelationsStore, orgService org.Service) DatasourceProvisioner {
@@ -123,6 +126,7 @@ func (dc *DatasourceProvisioner) apply(ctx context.Context, cfg *configs) error
 }
 
 func (dc *DatasourceProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	dc.applied = 0
 	configs, err := dc.cfgProvider.readConfig(ctx, configPath)
 	if err != nil {
 		return err
@@ -132,6 +136,7 @@ func (dc *DatasourceProvisioner) applyChanges(ctx context.Context, configPath st
 		if err := dc.apply(ctx, cfg); err != nil {
 			return err
 		}
+		dc.applied += len(cfg.Datasources)
 	}
 
 	return nil
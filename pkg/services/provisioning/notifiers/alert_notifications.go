@@ -26,9 +26,10 @@ type Manager interface {
 }
 
 // Provision alert notifiers
-func Provision(ctx context.Context, configDirectory string, alertingService Manager, orgService org.Service, encryptionService encryption.Internal, notificationService *notifications.NotificationService) error {
+func Provision(ctx context.Context, configDirectory string, alertingService Manager, orgService org.Service, encryptionService encryption.Internal, notificationService *notifications.NotificationService) (int, error) {
 	dc := newNotificationProvisioner(orgService, alertingService, encryptionService, notificationService, log.New("provisioning.notifiers"))
-	return dc.applyChanges(ctx, configDirectory)
+	err := dc.applyChanges(ctx, configDirectory)
+	return dc.applied, err
 }
 
 // NotificationProvisioner is responsible for provsioning alert notifiers
@@ -37,6 +38,8 @@ type NotificationProvisioner struct {
 	cfgProvider     *configReader
 	alertingManager Manager
 	orgService      org.Service
+	// applied is the number of notifiers applied by the last call to applyChanges
+	applied int
 }
 
 func newNotificationProvisioner(orgService org.Service, alertingManager Manager, encryptionService encryption.Internal, notifiationService *notifications.NotificationService, log log.Logger) NotificationProvisioner {
@@ -161,6 +164,7 @@ func (dc *NotificationProvisioner) mergeNotifications(ctx context.Context, notif
 }
 
 func (dc *NotificationProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	dc.applied = 0
 	configs, err := dc.cfgProvider.readConfig(ctx, configPath)
 	if err != nil {
 		return err
@@ -170,6 +174,7 @@ func (dc *NotificationProvisioner) applyChanges(ctx context.Context, configPath
 		if err := dc.apply(ctx, cfg); err != nil {
 			return err
 		}
+		dc.applied += len(cfg.Notifications)
 	}
 
 	return nil
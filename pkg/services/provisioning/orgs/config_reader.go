@@ -0,0 +1,155 @@
+package orgs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type configReader interface {
+	readConfig(path string) ([]*orgsAsConfig, error)
+}
+
+type configReaderImpl struct {
+	log log.Logger
+}
+
+func newConfigReader(logger log.Logger) configReader {
+	return &configReaderImpl{log: logger}
+}
+
+func (cr *configReaderImpl) readConfig(path string) ([]*orgsAsConfig, error) {
+	var orgs []*orgsAsConfig
+	cr.log.Debug("Looking for org provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("Failed to read org provisioning files from directory", "path", path, "error", err)
+		return orgs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cr.log.Debug("Parsing org provisioning file", "path", path, "file.Name", file.Name())
+			cfg, err := cr.parseOrgConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				orgs = append(orgs, cfg)
+			}
+		}
+	}
+
+	cr.log.Debug("Validating orgs")
+	if err := validateOrgsConfig(orgs); err != nil {
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+func (cr *configReaderImpl) parseOrgConfig(path string, file fs.DirEntry) (*orgsAsConfig, error) {
+	filename, err := filepath.Abs(filepath.Join(path, file.Name()))
+	if err != nil {
+		return nil, err
+	}
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg *orgsAsConfigV1
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.mapToOrgsFromConfig(), nil
+}
+
+// validateOrgsConfig checks the required fields, the roles and the permissions, and that an organization is
+// neither provisioned twice nor both provisioned and deleted.
+func validateOrgsConfig(configs []*orgsAsConfig) error {
+	provisioned := map[string]bool{}
+	for _, cfg := range configs {
+		for index, o := range cfg.Orgs {
+			if o.Name == "" {
+				return fmt.Errorf("org item %d in configuration doesn't contain required field name", index+1)
+			}
+			if provisioned[o.Name] {
+				return fmt.Errorf("org %q is provisioned more than once", o.Name)
+			}
+			provisioned[o.Name] = true
+
+			if err := validateOrgConfig(o); err != nil {
+				return fmt.Errorf("org %q: %w", o.Name, err)
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for index, o := range cfg.DeleteOrgs {
+			if o.Name == "" {
+				return fmt.Errorf("deleteOrgs item %d in configuration doesn't contain required field name", index+1)
+			}
+			if provisioned[o.Name] {
+				return fmt.Errorf("org %q is both provisioned and deleted", o.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateOrgConfig(o *orgFromConfig) error {
+	for index, u := range o.Users {
+		if u.LoginOrEmail == "" {
+			return fmt.Errorf("user item %d doesn't contain required field loginOrEmail", index+1)
+		}
+		if !u.Role.IsValid() {
+			return fmt.Errorf("user %q has invalid role %q", u.LoginOrEmail, u.Role)
+		}
+	}
+
+	teams := map[string]bool{}
+	for index, t := range o.Teams {
+		if t.Name == "" {
+			return fmt.Errorf("team item %d doesn't contain required field name", index+1)
+		}
+		if teams[t.Name] {
+			return fmt.Errorf("team %q is provisioned more than once", t.Name)
+		}
+		teams[t.Name] = true
+
+		for memberIndex, m := range t.Members {
+			if m.LoginOrEmail == "" {
+				return fmt.Errorf("team %q: member item %d doesn't contain required field loginOrEmail", t.Name, memberIndex+1)
+			}
+			if m.Permission != "" && m.Permission != teamPermissionMember && m.Permission != teamPermissionAdmin {
+				return fmt.Errorf("team %q: member %q has invalid permission %q", t.Name, m.LoginOrEmail, m.Permission)
+			}
+		}
+	}
+
+	for index, t := range o.DeleteTeams {
+		if t.Name == "" {
+			return fmt.Errorf("deleteTeams item %d doesn't contain required field name", index+1)
+		}
+		if teams[t.Name] {
+			return fmt.Errorf("team %q is both provisioned and deleted", t.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	correctProperties     = "./testdata/test-configs/correct-properties"
+	brokenYaml            = "./testdata/test-configs/broken-yaml"
+	invalidRole           = "./testdata/test-configs/invalid-role"
+	provisionedAndDeleted = "./testdata/test-configs/provisioned-and-deleted"
+	missingFolder         = "./testdata/test-configs/missing"
+)
+
+func TestConfigReader(t *testing.T) {
+	t.Run("Broken yaml should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(brokenYaml)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip missing directory", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(missingFolder)
+		require.NoError(t, err)
+		require.Len(t, cfg, 0)
+	})
+
+	t.Run("Invalid role should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(invalidRole)
+		require.Error(t, err)
+		require.Equal(t, "org \"Engineering\": user \"alice\" has invalid role \"Owner\"", err.Error())
+	})
+
+	t.Run("Org both provisioned and deleted should return error", func(t *testing.T) {
+		reader := newConfigReader(log.New("test logger"))
+		_, err := reader.readConfig(provisionedAndDeleted)
+		require.Error(t, err)
+		require.Equal(t, "org \"Engineering\" is both provisioned and deleted", err.Error())
+	})
+
+	t.Run("Can read correct properties", func(t *testing.T) {
+		t.Setenv("ORG_NAME", "Engineering")
+
+		reader := newConfigReader(log.New("test logger"))
+		cfg, err := reader.readConfig(correctProperties)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+
+		require.Equal(t, []*deleteOrgConfig{{Name: "Legacy"}}, cfg[0].DeleteOrgs)
+		require.Equal(t, []*orgFromConfig{
+			{
+				Name: "Engineering",
+				Users: []*orgUserFromConfig{
+					{LoginOrEmail: "alice", Role: org.RoleAdmin},
+					{LoginOrEmail: "bob@example.com", Role: org.RoleViewer},
+				},
+				Teams: []*teamFromConfig{
+					{
+						Name:  "Backend",
+						Email: "backend@example.com",
+						Members: []*teamMemberFromConfig{
+							{LoginOrEmail: "alice", Permission: teamPermissionAdmin},
+							{LoginOrEmail: "bob@example.com"},
+						},
+					},
+				},
+				DeleteTeams: []*deleteTeamConfig{{Name: "Frontend"}},
+			},
+		}, cfg[0].Orgs)
+	})
+}
@@ -0,0 +1,246 @@
+package orgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Provision scans a directory for provisioning config files
+// and provisions the orgs, teams and memberships in those files.
+func Provision(ctx context.Context, configDirectory string, orgService org.Service, userService user.Service, teamService team.Service) (int, error) {
+	logger := log.New("provisioning.orgs")
+	op := OrgProvisioner{
+		log:         logger,
+		cfgProvider: newConfigReader(logger),
+		orgService:  orgService,
+		userService: userService,
+		teamService: teamService,
+	}
+	err := op.applyChanges(ctx, configDirectory)
+	return op.applied, err
+}
+
+// OrgProvisioner is responsible for provisioning orgs, teams and their
+// members based on configuration read by the `configReader`. The users
+// must already exist: the ones that don't are skipped until they sign in
+// and the configuration is applied again.
+type OrgProvisioner struct {
+	log         log.Logger
+	cfgProvider configReader
+	orgService  org.Service
+	userService user.Service
+	teamService team.Service
+	// applied is the number of orgs applied by the last call to applyChanges
+	applied int
+}
+
+func (op *OrgProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	op.applied = 0
+	configs, err := op.cfgProvider.readConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		if err := op.deleteOrgs(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, o := range cfg.Orgs {
+			if err := op.applyOrg(ctx, o); err != nil {
+				return fmt.Errorf("org %q: %w", o.Name, err)
+			}
+			op.applied++
+		}
+	}
+
+	return nil
+}
+
+func (op *OrgProvisioner) deleteOrgs(ctx context.Context, cfg *orgsAsConfig) error {
+	for _, o := range cfg.DeleteOrgs {
+		res, err := op.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: o.Name})
+		if err != nil {
+			if errors.Is(err, org.ErrOrgNotFound) {
+				continue
+			}
+			return err
+		}
+		if res.ID == 1 {
+			return fmt.Errorf("org %q is the main org and can't be deleted", o.Name)
+		}
+
+		op.log.Info("Deleting org from configuration", "name", o.Name, "id", res.ID)
+		if err := op.orgService.Delete(ctx, &org.DeleteOrgCommand{ID: res.ID}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (op *OrgProvisioner) applyOrg(ctx context.Context, o *orgFromConfig) error {
+	users := make(map[string]int64, len(o.Users))
+	var adminID int64
+	for _, u := range o.Users {
+		userID, ok, err := op.getUserID(ctx, u.LoginOrEmail)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			op.log.Warn("Skipping org user that doesn't exist", "org", o.Name, "user", u.LoginOrEmail)
+			continue
+		}
+		users[u.LoginOrEmail] = userID
+		if adminID == 0 && u.Role == org.RoleAdmin {
+			adminID = userID
+		}
+	}
+
+	orgID, err := op.getOrCreateOrg(ctx, o.Name, adminID)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range o.Users {
+		userID, ok := users[u.LoginOrEmail]
+		if !ok {
+			continue
+		}
+		if err := op.applyOrgUser(ctx, orgID, userID, u.Role); err != nil {
+			return fmt.Errorf("user %q: %w", u.LoginOrEmail, err)
+		}
+	}
+
+	for _, t := range o.DeleteTeams {
+		teamID, ok, err := op.getTeamID(ctx, orgID, t.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		op.log.Info("Deleting team from configuration", "org", o.Name, "team", t.Name)
+		if err := op.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: orgID, ID: teamID}); err != nil {
+			return fmt.Errorf("team %q: %w", t.Name, err)
+		}
+	}
+
+	for _, t := range o.Teams {
+		if err := op.applyTeam(ctx, orgID, t, users); err != nil {
+			return fmt.Errorf("team %q: %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// getOrCreateOrg returns the ID of the org, creating it with the admin as its first member if it doesn't exist.
+func (op *OrgProvisioner) getOrCreateOrg(ctx context.Context, name string, adminID int64) (int64, error) {
+	res, err := op.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: name})
+	if err == nil {
+		return res.ID, nil
+	}
+	if !errors.Is(err, org.ErrOrgNotFound) {
+		return 0, err
+	}
+
+	op.log.Info("Creating org from configuration", "name", name)
+	created, err := op.orgService.CreateWithMember(ctx, &org.CreateOrgCommand{Name: name, UserID: adminID})
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// getUserID returns the ID of the user, false if the user doesn't exist.
+func (op *OrgProvisioner) getUserID(ctx context.Context, loginOrEmail string) (int64, bool, error) {
+	usr, err := op.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: loginOrEmail})
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return usr.ID, true, nil
+}
+
+func (op *OrgProvisioner) applyOrgUser(ctx context.Context, orgID, userID int64, role org.RoleType) error {
+	err := op.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{OrgID: orgID, UserID: userID, Role: role})
+	if errors.Is(err, org.ErrOrgUserAlreadyAdded) {
+		return op.orgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{OrgID: orgID, UserID: userID, Role: role})
+	}
+	return err
+}
+
+// getTeamID returns the ID of the team of the org, false if the team doesn't exist.
+func (op *OrgProvisioner) getTeamID(ctx context.Context, orgID int64, name string) (int64, bool, error) {
+	res, err := op.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		UserIDFilter: team.FilterIgnoreUser,
+		SignedInUser: accesscontrol.BackgroundUser("orgs_provisioning", orgID, org.RoleAdmin, []accesscontrol.Permission{
+			{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		}),
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if len(res.Teams) == 0 {
+		return 0, false, nil
+	}
+	return res.Teams[0].ID, true, nil
+}
+
+// applyTeam creates or updates the team and adds its members, or updates their permission. users maps the
+// users of the org provisioned by the same configuration to their IDs.
+func (op *OrgProvisioner) applyTeam(ctx context.Context, orgID int64, t *teamFromConfig, users map[string]int64) error {
+	teamID, ok, err := op.getTeamID(ctx, orgID, t.Name)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := op.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{ID: teamID, OrgID: orgID, Name: t.Name, Email: t.Email}); err != nil {
+			return err
+		}
+	} else {
+		op.log.Info("Creating team from configuration", "orgId", orgID, "team", t.Name)
+		created, err := op.teamService.CreateTeam(t.Name, t.Email, orgID)
+		if err != nil {
+			return err
+		}
+		teamID = created.ID
+	}
+
+	for _, m := range t.Members {
+		userID, ok := users[m.LoginOrEmail]
+		if !ok {
+			op.log.Warn("Skipping team member that isn't a user of the org", "orgId", orgID, "team", t.Name, "user", m.LoginOrEmail)
+			continue
+		}
+
+		err := op.teamService.AddTeamMember(userID, orgID, teamID, false, m.permissionType())
+		if errors.Is(err, team.ErrTeamMemberAlreadyAdded) {
+			err = op.teamService.UpdateTeamMember(ctx, &team.UpdateTeamMemberCommand{
+				UserID:     userID,
+				OrgID:      orgID,
+				TeamID:     teamID,
+				Permission: m.permissionType(),
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("member %q: %w", m.LoginOrEmail, err)
+		}
+	}
+
+	return nil
+}
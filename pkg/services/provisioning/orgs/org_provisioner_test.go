@@ -0,0 +1,250 @@
+package orgs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestOrgProvisioner(t *testing.T) {
+	t.Run("Should return error when config reader returns error", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		op := OrgProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{err: expectedErr}}
+		err := op.applyChanges(context.Background(), "")
+		require.Equal(t, expectedErr, err)
+	})
+
+	t.Run("Should create orgs, teams and memberships", func(t *testing.T) {
+		cfg := []*orgsAsConfig{
+			{
+				Orgs: []*orgFromConfig{
+					{
+						Name: "Engineering",
+						Users: []*orgUserFromConfig{
+							{LoginOrEmail: "bob", Role: org.RoleViewer},
+							{LoginOrEmail: "alice", Role: org.RoleAdmin},
+							{LoginOrEmail: "unknown", Role: org.RoleEditor},
+						},
+						Teams: []*teamFromConfig{
+							{
+								Name:  "Backend",
+								Email: "backend@example.com",
+								Members: []*teamMemberFromConfig{
+									{LoginOrEmail: "alice", Permission: teamPermissionAdmin},
+									{LoginOrEmail: "bob"},
+									{LoginOrEmail: "unknown"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		orgs, users, teams := newFakeServices()
+		op := OrgProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{result: cfg}, orgService: orgs, userService: users, teamService: teams}
+
+		err := op.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+		require.Equal(t, 1, op.applied)
+
+		orgID := orgs.orgs["Engineering"]
+		require.NotZero(t, orgID)
+		require.Equal(t, int64(1), orgs.creators[orgID], "the org should be created with its first admin")
+		require.Equal(t, map[int64]org.RoleType{1: org.RoleAdmin, 2: org.RoleViewer}, orgs.members[orgID])
+
+		teamID := teams.teams[orgID]["Backend"]
+		require.NotZero(t, teamID)
+		require.Equal(t, "backend@example.com", teams.emails[teamID])
+		require.Equal(t, map[int64]dashboards.PermissionType{1: dashboards.PERMISSION_ADMIN, 2: 0}, teams.members[teamID])
+	})
+
+	t.Run("Should update existing orgs, teams and memberships", func(t *testing.T) {
+		orgs, users, teams := newFakeServices()
+		orgs.orgs["Engineering"] = 2
+		orgs.members[2] = map[int64]org.RoleType{1: org.RoleAdmin, 2: org.RoleEditor}
+		teams.teams[2] = map[string]int64{"Backend": 10, "Frontend": 11}
+		teams.members[10] = map[int64]dashboards.PermissionType{2: dashboards.PERMISSION_ADMIN}
+
+		cfg := []*orgsAsConfig{
+			{
+				Orgs: []*orgFromConfig{
+					{
+						Name:  "Engineering",
+						Users: []*orgUserFromConfig{{LoginOrEmail: "bob", Role: org.RoleViewer}},
+						Teams: []*teamFromConfig{
+							{Name: "Backend", Email: "team@example.com", Members: []*teamMemberFromConfig{{LoginOrEmail: "bob"}}},
+						},
+						DeleteTeams: []*deleteTeamConfig{{Name: "Frontend"}, {Name: "Unknown"}},
+					},
+				},
+				DeleteOrgs: []*deleteOrgConfig{{Name: "Legacy"}, {Name: "Unknown"}},
+			},
+		}
+		orgs.orgs["Legacy"] = 3
+		op := OrgProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{result: cfg}, orgService: orgs, userService: users, teamService: teams}
+
+		err := op.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{3}, orgs.deleted)
+		require.Empty(t, orgs.creators)
+		require.Equal(t, map[int64]org.RoleType{1: org.RoleAdmin, 2: org.RoleViewer}, orgs.members[2])
+		require.Equal(t, []int64{11}, teams.deleted)
+		require.Equal(t, "team@example.com", teams.emails[10])
+		require.Equal(t, map[int64]dashboards.PermissionType{2: 0}, teams.members[10])
+	})
+
+	t.Run("Should not delete the main org", func(t *testing.T) {
+		orgs, users, teams := newFakeServices()
+		orgs.orgs["Main Org."] = 1
+		cfg := []*orgsAsConfig{{DeleteOrgs: []*deleteOrgConfig{{Name: "Main Org."}}}}
+		op := OrgProvisioner{log: log.New("test"), cfgProvider: &testConfigReader{result: cfg}, orgService: orgs, userService: users, teamService: teams}
+
+		err := op.applyChanges(context.Background(), "")
+		require.EqualError(t, err, "org \"Main Org.\" is the main org and can't be deleted")
+		require.Empty(t, orgs.deleted)
+	})
+}
+
+type testConfigReader struct {
+	result []*orgsAsConfig
+	err    error
+}
+
+func (tcr *testConfigReader) readConfig(_ string) ([]*orgsAsConfig, error) {
+	return tcr.result, tcr.err
+}
+
+func newFakeServices() (*fakeOrgService, *fakeUserService, *fakeTeamService) {
+	return &fakeOrgService{
+			orgs:     map[string]int64{},
+			creators: map[int64]int64{},
+			members:  map[int64]map[int64]org.RoleType{},
+		},
+		&fakeUserService{users: map[string]int64{"alice": 1, "bob": 2}},
+		&fakeTeamService{
+			teams:   map[int64]map[string]int64{},
+			emails:  map[int64]string{},
+			members: map[int64]map[int64]dashboards.PermissionType{},
+		}
+}
+
+type fakeOrgService struct {
+	org.Service
+
+	orgs     map[string]int64
+	creators map[int64]int64
+	members  map[int64]map[int64]org.RoleType
+	deleted  []int64
+}
+
+func (f *fakeOrgService) GetByName(_ context.Context, query *org.GetOrgByNameQuery) (*org.Org, error) {
+	if id, ok := f.orgs[query.Name]; ok {
+		return &org.Org{ID: id, Name: query.Name}, nil
+	}
+	return nil, org.ErrOrgNotFound.Errorf("org not found")
+}
+
+func (f *fakeOrgService) CreateWithMember(_ context.Context, cmd *org.CreateOrgCommand) (*org.Org, error) {
+	id := int64(len(f.orgs) + 100)
+	f.orgs[cmd.Name] = id
+	f.creators[id] = cmd.UserID
+	f.members[id] = map[int64]org.RoleType{cmd.UserID: org.RoleAdmin}
+	return &org.Org{ID: id, Name: cmd.Name}, nil
+}
+
+func (f *fakeOrgService) Delete(_ context.Context, cmd *org.DeleteOrgCommand) error {
+	f.deleted = append(f.deleted, cmd.ID)
+	return nil
+}
+
+func (f *fakeOrgService) AddOrgUser(_ context.Context, cmd *org.AddOrgUserCommand) error {
+	if _, ok := f.members[cmd.OrgID][cmd.UserID]; ok {
+		return org.ErrOrgUserAlreadyAdded
+	}
+	if f.members[cmd.OrgID] == nil {
+		f.members[cmd.OrgID] = map[int64]org.RoleType{}
+	}
+	f.members[cmd.OrgID][cmd.UserID] = cmd.Role
+	return nil
+}
+
+func (f *fakeOrgService) UpdateOrgUser(_ context.Context, cmd *org.UpdateOrgUserCommand) error {
+	f.members[cmd.OrgID][cmd.UserID] = cmd.Role
+	return nil
+}
+
+type fakeUserService struct {
+	user.Service
+
+	users map[string]int64
+}
+
+func (f *fakeUserService) GetByLogin(_ context.Context, query *user.GetUserByLoginQuery) (*user.User, error) {
+	if id, ok := f.users[query.LoginOrEmail]; ok {
+		return &user.User{ID: id, Login: query.LoginOrEmail}, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+type fakeTeamService struct {
+	team.Service
+
+	teams   map[int64]map[string]int64
+	emails  map[int64]string
+	members map[int64]map[int64]dashboards.PermissionType
+	deleted []int64
+}
+
+func (f *fakeTeamService) SearchTeams(_ context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	result := team.SearchTeamQueryResult{Teams: []*team.TeamDTO{}}
+	if id, ok := f.teams[query.OrgID][query.Name]; ok {
+		result.Teams = append(result.Teams, &team.TeamDTO{ID: id, OrgID: query.OrgID, Name: query.Name})
+	}
+	result.TotalCount = int64(len(result.Teams))
+	return result, nil
+}
+
+func (f *fakeTeamService) CreateTeam(name, email string, orgID int64) (team.Team, error) {
+	id := int64(len(f.emails) + 100)
+	if f.teams[orgID] == nil {
+		f.teams[orgID] = map[string]int64{}
+	}
+	f.teams[orgID][name] = id
+	f.emails[id] = email
+	return team.Team{ID: id, OrgID: orgID, Name: name, Email: email}, nil
+}
+
+func (f *fakeTeamService) UpdateTeam(_ context.Context, cmd *team.UpdateTeamCommand) error {
+	f.emails[cmd.ID] = cmd.Email
+	return nil
+}
+
+func (f *fakeTeamService) DeleteTeam(_ context.Context, cmd *team.DeleteTeamCommand) error {
+	f.deleted = append(f.deleted, cmd.ID)
+	return nil
+}
+
+func (f *fakeTeamService) AddTeamMember(userID, _, teamID int64, _ bool, permission dashboards.PermissionType) error {
+	if _, ok := f.members[teamID][userID]; ok {
+		return team.ErrTeamMemberAlreadyAdded
+	}
+	if f.members[teamID] == nil {
+		f.members[teamID] = map[int64]dashboards.PermissionType{}
+	}
+	f.members[teamID][userID] = permission
+	return nil
+}
+
+func (f *fakeTeamService) UpdateTeamMember(_ context.Context, cmd *team.UpdateTeamMemberCommand) error {
+	f.members[cmd.TeamID][cmd.UserID] = cmd.Permission
+	return nil
+}
@@ -0,0 +1,134 @@
+package orgs
+
+import (
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// orgsAsConfig is a normalized data object for orgs config data. Any config version should be mappable
+// to this type.
+type orgsAsConfig struct {
+	Orgs       []*orgFromConfig
+	DeleteOrgs []*deleteOrgConfig
+}
+
+type orgFromConfig struct {
+	Name        string
+	Users       []*orgUserFromConfig
+	Teams       []*teamFromConfig
+	DeleteTeams []*deleteTeamConfig
+}
+
+type orgUserFromConfig struct {
+	LoginOrEmail string
+	Role         org.RoleType
+}
+
+type teamFromConfig struct {
+	Name    string
+	Email   string
+	Members []*teamMemberFromConfig
+}
+
+type teamMemberFromConfig struct {
+	LoginOrEmail string
+	Permission   string
+}
+
+type deleteTeamConfig struct {
+	Name string
+}
+
+type deleteOrgConfig struct {
+	Name string
+}
+
+const (
+	teamPermissionMember = "Member"
+	teamPermissionAdmin  = "Admin"
+)
+
+// permissionType returns the permission of the member of the team, the zero value being a member.
+func (m *teamMemberFromConfig) permissionType() dashboards.PermissionType {
+	if m.Permission == teamPermissionAdmin {
+		return dashboards.PERMISSION_ADMIN
+	}
+	return 0
+}
+
+type orgFromConfigV1 struct {
+	Name        values.StringValue     `json:"name" yaml:"name"`
+	Users       []*orgUserFromConfigV1 `json:"users" yaml:"users"`
+	Teams       []*teamFromConfigV1    `json:"teams" yaml:"teams"`
+	DeleteTeams []*deleteTeamConfigV1  `json:"deleteTeams" yaml:"deleteTeams"`
+}
+
+type orgUserFromConfigV1 struct {
+	LoginOrEmail values.StringValue `json:"loginOrEmail" yaml:"loginOrEmail"`
+	Role         values.StringValue `json:"role" yaml:"role"`
+}
+
+type teamFromConfigV1 struct {
+	Name    values.StringValue        `json:"name" yaml:"name"`
+	Email   values.StringValue        `json:"email" yaml:"email"`
+	Members []*teamMemberFromConfigV1 `json:"members" yaml:"members"`
+}
+
+type teamMemberFromConfigV1 struct {
+	LoginOrEmail values.StringValue `json:"loginOrEmail" yaml:"loginOrEmail"`
+	Permission   values.StringValue `json:"permission" yaml:"permission"`
+}
+
+type deleteTeamConfigV1 struct {
+	Name values.StringValue `json:"name" yaml:"name"`
+}
+
+type deleteOrgConfigV1 struct {
+	Name values.StringValue `json:"name" yaml:"name"`
+}
+
+// orgsAsConfigV1 is a mapping for the version 1 configs. This is mapped to its normalised version.
+type orgsAsConfigV1 struct {
+	Orgs       []*orgFromConfigV1   `json:"orgs" yaml:"orgs"`
+	DeleteOrgs []*deleteOrgConfigV1 `json:"deleteOrgs" yaml:"deleteOrgs"`
+}
+
+// mapToOrgsFromConfig maps config syntax to a normalized orgsAsConfig object. Every version
+// of the config syntax should have this function.
+func (cfg *orgsAsConfigV1) mapToOrgsFromConfig() *orgsAsConfig {
+	r := &orgsAsConfig{}
+	if cfg == nil {
+		return r
+	}
+
+	for _, o := range cfg.Orgs {
+		result := &orgFromConfig{Name: o.Name.Value()}
+		for _, u := range o.Users {
+			result.Users = append(result.Users, &orgUserFromConfig{
+				LoginOrEmail: u.LoginOrEmail.Value(),
+				Role:         org.RoleType(u.Role.Value()),
+			})
+		}
+		for _, t := range o.Teams {
+			team := &teamFromConfig{Name: t.Name.Value(), Email: t.Email.Value()}
+			for _, m := range t.Members {
+				team.Members = append(team.Members, &teamMemberFromConfig{
+					LoginOrEmail: m.LoginOrEmail.Value(),
+					Permission:   m.Permission.Value(),
+				})
+			}
+			result.Teams = append(result.Teams, team)
+		}
+		for _, t := range o.DeleteTeams {
+			result.DeleteTeams = append(result.DeleteTeams, &deleteTeamConfig{Name: t.Name.Value()})
+		}
+		r.Orgs = append(r.Orgs, result)
+	}
+
+	for _, o := range cfg.DeleteOrgs {
+		r.DeleteOrgs = append(r.DeleteOrgs, &deleteOrgConfig{Name: o.Name.Value()})
+	}
+
+	return r
+}
@@ -100,6 +100,15 @@ func validateRequiredField(apps []*pluginsAsConfig) error {
 			}
 		}
 
+		for index, app := range apps[i].DeleteApps {
+			if app.PluginID == "" {
+				errStrings = append(
+					errStrings,
+					fmt.Sprintf("deleteApps item %d in configuration doesn't contain required field type", index+1),
+				)
+			}
+		}
+
 		if len(errStrings) != 0 {
 			return fmt.Errorf(strings.Join(errStrings, "\n"))
 		}
@@ -115,9 +124,13 @@ func (cr *configReaderImpl) validatePluginsConfig(ctx context.Context, apps []*p
 		}
 
 		for _, app := range apps[i].Apps {
-			if _, exists := cr.pluginStore.Plugin(ctx, app.PluginID); !exists {
+			p, exists := cr.pluginStore.Plugin(ctx, app.PluginID)
+			if !exists {
 				return fmt.Errorf("plugin not installed: %q", app.PluginID)
 			}
+			if !p.IsApp() {
+				return fmt.Errorf("plugin %q is not an app plugin", app.PluginID)
+			}
 		}
 	}
 
@@ -127,13 +140,22 @@ func (cr *configReaderImpl) validatePluginsConfig(ctx context.Context, apps []*p
 func checkOrgIDAndOrgName(apps []*pluginsAsConfig) {
 	for i := range apps {
 		for _, app := range apps[i].Apps {
-			if app.OrgID < 1 {
-				if app.OrgName == "" {
-					app.OrgID = 1
-				} else {
-					app.OrgID = 0
-				}
-			}
+			app.OrgID = defaultOrgID(app.OrgID, app.OrgName)
 		}
+		for _, app := range apps[i].DeleteApps {
+			app.OrgID = defaultOrgID(app.OrgID, app.OrgName)
+		}
+	}
+}
+
+// defaultOrgID returns the main organization if neither the id nor the name of the organization
+// are set, and 0 if only its name is set so that it is looked up when applying the configuration.
+func defaultOrgID(orgID int64, orgName string) int64 {
+	if orgID >= 1 {
+		return orgID
+	}
+	if orgName == "" {
+		return 1
 	}
+	return 0
 }
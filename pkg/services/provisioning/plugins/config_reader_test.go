@@ -17,6 +17,8 @@ const (
 	emptyFolder       = "./testdata/test-configs/empty_folder"
 	unknownApp        = "./testdata/test-configs/unknown-app"
 	correctProperties = "./testdata/test-configs/correct-properties"
+	deleteApps        = "./testdata/test-configs/delete-apps"
+	notApp            = "./testdata/test-configs/not-app"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -40,6 +42,31 @@ func TestConfigReader(t *testing.T) {
 		require.Equal(t, "plugin not installed: \"nonexisting\"", err.Error())
 	})
 
+	t.Run("Plugin that is not an app should return error", func(t *testing.T) {
+		pm := plugins.FakePluginStore{
+			PluginList: []plugins.PluginDTO{
+				{JSONData: plugins.JSONData{ID: "test-datasource", Type: plugins.DataSource}},
+			},
+		}
+		cfgProvider := newConfigReader(log.New("test logger"), pm)
+		_, err := cfgProvider.readConfig(context.Background(), notApp)
+		require.Error(t, err)
+		require.Equal(t, "plugin \"test-datasource\" is not an app plugin", err.Error())
+	})
+
+	t.Run("Can read apps to delete", func(t *testing.T) {
+		cfgProvider := newConfigReader(log.New("test logger"), plugins.FakePluginStore{})
+		cfg, err := cfgProvider.readConfig(context.Background(), deleteApps)
+		require.NoError(t, err)
+		require.Len(t, cfg, 1)
+		require.Empty(t, cfg[0].Apps)
+		require.Equal(t, []*deleteAppConfig{
+			{PluginID: "test-plugin", OrgID: 2},
+			{PluginID: "test-plugin-2", OrgName: "Org 3"},
+			{PluginID: "removed-plugin", OrgID: 1},
+		}, cfg[0].DeleteApps)
+	})
+
 	t.Run("Read incorrect properties", func(t *testing.T) {
 		cfgProvider := newConfigReader(log.New("test logger"), nil)
 		_, err := cfgProvider.readConfig(context.Background(), incorrectSettings)
@@ -50,8 +77,8 @@ func TestConfigReader(t *testing.T) {
 	t.Run("Can read correct properties", func(t *testing.T) {
 		pm := plugins.FakePluginStore{
 			PluginList: []plugins.PluginDTO{
-				{JSONData: plugins.JSONData{ID: "test-plugin"}},
-				{JSONData: plugins.JSONData{ID: "test-plugin-2"}},
+				{JSONData: plugins.JSONData{ID: "test-plugin", Type: plugins.App}},
+				{JSONData: plugins.JSONData{ID: "test-plugin-2", Type: plugins.App}},
 			},
 		}
 
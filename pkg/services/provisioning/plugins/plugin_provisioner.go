@@ -12,7 +12,7 @@ import (
 
 // Provision scans a directory for provisioning config files
 // and provisions the app in those files.
-func Provision(ctx context.Context, configDirectory string, pluginStore plugins.Store, pluginSettings pluginsettings.Service, orgService org.Service) error {
+func Provision(ctx context.Context, configDirectory string, pluginStore plugins.Store, pluginSettings pluginsettings.Service, orgService org.Service) (int, error) {
 	logger := log.New("provisioning.plugins")
 	ap := PluginProvisioner{
 		log:            logger,
@@ -20,30 +20,67 @@ func Provision(ctx context.Context, configDirectory string, pluginStore plugins.
 		pluginSettings: pluginSettings,
 		orgService:     orgService,
 	}
-	return ap.applyChanges(ctx, configDirectory)
+	err := ap.applyChanges(ctx, configDirectory)
+	return ap.applied, err
 }
 
 // PluginProvisioner is responsible for provisioning apps based on
-// configuration read by the `configReader`
+// configuration read by the `configReader`. The settings of the apps listed
+// in deleteApps are deleted before the apps of the same file are applied.
 type PluginProvisioner struct {
 	log            log.Logger
 	cfgProvider    configReader
 	pluginSettings pluginsettings.Service
 	orgService     org.Service
+	// applied is the number of apps applied by the last call to applyChanges
+	applied int
+}
+
+func (ap *PluginProvisioner) resolveOrgID(ctx context.Context, orgID int64, orgName string) (int64, error) {
+	if orgID == 0 && orgName != "" {
+		getOrgQuery := &org.GetOrgByNameQuery{Name: orgName}
+		res, err := ap.orgService.GetByName(ctx, getOrgQuery)
+		if err != nil {
+			return 0, err
+		}
+		return res.ID, nil
+	} else if orgID < 0 {
+		return 1, nil
+	}
+	return orgID, nil
+}
+
+func (ap *PluginProvisioner) deleteApps(ctx context.Context, cfg *pluginsAsConfig) error {
+	for _, app := range cfg.DeleteApps {
+		orgID, err := ap.resolveOrgID(ctx, app.OrgID, app.OrgName)
+		if err != nil {
+			return err
+		}
+		app.OrgID = orgID
+
+		ap.log.Info("Deleting app settings from configuration", "type", app.PluginID, "orgId", app.OrgID)
+		if err := ap.pluginSettings.DeletePluginSetting(ctx, &pluginsettings.DeleteArgs{
+			OrgID:    app.OrgID,
+			PluginID: app.PluginID,
+		}); err != nil && !errors.Is(err, pluginsettings.ErrPluginSettingNotFound) {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (ap *PluginProvisioner) apply(ctx context.Context, cfg *pluginsAsConfig) error {
+	if err := ap.deleteApps(ctx, cfg); err != nil {
+		return err
+	}
+
 	for _, app := range cfg.Apps {
-		if app.OrgID == 0 && app.OrgName != "" {
-			getOrgQuery := &org.GetOrgByNameQuery{Name: app.OrgName}
-			res, err := ap.orgService.GetByName(ctx, getOrgQuery)
-			if err != nil {
-				return err
-			}
-			app.OrgID = res.ID
-		} else if app.OrgID < 0 {
-			app.OrgID = 1
+		orgID, err := ap.resolveOrgID(ctx, app.OrgID, app.OrgName)
+		if err != nil {
+			return err
 		}
+		app.OrgID = orgID
 
 		ps, err := ap.pluginSettings.GetPluginSettingByPluginID(ctx, &pluginsettings.GetByPluginIDArgs{
 			OrgID:    app.OrgID,
@@ -75,6 +112,7 @@ func (ap *PluginProvisioner) apply(ctx context.Context, cfg *pluginsAsConfig) er
 }
 
 func (ap *PluginProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	ap.applied = 0
 	configs, err := ap.cfgProvider.readConfig(ctx, configPath)
 	if err != nil {
 		return err
@@ -84,6 +122,7 @@ func (ap *PluginProvisioner) applyChanges(ctx context.Context, configPath string
 		if err := ap.apply(ctx, cfg); err != nil {
 			return err
 		}
+		ap.applied += len(cfg.Apps)
 	}
 
 	return nil
@@ -68,6 +68,36 @@ func TestPluginProvisioner(t *testing.T) {
 			require.Equal(t, tc.ExpectedSecureJSONData, cmd.SecureJSONData)
 		}
 	})
+
+	t.Run("Should delete apps before applying configurations", func(t *testing.T) {
+		cfg := []*pluginsAsConfig{
+			{
+				Apps: []*appFromConfig{
+					{PluginID: "test-plugin", OrgID: 2, Enabled: true},
+				},
+				DeleteApps: []*deleteAppConfig{
+					{PluginID: "test-plugin", OrgID: 2},
+					{PluginID: "test-plugin-2", OrgName: "Org 4"},
+					{PluginID: "removed-plugin", OrgID: 1},
+				},
+			},
+		}
+		reader := &testConfigReader{result: cfg}
+		store := &mockStore{}
+		orgMock := orgtest.NewOrgServiceFake()
+		orgMock.ExpectedOrg = &org.Org{ID: 4}
+		ap := PluginProvisioner{log: log.New("test"), cfgProvider: reader, pluginSettings: store, orgService: orgMock}
+
+		err := ap.applyChanges(context.Background(), "")
+		require.NoError(t, err)
+		require.Equal(t, []*pluginsettings.DeleteArgs{
+			{PluginID: "test-plugin", OrgID: 2},
+			{PluginID: "test-plugin-2", OrgID: 4},
+			{PluginID: "removed-plugin", OrgID: 1},
+		}, store.deleteRequests)
+		require.Len(t, store.updateRequests, 1)
+		require.Equal(t, "test-plugin", store.updateRequests[0].PluginID)
+	})
 }
 
 type testConfigReader struct {
@@ -81,6 +111,7 @@ func (tcr *testConfigReader) readConfig(_ context.Context, _ string) ([]*plugins
 
 type mockStore struct {
 	updateRequests []*pluginsettings.UpdateArgs
+	deleteRequests []*pluginsettings.DeleteArgs
 }
 
 func (m *mockStore) GetPluginSettingByPluginID(_ context.Context, args *pluginsettings.GetByPluginIDArgs) (*pluginsettings.DTO, error) {
@@ -102,6 +133,14 @@ func (m *mockStore) UpdatePluginSettingPluginVersion(_ context.Context, _ *plugi
 	return nil
 }
 
+func (m *mockStore) DeletePluginSetting(_ context.Context, args *pluginsettings.DeleteArgs) error {
+	m.deleteRequests = append(m.deleteRequests, args)
+	if args.PluginID == "removed-plugin" {
+		return pluginsettings.ErrPluginSettingNotFound
+	}
+	return nil
+}
+
 func (m *mockStore) GetPluginSettings(_ context.Context, _ *pluginsettings.GetArgs) ([]*pluginsettings.InfoDTO, error) {
 	return nil, nil
 }
@@ -5,7 +5,14 @@ import "github.com/grafana/grafana/pkg/services/provisioning/values"
 // pluginsAsConfig is a normalized data object for plugins config data. Any config version should be mappable.
 // to this type.
 type pluginsAsConfig struct {
-	Apps []*appFromConfig
+	Apps       []*appFromConfig
+	DeleteApps []*deleteAppConfig
+}
+
+type deleteAppConfig struct {
+	OrgID    int64
+	OrgName  string
+	PluginID string
 }
 
 type appFromConfig struct {
@@ -28,9 +35,16 @@ type appFromConfigV0 struct {
 	SecureJSONData values.StringMapValue `json:"secureJsonData" yaml:"secureJsonData"`
 }
 
+type deleteAppConfigV0 struct {
+	OrgID   values.Int64Value  `json:"org_id" yaml:"org_id"`
+	OrgName values.StringValue `json:"org_name" yaml:"org_name"`
+	Type    values.StringValue `json:"type" yaml:"type"`
+}
+
 // pluginsAsConfigV0 is a mapping for zero version configs. This is mapped to its normalised version.
 type pluginsAsConfigV0 struct {
-	Apps []*appFromConfigV0 `json:"apps" yaml:"apps"`
+	Apps       []*appFromConfigV0   `json:"apps" yaml:"apps"`
+	DeleteApps []*deleteAppConfigV0 `json:"deleteApps" yaml:"deleteApps"`
 }
 
 // mapToPluginsFromConfig maps config syntax to a normalized notificationsAsConfig object. Every version
@@ -53,5 +67,13 @@ func (cfg *pluginsAsConfigV0) mapToPluginsFromConfig() *pluginsAsConfig {
 		})
 	}
 
+	for _, app := range cfg.DeleteApps {
+		r.DeleteApps = append(r.DeleteApps, &deleteAppConfig{
+			OrgID:    app.OrgID.Value(),
+			OrgName:  app.OrgName.Value(),
+			PluginID: app.Type.Value(),
+		})
+	}
+
 	return r
 }
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/distlock"
 	"github.com/grafana/grafana/pkg/infra/log"
 	plugifaces "github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/registry"
@@ -26,10 +28,13 @@ import (
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
+	"github.com/grafana/grafana/pkg/services/provisioning/orgs"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -51,6 +56,9 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	userService user.Service,
+	teamService team.Service,
+	lockService *distlock.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -63,6 +71,7 @@ func ProvideService(
 		provisionNotifiers:           notifiers.Provision,
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
+		provisionOrgs:                orgs.Provision,
 		provisionAlerting:            prov_alerting.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
@@ -75,6 +84,9 @@ func ProvideService(
 		secretService:                secrectService,
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
+		userService:                  userService,
+		teamService:                  teamService,
+		lockService:                  lockService,
 	}
 	return s, nil
 }
@@ -82,6 +94,7 @@ func ProvideService(
 type ProvisioningService interface {
 	registry.BackgroundService
 	RunInitProvisioners(ctx context.Context) error
+	ProvisionOrgs(ctx context.Context) error
 	ProvisionDatasources(ctx context.Context) error
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionNotifications(ctx context.Context) error
@@ -89,6 +102,9 @@ type ProvisioningService interface {
 	ProvisionAlerting(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	GetStatus() []ProviderStatus
+	ReloadAll(ctx context.Context) *ReloadJob
+	GetReloadJob(id string) (*ReloadJob, bool)
 }
 
 // Add a public constructor for overriding service to be able to instantiate OSS as fallback
@@ -100,15 +116,16 @@ func NewProvisioningServiceImpl() *ProvisioningServiceImpl {
 		provisionNotifiers:      notifiers.Provision,
 		provisionDatasources:    datasources.Provision,
 		provisionPlugins:        plugins.Provision,
+		provisionOrgs:           orgs.Provision,
 	}
 }
 
 // Used for testing purposes
 func newProvisioningServiceImpl(
 	newDashboardProvisioner dashboards.DashboardProvisionerFactory,
-	provisionNotifiers func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) error,
-	provisionDatasources func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error,
-	provisionPlugins func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error,
+	provisionNotifiers func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) (int, error),
+	provisionDatasources func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) (int, error),
+	provisionPlugins func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) (int, error),
 ) *ProvisioningServiceImpl {
 	return &ProvisioningServiceImpl{
 		log:                     log.New("provisioning"),
@@ -116,6 +133,7 @@ func newProvisioningServiceImpl(
 		provisionNotifiers:      provisionNotifiers,
 		provisionDatasources:    provisionDatasources,
 		provisionPlugins:        provisionPlugins,
+		provisionOrgs:           orgs.Provision,
 	}
 }
 
@@ -123,6 +141,8 @@ type ProvisioningServiceImpl struct {
 	Cfg                          *setting.Cfg
 	SQLStore                     db.DB
 	orgService                   org.Service
+	userService                  user.Service
+	teamService                  team.Service
 	ac                           accesscontrol.AccessControl
 	pluginStore                  plugifaces.Store
 	EncryptionService            encryption.Internal
@@ -131,10 +151,11 @@ type ProvisioningServiceImpl struct {
 	pollingCtxCancel             context.CancelFunc
 	newDashboardProvisioner      dashboards.DashboardProvisionerFactory
 	dashboardProvisioner         dashboards.DashboardProvisioner
-	provisionNotifiers           func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) error
-	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
-	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error
-	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionNotifiers           func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) (int, error)
+	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) (int, error)
+	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) (int, error)
+	provisionOrgs                func(context.Context, string, org.Service, user.Service, team.Service) (int, error)
+	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) (int, error)
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -145,10 +166,18 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	status                       statusTracker
+	lockService                  *distlock.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
-	err := ps.ProvisionDatasources(ctx)
+	// orgs are provisioned first so that the other providers can reference them
+	err := ps.ProvisionOrgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ps.ProvisionDatasources(ctx)
 	if err != nil {
 		return err
 	}
@@ -203,41 +232,95 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 	}
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionOrgs(ctx context.Context) error {
+	return ps.withLock(ctx, ProviderOrgs, func(ctx context.Context) error {
+		started := time.Now()
+		orgsPath := filepath.Join(ps.Cfg.ProvisioningPath, "orgs")
+		items, err := ps.provisionOrgs(ctx, orgsPath, ps.orgService, ps.userService, ps.teamService)
+		if err != nil {
+			err = fmt.Errorf("%v: %w", "Org provisioning error", err)
+			ps.log.Error("Failed to provision orgs", "error", err)
+		}
+		ps.status.record(ProviderOrgs, started, items, err)
+		return err
+	})
+}
+
 func (ps *ProvisioningServiceImpl) ProvisionDatasources(ctx context.Context) error {
-	datasourcePath := filepath.Join(ps.Cfg.ProvisioningPath, "datasources")
-	if err := ps.provisionDatasources(ctx, datasourcePath, ps.datasourceService, ps.correlationsService, ps.orgService); err != nil {
-		err = fmt.Errorf("%v: %w", "Datasource provisioning error", err)
-		ps.log.Error("Failed to provision data sources", "error", err)
+	return ps.withLock(ctx, ProviderDatasources, func(ctx context.Context) error {
+		started := time.Now()
+		datasourcePath := filepath.Join(ps.Cfg.ProvisioningPath, "datasources")
+		items, err := ps.provisionDatasources(ctx, datasourcePath, ps.datasourceService, ps.correlationsService, ps.orgService)
+		if err != nil {
+			err = fmt.Errorf("%v: %w", "Datasource provisioning error", err)
+			ps.log.Error("Failed to provision data sources", "error", err)
+		}
+		ps.status.record(ProviderDatasources, started, items, err)
 		return err
-	}
-	return nil
+	})
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionPlugins(ctx context.Context) error {
-	appPath := filepath.Join(ps.Cfg.ProvisioningPath, "plugins")
-	if err := ps.provisionPlugins(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService); err != nil {
-		err = fmt.Errorf("%v: %w", "app provisioning error", err)
-		ps.log.Error("Failed to provision plugins", "error", err)
+	return ps.withLock(ctx, ProviderPlugins, func(ctx context.Context) error {
+		started := time.Now()
+		appPath := filepath.Join(ps.Cfg.ProvisioningPath, "plugins")
+		items, err := ps.provisionPlugins(ctx, appPath, ps.pluginStore, ps.pluginsSettings, ps.orgService)
+		if err != nil {
+			err = fmt.Errorf("%v: %w", "app provisioning error", err)
+			ps.log.Error("Failed to provision plugins", "error", err)
+		}
+		ps.status.record(ProviderPlugins, started, items, err)
 		return err
-	}
-	return nil
+	})
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) error {
-	alertNotificationsPath := filepath.Join(ps.Cfg.ProvisioningPath, "notifiers")
-	if err := ps.provisionNotifiers(ctx, alertNotificationsPath, ps.alertingService, ps.orgService, ps.EncryptionService, ps.NotificationService); err != nil {
-		err = fmt.Errorf("%v: %w", "Alert notification provisioning error", err)
-		ps.log.Error("Failed to provision alert notifications", "error", err)
+	return ps.withLock(ctx, ProviderNotifications, func(ctx context.Context) error {
+		started := time.Now()
+		alertNotificationsPath := filepath.Join(ps.Cfg.ProvisioningPath, "notifiers")
+		items, err := ps.provisionNotifiers(ctx, alertNotificationsPath, ps.alertingService, ps.orgService, ps.EncryptionService, ps.NotificationService)
+		if err != nil {
+			err = fmt.Errorf("%v: %w", "Alert notification provisioning error", err)
+			ps.log.Error("Failed to provision alert notifications", "error", err)
+		}
+		ps.status.record(ProviderNotifications, started, items, err)
 		return err
-	}
-	return nil
+	})
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
+	return ps.withLock(ctx, ProviderDashboards, func(ctx context.Context) error {
+		started := time.Now()
+		items, err := ps.provisionDashboards(ctx)
+		ps.status.record(ProviderDashboards, started, items, err)
+		return err
+	})
+}
+
+// withLock provisions a provider under a lock shared by the instances, so that the instances of a high
+// availability setup starting together don't provision it concurrently.
+func (ps *ProvisioningServiceImpl) withLock(ctx context.Context, provider string, fn func(context.Context) error) error {
+	if ps.lockService == nil {
+		return fn(ctx)
+	}
+
+	lock, err := ps.lockService.Lock(ctx, "provisioning."+provider)
+	if err != nil {
+		return fmt.Errorf("failed to lock the provisioning of %s: %w", provider, err)
+	}
+	defer func() {
+		if err := lock.Release(context.Background()); err != nil {
+			ps.log.Warn("Failed to release the provisioning lock", "provider", provider, "error", err)
+		}
+	}()
+	return fn(ctx)
+}
+
+func (ps *ProvisioningServiceImpl) provisionDashboards(ctx context.Context) (int, error) {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
 	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
 	if err != nil {
-		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
+		return 0, fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
 
 	ps.mutex.Lock()
@@ -250,48 +333,53 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 	if err != nil {
 		// If we fail to provision with the new provisioner, the mutex will unlock and the polling will restart with the
 		// old provisioner as we did not switch them yet.
-		return fmt.Errorf("%v: %w", "Failed to provision dashboards", err)
+		return 0, fmt.Errorf("%v: %w", "Failed to provision dashboards", err)
 	}
 	ps.dashboardProvisioner = dashProvisioner
-	return nil
+	return dashProvisioner.GetProvisionedDashboardsCount(), nil
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
-	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
-	st := store.DBstore{
-		Cfg:              ps.Cfg.UnifiedAlerting,
-		SQLStore:         ps.SQLStore,
-		Logger:           ps.log,
-		FolderService:    nil, // we don't use it yet
-		AccessControl:    ps.ac,
-		DashboardService: ps.dashboardService,
-	}
-	ruleService := provisioning.NewAlertRuleService(
-		st,
-		st,
-		ps.dashboardService,
-		ps.quotaService,
-		ps.SQLStore,
-		int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
-		int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
-		ps.log)
-	contactPointService := provisioning.NewContactPointService(&st, ps.secretService,
-		st, ps.SQLStore, ps.log)
-	notificationPolicyService := provisioning.NewNotificationPolicyService(&st,
-		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
-	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
-	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
-	cfg := prov_alerting.ProvisionerConfig{
-		Path:                       alertingPath,
-		RuleService:                *ruleService,
-		DashboardService:           ps.dashboardService,
-		DashboardProvService:       ps.dashboardProvisioningService,
-		ContactPointService:        *contactPointService,
-		NotificiationPolicyService: *notificationPolicyService,
-		MuteTimingService:          *mutetimingsService,
-		TemplateService:            *templateService,
-	}
-	return ps.provisionAlerting(ctx, cfg)
+	return ps.withLock(ctx, ProviderAlerting, func(ctx context.Context) error {
+		started := time.Now()
+		alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+		st := store.DBstore{
+			Cfg:              ps.Cfg.UnifiedAlerting,
+			SQLStore:         ps.SQLStore,
+			Logger:           ps.log,
+			FolderService:    nil, // we don't use it yet
+			AccessControl:    ps.ac,
+			DashboardService: ps.dashboardService,
+		}
+		ruleService := provisioning.NewAlertRuleService(
+			st,
+			st,
+			ps.dashboardService,
+			ps.quotaService,
+			ps.SQLStore,
+			int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
+			int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
+			ps.log)
+		contactPointService := provisioning.NewContactPointService(&st, ps.secretService,
+			st, ps.SQLStore, ps.log)
+		notificationPolicyService := provisioning.NewNotificationPolicyService(&st,
+			st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
+		mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
+		templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
+		cfg := prov_alerting.ProvisionerConfig{
+			Path:                       alertingPath,
+			RuleService:                *ruleService,
+			DashboardService:           ps.dashboardService,
+			DashboardProvService:       ps.dashboardProvisioningService,
+			ContactPointService:        *contactPointService,
+			NotificiationPolicyService: *notificationPolicyService,
+			MuteTimingService:          *mutetimingsService,
+			TemplateService:            *templateService,
+		}
+		items, err := ps.provisionAlerting(ctx, cfg)
+		ps.status.record(ProviderAlerting, started, items, err)
+		return err
+	})
 }
 
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
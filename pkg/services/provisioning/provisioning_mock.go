@@ -4,6 +4,7 @@ import "context"
 
 type Calls struct {
 	RunInitProvisioners                 []interface{}
+	ProvisionOrgs                       []interface{}
 	ProvisionDatasources                []interface{}
 	ProvisionPlugins                    []interface{}
 	ProvisionNotifications              []interface{}
@@ -11,18 +12,25 @@ type Calls struct {
 	ProvisionAlerting                   []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
+	GetStatus                           []interface{}
+	ReloadAll                           []interface{}
+	GetReloadJob                        []interface{}
 	Run                                 []interface{}
 }
 
 type ProvisioningServiceMock struct {
 	Calls                                   *Calls
 	RunInitProvisionersFunc                 func(ctx context.Context) error
+	ProvisionOrgsFunc                       func(ctx context.Context) error
 	ProvisionDatasourcesFunc                func(ctx context.Context) error
 	ProvisionPluginsFunc                    func() error
 	ProvisionNotificationsFunc              func() error
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	GetStatusFunc                           func() []ProviderStatus
+	ReloadAllFunc                           func(ctx context.Context) *ReloadJob
+	GetReloadJobFunc                        func(id string) (*ReloadJob, bool)
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -40,6 +48,14 @@ func (mock *ProvisioningServiceMock) RunInitProvisioners(ctx context.Context) er
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionOrgs(ctx context.Context) error {
+	mock.Calls.ProvisionOrgs = append(mock.Calls.ProvisionOrgs, nil)
+	if mock.ProvisionOrgsFunc != nil {
+		return mock.ProvisionOrgsFunc(ctx)
+	}
+	return nil
+}
+
 func (mock *ProvisioningServiceMock) ProvisionDatasources(ctx context.Context) error {
 	mock.Calls.ProvisionDatasources = append(mock.Calls.ProvisionDatasources, nil)
 	if mock.ProvisionDatasourcesFunc != nil {
@@ -93,6 +109,30 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) GetStatus() []ProviderStatus {
+	mock.Calls.GetStatus = append(mock.Calls.GetStatus, nil)
+	if mock.GetStatusFunc != nil {
+		return mock.GetStatusFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ReloadAll(ctx context.Context) *ReloadJob {
+	mock.Calls.ReloadAll = append(mock.Calls.ReloadAll, nil)
+	if mock.ReloadAllFunc != nil {
+		return mock.ReloadAllFunc(ctx)
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) GetReloadJob(id string) (*ReloadJob, bool) {
+	mock.Calls.GetReloadJob = append(mock.Calls.GetReloadJob, id)
+	if mock.GetReloadJobFunc != nil {
+		return mock.GetReloadJobFunc(id)
+	}
+	return nil, false
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
@@ -8,10 +8,19 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/grafana/grafana/pkg/plugins"
 	dashboardstore "github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -67,6 +76,58 @@ func TestProvisioningServiceImpl(t *testing.T) {
 	})
 }
 
+func TestProvisioningServiceImpl_ReloadAll(t *testing.T) {
+	serviceTest := setup()
+	service := serviceTest.service
+	service.provisionOrgs = func(context.Context, string, org.Service, user.Service, team.Service) (int, error) {
+		return 4, nil
+	}
+	service.provisionDatasources = func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) (int, error) {
+		return 2, nil
+	}
+	service.provisionPlugins = func(context.Context, string, plugins.Store, pluginsettings.Service, org.Service) (int, error) {
+		return 0, errors.New("invalid plugin config")
+	}
+	service.provisionNotifiers = func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) (int, error) {
+		return 1, nil
+	}
+	service.provisionAlerting = func(context.Context, prov_alerting.ProvisionerConfig) (int, error) {
+		return 3, nil
+	}
+
+	for _, status := range service.GetStatus() {
+		assert.Nil(t, status.LastRun, "provider %s should not have run", status.Provider)
+	}
+
+	job := service.ReloadAll(context.Background())
+	assert.NotEmpty(t, job.ID)
+
+	assert.Eventually(t, func() bool {
+		job, ok := service.GetReloadJob(job.ID)
+		return ok && job.State != ReloadJobRunning
+	}, serviceTest.waitTimeout, 10*time.Millisecond)
+
+	job, ok := service.GetReloadJob(job.ID)
+	assert.True(t, ok)
+	assert.Equal(t, ReloadJobFailed, job.State)
+	assert.NotNil(t, job.Finished)
+	assert.Len(t, job.Providers, len(Providers))
+
+	items := map[string]int{}
+	errs := map[string]string{}
+	for _, status := range service.GetStatus() {
+		assert.NotNil(t, status.LastRun, "provider %s should have run", status.Provider)
+		items[status.Provider] = status.Items
+		errs[status.Provider] = status.Error
+	}
+	assert.Equal(t, map[string]int{ProviderOrgs: 4, ProviderDatasources: 2, ProviderPlugins: 0, ProviderNotifications: 1, ProviderAlerting: 3, ProviderDashboards: 0}, items)
+	assert.Contains(t, errs[ProviderPlugins], "invalid plugin config")
+	assert.Empty(t, errs[ProviderDatasources])
+
+	_, ok = service.GetReloadJob("unknown")
+	assert.False(t, ok)
+}
+
 type serviceTestStruct struct {
 	waitForPollChanges func()
 	waitForStop        func()
@@ -0,0 +1,214 @@
+package provisioning
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	ProviderOrgs          = "orgs"
+	ProviderDatasources   = "datasources"
+	ProviderPlugins       = "plugins"
+	ProviderNotifications = "notifications"
+	ProviderAlerting      = "alerting"
+	ProviderDashboards    = "dashboards"
+)
+
+// Providers lists the provisioning providers in the order they are run
+var Providers = []string{ProviderOrgs, ProviderDatasources, ProviderPlugins, ProviderNotifications, ProviderAlerting, ProviderDashboards}
+
+// maxReloadJobs is the number of reload jobs kept in memory
+const maxReloadJobs = 10
+
+// ProviderStatus is the outcome of the last run of a provisioning provider
+type ProviderStatus struct {
+	Provider string `json:"provider"`
+	// LastRun is nil if the provider hasn't run since the server started
+	LastRun    *time.Time `json:"lastRun"`
+	DurationMs int64      `json:"durationMs"`
+	// Items is the number of provisioned items applied by the last successful run
+	Items int    `json:"items"`
+	Error string `json:"error,omitempty"`
+}
+
+type ReloadJobState string
+
+const (
+	ReloadJobRunning   ReloadJobState = "running"
+	ReloadJobSucceeded ReloadJobState = "succeeded"
+	ReloadJobFailed    ReloadJobState = "failed"
+)
+
+// ReloadJob tracks an asynchronous reload of all the provisioning providers
+type ReloadJob struct {
+	ID        string           `json:"id"`
+	State     ReloadJobState   `json:"state"`
+	Started   time.Time        `json:"started"`
+	Finished  *time.Time       `json:"finished,omitempty"`
+	Providers []ProviderStatus `json:"providers"`
+}
+
+type statusTracker struct {
+	mu       sync.RWMutex
+	statuses map[string]ProviderStatus
+	jobs     map[string]*ReloadJob
+	// jobIDs holds the IDs of the jobs from the oldest to the most recent one
+	jobIDs []string
+}
+
+func (t *statusTracker) record(provider string, started time.Time, items int, err error) {
+	status := ProviderStatus{
+		Provider:   provider,
+		LastRun:    &started,
+		DurationMs: time.Since(started).Milliseconds(),
+		Items:      items,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.statuses == nil {
+		t.statuses = make(map[string]ProviderStatus)
+	}
+	if err != nil {
+		// keep the item count of the last successful run
+		status.Items = t.statuses[provider].Items
+	}
+	t.statuses[provider] = status
+}
+
+func (t *statusTracker) get() []ProviderStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	statuses := make([]ProviderStatus, 0, len(Providers))
+	for _, provider := range Providers {
+		status, ok := t.statuses[provider]
+		if !ok {
+			status = ProviderStatus{Provider: provider}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (t *statusTracker) statusOf(provider string) ProviderStatus {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.statuses[provider]
+}
+
+// startJob registers a new reload job, or returns the running one if any
+func (t *statusTracker) startJob() (*ReloadJob, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.jobs == nil {
+		t.jobs = make(map[string]*ReloadJob)
+	}
+	if len(t.jobIDs) > 0 {
+		if last := t.jobs[t.jobIDs[len(t.jobIDs)-1]]; last.State == ReloadJobRunning {
+			return last.copy(), false
+		}
+	}
+
+	job := &ReloadJob{ID: util.GenerateShortUID(), State: ReloadJobRunning, Started: time.Now(), Providers: []ProviderStatus{}}
+	t.jobs[job.ID] = job
+	t.jobIDs = append(t.jobIDs, job.ID)
+	if len(t.jobIDs) > maxReloadJobs {
+		delete(t.jobs, t.jobIDs[0])
+		t.jobIDs = t.jobIDs[1:]
+	}
+	return job.copy(), true
+}
+
+func (t *statusTracker) updateJob(id string, fn func(job *ReloadJob)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if job, ok := t.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+func (t *statusTracker) getJob(id string) (*ReloadJob, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	job, ok := t.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return job.copy(), true
+}
+
+func (j *ReloadJob) copy() *ReloadJob {
+	c := *j
+	c.Providers = append([]ProviderStatus{}, j.Providers...)
+	return &c
+}
+
+// GetStatus returns the status of every provisioning provider
+func (ps *ProvisioningServiceImpl) GetStatus() []ProviderStatus {
+	return ps.status.get()
+}
+
+// ReloadAll starts reloading all the provisioning providers in the background
+// and returns the job tracking the reload. If a reload is already running,
+// its job is returned instead.
+func (ps *ProvisioningServiceImpl) ReloadAll(ctx context.Context) *ReloadJob {
+	job, started := ps.status.startJob()
+	if !started {
+		return job
+	}
+
+	// the reload outlives the request that started it
+	go ps.runReloadJob(context.Background(), job.ID)
+	return job
+}
+
+// GetReloadJob returns a reload job started by ReloadAll
+func (ps *ProvisioningServiceImpl) GetReloadJob(id string) (*ReloadJob, bool) {
+	return ps.status.getJob(id)
+}
+
+func (ps *ProvisioningServiceImpl) runReloadJob(ctx context.Context, id string) {
+	reloaders := map[string]func(context.Context) error{
+		ProviderOrgs:          ps.ProvisionOrgs,
+		ProviderDatasources:   ps.ProvisionDatasources,
+		ProviderPlugins:       ps.ProvisionPlugins,
+		ProviderNotifications: ps.ProvisionNotifications,
+		ProviderAlerting:      ps.ProvisionAlerting,
+		ProviderDashboards:    ps.ProvisionDashboards,
+	}
+
+	failed := false
+	for _, provider := range Providers {
+		// errors are recorded in the provider status, the other providers are still reloaded
+		if err := reloaders[provider](ctx); err != nil {
+			failed = true
+		}
+
+		status := ps.status.statusOf(provider)
+		ps.status.updateJob(id, func(job *ReloadJob) {
+			job.Providers = append(job.Providers, status)
+		})
+	}
+
+	finished := time.Now()
+	ps.status.updateJob(id, func(job *ReloadJob) {
+		job.Finished = &finished
+		job.State = ReloadJobSucceeded
+		if failed {
+			job.State = ReloadJobFailed
+		}
+	})
+	ps.log.Info("Reloaded all provisioning providers", "job", id, "failed", failed)
+}
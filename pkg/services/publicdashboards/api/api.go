@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -15,6 +16,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -23,6 +26,8 @@ type Api struct {
 	RouteRegister          routing.RouteRegister
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
+	QuotaService           quota.Service
+	Cfg                    *setting.Cfg
 	Log                    log.Logger
 }
 
@@ -31,12 +36,16 @@ func ProvideApi(
 	rr routing.RouteRegister,
 	ac accesscontrol.AccessControl,
 	features *featuremgmt.FeatureManager,
+	quotaService quota.Service,
+	cfg *setting.Cfg,
 ) *Api {
 	api := &Api{
 		PublicDashboardService: pd,
 		RouteRegister:          rr,
 		AccessControl:          ac,
 		Features:               features,
+		QuotaService:           quotaService,
+		Cfg:                    cfg,
 		Log:                    log.New("publicdashboards.api"),
 	}
 
@@ -57,6 +66,7 @@ func (api *Api) RegisterAPIEndpoints() {
 
 	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
 	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboardCacheable))
 	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
 
 	// Auth endpoints
@@ -74,6 +84,7 @@ func (api *Api) RegisterAPIEndpoints() {
 	// Create Public Dashboard
 	api.RouteRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		middleware.Quota(api.QuotaService)(string(QuotaTargetSrv)),
 		routing.Wrap(api.CreatePublicDashboard))
 
 	// Update Public Dashboard
@@ -85,6 +96,11 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// Purge Public dashboard from the CDN
+	api.RouteRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/purge",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.PurgePublicDashboard))
 }
 
 // ListPublicDashboards Gets list of public dashboards by orgId
@@ -204,10 +220,25 @@ func (api *Api) DeletePublicDashboard(c *contextmodel.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, nil)
 }
 
+// PurgePublicDashboard Purges the cached responses of a public dashboard from the CDN
+// POST /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/purge
+func (api *Api) PurgePublicDashboard(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if !validation.IsValidShortUID(uid) {
+		return response.Err(ErrInvalidUid.Errorf("PurgePublicDashboard: invalid Uid %s", uid))
+	}
+
+	if err := api.PublicDashboardService.PurgeCDN(c.Req.Context(), uid); err != nil {
+		return response.Err(err)
+	}
+
+	return response.Success("Public dashboard purged from the CDN")
+}
+
 // Copied from pkg/api/metrics.go
-func toJsonStreamingResponse(features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
+func toJsonStreamingResponse(ctx context.Context, features *featuremgmt.FeatureManager, qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
-	if features.IsEnabled(featuremgmt.FlagDatasourceQueryMultiStatus) {
+	if features.IsEnabledCtx(ctx, featuremgmt.FlagDatasourceQueryMultiStatus) {
 		statusWhenError = http.StatusMultiStatus
 	}
 
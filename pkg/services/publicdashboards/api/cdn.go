@@ -0,0 +1,128 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+const (
+	queryURLSignatureParam     = "signature"
+	queryURLVersionParam       = "version"
+	queryURLIntervalParam      = "intervalMs"
+	queryURLMaxDataPointsParam = "maxDataPoints"
+
+	// defaultCacheableMaxDataPoints is the number of data points of the cacheable queries of the panels without
+	// maxDataPoints, whose number of data points depends on their width in the browser otherwise.
+	defaultCacheableMaxDataPoints = 1000
+)
+
+// setCDNCacheHeaders makes a response of a public dashboard cacheable by CDNs and shared caches, with the surrogate
+// key used to purge it.
+func (api *Api) setCDNCacheHeaders(c *contextmodel.ReqContext, pubdash *PublicDashboard) {
+	maxAge := int(api.Cfg.PublicDashboardsCDNCacheTTL / time.Second)
+	c.Resp.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d", maxAge, maxAge))
+	c.Resp.Header().Set("Surrogate-Key", SurrogateKey(pubdash))
+}
+
+// queryURLs returns the signed URLs of the cacheable queries of the panels of a public dashboard. The URLs are
+// versioned by the version of the dashboard, so that they change when the dashboard is saved, and signed so that
+// their parameters cannot be changed to bypass the caches.
+func (api *Api) queryURLs(pubdash *PublicDashboard, dash *dashboards.Dashboard) map[int64]string {
+	from := dash.Data.GetPath("time", "from").MustString()
+	to := dash.Data.GetPath("time", "to").MustString()
+	timeRange := legacydata.NewDataTimeRange(from, to)
+	rangeMs := timeRange.GetToAsMsEpoch() - timeRange.GetFromAsMsEpoch()
+
+	urls := map[int64]string{}
+	var walk func(parent *simplejson.Json)
+	walk = func(parent *simplejson.Json) {
+		for i := range parent.Get("panels").MustArray() {
+			panel := parent.Get("panels").GetIndex(i)
+			if panel.Get("type").MustString() == "row" {
+				walk(panel)
+				continue
+			}
+			if len(panel.Get("targets").MustArray()) == 0 {
+				continue
+			}
+
+			maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultCacheableMaxDataPoints)
+			if maxDataPoints <= 0 {
+				maxDataPoints = defaultCacheableMaxDataPoints
+			}
+			intervalMs := rangeMs / maxDataPoints
+			if intervalMs < 1 {
+				intervalMs = 1
+			}
+
+			panelID := panel.Get("id").MustInt64()
+			path := queryURLPath(pubdash.AccessToken, panelID)
+			query := url.Values{}
+			query.Set(queryURLVersionParam, strconv.Itoa(dash.Version))
+			query.Set(queryURLMaxDataPointsParam, strconv.FormatInt(maxDataPoints, 10))
+			query.Set(queryURLIntervalParam, strconv.FormatInt(intervalMs, 10))
+			query.Set(queryURLSignatureParam, querySignature(api.Cfg.SecretKey, path, query))
+			urls[panelID] = path + "?" + query.Encode()
+		}
+	}
+	walk(dash.Data)
+
+	return urls
+}
+
+// validateQueryURL checks the signature and the version of a cacheable query URL, and returns its query parameters.
+func (api *Api) validateQueryURL(path string, query url.Values, dash *dashboards.Dashboard) (PublicDashboardQueryDTO, error) {
+	unsigned := url.Values{}
+	for _, key := range []string{queryURLVersionParam, queryURLMaxDataPointsParam, queryURLIntervalParam} {
+		unsigned.Set(key, query.Get(key))
+	}
+	sig := query.Get(queryURLSignatureParam)
+	if sig == "" || !hmac.Equal([]byte(sig), []byte(querySignature(api.Cfg.SecretKey, path, unsigned))) {
+		return PublicDashboardQueryDTO{}, ErrInvalidQueryURL.Errorf("signature mismatch")
+	}
+	if query.Get(queryURLVersionParam) != strconv.Itoa(dash.Version) {
+		return PublicDashboardQueryDTO{}, ErrInvalidQueryURL.Errorf("query URL of version %s of dashboard %s, the current version is %d", query.Get(queryURLVersionParam), dash.UID, dash.Version)
+	}
+
+	maxDataPoints, err := strconv.ParseInt(query.Get(queryURLMaxDataPointsParam), 10, 64)
+	if err != nil {
+		return PublicDashboardQueryDTO{}, ErrInvalidMaxDataPoints.Errorf("invalid maxDataPoints: %w", err)
+	}
+	intervalMs, err := strconv.ParseInt(query.Get(queryURLIntervalParam), 10, 64)
+	if err != nil {
+		return PublicDashboardQueryDTO{}, ErrInvalidInterval.Errorf("invalid intervalMs: %w", err)
+	}
+
+	// the cacheable queries use the default time range of the dashboard
+	return PublicDashboardQueryDTO{
+		IntervalMs:    intervalMs,
+		MaxDataPoints: maxDataPoints,
+		TimeRange: TimeSettings{
+			From: dash.Data.GetPath("time", "from").MustString(),
+			To:   dash.Data.GetPath("time", "to").MustString(),
+		},
+	}, nil
+}
+
+func queryURLPath(accessToken string, panelID int64) string {
+	return fmt.Sprintf("api/public/dashboards/%s/panels/%d/query", accessToken, panelID)
+}
+
+func querySignature(secret, path string, query url.Values) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(path))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(query.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestAPICDNCache(t *testing.T) {
+	cacheablePubdash := &PublicDashboard{Uid: "pubdashuid", AccessToken: validAccessToken, IsEnabled: true, CdnCacheEnabled: true}
+	newDashboard := func(version int) *dashboards.Dashboard {
+		return &dashboards.Dashboard{UID: "dashuid", Version: version, Data: simplejson.NewFromAny(map[string]interface{}{
+			"time": map[string]interface{}{"from": "now-6h", "to": "now"},
+			"panels": []interface{}{
+				map[string]interface{}{"id": 1, "targets": []interface{}{map[string]interface{}{"refId": "A"}}},
+				map[string]interface{}{"id": 2, "type": "text"},
+				map[string]interface{}{"id": 3, "type": "row", "panels": []interface{}{
+					map[string]interface{}{"id": 4, "maxDataPoints": 100, "targets": []interface{}{map[string]interface{}{"refId": "A"}}},
+				}},
+			},
+		})}
+	}
+
+	setup := func(t *testing.T, pubdash *PublicDashboard, version int) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("FindEnabledPublicDashboardAndDashboardByAccessToken", mock.Anything, validAccessToken).
+			Return(pubdash, newDashboard(version), nil).Maybe()
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		cfg.SecretKey = "secret"
+		cfg.PublicDashboardsCDNCacheTTL = time.Minute
+
+		return setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser), service
+	}
+
+	viewQueryURLs := func(t *testing.T, server *web.Mux) map[int64]string {
+		resp := callAPI(server, http.MethodGet, fmt.Sprintf("/api/public/dashboards/%s", validAccessToken), nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, "public, max-age=60, s-maxage=60", resp.Header().Get("Cache-Control"))
+		require.Equal(t, "publicdashboard-pubdashuid", resp.Header().Get("Surrogate-Key"))
+
+		var dashResp dtos.DashboardFullWithMeta
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dashResp))
+		return dashResp.Meta.PublicDashboardQueryUrls
+	}
+
+	t.Run("the view is not cacheable when the CDN cache is disabled", func(t *testing.T) {
+		server, _ := setup(t, &PublicDashboard{Uid: "pubdashuid", AccessToken: validAccessToken, IsEnabled: true}, 1)
+
+		resp := callAPI(server, http.MethodGet, fmt.Sprintf("/api/public/dashboards/%s", validAccessToken), nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Empty(t, resp.Header().Get("Cache-Control"))
+		require.NotContains(t, resp.Body.String(), "publicDashboardQueryUrls")
+	})
+
+	t.Run("the view lists the signed query URLs of the panels with queries", func(t *testing.T) {
+		server, _ := setup(t, cacheablePubdash, 3)
+
+		urls := viewQueryURLs(t, server)
+		require.Len(t, urls, 2)
+		require.True(t, strings.HasPrefix(urls[1], "api/public/dashboards/"+validAccessToken+"/panels/1/query?"))
+		require.Contains(t, urls[1], "maxDataPoints=1000")
+		require.Contains(t, urls[1], "version=3")
+		require.Contains(t, urls[4], "maxDataPoints=100")
+		require.Contains(t, urls[4], "intervalMs=216000")
+	})
+
+	t.Run("the query URLs return cacheable query data", func(t *testing.T) {
+		server, service := setup(t, cacheablePubdash, 3)
+		service.On("GetQueryDataResponse", mock.Anything, mock.Anything, PublicDashboardQueryDTO{
+			IntervalMs:    216000,
+			MaxDataPoints: 100,
+			TimeRange:     TimeSettings{From: "now-6h", To: "now"},
+		}, int64(4), validAccessToken).Return(&backend.QueryDataResponse{}, nil).Once()
+
+		urls := viewQueryURLs(t, server)
+		resp := callAPI(server, http.MethodGet, "/"+urls[4], nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, "public, max-age=60, s-maxage=60", resp.Header().Get("Cache-Control"))
+		require.Equal(t, "publicdashboard-pubdashuid", resp.Header().Get("Surrogate-Key"))
+	})
+
+	t.Run("the query URLs are rejected when they are tampered with", func(t *testing.T) {
+		server, _ := setup(t, cacheablePubdash, 3)
+
+		urls := viewQueryURLs(t, server)
+		resp := callAPI(server, http.MethodGet, "/"+strings.Replace(urls[4], "maxDataPoints=100", "maxDataPoints=10000", 1), nil, t)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		require.Contains(t, resp.Body.String(), "publicdashboards.invalidQueryURL")
+	})
+
+	t.Run("the query URLs are rejected when the dashboard was saved", func(t *testing.T) {
+		server, _ := setup(t, cacheablePubdash, 3)
+		urls := viewQueryURLs(t, server)
+
+		server, _ = setup(t, cacheablePubdash, 4)
+		resp := callAPI(server, http.MethodGet, "/"+urls[4], nil, t)
+		require.Equal(t, http.StatusBadRequest, resp.Code)
+		require.Contains(t, resp.Body.String(), "publicdashboards.invalidQueryURL")
+	})
+
+	t.Run("the purge endpoint purges the public dashboard", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("PurgeCDN", mock.Anything, "pubdashuid").Return(nil).Once()
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, userAdmin)
+
+		resp := callAPI(server, http.MethodPost, "/api/dashboards/uid/dashuid/public-dashboards/pubdashuid/purge", nil, t)
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+}
@@ -47,6 +47,11 @@ func (api *Api) ViewPublicDashboard(c *contextmodel.ReqContext) response.Respons
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
+	if pubdash.CdnCacheEnabled {
+		meta.PublicDashboardQueryUrls = api.queryURLs(pubdash, dash)
+		api.setCDNCacheHeaders(c, pubdash)
+	}
+
 	dto := dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}
 
 	return response.JSON(http.StatusOK, dto)
@@ -75,7 +80,46 @@ func (api *Api) QueryPublicDashboard(c *contextmodel.ReqContext) response.Respon
 		return response.Err(err)
 	}
 
-	return toJsonStreamingResponse(api.Features, resp)
+	return toJsonStreamingResponse(c.Req.Context(), api.Features, resp)
+}
+
+// QueryPublicDashboardCacheable returns the results of a panel of a public dashboard for the default time range of
+// the dashboard, through the signed query URLs of the public dashboards cached by CDNs
+// GET /api/public/dashboards/:accessToken/panels/:panelId/query
+func (api *Api) QueryPublicDashboardCacheable(c *contextmodel.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !validation.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("QueryPublicDashboardCacheable: invalid access token"))
+	}
+
+	panelId, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+	if err != nil {
+		return response.Err(ErrInvalidPanelId.Errorf("QueryPublicDashboardCacheable: error parsing panelId %v", err))
+	}
+
+	pubdash, dash, err := api.PublicDashboardService.FindEnabledPublicDashboardAndDashboardByAccessToken(c.Req.Context(), accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+	if !pubdash.CdnCacheEnabled {
+		return response.Err(ErrInvalidQueryURL.Errorf("QueryPublicDashboardCacheable: CDN cache is not enabled for public dashboard %s", pubdash.Uid))
+	}
+
+	reqDTO, err := api.validateQueryURL(queryURLPath(accessToken, panelId), c.Req.URL.Query(), dash)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	resp, err := api.PublicDashboardService.GetQueryDataResponse(c.Req.Context(), c.SkipCache, reqDTO, panelId, accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	res := toJsonStreamingResponse(c.Req.Context(), api.Features, resp)
+	if res.Status() == http.StatusOK {
+		api.setCDNCacheHeaders(c, pubdash)
+	}
+	return res
 }
 
 // GetAnnotations returns annotations for a public dashboard
@@ -326,7 +326,8 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
 	ac := acmock.New()
 	ws := &publicdashboards.FakePublicDashboardServiceWrapper{}
 	cfg.RBACEnabled = false
-	service := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac, ws)
+	service, err := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac, ws, quotatest.New(false, nil))
+	require.NoError(t, err)
 	pubdash, err := service.Create(context.Background(), &user.SignedInUser{}, savePubDashboardCmd)
 	require.NoError(t, err)
 
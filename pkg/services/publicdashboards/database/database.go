@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
@@ -233,11 +234,12 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, share = ?, cdn_cache_enabled = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			cmd.PublicDashboard.Share,
+			cmd.PublicDashboard.CdnCacheEnabled,
 			string(timeSettingsJSON),
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
@@ -285,3 +287,40 @@ func (d *PublicDashboardStoreImpl) FindByDashboardFolder(ctx context.Context, da
 
 	return pubdashes, nil
 }
+
+// Count returns the number of public dashboards, globally and for the organization of the scope parameters
+func (d *PublicDashboardStoreImpl) Count(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	u := &quota.Map{}
+	type result struct {
+		Count int64
+	}
+
+	r := result{}
+	if err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.SQL("SELECT COUNT(*) AS count FROM dashboard_public").Get(&r)
+		return err
+	}); err != nil {
+		return u, err
+	}
+	tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.GlobalScope)
+	if err != nil {
+		return nil, err
+	}
+	u.Set(tag, r.Count)
+
+	if scopeParams != nil && scopeParams.OrgID != 0 {
+		if err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.SQL("SELECT COUNT(*) AS count FROM dashboard_public WHERE org_id = ?", scopeParams.OrgID).Get(&r)
+			return err
+		}); err != nil {
+			return u, err
+		}
+		tag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.OrgScope)
+		if err != nil {
+			return nil, err
+		}
+		u.Set(tag, r.Count)
+	}
+
+	return u, nil
+}
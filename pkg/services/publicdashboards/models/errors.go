@@ -20,6 +20,9 @@ var (
 	ErrInvalidMaxDataPoints                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
 	ErrInvalidShareType                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
+	ErrCdnCacheNotAllowed                  = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.cdnCacheNotAllowed", errutil.WithPublicMessage("Only the public dashboards shared publicly can be cached by CDNs"))
+	ErrInvalidQueryURL                     = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidQueryURL", errutil.WithPublicMessage("Invalid or outdated query URL"))
+	ErrCdnPurgeFailed                      = errutil.NewBase(errutil.StatusInternal, "publicdashboards.cdnPurgeFailed", errutil.WithPublicMessage("Failed to purge the CDN cache"))
 
 	ErrPublicDashboardNotEnabled = errutil.NewBase(errutil.StatusForbidden, "publicdashboards.notEnabled", errutil.WithPublicMessage("Public dashboard paused"))
 )
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/kinds/dashboard"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 )
 
@@ -32,6 +33,11 @@ const (
 	PublicShareType ShareType = "public"
 )
 
+const (
+	QuotaTargetSrv quota.TargetSrv = "public_dashboard"
+	QuotaTarget    quota.Target    = "public_dashboard"
+)
+
 var (
 	QueryResultStatuses = []string{QuerySuccess, QueryFailure}
 	ValidShareTypes     = []ShareType{EmailShareType, PublicShareType}
@@ -49,6 +55,7 @@ type PublicDashboard struct {
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
 	Share                ShareType     `json:"share" xorm:"share"`
+	CdnCacheEnabled      bool          `json:"cdnCacheEnabled" xorm:"cdn_cache_enabled"`
 	Recipients           []EmailDTO    `json:"recipients,omitempty" xorm:"-"`
 	CreatedBy            int64         `json:"createdBy" xorm:"created_by"`
 	UpdatedBy            int64         `json:"updatedBy" xorm:"updated_by"`
@@ -87,6 +94,11 @@ func (pd PublicDashboard) TableName() string {
 	return "dashboard_public"
 }
 
+// SurrogateKey is the key of the cacheable responses of a public dashboard, used to purge them from CDNs.
+func SurrogateKey(pd *PublicDashboard) string {
+	return "publicdashboard-" + pd.Uid
+}
+
 type PublicDashboardListResponse struct {
 	Uid          string `json:"uid" xorm:"uid"`
 	AccessToken  string `json:"accessToken" xorm:"access_token"`
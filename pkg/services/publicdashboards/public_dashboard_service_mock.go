@@ -425,6 +425,20 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
+// PurgeCDN provides a mock function with given fields: ctx, uid
+func (_m *FakePublicDashboardService) PurgeCDN(ctx context.Context, uid string) error {
+	ret := _m.Called(ctx, uid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, uid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
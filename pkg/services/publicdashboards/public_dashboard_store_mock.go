@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+
+	quota "github.com/grafana/grafana/pkg/services/quota"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -16,6 +18,29 @@ type FakePublicDashboardStore struct {
 	mock.Mock
 }
 
+// Count provides a mock function with given fields: ctx, scopeParams
+func (_m *FakePublicDashboardStore) Count(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+	ret := _m.Called(ctx, scopeParams)
+
+	var r0 *quota.Map
+	if rf, ok := ret.Get(0).(func(context.Context, *quota.ScopeParameters) *quota.Map); ok {
+		r0 = rf(ctx, scopeParams)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*quota.Map)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *quota.ScopeParameters) error); ok {
+		r1 = rf(ctx, scopeParams)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, cmd
 func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SavePublicDashboardCommand) (int64, error) {
 	ret := _m.Called(ctx, cmd)
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient/httpclientprovider"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// cdnPurger purges the responses of the public dashboards from a CDN, by calling its purge API with the surrogate
+// keys of the public dashboards.
+type cdnPurger struct {
+	url         string
+	headerName  string
+	headerValue string
+	client      *http.Client
+}
+
+func newCDNPurger(cfg *setting.Cfg) *cdnPurger {
+	p := &cdnPurger{
+		url: cfg.PublicDashboardsCDNPurgeURL,
+		client: &http.Client{
+			Timeout:   cfg.PublicDashboardsCDNPurgeTimeout,
+			Transport: httpclientprovider.OutboundRoundTripper(cfg, nil, "public-dashboards-cdn-purge", http.DefaultTransport),
+		},
+	}
+	if name, value, ok := strings.Cut(cfg.PublicDashboardsCDNPurgeHeader, ":"); ok {
+		p.headerName = strings.TrimSpace(name)
+		p.headerValue = strings.TrimSpace(value)
+	}
+	return p
+}
+
+// enabled returns true if a purge URL is configured.
+func (p *cdnPurger) enabled() bool {
+	return p != nil && p.url != ""
+}
+
+func (p *cdnPurger) purge(ctx context.Context, pubdash *PublicDashboard) error {
+	if !p.enabled() {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Surrogate-Key", SurrogateKey(pubdash))
+	if p.headerName != "" {
+		req.Header.Set(p.headerName, p.headerValue)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("purge request returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// purgeCDN purges the cached responses of a public dashboard whose responses were cacheable. The purge is best
+// effort: the responses expire after the configured cache TTL anyway.
+func (pd *PublicDashboardServiceImpl) purgeCDN(ctx context.Context, pubdash *PublicDashboard) {
+	if pubdash == nil || !pubdash.CdnCacheEnabled {
+		return
+	}
+	if err := pd.cdn.purge(ctx, pubdash); err != nil {
+		pd.log.Warn("Failed to purge the public dashboard from the CDN", "uid", pubdash.Uid, "error", err)
+	}
+}
+
+// PurgeCDN purges the cached responses of a public dashboard from the CDN.
+func (pd *PublicDashboardServiceImpl) PurgeCDN(ctx context.Context, uid string) error {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("PurgeCDN: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+	if pubdash == nil {
+		return ErrPublicDashboardNotFound.Errorf("PurgeCDN: public dashboard not found by uid: %s", uid)
+	}
+	if !pd.cdn.enabled() {
+		return ErrBadRequest.Errorf("PurgeCDN: no CDN purge URL is configured")
+	}
+
+	if err := pd.cdn.purge(ctx, pubdash); err != nil {
+		return ErrCdnPurgeFailed.Errorf("PurgeCDN: failed to purge public dashboard %s: %w", uid, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestPurgeCDN(t *testing.T) {
+	setup := func(t *testing.T, status int) (*PublicDashboardServiceImpl, *[]*http.Request) {
+		var requests []*http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+
+		cfg := setting.NewCfg()
+		cfg.PublicDashboardsCDNPurgeURL = server.URL
+		cfg.PublicDashboardsCDNPurgeHeader = "Fastly-Key: secret"
+		cfg.PublicDashboardsCDNPurgeTimeout = time.Second
+
+		store := publicdashboards.NewFakePublicDashboardStore(t)
+		store.On("Find", mock.Anything, "pubdashuid").Return(&PublicDashboard{Uid: "pubdashuid", CdnCacheEnabled: true}, nil).Maybe()
+		store.On("Find", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+		return &PublicDashboardServiceImpl{
+			log:   log.New("test.logger"),
+			store: store,
+			cdn:   newCDNPurger(cfg),
+		}, &requests
+	}
+
+	t.Run("purges the surrogate key of the public dashboard", func(t *testing.T) {
+		service, requests := setup(t, http.StatusOK)
+
+		require.NoError(t, service.PurgeCDN(context.Background(), "pubdashuid"))
+		require.Len(t, *requests, 1)
+		assert.Equal(t, http.MethodPost, (*requests)[0].Method)
+		assert.Equal(t, "publicdashboard-pubdashuid", (*requests)[0].Header.Get("Surrogate-Key"))
+		assert.Equal(t, "secret", (*requests)[0].Header.Get("Fastly-Key"))
+	})
+
+	t.Run("returns an error when the CDN rejects the purge", func(t *testing.T) {
+		service, _ := setup(t, http.StatusForbidden)
+
+		err := service.PurgeCDN(context.Background(), "pubdashuid")
+		require.ErrorIs(t, err, ErrCdnPurgeFailed)
+	})
+
+	t.Run("returns an error when the public dashboard does not exist", func(t *testing.T) {
+		service, requests := setup(t, http.StatusOK)
+
+		err := service.PurgeCDN(context.Background(), "missing")
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+		require.Empty(t, *requests)
+	})
+
+	t.Run("returns an error when no purge URL is configured", func(t *testing.T) {
+		service, _ := setup(t, http.StatusOK)
+		service.cdn = newCDNPurger(setting.NewCfg())
+
+		err := service.PurgeCDN(context.Background(), "pubdashuid")
+		require.ErrorIs(t, err, ErrBadRequest)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
@@ -34,6 +35,7 @@ type PublicDashboardServiceImpl struct {
 	AnnotationsRepo    annotations.Repository
 	ac                 accesscontrol.AccessControl
 	serviceWrapper     publicdashboards.ServiceWrapper
+	cdn                *cdnPurger
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -51,8 +53,9 @@ func ProvideService(
 	anno annotations.Repository,
 	ac accesscontrol.AccessControl,
 	serviceWrapper publicdashboards.ServiceWrapper,
-) *PublicDashboardServiceImpl {
-	return &PublicDashboardServiceImpl{
+	quotaService quota.Service,
+) (*PublicDashboardServiceImpl, error) {
+	s := &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
 		cfg:                cfg,
 		store:              store,
@@ -61,7 +64,23 @@ func ProvideService(
 		AnnotationsRepo:    anno,
 		ac:                 ac,
 		serviceWrapper:     serviceWrapper,
+		cdn:                newCDNPurger(cfg),
 	}
+
+	defaultLimits, err := readQuotaConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+		TargetSrv:     QuotaTargetSrv,
+		DefaultLimits: defaultLimits,
+		Reporter:      store.Count,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
 // FindByDashboardUid this method would be replaced by another implementation for Enterprise version
@@ -170,6 +189,10 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.Share = PublicShareType
 	}
 
+	if dto.PublicDashboard.CdnCacheEnabled && dto.PublicDashboard.Share != PublicShareType {
+		return nil, ErrCdnCacheNotAllowed.Errorf("Create: public dashboards shared by %s cannot be cached by CDNs", dto.PublicDashboard.Share)
+	}
+
 	uid, err := pd.NewPublicDashboardUid(ctx)
 	if err != nil {
 		return nil, err
@@ -190,6 +213,7 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
 			Share:                dto.PublicDashboard.Share,
+			CdnCacheEnabled:      dto.PublicDashboard.CdnCacheEnabled,
 			CreatedBy:            dto.UserId,
 			CreatedAt:            time.Now(),
 			AccessToken:          accessToken,
@@ -249,6 +273,10 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.Share = existingPubdash.Share
 	}
 
+	if dto.PublicDashboard.CdnCacheEnabled && dto.PublicDashboard.Share != PublicShareType {
+		return nil, ErrCdnCacheNotAllowed.Errorf("Update: public dashboards shared by %s cannot be cached by CDNs", dto.PublicDashboard.Share)
+	}
+
 	// set values to update
 	cmd := SavePublicDashboardCommand{
 		PublicDashboard: PublicDashboard{
@@ -258,6 +286,7 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
 			Share:                dto.PublicDashboard.Share,
+			CdnCacheEnabled:      dto.PublicDashboard.CdnCacheEnabled,
 			UpdatedBy:            dto.UserId,
 			UpdatedAt:            time.Now(),
 		},
@@ -281,6 +310,8 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 	}
 
 	pd.logIsEnabledChanged(existingPubdash, newPubdash, u)
+	// the responses cached with the previous configuration are outdated
+	pd.purgeCDN(ctx, existingPubdash)
 
 	return newPubdash, nil
 }
@@ -340,7 +371,20 @@ func (pd *PublicDashboardServiceImpl) GetOrgIdByAccessToken(ctx context.Context,
 }
 
 func (pd *PublicDashboardServiceImpl) Delete(ctx context.Context, uid string) error {
-	return pd.serviceWrapper.Delete(ctx, uid)
+	if !pd.cdn.enabled() {
+		return pd.serviceWrapper.Delete(ctx, uid)
+	}
+
+	// the public dashboard is loaded before it is deleted to purge its responses
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("Delete: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+	if err := pd.serviceWrapper.Delete(ctx, uid); err != nil {
+		return err
+	}
+	pd.purgeCDN(ctx, pubdash)
+	return nil
 }
 
 func (pd *PublicDashboardServiceImpl) DeleteByDashboard(ctx context.Context, dashboard *dashboards.Dashboard) error {
@@ -356,6 +400,7 @@ func (pd *PublicDashboardServiceImpl) DeleteByDashboard(ctx context.Context, das
 			if err != nil {
 				return err
 			}
+			pd.purgeCDN(ctx, pubdash)
 		}
 
 		return nil
@@ -369,7 +414,11 @@ func (pd *PublicDashboardServiceImpl) DeleteByDashboard(ctx context.Context, das
 		return nil
 	}
 
-	return pd.serviceWrapper.Delete(ctx, pubdash.Uid)
+	if err := pd.serviceWrapper.Delete(ctx, pubdash.Uid); err != nil {
+		return err
+	}
+	pd.purgeCDN(ctx, pubdash)
+	return nil
 }
 
 // intervalMS and maxQueryData values are being calculated on the frontend for regular dashboards
@@ -448,3 +497,24 @@ func GenerateAccessToken() (string, error) {
 	}
 	return fmt.Sprintf("%x", token[:]), nil
 }
+
+func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
+	limits := &quota.Map{}
+
+	if cfg == nil {
+		return limits, nil
+	}
+
+	globalQuotaTag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.GlobalScope)
+	if err != nil {
+		return &quota.Map{}, err
+	}
+	orgQuotaTag, err := quota.NewTag(QuotaTargetSrv, QuotaTarget, quota.OrgScope)
+	if err != nil {
+		return &quota.Map{}, err
+	}
+
+	limits.Set(globalQuotaTag, cfg.Quota.Global.PublicDashboard)
+	limits.Set(orgQuotaTag, cfg.Quota.Org.PublicDashboard)
+	return limits, nil
+}
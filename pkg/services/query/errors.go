@@ -10,4 +10,7 @@ var (
 	ErrMissingDataSourceInfo = errutil.NewBase(errutil.StatusBadRequest, "query.missingDataSourceInfo").MustTemplate("query missing datasource info: {{ .Public.RefId }}", errutil.WithPublic("Query {{ .Public.RefId }} is missing datasource information"))
 	ErrQueryParamMismatch    = errutil.NewBase(errutil.StatusBadRequest, "query.headerMismatch", errutil.WithPublicMessage("The request headers point to a different plugin than is defined in the request body")).Errorf("plugin header/body mismatch")
 	ErrDuplicateRefId        = errutil.NewBase(errutil.StatusBadRequest, "query.duplicateRefId", errutil.WithPublicMessage("Multiple queries using the same RefId is not allowed ")).Errorf("multiple queries using the same RefId is not allowed")
+	ErrQueryNotSaved         = errutil.NewBase(errutil.StatusForbidden, "query.notSaved").MustTemplate("query {{ .Public.RefId }} is not saved in the dashboard panel", errutil.WithPublic("Query {{ .Public.RefId }} is not one of the queries saved in the dashboard panel"))
+	ErrSavedPanelRequired    = errutil.NewBase(errutil.StatusForbidden, "query.savedPanelRequired", errutil.WithPublicMessage("Only the queries saved in dashboard panels can be run"))
+	ErrRawRequestRestricted  = errutil.NewBase(errutil.StatusForbidden, "query.rawRequestRestricted", errutil.WithPublicMessage("Only the queries saved in dashboard panels can be run, the data source proxy and resources are not available"))
 )
@@ -0,0 +1,116 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// AlignResponsesByTime outer joins the time series frames of the responses with the given refIds into a single
+// wide frame, with one time field holding the times of all the frames in ascending order. The value fields keep
+// their name, labels and config, and are null at the times missing from their frame. Long frames are converted
+// to wide ones first.
+//
+// The errors of the failed queries, and of the responses with frames that are not time series, are returned by
+// refId. Their frames are left out of the joined frame.
+func AlignResponsesByTime(responses backend.Responses, refIDs []string) (*data.Frame, map[string]error) {
+	errs := map[string]error{}
+	var series []*data.Frame
+	var timeIndices []int
+	for _, refID := range refIDs {
+		res, ok := responses[refID]
+		if !ok {
+			errs[refID] = fmt.Errorf("no response for refId %s", refID)
+			continue
+		}
+		if res.Error != nil {
+			errs[refID] = res.Error
+			continue
+		}
+
+		refSeries := make([]*data.Frame, 0, len(res.Frames))
+		refTimeIndices := make([]int, 0, len(res.Frames))
+		for _, frame := range res.Frames {
+			wide, timeIndex, err := toWideTimeSeries(frame)
+			if err != nil {
+				errs[refID] = err
+				break
+			}
+			refSeries = append(refSeries, wide)
+			refTimeIndices = append(refTimeIndices, timeIndex)
+		}
+		if _, failed := errs[refID]; !failed {
+			series = append(series, refSeries...)
+			timeIndices = append(timeIndices, refTimeIndices...)
+		}
+	}
+
+	times := map[int64]time.Time{}
+	for i, frame := range series {
+		timeField := frame.Fields[timeIndices[i]]
+		for row := 0; row < timeField.Len(); row++ {
+			if t, ok := timeField.ConcreteAt(row); ok {
+				times[t.(time.Time).UnixNano()] = t.(time.Time)
+			}
+		}
+	}
+
+	keys := make([]int64, 0, len(times))
+	for key := range times {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	sortedTimes := make([]time.Time, len(keys))
+	rows := make(map[int64]int, len(keys))
+	for row, key := range keys {
+		sortedTimes[row] = times[key]
+		rows[key] = row
+	}
+
+	aligned := data.NewFrame("", data.NewField("Time", nil, sortedTimes))
+	for i, frame := range series {
+		timeField := frame.Fields[timeIndices[i]]
+		for fieldIndex, field := range frame.Fields {
+			if fieldIndex == timeIndices[i] {
+				continue
+			}
+
+			alignedField := data.NewFieldFromFieldType(field.Type().NullableType(), len(sortedTimes))
+			alignedField.Name = field.Name
+			alignedField.Labels = field.Labels
+			alignedField.Config = field.Config
+			for row := 0; row < field.Len(); row++ {
+				t, ok := timeField.ConcreteAt(row)
+				if !ok {
+					continue
+				}
+				if v, ok := field.ConcreteAt(row); ok {
+					alignedField.SetConcrete(rows[t.(time.Time).UnixNano()], v)
+				}
+			}
+			aligned.Fields = append(aligned.Fields, alignedField)
+		}
+	}
+	return aligned, errs
+}
+
+// toWideTimeSeries returns the frame as a wide time series, with the index of its time field
+func toWideTimeSeries(frame *data.Frame) (*data.Frame, int, error) {
+	schema := frame.TimeSeriesSchema()
+	switch schema.Type {
+	case data.TimeSeriesTypeWide:
+		return frame, schema.TimeIndex, nil
+	case data.TimeSeriesTypeLong:
+		wide, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		return wide, wide.TimeSeriesSchema().TimeIndex, nil
+	default:
+		return nil, 0, fmt.Errorf("frame %q is not a time series", frame.Name)
+	}
+}
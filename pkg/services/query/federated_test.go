@@ -0,0 +1,71 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignResponsesByTime(t *testing.T) {
+	t0 := time.Unix(0, 0).UTC()
+	t1 := t0.Add(time.Minute)
+	t2 := t0.Add(2 * time.Minute)
+
+	responses := backend.Responses{
+		"A": backend.DataResponse{Frames: data.Frames{
+			data.NewFrame("a",
+				data.NewField("time", nil, []time.Time{t0, t1}),
+				data.NewField("cpu", data.Labels{"host": "a"}, []float64{1, 2}),
+			),
+		}},
+		"B": backend.DataResponse{Frames: data.Frames{
+			data.NewFrame("b",
+				data.NewField("time", nil, []time.Time{t1, t2}),
+				data.NewField("mem", nil, []*int64{int64Ptr(10), nil}),
+			),
+		}},
+		"C": backend.DataResponse{Error: errors.New("query failed")},
+		"D": backend.DataResponse{Frames: data.Frames{
+			data.NewFrame("table", data.NewField("value", nil, []float64{1})),
+		}},
+	}
+
+	aligned, errs := AlignResponsesByTime(responses, []string{"A", "B", "C", "D", "E"})
+
+	require.Len(t, errs, 3)
+	require.EqualError(t, errs["C"], "query failed")
+	require.EqualError(t, errs["D"], `frame "table" is not a time series`)
+	require.EqualError(t, errs["E"], "no response for refId E")
+
+	require.Len(t, aligned.Fields, 3)
+	require.Equal(t, 3, aligned.Rows())
+	require.Equal(t, t0, aligned.Fields[0].At(0))
+	require.Equal(t, t2, aligned.Fields[0].At(2))
+
+	cpu := aligned.Fields[1]
+	require.Equal(t, "cpu", cpu.Name)
+	require.Equal(t, data.Labels{"host": "a"}, cpu.Labels)
+	require.Equal(t, []interface{}{1.0, 2.0, nil}, concreteValues(cpu))
+
+	mem := aligned.Fields[2]
+	require.Equal(t, "mem", mem.Name)
+	require.Equal(t, []interface{}{nil, int64(10), nil}, concreteValues(mem))
+}
+
+func concreteValues(field *data.Field) []interface{} {
+	values := make([]interface{}, field.Len())
+	for i := range values {
+		if v, ok := field.ConcreteAt(i); ok {
+			values[i] = v
+		}
+	}
+	return values
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
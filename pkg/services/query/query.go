@@ -3,9 +3,11 @@ package query
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -13,8 +15,10 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/plugins/backendplugin"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/adapters"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/services/validations"
 	"github.com/grafana/grafana/pkg/setting"
@@ -55,6 +59,7 @@ func ProvideService(
 type Service interface {
 	Run(ctx context.Context) error
 	QueryData(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error)
+	QueryDataChunked(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, w backendplugin.ChunkedDataWriter) error
 }
 
 // Gives us compile time error if the service does not adhere to the contract of the interface
@@ -83,7 +88,45 @@ func (s *ServiceImpl) QueryData(ctx context.Context, user *user.SignedInUser, sk
 	if err != nil {
 		return nil, err
 	}
+	return s.queryParsedRequest(ctx, user, skipCache, reqDTO, parsedReq)
+}
+
+// QueryDataChunked processes queries like QueryData, but writes the frames to w instead of returning them.
+// Data sources that stream their responses have their frames written as they arrive, without buffering the
+// whole response. The responses of the other data sources, and of expressions, are written once complete.
+func (s *ServiceImpl) QueryDataChunked(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, w backendplugin.ChunkedDataWriter) error {
+	parsedReq, err := s.parseMetricRequest(ctx, user, skipCache, reqDTO)
+	if err != nil {
+		return err
+	}
+
+	sw := &syncChunkedDataWriter{w: w}
+	// expressions need the complete responses of the queries they are computed from
+	if !parsedReq.hasExpression {
+		ctx = backendplugin.WithChunkedDataWriter(ctx, sw)
+	}
+
+	resp, err := s.queryParsedRequest(ctx, user, skipCache, reqDTO, parsedReq)
+	if err != nil {
+		return err
+	}
 
+	for refID, res := range resp.Responses {
+		if res.Error != nil {
+			if err := sw.WriteError(refID, res.Error); err != nil {
+				return err
+			}
+		}
+		for _, frame := range res.Frames {
+			if err := sw.WriteFrame(refID, frame); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ServiceImpl) queryParsedRequest(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	// If there are expressions, handle them and return
 	if parsedReq.hasExpression {
 		return s.handleExpressions(ctx, user, parsedReq)
@@ -155,6 +198,24 @@ func (s *ServiceImpl) executeConcurrentQueries(ctx context.Context, user *user.S
 	return resp, nil
 }
 
+// syncChunkedDataWriter serializes the writes of the data sources queried concurrently
+type syncChunkedDataWriter struct {
+	mu sync.Mutex
+	w  backendplugin.ChunkedDataWriter
+}
+
+func (sw *syncChunkedDataWriter) WriteFrame(refID string, frame *data.Frame) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.WriteFrame(refID, frame)
+}
+
+func (sw *syncChunkedDataWriter) WriteError(refID string, err error) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.w.WriteError(refID, err)
+}
+
 // buildErrorResponses applies the provided error to each query response in the list. These queries should all belong to the same datasource.
 func buildErrorResponses(err error, queries []*simplejson.Json) backend.Responses {
 	er := backend.Responses{}
@@ -353,6 +414,6 @@ func (s *ServiceImpl) getDataSourceFromQuery(ctx context.Context, user *user.Sig
 
 func (s *ServiceImpl) decryptSecureJsonDataFn(ctx context.Context) func(ds *datasources.DataSource) (map[string]string, error) {
 	return func(ds *datasources.DataSource) (map[string]string, error) {
-		return s.dataSourceService.DecryptedValues(ctx, ds)
+		return s.dataSourceService.DecryptedValues(secrets.WithConsumer(ctx, "query"), ds)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	backend "github.com/grafana/grafana-plugin-sdk-go/backend"
+	backendplugin "github.com/grafana/grafana/pkg/plugins/backendplugin"
 
 	dtos "github.com/grafana/grafana/pkg/api/dtos"
 
@@ -42,6 +43,20 @@ func (_m *FakeQueryService) QueryData(ctx context.Context, _a1 *user.SignedInUse
 	return r0, r1
 }
 
+// QueryDataChunked provides a mock function with given fields: ctx, _a1, skipCache, reqDTO, w
+func (_m *FakeQueryService) QueryDataChunked(ctx context.Context, _a1 *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest, w backendplugin.ChunkedDataWriter) error {
+	ret := _m.Called(ctx, _a1, skipCache, reqDTO, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, bool, dtos.MetricRequest, backendplugin.ChunkedDataWriter) error); ok {
+		r0 = rf(ctx, _a1, skipCache, reqDTO, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Run provides a mock function with given fields: ctx
 func (_m *FakeQueryService) Run(ctx context.Context) error {
 	ret := _m.Called(ctx)
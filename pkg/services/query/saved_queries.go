@@ -0,0 +1,400 @@
+package query
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// runtimeQueryKeys are the keys that the frontend adds to the queries of a panel when it runs them,
+// they are not part of the query model saved in the dashboard.
+var runtimeQueryKeys = map[string]bool{
+	"datasource":      true,
+	"datasourceId":    true,
+	"intervalMs":      true,
+	"maxDataPoints":   true,
+	"queryCachingTTL": true,
+	"requestId":       true,
+	"utcOffsetSec":    true,
+}
+
+// templateVariableRegexp matches the $var, ${var} and [[var]] template variable syntaxes.
+var templateVariableRegexp = regexp.MustCompile(`\$\w+|\$\{[^}]+\}|\[\[[^\]]+\]\]`)
+
+// valueTokenPattern matches the values of the template variables whose values are not saved in the dashboard,
+// such as text box variables, the variables refreshed when the dashboard loads and the built-in variables. They
+// can't contain quotes, spaces or operators, so a viewer can't rewrite a query through them.
+const valueTokenPattern = `[\w.:@+\-]*`
+
+// SavedQueries are the queries of a dashboard panel as saved in the dashboard. The queries without
+// template variables are matched by the hash of their model, the queries with template variables are
+// matched by their structure, a template variable matching the values it can take in the dashboard.
+type SavedQueries struct {
+	hashes    map[string]*simplejson.Json
+	templates []savedQuery
+	variables map[string]*variable
+}
+
+type savedQuery struct {
+	datasource *simplejson.Json
+	model      interface{}
+}
+
+// variable is a template variable of the dashboard.
+type variable struct {
+	kind string
+	// query is the plugin type of the data sources of a data source variable
+	query string
+	regex string
+	// values are the values the variable can take, when saved in the dashboard
+	values []string
+}
+
+// FindPanel returns the panel of the dashboard with the ID, including the panels of collapsed rows.
+func FindPanel(dashboard *simplejson.Json, panelID int64) (*simplejson.Json, bool) {
+	for _, panelObj := range dashboard.Get("panels").MustArray() {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("id").MustInt64() == panelID {
+			return panel, true
+		}
+		if panel.Get("type").MustString() == "row" {
+			if p, ok := FindPanel(panel, panelID); ok {
+				return p, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// NewSavedQueries returns the queries of the panel of the dashboard. The queries without a data source use the
+// data source of the panel.
+func NewSavedQueries(dashboard, panel *simplejson.Json) *SavedQueries {
+	s := &SavedQueries{
+		hashes:    make(map[string]*simplejson.Json),
+		variables: dashboardVariables(dashboard),
+	}
+	panelDatasource := panel.Get("datasource")
+	for _, target := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(target)
+		ds := panelDatasource
+		if _, ok := query.CheckGet("datasource"); ok {
+			ds = query.Get("datasource")
+		}
+		model := normalizeQuery(query)
+		if hasTemplateVariables(model) {
+			s.templates = append(s.templates, savedQuery{datasource: ds, model: model})
+			continue
+		}
+		s.hashes[hashQuery(model)] = ds
+	}
+	return s
+}
+
+// Contains returns true if the query of the request is one of the saved queries, run against the data
+// source ds it is saved with.
+func (s *SavedQueries) Contains(query *simplejson.Json, ds *datasources.DataSource) bool {
+	if ds == nil {
+		return false
+	}
+	model := normalizeQuery(query)
+	if saved, ok := s.hashes[hashQuery(model)]; ok && s.matchDatasource(saved, ds) {
+		return true
+	}
+	for _, saved := range s.templates {
+		if s.matchDatasource(saved.datasource, ds) && s.matchTemplate(saved.model, model) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchDatasource returns true if ds is the saved data source. The data source is saved as a reference with
+// a UID, or as a name in the dashboards saved before the data source references were introduced, the default
+// data source when there is none. A template variable is resolved to the data sources it can take.
+func (s *SavedQueries) matchDatasource(saved *simplejson.Json, ds *datasources.DataSource) bool {
+	var ref string
+	byUID := true
+	switch value := saved.Interface().(type) {
+	case nil:
+		return ds.IsDefault
+	case string:
+		ref, byUID = value, false
+	case map[string]interface{}:
+		ref = saved.Get("uid").MustString()
+		if ref == "" {
+			return ds.IsDefault
+		}
+	default:
+		return false
+	}
+
+	if !templateVariableRegexp.MatchString(ref) {
+		return ref == ds.UID || (!byUID && ref == ds.Name)
+	}
+	v, ok := s.variables[variableName(ref)]
+	if !ok {
+		return false
+	}
+	if v.kind == "datasource" {
+		if v.query != ds.Type {
+			return false
+		}
+		if v.regex == "" {
+			return true
+		}
+		re, err := regexp.Compile(strings.Trim(v.regex, "/"))
+		return err == nil && re.MatchString(ds.Name)
+	}
+	for _, value := range v.values {
+		if value == ds.UID || value == ds.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// dashboardVariables returns the template variables of the dashboard by name.
+func dashboardVariables(dashboard *simplejson.Json) map[string]*variable {
+	variables := make(map[string]*variable)
+	for _, item := range dashboard.Get("templating").Get("list").MustArray() {
+		v := simplejson.NewFromAny(item)
+		name := v.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+		kind := v.Get("type").MustString()
+		variables[name] = &variable{
+			kind:   kind,
+			query:  v.Get("query").MustString(),
+			regex:  v.Get("regex").MustString(),
+			values: variableValues(kind, v),
+		}
+	}
+	return variables
+}
+
+// variableValues returns the values the variable can take when they are saved in the dashboard: the values
+// of the custom, constant and interval variables, and of the query variables that are not refreshed. It
+// returns nil for the other variables.
+func variableValues(kind string, v *simplejson.Json) []string {
+	var values []string
+	add := func(value interface{}) {
+		switch value := value.(type) {
+		case string:
+			if value != "$__all" {
+				values = append(values, value)
+			}
+		case []interface{}:
+			for _, item := range value {
+				if s, ok := item.(string); ok && s != "$__all" {
+					values = append(values, s)
+				}
+			}
+		}
+	}
+
+	switch kind {
+	case "constant":
+		add(v.Get("query").Interface())
+		return values
+	case "custom", "interval":
+	case "query":
+		if v.Get("refresh").MustInt() != 0 {
+			return nil
+		}
+	default:
+		return nil
+	}
+	for _, option := range v.Get("options").MustArray() {
+		add(simplejson.NewFromAny(option).Get("value").Interface())
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	add(v.Get("current").Get("value").Interface())
+	if allValue := v.Get("allValue").MustString(); allValue != "" {
+		values = append(values, allValue)
+	}
+	return values
+}
+
+// variableName returns the name of the variable of a $var, ${var:format} or [[var:format]] reference, the
+// name of the built-in variables such as ${__user.login} stops at the dot.
+func variableName(ref string) string {
+	name := strings.TrimLeft(ref, "$[{")
+	name = strings.TrimRight(name, "]}")
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+	if strings.HasPrefix(name, "__") {
+		if i := strings.Index(name, "."); i >= 0 {
+			name = name[:i]
+		}
+	}
+	return name
+}
+
+func normalizeQuery(query *simplejson.Json) interface{} {
+	m, err := query.Map()
+	if err != nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if !runtimeQueryKeys[k] {
+			result[k] = normalizeValue(v)
+		}
+	}
+	return result
+}
+
+// normalizeValue converts the numbers to float64, the saved dashboards and the requests are not decoded
+// in the same way.
+func normalizeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			result[k] = normalizeValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = normalizeValue(item)
+		}
+		return result
+	case json.Number:
+		f, err := value.Float64()
+		if err != nil {
+			return value.String()
+		}
+		return f
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	default:
+		return v
+	}
+}
+
+// hashQuery returns the hash of the query model. The keys of the JSON objects are sorted when
+// they are encoded, so the hash does not depend on their order.
+func hashQuery(model interface{}) string {
+	b, err := json.Marshal(model)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+func hasTemplateVariables(v interface{}) bool {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for _, item := range value {
+			if hasTemplateVariables(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range value {
+			if hasTemplateVariables(item) {
+				return true
+			}
+		}
+	case string:
+		return templateVariableRegexp.MatchString(value)
+	}
+	return false
+}
+
+// matchTemplate returns true if the requested query has the structure of the saved query, and the
+// same values but for the template variables of the saved strings.
+func (s *SavedQueries) matchTemplate(saved, requested interface{}) bool {
+	switch v := saved.(type) {
+	case map[string]interface{}:
+		r, ok := requested.(map[string]interface{})
+		if !ok || len(r) != len(v) {
+			return false
+		}
+		for k, item := range v {
+			ri, ok := r[k]
+			if !ok || !s.matchTemplate(item, ri) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		r, ok := requested.([]interface{})
+		if !ok || len(r) != len(v) {
+			return false
+		}
+		for i := range v {
+			if !s.matchTemplate(v[i], r[i]) {
+				return false
+			}
+		}
+		return true
+	case string:
+		if !templateVariableRegexp.MatchString(v) {
+			return v == requested
+		}
+		// a variable can be replaced by a number, e.g. the limit of a query
+		var r string
+		switch value := requested.(type) {
+		case string:
+			r = value
+		case float64:
+			r = fmt.Sprint(value)
+		default:
+			return false
+		}
+		re, err := s.templatePattern(v)
+		return err == nil && re.MatchString(r)
+	default:
+		return saved == requested
+	}
+}
+
+// templatePattern returns a regular expression matching the string with its template variables replaced
+// by the values they can take, or left as they are for the datasource to interpolate them.
+func (s *SavedQueries) templatePattern(str string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+	last := 0
+	for _, loc := range templateVariableRegexp.FindAllStringIndex(str, -1) {
+		ref := str[loc[0]:loc[1]]
+		b.WriteString(regexp.QuoteMeta(str[last:loc[0]]))
+		b.WriteString(`(?:`)
+		b.WriteString(regexp.QuoteMeta(ref))
+		b.WriteString(`|`)
+		b.WriteString(s.valuePattern(variableName(ref)))
+		b.WriteString(`)`)
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(str[last:]))
+	b.WriteString(`$`)
+	return regexp.Compile(b.String())
+}
+
+// valuePattern returns the pattern of the values of a variable. A multi-value variable is interpolated as a
+// list of its values, quoted or escaped for a regular expression, separated by commas or pipes and enclosed
+// in parentheses or braces depending on the data source.
+func (s *SavedQueries) valuePattern(name string) string {
+	v, ok := s.variables[name]
+	if !ok || len(v.values) == 0 {
+		return valueTokenPattern
+	}
+	alternatives := make([]string, 0, 2*len(v.values))
+	for _, value := range v.values {
+		alternatives = append(alternatives, regexp.QuoteMeta(value), regexp.QuoteMeta(regexp.QuoteMeta(value)))
+	}
+	item := `['"]?(?:` + strings.Join(alternatives, `|`) + `)['"]?`
+	return `[({]?` + item + `(?:[,|]` + item + `)*[)}]?`
+}
@@ -0,0 +1,159 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+const savedDashboard = `{
+	"templating": {"list": [
+		{"name": "job", "type": "custom", "options": [{"value": "api"}, {"value": "web"}], "current": {"value": "api"}},
+		{"name": "host", "type": "textbox", "current": {"value": "db-1"}},
+		{"name": "ds", "type": "datasource", "query": "mysql", "regex": "/prod/"}
+	]},
+	"panels": [{
+		"id": 1,
+		"datasource": {"type": "prometheus", "uid": "prom"},
+		"targets": [
+			{"refId": "A", "expr": "up", "legendFormat": "{{instance}}"},
+			{"refId": "B", "expr": "rate(http_requests_total{job=\"$job\"}[$__rate_interval])", "datasource": {"uid": "other"}}
+		]
+	}, {
+		"id": 2,
+		"type": "row",
+		"collapsed": true,
+		"panels": [{
+			"id": 3,
+			"datasource": {"type": "mysql", "uid": "${ds}"},
+			"targets": [{"refId": "A", "rawSql": "SELECT * FROM t WHERE host = '$host' LIMIT $limit", "format": "table"}]
+		}, {
+			"id": 4,
+			"datasource": null,
+			"targets": [{"refId": "A", "expr": "up"}]
+		}]
+	}]
+}`
+
+func TestSavedQueries(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(savedDashboard))
+	require.NoError(t, err)
+
+	parse := func(t *testing.T, s string) *simplejson.Json {
+		t.Helper()
+		q, err := simplejson.NewJson([]byte(s))
+		require.NoError(t, err)
+		return q
+	}
+
+	t.Run("should find the panels of collapsed rows", func(t *testing.T) {
+		panel, ok := FindPanel(dashboard, 3)
+		require.True(t, ok)
+		require.Equal(t, int64(3), panel.Get("id").MustInt64())
+
+		_, ok = FindPanel(dashboard, 5)
+		require.False(t, ok)
+	})
+
+	prom := &datasources.DataSource{UID: "prom", Type: "prometheus"}
+	other := &datasources.DataSource{UID: "other", Type: "prometheus"}
+	panel, _ := FindPanel(dashboard, 1)
+	saved := NewSavedQueries(dashboard, panel)
+
+	testCases := []struct {
+		desc     string
+		query    string
+		ds       *datasources.DataSource
+		expected bool
+	}{
+		{
+			desc:     "saved query with the runtime keys added by the frontend",
+			query:    `{"legendFormat": "{{instance}}", "expr": "up", "refId": "A", "datasource": {"uid": "prom"}, "datasourceId": 1, "intervalMs": 15000, "maxDataPoints": 1000}`,
+			ds:       prom,
+			expected: true,
+		},
+		{
+			desc:     "modified query",
+			query:    `{"refId": "A", "expr": "secret_metric", "legendFormat": "{{instance}}", "datasource": {"uid": "prom"}}`,
+			ds:       prom,
+			expected: false,
+		},
+		{
+			desc:     "saved query with an additional key",
+			query:    `{"refId": "A", "expr": "up", "legendFormat": "{{instance}}", "instant": true, "datasource": {"uid": "prom"}}`,
+			ds:       prom,
+			expected: false,
+		},
+		{
+			desc:     "saved query run against another data source",
+			query:    `{"refId": "A", "expr": "up", "legendFormat": "{{instance}}", "datasource": {"uid": "other"}}`,
+			ds:       other,
+			expected: false,
+		},
+		{
+			desc:     "saved query with the value of a template variable",
+			query:    `{"refId": "B", "expr": "rate(http_requests_total{job=\"api\"}[$__rate_interval])", "datasource": {"uid": "other"}}`,
+			ds:       other,
+			expected: true,
+		},
+		{
+			desc:     "saved query with a value the template variable can't take",
+			query:    `{"refId": "B", "expr": "rate(http_requests_total{job=\"admin\"}[$__rate_interval])", "datasource": {"uid": "other"}}`,
+			ds:       other,
+			expected: false,
+		},
+		{
+			desc:     "saved query with the values of a multi-value template variable",
+			query:    `{"refId": "B", "expr": "rate(http_requests_total{job=\"(api|web)\"}[1m])", "datasource": {"uid": "other"}}`,
+			ds:       other,
+			expected: true,
+		},
+		{
+			desc:     "query rewritten through a template variable",
+			query:    `{"refId": "B", "expr": "rate(http_requests_total{job=\"api\"}[1m])) or secret_metric or (up[1m])", "datasource": {"uid": "other"}}`,
+			ds:       other,
+			expected: false,
+		},
+		{
+			desc:     "query modified around a template variable",
+			query:    `{"refId": "B", "expr": "sum(rate(http_requests_total{job=\"api\"}[1m]))", "datasource": {"uid": "other"}}`,
+			ds:       other,
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			require.Equal(t, tc.expected, saved.Contains(parse(t, tc.query), tc.ds))
+		})
+	}
+
+	t.Run("should only match the values of the template variables", func(t *testing.T) {
+		panel, _ := FindPanel(dashboard, 3)
+		saved := NewSavedQueries(dashboard, panel)
+		ds := &datasources.DataSource{UID: "mysql-prod", Name: "MySQL prod", Type: "mysql"}
+		require.True(t, saved.Contains(parse(t, `{"refId": "A", "rawSql": "SELECT * FROM t WHERE host = 'db-2' LIMIT 10", "format": "table", "datasource": {"uid": "mysql-prod"}}`), ds))
+		require.False(t, saved.Contains(parse(t, `{"refId": "A", "rawSql": "SELECT * FROM t WHERE host = 'x' OR 1=1; DROP TABLE t; --' LIMIT 10", "format": "table", "datasource": {"uid": "mysql-prod"}}`), ds))
+		require.False(t, saved.Contains(parse(t, `{"refId": "A", "rawSql": "SELECT * FROM users", "format": "table", "datasource": {"uid": "mysql-prod"}}`), ds))
+	})
+
+	t.Run("should resolve the data source variables to the data sources they can take", func(t *testing.T) {
+		panel, _ := FindPanel(dashboard, 3)
+		saved := NewSavedQueries(dashboard, panel)
+		q := parse(t, `{"refId": "A", "rawSql": "SELECT * FROM t WHERE host = 'db-1' LIMIT 10", "format": "table"}`)
+		require.True(t, saved.Contains(q, &datasources.DataSource{UID: "mysql-prod", Name: "MySQL prod", Type: "mysql"}))
+		require.False(t, saved.Contains(q, &datasources.DataSource{UID: "mysql-dev", Name: "MySQL dev", Type: "mysql"}))
+		require.False(t, saved.Contains(q, &datasources.DataSource{UID: "pg-prod", Name: "Postgres prod", Type: "postgres"}))
+	})
+
+	t.Run("should resolve the queries without data source to the default data source", func(t *testing.T) {
+		panel, _ := FindPanel(dashboard, 4)
+		saved := NewSavedQueries(dashboard, panel)
+		q := parse(t, `{"refId": "A", "expr": "up"}`)
+		require.True(t, saved.Contains(q, &datasources.DataSource{UID: "prom", Type: "prometheus", IsDefault: true}))
+		require.False(t, saved.Contains(q, prom))
+		require.False(t, saved.Contains(q, nil))
+	})
+}
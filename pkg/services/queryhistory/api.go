@@ -1,7 +1,12 @@
 package queryhistory
 
 import (
+	"bytes"
+	"encoding/csv"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -16,10 +21,12 @@ func (s *QueryHistoryService) registerAPIEndpoints() {
 	s.RouteRegister.Group("/api/query-history", func(entities routing.RouteRegister) {
 		entities.Post("/", middleware.ReqSignedIn, routing.Wrap(s.createHandler))
 		entities.Get("/", middleware.ReqSignedIn, routing.Wrap(s.searchHandler))
+		entities.Get("/export", middleware.ReqSignedIn, routing.Wrap(s.exportHandler))
 		entities.Delete("/:uid", middleware.ReqSignedIn, routing.Wrap(s.deleteHandler))
 		entities.Post("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.starHandler))
 		entities.Delete("/star/:uid", middleware.ReqSignedIn, routing.Wrap(s.unstarHandler))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(s.patchCommentHandler))
+		entities.Put("/labels/:uid", middleware.ReqSignedIn, routing.Wrap(s.putLabelsHandler))
 		// Remove migrate endpoint in Grafana v10 as breaking change
 		entities.Post("/migrate", middleware.ReqSignedIn, routing.Wrap(s.migrateHandler))
 	})
@@ -63,18 +70,7 @@ func (s *QueryHistoryService) createHandler(c *contextmodel.ReqContext) response
 // 401: unauthorisedError
 // 500: internalServerError
 func (s *QueryHistoryService) searchHandler(c *contextmodel.ReqContext) response.Response {
-	timeRange := legacydata.NewDataTimeRange(c.Query("from"), c.Query("to"))
-
-	query := SearchInQueryHistoryQuery{
-		DatasourceUIDs: c.QueryStrings("datasourceUid"),
-		SearchString:   c.Query("searchString"),
-		OnlyStarred:    c.QueryBoolWithDefault("onlyStarred", false),
-		Sort:           c.Query("sort"),
-		Page:           c.QueryInt("page"),
-		Limit:          c.QueryInt("limit"),
-		From:           timeRange.GetFromAsSecondsEpoch(),
-		To:             timeRange.GetToAsSecondsEpoch(),
-	}
+	query := searchQueryFromRequest(c)
 
 	result, err := s.SearchInQueryHistory(c.Req.Context(), c.SignedInUser, query)
 	if err != nil {
@@ -84,6 +80,49 @@ func (s *QueryHistoryService) searchHandler(c *contextmodel.ReqContext) response
 	return response.JSON(http.StatusOK, QueryHistorySearchResponse{Result: result})
 }
 
+// swagger:route GET /query-history/export query_history exportQueries
+//
+// Export query history.
+//
+// Returns all the queries in the query history that match the search criteria, at most 10000 of them, as a JSON or CSV file.
+// It accepts the search parameters of the query history search, except for pagination.
+//
+// Produces:
+// - application/json
+// - text/csv
+//
+// Responses:
+// 200: exportQueryHistoryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (s *QueryHistoryService) exportHandler(c *contextmodel.ReqContext) response.Response {
+	format := c.Query("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		return response.Error(http.StatusBadRequest, "Unsupported export format, use json or csv", nil)
+	}
+
+	queries, err := s.ExportQueryHistory(c.Req.Context(), c.SignedInUser, searchQueryFromRequest(c))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export query history", err)
+	}
+
+	if format == "json" {
+		return response.JSONDownload(http.StatusOK, queries, "query-history.json")
+	}
+
+	body, err := queriesToCSV(queries)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to export query history", err)
+	}
+	return response.Respond(http.StatusOK, body).
+		SetHeader("Content-Type", "text/csv; charset=utf-8").
+		SetHeader("Content-Disposition", `attachment;filename="query-history.csv"`)
+}
+
 // swagger:route DELETE /query-history/{query_history_uid} query_history deleteQuery
 //
 // Delete query in query history.
@@ -141,6 +180,43 @@ func (s *QueryHistoryService) patchCommentHandler(c *contextmodel.ReqContext) re
 	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
 }
 
+// swagger:route PUT /query-history/labels/{query_history_uid} query_history putQueryLabels
+//
+// Set labels of query in query history.
+//
+// Replaces the labels of query in query history as specified by the UID. A query has at most 20 labels.
+//
+// Responses:
+// 200: getQueryHistoryResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (s *QueryHistoryService) putLabelsHandler(c *contextmodel.ReqContext) response.Response {
+	queryUID := web.Params(c.Req)[":uid"]
+	if len(queryUID) > 0 && !util.IsValidShortUID(queryUID) {
+		return response.Error(http.StatusNotFound, "Query in query history not found", nil)
+	}
+
+	cmd := PutQueryLabelsInQueryHistoryCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	query, err := s.PutQueryLabelsInQueryHistory(c.Req.Context(), c.SignedInUser, queryUID, cmd)
+	if err != nil {
+		if errors.Is(err, ErrInvalidLabel) || errors.Is(err, ErrTooManyLabels) {
+			return response.Error(http.StatusBadRequest, err.Error(), err)
+		}
+		if errors.Is(err, ErrQueryNotFound) {
+			return response.Error(http.StatusNotFound, "Query in query history not found", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to update labels of query in query history", err)
+	}
+
+	return response.JSON(http.StatusOK, QueryHistoryResponse{Result: query})
+}
+
 // swagger:route POST /query-history/star/{query_history_uid} query_history starQuery
 //
 // Add star to query in query history.
@@ -214,14 +290,62 @@ func (s *QueryHistoryService) migrateHandler(c *contextmodel.ReqContext) respons
 	return response.JSON(http.StatusOK, QueryHistoryMigrationResponse{Message: "Query history successfully migrated", TotalCount: totalCount, StarredCount: starredCount})
 }
 
-// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery
+func searchQueryFromRequest(c *contextmodel.ReqContext) SearchInQueryHistoryQuery {
+	timeRange := legacydata.NewDataTimeRange(c.Query("from"), c.Query("to"))
+
+	return SearchInQueryHistoryQuery{
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		SearchString:   c.Query("searchString"),
+		OnlyStarred:    c.QueryBoolWithDefault("onlyStarred", false),
+		Labels:         c.QueryStrings("label"),
+		Sort:           c.Query("sort"),
+		Page:           c.QueryInt("page"),
+		Limit:          c.QueryInt("limit"),
+		From:           timeRange.GetFromAsSecondsEpoch(),
+		To:             timeRange.GetToAsSecondsEpoch(),
+	}
+}
+
+// queriesToCSV writes the queries as CSV, one row per query with its queries as JSON
+func queriesToCSV(queries []QueryHistoryDTO) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"uid", "datasourceUid", "createdBy", "createdAt", "comment", "starred", "labels", "queries"}); err != nil {
+		return nil, err
+	}
+	for _, q := range queries {
+		var queriesJSON []byte
+		if q.Queries != nil {
+			var err error
+			if queriesJSON, err = q.Queries.MarshalJSON(); err != nil {
+				return nil, err
+			}
+		}
+		if err := w.Write([]string{
+			q.UID,
+			q.DatasourceUID,
+			strconv.FormatInt(q.CreatedBy, 10),
+			strconv.FormatInt(q.CreatedAt, 10),
+			q.Comment,
+			strconv.FormatBool(q.Starred),
+			strings.Join(q.Labels, ","),
+			string(queriesJSON),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// swagger:parameters starQuery patchQueryComment deleteQuery unstarQuery putQueryLabels
 type QueryHistoryByUID struct {
 	// in:path
 	// required:true
 	UID string `json:"query_history_uid"`
 }
 
-// swagger:parameters searchQueries
+// swagger:parameters searchQueries exportQueries
 type SearchQueriesParams struct {
 	// List of data source UIDs to search for
 	// in:query
@@ -237,6 +361,12 @@ type SearchQueriesParams struct {
 	// in:query
 	// required: false
 	OnlyStarred bool `json:"onlyStarred"`
+	// List of labels the returned queries have
+	// in:query
+	// required: false
+	// type: array
+	// collectionFormat: multi
+	Label []string `json:"label"`
 	// Sort method
 	// in:query
 	// required: false
@@ -275,6 +405,23 @@ type PatchQueryCommentParams struct {
 	Body PatchQueryCommentInQueryHistoryCommand `json:"body"`
 }
 
+// swagger:parameters exportQueries
+type ExportQueriesParams struct {
+	// Format of the exported file
+	// in:query
+	// required: false
+	// default: json
+	// Enum: json,csv
+	Format string `json:"format"`
+}
+
+// swagger:parameters putQueryLabels
+type PutQueryLabelsParams struct {
+	// in:body
+	// required:true
+	Body PutQueryLabelsInQueryHistoryCommand `json:"body"`
+}
+
 // swagger:parameters migrateQueries
 type MigrateQueriesParams struct {
 	// in:body
@@ -294,6 +441,12 @@ type GetQueryHistoryResponse struct {
 	Body QueryHistoryResponse `json:"body"`
 }
 
+// swagger:response exportQueryHistoryResponse
+type ExportQueryHistoryResponse struct {
+	// in: body
+	Body []QueryHistoryDTO `json:"body"`
+}
+
 // swagger:response getQueryHistoryDeleteQueryResponse
 type GetQueryHistoryDeleteQueryResponse struct {
 	// in: body
@@ -3,14 +3,25 @@ package queryhistory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
 
+const (
+	// maxQueryLabels is the maximum number of labels of a query
+	maxQueryLabels = 20
+	// exportLimit is the maximum number of queries exported at once
+	exportLimit = 10000
+)
+
 // createQuery adds a query into query history
 func (s QueryHistoryService) createQuery(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
 	queryHistory := QueryHistory{
@@ -39,6 +50,7 @@ func (s QueryHistoryService) createQuery(ctx context.Context, user *user.SignedI
 		Comment:       queryHistory.Comment,
 		Queries:       queryHistory.Queries,
 		Starred:       false,
+		Labels:        []string{},
 	}
 
 	return dto, nil
@@ -86,6 +98,10 @@ func (s QueryHistoryService) searchQueries(ctx context.Context, user *user.Signe
 			return err
 		}
 
+		if err := fillLabels(session, dtos); err != nil {
+			return err
+		}
+
 		countBuilder := db.SQLBuilder{}
 		countBuilder.Write(`SELECT
 		`)
@@ -127,6 +143,10 @@ func (s QueryHistoryService) deleteQuery(ctx context.Context, user *user.SignedI
 			return ErrQueryNotFound
 		}
 
+		if _, err := session.Where("query_uid = ?", UID).Delete(&QueryHistoryLabel{}); err != nil {
+			return err
+		}
+
 		queryID = id
 		return nil
 	})
@@ -138,6 +158,7 @@ func (s QueryHistoryService) deleteQuery(ctx context.Context, user *user.SignedI
 func (s QueryHistoryService) patchQueryComment(ctx context.Context, user *user.SignedInUser, UID string, cmd PatchQueryCommentInQueryHistoryCommand) (QueryHistoryDTO, error) {
 	var queryHistory QueryHistory
 	var isStarred bool
+	var labels []string
 
 	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
 		exists, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Get(&queryHistory)
@@ -158,6 +179,10 @@ func (s QueryHistoryService) patchQueryComment(ctx context.Context, user *user.S
 		if err != nil {
 			return err
 		}
+		labels, err = queryLabels(session, UID)
+		if err != nil {
+			return err
+		}
 		isStarred = starred
 		return nil
 	})
@@ -174,6 +199,7 @@ func (s QueryHistoryService) patchQueryComment(ctx context.Context, user *user.S
 		Comment:       queryHistory.Comment,
 		Queries:       queryHistory.Queries,
 		Starred:       isStarred,
+		Labels:        labels,
 	}
 
 	return dto, nil
@@ -183,6 +209,7 @@ func (s QueryHistoryService) patchQueryComment(ctx context.Context, user *user.S
 func (s QueryHistoryService) starQuery(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error) {
 	var queryHistory QueryHistory
 	var isStarred bool
+	var labels []string
 
 	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
 		// Check if query exists as we want to star only existing queries
@@ -208,6 +235,10 @@ func (s QueryHistoryService) starQuery(ctx context.Context, user *user.SignedInU
 			return err
 		}
 
+		labels, err = queryLabels(session, UID)
+		if err != nil {
+			return err
+		}
 		isStarred = true
 		return nil
 	})
@@ -224,6 +255,7 @@ func (s QueryHistoryService) starQuery(ctx context.Context, user *user.SignedInU
 		Comment:       queryHistory.Comment,
 		Queries:       queryHistory.Queries,
 		Starred:       isStarred,
+		Labels:        labels,
 	}
 
 	return dto, nil
@@ -233,6 +265,7 @@ func (s QueryHistoryService) starQuery(ctx context.Context, user *user.SignedInU
 func (s QueryHistoryService) unstarQuery(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error) {
 	var queryHistory QueryHistory
 	var isStarred bool
+	var labels []string
 
 	err := s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
 		exists, err := session.Table("query_history").Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Get(&queryHistory)
@@ -251,6 +284,10 @@ func (s QueryHistoryService) unstarQuery(ctx context.Context, user *user.SignedI
 			return err
 		}
 
+		labels, err = queryLabels(session, UID)
+		if err != nil {
+			return err
+		}
 		isStarred = false
 		return nil
 	})
@@ -267,6 +304,7 @@ func (s QueryHistoryService) unstarQuery(ctx context.Context, user *user.SignedI
 		Comment:       queryHistory.Comment,
 		Queries:       queryHistory.Queries,
 		Starred:       isStarred,
+		Labels:        labels,
 	}
 
 	return dto, nil
@@ -331,10 +369,16 @@ func (s QueryHistoryService) migrateQueries(ctx context.Context, usr *user.Signe
 	return len(queryHistories), len(starredQueries), nil
 }
 
-func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan int64) (int, error) {
+// deleteStaleQueries deletes the queries older than olderThan that are not starred, of an organization or of all
+// the organizations when orgID is 0
+func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, orgID int64, olderThan int64) (int, error) {
 	var rowsCount int64
 
 	err := s.store.WithDbSession(ctx, func(session *db.Session) error {
+		orgFilter := ""
+		if orgID != 0 {
+			orgFilter = "AND query_history.org_id = ?"
+		}
 		sql := `DELETE 
 			FROM query_history 
 			WHERE uid IN (
@@ -344,12 +388,17 @@ func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan i
 					ON query_history_star.query_uid = query_history.uid
 					WHERE query_history_star.query_uid IS NULL
 					AND query_history.created_at <= ?
+					` + orgFilter + `
 					ORDER BY query_history.id ASC
 					LIMIT 10000
 				) AS q
 			)`
+		args := []interface{}{sql, strconv.FormatInt(olderThan, 10)}
+		if orgID != 0 {
+			args = append(args, orgID)
+		}
 
-		res, err := session.Exec(sql, strconv.FormatInt(olderThan, 10))
+		res, err := session.Exec(args...)
 		if err != nil {
 			return err
 		}
@@ -359,7 +408,7 @@ func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan i
 			return err
 		}
 
-		return nil
+		return deleteOrphanLabels(session)
 	})
 
 	if err != nil {
@@ -369,6 +418,29 @@ func (s QueryHistoryService) deleteStaleQueries(ctx context.Context, olderThan i
 	return int(rowsCount), nil
 }
 
+// deleteExpiredQueries deletes the queries older than the retention of their organization
+func (s QueryHistoryService) deleteExpiredQueries(ctx context.Context) (int, error) {
+	orgs, err := s.orgService.Search(ctx, &org.SearchOrgsQuery{})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var deleted int
+	for _, o := range orgs {
+		days := s.orgSettings.GetInt64(ctx, o.ID, orgsettings.QueryHistoryRetentionDays)
+		if days <= 0 {
+			continue
+		}
+		rowsCount, err := s.deleteStaleQueries(ctx, o.ID, now.AddDate(0, 0, -int(days)).Unix())
+		if err != nil {
+			return deleted, err
+		}
+		deleted += rowsCount
+	}
+	return deleted, nil
+}
+
 // enforceQueryHistoryRowLimit is run in scheduled cleanup and it removes queries and stars that exceeded limit
 func (s QueryHistoryService) enforceQueryHistoryRowLimit(ctx context.Context, limit int, starredQueries bool) (int, error) {
 	var deletedRowsCount int64
@@ -427,6 +499,9 @@ func (s QueryHistoryService) enforceQueryHistoryRowLimit(ctx context.Context, li
 			if err != nil {
 				return err
 			}
+			if !starredQueries {
+				return deleteOrphanLabels(session)
+			}
 		}
 		return nil
 	})
@@ -436,3 +511,133 @@ func (s QueryHistoryService) enforceQueryHistoryRowLimit(ctx context.Context, li
 
 	return int(deletedRowsCount), nil
 }
+
+// putQueryLabels replaces the labels of a query in query history
+func (s QueryHistoryService) putQueryLabels(ctx context.Context, user *user.SignedInUser, UID string, cmd PutQueryLabelsInQueryHistoryCommand) (QueryHistoryDTO, error) {
+	labels, err := normalizeLabels(cmd.Labels)
+	if err != nil {
+		return QueryHistoryDTO{}, err
+	}
+
+	var queryHistory QueryHistory
+	var isStarred bool
+
+	err = s.store.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		exists, err := session.Where("org_id = ? AND created_by = ? AND uid = ?", user.OrgID, user.UserID, UID).Get(&queryHistory)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrQueryNotFound
+		}
+
+		if _, err := session.Where("query_uid = ?", UID).Delete(&QueryHistoryLabel{}); err != nil {
+			return err
+		}
+		for _, label := range labels {
+			if _, err := session.Insert(&QueryHistoryLabel{QueryUID: UID, Label: label}); err != nil {
+				return err
+			}
+		}
+
+		starred, err := session.Table("query_history_star").Where("user_id = ? AND query_uid = ?", user.UserID, UID).Exist()
+		if err != nil {
+			return err
+		}
+		isStarred = starred
+		return nil
+	})
+
+	if err != nil {
+		return QueryHistoryDTO{}, err
+	}
+
+	dto := QueryHistoryDTO{
+		UID:           queryHistory.UID,
+		DatasourceUID: queryHistory.DatasourceUID,
+		CreatedBy:     queryHistory.CreatedBy,
+		CreatedAt:     queryHistory.CreatedAt,
+		Comment:       queryHistory.Comment,
+		Queries:       queryHistory.Queries,
+		Starred:       isStarred,
+		Labels:        labels,
+	}
+
+	return dto, nil
+}
+
+// exportQueries returns the queries in query history that match the search, at most exportLimit of them
+func (s QueryHistoryService) exportQueries(ctx context.Context, user *user.SignedInUser, query SearchInQueryHistoryQuery) ([]QueryHistoryDTO, error) {
+	query.Page = 1
+	query.Limit = exportLimit
+
+	result, err := s.searchQueries(ctx, user, query)
+	if err != nil {
+		return nil, err
+	}
+	return result.QueryHistory, nil
+}
+
+// normalizeLabels trims, deduplicates and sorts the labels of a query
+func normalizeLabels(labels []string) ([]string, error) {
+	if len(labels) > maxQueryLabels {
+		return nil, ErrTooManyLabels
+	}
+
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || len(label) > 100 {
+			return nil, ErrInvalidLabel
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// queryLabels returns the labels of a query
+func queryLabels(session *db.Session, UID string) ([]string, error) {
+	labels := make([]string, 0)
+	err := session.Table("query_history_label").Where("query_uid = ?", UID).OrderBy("label").Cols("label").Find(&labels)
+	return labels, err
+}
+
+// fillLabels sets the labels of the queries
+func fillLabels(session *db.Session, dtos []QueryHistoryDTO) error {
+	if len(dtos) == 0 {
+		return nil
+	}
+
+	uids := make([]string, 0, len(dtos))
+	for _, dto := range dtos {
+		uids = append(uids, dto.UID)
+	}
+	var rows []QueryHistoryLabel
+	if err := session.In("query_uid", uids).OrderBy("label").Find(&rows); err != nil {
+		return err
+	}
+
+	labels := make(map[string][]string, len(dtos))
+	for _, row := range rows {
+		labels[row.QueryUID] = append(labels[row.QueryUID], row.Label)
+	}
+	for i := range dtos {
+		dtos[i].Labels = labels[dtos[i].UID]
+		if dtos[i].Labels == nil {
+			dtos[i].Labels = []string{}
+		}
+	}
+	return nil
+}
+
+// deleteOrphanLabels deletes the labels of the deleted queries
+func deleteOrphanLabels(session *db.Session) error {
+	_, err := session.Exec("DELETE FROM query_history_label WHERE query_uid NOT IN (SELECT uid FROM query_history)")
+	return err
+}
@@ -10,6 +10,8 @@ var (
 	ErrQueryNotFound        = errors.New("query in query history not found")
 	ErrStarredQueryNotFound = errors.New("starred query not found")
 	ErrQueryAlreadyStarred  = errors.New("query was already starred")
+	ErrInvalidLabel         = errors.New("query history labels must have between 1 and 100 characters")
+	ErrTooManyLabels        = errors.New("too many labels for query in query history")
 )
 
 // QueryHistory is the model for query history definitions
@@ -31,10 +33,18 @@ type QueryHistoryStar struct {
 	UserID   int64  `xorm:"user_id"`
 }
 
+// QueryHistoryLabel is the model for query history label definitions
+type QueryHistoryLabel struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	QueryUID string `xorm:"query_uid"`
+	Label    string `xorm:"label"`
+}
+
 type SearchInQueryHistoryQuery struct {
 	DatasourceUIDs []string `json:"datasourceUids"`
 	SearchString   string   `json:"searchString"`
 	OnlyStarred    bool     `json:"onlyStarred"`
+	Labels         []string `json:"labels"`
 	Sort           string   `json:"sort"`
 	Page           int      `json:"page"`
 	Limit          int      `json:"limit"`
@@ -50,6 +60,7 @@ type QueryHistoryDTO struct {
 	Comment       string           `json:"comment"`
 	Queries       *simplejson.Json `json:"queries"`
 	Starred       bool             `json:"starred"`
+	Labels        []string         `json:"labels" xorm:"-"`
 }
 
 // QueryHistoryResponse is a response struct for QueryHistoryDTO
@@ -106,6 +117,14 @@ type PatchQueryCommentInQueryHistoryCommand struct {
 	Comment string `json:"comment"`
 }
 
+// PutQueryLabelsInQueryHistoryCommand is the command for replacing the labels of a query in query history
+// swagger:model
+type PutQueryLabelsInQueryHistoryCommand struct {
+	// Labels of the query
+	// example: ["slow-queries","team-a"]
+	Labels []string `json:"labels"`
+}
+
 // MigrateQueriesToQueryHistoryCommand is the command used for migration of old queries into query history
 // swagger:model
 type MigrateQueriesToQueryHistoryCommand struct {
@@ -6,16 +6,20 @@ import (
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
-func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister) *QueryHistoryService {
+func ProvideService(cfg *setting.Cfg, sqlStore db.DB, routeRegister routing.RouteRegister, orgService org.Service, orgSettings orgsettings.Service) *QueryHistoryService {
 	s := &QueryHistoryService{
 		store:         sqlStore,
 		Cfg:           cfg,
 		RouteRegister: routeRegister,
 		log:           log.New("query-history"),
+		orgService:    orgService,
+		orgSettings:   orgSettings,
 	}
 
 	// Register routes only when query history is enabled
@@ -33,8 +37,11 @@ type Service interface {
 	PatchQueryCommentInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd PatchQueryCommentInQueryHistoryCommand) (QueryHistoryDTO, error)
 	StarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
 	UnstarQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string) (QueryHistoryDTO, error)
+	PutQueryLabelsInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd PutQueryLabelsInQueryHistoryCommand) (QueryHistoryDTO, error)
+	ExportQueryHistory(ctx context.Context, user *user.SignedInUser, query SearchInQueryHistoryQuery) ([]QueryHistoryDTO, error)
 	MigrateQueriesToQueryHistory(ctx context.Context, user *user.SignedInUser, cmd MigrateQueriesToQueryHistoryCommand) (int, int, error)
 	DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error)
+	DeleteExpiredQueriesInQueryHistory(ctx context.Context) (int, error)
 	EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error)
 }
 
@@ -43,6 +50,8 @@ type QueryHistoryService struct {
 	Cfg           *setting.Cfg
 	RouteRegister routing.RouteRegister
 	log           log.Logger
+	orgService    org.Service
+	orgSettings   orgsettings.Service
 }
 
 func (s QueryHistoryService) CreateQueryInQueryHistory(ctx context.Context, user *user.SignedInUser, cmd CreateQueryInQueryHistoryCommand) (QueryHistoryDTO, error) {
@@ -69,12 +78,25 @@ func (s QueryHistoryService) UnstarQueryInQueryHistory(ctx context.Context, user
 	return s.unstarQuery(ctx, user, UID)
 }
 
+func (s QueryHistoryService) PutQueryLabelsInQueryHistory(ctx context.Context, user *user.SignedInUser, UID string, cmd PutQueryLabelsInQueryHistoryCommand) (QueryHistoryDTO, error) {
+	return s.putQueryLabels(ctx, user, UID, cmd)
+}
+
+func (s QueryHistoryService) ExportQueryHistory(ctx context.Context, user *user.SignedInUser, query SearchInQueryHistoryQuery) ([]QueryHistoryDTO, error) {
+	return s.exportQueries(ctx, user, query)
+}
+
 func (s QueryHistoryService) MigrateQueriesToQueryHistory(ctx context.Context, user *user.SignedInUser, cmd MigrateQueriesToQueryHistoryCommand) (int, int, error) {
 	return s.migrateQueries(ctx, user, cmd)
 }
 
 func (s QueryHistoryService) DeleteStaleQueriesInQueryHistory(ctx context.Context, olderThan int64) (int, error) {
-	return s.deleteStaleQueries(ctx, olderThan)
+	return s.deleteStaleQueries(ctx, 0, olderThan)
+}
+
+// DeleteExpiredQueriesInQueryHistory deletes the queries older than the retention of their organization
+func (s QueryHistoryService) DeleteExpiredQueriesInQueryHistory(ctx context.Context) (int, error) {
+	return s.deleteExpiredQueries(ctx)
 }
 
 func (s QueryHistoryService) EnforceRowLimitInQueryHistory(ctx context.Context, limit int, starredQueries bool) (int, error) {
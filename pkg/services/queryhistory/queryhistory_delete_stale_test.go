@@ -7,6 +7,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
+	"github.com/grafana/grafana/pkg/services/orgsettings/orgsettingstest"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -51,3 +54,48 @@ func TestIntegrationDeleteStaleQueryFromQueryHistory(t *testing.T) {
 			require.Equal(t, 1, rowsDeleted)
 		})
 }
+
+func TestIntegrationDeleteExpiredQueryFromQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithQueryInQueryHistory(t, "Query history older than the retention of the organization is deleted",
+		func(t *testing.T, sc scenarioContext) {
+			setQueryCreatedAt(t, sc, time.Now().AddDate(0, 0, -3))
+			sc.service.orgSettings.(*orgsettingstest.FakeService).Overrides[orgsettings.QueryHistoryRetentionDays] = "2"
+
+			rowsDeleted, err := sc.service.DeleteExpiredQueriesInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "Query history within the retention of the organization is not deleted",
+		func(t *testing.T, sc scenarioContext) {
+			setQueryCreatedAt(t, sc, time.Now().AddDate(0, 0, -20))
+			sc.service.orgSettings.(*orgsettingstest.FakeService).Overrides[orgsettings.QueryHistoryRetentionDays] = "30"
+
+			rowsDeleted, err := sc.service.DeleteExpiredQueriesInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 0, rowsDeleted)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "Query history older than the default retention is deleted",
+		func(t *testing.T, sc scenarioContext) {
+			sc.service.Cfg.QueryHistoryRetentionDays = 14
+			setQueryCreatedAt(t, sc, time.Now().AddDate(0, 0, -20))
+
+			rowsDeleted, err := sc.service.DeleteExpiredQueriesInQueryHistory(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, rowsDeleted)
+		})
+}
+
+func setQueryCreatedAt(t *testing.T, sc scenarioContext, createdAt time.Time) {
+	t.Helper()
+
+	err := sc.sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		_, err := sess.Table("query_history").Where("uid = ?", sc.initialResult.Result.UID).Update(map[string]interface{}{"created_at": createdAt.Unix()})
+		return err
+	})
+	require.NoError(t, err)
+}
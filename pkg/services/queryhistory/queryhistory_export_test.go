@@ -0,0 +1,48 @@
+package queryhistory
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationExportQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users export query history as JSON, all the matching queries are returned",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("datasourceUid", testDsUID1)
+			sc.reqContext.Req.Form.Add("limit", "1")
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var queries []QueryHistoryDTO
+			require.NoError(t, json.Unmarshal(resp.Body(), &queries))
+			require.Len(t, queries, 2)
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users export query history as CSV, one row is returned per query",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("format", "csv")
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			rows, err := csv.NewReader(bytes.NewReader(resp.Body())).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, 4)
+			require.Equal(t, []string{"uid", "datasourceUid", "createdBy", "createdAt", "comment", "starred", "labels", "queries"}, rows[0])
+			require.Equal(t, `{"expr":"test"}`, rows[3][7])
+			require.Equal(t, "test comment 2", rows[3][4])
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When users export query history in an unknown format, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.reqContext.Req.Form.Add("format", "xml")
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+}
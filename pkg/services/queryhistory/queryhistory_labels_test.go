@@ -0,0 +1,62 @@
+package queryhistory
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestIntegrationPutQueryLabelsInQueryHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testScenarioWithQueryInQueryHistory(t, "When user tries to set labels of query in query history that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "missing"})
+			sc.reqContext.Req.Body = mockRequestBody(PutQueryLabelsInQueryHistoryCommand{Labels: []string{"slow"}})
+			resp := sc.service.putLabelsHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When user sets labels of query in query history, they are replaced, deduplicated and sorted",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(PutQueryLabelsInQueryHistoryCommand{Labels: []string{"team-a", "old"}})
+			resp := sc.service.putLabelsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.Req.Body = mockRequestBody(PutQueryLabelsInQueryHistoryCommand{Labels: []string{" slow ", "team-a", "slow"}})
+			resp = sc.service.putLabelsHandler(sc.reqContext)
+			result := validateAndUnMarshalResponse(t, resp)
+			require.Equal(t, []string{"slow", "team-a"}, result.Result.Labels)
+		})
+
+	testScenarioWithQueryInQueryHistory(t, "When user sets an empty label, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(PutQueryLabelsInQueryHistoryCommand{Labels: []string{" "}})
+			resp := sc.service.putLabelsHandler(sc.reqContext)
+			require.Equal(t, 400, resp.Status())
+		})
+
+	testScenarioWithMultipleQueriesInQueryHistory(t, "When users search by labels, only the queries with all the labels are returned",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			sc.reqContext.Req.Body = mockRequestBody(PutQueryLabelsInQueryHistoryCommand{Labels: []string{"slow", "team-a"}})
+			resp := sc.service.putLabelsHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			sc.reqContext.Req.Form.Add("label", "slow")
+			sc.reqContext.Req.Form.Add("label", "team-a")
+			resp = sc.service.searchHandler(sc.reqContext)
+			var response QueryHistorySearchResponse
+			require.NoError(t, json.Unmarshal(resp.Body(), &response))
+			require.Equal(t, 200, resp.Status())
+			require.Len(t, response.Result.QueryHistory, 1)
+			require.Equal(t, sc.initialResult.Result.UID, response.Result.QueryHistory[0].UID)
+			require.Equal(t, []string{"slow", "team-a"}, response.Result.QueryHistory[0].Labels)
+		})
+}
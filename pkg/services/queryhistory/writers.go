@@ -33,6 +33,19 @@ func writeFiltersSQL(query SearchInQueryHistoryQuery, user *user.SignedInUser, s
 		}
 		sql.WriteString(" AND query_history.datasource_uid IN (? " + strings.Repeat(",?", len(query.DatasourceUIDs)-1) + ") ")
 	}
+
+	if len(query.Labels) > 0 {
+		// the queries having all the labels
+		labels := make(map[string]bool, len(query.Labels))
+		for _, label := range query.Labels {
+			if !labels[label] {
+				labels[label] = true
+				params = append(params, label)
+			}
+		}
+		params = append(params, len(labels))
+		sql.WriteString(" AND query_history.uid IN (SELECT query_uid FROM query_history_label WHERE label IN (? " + strings.Repeat(",?", len(labels)-1) + ") GROUP BY query_uid HAVING COUNT(*) = ?) ")
+	}
 	builder.Write(sql.String(), params...)
 }
 
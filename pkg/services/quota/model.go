@@ -17,6 +17,7 @@ var ErrUsageFoundForTarget = errutil.NewBase(errutil.StatusNotFound, "quota.miss
 var ErrTargetSrvConflict = errutil.NewBase(errutil.StatusBadRequest, "quota.target-srv-conflict")
 var ErrDisabled = errutil.NewBase(errutil.StatusForbidden, "quota.disabled", errutil.WithPublicMessage("Quotas not enabled"))
 var ErrInvalidTagFormat = errutil.NewBase(errutil.StatusInternal, "quota.invalid-invalid-tag-format")
+var ErrQuotaReached = errutil.NewBase(errutil.StatusForbidden, "quota.reached", errutil.WithPublicMessage("Quota reached"))
 
 type ScopeParameters struct {
 	OrgID  int64
@@ -204,6 +205,14 @@ func (dto QuotaDTO) Tag() (Tag, error) {
 	return NewTag(TargetSrv(dto.Service), Target(dto.Target), Scope(dto.Scope))
 }
 
+// TargetDTO describes a quota target registered by a service
+type TargetDTO struct {
+	Service      string `json:"service"`
+	Target       string `json:"target"`
+	Scope        string `json:"scope"`
+	DefaultLimit int64  `json:"default_limit"`
+}
+
 type UpdateQuotaCmd struct {
 	Target string `json:"target"`
 	Limit  int64  `json:"limit"`
@@ -211,6 +220,18 @@ type UpdateQuotaCmd struct {
 	UserID int64  `json:"-"`
 }
 
+// Scope returns the scope of the quota that is going to be updated
+func (cmd UpdateQuotaCmd) Scope() Scope {
+	switch {
+	case cmd.UserID != 0:
+		return UserScope
+	case cmd.OrgID != 0:
+		return OrgScope
+	default:
+		return GlobalScope
+	}
+}
+
 type NewUsageReporter struct {
 	TargetSrv     TargetSrv
 	DefaultLimits *Map
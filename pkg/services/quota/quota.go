@@ -11,9 +11,12 @@ type Service interface {
 	// If the scope is organization, the ID is expected to be the organisation ID.
 	// If the scope is user, the id is expected to be the user ID.
 	GetQuotasByScope(ctx context.Context, scope Scope, ID int64) ([]QuotaDTO, error)
+	// GetTargets returns the quota targets registered by all the services together with their default limits.
+	GetTargets(ctx context.Context) ([]TargetDTO, error)
 	// Update overrides the quota for a specific scope (global, organization, user).
 	// If the cmd.OrgID is set, then the organization quota are updated.
 	// If the cmd.UseID is set, then the user quota are updated.
+	// If neither is set, then the global quota are updated.
 	Update(ctx context.Context, cmd *UpdateQuotaCmd) error
 	// QuotaReached is called by the quota middleware for applying quota enforcement to API handlers
 	QuotaReached(c *contextmodel.ReqContext, targetSrv TargetSrv) (bool, error)
@@ -28,3 +31,17 @@ type Service interface {
 }
 
 type UsageReporterFunc func(ctx context.Context, scopeParams *ScopeParameters) (*Map, error)
+
+// Enforce is a helper for services that need to apply quota enforcement outside of the HTTP API,
+// for example when resources are created by provisioning or by a background job.
+// It returns ErrQuotaReached if any of the limits of the target service is reached.
+func Enforce(ctx context.Context, s Service, targetSrv TargetSrv, scopeParams *ScopeParameters) error {
+	reached, err := s.CheckQuotaReached(ctx, targetSrv, scopeParams)
+	if err != nil {
+		return err
+	}
+	if reached {
+		return ErrQuotaReached.Errorf("quota reached for target service: %s", targetSrv)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package quotaimpl
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
@@ -24,6 +25,10 @@ func (s *serviceDisabled) GetQuotasByScope(ctx context.Context, scope quota.Scop
 	return nil, quota.ErrDisabled
 }
 
+func (s *serviceDisabled) GetTargets(ctx context.Context) ([]quota.TargetDTO, error) {
+	return nil, quota.ErrDisabled
+}
+
 func (s *serviceDisabled) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd) error {
 	return quota.ErrDisabled
 }
@@ -159,22 +164,56 @@ func (s *service) GetQuotasByScope(ctx context.Context, scope quota.Scope, id in
 	return q, nil
 }
 
-func (s *service) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd) error {
-	targetFound := false
-	knownTargets, err := s.defaultLimits.Targets()
-	if err != nil {
-		return err
+func (s *service) GetTargets(ctx context.Context) ([]quota.TargetDTO, error) {
+	targets := make([]quota.TargetDTO, 0)
+	for item := range s.defaultLimits.Iter() {
+		srv, err := item.Tag.GetSrv()
+		if err != nil {
+			return nil, err
+		}
+		target, err := item.Tag.GetTarget()
+		if err != nil {
+			return nil, err
+		}
+		scope, err := item.Tag.GetScope()
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, quota.TargetDTO{
+			Service:      string(srv),
+			Target:       string(target),
+			Scope:        string(scope),
+			DefaultLimit: item.Value,
+		})
 	}
 
-	for t := range knownTargets {
-		if t == quota.Target(cmd.Target) {
-			targetFound = true
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Service != targets[j].Service {
+			return targets[i].Service < targets[j].Service
 		}
-	}
-	if !targetFound {
+		if targets[i].Target != targets[j].Target {
+			return targets[i].Target < targets[j].Target
+		}
+		return targets[i].Scope < targets[j].Scope
+	})
+
+	return targets, nil
+}
+
+func (s *service) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd) error {
+	srv, ok := s.targetToSrv.Get(quota.Target(cmd.Target))
+	if !ok {
 		return quota.ErrInvalidTarget.Errorf("unknown quota target: %s", cmd.Target)
 	}
 
+	tag, err := quota.NewTag(srv, quota.Target(cmd.Target), cmd.Scope())
+	if err != nil {
+		return err
+	}
+	if _, ok := s.defaultLimits.Get(tag); !ok {
+		return quota.ErrInvalidTarget.Errorf("quota target %s is not available for scope: %s", cmd.Target, cmd.Scope())
+	}
+
 	c, err := s.getContext(ctx)
 	if err != nil {
 		return err
@@ -249,8 +288,8 @@ func (s *service) RegisterQuotaReporter(e *quota.NewUsageReporter) error {
 		return quota.ErrTargetSrvConflict.Errorf("target service: %s already exists", e.TargetSrv)
 	}
 
-	s.reporters[e.TargetSrv] = e.Reporter
-
+	// targets are stored in the database without the service they belong to,
+	// so they have to be unique across all the registered services
 	for item := range e.DefaultLimits.Iter() {
 		target, err := item.Tag.GetTarget()
 		if err != nil {
@@ -260,7 +299,22 @@ func (s *service) RegisterQuotaReporter(e *quota.NewUsageReporter) error {
 		if err != nil {
 			return err
 		}
-		s.targetToSrv.Set(target, srv)
+		if srv != e.TargetSrv {
+			return quota.ErrTargetSrvConflict.Errorf("target %s belongs to service %s instead of %s", target, srv, e.TargetSrv)
+		}
+		if existing, ok := s.targetToSrv.Get(target); ok && existing != srv {
+			return quota.ErrTargetSrvConflict.Errorf("target %s is already registered by service: %s", target, existing)
+		}
+	}
+
+	s.reporters[e.TargetSrv] = e.Reporter
+
+	for item := range e.DefaultLimits.Iter() {
+		target, err := item.Tag.GetTarget()
+		if err != nil {
+			return err
+		}
+		s.targetToSrv.Set(target, e.TargetSrv)
 		s.defaultLimits.Set(item.Tag, item.Value)
 	}
 
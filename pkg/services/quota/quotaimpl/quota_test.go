@@ -428,6 +428,80 @@ func TestIntegrationQuotaCommandsAndQueries(t *testing.T) {
 		require.Equal(t, customUserOrgLimit, query.Limit)
 	})
 
+	t.Run("Should be able to override global quota", func(t *testing.T) {
+		err := quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{
+			Target: string(dashboards.QuotaTarget),
+			Limit:  1,
+		})
+		require.NoError(t, err)
+
+		q, err := getQuotaBySrvTargetScope(t, quotaService, dashboards.QuotaTargetSrv, dashboards.QuotaTarget, quota.GlobalScope, &quota.ScopeParameters{})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), q.Limit)
+
+		reached, err := quotaService.CheckQuotaReached(context.Background(), dashboards.QuotaTargetSrv, nil)
+		require.NoError(t, err)
+		require.False(t, reached)
+	})
+
+	t.Run("Should not be able to update quota for unknown target or scope", func(t *testing.T) {
+		err := quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{
+			OrgID:  o.ID,
+			Target: "unknown",
+			Limit:  1,
+		})
+		require.ErrorIs(t, err, quota.ErrInvalidTarget)
+
+		// the org target is only available globally
+		err = quotaService.Update(context.Background(), &quota.UpdateQuotaCmd{
+			OrgID:  o.ID,
+			Target: org.OrgQuotaTarget,
+			Limit:  1,
+		})
+		require.ErrorIs(t, err, quota.ErrInvalidTarget)
+	})
+
+	t.Run("Should list registered targets", func(t *testing.T) {
+		targets, err := quotaService.GetTargets(context.Background())
+		require.NoError(t, err)
+		require.Contains(t, targets, quota.TargetDTO{
+			Service:      string(dashboards.QuotaTargetSrv),
+			Target:       string(dashboards.QuotaTarget),
+			Scope:        string(quota.OrgScope),
+			DefaultLimit: sqlStore.Cfg.Quota.Org.Dashboard,
+		})
+	})
+
+	t.Run("Should register custom targets and reject conflicting ones", func(t *testing.T) {
+		limits := &quota.Map{}
+		tag, err := quota.NewTag("custom", "custom_target", quota.OrgScope)
+		require.NoError(t, err)
+		limits.Set(tag, 1)
+		err = quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+			TargetSrv:     "custom",
+			DefaultLimits: limits,
+			Reporter: func(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+				usage := &quota.Map{}
+				usage.Set(tag, 1)
+				return usage, nil
+			},
+		})
+		require.NoError(t, err)
+
+		err = quota.Enforce(context.Background(), quotaService, "custom", &quota.ScopeParameters{OrgID: o.ID})
+		require.ErrorIs(t, err, quota.ErrQuotaReached)
+
+		conflicting := &quota.Map{}
+		tag, err = quota.NewTag("other", "custom_target", quota.GlobalScope)
+		require.NoError(t, err)
+		conflicting.Set(tag, 1)
+		err = quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+			TargetSrv:     "other",
+			DefaultLimits: conflicting,
+		})
+		require.ErrorIs(t, err, quota.ErrTargetSrvConflict)
+	})
+
 	// TODO data_source, file
 }
 
@@ -479,7 +553,7 @@ func setupEnv(t *testing.T, sqlStore *sqlstore.SQLStore, b bus.Bus, quotaService
 	m := metrics.NewNGAlert(prometheus.NewRegistry())
 	_, err = ngalert.ProvideService(
 		sqlStore.Cfg, featuremgmt.WithFeatures(), nil, nil, routing.NewRouteRegister(), sqlStore, nil, nil, nil, quotaService,
-		secretsService, nil, m, &foldertest.FakeService{}, &acmock.Mock{}, &dashboards.FakeDashboardService{}, nil, b, &acmock.Mock{}, annotationstest.NewFakeAnnotationsRepo(), &plugins.FakePluginStore{}, tracer,
+		secretsService, nil, m, &foldertest.FakeService{}, &acmock.Mock{}, &dashboards.FakeDashboardService{}, nil, b, &acmock.Mock{}, annotationstest.NewFakeAnnotationsRepo(), &plugins.FakePluginStore{}, tracer, nil, nil,
 	)
 	require.NoError(t, err)
 	_, err = storesrv.ProvideService(sqlStore, featuremgmt.WithFeatures(), sqlStore.Cfg, quotaService, storesrv.ProvideSystemUsersService())
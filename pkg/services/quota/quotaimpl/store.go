@@ -30,6 +30,13 @@ func (ss *sqlStore) DeleteByUser(ctx quota.Context, userID int64) error {
 
 func (ss *sqlStore) Get(ctx quota.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
 	limits := quota.Map{}
+
+	globalLimits, err := ss.getGlobalScopeQuota(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits.Merge(globalLimits)
+
 	if scopeParams == nil {
 		return &limits, nil
 	}
@@ -61,7 +68,8 @@ func (ss *sqlStore) Update(ctx quota.Context, cmd *quota.UpdateQuotaCmd) error {
 			UserId: cmd.UserID,
 			OrgId:  cmd.OrgID,
 		}
-		has, err := sess.Get(&quota)
+		// zero values are ignored by the session when used as conditions, so they have to be explicit for global quota
+		has, err := sess.Where("org_id=? AND user_id=? AND target=?", cmd.OrgID, cmd.UserID, cmd.Target).Get(&quota)
 		if err != nil {
 			return err
 		}
@@ -85,6 +93,30 @@ func (ss *sqlStore) Update(ctx quota.Context, cmd *quota.UpdateQuotaCmd) error {
 	})
 }
 
+func (ss *sqlStore) getGlobalScopeQuota(ctx quota.Context) (*quota.Map, error) {
+	r := quota.Map{}
+	err := ss.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		quotas := make([]*quota.Quota, 0)
+		if err := sess.Table("quota").Where("user_id=0 AND org_id=0").Find(&quotas); err != nil {
+			return err
+		}
+
+		for _, q := range quotas {
+			srv, ok := ctx.TargetToSrv.Get(quota.Target(q.Target))
+			if !ok {
+				ss.logger.Info("failed to get service for target", "target", q.Target)
+			}
+			tag, err := quota.NewTag(srv, quota.Target(q.Target), quota.GlobalScope)
+			if err != nil {
+				return err
+			}
+			r.Set(tag, q.Limit)
+		}
+		return nil
+	})
+	return &r, err
+}
+
 func (ss *sqlStore) getUserScopeQuota(ctx quota.Context, userID int64) (*quota.Map, error) {
 	r := quota.Map{}
 	err := ss.db.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
@@ -20,6 +20,10 @@ func (f *FakeQuotaService) GetQuotasByScope(ctx context.Context, scope quota.Sco
 	return []quota.QuotaDTO{}, nil
 }
 
+func (f *FakeQuotaService) GetTargets(ctx context.Context) ([]quota.TargetDTO, error) {
+	return []quota.TargetDTO{}, f.err
+}
+
 func (f *FakeQuotaService) Update(ctx context.Context, cmd *quota.UpdateQuotaCmd) error {
 	return nil
 }
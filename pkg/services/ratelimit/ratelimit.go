@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Groups of endpoints sharing a rate limit
+const (
+	GroupSearch = "search"
+	GroupQuery  = "query"
+	GroupRender = "render"
+)
+
+// Service limits the rate of the requests to groups of endpoints with token buckets, one per group and key.
+type Service interface {
+	// Allow takes a token from the bucket of the key in the group. ok is false when the group is not rate limited.
+	Allow(ctx context.Context, group, key string) (result Result, ok bool, err error)
+}
+
+// Result is the state of a bucket after a token was requested from it.
+type Result struct {
+	Allowed bool
+	// Limit is the number of tokens of a full bucket
+	Limit int
+	// Remaining is the number of tokens left in the bucket
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until a token is available, when the request was not allowed
+	RetryAfter time.Duration
+}
@@ -0,0 +1,57 @@
+package ratelimitimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// sweepInterval is how often the full buckets are removed from memory
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full is when the bucket is full again, and can be forgotten
+	full time.Time
+}
+
+// memoryLimiter keeps the token buckets in the memory of the instance, the limits apply per instance.
+type memoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newMemoryLimiter() *memoryLimiter {
+	return &memoryLimiter{buckets: map[string]*bucket{}}
+}
+
+func (l *memoryLimiter) take(_ context.Context, key string, limit setting.RateLimit, now time.Time) (ratelimit.Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for k, b := range l.buckets {
+			if !now.Before(b.full) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	tokens, res := takeToken(refill(b.tokens, now.Sub(b.updated), limit), limit)
+	b.tokens = tokens
+	b.updated = now
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
@@ -0,0 +1,66 @@
+package ratelimitimpl
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// takeTokenScript refills the bucket and takes a token from it atomically, it returns whether the token
+// was taken and the tokens left. The bucket expires once it is full again.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - updated) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// redisLimiter keeps the token buckets in Redis, the limits apply to all the instances sharing it.
+type redisLimiter struct {
+	client *redis.Client
+}
+
+func newRedisLimiter(client *redis.Client) *redisLimiter {
+	return &redisLimiter{client: client}
+}
+
+func (l *redisLimiter) take(ctx context.Context, key string, limit setting.RateLimit, now time.Time) (ratelimit.Result, error) {
+	values, err := takeTokenScript.Run(ctx, l.client, []string{redisKeyPrefix + key}, limit.Rate, limit.Burst, now.UnixMilli()).Slice()
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	if len(values) != 2 {
+		return ratelimit.Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	rawTokens, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(rawTokens, 64)
+	if err != nil {
+		return ratelimit.Result{}, fmt.Errorf("invalid tokens in rate limit script result: %w", err)
+	}
+	return newResult(tokens, allowed == 1, limit), nil
+}
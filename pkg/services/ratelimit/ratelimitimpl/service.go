@@ -0,0 +1,119 @@
+package ratelimitimpl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// limiter stores the token buckets
+type limiter interface {
+	// take refills the bucket of the key up to now and takes a token from it
+	take(ctx context.Context, key string, limit setting.RateLimit, now time.Time) (ratelimit.Result, error)
+}
+
+var _ ratelimit.Service = (*Service)(nil)
+
+type Service struct {
+	cfg      *setting.Cfg
+	limiter  limiter
+	requests *prometheus.CounterVec
+	log      log.Logger
+	now      func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, registerer prometheus.Registerer) (*Service, error) {
+	s := &Service{
+		cfg: cfg,
+		requests: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "grafana",
+			Subsystem: "rate_limiting",
+			Name:      "requests_total",
+			Help:      "Number of requests checked against a rate limit, by group of endpoints and result.",
+		}, []string{"group", "result"}),
+		log: log.New("ratelimit"),
+		now: time.Now,
+	}
+
+	switch cfg.RateLimiting.Backend {
+	case "", "memory":
+		s.limiter = newMemoryLimiter()
+	case "redis":
+		s.limiter = newRedisLimiter(redis.NewClient(&redis.Options{
+			Addr:     cfg.RateLimiting.RedisAddr,
+			Password: cfg.RateLimiting.RedisPassword,
+			DB:       cfg.RateLimiting.RedisDB,
+		}))
+	default:
+		return nil, fmt.Errorf("unknown rate limiting backend %q", cfg.RateLimiting.Backend)
+	}
+	return s, nil
+}
+
+func (s *Service) Allow(ctx context.Context, group, key string) (ratelimit.Result, bool, error) {
+	// the limits are read on every request, so that the reloaded limits apply at once
+	settings := s.cfg.CurrentRateLimiting()
+	if !settings.Enabled {
+		return ratelimit.Result{}, false, nil
+	}
+	limit, ok := settings.Limits[group]
+	if !ok || limit.Rate <= 0 {
+		return ratelimit.Result{}, false, nil
+	}
+
+	res, err := s.limiter.take(ctx, group+":"+key, limit, s.now())
+	if err != nil {
+		s.requests.WithLabelValues(group, "error").Inc()
+		return ratelimit.Result{}, true, err
+	}
+
+	if res.Allowed {
+		s.requests.WithLabelValues(group, "allowed").Inc()
+	} else {
+		s.requests.WithLabelValues(group, "limited").Inc()
+	}
+	return res, true, nil
+}
+
+// refill adds the tokens earned by the bucket during elapsed
+func refill(tokens float64, elapsed time.Duration, limit setting.RateLimit) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
+
+// takeToken takes a token from the bucket if it has one, and returns the tokens left
+func takeToken(tokens float64, limit setting.RateLimit) (float64, ratelimit.Result) {
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return tokens, newResult(tokens, allowed, limit)
+}
+
+func newResult(tokens float64, allowed bool, limit setting.RateLimit) ratelimit.Result {
+	res := ratelimit.Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
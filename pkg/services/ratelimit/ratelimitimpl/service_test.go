@@ -0,0 +1,87 @@
+package ratelimitimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/services/ratelimit"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Allow(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.RateLimiting = setting.RateLimitingSettings{
+		Enabled: true,
+		Limits: map[string]setting.RateLimit{
+			ratelimit.GroupSearch: {Rate: 1, Burst: 2},
+			ratelimit.GroupRender: {Rate: 0, Burst: 1},
+		},
+	}
+	s, err := ProvideService(cfg, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	t.Run("Requests are limited once the bucket is empty", func(t *testing.T) {
+		res, ok, err := s.Allow(ctx, ratelimit.GroupSearch, "user-1")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, res)
+
+		res, _, _ = s.Allow(ctx, ratelimit.GroupSearch, "user-1")
+		require.True(t, res.Allowed)
+		require.Equal(t, 0, res.Remaining)
+
+		res, _, _ = s.Allow(ctx, ratelimit.GroupSearch, "user-1")
+		require.False(t, res.Allowed)
+		require.Equal(t, time.Second, res.RetryAfter)
+		require.Equal(t, 2*time.Second, res.Reset)
+	})
+
+	t.Run("Keys have their own bucket", func(t *testing.T) {
+		res, _, _ := s.Allow(ctx, ratelimit.GroupSearch, "user-2")
+		require.True(t, res.Allowed)
+	})
+
+	t.Run("Buckets are refilled over time", func(t *testing.T) {
+		now = now.Add(1500 * time.Millisecond)
+		res, _, _ := s.Allow(ctx, ratelimit.GroupSearch, "user-1")
+		require.True(t, res.Allowed)
+		require.Equal(t, 0, res.Remaining)
+	})
+
+	t.Run("Groups without rate are not limited", func(t *testing.T) {
+		_, ok, err := s.Allow(ctx, ratelimit.GroupRender, "user-1")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, ok, err = s.Allow(ctx, ratelimit.GroupQuery, "user-1")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("Reloaded limits apply to the next requests", func(t *testing.T) {
+		updated, err := ini.Load([]byte("[rate_limiting]\nenabled = true\nrender_rate = 1\nrender_burst = 1\n"))
+		require.NoError(t, err)
+		require.NoError(t, cfg.ApplySection(updated, "rate_limiting", nil))
+
+		res, ok, err := s.Allow(ctx, ratelimit.GroupRender, "user-1")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, 1, res.Limit)
+	})
+
+	t.Run("Unknown backends are rejected", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.RateLimiting.Backend = "memcached"
+		_, err := ProvideService(cfg, prometheus.NewRegistry())
+		require.Error(t, err)
+	})
+}
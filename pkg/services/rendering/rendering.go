@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
@@ -44,9 +45,11 @@ type RenderingService struct {
 	Cfg                         *setting.Cfg
 	RemoteCacheService          *remotecache.RemoteCache
 	RendererPluginManager       plugins.RendererManager
+	// artifacts stores the rendered images, so that any instance serves them, nil when they're only on the local disk
+	artifacts objectstore.ObjectStore
 }
 
-func ProvideService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache, rm plugins.RendererManager) (*RenderingService, error) {
+func ProvideService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache, rm plugins.RendererManager, objectStorage *objectstore.Service) (*RenderingService, error) {
 	// ensure ImagesDir exists
 	err := os.MkdirAll(cfg.ImagesDir, 0700)
 	if err != nil {
@@ -106,6 +109,7 @@ func ProvideService(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache, rm p
 		Cfg:                   cfg,
 		RemoteCacheService:    remoteCache,
 		RendererPluginManager: rm,
+		artifacts:             objectStorage.Store(objectstore.ArtifactRendering),
 		log:                   logger,
 		domain:                domain,
 		sanitizeURL:           sanitizeURL,
@@ -286,7 +290,30 @@ func (rs *RenderingService) render(ctx context.Context, opts Opts, renderKeyProv
 	}()
 
 	metrics.MRenderingQueue.Set(float64(atomic.AddInt32(&rs.inProgressCount, 1)))
-	return rs.renderAction(ctx, renderKey, opts)
+	result, err := rs.renderAction(ctx, renderKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	rs.storeArtifact(ctx, result.FilePath)
+	return result, nil
+}
+
+// storeArtifact uploads a rendered image to the object storage of the rendering artifacts. The image is still
+// returned when the upload fails, as the local file can be used by this instance.
+func (rs *RenderingService) storeArtifact(ctx context.Context, filePath string) {
+	if rs.artifacts == nil {
+		return
+	}
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the path is generated by getNewFilePath
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		rs.log.Warn("Failed to read rendered image", "path", filePath, "error", err)
+		return
+	}
+	if err := rs.artifacts.Put(ctx, filepath.Base(filePath), data); err != nil {
+		rs.log.Warn("Failed to store rendered image", "path", filePath, "error", err)
+	}
 }
 
 func (rs *RenderingService) RenderCSV(ctx context.Context, opts CSVOpts, session Session) (*RenderCSVResult, error) {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/objectstore"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/setting"
@@ -244,3 +246,20 @@ func TestRenderingServiceGetRemotePluginVersion(t *testing.T) {
 		require.Eventually(t, func() bool { return rs.Version() == "3.1.4159" }, time.Second, time.Millisecond)
 	})
 }
+
+func TestStoreArtifact(t *testing.T) {
+	store, err := objectstore.New(context.Background(), setting.ObjectStoreSettings{
+		Provider: objectstore.ProviderFile,
+		File:     setting.ObjectStoreFileSettings{Path: t.TempDir()},
+	})
+	require.NoError(t, err)
+	rs := &RenderingService{log: log.NewNopLogger(), artifacts: store}
+
+	filePath := filepath.Join(t.TempDir(), "rendered.png")
+	require.NoError(t, os.WriteFile(filePath, []byte("png"), 0600))
+	rs.storeArtifact(context.Background(), filePath)
+
+	data, err := store.Get(context.Background(), "rendered.png")
+	require.NoError(t, err)
+	require.Equal(t, []byte("png"), data)
+}
@@ -0,0 +1,163 @@
+package rendering
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	signedURLSignatureParam = "signature"
+	signedURLExpiresParam   = "expires"
+	signedURLUserParam      = "signedBy"
+	signedURLRoleParam      = "signedRole"
+
+	// defaultSecretKey is the secret_key of the default configuration, signatures made with it could be forged by
+	// anyone.
+	defaultSecretKey = "SW2YcwTIb9zpOOhoPsMm"
+)
+
+var (
+	ErrInvalidSignedURL  = errutil.NewBase(errutil.StatusUnauthorized, "rendering.invalidSignedURL", errutil.WithPublicMessage("Invalid signed render URL"))
+	ErrExpiredSignedURL  = errutil.NewBase(errutil.StatusUnauthorized, "rendering.expiredSignedURL", errutil.WithPublicMessage("Signed render URL has expired"))
+	ErrSignedURLDisabled = errutil.NewBase(errutil.StatusForbidden, "rendering.signedURLDisabled", errutil.WithPublicMessage("Signed render URLs require a custom secret_key"))
+
+	// signedURLPathRegexp matches the dashboard and panel paths that can be signed, the dashboard UID is captured.
+	signedURLPathRegexp = regexp.MustCompile(`^/?d(?:-solo)?/([a-zA-Z0-9_-]+)(?:/[^/]*)?$`)
+)
+
+// SignedURLScope is what a signed render URL allows: rendering a dashboard, or one of its panels, over a time range
+// on behalf of the user who signed it.
+type SignedURLScope struct {
+	AuthOpts
+	DashboardUID string
+	PanelID      string
+	From         string
+	To           string
+}
+
+// IsSignedURL returns whether the render request is authenticated by a signature.
+func IsSignedURL(query url.Values) bool {
+	return query.Get(signedURLSignatureParam) != ""
+}
+
+// DashboardUIDFromPath returns the UID of the dashboard of a render path that can be signed.
+func DashboardUIDFromPath(path string) (string, bool) {
+	match := signedURLPathRegexp.FindStringSubmatch(path)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// WithoutSignature returns the query of a signed render URL without the signature parameters, which are not passed
+// to the image renderer.
+func WithoutSignature(query url.Values) url.Values {
+	stripped := url.Values{}
+	for key, values := range query {
+		switch key {
+		case signedURLSignatureParam, signedURLExpiresParam, signedURLUserParam, signedURLRoleParam:
+		default:
+			stripped[key] = values
+		}
+	}
+	return stripped
+}
+
+// SignURL signs the render path of a dashboard or panel, with its query, on behalf of the user. The signature covers
+// the path and every query parameter, so the dashboard, the panel and the time range cannot be changed.
+func SignURL(secret, path string, query url.Values, user AuthOpts, expires time.Time) (string, error) {
+	if err := checkSecret(secret); err != nil {
+		return "", err
+	}
+	if !signedURLPathRegexp.MatchString(path) {
+		return "", ErrInvalidSignedURL.Errorf("only dashboard and panel paths can be signed: %s", path)
+	}
+
+	signed := url.Values{}
+	for key, values := range query {
+		signed[key] = append([]string(nil), values...)
+	}
+	signed.Del(signedURLSignatureParam)
+	signed.Set("orgId", strconv.FormatInt(user.OrgID, 10))
+	signed.Set(signedURLUserParam, strconv.FormatInt(user.UserID, 10))
+	signed.Set(signedURLRoleParam, string(user.OrgRole))
+	signed.Set(signedURLExpiresParam, strconv.FormatInt(expires.Unix(), 10))
+	signed.Set(signedURLSignatureParam, signature(secret, path, signed))
+
+	return strings.TrimPrefix(path, "/") + "?" + signed.Encode(), nil
+}
+
+// ValidateSignedURL checks the signature and the expiry of a signed render URL and returns its scope.
+func ValidateSignedURL(secret, path string, query url.Values, now time.Time) (*SignedURLScope, error) {
+	if err := checkSecret(secret); err != nil {
+		return nil, err
+	}
+	sig := query.Get(signedURLSignatureParam)
+	unsigned := url.Values{}
+	for key, values := range query {
+		if key != signedURLSignatureParam {
+			unsigned[key] = values
+		}
+	}
+	if sig == "" || !hmac.Equal([]byte(sig), []byte(signature(secret, path, unsigned))) {
+		return nil, ErrInvalidSignedURL.Errorf("signature mismatch")
+	}
+
+	expires, err := strconv.ParseInt(query.Get(signedURLExpiresParam), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignedURL.Errorf("invalid expiry: %w", err)
+	}
+	if now.After(time.Unix(expires, 0)) {
+		return nil, ErrExpiredSignedURL.Errorf("signed render URL expired at %s", time.Unix(expires, 0))
+	}
+
+	dashboardUID, ok := DashboardUIDFromPath(path)
+	if !ok {
+		return nil, ErrInvalidSignedURL.Errorf("not a dashboard or panel path: %s", path)
+	}
+	orgID, err := strconv.ParseInt(query.Get("orgId"), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignedURL.Errorf("invalid organization: %w", err)
+	}
+	userID, err := strconv.ParseInt(query.Get(signedURLUserParam), 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignedURL.Errorf("invalid user: %w", err)
+	}
+	role := org.RoleType(query.Get(signedURLRoleParam))
+	if !role.IsValid() {
+		return nil, ErrInvalidSignedURL.Errorf("invalid role: %s", role)
+	}
+
+	return &SignedURLScope{
+		AuthOpts:     AuthOpts{OrgID: orgID, UserID: userID, OrgRole: role},
+		DashboardUID: dashboardUID,
+		PanelID:      query.Get("panelId"),
+		From:         query.Get("from"),
+		To:           query.Get("to"),
+	}, nil
+}
+
+func checkSecret(secret string) error {
+	if secret == "" || secret == defaultSecretKey {
+		return ErrSignedURLDisabled.Errorf("signed render URLs cannot be used with an empty or the default secret_key")
+	}
+	return nil
+}
+
+// signature is the HMAC of the path and the query, url.Values.Encode sorts the query by key.
+func signature(secret, path string, query url.Values) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.TrimPrefix(path, "/")))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(query.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,90 @@
+package rendering
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+func TestSignedURL(t *testing.T) {
+	const secret = "secret"
+	now := time.Unix(1680000000, 0)
+	user := AuthOpts{OrgID: 2, UserID: 3, OrgRole: org.RoleViewer}
+	query := url.Values{"panelId": {"4"}, "from": {"1000"}, "to": {"2000"}, "width": {"1000"}}
+
+	sign := func(t *testing.T) (string, url.Values) {
+		t.Helper()
+		signed, err := SignURL(secret, "/d-solo/abc/my-dashboard", query, user, now.Add(time.Hour))
+		require.NoError(t, err)
+		path, rawQuery, ok := strings.Cut(signed, "?")
+		require.True(t, ok)
+		values, err := url.ParseQuery(rawQuery)
+		require.NoError(t, err)
+		return path, values
+	}
+
+	t.Run("validates the scope of a signed URL", func(t *testing.T) {
+		path, values := sign(t)
+		require.Equal(t, "d-solo/abc/my-dashboard", path)
+		require.True(t, IsSignedURL(values))
+
+		scope, err := ValidateSignedURL(secret, path, values, now)
+		require.NoError(t, err)
+		assert.Equal(t, &SignedURLScope{
+			AuthOpts:     user,
+			DashboardUID: "abc",
+			PanelID:      "4",
+			From:         "1000",
+			To:           "2000",
+		}, scope)
+
+		assert.Equal(t, url.Values{"panelId": {"4"}, "from": {"1000"}, "to": {"2000"}, "width": {"1000"}, "orgId": {"2"}}, WithoutSignature(values))
+	})
+
+	t.Run("rejects a changed scope", func(t *testing.T) {
+		for param, value := range map[string]string{"from": "0", "panelId": "5", "orgId": "1", "signedRole": "Admin", "expires": "9999999999", "var-host": "a"} {
+			path, values := sign(t)
+			values.Set(param, value)
+			_, err := ValidateSignedURL(secret, path, values, now)
+			assert.ErrorIs(t, err, ErrInvalidSignedURL, param)
+		}
+
+		_, values := sign(t)
+		_, err := ValidateSignedURL(secret, "d-solo/other/my-dashboard", values, now)
+		assert.ErrorIs(t, err, ErrInvalidSignedURL)
+
+		path, values := sign(t)
+		_, err = ValidateSignedURL("other secret", path, values, now)
+		assert.ErrorIs(t, err, ErrInvalidSignedURL)
+	})
+
+	t.Run("rejects an expired URL", func(t *testing.T) {
+		path, values := sign(t)
+		_, err := ValidateSignedURL(secret, path, values, now.Add(2*time.Hour))
+		assert.ErrorIs(t, err, ErrExpiredSignedURL)
+	})
+
+	t.Run("requires a custom secret key", func(t *testing.T) {
+		_, err := SignURL(defaultSecretKey, "/d-solo/abc/my-dashboard", query, user, now.Add(time.Hour))
+		assert.ErrorIs(t, err, ErrSignedURLDisabled)
+
+		path, values := sign(t)
+		_, err = ValidateSignedURL("", path, values, now)
+		assert.ErrorIs(t, err, ErrSignedURLDisabled)
+	})
+
+	t.Run("only signs dashboard and panel paths", func(t *testing.T) {
+		_, err := SignURL(secret, "/api/datasources", url.Values{}, user, now)
+		assert.ErrorIs(t, err, ErrInvalidSignedURL)
+
+		uid, ok := DashboardUIDFromPath("d/abc")
+		assert.True(t, ok)
+		assert.Equal(t, "abc", uid)
+	})
+}
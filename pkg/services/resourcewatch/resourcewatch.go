@@ -0,0 +1,160 @@
+// Package resourcewatch streams the changes of dashboards, folders and alert rules published on the bus to the
+// clients watching the list endpoints, so that external sync tools don't have to poll them.
+package resourcewatch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// Kind is the kind of a watched resource.
+type Kind string
+
+const (
+	KindDashboard Kind = "dashboard"
+	KindFolder    Kind = "folder"
+	KindAlertRule Kind = "alert-rule"
+)
+
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event is a change of a resource. The deleted events only have the UID of the resource.
+type Event struct {
+	Type      EventType `json:"type"`
+	Kind      Kind      `json:"kind"`
+	UID       string    `json:"uid"`
+	Title     string    `json:"title,omitempty"`
+	FolderUID string    `json:"folderUid,omitempty"`
+	RuleGroup string    `json:"ruleGroup,omitempty"`
+	Version   int64     `json:"version,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	OrgID int64 `json:"-"`
+}
+
+// watcherBufferSize is the number of events buffered for a watcher. The watchers that don't keep up are
+// disconnected rather than slowing down the publishers.
+const watcherBufferSize = 100
+
+// Service dispatches the events published on the bus to the watchers of their organization and kind.
+type Service struct {
+	log      log.Logger
+	mu       sync.Mutex
+	watchers map[*watcher]struct{}
+}
+
+type watcher struct {
+	orgID  int64
+	kinds  map[Kind]bool
+	events chan Event
+}
+
+func ProvideService(bus bus.Bus) *Service {
+	s := &Service{
+		log:      log.New("resourcewatch"),
+		watchers: map[*watcher]struct{}{},
+	}
+
+	bus.AddEventListener(s.handleDashboardCreated)
+	bus.AddEventListener(s.handleDashboardUpdated)
+	bus.AddEventListener(s.handleDashboardDeleted)
+	bus.AddEventListener(s.handleAlertRuleCreated)
+	bus.AddEventListener(s.handleAlertRuleUpdated)
+	bus.AddEventListener(s.handleAlertRuleDeleted)
+
+	return s
+}
+
+// Watch returns the events of the given kinds of the organization. The channel is closed when the context is done,
+// or when the watcher doesn't consume the events fast enough.
+func (s *Service) Watch(ctx context.Context, orgID int64, kinds ...Kind) <-chan Event {
+	w := &watcher{orgID: orgID, kinds: make(map[Kind]bool, len(kinds)), events: make(chan Event, watcherBufferSize)}
+	for _, k := range kinds {
+		w.kinds[k] = true
+	}
+
+	s.mu.Lock()
+	s.watchers[w] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.remove(w)
+	}()
+
+	return w.events
+}
+
+func (s *Service) remove(w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.watchers[w]; ok {
+		delete(s.watchers, w)
+		close(w.events)
+	}
+}
+
+func (s *Service) publish(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for w := range s.watchers {
+		if w.orgID != e.OrgID || !w.kinds[e.Kind] {
+			continue
+		}
+		select {
+		case w.events <- e:
+		default:
+			s.log.Warn("Disconnecting resource watcher that doesn't keep up with the events", "orgId", w.orgID)
+			delete(s.watchers, w)
+			close(w.events)
+		}
+	}
+}
+
+func dashboardKind(isFolder bool) Kind {
+	if isFolder {
+		return KindFolder
+	}
+	return KindDashboard
+}
+
+func (s *Service) handleDashboardCreated(_ context.Context, e *events.DashboardCreated) error {
+	s.publish(Event{Type: EventCreated, Kind: dashboardKind(e.IsFolder), UID: e.UID, Title: e.Title, Version: int64(e.Version), Timestamp: e.Timestamp, OrgID: e.OrgID})
+	return nil
+}
+
+func (s *Service) handleDashboardUpdated(_ context.Context, e *events.DashboardUpdated) error {
+	s.publish(Event{Type: EventUpdated, Kind: dashboardKind(e.IsFolder), UID: e.UID, Title: e.Title, Version: int64(e.Version), Timestamp: e.Timestamp, OrgID: e.OrgID})
+	return nil
+}
+
+func (s *Service) handleDashboardDeleted(_ context.Context, e *events.DashboardDeleted) error {
+	s.publish(Event{Type: EventDeleted, Kind: dashboardKind(e.IsFolder), UID: e.UID, Timestamp: e.Timestamp, OrgID: e.OrgID})
+	return nil
+}
+
+func (s *Service) handleAlertRuleCreated(_ context.Context, e *events.AlertRuleCreated) error {
+	s.publish(Event{Type: EventCreated, Kind: KindAlertRule, UID: e.UID, Title: e.Title, FolderUID: e.NamespaceUID, RuleGroup: e.RuleGroup, Version: e.Version, Timestamp: e.Timestamp, OrgID: e.OrgID})
+	return nil
+}
+
+func (s *Service) handleAlertRuleUpdated(_ context.Context, e *events.AlertRuleUpdated) error {
+	s.publish(Event{Type: EventUpdated, Kind: KindAlertRule, UID: e.UID, Title: e.Title, FolderUID: e.NamespaceUID, RuleGroup: e.RuleGroup, Version: e.Version, Timestamp: e.Timestamp, OrgID: e.OrgID})
+	return nil
+}
+
+func (s *Service) handleAlertRuleDeleted(_ context.Context, e *events.AlertRuleDeleted) error {
+	s.publish(Event{Type: EventDeleted, Kind: KindAlertRule, UID: e.UID, Timestamp: e.Timestamp, OrgID: e.OrgID})
+	return nil
+}
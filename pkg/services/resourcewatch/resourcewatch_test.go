@@ -0,0 +1,65 @@
+package resourcewatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestService(t *testing.T) {
+	now := time.Now()
+
+	t.Run("should send the events of the organization and kinds of the watcher", func(t *testing.T) {
+		b := bus.ProvideBus(tracing.InitializeTracerForTest())
+		s := ProvideService(b)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		watched := s.Watch(ctx, 1, KindDashboard, KindAlertRule)
+
+		require.NoError(t, b.Publish(ctx, &events.DashboardCreated{Timestamp: now, UID: "dash", Title: "Dash", OrgID: 1, Version: 1}))
+		require.NoError(t, b.Publish(ctx, &events.DashboardCreated{Timestamp: now, UID: "other-org", OrgID: 2}))
+		require.NoError(t, b.Publish(ctx, &events.DashboardUpdated{Timestamp: now, UID: "folder", OrgID: 1, IsFolder: true}))
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleUpdated{Timestamp: now, UID: "rule", Title: "Rule", OrgID: 1, NamespaceUID: "folder", RuleGroup: "group", Version: 2}))
+		require.NoError(t, b.Publish(ctx, &events.DashboardDeleted{Timestamp: now, UID: "dash", OrgID: 1}))
+
+		require.Equal(t, Event{Type: EventCreated, Kind: KindDashboard, UID: "dash", Title: "Dash", Version: 1, Timestamp: now, OrgID: 1}, <-watched)
+		require.Equal(t, Event{Type: EventUpdated, Kind: KindAlertRule, UID: "rule", Title: "Rule", FolderUID: "folder", RuleGroup: "group", Version: 2, Timestamp: now, OrgID: 1}, <-watched)
+		require.Equal(t, Event{Type: EventDeleted, Kind: KindDashboard, UID: "dash", Timestamp: now, OrgID: 1}, <-watched)
+		require.Empty(t, watched)
+	})
+
+	t.Run("should close the events when the context is done", func(t *testing.T) {
+		s := ProvideService(bus.ProvideBus(tracing.InitializeTracerForTest()))
+		ctx, cancel := context.WithCancel(context.Background())
+		watched := s.Watch(ctx, 1, KindFolder)
+		cancel()
+
+		_, ok := <-watched
+		require.False(t, ok)
+	})
+
+	t.Run("should disconnect the watchers that don't keep up", func(t *testing.T) {
+		b := bus.ProvideBus(tracing.InitializeTracerForTest())
+		s := ProvideService(b)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		watched := s.Watch(ctx, 1, KindAlertRule)
+
+		for i := 0; i <= watcherBufferSize; i++ {
+			require.NoError(t, b.Publish(ctx, &events.AlertRuleDeleted{Timestamp: now, UID: "rule", OrgID: 1}))
+		}
+
+		received := 0
+		for range watched {
+			received++
+		}
+		require.Equal(t, watcherBufferSize, received)
+		require.Empty(t, s.watchers)
+	})
+}
@@ -0,0 +1,41 @@
+package resourcewatch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+const contentTypeStream = "application/x-ndjson"
+
+// Stream writes the events the signed in user can view to the response as newline delimited JSON, flushing each
+// event, until the request is canceled or the events channel is closed. canView is only called for the created
+// and updated events, the deleted resources can't be checked anymore.
+func Stream(c *contextmodel.ReqContext, events <-chan Event, canView func(ctx context.Context, e Event) bool) response.Response {
+	c.Resp.Header().Set("Content-Type", contentTypeStream)
+	c.Resp.WriteHeader(http.StatusOK)
+	c.Resp.Flush()
+
+	enc := json.NewEncoder(c.Resp)
+	ctx := c.Req.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if e.Type != EventDeleted && !canView(ctx, e) {
+				continue
+			}
+			if err := enc.Encode(e); err != nil {
+				return nil
+			}
+			c.Resp.Flush()
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package savedsearch
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+const (
+	ActionRead  = "savedsearches:read"
+	ActionWrite = "savedsearches:write"
+)
+
+var (
+	ErrNotFound     = errutil.NewBase(errutil.StatusNotFound, "savedsearch.notFound", errutil.WithPublicMessage("Saved search not found"))
+	ErrNotOwner     = errutil.NewBase(errutil.StatusForbidden, "savedsearch.notOwner", errutil.WithPublicMessage("Only the owner of the saved search or an organization admin can change it"))
+	ErrInvalid      = errutil.NewBase(errutil.StatusBadRequest, "savedsearch.invalid")
+	ErrNameTaken    = errutil.NewBase(errutil.StatusBadRequest, "savedsearch.nameTaken", errutil.WithPublicMessage("A saved search with the same name already exists"))
+	ErrTeamNotFound = errutil.NewBase(errutil.StatusBadRequest, "savedsearch.teamNotFound", errutil.WithPublicMessage("Team not found, or the user isn't a member of the team"))
+)
+
+// Service stores the searches of dashboards and folders saved by the users. A saved search is private to its owner,
+// unless it's shared with a team, whose members can then read and run it.
+type Service interface {
+	// List returns the searches saved by the user, and the ones shared with the teams of the user.
+	List(ctx context.Context, usr *user.SignedInUser) ([]*SavedSearch, error)
+	// Get returns the saved search, if the user owns it or it's shared with a team of the user.
+	Get(ctx context.Context, usr *user.SignedInUser, uid string) (*SavedSearch, error)
+	Create(ctx context.Context, usr *user.SignedInUser, cmd *SaveCommand) (*SavedSearch, error)
+	// Update replaces the saved search, only its owner or an organization admin can change it.
+	Update(ctx context.Context, usr *user.SignedInUser, uid string, cmd *SaveCommand) (*SavedSearch, error)
+	Delete(ctx context.Context, usr *user.SignedInUser, uid string) error
+}
+
+type SavedSearch struct {
+	ID      int64    `xorm:"pk autoincr 'id'" json:"-"`
+	UID     string   `xorm:"uid" json:"uid"`
+	OrgID   int64    `xorm:"org_id" json:"orgId"`
+	Name    string   `xorm:"name" json:"name"`
+	Query   string   `xorm:"query" json:"query"`
+	Filters *Filters `xorm:"filters" json:"filters"`
+	OwnerID int64    `xorm:"owner_id" json:"ownerId"`
+	// TeamID is the team the search is shared with, the search is private when 0
+	TeamID  int64     `xorm:"team_id" json:"teamId"`
+	Created time.Time `xorm:"created" json:"created"`
+	Updated time.Time `xorm:"updated" json:"updated"`
+}
+
+func (s SavedSearch) TableName() string {
+	return "saved_search"
+}
+
+// Filters are the filters of the search API saved with the query.
+type Filters struct {
+	Tags      []string `json:"tags,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	FolderIDs []int64  `json:"folderIds,omitempty"`
+	Starred   bool     `json:"starred,omitempty"`
+	Sort      string   `json:"sort,omitempty"`
+}
+
+func (f *Filters) FromDB(data []byte) error {
+	return json.Unmarshal(data, f)
+}
+
+func (f *Filters) ToDB() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+type SaveCommand struct {
+	Name    string  `json:"name"`
+	Query   string  `json:"query"`
+	Filters Filters `json:"filters"`
+	TeamID  int64   `json:"teamId"`
+}
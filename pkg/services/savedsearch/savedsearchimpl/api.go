@@ -0,0 +1,74 @@
+package savedsearchimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/saved-searches", func(searches routing.RouteRegister) {
+		searches.Get("/", authorize(middleware.ReqSignedIn, ac.EvalPermission(savedsearch.ActionRead)), routing.Wrap(s.handleList))
+		searches.Post("/", authorize(middleware.ReqEditorRole, ac.EvalPermission(savedsearch.ActionWrite)), routing.Wrap(s.handleCreate))
+		searches.Get("/:uid", authorize(middleware.ReqSignedIn, ac.EvalPermission(savedsearch.ActionRead)), routing.Wrap(s.handleGet))
+		searches.Put("/:uid", authorize(middleware.ReqEditorRole, ac.EvalPermission(savedsearch.ActionWrite)), routing.Wrap(s.handleUpdate))
+		searches.Delete("/:uid", authorize(middleware.ReqEditorRole, ac.EvalPermission(savedsearch.ActionWrite)), routing.Wrap(s.handleDelete))
+	}, middleware.ReqSignedIn)
+}
+
+func (s *Service) handleList(c *contextmodel.ReqContext) response.Response {
+	searches, err := s.List(c.Req.Context(), c.SignedInUser)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list saved searches", err)
+	}
+	return response.JSON(http.StatusOK, searches)
+}
+
+func (s *Service) handleGet(c *contextmodel.ReqContext) response.Response {
+	search, err := s.Get(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get saved search", err)
+	}
+	return response.JSON(http.StatusOK, search)
+}
+
+func (s *Service) handleCreate(c *contextmodel.ReqContext) response.Response {
+	cmd := savedsearch.SaveCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	search, err := s.Create(c.Req.Context(), c.SignedInUser, &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create saved search", err)
+	}
+	return response.JSON(http.StatusOK, search)
+}
+
+func (s *Service) handleUpdate(c *contextmodel.ReqContext) response.Response {
+	cmd := savedsearch.SaveCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	search, err := s.Update(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"], &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to update saved search", err)
+	}
+	return response.JSON(http.StatusOK, search)
+}
+
+func (s *Service) handleDelete(c *contextmodel.ReqContext) response.Response {
+	if err := s.Delete(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"]); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete saved search", err)
+	}
+	return response.Success("Saved search deleted")
+}
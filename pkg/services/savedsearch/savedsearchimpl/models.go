@@ -0,0 +1,29 @@
+package savedsearchimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+)
+
+var (
+	savedSearchesReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:savedsearches:reader",
+		DisplayName: "Saved search reader",
+		Description: "Read and run the saved searches owned by the user or shared with the teams of the user",
+		Group:       "Dashboards",
+		Permissions: []accesscontrol.Permission{
+			{Action: savedsearch.ActionRead},
+		},
+	}
+
+	savedSearchesWriterRole = accesscontrol.RoleDTO{
+		Name:        "fixed:savedsearches:writer",
+		DisplayName: "Saved search writer",
+		Description: "Read, run, create, update, share and delete saved searches",
+		Group:       "Dashboards",
+		Permissions: []accesscontrol.Permission{
+			{Action: savedsearch.ActionRead},
+			{Action: savedsearch.ActionWrite},
+		},
+	}
+)
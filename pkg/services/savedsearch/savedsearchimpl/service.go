@@ -0,0 +1,168 @@
+package savedsearchimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const maxNameLength = 190
+
+type Service struct {
+	store         store
+	accessControl ac.AccessControl
+	teamService   team.Service
+	log           log.Logger
+}
+
+var _ savedsearch.Service = (*Service)(nil)
+
+func ProvideService(
+	sql db.DB,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	teamService team.Service,
+	routeRegister routing.RouteRegister,
+) (*Service, error) {
+	s := &Service{
+		store:         &sqlStore{db: sql, now: time.Now},
+		accessControl: accessControl,
+		teamService:   teamService,
+		log:           log.New("savedsearch"),
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := accesscontrolService.DeclareFixedRoles(
+			ac.RoleRegistration{Role: savedSearchesReaderRole, Grants: []string{string(org.RoleViewer)}},
+			ac.RoleRegistration{Role: savedSearchesWriterRole, Grants: []string{string(org.RoleEditor)}},
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) List(ctx context.Context, usr *user.SignedInUser) ([]*savedsearch.SavedSearch, error) {
+	return s.store.List(ctx, usr.OrgID, usr.UserID, usr.Teams)
+}
+
+func (s *Service) Get(ctx context.Context, usr *user.SignedInUser, uid string) (*savedsearch.SavedSearch, error) {
+	search, err := s.store.Get(ctx, usr.OrgID, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !canRead(usr, search) {
+		// the searches of the other users are hidden rather than forbidden
+		return nil, savedsearch.ErrNotFound.Errorf("saved search %s isn't shared with user %d", uid, usr.UserID)
+	}
+	return search, nil
+}
+
+func (s *Service) Create(ctx context.Context, usr *user.SignedInUser, cmd *savedsearch.SaveCommand) (*savedsearch.SavedSearch, error) {
+	if err := s.validate(ctx, usr, cmd); err != nil {
+		return nil, err
+	}
+
+	search := &savedsearch.SavedSearch{
+		UID:     util.GenerateShortUID(),
+		OrgID:   usr.OrgID,
+		OwnerID: usr.UserID,
+	}
+	apply(search, cmd)
+	if err := s.store.Insert(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *Service) Update(ctx context.Context, usr *user.SignedInUser, uid string, cmd *savedsearch.SaveCommand) (*savedsearch.SavedSearch, error) {
+	search, err := s.getForWrite(ctx, usr, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, usr, cmd); err != nil {
+		return nil, err
+	}
+
+	apply(search, cmd)
+	if err := s.store.Update(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *Service) Delete(ctx context.Context, usr *user.SignedInUser, uid string) error {
+	search, err := s.getForWrite(ctx, usr, uid)
+	if err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, search.OrgID, search.ID)
+}
+
+func (s *Service) getForWrite(ctx context.Context, usr *user.SignedInUser, uid string) (*savedsearch.SavedSearch, error) {
+	search, err := s.Get(ctx, usr, uid)
+	if err != nil {
+		return nil, err
+	}
+	if search.OwnerID != usr.UserID && usr.OrgRole != org.RoleAdmin {
+		return nil, savedsearch.ErrNotOwner.Errorf("user %d doesn't own saved search %s", usr.UserID, uid)
+	}
+	return search, nil
+}
+
+// validate checks the command, the search can only be shared with a team of the user, or any team of the
+// organization by the organization admins.
+func (s *Service) validate(ctx context.Context, usr *user.SignedInUser, cmd *savedsearch.SaveCommand) error {
+	cmd.Name = strings.TrimSpace(cmd.Name)
+	if cmd.Name == "" {
+		return savedsearch.ErrInvalid.Errorf("name is required")
+	}
+	if len(cmd.Name) > maxNameLength {
+		return savedsearch.ErrInvalid.Errorf("name is longer than %d characters", maxNameLength)
+	}
+
+	if cmd.TeamID == 0 {
+		return nil
+	}
+	if usr.OrgRole != org.RoleAdmin && !isMember(usr, cmd.TeamID) {
+		return savedsearch.ErrTeamNotFound.Errorf("user %d isn't a member of team %d", usr.UserID, cmd.TeamID)
+	}
+	if _, err := s.teamService.GetTeamByID(ctx, &team.GetTeamByIDQuery{OrgID: usr.OrgID, ID: cmd.TeamID, SignedInUser: usr}); err != nil {
+		return savedsearch.ErrTeamNotFound.Errorf("team %d not found in org %d: %w", cmd.TeamID, usr.OrgID, err)
+	}
+	return nil
+}
+
+func apply(search *savedsearch.SavedSearch, cmd *savedsearch.SaveCommand) {
+	filters := cmd.Filters
+	search.Name = cmd.Name
+	search.Query = cmd.Query
+	search.Filters = &filters
+	search.TeamID = cmd.TeamID
+}
+
+func canRead(usr *user.SignedInUser, search *savedsearch.SavedSearch) bool {
+	return search.OwnerID == usr.UserID || usr.OrgRole == org.RoleAdmin || (search.TeamID != 0 && isMember(usr, search.TeamID))
+}
+
+func isMember(usr *user.SignedInUser, teamID int64) bool {
+	for _, id := range usr.Teams {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package savedsearchimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationSavedSearch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	s := &Service{
+		store:       &sqlStore{db: testDB, now: time.Now},
+		teamService: &teamtest.FakeService{ExpectedTeamDTO: &team.TeamDTO{ID: 1}},
+		log:         log.NewNopLogger(),
+	}
+	ctx := context.Background()
+
+	owner := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor, Teams: []int64{1}}
+	member := &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleEditor, Teams: []int64{1}}
+	other := &user.SignedInUser{UserID: 3, OrgID: 1, OrgRole: org.RoleEditor}
+	admin := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleAdmin}
+
+	private, err := s.Create(ctx, owner, &savedsearch.SaveCommand{Name: "mine", Query: "cpu", Filters: savedsearch.Filters{Tags: []string{"infra"}}})
+	require.NoError(t, err)
+	shared, err := s.Create(ctx, owner, &savedsearch.SaveCommand{Name: "ours", Query: "db", TeamID: 1})
+	require.NoError(t, err)
+
+	t.Run("should keep the searches private unless shared with a team", func(t *testing.T) {
+		searches, err := s.List(ctx, owner)
+		require.NoError(t, err)
+		require.Len(t, searches, 2)
+		require.Equal(t, []string{"infra"}, searches[0].Filters.Tags)
+
+		searches, err = s.List(ctx, member)
+		require.NoError(t, err)
+		require.Len(t, searches, 1)
+		require.Equal(t, shared.UID, searches[0].UID)
+
+		_, err = s.Get(ctx, member, private.UID)
+		require.ErrorIs(t, err, savedsearch.ErrNotFound)
+		_, err = s.Get(ctx, other, shared.UID)
+		require.ErrorIs(t, err, savedsearch.ErrNotFound)
+		_, err = s.Get(ctx, admin, private.UID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should only let the owner or an admin change a search", func(t *testing.T) {
+		_, err := s.Update(ctx, member, shared.UID, &savedsearch.SaveCommand{Name: "ours", Query: "mysql", TeamID: 1})
+		require.ErrorIs(t, err, savedsearch.ErrNotOwner)
+
+		updated, err := s.Update(ctx, admin, shared.UID, &savedsearch.SaveCommand{Name: "ours", Query: "mysql", TeamID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "mysql", updated.Query)
+		require.Equal(t, owner.UserID, updated.OwnerID)
+
+		require.ErrorIs(t, s.Delete(ctx, member, shared.UID), savedsearch.ErrNotOwner)
+	})
+
+	t.Run("should reject invalid searches", func(t *testing.T) {
+		_, err := s.Create(ctx, owner, &savedsearch.SaveCommand{Name: "mine"})
+		require.ErrorIs(t, err, savedsearch.ErrNameTaken)
+		_, err = s.Create(ctx, owner, &savedsearch.SaveCommand{Name: " "})
+		require.ErrorIs(t, err, savedsearch.ErrInvalid)
+		_, err = s.Create(ctx, other, &savedsearch.SaveCommand{Name: "theirs", TeamID: 1})
+		require.ErrorIs(t, err, savedsearch.ErrTeamNotFound)
+
+		// the names are unique per owner
+		_, err = s.Create(ctx, member, &savedsearch.SaveCommand{Name: "mine"})
+		require.NoError(t, err)
+	})
+
+	t.Run("should delete the search", func(t *testing.T) {
+		require.NoError(t, s.Delete(ctx, owner, private.UID))
+		_, err := s.Get(ctx, owner, private.UID)
+		require.ErrorIs(t, err, savedsearch.ErrNotFound)
+	})
+}
@@ -0,0 +1,108 @@
+package savedsearchimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+)
+
+type store interface {
+	// List returns the searches of the organization owned by the user or shared with one of the teams.
+	List(ctx context.Context, orgID, ownerID int64, teamIDs []int64) ([]*savedsearch.SavedSearch, error)
+	Get(ctx context.Context, orgID int64, uid string) (*savedsearch.SavedSearch, error)
+	Insert(ctx context.Context, search *savedsearch.SavedSearch) error
+	Update(ctx context.Context, search *savedsearch.SavedSearch) error
+	Delete(ctx context.Context, orgID, id int64) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) List(ctx context.Context, orgID, ownerID int64, teamIDs []int64) ([]*savedsearch.SavedSearch, error) {
+	searches := make([]*savedsearch.SavedSearch, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", orgID)
+		if len(teamIDs) > 0 {
+			args := []interface{}{ownerID}
+			placeholders := ""
+			for i, id := range teamIDs {
+				if i > 0 {
+					placeholders += ","
+				}
+				placeholders += "?"
+				args = append(args, id)
+			}
+			q = q.And("(owner_id = ? OR team_id IN ("+placeholders+"))", args...)
+		} else {
+			q = q.And("owner_id = ?", ownerID)
+		}
+		return q.Asc("name", "id").Find(&searches)
+	})
+	return searches, err
+}
+
+func (s *sqlStore) Get(ctx context.Context, orgID int64, uid string) (*savedsearch.SavedSearch, error) {
+	search := &savedsearch.SavedSearch{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(search)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return savedsearch.ErrNotFound.Errorf("saved search %s not found in org %d", uid, orgID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+func (s *sqlStore) Insert(ctx context.Context, search *savedsearch.SavedSearch) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := checkName(sess, search); err != nil {
+			return err
+		}
+		now := s.now()
+		search.Created = now
+		search.Updated = now
+		_, err := sess.Insert(search)
+		return err
+	})
+}
+
+func (s *sqlStore) Update(ctx context.Context, search *savedsearch.SavedSearch) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if err := checkName(sess, search); err != nil {
+			return err
+		}
+		search.Updated = s.now()
+		_, err := sess.ID(search.ID).Cols("name", "query", "filters", "team_id", "updated").Update(search)
+		return err
+	})
+}
+
+// checkName rejects the searches with the name of another search of their owner.
+func checkName(sess *db.Session, search *savedsearch.SavedSearch) error {
+	taken, err := sess.Table("saved_search").Where("org_id = ? AND owner_id = ? AND name = ? AND id <> ?",
+		search.OrgID, search.OwnerID, search.Name, search.ID).Exist()
+	if err != nil {
+		return err
+	}
+	if taken {
+		return savedsearch.ErrNameTaken.Errorf("saved search %q already exists", search.Name)
+	}
+	return nil
+}
+
+func (s *sqlStore) Delete(ctx context.Context, orgID, id int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM saved_search WHERE org_id = ? AND id = ?", orgID, id)
+		return err
+	})
+}
@@ -0,0 +1,38 @@
+package savedsearchtest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/savedsearch"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type FakeService struct {
+	ExpectedSavedSearch   *savedsearch.SavedSearch
+	ExpectedSavedSearches []*savedsearch.SavedSearch
+	ExpectedError         error
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (s *FakeService) List(ctx context.Context, usr *user.SignedInUser) ([]*savedsearch.SavedSearch, error) {
+	return s.ExpectedSavedSearches, s.ExpectedError
+}
+
+func (s *FakeService) Get(ctx context.Context, usr *user.SignedInUser, uid string) (*savedsearch.SavedSearch, error) {
+	return s.ExpectedSavedSearch, s.ExpectedError
+}
+
+func (s *FakeService) Create(ctx context.Context, usr *user.SignedInUser, cmd *savedsearch.SaveCommand) (*savedsearch.SavedSearch, error) {
+	return s.ExpectedSavedSearch, s.ExpectedError
+}
+
+func (s *FakeService) Update(ctx context.Context, usr *user.SignedInUser, uid string, cmd *savedsearch.SaveCommand) (*savedsearch.SavedSearch, error) {
+	return s.ExpectedSavedSearch, s.ExpectedError
+}
+
+func (s *FakeService) Delete(ctx context.Context, usr *user.SignedInUser, uid string) error {
+	return s.ExpectedError
+}
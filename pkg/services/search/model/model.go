@@ -2,6 +2,7 @@ package model
 
 import (
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 )
@@ -43,6 +44,17 @@ type Hit struct {
 	FolderURL    string   `json:"folderUrl,omitempty"`
 	SortMeta     int64    `json:"sortMeta"`
 	SortMetaName string   `json:"sortMetaName,omitempty"`
+
+	// Usage is only set for dashboards when requested with the usage parameter
+	Usage *HitUsage `json:"usage,omitempty"`
+}
+
+// HitUsage is how much a dashboard was used over the last 30 days
+type HitUsage struct {
+	Views        int64      `json:"views"`
+	Queries      int64      `json:"queries"`
+	Errors       int64      `json:"errors"`
+	LastViewedAt *time.Time `json:"lastViewedAt,omitempty"`
 }
 
 type HitList []*Hit
@@ -66,8 +66,21 @@ func (s *SearchService) SearchHandler(ctx context.Context, query *Query) error {
 		return err
 	}
 
-	// No starred dashboards will be found
-	if query.IsStarred && len(staredDashIDs.UserStars) == 0 {
+	starredFolders, err := s.starService.GetResourcesByUser(ctx, &star.GetUserResourceStarsQuery{
+		UserID: query.SignedInUser.UserID,
+		OrgID:  query.OrgId,
+		Kind:   star.KindFolder,
+	})
+	if err != nil {
+		return err
+	}
+	starredFolderUIDs := make(map[string]bool, len(starredFolders))
+	for _, f := range starredFolders {
+		starredFolderUIDs[f.UID] = true
+	}
+
+	// No starred dashboards or folders will be found
+	if query.IsStarred && len(staredDashIDs.UserStars) == 0 && len(starredFolderUIDs) == 0 {
 		query.Result = model.HitList{}
 		return nil
 	}
@@ -77,6 +90,26 @@ func (s *SearchService) SearchHandler(ctx context.Context, query *Query) error {
 		for id := range staredDashIDs.UserStars {
 			query.DashboardIds = append(query.DashboardIds, id)
 		}
+
+		// folders are stored as dashboards, their IDs are needed to find them along with the starred dashboards
+		if len(starredFolderUIDs) > 0 {
+			uids := make([]string, 0, len(starredFolderUIDs))
+			for uid := range starredFolderUIDs {
+				uids = append(uids, uid)
+			}
+			folders, err := s.dashboardService.GetDashboards(ctx, &dashboards.GetDashboardsQuery{DashboardUIDs: uids, OrgID: query.OrgId})
+			if err != nil {
+				return err
+			}
+			for _, f := range folders {
+				query.DashboardIds = append(query.DashboardIds, f.ID)
+			}
+		}
+
+		if len(query.DashboardIds) == 0 {
+			query.Result = model.HitList{}
+			return nil
+		}
 	}
 
 	dashboardQuery := dashboards.FindPersistedDashboardsQuery{
@@ -105,14 +138,17 @@ func (s *SearchService) SearchHandler(ctx context.Context, query *Query) error {
 		hits = sortedHits(hits)
 	}
 
-	// set starred dashboards
+	// set starred dashboards and folders
 	for _, dashboard := range hits {
 		if _, ok := staredDashIDs.UserStars[dashboard.ID]; ok {
 			dashboard.IsStarred = true
 		}
+		if dashboard.Type == model.DashHitFolder && starredFolderUIDs[dashboard.UID] {
+			dashboard.IsStarred = true
+		}
 	}
 
-	// filter for starred dashboards if requested
+	// filter for starred dashboards and folders if requested
 	if !query.IsStarred {
 		query.Result = hits
 	} else {
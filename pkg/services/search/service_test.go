@@ -98,3 +98,42 @@ func TestSearch_StarredResults(t *testing.T) {
 	assert.Equal(t, "A", query.Result[0].Title)
 	assert.Equal(t, "C", query.Result[1].Title)
 }
+
+func TestSearch_StarredFolders(t *testing.T) {
+	ss := startest.NewStarServiceFake()
+	db := dbtest.NewFakeDB()
+	ds := dashboards.NewFakeDashboardService(t)
+	ds.On("GetDashboards", mock.Anything, &dashboards.GetDashboardsQuery{DashboardUIDs: []string{"folder"}, OrgID: 1}).
+		Return([]*dashboards.Dashboard{{ID: 5, UID: "folder", IsFolder: true}}, nil)
+	ds.On("SearchDashboards", mock.Anything, mock.AnythingOfType("*dashboards.FindPersistedDashboardsQuery")).Run(func(args mock.Arguments) {
+		q := args.Get(1).(*dashboards.FindPersistedDashboardsQuery)
+		assert.ElementsMatch(t, []int64{1, 5}, q.DashboardIds)
+		q.Result = model.HitList{
+			&model.Hit{ID: 1, UID: "dashboard", Title: "A", Type: model.DashHitDB},
+			&model.Hit{ID: 5, UID: "folder", Title: "B", Type: model.DashHitFolder},
+		}
+	}).Return(nil)
+	ss.ExpectedUserStars = &star.GetUserStarsResult{UserStars: map[int64]bool{1: true}}
+	ss.ExpectedResources = []*star.ResourceStar{{OrgID: 1, Kind: star.KindFolder, UID: "folder"}}
+	svc := &SearchService{
+		sqlstore:         db,
+		starService:      ss,
+		dashboardService: ds,
+	}
+
+	query := &Query{
+		Limit:        2000,
+		IsStarred:    true,
+		OrgId:        1,
+		SignedInUser: &user.SignedInUser{OrgID: 1},
+	}
+
+	err := svc.SearchHandler(context.Background(), query)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, query.Result.Len())
+	assert.Equal(t, "B", query.Result[0].Title)
+	assert.True(t, query.Result[0].IsStarred)
+	assert.Equal(t, "A", query.Result[1].Title)
+	assert.True(t, query.Result[1].IsStarred)
+}
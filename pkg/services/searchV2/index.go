@@ -183,7 +183,7 @@ func (i *searchIndex) sync(ctx context.Context) error {
 
 func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh chan struct{}) error {
 	i.logger.Info("Initializing SearchV2", "dashboardLoadingBatchSize", i.settings.DashboardLoadingBatchSize, "fullReindexInterval", i.settings.FullReindexInterval, "indexUpdateInterval", i.settings.IndexUpdateInterval)
-	initialSetupCtx, initialSetupSpan := i.tracer.Start(ctx, "searchV2 initialSetup")
+	initialSetupCtx, initialSetupSpan := i.tracer.Start(ctx, "searchV2 initialSetup", tracing.WithComponent("search"))
 
 	reIndexInterval := i.settings.FullReindexInterval
 	fullReIndexTimer := time.NewTimer(reIndexInterval)
@@ -229,7 +229,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			close(doneCh)
 		case <-partialUpdateTimer.C:
 			// Periodically apply updates collected in entity events table.
-			partialIndexUpdateCtx, span := i.tracer.Start(ctx, "searchV2 partial update timer")
+			partialIndexUpdateCtx, span := i.tracer.Start(ctx, "searchV2 partial update timer", tracing.WithComponent("search"))
 			lastEventID = i.applyIndexUpdates(partialIndexUpdateCtx, lastEventID)
 			span.End()
 			partialUpdateTimer.Reset(partialUpdateInterval)
@@ -238,7 +238,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 			i.logger.Info("Full re-indexing due to external signal")
 			fullReIndexTimer.Reset(0)
 		case signal := <-i.buildSignals:
-			buildSignalCtx, span := i.tracer.Start(ctx, "searchV2 build signal")
+			buildSignalCtx, span := i.tracer.Start(ctx, "searchV2 build signal", tracing.WithComponent("search"))
 
 			// When search read request meets new not-indexed org we build index for it.
 			i.mu.RLock()
@@ -266,7 +266,7 @@ func (i *searchIndex) run(ctx context.Context, orgIDs []int64, reIndexSignalCh c
 				reIndexDoneCh <- lastIndexedEventID
 			}()
 		case <-fullReIndexTimer.C:
-			fullReindexCtx, span := i.tracer.Start(ctx, "searchV2 full reindex timer")
+			fullReindexCtx, span := i.tracer.Start(ctx, "searchV2 full reindex timer", tracing.WithComponent("search"))
 
 			// Periodically rebuild indexes since we could miss updates. At this moment we are issuing
 			// entity events non-atomically (outside of transaction) and do not cover all possible dashboard
@@ -0,0 +1,97 @@
+// Package seats counts the users by the tier of their roles, and checks the role assignments against the
+// configured seat limits of the tiers, so that the number of editors and admins stays within a license.
+package seats
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrSeatLimitReached = errutil.NewBase(errutil.StatusForbidden, "seats.limitReached").MustTemplate(
+	"no {{ .Public.tier }} seat left for user {{ .Private.userId }}",
+	errutil.WithPublic("All the {{ .Public.limit }} {{ .Public.tier }} seats are used"),
+)
+
+// Tier is the level of the permissions granted by the roles of a user. A user holds a seat of its highest tier
+// across all the organizations.
+type Tier string
+
+const (
+	TierViewer Tier = "viewer"
+	TierEditor Tier = "editor"
+	TierAdmin  Tier = "admin"
+)
+
+// Tiers lists the tiers from the lowest to the highest.
+var Tiers = []Tier{TierViewer, TierEditor, TierAdmin}
+
+func (t Tier) rank() int {
+	for i, tier := range Tiers {
+		if tier == t {
+			return i
+		}
+	}
+	return -1
+}
+
+// AtLeast returns true if the tier grants the permissions of the other tier.
+func (t Tier) AtLeast(other Tier) bool {
+	return t.rank() >= other.rank()
+}
+
+// Max returns the highest of the two tiers.
+func (t Tier) Max(other Tier) Tier {
+	if other.rank() > t.rank() {
+		return other
+	}
+	return t
+}
+
+// TierOf returns the tier of an organization role. The viewers are editors when viewers can edit.
+func TierOf(role org.RoleType, viewersCanEdit bool) Tier {
+	switch {
+	case role == org.RoleAdmin:
+		return TierAdmin
+	case role == org.RoleEditor, role == org.RoleViewer && viewersCanEdit:
+		return TierEditor
+	}
+	return TierViewer
+}
+
+// Mode is what happens when a role assignment exceeds a seat limit.
+type Mode string
+
+const (
+	// ModeEnforce rejects the role assignments exceeding a seat limit.
+	ModeEnforce Mode = "enforce"
+	// ModeWarn logs the role assignments exceeding a seat limit.
+	ModeWarn Mode = "warn"
+)
+
+func (m Mode) IsValid() bool {
+	return m == ModeEnforce || m == ModeWarn
+}
+
+type TierUsage struct {
+	Tier Tier  `json:"tier"`
+	Used int64 `json:"used"`
+	// Limit is the number of seats of the tier, 0 when unlimited
+	Limit    int64 `json:"limit"`
+	Exceeded bool  `json:"exceeded"`
+}
+
+type Usage struct {
+	Mode  Mode        `json:"mode"`
+	Tiers []TierUsage `json:"tiers"`
+}
+
+type Service interface {
+	// GetUsage returns the number of users holding a seat of each tier, the disabled users and the service
+	// accounts don't hold a seat.
+	GetUsage(ctx context.Context) (*Usage, error)
+	// CheckAssignment returns ErrSeatLimitReached when the role assignment needs a seat of a tier whose seats are
+	// all used, and seat limits are enforced.
+	CheckAssignment(ctx context.Context, assignment org.RoleAssignment) error
+}
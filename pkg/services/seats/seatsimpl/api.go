@@ -0,0 +1,22 @@
+package seatsimpl
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	routeRegister.Get("/api/admin/seats", middleware.ReqGrafanaAdmin, routing.Wrap(s.handleGetUsage))
+}
+
+func (s *Service) handleGetUsage(c *contextmodel.ReqContext) response.Response {
+	usage, err := s.GetUsage(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to count the seats", err)
+	}
+	return response.JSON(http.StatusOK, usage)
+}
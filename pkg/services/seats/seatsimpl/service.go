@@ -0,0 +1,105 @@
+package seatsimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/seats"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var _ seats.Service = (*Service)(nil)
+
+type Service struct {
+	store          store
+	log            log.Logger
+	mode           seats.Mode
+	limits         map[seats.Tier]int64
+	viewersCanEdit bool
+}
+
+func ProvideService(cfg *setting.Cfg, sql db.DB, orgService org.Service, routeRegister routing.RouteRegister) *Service {
+	section := cfg.SectionWithEnvOverrides("seats")
+	s := &Service{
+		store: &sqlStore{db: sql},
+		log:   log.New("seats"),
+		mode:  seats.Mode(section.Key("mode").MustString(string(seats.ModeEnforce))),
+		limits: map[seats.Tier]int64{
+			seats.TierEditor: section.Key("editor_limit").MustInt64(0),
+			seats.TierAdmin:  section.Key("admin_limit").MustInt64(0),
+		},
+		viewersCanEdit: cfg.ViewersCanEdit,
+	}
+	if !s.mode.IsValid() {
+		s.log.Warn("Invalid seats mode, the seat limits are enforced", "mode", s.mode)
+		s.mode = seats.ModeEnforce
+	}
+
+	orgService.RegisterRoleAssignmentHook(s.CheckAssignment)
+	s.registerAPIEndpoints(routeRegister)
+	return s
+}
+
+func (s *Service) GetUsage(ctx context.Context) (*seats.Usage, error) {
+	tiers, err := s.store.UserTiers(ctx, s.viewersCanEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[seats.Tier]int64, len(seats.Tiers))
+	for _, tier := range tiers {
+		used[tier]++
+	}
+
+	usage := &seats.Usage{Mode: s.mode, Tiers: make([]seats.TierUsage, 0, len(seats.Tiers))}
+	for _, tier := range seats.Tiers {
+		limit := s.limits[tier]
+		usage.Tiers = append(usage.Tiers, seats.TierUsage{
+			Tier:     tier,
+			Used:     used[tier],
+			Limit:    limit,
+			Exceeded: limit > 0 && used[tier] > limit,
+		})
+	}
+	return usage, nil
+}
+
+func (s *Service) CheckAssignment(ctx context.Context, assignment org.RoleAssignment) error {
+	tier := seats.TierOf(assignment.Role, s.viewersCanEdit)
+	limit := s.limits[tier]
+	if limit <= 0 {
+		return nil
+	}
+
+	tiers, err := s.store.UserTiers(ctx, s.viewersCanEdit)
+	if err != nil {
+		return err
+	}
+	if current, ok := tiers[assignment.UserID]; ok && current.AtLeast(tier) {
+		// the user already holds a seat of the tier
+		return nil
+	}
+
+	var used int64
+	for _, t := range tiers {
+		if t == tier {
+			used++
+		}
+	}
+	if used < limit {
+		return nil
+	}
+
+	if s.mode == seats.ModeWarn {
+		s.log.Warn("Role assignment exceeds the seat limit", "tier", tier, "limit", limit, "used", used, "orgID", assignment.OrgID, "userID", assignment.UserID, "role", assignment.Role)
+		return nil
+	}
+	return seats.ErrSeatLimitReached.Build(errutil.TemplateData{
+		Public:  map[string]interface{}{"tier": tier, "limit": limit},
+		Private: map[string]interface{}{"userId": assignment.UserID},
+	})
+}
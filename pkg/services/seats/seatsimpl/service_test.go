@@ -0,0 +1,128 @@
+package seatsimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/seats"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+type fakeStore struct {
+	tiers map[int64]seats.Tier
+}
+
+func (f *fakeStore) UserTiers(context.Context, bool) (map[int64]seats.Tier, error) {
+	return f.tiers, nil
+}
+
+func TestCheckAssignment(t *testing.T) {
+	newService := func(mode seats.Mode) *Service {
+		return &Service{
+			store: &fakeStore{tiers: map[int64]seats.Tier{
+				1: seats.TierAdmin,
+				2: seats.TierEditor,
+				3: seats.TierEditor,
+				4: seats.TierViewer,
+			}},
+			log:    log.NewNopLogger(),
+			mode:   mode,
+			limits: map[seats.Tier]int64{seats.TierEditor: 2, seats.TierAdmin: 2},
+		}
+	}
+
+	t.Run("rejects the assignments exceeding the limit", func(t *testing.T) {
+		err := newService(seats.ModeEnforce).CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 1, UserID: 4, Role: org.RoleEditor})
+		require.ErrorIs(t, err, seats.ErrSeatLimitReached)
+		var gfErr errutil.Error
+		require.ErrorAs(t, err, &gfErr)
+		require.Equal(t, "All the 2 editor seats are used", gfErr.Public().Message)
+	})
+
+	t.Run("allows the assignments of users already holding a seat of the tier", func(t *testing.T) {
+		s := newService(seats.ModeEnforce)
+		require.NoError(t, s.CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 2, UserID: 2, Role: org.RoleEditor}))
+		require.NoError(t, s.CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 2, UserID: 1, Role: org.RoleEditor}))
+	})
+
+	t.Run("allows the assignments within the limit", func(t *testing.T) {
+		s := newService(seats.ModeEnforce)
+		require.NoError(t, s.CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 1, UserID: 4, Role: org.RoleAdmin}))
+		require.NoError(t, s.CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 1, UserID: 5, Role: org.RoleViewer}))
+	})
+
+	t.Run("allows the assignments exceeding the limit in warn mode", func(t *testing.T) {
+		s := newService(seats.ModeWarn)
+		require.NoError(t, s.CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 1, UserID: 4, Role: org.RoleEditor}))
+	})
+
+	t.Run("counts the viewers as editors when viewers can edit", func(t *testing.T) {
+		s := newService(seats.ModeEnforce)
+		s.viewersCanEdit = true
+		err := s.CheckAssignment(context.Background(), org.RoleAssignment{OrgID: 1, UserID: 5, Role: org.RoleViewer})
+		require.ErrorIs(t, err, seats.ErrSeatLimitReached)
+	})
+}
+
+func TestIntegrationGetUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	now := time.Now()
+	err := testDB.WithDbSession(context.Background(), func(sess *db.Session) error {
+		users := []*user.User{
+			{Login: "server-admin", IsAdmin: true},
+			{Login: "editor"},
+			{Login: "editor-and-admin"},
+			{Login: "viewer"},
+			{Login: "disabled-admin", IsDisabled: true},
+			{Login: "sa-admin", IsServiceAccount: true},
+		}
+		for _, u := range users {
+			u.Email = u.Login
+			u.Created, u.Updated, u.LastSeenAt = now, now, now
+			if _, err := sess.Insert(u); err != nil {
+				return err
+			}
+		}
+		for _, ou := range []*org.OrgUser{
+			{OrgID: 1, UserID: users[0].ID, Role: org.RoleViewer},
+			{OrgID: 1, UserID: users[1].ID, Role: org.RoleEditor},
+			{OrgID: 1, UserID: users[2].ID, Role: org.RoleEditor},
+			{OrgID: 2, UserID: users[2].ID, Role: org.RoleAdmin},
+			{OrgID: 1, UserID: users[3].ID, Role: org.RoleViewer},
+			{OrgID: 1, UserID: users[4].ID, Role: org.RoleAdmin},
+			{OrgID: 1, UserID: users[5].ID, Role: org.RoleAdmin},
+		} {
+			ou.Created, ou.Updated = now, now
+			if _, err := sess.Insert(ou); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	s := &Service{
+		store:  &sqlStore{db: testDB},
+		log:    log.NewNopLogger(),
+		mode:   seats.ModeEnforce,
+		limits: map[seats.Tier]int64{seats.TierAdmin: 1},
+	}
+	usage, err := s.GetUsage(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &seats.Usage{Mode: seats.ModeEnforce, Tiers: []seats.TierUsage{
+		{Tier: seats.TierViewer, Used: 1},
+		{Tier: seats.TierEditor, Used: 1},
+		{Tier: seats.TierAdmin, Used: 2, Limit: 1, Exceeded: true},
+	}}, usage)
+}
@@ -0,0 +1,55 @@
+package seatsimpl
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/seats"
+)
+
+type store interface {
+	// UserTiers returns the tier of the seat of each user, the disabled users and the service accounts excluded.
+	UserTiers(ctx context.Context, viewersCanEdit bool) (map[int64]seats.Tier, error)
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+type userRoleRow struct {
+	UserID  int64   `xorm:"user_id"`
+	IsAdmin bool    `xorm:"is_admin"`
+	Role    *string `xorm:"role"`
+}
+
+func (ss *sqlStore) UserTiers(ctx context.Context, viewersCanEdit bool) (map[int64]seats.Tier, error) {
+	var rows []userRoleRow
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := `SELECT u.id AS user_id, u.is_admin, ou.role
+			FROM ` + ss.db.GetDialect().Quote("user") + ` AS u
+			LEFT JOIN org_user AS ou ON ou.user_id = u.id
+			WHERE u.is_service_account = ? AND u.is_disabled = ?`
+		return sess.SQL(rawSQL, ss.db.GetDialect().BooleanStr(false), ss.db.GetDialect().BooleanStr(false)).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tiers := make(map[int64]seats.Tier)
+	for _, row := range rows {
+		tier, ok := tiers[row.UserID]
+		if !ok {
+			tier = seats.TierViewer
+		}
+		if row.IsAdmin {
+			// the server admins have the permissions of the org admins
+			tier = seats.TierAdmin
+		}
+		if row.Role != nil {
+			tier = tier.Max(seats.TierOf(org.RoleType(*row.Role), viewersCanEdit))
+		}
+		tiers[row.UserID] = tier
+	}
+	return tiers, nil
+}
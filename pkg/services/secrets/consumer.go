@@ -0,0 +1,22 @@
+package secrets
+
+import "context"
+
+// UnknownConsumer is the consumer of the contexts without consumer.
+const UnknownConsumer = "unknown"
+
+type consumerKey struct{}
+
+// WithConsumer returns a context identifying the service or resource that reads the secrets, recorded in the
+// usage report of the secrets.
+func WithConsumer(ctx context.Context, consumer string) context.Context {
+	return context.WithValue(ctx, consumerKey{}, consumer)
+}
+
+// ConsumerFromContext returns the consumer of the context, UnknownConsumer if it has none.
+func ConsumerFromContext(ctx context.Context) string {
+	if consumer, ok := ctx.Value(consumerKey{}).(string); ok && consumer != "" {
+		return consumer
+	}
+	return UnknownConsumer
+}
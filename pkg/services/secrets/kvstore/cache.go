@@ -16,6 +16,8 @@ type CachedKVStore struct {
 	log   log.Logger
 	cache *localcache.CacheService
 	store SecretsKVStore
+	// usage records the reads of the secrets, including the reads served from the cache
+	usage *UsageTracker
 }
 
 func WithCache(store SecretsKVStore, defaultExpiration time.Duration, cleanupInterval time.Duration) *CachedKVStore {
@@ -30,6 +32,7 @@ func (kv *CachedKVStore) Get(ctx context.Context, orgId int64, namespace string,
 	key := fmt.Sprint(orgId, namespace, typ)
 	if value, ok := kv.cache.Get(key); ok {
 		kv.log.Debug("got secret value from cache", "orgId", orgId, "type", typ, "namespace", namespace)
+		kv.usage.RecordRead(ctx, orgId, namespace, typ)
 		return fmt.Sprint(value), true, nil
 	}
 	value, ok, err := kv.store.Get(ctx, orgId, namespace, typ)
@@ -38,6 +41,7 @@ func (kv *CachedKVStore) Get(ctx context.Context, orgId int64, namespace string,
 	}
 	if ok {
 		kv.cache.SetDefault(key, value)
+		kv.usage.RecordRead(ctx, orgId, namespace, typ)
 	}
 	return value, ok, err
 }
@@ -52,14 +56,18 @@ func (kv *CachedKVStore) Set(ctx context.Context, orgId int64, namespace string,
 	return nil
 }
 
+// Del deletes the secret, unless it is referenced by other consumers than the consumer of the context.
 func (kv *CachedKVStore) Del(ctx context.Context, orgId int64, namespace string, typ string) error {
+	if err := kv.usage.checkDeletion(ctx, orgId, namespace, typ); err != nil {
+		return err
+	}
 	err := kv.store.Del(ctx, orgId, namespace, typ)
 	if err != nil {
 		return err
 	}
 	key := fmt.Sprint(orgId, namespace, typ)
 	kv.cache.Delete(key)
-	return nil
+	return kv.usage.deleted(ctx, orgId, namespace, typ)
 }
 
 func (kv *CachedKVStore) Keys(ctx context.Context, orgId int64, namespace string, typ string) ([]Key, error) {
@@ -77,13 +85,21 @@ func (kv *CachedKVStore) Rename(ctx context.Context, orgId int64, namespace stri
 		kv.cache.SetDefault(newKey, value)
 		kv.cache.Delete(key)
 	}
-	return nil
+	return kv.usage.renamed(ctx, orgId, namespace, typ, newNamespace)
 }
 
 func (kv *CachedKVStore) GetAll(ctx context.Context) ([]Item, error) {
 	return kv.store.GetAll(ctx)
 }
 
+// GetUsageTrackerFromCache returns the usage tracker of a cached store, nil when the store does not track the usage.
+func GetUsageTrackerFromCache(kv SecretsKVStore) *UsageTracker {
+	if cache, ok := kv.(*CachedKVStore); ok {
+		return cache.usage
+	}
+	return nil
+}
+
 func GetUnwrappedStoreFromCache(kv SecretsKVStore) (SecretsKVStore, error) {
 	if cache, ok := kv.(*CachedKVStore); ok {
 		return cache.store, nil
@@ -26,6 +26,7 @@ func ProvideService(
 	kvstore kvstore.KVStore,
 	features featuremgmt.FeatureToggles,
 	cfg *setting.Cfg,
+	usageTracker *UsageTracker,
 ) (SecretsKVStore, error) {
 	var logger = log.New("secrets.kvstore")
 	var store SecretsKVStore
@@ -61,7 +62,9 @@ func ProvideService(
 		logger.Debug("secrets kvstore is using the default (SQL) implementation for secrets management")
 	}
 
-	return WithCache(store, 5*time.Second, 5*time.Minute), nil
+	cached := WithCache(store, 5*time.Second, 5*time.Minute)
+	cached.usage = usageTracker
+	return cached, nil
 }
 
 // SecretsKVStore is an interface for k/v store.
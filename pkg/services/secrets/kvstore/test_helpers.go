@@ -150,6 +150,10 @@ func (f fakeFeatureToggles) IsEnabled(feature string) bool {
 	return f.returnValue
 }
 
+func (f fakeFeatureToggles) IsEnabledCtx(_ context.Context, feature string) bool {
+	return f.returnValue
+}
+
 // Fake grpc secrets plugin impl
 type fakeGRPCSecretsPlugin struct {
 	kv map[Key]string
@@ -275,7 +279,7 @@ func SetupFatalCrashTest(
 	}
 	features := NewFakeFeatureToggles(t, isBackwardsCompatDisabled)
 	manager := NewFakeSecretsPluginManager(t, shouldFailOnStart)
-	svc, err := ProvideService(sqlStore, secretService, manager, kvstore, features, cfg)
+	svc, err := ProvideService(sqlStore, secretService, manager, kvstore, features, cfg, ProvideUsageTracker(sqlStore))
 	t.Cleanup(ResetPlugin)
 	return fatalCrashTestFields{
 		SecretsKVStore: svc,
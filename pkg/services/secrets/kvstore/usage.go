@@ -0,0 +1,386 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// usageFlushInterval bounds how often the reads of a secret by a consumer are written to the database,
+// the reads in between are counted in memory and written by the next read or the next periodic flush.
+const usageFlushInterval = time.Minute
+
+var ErrSecretReferenced = errutil.NewBase(errutil.StatusValidationFailed, "secrets.referenced").MustTemplate(
+	"secret {{ .Public.Namespace }}/{{ .Public.Type }} is referenced by {{ .Public.Consumers }}",
+	errutil.WithPublic("The secret is still referenced by {{ .Public.Consumers }}"),
+)
+
+// SecretUsage records the reads of a secret by a consumer.
+type SecretUsage struct {
+	Id        int64     `xorm:"pk autoincr 'id'" json:"-"`
+	OrgId     int64     `xorm:"org_id" json:"-"`
+	Namespace string    `xorm:"namespace" json:"-"`
+	Type      string    `xorm:"type" json:"-"`
+	Consumer  string    `xorm:"consumer" json:"consumer"`
+	ReadCount int64     `xorm:"read_count" json:"readCount"`
+	FirstRead time.Time `xorm:"first_read" json:"firstRead"`
+	LastRead  time.Time `xorm:"last_read" json:"lastRead"`
+}
+
+func (u *SecretUsage) TableName() string {
+	return "secret_usage"
+}
+
+// SecretReference is a consumer depending on a secret, a referenced secret cannot be deleted by another consumer.
+type SecretReference struct {
+	Id        int64     `xorm:"pk autoincr 'id'" json:"-"`
+	OrgId     int64     `xorm:"org_id" json:"-"`
+	Namespace string    `xorm:"namespace" json:"-"`
+	Type      string    `xorm:"type" json:"-"`
+	Consumer  string    `xorm:"consumer" json:"consumer"`
+	Created   time.Time `xorm:"created" json:"created"`
+}
+
+func (r *SecretReference) TableName() string {
+	return "secret_reference"
+}
+
+// SecretReferenceCommand identifies a secret and a consumer referencing it.
+type SecretReferenceCommand struct {
+	OrgId     int64  `json:"orgId"`
+	Namespace string `json:"namespace"`
+	Type      string `json:"type"`
+	Consumer  string `json:"consumer"`
+}
+
+// SecretUsageQuery filters the usage report, the empty fields match any value.
+type SecretUsageQuery struct {
+	OrgId     int64
+	Namespace string
+	Type      string
+}
+
+// SecretUsageReport is the usage of a stored secret.
+type SecretUsageReport struct {
+	OrgId      int64             `json:"orgId"`
+	Namespace  string            `json:"namespace"`
+	Type       string            `json:"type"`
+	References []SecretReference `json:"references"`
+	Reads      []SecretUsage     `json:"reads"`
+}
+
+// UsageTracker records which consumers read the secrets and which consumers reference them. The reads are
+// counted in memory and written at most once per usageFlushInterval for a secret and a consumer, so that
+// reading a cached secret does not write to the database every time. As a background service, it writes the
+// reads still counted in memory every usageFlushInterval and when Grafana stops.
+type UsageTracker struct {
+	sqlStore db.DB
+	log      log.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[usageKey]*pendingReads
+}
+
+type usageKey struct {
+	orgId     int64
+	namespace string
+	typ       string
+	consumer  string
+}
+
+type pendingReads struct {
+	count     int64
+	first     time.Time
+	last      time.Time
+	flushedAt time.Time
+}
+
+func ProvideUsageTracker(sqlStore db.DB) *UsageTracker {
+	return &UsageTracker{
+		sqlStore: sqlStore,
+		log:      log.New("secrets.usage"),
+		now:      time.Now,
+		pending:  make(map[usageKey]*pendingReads),
+	}
+}
+
+// RecordRead counts a read of the secret by the consumer of the context. It never fails the read: the errors
+// are logged.
+func (t *UsageTracker) RecordRead(ctx context.Context, orgId int64, namespace string, typ string) {
+	if t == nil {
+		return
+	}
+
+	key := usageKey{orgId: orgId, namespace: namespace, typ: typ, consumer: secrets.ConsumerFromContext(ctx)}
+	now := t.now()
+
+	t.mu.Lock()
+	p, ok := t.pending[key]
+	if !ok {
+		p = &pendingReads{first: now}
+		t.pending[key] = p
+	}
+	p.count++
+	p.last = now
+	if ok && now.Sub(p.flushedAt) < usageFlushInterval {
+		t.mu.Unlock()
+		return
+	}
+	reads := *p
+	p.count = 0
+	p.first = time.Time{}
+	p.flushedAt = now
+	t.mu.Unlock()
+
+	if err := t.flush(ctx, key, reads); err != nil {
+		t.log.Warn("Failed to record the read of a secret", "orgId", orgId, "namespace", namespace, "type", typ, "consumer", key.consumer, "error", err)
+	}
+}
+
+// Run writes the reads counted in memory every usageFlushInterval, and a last time when the context is canceled.
+func (t *UsageTracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(usageFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// the context of the service is canceled on shutdown, the last reads are written with a new one
+			t.flushPending(context.Background())
+			return nil
+		case <-ticker.C:
+			t.flushPending(ctx)
+		}
+	}
+}
+
+// flushPending writes the reads counted in memory of every secret and consumer, and forgets the secrets and
+// consumers that were not read since the previous flush.
+func (t *UsageTracker) flushPending(ctx context.Context) {
+	now := t.now()
+
+	t.mu.Lock()
+	batch := make(map[usageKey]pendingReads)
+	for key, p := range t.pending {
+		if p.count == 0 {
+			delete(t.pending, key)
+			continue
+		}
+		batch[key] = *p
+		p.count = 0
+		p.first = time.Time{}
+		p.flushedAt = now
+	}
+	t.mu.Unlock()
+
+	for key, reads := range batch {
+		if err := t.flush(ctx, key, reads); err != nil {
+			t.log.Warn("Failed to record the reads of a secret", "orgId", key.orgId, "namespace", key.namespace, "type", key.typ, "consumer", key.consumer, "error", err)
+		}
+	}
+}
+
+func (t *UsageTracker) flush(ctx context.Context, key usageKey, reads pendingReads) error {
+	return t.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		existing := SecretUsage{}
+		has, err := sess.Where("org_id = ? AND namespace = ? AND type = ? AND consumer = ?", key.orgId, key.namespace, key.typ, key.consumer).Get(&existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			first := reads.first
+			if first.IsZero() {
+				first = reads.last
+			}
+			_, err = sess.Insert(&SecretUsage{
+				OrgId:     key.orgId,
+				Namespace: key.namespace,
+				Type:      key.typ,
+				Consumer:  key.consumer,
+				ReadCount: reads.count,
+				FirstRead: first,
+				LastRead:  reads.last,
+			})
+			return err
+		}
+		_, err = sess.Exec("UPDATE secret_usage SET read_count = read_count + ?, last_read = ? WHERE id = ?", reads.count, reads.last, existing.Id)
+		return err
+	})
+}
+
+// AddReference records that the consumer depends on the secret.
+func (t *UsageTracker) AddReference(ctx context.Context, orgId int64, namespace string, typ string, consumer string) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND namespace = ? AND type = ? AND consumer = ?", orgId, namespace, typ, consumer).Exist(&SecretReference{})
+		if err != nil || has {
+			return err
+		}
+		_, err = sess.Insert(&SecretReference{OrgId: orgId, Namespace: namespace, Type: typ, Consumer: consumer, Created: t.now()})
+		return err
+	})
+}
+
+// RemoveReference removes the reference of the consumer to the secret.
+func (t *UsageTracker) RemoveReference(ctx context.Context, orgId int64, namespace string, typ string, consumer string) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM secret_reference WHERE org_id = ? AND namespace = ? AND type = ? AND consumer = ?", orgId, namespace, typ, consumer)
+		return err
+	})
+}
+
+// Report returns the usage of the secrets read or referenced by a consumer, the secrets never read are not reported.
+func (t *UsageTracker) Report(ctx context.Context, query *SecretUsageQuery) ([]SecretUsageReport, error) {
+	var references []SecretReference
+	var reads []SecretUsage
+	err := t.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		filter := func(s *xorm.Session) *xorm.Session {
+			if query.OrgId != AllOrganizations {
+				s = s.Where("org_id = ?", query.OrgId)
+			}
+			if query.Namespace != "" {
+				s = s.Where("namespace = ?", query.Namespace)
+			}
+			if query.Type != "" {
+				s = s.Where("type = ?", query.Type)
+			}
+			return s
+		}
+		if err := filter(sess.Asc("consumer")).Find(&references); err != nil {
+			return err
+		}
+		return filter(sess.Desc("last_read")).Find(&reads)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make(map[Key]*SecretUsageReport)
+	report := func(orgId int64, namespace string, typ string) *SecretUsageReport {
+		k := Key{OrgId: orgId, Namespace: namespace, Type: typ}
+		r, ok := reports[k]
+		if !ok {
+			r = &SecretUsageReport{OrgId: orgId, Namespace: namespace, Type: typ, References: []SecretReference{}, Reads: []SecretUsage{}}
+			reports[k] = r
+		}
+		return r
+	}
+	for _, ref := range references {
+		r := report(ref.OrgId, ref.Namespace, ref.Type)
+		r.References = append(r.References, ref)
+	}
+	for _, read := range reads {
+		r := report(read.OrgId, read.Namespace, read.Type)
+		r.Reads = append(r.Reads, read)
+	}
+
+	result := make([]SecretUsageReport, 0, len(reports))
+	for _, r := range reports {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.OrgId != b.OrgId {
+			return a.OrgId < b.OrgId
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Type < b.Type
+	})
+	return result, nil
+}
+
+// checkDeletion returns an error if the secret is referenced by other consumers than the consumer of the context.
+func (t *UsageTracker) checkDeletion(ctx context.Context, orgId int64, namespace string, typ string) error {
+	if t == nil {
+		return nil
+	}
+
+	var references []SecretReference
+	err := t.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ? AND namespace = ? AND type = ? AND consumer <> ?", orgId, namespace, typ, secrets.ConsumerFromContext(ctx)).
+			Asc("consumer").Find(&references)
+	})
+	if err != nil {
+		return err
+	}
+	if len(references) == 0 {
+		return nil
+	}
+
+	consumers := make([]string, 0, len(references))
+	for _, ref := range references {
+		consumers = append(consumers, ref.Consumer)
+	}
+	return ErrSecretReferenced.Build(errutil.TemplateData{
+		Public: map[string]interface{}{"Namespace": namespace, "Type": typ, "Consumers": fmt.Sprint(consumers)},
+	})
+}
+
+// deleted removes the usage of a deleted secret.
+func (t *UsageTracker) deleted(ctx context.Context, orgId int64, namespace string, typ string) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	for k := range t.pending {
+		if k.orgId == orgId && k.namespace == namespace && k.typ == typ {
+			delete(t.pending, k)
+		}
+	}
+	t.mu.Unlock()
+
+	return t.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, table := range []string{"secret_usage", "secret_reference"} {
+			if _, err := sess.Exec("DELETE FROM "+table+" WHERE org_id = ? AND namespace = ? AND type = ?", orgId, namespace, typ); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// renamed moves the usage of a secret to its new namespace.
+func (t *UsageTracker) renamed(ctx context.Context, orgId int64, namespace string, typ string, newNamespace string) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	for k, p := range t.pending {
+		if k.orgId == orgId && k.namespace == namespace && k.typ == typ {
+			delete(t.pending, k)
+			k.namespace = newNamespace
+			t.pending[k] = p
+		}
+	}
+	t.mu.Unlock()
+
+	return t.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, table := range []string{"secret_usage", "secret_reference"} {
+			if _, err := sess.Exec("UPDATE "+table+" SET namespace = ? WHERE org_id = ? AND namespace = ? AND type = ?", newNamespace, orgId, namespace, typ); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
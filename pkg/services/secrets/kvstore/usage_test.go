@@ -0,0 +1,151 @@
+package kvstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+func TestIntegrationSecretsUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	tracker := ProvideUsageTracker(sqlStore)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	kv := WithCache(NewFakeSecretsKVStore(), time.Minute, time.Minute)
+	kv.usage = tracker
+
+	ctx := context.Background()
+	proxyCtx := secrets.WithConsumer(ctx, "datasource-proxy")
+	pluginsCtx := secrets.WithConsumer(ctx, "plugins")
+
+	require.NoError(t, kv.Set(ctx, 1, "ds", "datasource", "secret"))
+
+	t.Run("should record the reads per consumer, including the cached reads", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, _, err := kv.Get(proxyCtx, 1, "ds", "datasource")
+			require.NoError(t, err)
+		}
+		// the reads counted in memory are written after the flush interval
+		now = now.Add(usageFlushInterval)
+		_, _, err := kv.Get(proxyCtx, 1, "ds", "datasource")
+		require.NoError(t, err)
+		_, _, err = kv.Get(pluginsCtx, 1, "ds", "datasource")
+		require.NoError(t, err)
+
+		reports, err := tracker.Report(ctx, &SecretUsageQuery{OrgId: AllOrganizations, Namespace: "ds"})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Len(t, reports[0].Reads, 2)
+
+		reads := map[string]int64{}
+		for _, r := range reports[0].Reads {
+			reads[r.Consumer] = r.ReadCount
+		}
+		require.Equal(t, map[string]int64{"datasource-proxy": 4, "plugins": 1}, reads)
+	})
+
+	t.Run("should block the deletion of a secret referenced by another consumer", func(t *testing.T) {
+		require.NoError(t, tracker.AddReference(ctx, 1, "ds", "datasource", "alerting"))
+
+		err := kv.Del(proxyCtx, 1, "ds", "datasource")
+		require.ErrorIs(t, err, ErrSecretReferenced)
+
+		_, found, err := kv.Get(proxyCtx, 1, "ds", "datasource")
+		require.NoError(t, err)
+		require.True(t, found)
+
+		reports, err := tracker.Report(ctx, &SecretUsageQuery{OrgId: 1, Namespace: "ds", Type: "datasource"})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Len(t, reports[0].References, 1)
+		require.Equal(t, "alerting", reports[0].References[0].Consumer)
+	})
+
+	t.Run("should move the usage of a renamed secret", func(t *testing.T) {
+		require.NoError(t, kv.Rename(ctx, 1, "ds", "datasource", "ds-renamed"))
+
+		reports, err := tracker.Report(ctx, &SecretUsageQuery{OrgId: 1, Namespace: "ds"})
+		require.NoError(t, err)
+		require.Empty(t, reports)
+
+		reports, err = tracker.Report(ctx, &SecretUsageQuery{OrgId: 1, Namespace: "ds-renamed"})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Len(t, reports[0].References, 1)
+		require.Len(t, reports[0].Reads, 2)
+	})
+
+	t.Run("should allow the deletion by the referencing consumer and remove the usage", func(t *testing.T) {
+		require.NoError(t, kv.Del(secrets.WithConsumer(ctx, "alerting"), 1, "ds-renamed", "datasource"))
+
+		reports, err := tracker.Report(ctx, &SecretUsageQuery{OrgId: AllOrganizations})
+		require.NoError(t, err)
+		require.Empty(t, reports)
+	})
+
+	t.Run("should allow the deletion once the reference is removed", func(t *testing.T) {
+		require.NoError(t, kv.Set(ctx, 1, "ds", "other", "secret"))
+		require.NoError(t, tracker.AddReference(ctx, 1, "ds", "other", "alerting"))
+		require.ErrorIs(t, kv.Del(ctx, 1, "ds", "other"), ErrSecretReferenced)
+
+		require.NoError(t, tracker.RemoveReference(ctx, 1, "ds", "other", "alerting"))
+		require.NoError(t, kv.Del(ctx, 1, "ds", "other"))
+	})
+}
+
+func TestIntegrationSecretsUsageFlush(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	tracker := ProvideUsageTracker(db.InitTestDB(t))
+	ctx := context.Background()
+	proxyCtx := secrets.WithConsumer(ctx, "datasource-proxy")
+
+	readCount := func(t *testing.T) int64 {
+		t.Helper()
+		reports, err := tracker.Report(ctx, &SecretUsageQuery{OrgId: 1, Namespace: "ds"})
+		require.NoError(t, err)
+		require.Len(t, reports, 1)
+		require.Len(t, reports[0].Reads, 1)
+		return reports[0].Reads[0].ReadCount
+	}
+
+	// the first read is written, the next ones are counted in memory
+	for i := 0; i < 3; i++ {
+		tracker.RecordRead(proxyCtx, 1, "ds", "datasource")
+	}
+	require.Equal(t, int64(1), readCount(t))
+
+	t.Run("should write the reads counted in memory", func(t *testing.T) {
+		tracker.flushPending(ctx)
+		require.Equal(t, int64(3), readCount(t))
+	})
+
+	t.Run("should forget the secrets not read since the previous flush", func(t *testing.T) {
+		tracker.flushPending(ctx)
+		require.Empty(t, tracker.pending)
+		require.Equal(t, int64(3), readCount(t))
+	})
+
+	t.Run("should write the reads counted in memory when stopped", func(t *testing.T) {
+		tracker.RecordRead(proxyCtx, 1, "ds", "datasource")
+		tracker.RecordRead(proxyCtx, 1, "ds", "datasource")
+		require.Equal(t, int64(4), readCount(t))
+
+		runCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.NoError(t, tracker.Run(runCtx))
+		require.Equal(t, int64(5), readCount(t))
+	})
+}
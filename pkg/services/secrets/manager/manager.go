@@ -495,6 +495,14 @@ func (s *SecretsService) Run(ctx context.Context) error {
 			MustDuration(time.Minute),
 	)
 
+	// the data keys encrypted with a previous version of a rotated key encryption key are re-encrypted
+	var rotation <-chan time.Time
+	if interval := s.settings.KeyValue("security.encryption", "key_rotation_check_interval").MustDuration(time.Hour); interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		rotation = t.C
+	}
+
 	grp, gCtx := errgroup.WithContext(ctx)
 
 	for _, p := range s.providers {
@@ -511,6 +519,10 @@ func (s *SecretsService) Run(ctx context.Context) error {
 			s.log.Debug("Removing expired data keys from cache...")
 			s.dataKeyCache.removeExpired()
 			s.log.Debug("Removing expired data keys from cache finished successfully")
+		case <-rotation:
+			if err := s.reEncryptOutdatedDataKeys(gCtx); err != nil {
+				s.log.Error("Failed to re-encrypt the data keys after a key encryption key rotation", "error", err)
+			}
 		case <-gCtx.Done():
 			s.log.Debug("Grafana is shutting down; stopping...")
 			gc.Stop()
@@ -524,6 +536,37 @@ func (s *SecretsService) Run(ctx context.Context) error {
 	}
 }
 
+// reEncryptOutdatedDataKeys re-encrypts the data keys if one of them was encrypted with a previous
+// version of the key encryption key of the current provider, i.e. the key was rotated in the KMS.
+func (s *SecretsService) reEncryptOutdatedDataKeys(ctx context.Context) error {
+	provider, ok := s.providers[s.currentProviderID].(secrets.RotatableProvider)
+	if !ok {
+		return nil
+	}
+
+	keys, err := s.store.GetAllDataKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if kmsproviders.NormalizeProviderID(k.Provider) != s.currentProviderID {
+			continue
+		}
+		outdated, err := provider.Outdated(ctx, k.EncryptedData)
+		if err != nil {
+			s.log.Warn("Failed to check the version of the key encryption key of a data key", "id", k.Id, "provider", k.Provider, "error", err)
+			continue
+		}
+		if outdated {
+			s.log.Info("Key encryption key rotated, re-encrypting data keys", "provider", s.currentProviderID)
+			return s.ReEncryptDataKeys(ctx)
+		}
+	}
+
+	return nil
+}
+
 // Caching a data key is tricky, because at SecretsService level we cannot guarantee
 // that a newly created data key has actually been persisted, depending on the different
 // use cases that rely on SecretsService encryption and different database engines that
@@ -362,6 +362,79 @@ func TestSecretsService_ReEncryptDataKeys(t *testing.T) {
 	})
 }
 
+// rotatingProvider prefixes the encrypted blobs with the version of its key.
+type rotatingProvider struct {
+	version byte
+}
+
+func (p *rotatingProvider) Encrypt(_ context.Context, blob []byte) ([]byte, error) {
+	return append([]byte{p.version}, blob...), nil
+}
+
+func (p *rotatingProvider) Decrypt(_ context.Context, blob []byte) ([]byte, error) {
+	return blob[1:], nil
+}
+
+func (p *rotatingProvider) Outdated(_ context.Context, blob []byte) (bool, error) {
+	return blob[0] != p.version, nil
+}
+
+type rotatingKMS struct {
+	provider *rotatingProvider
+}
+
+func (k rotatingKMS) Provide() (map[secrets.ProviderID]secrets.Provider, error) {
+	return map[secrets.ProviderID]secrets.Provider{"rotating.v1": k.provider}, nil
+}
+
+func TestSecretsService_ReEncryptOutdatedDataKeys(t *testing.T) {
+	ctx := context.Background()
+
+	raw, err := ini.Load([]byte(`
+		[security]
+		encryption_provider = rotating.v1
+		`))
+	require.NoError(t, err)
+	settings := &setting.OSSImpl{Cfg: &setting.Cfg{Raw: raw}}
+
+	encryptionService, err := encryptionservice.ProvideEncryptionService(encryptionprovider.Provider{}, &usagestats.UsageStatsMock{}, settings)
+	require.NoError(t, err)
+
+	provider := &rotatingProvider{version: 1}
+	store := database.ProvideSecretsStore(db.InitTestDB(t))
+	svc, err := ProvideSecretsService(store, rotatingKMS{provider: provider}, encryptionService, settings, featuremgmt.WithFeatures(), &usagestats.UsageStatsMock{T: t})
+	require.NoError(t, err)
+
+	ciphertext, err := svc.Encrypt(ctx, []byte("grafana"), secrets.WithoutScope())
+	require.NoError(t, err)
+
+	t.Run("should not re-encrypt the data keys if the key encryption key was not rotated", func(t *testing.T) {
+		prevDataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, svc.reEncryptOutdatedDataKeys(ctx))
+
+		dataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, prevDataKeys[0].EncryptedData, dataKeys[0].EncryptedData)
+	})
+
+	t.Run("should re-encrypt the data keys with the new version of the key encryption key", func(t *testing.T) {
+		provider.version = 2
+
+		require.NoError(t, svc.reEncryptOutdatedDataKeys(ctx))
+
+		dataKeys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, dataKeys, 1)
+		assert.Equal(t, byte(2), dataKeys[0].EncryptedData[0])
+
+		decrypted, err := svc.Decrypt(ctx, ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("grafana"), decrypted)
+	})
+}
+
 func TestSecretsService_Decrypt(t *testing.T) {
 	ctx := context.Background()
 	testDB := db.InitTestDB(t)
@@ -67,6 +67,14 @@ type BackgroundProvider interface {
 	Run(ctx context.Context) error
 }
 
+// RotatableProvider should be implemented for a provider whose key encryption key can be rotated
+// in the key management service, so that the data keys encrypted with a previous version are re-encrypted.
+type RotatableProvider interface {
+	// Outdated returns true if the blob was encrypted with a version of the key encryption key
+	// that is no longer the current one.
+	Outdated(ctx context.Context, blob []byte) (bool, error)
+}
+
 // Migrator is responsible for secrets migrations like re-encrypting or rolling back secrets.
 type Migrator interface {
 	// ReEncryptSecrets decrypts and re-encrypts the secrets with most recent
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,8 +9,14 @@ import (
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/api/response"
 	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -28,6 +35,8 @@ type TokenDTO struct {
 	Created *time.Time `json:"created"`
 	// example: 2022-03-23T10:31:02Z
 	LastUsedAt *time.Time `json:"lastUsedAt"`
+	// example: 192.168.1.1
+	LastUsedIP *string `json:"lastUsedIp"`
 	// example: 2022-03-23T10:31:02Z
 	Expiration *time.Time `json:"expiration"`
 	// example: 0
@@ -36,6 +45,8 @@ type TokenDTO struct {
 	HasExpired bool `json:"hasExpired"`
 	// example: false
 	IsRevoked *bool `json:"isRevoked"`
+	// Permissions restricting the token, it has all the permissions of the service account when empty.
+	Permissions apikey.Permissions `json:"permissions,omitempty"`
 }
 
 func hasExpired(expiration *int64) bool {
@@ -102,7 +113,9 @@ func (api *ServiceAccountsAPI) ListTokens(ctx *contextmodel.ReqContext) response
 			SecondsUntilExpiration: &secondsUntilExpiration,
 			HasExpired:             isExpired,
 			LastUsedAt:             token.LastUsedAt,
+			LastUsedIP:             token.LastUsedIP,
 			IsRevoked:              token.IsRevoked,
+			Permissions:            token.Permissions,
 		}
 	}
 
@@ -131,7 +144,8 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 	}
 
 	// confirm service account exists
-	if _, err = api.service.RetrieveServiceAccount(c.Req.Context(), c.OrgID, saID); err != nil {
+	sa, err := api.service.RetrieveServiceAccount(c.Req.Context(), c.OrgID, saID)
+	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to retrieve service account", err)
 	}
 
@@ -140,14 +154,20 @@ func (api *ServiceAccountsAPI) CreateToken(c *contextmodel.ReqContext) response.
 		return response.Error(http.StatusBadRequest, "Bad request data", err)
 	}
 
+	if len(cmd.Permissions) > 0 {
+		if err := api.checkTokenPermissions(c.Req.Context(), sa, cmd.Permissions); err != nil {
+			return response.ErrOrFallback(http.StatusInternalServerError, "Failed to check service account token permissions", err)
+		}
+	}
+
 	// Force affected service account to be the one referenced in the URL
 	cmd.OrgId = c.OrgID
 
-	if api.cfg.ApiKeyMaxSecondsToLive != -1 {
+	if maxSecondsToLive := api.orgSettings.GetInt64(c.Req.Context(), c.OrgID, orgsettings.APIKeyMaxSecondsToLive); maxSecondsToLive != -1 {
 		if cmd.SecondsToLive == 0 {
 			return response.Error(http.StatusBadRequest, "Number of seconds before expiration should be set", nil)
 		}
-		if cmd.SecondsToLive > api.cfg.ApiKeyMaxSecondsToLive {
+		if cmd.SecondsToLive > maxSecondsToLive {
 			return response.Error(http.StatusBadRequest, "Number of seconds before expiration is greater than the global limit", nil)
 		}
 	}
@@ -220,6 +240,60 @@ func (api *ServiceAccountsAPI) DeleteToken(c *contextmodel.ReqContext) response.
 	return response.Success("Service account token deleted")
 }
 
+// swagger:route GET /serviceaccounts/{serviceAccountId}/tokens/{tokenId}/usage service_accounts getTokenUsage
+//
+// # Get the usage of a service account token
+//
+// Returns the number of calls by endpoint made with the token, the most used endpoints first.
+//
+// Required permissions (See note in the [introduction](https://grafana.com/docs/grafana/latest/developers/http_api/serviceaccount/#service-account-api) for an explanation):
+// action: `serviceaccounts:read` scope: `serviceaccounts:id:1` (single service account)
+//
+// Responses:
+// 200: getTokenUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *ServiceAccountsAPI) GetTokenUsage(c *contextmodel.ReqContext) response.Response {
+	saID, err := strconv.ParseInt(web.Params(c.Req)[":serviceAccountId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
+	}
+
+	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "Token ID is invalid", err)
+	}
+
+	// confirm the token belongs to the service account
+	tokens, err := api.service.ListTokens(c.Req.Context(), &serviceaccounts.GetSATokensQuery{
+		OrgID:            &c.OrgID,
+		ServiceAccountID: &saID,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to list service account tokens", err)
+	}
+	found := false
+	for _, token := range tokens {
+		if token.ID == tokenID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return response.Error(http.StatusNotFound, "Service account token not found", nil)
+	}
+
+	usage, err := api.apiKeyService.GetAPIKeyUsage(c.Req.Context(), &apikey.GetUsageQuery{OrgID: c.OrgID, APIKeyID: tokenID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get service account token usage", err)
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
+
 // swagger:parameters listTokens
 type ListTokensParams struct {
 	// in:path
@@ -242,6 +316,20 @@ type DeleteTokenParams struct {
 	ServiceAccountId int64 `json:"serviceAccountId"`
 }
 
+// swagger:parameters getTokenUsage
+type GetTokenUsageParams struct {
+	// in:path
+	TokenId int64 `json:"tokenId"`
+	// in:path
+	ServiceAccountId int64 `json:"serviceAccountId"`
+}
+
+// swagger:response getTokenUsageResponse
+type GetTokenUsageResponse struct {
+	// in:body
+	Body []*apikey.EndpointUsage
+}
+
 // swagger:response listTokensResponse
 type ListTokensResponse struct {
 	// in:body
@@ -253,3 +341,43 @@ type CreateTokenResponse struct {
 	// in:body
 	Body *dtos.NewApiKeyResult
 }
+
+// checkTokenPermissions verifies that the service account has the permissions of the token, they are enforced when
+// evaluating the permissions of the requests made with it, so changing the permissions of the service account later
+// restricts the token too.
+func (api *ServiceAccountsAPI) checkTokenPermissions(ctx context.Context, sa *serviceaccounts.ServiceAccountProfileDTO, permissions apikey.Permissions) error {
+	if api.accesscontrolService.IsDisabled() {
+		return serviceaccounts.ErrTokenPermissionNotGranted.Errorf("service account token permissions require role-based access control")
+	}
+
+	// the permissions of the service account include the permissions of its teams
+	teams, err := api.teamService.GetTeamIDsByUser(ctx, &team.GetTeamIDsByUserQuery{OrgID: sa.OrgId, UserID: sa.Id})
+	if err != nil {
+		return err
+	}
+	saPermissions, err := api.accesscontrolService.GetUserPermissions(ctx, &user.SignedInUser{
+		UserID:           sa.Id,
+		OrgID:            sa.OrgId,
+		OrgRole:          org.RoleType(sa.Role),
+		Teams:            teams,
+		IsServiceAccount: true,
+	}, accesscontrol.Options{})
+	if err != nil {
+		return err
+	}
+	granted := accesscontrol.GroupScopesByAction(saPermissions)
+
+	for _, p := range permissions {
+		if p.Action == "" {
+			return serviceaccounts.ErrTokenPermissionNotGranted.Errorf("service account token permission without action")
+		}
+		evaluator := accesscontrol.EvalPermission(p.Action)
+		if p.Scope != "" {
+			evaluator = accesscontrol.EvalPermission(p.Action, p.Scope)
+		}
+		if !evaluator.Evaluate(granted) {
+			return serviceaccounts.ErrTokenPermissionNotGranted.Errorf("service account %d doesn't have the permission %s on %q", sa.Id, p.Action, p.Scope)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,8 +12,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/apikey/apikeytest"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team/teamtest"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web/webtest"
 )
@@ -58,14 +62,17 @@ func TestServiceAccountsAPI_ListTokens(t *testing.T) {
 
 func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 	type TestCase struct {
-		desc           string
-		id             int64
-		body           string
-		permissions    []accesscontrol.Permission
-		tokenTTL       int64
-		expectedErr    error
-		expectedAPIKey *apikey.APIKey
-		expectedCode   int
+		desc          string
+		id            int64
+		body          string
+		permissions   []accesscontrol.Permission
+		saPermissions []accesscontrol.Permission
+		// teamPermissions are granted to the service account by its team
+		teamPermissions []accesscontrol.Permission
+		tokenTTL        int64
+		expectedErr     error
+		expectedAPIKey  *apikey.APIKey
+		expectedCode    int
 	}
 
 	tests := []TestCase{
@@ -102,6 +109,35 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			tokenTTL:     10 * int64(time.Hour),
 			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
 			expectedCode: http.StatusBadRequest,
+		}, {
+			desc:           "should be able to create token restricted to permissions of the service account",
+			id:             1,
+			body:           `{"name": "test", "permissions": [{"action": "dashboards:read", "scope": "dashboards:uid:ci"}, {"action": "annotations:create"}]}`,
+			tokenTTL:       -1,
+			permissions:    []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			saPermissions:  []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}, {Action: "annotations:create", Scope: "annotations:type:*"}},
+			expectedAPIKey: &apikey.APIKey{},
+			expectedCode:   http.StatusOK,
+		},
+		{
+			desc:          "should not be able to create token with permissions the service account doesn't have",
+			id:            1,
+			body:          `{"name": "test", "permissions": [{"action": "dashboards:write", "scope": "dashboards:uid:ci"}]}`,
+			tokenTTL:      -1,
+			permissions:   []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			saPermissions: []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}},
+			expectedCode:  http.StatusBadRequest,
+		},
+		{
+			desc:            "should be able to create token restricted to permissions of the teams of the service account",
+			id:              1,
+			body:            `{"name": "test", "permissions": [{"action": "dashboards:write", "scope": "dashboards:uid:ci"}]}`,
+			tokenTTL:        -1,
+			permissions:     []accesscontrol.Permission{{Action: serviceaccounts.ActionWrite, Scope: "serviceaccounts:id:1"}},
+			saPermissions:   []accesscontrol.Permission{{Action: "dashboards:read", Scope: "dashboards:*"}},
+			teamPermissions: []accesscontrol.Permission{{Action: "dashboards:write", Scope: "dashboards:uid:ci"}},
+			expectedAPIKey:  &apikey.APIKey{},
+			expectedCode:    http.StatusOK,
 		},
 	}
 
@@ -110,9 +146,15 @@ func TestServiceAccountsAPI_CreateToken(t *testing.T) {
 			server := setupTests(t, func(a *ServiceAccountsAPI) {
 				a.cfg.ApiKeyMaxSecondsToLive = tt.tokenTTL
 				a.service = &fakeServiceAccountService{
-					ExpectedErr:    tt.expectedErr,
-					ExpectedAPIKey: tt.expectedAPIKey,
+					ExpectedErr:                   tt.expectedErr,
+					ExpectedAPIKey:                tt.expectedAPIKey,
+					ExpectedServiceAccountProfile: &serviceaccounts.ServiceAccountProfileDTO{Id: tt.id, OrgId: 1, Role: "Viewer"},
+				}
+				a.accesscontrolService = fakeTeamsPermissionsService{
+					FakeService:     actest.FakeService{ExpectedPermissions: tt.saPermissions},
+					teamPermissions: map[int64][]accesscontrol.Permission{7: tt.teamPermissions},
 				}
+				a.teamService = &teamtest.FakeService{ExpectedTeamIDsByUser: []int64{7}}
 			})
 			req := server.NewRequest(http.MethodPost, fmt.Sprintf("/api/serviceaccounts/%d/tokens", tt.id), strings.NewReader(tt.body))
 			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
@@ -176,3 +218,68 @@ func TestServiceAccountsAPI_DeleteToken(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceAccountsAPI_GetTokenUsage(t *testing.T) {
+	type TestCase struct {
+		desc         string
+		saID         int64
+		apikeyID     int64
+		permissions  []accesscontrol.Permission
+		expectedCode int
+	}
+
+	tests := []TestCase{
+		{
+			desc:         "should be able to get service account token usage with correct permission",
+			saID:         1,
+			apikeyID:     1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionRead, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "should not be able to get service account token usage with wrong permission",
+			saID:         2,
+			apikeyID:     1,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionRead, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "should not be able to get the usage of a token of another service account",
+			saID:         1,
+			apikeyID:     2,
+			permissions:  []accesscontrol.Permission{{Action: serviceaccounts.ActionRead, Scope: "serviceaccounts:id:1"}},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			server := setupTests(t, func(a *ServiceAccountsAPI) {
+				a.service = &fakeServiceAccountService{ExpectedServiceAccountTokens: []apikey.APIKey{{ID: 1}}}
+				a.apiKeyService = &apikeytest.Service{ExpectedUsage: []*apikey.EndpointUsage{{Endpoint: "GET /api/search", Count: 1}}}
+			})
+
+			req := server.NewGetRequest(fmt.Sprintf("/api/serviceaccounts/%d/tokens/%d/usage", tt.saID, tt.apikeyID))
+			webtest.RequestWithSignedInUser(req, &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(tt.permissions)}})
+			res, err := server.Send(req)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedCode, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+// fakeTeamsPermissionsService grants the permissions of the teams to the users signed in with their teams.
+type fakeTeamsPermissionsService struct {
+	actest.FakeService
+	teamPermissions map[int64][]accesscontrol.Permission
+}
+
+func (f fakeTeamsPermissionsService) GetUserPermissions(ctx context.Context, u *user.SignedInUser, options accesscontrol.Options) ([]accesscontrol.Permission, error) {
+	permissions := append([]accesscontrol.Permission{}, f.ExpectedPermissions...)
+	for _, teamID := range u.Teams {
+		permissions = append(permissions, f.teamPermissions[teamID]...)
+	}
+	return permissions, f.ExpectedErr
+}
@@ -7,17 +7,20 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/distlock"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/usagestats"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/orgsettings"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/api"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/database"
 	"github.com/grafana/grafana/pkg/services/serviceaccounts/secretscan"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -35,6 +38,8 @@ type ServiceAccountsService struct {
 
 	secretScanEnabled  bool
 	secretScanInterval time.Duration
+
+	lockService *distlock.Service
 }
 
 func ProvideServiceAccountsService(
@@ -49,6 +54,9 @@ func ProvideServiceAccountsService(
 	orgService org.Service,
 	permissionService accesscontrol.ServiceAccountPermissionsService,
 	accesscontrolService accesscontrol.Service,
+	orgSettings orgsettings.Service,
+	lockService *distlock.Service,
+	teamService team.Service,
 ) (*ServiceAccountsService, error) {
 	serviceAccountsStore := database.ProvideServiceAccountsStore(
 		cfg,
@@ -63,6 +71,7 @@ func ProvideServiceAccountsService(
 		store:         serviceAccountsStore,
 		log:           log,
 		backgroundLog: log.New("serviceaccounts.background"),
+		lockService:   lockService,
 	}
 
 	if err := RegisterRoles(accesscontrolService); err != nil {
@@ -71,7 +80,7 @@ func ProvideServiceAccountsService(
 
 	usageStats.RegisterMetricsFunc(s.getUsageMetrics)
 
-	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService)
+	serviceaccountsAPI := api.NewServiceAccountsAPI(cfg, s, ac, accesscontrolService, routeRegister, permissionService, orgSettings, apiKeyService, teamService)
 	serviceaccountsAPI.RegisterAPIEndpoints()
 
 	s.secretScanEnabled = cfg.SectionWithEnvOverrides("secretscan").Key("enabled").MustBool(false)
@@ -114,9 +123,7 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 		tokenCheckTicker.Stop()
 	} else {
 		sa.backgroundLog.Debug("enabled token secret check and executing first check")
-		if err := sa.secretScanService.CheckTokens(ctx); err != nil {
-			sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
-		}
+		sa.checkTokens(ctx)
 
 		defer tokenCheckTicker.Stop()
 	}
@@ -139,11 +146,20 @@ func (sa *ServiceAccountsService) Run(ctx context.Context) error {
 			}
 		case <-tokenCheckTicker.C:
 			sa.backgroundLog.Debug("checking for leaked tokens")
+			sa.checkTokens(ctx)
+		}
+	}
+}
 
-			if err := sa.secretScanService.CheckTokens(ctx); err != nil {
-				sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
-			}
+// checkTokens checks for leaked tokens, once per interval across the instances.
+func (sa *ServiceAccountsService) checkTokens(ctx context.Context) {
+	err := sa.lockService.RunOnce(ctx, "service accounts secret scan", sa.secretScanInterval/2, func(ctx context.Context) {
+		if err := sa.secretScanService.CheckTokens(ctx); err != nil {
+			sa.backgroundLog.Warn("Failed to check for leaked tokens", "error", err.Error())
 		}
+	})
+	if err != nil {
+		sa.backgroundLog.Warn("Failed to lock the check for leaked tokens", "error", err.Error())
 	}
 }
 
@@ -109,7 +109,7 @@ func (f *SecretsCheckerFake) CheckTokens(ctx context.Context) error {
 
 func TestProvideServiceAccount_DeleteServiceAccount(t *testing.T) {
 	storeMock := newServiceAccountStoreFake()
-	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0}
+	svc := ServiceAccountsService{storeMock, log.New("test"), log.New("background.test"), &SecretsCheckerFake{}, false, 0, nil}
 	testOrgId := 1
 
 	t.Run("should create service account", func(t *testing.T) {
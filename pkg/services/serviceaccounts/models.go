@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/apikey"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util/errutil"
@@ -35,6 +36,7 @@ var (
 	ErrServiceAccountTokenNotFound       = errutil.NewBase(errutil.StatusNotFound, "serviceaccounts.ErrTokenNotFound", errutil.WithPublicMessage("service account token not found"))
 	ErrInvalidTokenExpiration            = errutil.NewBase(errutil.StatusValidationFailed, "serviceaccounts.ErrInvalidInput", errutil.WithPublicMessage("invalid SecondsToLive value"))
 	ErrDuplicateToken                    = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrTokenAlreadyExists", errutil.WithPublicMessage("service account token with given name already exists in the organization"))
+	ErrTokenPermissionNotGranted         = errutil.NewBase(errutil.StatusBadRequest, "serviceaccounts.ErrTokenPermissionNotGranted", errutil.WithPublicMessage("service account token permissions must be granted to the service account"))
 )
 
 type ServiceAccount struct {
@@ -90,6 +92,8 @@ type AddServiceAccountTokenCommand struct {
 	OrgId         int64  `json:"-"`
 	Key           string `json:"-"`
 	SecondsToLive int64  `json:"secondsToLive"`
+	// Permissions restrict the token to a subset of the permissions of the service account.
+	Permissions apikey.Permissions `json:"permissions,omitempty"`
 }
 
 type SearchOrgServiceAccountsQuery struct {
@@ -0,0 +1,222 @@
+// Package settingswatcher reads the configuration files again when the server receives SIGHUP or, when enabled,
+// when the configuration file is modified, and applies the changes of the reloadable sections without a restart.
+package settingswatcher
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	TriggerSignal = "signal"
+	TriggerFile   = "file"
+	TriggerAPI    = "api"
+)
+
+// ReloadResult describes the changes found when the configuration files were read again.
+type ReloadResult struct {
+	Time    time.Time `json:"time"`
+	Trigger string    `json:"trigger,omitempty"`
+	// Applied are the changed keys of the sections reloaded without a restart
+	Applied []setting.SettingChange `json:"applied"`
+	// RequiresRestart are the changed keys of the sections that are only read on startup
+	RequiresRestart []setting.SettingChange `json:"requiresRestart"`
+	// Errors are the reasons the changes of some reloadable sections were not applied, by section
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+type Service struct {
+	cfg      *setting.Cfg
+	provider setting.Provider
+	log      log.Logger
+
+	// mu serializes the reloads
+	mu sync.Mutex
+	// loaded are the configuration files as they were last applied, the running configuration
+	// cannot be compared with the files since reading the settings adds the missing keys to it
+	loaded  *ini.File
+	last    *ReloadResult
+	modTime time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, provider setting.Provider) *Service {
+	s := &Service{
+		cfg:      cfg,
+		provider: provider,
+		log:      log.New("settings.watcher"),
+	}
+	s.modTime = s.configModTime()
+
+	loaded, err := cfg.ReadConfigFiles()
+	if err != nil {
+		s.log.Warn("Failed to read the configuration files, comparing the changes with the running configuration", "error", err)
+		loaded = cfg.Raw
+	}
+	s.loaded = loaded
+	return s
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.SettingsReload.Enabled && !s.cfg.SettingsReload.WatchFile
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	sighupChan := make(chan os.Signal, 1)
+	if s.cfg.SettingsReload.Enabled {
+		signal.Notify(sighupChan, syscall.SIGHUP)
+		defer signal.Stop(sighupChan)
+	}
+
+	// a nil channel blocks forever, so the file is only polled when watching it is enabled
+	var poll <-chan time.Time
+	if s.cfg.SettingsReload.WatchFile {
+		ticker := time.NewTicker(s.cfg.SettingsReload.PollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sighupChan:
+			s.reloadAndLog(TriggerSignal)
+		case <-poll:
+			if modTime := s.configModTime(); !modTime.Equal(s.modTime) {
+				s.reloadAndLog(TriggerFile)
+			}
+		}
+	}
+}
+
+func (s *Service) reloadAndLog(trigger string) {
+	result, err := s.Reload(trigger)
+	if err != nil {
+		s.log.Error("Failed to reload the configuration", "trigger", trigger, "error", err)
+		return
+	}
+	if len(result.RequiresRestart) > 0 {
+		s.log.Warn("Some configuration changes require a restart", "changes", len(result.RequiresRestart))
+	}
+}
+
+// Pending returns the changes of the configuration files that are not applied yet, without applying them.
+// The changes that a reload would apply are returned in Applied.
+func (s *Service) Pending() (*ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated, err := s.cfg.ReadConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	result := newResult("")
+	for _, change := range setting.DiffConfig(s.loaded, updated) {
+		if s.isReloadable(change) {
+			result.Applied = append(result.Applied, change)
+		} else {
+			result.RequiresRestart = append(result.RequiresRestart, change)
+		}
+	}
+	return result, nil
+}
+
+// Reload reads the configuration files again and applies the changes of the reloadable sections.
+func (s *Service) Reload(trigger string) (*ReloadResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the modification time is read first, so that a change made while reloading triggers another reload
+	s.modTime = s.configModTime()
+	updated, err := s.cfg.ReadConfigFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	result := newResult(trigger)
+	bySection := make(map[string][]setting.SettingChange)
+	var sections []string
+	for _, change := range setting.DiffConfig(s.loaded, updated) {
+		if !s.isReloadable(change) {
+			result.RequiresRestart = append(result.RequiresRestart, change)
+			continue
+		}
+		if _, ok := bySection[change.Section]; !ok {
+			sections = append(sections, change.Section)
+		}
+		bySection[change.Section] = append(bySection[change.Section], change)
+	}
+
+	for _, section := range sections {
+		if err := s.cfg.ApplySection(updated, section, s.handlers(section)); err != nil {
+			s.log.Error("Failed to apply the configuration changes", "section", section, "error", err)
+			result.Errors[section] = err.Error()
+			continue
+		}
+		// only the applied keys are copied, the keys requiring a restart are reported again by the next reload
+		for _, change := range bySection[section] {
+			if err := setting.CopyKey(s.loaded, updated, change); err != nil {
+				return nil, err
+			}
+		}
+		s.log.Info("Applied the configuration changes", "section", section, "keys", len(bySection[section]))
+		result.Applied = append(result.Applied, bySection[section]...)
+	}
+
+	s.last = result
+	return result, nil
+}
+
+// LastReload returns the result of the last reload, nil if the configuration was not reloaded since startup.
+func (s *Service) LastReload() *ReloadResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+func (s *Service) isReloadable(change setting.SettingChange) bool {
+	if setting.IsStartupOnlyKey(change.Section, change.Key) {
+		return false
+	}
+	return setting.IsReloadableSection(change.Section) || len(s.handlers(change.Section)) > 0
+}
+
+func (s *Service) handlers(section string) []setting.ReloadHandler {
+	registry, ok := s.provider.(setting.ReloadHandlerRegistry)
+	if !ok {
+		return nil
+	}
+	return registry.ReloadHandlers(section)
+}
+
+func (s *Service) configModTime() time.Time {
+	path := s.cfg.ConfigFilePath()
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func newResult(trigger string) *ReloadResult {
+	return &ReloadResult{
+		Time:            time.Now(),
+		Trigger:         trigger,
+		Applied:         make([]setting.SettingChange, 0),
+		RequiresRestart: make([]setting.SettingChange, 0),
+		Errors:          make(map[string]string),
+	}
+}
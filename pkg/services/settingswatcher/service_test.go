@@ -0,0 +1,53 @@
+package settingswatcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestService_Reload(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "custom.ini")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(configFile, []byte(content), 0600))
+	}
+	writeConfig("[rate_limiting]\nenabled = false\n[server]\nhttp_port = 3000\n")
+
+	cfg := setting.NewCfg()
+	require.NoError(t, cfg.Load(setting.CommandLineArgs{HomePath: "../../../", Config: configFile}))
+	s := ProvideService(cfg, setting.ProvideProvider(cfg))
+
+	pending, err := s.Pending()
+	require.NoError(t, err)
+	require.Empty(t, pending.Applied)
+	require.Empty(t, pending.RequiresRestart)
+	require.Nil(t, s.LastReload())
+
+	writeConfig("[rate_limiting]\nenabled = true\nbackend = redis\n[server]\nhttp_port = 4000\n")
+
+	pending, err = s.Pending()
+	require.NoError(t, err)
+	require.Equal(t, []setting.SettingChange{{Section: "rate_limiting", Key: "enabled"}}, pending.Applied)
+	require.False(t, cfg.CurrentRateLimiting().Enabled)
+
+	result, err := s.Reload(TriggerAPI)
+	require.NoError(t, err)
+	require.Equal(t, TriggerAPI, result.Trigger)
+	require.Equal(t, []setting.SettingChange{{Section: "rate_limiting", Key: "enabled"}}, result.Applied)
+	require.Equal(t, []setting.SettingChange{{Section: "rate_limiting", Key: "backend"}, {Section: "server", Key: "http_port"}}, result.RequiresRestart)
+	require.Empty(t, result.Errors)
+	require.Equal(t, result, s.LastReload())
+
+	require.True(t, cfg.CurrentRateLimiting().Enabled)
+	require.Equal(t, "memory", cfg.CurrentRateLimiting().Backend)
+	require.Equal(t, "3000", cfg.HTTPPort)
+
+	result, err = s.Reload(TriggerAPI)
+	require.NoError(t, err)
+	require.Empty(t, result.Applied)
+	require.Equal(t, []setting.SettingChange{{Section: "rate_limiting", Key: "backend"}, {Section: "server", Key: "http_port"}}, result.RequiresRestart)
+}
@@ -11,6 +11,9 @@ var (
 	ErrShortURLNotFound     = errutil.NewBase(errutil.StatusNotFound, "shorturl.not-found")
 	ErrShortURLAbsolutePath = errutil.NewBase(errutil.StatusValidationFailed, "shorturl.absolute-path", errutil.WithPublicMessage("Path should be relative"))
 	ErrShortURLInvalidPath  = errutil.NewBase(errutil.StatusValidationFailed, "shorturl.invalid-path", errutil.WithPublicMessage("Invalid short URL path"))
+	ErrShortURLInvalidSlug  = errutil.NewBase(errutil.StatusValidationFailed, "shorturl.invalid-slug", errutil.WithPublicMessage("Slug should be at most 40 letters, digits, '-' or '_'"))
+	ErrShortURLSlugTaken    = errutil.NewBase(errutil.StatusBadRequest, "shorturl.slug-taken", errutil.WithPublicMessage("Slug is already used by another short URL"))
+	ErrShortURLInvalidTTL   = errutil.NewBase(errutil.StatusValidationFailed, "shorturl.invalid-ttl", errutil.WithPublicMessage("Expiry should not be negative"))
 	ErrShortURLInternal     = errutil.NewBase(errutil.StatusInternal, "shorturl.internal")
 )
 
@@ -22,6 +25,20 @@ type ShortUrl struct {
 	CreatedBy  int64
 	CreatedAt  int64
 	LastSeenAt int64
+	// IsCustom is set when the uid was chosen by the user, such short URLs are never deleted for being unused
+	IsCustom bool
+	// ExpiresAt is when the short URL is deleted, it never expires when 0
+	ExpiresAt int64
+	// Hits is the number of times the short URL was followed
+	Hits int64
+}
+
+type CreateShortURLCommand struct {
+	Path string
+	// Slug is the uid chosen by the user, a random uid is generated when empty
+	Slug string
+	// TTL is how long the short URL lives, it never expires when 0
+	TTL time.Duration
 }
 
 type DeleteShortUrlCommand struct {
@@ -29,3 +46,37 @@ type DeleteShortUrlCommand struct {
 
 	NumDeleted int64
 }
+
+type DeleteExpiredShortURLsCommand struct {
+	NumDeleted int64
+}
+
+type SearchShortURLsQuery struct {
+	// OrgID filters the short URLs by organization, all the organizations when 0
+	OrgID int64
+	// Query filters the short URLs by uid or path
+	Query string
+	Page  int
+	Limit int
+}
+
+type ShortURLSearchHit struct {
+	ID             int64  `json:"id" xorm:"id"`
+	OrgID          int64  `json:"orgId" xorm:"org_id"`
+	UID            string `json:"uid" xorm:"uid"`
+	Path           string `json:"path"`
+	IsCustom       bool   `json:"isCustom"`
+	CreatedBy      int64  `json:"createdBy"`
+	CreatedByLogin string `json:"createdByLogin"`
+	CreatedAt      int64  `json:"createdAt"`
+	LastSeenAt     int64  `json:"lastSeenAt"`
+	ExpiresAt      int64  `json:"expiresAt"`
+	Hits           int64  `json:"hits"`
+}
+
+type SearchShortURLsResult struct {
+	TotalCount int64                `json:"totalCount"`
+	ShortURLs  []*ShortURLSearchHit `json:"shortUrls"`
+	Page       int                  `json:"page"`
+	PerPage    int                  `json:"perPage"`
+}
@@ -8,7 +8,10 @@ import (
 
 type Service interface {
 	GetShortURLByUID(ctx context.Context, user *user.SignedInUser, uid string) (*ShortUrl, error)
-	CreateShortURL(ctx context.Context, user *user.SignedInUser, path string) (*ShortUrl, error)
+	CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *CreateShortURLCommand) (*ShortUrl, error)
+	// UpdateLastSeenAt records that the short URL was followed.
 	UpdateLastSeenAt(ctx context.Context, shortURL *ShortUrl) error
 	DeleteStaleShortURLs(ctx context.Context, cmd *DeleteShortUrlCommand) error
+	DeleteExpiredShortURLs(ctx context.Context, cmd *DeleteExpiredShortURLsCommand) error
+	SearchShortURLs(ctx context.Context, query *SearchShortURLsQuery) (*SearchShortURLsResult, error)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
 	"github.com/teris-io/shortid"
 )
 
@@ -34,8 +35,8 @@ func (s ShortURLService) UpdateLastSeenAt(ctx context.Context, shortURL *shortur
 	return s.SQLStore.Update(ctx, shortURL)
 }
 
-func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, relPath string) (*shorturls.ShortUrl, error) {
-	relPath = strings.TrimSpace(relPath)
+func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedInUser, cmd *shorturls.CreateShortURLCommand) (*shorturls.ShortUrl, error) {
+	relPath := strings.TrimSpace(cmd.Path)
 
 	if path.IsAbs(relPath) {
 		return nil, shorturls.ErrShortURLAbsolutePath.Errorf("expected relative path: %s", relPath)
@@ -43,19 +44,40 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedIn
 	if strings.Contains(relPath, "../") {
 		return nil, shorturls.ErrShortURLInvalidPath.Errorf("path cannot contain '../': %s", relPath)
 	}
+	if cmd.TTL < 0 {
+		return nil, shorturls.ErrShortURLInvalidTTL.Errorf("negative ttl: %s", cmd.TTL)
+	}
 
-	uid, err := shortid.Generate()
-	if err != nil {
-		return nil, shorturls.ErrShortURLInternal.Errorf("failed to generate uid: %w", err)
+	uid := strings.TrimSpace(cmd.Slug)
+	if uid != "" {
+		if !util.IsValidShortUID(uid) || util.IsShortUIDTooLong(uid) {
+			return nil, shorturls.ErrShortURLInvalidSlug.Errorf("invalid slug: %s", uid)
+		}
+		exists, err := s.SQLStore.Exists(ctx, user.OrgID, uid)
+		if err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to look up slug: %w", err)
+		}
+		if exists {
+			return nil, shorturls.ErrShortURLSlugTaken.Errorf("slug already used: %s", uid)
+		}
+	} else {
+		var err error
+		if uid, err = shortid.Generate(); err != nil {
+			return nil, shorturls.ErrShortURLInternal.Errorf("failed to generate uid: %w", err)
+		}
 	}
 
-	now := time.Now().Unix()
+	now := getTime()
 	shortURL := shorturls.ShortUrl{
 		OrgId:     user.OrgID,
 		Uid:       uid,
 		Path:      relPath,
 		CreatedBy: user.UserID,
-		CreatedAt: now,
+		CreatedAt: now.Unix(),
+		IsCustom:  cmd.Slug != "",
+	}
+	if cmd.TTL > 0 {
+		shortURL.ExpiresAt = now.Add(cmd.TTL).Unix()
 	}
 
 	if err := s.SQLStore.Insert(ctx, &shortURL); err != nil {
@@ -68,3 +90,17 @@ func (s ShortURLService) CreateShortURL(ctx context.Context, user *user.SignedIn
 func (s ShortURLService) DeleteStaleShortURLs(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.SQLStore.Delete(ctx, cmd)
 }
+
+func (s ShortURLService) DeleteExpiredShortURLs(ctx context.Context, cmd *shorturls.DeleteExpiredShortURLsCommand) error {
+	return s.SQLStore.DeleteExpired(ctx, cmd)
+}
+
+func (s ShortURLService) SearchShortURLs(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	if query.Limit <= 0 {
+		query.Limit = 1000
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	return s.SQLStore.Search(ctx, query)
+}
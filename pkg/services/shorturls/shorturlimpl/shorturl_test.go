@@ -21,7 +21,7 @@ func TestShortURLService(t *testing.T) {
 
 		service := ShortURLService{SQLStore: &sqlStore{db: store}}
 
-		newShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+		newShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: refPath})
 		require.NoError(t, err)
 		require.NotNil(t, newShortURL)
 		require.NotEmpty(t, newShortURL.Uid)
@@ -48,10 +48,11 @@ func TestShortURLService(t *testing.T) {
 			updatedShortURL, err := service.GetShortURLByUID(context.Background(), user, existingShortURL.Uid)
 			require.NoError(t, err)
 			require.Equal(t, expectedTime.Unix(), updatedShortURL.LastSeenAt)
+			require.Equal(t, int64(1), updatedShortURL.Hits)
 		})
 
 		t.Run("and stale short urls can be deleted", func(t *testing.T) {
-			staleShortURL, err := service.CreateShortURL(context.Background(), user, refPath)
+			staleShortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: refPath})
 			require.NoError(t, err)
 			require.NotNil(t, staleShortURL)
 			require.NotEmpty(t, staleShortURL.Uid)
@@ -76,6 +77,76 @@ func TestShortURLService(t *testing.T) {
 		})
 	})
 
+	t.Run("User can create short URLs with a custom slug", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+		cmd := &shorturls.CreateShortURLCommand{Path: "d/runbook", Slug: "runbook-db"}
+
+		shortURL, err := service.CreateShortURL(context.Background(), user, cmd)
+		require.NoError(t, err)
+		require.Equal(t, "runbook-db", shortURL.Uid)
+		require.True(t, shortURL.IsCustom)
+
+		_, err = service.CreateShortURL(context.Background(), user, cmd)
+		require.ErrorIs(t, err, shorturls.ErrShortURLSlugTaken)
+
+		_, err = service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/runbook", Slug: "not/valid"})
+		require.ErrorIs(t, err, shorturls.ErrShortURLInvalidSlug)
+
+		t.Run("and custom short URLs are never stale", func(t *testing.T) {
+			cmd := shorturls.DeleteShortUrlCommand{OlderThan: time.Unix(shortURL.CreatedAt, 0)}
+			require.NoError(t, service.DeleteStaleShortURLs(context.Background(), &cmd))
+
+			_, err := service.GetShortURLByUID(context.Background(), user, shortURL.Uid)
+			require.NoError(t, err)
+		})
+	})
+
+	t.Run("Short URLs expire", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+		origGetTime := getTime
+		t.Cleanup(func() {
+			getTime = origGetTime
+		})
+		now := time.Now()
+		getTime = func() time.Time { return now }
+
+		shortURL, err := service.CreateShortURL(context.Background(), user, &shorturls.CreateShortURLCommand{Path: "d/expiring", TTL: time.Hour})
+		require.NoError(t, err)
+		require.Equal(t, now.Add(time.Hour).Unix(), shortURL.ExpiresAt)
+
+		_, err = service.GetShortURLByUID(context.Background(), user, shortURL.Uid)
+		require.NoError(t, err)
+
+		now = now.Add(time.Hour)
+		_, err = service.GetShortURLByUID(context.Background(), user, shortURL.Uid)
+		require.ErrorIs(t, err, shorturls.ErrShortURLNotFound)
+
+		cmd := shorturls.DeleteExpiredShortURLsCommand{}
+		require.NoError(t, service.DeleteExpiredShortURLs(context.Background(), &cmd))
+		require.Equal(t, int64(1), cmd.NumDeleted)
+	})
+
+	t.Run("Admins can search short URLs", func(t *testing.T) {
+		service := ShortURLService{SQLStore: &sqlStore{db: store}}
+
+		result, err := service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{Query: "runbook"})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.TotalCount)
+		require.Len(t, result.ShortURLs, 1)
+		require.Equal(t, "runbook-db", result.ShortURLs[0].UID)
+		require.True(t, result.ShortURLs[0].IsCustom)
+
+		result, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Equal(t, int64(0), result.TotalCount)
+		require.Empty(t, result.ShortURLs)
+
+		result, err = service.SearchShortURLs(context.Background(), &shorturls.SearchShortURLsQuery{Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, result.ShortURLs, 1)
+		require.Greater(t, result.TotalCount, int64(1))
+	})
+
 	t.Run("User cannot look up nonexistent short URLs", func(t *testing.T) {
 		service := ShortURLService{SQLStore: &sqlStore{db: store}}
 
@@ -2,6 +2,7 @@ package shorturlimpl
 
 import (
 	"context"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/shorturls"
@@ -10,9 +11,12 @@ import (
 
 type store interface {
 	Get(ctx context.Context, user *user.SignedInUser, uid string) (*shorturls.ShortUrl, error)
+	Exists(ctx context.Context, orgID int64, uid string) (bool, error)
 	Update(ctx context.Context, shortURL *shorturls.ShortUrl) error
 	Insert(ctx context.Context, shortURL *shorturls.ShortUrl) error
 	Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error
+	DeleteExpired(ctx context.Context, cmd *shorturls.DeleteExpiredShortURLsCommand) error
+	Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error)
 }
 
 type sqlStore struct {
@@ -26,7 +30,7 @@ func (s sqlStore) Get(ctx context.Context, user *user.SignedInUser, uid string)
 		if err != nil {
 			return err
 		}
-		if !exists {
+		if !exists || (shortURL.ExpiresAt > 0 && shortURL.ExpiresAt <= getTime().Unix()) {
 			return shorturls.ErrShortURLNotFound.Errorf("short URL not found")
 		}
 
@@ -39,13 +43,25 @@ func (s sqlStore) Get(ctx context.Context, user *user.SignedInUser, uid string)
 	return &shortURL, nil
 }
 
+func (s sqlStore) Exists(ctx context.Context, orgID int64, uid string) (bool, error) {
+	var exists bool
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		var err error
+		exists, err = dbSession.Table("short_url").Where("org_id=? AND uid=?", orgID, uid).Exist()
+		return err
+	})
+	return exists, err
+}
+
 func (s sqlStore) Update(ctx context.Context, shortURL *shorturls.ShortUrl) error {
 	shortURL.LastSeenAt = getTime().Unix()
 	return s.db.WithTransactionalDbSession(ctx, func(dbSession *db.Session) error {
-		_, err := dbSession.ID(shortURL.Id).Update(shortURL)
+		// the hits are counted by the database, so that concurrent hits are not lost
+		_, err := dbSession.Exec("UPDATE short_url SET last_seen_at = ?, hits = hits + 1 WHERE id = ?", shortURL.LastSeenAt, shortURL.Id)
 		if err != nil {
 			return err
 		}
+		shortURL.Hits++
 		return nil
 	})
 }
@@ -59,9 +75,22 @@ func (s sqlStore) Insert(ctx context.Context, shortURL *shorturls.ShortUrl) erro
 
 func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlCommand) error {
 	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
-		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0)"
+		var rawSql = "DELETE FROM short_url WHERE created_at <= ? AND (last_seen_at IS NULL OR last_seen_at = 0) AND is_custom = ?"
+
+		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix(), false); err != nil {
+			return err
+		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+func (s sqlStore) DeleteExpired(ctx context.Context, cmd *shorturls.DeleteExpiredShortURLsCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(session *db.Session) error {
+		var rawSql = "DELETE FROM short_url WHERE expires_at > 0 AND expires_at <= ?"
 
-		if result, err := session.Exec(rawSql, cmd.OlderThan.Unix()); err != nil {
+		if result, err := session.Exec(rawSql, getTime().Unix()); err != nil {
 			return err
 		} else if cmd.NumDeleted, err = result.RowsAffected(); err != nil {
 			return err
@@ -69,3 +98,47 @@ func (s sqlStore) Delete(ctx context.Context, cmd *shorturls.DeleteShortUrlComma
 		return nil
 	})
 }
+
+func (s sqlStore) Search(ctx context.Context, query *shorturls.SearchShortURLsQuery) (*shorturls.SearchShortURLsResult, error) {
+	result := &shorturls.SearchShortURLsResult{
+		ShortURLs: make([]*shorturls.ShortURLSearchHit, 0),
+		Page:      query.Page,
+		PerPage:   query.Limit,
+	}
+	err := s.db.WithDbSession(ctx, func(dbSession *db.Session) error {
+		var (
+			filters []string
+			params  []interface{}
+		)
+		if query.OrgID > 0 {
+			filters = append(filters, "short_url.org_id = ?")
+			params = append(params, query.OrgID)
+		}
+		if query.Query != "" {
+			like := "%" + query.Query + "%"
+			likeStr := s.db.GetDialect().LikeStr()
+			filters = append(filters, "(short_url.uid "+likeStr+" ? OR short_url.path "+likeStr+" ?)")
+			params = append(params, like, like)
+		}
+		where := ""
+		if len(filters) > 0 {
+			where = " WHERE " + strings.Join(filters, " AND ")
+		}
+
+		if _, err := dbSession.SQL("SELECT COUNT(*) FROM short_url"+where, params...).Get(&result.TotalCount); err != nil {
+			return err
+		}
+
+		userTable := s.db.GetDialect().Quote("user")
+		sql := `SELECT short_url.id, short_url.org_id, short_url.uid, short_url.path, short_url.is_custom,
+			short_url.created_by, ` + userTable + `.login AS created_by_login, short_url.created_at,
+			short_url.last_seen_at, short_url.expires_at, short_url.hits
+			FROM short_url LEFT OUTER JOIN ` + userTable + ` ON ` + userTable + `.id = short_url.created_by` +
+			where + " ORDER BY short_url.created_at DESC " + s.db.GetDialect().LimitOffset(int64(query.Limit), int64((query.Page-1)*query.Limit))
+		return dbSession.SQL(sql, params...).Find(&result.ShortURLs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
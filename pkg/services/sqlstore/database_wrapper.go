@@ -17,6 +17,7 @@ import (
 	"xorm.io/core"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/metrics/metricutil"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
@@ -83,17 +84,7 @@ func (h *databaseQueryWrapper) instrument(ctx context.Context, status string, qu
 	begin := ctx.Value(databaseQueryWrapperKey{}).(time.Time)
 	elapsed := time.Since(begin)
 
-	histogram := databaseQueryHistogram.WithLabelValues(status)
-	if traceID := tracing.TraceIDFromContext(ctx, true); traceID != "" {
-		// Need to type-convert the Observer to an
-		// ExemplarObserver. This will always work for a
-		// HistogramVec.
-		histogram.(prometheus.ExemplarObserver).ObserveWithExemplar(
-			elapsed.Seconds(), prometheus.Labels{"traceID": traceID},
-		)
-	} else {
-		histogram.Observe(elapsed.Seconds())
-	}
+	metricutil.ObserveWithExemplar(ctx, databaseQueryHistogram.WithLabelValues(status), elapsed.Seconds())
 
 	ctx = log.IncDBCallCounter(ctx)
 
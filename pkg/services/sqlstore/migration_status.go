@@ -0,0 +1,66 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// MigrationStatus describes which of the registered database migrations have been applied.
+type MigrationStatus struct {
+	Total   int               `json:"total"`
+	Applied int               `json:"applied"`
+	Pending []string          `json:"pending"`
+	Failed  []FailedMigration `json:"failed"`
+}
+
+// FailedMigration is a pending migration whose last attempt failed.
+type FailedMigration struct {
+	ID        string    `json:"id"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MigrationStatus compares the registered migrations with the migration log, without applying anything.
+func (ss *SQLStore) MigrationStatus() (*MigrationStatus, error) {
+	mg := migrator.NewMigrator(ss.engine, ss.Cfg)
+	if ss.migrations != nil {
+		ss.migrations.AddMigration(mg)
+	}
+
+	logItems := make([]migrator.MigrationLog, 0)
+	exists, err := ss.engine.IsTableExist(new(migrator.MigrationLog))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if err := ss.engine.Asc("id").Find(&logItems); err != nil {
+			return nil, err
+		}
+	}
+
+	applied := make(map[string]bool, len(logItems))
+	lastFailure := make(map[string]migrator.MigrationLog)
+	for _, item := range logItems {
+		if item.Success {
+			applied[item.MigrationID] = true
+		} else {
+			lastFailure[item.MigrationID] = item
+		}
+	}
+
+	ids := mg.GetMigrationIDs(true)
+	status := &MigrationStatus{Total: len(ids), Pending: []string{}, Failed: []FailedMigration{}}
+	for _, id := range ids {
+		if applied[id] {
+			status.Applied++
+			continue
+		}
+		status.Pending = append(status.Pending, id)
+		if failure, ok := lastFailure[id]; ok {
+			status.Failed = append(status.Failed, FailedMigration{ID: id, Error: failure.Error, Timestamp: failure.Timestamp})
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,21 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationMigrationStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := InitTestDB(t)
+
+	status, err := store.MigrationStatus()
+	require.NoError(t, err)
+	require.NotZero(t, status.Total)
+	require.Equal(t, status.Total, status.Applied)
+	require.Empty(t, status.Pending)
+	require.Empty(t, status.Failed)
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAccountDeletionMigrations(mg *Migrator) {
+	deletionRequestV1 := Table{
+		Name: "user_deletion_request",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "reason", Type: DB_Text, Nullable: true},
+			{Name: "reviewed_by", Type: DB_BigInt, Nullable: false},
+			{Name: "delete_after", Type: DB_DateTime, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id"}, Type: UniqueIndex},
+			{Cols: []string{"status", "delete_after"}},
+		},
+	}
+
+	mg.AddMigration("create user_deletion_request table", NewAddTableMigration(deletionRequestV1))
+	mg.AddMigration("add unique index user_deletion_request.user_id", NewAddIndexMigration(deletionRequestV1, deletionRequestV1.Indices[0]))
+	mg.AddMigration("add index user_deletion_request.status_delete_after", NewAddIndexMigration(deletionRequestV1, deletionRequestV1.Indices[1]))
+}
@@ -100,4 +100,31 @@ func addApiKeyMigrations(mg *Migrator) {
 	mg.AddMigration("Add is_revoked column to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
 		Name: "is_revoked", Type: DB_Bool, Nullable: true, Default: "0",
 	}))
+
+	mg.AddMigration("Add last_used_ip to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "last_used_ip", Type: DB_NVarchar, Length: 64, Nullable: true,
+	}))
+
+	// permissions restrict a service account token to a subset of the permissions of its service account
+	mg.AddMigration("Add permissions to api_key table", NewAddColumnMigration(apiKeyV2, &Column{
+		Name: "permissions", Type: DB_Text, Nullable: true,
+	}))
+
+	// api_key_usage counts the calls by endpoint of every API key and service account token
+	apiKeyUsageV1 := Table{
+		Name: "api_key_usage",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "api_key_id", Type: DB_BigInt, Nullable: false},
+			{Name: "endpoint", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "call_count", Type: DB_BigInt, Nullable: false},
+			{Name: "last_used_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"api_key_id", "endpoint"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create api_key_usage table", NewAddTableMigration(apiKeyUsageV1))
+	mg.AddMigration("add unique index api_key_usage.api_key_id_endpoint", NewAddIndexMigration(apiKeyUsageV1, apiKeyUsageV1.Indices[0]))
 }
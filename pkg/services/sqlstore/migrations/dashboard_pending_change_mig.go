@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardPendingChangeMigrations(mg *Migrator) {
+	pendingChangeV1 := Table{
+		Name: "dashboard_pending_change",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 189, Nullable: false},
+			{Name: "data", Type: DB_MediumText, Nullable: false},
+			{Name: "message", Type: DB_Text, Nullable: false},
+			{Name: "base_version", Type: DB_Int, Nullable: false},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "reviewed_by", Type: DB_BigInt, Nullable: false},
+			{Name: "review_comment", Type: DB_Text, Nullable: false},
+			{Name: "dashboard_version", Type: DB_Int, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "status"}},
+			{Cols: []string{"org_id", "dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_pending_change table", NewAddTableMigration(pendingChangeV1))
+	mg.AddMigration("add index dashboard_pending_change.org_id_status", NewAddIndexMigration(pendingChangeV1, pendingChangeV1.Indices[0]))
+	mg.AddMigration("add index dashboard_pending_change.org_id_dashboard_uid", NewAddIndexMigration(pendingChangeV1, pendingChangeV1.Indices[1]))
+}
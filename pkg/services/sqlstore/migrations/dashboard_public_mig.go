@@ -96,4 +96,11 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	mg.AddMigration("add cdn_cache_enabled column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "cdn_cache_enabled",
+		Type:     DB_Bool,
+		Nullable: false,
+		Default:  "0",
+	}))
 }
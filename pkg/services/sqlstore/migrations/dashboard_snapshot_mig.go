@@ -71,4 +71,8 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add payload_in_storage column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "payload_in_storage", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
 }
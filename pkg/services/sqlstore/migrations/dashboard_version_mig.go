@@ -1,6 +1,12 @@
 package migrations
 
-import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+import (
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
 
 func addDashboardVersionMigration(mg *Migrator) {
 	dashboardVersionV1 := Table{
@@ -56,4 +62,105 @@ FROM dashboard;`
 	// change column type of dashboard_version.data
 	mg.AddMigration("alter dashboard_version.data to mediumtext v1", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_version MODIFY data MEDIUMTEXT;"))
+
+	// the JSON of the versions is stored once per distinct content, compressed
+	dashboardVersionContentV1 := Table{
+		Name: "dashboard_version_content",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "hash", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "data", Type: DB_MediumBlob, Nullable: false},
+			{Name: "compression", Type: DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "size", Type: DB_Int, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"hash"}, Type: UniqueIndex},
+		},
+	}
+	mg.AddMigration("create dashboard_version_content table v1", NewAddTableMigration(dashboardVersionContentV1))
+	mg.AddMigration("add unique index dashboard_version_content.hash", NewAddIndexMigration(dashboardVersionContentV1, dashboardVersionContentV1.Indices[0]))
+	// the data of the archived contents is in the dashboard version archive, the column must exist before the
+	// deduplication below inserts the contents
+	mg.AddMigration("add column archived in dashboard_version_content", NewAddColumnMigration(dashboardVersionContentV1, &Column{
+		Name: "archived", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+	mg.AddMigration("add index dashboard_version_content.archived_updated", NewAddIndexMigration(dashboardVersionContentV1, &Index{
+		Cols: []string{"archived", "updated"},
+	}))
+
+	mg.AddMigration("add column content_hash in dashboard_version", NewAddColumnMigration(dashboardVersionV1, &Column{
+		Name: "content_hash", Type: DB_NVarchar, Length: 64, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("add index dashboard_version.content_hash", NewAddIndexMigration(dashboardVersionV1, &Index{
+		Cols: []string{"content_hash"},
+	}).Online())
+
+	mg.AddMigration("deduplicate dashboard_version data v1", &dedupDashboardVersionDataMigration{})
+}
+
+const dedupDashboardVersionBatchSize = 100
+
+// dedupDashboardVersionDataMigration moves the JSON of the existing versions to the dashboard_version_content table.
+type dedupDashboardVersionDataMigration struct {
+	MigrationBase
+}
+
+func (m *dedupDashboardVersionDataMigration) SQL(dialect Dialect) string {
+	return "code migration"
+}
+
+type dedupDashboardVersionRow struct {
+	ID      int64  `xorm:"id"`
+	Version int    `xorm:"version"`
+	Data    []byte `xorm:"data"`
+}
+
+func (m *dedupDashboardVersionDataMigration) Exec(sess *xorm.Session, mg *Migrator) error {
+	// the hashes already in the table, to skip the lookups of the contents shared by many versions
+	saved := map[string]bool{}
+	var lastID int64
+	for {
+		rows := make([]*dedupDashboardVersionRow, 0, dedupDashboardVersionBatchSize)
+		if err := sess.SQL("SELECT id, version, data FROM dashboard_version WHERE content_hash = '' AND id > ? ORDER BY id LIMIT ?",
+			lastID, dedupDashboardVersionBatchSize).Find(&rows); err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			data, err := simplejson.NewJson(row.Data)
+			if err != nil {
+				// the versions which aren't valid JSON objects are left as they are
+				mg.Logger.Warn("Failed to read the JSON of a dashboard version", "id", row.ID, "error", err)
+				continue
+			}
+			content, err := dashver.NewDashboardVersionContent(data, row.Version)
+			if err != nil {
+				mg.Logger.Warn("Failed to read the JSON of a dashboard version", "id", row.ID, "error", err)
+				continue
+			}
+
+			if !saved[content.Hash] {
+				exists, err := sess.Table("dashboard_version_content").Where("hash = ?", content.Hash).Exist()
+				if err != nil {
+					return err
+				}
+				if !exists {
+					if _, err := sess.Table("dashboard_version_content").Insert(content); err != nil {
+						return err
+					}
+				}
+				saved[content.Hash] = true
+			}
+
+			if _, err := sess.Exec("UPDATE dashboard_version SET content_hash = ?, data = ? WHERE id = ?", content.Hash, "{}", row.ID); err != nil {
+				return err
+			}
+		}
+	}
 }
@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestDedupDashboardVersionDataMigration(t *testing.T) {
+	// a database of its own, the in-memory test database is shared with the other tests
+	x, err := xorm.NewEngine("sqlite3", filepath.Join(t.TempDir(), "grafana.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = x.Close() })
+
+	mg := NewMigrator(x, &setting.Cfg{})
+	(&OSSMigrations{}).AddMigration(mg)
+	require.NoError(t, mg.Start(false, 0))
+
+	insert := func(version int, data string) {
+		_, err := x.Exec(`INSERT INTO dashboard_version (dashboard_id, parent_version, restored_from, version, created, created_by, message, data)
+			VALUES (1, ?, 0, ?, ?, 1, '', ?)`, version-1, version, time.Now(), data)
+		require.NoError(t, err)
+	}
+	insert(1, `{"title":"Dash","version":1}`)
+	insert(2, `{"version":2,"title":"Dash"}`)
+	insert(3, `{"title":"Renamed","version":3}`)
+	insert(4, `not json`)
+
+	sess := x.NewSession()
+	defer sess.Close()
+	require.NoError(t, (&dedupDashboardVersionDataMigration{}).Exec(sess, mg))
+
+	var versions []struct {
+		Version     int    `xorm:"version"`
+		ContentHash string `xorm:"content_hash"`
+		Data        string `xorm:"data"`
+	}
+	require.NoError(t, x.SQL("SELECT version, content_hash, data FROM dashboard_version ORDER BY version").Find(&versions))
+	require.Len(t, versions, 4)
+	require.Equal(t, versions[0].ContentHash, versions[1].ContentHash)
+	require.NotEqual(t, versions[0].ContentHash, versions[2].ContentHash)
+	require.Equal(t, "{}", versions[0].Data)
+	require.Empty(t, versions[3].ContentHash, "the invalid versions are left as they are")
+	require.Equal(t, "not json", versions[3].Data)
+
+	var contents []*dashver.DashboardVersionContent
+	require.NoError(t, x.Table("dashboard_version_content").Find(&contents))
+	require.Len(t, contents, 2)
+
+	data, err := contents[0].Decode(2)
+	require.NoError(t, err)
+	require.Equal(t, "Dash", data.Get("title").MustString())
+	require.Equal(t, 2, data.Get("version").MustInt())
+}
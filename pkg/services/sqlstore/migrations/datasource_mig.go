@@ -134,4 +134,22 @@ func addDataSourceMigration(mg *Migrator) {
 
 	mg.AddMigration("add unique index datasource_org_id_is_default", NewAddIndexMigration(tableV2, &Index{
 		Cols: []string{"org_id", "is_default"}}))
+
+	dataSourceTeamV1 := Table{
+		Name: "data_source_team",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "data_source_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"data_source_id", "team_id"}, Type: UniqueIndex},
+			{Cols: []string{"team_id"}},
+		},
+	}
+
+	mg.AddMigration("create data_source_team table", NewAddTableMigration(dataSourceTeamV1))
+	addTableIndicesMigrations(mg, "v1", dataSourceTeamV1)
 }
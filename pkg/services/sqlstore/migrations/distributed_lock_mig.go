@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDistributedLockMigrations(mg *Migrator) {
+	distributedLockV1 := Table{
+		Name: "distributed_lock",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "holder", Type: DB_NVarchar, Length: 190, Nullable: false, Default: "''"},
+			// the token identifies the acquisition, so that a holder can't renew or release a lock it lost
+			{Name: "token", Type: DB_NVarchar, Length: 40, Nullable: false, Default: "''"},
+			{Name: "acquired_at", Type: DB_BigInt, Nullable: false, Default: "0"},
+			// a lock is free once it expired, either released or not renewed by its holder
+			{Name: "expires_at", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "last_completed", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"name"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create distributed_lock table", NewAddTableMigration(distributedLockV1))
+	addTableIndicesMigrations(mg, "v1", distributedLockV1)
+
+	distributedLockWaiterV1 := Table{
+		Name: "distributed_lock_waiter",
+		Columns: []*Column{
+			// the id orders the waiters, the lock is granted to the oldest live one
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "holder", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "heartbeat_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"name", "id"}},
+		},
+	}
+
+	mg.AddMigration("create distributed_lock_waiter table", NewAddTableMigration(distributedLockWaiterV1))
+	addTableIndicesMigrations(mg, "v1", distributedLockWaiterV1)
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addFeatureToggleOverrideMigrations(mg *Migrator) {
+	featureToggleOverrideV1 := Table{
+		Name: "feature_toggle_override",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "enabled", Type: DB_Bool, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"name"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create feature_toggle_override table", NewAddTableMigration(featureToggleOverrideV1))
+	mg.AddMigration("add unique index feature_toggle_override.name", NewAddIndexMigration(featureToggleOverrideV1, featureToggleOverrideV1.Indices[0]))
+
+	featureToggleRolloutV1 := Table{
+		Name: "feature_toggle_rollout",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "org_ids", Type: DB_Text, Nullable: true},
+			{Name: "team_ids", Type: DB_Text, Nullable: true},
+			{Name: "percentage", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"name"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create feature_toggle_rollout table", NewAddTableMigration(featureToggleRolloutV1))
+	mg.AddMigration("add unique index feature_toggle_rollout.name", NewAddIndexMigration(featureToggleRolloutV1, featureToggleRolloutV1.Indices[0]))
+}
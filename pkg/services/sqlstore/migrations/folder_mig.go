@@ -1,6 +1,8 @@
 package migrations
 
 import (
+	"xorm.io/xorm"
+
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 )
 
@@ -26,6 +28,75 @@ func addFolderMigrations(mg *migrator.Migrator) {
 		Type: migrator.UniqueIndex,
 		Cols: []string{"title", "parent_uid"},
 	}))
+
+	folderClosureV1 := migrator.Table{
+		Name: "folder_closure",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "ancestor_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "descendant_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "depth", Type: migrator.DB_Int, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "ancestor_uid", "descendant_uid"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "descendant_uid"}},
+		},
+	}
+
+	mg.AddMigration("create folder_closure table", migrator.NewAddTableMigration(folderClosureV1))
+	addTableIndicesMigrations(mg, "v1", folderClosureV1)
+	mg.AddMigration("populate folder_closure table", &folderClosureMigration{})
+}
+
+// folderClosureMigration fills the folder_closure table with the ancestors of the existing folders,
+// the folder store keeps it up to date afterwards.
+type folderClosureMigration struct {
+	migrator.MigrationBase
+}
+
+func (m *folderClosureMigration) SQL(dialect migrator.Dialect) string {
+	return "code migration"
+}
+
+func (m *folderClosureMigration) Exec(sess *xorm.Session, mg *migrator.Migrator) error {
+	type folderRow struct {
+		OrgID     int64   `xorm:"org_id"`
+		UID       string  `xorm:"uid"`
+		ParentUID *string `xorm:"parent_uid"`
+	}
+	var folders []folderRow
+	if err := sess.SQL("SELECT org_id, uid, parent_uid FROM folder").Find(&folders); err != nil {
+		return err
+	}
+
+	type key struct {
+		orgID int64
+		uid   string
+	}
+	parents := make(map[key]string, len(folders))
+	for _, f := range folders {
+		parents[key{f.OrgID, f.UID}] = ""
+		if f.ParentUID != nil {
+			parents[key{f.OrgID, f.UID}] = *f.ParentUID
+		}
+	}
+
+	for _, f := range folders {
+		ancestor, depth := f.UID, 0
+		for {
+			if _, err := sess.Exec("INSERT INTO folder_closure (org_id, ancestor_uid, descendant_uid, depth) VALUES (?, ?, ?, ?)", f.OrgID, ancestor, f.UID, depth); err != nil {
+				return err
+			}
+			parent := parents[key{f.OrgID, ancestor}]
+			// stop at the root, or at the general folder that has no row; the depth check protects against cycles
+			if _, ok := parents[key{f.OrgID, parent}]; !ok || depth > len(folders) {
+				break
+			}
+			ancestor, depth = parent, depth+1
+		}
+	}
+	return nil
 }
 
 func folderv1() migrator.Table {
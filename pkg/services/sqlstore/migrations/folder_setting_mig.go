@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addFolderSettingMigrations(mg *Migrator) {
+	folderSettingV1 := Table{
+		Name: "folder_setting",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "default_datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "tags", Type: DB_Text, Nullable: false},
+			{Name: "default_refresh", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "folder_uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create folder_setting table", NewAddTableMigration(folderSettingV1))
+	mg.AddMigration("add unique index folder_setting.org_id_folder_uid", NewAddIndexMigration(folderSettingV1, folderSettingV1.Indices[0]))
+
+	mg.AddMigration("add require_approval column to folder_setting", NewAddColumnMigration(folderSettingV1, &Column{
+		Name: "require_approval", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addJobQueueMigrations(mg *Migrator) {
+	jobQueueV1 := Table{
+		Name: "job_queue",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "type", Type: DB_NVarchar, Length: 190, Nullable: false},
+			// the key is cleared once the job is dead, so that a new job with the same key can be queued
+			{Name: "dedup_key", Type: DB_NVarchar, Length: 190, Nullable: true},
+			{Name: "payload", Type: DB_Text, Nullable: true},
+			{Name: "priority", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "status", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false, Default: "0"},
+			{Name: "max_attempts", Type: DB_Int, Nullable: false},
+			{Name: "last_error", Type: DB_Text, Nullable: true},
+			{Name: "run_at", Type: DB_BigInt, Nullable: false},
+			// a running job whose lock expired is picked again, its instance is assumed to be gone
+			{Name: "locked_until", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"dedup_key"}, Type: UniqueIndex},
+			{Cols: []string{"status", "run_at"}},
+		},
+	}
+
+	mg.AddMigration("create job_queue table", NewAddTableMigration(jobQueueV1))
+	addTableIndicesMigrations(mg, "v1", jobQueueV1)
+}
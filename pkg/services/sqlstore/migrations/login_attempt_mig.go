@@ -39,4 +39,8 @@ func addLoginAttemptMigrations(mg *Migrator) {
 		"username":   "username",
 		"ip_address": "ip_address",
 	})
+
+	mg.AddMigration("add index login_attempt.ip_address", NewAddIndexMigration(loginAttemptV2, &Index{
+		Cols: []string{"ip_address"},
+	}).Online())
 }
@@ -100,6 +100,37 @@ func (*OSSMigrations) AddMigration(mg *Migrator) {
 	AddExternalAlertmanagerToDatasourceMigration(mg)
 
 	addFolderMigrations(mg)
+
+	addFeatureToggleOverrideMigrations(mg)
+
+	addUsageInsightsMigrations(mg)
+
+	addOrgSettingMigrations(mg)
+
+	addUserInactivityNoticeMigrations(mg)
+
+	addJobQueueMigrations(mg)
+	addNavCustomizationMigrations(mg)
+
+	addUsageStatsReportMigrations(mg)
+
+	addFolderSettingMigrations(mg)
+
+	addSecretUsageMigrations(mg)
+
+	addSavedSearchMigrations(mg)
+
+	addQueryHistoryLabelMigrations(mg)
+
+	addDistributedLockMigrations(mg)
+
+	addOAuthServerMigrations(mg)
+
+	addAccountDeletionMigrations(mg)
+
+	addUserNotificationMigrations(mg)
+
+	addDashboardPendingChangeMigrations(mg)
 }
 
 func addMigrationLogMigrations(mg *Migrator) {
@@ -133,4 +164,24 @@ func addStarMigrations(mg *Migrator) {
 
 	mg.AddMigration("create star table", NewAddTableMigration(starV1))
 	mg.AddMigration("add unique index star.user_id_dashboard_id", NewAddIndexMigration(starV1, starV1.Indices[0]))
+
+	resourceStarV1 := Table{
+		Name: "resource_star",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id", "org_id", "kind", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "kind", "uid"}},
+		},
+	}
+
+	mg.AddMigration("create resource_star table", NewAddTableMigration(resourceStarV1))
+	mg.AddMigration("add unique index resource_star.user_id_org_id_kind_uid", NewAddIndexMigration(resourceStarV1, resourceStarV1.Indices[0]))
+	mg.AddMigration("add index resource_star.org_id_kind_uid", NewAddIndexMigration(resourceStarV1, resourceStarV1.Indices[1]))
 }
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addNavCustomizationMigrations(mg *Migrator) {
+	navCustomizationV1 := Table{
+		Name: "nav_customization",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false},
+			{Name: "nav_id", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "type", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "sort_weight", Type: DB_BigInt, Nullable: false},
+			{Name: "parent_id", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "text", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "url", Type: DB_Text, Nullable: false},
+			{Name: "icon", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "target", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+			{Name: "updated_by", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "team_id", "nav_id"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create nav_customization table", NewAddTableMigration(navCustomizationV1))
+	mg.AddMigration("add unique index nav_customization.org_id_team_id_nav_id", NewAddIndexMigration(navCustomizationV1, navCustomizationV1.Indices[0]))
+}
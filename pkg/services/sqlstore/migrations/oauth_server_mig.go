@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addOAuthServerMigrations(mg *Migrator) {
+	oauthClientV1 := Table{
+		Name: "oauth_client",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "client_id", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "secret", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "service_account_id", Type: DB_BigInt, Nullable: false},
+			{Name: "scopes", Type: DB_Text, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"client_id"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "name"}},
+		},
+	}
+
+	mg.AddMigration("create oauth_client table", NewAddTableMigration(oauthClientV1))
+	mg.AddMigration("add unique index oauth_client.client_id", NewAddIndexMigration(oauthClientV1, oauthClientV1.Indices[0]))
+	mg.AddMigration("add index oauth_client.org_id_name", NewAddIndexMigration(oauthClientV1, oauthClientV1.Indices[1]))
+
+	oauthTokenV1 := Table{
+		Name: "oauth_token",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "client_id", Type: DB_BigInt, Nullable: false},
+			{Name: "family_id", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "access_token", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "refresh_token", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "scopes", Type: DB_Text, Nullable: false},
+			{Name: "access_expires", Type: DB_DateTime, Nullable: false},
+			{Name: "refresh_expires", Type: DB_DateTime, Nullable: false},
+			{Name: "rotated", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "revoked", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"access_token"}, Type: UniqueIndex},
+			{Cols: []string{"refresh_token"}, Type: UniqueIndex},
+			{Cols: []string{"client_id", "family_id"}},
+			{Cols: []string{"client_id", "refresh_expires"}},
+		},
+	}
+
+	mg.AddMigration("create oauth_token table", NewAddTableMigration(oauthTokenV1))
+	mg.AddMigration("add unique index oauth_token.access_token", NewAddIndexMigration(oauthTokenV1, oauthTokenV1.Indices[0]))
+	mg.AddMigration("add unique index oauth_token.refresh_token", NewAddIndexMigration(oauthTokenV1, oauthTokenV1.Indices[1]))
+	mg.AddMigration("add index oauth_token.client_id_family_id", NewAddIndexMigration(oauthTokenV1, oauthTokenV1.Indices[2]))
+	mg.AddMigration("add index oauth_token.client_id_refresh_expires", NewAddIndexMigration(oauthTokenV1, oauthTokenV1.Indices[3]))
+}
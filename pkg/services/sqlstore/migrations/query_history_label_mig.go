@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addQueryHistoryLabelMigrations(mg *Migrator) {
+	queryHistoryLabelV1 := Table{
+		Name: "query_history_label",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "query_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "label", Type: DB_NVarchar, Length: 100, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"query_uid", "label"}, Type: UniqueIndex},
+			{Cols: []string{"label"}},
+		},
+	}
+
+	mg.AddMigration("create query_history_label table v1", NewAddTableMigration(queryHistoryLabelV1))
+	mg.AddMigration("add unique index query_history_label.query_uid-label", NewAddIndexMigration(queryHistoryLabelV1, queryHistoryLabelV1.Indices[0]))
+	mg.AddMigration("add index query_history_label.label", NewAddIndexMigration(queryHistoryLabelV1, queryHistoryLabelV1.Indices[1]))
+}
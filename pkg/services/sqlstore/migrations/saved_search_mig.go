@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addSavedSearchMigrations(mg *Migrator) {
+	savedSearchV1 := Table{
+		Name: "saved_search",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "query", Type: DB_Text, Nullable: false},
+			{Name: "filters", Type: DB_Text, Nullable: true},
+			{Name: "owner_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "owner_id", "name"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "team_id"}},
+		},
+	}
+
+	mg.AddMigration("create saved_search table", NewAddTableMigration(savedSearchV1))
+	mg.AddMigration("add unique index saved_search.org_id_uid", NewAddIndexMigration(savedSearchV1, savedSearchV1.Indices[0]))
+	mg.AddMigration("add unique index saved_search.org_id_owner_id_name", NewAddIndexMigration(savedSearchV1, savedSearchV1.Indices[1]))
+	mg.AddMigration("add index saved_search.org_id_team_id", NewAddIndexMigration(savedSearchV1, savedSearchV1.Indices[2]))
+}
@@ -74,3 +74,43 @@ func addSecretsMigration(mg *migrator.Migrator) {
 
 	// --------------------
 }
+
+func addSecretUsageMigrations(mg *migrator.Migrator) {
+	secretUsageV1 := migrator.Table{
+		Name: "secret_usage",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "namespace", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "type", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "consumer", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "read_count", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "first_read", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "last_read", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "namespace", "type", "consumer"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create secret_usage table", migrator.NewAddTableMigration(secretUsageV1))
+	mg.AddMigration("add unique index secret_usage.org_id_namespace_type_consumer", migrator.NewAddIndexMigration(secretUsageV1, secretUsageV1.Indices[0]))
+
+	secretReferenceV1 := migrator.Table{
+		Name: "secret_reference",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "namespace", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "type", Type: migrator.DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "consumer", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "namespace", "type", "consumer"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create secret_reference table", migrator.NewAddTableMigration(secretReferenceV1))
+	mg.AddMigration("add unique index secret_reference.org_id_namespace_type_consumer", migrator.NewAddIndexMigration(secretReferenceV1, secretReferenceV1.Indices[0]))
+}
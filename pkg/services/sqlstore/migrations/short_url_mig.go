@@ -28,4 +28,16 @@ func addShortURLMigrations(mg *Migrator) {
 	mg.AddMigration("alter table short_url alter column created_by type to bigint", NewRawSQLMigration("").
 		Mysql("ALTER TABLE short_url MODIFY created_by BIGINT;").
 		Postgres("ALTER TABLE short_url ALTER COLUMN created_by TYPE BIGINT;"))
+
+	mg.AddMigration("add column is_custom to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "is_custom", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add column expires_at to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "expires_at", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("add column hits to short_url", NewAddColumnMigration(shortURLV1, &Column{
+		Name: "hits", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
 }
@@ -60,4 +60,12 @@ func addTeamMigrations(mg *Migrator) {
 	mg.AddMigration("Add column permission to team_member table", NewAddColumnMigration(teamMemberV1, &Column{
 		Name: "permission", Type: DB_SmallInt, Nullable: true,
 	}))
+
+	mg.AddMigration("Add column parent_id to team table", NewAddColumnMigration(teamV1, &Column{
+		Name: "parent_id", Type: DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add index team.org_id_parent_id", NewAddIndexMigration(teamV1, &Index{
+		Cols: []string{"org_id", "parent_id"},
+	}))
 }
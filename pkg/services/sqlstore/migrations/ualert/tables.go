@@ -24,6 +24,8 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	addAlertNotificationLogMigrations(mg)
 }
 
 // historicalTableMigrations contains those migrations that existed prior to creating the improved messaging around migration immutability.
@@ -490,6 +492,36 @@ func addAlertImageMigrations(mg *migrator.Migrator) {
 		Mysql("ALTER TABLE alert_image MODIFY url VARCHAR(2048) NOT NULL;"))
 }
 
+func addAlertNotificationLogMigrations(mg *migrator.Migrator) {
+	notificationLog := migrator.Table{
+		Name: "alert_notification_log",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "integration_type", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "retry", Type: migrator.DB_Int, Nullable: false},
+			{Name: "will_retry", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "alerts", Type: migrator.DB_Int, Nullable: false},
+			{Name: "latency_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "sent_at", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "sent_at"}},
+			{Cols: []string{"sent_at"}},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_log table", migrator.NewAddTableMigration(notificationLog))
+	mg.AddMigration("add index in alert_notification_log on org_id and sent_at columns", migrator.NewAddIndexMigration(notificationLog, notificationLog.Indices[0]))
+	mg.AddMigration("add index in alert_notification_log on sent_at column", migrator.NewAddIndexMigration(notificationLog, notificationLog.Indices[1]))
+}
+
 func extractAlertmanagerConfigurationHistoryMigration(mg *migrator.Migrator) {
 	if !mg.Cfg.UnifiedAlerting.IsEnabled() {
 		return
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addUsageInsightsMigrations(mg *Migrator) {
+	dashboardUsageV1 := Table{
+		Name: "dashboard_usage_by_day",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			// unix timestamp of the beginning of the UTC day
+			{Name: "day", Type: DB_BigInt, Nullable: false},
+			{Name: "views", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "queries", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "errors", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid", "day"}, Type: UniqueIndex},
+			{Cols: []string{"day"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_usage_by_day table", NewAddTableMigration(dashboardUsageV1))
+	addTableIndicesMigrations(mg, "v1", dashboardUsageV1)
+
+	dataSourceUsageV1 := Table{
+		Name: "datasource_usage_by_day",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "datasource_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			// unix timestamp of the beginning of the UTC day
+			{Name: "day", Type: DB_BigInt, Nullable: false},
+			{Name: "queries", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "errors", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "datasource_uid", "day"}, Type: UniqueIndex},
+			{Cols: []string{"day"}},
+		},
+	}
+
+	mg.AddMigration("create datasource_usage_by_day table", NewAddTableMigration(dataSourceUsageV1))
+	addTableIndicesMigrations(mg, "v1", dataSourceUsageV1)
+
+	// Kept after the daily rows are deleted so unused dashboards can still be told apart
+	dashboardUsageSummaryV1 := Table{
+		Name: "dashboard_usage_summary",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "last_viewed_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard_usage_summary table", NewAddTableMigration(dashboardUsageSummaryV1))
+	addTableIndicesMigrations(mg, "v1", dashboardUsageSummaryV1)
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addUsageStatsReportMigrations(mg *Migrator) {
+	usageStatsReportV1 := Table{
+		Name: "usage_stats_report",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "report", Type: DB_MediumText, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create usage_stats_report table", NewAddTableMigration(usageStatsReportV1))
+	addTableIndicesMigrations(mg, "v1", usageStatsReportV1)
+}
@@ -44,4 +44,17 @@ func addUserAuthTokenMigrations(mg *Migrator) {
 			},
 		),
 	)
+
+	mg.AddMigration(
+		"Add auth_module to the user auth token",
+		NewAddColumnMigration(
+			userAuthTokenV1,
+			&Column{
+				Name:     "auth_module",
+				Type:     DB_NVarchar,
+				Length:   190,
+				Nullable: true,
+			},
+		),
+	)
 }
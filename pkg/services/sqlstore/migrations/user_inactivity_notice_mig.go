@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addUserInactivityNoticeMigrations(mg *Migrator) {
+	userInactivityNoticeV1 := Table{
+		Name: "user_inactivity_notice",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "last_active", Type: DB_DateTime, Nullable: false},
+			{Name: "notified_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "user_id"}, Type: UniqueIndex},
+			{Cols: []string{"user_id"}},
+		},
+	}
+
+	mg.AddMigration("create user_inactivity_notice table", NewAddTableMigration(userInactivityNoticeV1))
+	mg.AddMigration("add unique index user_inactivity_notice.org_id_user_id", NewAddIndexMigration(userInactivityNoticeV1, userInactivityNoticeV1.Indices[0]))
+	mg.AddMigration("add index user_inactivity_notice.user_id", NewAddIndexMigration(userInactivityNoticeV1, userInactivityNoticeV1.Indices[1]))
+}
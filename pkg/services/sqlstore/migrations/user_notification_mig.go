@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addUserNotificationMigrations(mg *Migrator) {
+	notificationV1 := Table{
+		Name: "user_notification",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "body", Type: DB_Text, Nullable: true},
+			{Name: "url", Type: DB_NVarchar, Length: 2048, Nullable: true},
+			{Name: "is_read", Type: DB_Bool, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"user_id", "org_id", "is_read"}},
+			{Cols: []string{"created"}},
+		},
+	}
+
+	mg.AddMigration("create user_notification table", NewAddTableMigration(notificationV1))
+	mg.AddMigration("add index user_notification.user_id_org_id_is_read", NewAddIndexMigration(notificationV1, notificationV1.Indices[0]))
+	mg.AddMigration("add index user_notification.created", NewAddIndexMigration(notificationV1, notificationV1.Indices[1]))
+}
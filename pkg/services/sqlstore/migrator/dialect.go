@@ -3,6 +3,7 @@ package migrator
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"xorm.io/xorm"
 )
@@ -36,6 +37,14 @@ type Dialect interface {
 	CopyTableData(sourceTable string, targetTable string, sourceCols []string, targetCols []string) string
 	DropTable(tableName string) string
 	DropIndexSQL(tableName string, index *Index) string
+	// CreateIndexOnlineSQL returns the statement creating the index without blocking the writes on the table, on the
+	// dialects that support online DDL.
+	CreateIndexOnlineSQL(tableName string, index *Index) string
+	// SupportsOnlineDDL is true when the online statements run outside of a transaction, with a lock timeout.
+	SupportsOnlineDDL() bool
+	// LockTimeoutSQL returns the statement limiting how long the statements of the session wait for table locks,
+	// zero restores the default.
+	LockTimeoutSQL(timeout time.Duration) string
 
 	// RenameTable is deprecated, its use cause breaking changes
 	// so, it should no longer be used. Kept for legacy reasons.
@@ -235,6 +244,18 @@ func (b *BaseDialect) DropIndexSQL(tableName string, index *Index) string {
 	return fmt.Sprintf("DROP INDEX %v ON %s", quote(name), quote(tableName))
 }
 
+func (b *BaseDialect) CreateIndexOnlineSQL(tableName string, index *Index) string {
+	return b.dialect.CreateIndexSQL(tableName, index)
+}
+
+func (b *BaseDialect) SupportsOnlineDDL() bool {
+	return false
+}
+
+func (b *BaseDialect) LockTimeoutSQL(timeout time.Duration) string {
+	return ""
+}
+
 func (b *BaseDialect) UpdateTableSQL(tableName string, columns []*Column) string {
 	return "-- NOT REQUIRED"
 }
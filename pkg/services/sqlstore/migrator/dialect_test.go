@@ -0,0 +1,54 @@
+package migrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIndexOnlineSQL(t *testing.T) {
+	index := &Index{Type: UniqueIndex, Cols: []string{"org_id", "uid"}}
+
+	tests := []struct {
+		dialect           Dialect
+		expectedSQL       string
+		expectedTimeout   string
+		expectedReset     string
+		expectedCleanup   string
+		supportsOnlineDDL bool
+	}{
+		{
+			dialect:           NewPostgresDialect(nil),
+			expectedSQL:       `CREATE UNIQUE INDEX CONCURRENTLY "UQE_dashboard_org_id_uid" ON "dashboard" ("org_id","uid");`,
+			expectedTimeout:   "SET lock_timeout = 1500",
+			expectedReset:     "SET lock_timeout = 0",
+			expectedCleanup:   `DROP INDEX "UQE_dashboard_org_id_uid" CASCADE`,
+			supportsOnlineDDL: true,
+		},
+		{
+			dialect:           NewMysqlDialect(nil),
+			expectedSQL:       "CREATE UNIQUE INDEX `UQE_dashboard_org_id_uid` ON `dashboard` (`org_id`,`uid`) ALGORITHM=INPLACE LOCK=NONE;",
+			expectedTimeout:   "SET SESSION lock_wait_timeout = 2",
+			expectedReset:     "SET SESSION lock_wait_timeout = DEFAULT",
+			supportsOnlineDDL: true,
+		},
+		{
+			dialect:     NewSQLite3Dialect(nil),
+			expectedSQL: "CREATE UNIQUE INDEX `UQE_dashboard_org_id_uid` ON `dashboard` (`org_id`,`uid`);",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.dialect.DriverName(), func(t *testing.T) {
+			m := NewAddIndexMigration(Table{Name: "dashboard"}, index).Online()
+
+			assert.True(t, m.IsOnline())
+			assert.Equal(t, tc.expectedSQL, m.SQL(tc.dialect))
+			assert.Equal(t, tc.expectedCleanup, m.CleanupSQL(tc.dialect))
+			assert.Equal(t, tc.supportsOnlineDDL, tc.dialect.SupportsOnlineDDL())
+			assert.Equal(t, tc.expectedTimeout, tc.dialect.LockTimeoutSQL(1500*time.Millisecond))
+			assert.Equal(t, tc.expectedReset, tc.dialect.LockTimeoutSQL(0))
+		})
+	}
+}
@@ -147,6 +147,7 @@ type AddIndexMigration struct {
 	MigrationBase
 	tableName string
 	index     *Index
+	online    bool
 }
 
 func NewAddIndexMigration(table Table, index *Index) *AddIndexMigration {
@@ -160,10 +161,32 @@ func (m *AddIndexMigration) Table(tableName string) *AddIndexMigration {
 	return m
 }
 
+// Online creates the index without blocking the writes on the table, for the indexes of large tables.
+func (m *AddIndexMigration) Online() *AddIndexMigration {
+	m.online = true
+	return m
+}
+
+func (m *AddIndexMigration) IsOnline() bool {
+	return m.online
+}
+
 func (m *AddIndexMigration) SQL(dialect Dialect) string {
+	if m.online {
+		return dialect.CreateIndexOnlineSQL(m.tableName, m.index)
+	}
 	return dialect.CreateIndexSQL(m.tableName, m.index)
 }
 
+// CleanupSQL drops the invalid index left by a failed concurrent index creation on Postgres, the other dialects
+// leave nothing behind.
+func (m *AddIndexMigration) CleanupSQL(dialect Dialect) string {
+	if dialect.DriverName() != Postgres {
+		return ""
+	}
+	return dialect.DropIndexSQL(m.tableName, m.index)
+}
+
 type DropIndexMigration struct {
 	MigrationBase
 	tableName string
@@ -1,6 +1,7 @@
 package migrator
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -19,6 +20,8 @@ var (
 	ErrMigratorIsUnlocked = fmt.Errorf("migrator is unlocked")
 )
 
+const defaultOnlineLockTimeout = 10 * time.Second
+
 type Migrator struct {
 	DBEngine     *xorm.Engine
 	Dialect      Dialect
@@ -27,6 +30,8 @@ type Migrator struct {
 	Logger       log.Logger
 	Cfg          *setting.Cfg
 	isLocked     atomic.Bool
+	// OnlineLockTimeout limits how long the online migrations wait for table locks
+	OnlineLockTimeout time.Duration
 }
 
 type MigrationLog struct {
@@ -46,6 +51,7 @@ func NewMigrator(engine *xorm.Engine, cfg *setting.Cfg) *Migrator {
 	mg.migrationIds = make(map[string]struct{})
 	mg.Dialect = NewDialect(mg.DBEngine)
 	mg.Cfg = cfg
+	mg.OnlineLockTimeout = defaultOnlineLockTimeout
 	return mg
 }
 
@@ -153,6 +159,14 @@ func (mg *Migrator) run() (err error) {
 			Timestamp:   time.Now(),
 		}
 
+		if om, ok := m.(OnlineMigration); ok && om.IsOnline() && mg.Dialect.SupportsOnlineDDL() {
+			if err := mg.runOnline(om, &record); err != nil {
+				return fmt.Errorf("%v: %w", fmt.Sprintf("migration failed (id = %s)", m.Id()), err)
+			}
+			migrationsPerformed++
+			continue
+		}
+
 		err := mg.InTransaction(func(sess *xorm.Session) error {
 			err := mg.exec(m, sess)
 			if err != nil {
@@ -225,6 +239,83 @@ func (mg *Migrator) exec(m Migration, sess *xorm.Session) error {
 	return nil
 }
 
+// runOnline runs an online migration and records it in the migration log.
+func (mg *Migrator) runOnline(m OnlineMigration, record *MigrationLog) error {
+	if err := mg.execOnline(m); err != nil {
+		mg.Logger.Error("Exec failed", "error", err, "sql", record.SQL)
+		record.Error = err.Error()
+		if !m.SkipMigrationLog() {
+			if _, err := mg.DBEngine.Insert(record); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+
+	record.Success = true
+	if !m.SkipMigrationLog() {
+		if _, err := mg.DBEngine.Insert(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execOnline runs the statement of an online migration outside of a transaction, on a dedicated connection. Waiting
+// for the table locks is limited by the lock timeout, so that a migration stuck behind a long running transaction
+// fails instead of queuing all the writes on the table.
+func (mg *Migrator) execOnline(m OnlineMigration) error {
+	mg.Logger.Info("Executing online migration", "id", m.Id())
+
+	if condition := m.GetCondition(); condition != nil {
+		if sql, args := condition.SQL(mg.Dialect); sql != "" {
+			mg.Logger.Debug("Executing migration condition SQL", "id", m.Id(), "sql", sql, "args", args)
+			results, err := mg.DBEngine.SQL(sql, args...).Query()
+			if err != nil {
+				mg.Logger.Error("Executing migration condition failed", "id", m.Id(), "error", err)
+				return err
+			}
+
+			if !condition.IsFulfilled(results) {
+				mg.Logger.Warn("Skipping migration: Already executed, but not recorded in migration log", "id", m.Id())
+				return nil
+			}
+		}
+	}
+
+	ctx := context.Background()
+	conn, err := mg.DBEngine.DB().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.ExecContext(ctx, mg.Dialect.LockTimeoutSQL(mg.OnlineLockTimeout)); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, mg.Dialect.LockTimeoutSQL(0)); err != nil {
+			mg.Logger.Warn("Failed to reset the lock timeout", "error", err)
+		}
+	}()
+
+	sql := m.SQL(mg.Dialect)
+	mg.Logger.Debug("Executing online sql migration", "id", m.Id(), "sql", sql)
+	if _, err := conn.ExecContext(ctx, sql); err != nil {
+		mg.Logger.Error("Executing migration failed", "id", m.Id(), "error", err)
+		if cleanup := m.CleanupSQL(mg.Dialect); cleanup != "" {
+			if _, cleanupErr := conn.ExecContext(ctx, cleanup); cleanupErr != nil {
+				mg.Logger.Debug("Nothing to clean up after the failed migration", "id", m.Id(), "error", cleanupErr)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
 type dbTransactionFunc func(sess *xorm.Session) error
 
 func (mg *Migrator) InTransaction(callback dbTransactionFunc) error {
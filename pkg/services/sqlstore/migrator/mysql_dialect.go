@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/VividCortex/mysqlerr"
 	"github.com/go-sql-driver/mysql"
@@ -101,6 +102,23 @@ func (db *MySQLDialect) SQLType(c *Column) string {
 	return res
 }
 
+// CreateIndexOnlineSQL creates the index in place, the table stays writable while the index is built.
+func (db *MySQLDialect) CreateIndexOnlineSQL(tableName string, index *Index) string {
+	return strings.TrimSuffix(db.CreateIndexSQL(tableName, index), ";") + " ALGORITHM=INPLACE LOCK=NONE;"
+}
+
+func (db *MySQLDialect) SupportsOnlineDDL() bool {
+	return true
+}
+
+func (db *MySQLDialect) LockTimeoutSQL(timeout time.Duration) string {
+	if timeout <= 0 {
+		return "SET SESSION lock_wait_timeout = DEFAULT"
+	}
+	// the timeout is in seconds, rounded up so that it never disables the waits
+	return fmt.Sprintf("SET SESSION lock_wait_timeout = %d", int64((timeout+time.Second-1)/time.Second))
+}
+
 func (db *MySQLDialect) UpdateTableSQL(tableName string, columns []*Column) string {
 	var statements = []string{}
 
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/lib/pq"
@@ -120,6 +121,19 @@ func (db *PostgresDialect) DropIndexSQL(tableName string, index *Index) string {
 	return fmt.Sprintf("DROP INDEX %v CASCADE", quote(idxName))
 }
 
+// CreateIndexOnlineSQL creates the index concurrently, which can't run in a transaction.
+func (db *PostgresDialect) CreateIndexOnlineSQL(tableName string, index *Index) string {
+	return strings.Replace(db.CreateIndexSQL(tableName, index), " INDEX ", " INDEX CONCURRENTLY ", 1)
+}
+
+func (db *PostgresDialect) SupportsOnlineDDL() bool {
+	return true
+}
+
+func (db *PostgresDialect) LockTimeoutSQL(timeout time.Duration) string {
+	return fmt.Sprintf("SET lock_timeout = %d", timeout.Milliseconds())
+}
+
 func (db *PostgresDialect) UpdateTableSQL(tableName string, columns []*Column) string {
 	var statements = []string{}
 
@@ -25,6 +25,16 @@ type Migration interface {
 	SkipMigrationLog() bool
 }
 
+// OnlineMigration is a migration that doesn't block the writes on the table, on the dialects that support online
+// DDL. It runs outside of a transaction.
+type OnlineMigration interface {
+	Migration
+	IsOnline() bool
+	// CleanupSQL undoes what a failed online statement leaves behind, like the invalid index of a failed concurrent
+	// index creation.
+	CleanupSQL(dialect Dialect) string
+}
+
 type CodeMigration interface {
 	Migration
 	Exec(sess *xorm.Session, migrator *Migrator) error
@@ -78,10 +78,24 @@ func (d DashboardPermissionFilter) Where() (string, []interface{}) {
 	return sql, params
 }
 
+// NestedFoldersMode defines how the folder scopes of the permissions are applied to the subfolders.
+type NestedFoldersMode int
+
+const (
+	// NestedFoldersDisabled applies a folders:uid scope to the folder and its dashboards only.
+	NestedFoldersDisabled NestedFoldersMode = iota
+	// NestedFoldersRecursive expands a folders:uid scope to all the descendant folders with a recursive query.
+	NestedFoldersRecursive
+	// NestedFoldersClosure expands a folders:uid scope to all the descendant folders with the folder_closure table,
+	// for the databases that do not support recursive queries.
+	NestedFoldersClosure
+)
+
 type AccessControlDashboardPermissionFilter struct {
-	user             *user.SignedInUser
-	dashboardActions []string
-	folderActions    []string
+	user              *user.SignedInUser
+	dashboardActions  []string
+	folderActions     []string
+	nestedFoldersMode NestedFoldersMode
 }
 
 // NewAccessControlDashboardPermissionFilter creates a new AccessControlDashboardPermissionFilter that is configured with specific actions calculated based on the dashboards.PermissionType and query type
@@ -124,6 +138,12 @@ func NewAccessControlDashboardPermissionFilter(user *user.SignedInUser, permissi
 	return AccessControlDashboardPermissionFilter{user: user, folderActions: folderActions, dashboardActions: dashboardActions}
 }
 
+// WithNestedFolders returns a copy of the filter that applies the folder permissions to the subfolders, and their dashboards, according to the mode.
+func (f AccessControlDashboardPermissionFilter) WithNestedFolders(mode NestedFoldersMode) AccessControlDashboardPermissionFilter {
+	f.nestedFoldersMode = mode
+	return f
+}
+
 func (f AccessControlDashboardPermissionFilter) Where() (string, []interface{}) {
 	if f.user == nil || f.user.Permissions == nil || f.user.Permissions[f.user.OrgID] == nil {
 		return "(1 = 0)", nil
@@ -140,34 +160,15 @@ func (f AccessControlDashboardPermissionFilter) Where() (string, []interface{})
 		toCheck := actionsToCheck(f.dashboardActions, f.user.Permissions[f.user.OrgID], dashWildcards, folderWildcards)
 
 		if len(toCheck) > 0 {
-			builder.WriteString("(dashboard.uid IN (SELECT substr(scope, 16) FROM permission WHERE scope LIKE 'dashboards:uid:%'")
-			builder.WriteString(rolesFilter)
-			args = append(args, params...)
-
-			if len(toCheck) == 1 {
-				builder.WriteString(" AND action = ?")
-				args = append(args, toCheck[0])
-			} else {
-				builder.WriteString(" AND action IN (?" + strings.Repeat(", ?", len(toCheck)-1) + ") GROUP BY role_id, scope HAVING COUNT(action) = ?")
-				args = append(args, toCheck...)
-				args = append(args, len(toCheck))
-			}
-			builder.WriteString(") AND NOT dashboard.is_folder)")
+			dashboardUIDs, dashboardArgs := permissionScopesQuery("SELECT substr(scope, 16) FROM permission WHERE scope LIKE 'dashboards:uid:%'", rolesFilter, params, toCheck)
+			builder.WriteString("(dashboard.uid IN (" + dashboardUIDs + ") AND NOT dashboard.is_folder)")
+			args = append(args, dashboardArgs...)
 
 			builder.WriteString(" OR ")
-			builder.WriteString("(dashboard.folder_id IN (SELECT id FROM dashboard as d WHERE d.uid IN (SELECT substr(scope, 13) FROM permission WHERE scope LIKE 'folders:uid:%' ")
-			builder.WriteString(rolesFilter)
-			args = append(args, params...)
-
-			if len(toCheck) == 1 {
-				builder.WriteString(" AND action = ?")
-				args = append(args, toCheck[0])
-			} else {
-				builder.WriteString(" AND action IN (?" + strings.Repeat(", ?", len(toCheck)-1) + ") GROUP BY role_id, scope HAVING COUNT(action) = ?")
-				args = append(args, toCheck...)
-				args = append(args, len(toCheck))
-			}
-			builder.WriteString(")) AND NOT dashboard.is_folder)")
+			folderUIDs, folderArgs := permissionScopesQuery("SELECT substr(scope, 13) FROM permission WHERE scope LIKE 'folders:uid:%' ", rolesFilter, params, toCheck)
+			folderFilter, folderArgs := f.folderUIDFilter("d.uid", folderUIDs, folderArgs)
+			builder.WriteString("(dashboard.folder_id IN (SELECT id FROM dashboard as d WHERE " + folderFilter + ") AND NOT dashboard.is_folder)")
+			args = append(args, folderArgs...)
 		} else {
 			builder.WriteString("NOT dashboard.is_folder")
 		}
@@ -180,18 +181,10 @@ func (f AccessControlDashboardPermissionFilter) Where() (string, []interface{})
 
 		toCheck := actionsToCheck(f.folderActions, f.user.Permissions[f.user.OrgID], folderWildcards)
 		if len(toCheck) > 0 {
-			builder.WriteString("(dashboard.uid IN (SELECT substr(scope, 13) FROM permission WHERE scope LIKE 'folders:uid:%'")
-			builder.WriteString(rolesFilter)
-			args = append(args, params...)
-			if len(toCheck) == 1 {
-				builder.WriteString(" AND action = ?")
-				args = append(args, toCheck[0])
-			} else {
-				builder.WriteString(" AND action IN (?" + strings.Repeat(", ?", len(toCheck)-1) + ") GROUP BY role_id, scope HAVING COUNT(action) = ?")
-				args = append(args, toCheck...)
-				args = append(args, len(toCheck))
-			}
-			builder.WriteString(") AND dashboard.is_folder)")
+			folderUIDs, folderArgs := permissionScopesQuery("SELECT substr(scope, 13) FROM permission WHERE scope LIKE 'folders:uid:%'", rolesFilter, params, toCheck)
+			folderFilter, folderArgs := f.folderUIDFilter("dashboard.uid", folderUIDs, folderArgs)
+			builder.WriteString("(" + folderFilter + " AND dashboard.is_folder)")
+			args = append(args, folderArgs...)
 		} else {
 			builder.WriteString("dashboard.is_folder")
 		}
@@ -200,6 +193,41 @@ func (f AccessControlDashboardPermissionFilter) Where() (string, []interface{})
 	return builder.String(), args
 }
 
+// permissionScopesQuery returns the query selecting the scopes that grant all the actions to check.
+func permissionScopesQuery(selectScopes, rolesFilter string, params []interface{}, toCheck []interface{}) (string, []interface{}) {
+	var args []interface{}
+	builder := strings.Builder{}
+	builder.WriteString(selectScopes)
+	builder.WriteString(rolesFilter)
+	args = append(args, params...)
+	if len(toCheck) == 1 {
+		builder.WriteString(" AND action = ?")
+		args = append(args, toCheck[0])
+	} else {
+		builder.WriteString(" AND action IN (?" + strings.Repeat(", ?", len(toCheck)-1) + ") GROUP BY role_id, scope HAVING COUNT(action) = ?")
+		args = append(args, toCheck...)
+		args = append(args, len(toCheck))
+	}
+	return builder.String(), args
+}
+
+// folderUIDFilter returns the condition matching the column against the folder UIDs selected by the query and, depending on the mode, their descendants.
+func (f AccessControlDashboardPermissionFilter) folderUIDFilter(column string, folderUIDs string, args []interface{}) (string, []interface{}) {
+	switch f.nestedFoldersMode {
+	case NestedFoldersRecursive:
+		descendants := "WITH RECURSIVE RecQry AS (" +
+			"SELECT uid, org_id FROM folder WHERE org_id = ? AND uid IN (" + folderUIDs + ") " +
+			"UNION ALL SELECT f.uid, f.org_id FROM folder f INNER JOIN RecQry r ON f.parent_uid = r.uid AND f.org_id = r.org_id" +
+			") SELECT uid FROM RecQry"
+		return "(" + column + " IN (" + folderUIDs + ") OR " + column + " IN (" + descendants + "))", append(append(args, f.user.OrgID), args...)
+	case NestedFoldersClosure:
+		descendants := "SELECT descendant_uid FROM folder_closure WHERE org_id = ? AND ancestor_uid IN (" + folderUIDs + ")"
+		return "(" + column + " IN (" + folderUIDs + ") OR " + column + " IN (" + descendants + "))", append(append(args, f.user.OrgID), args...)
+	default:
+		return column + " IN (" + folderUIDs + ")", args
+	}
+}
+
 func actionsToCheck(actions []string, permissions map[string][]string, wildcards ...accesscontrol.Wildcards) []interface{} {
 	toCheck := make([]interface{}, 0, len(actions))
 
@@ -145,6 +145,77 @@ func TestIntegration_DashboardPermissionFilter(t *testing.T) {
 	}
 }
 
+func TestIntegration_DashboardNestedFoldersPermissionFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	permissionsToTest := []accesscontrol.Permission{
+		{Action: dashboards.ActionFoldersRead, Scope: "folders:uid:1"},
+		{Action: dashboards.ActionDashboardsRead, Scope: "folders:uid:1"},
+	}
+
+	testCases := []struct {
+		desc           string
+		mode           permissions.NestedFoldersMode
+		queryType      string
+		expectedResult int
+	}{
+		{desc: "Should only return the folder without nested folders", mode: permissions.NestedFoldersDisabled, queryType: searchstore.TypeFolder, expectedResult: 1},
+		{desc: "Should return the folder and its descendants with a recursive query", mode: permissions.NestedFoldersRecursive, queryType: searchstore.TypeFolder, expectedResult: 3},
+		{desc: "Should return the folder and its descendants with the closure table", mode: permissions.NestedFoldersClosure, queryType: searchstore.TypeFolder, expectedResult: 3},
+		{desc: "Should only return the dashboards of the folder without nested folders", mode: permissions.NestedFoldersDisabled, queryType: searchstore.TypeDashboard, expectedResult: 1},
+		{desc: "Should return the dashboards of the subfolders with a recursive query", mode: permissions.NestedFoldersRecursive, queryType: searchstore.TypeDashboard, expectedResult: 3},
+		{desc: "Should return the dashboards of the subfolders with the closure table", mode: permissions.NestedFoldersClosure, queryType: searchstore.TypeDashboard, expectedResult: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			store := setupTest(t, 4, 4, permissionsToTest)
+			if tc.mode == permissions.NestedFoldersRecursive {
+				if supported, err := store.RecursiveQueriesAreSupported(); err != nil || !supported {
+					t.Skip("recursive queries are not supported")
+				}
+			}
+			// folders 1 > 2 > 3, folder 4 is not nested
+			err := store.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+				for _, f := range []struct{ uid, parent string }{{"1", ""}, {"2", "1"}, {"3", "2"}, {"4", ""}} {
+					var parentUID interface{}
+					if f.parent != "" {
+						parentUID = f.parent
+					}
+					if _, err := sess.Exec("INSERT INTO folder (org_id, uid, parent_uid, title, created, updated) VALUES (1, ?, ?, ?, ?, ?)", f.uid, parentUID, f.uid, time.Now(), time.Now()); err != nil {
+						return err
+					}
+				}
+				for _, c := range []struct {
+					ancestor, descendant string
+					depth                int
+				}{{"1", "1", 0}, {"2", "2", 0}, {"3", "3", 0}, {"4", "4", 0}, {"1", "2", 1}, {"2", "3", 1}, {"1", "3", 2}} {
+					if _, err := sess.Exec("INSERT INTO folder_closure (org_id, ancestor_uid, descendant_uid, depth) VALUES (1, ?, ?, ?)", c.ancestor, c.descendant, c.depth); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			require.NoError(t, err)
+
+			usr := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleViewer, Permissions: map[int64]map[string][]string{1: accesscontrol.GroupScopesByAction(permissionsToTest)}}
+			filter := permissions.NewAccessControlDashboardPermissionFilter(usr, dashboards.PERMISSION_VIEW, tc.queryType).WithNestedFolders(tc.mode)
+
+			var result int
+			err = store.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
+				q, params := filter.Where()
+				_, err := sess.SQL("SELECT COUNT(*) FROM dashboard WHERE "+q, params...).Get(&result)
+				return err
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
 func setupTest(t *testing.T, numFolders, numDashboards int, permissions []accesscontrol.Permission) db.DB {
 	store := db.InitTestDB(t)
 	err := store.WithDbSession(context.Background(), func(sess *sqlstore.DBSession) error {
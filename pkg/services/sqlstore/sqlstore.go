@@ -2,6 +2,7 @@ package sqlstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/VividCortex/mysqlerr"
 	"github.com/dlmiddlecote/sqlstats"
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -52,6 +54,9 @@ type SQLStore struct {
 	skipEnsureDefaultOrgAndUser bool
 	migrations                  registry.DatabaseMigrator
 	tracer                      tracing.Tracer
+
+	recursiveQueriesMu           sync.Mutex
+	recursiveQueriesAreSupported *bool
 }
 
 func ProvideService(cfg *setting.Cfg, cacheService *localcache.CacheService, migrations registry.DatabaseMigrator, bus bus.Bus, tracer tracing.Tracer) (*SQLStore, error) {
@@ -134,6 +139,7 @@ func (ss *SQLStore) Migrate(isDatabaseLockingEnabled bool) error {
 	}
 
 	migrator := migrator.NewMigrator(ss.engine, ss.Cfg)
+	migrator.OnlineLockTimeout = ss.dbCfg.OnlineMigrationLockTimeout
 	ss.migrations.AddMigration(migrator)
 
 	return migrator.Start(isDatabaseLockingEnabled, ss.dbCfg.MigrationLockAttemptTimeout)
@@ -195,6 +201,47 @@ func (ss *SQLStore) GetSqlxSession() *session.SessionDB {
 	return ss.sqlxsession
 }
 
+// RecursiveQueriesAreSupported returns whether the database supports recursive common table expressions.
+// Only MySQL before 8.0 does not support them; the result of the first successful check is cached.
+func (ss *SQLStore) RecursiveQueriesAreSupported() (bool, error) {
+	ss.recursiveQueriesMu.Lock()
+	defer ss.recursiveQueriesMu.Unlock()
+	if ss.recursiveQueriesAreSupported != nil {
+		return *ss.recursiveQueriesAreSupported, nil
+	}
+
+	recursiveQueriesAreSupported := func() (bool, error) {
+		var result []int
+		if err := ss.WithDbSession(context.Background(), func(sess *DBSession) error {
+			recQry := `WITH RECURSIVE cte (n) AS
+			(
+			SELECT 1
+			UNION ALL
+			SELECT n + 1 FROM cte WHERE n < 2
+			)
+			SELECT * FROM cte;
+		`
+			return sess.SQL(recQry).Find(&result)
+		}); err != nil {
+			var driverErr *mysql.MySQLError
+			if errors.As(err, &driverErr) {
+				if driverErr.Number == mysqlerr.ER_PARSE_ERROR {
+					return false, nil
+				}
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	supported, err := recursiveQueriesAreSupported()
+	if err != nil {
+		return false, err
+	}
+	ss.recursiveQueriesAreSupported = &supported
+	return supported, nil
+}
+
 func (ss *SQLStore) ensureMainOrgAndAdminUser(test bool) error {
 	ctx := context.Background()
 	err := ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
@@ -470,6 +517,7 @@ func (ss *SQLStore) readConfig() error {
 	ss.dbCfg.WALEnabled = sec.Key("wal").MustBool(false)
 	ss.dbCfg.SkipMigrations = sec.Key("skip_migrations").MustBool()
 	ss.dbCfg.MigrationLockAttemptTimeout = sec.Key("locking_attempt_timeout_sec").MustInt()
+	ss.dbCfg.OnlineMigrationLockTimeout = sec.Key("online_migration_lock_timeout").MustDuration(10 * time.Second)
 
 	ss.dbCfg.QueryRetries = sec.Key("query_retries").MustInt()
 	ss.dbCfg.TransactionRetries = sec.Key("transaction_retries").MustInt(5)
@@ -693,6 +741,7 @@ type DatabaseConfig struct {
 	UrlQueryParams              map[string][]string
 	SkipMigrations              bool
 	MigrationLockAttemptTimeout int
+	OnlineMigrationLockTimeout  time.Duration
 	// SQLite only
 	QueryRetries int
 	// SQLite only
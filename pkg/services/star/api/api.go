@@ -2,28 +2,37 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/star"
 	"github.com/grafana/grafana/pkg/web"
 )
 
 type API struct {
-	starService      star.Service
-	dashboardService dashboards.DashboardService
+	starService       star.Service
+	dashboardService  dashboards.DashboardService
+	folderService     folder.Service
+	dataSourceService datasources.DataSourceService
 }
 
 func ProvideApi(
 	starService star.Service,
 	dashboardService dashboards.DashboardService,
+	folderService folder.Service,
+	dataSourceService datasources.DataSourceService,
 ) *API {
 	api := &API{
-		starService:      starService,
-		dashboardService: dashboardService,
+		starService:       starService,
+		dashboardService:  dashboardService,
+		folderService:     folderService,
+		dataSourceService: dataSourceService,
 	}
 	return api
 }
@@ -204,6 +213,228 @@ func (api *API) UnstarDashboardByUID(c *contextmodel.ReqContext) response.Respon
 	return response.Success("Dashboard unstarred")
 }
 
+// swagger:route GET /stars stars listStars
+//
+// List the stars of the signed in user.
+//
+// Lists the starred dashboards, folders and datasources of the signed in user in the current organization,
+// the kind query parameter restricts the listing to one kind of resource.
+//
+// Responses:
+// 200: listStarsResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 500: internalServerError
+func (api *API) ListStars(c *contextmodel.ReqContext) response.Response {
+	kind := star.Kind(c.Query("kind"))
+	if kind != "" && kind != star.KindDashboard && !kind.IsResourceKind() {
+		return response.Error(http.StatusBadRequest, "Invalid kind, expected one of dashboard, folder or datasource", nil)
+	}
+
+	result := make([]StarDTO, 0)
+	if kind == "" || kind == star.KindDashboard {
+		userStars, err := api.starService.GetByUser(c.Req.Context(), &star.GetUserStarsQuery{UserID: c.SignedInUser.UserID})
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get user stars", err)
+		}
+
+		if len(userStars.UserStars) > 0 {
+			ids := make([]int64, 0, len(userStars.UserStars))
+			for id := range userStars.UserStars {
+				ids = append(ids, id)
+			}
+			starredDashboards, err := api.dashboardService.GetDashboards(c.Req.Context(), &dashboards.GetDashboardsQuery{DashboardIDs: ids, OrgID: c.OrgID})
+			if err != nil {
+				return response.ErrOrFallback(http.StatusInternalServerError, "Failed to fetch dashboards", err)
+			}
+			for _, dash := range starredDashboards {
+				result = append(result, StarDTO{Kind: star.KindDashboard, UID: dash.UID})
+			}
+		}
+	}
+
+	if kind != star.KindDashboard {
+		resourceStars, err := api.starService.GetResourcesByUser(c.Req.Context(), &star.GetUserResourceStarsQuery{
+			UserID: c.SignedInUser.UserID,
+			OrgID:  c.OrgID,
+			Kind:   kind,
+		})
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to get user stars", err)
+		}
+		for _, s := range resourceStars {
+			result = append(result, StarDTO{Kind: s.Kind, UID: s.UID})
+		}
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /user/stars/folder/uid/{folder_uid} signed_in_user starFolderByUID
+//
+// Star a folder.
+//
+// Stars the given folder for the actual user.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *API) StarFolderByUID(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if uid == "" {
+		return response.Error(http.StatusBadRequest, "Invalid folder UID", nil)
+	}
+
+	if _, err := api.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{UID: &uid, OrgID: c.OrgID, SignedInUser: c.SignedInUser}); err != nil {
+		return response.ErrOrFallback(http.StatusNotFound, "Folder not found", err)
+	}
+
+	cmd := star.StarResourceCommand{UserID: c.UserID, OrgID: c.OrgID, Kind: star.KindFolder, UID: uid}
+	if err := api.starService.AddResource(c.Req.Context(), &cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to star folder", err)
+	}
+
+	return response.Success("Folder starred!")
+}
+
+// swagger:route DELETE /user/stars/folder/uid/{folder_uid} signed_in_user unstarFolderByUID
+//
+// Unstar a folder.
+//
+// Deletes the starring of the given folder for the actual user.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *API) UnstarFolderByUID(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if uid == "" {
+		return response.Error(http.StatusBadRequest, "Invalid folder UID", nil)
+	}
+
+	cmd := star.UnstarResourceCommand{UserID: c.UserID, OrgID: c.OrgID, Kind: star.KindFolder, UID: uid}
+	if err := api.starService.DeleteResource(c.Req.Context(), &cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to unstar folder", err)
+	}
+
+	return response.Success("Folder unstarred")
+}
+
+// swagger:route POST /user/stars/datasource/uid/{datasource_uid} signed_in_user starDatasourceByUID
+//
+// Star a datasource.
+//
+// Stars the given datasource for the actual user.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (api *API) StarDatasourceByUID(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if uid == "" {
+		return response.Error(http.StatusBadRequest, "Invalid datasource UID", nil)
+	}
+
+	if _, err := api.dataSourceService.GetDataSource(c.Req.Context(), &datasources.GetDataSourceQuery{UID: uid, OrgID: c.OrgID}); err != nil {
+		if errors.Is(err, datasources.ErrDataSourceNotFound) {
+			return response.Error(http.StatusNotFound, "Data source not found", nil)
+		}
+		return response.Error(http.StatusInternalServerError, "Failed to query datasource", err)
+	}
+
+	cmd := star.StarResourceCommand{UserID: c.UserID, OrgID: c.OrgID, Kind: star.KindDatasource, UID: uid}
+	if err := api.starService.AddResource(c.Req.Context(), &cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to star datasource", err)
+	}
+
+	return response.Success("Datasource starred!")
+}
+
+// swagger:route DELETE /user/stars/datasource/uid/{datasource_uid} signed_in_user unstarDatasourceByUID
+//
+// Unstar a datasource.
+//
+// Deletes the starring of the given datasource for the actual user.
+//
+// Responses:
+// 200: okResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (api *API) UnstarDatasourceByUID(c *contextmodel.ReqContext) response.Response {
+	uid := web.Params(c.Req)[":uid"]
+	if uid == "" {
+		return response.Error(http.StatusBadRequest, "Invalid datasource UID", nil)
+	}
+
+	cmd := star.UnstarResourceCommand{UserID: c.UserID, OrgID: c.OrgID, Kind: star.KindDatasource, UID: uid}
+	if err := api.starService.DeleteResource(c.Req.Context(), &cmd); err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to unstar datasource", err)
+	}
+
+	return response.Success("Datasource unstarred")
+}
+
+// StarDTO is a starred resource in the listing of the stars of a user.
+type StarDTO struct {
+	Kind star.Kind `json:"kind"`
+	UID  string    `json:"uid"`
+}
+
+// swagger:parameters listStars
+type ListStarsParams struct {
+	// in:query
+	// required:false
+	// enum: dashboard,folder,datasource
+	Kind string `json:"kind"`
+}
+
+// swagger:response listStarsResponse
+type ListStarsResponse struct {
+	// in:body
+	Body []StarDTO `json:"body"`
+}
+
+// swagger:parameters starFolderByUID
+type StarFolderByUIDParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters unstarFolderByUID
+type UnstarFolderByUIDParams struct {
+	// in:path
+	// required:true
+	FolderUID string `json:"folder_uid"`
+}
+
+// swagger:parameters starDatasourceByUID
+type StarDatasourceByUIDParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"datasource_uid"`
+}
+
+// swagger:parameters unstarDatasourceByUID
+type UnstarDatasourceByUIDParams struct {
+	// in:path
+	// required:true
+	DatasourceUID string `json:"datasource_uid"`
+}
+
 // swagger:parameters starDashboard
 type StarDashboardParams struct {
 	// in:path
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/star"
+	"github.com/grafana/grafana/pkg/services/star/startest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestListStars(t *testing.T) {
+	starService := startest.NewStarServiceFake()
+	starService.ExpectedUserStars = &star.GetUserStarsResult{UserStars: map[int64]bool{1: true}}
+	starService.ExpectedResources = []*star.ResourceStar{{OrgID: 1, Kind: star.KindDatasource, UID: "datasource"}}
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboards", mock.Anything, mock.Anything).Return([]*dashboards.Dashboard{{ID: 1, UID: "dashboard"}}, nil).Maybe()
+
+	api := ProvideApi(starService, dashboardService, nil, nil)
+	routeRegister := routing.NewRouteRegister()
+	routeRegister.Get("/api/stars", routing.Wrap(api.ListStars))
+	s := webtest.NewServer(t, routeRegister)
+
+	list := func(t *testing.T, target string) (int, []StarDTO) {
+		t.Helper()
+		req := webtest.RequestWithSignedInUser(s.NewGetRequest(target), &user.SignedInUser{UserID: 1, OrgID: 1})
+		resp, err := s.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, resp.Body.Close()) }()
+
+		var stars []StarDTO
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&stars))
+		}
+		return resp.StatusCode, stars
+	}
+
+	t.Run("lists the stars of all kinds", func(t *testing.T) {
+		code, stars := list(t, "/api/stars")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []StarDTO{
+			{Kind: star.KindDashboard, UID: "dashboard"},
+			{Kind: star.KindDatasource, UID: "datasource"},
+		}, stars)
+	})
+
+	t.Run("lists the stars of one kind", func(t *testing.T) {
+		code, stars := list(t, "/api/stars?kind=dashboard")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, []StarDTO{{Kind: star.KindDashboard, UID: "dashboard"}}, stars)
+	})
+
+	t.Run("rejects unknown kinds", func(t *testing.T) {
+		code, _ := list(t, "/api/stars?kind=playlist")
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
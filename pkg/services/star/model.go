@@ -1,6 +1,9 @@
 package star
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var ErrCommandValidationFailed = errors.New("command missing required fields")
 
@@ -10,6 +13,31 @@ type Star struct {
 	DashboardID int64 `xorm:"dashboard_id" db:"dashboard_id"`
 }
 
+// Kind is the kind of resource a star is for.
+type Kind string
+
+const (
+	KindDashboard  Kind = "dashboard"
+	KindFolder     Kind = "folder"
+	KindDatasource Kind = "datasource"
+)
+
+// IsResourceKind returns true for the kinds of resources stored as ResourceStar,
+// dashboards have their own table.
+func (k Kind) IsResourceKind() bool {
+	return k == KindFolder || k == KindDatasource
+}
+
+// ResourceStar is a star for a resource that is not a dashboard, identified by its UID.
+type ResourceStar struct {
+	ID      int64     `xorm:"pk autoincr 'id'" db:"id"`
+	UserID  int64     `xorm:"user_id" db:"user_id"`
+	OrgID   int64     `xorm:"org_id" db:"org_id"`
+	Kind    Kind      `xorm:"kind" db:"kind"`
+	UID     string    `xorm:"uid" db:"uid"`
+	Created time.Time `xorm:"created" db:"created"`
+}
+
 // ----------------------
 // COMMANDS
 
@@ -37,6 +65,34 @@ func (cmd *UnstarDashboardCommand) Validate() error {
 	return nil
 }
 
+type StarResourceCommand struct {
+	UserID int64
+	OrgID  int64
+	Kind   Kind
+	UID    string
+}
+
+func (cmd *StarResourceCommand) Validate() error {
+	if cmd.UserID == 0 || cmd.OrgID == 0 || cmd.UID == "" || !cmd.Kind.IsResourceKind() {
+		return ErrCommandValidationFailed
+	}
+	return nil
+}
+
+type UnstarResourceCommand struct {
+	UserID int64
+	OrgID  int64
+	Kind   Kind
+	UID    string
+}
+
+func (cmd *UnstarResourceCommand) Validate() error {
+	if cmd.UserID == 0 || cmd.OrgID == 0 || cmd.UID == "" || !cmd.Kind.IsResourceKind() {
+		return ErrCommandValidationFailed
+	}
+	return nil
+}
+
 // ---------------------
 // QUERIES
 
@@ -52,3 +108,11 @@ type IsStarredByUserQuery struct {
 type GetUserStarsResult struct {
 	UserStars map[int64]bool
 }
+
+// GetUserResourceStarsQuery lists the stars of a user in an organization,
+// only the stars of the given kind are listed when it is set.
+type GetUserResourceStarsQuery struct {
+	UserID int64
+	OrgID  int64
+	Kind   Kind
+}
@@ -10,4 +10,8 @@ type Service interface {
 	DeleteByUser(context.Context, int64) error
 	IsStarredByUser(context.Context, *IsStarredByUserQuery) (bool, error)
 	GetByUser(context.Context, *GetUserStarsQuery) (*GetUserStarsResult, error)
+	// AddResource stars a folder or a datasource, starring a resource twice is a no-op.
+	AddResource(context.Context, *StarResourceCommand) error
+	DeleteResource(context.Context, *UnstarResourceCommand) error
+	GetResourcesByUser(context.Context, *GetUserResourceStarsQuery) ([]*ResourceStar, error)
 }
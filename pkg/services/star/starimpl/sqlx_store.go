@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/grafana/grafana/pkg/services/sqlstore/session"
 	"github.com/grafana/grafana/pkg/services/star"
@@ -43,8 +44,13 @@ func (s *sqlxStore) Delete(ctx context.Context, cmd *star.UnstarDashboardCommand
 }
 
 func (s *sqlxStore) DeleteByUser(ctx context.Context, userID int64) error {
-	_, err := s.sess.Exec(ctx, "DELETE FROM star WHERE user_id = ?", userID)
-	return err
+	return s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM star WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, "DELETE FROM resource_star WHERE user_id = ?", userID)
+		return err
+	})
 }
 
 func (s *sqlxStore) List(ctx context.Context, query *star.GetUserStarsQuery) (*star.GetUserStarsResult, error) {
@@ -60,3 +66,44 @@ func (s *sqlxStore) List(ctx context.Context, query *star.GetUserStarsQuery) (*s
 
 	return &star.GetUserStarsResult{UserStars: userStars}, err
 }
+
+func (s *sqlxStore) InsertResource(ctx context.Context, cmd *star.StarResourceCommand) error {
+	return s.sess.WithTransaction(ctx, func(tx *session.SessionTx) error {
+		var count int64
+		err := tx.Get(ctx, &count, "SELECT COUNT(*) FROM resource_star WHERE user_id=? AND org_id=? AND kind=? AND uid=?", cmd.UserID, cmd.OrgID, cmd.Kind, cmd.UID)
+		if err != nil || count > 0 {
+			return err
+		}
+
+		entity := star.ResourceStar{
+			UserID:  cmd.UserID,
+			OrgID:   cmd.OrgID,
+			Kind:    cmd.Kind,
+			UID:     cmd.UID,
+			Created: time.Now(),
+		}
+		_, err = tx.NamedExec(ctx, `INSERT INTO resource_star (user_id, org_id, kind, uid, created) VALUES (:user_id, :org_id, :kind, :uid, :created)`, entity)
+		return err
+	})
+}
+
+func (s *sqlxStore) DeleteResource(ctx context.Context, cmd *star.UnstarResourceCommand) error {
+	_, err := s.sess.Exec(ctx, "DELETE FROM resource_star WHERE user_id=? AND org_id=? AND kind=? AND uid=?", cmd.UserID, cmd.OrgID, cmd.Kind, cmd.UID)
+	return err
+}
+
+func (s *sqlxStore) ListResources(ctx context.Context, query *star.GetUserResourceStarsQuery) ([]*star.ResourceStar, error) {
+	rawSQL := "SELECT * FROM resource_star WHERE user_id=? AND org_id=?"
+	args := []interface{}{query.UserID, query.OrgID}
+	if query.Kind != "" {
+		rawSQL += " AND kind=?"
+		args = append(args, query.Kind)
+	}
+	rawSQL += " ORDER BY id ASC"
+
+	stars := make([]*star.ResourceStar, 0)
+	if err := s.sess.Select(ctx, &stars, rawSQL, args...); err != nil {
+		return nil, err
+	}
+	return stars, nil
+}
@@ -13,4 +13,7 @@ func TestIntegrationSQLxUserStarsDataAccess(t *testing.T) {
 	testIntegrationUserStarsDataAccess(t, func(ss db.DB) store {
 		return &sqlxStore{sess: ss.GetSqlxSession()}
 	})
+	testIntegrationUserResourceStarsDataAccess(t, func(ss db.DB) store {
+		return &sqlxStore{sess: ss.GetSqlxSession()}
+	})
 }
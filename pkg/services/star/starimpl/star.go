@@ -53,3 +53,21 @@ func (s *Service) GetByUser(ctx context.Context, cmd *star.GetUserStarsQuery) (*
 func (s *Service) DeleteByUser(ctx context.Context, userID int64) error {
 	return s.store.DeleteByUser(ctx, userID)
 }
+
+func (s *Service) AddResource(ctx context.Context, cmd *star.StarResourceCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	return s.store.InsertResource(ctx, cmd)
+}
+
+func (s *Service) DeleteResource(ctx context.Context, cmd *star.UnstarResourceCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	return s.store.DeleteResource(ctx, cmd)
+}
+
+func (s *Service) GetResourcesByUser(ctx context.Context, query *star.GetUserResourceStarsQuery) ([]*star.ResourceStar, error) {
+	return s.store.ListResources(ctx, query)
+}
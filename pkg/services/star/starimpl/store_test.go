@@ -89,3 +89,61 @@ func testIntegrationUserStarsDataAccess(t *testing.T, fn getStore) {
 		})
 	})
 }
+
+func testIntegrationUserResourceStarsDataAccess(t *testing.T, fn getStore) {
+	t.Helper()
+
+	t.Run("Testing User Resource Stars Data Access", func(t *testing.T) {
+		ss := db.InitTestDB(t)
+		starStore := fn(ss)
+		ctx := context.Background()
+
+		folderStar := star.StarResourceCommand{UserID: 12, OrgID: 1, Kind: star.KindFolder, UID: "folder"}
+		require.NoError(t, starStore.InsertResource(ctx, &folderStar))
+		require.NoError(t, starStore.InsertResource(ctx, &star.StarResourceCommand{UserID: 12, OrgID: 1, Kind: star.KindDatasource, UID: "datasource"}))
+		require.NoError(t, starStore.InsertResource(ctx, &star.StarResourceCommand{UserID: 12, OrgID: 2, Kind: star.KindDatasource, UID: "other-org"}))
+		require.NoError(t, starStore.InsertResource(ctx, &star.StarResourceCommand{UserID: 11, OrgID: 1, Kind: star.KindFolder, UID: "folder"}))
+
+		t.Run("InsertResource should ignore stars that already exist", func(t *testing.T) {
+			require.NoError(t, starStore.InsertResource(ctx, &folderStar))
+		})
+
+		t.Run("ListResources should return the stars of the user in the organization", func(t *testing.T) {
+			stars, err := starStore.ListResources(ctx, &star.GetUserResourceStarsQuery{UserID: 12, OrgID: 1})
+			require.NoError(t, err)
+			require.Len(t, stars, 2)
+			require.Equal(t, star.KindFolder, stars[0].Kind)
+			require.Equal(t, "folder", stars[0].UID)
+			require.Equal(t, star.KindDatasource, stars[1].Kind)
+			require.Equal(t, "datasource", stars[1].UID)
+		})
+
+		t.Run("ListResources should filter by kind", func(t *testing.T) {
+			stars, err := starStore.ListResources(ctx, &star.GetUserResourceStarsQuery{UserID: 12, OrgID: 1, Kind: star.KindDatasource})
+			require.NoError(t, err)
+			require.Len(t, stars, 1)
+			require.Equal(t, "datasource", stars[0].UID)
+		})
+
+		t.Run("DeleteResource should remove the star", func(t *testing.T) {
+			err := starStore.DeleteResource(ctx, &star.UnstarResourceCommand{UserID: 12, OrgID: 1, Kind: star.KindFolder, UID: "folder"})
+			require.NoError(t, err)
+			stars, err := starStore.ListResources(ctx, &star.GetUserResourceStarsQuery{UserID: 12, OrgID: 1, Kind: star.KindFolder})
+			require.NoError(t, err)
+			require.Len(t, stars, 0)
+			stars, err = starStore.ListResources(ctx, &star.GetUserResourceStarsQuery{UserID: 11, OrgID: 1, Kind: star.KindFolder})
+			require.NoError(t, err)
+			require.Len(t, stars, 1)
+		})
+
+		t.Run("DeleteByUser should remove the resource stars of the user", func(t *testing.T) {
+			require.NoError(t, starStore.DeleteByUser(ctx, 12))
+			stars, err := starStore.ListResources(ctx, &star.GetUserResourceStarsQuery{UserID: 12, OrgID: 2})
+			require.NoError(t, err)
+			require.Len(t, stars, 0)
+			stars, err = starStore.ListResources(ctx, &star.GetUserResourceStarsQuery{UserID: 11, OrgID: 1})
+			require.NoError(t, err)
+			require.Len(t, stars, 1)
+		})
+	})
+}
@@ -2,6 +2,7 @@ package starimpl
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/star"
@@ -49,8 +50,10 @@ func (s *sqlStore) Delete(ctx context.Context, cmd *star.UnstarDashboardCommand)
 
 func (s *sqlStore) DeleteByUser(ctx context.Context, userID int64) error {
 	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
-		var rawSQL = "DELETE FROM star WHERE user_id = ?"
-		_, err := sess.Exec(rawSQL, userID)
+		if _, err := sess.Exec("DELETE FROM star WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+		_, err := sess.Exec("DELETE FROM resource_star WHERE user_id = ?", userID)
 		return err
 	})
 }
@@ -67,3 +70,40 @@ func (s *sqlStore) List(ctx context.Context, query *star.GetUserStarsQuery) (*st
 	})
 	return &star.GetUserStarsResult{UserStars: userStars}, err
 }
+
+func (s *sqlStore) InsertResource(ctx context.Context, cmd *star.StarResourceCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("user_id=? AND org_id=? AND kind=? AND uid=?", cmd.UserID, cmd.OrgID, cmd.Kind, cmd.UID).Exist(&star.ResourceStar{})
+		if err != nil || exists {
+			return err
+		}
+
+		_, err = sess.Insert(&star.ResourceStar{
+			UserID:  cmd.UserID,
+			OrgID:   cmd.OrgID,
+			Kind:    cmd.Kind,
+			UID:     cmd.UID,
+			Created: time.Now(),
+		})
+		return err
+	})
+}
+
+func (s *sqlStore) DeleteResource(ctx context.Context, cmd *star.UnstarResourceCommand) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM resource_star WHERE user_id=? AND org_id=? AND kind=? AND uid=?", cmd.UserID, cmd.OrgID, cmd.Kind, cmd.UID)
+		return err
+	})
+}
+
+func (s *sqlStore) ListResources(ctx context.Context, query *star.GetUserResourceStarsQuery) ([]*star.ResourceStar, error) {
+	stars := make([]*star.ResourceStar, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("user_id=? AND org_id=?", query.UserID, query.OrgID)
+		if query.Kind != "" {
+			q = q.And("kind=?", query.Kind)
+		}
+		return q.Asc("id").Find(&stars)
+	})
+	return stars, err
+}
@@ -13,4 +13,7 @@ func TestIntegrationXormUserStarsDataAccess(t *testing.T) {
 	testIntegrationUserStarsDataAccess(t, func(ss db.DB) store {
 		return &sqlStore{db: ss}
 	})
+	testIntegrationUserResourceStarsDataAccess(t, func(ss db.DB) store {
+		return &sqlStore{db: ss}
+	})
 }
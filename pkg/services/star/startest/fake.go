@@ -10,6 +10,7 @@ type FakeStarService struct {
 	ExpectedStars     *star.Star
 	ExpectedError     error
 	ExpectedUserStars *star.GetUserStarsResult
+	ExpectedResources []*star.ResourceStar
 }
 
 func NewStarServiceFake() *FakeStarService {
@@ -35,3 +36,15 @@ func (f *FakeStarService) DeleteByUser(ctx context.Context, userID int64) error
 func (f *FakeStarService) GetByUser(ctx context.Context, query *star.GetUserStarsQuery) (*star.GetUserStarsResult, error) {
 	return f.ExpectedUserStars, f.ExpectedError
 }
+
+func (f *FakeStarService) AddResource(ctx context.Context, cmd *star.StarResourceCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakeStarService) DeleteResource(ctx context.Context, cmd *star.UnstarResourceCommand) error {
+	return f.ExpectedError
+}
+
+func (f *FakeStarService) GetResourcesByUser(ctx context.Context, query *star.GetUserResourceStarsQuery) ([]*star.ResourceStar, error) {
+	return f.ExpectedResources, f.ExpectedError
+}
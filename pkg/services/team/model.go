@@ -18,6 +18,7 @@ var (
 	ErrNotAllowedToUpdateTeamInDifferentOrg = errors.New("user not allowed to update team in another org")
 
 	ErrTeamMemberAlreadyAdded = errors.New("user is already added to this team")
+	ErrTeamHierarchyCycle     = errors.New("team hierarchy would contain a cycle")
 )
 
 // Team model
@@ -26,6 +27,8 @@ type Team struct {
 	OrgID int64  `json:"orgId" xorm:"org_id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	// ParentID is the ID of the parent team, or 0 for a root team.
+	ParentID int64 `json:"parentId" xorm:"parent_id"`
 
 	Created time.Time `json:"created"`
 	Updated time.Time `json:"updated"`
@@ -52,6 +55,14 @@ type DeleteTeamCommand struct {
 	ID    int64
 }
 
+// SetParentTeamCommand moves a team below another team of the same
+// organization. A ParentID of 0 turns the team into a root team.
+type SetParentTeamCommand struct {
+	OrgID    int64 `json:"-"`
+	TeamID   int64 `json:"-"`
+	ParentID int64 `json:"parentId"`
+}
+
 type GetTeamByIDQuery struct {
 	OrgID        int64
 	ID           int64
@@ -67,6 +78,22 @@ type GetTeamsByUserQuery struct {
 	OrgID        int64
 	UserID       int64 `json:"userId"`
 	SignedInUser *user.SignedInUser
+	// IncludeInherited also returns all ancestors of the teams the user is a
+	// direct member of.
+	IncludeInherited bool
+}
+
+// GetTeamIDsByUserQuery resolves the teams of a user for the permission checks, it is not filtered by the teams the
+// signed in user can read.
+type GetTeamIDsByUserQuery struct {
+	OrgID  int64
+	UserID int64
+}
+
+type GetChildTeamsQuery struct {
+	OrgID        int64
+	TeamID       int64
+	SignedInUser *user.SignedInUser
 }
 
 type SearchTeamsQuery struct {
@@ -85,6 +112,7 @@ type TeamDTO struct {
 	OrgID         int64                     `json:"orgId" xorm:"org_id"`
 	Name          string                    `json:"name"`
 	Email         string                    `json:"email"`
+	ParentID      int64                     `json:"parentId" xorm:"parent_id"`
 	AvatarURL     string                    `json:"avatarUrl"`
 	MemberCount   int64                     `json:"memberCount"`
 	Permission    dashboards.PermissionType `json:"permission"`
@@ -22,6 +22,7 @@ type store interface {
 	Search(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error)
 	GetByID(ctx context.Context, query *team.GetTeamByIDQuery) (*team.TeamDTO, error)
 	GetByUser(ctx context.Context, query *team.GetTeamsByUserQuery) ([]*team.TeamDTO, error)
+	GetIDsByUser(ctx context.Context, query *team.GetTeamIDsByUserQuery) ([]int64, error)
 	AddMember(userID, orgID, teamID int64, isExternal bool, permission dashboards.PermissionType) error
 	UpdateMember(ctx context.Context, cmd *team.UpdateTeamMemberCommand) error
 	IsMember(orgId int64, teamId int64, userId int64) (bool, error)
@@ -29,6 +30,8 @@ type store interface {
 	GetMemberships(ctx context.Context, orgID, userID int64, external bool) ([]*team.TeamMemberDTO, error)
 	GetMembers(ctx context.Context, query *team.GetTeamMembersQuery) ([]*team.TeamMemberDTO, error)
 	IsAdmin(ctx context.Context, query *team.IsAdminOfTeamsQuery) (bool, error)
+	SetParent(ctx context.Context, cmd *team.SetParentTeamCommand) error
+	GetChildren(ctx context.Context, query *team.GetChildTeamsQuery) ([]*team.TeamDTO, error)
 }
 
 type xormStore struct {
@@ -69,7 +72,8 @@ func getTeamSelectSQLBase(db db.DB, filteredUsers []string) string {
 		team.id as id,
 		team.org_id,
 		team.name as name,
-		team.email as email, ` +
+		team.email as email,
+		team.parent_id as parent_id, ` +
 		getTeamMemberCount(db, filteredUsers) +
 		` FROM team as team `
 }
@@ -80,6 +84,7 @@ func getTeamSelectWithPermissionsSQLBase(db db.DB, filteredUsers []string) strin
 		team.org_id,
 		team.name AS name,
 		team.email AS email,
+		team.parent_id AS parent_id,
 		team_member.permission, ` +
 		getTeamMemberCount(db, filteredUsers) +
 		` FROM team AS team
@@ -149,6 +154,8 @@ func (ss *xormStore) Delete(ctx context.Context, cmd *team.DeleteTeamCommand) er
 			"DELETE FROM team WHERE org_id=? and id = ?",
 			"DELETE FROM dashboard_acl WHERE org_id=? and team_id = ?",
 			"DELETE FROM team_role WHERE org_id=? and team_id = ?",
+			"DELETE FROM nav_customization WHERE org_id=? and team_id = ?",
+			"DELETE FROM data_source_team WHERE org_id=? and team_id = ?",
 		}
 
 		for _, sql := range deletes {
@@ -158,6 +165,11 @@ func (ss *xormStore) Delete(ctx context.Context, cmd *team.DeleteTeamCommand) er
 			}
 		}
 
+		// Children of the deleted team become root teams
+		if _, err := sess.Exec("UPDATE team SET parent_id = 0 WHERE org_id=? and parent_id = ?", cmd.OrgID, cmd.ID); err != nil {
+			return err
+		}
+
 		_, err := sess.Exec("DELETE FROM permission WHERE scope=?", ac.Scope("teams", "id", fmt.Sprint(cmd.ID)))
 
 		return err
@@ -348,9 +360,176 @@ func (ss *xormStore) GetByUser(ctx context.Context, query *team.GetTeamsByUserQu
 			params = append(params, acFilter.Args...)
 		}
 
-		err := sess.SQL(sql.String(), params...).Find(&queryResult)
+		if err := sess.SQL(sql.String(), params...).Find(&queryResult); err != nil {
+			return err
+		}
+
+		if !query.IncludeInherited || len(queryResult) == 0 {
+			return nil
+		}
+
+		direct := make([]int64, 0, len(queryResult))
+		for _, t := range queryResult {
+			direct = append(direct, t.ID)
+		}
+
+		ancestors, err := getTeamAncestorIDs(sess, query.OrgID, direct)
+		if err != nil || len(ancestors) == 0 {
+			return err
+		}
+
+		var inherited []*team.TeamDTO
+		sql.Reset()
+		params = []interface{}{query.OrgID}
+		sql.WriteString(getTeamSelectSQLBase(ss.db, []string{}))
+		sql.WriteString(` WHERE team.org_id = ? and team.id IN (?` + strings.Repeat(",?", len(ancestors)-1) + `)`)
+		for _, id := range ancestors {
+			params = append(params, id)
+		}
+
+		if !ac.IsDisabled(ss.cfg) {
+			acFilter, err := ac.Filter(query.SignedInUser, "team.id", "teams:id:", ac.ActionTeamsRead)
+			if err != nil {
+				return err
+			}
+			sql.WriteString(` and` + acFilter.Where)
+			params = append(params, acFilter.Args...)
+		}
+
+		if err := sess.SQL(sql.String(), params...).Find(&inherited); err != nil {
+			return err
+		}
+
+		queryResult = append(queryResult, inherited...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return queryResult, nil
+}
+
+// GetIDsByUser returns the teams the user is a member of, directly or through
+// the team hierarchy
+func (ss *xormStore) GetIDsByUser(ctx context.Context, query *team.GetTeamIDsByUserQuery) ([]int64, error) {
+	ids := make([]int64, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := `SELECT team.id FROM team INNER JOIN team_member on team.id = team_member.team_id WHERE team.org_id = ? and team_member.user_id = ?`
+		if err := sess.SQL(rawSQL, query.OrgID, query.UserID).Find(&ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		ancestors, err := getTeamAncestorIDs(sess, query.OrgID, ids)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, ancestors...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// getTeamAncestorIDs returns the IDs of all ancestors of the given teams that
+// are not part of teamIDs themselves.
+func getTeamAncestorIDs(sess *db.Session, orgID int64, teamIDs []int64) ([]int64, error) {
+	seen := make(map[int64]bool, len(teamIDs))
+	for _, id := range teamIDs {
+		seen[id] = true
+	}
+
+	ancestors := make([]int64, 0)
+	current := teamIDs
+	for len(current) > 0 {
+		params := []interface{}{orgID}
+		for _, id := range current {
+			params = append(params, id)
+		}
+
+		var parents []int64
+		rawSQL := `SELECT parent_id FROM team WHERE org_id = ? and parent_id <> 0 and id IN (?` + strings.Repeat(",?", len(current)-1) + `)`
+		if err := sess.SQL(rawSQL, params...).Find(&parents); err != nil {
+			return nil, err
+		}
+
+		current = current[:0:0]
+		for _, id := range parents {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			ancestors = append(ancestors, id)
+			current = append(current, id)
+		}
+	}
+
+	return ancestors, nil
+}
+
+// SetParent moves a team below another team, refusing changes that would
+// introduce a cycle in the team hierarchy
+func (ss *xormStore) SetParent(ctx context.Context, cmd *team.SetParentTeamCommand) error {
+	return ss.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := teamExists(cmd.OrgID, cmd.TeamID, sess); err != nil {
+			return err
+		}
+
+		if cmd.ParentID != 0 {
+			if cmd.ParentID == cmd.TeamID {
+				return team.ErrTeamHierarchyCycle
+			}
+
+			if _, err := teamExists(cmd.OrgID, cmd.ParentID, sess); err != nil {
+				return err
+			}
+
+			ancestors, err := getTeamAncestorIDs(sess, cmd.OrgID, []int64{cmd.ParentID})
+			if err != nil {
+				return err
+			}
+			for _, id := range ancestors {
+				if id == cmd.TeamID {
+					return team.ErrTeamHierarchyCycle
+				}
+			}
+		}
+
+		_, err := sess.Exec("UPDATE team SET parent_id = ?, updated = ? WHERE org_id=? and id = ?",
+			cmd.ParentID, time.Now(), cmd.OrgID, cmd.TeamID)
 		return err
 	})
+}
+
+// GetChildren returns the direct children of a team
+func (ss *xormStore) GetChildren(ctx context.Context, query *team.GetChildTeamsQuery) ([]*team.TeamDTO, error) {
+	queryResult := make([]*team.TeamDTO, 0)
+	err := ss.db.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := teamExists(query.OrgID, query.TeamID, sess); err != nil {
+			return err
+		}
+
+		var sql bytes.Buffer
+		params := []interface{}{query.OrgID, query.TeamID}
+		sql.WriteString(getTeamSelectSQLBase(ss.db, []string{}))
+		sql.WriteString(` WHERE team.org_id = ? and team.parent_id = ?`)
+
+		if !ac.IsDisabled(ss.cfg) {
+			acFilter, err := ac.Filter(query.SignedInUser, "team.id", "teams:id:", ac.ActionTeamsRead)
+			if err != nil {
+				return err
+			}
+			sql.WriteString(` and` + acFilter.Where)
+			params = append(params, acFilter.Args...)
+		}
+
+		sql.WriteString(` order by team.name asc`)
+		return sess.SQL(sql.String(), params...).Find(&queryResult)
+	})
 	if err != nil {
 		return nil, err
 	}
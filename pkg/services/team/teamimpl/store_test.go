@@ -254,6 +254,68 @@ func TestIntegrationTeamCommandsAndQueries(t *testing.T) {
 				require.Equal(t, queryResult[0].Email, "test2@test.com")
 			})
 
+			t.Run("Should resolve inherited teams through the team hierarchy", func(t *testing.T) {
+				root, err := teamSvc.CreateTeam("hierarchy root", "", testOrgID)
+				require.NoError(t, err)
+				child, err := teamSvc.CreateTeam("hierarchy child", "", testOrgID)
+				require.NoError(t, err)
+				leaf, err := teamSvc.CreateTeam("hierarchy leaf", "", testOrgID)
+				require.NoError(t, err)
+
+				err = teamSvc.SetParentTeam(context.Background(), &team.SetParentTeamCommand{OrgID: testOrgID, TeamID: child.ID, ParentID: root.ID})
+				require.NoError(t, err)
+				err = teamSvc.SetParentTeam(context.Background(), &team.SetParentTeamCommand{OrgID: testOrgID, TeamID: leaf.ID, ParentID: child.ID})
+				require.NoError(t, err)
+				err = teamSvc.AddTeamMember(userIds[3], testOrgID, leaf.ID, false, 0)
+				require.NoError(t, err)
+
+				signedInUser := &user.SignedInUser{
+					OrgID:       testOrgID,
+					Permissions: map[int64]map[string][]string{testOrgID: {ac.ActionTeamsRead: {ac.ScopeTeamsAll}}},
+				}
+
+				direct, err := teamSvc.GetTeamsByUser(context.Background(), &team.GetTeamsByUserQuery{OrgID: testOrgID, UserID: userIds[3], SignedInUser: signedInUser})
+				require.NoError(t, err)
+				require.Len(t, direct, 1)
+				require.Equal(t, child.ID, direct[0].ParentID)
+
+				inherited, err := teamSvc.GetTeamsByUser(context.Background(), &team.GetTeamsByUserQuery{OrgID: testOrgID, UserID: userIds[3], SignedInUser: signedInUser, IncludeInherited: true})
+				require.NoError(t, err)
+				ids := make([]int64, 0, len(inherited))
+				for _, tm := range inherited {
+					ids = append(ids, tm.ID)
+				}
+				require.ElementsMatch(t, []int64{root.ID, child.ID, leaf.ID}, ids)
+
+				// the permission checks resolve the teams regardless of the teams the user can read
+				teamIDs, err := teamSvc.GetTeamIDsByUser(context.Background(), &team.GetTeamIDsByUserQuery{OrgID: testOrgID, UserID: userIds[3]})
+				require.NoError(t, err)
+				require.ElementsMatch(t, []int64{root.ID, child.ID, leaf.ID}, teamIDs)
+
+				children, err := teamSvc.GetChildTeams(context.Background(), &team.GetChildTeamsQuery{OrgID: testOrgID, TeamID: root.ID, SignedInUser: signedInUser})
+				require.NoError(t, err)
+				require.Len(t, children, 1)
+				require.Equal(t, child.ID, children[0].ID)
+
+				t.Run("Should reject cycles", func(t *testing.T) {
+					err := teamSvc.SetParentTeam(context.Background(), &team.SetParentTeamCommand{OrgID: testOrgID, TeamID: root.ID, ParentID: leaf.ID})
+					require.ErrorIs(t, err, team.ErrTeamHierarchyCycle)
+					err = teamSvc.SetParentTeam(context.Background(), &team.SetParentTeamCommand{OrgID: testOrgID, TeamID: root.ID, ParentID: root.ID})
+					require.ErrorIs(t, err, team.ErrTeamHierarchyCycle)
+					err = teamSvc.SetParentTeam(context.Background(), &team.SetParentTeamCommand{OrgID: testOrgID, TeamID: root.ID, ParentID: 10000})
+					require.ErrorIs(t, err, team.ErrTeamNotFound)
+				})
+
+				t.Run("Should detach children when deleting a parent", func(t *testing.T) {
+					err := teamSvc.DeleteTeam(context.Background(), &team.DeleteTeamCommand{OrgID: testOrgID, ID: child.ID})
+					require.NoError(t, err)
+
+					leafResult, err := teamSvc.GetTeamByID(context.Background(), &team.GetTeamByIDQuery{OrgID: testOrgID, ID: leaf.ID})
+					require.NoError(t, err)
+					require.Equal(t, int64(0), leafResult.ParentID)
+				})
+			})
+
 			t.Run("Should be able to remove users from a group", func(t *testing.T) {
 				err = teamSvc.AddTeamMember(userIds[0], testOrgID, team1.ID, false, 0)
 				require.NoError(t, err)
@@ -41,6 +41,10 @@ func (s *Service) GetTeamsByUser(ctx context.Context, query *team.GetTeamsByUser
 	return s.store.GetByUser(ctx, query)
 }
 
+func (s *Service) GetTeamIDsByUser(ctx context.Context, query *team.GetTeamIDsByUserQuery) ([]int64, error) {
+	return s.store.GetIDsByUser(ctx, query)
+}
+
 func (s *Service) AddTeamMember(userID, orgID, teamID int64, isExternal bool, permission dashboards.PermissionType) error {
 	return s.store.AddMember(userID, orgID, teamID, isExternal, permission)
 }
@@ -68,3 +72,11 @@ func (s *Service) GetTeamMembers(ctx context.Context, query *team.GetTeamMembers
 func (s *Service) IsAdminOfTeams(ctx context.Context, query *team.IsAdminOfTeamsQuery) (bool, error) {
 	return s.store.IsAdmin(ctx, query)
 }
+
+func (s *Service) SetParentTeam(ctx context.Context, cmd *team.SetParentTeamCommand) error {
+	return s.store.SetParent(ctx, cmd)
+}
+
+func (s *Service) GetChildTeams(ctx context.Context, query *team.GetChildTeamsQuery) ([]*team.TeamDTO, error) {
+	return s.store.GetChildren(ctx, query)
+}
@@ -8,13 +8,15 @@ import (
 )
 
 type FakeService struct {
-	ExpectedTeam        team.Team
-	ExpectedIsMember    bool
-	ExpectedIsAdmin     bool
-	ExpectedTeamDTO     *team.TeamDTO
-	ExpectedTeamsByUser []*team.TeamDTO
-	ExpectedMembers     []*team.TeamMemberDTO
-	ExpectedError       error
+	ExpectedTeam          team.Team
+	ExpectedIsMember      bool
+	ExpectedIsAdmin       bool
+	ExpectedTeamDTO       *team.TeamDTO
+	ExpectedTeamsByUser   []*team.TeamDTO
+	ExpectedTeamIDsByUser []int64
+	ExpectedChildTeams    []*team.TeamDTO
+	ExpectedMembers       []*team.TeamMemberDTO
+	ExpectedError         error
 }
 
 func NewFakeService() *FakeService {
@@ -45,6 +47,10 @@ func (s *FakeService) GetTeamsByUser(ctx context.Context, query *team.GetTeamsBy
 	return s.ExpectedTeamsByUser, s.ExpectedError
 }
 
+func (s *FakeService) GetTeamIDsByUser(ctx context.Context, query *team.GetTeamIDsByUserQuery) ([]int64, error) {
+	return s.ExpectedTeamIDsByUser, s.ExpectedError
+}
+
 func (s *FakeService) AddTeamMember(userID, orgID, teamID int64, isExternal bool, permission dashboards.PermissionType) error {
 	return s.ExpectedError
 }
@@ -72,3 +78,11 @@ func (s *FakeService) GetTeamMembers(ctx context.Context, query *team.GetTeamMem
 func (s *FakeService) IsAdminOfTeams(ctx context.Context, query *team.IsAdminOfTeamsQuery) (bool, error) {
 	return s.ExpectedIsAdmin, s.ExpectedError
 }
+
+func (s *FakeService) SetParentTeam(ctx context.Context, cmd *team.SetParentTeamCommand) error {
+	return s.ExpectedError
+}
+
+func (s *FakeService) GetChildTeams(ctx context.Context, query *team.GetChildTeamsQuery) ([]*team.TeamDTO, error) {
+	return s.ExpectedChildTeams, s.ExpectedError
+}
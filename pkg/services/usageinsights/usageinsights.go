@@ -0,0 +1,70 @@
+package usageinsights
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrInvalidSort = errutil.NewBase(errutil.StatusBadRequest, "usageinsights.invalid-sort")
+)
+
+// Service records how often dashboards are viewed and data sources are queried,
+// and reports the aggregated numbers.
+type Service interface {
+	// RecordDashboardView counts a view of a dashboard. It does not block on the database.
+	RecordDashboardView(ctx context.Context, orgID int64, dashboardUID string)
+	// RecordQuery counts a query sent to a data source, optionally from a dashboard. It does not block on the database.
+	RecordQuery(ctx context.Context, orgID int64, dashboardUID string, datasourceUID string, failed bool)
+
+	GetDashboardsUsage(ctx context.Context, query GetDashboardsUsageQuery) ([]*DashboardUsage, error)
+	GetDataSourcesUsage(ctx context.Context, query GetDataSourcesUsageQuery) ([]*DataSourceUsage, error)
+	// GetDashboardsUsageByUID returns the usage of the given dashboards keyed by dashboard uid
+	GetDashboardsUsageByUID(ctx context.Context, orgID int64, dashboardUIDs []string, since time.Time) (map[string]*DashboardUsage, error)
+}
+
+type SortField string
+
+const (
+	SortViews      SortField = "views"
+	SortQueries    SortField = "queries"
+	SortErrors     SortField = "errors"
+	SortLastViewed SortField = "lastViewed"
+)
+
+type DashboardUsage struct {
+	UID          string     `json:"uid"`
+	Title        string     `json:"title,omitempty"`
+	Views        int64      `json:"views"`
+	Queries      int64      `json:"queries"`
+	Errors       int64      `json:"errors"`
+	LastViewedAt *time.Time `json:"lastViewedAt,omitempty"`
+}
+
+type DataSourceUsage struct {
+	UID     string `json:"uid"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Queries int64  `json:"queries"`
+	Errors  int64  `json:"errors"`
+}
+
+type GetDashboardsUsageQuery struct {
+	OrgID int64
+	Since time.Time
+	Sort  SortField
+	Desc  bool
+	Limit int64
+	Page  int64
+}
+
+type GetDataSourcesUsageQuery struct {
+	OrgID int64
+	Since time.Time
+	Sort  SortField
+	Desc  bool
+	Limit int64
+	Page  int64
+}
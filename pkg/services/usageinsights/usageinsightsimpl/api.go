@@ -0,0 +1,97 @@
+package usageinsightsimpl
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/usageinsights"
+)
+
+const (
+	defaultPeriodDays = 30
+	defaultLimit      = 100
+	maxLimit          = 5000
+)
+
+func (s *Service) registerAPIEndpoints(routeRegister routing.RouteRegister) {
+	authorize := ac.Middleware(s.accessControl)
+
+	routeRegister.Group("/api/usage", func(usage routing.RouteRegister) {
+		usage.Get("/dashboards", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ActionRead)), routing.Wrap(s.handleGetDashboardsUsage))
+		usage.Get("/datasources", authorize(middleware.ReqOrgAdmin, ac.EvalPermission(ActionRead)), routing.Wrap(s.handleGetDataSourcesUsage))
+	})
+}
+
+// handleGetDashboardsUsage lists all dashboards of the organization with their usage.
+// Sorting by views in ascending order shows the dashboards nobody looks at.
+func (s *Service) handleGetDashboardsUsage(c *contextmodel.ReqContext) response.Response {
+	since, limit, page, resp := s.parsePeriodAndPaging(c)
+	if resp != nil {
+		return resp
+	}
+
+	result, err := s.GetDashboardsUsage(c.Req.Context(), usageinsights.GetDashboardsUsageQuery{
+		OrgID: c.OrgID,
+		Since: since,
+		Sort:  usageinsights.SortField(c.Query("sort")),
+		Desc:  c.Query("order") != "asc",
+		Limit: limit,
+		Page:  page,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get dashboard usage", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) handleGetDataSourcesUsage(c *contextmodel.ReqContext) response.Response {
+	since, limit, page, resp := s.parsePeriodAndPaging(c)
+	if resp != nil {
+		return resp
+	}
+
+	result, err := s.GetDataSourcesUsage(c.Req.Context(), usageinsights.GetDataSourcesUsageQuery{
+		OrgID: c.OrgID,
+		Since: since,
+		Sort:  usageinsights.SortField(c.Query("sort")),
+		Desc:  c.Query("order") != "asc",
+		Limit: limit,
+		Page:  page,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get data source usage", err)
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+func (s *Service) parsePeriodAndPaging(c *contextmodel.ReqContext) (time.Time, int64, int64, response.Response) {
+	days := int64(defaultPeriodDays)
+	if c.Query("days") != "" {
+		days = c.QueryInt64("days")
+	}
+	if days <= 0 {
+		return time.Time{}, 0, 0, response.Error(http.StatusBadRequest, "days must be a positive number", nil)
+	}
+
+	limit := int64(defaultLimit)
+	if c.Query("limit") != "" {
+		limit = c.QueryInt64("limit")
+	}
+	if limit <= 0 || limit > maxLimit {
+		return time.Time{}, 0, 0, response.Error(http.StatusBadRequest, "limit must be between 1 and 5000", nil)
+	}
+
+	page := c.QueryInt64("page")
+	if page < 1 {
+		page = 1
+	}
+
+	return s.now().AddDate(0, 0, -int(days)), limit, page, nil
+}
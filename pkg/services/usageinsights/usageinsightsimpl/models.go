@@ -0,0 +1,21 @@
+package usageinsightsimpl
+
+import (
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+const (
+	ActionRead = "usage.insights:read"
+)
+
+var (
+	usageReaderRole = accesscontrol.RoleDTO{
+		Name:        "fixed:usage.insights:reader",
+		DisplayName: "Usage insights reader",
+		Description: "Read how often dashboards and data sources are used",
+		Group:       "Usage insights",
+		Permissions: []accesscontrol.Permission{
+			{Action: ActionRead},
+		},
+	}
+)
@@ -0,0 +1,233 @@
+package usageinsightsimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/usageinsights"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	defaultFlushInterval = time.Minute
+	cleanupInterval      = time.Hour
+)
+
+var _ usageinsights.Service = (*Service)(nil)
+
+type dashboardKey struct {
+	orgID int64
+	uid   string
+	day   int64
+}
+
+type dataSourceKey struct {
+	orgID int64
+	uid   string
+	day   int64
+}
+
+// Service buffers usage events in memory and periodically rolls them up
+// into daily aggregates in the database.
+type Service struct {
+	store         store
+	accessControl ac.AccessControl
+	lock          *serverlock.ServerLockService
+	log           log.Logger
+	now           func() time.Time
+
+	enabled       bool
+	flushInterval time.Duration
+	retentionDays int
+
+	mutex       sync.Mutex
+	dashboards  map[dashboardKey]*dashboardUsageDelta
+	datasources map[dataSourceKey]*dataSourceUsageDelta
+}
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	accessControl ac.AccessControl,
+	accesscontrolService ac.Service,
+	routeRegister routing.RouteRegister,
+	lock *serverlock.ServerLockService,
+) (*Service, error) {
+	section := cfg.SectionWithEnvOverrides("usage_insights")
+	s := &Service{
+		store:         &sqlStore{db: sql},
+		accessControl: accessControl,
+		lock:          lock,
+		log:           log.New("usageinsights"),
+		now:           time.Now,
+		enabled:       section.Key("enabled").MustBool(true),
+		flushInterval: section.Key("flush_interval").MustDuration(defaultFlushInterval),
+		retentionDays: section.Key("retention_days").MustInt(90),
+		dashboards:    make(map[dashboardKey]*dashboardUsageDelta),
+		datasources:   make(map[dataSourceKey]*dataSourceUsageDelta),
+	}
+
+	if !s.enabled {
+		return s, nil
+	}
+
+	if !accessControl.IsDisabled() {
+		if err := accesscontrolService.DeclareFixedRoles(ac.RoleRegistration{
+			Role:   usageReaderRole,
+			Grants: []string{string(org.RoleAdmin)},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	s.registerAPIEndpoints(routeRegister)
+
+	return s, nil
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	flushTicker := time.NewTicker(s.flushInterval)
+	defer flushTicker.Stop()
+	cleanupTicker := time.NewTicker(cleanupInterval)
+	defer cleanupTicker.Stop()
+
+	for {
+		select {
+		case <-flushTicker.C:
+			s.flush(ctx)
+		case <-cleanupTicker.C:
+			s.cleanup(ctx)
+		case <-ctx.Done():
+			// don't lose the last buffered events on shutdown
+			s.flush(context.Background())
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) RecordDashboardView(ctx context.Context, orgID int64, dashboardUID string) {
+	if !s.enabled || dashboardUID == "" {
+		return
+	}
+
+	now := s.now()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	usage := s.dashboardUsage(orgID, dashboardUID, dayStart(now))
+	usage.Views++
+	usage.LastViewedAt = now.Unix()
+}
+
+func (s *Service) RecordQuery(ctx context.Context, orgID int64, dashboardUID string, datasourceUID string, failed bool) {
+	if !s.enabled {
+		return
+	}
+
+	day := dayStart(s.now())
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if dashboardUID != "" {
+		usage := s.dashboardUsage(orgID, dashboardUID, day)
+		usage.Queries++
+		if failed {
+			usage.Errors++
+		}
+	}
+
+	if datasourceUID != "" {
+		key := dataSourceKey{orgID: orgID, uid: datasourceUID, day: day}
+		usage, ok := s.datasources[key]
+		if !ok {
+			usage = &dataSourceUsageDelta{OrgID: orgID, DataSourceUID: datasourceUID, Day: day}
+			s.datasources[key] = usage
+		}
+		usage.Queries++
+		if failed {
+			usage.Errors++
+		}
+	}
+}
+
+func (s *Service) GetDashboardsUsage(ctx context.Context, query usageinsights.GetDashboardsUsageQuery) ([]*usageinsights.DashboardUsage, error) {
+	return s.store.GetDashboardsUsage(ctx, query)
+}
+
+func (s *Service) GetDataSourcesUsage(ctx context.Context, query usageinsights.GetDataSourcesUsageQuery) ([]*usageinsights.DataSourceUsage, error) {
+	return s.store.GetDataSourcesUsage(ctx, query)
+}
+
+func (s *Service) GetDashboardsUsageByUID(ctx context.Context, orgID int64, dashboardUIDs []string, since time.Time) (map[string]*usageinsights.DashboardUsage, error) {
+	return s.store.GetDashboardsUsageByUID(ctx, orgID, dashboardUIDs, since)
+}
+
+// dashboardUsage must be called while holding the mutex
+func (s *Service) dashboardUsage(orgID int64, uid string, day int64) *dashboardUsageDelta {
+	key := dashboardKey{orgID: orgID, uid: uid, day: day}
+	usage, ok := s.dashboards[key]
+	if !ok {
+		usage = &dashboardUsageDelta{OrgID: orgID, DashboardUID: uid, Day: day}
+		s.dashboards[key] = usage
+	}
+	return usage
+}
+
+func (s *Service) flush(ctx context.Context) {
+	s.mutex.Lock()
+	dashboards := s.dashboards
+	datasources := s.datasources
+	s.dashboards = make(map[dashboardKey]*dashboardUsageDelta)
+	s.datasources = make(map[dataSourceKey]*dataSourceUsageDelta)
+	s.mutex.Unlock()
+
+	if len(dashboards) > 0 {
+		deltas := make([]dashboardUsageDelta, 0, len(dashboards))
+		for _, d := range dashboards {
+			deltas = append(deltas, *d)
+		}
+		if err := s.store.AddDashboardUsage(ctx, deltas); err != nil {
+			s.log.Error("failed to store dashboard usage", "error", err, "dashboards", len(deltas))
+		}
+	}
+
+	if len(datasources) > 0 {
+		deltas := make([]dataSourceUsageDelta, 0, len(datasources))
+		for _, d := range datasources {
+			deltas = append(deltas, *d)
+		}
+		if err := s.store.AddDataSourceUsage(ctx, deltas); err != nil {
+			s.log.Error("failed to store data source usage", "error", err, "datasources", len(deltas))
+		}
+	}
+}
+
+func (s *Service) cleanup(ctx context.Context) {
+	if s.retentionDays <= 0 {
+		return
+	}
+
+	err := s.lock.LockAndExecute(ctx, "delete old usage insights", cleanupInterval, func(ctx context.Context) {
+		olderThan := dayStart(s.now().AddDate(0, 0, -s.retentionDays))
+		deleted, err := s.store.DeleteOlderThan(ctx, olderThan)
+		if err != nil {
+			s.log.Error("failed to delete old usage insights", "error", err)
+			return
+		}
+		s.log.Debug("deleted old usage insights", "rows", deleted)
+	})
+	if err != nil {
+		s.log.Error("failed to lock and execute cleanup of old usage insights", "error", err)
+	}
+}
@@ -0,0 +1,124 @@
+package usageinsightsimpl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/usageinsights"
+)
+
+func TestIntegrationUsageInsights(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB := db.InitTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2023, 2, 10, 12, 0, 0, 0, time.UTC)
+
+	err := testDB.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, uid := range []string{"busy", "quiet", "dead"} {
+			dash := &dashboards.Dashboard{
+				UID: uid, Title: uid, Slug: uid, OrgID: 1, Data: simplejson.New(),
+				Created: now, Updated: now,
+			}
+			if _, err := sess.Insert(dash); err != nil {
+				return err
+			}
+		}
+		_, err := sess.Insert(&datasources.DataSource{
+			UID: "prom", Name: "Prometheus", Type: "prometheus", OrgID: 1, Created: now, Updated: now,
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	s := &Service{
+		store:       &sqlStore{db: testDB},
+		log:         log.NewNopLogger(),
+		now:         func() time.Time { return now },
+		enabled:     true,
+		dashboards:  make(map[dashboardKey]*dashboardUsageDelta),
+		datasources: make(map[dataSourceKey]*dataSourceUsageDelta),
+	}
+
+	t.Run("should aggregate usage in memory and flush it", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			s.RecordDashboardView(ctx, 1, "busy")
+			s.RecordQuery(ctx, 1, "busy", "prom", i == 0)
+		}
+		s.RecordDashboardView(ctx, 1, "quiet")
+		s.flush(ctx)
+
+		// A second flush for the same day adds to the existing rows
+		s.RecordDashboardView(ctx, 1, "busy")
+		s.flush(ctx)
+
+		result, err := s.GetDashboardsUsage(ctx, usageinsights.GetDashboardsUsageQuery{
+			OrgID: 1, Since: now.AddDate(0, 0, -30), Sort: usageinsights.SortViews, Desc: true, Limit: 10, Page: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		require.Equal(t, "busy", result[0].UID)
+		require.Equal(t, int64(4), result[0].Views)
+		require.Equal(t, int64(3), result[0].Queries)
+		require.Equal(t, int64(1), result[0].Errors)
+		require.NotNil(t, result[0].LastViewedAt)
+
+		// Dashboards that were never viewed are listed with zero usage
+		require.Equal(t, "dead", result[2].UID)
+		require.Equal(t, int64(0), result[2].Views)
+		require.Nil(t, result[2].LastViewedAt)
+
+		dsResult, err := s.GetDataSourcesUsage(ctx, usageinsights.GetDataSourcesUsageQuery{
+			OrgID: 1, Since: now.AddDate(0, 0, -30), Desc: true, Limit: 10, Page: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, dsResult, 1)
+		require.Equal(t, int64(3), dsResult[0].Queries)
+		require.Equal(t, int64(1), dsResult[0].Errors)
+
+		byUID, err := s.GetDashboardsUsageByUID(ctx, 1, []string{"busy", "dead"}, now.AddDate(0, 0, -30))
+		require.NoError(t, err)
+		require.Equal(t, int64(4), byUID["busy"].Views)
+		require.NotContains(t, byUID, "dead")
+
+		// a full search page is queried in batches
+		uids := make([]string, 0, 5000)
+		for i := 0; len(uids) < cap(uids)-1; i++ {
+			uids = append(uids, fmt.Sprintf("unknown-%d", i))
+		}
+		uids = append(uids, "quiet")
+		byUID, err = s.GetDashboardsUsageByUID(ctx, 1, uids, now.AddDate(0, 0, -30))
+		require.NoError(t, err)
+		require.Len(t, byUID, 1)
+		require.Equal(t, int64(1), byUID["quiet"].Views)
+	})
+
+	t.Run("should reject unknown sort fields", func(t *testing.T) {
+		_, err := s.GetDataSourcesUsage(ctx, usageinsights.GetDataSourcesUsageQuery{
+			OrgID: 1, Sort: usageinsights.SortLastViewed, Limit: 10, Page: 1,
+		})
+		require.ErrorIs(t, err, usageinsights.ErrInvalidSort)
+	})
+
+	t.Run("should delete old daily usage but keep the last view", func(t *testing.T) {
+		deleted, err := s.store.DeleteOlderThan(ctx, dayStart(now.AddDate(0, 0, 1)))
+		require.NoError(t, err)
+		require.Equal(t, int64(3), deleted)
+
+		byUID, err := s.GetDashboardsUsageByUID(ctx, 1, []string{"busy"}, now.AddDate(0, 0, -30))
+		require.NoError(t, err)
+		require.Equal(t, int64(0), byUID["busy"].Views)
+		require.NotNil(t, byUID["busy"].LastViewedAt)
+	})
+}
@@ -0,0 +1,362 @@
+package usageinsightsimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/usageinsights"
+)
+
+type store interface {
+	AddDashboardUsage(ctx context.Context, deltas []dashboardUsageDelta) error
+	AddDataSourceUsage(ctx context.Context, deltas []dataSourceUsageDelta) error
+	GetDashboardsUsage(ctx context.Context, query usageinsights.GetDashboardsUsageQuery) ([]*usageinsights.DashboardUsage, error)
+	GetDataSourcesUsage(ctx context.Context, query usageinsights.GetDataSourcesUsageQuery) ([]*usageinsights.DataSourceUsage, error)
+	GetDashboardsUsageByUID(ctx context.Context, orgID int64, dashboardUIDs []string, since time.Time) (map[string]*usageinsights.DashboardUsage, error)
+	DeleteOlderThan(ctx context.Context, day int64) (int64, error)
+}
+
+// usageByUIDBatchSize is the number of dashboards queried at once by GetDashboardsUsageByUID
+const usageByUIDBatchSize = 500
+
+type sqlStore struct {
+	db db.DB
+}
+
+// dashboardUsageDelta is the usage of a dashboard accumulated in memory since the last flush
+type dashboardUsageDelta struct {
+	OrgID        int64
+	DashboardUID string
+	Day          int64
+	Views        int64
+	Queries      int64
+	Errors       int64
+	LastViewedAt int64
+}
+
+// dataSourceUsageDelta is the usage of a data source accumulated in memory since the last flush
+type dataSourceUsageDelta struct {
+	OrgID         int64
+	DataSourceUID string
+	Day           int64
+	Queries       int64
+	Errors        int64
+}
+
+type dashboardUsageRow struct {
+	UID          string `xorm:"uid"`
+	Title        string `xorm:"title"`
+	Views        int64  `xorm:"views"`
+	Queries      int64  `xorm:"queries"`
+	Errors       int64  `xorm:"errors"`
+	LastViewedAt int64  `xorm:"last_viewed_at"`
+}
+
+type dataSourceUsageRow struct {
+	UID     string `xorm:"uid"`
+	Name    string `xorm:"name"`
+	Type    string `xorm:"type"`
+	Queries int64  `xorm:"queries"`
+	Errors  int64  `xorm:"errors"`
+}
+
+func (s *sqlStore) AddDashboardUsage(ctx context.Context, deltas []dashboardUsageDelta) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, d := range deltas {
+			res, err := sess.Exec(`UPDATE dashboard_usage_by_day SET views = views + ?, queries = queries + ?, errors = errors + ?
+				WHERE org_id = ? AND dashboard_uid = ? AND day = ?`,
+				d.Views, d.Queries, d.Errors, d.OrgID, d.DashboardUID, d.Day)
+			if err != nil {
+				return err
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			if affected == 0 {
+				if _, err := sess.Exec(`INSERT INTO dashboard_usage_by_day (org_id, dashboard_uid, day, views, queries, errors) VALUES (?, ?, ?, ?, ?, ?)`,
+					d.OrgID, d.DashboardUID, d.Day, d.Views, d.Queries, d.Errors); err != nil {
+					return err
+				}
+			}
+
+			if d.LastViewedAt == 0 {
+				continue
+			}
+
+			res, err = sess.Exec(`UPDATE dashboard_usage_summary SET last_viewed_at = ? WHERE org_id = ? AND dashboard_uid = ? AND last_viewed_at < ?`,
+				d.LastViewedAt, d.OrgID, d.DashboardUID, d.LastViewedAt)
+			if err != nil {
+				return err
+			}
+
+			if affected, err = res.RowsAffected(); err != nil {
+				return err
+			}
+
+			if affected == 0 {
+				exists, err := sess.Table("dashboard_usage_summary").Where("org_id = ? AND dashboard_uid = ?", d.OrgID, d.DashboardUID).Exist()
+				if err != nil {
+					return err
+				}
+				if exists {
+					continue
+				}
+
+				if _, err := sess.Exec(`INSERT INTO dashboard_usage_summary (org_id, dashboard_uid, last_viewed_at) VALUES (?, ?, ?)`,
+					d.OrgID, d.DashboardUID, d.LastViewedAt); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) AddDataSourceUsage(ctx context.Context, deltas []dataSourceUsageDelta) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, d := range deltas {
+			res, err := sess.Exec(`UPDATE datasource_usage_by_day SET queries = queries + ?, errors = errors + ?
+				WHERE org_id = ? AND datasource_uid = ? AND day = ?`,
+				d.Queries, d.Errors, d.OrgID, d.DataSourceUID, d.Day)
+			if err != nil {
+				return err
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+
+			if affected == 0 {
+				if _, err := sess.Exec(`INSERT INTO datasource_usage_by_day (org_id, datasource_uid, day, queries, errors) VALUES (?, ?, ?, ?, ?)`,
+					d.OrgID, d.DataSourceUID, d.Day, d.Queries, d.Errors); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) GetDashboardsUsage(ctx context.Context, query usageinsights.GetDashboardsUsageQuery) ([]*usageinsights.DashboardUsage, error) {
+	orderBy, err := orderByClause(query.Sort, query.Desc, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]dashboardUsageRow, 0)
+	err = s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := strings.Builder{}
+		sql.WriteString(`SELECT d.uid, d.title,
+			COALESCE(SUM(u.views), 0) AS views,
+			COALESCE(SUM(u.queries), 0) AS queries,
+			COALESCE(SUM(u.errors), 0) AS errors,
+			COALESCE(s.last_viewed_at, 0) AS last_viewed_at
+			FROM dashboard d
+			LEFT JOIN dashboard_usage_by_day u ON u.org_id = d.org_id AND u.dashboard_uid = d.uid AND u.day >= ?
+			LEFT JOIN dashboard_usage_summary s ON s.org_id = d.org_id AND s.dashboard_uid = d.uid
+			WHERE d.org_id = ? AND d.is_folder = ` + s.db.GetDialect().BooleanStr(false) + `
+			GROUP BY d.uid, d.title, s.last_viewed_at `)
+		sql.WriteString(orderBy)
+		sql.WriteString(s.db.GetDialect().LimitOffset(query.Limit, (query.Page-1)*query.Limit))
+
+		return sess.SQL(sql.String(), dayStart(query.Since), query.OrgID).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*usageinsights.DashboardUsage, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &usageinsights.DashboardUsage{
+			UID:          row.UID,
+			Title:        row.Title,
+			Views:        row.Views,
+			Queries:      row.Queries,
+			Errors:       row.Errors,
+			LastViewedAt: unixToTime(row.LastViewedAt),
+		})
+	}
+	return result, nil
+}
+
+func (s *sqlStore) GetDataSourcesUsage(ctx context.Context, query usageinsights.GetDataSourcesUsageQuery) ([]*usageinsights.DataSourceUsage, error) {
+	orderBy, err := orderByClause(query.Sort, query.Desc, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]dataSourceUsageRow, 0)
+	err = s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := strings.Builder{}
+		sql.WriteString(`SELECT ds.uid, ds.name, ds.type,
+			COALESCE(SUM(u.queries), 0) AS queries,
+			COALESCE(SUM(u.errors), 0) AS errors
+			FROM data_source ds
+			LEFT JOIN datasource_usage_by_day u ON u.org_id = ds.org_id AND u.datasource_uid = ds.uid AND u.day >= ?
+			WHERE ds.org_id = ?
+			GROUP BY ds.uid, ds.name, ds.type `)
+		sql.WriteString(orderBy)
+		sql.WriteString(s.db.GetDialect().LimitOffset(query.Limit, (query.Page-1)*query.Limit))
+
+		return sess.SQL(sql.String(), dayStart(query.Since), query.OrgID).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*usageinsights.DataSourceUsage, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &usageinsights.DataSourceUsage{
+			UID:     row.UID,
+			Name:    row.Name,
+			Type:    row.Type,
+			Queries: row.Queries,
+			Errors:  row.Errors,
+		})
+	}
+	return result, nil
+}
+
+func (s *sqlStore) GetDashboardsUsageByUID(ctx context.Context, orgID int64, dashboardUIDs []string, since time.Time) (map[string]*usageinsights.DashboardUsage, error) {
+	result := make(map[string]*usageinsights.DashboardUsage, len(dashboardUIDs))
+	if len(dashboardUIDs) == 0 {
+		return result, nil
+	}
+
+	type lastViewRow struct {
+		UID          string `xorm:"dashboard_uid"`
+		LastViewedAt int64  `xorm:"last_viewed_at"`
+	}
+
+	rows := make([]dashboardUsageRow, 0)
+	lastViews := make([]lastViewRow, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		// the dashboards of a search page are queried in batches to stay below the bound variables limit of SQLite
+		for start := 0; start < len(dashboardUIDs); start += usageByUIDBatchSize {
+			end := start + usageByUIDBatchSize
+			if end > len(dashboardUIDs) {
+				end = len(dashboardUIDs)
+			}
+			batch := dashboardUIDs[start:end]
+
+			uids := make([]interface{}, 0, len(batch))
+			for _, uid := range batch {
+				uids = append(uids, uid)
+			}
+			in := "(?" + strings.Repeat(",?", len(batch)-1) + ")"
+
+			params := append([]interface{}{orgID, dayStart(since)}, uids...)
+			batchRows := make([]dashboardUsageRow, 0)
+			err := sess.SQL(`SELECT dashboard_uid AS uid, SUM(views) AS views, SUM(queries) AS queries, SUM(errors) AS errors
+				FROM dashboard_usage_by_day
+				WHERE org_id = ? AND day >= ? AND dashboard_uid IN `+in+`
+				GROUP BY dashboard_uid`, params...).Find(&batchRows)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, batchRows...)
+
+			params = append([]interface{}{orgID}, uids...)
+			batchLastViews := make([]lastViewRow, 0)
+			err = sess.SQL(`SELECT dashboard_uid, last_viewed_at FROM dashboard_usage_summary
+				WHERE org_id = ? AND dashboard_uid IN `+in, params...).Find(&batchLastViews)
+			if err != nil {
+				return err
+			}
+			lastViews = append(lastViews, batchLastViews...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.UID] = &usageinsights.DashboardUsage{
+			UID:     row.UID,
+			Views:   row.Views,
+			Queries: row.Queries,
+			Errors:  row.Errors,
+		}
+	}
+
+	for _, row := range lastViews {
+		usage, ok := result[row.UID]
+		if !ok {
+			usage = &usageinsights.DashboardUsage{UID: row.UID}
+			result[row.UID] = usage
+		}
+		usage.LastViewedAt = unixToTime(row.LastViewedAt)
+	}
+	return result, nil
+}
+
+func (s *sqlStore) DeleteOlderThan(ctx context.Context, day int64) (int64, error) {
+	var deleted int64
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, table := range []string{"dashboard_usage_by_day", "datasource_usage_by_day"} {
+			res, err := sess.Exec("DELETE FROM "+table+" WHERE day < ?", day)
+			if err != nil {
+				return err
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += affected
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+func orderByClause(sort usageinsights.SortField, desc bool, dashboards bool) (string, error) {
+	var column string
+	switch sort {
+	case "", usageinsights.SortViews:
+		column = "views"
+		if !dashboards {
+			column = "queries"
+		}
+	case usageinsights.SortQueries:
+		column = "queries"
+	case usageinsights.SortErrors:
+		column = "errors"
+	case usageinsights.SortLastViewed:
+		if !dashboards {
+			return "", usageinsights.ErrInvalidSort.Errorf("data sources can not be sorted by %s", sort)
+		}
+		column = "last_viewed_at"
+	default:
+		return "", usageinsights.ErrInvalidSort.Errorf("unknown sort field %q", sort)
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	// uid is used as a tie breaker to keep pagination stable
+	return fmt.Sprintf("ORDER BY %s %s, uid ASC ", column, direction), nil
+}
+
+// dayStart returns the unix timestamp of the beginning of the UTC day
+func dayStart(t time.Time) int64 {
+	return t.UTC().Truncate(24 * time.Hour).Unix()
+}
+
+func unixToTime(ts int64) *time.Time {
+	if ts == 0 {
+		return nil
+	}
+	t := time.Unix(ts, 0)
+	return &t
+}
@@ -0,0 +1,43 @@
+package usageinsightstest
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/usageinsights"
+)
+
+var _ usageinsights.Service = new(FakeService)
+
+type FakeService struct {
+	ExpectedDashboardsUsage  []*usageinsights.DashboardUsage
+	ExpectedDataSourcesUsage []*usageinsights.DataSourceUsage
+	ExpectedError            error
+
+	DashboardViews int
+	Queries        int
+}
+
+func (f *FakeService) RecordDashboardView(ctx context.Context, orgID int64, dashboardUID string) {
+	f.DashboardViews++
+}
+
+func (f *FakeService) RecordQuery(ctx context.Context, orgID int64, dashboardUID string, datasourceUID string, failed bool) {
+	f.Queries++
+}
+
+func (f *FakeService) GetDashboardsUsage(ctx context.Context, query usageinsights.GetDashboardsUsageQuery) ([]*usageinsights.DashboardUsage, error) {
+	return f.ExpectedDashboardsUsage, f.ExpectedError
+}
+
+func (f *FakeService) GetDataSourcesUsage(ctx context.Context, query usageinsights.GetDataSourcesUsageQuery) ([]*usageinsights.DataSourceUsage, error) {
+	return f.ExpectedDataSourcesUsage, f.ExpectedError
+}
+
+func (f *FakeService) GetDashboardsUsageByUID(ctx context.Context, orgID int64, dashboardUIDs []string, since time.Time) (map[string]*usageinsights.DashboardUsage, error) {
+	result := make(map[string]*usageinsights.DashboardUsage, len(f.ExpectedDashboardsUsage))
+	for _, u := range f.ExpectedDashboardsUsage {
+		result[u.UID] = u
+	}
+	return result, f.ExpectedError
+}
@@ -220,6 +220,9 @@ type SignedInUser struct {
 	Analytics          AnalyticsSettings
 	// Permissions grouped by orgID and actions
 	Permissions map[int64]map[string][]string `json:"-"`
+	// PermissionBoundary restricts the permissions of a service account to the scopes grouped by action, when it
+	// signed in with a token created for a subset of them. The role of a bounded user grants no access by itself.
+	PermissionBoundary map[string][]string `json:"-"`
 }
 
 func (u *User) NameOrFallback() string {
@@ -280,6 +283,10 @@ func (u *SignedInUser) HasRole(role roletype.RoleType) bool {
 	if u.IsGrafanaAdmin {
 		return true
 	}
+	// the access of a user restricted by a permission boundary is only granted by its permissions
+	if u.PermissionBoundary != nil {
+		return false
+	}
 
 	return u.OrgRole.Includes(role)
 }
@@ -278,10 +278,12 @@ func (s *Service) GetSignedInUser(ctx context.Context, query *user.GetSignedInUs
 			},
 		},
 	}
+	// Teams inherited through the team hierarchy grant their roles as well
 	getTeamsByUserQuery := &team.GetTeamsByUserQuery{
-		OrgID:        signedInUser.OrgID,
-		UserID:       signedInUser.UserID,
-		SignedInUser: tempUser,
+		OrgID:            signedInUser.OrgID,
+		UserID:           signedInUser.UserID,
+		SignedInUser:     tempUser,
+		IncludeInherited: true,
 	}
 	getTeamsByUserQueryResult, err := s.teamService.GetTeamsByUser(ctx, getTeamsByUserQuery)
 	if err != nil {
@@ -0,0 +1,131 @@
+package userdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log/audit"
+	"github.com/grafana/grafana/pkg/services/org"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/queryhistory"
+	"github.com/grafana/grafana/pkg/services/star"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+type DeletionStatus string
+
+const (
+	// DeletionPending requests wait for the approval of a server admin
+	DeletionPending  DeletionStatus = "pending"
+	DeletionApproved DeletionStatus = "approved"
+	DeletionRejected DeletionStatus = "rejected"
+)
+
+var (
+	ErrDeletionDisabled         = errutil.NewBase(errutil.StatusNotFound, "userdata.deletionDisabled", errutil.WithPublicMessage("Account deletion is disabled"))
+	ErrDeletionRequestNotFound  = errutil.NewBase(errutil.StatusNotFound, "userdata.deletionRequestNotFound", errutil.WithPublicMessage("Account deletion request not found"))
+	ErrDeletionAlreadyRequested = errutil.NewBase(errutil.StatusBadRequest, "userdata.deletionAlreadyRequested", errutil.WithPublicMessage("The deletion of the account is already requested"))
+	ErrDeletionNotAllowed       = errutil.NewBase(errutil.StatusForbidden, "userdata.deletionNotAllowed", errutil.WithPublicMessage("The account of a server admin or a service account can't be deleted on request, it has to be deleted by a server admin"))
+	ErrDeletionNotPending       = errutil.NewBase(errutil.StatusBadRequest, "userdata.deletionNotPending", errutil.WithPublicMessage("Only the pending deletion requests can be approved or rejected"))
+)
+
+// Service gives the users access to the personal data Grafana holds about them: they can export it, and request
+// the deletion of their account. The accounts are deleted once the grace period of the request is over, and once
+// a server admin approved the request when approval is required.
+type Service interface {
+	// Export returns all the personal data of the user.
+	Export(ctx context.Context, userID int64) (*Export, error)
+
+	// RequestDeletion schedules the deletion of the account of the user after the grace period.
+	RequestDeletion(ctx context.Context, userID int64, cmd *RequestDeletionCommand) (*DeletionRequest, error)
+	GetDeletionRequest(ctx context.Context, userID int64) (*DeletionRequest, error)
+	// CancelDeletion removes the deletion request of the user, the account is kept.
+	CancelDeletion(ctx context.Context, userID int64) error
+	// ListDeletionRequests returns the deletion requests with their users, of all the statuses when the status is empty.
+	ListDeletionRequests(ctx context.Context, status DeletionStatus) ([]*DeletionRequestDTO, error)
+	ApproveDeletion(ctx context.Context, id, reviewerID int64) (*DeletionRequest, error)
+	RejectDeletion(ctx context.Context, id, reviewerID int64) (*DeletionRequest, error)
+	// ListDueDeletions returns the approved requests whose grace period is over.
+	ListDueDeletions(ctx context.Context) ([]*DeletionRequest, error)
+	// DeleteByUser removes the deletion request of a deleted user.
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+type DeletionRequest struct {
+	ID     int64          `xorm:"pk autoincr 'id'" json:"id"`
+	UserID int64          `xorm:"user_id" json:"userId"`
+	Status DeletionStatus `xorm:"status" json:"status"`
+	Reason string         `xorm:"reason" json:"reason"`
+	// ReviewedBy is the server admin who approved or rejected the request, 0 when approval isn't required
+	ReviewedBy int64 `xorm:"reviewed_by" json:"reviewedBy"`
+	// DeleteAfter is when the account is deleted, if the request is approved by then
+	DeleteAfter time.Time `xorm:"delete_after" json:"deleteAfter"`
+	Created     time.Time `xorm:"created" json:"created"`
+	Updated     time.Time `xorm:"updated" json:"updated"`
+}
+
+func (r DeletionRequest) TableName() string {
+	return "user_deletion_request"
+}
+
+// DeletionRequestDTO is a deletion request with the user it is for.
+type DeletionRequestDTO struct {
+	DeletionRequest `xorm:"extends"`
+	Login           string `xorm:"login" json:"login"`
+	Email           string `xorm:"email" json:"email"`
+	Name            string `xorm:"name" json:"name"`
+}
+
+type RequestDeletionCommand struct {
+	Reason string `json:"reason"`
+}
+
+// Export is the personal data of a user.
+type Export struct {
+	ExportedAt      time.Time              `json:"exportedAt"`
+	Profile         *user.UserProfileDTO   `json:"profile"`
+	Organizations   []*org.UserOrgDTO      `json:"organizations"`
+	Preferences     []*ExportedPreferences `json:"preferences"`
+	Stars           *ExportedStars         `json:"stars"`
+	QueryHistory    []*ExportedQueries     `json:"queryHistory"`
+	Sessions        []*ExportedSession     `json:"sessions"`
+	AuditEvents     []audit.Event          `json:"auditEvents"`
+	DeletionRequest *DeletionRequest       `json:"deletionRequest,omitempty"`
+}
+
+type ExportedPreferences struct {
+	OrgID           int64                    `json:"orgId"`
+	Theme           string                   `json:"theme"`
+	Timezone        string                   `json:"timezone"`
+	WeekStart       *string                  `json:"weekStart,omitempty"`
+	HomeDashboardID int64                    `json:"homeDashboardId"`
+	JSONData        *pref.PreferenceJSONData `json:"jsonData,omitempty"`
+	Updated         time.Time                `json:"updated"`
+}
+
+type ExportedStars struct {
+	DashboardIDs []int64                 `json:"dashboardIds"`
+	Resources    []*ExportedResourceStar `json:"resources"`
+}
+
+type ExportedResourceStar struct {
+	OrgID   int64     `json:"orgId"`
+	Kind    star.Kind `json:"kind"`
+	UID     string    `json:"uid"`
+	Created time.Time `json:"created"`
+}
+
+type ExportedQueries struct {
+	OrgID   int64                          `json:"orgId"`
+	Queries []queryhistory.QueryHistoryDTO `json:"queries"`
+}
+
+// ExportedSession is a session of the user, without its tokens.
+type ExportedSession struct {
+	ClientIP   string    `json:"clientIp"`
+	UserAgent  string    `json:"userAgent"`
+	AuthModule string    `json:"authModule,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	SeenAt     time.Time `json:"seenAt"`
+}
@@ -0,0 +1,251 @@
+package userdataimpl
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/log/audit"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/org"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/queryhistory"
+	"github.com/grafana/grafana/pkg/services/star"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/userdata"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// auditReader reads back the audit events of a user.
+type auditReader interface {
+	UserEvents(ctx context.Context, userID int64) ([]audit.Event, error)
+}
+
+type Service struct {
+	cfg                 setting.AccountDeletionSettings
+	queryHistoryEnabled bool
+	store               store
+	userService         user.Service
+	orgService          org.Service
+	prefService         pref.Service
+	starService         star.Service
+	queryHistory        queryhistory.Service
+	authTokenService    auth.UserTokenService
+	audit               auditReader
+	log                 log.Logger
+	now                 func() time.Time
+}
+
+var _ userdata.Service = (*Service)(nil)
+
+func ProvideService(
+	cfg *setting.Cfg,
+	sql db.DB,
+	userService user.Service,
+	orgService org.Service,
+	prefService pref.Service,
+	starService star.Service,
+	queryHistory queryhistory.Service,
+	authTokenService auth.UserTokenService,
+	auditService *audit.Service,
+) *Service {
+	return &Service{
+		cfg:                 cfg.AccountDeletion,
+		queryHistoryEnabled: cfg.QueryHistoryEnabled,
+		store:               &sqlStore{db: sql, now: time.Now},
+		userService:         userService,
+		orgService:          orgService,
+		prefService:         prefService,
+		starService:         starService,
+		queryHistory:        queryHistory,
+		authTokenService:    authTokenService,
+		audit:               auditService,
+		log:                 log.New("userdata"),
+		now:                 time.Now,
+	}
+}
+
+func (s *Service) Export(ctx context.Context, userID int64) (*userdata.Export, error) {
+	profile, err := s.userService.GetProfile(ctx, &user.GetUserProfileQuery{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	orgs, err := s.orgService.GetUserOrgList(ctx, &org.GetUserOrgListQuery{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	export := &userdata.Export{
+		ExportedAt:    s.now(),
+		Profile:       profile,
+		Organizations: orgs,
+		Preferences:   []*userdata.ExportedPreferences{},
+		Stars:         &userdata.ExportedStars{DashboardIDs: []int64{}, Resources: []*userdata.ExportedResourceStar{}},
+		QueryHistory:  []*userdata.ExportedQueries{},
+		Sessions:      []*userdata.ExportedSession{},
+	}
+
+	for _, o := range orgs {
+		prefs, err := s.prefService.Get(ctx, &pref.GetPreferenceQuery{OrgID: o.OrgID, UserID: userID})
+		if err != nil {
+			return nil, err
+		}
+		// the users who never changed their preferences have none
+		if prefs.ID != 0 {
+			export.Preferences = append(export.Preferences, &userdata.ExportedPreferences{
+				OrgID:           o.OrgID,
+				Theme:           prefs.Theme,
+				Timezone:        prefs.Timezone,
+				WeekStart:       prefs.WeekStart,
+				HomeDashboardID: prefs.HomeDashboardID,
+				JSONData:        prefs.JSONData,
+				Updated:         prefs.Updated,
+			})
+		}
+
+		resources, err := s.starService.GetResourcesByUser(ctx, &star.GetUserResourceStarsQuery{UserID: userID, OrgID: o.OrgID})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resources {
+			export.Stars.Resources = append(export.Stars.Resources, &userdata.ExportedResourceStar{OrgID: r.OrgID, Kind: r.Kind, UID: r.UID, Created: r.Created})
+		}
+
+		if s.queryHistoryEnabled {
+			queries, err := s.queryHistory.ExportQueryHistory(ctx, &user.SignedInUser{UserID: userID, OrgID: o.OrgID}, queryhistory.SearchInQueryHistoryQuery{})
+			if err != nil {
+				return nil, err
+			}
+			if len(queries) > 0 {
+				export.QueryHistory = append(export.QueryHistory, &userdata.ExportedQueries{OrgID: o.OrgID, Queries: queries})
+			}
+		}
+	}
+
+	stars, err := s.starService.GetByUser(ctx, &star.GetUserStarsQuery{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	for id := range stars.UserStars {
+		export.Stars.DashboardIDs = append(export.Stars.DashboardIDs, id)
+	}
+	sort.Slice(export.Stars.DashboardIDs, func(i, j int) bool { return export.Stars.DashboardIDs[i] < export.Stars.DashboardIDs[j] })
+
+	tokens, err := s.authTokenService.GetUserTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		export.Sessions = append(export.Sessions, &userdata.ExportedSession{
+			ClientIP:   t.ClientIp,
+			UserAgent:  t.UserAgent,
+			AuthModule: t.AuthModule,
+			CreatedAt:  time.Unix(t.CreatedAt, 0),
+			SeenAt:     time.Unix(t.SeenAt, 0),
+		})
+	}
+
+	if export.AuditEvents, err = s.audit.UserEvents(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	req, err := s.store.GetByUser(ctx, userID)
+	if err != nil && !errors.Is(err, userdata.ErrDeletionRequestNotFound) {
+		return nil, err
+	}
+	export.DeletionRequest = req
+
+	return export, nil
+}
+
+func (s *Service) RequestDeletion(ctx context.Context, userID int64, cmd *userdata.RequestDeletionCommand) (*userdata.DeletionRequest, error) {
+	if !s.cfg.Enabled {
+		return nil, userdata.ErrDeletionDisabled.Errorf("account deletion is disabled")
+	}
+
+	usr, err := s.userService.GetByID(ctx, &user.GetUserByIDQuery{ID: userID})
+	if err != nil {
+		return nil, err
+	}
+	if usr.IsAdmin {
+		return nil, userdata.ErrDeletionNotAllowed.Errorf("user %d is a server admin", userID)
+	}
+	if usr.IsServiceAccount {
+		return nil, userdata.ErrDeletionNotAllowed.Errorf("user %d is a service account", userID)
+	}
+
+	req := &userdata.DeletionRequest{
+		UserID:      userID,
+		Status:      userdata.DeletionApproved,
+		Reason:      strings.TrimSpace(cmd.Reason),
+		DeleteAfter: s.now().Add(s.cfg.GracePeriod),
+	}
+	if s.cfg.RequireApproval {
+		req.Status = userdata.DeletionPending
+	}
+	if err := s.store.Insert(ctx, req); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("Account deletion requested", "userId", userID, "status", req.Status, "deleteAfter", req.DeleteAfter)
+	return req, nil
+}
+
+func (s *Service) GetDeletionRequest(ctx context.Context, userID int64) (*userdata.DeletionRequest, error) {
+	return s.store.GetByUser(ctx, userID)
+}
+
+func (s *Service) CancelDeletion(ctx context.Context, userID int64) error {
+	if _, err := s.store.GetByUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.store.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	s.log.Info("Account deletion cancelled", "userId", userID)
+	return nil
+}
+
+func (s *Service) ListDeletionRequests(ctx context.Context, status userdata.DeletionStatus) ([]*userdata.DeletionRequestDTO, error) {
+	return s.store.List(ctx, status)
+}
+
+func (s *Service) ApproveDeletion(ctx context.Context, id, reviewerID int64) (*userdata.DeletionRequest, error) {
+	return s.review(ctx, id, userdata.DeletionApproved, reviewerID)
+}
+
+func (s *Service) RejectDeletion(ctx context.Context, id, reviewerID int64) (*userdata.DeletionRequest, error) {
+	return s.review(ctx, id, userdata.DeletionRejected, reviewerID)
+}
+
+func (s *Service) review(ctx context.Context, id int64, status userdata.DeletionStatus, reviewerID int64) (*userdata.DeletionRequest, error) {
+	if _, err := s.store.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.store.Review(ctx, id, status, reviewerID); err != nil {
+		return nil, err
+	}
+
+	req, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("Account deletion reviewed", "userId", req.UserID, "status", status, "reviewedBy", reviewerID)
+	return req, nil
+}
+
+func (s *Service) ListDueDeletions(ctx context.Context) ([]*userdata.DeletionRequest, error) {
+	if !s.cfg.Enabled {
+		return []*userdata.DeletionRequest{}, nil
+	}
+	return s.store.ListDue(ctx)
+}
+
+func (s *Service) DeleteByUser(ctx context.Context, userID int64) error {
+	return s.store.DeleteByUser(ctx, userID)
+}
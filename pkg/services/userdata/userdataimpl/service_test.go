@@ -0,0 +1,165 @@
+package userdataimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/log/audit"
+	"github.com/grafana/grafana/pkg/services/auth"
+	"github.com/grafana/grafana/pkg/services/auth/authtest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/star"
+	"github.com/grafana/grafana/pkg/services/star/startest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/services/userdata"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationUserData(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sql := db.InitTestDB(t)
+	now := time.Now().Truncate(time.Second)
+	clock := func() time.Time { return now }
+	users := usertest.NewUserServiceFake()
+	users.ExpectedUser = &user.User{ID: 1}
+	tokens := authtest.NewFakeUserAuthTokenService()
+	s := &Service{
+		cfg:              setting.AccountDeletionSettings{Enabled: true, GracePeriod: 24 * time.Hour, RequireApproval: true},
+		store:            &sqlStore{db: sql, now: clock},
+		userService:      users,
+		orgService:       &orgtest.FakeOrgService{ExpectedUserOrgDTO: []*org.UserOrgDTO{{OrgID: 1, Name: "Main Org."}}},
+		prefService:      &preftest.FakePreferenceService{ExpectedPreference: &pref.Preference{ID: 1, Theme: "dark"}},
+		starService:      &startest.FakeStarService{ExpectedUserStars: &star.GetUserStarsResult{UserStars: map[int64]bool{3: true, 2: true}}},
+		authTokenService: tokens,
+		audit:            fakeAuditReader{events: []audit.Event{{Action: audit.ActionLogin, Actor: audit.Actor{UserID: 1}}}},
+		log:              log.NewNopLogger(),
+		now:              clock,
+	}
+	ctx := context.Background()
+
+	createUser := func(t *testing.T, login string) int64 {
+		t.Helper()
+		usr := &user.User{Login: login, Email: login + "@example.org", Created: now, Updated: now}
+		err := sql.WithDbSession(ctx, func(sess *db.Session) error {
+			_, err := sess.Insert(usr)
+			return err
+		})
+		require.NoError(t, err)
+		return usr.ID
+	}
+
+	t.Run("should export the personal data of the user", func(t *testing.T) {
+		users.ExpectedUserProfileDTO = &user.UserProfileDTO{ID: 1, Login: "viewer"}
+		tokens.GetUserTokensProvider = func(ctx context.Context, userID int64) ([]*auth.UserToken, error) {
+			return []*auth.UserToken{{UserId: userID, AuthToken: "hashed", ClientIp: "10.0.0.1", CreatedAt: now.Unix()}}, nil
+		}
+
+		export, err := s.Export(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "viewer", export.Profile.Login)
+		require.Len(t, export.Organizations, 1)
+		require.Len(t, export.Preferences, 1)
+		require.Equal(t, "dark", export.Preferences[0].Theme)
+		require.Equal(t, []int64{2, 3}, export.Stars.DashboardIDs)
+		require.Equal(t, []*userdata.ExportedSession{{ClientIP: "10.0.0.1", CreatedAt: now, SeenAt: time.Unix(0, 0)}}, export.Sessions)
+		require.Len(t, export.AuditEvents, 1)
+		require.Empty(t, export.QueryHistory)
+		require.Nil(t, export.DeletionRequest)
+	})
+
+	t.Run("should delete the account once the request is approved and the grace period is over", func(t *testing.T) {
+		userID := createUser(t, "approved")
+		req, err := s.RequestDeletion(ctx, userID, &userdata.RequestDeletionCommand{Reason: " leaving "})
+		require.NoError(t, err)
+		require.Equal(t, userdata.DeletionPending, req.Status)
+		require.Equal(t, "leaving", req.Reason)
+		require.Equal(t, now.Add(24*time.Hour), req.DeleteAfter)
+
+		_, err = s.RequestDeletion(ctx, userID, &userdata.RequestDeletionCommand{})
+		require.ErrorIs(t, err, userdata.ErrDeletionAlreadyRequested)
+
+		pending, err := s.ListDeletionRequests(ctx, userdata.DeletionPending)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, "approved", pending[0].Login)
+
+		approved, err := s.ApproveDeletion(ctx, req.ID, 10)
+		require.NoError(t, err)
+		require.Equal(t, userdata.DeletionApproved, approved.Status)
+		require.Equal(t, int64(10), approved.ReviewedBy)
+		_, err = s.RejectDeletion(ctx, req.ID, 10)
+		require.ErrorIs(t, err, userdata.ErrDeletionNotPending)
+
+		due, err := s.ListDueDeletions(ctx)
+		require.NoError(t, err)
+		require.Empty(t, due)
+
+		now = now.Add(25 * time.Hour)
+		due, err = s.ListDueDeletions(ctx)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		require.Equal(t, userID, due[0].UserID)
+
+		require.NoError(t, s.DeleteByUser(ctx, userID))
+		_, err = s.GetDeletionRequest(ctx, userID)
+		require.ErrorIs(t, err, userdata.ErrDeletionRequestNotFound)
+	})
+
+	t.Run("should let the user request the deletion again once rejected, and cancel it", func(t *testing.T) {
+		userID := createUser(t, "rejected")
+		req, err := s.RequestDeletion(ctx, userID, &userdata.RequestDeletionCommand{})
+		require.NoError(t, err)
+		_, err = s.RejectDeletion(ctx, req.ID, 10)
+		require.NoError(t, err)
+
+		_, err = s.RequestDeletion(ctx, userID, &userdata.RequestDeletionCommand{})
+		require.NoError(t, err)
+		require.NoError(t, s.CancelDeletion(ctx, userID))
+		require.ErrorIs(t, s.CancelDeletion(ctx, userID), userdata.ErrDeletionRequestNotFound)
+	})
+
+	t.Run("should not require approval when disabled", func(t *testing.T) {
+		s.cfg.RequireApproval = false
+		t.Cleanup(func() { s.cfg.RequireApproval = true })
+
+		req, err := s.RequestDeletion(ctx, createUser(t, "unreviewed"), &userdata.RequestDeletionCommand{})
+		require.NoError(t, err)
+		require.Equal(t, userdata.DeletionApproved, req.Status)
+	})
+
+	t.Run("should refuse the requests of the server admins and service accounts, or when the deletion is disabled", func(t *testing.T) {
+		users.ExpectedUser = &user.User{ID: 1, IsAdmin: true}
+		t.Cleanup(func() { users.ExpectedUser = &user.User{ID: 1} })
+		_, err := s.RequestDeletion(ctx, 1, &userdata.RequestDeletionCommand{})
+		require.ErrorIs(t, err, userdata.ErrDeletionNotAllowed)
+
+		users.ExpectedUser = &user.User{ID: 1, IsServiceAccount: true}
+		_, err = s.RequestDeletion(ctx, 1, &userdata.RequestDeletionCommand{})
+		require.ErrorIs(t, err, userdata.ErrDeletionNotAllowed)
+
+		s.cfg.Enabled = false
+		t.Cleanup(func() { s.cfg.Enabled = true })
+		_, err = s.RequestDeletion(ctx, 1, &userdata.RequestDeletionCommand{})
+		require.ErrorIs(t, err, userdata.ErrDeletionDisabled)
+	})
+}
+
+type fakeAuditReader struct {
+	events []audit.Event
+}
+
+func (f fakeAuditReader) UserEvents(ctx context.Context, userID int64) ([]audit.Event, error) {
+	return f.events, nil
+}
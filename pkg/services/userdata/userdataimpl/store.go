@@ -0,0 +1,133 @@
+package userdataimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/userdata"
+)
+
+type store interface {
+	Get(ctx context.Context, id int64) (*userdata.DeletionRequest, error)
+	GetByUser(ctx context.Context, userID int64) (*userdata.DeletionRequest, error)
+	// Insert replaces the rejected request of the user, if any.
+	Insert(ctx context.Context, req *userdata.DeletionRequest) error
+	// Review sets the status of a pending request.
+	Review(ctx context.Context, id int64, status userdata.DeletionStatus, reviewerID int64) error
+	List(ctx context.Context, status userdata.DeletionStatus) ([]*userdata.DeletionRequestDTO, error)
+	ListDue(ctx context.Context) ([]*userdata.DeletionRequest, error)
+	DeleteByUser(ctx context.Context, userID int64) error
+}
+
+type sqlStore struct {
+	db  db.DB
+	now func() time.Time
+}
+
+func (s *sqlStore) Get(ctx context.Context, id int64) (*userdata.DeletionRequest, error) {
+	req := &userdata.DeletionRequest{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.ID(id).Get(req)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return userdata.ErrDeletionRequestNotFound.Errorf("deletion request %d not found", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *sqlStore) GetByUser(ctx context.Context, userID int64) (*userdata.DeletionRequest, error) {
+	req := &userdata.DeletionRequest{}
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("user_id = ?", userID).Get(req)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return userdata.ErrDeletionRequestNotFound.Errorf("no deletion request for user %d", userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *sqlStore) Insert(ctx context.Context, req *userdata.DeletionRequest) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		existing := &userdata.DeletionRequest{}
+		has, err := sess.Where("user_id = ?", req.UserID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if has {
+			if existing.Status != userdata.DeletionRejected {
+				return userdata.ErrDeletionAlreadyRequested.Errorf("user %d already requested the deletion of the account", req.UserID)
+			}
+			if _, err := sess.ID(existing.ID).Delete(&userdata.DeletionRequest{}); err != nil {
+				return err
+			}
+		}
+
+		now := s.now()
+		req.Created = now
+		req.Updated = now
+		_, err = sess.Insert(req)
+		return err
+	})
+}
+
+func (s *sqlStore) Review(ctx context.Context, id int64, status userdata.DeletionStatus, reviewerID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.ID(id).Where("status = ?", userdata.DeletionPending).Cols("status", "reviewed_by", "updated").Update(&userdata.DeletionRequest{
+			Status:     status,
+			ReviewedBy: reviewerID,
+			Updated:    s.now(),
+		})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return userdata.ErrDeletionNotPending.Errorf("deletion request %d isn't pending", id)
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) List(ctx context.Context, status userdata.DeletionStatus) ([]*userdata.DeletionRequestDTO, error) {
+	requests := make([]*userdata.DeletionRequestDTO, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		userTable := s.db.GetDialect().Quote("user")
+		q := sess.Table("user_deletion_request").
+			Join("INNER", userTable, "user_deletion_request.user_id = "+userTable+".id").
+			Select("user_deletion_request.*, " + userTable + ".login, " + userTable + ".email, " + userTable + ".name")
+		if status != "" {
+			q = q.Where("user_deletion_request.status = ?", status)
+		}
+		return q.Asc("user_deletion_request.delete_after", "user_deletion_request.id").Find(&requests)
+	})
+	return requests, err
+}
+
+func (s *sqlStore) ListDue(ctx context.Context) ([]*userdata.DeletionRequest, error) {
+	requests := make([]*userdata.DeletionRequest, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("status = ? AND delete_after <= ?", userdata.DeletionApproved, s.now()).Asc("delete_after").Find(&requests)
+	})
+	return requests, err
+}
+
+func (s *sqlStore) DeleteByUser(ctx context.Context, userID int64) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("user_id = ?", userID).Delete(&userdata.DeletionRequest{})
+		return err
+	})
+}
@@ -0,0 +1,59 @@
+package userdatatest
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/userdata"
+)
+
+type FakeService struct {
+	ExpectedExport           *userdata.Export
+	ExpectedDeletionRequest  *userdata.DeletionRequest
+	ExpectedDeletionRequests []*userdata.DeletionRequestDTO
+	ExpectedDueDeletions     []*userdata.DeletionRequest
+	ExpectedError            error
+
+	// DeletedUsers are the users whose deletion request was removed by DeleteByUser
+	DeletedUsers []int64
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{}
+}
+
+func (s *FakeService) Export(ctx context.Context, userID int64) (*userdata.Export, error) {
+	return s.ExpectedExport, s.ExpectedError
+}
+
+func (s *FakeService) RequestDeletion(ctx context.Context, userID int64, cmd *userdata.RequestDeletionCommand) (*userdata.DeletionRequest, error) {
+	return s.ExpectedDeletionRequest, s.ExpectedError
+}
+
+func (s *FakeService) GetDeletionRequest(ctx context.Context, userID int64) (*userdata.DeletionRequest, error) {
+	return s.ExpectedDeletionRequest, s.ExpectedError
+}
+
+func (s *FakeService) CancelDeletion(ctx context.Context, userID int64) error {
+	return s.ExpectedError
+}
+
+func (s *FakeService) ListDeletionRequests(ctx context.Context, status userdata.DeletionStatus) ([]*userdata.DeletionRequestDTO, error) {
+	return s.ExpectedDeletionRequests, s.ExpectedError
+}
+
+func (s *FakeService) ApproveDeletion(ctx context.Context, id, reviewerID int64) (*userdata.DeletionRequest, error) {
+	return s.ExpectedDeletionRequest, s.ExpectedError
+}
+
+func (s *FakeService) RejectDeletion(ctx context.Context, id, reviewerID int64) (*userdata.DeletionRequest, error) {
+	return s.ExpectedDeletionRequest, s.ExpectedError
+}
+
+func (s *FakeService) ListDueDeletions(ctx context.Context) ([]*userdata.DeletionRequest, error) {
+	return s.ExpectedDueDeletions, s.ExpectedError
+}
+
+func (s *FakeService) DeleteByUser(ctx context.Context, userID int64) error {
+	s.DeletedUsers = append(s.DeletedUsers, userID)
+	return s.ExpectedError
+}
@@ -32,9 +32,29 @@ var expanders = []registeredExpander{
 		priority: -5,
 		expander: fileExpander{},
 	},
+	// the external secret stores are resolved after the environment variables and the files,
+	// so that their addresses and credentials can be read from them
+	{
+		name:     "vault",
+		priority: -2,
+		expander: &vaultExpander{},
+	},
+	{
+		name:     "awsssm",
+		priority: -1,
+		expander: &awsSSMExpander{},
+	},
 }
 
+// AddExpander registers an expander, replacing the expander registered with the same name if any.
 func AddExpander(name string, priority int64, e Expander) {
+	for i := range expanders {
+		if expanders[i].name == name {
+			expanders[i] = registeredExpander{name: name, priority: priority, expander: e}
+			return
+		}
+	}
+
 	expanders = append(expanders, registeredExpander{
 		name:     name,
 		priority: priority,
@@ -0,0 +1,85 @@
+package setting
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"gopkg.in/ini.v1"
+)
+
+type ssmParameterGetter interface {
+	GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+}
+
+// awsSSMExpander resolves $__awsssm{<parameter name>} from the AWS Systems Manager Parameter Store,
+// decrypting the SecureString parameters. The credentials are read from the default AWS credential chain.
+// The parameters are read once when the configuration is loaded, and again when it is reloaded.
+type awsSSMExpander struct {
+	mu       sync.Mutex
+	region   string
+	endpoint string
+	client   ssmParameterGetter
+	cache    map[string]string
+}
+
+func (e *awsSSMExpander) SetupExpander(file *ini.File) error {
+	section := file.Section("expanders.aws_ssm")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	region := section.Key("region").MustString("")
+	endpoint := section.Key("endpoint").MustString("")
+	if region != e.region || endpoint != e.endpoint {
+		// the client is created on first use, so that the AWS session is only set up when parameters are referenced
+		e.client = nil
+	}
+	e.region = region
+	e.endpoint = endpoint
+	e.cache = make(map[string]string)
+	return nil
+}
+
+func (e *awsSSMExpander) Expand(s string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if value, ok := e.cache[s]; ok {
+		return value, nil
+	}
+
+	if e.client == nil {
+		cfg := aws.NewConfig()
+		if e.region != "" {
+			cfg = cfg.WithRegion(e.region)
+		}
+		if e.endpoint != "" {
+			cfg = cfg.WithEndpoint(e.endpoint)
+		}
+		sess, err := session.NewSessionWithOptions(session.Options{Config: *cfg, SharedConfigState: session.SharedConfigEnable})
+		if err != nil {
+			return "", fmt.Errorf("failed to create the AWS session: %w", err)
+		}
+		e.client = ssm.New(sess)
+	}
+
+	out, err := e.client.GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(s),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read the SSM parameter %q: %w", s, err)
+	}
+	if out.Parameter == nil || out.Parameter.Value == nil {
+		return "", fmt.Errorf("SSM parameter %q has no value", s)
+	}
+
+	if e.cache == nil {
+		e.cache = make(map[string]string)
+	}
+	e.cache[s] = *out.Parameter.Value
+	return *out.Parameter.Value, nil
+}
@@ -0,0 +1,89 @@
+package setting
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestVaultExpander(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "s.token", r.Header.Get("X-Vault-Token"))
+		if r.URL.Path != "/v1/secret/data/grafana/database" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"user":"grafana","password":"s3cr3t"},"metadata":{"version":3}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	file, err := ini.Load([]byte(`
+[expanders.vault]
+address = ` + server.URL + `
+token = s.token
+
+[database]
+user = $__vault{grafana/database:user}
+password = $__vault{grafana/database:password}
+`))
+	require.NoError(t, err)
+
+	require.NoError(t, expandConfig(file))
+	require.Equal(t, "grafana", file.Section("database").Key("user").String())
+	require.Equal(t, "s3cr3t", file.Section("database").Key("password").String())
+	require.Equal(t, 1, requests, "the secret should be read once per path")
+
+	e := &vaultExpander{}
+	require.NoError(t, e.SetupExpander(file))
+	_, err = e.Expand("grafana/database:missing")
+	require.Error(t, err)
+	_, err = e.Expand("grafana/other:password")
+	require.Error(t, err)
+	_, err = e.Expand("grafana/database")
+	require.Error(t, err)
+}
+
+type fakeSSMClient struct {
+	values map[string]string
+	calls  int
+}
+
+func (c *fakeSSMClient) GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	c.calls++
+	value, ok := c.values[*input.Name]
+	if !ok {
+		return nil, &ssm.ParameterNotFound{}
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Name: input.Name, Value: aws.String(value)}}, nil
+}
+
+func TestAWSSSMExpander(t *testing.T) {
+	client := &fakeSSMClient{values: map[string]string{"/grafana/admin_password": "s3cr3t"}}
+	e := &awsSSMExpander{}
+	require.NoError(t, e.SetupExpander(ini.Empty()))
+	e.client = client
+
+	value, err := e.Expand("/grafana/admin_password")
+	require.NoError(t, err)
+	require.Equal(t, "s3cr3t", value)
+
+	_, err = e.Expand("/grafana/admin_password")
+	require.NoError(t, err)
+	require.Equal(t, 1, client.calls, "the parameter should be cached until the configuration is reloaded")
+
+	_, err = e.Expand("/grafana/missing")
+	require.Error(t, err)
+
+	require.NoError(t, e.SetupExpander(ini.Empty()))
+	_, err = e.Expand("/grafana/admin_password")
+	require.NoError(t, err)
+	require.Equal(t, 3, client.calls)
+}
@@ -0,0 +1,125 @@
+package setting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// vaultExpander resolves $__vault{<path>:<key>} from the KV secrets engine of HashiCorp Vault.
+// The secrets are read once per path when the configuration is loaded, and again when it is reloaded.
+type vaultExpander struct {
+	mu        sync.Mutex
+	address   string
+	token     string
+	namespace string
+	mount     string
+	kvVersion int
+	client    *http.Client
+	cache     map[string]map[string]interface{}
+}
+
+func (e *vaultExpander) SetupExpander(file *ini.File) error {
+	section := file.Section("expanders.vault")
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.address = strings.TrimSuffix(section.Key("address").MustString(os.Getenv("VAULT_ADDR")), "/")
+	e.token = section.Key("token").MustString(os.Getenv("VAULT_TOKEN"))
+	e.namespace = section.Key("namespace").MustString(os.Getenv("VAULT_NAMESPACE"))
+	e.mount = strings.Trim(section.Key("mount").MustString("secret"), "/")
+	e.kvVersion = section.Key("kv_version").MustInt(2)
+	if e.kvVersion != 1 && e.kvVersion != 2 {
+		return fmt.Errorf("unsupported kv_version %d, expected 1 or 2", e.kvVersion)
+	}
+	e.client = &http.Client{Timeout: section.Key("timeout").MustDuration(10 * time.Second)}
+	e.cache = make(map[string]map[string]interface{})
+	return nil
+}
+
+func (e *vaultExpander) Expand(s string) (string, error) {
+	path, key, ok := strings.Cut(s, ":")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("invalid vault reference %q, expected <path>:<key>", s)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.address == "" {
+		return "", fmt.Errorf("the vault address is not configured")
+	}
+
+	secret, ok := e.cache[path]
+	if !ok {
+		var err error
+		if secret, err = e.read(path); err != nil {
+			return "", err
+		}
+		if e.cache == nil {
+			e.cache = make(map[string]map[string]interface{})
+		}
+		e.cache[path] = secret
+	}
+
+	value, ok := secret[key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in vault secret %q", key, path)
+	}
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+func (e *vaultExpander) read(path string) (map[string]interface{}, error) {
+	path = strings.Trim(path, "/")
+	if e.kvVersion == 2 {
+		path = "data/" + path
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s/v1/%s/%s", e.address, e.mount, path))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", e.token)
+	if e.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", e.namespace)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault secret %q: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read vault secret %q: unexpected status %d", path, resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault secret %q: %w", path, err)
+	}
+
+	// the KV version 2 engine nests the secret in data.data, next to its metadata
+	if e.kvVersion == 2 {
+		data, _ := body.Data["data"].(map[string]interface{})
+		return data, nil
+	}
+	return body.Data, nil
+}
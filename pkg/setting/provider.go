@@ -3,6 +3,7 @@ package setting
 import (
 	"errors"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/ini.v1"
@@ -89,6 +90,14 @@ type ReloadHandler interface {
 	Validate(section Section) error
 }
 
+// ReloadHandlerRegistry is implemented by the providers that keep the
+// registered reload handlers, so that they can be called when the
+// configuration files are reloaded.
+type ReloadHandlerRegistry interface {
+	// ReloadHandlers returns the handlers registered for the section.
+	ReloadHandlers(section string) []ReloadHandler
+}
+
 type SettingsBag map[string]map[string]string
 type SettingsRemovals map[string][]string
 
@@ -100,9 +109,12 @@ func ProvideProvider(cfg *Cfg) *OSSImpl {
 
 type OSSImpl struct {
 	Cfg *Cfg
+
+	mu       sync.RWMutex
+	handlers map[string][]ReloadHandler
 }
 
-func (o OSSImpl) Current() SettingsBag {
+func (o *OSSImpl) Current() SettingsBag {
 	settingsCopy := make(SettingsBag)
 
 	for _, section := range o.Cfg.Raw.Sections() {
@@ -115,7 +127,7 @@ func (o OSSImpl) Current() SettingsBag {
 	return settingsCopy
 }
 
-func (OSSImpl) Update(SettingsBag, SettingsRemovals) error {
+func (*OSSImpl) Update(SettingsBag, SettingsRemovals) error {
 	return errors.New("oss settings provider do not have support for settings updates")
 }
 
@@ -127,9 +139,24 @@ func (o *OSSImpl) Section(section string) Section {
 	return &sectionImpl{section: o.Cfg.Raw.Section(section)}
 }
 
-func (OSSImpl) RegisterReloadHandler(string, ReloadHandler) {}
+func (o *OSSImpl) RegisterReloadHandler(section string, handler ReloadHandler) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.handlers == nil {
+		o.handlers = make(map[string][]ReloadHandler)
+	}
+	o.handlers[section] = append(o.handlers[section], handler)
+}
+
+func (o *OSSImpl) ReloadHandlers(section string) []ReloadHandler {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return append([]ReloadHandler(nil), o.handlers[section]...)
+}
 
-func (o OSSImpl) IsFeatureToggleEnabled(name string) bool {
+func (o *OSSImpl) IsFeatureToggleEnabled(name string) bool {
 	return o.Cfg.IsFeatureToggleEnabled(name)
 }
 
@@ -19,6 +19,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gobwas/glob"
@@ -148,25 +149,35 @@ type Cfg struct {
 	Raw    *ini.File
 	Logger log.Logger
 
+	// where the overridden keys of Raw come from
+	configSources configSources
+	// the arguments the configuration was loaded with, to read it again on reload
+	args CommandLineArgs
+
 	// HTTP Server Settings
-	CertFile         string
-	KeyFile          string
-	HTTPAddr         string
-	HTTPPort         string
-	AppURL           string
-	AppSubURL        string
-	ServeFromSubPath bool
-	StaticRootPath   string
-	Protocol         Scheme
-	SocketGid        int
-	SocketMode       int
-	SocketPath       string
-	RouterLogging    bool
-	Domain           string
-	CDNRootURL       *url.URL
-	ReadTimeout      time.Duration
-	EnableGzip       bool
-	EnforceDomain    bool
+	CertFile          string
+	KeyFile           string
+	HTTPAddr          string
+	HTTPPort          string
+	AppURL            string
+	AppSubURL         string
+	ServeFromSubPath  bool
+	StaticRootPath    string
+	Protocol          Scheme
+	SocketGid         int
+	SocketMode        int
+	SocketPath        string
+	RouterLogging     bool
+	Domain            string
+	CDNRootURL        *url.URL
+	ReadTimeout       time.Duration
+	EnableGzip        bool
+	IdempotencyKeyTTL time.Duration
+	EnforceDomain     bool
+	// ShutdownDrainDelay is how long the server reports not ready before it stops accepting requests
+	ShutdownDrainDelay time.Duration
+	// ShutdownTimeout is how long the requests and alert evaluations in progress are given to complete on shutdown
+	ShutdownTimeout time.Duration
 
 	// Security settings
 	SecretKey             string
@@ -202,10 +213,13 @@ type Cfg struct {
 	RendererAuthToken              string
 	RendererConcurrentRequestLimit int
 	RendererRenderKeyLifeTime      time.Duration
+	// RendererSignedURLMaxExpiration is the maximum lifetime of the signed render URLs.
+	RendererSignedURLMaxExpiration time.Duration
 
 	// Security
 	DisableInitAdminCreation          bool
 	DisableBruteForceLoginProtection  bool
+	BruteForceLoginProtection         BruteForceLoginProtectionSettings
 	CookieSecure                      bool
 	CookieSameSiteDisabled            bool
 	CookieSameSiteMode                http.SameSite
@@ -249,10 +263,25 @@ type Cfg struct {
 	MetricsEndpointBasicAuthUsername string
 	MetricsEndpointBasicAuthPassword string
 	MetricsEndpointDisableTotalStats bool
+	MetricsNativeHistograms          bool
 	MetricsGrafanaEnvironmentInfo    map[string]string
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	RestrictViewerQueries    bool
+	// DashboardSaveWebhooks are called in order with the dashboards about to be saved, to validate or modify them.
+	DashboardSaveWebhooks        []string
+	DashboardSaveWebhookTimeout  time.Duration
+	DashboardSaveWebhookFailOpen bool
+	// DashboardLintOnSaveSeverity rejects the saved dashboards with lint findings of this severity or higher, if set.
+	DashboardLintOnSaveSeverity string
+	DashboardLintDisabledRules  []string
+
+	// Public dashboards
+	PublicDashboardsCDNCacheTTL     time.Duration
+	PublicDashboardsCDNPurgeURL     string
+	PublicDashboardsCDNPurgeHeader  string
+	PublicDashboardsCDNPurgeTimeout time.Duration
 
 	// Auth
 	LoginCookieName              string
@@ -289,6 +318,12 @@ type Cfg struct {
 	AuthProxyHeaders          map[string]string
 	AuthProxyHeadersEncoded   bool
 	AuthProxySyncTTL          int
+	// AuthProxySignature is the optional verification of the signature of the auth proxy headers
+	AuthProxySignature AuthProxySignatureSettings
+	// AuthProxyRoleMapping maps the values of the role header to Grafana roles
+	AuthProxyRoleMapping map[string]string
+	// AuthProxyGroupsMapping maps the values of the groups header to the groups used by team sync
+	AuthProxyGroupsMapping map[string]string
 
 	// OAuth
 	OAuthAutoLogin    bool
@@ -333,6 +368,8 @@ type Cfg struct {
 	EditorsCanAdmin bool
 
 	ApiKeyMaxSecondsToLive int64
+	// AllowedAuthMethods is the comma-separated list of the authentication methods the users can sign in with, all of them when empty
+	AllowedAuthMethods string
 
 	// Check if a feature toggle is enabled
 	// @deprecated
@@ -350,6 +387,13 @@ type Cfg struct {
 	HiddenUsers           map[string]struct{}
 	CaseInsensitiveLogin  bool // Login and Email will be considered case insensitive
 
+	// Inactive users policy, 0 days disables the policy
+	InactiveUserDisableAfterDays int64
+	InactiveUserAction           string
+	InactiveUserNotifyBeforeDays int64
+	InactiveUserExemptions       string // comma-separated logins or emails
+	InactiveUserPolicyInterval   time.Duration
+
 	// Service Accounts
 	SATokenExpirationDayLimit int
 
@@ -359,6 +403,7 @@ type Cfg struct {
 	AlertingAnnotationCleanupSetting   AnnotationCleanupSettings
 	DashboardAnnotationCleanupSettings AnnotationCleanupSettings
 	APIAnnotationCleanupSettings       AnnotationCleanupSettings
+	AnnotationFederation               AnnotationFederationSettings
 
 	// Sentry config
 	Sentry Sentry
@@ -378,6 +423,16 @@ type Cfg struct {
 
 	SnapshotPublicMode bool
 
+	// ExternalSnapshotToken authenticates this instance to the external snapshot server.
+	ExternalSnapshotToken string
+
+	// Snapshot server mode, where this instance acts as the external snapshot server of other instances.
+	SnapshotServerEnabled bool
+	// SnapshotServerTokens maps the tokens of the instances to the organizations storing their snapshots.
+	SnapshotServerTokens   map[string]int64
+	SnapshotServerMaxTTL   time.Duration
+	SnapshotServerOrgQuota int64
+
 	ErrTemplateName string
 
 	Env string
@@ -475,7 +530,8 @@ type Cfg struct {
 	UnifiedAlerting UnifiedAlertingSettings
 
 	// Query history
-	QueryHistoryEnabled bool
+	QueryHistoryEnabled       bool
+	QueryHistoryRetentionDays int64
 
 	DashboardPreviews DashboardPreviewsSettings
 
@@ -483,6 +539,37 @@ type Cfg struct {
 
 	Search SearchSettings
 
+	// RateLimiting are the rate limiting settings the server started with, CurrentRateLimiting returns the
+	// settings with the reloaded limits.
+	RateLimiting RateLimitingSettings
+	// reloadedRateLimiting are the rate limiting settings published by ApplySection
+	reloadedRateLimiting atomic.Value
+
+	RequestLimits RequestLimitsSettings
+
+	OutboundHTTP OutboundHTTPSettings
+
+	Audit AuditSettings
+
+	OrgTemplate OrgTemplateSettings
+	OrgHooks    OrgHooksSettings
+
+	EventOutbox EventOutboxSettings
+
+	Cleanup CleanupSettings
+
+	DashboardVersionArchive DashboardVersionArchiveSettings
+
+	ObjectStorage ObjectStorageSettings
+
+	OAuthServer OAuthServerSettings
+
+	AccountDeletion AccountDeletionSettings
+
+	UsageStatsExport UsageStatsExportSettings
+
+	SettingsReload SettingsReloadSettings
+
 	SecureSocksDSProxy SecureSocksDSProxySettings
 
 	// SAML Auth
@@ -553,11 +640,22 @@ func RedactedValue(key, value string) string {
 		"ACCOUNT_KEY",
 		"ENCRYPTION_KEY",
 		"VAULT_TOKEN",
+		"SIGNATURE_KEYS?$",
+		"HTTP_HEADERS$",
+		"PURGE_HEADER$",
 	} {
 		if match, err := regexp.MatchString(pattern, uppercased); match && err == nil {
 			return RedactedPassword
 		}
 	}
+	// the switches of the token features are not secrets
+	if match, err := regexp.MatchString("TOKENS?$", uppercased); match && err == nil && !strings.Contains(uppercased, "ENABLE") {
+		return RedactedPassword
+	}
+	// the quotas of the API keys are not secrets
+	if match, err := regexp.MatchString("API_KEY$", uppercased); match && err == nil && !strings.Contains(uppercased, "QUOTA") {
+		return RedactedPassword
+	}
 
 	for _, exception := range []string{
 		"RUDDERSTACK",
@@ -609,7 +707,7 @@ func RedactedURL(value string) (string, error) {
 	return strings.Join(chunks, " "), nil
 }
 
-func applyEnvVariableOverrides(file *ini.File) error {
+func applyEnvVariableOverrides(file *ini.File, sources configSources) error {
 	appliedEnvOverrides = make([]string, 0)
 	for _, section := range file.Sections() {
 		for _, key := range section.Keys() {
@@ -618,6 +716,7 @@ func applyEnvVariableOverrides(file *ini.File) error {
 
 			if len(envValue) > 0 {
 				key.SetValue(envValue)
+				sources.set(section.Name(), key.Name(), ConfigSourceEnv)
 				appliedEnvOverrides = append(appliedEnvOverrides, fmt.Sprintf("%s=%s", envKey, RedactedValue(envKey, envValue)))
 			}
 		}
@@ -705,7 +804,7 @@ func EnvKey(sectionName string, keyName string) string {
 	return envKey
 }
 
-func applyCommandLineDefaultProperties(props map[string]string, file *ini.File) {
+func applyCommandLineDefaultProperties(props map[string]string, file *ini.File, sources configSources) {
 	appliedCommandLineProperties = make([]string, 0)
 	for _, section := range file.Sections() {
 		for _, key := range section.Keys() {
@@ -713,6 +812,7 @@ func applyCommandLineDefaultProperties(props map[string]string, file *ini.File)
 			value, exists := props[keyString]
 			if exists {
 				key.SetValue(value)
+				sources.set(section.Name(), key.Name(), ConfigSourceCommandLine)
 				appliedCommandLineProperties = append(appliedCommandLineProperties,
 					fmt.Sprintf("%s=%s", keyString, RedactedValue(keyString, value)))
 			}
@@ -720,7 +820,7 @@ func applyCommandLineDefaultProperties(props map[string]string, file *ini.File)
 	}
 }
 
-func applyCommandLineProperties(props map[string]string, file *ini.File) {
+func applyCommandLineProperties(props map[string]string, file *ini.File, sources configSources) {
 	for _, section := range file.Sections() {
 		sectionName := section.Name() + "."
 		if section.Name() == ini.DefaultSection {
@@ -732,6 +832,7 @@ func applyCommandLineProperties(props map[string]string, file *ini.File) {
 			if exists {
 				appliedCommandLineProperties = append(appliedCommandLineProperties, fmt.Sprintf("%s=%s", keyString, value))
 				key.SetValue(value)
+				sources.set(section.Name(), key.Name(), ConfigSourceCommandLine)
 			}
 		}
 	}
@@ -795,6 +896,7 @@ func (cfg *Cfg) loadSpecifiedConfigFile(configFile string, masterFile *ini.File)
 				defaultKey, _ = defaultSec.NewKey(key.Name(), key.Value())
 			}
 			defaultKey.SetValue(key.Value())
+			cfg.configSources.set(section.Name(), key.Name(), ConfigSourceFile)
 		}
 	}
 
@@ -822,11 +924,12 @@ func (cfg *Cfg) loadConfiguration(args CommandLineArgs) (*ini.File, error) {
 	}
 
 	parsedFile.BlockMode = false
+	cfg.configSources = make(configSources)
 
 	// command line props
 	commandLineProps := cfg.getCommandLineProperties(args.Args)
 	// load default overrides
-	applyCommandLineDefaultProperties(commandLineProps, parsedFile)
+	applyCommandLineDefaultProperties(commandLineProps, parsedFile, cfg.configSources)
 
 	// load specified config file
 	err = cfg.loadSpecifiedConfigFile(args.Config, parsedFile)
@@ -840,13 +943,13 @@ func (cfg *Cfg) loadConfiguration(args CommandLineArgs) (*ini.File, error) {
 	}
 
 	// apply environment overrides
-	err = applyEnvVariableOverrides(parsedFile)
+	err = applyEnvVariableOverrides(parsedFile, cfg.configSources)
 	if err != nil {
 		return nil, err
 	}
 
 	// apply command line overrides
-	applyCommandLineProperties(commandLineProps, parsedFile)
+	applyCommandLineProperties(commandLineProps, parsedFile, cfg.configSources)
 
 	// evaluate config values containing environment variables
 	err = expandConfig(parsedFile)
@@ -939,6 +1042,7 @@ func (cfg *Cfg) validateStaticRootPath() error {
 }
 
 func (cfg *Cfg) Load(args CommandLineArgs) error {
+	cfg.args = args
 	cfg.setHomePath(args)
 
 	// Fix for missing IANA db on Windows
@@ -1006,6 +1110,18 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.RestrictViewerQueries = dashboards.Key("restrict_viewer_queries").MustBool(false)
+	cfg.DashboardSaveWebhooks = util.SplitString(dashboards.Key("save_webhook_urls").MustString(""))
+	cfg.DashboardSaveWebhookTimeout = dashboards.Key("save_webhook_timeout").MustDuration(5 * time.Second)
+	cfg.DashboardSaveWebhookFailOpen = dashboards.Key("save_webhook_fail_open").MustBool(false)
+	cfg.DashboardLintOnSaveSeverity = dashboards.Key("lint_on_save_severity").MustString("")
+	cfg.DashboardLintDisabledRules = util.SplitString(dashboards.Key("lint_disabled_rules").MustString(""))
+
+	publicDashboards := iniFile.Section("public_dashboards")
+	cfg.PublicDashboardsCDNCacheTTL = publicDashboards.Key("cdn_cache_ttl").MustDuration(time.Minute)
+	cfg.PublicDashboardsCDNPurgeURL = publicDashboards.Key("cdn_purge_url").MustString("")
+	cfg.PublicDashboardsCDNPurgeHeader = publicDashboards.Key("cdn_purge_header").MustString("")
+	cfg.PublicDashboardsCDNPurgeTimeout = publicDashboards.Key("cdn_purge_timeout").MustDuration(10 * time.Second)
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1026,6 +1142,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.MetricsEndpointBasicAuthUsername = valueAsString(iniFile.Section("metrics"), "basic_auth_username", "")
 	cfg.MetricsEndpointBasicAuthPassword = valueAsString(iniFile.Section("metrics"), "basic_auth_password", "")
 	cfg.MetricsEndpointDisableTotalStats = iniFile.Section("metrics").Key("disable_total_stats").MustBool(false)
+	cfg.MetricsNativeHistograms = iniFile.Section("metrics").Key("native_histograms").MustBool(false)
 
 	analytics := iniFile.Section("analytics")
 	cfg.CheckForGrafanaUpdates = analytics.Key("check_for_updates").MustBool(true)
@@ -1067,6 +1184,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(true)
+	cfg.QueryHistoryRetentionDays = queryHistory.Key("retention_days").MustInt64(14)
 
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
@@ -1110,6 +1228,25 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	cfg.DashboardPreviews = readDashboardPreviewsSettings(iniFile)
 	cfg.Storage = readStorageSettings(iniFile)
 	cfg.Search = readSearchSettings(iniFile)
+	cfg.RateLimiting = readRateLimitingSettings(iniFile)
+	cfg.RequestLimits = readRequestLimitsSettings(iniFile)
+	cfg.OutboundHTTP = readOutboundHTTPSettings(iniFile)
+	cfg.Audit = readAuditSettings(iniFile, cfg.LogsPath)
+	cfg.OrgTemplate = readOrgTemplateSettings(iniFile)
+	cfg.OrgHooks = readOrgHooksSettings(iniFile)
+	cfg.EventOutbox = readEventOutboxSettings(iniFile)
+	cfg.AnnotationFederation = readAnnotationFederationSettings(iniFile)
+	cfg.SettingsReload = readSettingsReloadSettings(iniFile)
+	cfg.Cleanup = readCleanupSettings(iniFile, cfg)
+	cfg.DashboardVersionArchive = readDashboardVersionArchiveSettings(iniFile)
+	cfg.ObjectStorage = readObjectStorageSettings(iniFile)
+	cfg.OAuthServer = readOAuthServerSettings(iniFile)
+	cfg.AccountDeletion = readAccountDeletionSettings(iniFile)
+
+	cfg.UsageStatsExport, err = readUsageStatsExportSettings(iniFile)
+	if err != nil {
+		return err
+	}
 
 	cfg.SecureSocksDSProxy, err = readSecureSocksDSProxySettings(iniFile)
 	if err != nil {
@@ -1237,6 +1374,12 @@ func (cfg *Cfg) readSessionConfig() {
 }
 
 func (cfg *Cfg) initLogging(file *ini.File) error {
+	logsPath := valueAsString(file.Section("paths"), "logs", "")
+	cfg.LogsPath = makeAbsolute(logsPath, HomePath)
+	return log.ReadLoggingConfig(logModes(file), cfg.LogsPath, file)
+}
+
+func logModes(file *ini.File) []string {
 	logModeStr := valueAsString(file.Section("log"), "mode", "console")
 	// split on comma
 	logModes := strings.Split(logModeStr, ",")
@@ -1244,9 +1387,7 @@ func (cfg *Cfg) initLogging(file *ini.File) error {
 	if len(logModes) == 1 {
 		logModes = strings.Split(logModeStr, " ")
 	}
-	logsPath := valueAsString(file.Section("paths"), "logs", "")
-	cfg.LogsPath = makeAbsolute(logsPath, HomePath)
-	return log.ReadLoggingConfig(logModes, cfg.LogsPath, file)
+	return logModes
 }
 
 func (cfg *Cfg) LogConfigSources() {
@@ -1312,6 +1453,7 @@ func readSecuritySettings(iniFile *ini.File, cfg *Cfg) error {
 	cfg.SecretKey = SecretKey
 	DisableGravatar = security.Key("disable_gravatar").MustBool(true)
 	cfg.DisableBruteForceLoginProtection = security.Key("disable_brute_force_login_protection").MustBool(false)
+	cfg.BruteForceLoginProtection = readBruteForceLoginProtectionSettings(security)
 
 	CookieSecure = security.Key("cookie_secure").MustBool(false)
 	cfg.CookieSecure = CookieSecure
@@ -1429,6 +1571,7 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 	}
 
 	cfg.ApiKeyMaxSecondsToLive = auth.Key("api_key_max_seconds_to_live").MustInt64(-1)
+	cfg.AllowedAuthMethods = auth.Key("allowed_auth_methods").MustString("")
 
 	cfg.TokenRotationIntervalMinutes = auth.Key("token_rotation_interval_minutes").MustInt(10)
 	if cfg.TokenRotationIntervalMinutes < 2 {
@@ -1519,18 +1662,19 @@ func readAuthSettings(iniFile *ini.File, cfg *Cfg) (err error) {
 
 	cfg.AuthProxyWhitelist = valueAsString(authProxy, "whitelist", "")
 
-	cfg.AuthProxyHeaders = make(map[string]string)
-	headers := valueAsString(authProxy, "headers", "")
-
-	for _, propertyAndHeader := range util.SplitString(headers) {
-		split := strings.SplitN(propertyAndHeader, ":", 2)
-		if len(split) == 2 {
-			cfg.AuthProxyHeaders[split[0]] = split[1]
-		}
-	}
+	cfg.AuthProxyHeaders = parseAuthProxyMapping(valueAsString(authProxy, "headers", ""))
 
 	cfg.AuthProxyHeadersEncoded = authProxy.Key("headers_encoded").MustBool(false)
 
+	cfg.AuthProxySignature = AuthProxySignatureSettings{
+		Header: valueAsString(authProxy, "signature_header", ""),
+		Type:   valueAsString(authProxy, "signature_type", "hmac"),
+		Keys:   util.SplitString(valueAsString(authProxy, "signature_keys", "")),
+		MaxAge: authProxy.Key("signature_max_age").MustDuration(5 * time.Minute),
+	}
+	cfg.AuthProxyRoleMapping = parseAuthProxyMapping(valueAsString(authProxy, "role_mapping", ""))
+	cfg.AuthProxyGroupsMapping = parseAuthProxyMapping(valueAsString(authProxy, "groups_mapping", ""))
+
 	// GrafanaCom
 	readAuthGrafanaComSettings(iniFile, cfg)
 
@@ -1581,6 +1725,15 @@ func readUserSettings(iniFile *ini.File, cfg *Cfg) error {
 		return errors.New("the minimum supported value for the `user_invite_max_lifetime_duration` configuration is 15m (15 minutes)")
 	}
 
+	cfg.InactiveUserDisableAfterDays = users.Key("inactive_user_disable_after_days").MustInt64(0)
+	cfg.InactiveUserAction = users.Key("inactive_user_action").In("disable", []string{"disable", "downgrade"})
+	cfg.InactiveUserNotifyBeforeDays = users.Key("inactive_user_notify_before_days").MustInt64(7)
+	cfg.InactiveUserExemptions = valueAsString(users, "inactive_user_exemptions", "")
+	cfg.InactiveUserPolicyInterval = users.Key("inactive_user_policy_interval").MustDuration(time.Hour)
+	if cfg.InactiveUserDisableAfterDays < 0 || cfg.InactiveUserNotifyBeforeDays < 0 {
+		return errors.New("`inactive_user_disable_after_days` and `inactive_user_notify_before_days` cannot be negative")
+	}
+
 	cfg.HiddenUsers = make(map[string]struct{})
 	hiddenUsers := users.Key("hidden_users").MustString("")
 	for _, user := range strings.Split(hiddenUsers, ",") {
@@ -1621,6 +1774,7 @@ func (cfg *Cfg) readRenderingSettings(iniFile *ini.File) error {
 
 	cfg.RendererConcurrentRequestLimit = renderSec.Key("concurrent_render_request_limit").MustInt(30)
 	cfg.RendererRenderKeyLifeTime = renderSec.Key("render_key_lifetime").MustDuration(5 * time.Minute)
+	cfg.RendererSignedURLMaxExpiration = renderSec.Key("signed_url_max_expiration").MustDuration(24 * time.Hour)
 	cfg.ImagesDir = filepath.Join(cfg.DataPath, "png")
 	cfg.CSVsDir = filepath.Join(cfg.DataPath, "csv")
 
@@ -1729,6 +1883,24 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 	cfg.ExternalEnabled = snapshots.Key("external_enabled").MustBool(true)
 	cfg.SnapShotRemoveExpired = snapshots.Key("snapshot_remove_expired").MustBool(true)
 	cfg.SnapshotPublicMode = snapshots.Key("public_mode").MustBool(false)
+	cfg.ExternalSnapshotToken = valueAsString(snapshots, "external_snapshot_token", "")
+
+	cfg.SnapshotServerEnabled = snapshots.Key("external_server_enabled").MustBool(false)
+	cfg.SnapshotServerTokens = map[string]int64{}
+	for _, entry := range util.SplitString(valueAsString(snapshots, "external_server_tokens", "")) {
+		orgID, token, ok := strings.Cut(entry, ":")
+		id, err := strconv.ParseInt(orgID, 10, 64)
+		if !ok || err != nil || token == "" {
+			return errors.New("invalid external_server_tokens entry, expected <org id>:<token>")
+		}
+		cfg.SnapshotServerTokens[token] = id
+	}
+	maxTTL, err := gtime.ParseDuration(valueAsString(snapshots, "external_server_max_ttl", "0"))
+	if err != nil {
+		return fmt.Errorf("invalid external_server_max_ttl: %w", err)
+	}
+	cfg.SnapshotServerMaxTTL = maxTTL
+	cfg.SnapshotServerOrgQuota = snapshots.Key("external_server_org_quota").MustInt64(0)
 
 	return nil
 }
@@ -1790,6 +1962,9 @@ func (cfg *Cfg) readServerSettings(iniFile *ini.File) error {
 	}
 
 	cfg.ReadTimeout = server.Key("read_timeout").MustDuration(0)
+	cfg.IdempotencyKeyTTL = server.Key("idempotency_key_ttl").MustDuration(24 * time.Hour)
+	cfg.ShutdownDrainDelay = server.Key("shutdown_drain_delay").MustDuration(0)
+	cfg.ShutdownTimeout = server.Key("shutdown_timeout").MustDuration(30 * time.Second)
 
 	headersSection := cfg.Raw.Section("server.custom_response_headers")
 	keys := headersSection.Keys()
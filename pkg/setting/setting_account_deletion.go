@@ -0,0 +1,29 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// AccountDeletionSettings configure the deletion of the accounts requested by the users themselves.
+type AccountDeletionSettings struct {
+	Enabled bool
+	// GracePeriod is how long after the request the account is deleted, the users can cancel the request until then
+	GracePeriod time.Duration
+	// RequireApproval makes the requests wait for the approval of a server admin
+	RequireApproval bool
+}
+
+func readAccountDeletionSettings(iniFile *ini.File) AccountDeletionSettings {
+	section := iniFile.Section("account_deletion")
+	s := AccountDeletionSettings{
+		Enabled:         section.Key("enabled").MustBool(false),
+		GracePeriod:     section.Key("grace_period").MustDuration(30 * 24 * time.Hour),
+		RequireApproval: section.Key("require_approval").MustBool(true),
+	}
+	if s.GracePeriod < 0 {
+		s.GracePeriod = 0
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package setting
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const annotationSourceSectionPrefix = "annotations.federation.source."
+
+// AnnotationFederationSettings configure the external annotation sources merged into the annotations API.
+type AnnotationFederationSettings struct {
+	Enabled bool
+	// Timeout is the timeout of the sources without their own
+	Timeout time.Duration
+	Sources []AnnotationSourceSettings
+}
+
+// AnnotationSourceSettings configure an external annotation source, read from an
+// [annotations.federation.source.<name>] section.
+type AnnotationSourceSettings struct {
+	Name string
+	// Type is grafana for another Grafana instance, http for an API returning the generic annotation format
+	Type   string
+	URL    string
+	APIKey string
+	// OrgIDs are the organizations the annotations of the source are merged into, all of them when empty
+	OrgIDs  []int64
+	Timeout time.Duration
+}
+
+func readAnnotationFederationSettings(iniFile *ini.File) AnnotationFederationSettings {
+	section := iniFile.Section("annotations.federation")
+	settings := AnnotationFederationSettings{
+		Enabled: section.Key("enabled").MustBool(false),
+		Timeout: section.Key("timeout").MustDuration(5 * time.Second),
+	}
+
+	for _, source := range iniFile.Sections() {
+		if !strings.HasPrefix(source.Name(), annotationSourceSectionPrefix) {
+			continue
+		}
+		orgIDs := make([]int64, 0)
+		for _, id := range util.SplitString(source.Key("org_ids").MustString("")) {
+			if orgID, err := strconv.ParseInt(id, 10, 64); err == nil {
+				orgIDs = append(orgIDs, orgID)
+			}
+		}
+		settings.Sources = append(settings.Sources, AnnotationSourceSettings{
+			Name:    strings.TrimPrefix(source.Name(), annotationSourceSectionPrefix),
+			Type:    source.Key("type").MustString("grafana"),
+			URL:     strings.TrimSuffix(source.Key("url").MustString(""), "/"),
+			APIKey:  source.Key("api_key").MustString(""),
+			OrgIDs:  orgIDs,
+			Timeout: source.Key("timeout").MustDuration(settings.Timeout),
+		})
+	}
+	return settings
+}
@@ -0,0 +1,62 @@
+package setting
+
+import (
+	"path/filepath"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type AuditSettings struct {
+	Enabled bool
+	// Sinks are the destinations of the audit events, any of "file", "loki" and "syslog"
+	Sinks []string
+	// RedactFields are the patterns of the event fields whose values are redacted
+	RedactFields []string
+	// BufferSize is the number of events waiting to be written before the callers are blocked
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+
+	FilePath string
+
+	LokiURL      string
+	LokiTenantID string
+	LokiTimeout  time.Duration
+
+	SyslogNetwork string
+	SyslogAddress string
+	SyslogTag     string
+}
+
+func readAuditSettings(iniFile *ini.File, logsPath string) AuditSettings {
+	section := iniFile.Section("audit")
+	s := AuditSettings{
+		Enabled:       section.Key("enabled").MustBool(false),
+		Sinks:         util.SplitString(section.Key("sinks").MustString("file")),
+		RedactFields:  util.SplitString(section.Key("redact_fields").MustString("password, *token*, *secret*, *apikey*")),
+		BufferSize:    section.Key("buffer_size").MustInt(1000),
+		BatchSize:     section.Key("batch_size").MustInt(100),
+		FlushInterval: section.Key("flush_interval").MustDuration(5 * time.Second),
+	}
+	if s.FlushInterval <= 0 {
+		s.FlushInterval = 5 * time.Second
+	}
+
+	fileSection := iniFile.Section("audit.file")
+	s.FilePath = fileSection.Key("path").MustString(filepath.Join(logsPath, "audit.log"))
+
+	lokiSection := iniFile.Section("audit.loki")
+	s.LokiURL = lokiSection.Key("url").MustString("")
+	s.LokiTenantID = lokiSection.Key("tenant_id").MustString("")
+	s.LokiTimeout = lokiSection.Key("timeout").MustDuration(10 * time.Second)
+
+	syslogSection := iniFile.Section("audit.syslog")
+	s.SyslogNetwork = syslogSection.Key("network").MustString("")
+	s.SyslogAddress = syslogSection.Key("address").MustString("")
+	s.SyslogTag = syslogSection.Key("tag").MustString("grafana-audit")
+
+	return s
+}
@@ -0,0 +1,33 @@
+package setting
+
+import (
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// AuthProxySignatureSettings configure the verification of the signature sent by the auth proxy with its headers,
+// so that the headers cannot be spoofed by a client that reaches Grafana without going through the proxy.
+type AuthProxySignatureSettings struct {
+	// Header is the name of the header of the signature, the verification is disabled when it is empty
+	Header string
+	// Type of signature, hmac or jwt
+	Type string
+	// Keys are the shared secrets accepted for the signature, several keys can be accepted while they are rotated
+	Keys []string
+	// MaxAge is the maximum age of a signature
+	MaxAge time.Duration
+}
+
+// parseAuthProxyMapping parses a list of key:value pairs, separated by commas or spaces.
+func parseAuthProxyMapping(s string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range util.SplitString(s) {
+		split := strings.SplitN(pair, ":", 2)
+		if len(split) == 2 {
+			mapping[split[0]] = split[1]
+		}
+	}
+	return mapping
+}
@@ -0,0 +1,35 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// BruteForceLoginProtectionSettings is the policy applied to failed login attempts, a threshold of 0 is disabled.
+type BruteForceLoginProtectionSettings struct {
+	// MaxAttempts is the number of failed login attempts of a username after which it is locked out
+	MaxAttempts int64
+	// MaxAttemptsPerIP is the number of failed login attempts from an IP address after which it is locked out
+	MaxAttemptsPerIP int64
+	// LockoutDuration is the duration of the first lockout, it doubles with every failed login attempt after it
+	LockoutDuration time.Duration
+	// MaxLockoutDuration is the maximum duration of a lockout, failed login attempts are kept for this long
+	MaxLockoutDuration time.Duration
+}
+
+func readBruteForceLoginProtectionSettings(security *ini.Section) BruteForceLoginProtectionSettings {
+	settings := BruteForceLoginProtectionSettings{
+		MaxAttempts:        security.Key("brute_force_login_protection_max_attempts").MustInt64(5),
+		MaxAttemptsPerIP:   security.Key("brute_force_login_protection_max_attempts_per_ip").MustInt64(0),
+		LockoutDuration:    security.Key("brute_force_login_protection_lockout_duration").MustDuration(5 * time.Minute),
+		MaxLockoutDuration: security.Key("brute_force_login_protection_max_lockout_duration").MustDuration(time.Hour),
+	}
+	if settings.LockoutDuration <= 0 {
+		settings.LockoutDuration = 5 * time.Minute
+	}
+	if settings.MaxLockoutDuration < settings.LockoutDuration {
+		settings.MaxLockoutDuration = settings.LockoutDuration
+	}
+	return settings
+}
@@ -0,0 +1,80 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// CleanupSettings configure the retention policies applied by the cleanup service.
+type CleanupSettings struct {
+	// Interval is the time between two runs of the cleanup jobs
+	Interval time.Duration
+	// ReportsToKeep is the number of run reports kept and listed by /api/admin/cleanup/reports
+	ReportsToKeep int
+
+	TempFiles         CleanupPolicySettings
+	RenderCache       CleanupPolicySettings
+	ExpiredSnapshots  CleanupPolicySettings
+	LoginAttempts     CleanupPolicySettings
+	DashboardVersions CleanupPolicySettings
+	ShortURLs         CleanupPolicySettings
+	Notifications     CleanupPolicySettings
+}
+
+// CleanupPolicySettings is the retention policy of a type of artifact.
+type CleanupPolicySettings struct {
+	Enabled bool
+	// Retention is how long the artifacts are kept, see conf/defaults.ini for its meaning for each type of artifact
+	Retention time.Duration
+	// MaxCount is the number of artifacts kept regardless of their age, only used by the dashboard versions
+	MaxCount int
+}
+
+func readCleanupSettings(iniFile *ini.File, cfg *Cfg) CleanupSettings {
+	section := iniFile.Section("cleanup")
+	settings := CleanupSettings{
+		Interval:      section.Key("interval").MustDuration(10 * time.Minute),
+		ReportsToKeep: section.Key("reports_to_keep").MustInt(20),
+	}
+	if settings.Interval < time.Minute {
+		settings.Interval = time.Minute
+	}
+	if settings.ReportsToKeep < 0 {
+		settings.ReportsToKeep = 0
+	}
+
+	// the policies default to the settings they replace
+	settings.TempFiles = readCleanupPolicySettings(iniFile.Section("cleanup.temp_files"), true, cfg.TempDataLifetime)
+	settings.RenderCache = readCleanupPolicySettings(iniFile.Section("cleanup.render_cache"), true, cfg.TempDataLifetime)
+	settings.ExpiredSnapshots = readCleanupPolicySettings(iniFile.Section("cleanup.expired_snapshots"), cfg.SnapShotRemoveExpired, 0)
+	settings.ShortURLs = readCleanupPolicySettings(iniFile.Section("cleanup.short_urls"), true, 7*24*time.Hour)
+	settings.Notifications = readCleanupPolicySettings(iniFile.Section("cleanup.notifications"), true, 90*24*time.Hour)
+
+	// the failed login attempts are needed for as long as they can lock a user out
+	maxLockout := cfg.BruteForceLoginProtection.MaxLockoutDuration
+	settings.LoginAttempts = readCleanupPolicySettings(iniFile.Section("cleanup.login_attempts"), true, maxLockout)
+	if settings.LoginAttempts.Retention < maxLockout {
+		settings.LoginAttempts.Retention = maxLockout
+	}
+
+	versions := iniFile.Section("cleanup.dashboard_versions")
+	settings.DashboardVersions = readCleanupPolicySettings(versions, true, 0)
+	settings.DashboardVersions.MaxCount = versions.Key("max_count").MustInt(DashboardVersionsToKeep)
+	if settings.DashboardVersions.MaxCount < 1 {
+		settings.DashboardVersions.MaxCount = 1
+	}
+
+	return settings
+}
+
+func readCleanupPolicySettings(section *ini.Section, enabled bool, retention time.Duration) CleanupPolicySettings {
+	settings := CleanupPolicySettings{
+		Enabled:   section.Key("enabled").MustBool(enabled),
+		Retention: section.Key("retention").MustDuration(retention),
+	}
+	if settings.Retention < 0 {
+		settings.Retention = 0
+	}
+	return settings
+}
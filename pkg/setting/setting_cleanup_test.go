@@ -0,0 +1,57 @@
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadCleanupSettings(t *testing.T) {
+	cfg := NewCfg()
+	cfg.TempDataLifetime = 12 * time.Hour
+	cfg.SnapShotRemoveExpired = false
+	cfg.BruteForceLoginProtection.MaxLockoutDuration = time.Hour
+	versionsToKeep := DashboardVersionsToKeep
+	DashboardVersionsToKeep = 20
+	t.Cleanup(func() { DashboardVersionsToKeep = versionsToKeep })
+
+	t.Run("The policies default to the settings they replace", func(t *testing.T) {
+		settings := readCleanupSettings(ini.Empty(), cfg)
+		assert.Equal(t, 10*time.Minute, settings.Interval)
+		assert.Equal(t, CleanupPolicySettings{Enabled: true, Retention: 12 * time.Hour}, settings.TempFiles)
+		assert.Equal(t, CleanupPolicySettings{Enabled: true, Retention: 12 * time.Hour}, settings.RenderCache)
+		assert.False(t, settings.ExpiredSnapshots.Enabled)
+		assert.Equal(t, time.Hour, settings.LoginAttempts.Retention)
+		assert.Equal(t, 20, settings.DashboardVersions.MaxCount)
+		assert.Equal(t, 7*24*time.Hour, settings.ShortURLs.Retention)
+		assert.Equal(t, CleanupPolicySettings{Enabled: true, Retention: 90 * 24 * time.Hour}, settings.Notifications)
+	})
+
+	t.Run("The policies are configured individually", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[cleanup.render_cache]
+retention = 1h
+
+[cleanup.short_urls]
+enabled = false
+
+[cleanup.login_attempts]
+retention = 1m
+
+[cleanup.dashboard_versions]
+retention = 720h
+max_count = 5
+`))
+		require.NoError(t, err)
+
+		settings := readCleanupSettings(f, cfg)
+		assert.Equal(t, 12*time.Hour, settings.TempFiles.Retention)
+		assert.Equal(t, time.Hour, settings.RenderCache.Retention)
+		assert.False(t, settings.ShortURLs.Enabled)
+		assert.Equal(t, time.Hour, settings.LoginAttempts.Retention, "the login attempts are kept for the max lockout duration")
+		assert.Equal(t, CleanupPolicySettings{Enabled: true, Retention: 720 * time.Hour, MaxCount: 5}, settings.DashboardVersions)
+	})
+}
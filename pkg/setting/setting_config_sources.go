@@ -0,0 +1,34 @@
+package setting
+
+// ConfigSource is where the effective value of a configuration key comes from
+type ConfigSource string
+
+const (
+	ConfigSourceDefault     ConfigSource = "default"
+	ConfigSourceFile        ConfigSource = "file"
+	ConfigSourceEnv         ConfigSource = "env"
+	ConfigSourceCommandLine ConfigSource = "cli"
+)
+
+// configSources records the source of the keys that were overridden while
+// loading the configuration, indexed by section and key name.
+type configSources map[string]map[string]ConfigSource
+
+func (s configSources) set(section, key string, source ConfigSource) {
+	if s == nil {
+		return
+	}
+	if s[section] == nil {
+		s[section] = make(map[string]ConfigSource)
+	}
+	s[section][key] = source
+}
+
+// ConfigSource returns where the effective value of the key comes from. Keys
+// that were never overridden come from the defaults.
+func (cfg *Cfg) ConfigSource(section, key string) ConfigSource {
+	if source, ok := cfg.configSources[section][key]; ok {
+		return source
+	}
+	return ConfigSourceDefault
+}
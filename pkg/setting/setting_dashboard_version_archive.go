@@ -0,0 +1,32 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// DashboardVersionArchiveSettings configure the offloading of the old dashboard versions to an object storage.
+type DashboardVersionArchiveSettings struct {
+	// Enabled archives the versions older than ArchiveAfter, the archived versions are read from the storage
+	// whenever it is configured
+	Enabled      bool
+	ArchiveAfter time.Duration
+	Storage      ObjectStoreSettings
+}
+
+func readDashboardVersionArchiveSettings(iniFile *ini.File) DashboardVersionArchiveSettings {
+	section := iniFile.Section("dashboard_version_archive")
+	settings := DashboardVersionArchiveSettings{
+		Enabled:      section.Key("enabled").MustBool(false),
+		ArchiveAfter: section.Key("archive_after").MustDuration(30 * 24 * time.Hour),
+		Storage:      readObjectStoreSettings(iniFile, "dashboard_version_archive"),
+	}
+	if settings.ArchiveAfter < time.Hour {
+		settings.ArchiveAfter = time.Hour
+	}
+	if settings.Storage.Prefix == "" {
+		settings.Storage.Prefix = "dashboard-versions/"
+	}
+	return settings
+}
@@ -0,0 +1,43 @@
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadDashboardVersionArchiveSettings(t *testing.T) {
+	t.Run("The archival is disabled by default", func(t *testing.T) {
+		settings := readDashboardVersionArchiveSettings(ini.Empty())
+		assert.False(t, settings.Enabled)
+		assert.Equal(t, 30*24*time.Hour, settings.ArchiveAfter)
+		assert.Empty(t, settings.Storage.Provider)
+		assert.Equal(t, "dashboard-versions/", settings.Storage.Prefix)
+	})
+
+	t.Run("The storage is read from the subsection of the provider", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[dashboard_version_archive]
+enabled = true
+archive_after = 1m
+provider = S3
+prefix = grafana/
+
+[dashboard_version_archive.s3]
+bucket = versions
+region = eu-west-1
+path_style_access = true
+`))
+		require.NoError(t, err)
+
+		settings := readDashboardVersionArchiveSettings(f)
+		assert.True(t, settings.Enabled)
+		assert.Equal(t, time.Hour, settings.ArchiveAfter, "the archival delay is at least an hour")
+		assert.Equal(t, "s3", settings.Storage.Provider)
+		assert.Equal(t, "grafana/", settings.Storage.Prefix)
+		assert.Equal(t, ObjectStoreS3Settings{Bucket: "versions", Region: "eu-west-1", PathStyleAccess: true}, settings.Storage.S3)
+	})
+}
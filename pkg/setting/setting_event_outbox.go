@@ -0,0 +1,79 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// EventOutboxSettings configure the delivery of the domain events to external systems.
+type EventOutboxSettings struct {
+	Enabled bool
+	// Events are the types of the delivered events, all of them when empty
+	Events []string
+	// MaxAttempts is the number of deliveries attempted before an event is dead, the job queue default when 0
+	MaxAttempts int
+
+	Webhook EventOutboxWebhookSettings
+	Kafka   EventOutboxKafkaSettings
+	NATS    EventOutboxNATSSettings
+}
+
+type EventOutboxWebhookSettings struct {
+	URL string
+	// Secret signs the requests, the signature is sent in the X-Grafana-Signature header
+	Secret  string
+	Timeout time.Duration
+}
+
+// EventOutboxKafkaSettings configure the publication of the events to a Kafka topic through a Kafka REST proxy.
+type EventOutboxKafkaSettings struct {
+	RestProxyURL string
+	Topic        string
+	Username     string
+	Password     string
+	Timeout      time.Duration
+}
+
+type EventOutboxNATSSettings struct {
+	URL      string
+	Subject  string
+	Token    string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+func readEventOutboxSettings(iniFile *ini.File) EventOutboxSettings {
+	section := iniFile.Section("event_outbox")
+	webhook := iniFile.Section("event_outbox.webhook")
+	kafka := iniFile.Section("event_outbox.kafka")
+	nats := iniFile.Section("event_outbox.nats")
+	return EventOutboxSettings{
+		Enabled:     section.Key("enabled").MustBool(false),
+		Events:      util.SplitString(section.Key("events").MustString("")),
+		MaxAttempts: section.Key("max_attempts").MustInt(0),
+		Webhook: EventOutboxWebhookSettings{
+			URL:     webhook.Key("url").MustString(""),
+			Secret:  webhook.Key("secret").MustString(""),
+			Timeout: webhook.Key("timeout").MustDuration(10 * time.Second),
+		},
+		Kafka: EventOutboxKafkaSettings{
+			RestProxyURL: kafka.Key("rest_proxy_url").MustString(""),
+			Topic:        kafka.Key("topic").MustString("grafana-events"),
+			Username:     kafka.Key("username").MustString(""),
+			Password:     kafka.Key("password").MustString(""),
+			Timeout:      kafka.Key("timeout").MustDuration(10 * time.Second),
+		},
+		NATS: EventOutboxNATSSettings{
+			URL:      nats.Key("url").MustString(""),
+			Subject:  nats.Key("subject").MustString("grafana.events"),
+			Token:    nats.Key("token").MustString(""),
+			Username: nats.Key("username").MustString(""),
+			Password: nats.Key("password").MustString(""),
+			Timeout:  nats.Key("timeout").MustDuration(10 * time.Second),
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// OAuthServerSettings configure the OAuth2 authorization server of Grafana, which issues the access tokens of the
+// external applications.
+type OAuthServerSettings struct {
+	Enabled              bool
+	AccessTokenLifetime  time.Duration
+	RefreshTokenLifetime time.Duration
+}
+
+func readOAuthServerSettings(iniFile *ini.File) OAuthServerSettings {
+	section := iniFile.Section("oauth_server")
+	s := OAuthServerSettings{
+		Enabled:              section.Key("enabled").MustBool(false),
+		AccessTokenLifetime:  section.Key("access_token_lifetime").MustDuration(time.Hour),
+		RefreshTokenLifetime: section.Key("refresh_token_lifetime").MustDuration(30 * 24 * time.Hour),
+	}
+	if s.AccessTokenLifetime <= 0 {
+		s.AccessTokenLifetime = time.Hour
+	}
+	if s.RefreshTokenLifetime <= 0 {
+		s.RefreshTokenLifetime = 30 * 24 * time.Hour
+	}
+	return s
+}
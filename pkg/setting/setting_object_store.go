@@ -0,0 +1,142 @@
+package setting
+
+import (
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// ObjectStoreSettings configure an object storage, read from a section and its s3, gcs, azure and file
+// subsections.
+type ObjectStoreSettings struct {
+	// Provider is s3, gcs, azure or file, the object storage is disabled when empty
+	Provider string
+	// Prefix is prepended to the keys of the objects
+	Prefix string
+
+	S3    ObjectStoreS3Settings
+	GCS   ObjectStoreGCSSettings
+	Azure ObjectStoreAzureSettings
+	File  ObjectStoreFileSettings
+}
+
+type ObjectStoreS3Settings struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	PathStyleAccess bool
+	// AccessKey and SecretKey default to the credentials of the environment, shared files or instance role
+	AccessKey string
+	SecretKey string
+}
+
+type ObjectStoreGCSSettings struct {
+	Bucket string
+	// KeyFile is the JSON key of a service account, the application default credentials are used when empty
+	KeyFile string
+}
+
+type ObjectStoreAzureSettings struct {
+	AccountName   string
+	AccountKey    string
+	ContainerName string
+	// Endpoint defaults to https://<account name>.blob.core.windows.net
+	Endpoint string
+}
+
+type ObjectStoreFileSettings struct {
+	Path string
+}
+
+func readObjectStoreSettings(iniFile *ini.File, name string) ObjectStoreSettings {
+	section := iniFile.Section(name)
+	s3 := iniFile.Section(name + ".s3")
+	gcs := iniFile.Section(name + ".gcs")
+	azure := iniFile.Section(name + ".azure")
+	file := iniFile.Section(name + ".file")
+	return ObjectStoreSettings{
+		Provider: strings.ToLower(section.Key("provider").MustString("")),
+		Prefix:   section.Key("prefix").MustString(""),
+		S3: ObjectStoreS3Settings{
+			Bucket:          s3.Key("bucket").MustString(""),
+			Region:          s3.Key("region").MustString(""),
+			Endpoint:        s3.Key("endpoint").MustString(""),
+			PathStyleAccess: s3.Key("path_style_access").MustBool(false),
+			AccessKey:       s3.Key("access_key").MustString(""),
+			SecretKey:       s3.Key("secret_key").MustString(""),
+		},
+		GCS: ObjectStoreGCSSettings{
+			Bucket:  gcs.Key("bucket").MustString(""),
+			KeyFile: gcs.Key("key_file").MustString(""),
+		},
+		Azure: ObjectStoreAzureSettings{
+			AccountName:   azure.Key("account_name").MustString(""),
+			AccountKey:    azure.Key("account_key").MustString(""),
+			ContainerName: azure.Key("container_name").MustString(""),
+			Endpoint:      azure.Key("endpoint").MustString(""),
+		},
+		File: ObjectStoreFileSettings{
+			Path: file.Key("path").MustString(""),
+		},
+	}
+}
+
+// ObjectStorageSettings configure the object storage of each type of artifact, the artifacts without object
+// storage are kept on the local disk or in the database.
+type ObjectStorageSettings struct {
+	// Rendering stores the rendered images
+	Rendering ObjectStoreSettings
+	// Reports stores the generated reports
+	Reports ObjectStoreSettings
+	// Snapshots stores the payloads of the dashboard snapshots
+	Snapshots ObjectStoreSettings
+}
+
+// readObjectStorageSettings reads the default object storage from the [object_storage] section, and the storage of
+// each artifact type from the [object_storage.<artifact>] sections. The artifacts use the default storage unless
+// they have their own provider, or the none provider to opt out of it.
+func readObjectStorageSettings(iniFile *ini.File) ObjectStorageSettings {
+	defaults := readObjectStoreSettings(iniFile, "object_storage")
+	artifact := func(name string) ObjectStoreSettings {
+		// the keys of the artifact section are read without falling back to the [object_storage] section, as the
+		// dotted sections inherit the keys of their parent
+		section := iniFile.Section("object_storage." + name)
+		prefix := ownKeyValue(section, "prefix")
+		if prefix == "" {
+			prefix = name + "/"
+		}
+
+		switch provider := strings.ToLower(ownKeyValue(section, "provider")); provider {
+		case "none":
+			return ObjectStoreSettings{}
+		case "":
+			if defaults.Provider == "" {
+				return ObjectStoreSettings{}
+			}
+			settings := defaults
+			settings.Prefix = defaults.Prefix + prefix
+			return settings
+		default:
+			settings := readObjectStoreSettings(iniFile, section.Name())
+			settings.Provider = provider
+			settings.Prefix = prefix
+			return settings
+		}
+	}
+
+	return ObjectStorageSettings{
+		Rendering: artifact("rendering"),
+		Reports:   artifact("reports"),
+		Snapshots: artifact("snapshots"),
+	}
+}
+
+// ownKeyValue returns the value of a key of the section, ignoring the keys of its parent sections.
+func ownKeyValue(section *ini.Section, name string) string {
+	for _, key := range section.KeyStrings() {
+		if key == name {
+			return section.Key(name).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package setting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestReadObjectStorageSettings(t *testing.T) {
+	t.Run("The artifacts have no object storage by default", func(t *testing.T) {
+		settings := readObjectStorageSettings(ini.Empty())
+		assert.Equal(t, ObjectStorageSettings{}, settings)
+	})
+
+	t.Run("The artifacts use the default storage unless they have their own", func(t *testing.T) {
+		f, err := ini.Load([]byte(`
+[object_storage]
+provider = s3
+prefix = grafana/
+
+[object_storage.s3]
+bucket = artifacts
+
+[object_storage.reports]
+provider = gcs
+prefix = pdf/
+
+[object_storage.reports.gcs]
+bucket = reports
+
+[object_storage.snapshots]
+provider = none
+`))
+		require.NoError(t, err)
+
+		settings := readObjectStorageSettings(f)
+		assert.Equal(t, "s3", settings.Rendering.Provider)
+		assert.Equal(t, "grafana/rendering/", settings.Rendering.Prefix)
+		assert.Equal(t, "artifacts", settings.Rendering.S3.Bucket)
+
+		assert.Equal(t, "gcs", settings.Reports.Provider)
+		assert.Equal(t, "pdf/", settings.Reports.Prefix)
+		assert.Equal(t, "reports", settings.Reports.GCS.Bucket)
+
+		assert.Empty(t, settings.Snapshots.Provider)
+	})
+}
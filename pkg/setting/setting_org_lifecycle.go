@@ -0,0 +1,54 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// OrgTemplateSettings are the defaults seeded in the organizations when they are created.
+type OrgTemplateSettings struct {
+	Enabled bool
+	Folders []string
+	// SourceOrgID is the organization of the data sources referenced by the template
+	SourceOrgID int64
+	// DataSources are the UIDs of the data sources of the source organization copied to the new organizations
+	DataSources []string
+	Teams       []string
+	Theme       string
+	Timezone    string
+	WeekStart   string
+}
+
+// OrgHooksSettings are the webhooks called when an organization is created or deleted.
+type OrgHooksSettings struct {
+	URLs []string
+	// Secret signs the webhook requests, the signature is sent in the X-Grafana-Signature header
+	Secret  string
+	Timeout time.Duration
+}
+
+func readOrgTemplateSettings(iniFile *ini.File) OrgTemplateSettings {
+	section := iniFile.Section("org_template")
+	return OrgTemplateSettings{
+		Enabled:     section.Key("enabled").MustBool(false),
+		Folders:     util.SplitString(section.Key("folders").MustString("")),
+		SourceOrgID: section.Key("source_org_id").MustInt64(1),
+		DataSources: util.SplitString(section.Key("datasources").MustString("")),
+		Teams:       util.SplitString(section.Key("teams").MustString("")),
+		Theme:       section.Key("theme").MustString(""),
+		Timezone:    section.Key("timezone").MustString(""),
+		WeekStart:   section.Key("week_start").MustString(""),
+	}
+}
+
+func readOrgHooksSettings(iniFile *ini.File) OrgHooksSettings {
+	section := iniFile.Section("org_hooks")
+	return OrgHooksSettings{
+		URLs:    util.SplitString(section.Key("urls").MustString("")),
+		Secret:  section.Key("secret").MustString(""),
+		Timeout: section.Key("timeout").MustDuration(10 * time.Second),
+	}
+}
@@ -0,0 +1,34 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// OutboundHTTPSettings configure the middlewares shared by the clients of the outbound requests: the data source
+// proxy, the alert notifiers and the webhooks.
+type OutboundHTTPSettings struct {
+	// CircuitBreakerFailures is the number of consecutive failures of a host that opens its circuit, 0 is disabled
+	CircuitBreakerFailures int
+	// CircuitBreakerOpenDuration is how long the requests to a host fail immediately once its circuit is open
+	CircuitBreakerOpenDuration time.Duration
+	// HedgeDelay is the delay after which an idempotent request without response is sent again, 0 is disabled
+	HedgeDelay time.Duration
+	// HedgeMaxAttempts is the maximum number of requests sent for a hedged request, including the first one
+	HedgeMaxAttempts int
+}
+
+func readOutboundHTTPSettings(iniFile *ini.File) OutboundHTTPSettings {
+	section := iniFile.Section("outbound_http")
+	s := OutboundHTTPSettings{
+		CircuitBreakerFailures:     section.Key("circuit_breaker_failures").MustInt(0),
+		CircuitBreakerOpenDuration: section.Key("circuit_breaker_open_duration").MustDuration(30 * time.Second),
+		HedgeDelay:                 section.Key("hedge_delay").MustDuration(0),
+		HedgeMaxAttempts:           section.Key("hedge_max_attempts").MustInt(2),
+	}
+	if s.HedgeMaxAttempts < 1 {
+		s.HedgeMaxAttempts = 1
+	}
+	return s
+}
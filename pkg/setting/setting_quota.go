@@ -6,6 +6,8 @@ type OrgQuota struct {
 	Dashboard  int64 `target:"dashboard"`
 	ApiKey     int64 `target:"api_key"`
 	AlertRule  int64 `target:"alert_rule"`
+
+	PublicDashboard int64 `target:"public_dashboard"`
 }
 
 type UserQuota struct {
@@ -21,6 +23,8 @@ type GlobalQuota struct {
 	Session    int64 `target:"-"`
 	AlertRule  int64 `target:"alert_rule"`
 	File       int64 `target:"file"`
+
+	PublicDashboard int64 `target:"public_dashboard"`
 }
 
 type QuotaSettings struct {
@@ -48,6 +52,8 @@ func (cfg *Cfg) readQuotaSettings() {
 		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
 		ApiKey:     quota.Key("org_api_key").MustInt64(10),
 		AlertRule:  alertOrgQuota,
+
+		PublicDashboard: quota.Key("org_public_dashboard").MustInt64(-1),
 	}
 
 	// per User limits
@@ -65,5 +71,7 @@ func (cfg *Cfg) readQuotaSettings() {
 		Session:    quota.Key("global_session").MustInt64(-1),
 		File:       quota.Key("global_file").MustInt64(-1),
 		AlertRule:  alertGlobalQuota,
+
+		PublicDashboard: quota.Key("global_public_dashboard").MustInt64(-1),
 	}
 }
@@ -0,0 +1,53 @@
+package setting
+
+import (
+	"gopkg.in/ini.v1"
+)
+
+type RateLimit struct {
+	// Rate is the number of requests allowed per second, 0 disables the limit
+	Rate float64
+	// Burst is the number of requests allowed at once
+	Burst int
+}
+
+type RateLimitingSettings struct {
+	Enabled bool
+	// Backend stores the token buckets, either "memory" or "redis"
+	Backend       string
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	// PerOrg shares the buckets between all the users of an organization
+	PerOrg bool
+	// Limits are the rate limits by group of endpoints
+	Limits map[string]RateLimit
+}
+
+func readRateLimitingSettings(iniFile *ini.File) RateLimitingSettings {
+	section := iniFile.Section("rate_limiting")
+	s := RateLimitingSettings{
+		Enabled:       section.Key("enabled").MustBool(false),
+		Backend:       section.Key("backend").MustString("memory"),
+		RedisAddr:     section.Key("redis_addr").MustString("127.0.0.1:6379"),
+		RedisPassword: section.Key("redis_password").MustString(""),
+		RedisDB:       section.Key("redis_db").MustInt(0),
+		PerOrg:        section.Key("key_by").MustString("user") == "org",
+		Limits:        map[string]RateLimit{},
+	}
+
+	defaults := map[string]RateLimit{
+		"search": {Rate: 10, Burst: 20},
+		"query":  {Rate: 20, Burst: 40},
+		"render": {Rate: 1, Burst: 5},
+	}
+	for group, limit := range defaults {
+		rate := section.Key(group + "_rate").MustFloat64(limit.Rate)
+		burst := section.Key(group + "_burst").MustInt(limit.Burst)
+		if burst < 1 {
+			burst = 1
+		}
+		s.Limits[group] = RateLimit{Rate: rate, Burst: burst}
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+package setting
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// SettingsReloadSettings configures when the configuration files are read again.
+type SettingsReloadSettings struct {
+	// Enabled reloads the configuration when the server receives SIGHUP
+	Enabled bool
+	// WatchFile reloads the configuration when the configuration file is modified
+	WatchFile bool
+	// PollInterval is how often the modification time of the configuration file is checked
+	PollInterval time.Duration
+}
+
+func readSettingsReloadSettings(iniFile *ini.File) SettingsReloadSettings {
+	section := iniFile.Section("settings_reload")
+	return SettingsReloadSettings{
+		Enabled:      section.Key("enabled").MustBool(true),
+		WatchFile:    section.Key("watch_file").MustBool(false),
+		PollInterval: section.Key("poll_interval").MustDuration(10 * time.Second),
+	}
+}
+
+// ReloadableSections are the sections whose derived settings are updated by ApplySection,
+// the changes of other sections require a restart unless a ReloadHandler is registered for them.
+var ReloadableSections = []string{"log", "log.console", "log.file", "log.syslog", "rate_limiting"}
+
+// startupOnlyKeys are the keys of the reloadable sections that are only read on startup.
+var startupOnlyKeys = map[string][]string{
+	"rate_limiting": {"backend", "redis_addr", "redis_password", "redis_db"},
+}
+
+func IsReloadableSection(section string) bool {
+	for _, s := range ReloadableSections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStartupOnlyKey returns whether the key is only read on startup, its changes require a restart even when
+// its section is reloadable.
+func IsStartupOnlyKey(section, key string) bool {
+	for _, k := range startupOnlyKeys[section] {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SettingChange is a key whose value differs between the running configuration and the configuration files.
+type SettingChange struct {
+	Section string `json:"section"`
+	Key     string `json:"key"`
+}
+
+// ConfigFilePath returns the configuration file the settings are loaded from, empty if there is none.
+func (cfg *Cfg) ConfigFilePath() string {
+	if cfg.args.Config != "" {
+		return cfg.args.Config
+	}
+
+	configFile := filepath.Join(cfg.HomePath, CustomInitPath)
+	if !pathExists(configFile) {
+		return ""
+	}
+	return configFile
+}
+
+// ReadConfigFiles reads the configuration files, the environment variables and the command line
+// properties again, the same way Load does, without changing the running configuration.
+func (cfg *Cfg) ReadConfigFiles() (*ini.File, error) {
+	parsedFile, err := ini.Load(path.Join(cfg.HomePath, "conf/defaults.ini"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse defaults.ini: %w", err)
+	}
+	parsedFile.BlockMode = false
+
+	// read into a copy, so that the sources and the loaded files of the running configuration are left untouched
+	files := configFiles
+	defer func() { configFiles = files }()
+	tmp := &Cfg{HomePath: cfg.HomePath, configSources: make(configSources)}
+
+	commandLineProps := cfg.getCommandLineProperties(cfg.args.Args)
+	applyCommandLineDefaultProperties(commandLineProps, parsedFile, tmp.configSources)
+
+	if err := tmp.loadSpecifiedConfigFile(cfg.args.Config, parsedFile); err != nil {
+		return nil, err
+	}
+
+	if err := applyEnvVariableOverrides(parsedFile, tmp.configSources); err != nil {
+		return nil, err
+	}
+	applyCommandLineProperties(commandLineProps, parsedFile, tmp.configSources)
+
+	if err := expandConfig(parsedFile); err != nil {
+		return nil, err
+	}
+
+	return parsedFile, nil
+}
+
+// DiffConfig returns the keys added, removed or changed in updated compared to current, sorted by section and key.
+func DiffConfig(current, updated *ini.File) []SettingChange {
+	changes := make([]SettingChange, 0)
+	seen := make(map[SettingChange]bool)
+
+	compare := func(from, to *ini.File) {
+		for _, section := range from.Sections() {
+			for _, key := range section.Keys() {
+				change := SettingChange{Section: section.Name(), Key: key.Name()}
+				if seen[change] {
+					continue
+				}
+				seen[change] = true
+
+				other, err := to.Section(section.Name()).GetKey(key.Name())
+				if err != nil || other.Value() != key.Value() {
+					changes = append(changes, change)
+				}
+			}
+		}
+	}
+	compare(current, updated)
+	compare(updated, current)
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Section != changes[j].Section {
+			return changes[i].Section < changes[j].Section
+		}
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// ApplySection applies the keys of a section of updated: it updates the settings derived from the section and calls
+// the reload handlers registered for it with the section of updated. The section is left untouched when one of the
+// handlers fails to validate it. Raw and the fields of Cfg are read without synchronization, so they keep the values
+// the server started with, the reloaded settings are published to their consumers instead.
+func (cfg *Cfg) ApplySection(updated *ini.File, name string, handlers []ReloadHandler) error {
+	for _, handler := range handlers {
+		if err := handler.Validate(&sectionImpl{section: updated.Section(name)}); err != nil {
+			return fmt.Errorf("invalid section %s: %w", name, err)
+		}
+	}
+
+	if err := cfg.applyDerivedSettings(updated, name); err != nil {
+		return err
+	}
+
+	for _, handler := range handlers {
+		if err := handler.Reload(&sectionImpl{section: updated.Section(name)}); err != nil {
+			return fmt.Errorf("failed to reload section %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (cfg *Cfg) applyDerivedSettings(updated *ini.File, name string) error {
+	switch name {
+	case "log", "log.console", "log.file", "log.syslog":
+		// the loggers are swapped under the lock of the log package, the logs path is only read on startup
+		return log.ReadLoggingConfig(logModes(updated), cfg.LogsPath, updated)
+	case "rate_limiting":
+		settings := readRateLimitingSettings(updated)
+		current := cfg.CurrentRateLimiting()
+		settings.Backend = current.Backend
+		settings.RedisAddr = current.RedisAddr
+		settings.RedisPassword = current.RedisPassword
+		settings.RedisDB = current.RedisDB
+		cfg.reloadedRateLimiting.Store(settings)
+	}
+	return nil
+}
+
+// CurrentRateLimiting returns the rate limiting settings with the limits of the last reload, it is safe to call
+// while the configuration is reloaded.
+func (cfg *Cfg) CurrentRateLimiting() RateLimitingSettings {
+	if settings, ok := cfg.reloadedRateLimiting.Load().(RateLimitingSettings); ok {
+		return settings
+	}
+	return cfg.RateLimiting
+}
+
+// CopyKey sets the key of a section of dst to its value in src, or deletes it from dst when src does not have it.
+func CopyKey(dst, src *ini.File, change SettingChange) error {
+	key, err := src.Section(change.Section).GetKey(change.Key)
+	if err != nil {
+		dst.Section(change.Section).DeleteKey(change.Key)
+		return nil
+	}
+	_, err = dst.Section(change.Section).NewKey(change.Key, key.Value())
+	return err
+}
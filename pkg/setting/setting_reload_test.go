@@ -0,0 +1,74 @@
+package setting
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/ini.v1"
+)
+
+func TestDiffConfig(t *testing.T) {
+	current, err := ini.Load([]byte("[log]\nlevel = info\nmode = console\n[server]\nhttp_port = 3000\n"))
+	require.NoError(t, err)
+	updated, err := ini.Load([]byte("[log]\nlevel = debug\nmode = console\n[server]\nhttp_port = 3000\nprotocol = https\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, []SettingChange{
+		{Section: "log", Key: "level"},
+		{Section: "server", Key: "protocol"},
+	}, DiffConfig(current, updated))
+	require.Empty(t, DiffConfig(current, current))
+}
+
+type fakeReloadHandler struct {
+	validateErr error
+	reloaded    []string
+}
+
+func (h *fakeReloadHandler) Reload(section Section) error {
+	h.reloaded = append(h.reloaded, section.KeyValue("limit").Value())
+	return nil
+}
+
+func (h *fakeReloadHandler) Validate(Section) error {
+	return h.validateErr
+}
+
+func TestApplySection(t *testing.T) {
+	newCfg := func(t *testing.T) *Cfg {
+		cfg := NewCfg()
+		raw, err := ini.Load([]byte("[rate_limiting]\nenabled = false\nbackend = redis\n[custom]\nlimit = 1\n"))
+		require.NoError(t, err)
+		cfg.Raw = raw
+		cfg.RateLimiting = readRateLimitingSettings(raw)
+		return cfg
+	}
+	updated, err := ini.Load([]byte("[rate_limiting]\nenabled = true\n[custom]\nlimit = 2\n"))
+	require.NoError(t, err)
+
+	t.Run("publishes the derived settings, except the keys only read on startup", func(t *testing.T) {
+		cfg := newCfg(t)
+		require.NoError(t, cfg.ApplySection(updated, "rate_limiting", nil))
+		require.True(t, cfg.CurrentRateLimiting().Enabled)
+		require.Equal(t, "redis", cfg.CurrentRateLimiting().Backend)
+		require.False(t, cfg.RateLimiting.Enabled)
+		require.Equal(t, "false", cfg.Raw.Section("rate_limiting").Key("enabled").Value())
+		require.True(t, IsStartupOnlyKey("rate_limiting", "backend"))
+		require.False(t, IsStartupOnlyKey("rate_limiting", "enabled"))
+	})
+
+	t.Run("calls the reload handlers with the new values", func(t *testing.T) {
+		cfg := newCfg(t)
+		handler := &fakeReloadHandler{}
+		require.NoError(t, cfg.ApplySection(updated, "custom", []ReloadHandler{handler}))
+		require.Equal(t, []string{"2"}, handler.reloaded)
+	})
+
+	t.Run("leaves the section untouched when the validation fails", func(t *testing.T) {
+		cfg := newCfg(t)
+		handler := &fakeReloadHandler{validateErr: errors.New("invalid")}
+		require.Error(t, cfg.ApplySection(updated, "custom", []ReloadHandler{handler}))
+		require.Empty(t, handler.reloaded)
+	})
+}
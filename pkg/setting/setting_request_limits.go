@@ -0,0 +1,29 @@
+package setting
+
+import (
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+// RequestLimitsSettings protect the instance from oversized requests, a limit of 0 is disabled.
+type RequestLimitsSettings struct {
+	// DashboardMaxBodySize is the maximum size in bytes of the saved dashboards
+	DashboardMaxBodySize int64
+	// AnnotationMaxBodySize is the maximum size in bytes of the created and updated annotations
+	AnnotationMaxBodySize int64
+	// MaxQueriesPerRequest is the maximum number of queries of a data source query request
+	MaxQueriesPerRequest int
+	// MaxQueryTimeRange is the maximum time range of a data source query request
+	MaxQueryTimeRange time.Duration
+}
+
+func readRequestLimitsSettings(iniFile *ini.File) RequestLimitsSettings {
+	section := iniFile.Section("request_limits")
+	return RequestLimitsSettings{
+		DashboardMaxBodySize:  section.Key("dashboard_max_body_size").MustInt64(10 << 20),
+		AnnotationMaxBodySize: section.Key("annotation_max_body_size").MustInt64(1 << 20),
+		MaxQueriesPerRequest:  section.Key("max_queries_per_request").MustInt(0),
+		MaxQueryTimeRange:     section.Key("max_query_time_range").MustDuration(0),
+	}
+}
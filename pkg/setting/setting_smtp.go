@@ -1,6 +1,17 @@
 package setting
 
-import "github.com/grafana/grafana/pkg/util"
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	EmailProviderSMTP     = "smtp"
+	EmailProviderSES      = "ses"
+	EmailProviderSendGrid = "sendgrid"
+	EmailProviderMailgun  = "mailgun"
+)
 
 type SmtpSettings struct {
 	Enabled        bool
@@ -15,11 +26,43 @@ type SmtpSettings struct {
 	StartTLSPolicy string
 	SkipVerify     bool
 
+	// Provider sends the emails, either through an SMTP server or the API of an email service
+	Provider string
+	// MaxIdleConnections is the number of connections to the SMTP server or the API kept open between emails
+	MaxIdleConnections int
+	IdleTimeout        time.Duration
+	// MaxRetries is the number of times an email is sent again when the provider fails to send it
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// DeliveryLogSize is the number of deliveries kept in the delivery log
+	DeliveryLogSize int
+
+	SES      SESSettings
+	SendGrid SendGridSettings
+	Mailgun  MailgunSettings
+
 	SendWelcomeEmailOnSignUp bool
 	TemplatesPatterns        []string
 	ContentTypes             []string
 }
 
+type SESSettings struct {
+	Region   string
+	Endpoint string
+}
+
+type SendGridSettings struct {
+	APIKey string
+	URL    string
+}
+
+type MailgunSettings struct {
+	Domain string
+	APIKey string
+	// URL is the base URL of the API, https://api.eu.mailgun.net for the domains in the EU region
+	URL string
+}
+
 func (cfg *Cfg) readSmtpSettings() {
 	sec := cfg.Raw.Section("smtp")
 	cfg.Smtp.Enabled = sec.Key("enabled").MustBool(false)
@@ -34,6 +77,31 @@ func (cfg *Cfg) readSmtpSettings() {
 	cfg.Smtp.StartTLSPolicy = sec.Key("startTLS_policy").String()
 	cfg.Smtp.SkipVerify = sec.Key("skip_verify").MustBool(false)
 
+	cfg.Smtp.Provider = sec.Key("provider").In(EmailProviderSMTP,
+		[]string{EmailProviderSMTP, EmailProviderSES, EmailProviderSendGrid, EmailProviderMailgun})
+	cfg.Smtp.MaxIdleConnections = sec.Key("max_idle_connections").MustInt(2)
+	cfg.Smtp.IdleTimeout = sec.Key("idle_timeout").MustDuration(30 * time.Second)
+	cfg.Smtp.MaxRetries = sec.Key("max_retries").MustInt(2)
+	cfg.Smtp.RetryBackoff = sec.Key("retry_backoff").MustDuration(time.Second)
+	cfg.Smtp.DeliveryLogSize = sec.Key("delivery_log_size").MustInt(1000)
+
+	ses := cfg.Raw.Section("smtp.ses")
+	cfg.Smtp.SES = SESSettings{
+		Region:   ses.Key("region").String(),
+		Endpoint: ses.Key("endpoint").String(),
+	}
+	sendGrid := cfg.Raw.Section("smtp.sendgrid")
+	cfg.Smtp.SendGrid = SendGridSettings{
+		APIKey: sendGrid.Key("api_key").String(),
+		URL:    sendGrid.Key("url").MustString("https://api.sendgrid.com"),
+	}
+	mailgun := cfg.Raw.Section("smtp.mailgun")
+	cfg.Smtp.Mailgun = MailgunSettings{
+		Domain: mailgun.Key("domain").String(),
+		APIKey: mailgun.Key("api_key").String(),
+		URL:    mailgun.Key("url").MustString("https://api.mailgun.net"),
+	}
+
 	emails := cfg.Raw.Section("emails")
 	cfg.Smtp.SendWelcomeEmailOnSignUp = emails.Key("welcome_email_on_sign_up").MustBool(false)
 	cfg.Smtp.TemplatesPatterns = util.SplitString(emails.Key("templates_pattern").MustString("emails/*.html, emails/*.txt"))
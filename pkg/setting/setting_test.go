@@ -191,6 +191,23 @@ func TestLoadingSettings(t *testing.T) {
 		}
 	})
 
+	t.Run("Should record where the value of each key comes from", func(t *testing.T) {
+		t.Setenv("GF_SERVER_DOMAIN", "env.example.com")
+
+		cfg := NewCfg()
+		err := cfg.Load(CommandLineArgs{
+			HomePath: "../../",
+			Config:   filepath.Join(HomePath, "pkg/setting/testdata/override.ini"),
+			Args:     []string{"cfg:server.http_port=4000"},
+		})
+		require.Nil(t, err)
+
+		require.Equal(t, ConfigSourceFile, cfg.ConfigSource("paths", "data"))
+		require.Equal(t, ConfigSourceEnv, cfg.ConfigSource("server", "domain"))
+		require.Equal(t, ConfigSourceCommandLine, cfg.ConfigSource("server", "http_port"))
+		require.Equal(t, ConfigSourceDefault, cfg.ConfigSource("server", "protocol"))
+	})
+
 	t.Run("Can use environment variables in config values", func(t *testing.T) {
 		if runtime.GOOS == windows {
 			err := os.Setenv("GF_DATA_PATH", `c:\tmp\env_override`)
@@ -767,3 +784,61 @@ func TestAlertingEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestReadSnapshotsSettings(t *testing.T) {
+	t.Run("reads the snapshot server settings", func(t *testing.T) {
+		f := ini.Empty()
+		snapshots, err := f.NewSection("snapshots")
+		require.NoError(t, err)
+		_, err = snapshots.NewKey("external_server_enabled", "true")
+		require.NoError(t, err)
+		_, err = snapshots.NewKey("external_server_tokens", "1:abc, 2:def")
+		require.NoError(t, err)
+		_, err = snapshots.NewKey("external_server_max_ttl", "7d")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		require.NoError(t, readSnapshotsSettings(cfg, f))
+		require.True(t, cfg.SnapshotServerEnabled)
+		require.Equal(t, map[string]int64{"abc": 1, "def": 2}, cfg.SnapshotServerTokens)
+		require.Equal(t, 7*24*time.Hour, cfg.SnapshotServerMaxTTL)
+	})
+
+	t.Run("rejects tokens without organization", func(t *testing.T) {
+		f := ini.Empty()
+		snapshots, err := f.NewSection("snapshots")
+		require.NoError(t, err)
+		_, err = snapshots.NewKey("external_server_tokens", "abc")
+		require.NoError(t, err)
+
+		require.Error(t, readSnapshotsSettings(NewCfg(), f))
+	})
+}
+
+func TestRedactedValue(t *testing.T) {
+	testCases := []struct {
+		key      string
+		value    string
+		expected string
+	}{
+		{key: "GF_SNAPSHOTS_EXTERNAL_SNAPSHOT_TOKEN", value: "secret", expected: RedactedPassword},
+		{key: "GF_SNAPSHOTS_EXTERNAL_SERVER_TOKENS", value: "secret1,secret2", expected: RedactedPassword},
+		{key: "GF_AUTH_PROXY_ENABLE_LOGIN_TOKEN", value: "false", expected: "false"},
+		{key: "default.snapshots.external_server_tokens", value: "secret", expected: RedactedPassword},
+		{key: "GF_AUTH_PROXY_SIGNATURE_KEYS", value: "key1,key2", expected: RedactedPassword},
+		{key: "GF_AUTH_PROXY_SIGNATURE_HEADER", value: "X-Grafana-Signature", expected: "X-Grafana-Signature"},
+		{key: "GF_SMTP_SENDGRID_API_KEY", value: "SG.secret", expected: RedactedPassword},
+		{key: "GF_SMTP_MAILGUN_API_KEY", value: "key-secret", expected: RedactedPassword},
+		{key: "GF_QUOTA_ORG_API_KEY", value: "10", expected: "10"},
+		{key: "GF_EVENT_OUTBOX_NATS_TOKEN", value: "secret", expected: RedactedPassword},
+		{key: "GF_EVENT_OUTBOX_NATS_SUBJECT", value: "grafana.events", expected: "grafana.events"},
+		{key: "GF_ANALYTICS_USAGE_STATS_HTTP_HEADERS", value: "Authorization:Bearer secret", expected: RedactedPassword},
+		{key: "GF_PUBLIC_DASHBOARDS_CDN_PURGE_HEADER", value: "Authorization: Bearer secret", expected: RedactedPassword},
+		{key: "GF_SNAPSHOTS_EXTERNAL_SNAPSHOT_NAME", value: "Publish to snapshots.raintank.io", expected: "Publish to snapshots.raintank.io"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			require.Equal(t, tc.expected, RedactedValue(tc.key, tc.value))
+		})
+	}
+}
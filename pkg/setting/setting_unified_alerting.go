@@ -20,6 +20,15 @@ const (
 	alertmanagerDefaultGossipInterval     = cluster.DefaultGossipInterval
 	alertmanagerDefaultPushPullInterval   = cluster.DefaultPushPullInterval
 	alertmanagerDefaultConfigPollInterval = time.Minute
+
+	alertmanagerDefaultExternalConfigSyncInterval = time.Minute
+
+	alertmanagerDefaultNotificationLogRetention = 7 * 24 * time.Hour
+
+	schedulerDefaultFlapDetectionWindow    = time.Hour
+	schedulerDefaultFlapDetectionThreshold = 6
+
+	evaluationBudgetDefaultWindow = 5 * time.Minute
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -53,6 +62,8 @@ const (
 	screenshotsMaxCaptureTimeout            = 30 * time.Second
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultRetention             = 24 * time.Hour
+	screenshotsDefaultContactPointOptIn     = false
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -86,6 +97,20 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	EvaluationBudget              UnifiedAlertingEvaluationBudgetSettings
+
+	// NotificationLogRetention is how long the delivery attempts of notifications are kept. Zero disables the log.
+	NotificationLogRetention time.Duration
+
+	// FlapDetectionWindow is the rolling window in which the state transitions of an alert instance are counted.
+	FlapDetectionWindow time.Duration
+	// FlapDetectionThreshold is the number of transitions in the window from which an alert instance is flapping.
+	// Zero disables the flap detection.
+	FlapDetectionThreshold int
+
+	// ExternalAlertmanagerConfigSyncInterval is how often the Grafana-managed contact points and notification policies
+	// are pushed to the external Alertmanagers that synchronize them. Zero disables the periodic synchronization.
+	ExternalAlertmanagerConfigSyncInterval time.Duration
 }
 
 type UnifiedAlertingScreenshotSettings struct {
@@ -93,6 +118,27 @@ type UnifiedAlertingScreenshotSettings struct {
 	CaptureTimeout             time.Duration
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	// Retention is how long the screenshots can be attached to notifications before they are deleted.
+	Retention time.Duration
+	// ContactPointOptIn attaches the screenshots only to the notifications of the contact points with the
+	// includeImages setting, instead of all the contact points.
+	ContactPointOptIn bool
+}
+
+// UnifiedAlertingEvaluationBudgetSettings limits the cost of the evaluations of the rules, in time spent evaluating
+// and datapoints returned by the data sources, over a rolling window. The rules exceeding their quota, and the
+// noisiest rules of the folders exceeding their budget, are paused until the end of the window. Zero disables a limit.
+type UnifiedAlertingEvaluationBudgetSettings struct {
+	Window              time.Duration
+	RuleMaxDuration     time.Duration
+	RuleMaxDatapoints   int64
+	FolderMaxDuration   time.Duration
+	FolderMaxDatapoints int64
+}
+
+// Enabled returns true if any limit is set.
+func (s UnifiedAlertingEvaluationBudgetSettings) Enabled() bool {
+	return s.RuleMaxDuration > 0 || s.RuleMaxDatapoints > 0 || s.FolderMaxDuration > 0 || s.FolderMaxDatapoints > 0
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
@@ -208,6 +254,10 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	if err != nil {
 		return err
 	}
+	uaCfg.ExternalAlertmanagerConfigSyncInterval, err = gtime.ParseDuration(valueAsString(ua, "external_alertmanager_config_sync_interval", (alertmanagerDefaultExternalConfigSyncInterval).String()))
+	if err != nil {
+		return err
+	}
 	uaCfg.HAPeerTimeout, err = gtime.ParseDuration(valueAsString(ua, "ha_peer_timeout", (alertmanagerDefaultPeerTimeout).String()))
 	if err != nil {
 		return err
@@ -220,6 +270,18 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	if err != nil {
 		return err
 	}
+	uaCfg.NotificationLogRetention, err = gtime.ParseDuration(valueAsString(ua, "notification_log_retention", (alertmanagerDefaultNotificationLogRetention).String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.FlapDetectionWindow, err = gtime.ParseDuration(valueAsString(ua, "flap_detection_window", (schedulerDefaultFlapDetectionWindow).String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.FlapDetectionThreshold = ua.Key("flap_detection_threshold").MustInt(schedulerDefaultFlapDetectionThreshold)
+	if uaCfg.FlapDetectionThreshold < 0 {
+		return fmt.Errorf("value of setting 'flap_detection_threshold' should be 0 or greater")
+	}
 	uaCfg.HAListenAddr = ua.Key("ha_listen_address").MustString(alertmanagerDefaultClusterAddr)
 	uaCfg.HAAdvertiseAddr = ua.Key("ha_advertise_address").MustString("")
 	peers := ua.Key("ha_peers").MustString("")
@@ -308,6 +370,13 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+
+	retention := screenshots.Key("retention").MustDuration(screenshotsDefaultRetention)
+	if retention <= 0 {
+		return fmt.Errorf("value of setting 'retention' must be greater than 0")
+	}
+	uaCfgScreenshots.Retention = retention
+	uaCfgScreenshots.ContactPointOptIn = screenshots.Key("contact_point_opt_in").MustBool(screenshotsDefaultContactPointOptIn)
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -334,6 +403,32 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	evaluationBudget := iniFile.Section("unified_alerting.evaluation_budget")
+	uaCfgEvaluationBudget := UnifiedAlertingEvaluationBudgetSettings{
+		RuleMaxDatapoints:   evaluationBudget.Key("rule_max_datapoints").MustInt64(0),
+		FolderMaxDatapoints: evaluationBudget.Key("folder_max_datapoints").MustInt64(0),
+	}
+	uaCfgEvaluationBudget.Window, err = gtime.ParseDuration(valueAsString(evaluationBudget, "window", evaluationBudgetDefaultWindow.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgEvaluationBudget.Window <= 0 {
+		return fmt.Errorf("value of setting 'window' in section 'unified_alerting.evaluation_budget' should be greater than 0")
+	}
+	uaCfgEvaluationBudget.RuleMaxDuration, err = gtime.ParseDuration(valueAsString(evaluationBudget, "rule_max_duration", "0"))
+	if err != nil {
+		return err
+	}
+	uaCfgEvaluationBudget.FolderMaxDuration, err = gtime.ParseDuration(valueAsString(evaluationBudget, "folder_max_duration", "0"))
+	if err != nil {
+		return err
+	}
+	if uaCfgEvaluationBudget.RuleMaxDuration < 0 || uaCfgEvaluationBudget.FolderMaxDuration < 0 ||
+		uaCfgEvaluationBudget.RuleMaxDatapoints < 0 || uaCfgEvaluationBudget.FolderMaxDatapoints < 0 {
+		return fmt.Errorf("the limits in section 'unified_alerting.evaluation_budget' should be 0 or greater")
+	}
+	uaCfg.EvaluationBudget = uaCfgEvaluationBudget
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
@@ -41,6 +41,50 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 	}
 }
 
+func TestEvaluationBudgetSettings(t *testing.T) {
+	t.Run("should disable the limits by default", func(t *testing.T) {
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(ini.Empty()))
+		require.Equal(t, 5*time.Minute, cfg.UnifiedAlerting.EvaluationBudget.Window)
+		require.False(t, cfg.UnifiedAlerting.EvaluationBudget.Enabled())
+	})
+
+	t.Run("should read the limits", func(t *testing.T) {
+		f := ini.Empty()
+		s, err := f.NewSection("unified_alerting.evaluation_budget")
+		require.NoError(t, err)
+		_, err = s.NewKey("window", "10m")
+		require.NoError(t, err)
+		_, err = s.NewKey("rule_max_duration", "30s")
+		require.NoError(t, err)
+		_, err = s.NewKey("folder_max_datapoints", "100000")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(f))
+		require.Equal(t, UnifiedAlertingEvaluationBudgetSettings{
+			Window:              10 * time.Minute,
+			RuleMaxDuration:     30 * time.Second,
+			FolderMaxDatapoints: 100000,
+		}, cfg.UnifiedAlerting.EvaluationBudget)
+		require.True(t, cfg.UnifiedAlerting.EvaluationBudget.Enabled())
+	})
+
+	t.Run("should fail with a negative limit", func(t *testing.T) {
+		f := ini.Empty()
+		s, err := f.NewSection("unified_alerting.evaluation_budget")
+		require.NoError(t, err)
+		_, err = s.NewKey("rule_max_datapoints", "-1")
+		require.NoError(t, err)
+
+		cfg := NewCfg()
+		cfg.IsFeatureToggleEnabled = func(key string) bool { return false }
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(f))
+	})
+}
+
 func TestUnifiedAlertingSettings(t *testing.T) {
 	testCases := []struct {
 		desc                   string
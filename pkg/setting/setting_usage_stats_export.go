@@ -0,0 +1,74 @@
+package setting
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"gopkg.in/ini.v1"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	UsageStatsExporterHTTP  = "http"
+	UsageStatsExporterLocal = "local"
+)
+
+// UsageStatsExportSettings configures the destinations of the usage stats report
+// in addition to Grafana.com, which is controlled by reporting_enabled.
+type UsageStatsExportSettings struct {
+	// Exporters are the enabled exporters, any of "http" and "local"
+	Exporters   []string
+	HTTPURL     string
+	HTTPHeaders map[string]string
+	HTTPTimeout time.Duration
+	// LocalRetention is how long the reports written to the database are kept
+	LocalRetention time.Duration
+}
+
+func (s UsageStatsExportSettings) IsEnabled(exporter string) bool {
+	for _, e := range s.Exporters {
+		if e == exporter {
+			return true
+		}
+	}
+	return false
+}
+
+func readUsageStatsExportSettings(iniFile *ini.File) (UsageStatsExportSettings, error) {
+	analytics := iniFile.Section("analytics")
+	s := UsageStatsExportSettings{
+		Exporters:   util.SplitString(analytics.Key("usage_stats_exporters").MustString("")),
+		HTTPURL:     analytics.Key("usage_stats_http_url").MustString(""),
+		HTTPHeaders: map[string]string{},
+		HTTPTimeout: analytics.Key("usage_stats_http_timeout").MustDuration(10 * time.Second),
+	}
+
+	for _, e := range s.Exporters {
+		if e != UsageStatsExporterHTTP && e != UsageStatsExporterLocal {
+			return s, fmt.Errorf("unknown usage stats exporter %q", e)
+		}
+	}
+
+	if s.IsEnabled(UsageStatsExporterHTTP) && s.HTTPURL == "" {
+		return s, fmt.Errorf("usage_stats_http_url is required by the http usage stats exporter")
+	}
+
+	for _, header := range util.SplitString(analytics.Key("usage_stats_http_headers").MustString("")) {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			return s, fmt.Errorf("invalid usage stats http header %q, expected Name:Value", header)
+		}
+		s.HTTPHeaders[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	retention, err := gtime.ParseDuration(analytics.Key("usage_stats_local_retention").MustString("90d"))
+	if err != nil {
+		return s, fmt.Errorf("invalid usage_stats_local_retention: %w", err)
+	}
+	s.LocalRetention = retention
+
+	return s, nil
+}
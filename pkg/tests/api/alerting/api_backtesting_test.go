@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
@@ -92,6 +93,28 @@ func TestBacktesting(t *testing.T) {
 		})
 	})
 
+	t.Run("and request contains number of evaluations", func(t *testing.T) {
+		t.Run("should replay the last evaluations", func(t *testing.T) {
+			request := queryRequest
+			request.From = time.Time{}
+			request.Evaluations = 10
+
+			status, body := apiCli.SubmitRuleForBacktesting(t, request)
+			require.Equalf(t, http.StatusOK, status, "Response: %s", body)
+			var result data.Frame
+			require.NoErrorf(t, json.Unmarshal([]byte(body), &result), "cannot parse response to data frame")
+			require.Equal(t, 10, result.Rows())
+		})
+
+		t.Run("should reject request with from", func(t *testing.T) {
+			request := queryRequest
+			request.Evaluations = 10
+
+			status, body := apiCli.SubmitRuleForBacktesting(t, request)
+			require.Equalf(t, http.StatusBadRequest, status, "Response: %s", body)
+		})
+	})
+
 	t.Run("if user does not have permissions", func(t *testing.T) {
 		if !setting.IsEnterprise {
 			t.Skip("Enterprise-only test")
@@ -0,0 +1,103 @@
+package correlations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/correlations"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestIntegrationGenerateCorrelationTargetQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := NewTestEnv(t)
+
+	adminUser := ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleAdmin),
+		Password:       "admin",
+		Login:          "admin",
+	})
+
+	viewerUser := ctx.createUser(user.CreateUserCommand{
+		DefaultOrgRole: string(org.RoleViewer),
+		Password:       "viewer",
+		Login:          "viewer",
+		OrgID:          adminUser.User.OrgID,
+	})
+
+	logsDs := ctx.createDs(&datasources.AddDataSourceCommand{
+		Name:  "logs",
+		Type:  "loki",
+		OrgID: 1,
+	})
+	tracesDs := ctx.createDs(&datasources.AddDataSourceCommand{
+		Name:  "traces",
+		Type:  "tempo",
+		OrgID: 1,
+	})
+	correlation := ctx.createCorrelation(correlations.CreateCorrelationCommand{
+		SourceUID: logsDs.UID,
+		TargetUID: &tracesDs.UID,
+		OrgId:     logsDs.OrgID,
+		Config: correlations.CorrelationConfig{
+			Type:   correlations.ConfigTypeQuery,
+			Field:  "message",
+			Target: map[string]interface{}{"query": "${traceID}", "queryType": "traceId"},
+			Transformations: []correlations.Transformation{
+				{Type: "logfmt"},
+				{Type: "template", Expression: "${service}/${level}", MapValue: "name"},
+			},
+		},
+	})
+
+	t.Run("Should generate the target query for a viewer", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/target-query", logsDs.UID, correlation.UID),
+			body: `{"fields": {"message": "level=error traceID=8f3d1", "service": "api"}}`,
+			user: viewerUser,
+		})
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response correlations.TargetQuery
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, tracesDs.UID, *response.TargetUID)
+		require.Equal(t, map[string]interface{}{"query": "8f3d1", "queryType": "traceId"}, response.Query)
+		require.Equal(t, "api/error", response.Variables["name"])
+
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("If no correlation exists it should return 404", func(t *testing.T) {
+		res := ctx.Post(PostParams{
+			url:  fmt.Sprintf("/api/datasources/uid/%s/correlations/%s/target-query", logsDs.UID, "some-correlation-uid"),
+			body: `{"fields": {"message": "level=error"}}`,
+			user: adminUser,
+		})
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+		responseBody, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		var response errorResponseBody
+		err = json.Unmarshal(responseBody, &response)
+		require.NoError(t, err)
+
+		require.Equal(t, "Correlation not found", response.Message)
+
+		require.NoError(t, res.Body.Close())
+	})
+}
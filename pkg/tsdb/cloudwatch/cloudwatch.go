@@ -238,6 +238,12 @@ func (e *cloudWatchExecutor) checkHealthLogs(pluginCtx backend.PluginContext) er
 }
 
 func (e *cloudWatchExecutor) newSession(pluginCtx backend.PluginContext, region string) (*session.Session, error) {
+	return e.newSessionForRole(pluginCtx, region, "")
+}
+
+// newSessionForRole returns a session assuming the given role instead of the role of the data source, when set.
+// The sessions and their credentials are cached per region and role.
+func (e *cloudWatchExecutor) newSessionForRole(pluginCtx backend.PluginContext, region string, roleARN string) (*session.Session, error) {
 	instance, err := e.getInstance(pluginCtx)
 	if err != nil {
 		return nil, err
@@ -247,6 +253,11 @@ func (e *cloudWatchExecutor) newSession(pluginCtx backend.PluginContext, region
 		region = instance.Settings.Region
 	}
 
+	assumeRoleARN := instance.Settings.AssumeRoleARN
+	if roleARN != "" {
+		assumeRoleARN = roleARN
+	}
+
 	return e.sessions.GetSession(awsds.SessionConfig{
 		// https://github.com/grafana/grafana/issues/46365
 		// HTTPClient: dsInfo.HTTPClient,
@@ -254,7 +265,7 @@ func (e *cloudWatchExecutor) newSession(pluginCtx backend.PluginContext, region
 			Profile:       instance.Settings.Profile,
 			Region:        region,
 			AuthType:      instance.Settings.AuthType,
-			AssumeRoleARN: instance.Settings.AssumeRoleARN,
+			AssumeRoleARN: assumeRoleARN,
 			ExternalID:    instance.Settings.ExternalID,
 			Endpoint:      instance.Settings.Endpoint,
 			DefaultRegion: instance.Settings.Region,
@@ -276,7 +287,11 @@ func (e *cloudWatchExecutor) getInstance(pluginCtx backend.PluginContext) (*Data
 }
 
 func (e *cloudWatchExecutor) getCWClient(pluginCtx backend.PluginContext, region string) (cloudwatchiface.CloudWatchAPI, error) {
-	sess, err := e.newSession(pluginCtx, region)
+	return e.getCWClientForRole(pluginCtx, region, "")
+}
+
+func (e *cloudWatchExecutor) getCWClientForRole(pluginCtx backend.PluginContext, region string, roleARN string) (cloudwatchiface.CloudWatchAPI, error) {
+	sess, err := e.newSessionForRole(pluginCtx, region, roleARN)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,57 @@
+package cloudwatch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const accountLabel = "account"
+
+// accountIDFromRoleARN returns the account of a role ARN, arn:aws:iam::<account>:role/<name>, or the ARN itself
+// when it cannot be parsed.
+func accountIDFromRoleARN(roleARN string) string {
+	parts := strings.Split(roleARN, ":")
+	if len(parts) < 6 || parts[4] == "" {
+		return roleARN
+	}
+	return parts[4]
+}
+
+// addAccountLabel labels the series with the account they were queried from, so that the same series of different
+// accounts can be told apart.
+func addAccountLabel(frames data.Frames, accountID string) {
+	for _, frame := range frames {
+		if frame.Name == "" {
+			frame.Name = accountID
+		} else {
+			frame.Name = fmt.Sprintf("%s (%s)", frame.Name, accountID)
+		}
+		for _, field := range frame.Fields {
+			if field.Type().Time() {
+				continue
+			}
+			if field.Labels == nil {
+				field.Labels = data.Labels{}
+			}
+			field.Labels[accountLabel] = accountID
+			if field.Config != nil && field.Config.DisplayNameFromDS != "" {
+				field.Config.DisplayNameFromDS = frame.Name
+			}
+		}
+	}
+}
+
+// mergeDataResponses merges the responses of the same query run in several accounts.
+func mergeDataResponses(existing backend.DataResponse, res backend.DataResponse) backend.DataResponse {
+	existing.Frames = append(existing.Frames, res.Frames...)
+	switch {
+	case existing.Error == nil:
+		existing.Error = res.Error
+	case res.Error != nil:
+		existing.Error = fmt.Errorf("%v; %w", existing.Error, res.Error)
+	}
+	return existing
+}
@@ -0,0 +1,96 @@
+package cloudwatch
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/grafana/grafana-aws-sdk/pkg/awsds"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/tsdb/cloudwatch/mocks"
+	"github.com/grafana/grafana/pkg/tsdb/cloudwatch/models"
+)
+
+func TestAccountIDFromRoleARN(t *testing.T) {
+	assert.Equal(t, "123456789012", accountIDFromRoleARN("arn:aws:iam::123456789012:role/grafana"))
+	assert.Equal(t, "not-an-arn", accountIDFromRoleARN("not-an-arn"))
+}
+
+func TestTimeSeriesQuery_CrossAccountRoles(t *testing.T) {
+	origNewCWClient := NewCWClient
+	t.Cleanup(func() {
+		NewCWClient = origNewCWClient
+	})
+
+	now := time.Now()
+	api := mocks.MetricsAPI{}
+	api.On("GetMetricDataWithContext", mock.Anything, mock.Anything, mock.Anything).Return(&cloudwatch.GetMetricDataOutput{
+		MetricDataResults: []*cloudwatch.MetricDataResult{
+			{StatusCode: aws.String("Complete"), Id: aws.String("a"), Label: aws.String("NetworkOut"), Values: []*float64{aws.Float64(1.0)}, Timestamps: []*time.Time{&now}},
+		}}, nil)
+	NewCWClient = func(sess *session.Session) cloudwatchiface.CloudWatchAPI {
+		return &api
+	}
+
+	var mu sync.Mutex
+	var assumedRoles []string
+	sessions := &fakeSessionCache{getSession: func(c awsds.SessionConfig) (*session.Session, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		assumedRoles = append(assumedRoles, c.Settings.AssumeRoleARN)
+		return &session.Session{Config: &aws.Config{}}, nil
+	}}
+
+	im := datasource.NewInstanceManager(func(s backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		return DataSource{Settings: models.CloudWatchSettings{
+			CrossAccountRoleARNs: []string{"arn:aws:iam::111111111111:role/grafana", "arn:aws:iam::222222222222:role/grafana"},
+		}}, nil
+	})
+	executor := newExecutor(im, newTestConfig(), sessions, featuremgmt.WithFeatures())
+
+	resp, err := executor.QueryData(context.Background(), &backend.QueryDataRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{},
+		},
+		Queries: []backend.DataQuery{
+			{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: now.Add(time.Hour * -2), To: now.Add(time.Hour * -1)},
+				JSON: json.RawMessage(`{
+					"type":      "timeSeriesQuery",
+					"namespace": "AWS/EC2",
+					"metricName": "NetworkOut",
+					"region": "us-east-1",
+					"id": "a",
+					"statistic": "Maximum",
+					"period": "300"
+				}`),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	sort.Strings(assumedRoles)
+	assert.Equal(t, []string{"arn:aws:iam::111111111111:role/grafana", "arn:aws:iam::222222222222:role/grafana"}, assumedRoles)
+
+	frames := resp.Responses["A"].Frames
+	require.Len(t, frames, 2)
+	accounts := []string{frames[0].Fields[1].Labels[accountLabel], frames[1].Fields[1].Labels[accountLabel]}
+	sort.Strings(accounts)
+	assert.Equal(t, []string{"111111111111", "222222222222"}, accounts)
+	assert.Contains(t, []string{"NetworkOut_Maximum (111111111111)", "NetworkOut_Maximum (222222222222)"}, frames[0].Name)
+}
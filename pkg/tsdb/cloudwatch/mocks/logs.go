@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/grafana/grafana/pkg/tsdb/cloudwatch/models/resources"
 	"github.com/stretchr/testify/mock"
@@ -47,3 +49,7 @@ func (f *MockFeatures) IsEnabled(feature string) bool {
 
 	return args.Bool(0)
 }
+
+func (f *MockFeatures) IsEnabledCtx(_ context.Context, feature string) bool {
+	return f.IsEnabled(feature)
+}
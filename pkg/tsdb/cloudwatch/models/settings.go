@@ -11,6 +11,8 @@ import (
 type CloudWatchSettings struct {
 	awsds.AWSDatasourceSettings
 	Namespace string `json:"customMetricsNamespaces"`
+	// CrossAccountRoleARNs are the roles assumed to run the metric queries in several accounts at once.
+	CrossAccountRoleARNs []string `json:"crossAccountRoleArns"`
 }
 
 func LoadCloudWatchSettings(config backend.DataSourceInstanceSettings) (CloudWatchSettings, error) {
@@ -55,51 +55,63 @@ func (e *cloudWatchExecutor) executeTimeSeriesQuery(ctx context.Context, logger
 		requestQueriesByRegion[query.Region] = append(requestQueriesByRegion[query.Region], query)
 	}
 
-	resultChan := make(chan *responseWrapper, len(req.Queries))
+	// without cross-account roles, the queries run once with the credentials of the data source
+	roleARNs := instance.Settings.CrossAccountRoleARNs
+	if len(roleARNs) == 0 {
+		roleARNs = []string{""}
+	}
+
+	resultChan := make(chan *responseWrapper, len(req.Queries)*len(roleARNs))
 	eg, ectx := errgroup.WithContext(ctx)
 	for r, q := range requestQueriesByRegion {
-		requestQueries := q
-		region := r
-		eg.Go(func() error {
-			defer func() {
-				if err := recover(); err != nil {
-					logger.Error("Execute Get Metric Data Query Panic", "error", err, "stack", log.Stack(1))
-					if theErr, ok := err.(error); ok {
-						resultChan <- &responseWrapper{
-							DataResponse: &backend.DataResponse{
-								Error: theErr,
-							},
+		for _, roleARN := range roleARNs {
+			requestQueries := q
+			region := r
+			roleARN := roleARN
+			eg.Go(func() error {
+				defer func() {
+					if err := recover(); err != nil {
+						logger.Error("Execute Get Metric Data Query Panic", "error", err, "stack", log.Stack(1))
+						if theErr, ok := err.(error); ok {
+							resultChan <- &responseWrapper{
+								DataResponse: &backend.DataResponse{
+									Error: theErr,
+								},
+							}
 						}
 					}
+				}()
+
+				client, err := e.getCWClientForRole(req.PluginContext, region, roleARN)
+				if err != nil {
+					return err
+				}
+
+				metricDataInput, err := e.buildMetricDataInput(logger, startTime, endTime, requestQueries)
+				if err != nil {
+					return err
+				}
+
+				mdo, err := e.executeRequest(ectx, client, metricDataInput)
+				if err != nil {
+					return err
+				}
+
+				res, err := e.parseResponse(startTime, endTime, mdo, requestQueries)
+				if err != nil {
+					return err
+				}
+
+				for _, responseWrapper := range res {
+					if roleARN != "" {
+						addAccountLabel(responseWrapper.DataResponse.Frames, accountIDFromRoleARN(roleARN))
+					}
+					resultChan <- responseWrapper
 				}
-			}()
-
-			client, err := e.getCWClient(req.PluginContext, region)
-			if err != nil {
-				return err
-			}
-
-			metricDataInput, err := e.buildMetricDataInput(logger, startTime, endTime, requestQueries)
-			if err != nil {
-				return err
-			}
-
-			mdo, err := e.executeRequest(ectx, client, metricDataInput)
-			if err != nil {
-				return err
-			}
-
-			res, err := e.parseResponse(startTime, endTime, mdo, requestQueries)
-			if err != nil {
-				return err
-			}
-
-			for _, responseWrapper := range res {
-				resultChan <- responseWrapper
-			}
-
-			return nil
-		})
+
+				return nil
+			})
+		}
 	}
 
 	if err := eg.Wait(); err != nil {
@@ -113,7 +125,7 @@ func (e *cloudWatchExecutor) executeTimeSeriesQuery(ctx context.Context, logger
 	close(resultChan)
 
 	for result := range resultChan {
-		resp.Responses[result.RefId] = *result.DataResponse
+		resp.Responses[result.RefId] = mergeDataResponses(resp.Responses[result.RefId], *result.DataResponse)
 	}
 
 	return resp, nil
@@ -0,0 +1,163 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// asyncSearchWaitTimeout is how long each request to Elasticsearch waits for the search to complete, it is kept
+	// short so that no request is held open long enough to be dropped by a proxy.
+	asyncSearchWaitTimeout = 5 * time.Second
+	// asyncSearchKeepAlive is how long Elasticsearch keeps a running search after the last poll.
+	asyncSearchKeepAlive = time.Minute
+)
+
+// asyncSearchResponse is the response of the submit and get async search APIs.
+type asyncSearchResponse struct {
+	ID        string                 `json:"id"`
+	IsRunning bool                   `json:"is_running"`
+	IsPartial bool                   `json:"is_partial"`
+	Response  *SearchResponse        `json:"response"`
+	Error     map[string]interface{} `json:"error"`
+}
+
+// executeAsyncSearches runs every search of the multisearch request with the async search API, in parallel, and
+// returns their responses as a multisearch response.
+func (c *baseClientImpl) executeAsyncSearches(r *MultiSearchRequest) (*MultiSearchResponse, error) {
+	multiRequests := c.createMultiSearchRequests(r.Requests)
+	responses := make([]*SearchResponse, len(multiRequests))
+	errs := make([]error, len(multiRequests))
+
+	var wg sync.WaitGroup
+	for i, mr := range multiRequests {
+		wg.Add(1)
+		go func(i int, mr *multiRequest) {
+			defer wg.Done()
+			responses[i], errs[i] = c.executeAsyncSearch(mr)
+		}(i, mr)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &MultiSearchResponse{Status: http.StatusOK, Responses: responses}, nil
+}
+
+func (c *baseClientImpl) executeAsyncSearch(mr *multiRequest) (*SearchResponse, error) {
+	body, err := encodeRequestBody(mr)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.doAsyncSearchRequest(http.MethodPost, path.Join(strings.Join(c.indices, ","), "_async_search"), c.getAsyncSearchQueryParameters(), []byte(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for res.IsRunning {
+		c.logger.Debug("Polling async search", "id", res.ID, "partial", res.IsPartial)
+		if c.ctx.Err() != nil {
+			c.deleteAsyncSearch(res.ID)
+			return nil, c.ctx.Err()
+		}
+		qs := url.Values{}
+		qs.Set("wait_for_completion_timeout", formatDuration(asyncSearchWaitTimeout))
+		qs.Set("keep_alive", formatDuration(asyncSearchKeepAlive))
+		id := res.ID
+		res, err = c.doAsyncSearchRequest(http.MethodGet, path.Join("_async_search", id), qs.Encode(), nil)
+		if err != nil {
+			c.deleteAsyncSearch(id)
+			return nil, err
+		}
+	}
+
+	if res.ID != "" {
+		// the search outlived the first request and is stored until it expires
+		c.deleteAsyncSearch(res.ID)
+	}
+	if res.Error != nil {
+		return &SearchResponse{Error: res.Error}, nil
+	}
+	if res.Response == nil {
+		return nil, fmt.Errorf("async search returned no response")
+	}
+	return res.Response, nil
+}
+
+func (c *baseClientImpl) doAsyncSearchRequest(method, uriPath, uriQuery string, body []byte) (*asyncSearchResponse, error) {
+	httpRes, err := c.executeRequest(method, uriPath, uriQuery, body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := httpRes.Body.Close(); err != nil {
+			c.logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	var res asyncSearchResponse
+	if err := json.NewDecoder(httpRes.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode async search response with status %d: %w", httpRes.StatusCode, err)
+	}
+	return &res, nil
+}
+
+// deleteAsyncSearch cancels a running search or deletes its stored results. It does not use the context of the
+// query, which may be cancelled already.
+func (c *baseClientImpl) deleteAsyncSearch(id string) {
+	u, err := url.Parse(c.ds.URL)
+	if err != nil {
+		return
+	}
+	u.Path = path.Join(u.Path, "_async_search", id)
+
+	ctx, cancel := context.WithTimeout(context.Background(), asyncSearchWaitTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+	if err != nil {
+		return
+	}
+	//nolint:bodyclose
+	res, err := c.ds.HTTPClient.Do(req)
+	if err != nil {
+		c.logger.Warn("Failed to delete async search", "id", id, "err", err)
+		return
+	}
+	if err := res.Body.Close(); err != nil {
+		c.logger.Warn("Failed to close response body", "err", err)
+	}
+}
+
+func (c *baseClientImpl) getAsyncSearchQueryParameters() string {
+	qs := url.Values{}
+	qs.Set("ignore_unavailable", "true")
+	qs.Set("wait_for_completion_timeout", formatDuration(asyncSearchWaitTimeout))
+	qs.Set("keep_alive", formatDuration(asyncSearchKeepAlive))
+
+	maxConcurrentShardRequests := c.ds.MaxConcurrentShardRequests
+	if maxConcurrentShardRequests == 0 {
+		maxConcurrentShardRequests = 5
+	}
+	qs.Set("max_concurrent_shard_requests", fmt.Sprintf("%d", maxConcurrentShardRequests))
+
+	if c.ds.IncludeFrozen && c.ds.XPack {
+		qs.Set("ignore_throttled", "false")
+	}
+	return qs.Encode()
+}
+
+// formatDuration formats a duration in the Elasticsearch time units.
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
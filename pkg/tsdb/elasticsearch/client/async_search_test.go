@@ -0,0 +1,114 @@
+package es
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ExecuteAsyncSearch(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+		body     string
+		polls    int
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/metrics-2018.05.15/_async_search":
+			require.Equal(t, "true", r.URL.Query().Get("ignore_unavailable"))
+			require.Equal(t, "5000ms", r.URL.Query().Get("wait_for_completion_timeout"))
+			buf, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			body = string(buf)
+			_, _ = rw.Write([]byte(`{"id": "search-1", "is_running": true, "is_partial": true}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/_async_search/search-1":
+			polls++
+			if polls == 1 {
+				_, _ = rw.Write([]byte(`{"id": "search-1", "is_running": true, "is_partial": true}`))
+				return
+			}
+			_, _ = rw.Write([]byte(`{"id": "search-1", "is_running": false, "is_partial": false, "response": {"hits": {"hits": [{"_id": "1"}]}}}`))
+		case r.Method == http.MethodDelete:
+			_, _ = rw.Write([]byte(`{"acknowledged": true}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	version, err := semver.NewVersion("8.0.0")
+	require.NoError(t, err)
+	ds := DatasourceInfo{
+		URL:              ts.URL,
+		HTTPClient:       ts.Client(),
+		Database:         "[metrics-]YYYY.MM.DD",
+		ESVersion:        version,
+		ConfiguredFields: ConfiguredFields{TimeField: "@timestamp"},
+		Interval:         "Daily",
+		AsyncSearch:      true,
+	}
+	from := time.Date(2018, 5, 15, 17, 50, 0, 0, time.UTC)
+	c, err := NewClient(context.Background(), &ds, backend.TimeRange{From: from, To: from.Add(5 * time.Minute)})
+	require.NoError(t, err)
+
+	ms := c.MultiSearch()
+	ms.Search(15*time.Second).Size(10).AddDocValueField("@timestamp").Query().Bool().Filter().AddDateRangeFilter("@timestamp", from.UnixMilli(), from.Add(5*time.Minute).UnixMilli(), DateFormatEpochMS)
+	req, err := ms.Build()
+	require.NoError(t, err)
+
+	res, err := c.ExecuteMultisearch(req)
+	require.NoError(t, err)
+	require.Len(t, res.Responses, 1)
+	require.Len(t, res.Responses[0].Hits.Hits, 1)
+
+	require.Contains(t, body, `"size":10`)
+	require.Equal(t, []string{
+		"POST /metrics-2018.05.15/_async_search",
+		"GET /_async_search/search-1",
+		"GET /_async_search/search-1",
+		"DELETE /_async_search/search-1",
+	}, requests)
+}
+
+func TestClient_ExecuteAsyncSearchError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadRequest)
+		_, _ = rw.Write([]byte(`{"error": {"type": "parsing_exception", "reason": "unknown query"}, "status": 400}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	version, err := semver.NewVersion("8.0.0")
+	require.NoError(t, err)
+	ds := DatasourceInfo{
+		URL:              ts.URL,
+		HTTPClient:       ts.Client(),
+		Database:         "metrics",
+		ESVersion:        version,
+		ConfiguredFields: ConfiguredFields{TimeField: "@timestamp"},
+		AsyncSearch:      true,
+	}
+	c, err := NewClient(context.Background(), &ds, backend.TimeRange{From: time.Now().Add(-time.Hour), To: time.Now()})
+	require.NoError(t, err)
+
+	ms := c.MultiSearch()
+	ms.Search(15 * time.Second)
+	req, err := ms.Build()
+	require.NoError(t, err)
+
+	res, err := c.ExecuteMultisearch(req)
+	require.NoError(t, err)
+	require.Equal(t, "unknown query", res.Responses[0].Error["reason"])
+}
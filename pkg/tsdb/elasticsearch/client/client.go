@@ -31,6 +31,8 @@ type DatasourceInfo struct {
 	MaxConcurrentShardRequests int64
 	IncludeFrozen              bool
 	XPack                      bool
+	// AsyncSearch runs the searches with the async search API and polls for their results
+	AsyncSearch bool
 }
 
 type ConfiguredFields struct {
@@ -118,15 +120,11 @@ func (c *baseClientImpl) encodeBatchRequests(requests []*multiRequest) ([]byte,
 		}
 		payload.WriteString(string(reqHeader) + "\n")
 
-		reqBody, err := json.Marshal(r.body)
+		body, err := encodeRequestBody(r)
 		if err != nil {
 			return nil, err
 		}
 
-		body := string(reqBody)
-		body = strings.ReplaceAll(body, "$__interval_ms", strconv.FormatInt(r.interval.Milliseconds(), 10))
-		body = strings.ReplaceAll(body, "$__interval", r.interval.String())
-
 		payload.WriteString(body + "\n")
 	}
 
@@ -136,6 +134,18 @@ func (c *baseClientImpl) encodeBatchRequests(requests []*multiRequest) ([]byte,
 	return payload.Bytes(), nil
 }
 
+func encodeRequestBody(r *multiRequest) (string, error) {
+	reqBody, err := json.Marshal(r.body)
+	if err != nil {
+		return "", err
+	}
+
+	body := string(reqBody)
+	body = strings.ReplaceAll(body, "$__interval_ms", strconv.FormatInt(r.interval.Milliseconds(), 10))
+	body = strings.ReplaceAll(body, "$__interval", r.interval.String())
+	return body, nil
+}
+
 func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body []byte) (*http.Response, error) {
 	u, err := url.Parse(c.ds.URL)
 	if err != nil {
@@ -148,7 +158,7 @@ func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body [
 	if method == http.MethodPost {
 		req, err = http.NewRequestWithContext(c.ctx, http.MethodPost, u.String(), bytes.NewBuffer(body))
 	} else {
-		req, err = http.NewRequestWithContext(c.ctx, http.MethodGet, u.String(), nil)
+		req, err = http.NewRequestWithContext(c.ctx, method, u.String(), nil)
 	}
 	if err != nil {
 		return nil, err
@@ -175,6 +185,10 @@ func (c *baseClientImpl) executeRequest(method, uriPath, uriQuery string, body [
 func (c *baseClientImpl) ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error) {
 	c.logger.Debug("Executing multisearch", "search requests", len(r.Requests))
 
+	if c.ds.AsyncSearch {
+		return c.executeAsyncSearches(r)
+	}
+
 	multiRequests := c.createMultiSearchRequests(r.Requests)
 	queryParams := c.getMultiSearchQueryParameters()
 	clientRes, err := c.executeBatchRequest("_msearch", queryParams, multiRequests)
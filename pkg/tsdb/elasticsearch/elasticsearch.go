@@ -142,6 +142,11 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			xpack = false
 		}
 
+		asyncSearch, ok := jsonData["asyncSearch"].(bool)
+		if !ok {
+			asyncSearch = false
+		}
+
 		configuredFields := es.ConfiguredFields{
 			TimeField:       timeField,
 			LogLevelField:   logLevelField,
@@ -160,6 +165,7 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			TimeInterval:               timeInterval,
 			IncludeFrozen:              includeFrozen,
 			XPack:                      xpack,
+			AsyncSearch:                asyncSearch,
 		}
 		return model, nil
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/adapters"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 )
 
@@ -30,7 +31,7 @@ func ProvideService(pluginsClient plugins.Client, oAuthTokenService oauthtoken.O
 
 //nolint:staticcheck // legacydata.DataResponse deprecated
 func (h *Service) HandleRequest(ctx context.Context, ds *datasources.DataSource, query legacydata.DataQuery) (legacydata.DataResponse, error) {
-	decryptedJsonData, err := h.dataSourcesService.DecryptedValues(ctx, ds)
+	decryptedJsonData, err := h.dataSourcesService.DecryptedValues(secrets.WithConsumer(ctx, "legacy-data"), ds)
 	if err != nil {
 		return legacydata.DataResponse{}, err
 	}
@@ -0,0 +1,134 @@
+package loki
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// queryLimits are the guardrails of the log queries of a data source, configured in the queryLimits
+// field of the jsonData.
+type queryLimits struct {
+	// MaxLines is the maximum number of log lines of a query, 0 means no limit.
+	MaxLines int `json:"maxLines"`
+	// MaxBytes is the maximum size of the log lines of a query, 0 means no limit.
+	MaxBytes int64 `json:"maxBytes"`
+	// Sampling returns every Nth line of the queries above the limits instead of failing them.
+	Sampling bool `json:"sampling"`
+}
+
+func parseQueryLimits(jsonData json.RawMessage) (queryLimits, error) {
+	var settings struct {
+		QueryLimits queryLimits `json:"queryLimits"`
+	}
+	if len(jsonData) == 0 {
+		return settings.QueryLimits, nil
+	}
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return queryLimits{}, fmt.Errorf("error reading settings: %w", err)
+	}
+	return settings.QueryLimits, nil
+}
+
+// applyToQuery caps the number of lines requested from Loki. In sampling mode the lines requested by the query
+// are fetched and sampled afterwards.
+func (l queryLimits) applyToQuery(query *lokiQuery) {
+	if l.MaxLines <= 0 || l.Sampling {
+		return
+	}
+	if query.MaxLines == 0 || query.MaxLines > l.MaxLines {
+		query.MaxLines = l.MaxLines
+	}
+}
+
+// enforce checks the log lines of the frames against the limits. Without sampling, it fails when the lines exceed
+// the limits. With sampling, it keeps every Nth line, with the smallest N that fits within the limits, and adds a
+// notice to the frames.
+func (l queryLimits) enforce(frames data.Frames) (data.Frames, error) {
+	if l.MaxLines <= 0 && l.MaxBytes <= 0 {
+		return frames, nil
+	}
+
+	var sizes []int64
+	var totalBytes int64
+	for _, frame := range frames {
+		if !isLogsFrame(frame) {
+			continue
+		}
+		lineField := frame.Fields[2]
+		for i := 0; i < lineField.Len(); i++ {
+			size := int64(len(lineField.At(i).(string)))
+			sizes = append(sizes, size)
+			totalBytes += size
+		}
+	}
+
+	if l.withinLimits(len(sizes), totalBytes) {
+		return frames, nil
+	}
+	if !l.Sampling {
+		return nil, fmt.Errorf("the query returned %d log lines (%d bytes), which exceeds the limits of the data source, narrow down the query or its time range", len(sizes), totalBytes)
+	}
+
+	n := 2
+	for ; n < len(sizes); n++ {
+		lines, bytes := 0, int64(0)
+		for i := 0; i < len(sizes); i += n {
+			lines++
+			bytes += sizes[i]
+		}
+		if l.withinLimits(lines, bytes) {
+			break
+		}
+	}
+
+	notice := data.Notice{
+		Severity: data.NoticeSeverityWarning,
+		Text:     fmt.Sprintf("The query returned %d log lines (%d bytes), which exceeds the limits of the data source. Only every %s line is shown.", len(sizes), totalBytes, ordinal(n)),
+	}
+	row := 0
+	sampled := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		if !isLogsFrame(frame) {
+			sampled = append(sampled, frame)
+			continue
+		}
+		sampledFrame := frame.EmptyCopy()
+		for i := 0; i < frame.Rows(); i++ {
+			if row%n == 0 {
+				sampledFrame.AppendRow(frame.RowCopy(i)...)
+			}
+			row++
+		}
+		if sampledFrame.Meta == nil {
+			sampledFrame.Meta = &data.FrameMeta{}
+		}
+		sampledFrame.Meta.Notices = append(sampledFrame.Meta.Notices, notice)
+		sampled = append(sampled, sampledFrame)
+	}
+	return sampled, nil
+}
+
+func (l queryLimits) withinLimits(lines int, bytes int64) bool {
+	return (l.MaxLines <= 0 || lines <= l.MaxLines) && (l.MaxBytes <= 0 || bytes <= l.MaxBytes)
+}
+
+// isLogsFrame returns whether the frame is a logs frame adjusted by adjustLogsFrame.
+func isLogsFrame(frame *data.Frame) bool {
+	return len(frame.Fields) > 2 && frame.Fields[1].Type() == data.FieldTypeTime && frame.Fields[2].Type() == data.FieldTypeString
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
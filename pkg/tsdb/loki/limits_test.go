@@ -0,0 +1,96 @@
+package loki
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func makeLogsFrame(lines ...string) *data.Frame {
+	labels := make([]json.RawMessage, len(lines))
+	times := make([]time.Time, len(lines))
+	tsNs := make([]string, len(lines))
+	for i := range lines {
+		labels[i] = json.RawMessage(`{"app":"a"}`)
+		times[i] = time.Unix(int64(i), 0)
+		tsNs[i] = fmt.Sprintf("%d", times[i].UnixNano())
+	}
+	return data.NewFrame("",
+		data.NewField("labels", nil, labels),
+		data.NewField("Time", nil, times),
+		data.NewField("Line", nil, lines),
+		data.NewField("tsNs", nil, tsNs),
+	)
+}
+
+func frameLines(frame *data.Frame) []string {
+	lines := make([]string, 0, frame.Rows())
+	for i := 0; i < frame.Rows(); i++ {
+		lines = append(lines, frame.Fields[2].At(i).(string))
+	}
+	return lines
+}
+
+func TestQueryLimits(t *testing.T) {
+	t.Run("parses the limits of the data source", func(t *testing.T) {
+		limits, err := parseQueryLimits(json.RawMessage(`{"queryLimits": {"maxLines": 100, "maxBytes": 2048, "sampling": true}}`))
+		require.NoError(t, err)
+		require.Equal(t, queryLimits{MaxLines: 100, MaxBytes: 2048, Sampling: true}, limits)
+
+		limits, err = parseQueryLimits(nil)
+		require.NoError(t, err)
+		require.Equal(t, queryLimits{}, limits)
+	})
+
+	t.Run("caps the lines requested without sampling", func(t *testing.T) {
+		query := &lokiQuery{MaxLines: 5000}
+		queryLimits{MaxLines: 1000}.applyToQuery(query)
+		require.Equal(t, 1000, query.MaxLines)
+
+		query = &lokiQuery{MaxLines: 5000}
+		queryLimits{MaxLines: 1000, Sampling: true}.applyToQuery(query)
+		require.Equal(t, 5000, query.MaxLines)
+	})
+
+	t.Run("leaves the frames within the limits untouched", func(t *testing.T) {
+		frames := data.Frames{makeLogsFrame("a", "b", "c")}
+		result, err := queryLimits{MaxLines: 3, MaxBytes: 3}.enforce(frames)
+		require.NoError(t, err)
+		require.Equal(t, frames, result)
+	})
+
+	t.Run("fails the queries above the limits without sampling", func(t *testing.T) {
+		_, err := queryLimits{MaxBytes: 10}.enforce(data.Frames{makeLogsFrame(strings.Repeat("a", 11))})
+		require.Error(t, err)
+	})
+
+	t.Run("samples the queries above the limits", func(t *testing.T) {
+		metricFrame := data.NewFrame("",
+			data.NewField("Time", nil, []time.Time{time.Unix(0, 0)}),
+			data.NewField("Value", nil, []float64{1}),
+		)
+		frames := data.Frames{makeLogsFrame("0", "1", "2", "3", "4", "5", "6", "7", "8", "9"), metricFrame}
+
+		result, err := queryLimits{MaxLines: 4, Sampling: true}.enforce(frames)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		require.Equal(t, []string{"0", "3", "6", "9"}, frameLines(result[0]))
+		require.Len(t, result[0].Meta.Notices, 1)
+		require.Contains(t, result[0].Meta.Notices[0].Text, "every 3rd line")
+		require.Same(t, metricFrame, result[1])
+		// the original frame is not modified
+		require.Equal(t, 10, frames[0].Rows())
+	})
+
+	t.Run("samples by size", func(t *testing.T) {
+		frames := data.Frames{makeLogsFrame(strings.Repeat("a", 10), "b", strings.Repeat("c", 10), "d")}
+		result, err := queryLimits{MaxBytes: 10, Sampling: true}.enforce(frames)
+		require.NoError(t, err)
+		require.Equal(t, []string{strings.Repeat("a", 10)}, frameLines(result[0]))
+	})
+}
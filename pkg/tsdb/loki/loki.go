@@ -51,6 +51,7 @@ var (
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
+	limits     queryLimits
 
 	// open streams
 	streams   map[string]data.FrameJSONCache
@@ -81,9 +82,15 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		limits, err := parseQueryLimits(settings.JSONData)
+		if err != nil {
+			return nil, err
+		}
+
 		model := &datasourceInfo{
 			HTTPClient: client,
 			URL:        settings.URL,
+			limits:     limits,
 			streams:    make(map[string]data.FrameJSONCache),
 		}
 		return model, nil
@@ -163,10 +170,15 @@ func queryData(ctx context.Context, req *backend.QueryDataRequest, dsInfo *datas
 		span.SetAttributes("start_unixnano", query.Start, attribute.Key("start_unixnano").Int64(query.Start.UnixNano()))
 		span.SetAttributes("stop_unixnano", query.End, attribute.Key("stop_unixnano").Int64(query.End.UnixNano()))
 
+		dsInfo.limits.applyToQuery(query)
+
 		logger := logger.FromContext(ctx) // get logger with trace-id and other contextual info
 		logger.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "query", query.Expr)
 
 		frames, err := runQuery(ctx, api, query)
+		if err == nil {
+			frames, err = dsInfo.limits.enforce(frames)
+		}
 
 		span.End()
 		queryRes := backend.DataResponse{}
@@ -94,20 +94,24 @@ func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
 		}
 
 		driverName := "mssql"
+		userConnectionString := generateConnectionString
 		// register a new proxy driver if the secure socks proxy is enabled
 		if cfg.IsFeatureToggleEnabled(featuremgmt.FlagSecureSocksDatasourceProxy) && cfg.SecureSocksDSProxy.Enabled && jsonData.SecureDSProxy {
 			driverName, err = createMSSQLProxyDriver(&cfg.SecureSocksDSProxy, cnnstr)
 			if err != nil {
 				return nil, err
 			}
+			// the proxy driver is bound to the connection string of the shared account
+			userConnectionString = nil
 		}
 
 		config := sqleng.DataPluginConfiguration{
-			DriverName:        driverName,
-			ConnectionString:  cnnstr,
-			DSInfo:            dsInfo,
-			MetricColumnTypes: []string{"VARCHAR", "CHAR", "NVARCHAR", "NCHAR"},
-			RowLimit:          cfg.DataProxyRowLimit,
+			DriverName:           driverName,
+			ConnectionString:     cnnstr,
+			DSInfo:               dsInfo,
+			MetricColumnTypes:    []string{"VARCHAR", "CHAR", "NVARCHAR", "NCHAR"},
+			RowLimit:             cfg.DataProxyRowLimit,
+			UserConnectionString: userConnectionString,
 		}
 
 		queryResultTransformer := mssqlQueryResultTransformer{}
@@ -94,14 +94,6 @@ func newInstanceSettings(cfg *setting.Cfg, httpClientProvider httpclient.Provide
 			}
 		}
 
-		cnnstr := fmt.Sprintf("%s:%s@%s(%s)/%s?collation=utf8mb4_unicode_ci&parseTime=true&loc=UTC&allowNativePasswords=true",
-			characterEscape(dsInfo.User, ":"),
-			dsInfo.DecryptedSecureJSONData["password"],
-			protocol,
-			characterEscape(dsInfo.URL, ")"),
-			characterEscape(dsInfo.Database, "?"),
-		)
-
 		opts, err := settings.HTTPClientOptions()
 		if err != nil {
 			return nil, err
@@ -112,16 +104,36 @@ func newInstanceSettings(cfg *setting.Cfg, httpClientProvider httpclient.Provide
 			return nil, err
 		}
 
+		tlsConfigString := ""
 		if tlsConfig.RootCAs != nil || len(tlsConfig.Certificates) > 0 {
-			tlsConfigString := fmt.Sprintf("ds%d", settings.ID)
+			tlsConfigString = fmt.Sprintf("ds%d", settings.ID)
 			if err := mysql.RegisterTLSConfig(tlsConfigString, tlsConfig); err != nil {
 				return nil, err
 			}
-			cnnstr += "&tls=" + tlsConfigString
 		}
 
-		if dsInfo.JsonData.Timezone != "" {
-			cnnstr += fmt.Sprintf("&time_zone='%s'", url.QueryEscape(dsInfo.JsonData.Timezone))
+		generateConnectionString := func(dsInfo sqleng.DataSourceInfo) (string, error) {
+			cnnstr := fmt.Sprintf("%s:%s@%s(%s)/%s?collation=utf8mb4_unicode_ci&parseTime=true&loc=UTC&allowNativePasswords=true",
+				characterEscape(dsInfo.User, ":"),
+				dsInfo.DecryptedSecureJSONData["password"],
+				protocol,
+				characterEscape(dsInfo.URL, ")"),
+				characterEscape(dsInfo.Database, "?"),
+			)
+
+			if tlsConfigString != "" {
+				cnnstr += "&tls=" + tlsConfigString
+			}
+
+			if dsInfo.JsonData.Timezone != "" {
+				cnnstr += fmt.Sprintf("&time_zone='%s'", url.QueryEscape(dsInfo.JsonData.Timezone))
+			}
+			return cnnstr, nil
+		}
+
+		cnnstr, err := generateConnectionString(dsInfo)
+		if err != nil {
+			return nil, err
 		}
 
 		if cfg.Env == setting.Dev {
@@ -129,12 +141,13 @@ func newInstanceSettings(cfg *setting.Cfg, httpClientProvider httpclient.Provide
 		}
 
 		config := sqleng.DataPluginConfiguration{
-			DriverName:        "mysql",
-			ConnectionString:  cnnstr,
-			DSInfo:            dsInfo,
-			TimeColumnNames:   []string{"time", "time_sec"},
-			MetricColumnTypes: []string{"CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT"},
-			RowLimit:          cfg.DataProxyRowLimit,
+			DriverName:           "mysql",
+			ConnectionString:     cnnstr,
+			DSInfo:               dsInfo,
+			TimeColumnNames:      []string{"time", "time_sec"},
+			MetricColumnTypes:    []string{"CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT"},
+			RowLimit:             cfg.DataProxyRowLimit,
+			UserConnectionString: generateConnectionString,
 		}
 
 		rowTransformer := mysqlQueryResultTransformer{}
@@ -95,20 +95,24 @@ func (s *Service) newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFacto
 		}
 
 		driverName := "postgres"
+		userConnectionString := s.generateConnectionString
 		// register a proxy driver if the secure socks proxy is enabled
 		if cfg.IsFeatureToggleEnabled(featuremgmt.FlagSecureSocksDatasourceProxy) && cfg.SecureSocksDSProxy.Enabled && jsonData.SecureDSProxy {
 			driverName, err = createPostgresProxyDriver(&cfg.SecureSocksDSProxy, cnnstr)
 			if err != nil {
 				return "", nil
 			}
+			// the proxy driver is bound to the connection string of the shared account
+			userConnectionString = nil
 		}
 
 		config := sqleng.DataPluginConfiguration{
-			DriverName:        driverName,
-			ConnectionString:  cnnstr,
-			DSInfo:            dsInfo,
-			MetricColumnTypes: []string{"UNKNOWN", "TEXT", "VARCHAR", "CHAR"},
-			RowLimit:          cfg.DataProxyRowLimit,
+			DriverName:           driverName,
+			ConnectionString:     cnnstr,
+			DSInfo:               dsInfo,
+			MetricColumnTypes:    []string{"UNKNOWN", "TEXT", "VARCHAR", "CHAR"},
+			RowLimit:             cfg.DataProxyRowLimit,
+			UserConnectionString: userConnectionString,
 		}
 
 		queryResultTransformer := postgresQueryResultTransformer{}
@@ -0,0 +1,122 @@
+package querydata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+)
+
+const defaultRangeQueryChunkParallelism = 4
+
+// rangeQueryChunks splits the time range of a query into consecutive chunks of at most chunkDuration. The chunks are
+// aligned to the step so that every point of the original query belongs to exactly one chunk.
+func rangeQueryChunks(q *models.Query, chunkDuration time.Duration) []*models.Query {
+	tr := q.TimeRange()
+	if chunkDuration <= 0 || tr.Step <= 0 || tr.End.Sub(tr.Start) <= chunkDuration {
+		return []*models.Query{q}
+	}
+
+	steps := chunkDuration / tr.Step
+	if steps < 1 {
+		steps = 1
+	}
+	chunkDuration = steps * tr.Step
+
+	var chunks []*models.Query
+	for start := tr.Start; !start.After(tr.End); start = start.Add(chunkDuration) {
+		end := start.Add(chunkDuration - tr.Step)
+		if end.After(tr.End) {
+			end = tr.End
+		}
+		chunk := *q
+		chunk.Start = start
+		chunk.End = end
+		chunks = append(chunks, &chunk)
+	}
+	return chunks
+}
+
+// chunkedRangeQuery runs the chunks of a long range query in parallel and merges the series of the chunks. When some
+// chunks fail, the data of the other chunks is returned with the error.
+func (s *QueryData) chunkedRangeQuery(ctx context.Context, c *client.Client, q *models.Query, chunks []*models.Query, headers map[string]string) backend.DataResponse {
+	s.log.FromContext(ctx).Debug("Splitting range query", "query", q.Expr, "chunks", len(chunks))
+
+	parallelism := s.rangeQueryChunkParallelism
+	if parallelism <= 0 {
+		parallelism = defaultRangeQueryChunkParallelism
+	}
+
+	responses := make([]backend.DataResponse, len(chunks))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk *models.Query) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				responses[i] = backend.DataResponse{Error: ctx.Err()}
+				return
+			}
+			responses[i] = s.rangeQuery(ctx, c, chunk, headers)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	return mergeChunkResponses(q, responses)
+}
+
+// mergeChunkResponses appends the rows of the series of every chunk, in order, to the series of the first chunk
+// they appear in.
+func mergeChunkResponses(q *models.Query, responses []backend.DataResponse) backend.DataResponse {
+	var (
+		frames []*data.Frame
+		errs   []error
+	)
+	byKey := map[string]*data.Frame{}
+	for _, res := range responses {
+		if res.Error != nil {
+			errs = append(errs, res.Error)
+			continue
+		}
+		for _, frame := range res.Frames {
+			// frames without values only carry the metadata of an empty result
+			if len(frame.Fields) < 2 {
+				continue
+			}
+			key := frame.Name + frame.Fields[1].Labels.String()
+			merged, ok := byKey[key]
+			if !ok {
+				byKey[key] = frame
+				frames = append(frames, frame)
+				continue
+			}
+			for i := 0; i < frame.Rows(); i++ {
+				merged.AppendRow(frame.RowCopy(i)...)
+			}
+		}
+	}
+
+	if len(frames) == 0 {
+		frame := data.NewFrame("")
+		addMetadataToMultiFrame(q, frame)
+		frames = append(frames, frame)
+	}
+
+	var err error
+	if len(errs) > 0 {
+		err = fmt.Errorf("%d of %d chunks of the range query failed: %w", len(errs), len(responses), errs[0])
+	}
+	return backend.DataResponse{
+		Frames: frames,
+		Error:  err,
+	}
+}
@@ -0,0 +1,134 @@
+package querydata_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata"
+)
+
+// rangeRoundTripper answers the range queries with one point per step for a single series.
+type rangeRoundTripper struct {
+	mu       sync.Mutex
+	requests [][2]int64
+	failFrom int64
+}
+
+func (rt *rangeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	q := req.URL.Query()
+	start, _ := strconv.ParseInt(q.Get("start"), 10, 64)
+	end, _ := strconv.ParseInt(q.Get("end"), 10, 64)
+	step, _ := strconv.ParseInt(q.Get("step"), 10, 64)
+
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, [2]int64{start, end})
+	rt.mu.Unlock()
+
+	if rt.failFrom != 0 && start >= rt.failFrom {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader(`{"status":"error","errorType":"timeout","error":"query timed out"}`)),
+		}, nil
+	}
+
+	values := make([]string, 0)
+	for ts := start; ts <= end; ts += step {
+		values = append(values, fmt.Sprintf(`[%d, "%d"]`, ts, ts))
+	}
+	body := `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"up","job":"a"},"values":[` +
+		strings.Join(values, ",") + `]}]}}`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil
+}
+
+func TestChunkedRangeQuery(t *testing.T) {
+	from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+
+	run := func(t *testing.T, rt *rangeRoundTripper, jsonData string) backend.DataResponse {
+		t.Helper()
+		settings := backend.DataSourceInstanceSettings{
+			URL:      "http://localhost:9090",
+			JSONData: json.RawMessage(jsonData),
+		}
+		qd, err := querydata.New(&http.Client{Transport: rt}, &fakeFeatureToggles{flags: map[string]bool{}}, tracing.InitializeTracerForTest(), settings, &logtest.Fake{})
+		require.NoError(t, err)
+
+		res, err := qd.Execute(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					RefID:         "A",
+					JSON:          []byte(`{"expr": "up", "range": true, "interval": "1h"}`),
+					TimeRange:     backend.TimeRange{From: from, To: to},
+					Interval:      time.Hour,
+					MaxDataPoints: 10000,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return res.Responses["A"]
+	}
+
+	timestamps := func(t *testing.T, frame *data.Frame) []time.Time {
+		t.Helper()
+		ts := make([]time.Time, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			ts = append(ts, frame.Fields[0].At(i).(time.Time))
+		}
+		return ts
+	}
+
+	t.Run("short ranges are not split", func(t *testing.T) {
+		rt := &rangeRoundTripper{}
+		res := run(t, rt, `{"rangeQueryChunkDuration": "7d"}`)
+		require.NoError(t, res.Error)
+		require.Len(t, rt.requests, 1)
+	})
+
+	t.Run("long ranges are split and the series merged", func(t *testing.T) {
+		unsplitRT := &rangeRoundTripper{}
+		unsplit := run(t, unsplitRT, `{}`)
+		require.NoError(t, unsplit.Error)
+		require.Len(t, unsplitRT.requests, 1)
+
+		rt := &rangeRoundTripper{}
+		res := run(t, rt, `{"rangeQueryChunkDuration": "1d", "rangeQueryChunkParallelism": 2}`)
+		require.NoError(t, res.Error)
+		require.Len(t, rt.requests, 4)
+
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, timestamps(t, unsplit.Frames[0]), timestamps(t, res.Frames[0]))
+		require.Equal(t, unsplit.Frames[0].Fields[1].Labels, res.Frames[0].Fields[1].Labels)
+	})
+
+	t.Run("failed chunks return the data of the other chunks", func(t *testing.T) {
+		rt := &rangeRoundTripper{failFrom: from.Add(48 * time.Hour).Unix()}
+		res := run(t, rt, `{"rangeQueryChunkDuration": "1d"}`)
+		require.Error(t, res.Error)
+		require.Len(t, res.Frames, 1)
+		require.Equal(t, 48, res.Frames[0].Rows())
+	})
+
+	t.Run("invalid chunk duration", func(t *testing.T) {
+		settings := backend.DataSourceInstanceSettings{JSONData: json.RawMessage(`{"rangeQueryChunkDuration": "one day"}`)}
+		_, err := querydata.New(&http.Client{}, &fakeFeatureToggles{flags: map[string]bool{}}, tracing.InitializeTracerForTest(), settings, &logtest.Fake{})
+		require.Error(t, err)
+	})
+}
@@ -14,6 +14,8 @@ type Framer struct {
 	labelTracker LabelTracker
 	meta         *data.FrameMeta
 	refID        string
+
+	traceIDDestinations []TraceIDDestination
 }
 
 func NewFramer(sampler Sampler, labelTracker LabelTracker) *Framer {
@@ -32,6 +34,11 @@ func (f *Framer) SetRefID(refID string) {
 	f.refID = refID
 }
 
+// SetTraceIDDestinations sets the destinations linked from the trace ID labels of the exemplars.
+func (f *Framer) SetTraceIDDestinations(destinations []TraceIDDestination) {
+	f.traceIDDestinations = destinations
+}
+
 func (f *Framer) AddFrame(frame *data.Frame) {
 	f.frames = append(f.frames, frame)
 }
@@ -74,6 +81,8 @@ func (f *Framer) Frames() (data.Frames, error) {
 		}
 	}
 
+	addTraceLinks(exemplarFrame, f.traceIDDestinations)
+
 	f.frames = append(f.frames, exemplarFrame)
 
 	return f.frames, nil
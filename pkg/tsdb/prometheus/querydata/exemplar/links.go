@@ -0,0 +1,130 @@
+package exemplar
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// traceRangePadding widens the time range of the links to the tracing data source around the exemplars, as the
+// spans of a trace may start before and end after the exemplar was recorded.
+const traceRangePadding = 30 * time.Minute
+
+// TraceIDDestination is an entry of the exemplarTraceIdDestinations of the data source, it links the exemplar label
+// with the given name to a tracing data source or to an external URL.
+type TraceIDDestination struct {
+	Name            string `json:"name"`
+	DatasourceUID   string `json:"datasourceUid"`
+	URL             string `json:"url"`
+	URLDisplayLabel string `json:"urlDisplayLabel"`
+}
+
+// ParseTraceIDDestinations reads the exemplarTraceIdDestinations of the data source settings.
+func ParseTraceIDDestinations(jsonData json.RawMessage) ([]TraceIDDestination, error) {
+	if len(jsonData) == 0 {
+		return nil, nil
+	}
+	var settings struct {
+		Destinations []TraceIDDestination `json:"exemplarTraceIdDestinations"`
+	}
+	if err := json.Unmarshal(jsonData, &settings); err != nil {
+		return nil, fmt.Errorf("invalid exemplar trace ID destinations: %w", err)
+	}
+	return settings.Destinations, nil
+}
+
+// addTraceLinks adds the links of the destinations to the trace ID fields of the exemplar frame. Links to a tracing
+// data source open the trace in Explore with a time range around the exemplars of the frame.
+func addTraceLinks(frame *data.Frame, destinations []TraceIDDestination) {
+	if len(destinations) == 0 || len(frame.Fields) == 0 {
+		return
+	}
+	from, to, ok := timeRange(frame.Fields[0])
+	if !ok {
+		return
+	}
+	from, to = from.Add(-traceRangePadding), to.Add(traceRangePadding)
+
+	for _, d := range destinations {
+		field, _ := frame.FieldByName(d.Name)
+		if field == nil {
+			continue
+		}
+		if field.Config == nil {
+			field.Config = &data.FieldConfig{}
+		}
+		if d.DatasourceUID != "" {
+			title := d.URLDisplayLabel
+			if title == "" {
+				title = "View trace"
+			}
+			field.Config.Links = append(field.Config.Links, data.DataLink{
+				Title: title,
+				URL:   exploreURL(d.DatasourceUID, from, to),
+			})
+		}
+		if d.URL != "" {
+			title := d.URLDisplayLabel
+			if title == "" {
+				title = "Go to " + d.URL
+			}
+			field.Config.Links = append(field.Config.Links, data.DataLink{
+				Title:       title,
+				URL:         d.URL,
+				TargetBlank: true,
+			})
+		}
+	}
+}
+
+type exploreQuery struct {
+	RefID      string            `json:"refId"`
+	Datasource map[string]string `json:"datasource"`
+	Query      string            `json:"query"`
+	QueryType  string            `json:"queryType"`
+}
+
+type exploreState struct {
+	Datasource string            `json:"datasource"`
+	Queries    []exploreQuery    `json:"queries"`
+	Range      map[string]string `json:"range"`
+}
+
+// exploreURL returns the URL of Explore querying the trace of the exemplar in the data source. The trace ID is
+// interpolated by the frontend from the value of the field.
+func exploreURL(datasourceUID string, from, to time.Time) string {
+	left, _ := json.Marshal(exploreState{
+		Datasource: datasourceUID,
+		Queries: []exploreQuery{{
+			RefID:      "A",
+			Datasource: map[string]string{"uid": datasourceUID},
+			Query:      "${__value.raw}",
+			QueryType:  "traceql",
+		}},
+		Range: map[string]string{
+			"from": strconv.FormatInt(from.UnixMilli(), 10),
+			"to":   strconv.FormatInt(to.UnixMilli(), 10),
+		},
+	})
+	return "/explore?left=" + string(left)
+}
+
+func timeRange(field *data.Field) (from, to time.Time, ok bool) {
+	for i := 0; i < field.Len(); i++ {
+		t, isTime := field.At(i).(time.Time)
+		if !isTime {
+			return time.Time{}, time.Time{}, false
+		}
+		if !ok || t.Before(from) {
+			from = t
+		}
+		if !ok || t.After(to) {
+			to = t
+		}
+		ok = true
+	}
+	return from, to, ok
+}
@@ -0,0 +1,50 @@
+package exemplar_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata/exemplar"
+)
+
+func TestTraceLinks(t *testing.T) {
+	destinations, err := exemplar.ParseTraceIDDestinations(json.RawMessage(`{
+		"exemplarTraceIdDestinations": [
+			{"name": "traceID", "datasourceUid": "tempo"},
+			{"name": "traceID", "url": "http://jaeger/trace/${__value.raw}", "urlDisplayLabel": "Jaeger"},
+			{"name": "missing", "datasourceUid": "tempo"}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, destinations, 3)
+
+	sampler := exemplar.NewNoOpSampler()
+	labelTracker := exemplar.NewLabelTracker()
+	for _, ts := range []int64{3600, 7200} {
+		labels := map[string]string{"traceID": "abc"}
+		labelTracker.Add(labels)
+		sampler.Add(models.Exemplar{Timestamp: time.Unix(ts, 0), Value: 1, Labels: labels})
+	}
+	framer := exemplar.NewFramer(sampler, labelTracker)
+	framer.SetTraceIDDestinations(destinations)
+
+	frames, err := framer.Frames()
+	require.NoError(t, err)
+	require.Len(t, frames, 1)
+
+	field, _ := frames[0].FieldByName("traceID")
+	require.NotNil(t, field)
+	links := field.Config.Links
+	require.Len(t, links, 2)
+
+	require.Equal(t, "View trace", links[0].Title)
+	require.Equal(t, `/explore?left={"datasource":"tempo","queries":[{"refId":"A","datasource":{"uid":"tempo"},"query":"${__value.raw}","queryType":"traceql"}],"range":{"from":"1800000","to":"9000000"}}`, links[0].URL)
+
+	require.Equal(t, "Jaeger", links[1].Title)
+	require.Equal(t, "http://jaeger/trace/${__value.raw}", links[1].URL)
+	require.True(t, links[1].TargetBlank)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 
@@ -44,6 +45,13 @@ type QueryData struct {
 	TimeInterval       string
 	enableWideSeries   bool
 	exemplarSampler    func() exemplar.Sampler
+
+	// traceIDDestinations are linked from the trace IDs of the exemplars
+	traceIDDestinations []exemplar.TraceIDDestination
+
+	// rangeQueryChunkDuration splits the range queries over a longer time range in chunks, 0 disables it
+	rangeQueryChunkDuration    time.Duration
+	rangeQueryChunkParallelism int
 }
 
 func New(
@@ -64,6 +72,27 @@ func New(
 		return nil, err
 	}
 
+	chunkDuration, err := maputil.GetStringOptional(jsonData, "rangeQueryChunkDuration")
+	if err != nil {
+		return nil, err
+	}
+	var rangeQueryChunkDuration time.Duration
+	if chunkDuration != "" {
+		rangeQueryChunkDuration, err = gtime.ParseDuration(chunkDuration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range query chunk duration: %w", err)
+		}
+	}
+	var rangeQueryChunkParallelism int
+	if parallelism, ok := jsonData["rangeQueryChunkParallelism"].(float64); ok {
+		rangeQueryChunkParallelism = int(parallelism)
+	}
+
+	traceIDDestinations, err := exemplar.ParseTraceIDDestinations(settings.JSONData)
+	if err != nil {
+		return nil, err
+	}
+
 	promClient := client.NewClient(httpClient, httpMethod, settings.URL)
 
 	// standard deviation sampler is the default for backwards compatibility
@@ -83,6 +112,11 @@ func New(
 		URL:                settings.URL,
 		enableWideSeries:   features.IsEnabled(featuremgmt.FlagPrometheusWideSeries),
 		exemplarSampler:    exemplarSampler,
+
+		traceIDDestinations: traceIDDestinations,
+
+		rangeQueryChunkDuration:    rangeQueryChunkDuration,
+		rangeQueryChunkParallelism: rangeQueryChunkParallelism,
 	}, nil
 }
 
@@ -127,7 +161,13 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 	}
 
 	if q.RangeQuery {
-		res := s.rangeQuery(traceCtx, client, q, headers)
+		var res backend.DataResponse
+		// the series of wide frames cannot be merged
+		if chunks := rangeQueryChunks(q, s.rangeQueryChunkDuration); len(chunks) > 1 && !s.enableWideSeries {
+			res = s.chunkedRangeQuery(traceCtx, client, q, chunks, headers)
+		} else {
+			res = s.rangeQuery(traceCtx, client, q, headers)
+		}
 		if res.Error != nil {
 			if dr.Error == nil {
 				dr.Error = res.Error
@@ -472,6 +472,10 @@ func (f *fakeFeatureToggles) IsEnabled(feature string) bool {
 	return f.flags[feature]
 }
 
+func (f *fakeFeatureToggles) IsEnabledCtx(_ context.Context, feature string) bool {
+	return f.flags[feature]
+}
+
 type fakeHttpClientProvider struct {
 	httpclient.Provider
 	opts sdkhttpclient.Options
@@ -60,6 +60,7 @@ func (s *QueryData) processExemplars(q *models.Query, dr backend.DataResponse) b
 	// so we need to build a new frame array with the
 	// old exemplar frames filtered out
 	framer := exemplar.NewFramer(sampler, labelTracker)
+	framer.SetTraceIDDestinations(s.traceIDDestinations)
 
 	for _, frame := range dr.Frames {
 		// we don't need to process non-exemplar frames
@@ -0,0 +1,48 @@
+package sqleng
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// userCredentialsKey is the secure JSON data key holding the per-user credentials of a data source.
+const userCredentialsKey = "userCredentials"
+
+var ErrNoUserCredentials = errors.New("no database credentials configured for the user")
+
+// UserCredentials are the database credentials used for the queries of a Grafana user.
+type UserCredentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+// CredentialsProvider resolves the database credentials of a Grafana user when the data source is configured
+// with per-user credentials.
+type CredentialsProvider interface {
+	// UserCredentials returns ErrNoUserCredentials when the user has no credentials.
+	UserCredentials(ctx context.Context, dsInfo DataSourceInfo, user *backend.User) (UserCredentials, error)
+}
+
+// SecureJSONCredentialsProvider reads the credentials from the secure JSON data of the data source, where
+// userCredentials is a JSON object of the credentials by user login.
+type SecureJSONCredentialsProvider struct{}
+
+func (SecureJSONCredentialsProvider) UserCredentials(_ context.Context, dsInfo DataSourceInfo, user *backend.User) (UserCredentials, error) {
+	raw, ok := dsInfo.DecryptedSecureJSONData[userCredentialsKey]
+	if !ok || user == nil {
+		return UserCredentials{}, ErrNoUserCredentials
+	}
+	var byLogin map[string]UserCredentials
+	if err := json.Unmarshal([]byte(raw), &byLogin); err != nil {
+		return UserCredentials{}, fmt.Errorf("failed to read the per-user credentials: %w", err)
+	}
+	creds, ok := byLogin[user.Login]
+	if !ok || creds.User == "" {
+		return UserCredentials{}, ErrNoUserCredentials
+	}
+	return creds, nil
+}
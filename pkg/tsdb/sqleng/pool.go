@@ -0,0 +1,195 @@
+package sqleng
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const defaultPoolIdleTimeout = 10 * time.Minute
+
+var (
+	userPoolsGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Name:      "sql_datasource_user_pools",
+			Help:      "Number of open connection pools of SQL data sources with per-user credentials",
+		}, []string{"driver"},
+	)
+
+	userPoolEvictionsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "grafana",
+			Name:      "sql_datasource_user_pool_evictions_total",
+			Help:      "Number of connection pools of SQL data sources with per-user credentials closed after being idle",
+		}, []string{"driver"},
+	)
+
+	userPoolConnectionsGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "grafana",
+			Name:      "sql_datasource_user_pool_connections",
+			Help:      "Number of connections of the SQL data source pools with per-user credentials",
+		}, []string{"driver", "state"},
+	)
+)
+
+// Pools is the connection pool manager shared by the SQL data sources.
+var Pools = NewPoolManager(defaultPoolIdleTimeout)
+
+// poolKey identifies a pool by the data source ID, the UIDs are only unique within an organization.
+type poolKey struct {
+	dataSourceID int64
+	updated      time.Time
+	login        string
+}
+
+type userPool struct {
+	driver   string
+	engine   *xorm.Engine
+	lastUsed time.Time
+	// inUse is the number of queries running on the pool, closed is set when the pool is removed while in use and
+	// it is closed by the last of them.
+	inUse  int
+	closed bool
+}
+
+// PoolManager holds the connection pools opened with the credentials of a user. Each data source, version and user
+// gets its own pool, which is closed once it has not been used for the idle timeout.
+type PoolManager struct {
+	mu          sync.Mutex
+	pools       map[poolKey]*userPool
+	idleTimeout time.Duration
+	now         func() time.Time
+	log         log.Logger
+	startOnce   sync.Once
+}
+
+func NewPoolManager(idleTimeout time.Duration) *PoolManager {
+	return &PoolManager{
+		pools:       map[poolKey]*userPool{},
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		log:         log.New("tsdb.sql.pools"),
+	}
+}
+
+// Get returns the pool of the key, open is called to create it when there is none. The pool is not closed until
+// release is called, which must be done once the queries using it are done.
+func (m *PoolManager) Get(key poolKey, driver string, open func() (*xorm.Engine, error)) (engine *xorm.Engine, release func(), err error) {
+	m.startOnce.Do(func() {
+		go m.run()
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool, ok := m.pools[key]
+	if !ok {
+		engine, err := open()
+		if err != nil {
+			return nil, nil, err
+		}
+		pool = &userPool{driver: driver, engine: engine}
+		m.pools[key] = pool
+		userPoolsGauge.WithLabelValues(driver).Inc()
+	}
+	pool.inUse++
+	pool.lastUsed = m.now()
+
+	var once sync.Once
+	return pool.engine, func() { once.Do(func() { m.release(key, pool) }) }, nil
+}
+
+func (m *PoolManager) release(key poolKey, pool *userPool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pool.inUse--
+	pool.lastUsed = m.now()
+	if pool.closed && pool.inUse == 0 {
+		m.closeEngine(key, pool)
+	}
+}
+
+// CloseDataSource closes the pools of a version of a data source.
+func (m *PoolManager) CloseDataSource(id int64, updated time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, pool := range m.pools {
+		if key.dataSourceID == id && key.updated.Equal(updated) {
+			m.close(key, pool)
+		}
+	}
+}
+
+// EvictIdle closes the pools that were not used for the idle timeout and returns how many were closed. The pools
+// running queries are never idle.
+func (m *PoolManager) EvictIdle() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	evicted := 0
+	for key, pool := range m.pools {
+		if pool.inUse > 0 || m.now().Sub(pool.lastUsed) < m.idleTimeout {
+			continue
+		}
+		m.close(key, pool)
+		userPoolEvictionsCounter.WithLabelValues(pool.driver).Inc()
+		evicted++
+	}
+	return evicted
+}
+
+// close removes the pool, its engine is closed once the queries running on it are done.
+func (m *PoolManager) close(key poolKey, pool *userPool) {
+	delete(m.pools, key)
+	userPoolsGauge.WithLabelValues(pool.driver).Dec()
+	pool.closed = true
+	if pool.inUse == 0 {
+		m.closeEngine(key, pool)
+	}
+}
+
+func (m *PoolManager) closeEngine(key poolKey, pool *userPool) {
+	if err := pool.engine.Close(); err != nil {
+		m.log.Error("Failed to close connection pool", "datasourceId", key.dataSourceID, "error", err)
+	}
+}
+
+func (m *PoolManager) updateMetrics() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inUse := map[string]int{}
+	idle := map[string]int{}
+	for _, pool := range m.pools {
+		stats := pool.engine.DB().Stats()
+		inUse[pool.driver] += stats.InUse
+		idle[pool.driver] += stats.Idle
+	}
+	userPoolConnectionsGauge.Reset()
+	for driver, n := range inUse {
+		userPoolConnectionsGauge.WithLabelValues(driver, "in_use").Set(float64(n))
+	}
+	for driver, n := range idle {
+		userPoolConnectionsGauge.WithLabelValues(driver, "idle").Set(float64(n))
+	}
+}
+
+func (m *PoolManager) run() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		if evicted := m.EvictIdle(); evicted > 0 {
+			m.log.Debug("Closed idle connection pools", "count", evicted)
+		}
+		m.updateMetrics()
+	}
+}
@@ -0,0 +1,182 @@
+package sqleng
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+	"xorm.io/xorm"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func newTestPoolManager(now *time.Time) *PoolManager {
+	m := NewPoolManager(time.Minute)
+	m.now = func() time.Time { return *now }
+	// the tests evict the pools themselves
+	m.startOnce.Do(func() {})
+	return m
+}
+
+func openTestEngine() (*xorm.Engine, error) {
+	return xorm.NewEngine("sqlite3", ":memory:")
+}
+
+func TestPoolManager(t *testing.T) {
+	updated := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("reuses the pool of a user and evicts it when idle", func(t *testing.T) {
+		now := updated
+		m := newTestPoolManager(&now)
+		opened := 0
+		open := func() (*xorm.Engine, error) {
+			opened++
+			return openTestEngine()
+		}
+
+		key := poolKey{dataSourceID: 1, updated: updated, login: "alice"}
+		first, release, err := m.Get(key, "sqlite3", open)
+		require.NoError(t, err)
+		release()
+		second, release, err := m.Get(key, "sqlite3", open)
+		require.NoError(t, err)
+		release()
+		require.Same(t, first, second)
+		require.Equal(t, 1, opened)
+
+		_, release, err = m.Get(poolKey{dataSourceID: 1, updated: updated, login: "bob"}, "sqlite3", open)
+		require.NoError(t, err)
+		release()
+		require.Equal(t, 2, opened)
+
+		// the data sources of different organizations can have the same UID, their pools are not shared
+		_, release, err = m.Get(poolKey{dataSourceID: 2, updated: updated, login: "alice"}, "sqlite3", open)
+		require.NoError(t, err)
+		release()
+		require.Equal(t, 3, opened)
+
+		now = now.Add(30 * time.Second)
+		_, release, err = m.Get(key, "sqlite3", open)
+		require.NoError(t, err)
+		release()
+
+		now = now.Add(45 * time.Second)
+		require.Equal(t, 2, m.EvictIdle())
+		require.Len(t, m.pools, 1)
+		require.Contains(t, m.pools, key)
+	})
+
+	t.Run("does not close the pools running queries", func(t *testing.T) {
+		now := updated
+		m := newTestPoolManager(&now)
+
+		key := poolKey{dataSourceID: 1, updated: updated, login: "alice"}
+		engine, release, err := m.Get(key, "sqlite3", openTestEngine)
+		require.NoError(t, err)
+
+		// a query running for longer than the idle timeout
+		now = now.Add(2 * time.Minute)
+		require.Equal(t, 0, m.EvictIdle())
+		release()
+		require.Equal(t, 0, m.EvictIdle())
+		now = now.Add(2 * time.Minute)
+		require.Equal(t, 1, m.EvictIdle())
+		require.Error(t, engine.Ping())
+
+		// the pools of a removed data source are closed once their queries are done
+		engine, release, err = m.Get(key, "sqlite3", openTestEngine)
+		require.NoError(t, err)
+		m.CloseDataSource(1, updated)
+		require.Empty(t, m.pools)
+		require.NoError(t, engine.Ping())
+		release()
+		require.Error(t, engine.Ping())
+	})
+
+	t.Run("closes the pools of a data source version", func(t *testing.T) {
+		now := updated
+		m := newTestPoolManager(&now)
+
+		for _, key := range []poolKey{
+			{dataSourceID: 1, updated: updated, login: "alice"},
+			{dataSourceID: 1, updated: updated.Add(time.Hour), login: "alice"},
+			{dataSourceID: 2, updated: updated, login: "alice"},
+		} {
+			_, release, err := m.Get(key, "sqlite3", openTestEngine)
+			require.NoError(t, err)
+			release()
+		}
+
+		m.CloseDataSource(1, updated)
+		require.Len(t, m.pools, 2)
+		require.NotContains(t, m.pools, poolKey{dataSourceID: 1, updated: updated, login: "alice"})
+	})
+}
+
+func TestPerUserCredentials(t *testing.T) {
+	now := time.Now()
+	dsInfo := DataSourceInfo{
+		ID:      1,
+		UID:     "ds",
+		User:    "grafana",
+		Updated: now,
+		JsonData: JsonData{
+			PerUserCredentials: true,
+		},
+		DecryptedSecureJSONData: map[string]string{
+			"password":        "shared",
+			"userCredentials": `{"alice": {"user": "alice_ro", "password": "secret"}}`,
+		},
+	}
+
+	t.Run("reads the credentials of the user from the secure json data", func(t *testing.T) {
+		creds, err := SecureJSONCredentialsProvider{}.UserCredentials(context.Background(), dsInfo, &backend.User{Login: "alice"})
+		require.NoError(t, err)
+		require.Equal(t, UserCredentials{User: "alice_ro", Password: "secret"}, creds)
+
+		_, err = SecureJSONCredentialsProvider{}.UserCredentials(context.Background(), dsInfo, &backend.User{Login: "bob"})
+		require.ErrorIs(t, err, ErrNoUserCredentials)
+	})
+
+	t.Run("requires a user connection string", func(t *testing.T) {
+		_, err := NewQueryDataHandler(DataPluginConfiguration{DriverName: "sqlite3", ConnectionString: ":memory:", DSInfo: dsInfo}, nil, nil, log.NewNopLogger())
+		require.Error(t, err)
+	})
+
+	t.Run("opens a pool with the credentials of the user", func(t *testing.T) {
+		var connectionStrings []string
+		handler, err := NewQueryDataHandler(DataPluginConfiguration{
+			DriverName:       "sqlite3",
+			ConnectionString: ":memory:",
+			DSInfo:           dsInfo,
+			UserConnectionString: func(dsInfo DataSourceInfo) (string, error) {
+				connectionStrings = append(connectionStrings, dsInfo.User+":"+dsInfo.DecryptedSecureJSONData["password"])
+				return ":memory:", nil
+			},
+		}, nil, nil, log.NewNopLogger())
+		require.NoError(t, err)
+		handler.pools = newTestPoolManager(&now)
+
+		db, release, err := handler.userDB(context.Background(), &backend.User{Login: "alice"})
+		require.NoError(t, err)
+		release()
+		require.NotSame(t, handler.session.DB(), db)
+		require.Equal(t, []string{"alice_ro:secret"}, connectionStrings)
+		// the credentials of the data source are left untouched
+		require.Equal(t, "shared", dsInfo.DecryptedSecureJSONData["password"])
+
+		_, _, err = handler.userDB(context.Background(), &backend.User{Login: "bob"})
+		require.ErrorIs(t, err, ErrNoUserCredentials)
+
+		db, release, err = handler.userDB(context.Background(), nil)
+		require.NoError(t, err)
+		release()
+		require.Same(t, handler.session.DB(), db)
+
+		handler.Dispose()
+		require.Empty(t, handler.pools.pools)
+	})
+}
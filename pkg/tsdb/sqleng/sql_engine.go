@@ -71,6 +71,7 @@ type JsonData struct {
 	TimeInterval        string `json:"timeInterval"`
 	Database            string `json:"database"`
 	SecureDSProxy       bool   `json:"enableSecureSocksProxy"`
+	PerUserCredentials  bool   `json:"perUserCredentials"`
 }
 
 type DataSourceInfo struct {
@@ -91,6 +92,11 @@ type DataPluginConfiguration struct {
 	TimeColumnNames   []string
 	MetricColumnTypes []string
 	RowLimit          int64
+	// UserConnectionString builds the connection string for the credentials of a user, it is required when
+	// the data source uses per-user credentials.
+	UserConnectionString func(dsInfo DataSourceInfo) (string, error)
+	// Credentials resolves the per-user credentials, SecureJSONCredentialsProvider is used when nil.
+	Credentials CredentialsProvider
 }
 type DataSourceHandler struct {
 	macroEngine            SQLMacroEngine
@@ -102,6 +108,10 @@ type DataSourceHandler struct {
 	dsInfo                 DataSourceInfo
 	rowLimit               int64
 	session                *xorm.Session
+	driverName             string
+	userConnectionString   func(dsInfo DataSourceInfo) (string, error)
+	credentials            CredentialsProvider
+	pools                  *PoolManager
 }
 
 type QueryJson struct {
@@ -141,6 +151,18 @@ func NewQueryDataHandler(config DataPluginConfiguration, queryResultTransformer
 		log:                    log,
 		dsInfo:                 config.DSInfo,
 		rowLimit:               config.RowLimit,
+		driverName:             config.DriverName,
+		userConnectionString:   config.UserConnectionString,
+		credentials:            config.Credentials,
+		pools:                  Pools,
+	}
+
+	if config.DSInfo.JsonData.PerUserCredentials && config.UserConnectionString == nil {
+		return nil, errors.New("per-user credentials are not supported with this data source configuration")
+	}
+
+	if queryDataHandler.credentials == nil {
+		queryDataHandler.credentials = SecureJSONCredentialsProvider{}
 	}
 
 	if len(config.TimeColumnNames) > 0 {
@@ -182,6 +204,9 @@ func (e *DataSourceHandler) Dispose() {
 			e.log.Error("Failed to dispose engine", "error", err)
 		}
 	}
+	if e.dsInfo.JsonData.PerUserCredentials {
+		e.pools.CloseDataSource(e.dsInfo.ID, e.dsInfo.Updated)
+	}
 	e.log.Debug("Engine disposed")
 }
 
@@ -189,7 +214,55 @@ func (e *DataSourceHandler) Ping() error {
 	return e.engine.Ping()
 }
 
+// userDB returns the connection pool for the user of the request. The shared pool is used when the data source does
+// not use per-user credentials or when the request is not made on behalf of a user, for example by alerting. release
+// must be called once the queries are done.
+func (e *DataSourceHandler) userDB(ctx context.Context, user *backend.User) (db *core.DB, release func(), err error) {
+	if !e.dsInfo.JsonData.PerUserCredentials || user == nil {
+		return e.session.DB(), func() {}, nil
+	}
+
+	creds, err := e.credentials.UserCredentials(ctx, e.dsInfo, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := poolKey{dataSourceID: e.dsInfo.ID, updated: e.dsInfo.Updated, login: user.Login}
+	engine, release, err := e.pools.Get(key, e.driverName, func() (*xorm.Engine, error) {
+		dsInfo := e.dsInfo
+		dsInfo.User = creds.User
+		dsInfo.DecryptedSecureJSONData = map[string]string{}
+		for k, v := range e.dsInfo.DecryptedSecureJSONData {
+			dsInfo.DecryptedSecureJSONData[k] = v
+		}
+		dsInfo.DecryptedSecureJSONData["password"] = creds.Password
+
+		cnnstr, err := e.userConnectionString(dsInfo)
+		if err != nil {
+			return nil, err
+		}
+		engine, err := NewXormEngine(e.driverName, cnnstr)
+		if err != nil {
+			return nil, err
+		}
+		engine.SetMaxOpenConns(e.dsInfo.JsonData.MaxOpenConns)
+		engine.SetMaxIdleConns(e.dsInfo.JsonData.MaxIdleConns)
+		engine.SetConnMaxLifetime(time.Duration(e.dsInfo.JsonData.ConnMaxLifetime) * time.Second)
+		return engine, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return engine.DB(), release, nil
+}
+
 func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	db, release, err := e.userDB(ctx, req.PluginContext.User)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	result := backend.NewQueryDataResponse()
 	ch := make(chan DBDataResponse, len(req.Queries))
 	var wg sync.WaitGroup
@@ -208,7 +281,7 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 		}
 
 		wg.Add(1)
-		go e.executeQuery(query, &wg, ctx, ch, queryjson)
+		go e.executeQuery(query, &wg, ctx, ch, queryjson, db)
 	}
 
 	wg.Wait()
@@ -224,7 +297,7 @@ func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDat
 }
 
 func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitGroup, queryContext context.Context,
-	ch chan DBDataResponse, queryJson QueryJson) {
+	ch chan DBDataResponse, queryJson QueryJson, db *core.DB) {
 	defer wg.Done()
 	queryResult := DBDataResponse{
 		dataResponse: backend.DataResponse{},
@@ -277,8 +350,6 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 		return
 	}
 
-	db := e.session.DB()
-
 	rows, err := db.QueryContext(queryContext, interpolatedQuery)
 	if err != nil {
 		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery)
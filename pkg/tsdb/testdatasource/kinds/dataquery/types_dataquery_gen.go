@@ -60,6 +60,7 @@ const (
 	ScenarioIdRandomWalkTable              ScenarioId = "random_walk_table"
 	ScenarioIdRandomWalkWithError          ScenarioId = "random_walk_with_error"
 	ScenarioIdRawFrame                     ScenarioId = "raw_frame"
+	ScenarioIdRecorded                     ScenarioId = "recorded"
 	ScenarioIdServerError500               ScenarioId = "server_error_500"
 	ScenarioIdSimulation                   ScenarioId = "simulation"
 	ScenarioIdSlowQuery                    ScenarioId = "slow_query"
@@ -100,6 +101,7 @@ const (
 	TestDataQueryTypeRandomWalkTable              TestDataQueryType = "random_walk_table"
 	TestDataQueryTypeRandomWalkWithError          TestDataQueryType = "random_walk_with_error"
 	TestDataQueryTypeRawFrame                     TestDataQueryType = "raw_frame"
+	TestDataQueryTypeRecorded                     TestDataQueryType = "recorded"
 	TestDataQueryTypeServerError500               TestDataQueryType = "server_error_500"
 	TestDataQueryTypeSimulation                   TestDataQueryType = "simulation"
 	TestDataQueryTypeSlowQuery                    TestDataQueryType = "slow_query"
@@ -0,0 +1,209 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// maxRecordingSize is the maximum size of a recorded response.
+const maxRecordingSize = 10 * 1024 * 1024
+
+var (
+	recordingNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-.]+$`)
+
+	errRecordingNotFound = errors.New("recording not found")
+)
+
+// recording is a query response recorded from a data source that can be replayed by the testdata data source.
+type recording struct {
+	Frames []*data.Frame `json:"frames"`
+}
+
+// recordingsDir returns the directory of the recordings. Recordings are stored as JSON files, which can also be
+// provisioned in the directory for e2e tests.
+func (s *Service) recordingsDir() string {
+	return filepath.Join(s.cfg.DataPath, "testdata-recordings")
+}
+
+func (s *Service) recordingPath(name string) (string, error) {
+	if !recordingNameRegexp.MatchString(name) || strings.Trim(name, ".") == "" {
+		return "", fmt.Errorf("invalid recording name %q", name)
+	}
+	return filepath.Join(s.recordingsDir(), name+".json"), nil
+}
+
+func (s *Service) listRecordings() ([]string, error) {
+	entries, err := os.ReadDir(s.recordingsDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *Service) loadRecording(name string) (*recording, error) {
+	path, err := s.recordingPath(name)
+	if err != nil {
+		return nil, err
+	}
+	// nolint:gosec
+	// We can ignore the gosec G304 warning since the name of the recording is validated
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errRecordingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec recording
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("failed to read recording %q: %w", name, err)
+	}
+	return &rec, nil
+}
+
+func (s *Service) saveRecording(name string, rec *recording) error {
+	path, err := s.recordingPath(name)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.recordingsDir(), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+func (s *Service) deleteRecording(name string) error {
+	path, err := s.recordingPath(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return errRecordingNotFound
+	}
+	return err
+}
+
+// handleRecordedScenario replays the frames of the recording named by the string input of the query.
+func (s *Service) handleRecordedScenario(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		model, err := simplejson.NewJson(q.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query json: %v", err)
+		}
+
+		name := model.Get("stringInput").MustString()
+		rec, err := s.loadRecording(name)
+		if err != nil {
+			resp.Responses[q.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
+
+		respD := resp.Responses[q.RefID]
+		for _, frame := range rec.Frames {
+			frame.RefID = q.RefID
+			respD.Frames = append(respD.Frames, frame)
+		}
+		resp.Responses[q.RefID] = respD
+	}
+
+	return resp, nil
+}
+
+// canEditRecordings returns whether the user can record or delete responses, which are stored on the disk of
+// the server.
+func canEditRecordings(user *backend.User) bool {
+	return user != nil && (user.Role == "Editor" || user.Role == "Admin")
+}
+
+// recordingsHandler lists the recordings on GET /recordings, and records, returns or deletes a recording on
+// POST, GET and DELETE /recordings/{name}.
+func (s *Service) recordingsHandler(rw http.ResponseWriter, req *http.Request) {
+	name := strings.Trim(strings.TrimPrefix(req.URL.Path, "/recordings"), "/")
+
+	if req.Method != http.MethodGet && !canEditRecordings(httpadapter.UserFromContext(req.Context())) {
+		http.Error(rw, "only editors can record responses", http.StatusForbidden)
+		return
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+	switch {
+	case name == "" && req.Method == http.MethodGet:
+		result, err = s.listRecordings()
+	case name != "" && req.Method == http.MethodGet:
+		result, err = s.loadRecording(name)
+	case name != "" && req.Method == http.MethodPost:
+		var rec recording
+		if err = json.NewDecoder(io.LimitReader(req.Body, maxRecordingSize)).Decode(&rec); err != nil {
+			http.Error(rw, fmt.Sprintf("invalid recording: %v", err), http.StatusBadRequest)
+			return
+		}
+		err = s.saveRecording(name, &rec)
+		result = map[string]string{"message": "Recording saved"}
+	case name != "" && req.Method == http.MethodDelete:
+		err = s.deleteRecording(name)
+		result = map[string]string{"message": "Recording deleted"}
+	default:
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if errors.Is(err, errRecordingNotFound) {
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to handle recording request", "name", name, "method", req.Method, "error", err)
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		s.logger.Error("Failed to marshal response body to JSON", "error", err)
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(bytes); err != nil {
+		s.logger.Error("Failed to write response", "error", err)
+	}
+}
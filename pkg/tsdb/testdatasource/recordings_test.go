@@ -0,0 +1,107 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type resourceSender func(*backend.CallResourceResponse)
+
+func (s resourceSender) Send(res *backend.CallResourceResponse) error {
+	s(res)
+	return nil
+}
+
+func TestRecordedScenario(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.DataPath = t.TempDir()
+
+	s := &Service{
+		cfg:    cfg,
+		logger: log.New("tsdb.testdata"),
+	}
+	s.resourceHandler = httpadapter.New(s.registerRoutes())
+
+	doAs := func(role, method, path, body string) *backend.CallResourceResponse {
+		t.Helper()
+		var res *backend.CallResourceResponse
+		err := s.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{User: &backend.User{Login: "user", Role: role}},
+			Method:        method,
+			Path:          strings.TrimPrefix(path, "/"),
+			URL:           path,
+			Body:          []byte(body),
+		}, resourceSender(func(r *backend.CallResourceResponse) { res = r }))
+		require.NoError(t, err)
+		return res
+	}
+	do := func(method, path, body string) *backend.CallResourceResponse {
+		t.Helper()
+		return doAs("Editor", method, path, body)
+	}
+
+	frame := data.NewFrame("cpu",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC()}),
+		data.NewField("value", data.Labels{"host": "a"}, []float64{1, 2}),
+	)
+	body, err := json.Marshal(recording{Frames: []*data.Frame{frame}})
+	require.NoError(t, err)
+
+	t.Run("records a response", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodPost, "/recordings/demo", string(body)).Status)
+
+		res := do(http.MethodGet, "/recordings", "")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `["demo"]`, string(res.Body))
+	})
+
+	t.Run("only editors can record responses", func(t *testing.T) {
+		require.Equal(t, http.StatusForbidden, doAs("Viewer", http.MethodPost, "/recordings/demo", string(body)).Status)
+		require.Equal(t, http.StatusForbidden, doAs("Viewer", http.MethodDelete, "/recordings/demo", "").Status)
+		require.Equal(t, http.StatusOK, doAs("Viewer", http.MethodGet, "/recordings/demo", "").Status)
+	})
+
+	t.Run("rejects invalid recordings", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/recordings/demo", "not json").Status)
+		require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/recordings/not%20valid", string(body)).Status)
+		require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/recordings/unknown", "").Status)
+	})
+
+	t.Run("replays a recorded response", func(t *testing.T) {
+		resp, err := s.handleRecordedScenario(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{RefID: "A", JSON: []byte(`{"scenarioId": "recorded", "stringInput": "demo"}`)},
+				{RefID: "B", JSON: []byte(`{"scenarioId": "recorded", "stringInput": "unknown"}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, resp.Responses["A"].Error)
+		require.Len(t, resp.Responses["A"].Frames, 1)
+		replayed := resp.Responses["A"].Frames[0]
+		require.Equal(t, "A", replayed.RefID)
+		require.Equal(t, "cpu", replayed.Name)
+		require.Equal(t, data.Labels{"host": "a"}, replayed.Fields[1].Labels)
+		require.Equal(t, 2.0, replayed.Fields[1].At(1))
+
+		require.ErrorIs(t, resp.Responses["B"].Error, errRecordingNotFound)
+	})
+
+	t.Run("deletes a recording", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, do(http.MethodDelete, "/recordings/demo", "").Status)
+		require.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/recordings/demo", "").Status)
+		require.JSONEq(t, `[]`, string(do(http.MethodGet, "/recordings", "").Body))
+	})
+}
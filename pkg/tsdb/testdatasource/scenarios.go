@@ -45,6 +45,7 @@ const (
 	csvFileQueryType                  queryType = "csv_file"
 	csvContentQueryType               queryType = "csv_content"
 	traceType                         queryType = "trace"
+	recordedQueryType                 queryType = "recorded"
 )
 
 type queryType string
@@ -225,6 +226,14 @@ Timestamps will line up evenly on timeStepSeconds (For example, 60 seconds means
 		Name: "Trace",
 	})
 
+	s.registerScenario(&Scenario{
+		ID:          string(recordedQueryType),
+		Name:        "Recorded response",
+		handler:     s.handleRecordedScenario,
+		StringInput: "recording",
+		Description: "Replays the response recorded with the name of the String Input field",
+	})
+
 	s.queryMux.HandleFunc("", s.handleFallbackScenario)
 }
 
@@ -3,6 +3,7 @@ package util
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 )
@@ -42,3 +43,17 @@ func WithCancelCause(parent context.Context) (context.Context, CancelCauseFunc)
 	}
 	return result, cancelFn
 }
+
+type withoutCancelContext struct {
+	parent context.Context
+}
+
+func (withoutCancelContext) Deadline() (deadline time.Time, ok bool) { return }
+func (withoutCancelContext) Done() <-chan struct{}                   { return nil }
+func (withoutCancelContext) Err() error                              { return nil }
+func (c withoutCancelContext) Value(key any) any                     { return c.parent.Value(key) }
+
+// WithoutCancel returns a context that carries the values of the parent, but is not cancelled when the parent is.
+func WithoutCancel(parent context.Context) context.Context {
+	return withoutCancelContext{parent: parent}
+}
@@ -33,6 +33,10 @@ const (
 	// checks.
 	// HTTP status code 400.
 	StatusValidationFailed CoreStatus = "Validation failed"
+	// StatusPayloadTooLarge means that the payload for the request is
+	// larger than the server accepts.
+	// HTTP status code 413.
+	StatusPayloadTooLarge CoreStatus = "Payload too large"
 	// StatusInternal means that the server acknowledges that there's
 	// an error, but that there is nothing the client can do to fix it.
 	// HTTP status code 500.
@@ -75,6 +79,8 @@ func (s CoreStatus) HTTPStatus() int {
 		return http.StatusTooManyRequests
 	case StatusBadRequest, StatusValidationFailed:
 		return http.StatusBadRequest
+	case StatusPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
 	case StatusNotImplemented:
 		return http.StatusNotImplemented
 	case StatusUnknown, StatusInternal:
@@ -101,6 +107,8 @@ func (s CoreStatus) LogLevel() LogLevel {
 		return LevelDebug
 	case StatusValidationFailed:
 		return LevelDebug
+	case StatusPayloadTooLarge:
+		return LevelDebug
 	case StatusNotImplemented:
 		return LevelDebug
 	case StatusUnknown, StatusInternal:
@@ -0,0 +1,15 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HmacSha256Sum returns the hex HMAC-SHA256 of the body with the secret as key, used to sign the webhook
+// requests so the receivers can check they come from Grafana.
+func HmacSha256Sum(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,12 @@
+package util
+
+import "testing"
+
+func TestHmacSha256Sum(t *testing.T) {
+	have := HmacSha256Sum("key", []byte("The quick brown fox jumps over the lazy dog"))
+
+	want := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if have != want {
+		t.Fatalf("expected: %s got: %s", want, have)
+	}
+}
@@ -1,6 +1,7 @@
 import React, { useMemo } from 'react';
 
 import { dateTime } from '@grafana/data';
+import { Badge } from '@grafana/ui';
 import { Alert, PaginationProps } from 'app/types/unified-alerting';
 
 import { alertInstanceKey } from '../../utils/rules';
@@ -46,8 +47,19 @@ const columns: AlertTableColumnProps[] = [
     id: 'state',
     label: 'State',
     // eslint-disable-next-line react/display-name
-    renderCell: ({ data: { state } }) => <AlertStateTag state={state} />,
-    size: '80px',
+    renderCell: ({ data: { state, flapping } }) => (
+      <>
+        <AlertStateTag state={state} />
+        {flapping && (
+          <Badge
+            color="orange"
+            text="Flapping"
+            tooltip="The alert instance changed between firing and normal too often recently"
+          />
+        )}
+      </>
+    ),
+    size: '160px',
   },
   {
     id: 'labels',
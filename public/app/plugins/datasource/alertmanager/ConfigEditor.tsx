@@ -73,6 +73,27 @@ export const ConfigEditor = (props: Props) => {
             />
           </InlineField>
         </div>
+        {options.jsonData.handleGrafanaManagedAlerts &&
+          options.jsonData.implementation !== AlertManagerImplementation.prometheus && (
+            <div className="gf-form-inline">
+              <InlineField
+                label="Synchronize Grafana configuration"
+                tooltip="When enabled, the Grafana-managed contact points and notification policies are pushed to this Alertmanager. Changes made to its configuration outside of Grafana are reported instead of overwritten."
+                labelWidth={26}
+              >
+                <InlineSwitch
+                  value={options.jsonData.syncGrafanaManagedConfig ?? false}
+                  onChange={(e) => {
+                    onOptionsChange(
+                      produce(options, (draft) => {
+                        draft.jsonData.syncGrafanaManagedConfig = e.currentTarget.checked;
+                      })
+                    );
+                  }}
+                />
+              </InlineField>
+            </div>
+          )}
       </div>
       <DataSourceHttpSettings
         defaultUrl={''}
@@ -109,10 +109,13 @@ export type Route = {
   repeat_interval?: string;
   routes?: Route[];
   mute_time_intervals?: string[];
+  flapping_action?: FlappingAction;
   /** only the root policy might have a provenance field defined */
   provenance?: string;
 };
 
+export type FlappingAction = 'notify' | 'suppress' | 'coalesce';
+
 export interface RouteWithID extends Route {
   id: string;
   routes?: RouteWithID[];
@@ -322,4 +325,5 @@ export type MuteTimeInterval = {
 export interface AlertManagerDataSourceJsonData extends DataSourceJsonData {
   implementation?: AlertManagerImplementation;
   handleGrafanaManagedAlerts?: boolean;
+  syncGrafanaManagedConfig?: boolean;
 }
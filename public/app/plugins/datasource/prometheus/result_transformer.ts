@@ -82,7 +82,9 @@ export function transformV2(
   // EXEMPLAR FRAMES: We enrich exemplar frames with data links and add dataTopic meta info
   const { exemplarTraceIdDestinations: destinations } = options;
   const processedExemplarFrames = exemplarFrames.map((dataFrame) => {
-    if (destinations?.length) {
+    // the links of the trace ID fields are resolved by the backend for data sources that support it
+    const hasBackendLinks = dataFrame.fields.some((field) => field.config.links?.length);
+    if (destinations?.length && !hasBackendLinks) {
       for (const exemplarTraceIdDestination of destinations) {
         const traceIDField = dataFrame.fields.find((field) => field.name === exemplarTraceIdDestination.name);
         if (traceIDField) {
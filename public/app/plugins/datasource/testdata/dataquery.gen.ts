@@ -34,6 +34,7 @@ export enum TestDataQueryType {
   RandomWalkTable = 'random_walk_table',
   RandomWalkWithError = 'random_walk_with_error',
   RawFrame = 'raw_frame',
+  Recorded = 'recorded',
   ServerError500 = 'server_error_500',
   Simulation = 'simulation',
   SlowQuery = 'slow_query',
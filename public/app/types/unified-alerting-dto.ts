@@ -117,6 +117,7 @@ export interface PromAlertingRuleDTO extends PromRuleDTOBase {
     state: Exclude<PromAlertingRuleState | GrafanaAlertStateWithReason, PromAlertingRuleState.Inactive>;
     activeAt: string;
     value: string;
+    flapping?: boolean;
   }>;
   labels: Labels;
   annotations?: Annotations;
@@ -20,6 +20,8 @@ export type Alert = {
   labels: { [key: string]: string };
   state: PromAlertingRuleState | GrafanaAlertStateWithReason;
   value: string;
+  /** set when the alert instance changed between firing and not firing too often recently */
+  flapping?: boolean;
 };
 
 export function hasAlertState(alert: Alert, state: PromAlertingRuleState | GrafanaAlertState): boolean {